@@ -12,19 +12,45 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
 	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/api/routes"
 	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/cache"
+	"ai-conversation-platform/internal/integrations/crm"
+	"ai-conversation-platform/internal/integrations/email"
+	"ai-conversation-platform/internal/integrations/scraper"
+	"ai-conversation-platform/internal/integrations/slack"
 	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/ai/openai"
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/middleware"
+	"ai-conversation-platform/internal/moderation"
+	"ai-conversation-platform/internal/middleware/ratelimit"
 	"ai-conversation-platform/internal/rules"
+	"ai-conversation-platform/internal/secrets"
 	"ai-conversation-platform/internal/services/agentassist"
 	"ai-conversation-platform/internal/services/analytics"
 	"ai-conversation-platform/internal/services/autoreply"
 	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/services/health"
+	"ai-conversation-platform/internal/services/tenant"
+	"ai-conversation-platform/internal/services/webhook"
 	"ai-conversation-platform/internal/storage/chroma"
 	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
 )
 
+// serviceName identifies this server in traces
+const serviceName = "ai-conversation-platform"
+
 func main() {
 	// Initialize database client
 	dbClient, err := postgres.NewClient()
@@ -51,16 +77,31 @@ func main() {
 	// Initialize AI components (if Chroma and Gemini are available)
 	var analyzer *ai.Analyzer
 	var embeddingService *ai.EmbeddingService
+	var defaultGeminiClient *ai.Client
+	var textGenerator ai.TextGenerator
 	if chromaClient != nil {
 		geminiClient, err := ai.NewGeminiClient()
 		if err != nil {
 			log.Printf("Warning: Failed to initialize Gemini client: %v", err)
 			log.Println("AI features will be disabled")
 		} else {
-			// Initialize AI services
+			defaultGeminiClient = geminiClient
+			// Text generation falls back through AI_PROVIDER_ORDER when a provider fails
+			providerChain := newProviderChain(ai.ProviderOrderFromEnv(), geminiClient)
+			textGenerator = providerChain
+
+			// Initialize AI services. Embeddings always come from Gemini so stored vectors stay comparable.
 			retriever := chroma.NewRetriever(chromaClient)
 			embeddingService = ai.NewEmbeddingService(geminiClient, chromaClient)
-			analyzer = ai.NewAnalyzer(geminiClient, retriever, embeddingService, conversationStorage)
+			embeddingService.SetBatchDelay(ai.BatchDelayFromEnv())
+			analyzer = ai.NewAnalyzer(providerChain, retriever, embeddingService, conversationStorage)
+
+			// A restarted Chroma with ephemeral storage comes back without collections
+			if recreated, err := embeddingService.RecreateMissingCollections(context.Background()); err != nil {
+				log.Printf("Warning: Chroma collection check failed: %v", err)
+			} else if len(recreated) > 0 {
+				log.Printf("Recreated missing Chroma collections: %v", recreated)
+			}
 
 			// Health check Gemini
 			if err := geminiClient.HealthCheck(); err != nil {
@@ -73,8 +114,15 @@ func main() {
 
 	// Initialize services
 	ingestionService := conversation.NewIngestionService(conversationStorage)
+	// Analyses run on a bounded worker pool; quota errors are retried with backoff
+	analysisPoolConfig := worker.ConfigFromEnv()
+	analysisPoolConfig.Retryable = ai.IsQuotaError
+	analysisPool := worker.NewPool(analysisPoolConfig)
+	analysisPool.Start()
 	if analyzer != nil {
-		ingestionService.SetAnalyzer(analyzer)
+		ingestionService.SetAnalyzer(analyzer, analysisPool)
+		// Customer messages the local detector is unsure about are confirmed with Gemini
+		ingestionService.SetLanguageConfirmer(analyzer)
 	}
 
 	// Initialize storage layers
@@ -83,36 +131,191 @@ func main() {
 	memoryStorage := postgres.NewMemoryStorage(dbClient)
 	brandToneStorage := postgres.NewBrandToneStorage(dbClient)
 	productStorage := postgres.NewProductStorage(dbClient)
+	productVariantStorage := postgres.NewProductVariantStorage(dbClient)
+	categoryStorage := postgres.NewCategoryStorage(dbClient)
 	autoReplyGlobalStorage := postgres.NewAutoReplyStorage(dbClient)
 	autoReplyConversationStorage := postgres.NewAutoReplyStorage(dbClient)
 	suggestionsStorage := postgres.NewSuggestionsStorage(dbClient)
+	corsConfigStorage := postgres.NewCORSConfigStorage(dbClient)
+	// Suggestions and dashboard metrics are cached in Redis when REDIS_URL is set, otherwise in memory
+	responseCache := cache.FromEnv()
+	leadStageStorage := postgres.NewLeadStageStorage(dbClient)
+	hotLeadAlertStorage := postgres.NewHotLeadAlertStorage(dbClient)
+	pricingSuggestionStorage := postgres.NewPricingSuggestionStorage(dbClient)
+	slackConfigStorage := postgres.NewSlackConfigStorage(dbClient)
+	crmFieldMappingStorage := postgres.NewCRMFieldMappingStorage(dbClient)
+	notificationStorage := postgres.NewNotificationStorage(dbClient)
+	auditStorage := postgres.NewAuditStorage(dbClient)
+	watchlistStorage := postgres.NewWatchlistStorage(dbClient)
+	knowledgeArticleStorage := postgres.NewKnowledgeArticleStorage(dbClient)
+	agentProfileStorage := postgres.NewAgentProfileStorage(dbClient)
+	usageStorage := postgres.NewUsageStorage(dbClient)
+	slaStorage := postgres.NewSLAStorage(dbClient)
+	webhookStorage := postgres.NewWebhookStorage(dbClient)
+	tagStorage := postgres.NewTagStorage(dbClient)
+	noteStorage := postgres.NewNoteStorage(dbClient)
+	suggestionFeedbackStorage := postgres.NewSuggestionFeedbackStorage(dbClient)
+	knowledgeGapStorage := postgres.NewKnowledgeGapStorage(dbClient)
+	analyticsConfigStorage := postgres.NewAnalyticsConfigStorage(dbClient)
+	moderationStorage := postgres.NewModerationStorage(dbClient)
+	objectionResolutionStorage := postgres.NewObjectionResolutionStorage(dbClient)
+	escalationStorage := postgres.NewEscalationStorage(dbClient)
+	routingRuleStorage := postgres.NewRoutingRuleStorage(dbClient)
+	businessHoursStorage := postgres.NewBusinessHoursStorage(dbClient)
+
+	// Inbound messages are screened against each tenant's content moderation rules
+	ingestionService.SetContentModeration(rules.NewRuleEngine(), ruleStorage)
+	ingestionService.SetMessageModerator(moderation.NewService(moderationStorage, moderationStorage))
+	ingestionService.SetAuditStorage(auditStorage)
+	ingestionService.SetWatchlistStorage(watchlistStorage)
+	ingestionService.SetMemoryStorage(memoryStorage)
+	memoryStorage.SetMaxObjectionsRetained(analytics.DefaultAnalyticsConfig().MaxObjectionsRetained)
+	if analyzer != nil {
+		// Objections and product interests found by analysis accumulate in customer memory
+		analyzer.SetCustomerMemory(memoryStorage, productStorage)
+		// Objections that disappear between analyses are recorded as resolved
+		analyzer.SetObjectionResolutionRecorder(objectionResolutionStorage)
+	}
+	messageBroadcaster := conversation.NewMessageBroadcaster()
+	ingestionService.SetMessageBroadcaster(messageBroadcaster)
+	dashboardUpdates := conversation.NewDashboardUpdates()
+	ingestionService.SetDashboardUpdates(dashboardUpdates)
+	// Customer messages get a response deadline; a scan on the worker pool marks missed ones
+	slaTracker := conversation.NewSLATracker(slaStorage)
+	slaTracker.SetComplexitySources(conversationStorage, watchlistStorage)
+	ingestionService.SetSLATracker(slaTracker)
+	slaTracker.Start(analysisPool)
+	defer slaTracker.Stop()
+	// Conversation events are delivered to tenant webhooks, mapped to the tenant's CRM fields when configured
+	webhookDispatcher := webhook.NewDispatcher(webhookStorage)
+	webhookDispatcher.SetPayloadMapper(crm.NewFieldMapper(crmFieldMappingStorage))
+	ingestionService.SetEventPublisher(webhookDispatcher)
+	conversationStorage.AddCloseListener(ingestionService)
+
+	// Tenant Gemini keys are encrypted with CREDENTIAL_MASTER_KEY
+	credentialCipher, err := secrets.NewCipherFromEnv()
+	if err != nil {
+		log.Printf("Warning: tenant credential storage disabled: %v", err)
+		credentialCipher = nil
+	}
+	credentialStorage := postgres.NewCredentialStorage(dbClient, credentialCipher)
+	geminiClientFactory := ai.NewGeminiClientFactory(credentialStorage, textGenerator)
+	if analyzer != nil {
+		analyzer.SetClientFactory(geminiClientFactory)
+		analyzer.SetUsageRecorder(usageStorage)
+	}
+
+	// Sentiment scores are normalized per model using stored calibrations
+	modelCalibrationStorage := postgres.NewModelCalibrationStorage(dbClient)
+	sentimentNormalizer, err := ai.LoadSentimentNormalizer(modelCalibrationStorage)
+	if err != nil {
+		log.Printf("Warning: Failed to load model calibrations: %v", err)
+	}
+	if analyzer != nil {
+		analyzer.SetSentimentNormalizer(sentimentNormalizer)
+	}
+
+	// Tenants may replace the default analysis intents
+	aiConfigStorage := postgres.NewAIConfigStorage(dbClient)
+	if analyzer != nil {
+		analyzer.SetIntentConfigSource(aiConfigStorage)
+	}
 
 	// Initialize AI components for agent assist (if available)
 	var agentAssistService *agentassist.AgentAssistService
+	var pricingService *agentassist.PricingService
 	if analyzer != nil && chromaClient != nil && embeddingService != nil {
-		geminiClient, err := ai.NewGeminiClient()
-		if err == nil {
-			retriever := chroma.NewRetriever(chromaClient)
-			ruleEngine := rules.NewRuleEngine()
-			
-			agentAssistService = agentassist.NewAgentAssistService(
-				analyzer,
-				geminiClient,
-				retriever,
-				embeddingService,
-				ruleEngine,
-				ruleStorage,
-				conversationStorage,
-				memoryStorage,
-				brandToneStorage,
-				suggestionsStorage,
-			)
-			log.Println("Agent assist service initialized successfully")
-		}
+		retriever := chroma.NewRetriever(chromaClient)
+		ruleEngine := rules.NewRuleEngine()
+
+		agentAssistService = agentassist.NewAgentAssistService(
+			analyzer,
+			textGenerator,
+			retriever,
+			embeddingService,
+			ruleEngine,
+			ruleStorage,
+			conversationStorage,
+			memoryStorage,
+			brandToneStorage,
+			suggestionsStorage,
+		)
+		pricingService = agentassist.NewPricingService(textGenerator, ruleEngine, ruleStorage, pricingSuggestionStorage)
+		agentAssistService.SetPricingService(pricingService)
+		agentAssistService.SetClientFactory(geminiClientFactory)
+		agentAssistService.SetAuditStorage(auditStorage)
+		agentAssistService.SetSuggestionCountSource(aiConfigStorage)
+		agentAssistService.SetAgentProfileStorage(agentProfileStorage)
+		agentAssistService.SetUsageRecorder(usageStorage)
+		// Recommended products are tracked and ranked in the prompt by how often agents accepted them
+		agentAssistService.SetProductRecommendationStore(productStorage)
+		agentAssistService.SetCache(responseCache)
+		agentAssistService.SetKnowledgeGapRecorder(knowledgeGapStorage)
+		log.Println("Agent assist service initialized successfully")
+	}
+
+	// Pricing review works without AI; only generating new suggestions needs Gemini
+	if pricingService == nil {
+		pricingService = agentassist.NewPricingService(nil, rules.NewRuleEngine(), ruleStorage, pricingSuggestionStorage)
 	}
+	pricingService.SetClientFactory(geminiClientFactory)
+	pricingService.SetUsageRecorder(usageStorage)
+	pricingService.SetEventPublisher(webhookDispatcher)
+	pricingService.SetVariantSource(productVariantStorage)
+	pricingService.SetProductSource(productStorage)
+
+	// Slack notifications for hot leads and escalations; rate-limited sends are retried from the notifications queue
+	slackService := slack.NewService(slackConfigStorage, notificationStorage, conversationStorage, userStorage)
+	slackService.SetWatchlistChecker(watchlistStorage)
+	slackService.Start(30 * time.Second)
+	defer slackService.Stop()
 
 	// Initialize analytics service
-	analyticsService := analytics.NewAnalyticsService(conversationStorage)
+	analyticsService := analytics.NewAnalyticsService(conversationStorage, leadStageStorage, hotLeadAlertStorage)
+	analyticsService.SetHotLeadNotifier(slackService)
+	analyticsService.SetEscalationNotifier(slackService)
+	analyticsService.SetWatchlistStorage(watchlistStorage)
+	analyticsService.SetSLAStorage(slaStorage)
+	analyticsService.SetTagStorage(tagStorage)
+	analyticsService.SetNoteStorage(noteStorage)
+	analyticsService.SetSuggestionFeedbackStorage(suggestionFeedbackStorage)
+	analyticsService.SetObjectionResolutionStorage(objectionResolutionStorage)
+	analyticsService.SetMemoryStorage(memoryStorage)
+	analyticsService.SetCache(responseCache)
+	analyticsService.SetEscalationStorage(escalationStorage, ruleStorage)
+	analyticsService.SetEventPublisher(webhookDispatcher)
+	analyticsService.SetConfigStore(analyticsConfigStorage)
+	if analyzer != nil {
+		analyzer.SetAnalysisListener(analyticsService)
+	}
+	// Leads stuck in a stage are checked hourly on the worker pool rather than after every analysis
+	stuckLeadScanner := analytics.NewStuckLeadScanner(analyticsService, leadStageStorage)
+	stuckLeadScanner.Start(analysisPool)
+	defer stuckLeadScanner.Stop()
+	// Unassigned conversations are assigned by the tenant's routing rules after each analysis
+	routingEngine := conversation.NewRoutingEngine(routingRuleStorage, conversationStorage)
+	routingEngine.SetUrgencyScorer(analyticsService)
+	ingestionService.SetRoutingEngine(routingEngine)
+	// Suggestion confidence is rescaled to how often each tenant's agents used similar scores
+	confidenceCalibrator := ai.NewConfidenceCalibrator(suggestionFeedbackStorage, responseCache)
+	if agentAssistService != nil {
+		// Suggestions for intents agents often accept score higher, and vice versa
+		agentAssistService.SetAcceptanceRateSource(analyticsService, analytics.DefaultAnalyticsConfig().SuggestionAcceptanceWeight)
+		agentAssistService.SetConfidenceCalibrator(confidenceCalibrator)
+	}
+
+	// Nightly watchlist digest for admins, customer transcript emails and invitation emails (require SMTP)
+	var transcriptService *conversation.TranscriptEmailService
+	var inviteNotifier handlers.InviteNotifier
+	if emailSender, err := email.NewSMTPSender(); err == nil {
+		watchlistDigest := analytics.NewWatchlistDigest(analyticsService, watchlistStorage, userStorage, emailSender)
+		watchlistDigest.Start()
+		defer watchlistDigest.Stop()
+		transcriptService = conversation.NewTranscriptEmailService(conversationStorage, productStorage, emailSender)
+		inviteNotifier = email.NewInviteNotifier(emailSender)
+	} else {
+		log.Printf("Warning: watchlist digest, transcript and invitation emails disabled: %v", err)
+	}
 
 	// Initialize auto-reply service (if agent assist is available)
 	var autoReplyService *autoreply.AutoReplyService
@@ -124,6 +327,9 @@ func main() {
 			agentAssistService,
 			ingestionService,
 		)
+		autoReplyService.SetSimilarityThreshold(analytics.DefaultAnalyticsConfig().AutoReplySimilarityThreshold)
+		autoReplyService.SetHandoffNotifier(slackService)
+		autoReplyService.SetBusinessHours(businessHoursStorage)
 		ingestionService.SetAutoReplyService(autoReplyService)
 		log.Println("Auto-reply service initialized successfully")
 	}
@@ -134,12 +340,80 @@ func main() {
 	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(userStorage)
+	revocations := auth.NewRevocationCache()
+	authHandler := handlers.NewAuthHandler(userStorage, postgres.NewRefreshTokenStorage(dbClient), revocations)
 	conversationHandler := handlers.NewConversationHandler(ingestionService, userStorage)
+	conversationHandler.SetNoteLister(noteStorage)
+	if analyzer != nil {
+		conversationHandler.SetSummarizer(ai.NewSummarizer(analyzer, postgres.NewConversationSummaryStorage(dbClient)))
+	}
 	ruleHandler := handlers.NewRuleHandler(ruleStorage)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, ingestionService, userStorage)
-	productHandler := handlers.NewProductHandler(productStorage, embeddingService)
+	analyticsHandler.SetDashboardUpdates(dashboardUpdates)
+	productHandler := handlers.NewProductHandler(productStorage, productVariantStorage, embeddingService)
+	productHandler.SetCategoryStorage(categoryStorage)
 	memoryHandler := handlers.NewMemoryHandler(memoryStorage)
+	corsConfigHandler := handlers.NewCORSConfigHandler(corsConfigStorage)
+	pricingHandler := handlers.NewPricingHandler(agentAssistService, pricingService)
+	credentialsHandler := handlers.NewCredentialsHandler(credentialStorage, geminiClientFactory)
+	slackConfigHandler := handlers.NewSlackConfigHandler(slackConfigStorage, slackService)
+	crmConfigHandler := handlers.NewCRMConfigHandler(crmFieldMappingStorage)
+	calibrationHandler := handlers.NewCalibrationHandler(modelCalibrationStorage, sentimentNormalizer)
+	calibrationHandler.SetConfidenceCalibrator(confidenceCalibrator)
+	aiConfigHandler := handlers.NewAIConfigHandler(aiConfigStorage)
+	userAdminHandler := handlers.NewUserAdminHandler(userStorage)
+	invitationStorage := postgres.NewInvitationStorage(dbClient)
+	userAdminHandler.SetInvitations(invitationStorage, inviteNotifier)
+	authHandler.SetInvitationStore(invitationStorage)
+	conversationImporter := conversation.NewConversationImporter(conversationStorage, userStorage)
+	conversationImporter.SetAnalysisScheduler(ingestionService)
+	superAdminHandler := handlers.NewSuperAdminHandler(analytics.NewChurnRiskAggregation(analyticsService, conversationStorage), usageStorage)
+
+	// Scraped knowledge articles, closed conversation transcripts and the message search index are
+	// embedded in the background
+	var embeddingQueue *ai.EmbeddingQueue
+	if embeddingService != nil {
+		embeddingService.SetConversationLoader(conversationStorage)
+		embeddingQueue = ai.NewEmbeddingQueue(embeddingService)
+		embeddingQueue.SetMessageSource(conversationStorage)
+		conversationStorage.AddCloseListener(embeddingQueue)
+		ingestionService.SetMessageIndexer(embeddingQueue)
+		conversationHandler.SetMessageSearcher(embeddingService)
+		embeddingQueue.Start()
+		defer embeddingQueue.Stop()
+	}
+	knowledgeIndexer := scraper.NewKnowledgeArticleIndexer(knowledgeArticleStorage, productStorage, embeddingQueue)
+	knowledgeHandler := handlers.NewKnowledgeHandler(knowledgeIndexer, knowledgeArticleStorage)
+
+	// Vectors left behind by deleted products are cleaned up weekly
+	var vectorStoreCleaner *ai.VectorStoreCleaner
+	if embeddingService != nil {
+		vectorStoreCleaner = ai.NewVectorStoreCleaner(embeddingService, productStorage)
+		vectorStoreCleaner.Start()
+		defer vectorStoreCleaner.Stop()
+	}
+	vectorStoreHandler := handlers.NewVectorStoreHandler(vectorStoreCleaner)
+	if embeddingService != nil {
+		vectorStoreHandler.SetCollectionRepairer(embeddingService)
+
+		// Products are re-embedded in the background when the embedding model changed since they were indexed
+		embeddingReindexer := ai.NewEmbeddingReindexer(embeddingService, postgres.NewEmbeddingVersionStorage(dbClient), productStorage, productVariantStorage)
+		if _, err := embeddingReindexer.CheckVersion(); err != nil {
+			log.Printf("Warning: Embedding version check failed: %v", err)
+		}
+		vectorStoreHandler.SetEmbeddingStatus(embeddingReindexer)
+	}
+
+	// Agent writing profiles used to personalize suggestions are rebuilt nightly
+	profileBuilder := agentassist.NewProfileBuilder(conversationStorage, agentProfileStorage)
+	profileBuilder.Start()
+	defer profileBuilder.Stop()
+	agentProfileHandler := handlers.NewAgentProfileHandler(agentProfileStorage, profileBuilder)
+
+	// Soft-deleted conversations are purged nightly once older than RETENTION_DAYS
+	retentionEnforcer := conversation.NewRetentionEnforcer(conversationStorage)
+	retentionEnforcer.Start()
+	defer retentionEnforcer.Stop()
 	
 	var agentAssistHandler *handlers.AgentAssistHandler
 	if agentAssistService != nil {
@@ -160,122 +434,126 @@ func main() {
 		analyzer.SetRuleLoader(ruleStorage)
 	}
 
+	// Prometheus metrics, served on their own port so they aren't exposed alongside the API
+	metricsRegistry := metrics.NewRegistry()
+	metrics.SetRegistry(metricsRegistry)
+
+	// Request tracing, exported over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set. Incoming W3C
+	// traceparent headers are continued either way.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tracerProvider, err := newTracerProvider(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	if tracerProvider != nil {
+		otel.SetTracerProvider(tracerProvider)
+		log.Printf("[TRACING] exporting spans over OTLP/HTTP")
+	}
+
 	// Set up router
 	router := gin.Default()
+	router.Use(otelgin.Middleware(serviceName))
+	router.Use(metrics.Middleware())
 
 	// Middleware
-	router.Use(corsMiddleware())
+	corsConfig := middleware.CORSConfigFromEnv()
+	corsConfig.OriginSource = corsConfigStorage
+	log.Printf("[CORS] mode=%s allowed_origins=%v", corsConfig.Mode, corsConfig.AllowedOrigins)
+	router.Use(middleware.CORSMiddleware(corsConfig))
 	router.Use(loggingMiddleware())
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	// Per-tenant quotas, checked before authentication so throttled tenants are turned away cheaply
+	rateLimiter := ratelimit.NewTenantRateLimiter(ratelimit.ConfigFromEnv())
+	rateLimitConfig := rateLimiter.Config()
+	log.Printf("[RATE_LIMIT] requests_per_minute=%d burst=%d ai_calls_per_minute=%d",
+		rateLimitConfig.MaxRequestsPerMinute, rateLimitConfig.BurstSize, rateLimitConfig.MaxAICallsPerMinute)
+	router.Use(rateLimiter.Middleware(tenantFromToken))
 
-	// Public routes (no JWT required)
-	api := router.Group("/api")
-	{
-		auth := api.Group("/auth")
-		{
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/customer-login", authHandler.CustomerLogin)
-		}
+	// GDPR erasure of a tenant's rows and embeddings
+	dataDeletionService := tenant.NewDataDeletionService(postgres.NewTenantDataStorage(dbClient))
+	if chromaClient != nil {
+		dataDeletionService.SetVectorStore(chromaClient)
 	}
 
-	// Protected API routes (JWT required)
-	api = router.Group("/api")
-	api.Use(jwtAuthMiddleware())
-	{
-		api.POST("/conversations", conversationHandler.CreateConversation)
-		api.POST("/conversations/:id/messages", conversationHandler.SendMessage)
-		api.GET("/conversations/:id", conversationHandler.GetConversation)
-		api.GET("/conversations", conversationHandler.ListConversations)
-
-		// Agent assist routes (agent only)
-		if agentAssistHandler != nil {
-			api.POST("/conversations/:id/suggestions", agentAssistHandler.GetSuggestions)
-			api.GET("/conversations/:id/insights", agentAssistHandler.GetInsights)
-		}
-
-		// Rule management routes (admin only)
-		rules := api.Group("/rules")
-		rules.Use(adminMiddleware())
-		{
-			rules.GET("", ruleHandler.ListRules)
-			rules.GET("/:id", ruleHandler.GetRule)
-			rules.POST("", ruleHandler.CreateRule)
-			rules.PUT("/:id", ruleHandler.UpdateRule)
-			rules.DELETE("/:id", ruleHandler.DeleteRule)
-		}
+	// Health checks and Kubernetes probes
+	healthChecker := health.NewChecker(dbClient)
+	if chromaClient != nil {
+		healthChecker.SetVectorStore(chromaClient)
+	}
+	if embeddingService != nil {
+		healthChecker.SetCollectionChecker(embeddingService)
+	}
+	if defaultGeminiClient != nil {
+		healthChecker.SetModelAPI(defaultGeminiClient)
+	}
+	if redisCache, ok := responseCache.(*cache.RedisCache); ok {
+		healthChecker.SetCacheServer(redisCache)
+	}
+	routes.RegisterAll(router.Group(""), []routes.Router{
+		routes.NewHealthRouter(handlers.NewHealthHandler(healthChecker)),
+	})
 
-		// Analytics routes
-		analyticsGroup := api.Group("/analytics")
-		{
-			analyticsGroup.GET("/leads", analyticsHandler.GetLeads)
-			analyticsGroup.GET("/conversations/:id/win-probability", analyticsHandler.GetWinProbability)
-			analyticsGroup.GET("/conversations/:id/churn-risk", analyticsHandler.GetChurnRisk)
-			analyticsGroup.GET("/conversations/:id/trends", analyticsHandler.GetTrends)
-			analyticsGroup.GET("/conversations/:id/clv", analyticsHandler.GetCLV)
-			analyticsGroup.GET("/conversations/:id/sales-cycle", analyticsHandler.GetSalesCycle)
-			analyticsGroup.GET("/dashboard", analyticsHandler.GetDashboard)
-
-			// Admin-only analytics routes
-			analyticsAdmin := analyticsGroup.Group("")
-			analyticsAdmin.Use(adminMiddleware())
-			{
-				analyticsAdmin.GET("/conversations/:id/quality", analyticsHandler.GetQuality)
-			}
-		}
+	// Public routes (no JWT required); super admin routes use SUPER_ADMIN_TOKEN instead
+	routes.RegisterAll(router.Group("/api"), []routes.Router{
+		routes.NewAuthRouter(authHandler),
+		routes.NewSuperAdminRouter(superAdminHandler, calibrationHandler),
+	})
 
-		// Product routes
-		products := api.Group("/products")
-		{
-			// Public GET routes (authenticated users can view products)
-			products.GET("", productHandler.ListProducts)
-			products.GET("/:id", productHandler.GetProduct)
-
-			// Admin-only management routes
-			productsAdmin := products.Group("")
-			productsAdmin.Use(adminMiddleware())
-			{
-				productsAdmin.POST("", productHandler.CreateProduct)
-				productsAdmin.PUT("/:id", productHandler.UpdateProduct)
-				productsAdmin.DELETE("/:id", productHandler.DeleteProduct)
-			}
-		}
+	// Accepted suggestions count towards the conversion rate of the products they recommended
+	suggestionFeedbackHandler := handlers.NewSuggestionFeedbackHandler(suggestionFeedbackStorage)
+	suggestionFeedbackHandler.SetRecommendationAcceptor(productStorage)
 
-		// Auto-reply routes
-		if autoReplyHandler != nil {
-			autoreplyGroup := api.Group("/autoreply")
-			{
-				// Global config (admin only)
-				autoreplyGlobal := autoreplyGroup.Group("/global")
-				autoreplyGlobal.Use(adminMiddleware())
-				{
-					autoreplyGlobal.GET("", autoReplyHandler.GetGlobalAutoReply)
-					autoreplyGlobal.PUT("", autoReplyHandler.UpdateGlobalAutoReply)
-				}
-
-				// Conversation config (agent/admin)
-				api.GET("/conversations/:id/autoreply", autoReplyHandler.GetConversationAutoReply)
-				api.PUT("/conversations/:id/autoreply", autoReplyHandler.UpdateConversationAutoReply)
-
-				// Test auto-reply (admin only)
-				api.POST("/conversations/:id/autoreply/test", autoReplyHandler.TestAutoReply)
-			}
-		}
+	// Changes to rules, products, categories, brand tone and the global auto-reply config are audited
+	ruleRouter := routes.NewRuleRouter(ruleHandler)
+	ruleRouter.SetAuditRecorder(auditStorage)
+	productRouter := routes.NewProductRouter(productHandler)
+	productRouter.SetAuditRecorder(auditStorage)
+	categoryRouter := routes.NewCategoryRouter(handlers.NewCategoryHandler(categoryStorage, productStorage))
+	categoryRouter.SetAuditRecorder(auditStorage)
+	brandToneRouter := routes.NewBrandToneRouter(handlers.NewBrandToneHandler(brandToneStorage))
+	brandToneRouter.SetAuditRecorder(auditStorage)
 
-		// Customer memory routes (admin only)
-		memories := api.Group("/memories")
-		memories.Use(adminMiddleware())
-		{
-			memories.GET("", memoryHandler.ListMemories)
-			memories.GET("/:id", memoryHandler.GetMemory)
-			memories.POST("", memoryHandler.CreateMemory)
-			memories.PUT("/:id", memoryHandler.UpdateMemory)
-			memories.DELETE("/:id", memoryHandler.DeleteMemory)
-		}
+	// Protected API routes (JWT required)
+	protectedRouters := []routes.Router{
+		routes.NewConversationRouter(conversationHandler),
+		ruleRouter,
+		routes.NewAnalyticsRouter(analyticsHandler),
+		productRouter,
+		categoryRouter,
+		routes.NewMemoryRouter(memoryHandler),
+		brandToneRouter,
+		routes.NewSLARouter(handlers.NewSLAConfigHandler(slaStorage, slaTracker)),
+		routes.NewWebhookRouter(handlers.NewWebhookHandler(webhookStorage)),
+		routes.NewRoutingRuleRouter(handlers.NewRoutingRuleHandler(routingRuleStorage)),
+		routes.NewBusinessHoursRouter(handlers.NewBusinessHoursHandler(businessHoursStorage)),
+		routes.NewPricingRouter(pricingHandler),
+		routes.NewAdminRouter(corsConfigHandler, credentialsHandler, slackConfigHandler, crmConfigHandler, calibrationHandler, aiConfigHandler, userAdminHandler, vectorStoreHandler),
+		routes.NewKnowledgeRouter(knowledgeHandler),
+		routes.NewAgentProfileRouter(agentProfileHandler),
+		routes.NewTranscriptRouter(handlers.NewTranscriptHandler(transcriptService)),
+		routes.NewMessageStreamRouter(handlers.NewMessageStreamHandler(conversationStorage, messageBroadcaster, handlers.MessageStreamConfigFromEnv())),
+		routes.NewTimelineRouter(handlers.NewTimelineHandler(conversation.NewConversationTimelineService(conversationStorage, auditStorage))),
+		routes.NewAssignmentRouter(handlers.NewAssignmentHandler(conversationStorage)),
+		routes.NewTagRouter(handlers.NewTagHandler(tagStorage)),
+		routes.NewSuggestionFeedbackRouter(suggestionFeedbackHandler),
+		routes.NewTenantDataRouter(handlers.NewTenantDataHandler(dataDeletionService)),
+		routes.NewAuditRouter(handlers.NewAuditLogHandler(auditStorage)),
+		routes.NewEscalationRouter(handlers.NewEscalationHandler(escalationStorage)),
+		routes.NewConversationImportRouter(handlers.NewConversationImportHandler(conversationImporter)),
+		routes.NewKnowledgeGapRouter(handlers.NewKnowledgeGapHandler(knowledgeGapStorage)),
+		routes.NewAnalyticsConfigRouter(handlers.NewAnalyticsConfigHandler(analyticsService)),
+		routes.NewModerationRouter(handlers.NewModerationHandler(moderationStorage)),
+		routes.NewNoteRouter(handlers.NewNoteHandler(noteStorage)),
+	}
+	if agentAssistHandler != nil {
+		protectedRouters = append(protectedRouters, routes.NewAgentAssistRouter(agentAssistHandler))
 	}
+	if autoReplyHandler != nil {
+		autoReplyRouter := routes.NewAutoReplyRouter(autoReplyHandler)
+		autoReplyRouter.SetAuditRecorder(auditStorage)
+		protectedRouters = append(protectedRouters, autoReplyRouter)
+	}
+	routes.RegisterAll(router.Group("/api", jwtAuthMiddleware(revocations, rateLimiter)), protectedRouters)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -297,6 +575,23 @@ func main() {
 
 	fmt.Printf("Server running on port %s\n", port)
 
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metricsRegistry.Handler())
+	metricsSrv := &http.Server{
+		Addr:    ":" + metricsPort,
+		Handler: metricsMux,
+	}
+	go func() {
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("[METRICS] metrics server failed port=%s error=%v", metricsPort, err)
+		}
+	}()
+	log.Printf("[METRICS] serving /metrics port=%s", metricsPort)
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -304,30 +599,85 @@ func main() {
 
 	fmt.Println("Shutting down server...")
 
+	// Finish queued analyses before the server stops; new ones are rejected while draining
+	slaTracker.Stop()
+	stuckLeadScanner.Stop()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := analysisPool.Drain(drainCtx); err != nil {
+		log.Printf("[WORKER] %v", err)
+	}
+	drainCancel()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if err := metricsSrv.Shutdown(ctx); err != nil {
+		log.Printf("[METRICS] failed to shut down metrics server: %v", err)
+	}
+
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			log.Printf("[TRACING] failed to flush spans on shutdown: %v", err)
+		}
+	}
 
 	fmt.Println("Server exited")
 }
 
-func corsMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+// newTracerProvider creates a tracer provider batching spans to the OTLP/HTTP collector in
+// OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT). Returns nil when neither is
+// set, leaving the global no-op provider in place. OTEL_SERVICE_NAME overrides serviceName.
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return nil, nil
+	}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
 
-		c.Next()
+// newProviderChain builds the text generation chain in AI_PROVIDER_ORDER. Providers without
+// an API key are left out; geminiClient is the already configured Gemini client.
+func newProviderChain(order []string, geminiClient *ai.Client) *ai.ProviderChain {
+	var providers []ai.Provider
+	for _, name := range order {
+		switch name {
+		case ai.ProviderGemini:
+			providers = append(providers, geminiClient)
+		case ai.ProviderOpenAI:
+			openaiClient, err := openai.NewOpenAIClient()
+			if err != nil {
+				log.Printf("Warning: OpenAI provider disabled: %v", err)
+				continue
+			}
+			providers = append(providers, openaiClient)
+		}
+	}
+	if len(providers) == 0 {
+		log.Printf("Warning: no provider in AI_PROVIDER_ORDER is configured, using Gemini")
+		providers = append(providers, geminiClient)
 	}
+
+	chain := ai.NewProviderChain(providers)
+	log.Printf("[AI] text generation providers=%v", chain.Providers())
+	return chain
 }
 
 func loggingMiddleware() gin.HandlerFunc {
@@ -345,7 +695,23 @@ func loggingMiddleware() gin.HandlerFunc {
 	}
 }
 
-func jwtAuthMiddleware() gin.HandlerFunc {
+// tenantFromToken returns the tenant of the request's access token, or "" when there is no valid
+// token (jwtAuthMiddleware rejects those requests)
+func tenantFromToken(c *gin.Context) string {
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return ""
+	}
+	claims, err := auth.ValidateToken(parts[1])
+	if err != nil {
+		return ""
+	}
+	return claims.TenantID
+}
+
+// jwtAuthMiddleware authenticates requests by access token, rejecting tokens revoked by logout.
+// Gemini calls made while handling the request count against the tenant's AI quota.
+func jwtAuthMiddleware(revocations *auth.RevocationCache, rateLimiter *ratelimit.TenantRateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -372,6 +738,11 @@ func jwtAuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if revocations.IsRevoked(claims.ID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			c.Abort()
+			return
+		}
 
 		// Log extracted claims for debugging
 		log.Printf("[JWT] Extracted claims - UserID: %s, TenantID: %s, Role: %s", claims.UserID, claims.TenantID, claims.Role)
@@ -390,20 +761,8 @@ func jwtAuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("tenant_id", claims.TenantID)
 		c.Set("role", claims.Role)
+		c.Request = c.Request.WithContext(ai.WithCallQuota(c.Request.Context(), rateLimiter.AIQuota(claims.TenantID)))
 
 		c.Next()
 	}
 }
-
-func adminMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		if role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-			c.Abort()
-			return
-		}
-		c.Next()
-	}
-}
-
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"fmt"
@@ -8,12 +9,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/chroma"
 	"ai-conversation-platform/internal/storage/postgres"
 )
 
 func main() {
 	var direction string
+	var reembedProducts bool
+	var steps int
 	flag.StringVar(&direction, "direction", "up", "Migration direction: up or down")
+	flag.IntVar(&steps, "steps", 0, "Number of migrations to roll back with -direction=down (0 rolls back all)")
+	flag.BoolVar(&reembedProducts, "reembed-products", false, "Delete and re-embed all products as section chunks")
 	flag.Parse()
 
 	client, err := postgres.NewClient()
@@ -23,47 +33,279 @@ func main() {
 	}
 	defer client.Close()
 
-	if direction == "up" {
+	switch direction {
+	case "up":
 		if err := runMigrations(client.DB); err != nil {
 			fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
 			os.Exit(1)
 		}
 		fmt.Println("Migrations completed successfully")
-	} else {
-		fmt.Println("Down migrations not implemented in MVP")
+
+		if reembedProducts {
+			if err := reembedAllProducts(client); err != nil {
+				fmt.Fprintf(os.Stderr, "Product re-embedding failed: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	case "down":
+		if err := runDownMigrations(client.DB, steps); err != nil {
+			fmt.Fprintf(os.Stderr, "Rollback failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Rollback completed successfully")
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown direction %q: use up or down\n", direction)
+		os.Exit(1)
 	}
 }
 
-func runMigrations(db *sql.DB) error {
-	migrations := []string{
-		createUsersTable,
-		createConversationsTable,
-		createMessagesTable,
-		createConversationMetadataTable,
-		createCustomerMemoryTable,
-		createRulesTable,
-		createBrandToneTable,
-		createProductsTable,
-		createAutoReplyGlobalTable,
-		createAutoReplyConversationsTable,
-		createSuggestionsTable,
-	}
-
-	for i, migration := range migrations {
-		if _, err := db.Exec(migration); err != nil {
-			return fmt.Errorf("migration %d failed: %w", i+1, err)
+// migration is a reversible schema change. Migrations are applied in ascending version order and
+// rolled back in descending order, so new migrations are appended with the next version.
+type migration struct {
+	version int
+	name    string
+	up      func(db *sql.DB) error
+	down    func(db *sql.DB) error
+}
+
+// execStatements returns a migration step that runs the given statements in order
+func execStatements(statements ...string) func(db *sql.DB) error {
+	return func(db *sql.DB) error {
+		for _, stmt := range statements {
+			if _, err := db.Exec(stmt); err != nil {
+				return err
+			}
 		}
-		fmt.Printf("Migration %d completed\n", i+1)
+		return nil
+	}
+}
+
+// tableMigration creates a table (with its indexes) and drops it on rollback
+func tableMigration(version int, table, create, drop string) migration {
+	return migration{version: version, name: "create " + table, up: execStatements(create), down: execStatements(drop)}
+}
+
+// columnMigration adds a column and drops it on rollback. Indexes on the column are created after
+// it is added and dropped before it is removed, since SQLite can't drop an indexed column.
+func columnMigration(version int, table, column, definition string, indexes ...string) migration {
+	return migration{
+		version: version,
+		name:    fmt.Sprintf("add %s.%s", table, column),
+		up: func(db *sql.DB) error {
+			if err := addColumnIfMissing(db, table, column, definition); err != nil {
+				return err
+			}
+			for _, index := range indexes {
+				stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(%s)", index, table, column)
+				if _, err := db.Exec(stmt); err != nil {
+					return fmt.Errorf("failed to create index %s: %w", index, err)
+				}
+			}
+			return nil
+		},
+		down: func(db *sql.DB) error {
+			for _, index := range indexes {
+				if _, err := db.Exec("DROP INDEX IF EXISTS " + index); err != nil {
+					return fmt.Errorf("failed to drop index %s: %w", index, err)
+				}
+			}
+			return dropColumnIfExists(db, table, column)
+		},
 	}
+}
+
+// migrations lists every schema change in the order it is applied
+var migrations = []migration{
+	tableMigration(1, "users", createUsersTable, dropUsersTable),
+	tableMigration(2, "conversations", createConversationsTable, dropConversationsTable),
+	tableMigration(3, "messages", createMessagesTable, dropMessagesTable),
+	tableMigration(4, "conversation_metadata", createConversationMetadataTable, dropConversationMetadataTable),
+	tableMigration(5, "customer_memory", createCustomerMemoryTable, dropCustomerMemoryTable),
+	tableMigration(6, "rules", createRulesTable, dropRulesTable),
+	tableMigration(7, "brand_tone", createBrandToneTable, dropBrandToneTable),
+	tableMigration(8, "products", createProductsTable, dropProductsTable),
+	tableMigration(9, "auto_reply_global", createAutoReplyGlobalTable, dropAutoReplyGlobalTable),
+	tableMigration(10, "auto_reply_conversations", createAutoReplyConversationsTable, dropAutoReplyConversationsTable),
+	tableMigration(11, "suggestions", createSuggestionsTable, dropSuggestionsTable),
+	tableMigration(12, "suggestion_feedback", createSuggestionFeedbackTable, dropSuggestionFeedbackTable),
+	tableMigration(13, "cors_config", createCORSConfigTable, dropCORSConfigTable),
+	tableMigration(14, "transfer_events", createTransferEventsTable, dropTransferEventsTable),
+	tableMigration(15, "lead_stage_transitions", createLeadStageTransitionsTable, dropLeadStageTransitionsTable),
+	tableMigration(16, "hot_lead_alerts", createHotLeadAlertsTable, dropHotLeadAlertsTable),
+	tableMigration(17, "message_deletions", createMessageDeletionsTable, dropMessageDeletionsTable),
+	tableMigration(18, "pricing_suggestions", createPricingSuggestionsTable, dropPricingSuggestionsTable),
+	tableMigration(19, "tenant_api_credentials", createTenantAPICredentialsTable, dropTenantAPICredentialsTable),
+	tableMigration(20, "tenant_slack_config", createTenantSlackConfigTable, dropTenantSlackConfigTable),
+	tableMigration(21, "notifications", createNotificationsTable, dropNotificationsTable),
+	tableMigration(22, "model_calibrations", createModelCalibrationsTable, dropModelCalibrationsTable),
+	tableMigration(23, "audit_logs", createAuditLogsTable, dropAuditLogsTable),
+	tableMigration(24, "tenant_ai_config", createTenantAIConfigTable, dropTenantAIConfigTable),
+	tableMigration(25, "watchlist", createWatchlistTable, dropWatchlistTable),
+	tableMigration(26, "knowledge_articles", createKnowledgeArticlesTable, dropKnowledgeArticlesTable),
+	tableMigration(27, "agent_suggestion_profiles", createAgentSuggestionProfilesTable, dropAgentSuggestionProfilesTable),
+	tableMigration(28, "knowledge_article_versions", createKnowledgeArticleVersionsTable, dropKnowledgeArticleVersionsTable),
+	tableMigration(29, "message_reads", createMessageReadsTable, dropMessageReadsTable),
+	tableMigration(30, "ai_usage_events", createAIUsageEventsTable, dropAIUsageEventsTable),
+	tableMigration(31, "crm_field_mappings", createCRMFieldMappingsTable, dropCRMFieldMappingsTable),
+	tableMigration(32, "refresh_tokens", createRefreshTokensTable, dropRefreshTokensTable),
+
+	// Handle product_id and customer_id column additions separately (SQLite compatibility)
+	{version: 33, name: "add conversations.product_id", up: addProductIdColumn, down: dropProductIdColumn},
+	{version: 34, name: "add conversations.customer_id", up: addCustomerIdColumn, down: dropCustomerIdColumn},
+
+	// Agent assignment used by leaderboard and performance analytics
+	columnMigration(35, "conversations", "assigned_agent_id", "TEXT", "idx_conversations_assigned_agent_id"),
+
+	// Conversation complexity score (1-10) computed during analysis
+	columnMigration(36, "conversation_metadata", "complexity_score", "REAL"),
+
+	// Model that produced the (normalized) sentiment score
+	columnMigration(37, "conversation_metadata", "sentiment_model", "TEXT"),
+
+	// How a closed conversation ended (deal_won, deal_lost, resolved, abandoned); drives dashboard win rate
+	columnMigration(38, "conversations", "resolution_type", "TEXT"),
+	{
+		version: 39,
+		name:    "index conversations(tenant_id, created_at)",
+		up:      execStatements("CREATE INDEX IF NOT EXISTS idx_conversations_tenant_created ON conversations(tenant_id, created_at)"),
+		down:    execStatements("DROP INDEX IF EXISTS idx_conversations_tenant_created"),
+	},
+
+	// Slack channel for watchlisted conversations (defaults to #executive-watchlist when empty)
+	columnMigration(40, "tenant_slack_config", "watchlist_channel", "TEXT NOT NULL DEFAULT ''"),
+
+	// Agent-set conversation language for code-mixed messages; copied from customer memory when
+	// the customer has a language override so it persists across conversations
+	columnMigration(41, "conversations", "override_language", "TEXT", "idx_conversations_override_language"),
+	columnMigration(42, "customer_memory", "language_override", "BOOLEAN NOT NULL DEFAULT FALSE"),
+
+	// Reply suggestions generated per request; NULL uses SUGGESTION_COUNT_DEFAULT (3)
+	columnMigration(43, "tenant_ai_config", "suggestions_count", "INTEGER"),
+
+	// User deactivation (soft delete from the admin user API)
+	columnMigration(44, "users", "deactivated_at", "TIMESTAMP"),
+
+	// Knowledge article version numbers (history lives in knowledge_article_versions)
+	columnMigration(45, "knowledge_articles", "version", "INTEGER NOT NULL DEFAULT 1"),
+
+	// Transcript emails are sent at most once per conversation
+	columnMigration(46, "conversations", "transcript_sent_at", "TIMESTAMP"),
+
+	// Agent messages sent automatically by auto-reply
+	columnMigration(47, "messages", "is_auto_reply", "BOOLEAN NOT NULL DEFAULT FALSE"),
+	// Confidence of the suggestion an auto-reply was sent from (NULL for other messages)
+	columnMigration(48, "messages", "suggestion_confidence", "REAL"),
+
+	// Conversation soft delete; soft-deleted conversations are purged after RETENTION_DAYS
+	columnMigration(49, "conversations", "deleted_at", "TIMESTAMP"),
+	columnMigration(50, "conversations", "deleted_by", "TEXT"),
+
+	// Message soft delete (GDPR, abuse, error corrections)
+	columnMigration(51, "messages", "deleted_at", "TIMESTAMP"),
+	columnMigration(52, "messages", "deleted_by", "TEXT"),
 
-	// Handle product_id column addition separately (SQLite compatibility)
-	if err := addProductIdColumn(db); err != nil {
-		return fmt.Errorf("failed to add product_id column: %w", err)
+	// Full-text search over message content (PostgreSQL only; SQLite searches with LIKE)
+	{version: 53, name: "add messages.content_tsv", up: addMessageContentTSV, down: dropMessageContentTSV},
+
+	// Free-text brand tone descriptions instead of three fixed tones
+	{version: 54, name: "allow custom brand_tone.tone", up: allowCustomBrandTone, down: restrictBrandTone},
+
+	// Agent response SLA tracking
+	tableMigration(55, "tenant_sla_config", createTenantSLAConfigTable, dropTenantSLAConfigTable),
+	tableMigration(56, "sla_breaches", createSLABreachesTable, dropSLABreachesTable),
+
+	// Outgoing webhooks for conversation events
+	tableMigration(57, "webhooks", createWebhooksTable, dropWebhooksTable),
+
+	// Agent-defined conversation tags
+	tableMigration(58, "tags", createTagsTable, dropTagsTable),
+	tableMigration(59, "conversation_tags", createConversationTagsTable, dropConversationTagsTable),
+
+	// Platform super admins, who can erase a tenant's data
+	{version: 60, name: "allow users.role super_admin", up: allowSuperAdminRole, down: disallowSuperAdminRole},
+
+	// When a conversation was closed (drives the dashboard's closed_today)
+	columnMigration(61, "conversations", "closed_at", "TIMESTAMP"),
+
+	// Product pricing tiers (monthly/annual, seats)
+	tableMigration(62, "product_variants", createProductVariantsTable, dropProductVariantsTable),
+
+	// Objections raised in a conversation and whether an agent resolved them
+	tableMigration(63, "objection_resolutions", createObjectionResolutionsTable, dropObjectionResolutionsTable),
+
+	// How sure language detection was about each message's language
+	columnMigration(64, "messages", "language_confidence", "REAL NOT NULL DEFAULT 0"),
+
+	// Rules assigning new conversations to agents by intent, sentiment, product, language or urgency
+	tableMigration(65, "routing_rules", createRoutingRulesTable, dropRoutingRulesTable),
+
+	// Products recommended in reply suggestions and whether agents accepted them
+	tableMigration(66, "product_recommendations", createProductRecommendationsTable, dropProductRecommendationsTable),
+
+	// Business hours that limit when auto-reply answers, and what it says outside them
+	tableMigration(67, "business_hours_config", createBusinessHoursConfigTable, dropBusinessHoursConfigTable),
+	columnMigration(68, "auto_reply_global", "out_of_hours_message", "TEXT"),
+
+	// Embedding model each vector collection is indexed with, so a model change triggers a re-index
+	tableMigration(69, "embedding_versions", createEmbeddingVersionsTable, dropEmbeddingVersionsTable),
+
+	// Every analysis of a conversation, not just the latest kept in conversation_metadata
+	tableMigration(70, "conversation_analysis_history", createConversationAnalysisHistoryTable, dropConversationAnalysisHistoryTable),
+
+	// Hierarchical product categories; products.category is kept for backward compatibility
+	tableMigration(71, "product_categories", createProductCategoriesTable, dropProductCategoriesTable),
+	columnMigration(72, "products", "category_id", "TEXT REFERENCES product_categories(id)", "idx_products_category_id"),
+
+	// Escalation of conversations whose sentiment drops or that match an escalate rule
+	{version: 73, name: "allow rules.action escalate", up: allowEscalateRuleAction, down: disallowEscalateRuleAction},
+	tableMigration(74, "escalation_events", createEscalationEventsTable, dropEscalationEventsTable),
+
+	// Per-product response and resolution SLA, overriding tenant_sla_config
+	tableMigration(75, "product_sla_config", createProductSLAConfigTable, dropProductSLAConfigTable),
+
+	// Invitations for admins to onboard agents and admins
+	tableMigration(76, "user_invitations", createUserInvitationsTable, dropUserInvitationsTable),
+
+	// Customer questions product knowledge had no answer for
+	tableMigration(77, "knowledge_gaps", createKnowledgeGapsTable, dropKnowledgeGapsTable),
+
+	// Per-tenant analytics weights and thresholds, overriding DefaultAnalyticsConfig
+	tableMigration(78, "tenant_analytics_config", createTenantAnalyticsConfigTable, dropTenantAnalyticsConfigTable),
+
+	// Tenant keyword lists messages are moderated against, and what moderation flagged or blocked
+	tableMigration(79, "moderation_lists", createModerationListsTable, dropModerationListsTable),
+	tableMigration(80, "moderation_events", createModerationEventsTable, dropModerationEventsTable),
+
+	// Cached AI summaries of conversations, regenerated as new messages arrive
+	tableMigration(81, "conversation_summaries", createConversationSummariesTable, dropConversationSummariesTable),
+
+	// Agents' internal notes on conversations
+	tableMigration(82, "conversation_notes", createConversationNotesTable, dropConversationNotesTable),
+
+	// Agent whose profile personalized cached suggestions; '' for unpersonalized ones
+	columnMigration(83, "suggestions", "agent_id", "TEXT NOT NULL DEFAULT ''"),
+}
+
+// runMigrations applies every migration not yet recorded in schema_migrations, then seeds the
+// demo products. Migrations don't run in a transaction: PostgreSQL aborts a transaction on the
+// "already exists" errors the column steps swallow, and every step is safe to re-run anyway.
+func runMigrations(db *sql.DB) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
 	}
 
-	// Handle customer_id column addition separately (SQLite compatibility)
-	if err := addCustomerIdColumn(db); err != nil {
-		return fmt.Errorf("failed to add customer_id column: %w", err)
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := m.up(db); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		if err := recordMigration(db, m.version, "up"); err != nil {
+			return err
+		}
+		fmt.Printf("Migration %d completed (%s)\n", m.version, m.name)
 	}
 
 	// Seed demo products
@@ -74,6 +316,109 @@ func runMigrations(db *sql.DB) error {
 	return nil
 }
 
+// runDownMigrations rolls back the most recently applied migrations, newest first. steps limits
+// how many are rolled back; 0 rolls back all of them.
+func runDownMigrations(db *sql.DB, steps int) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	rolledBack := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if steps > 0 && rolledBack == steps {
+			break
+		}
+		m := migrations[i]
+		if !applied[m.version] {
+			continue
+		}
+		if err := m.down(db); err != nil {
+			return fmt.Errorf("rollback of migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		if err := recordMigration(db, m.version, "down"); err != nil {
+			return err
+		}
+		rolledBack++
+		fmt.Printf("Migration %d rolled back (%s)\n", m.version, m.name)
+	}
+
+	return nil
+}
+
+// appliedMigrations returns the versions whose latest recorded run was up. Each run appends a row,
+// so a version is applied when it has more up rows than down rows.
+func appliedMigrations(db *sql.DB) (map[int]bool, error) {
+	if _, err := db.Exec(createSchemaMigrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT version, SUM(CASE WHEN direction = 'up' THEN 1 ELSE -1 END)
+		FROM schema_migrations
+		GROUP BY version
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version, balance int
+		if err := rows.Scan(&version, &balance); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = balance > 0
+	}
+	return applied, rows.Err()
+}
+
+// recordMigration appends a run of a migration to schema_migrations
+func recordMigration(db *sql.DB, version int, direction string) error {
+	_, err := db.Exec(
+		"INSERT INTO schema_migrations (id, version, direction, applied_at) VALUES ($1, $2, $3, $4)",
+		uuid.New().String(), version, direction, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d %s: %w", version, direction, err)
+	}
+	return nil
+}
+
+// reembedAllProducts deletes existing product embeddings and re-embeds every
+// product as section chunks, recording the embedding model as the collection's
+// version. Requires Gemini and Chroma to be reachable.
+func reembedAllProducts(client *postgres.Client) error {
+	geminiClient, err := ai.NewGeminiClient()
+	if err != nil {
+		return fmt.Errorf("failed to create gemini client: %w", err)
+	}
+
+	chromaClient, err := chroma.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create chroma client: %w", err)
+	}
+	if err := chromaClient.HealthCheck(); err != nil {
+		return fmt.Errorf("chroma not available: %w", err)
+	}
+
+	embeddingService := ai.NewEmbeddingService(geminiClient, chromaClient)
+	embeddingService.SetBatchDelay(ai.BatchDelayFromEnv())
+	reindexer := ai.NewEmbeddingReindexer(
+		embeddingService,
+		postgres.NewEmbeddingVersionStorage(client),
+		postgres.NewProductStorage(client),
+		postgres.NewProductVariantStorage(client),
+	)
+	if err := reindexer.Reindex(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Re-embedded all products as section chunks with %s\n", embeddingService.EmbeddingModel())
+	return nil
+}
+
 // addProductIdColumn adds product_id column to conversations table
 // Handles both SQLite and PostgreSQL by attempting to add and ignoring if already exists
 func addProductIdColumn(db *sql.DB) error {
@@ -93,10 +438,271 @@ func addProductIdColumn(db *sql.DB) error {
 	return err
 }
 
+// dropProductIdColumn removes product_id and its index from the conversations table
+func dropProductIdColumn(db *sql.DB) error {
+	if _, err := db.Exec("DROP INDEX IF EXISTS idx_conversations_product_id"); err != nil {
+		return err
+	}
+	return dropColumnIfExists(db, "conversations", "product_id")
+}
+
+// addColumnIfMissing adds a column to a table, ignoring the error if it already exists
+// Handles both SQLite and PostgreSQL since neither supports ADD COLUMN IF NOT EXISTS uniformly
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if !contains(errStr, "duplicate column") && !contains(errStr, "already exists") {
+			return err
+		}
+	}
+	return nil
+}
+
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
 
+// dropColumnIfExists drops a column from a table, ignoring the error if it was already removed.
+// SQLite 3.35+ supports DROP COLUMN; neither database supports DROP COLUMN IF EXISTS uniformly.
+func dropColumnIfExists(db *sql.DB, table, column string) error {
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, column))
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if !contains(errStr, "no such column") && !contains(errStr, "does not exist") && !contains(errStr, "no such table") {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSQLite reports whether db is a SQLite database
+func isSQLite(db *sql.DB) bool {
+	_, ok := db.Driver().(*sqlite3.SQLiteDriver)
+	return ok
+}
+
+// addMessageContentTSV adds a generated tsvector of message content with a GIN index for
+// conversation search. SQLite has no tsvector, so it is skipped there.
+func addMessageContentTSV(db *sql.DB) error {
+	if isSQLite(db) {
+		return nil
+	}
+	if err := addColumnIfMissing(db, "messages", "content_tsv", "tsvector GENERATED ALWAYS AS (to_tsvector('english', content)) STORED"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_messages_content_tsv ON messages USING GIN(content_tsv)"); err != nil {
+		return fmt.Errorf("failed to create index idx_messages_content_tsv: %w", err)
+	}
+	return nil
+}
+
+// dropMessageContentTSV removes the message search column and its index
+func dropMessageContentTSV(db *sql.DB) error {
+	if isSQLite(db) {
+		return nil
+	}
+	if _, err := db.Exec("DROP INDEX IF EXISTS idx_messages_content_tsv"); err != nil {
+		return fmt.Errorf("failed to drop index idx_messages_content_tsv: %w", err)
+	}
+	return dropColumnIfExists(db, "messages", "content_tsv")
+}
+
+// brandToneFixedValues is the CHECK on brand_tone.tone before migration 54
+const brandToneFixedValues = "tone IN ('Professional', 'Friendly', 'Sales-focused')"
+
+// brandToneMaxLength is the CHECK on brand_tone.tone from migration 54 on
+const brandToneMaxLength = "length(tone) BETWEEN 1 AND 500"
+
+// allowCustomBrandTone replaces the fixed brand tone values with a length limit
+func allowCustomBrandTone(db *sql.DB) error {
+	return replaceBrandToneCheck(db, brandToneMaxLength)
+}
+
+// restrictBrandTone restores the fixed brand tone values; custom tones fall back to Professional
+func restrictBrandTone(db *sql.DB) error {
+	if _, err := db.Exec("UPDATE brand_tone SET tone = 'Professional' WHERE NOT (" + brandToneFixedValues + ")"); err != nil {
+		return fmt.Errorf("failed to reset custom brand tones: %w", err)
+	}
+	return replaceBrandToneCheck(db, brandToneFixedValues)
+}
+
+// replaceBrandToneCheck swaps the CHECK constraint on brand_tone.tone. SQLite can't alter
+// constraints, so the table is rebuilt there.
+func replaceBrandToneCheck(db *sql.DB, check string) error {
+	if !isSQLite(db) {
+		return execStatements(
+			"ALTER TABLE brand_tone DROP CONSTRAINT IF EXISTS brand_tone_tone_check",
+			"ALTER TABLE brand_tone ADD CONSTRAINT brand_tone_tone_check CHECK ("+check+")",
+		)(db)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`CREATE TABLE brand_tone_new (
+	tenant_id TEXT PRIMARY KEY,
+	tone TEXT NOT NULL CHECK(` + check + `),
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+		"INSERT INTO brand_tone_new (tenant_id, tone, updated_at) SELECT tenant_id, tone, updated_at FROM brand_tone",
+		"DROP TABLE brand_tone",
+		"ALTER TABLE brand_tone_new RENAME TO brand_tone",
+		"CREATE INDEX IF NOT EXISTS idx_brand_tone_tenant_id ON brand_tone(tenant_id)",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild brand_tone: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// userRolesBase is the CHECK on users.role before migration 60
+const userRolesBase = "role IN ('customer', 'agent', 'admin')"
+
+// userRolesWithSuperAdmin is the CHECK on users.role from migration 60 on
+const userRolesWithSuperAdmin = "role IN ('customer', 'agent', 'admin', 'super_admin')"
+
+// allowSuperAdminRole adds super_admin to the allowed user roles
+func allowSuperAdminRole(db *sql.DB) error {
+	return replaceUserRoleCheck(db, userRolesWithSuperAdmin)
+}
+
+// disallowSuperAdminRole removes the super_admin role; super admins are demoted to admin
+func disallowSuperAdminRole(db *sql.DB) error {
+	if _, err := db.Exec("UPDATE users SET role = 'admin' WHERE role = 'super_admin'"); err != nil {
+		return fmt.Errorf("failed to demote super admins: %w", err)
+	}
+	return replaceUserRoleCheck(db, userRolesBase)
+}
+
+// replaceUserRoleCheck swaps the CHECK constraint on users.role. SQLite can't alter constraints,
+// so the table is rebuilt there, with foreign keys off since transfer_events references users.
+func replaceUserRoleCheck(db *sql.DB, check string) error {
+	if !isSQLite(db) {
+		return execStatements(
+			"ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check",
+			"ALTER TABLE users ADD CONSTRAINT users_role_check CHECK ("+check+")",
+		)(db)
+	}
+
+	// PRAGMA foreign_keys applies per connection and is ignored inside a transaction
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	var foreignKeys int
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		return fmt.Errorf("failed to read foreign_keys: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, fmt.Sprintf("PRAGMA foreign_keys = %d", foreignKeys))
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`CREATE TABLE users_new (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	password_hash TEXT NOT NULL,
+	role TEXT NOT NULL CHECK(` + check + `),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	deactivated_at TIMESTAMP
+)`,
+		`INSERT INTO users_new (id, tenant_id, email, password_hash, role, created_at, updated_at, deactivated_at)
+	SELECT id, tenant_id, email, password_hash, role, created_at, updated_at, deactivated_at FROM users`,
+		"DROP TABLE users",
+		"ALTER TABLE users_new RENAME TO users",
+		"CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id)",
+		"CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to rebuild users: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ruleActionsBase is the CHECK on rules.action before migration 73
+const ruleActionsBase = "action IN ('block', 'auto_correct', 'flag')"
+
+// ruleActionsWithEscalate is the CHECK on rules.action from migration 73 on
+const ruleActionsWithEscalate = "action IN ('block', 'auto_correct', 'flag', 'escalate')"
+
+// allowEscalateRuleAction adds escalate to the allowed rule actions
+func allowEscalateRuleAction(db *sql.DB) error {
+	return replaceRuleActionCheck(db, ruleActionsWithEscalate)
+}
+
+// disallowEscalateRuleAction removes the escalate action; escalate rules are deleted
+func disallowEscalateRuleAction(db *sql.DB) error {
+	if _, err := db.Exec("DELETE FROM rules WHERE action = 'escalate'"); err != nil {
+		return fmt.Errorf("failed to delete escalate rules: %w", err)
+	}
+	return replaceRuleActionCheck(db, ruleActionsBase)
+}
+
+// replaceRuleActionCheck swaps the CHECK constraint on rules.action. SQLite can't alter
+// constraints, so the table is rebuilt there.
+func replaceRuleActionCheck(db *sql.DB, check string) error {
+	if !isSQLite(db) {
+		return execStatements(
+			"ALTER TABLE rules DROP CONSTRAINT IF EXISTS rules_action_check",
+			"ALTER TABLE rules ADD CONSTRAINT rules_action_check CHECK ("+check+")",
+		)(db)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`CREATE TABLE rules_new (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT,
+	type TEXT NOT NULL,
+	pattern TEXT NOT NULL,
+	action TEXT NOT NULL CHECK(` + check + `),
+	is_active BOOLEAN NOT NULL DEFAULT true,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+		`INSERT INTO rules_new (id, tenant_id, name, description, type, pattern, action, is_active, created_at, updated_at)
+	SELECT id, tenant_id, name, description, type, pattern, action, is_active, created_at, updated_at FROM rules`,
+		"DROP TABLE rules",
+		"ALTER TABLE rules_new RENAME TO rules",
+		"CREATE INDEX IF NOT EXISTS idx_rules_tenant_id ON rules(tenant_id)",
+		"CREATE INDEX IF NOT EXISTS idx_rules_is_active ON rules(is_active)",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild rules: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
 // addCustomerIdColumn adds customer_id column to conversations table
 // Handles both SQLite and PostgreSQL by attempting to add and ignoring if already exists
 func addCustomerIdColumn(db *sql.DB) error {
@@ -121,6 +727,17 @@ func addCustomerIdColumn(db *sql.DB) error {
 	return err
 }
 
+// dropCustomerIdColumn removes customer_id and its indexes from the conversations table
+func dropCustomerIdColumn(db *sql.DB) error {
+	if _, err := db.Exec("DROP INDEX IF EXISTS idx_conversations_customer_status"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("DROP INDEX IF EXISTS idx_conversations_customer_id"); err != nil {
+		return err
+	}
+	return dropColumnIfExists(db, "conversations", "customer_id")
+}
+
 // seedDemoProducts seeds the 5 demo products
 func seedDemoProducts(db *sql.DB) error {
 	tenantID := "OMX26"
@@ -220,6 +837,19 @@ func seedDemoProducts(db *sql.DB) error {
 	return nil
 }
 
+// createSchemaMigrationsTable tracks migration runs. Rows are appended for every up and down run
+// rather than updated, so the table doubles as a history.
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	id TEXT PRIMARY KEY,
+	version INTEGER NOT NULL,
+	direction TEXT NOT NULL CHECK(direction IN ('up', 'down')),
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_schema_migrations_version ON schema_migrations(version);
+`
+
 const createUsersTable = `
 CREATE TABLE IF NOT EXISTS users (
 	id TEXT PRIMARY KEY,
@@ -235,6 +865,8 @@ CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id);
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 `
 
+const dropUsersTable = `DROP TABLE IF EXISTS users;`
+
 const createConversationsTable = `
 CREATE TABLE IF NOT EXISTS conversations (
 	id TEXT PRIMARY KEY,
@@ -248,6 +880,8 @@ CREATE INDEX IF NOT EXISTS idx_conversations_tenant_id ON conversations(tenant_i
 CREATE INDEX IF NOT EXISTS idx_conversations_status ON conversations(status);
 `
 
+const dropConversationsTable = `DROP TABLE IF EXISTS conversations;`
+
 const createMessagesTable = `
 CREATE TABLE IF NOT EXISTS messages (
 	id TEXT PRIMARY KEY,
@@ -265,6 +899,8 @@ CREATE INDEX IF NOT EXISTS idx_messages_conversation_id ON messages(conversation
 CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
 `
 
+const dropMessagesTable = `DROP TABLE IF EXISTS messages;`
+
 const createConversationMetadataTable = `
 CREATE TABLE IF NOT EXISTS conversation_metadata (
 	id TEXT PRIMARY KEY,
@@ -282,6 +918,8 @@ CREATE TABLE IF NOT EXISTS conversation_metadata (
 CREATE INDEX IF NOT EXISTS idx_metadata_conversation_id ON conversation_metadata(conversation_id);
 `
 
+const dropConversationMetadataTable = `DROP TABLE IF EXISTS conversation_metadata;`
+
 const createCustomerMemoryTable = `
 CREATE TABLE IF NOT EXISTS customer_memory (
 	id TEXT PRIMARY KEY,
@@ -299,6 +937,8 @@ CREATE INDEX IF NOT EXISTS idx_memory_tenant_id ON customer_memory(tenant_id);
 CREATE INDEX IF NOT EXISTS idx_memory_customer_id ON customer_memory(customer_id);
 `
 
+const dropCustomerMemoryTable = `DROP TABLE IF EXISTS customer_memory;`
+
 const createRulesTable = `
 CREATE TABLE IF NOT EXISTS rules (
 	id TEXT PRIMARY KEY,
@@ -317,6 +957,8 @@ CREATE INDEX IF NOT EXISTS idx_rules_tenant_id ON rules(tenant_id);
 CREATE INDEX IF NOT EXISTS idx_rules_is_active ON rules(is_active);
 `
 
+const dropRulesTable = `DROP TABLE IF EXISTS rules;`
+
 const createBrandToneTable = `
 CREATE TABLE IF NOT EXISTS brand_tone (
 	tenant_id TEXT PRIMARY KEY,
@@ -327,6 +969,8 @@ CREATE TABLE IF NOT EXISTS brand_tone (
 CREATE INDEX IF NOT EXISTS idx_brand_tone_tenant_id ON brand_tone(tenant_id);
 `
 
+const dropBrandToneTable = `DROP TABLE IF EXISTS brand_tone;`
+
 const createProductsTable = `
 CREATE TABLE IF NOT EXISTS products (
 	id TEXT PRIMARY KEY,
@@ -348,6 +992,8 @@ CREATE INDEX IF NOT EXISTS idx_products_tenant_id ON products(tenant_id);
 CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
 `
 
+const dropProductsTable = `DROP TABLE IF EXISTS products;`
+
 const createAutoReplyGlobalTable = `
 CREATE TABLE IF NOT EXISTS auto_reply_global (
 	tenant_id TEXT PRIMARY KEY,
@@ -359,6 +1005,8 @@ CREATE TABLE IF NOT EXISTS auto_reply_global (
 CREATE INDEX IF NOT EXISTS idx_autoreply_global_tenant_id ON auto_reply_global(tenant_id);
 `
 
+const dropAutoReplyGlobalTable = `DROP TABLE IF EXISTS auto_reply_global;`
+
 const createAutoReplyConversationsTable = `
 CREATE TABLE IF NOT EXISTS auto_reply_conversations (
 	conversation_id TEXT PRIMARY KEY,
@@ -371,6 +1019,8 @@ CREATE TABLE IF NOT EXISTS auto_reply_conversations (
 CREATE INDEX IF NOT EXISTS idx_autoreply_conversations_id ON auto_reply_conversations(conversation_id);
 `
 
+const dropAutoReplyConversationsTable = `DROP TABLE IF EXISTS auto_reply_conversations;`
+
 const createSuggestionsTable = `
 CREATE TABLE IF NOT EXISTS suggestions (
 	id TEXT PRIMARY KEY,
@@ -387,3 +1037,691 @@ CREATE INDEX IF NOT EXISTS idx_suggestions_conversation_message ON suggestions(c
 CREATE INDEX IF NOT EXISTS idx_suggestions_conversation_id ON suggestions(conversation_id);
 `
 
+const dropSuggestionsTable = `DROP TABLE IF EXISTS suggestions;`
+
+const createSuggestionFeedbackTable = `
+CREATE TABLE IF NOT EXISTS suggestion_feedback (
+	id TEXT PRIMARY KEY,
+	suggestion_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	agent_id TEXT,
+	action TEXT NOT NULL CHECK(action IN ('accepted', 'rejected', 'edited')),
+	edited_text TEXT,
+	feedback_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_suggestion_feedback_tenant_id ON suggestion_feedback(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_suggestion_feedback_agent_id ON suggestion_feedback(agent_id);
+CREATE INDEX IF NOT EXISTS idx_suggestion_feedback_conversation_id ON suggestion_feedback(conversation_id);
+`
+
+const dropSuggestionFeedbackTable = `DROP TABLE IF EXISTS suggestion_feedback;`
+
+const createCORSConfigTable = `
+CREATE TABLE IF NOT EXISTS cors_config (
+	tenant_id TEXT PRIMARY KEY,
+	allowed_origins TEXT NOT NULL DEFAULT '[]', -- JSON array stored as text
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropCORSConfigTable = `DROP TABLE IF EXISTS cors_config;`
+
+const createTransferEventsTable = `
+CREATE TABLE IF NOT EXISTS transfer_events (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	from_agent_id TEXT,
+	to_agent_id TEXT NOT NULL,
+	reason TEXT,
+	transferred_by TEXT NOT NULL,
+	transferred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+	FOREIGN KEY (from_agent_id) REFERENCES users(id),
+	FOREIGN KEY (to_agent_id) REFERENCES users(id),
+	FOREIGN KEY (transferred_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_events_conversation_id ON transfer_events(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_events_tenant_id ON transfer_events(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_events_from_agent_id ON transfer_events(from_agent_id);
+`
+
+const dropTransferEventsTable = `DROP TABLE IF EXISTS transfer_events;`
+
+const createLeadStageTransitionsTable = `
+CREATE TABLE IF NOT EXISTS lead_stage_transitions (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	from_stage TEXT,
+	to_stage TEXT NOT NULL,
+	transitioned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_stage_transitions_conversation_id ON lead_stage_transitions(conversation_id, transitioned_at);
+CREATE INDEX IF NOT EXISTS idx_lead_stage_transitions_tenant_id ON lead_stage_transitions(tenant_id);
+`
+
+const dropLeadStageTransitionsTable = `DROP TABLE IF EXISTS lead_stage_transitions;`
+
+const createHotLeadAlertsTable = `
+CREATE TABLE IF NOT EXISTS hot_lead_alerts (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	details TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	resolved_at TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_hot_lead_alerts_tenant_id ON hot_lead_alerts(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_hot_lead_alerts_conversation_id ON hot_lead_alerts(conversation_id);
+`
+
+const dropHotLeadAlertsTable = `DROP TABLE IF EXISTS hot_lead_alerts;`
+
+// message_deletions is an audit log and intentionally has no foreign keys so
+// records survive deletion of the underlying conversation
+const createMessageDeletionsTable = `
+CREATE TABLE IF NOT EXISTS message_deletions (
+	id TEXT PRIMARY KEY,
+	message_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	deleted_by TEXT NOT NULL,
+	reason TEXT NOT NULL CHECK(reason IN ('gdpr_request', 'abuse', 'error')),
+	deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_deletions_tenant_id ON message_deletions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_message_deletions_message_id ON message_deletions(message_id);
+`
+
+const dropMessageDeletionsTable = `DROP TABLE IF EXISTS message_deletions;`
+
+const createPricingSuggestionsTable = `
+CREATE TABLE IF NOT EXISTS pricing_suggestions (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	min_price REAL NOT NULL,
+	max_price REAL NOT NULL,
+	confidence REAL NOT NULL DEFAULT 0,
+	reasoning TEXT,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'approved', 'rejected')),
+	approved_by TEXT,
+	approved_at TIMESTAMP,
+	rejected_by TEXT,
+	rejected_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_suggestions_conversation_id ON pricing_suggestions(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_pricing_suggestions_tenant_status ON pricing_suggestions(tenant_id, status);
+`
+
+const dropPricingSuggestionsTable = `DROP TABLE IF EXISTS pricing_suggestions;`
+
+const createTenantAPICredentialsTable = `
+CREATE TABLE IF NOT EXISTS tenant_api_credentials (
+	tenant_id TEXT NOT NULL,
+	provider TEXT NOT NULL CHECK(provider IN ('gemini')),
+	api_key_encrypted TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, provider)
+);
+`
+
+const dropTenantAPICredentialsTable = `DROP TABLE IF EXISTS tenant_api_credentials;`
+
+const createTenantSlackConfigTable = `
+CREATE TABLE IF NOT EXISTS tenant_slack_config (
+	tenant_id TEXT PRIMARY KEY,
+	webhook_url TEXT NOT NULL,
+	hot_lead_channel TEXT NOT NULL DEFAULT '',
+	escalation_channel TEXT NOT NULL DEFAULT '',
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropTenantSlackConfigTable = `DROP TABLE IF EXISTS tenant_slack_config;`
+
+const createNotificationsTable = `
+CREATE TABLE IF NOT EXISTS notifications (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	channel TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'sent', 'failed')),
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications(channel, status, next_attempt_at);
+`
+
+const dropNotificationsTable = `DROP TABLE IF EXISTS notifications;`
+
+const createModelCalibrationsTable = `
+CREATE TABLE IF NOT EXISTS model_calibrations (
+	model_name TEXT NOT NULL,
+	metric TEXT NOT NULL,
+	baseline_mean REAL NOT NULL,
+	baseline_std REAL NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (model_name, metric)
+);
+`
+
+const dropModelCalibrationsTable = `DROP TABLE IF EXISTS model_calibrations;`
+
+const createAuditLogsTable = `
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	user_id TEXT,
+	action TEXT NOT NULL,
+	resource_type TEXT NOT NULL,
+	resource_id TEXT,
+	old_value TEXT, -- JSON stored as text
+	new_value TEXT, -- JSON stored as text
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_tenant_created ON audit_logs(tenant_id, created_at);
+`
+
+const dropAuditLogsTable = `DROP TABLE IF EXISTS audit_logs;`
+
+const createTenantAIConfigTable = `
+CREATE TABLE IF NOT EXISTS tenant_ai_config (
+	tenant_id TEXT PRIMARY KEY,
+	custom_intents TEXT, -- JSON array stored as text; NULL means the default intents
+	intent_descriptions TEXT, -- JSON object stored as text
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropTenantAIConfigTable = `DROP TABLE IF EXISTS tenant_ai_config;`
+
+const createWatchlistTable = `
+CREATE TABLE IF NOT EXISTS watchlist (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	added_by TEXT NOT NULL,
+	priority INTEGER NOT NULL DEFAULT 1,
+	notes TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (tenant_id, conversation_id),
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_conversation_id ON watchlist(conversation_id);
+`
+
+const dropWatchlistTable = `DROP TABLE IF EXISTS watchlist;`
+
+const createKnowledgeArticlesTable = `
+CREATE TABLE IF NOT EXISTS knowledge_articles (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	product_id TEXT,
+	title TEXT NOT NULL,
+	content TEXT NOT NULL,
+	source_url TEXT, -- Original URL for scraped articles, used for re-indexing
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_articles_tenant_id ON knowledge_articles(tenant_id);
+`
+
+const dropKnowledgeArticlesTable = `DROP TABLE IF EXISTS knowledge_articles;`
+
+const createAgentSuggestionProfilesTable = `
+CREATE TABLE IF NOT EXISTS agent_suggestion_profiles (
+	tenant_id TEXT NOT NULL,
+	agent_id TEXT NOT NULL,
+	preferred_tone TEXT NOT NULL,
+	avg_message_length REAL NOT NULL DEFAULT 0, -- words per message
+	common_phrases TEXT NOT NULL DEFAULT '[]', -- JSON array stored as text
+	message_count INTEGER NOT NULL DEFAULT 0,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, agent_id)
+);
+`
+
+const dropAgentSuggestionProfilesTable = `DROP TABLE IF EXISTS agent_suggestion_profiles;`
+
+const createKnowledgeArticleVersionsTable = `
+CREATE TABLE IF NOT EXISTS knowledge_article_versions (
+	id TEXT PRIMARY KEY,
+	article_id TEXT NOT NULL,
+	title TEXT NOT NULL,
+	content TEXT NOT NULL,
+	version INTEGER NOT NULL,
+	changed_by TEXT, -- user whose edit replaced this version
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (article_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_article_versions_article_id ON knowledge_article_versions(article_id);
+`
+
+const dropKnowledgeArticleVersionsTable = `DROP TABLE IF EXISTS knowledge_article_versions;`
+
+const createMessageReadsTable = `
+CREATE TABLE IF NOT EXISTS message_reads (
+	message_id TEXT NOT NULL,
+	reader_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	read_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_reads_message_reader ON message_reads(message_id, reader_id);
+CREATE INDEX IF NOT EXISTS idx_message_reads_tenant_id ON message_reads(tenant_id);
+`
+
+const dropMessageReadsTable = `DROP TABLE IF EXISTS message_reads;`
+
+const createAIUsageEventsTable = `
+CREATE TABLE IF NOT EXISTS ai_usage_events (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	operation TEXT NOT NULL, -- conversation_analysis, reply_suggestions, pricing_suggestion
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_events_tenant_created ON ai_usage_events(tenant_id, created_at);
+`
+
+const dropAIUsageEventsTable = `DROP TABLE IF EXISTS ai_usage_events;`
+
+const createCRMFieldMappingsTable = `
+CREATE TABLE IF NOT EXISTS crm_field_mappings (
+	tenant_id TEXT NOT NULL,
+	crm_type TEXT NOT NULL CHECK(crm_type IN ('hubspot', 'salesforce', 'zoho', 'custom')),
+	mappings TEXT NOT NULL DEFAULT '{}', -- JSON object (platform field -> CRM field) stored as text
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, crm_type)
+);
+`
+
+const dropCRMFieldMappingsTable = `DROP TABLE IF EXISTS crm_field_mappings;`
+
+const createRefreshTokensTable = `
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	jti TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	token_hash TEXT NOT NULL, -- SHA-256 of the token; the token itself is never stored
+	expires_at TIMESTAMP NOT NULL,
+	revoked BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(tenant_id, user_id);
+`
+
+const dropRefreshTokensTable = `DROP TABLE IF EXISTS refresh_tokens;`
+
+const createTenantSLAConfigTable = `
+CREATE TABLE IF NOT EXISTS tenant_sla_config (
+	tenant_id TEXT PRIMARY KEY,
+	response_threshold_minutes INTEGER NOT NULL CHECK(response_threshold_minutes > 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropTenantSLAConfigTable = `DROP TABLE IF EXISTS tenant_sla_config;`
+
+const createSLABreachesTable = `
+CREATE TABLE IF NOT EXISTS sla_breaches (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	customer_message_id TEXT NOT NULL UNIQUE,
+	expected_response_by TIMESTAMP NOT NULL,
+	responded_at TIMESTAMP, -- NULL until an agent replies
+	breached BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_conversation ON sla_breaches(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_open ON sla_breaches(expected_response_by) WHERE responded_at IS NULL AND breached = FALSE;
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_tenant ON sla_breaches(tenant_id, expected_response_by);
+`
+
+const dropSLABreachesTable = `DROP TABLE IF EXISTS sla_breaches;`
+
+const createWebhooksTable = `
+CREATE TABLE IF NOT EXISTS webhooks (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL, -- HMAC-SHA256 signing key, needed in plain text to sign deliveries
+	events TEXT NOT NULL DEFAULT '[]', -- JSON array of subscribed event names stored as text
+	crm_type TEXT, -- When set, payloads are renamed with the tenant's CRM field mapping
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_tenant ON webhooks(tenant_id);
+`
+
+const dropWebhooksTable = `DROP TABLE IF EXISTS webhooks;`
+
+const createTagsTable = `
+CREATE TABLE IF NOT EXISTS tags (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	color TEXT NOT NULL DEFAULT '', -- Hex color like #ff8800, empty for the UI default
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (tenant_id, name)
+);
+`
+
+const dropTagsTable = `DROP TABLE IF EXISTS tags;`
+
+const createConversationTagsTable = `
+CREATE TABLE IF NOT EXISTS conversation_tags (
+	conversation_id TEXT NOT NULL,
+	tag_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (conversation_id, tag_id),
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+	FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_tags_tag ON conversation_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_conversation_tags_tenant ON conversation_tags(tenant_id);
+`
+
+const dropConversationTagsTable = `DROP TABLE IF EXISTS conversation_tags;`
+
+const createProductVariantsTable = `
+CREATE TABLE IF NOT EXISTS product_variants (
+	id TEXT PRIMARY KEY,
+	product_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	price REAL NOT NULL,
+	price_currency TEXT NOT NULL DEFAULT 'INR',
+	description TEXT NOT NULL DEFAULT '',
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variants_product ON product_variants(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_variants_tenant ON product_variants(tenant_id);
+`
+
+const dropProductVariantsTable = `DROP TABLE IF EXISTS product_variants;`
+
+const createObjectionResolutionsTable = `
+CREATE TABLE IF NOT EXISTS objection_resolutions (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	objection_type TEXT NOT NULL,
+	resolution_status TEXT NOT NULL DEFAULT 'unresolved' CHECK(resolution_status IN ('resolved', 'unresolved')),
+	raised_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	resolved_at TIMESTAMP, -- NULL while unresolved
+	resolving_message_id TEXT, -- Latest agent message when the objection disappeared from the analysis
+	UNIQUE (conversation_id, objection_type),
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_objection_resolutions_tenant ON objection_resolutions(tenant_id, objection_type);
+`
+
+const dropObjectionResolutionsTable = `DROP TABLE IF EXISTS objection_resolutions;`
+
+const createRoutingRulesTable = `
+CREATE TABLE IF NOT EXISTS routing_rules (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	priority INTEGER NOT NULL DEFAULT 0, -- Higher priorities are evaluated first
+	conditions TEXT NOT NULL DEFAULT '[]', -- JSON array of {field, operator, value}, all of which must match
+	action TEXT NOT NULL, -- JSON {type, agent_id}; only assign_agent is supported
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_routing_rules_tenant ON routing_rules(tenant_id, priority);
+`
+
+const dropRoutingRulesTable = `DROP TABLE IF EXISTS routing_rules;`
+
+const createProductRecommendationsTable = `
+CREATE TABLE IF NOT EXISTS product_recommendations (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	suggestion_id TEXT NOT NULL, -- The reply suggestion that recommended the product
+	product_id TEXT NOT NULL,
+	suggested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	accepted BOOLEAN NOT NULL DEFAULT FALSE, -- Set when an agent accepts or edits the suggestion
+	accepted_at TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_recommendations_suggestion ON product_recommendations(conversation_id, suggestion_id);
+CREATE INDEX IF NOT EXISTS idx_product_recommendations_tenant ON product_recommendations(tenant_id, product_id);
+`
+
+const dropProductRecommendationsTable = `DROP TABLE IF EXISTS product_recommendations;`
+
+const createBusinessHoursConfigTable = `
+CREATE TABLE IF NOT EXISTS business_hours_config (
+	tenant_id TEXT PRIMARY KEY,
+	timezone TEXT NOT NULL, -- IANA name, e.g. Asia/Kolkata
+	schedule TEXT NOT NULL DEFAULT '[]', -- JSON array of {day_of_week, open_time, close_time}
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropBusinessHoursConfigTable = `DROP TABLE IF EXISTS business_hours_config;`
+
+const createEmbeddingVersionsTable = `
+CREATE TABLE IF NOT EXISTS embedding_versions (
+	id TEXT PRIMARY KEY,
+	collection_name TEXT NOT NULL,
+	model_name TEXT NOT NULL,
+	dimension INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	is_active BOOLEAN NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_embedding_versions_collection ON embedding_versions(collection_name, is_active);
+`
+
+const dropEmbeddingVersionsTable = `DROP TABLE IF EXISTS embedding_versions;`
+
+const createConversationAnalysisHistoryTable = `
+CREATE TABLE IF NOT EXISTS conversation_analysis_history (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	intent TEXT,
+	intent_score REAL NOT NULL DEFAULT 0,
+	sentiment TEXT,
+	sentiment_score REAL NOT NULL DEFAULT 0,
+	emotions TEXT NOT NULL DEFAULT '[]', -- JSON array
+	objections TEXT NOT NULL DEFAULT '[]', -- JSON array
+	analyzed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	message_count_at_analysis INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_conversation_analysis_history_conversation ON conversation_analysis_history(conversation_id, analyzed_at);
+`
+
+const dropConversationAnalysisHistoryTable = `DROP TABLE IF EXISTS conversation_analysis_history;`
+
+const createProductCategoriesTable = `
+CREATE TABLE IF NOT EXISTS product_categories (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	parent_id TEXT, -- NULL for top-level categories
+	description TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (parent_id) REFERENCES product_categories(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_product_categories_tenant ON product_categories(tenant_id, parent_id);
+`
+
+const dropProductCategoriesTable = `DROP TABLE IF EXISTS product_categories;`
+
+const createEscalationEventsTable = `
+CREATE TABLE IF NOT EXISTS escalation_events (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	triggered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	sentiment_score REAL NOT NULL DEFAULT 0,
+	reason TEXT NOT NULL, -- sentiment or rule
+	details TEXT NOT NULL DEFAULT '',
+	assigned_to TEXT,
+	resolved BOOLEAN NOT NULL DEFAULT false,
+	resolved_at TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_escalation_events_tenant ON escalation_events(tenant_id, resolved, sentiment_score);
+-- At most one open escalation per conversation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_escalation_events_open ON escalation_events(conversation_id) WHERE resolved = false;
+`
+
+const dropEscalationEventsTable = `DROP TABLE IF EXISTS escalation_events;`
+
+const createProductSLAConfigTable = `
+CREATE TABLE IF NOT EXISTS product_sla_config (
+	product_id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	first_response_minutes INTEGER NOT NULL CHECK(first_response_minutes > 0),
+	resolution_hours INTEGER NOT NULL DEFAULT 0 CHECK(resolution_hours >= 0), -- 0 means no resolution deadline
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_product_sla_config_tenant ON product_sla_config(tenant_id);
+`
+
+const dropProductSLAConfigTable = `DROP TABLE IF EXISTS product_sla_config;`
+
+const createUserInvitationsTable = `
+CREATE TABLE IF NOT EXISTS user_invitations (
+	id TEXT PRIMARY KEY, -- The invitation token's jti
+	tenant_id TEXT NOT NULL,
+	email TEXT NOT NULL,
+	role TEXT NOT NULL CHECK(role IN ('agent', 'admin')),
+	invited_by TEXT NOT NULL,
+	token_hash TEXT NOT NULL, -- SHA-256 of the token; the token itself is never stored
+	status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'accepted', 'revoked')),
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	accepted_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_user_invitations_email ON user_invitations(tenant_id, email, status);
+`
+
+const dropUserInvitationsTable = `DROP TABLE IF EXISTS user_invitations;`
+
+const createKnowledgeGapsTable = `
+CREATE TABLE IF NOT EXISTS knowledge_gaps (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	query_text TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_knowledge_gaps_tenant_created ON knowledge_gaps(tenant_id, created_at);
+`
+
+const dropKnowledgeGapsTable = `DROP TABLE IF EXISTS knowledge_gaps;`
+
+const createTenantAnalyticsConfigTable = `
+CREATE TABLE IF NOT EXISTS tenant_analytics_config (
+	tenant_id TEXT PRIMARY KEY,
+	config_json TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const dropTenantAnalyticsConfigTable = `DROP TABLE IF EXISTS tenant_analytics_config;`
+
+const createModerationListsTable = `
+CREATE TABLE IF NOT EXISTS moderation_lists (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	list_type TEXT NOT NULL,
+	pattern TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_lists_tenant ON moderation_lists(tenant_id);
+`
+
+const dropModerationListsTable = `DROP TABLE IF EXISTS moderation_lists;`
+
+const createModerationEventsTable = `
+CREATE TABLE IF NOT EXISTS moderation_events (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	message_id TEXT NOT NULL,
+	sender TEXT NOT NULL,
+	action TEXT NOT NULL,
+	categories TEXT NOT NULL DEFAULT '[]',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_events_tenant_created ON moderation_events(tenant_id, created_at);
+`
+
+const dropModerationEventsTable = `DROP TABLE IF EXISTS moderation_events;`
+
+const createConversationSummariesTable = `
+CREATE TABLE IF NOT EXISTS conversation_summaries (
+	conversation_id TEXT PRIMARY KEY,
+	summary_text TEXT NOT NULL,
+	message_count_when_generated INTEGER NOT NULL,
+	generated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+`
+
+const dropConversationSummariesTable = `DROP TABLE IF EXISTS conversation_summaries;`
+
+const createConversationNotesTable = `
+CREATE TABLE IF NOT EXISTS conversation_notes (
+	id TEXT PRIMARY KEY,
+	conversation_id TEXT NOT NULL,
+	tenant_id TEXT NOT NULL,
+	agent_id TEXT NOT NULL,
+	content TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_conversation_notes_tenant_conversation ON conversation_notes(tenant_id, conversation_id);
+`
+
+const dropConversationNotesTable = `DROP TABLE IF EXISTS conversation_notes;`
@@ -0,0 +1,285 @@
+//go:build integration
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// newMemoryDB opens a private in-memory SQLite database through the regular client
+func newMemoryDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Setenv("DB_TYPE", "sqlite")
+	t.Setenv("SQLITE_PATH", fmt.Sprintf("file:%s?mode=memory&cache=shared&_foreign_keys=on", t.Name()))
+	client, err := postgres.NewClient()
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client.DB
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1", table).Scan(&count); err != nil {
+		t.Fatalf("failed to check table %s: %v", table, err)
+	}
+	return count > 0
+}
+
+func columnExists(t *testing.T, db *sql.DB, table, column string) bool {
+	t.Helper()
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM pragma_table_info('%s') WHERE name = $1", table)
+	if err := db.QueryRow(query, column).Scan(&count); err != nil {
+		t.Fatalf("failed to check column %s.%s: %v", table, column, err)
+	}
+	return count > 0
+}
+
+func appliedCount(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		t.Fatalf("appliedMigrations: %v", err)
+	}
+	count := 0
+	for _, ok := range applied {
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+func TestMigrationVersionsAreUniqueAndAscending(t *testing.T) {
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version <= migrations[i-1].version {
+			t.Fatalf("migration %d (%s) must have a higher version than %d", migrations[i].version, migrations[i].name, migrations[i-1].version)
+		}
+	}
+}
+
+func TestRunMigrationsIsIdempotent(t *testing.T) {
+	db := newMemoryDB(t)
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("first up: %v", err)
+	}
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("second up: %v", err)
+	}
+
+	if got := appliedCount(t, db); got != len(migrations) {
+		t.Errorf("applied = %d, want %d", got, len(migrations))
+	}
+	var runs int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE direction = 'up'").Scan(&runs); err != nil {
+		t.Fatalf("count runs: %v", err)
+	}
+	if runs != len(migrations) {
+		t.Errorf("recorded up runs = %d, want %d (second run should skip applied migrations)", runs, len(migrations))
+	}
+	if !columnExists(t, db, "conversations", "customer_id") || !columnExists(t, db, "messages", "deleted_by") {
+		t.Error("expected added columns to exist after up")
+	}
+}
+
+func TestDownMigrationsWithSteps(t *testing.T) {
+	db := newMemoryDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	// Roll back to just after conversations.deleted_by (version 50)
+	steps := 0
+	for _, m := range migrations {
+		if m.version > 50 {
+			steps++
+		}
+	}
+	if err := runDownMigrations(db, steps); err != nil {
+		t.Fatalf("down %d steps: %v", steps, err)
+	}
+	if got, want := appliedCount(t, db), len(migrations)-steps; got != want {
+		t.Errorf("applied after %d steps = %d, want %d", steps, got, want)
+	}
+	if columnExists(t, db, "messages", "deleted_by") || columnExists(t, db, "messages", "deleted_at") {
+		t.Error("expected the message columns added after version 50 to be dropped")
+	}
+	if !columnExists(t, db, "conversations", "deleted_by") {
+		t.Error("expected conversations.deleted_by to be kept")
+	}
+
+	// Up only re-applies what was rolled back
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up after partial rollback: %v", err)
+	}
+	if !columnExists(t, db, "messages", "deleted_by") {
+		t.Error("expected messages.deleted_by to be re-added")
+	}
+	if got := appliedCount(t, db); got != len(migrations) {
+		t.Errorf("applied = %d, want %d", got, len(migrations))
+	}
+}
+
+func TestDownMigrationsRollsBackEverything(t *testing.T) {
+	db := newMemoryDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	if err := runDownMigrations(db, 0); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if got := appliedCount(t, db); got != 0 {
+		t.Errorf("applied after full rollback = %d, want 0", got)
+	}
+	for _, table := range []string{"users", "conversations", "messages", "products", "refresh_tokens"} {
+		if tableExists(t, db, table) {
+			t.Errorf("expected table %s to be dropped", table)
+		}
+	}
+
+	// Rolling back again is a no-op
+	if err := runDownMigrations(db, 0); err != nil {
+		t.Fatalf("second down: %v", err)
+	}
+
+	// And the schema can be rebuilt from scratch
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up after rollback: %v", err)
+	}
+	if !tableExists(t, db, "conversations") || !columnExists(t, db, "conversations", "product_id") {
+		t.Error("expected schema to be recreated")
+	}
+}
+
+func TestDropColumnIfExistsIgnoresRemovedColumns(t *testing.T) {
+	db := newMemoryDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	if err := dropProductIdColumn(db); err != nil {
+		t.Fatalf("first drop: %v", err)
+	}
+	if err := dropProductIdColumn(db); err != nil {
+		t.Fatalf("second drop should ignore the missing column: %v", err)
+	}
+	if err := dropCustomerIdColumn(db); err != nil {
+		t.Fatalf("drop customer_id: %v", err)
+	}
+	if columnExists(t, db, "conversations", "product_id") || columnExists(t, db, "conversations", "customer_id") {
+		t.Error("expected product_id and customer_id to be dropped")
+	}
+}
+
+func TestBrandToneMigrationAllowsCustomTones(t *testing.T) {
+	db := newMemoryDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	insert := func(tenantID, tone string) error {
+		_, err := db.Exec("INSERT INTO brand_tone (tenant_id, tone) VALUES ($1, $2)", tenantID, tone)
+		return err
+	}
+	if err := insert("tenant-custom", "Empathetic for healthcare"); err != nil {
+		t.Fatalf("insert custom tone: %v", err)
+	}
+	if err := insert("tenant-long", strings.Repeat("a", 501)); err == nil {
+		t.Error("expected a tone over 500 characters to be rejected")
+	}
+
+	// Rolling back past version 54 restores the fixed tones; custom ones fall back to Professional
+	steps := 0
+	for _, m := range migrations {
+		if m.version >= 54 {
+			steps++
+		}
+	}
+	if err := runDownMigrations(db, steps); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	var tone string
+	if err := db.QueryRow("SELECT tone FROM brand_tone WHERE tenant_id = $1", "tenant-custom").Scan(&tone); err != nil {
+		t.Fatalf("select tone: %v", err)
+	}
+	if tone != "Professional" {
+		t.Errorf("tone after rollback = %q, want Professional", tone)
+	}
+	if err := insert("tenant-other", "Technical but warm"); err == nil {
+		t.Error("expected custom tones to be rejected after rollback")
+	}
+}
+
+func TestSuperAdminRoleMigration(t *testing.T) {
+	db := newMemoryDB(t)
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	insertUser := func(id, role string) error {
+		_, err := db.Exec("INSERT INTO users (id, tenant_id, email, password_hash, role) VALUES ($1, $2, $3, $4, $5)",
+			id, "tenant-1", id+"@example.com", "hash", role)
+		return err
+	}
+	if err := insertUser("root", "super_admin"); err != nil {
+		t.Fatalf("insert super admin: %v", err)
+	}
+	if err := insertUser("agent", "agent"); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	if err := insertUser("bogus", "owner"); err == nil {
+		t.Error("expected an unknown role to be rejected")
+	}
+	// Rows referencing users must survive the SQLite table rebuild on rollback
+	if _, err := db.Exec("INSERT INTO conversations (id, tenant_id, status) VALUES ($1, $2, $3)", "c1", "tenant-1", "active"); err != nil {
+		t.Fatalf("insert conversation: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO transfer_events (id, conversation_id, tenant_id, to_agent_id, transferred_by) VALUES ($1, $2, $3, $4, $5)",
+		"t1", "c1", "tenant-1", "agent", "root"); err != nil {
+		t.Fatalf("insert transfer event: %v", err)
+	}
+
+	// Rolling back version 60 demotes super admins to admin
+	if err := runDownMigrations(db, len(migrations)-indexOfVersion(t, 60)); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	var role string
+	if err := db.QueryRow("SELECT role FROM users WHERE id = $1", "root").Scan(&role); err != nil {
+		t.Fatalf("select role: %v", err)
+	}
+	if role != "admin" {
+		t.Errorf("role after rollback = %q, want admin", role)
+	}
+	if err := insertUser("root2", "super_admin"); err == nil {
+		t.Error("expected super_admin to be rejected after rollback")
+	}
+	var transfers int
+	if err := db.QueryRow("SELECT COUNT(*) FROM transfer_events").Scan(&transfers); err != nil || transfers != 1 {
+		t.Errorf("transfer events after rollback = %d, %v; want 1", transfers, err)
+	}
+	if !columnExists(t, db, "users", "deactivated_at") {
+		t.Error("users.deactivated_at lost in the rebuild")
+	}
+}
+
+// indexOfVersion returns the position of a migration version in migrations
+func indexOfVersion(t *testing.T, version int) int {
+	t.Helper()
+	for i, m := range migrations {
+		if m.version == version {
+			return i
+		}
+	}
+	t.Fatalf("migration %d not found", version)
+	return 0
+}
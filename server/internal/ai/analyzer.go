@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,11 +9,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"ai-conversation-platform/internal/models"
 	"ai-conversation-platform/internal/rules"
 	"ai-conversation-platform/internal/storage/chroma"
 	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
 )
 
 // RuleLoader interface for loading rules (to keep analyzer decoupled from storage)
@@ -20,20 +25,46 @@ type RuleLoader interface {
 	LoadRules(tenantID string) ([]*models.Rule, error)
 }
 
+// AnalysisListener is notified after a conversation's analysis has been stored
+type AnalysisListener interface {
+	OnAnalysisComplete(tenantID, conversationID string)
+}
+
+// CustomerMemoryUpdater merges analyses into customer memory
+type CustomerMemoryUpdater interface {
+	UpsertFromAnalysis(tenantID, customerID string, metadata *models.ConversationMetadata, productInterests ...string) error
+}
+
+// ProductLookup loads a tenant's product
+type ProductLookup interface {
+	GetProduct(tenantID, productID string) (*models.Product, error)
+}
+
+// FallbackSentimentModel tags sentiment scores from keyword analysis when the API is unavailable
+const FallbackSentimentModel = "keyword-fallback"
+
 // Analyzer handles AI analysis of conversations
 type Analyzer struct {
-	geminiClient      *Client
-	retriever        *chroma.Retriever
-	embeddingService *EmbeddingService
-	metadataStorage  *postgres.ConversationStorage
-	ruleEngine       *rules.RuleEngine
-	ruleLoader       RuleLoader
+	generator           TextGenerator
+	retriever           *chroma.Retriever
+	embeddingService    *EmbeddingService
+	metadataStorage     *postgres.ConversationStorage
+	ruleEngine          *rules.RuleEngine
+	ruleLoader          RuleLoader
+	analysisListener    AnalysisListener
+	clientFactory       *GeminiClientFactory
+	sentimentNormalizer *SentimentNormalizer
+	intentConfigSource  IntentConfigSource
+	usageRecorder       UsageRecorder
+	customerMemory      CustomerMemoryUpdater
+	products            ProductLookup
+	objectionRecorder   ObjectionResolutionRecorder
 }
 
 // NewAnalyzer creates a new analyzer
-func NewAnalyzer(geminiClient *Client, retriever *chroma.Retriever, embeddingService *EmbeddingService, metadataStorage *postgres.ConversationStorage) *Analyzer {
+func NewAnalyzer(generator TextGenerator, retriever *chroma.Retriever, embeddingService *EmbeddingService, metadataStorage *postgres.ConversationStorage) *Analyzer {
 	return &Analyzer{
-		geminiClient:      geminiClient,
+		generator:        generator,
 		retriever:        retriever,
 		embeddingService: embeddingService,
 		metadataStorage:  metadataStorage,
@@ -46,36 +77,110 @@ func (a *Analyzer) SetRuleLoader(loader RuleLoader) {
 	a.ruleLoader = loader
 }
 
-// AnalyzeConversationAsync triggers async analysis
-func (a *Analyzer) AnalyzeConversationAsync(tenantID, conversationID string, messages []*models.Message) {
-	go func() {
-		if err := a.analyzeConversation(tenantID, conversationID, messages); err != nil {
-			log.Printf("[AI] analysis failed conversation=%s error=%v", conversationID, err)
-		}
-	}()
+// SetAnalysisListener sets a listener notified after each completed analysis (optional)
+func (a *Analyzer) SetAnalysisListener(listener AnalysisListener) {
+	a.analysisListener = listener
+}
+
+// SetClientFactory enables per-tenant Gemini clients (optional)
+func (a *Analyzer) SetClientFactory(factory *GeminiClientFactory) {
+	a.clientFactory = factory
+}
+
+// SetSentimentNormalizer enables cross-model sentiment score normalization (optional)
+func (a *Analyzer) SetSentimentNormalizer(normalizer *SentimentNormalizer) {
+	a.sentimentNormalizer = normalizer
+}
+
+// SetIntentConfigSource enables per-tenant custom intents (optional)
+func (a *Analyzer) SetIntentConfigSource(source IntentConfigSource) {
+	a.intentConfigSource = source
+}
+
+// SetUsageRecorder records analysis calls per tenant (optional)
+func (a *Analyzer) SetUsageRecorder(recorder UsageRecorder) {
+	a.usageRecorder = recorder
+}
+
+// SetCustomerMemory merges each analysis into the customer's memory (optional). products resolves
+// the conversation's product name as a product interest and may be nil.
+func (a *Analyzer) SetCustomerMemory(memory CustomerMemoryUpdater, products ProductLookup) {
+	a.customerMemory = memory
+	a.products = products
+}
+
+// clientFor returns the tenant's Gemini client, falling back to the default generator
+func (a *Analyzer) clientFor(tenantID string) TextGenerator {
+	if a.clientFactory == nil || tenantID == "" {
+		return a.generator
+	}
+	client, err := a.clientFactory.ClientForTenant(tenantID)
+	if err != nil || client == nil {
+		return a.generator
+	}
+	return client
+}
+
+// IsQuotaError reports whether err was caused by Gemini quota or rate limits
+func IsQuotaError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), "quota") || strings.Contains(err.Error(), "Quota") ||
+		strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "rate limit")
 }
 
-// analyzeConversation performs the actual analysis
-func (a *Analyzer) analyzeConversation(tenantID, conversationID string, messages []*models.Message) error {
+// AnalyzeConversation analyzes a conversation and stores the result. It runs on a worker pool after
+// the request that triggered it has returned, so it is traced as its own root span. When the
+// analysis is blocked by API quota the error is returned so the job can be retried, except on the
+// final attempt, which stores a keyword fallback analysis instead. While the Gemini circuit
+// breaker is open the fallback is stored straight away.
+func (a *Analyzer) AnalyzeConversation(ctx context.Context, tenantID, conversationID string, messages []*models.Message) (err error) {
+	ctx, span := tracer.Start(ctx, "ai.analyze_conversation", trace.WithAttributes(
+		attribute.String("tenant.id", tenantID),
+		attribute.String("conversation.id", conversationID),
+	))
+	defer func() { EndSpan(span, err) }()
+
+	messages = activeMessages(messages)
+
 	context, err := a.retrieveContext(messages)
 	if err != nil {
 		// Check if error is due to quota/API limits - continue without context
-		if strings.Contains(err.Error(), "quota") || strings.Contains(err.Error(), "Quota") || 
-		   strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "rate limit") {
+		switch {
+		case IsCircuitOpen(err):
+			// Gemini is down; the circuit breaker logged it when it opened
+		case IsQuotaError(err):
 			log.Printf("[AI] context retrieval blocked by API quota, continuing without context conversation=%s", conversationID)
-		} else {
+		default:
 			log.Printf("[AI] context retrieval failed conversation=%s error=%v", conversationID, err)
 		}
 		context = ""
 	}
 
-	analysis, err := a.performAnalysis(messages, context)
+	// The conversation is only needed for its language override; analysis proceeds without it
+	conv, err := a.metadataStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[AI] failed to load conversation, using detected language conversation=%s error=%v", conversationID, err)
+	}
+
+	intentConfig := a.intentConfigFor(tenantID)
+	RecordUsage(a.usageRecorder, tenantID, UsageConversationAnalysis)
+	analysis, err := a.performAnalysis(ctx, a.clientFor(tenantID), conv, messages, context, intentConfig)
 	if err != nil {
-		// Check if error is due to quota/API limits - use fallback analysis
-		if strings.Contains(err.Error(), "quota") || strings.Contains(err.Error(), "Quota") || 
-		   strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "rate limit") {
+		// Check if error is due to quota/API limits - retry later, or use fallback analysis.
+		// While the circuit breaker is open Gemini is down, so the fallback is used straight away.
+		if IsCircuitOpen(err) {
+			log.Printf("[AI] gemini circuit open, using fallback analysis conversation=%s", conversationID)
+			analysis = a.performFallbackAnalysis(messages)
+			analysis.Intent = matchIntent(analysis.Intent, intentConfig.Intents)
+		} else if IsQuotaError(err) && !worker.IsFinalAttempt(ctx) {
+			return fmt.Errorf("analysis blocked by API quota: %w", err)
+		} else if IsQuotaError(err) {
 			log.Printf("[AI] analysis blocked by API quota, using fallback analysis conversation=%s", conversationID)
 			analysis = a.performFallbackAnalysis(messages)
+			// Keyword fallback only knows the default intents
+			analysis.Intent = matchIntent(analysis.Intent, intentConfig.Intents)
 		} else {
 			log.Printf("[AI] analysis failed conversation=%s error=%v", conversationID, err)
 			return fmt.Errorf("analysis failed: %w", err)
@@ -100,12 +205,23 @@ func (a *Analyzer) analyzeConversation(tenantID, conversationID string, messages
 		}
 	}
 
-	if err := a.storeMetadata(conversationID, analysis); err != nil {
+	complexity := NewComplexityScorer().Score(messages, analysis)
+	analysis.ComplexityScore = float64(complexity.Score)
+
+	previousObjections := a.previousObjections(tenantID, conversationID)
+	if err := a.storeMetadata(tenantID, conversationID, analysis); err != nil {
 		return fmt.Errorf("failed to store metadata: %w", err)
 	}
+	a.recordAnalysisHistory(conversationID, analysis, len(messages))
+	a.updateCustomerMemory(tenantID, conv, analysis)
+	a.trackObjectionResolutions(tenantID, conversationID, previousObjections, analysis.Objections, messages)
+
+	log.Printf("[AI] analysis complete conversation=%s intent=%s sentiment=%s objections=%v complexity=%d",
+		conversationID, analysis.Intent, analysis.Sentiment, analysis.Objections, complexity.Score)
 
-	log.Printf("[AI] analysis complete conversation=%s intent=%s sentiment=%s objections=%v",
-		conversationID, analysis.Intent, analysis.Sentiment, analysis.Objections)
+	if a.analysisListener != nil && tenantID != "" {
+		a.analysisListener.OnAnalysisComplete(tenantID, conversationID)
+	}
 	return nil
 }
 
@@ -142,11 +258,14 @@ func (a *Analyzer) retrieveContext(messages []*models.Message) (string, error) {
 }
 
 // performAnalysis calls Gemini API for analysis
-func (a *Analyzer) performAnalysis(messages []*models.Message, context string) (*models.ConversationMetadata, error) {
+func (a *Analyzer) performAnalysis(ctx context.Context, client TextGenerator, conv *models.Conversation, messages []*models.Message, context string, intentConfig *postgres.IntentConfig) (*models.ConversationMetadata, error) {
+	ctx, span := tracer.Start(ctx, "ai.perform_analysis", trace.WithAttributes(attribute.Int("messages.count", len(messages))))
+	defer span.End()
+
 	conversationText := a.buildConversationText(messages)
 	
-	// Detect language from messages
-	detectedLang := a.detectLanguage(messages)
+	// Detect language from messages, unless an agent has set the conversation language
+	detectedLang := a.detectLanguage(conv, messages)
 	
 	// Translate to English if needed for analysis
 	translatedText := conversationText
@@ -157,35 +276,59 @@ func (a *Analyzer) performAnalysis(messages []*models.Message, context string) (
 		}
 	}
 	
-	prompt := a.buildAnalysisPrompt(translatedText, context)
+	prompt := a.buildAnalysisPrompt(translatedText, context, intentConfig)
 
 	req := GenerateTextRequest{
 		Prompt:  prompt,
 		Context: context,
 	}
 
-	resp, err := a.geminiClient.GenerateText(req)
+	resp, err := client.GenerateTextContext(ctx, req)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("gemini API call failed: %w", err)
 	}
 
-	return a.parseAnalysisResponse(resp.Text)
+	return a.parseAnalysisResponse(resp.Text, resp.Model, intentConfig.Intents)
 }
 
-// buildConversationText builds text from messages
+// activeMessages drops soft-deleted messages
+func activeMessages(messages []*models.Message) []*models.Message {
+	active := make([]*models.Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg.DeletedAt == nil {
+			active = append(active, msg)
+		}
+	}
+	return active
+}
+
+// buildConversationText builds text from messages, skipping deleted messages
+// so removed content never reaches the model
 func (a *Analyzer) buildConversationText(messages []*models.Message) string {
 	parts := make([]string, 0, len(messages))
 	for _, msg := range messages {
+		if msg.DeletedAt != nil {
+			continue
+		}
 		parts = append(parts, fmt.Sprintf("%s: %s", msg.Sender, msg.Content))
 	}
 	return strings.Join(parts, "\n")
 }
 
-// buildAnalysisPrompt builds the analysis prompt
-func (a *Analyzer) buildAnalysisPrompt(conversationText string, context string) string {
+// buildAnalysisPrompt builds the analysis prompt with the tenant's intent list
+func (a *Analyzer) buildAnalysisPrompt(conversationText string, context string, intentConfig *postgres.IntentConfig) string {
+	intentLine := "- intent: one of [" + strings.Join(intentConfig.Intents, ",") + "]\n"
+	for _, intent := range intentConfig.Intents {
+		if description := intentConfig.Descriptions[intent]; description != "" {
+			intentLine += "  - " + intent + ": " + description + "\n"
+		}
+	}
+
 	prompt := `Analyze this customer conversation and return JSON with:
-- intent: "buying", "support", or "complaint"
-- sentiment: "positive", "neutral", or "negative"
+` + intentLine + `- sentiment: "positive", "neutral", or "negative"
+- sentiment_score: your confidence in the sentiment, a number from 0 to 1
 - emotions: array of ["frustration", "urgency", "confusion", "trust", "satisfaction"]
 - objections: array of ["price", "trust", "delivery", "competitor"] if any
 
@@ -199,8 +342,24 @@ Conversation:
 	return prompt
 }
 
-// parseAnalysisResponse parses Gemini response
-func (a *Analyzer) parseAnalysisResponse(responseText string) (*models.ConversationMetadata, error) {
+// parseAnalysisResponse parses Gemini response. Intents outside the configured list are
+// dropped. The sentiment score is tagged with modelName and normalized so scores from
+// different models are comparable.
+func (a *Analyzer) parseAnalysisResponse(responseText, modelName string, intents []string) (*models.ConversationMetadata, error) {
+	metadata, err := a.parseAnalysisJSON(responseText, intents)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.SentimentModel = modelName
+	if a.sentimentNormalizer != nil {
+		metadata.SentimentScore = a.sentimentNormalizer.Normalize(metadata.SentimentScore, modelName)
+	}
+	return metadata, nil
+}
+
+// parseAnalysisJSON extracts analysis fields from the model's JSON (or free text) reply
+func (a *Analyzer) parseAnalysisJSON(responseText string, intents []string) (*models.ConversationMetadata, error) {
 	metadata := &models.ConversationMetadata{
 		Emotions:   []string{},
 		Objections: []string{},
@@ -210,23 +369,30 @@ func (a *Analyzer) parseAnalysisResponse(responseText string) (*models.Conversat
 	jsonStart := strings.Index(responseText, "{")
 	jsonEnd := strings.LastIndex(responseText, "}")
 	if jsonStart == -1 || jsonEnd == -1 {
-		return a.parseTextResponse(responseText, metadata)
+		return a.parseTextResponse(responseText, metadata, intents)
 	}
 
 	jsonStr := responseText[jsonStart : jsonEnd+1]
 	var result map[string]interface{}
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return a.parseTextResponse(responseText, metadata)
+		return a.parseTextResponse(responseText, metadata, intents)
 	}
 
 	if intent, ok := result["intent"].(string); ok {
-		metadata.Intent = strings.ToLower(intent)
-		metadata.IntentScore = 0.8
+		metadata.Intent = matchIntent(intent, intents)
+		if metadata.Intent != "" {
+			metadata.IntentScore = 0.8
+		} else {
+			log.Printf("[AI] WARN ignoring intent outside configured list intent=%s", intent)
+		}
 	}
 
 	if sentiment, ok := result["sentiment"].(string); ok {
 		metadata.Sentiment = strings.ToLower(sentiment)
 		metadata.SentimentScore = 0.8
+		if score, ok := result["sentiment_score"].(float64); ok && score >= 0 && score <= 1 {
+			metadata.SentimentScore = score
+		}
 	}
 
 	if emotions, ok := result["emotions"].([]interface{}); ok {
@@ -241,7 +407,7 @@ func (a *Analyzer) parseAnalysisResponse(responseText string) (*models.Conversat
 }
 
 // parseTextResponse parses text response as fallback
-func (a *Analyzer) parseTextResponse(text string, metadata *models.ConversationMetadata) (*models.ConversationMetadata, error) {
+func (a *Analyzer) parseTextResponse(text string, metadata *models.ConversationMetadata, intents []string) (*models.ConversationMetadata, error) {
 	text = strings.ToLower(text)
 	if strings.Contains(text, "buying") || strings.Contains(text, "purchase") {
 		metadata.Intent = "buying"
@@ -250,6 +416,16 @@ func (a *Analyzer) parseTextResponse(text string, metadata *models.ConversationM
 	} else {
 		metadata.Intent = "support"
 	}
+	metadata.Intent = matchIntent(metadata.Intent, intents)
+	if metadata.Intent == "" {
+		// Custom intents: take the first one mentioned in the reply
+		for _, intent := range intents {
+			if strings.Contains(text, intent) || strings.Contains(text, strings.ReplaceAll(intent, "_", " ")) {
+				metadata.Intent = intent
+				break
+			}
+		}
+	}
 	metadata.IntentScore = 0.5
 
 	if strings.Contains(text, "positive") || strings.Contains(text, "happy") {
@@ -294,6 +470,7 @@ func (a *Analyzer) performFallbackAnalysis(messages []*models.Message) *models.C
 		metadata.Sentiment = "neutral"
 	}
 	metadata.SentimentScore = 0.6 // Lower confidence for fallback
+	metadata.SentimentModel = FallbackSentimentModel
 
 	// Detect objections using keyword matching
 	objections := a.detectObjections(messages, metadata)
@@ -342,19 +519,89 @@ func (a *Analyzer) detectObjections(messages []*models.Message, analysis *models
 	return result
 }
 
-// storeMetadata stores analysis results
-func (a *Analyzer) storeMetadata(conversationID string, analysis *models.ConversationMetadata) error {
+// storeMetadata stores analysis results. Existing metadata is patched so that fields the
+// re-analysis did not detect keep the values from a previous run.
+func (a *Analyzer) storeMetadata(tenantID, conversationID string, analysis *models.ConversationMetadata) error {
 	analysis.ConversationID = conversationID
+	if _, err := a.metadataStorage.GetConversationMetadata(tenantID, conversationID); err == nil {
+		return a.metadataStorage.PatchConversationMetadata(tenantID, conversationID, metadataPatchFromAnalysis(analysis))
+	}
+
 	if analysis.ID == "" {
 		analysis.ID = uuid.New().String()
 	}
 	analysis.UpdatedAt = time.Now()
 
-	return a.metadataStorage.CreateConversationMetadata(analysis)
+	return a.metadataStorage.CreateConversationMetadata(tenantID, analysis)
 }
 
-// detectLanguage detects the primary language from messages
-func (a *Analyzer) detectLanguage(messages []*models.Message) string {
+// recordAnalysisHistory appends the analysis to the conversation's history. Failures are logged,
+// not returned, since the analysis itself is already stored.
+func (a *Analyzer) recordAnalysisHistory(conversationID string, analysis *models.ConversationMetadata, messageCount int) {
+	entry := &models.AnalysisHistoryEntry{
+		ConversationID:         conversationID,
+		Intent:                 analysis.Intent,
+		IntentScore:            analysis.IntentScore,
+		Sentiment:              analysis.Sentiment,
+		SentimentScore:         analysis.SentimentScore,
+		Emotions:               analysis.Emotions,
+		Objections:             analysis.Objections,
+		AnalyzedAt:             time.Now(),
+		MessageCountAtAnalysis: messageCount,
+	}
+	if err := a.metadataStorage.AppendAnalysisHistory(entry); err != nil {
+		log.Printf("[AI] failed to record analysis history conversation=%s error=%v", conversationID, err)
+	}
+}
+
+// updateCustomerMemory merges an analysis into the memory of the conversation's customer. conv may
+// be nil; conversations without a customer are skipped. Failures are logged, not returned, since
+// the analysis itself is already stored.
+func (a *Analyzer) updateCustomerMemory(tenantID string, conv *models.Conversation, analysis *models.ConversationMetadata) {
+	if a.customerMemory == nil || conv == nil || conv.CustomerID == nil || *conv.CustomerID == "" {
+		return
+	}
+
+	var productInterests []string
+	if a.products != nil && conv.ProductID != nil && *conv.ProductID != "" {
+		if product, err := a.products.GetProduct(tenantID, *conv.ProductID); err == nil && product != nil {
+			productInterests = append(productInterests, product.Name)
+		}
+	}
+
+	if err := a.customerMemory.UpsertFromAnalysis(tenantID, *conv.CustomerID, analysis, productInterests...); err != nil {
+		log.Printf("[AI] failed to update customer memory conversation=%s customer=%s error=%v", conv.ID, *conv.CustomerID, err)
+	}
+}
+
+// metadataPatchFromAnalysis includes only the fields an analysis actually produced
+func metadataPatchFromAnalysis(analysis *models.ConversationMetadata) postgres.MetadataPatch {
+	var patch postgres.MetadataPatch
+	if analysis.Intent != "" {
+		patch.Intent = &analysis.Intent
+		patch.IntentScore = &analysis.IntentScore
+	}
+	if analysis.Sentiment != "" {
+		patch.Sentiment = &analysis.Sentiment
+		patch.SentimentScore = &analysis.SentimentScore
+	}
+	if analysis.SentimentModel != "" {
+		patch.SentimentModel = &analysis.SentimentModel
+	}
+	patch.Emotions = analysis.Emotions
+	patch.Objections = analysis.Objections
+	if analysis.ComplexityScore > 0 {
+		patch.ComplexityScore = &analysis.ComplexityScore
+	}
+	return patch
+}
+
+// detectLanguage returns the conversation's override language if set, otherwise the
+// primary language detected from messages. conv may be nil.
+func (a *Analyzer) detectLanguage(conv *models.Conversation, messages []*models.Message) string {
+	if conv != nil && conv.OverrideLanguage != nil && *conv.OverrideLanguage != "" {
+		return *conv.OverrideLanguage
+	}
 	if len(messages) == 0 {
 		return ""
 	}
@@ -382,7 +629,7 @@ func (a *Analyzer) translateText(text, fromLang, toLang string) (string, error)
 		Prompt: prompt,
 	}
 	
-	resp, err := a.geminiClient.GenerateText(req)
+	resp, err := a.generator.GenerateText(req)
 	if err != nil {
 		return text, fmt.Errorf("translation failed: %w", err)
 	}
@@ -394,7 +641,7 @@ func (a *Analyzer) translateText(text, fromLang, toLang string) (string, error)
 func (a *Analyzer) GenerateReplyWithTranslation(messages []*models.Message, agentLang, customerLang string, prompt string) (string, error) {
 	// Detect customer language if not provided
 	if customerLang == "" {
-		customerLang = a.detectLanguage(messages)
+		customerLang = a.detectLanguage(nil, messages)
 	}
 	
 	// If customer language is different from agent language, translate customer messages
@@ -412,7 +659,7 @@ func (a *Analyzer) GenerateReplyWithTranslation(messages []*models.Message, agen
 		Prompt: fullPrompt,
 	}
 	
-	resp, err := a.geminiClient.GenerateText(req)
+	resp, err := a.generator.GenerateText(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate reply: %w", err)
 	}
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestMetadataPatchFromAnalysisSkipsUndetectedFields(t *testing.T) {
+	analysis := &models.ConversationMetadata{
+		Sentiment:      "negative",
+		SentimentScore: 0.2,
+		Objections:     []string{},
+	}
+
+	patch := metadataPatchFromAnalysis(analysis)
+	if patch.Intent != nil || patch.IntentScore != nil {
+		t.Errorf("intent should be left unchanged when not detected: %+v", patch)
+	}
+	if patch.Sentiment == nil || *patch.Sentiment != "negative" || patch.SentimentScore == nil || *patch.SentimentScore != 0.2 {
+		t.Errorf("sentiment not patched: %+v", patch)
+	}
+	if patch.Emotions != nil || patch.ComplexityScore != nil || patch.SentimentModel != nil {
+		t.Errorf("unset fields should be nil: %+v", patch)
+	}
+	if patch.Objections == nil {
+		t.Error("detected (empty) objections should replace the stored ones")
+	}
+}
+
+type fakeMemoryUpdater struct {
+	customerID string
+	interests  []string
+	calls      int
+}
+
+func (f *fakeMemoryUpdater) UpsertFromAnalysis(tenantID, customerID string, metadata *models.ConversationMetadata, productInterests ...string) error {
+	f.calls++
+	f.customerID = customerID
+	f.interests = productInterests
+	return nil
+}
+
+type fakeProductLookup map[string]string
+
+func (f fakeProductLookup) GetProduct(tenantID, productID string) (*models.Product, error) {
+	name, ok := f[productID]
+	if !ok {
+		return nil, errors.New("product not found")
+	}
+	return &models.Product{ID: productID, Name: name}, nil
+}
+
+func TestUpdateCustomerMemory(t *testing.T) {
+	memory := &fakeMemoryUpdater{}
+	a := &Analyzer{}
+	a.SetCustomerMemory(memory, fakeProductLookup{"p1": "Pro Plan"})
+	analysis := &models.ConversationMetadata{Objections: []string{"price"}}
+
+	customerID, productID, missingProduct := "customer-1", "p1", "p2"
+	a.updateCustomerMemory("tenant-1", &models.Conversation{ID: "c1", CustomerID: &customerID, ProductID: &productID}, analysis)
+	if memory.calls != 1 || memory.customerID != customerID || !reflect.DeepEqual(memory.interests, []string{"Pro Plan"}) {
+		t.Errorf("update = %d calls for %q with interests %v, want one for customer-1 with [Pro Plan]", memory.calls, memory.customerID, memory.interests)
+	}
+
+	// Unknown products still record the objections
+	a.updateCustomerMemory("tenant-1", &models.Conversation{ID: "c2", CustomerID: &customerID, ProductID: &missingProduct}, analysis)
+	if memory.calls != 2 || len(memory.interests) != 0 {
+		t.Errorf("update with unknown product = %d calls with interests %v, want no interests", memory.calls, memory.interests)
+	}
+
+	// Conversations without a customer have no memory to update
+	a.updateCustomerMemory("tenant-1", &models.Conversation{ID: "c3"}, analysis)
+	a.updateCustomerMemory("tenant-1", nil, analysis)
+	if memory.calls != 2 {
+		t.Errorf("calls = %d, want anonymous conversations skipped", memory.calls)
+	}
+}
@@ -0,0 +1,25 @@
+package ai
+
+import "context"
+
+// CallQuota limits the model API calls made on behalf of a request, e.g. a tenant's AI quota.
+// AllowCall counts one call and returns an error when the quota is used up.
+type CallQuota interface {
+	AllowCall() error
+}
+
+type callQuotaKey struct{}
+
+// WithCallQuota returns a copy of ctx whose Gemini calls are counted against quota
+func WithCallQuota(ctx context.Context, quota CallQuota) context.Context {
+	return context.WithValue(ctx, callQuotaKey{}, quota)
+}
+
+// checkCallQuota counts a call against the quota in ctx. Calls without a quota, such as
+// background analysis, are always allowed.
+func checkCallQuota(ctx context.Context) error {
+	if quota, ok := ctx.Value(callQuotaKey{}).(CallQuota); ok {
+		return quota.AllowCall()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixedQuota allows a fixed number of calls
+type fixedQuota struct {
+	remaining int
+	calls     int
+}
+
+func (q *fixedQuota) AllowCall() error {
+	q.calls++
+	if q.remaining == 0 {
+		return errors.New("tenant AI quota exceeded")
+	}
+	q.remaining--
+	return nil
+}
+
+func TestGenerateTextRespectsCallQuota(t *testing.T) {
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = "http://127.0.0.1:0" // Any real request would fail
+	quota := &fixedQuota{}
+	ctx := WithCallQuota(context.Background(), quota)
+
+	_, err := client.GenerateTextContext(ctx, GenerateTextRequest{Prompt: "prompt"})
+	if err == nil || !IsQuotaError(err) {
+		t.Fatalf("err = %v, want the quota error before any request", err)
+	}
+	if _, err := client.GenerateEmbeddingContext(ctx, GenerateEmbeddingRequest{Text: "text"}); err == nil || !IsQuotaError(err) {
+		t.Errorf("embedding err = %v, want the quota error", err)
+	}
+	if quota.calls != 2 {
+		t.Errorf("calls = %d, want each call counted once", quota.calls)
+	}
+}
+
+func TestCachedResponsesDontCountAgainstCallQuota(t *testing.T) {
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = "http://127.0.0.1:0"
+	cache := NewPromptCache(time.Minute, 10)
+	client.SetPromptCache(cache)
+	req := GenerateTextRequest{Prompt: "prompt"}
+	cache.Set(client.Model(), req.Context+"\x00"+req.Prompt, &GenerateTextResponse{Text: "cached"})
+
+	quota := &fixedQuota{}
+	resp, err := client.GenerateTextContext(WithCallQuota(context.Background(), quota), req)
+	if err != nil || resp.Text != "cached" {
+		t.Fatalf("GenerateTextContext = %+v, %v; want cached response", resp, err)
+	}
+	if quota.calls != 0 {
+		t.Errorf("calls = %d, want cache hits not counted", quota.calls)
+	}
+}
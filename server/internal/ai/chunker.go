@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// Product chunk section types
+const (
+	SectionOverview    = "overview"
+	SectionFeatures    = "features"
+	SectionLimitations = "limitations"
+	SectionAudience    = "audience"
+	SectionPricing     = "pricing"
+	SectionArticle     = "article" // Knowledge article text, split into numbered chunks
+)
+
+// articleChunkSize is the target character length of a knowledge article chunk
+const articleChunkSize = 2000
+
+// ProductSections lists all section types in chunking order
+var ProductSections = []string{
+	SectionOverview,
+	SectionFeatures,
+	SectionLimitations,
+	SectionAudience,
+	SectionPricing,
+}
+
+// ProductChunk represents a single semantic section of a product
+type ProductChunk struct {
+	Text        string
+	SectionType string
+	Metadata    map[string]interface{}
+}
+
+// ProductChunker splits products into section-level chunks for embedding
+type ProductChunker struct{}
+
+// NewProductChunker creates a new product chunker
+func NewProductChunker() *ProductChunker {
+	return &ProductChunker{}
+}
+
+// ProductChunkID returns the Chroma document ID for a product section
+func ProductChunkID(productID, sectionType string) string {
+	return fmt.Sprintf("%s_%s", productID, sectionType)
+}
+
+// ProductChunkIDs returns every document ID a product may own in Chroma,
+// including the legacy single-document ID used before chunking
+func ProductChunkIDs(productID string) []string {
+	ids := []string{productID}
+	for _, section := range ProductSections {
+		ids = append(ids, ProductChunkID(productID, section))
+	}
+	return ids
+}
+
+// Chunk splits a product into semantic sections. Empty sections are skipped.
+func (c *ProductChunker) Chunk(product *models.Product) []ProductChunk {
+	if product == nil {
+		return []ProductChunk{}
+	}
+
+	// The full category path ("Automation > WhatsApp") is more descriptive than the free-text category
+	category := product.Category
+	if product.CategoryPath != "" {
+		category = product.CategoryPath
+	}
+
+	chunks := make([]ProductChunk, 0, len(ProductSections))
+	add := func(sectionType string, parts []string) {
+		if len(parts) == 0 {
+			return
+		}
+		// Prefix every chunk with the product name so sections stay self-describing
+		text := fmt.Sprintf("Product: %s\n%s", product.Name, strings.Join(parts, "\n"))
+		chunks = append(chunks, ProductChunk{
+			Text:        text,
+			SectionType: sectionType,
+			Metadata: map[string]interface{}{
+				"id":           ProductChunkID(product.ID, sectionType),
+				"tenant_id":    product.TenantID,
+				"product_id":   product.ID,
+				"name":         product.Name,
+				"category":     category,
+				"section_type": sectionType,
+			},
+		})
+	}
+
+	// 1. Name + description
+	var overview []string
+	if product.Description != "" {
+		overview = append(overview, fmt.Sprintf("Description: %s", product.Description))
+	}
+	if category != "" {
+		overview = append(overview, fmt.Sprintf("Category: %s", category))
+	}
+	add(SectionOverview, overview)
+
+	// 2. Features
+	if len(product.Features) > 0 {
+		add(SectionFeatures, []string{fmt.Sprintf("Features: %s", strings.Join(product.Features, ", "))})
+	}
+
+	// 3. Limitations
+	if len(product.Limitations) > 0 {
+		add(SectionLimitations, []string{fmt.Sprintf("Limitations: %s", strings.Join(product.Limitations, ", "))})
+	}
+
+	// 4. Target audience + common questions
+	var audience []string
+	if product.TargetAudience != "" {
+		audience = append(audience, fmt.Sprintf("Target Audience: %s", product.TargetAudience))
+	}
+	if len(product.CommonQuestions) > 0 {
+		audience = append(audience, fmt.Sprintf("Common Questions: %s", strings.Join(product.CommonQuestions, ", ")))
+	}
+	add(SectionAudience, audience)
+
+	// 5. Pricing, with the active variants so agents can recommend a specific tier
+	var pricing []string
+	if product.Price > 0 {
+		pricing = append(pricing, fmt.Sprintf("Price: %s %.2f", product.PriceCurrency, product.Price))
+	}
+	var tiers []string
+	for _, variant := range product.Variants {
+		if !variant.IsActive {
+			continue
+		}
+		tier := fmt.Sprintf("%s: %s %.2f", variant.Name, variant.PriceCurrency, variant.Price)
+		if variant.Description != "" {
+			tier += " (" + variant.Description + ")"
+		}
+		tiers = append(tiers, tier)
+	}
+	if len(tiers) > 0 {
+		pricing = append(pricing, fmt.Sprintf("Plans: %s", strings.Join(tiers, "; ")))
+	}
+	add(SectionPricing, pricing)
+
+	return chunks
+}
+
+// ChunkArticle splits knowledge article text into paragraph-aligned chunks of about 2000
+// characters. Chunks carry the product_id (when set) so retrieval merges them with the product.
+func (c *ProductChunker) ChunkArticle(tenantID, productID, articleID, title, text string) []ProductChunk {
+	var parts []string
+	var current strings.Builder
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if current.Len() > 0 && current.Len()+len(paragraph) > articleChunkSize {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(paragraph)
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	chunks := make([]ProductChunk, 0, len(parts))
+	for i, part := range parts {
+		chunkID := ProductChunkID(articleID, fmt.Sprintf("%s_%d", SectionArticle, i))
+		chunks = append(chunks, ProductChunk{
+			Text:        fmt.Sprintf("Article: %s\n%s", title, part),
+			SectionType: SectionArticle,
+			Metadata: map[string]interface{}{
+				"id":           chunkID,
+				"tenant_id":    tenantID,
+				"product_id":   productID,
+				"article_id":   articleID,
+				"name":         title,
+				"section_type": SectionArticle,
+			},
+		})
+	}
+	return chunks
+}
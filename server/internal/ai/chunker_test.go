@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func pricingChunk(chunks []ProductChunk) *ProductChunk {
+	for i := range chunks {
+		if chunks[i].SectionType == SectionPricing {
+			return &chunks[i]
+		}
+	}
+	return nil
+}
+
+func TestChunkIncludesActiveVariantsInPricing(t *testing.T) {
+	product := &models.Product{
+		ID:            "prod-1",
+		TenantID:      "tenant-1",
+		Name:          "Sales CRM",
+		Price:         999,
+		PriceCurrency: "INR",
+		Variants: []models.ProductVariant{
+			{Name: "Monthly", Price: 999, PriceCurrency: "INR", IsActive: true},
+			{Name: "Annual", Price: 9990, PriceCurrency: "INR", Description: "2 months free", IsActive: true},
+			{Name: "Legacy", Price: 499, PriceCurrency: "INR", IsActive: false},
+		},
+	}
+
+	chunk := pricingChunk(NewProductChunker().Chunk(product))
+	if chunk == nil {
+		t.Fatal("expected a pricing chunk")
+	}
+	for _, want := range []string{"Price: INR 999.00", "Monthly: INR 999.00", "Annual: INR 9990.00 (2 months free)"} {
+		if !strings.Contains(chunk.Text, want) {
+			t.Errorf("pricing text = %q, want it to contain %q", chunk.Text, want)
+		}
+	}
+	if strings.Contains(chunk.Text, "Legacy") {
+		t.Errorf("pricing text = %q, want inactive variants left out", chunk.Text)
+	}
+}
+
+func TestChunkPricingFromVariantsOnly(t *testing.T) {
+	product := &models.Product{
+		ID:       "prod-1",
+		Name:     "Seats add-on",
+		Variants: []models.ProductVariant{{Name: "10 seats", Price: 4999, PriceCurrency: "INR", IsActive: true}},
+	}
+
+	chunk := pricingChunk(NewProductChunker().Chunk(product))
+	if chunk == nil || !strings.Contains(chunk.Text, "Plans: 10 seats: INR 4999.00") {
+		t.Fatalf("pricing chunk = %+v, want the variant price without a base price", chunk)
+	}
+	if strings.Contains(chunk.Text, "Price:") {
+		t.Errorf("pricing text = %q, want no base price line for an unpriced product", chunk.Text)
+	}
+}
+
+func TestChunkOverviewUsesCategoryPath(t *testing.T) {
+	product := &models.Product{
+		ID:           "prod-1",
+		Name:         "WhatsApp Bot",
+		Description:  "Automated replies on WhatsApp",
+		Category:     "bots",
+		CategoryPath: "Automation > WhatsApp",
+	}
+
+	chunks := NewProductChunker().Chunk(product)
+	if len(chunks) == 0 || chunks[0].SectionType != SectionOverview {
+		t.Fatalf("chunks = %+v, want an overview chunk first", chunks)
+	}
+	if !strings.Contains(chunks[0].Text, "Category: Automation > WhatsApp") {
+		t.Errorf("overview text = %q, want the full category path", chunks[0].Text)
+	}
+	if chunks[0].Metadata["category"] != "Automation > WhatsApp" {
+		t.Errorf("category metadata = %v, want the full category path", chunks[0].Metadata["category"])
+	}
+
+	product.CategoryPath = ""
+	if text := NewProductChunker().Chunk(product)[0].Text; !strings.Contains(text, "Category: bots") {
+		t.Errorf("overview text = %q, want the free-text category without a category path", text)
+	}
+}
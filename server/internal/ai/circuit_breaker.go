@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"ai-conversation-platform/internal/ai/circuitbreaker"
+)
+
+const (
+	// defaultCircuitFailureThreshold is how many consecutive failed Gemini calls open the breaker
+	defaultCircuitFailureThreshold = 5
+	// defaultCircuitRecoveryTimeout is how long an open breaker rejects calls before probing again
+	defaultCircuitRecoveryTimeout = 30 * time.Second
+)
+
+// circuitBreakerFromEnv creates a Gemini circuit breaker configured by
+// GEMINI_CIRCUIT_FAILURE_THRESHOLD (default 5) and GEMINI_CIRCUIT_RECOVERY_SECONDS (default 30)
+func circuitBreakerFromEnv() *circuitbreaker.CircuitBreaker {
+	threshold := defaultCircuitFailureThreshold
+	if v := os.Getenv("GEMINI_CIRCUIT_FAILURE_THRESHOLD"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			threshold = n
+		}
+	}
+	recovery := defaultCircuitRecoveryTimeout
+	if v := os.Getenv("GEMINI_CIRCUIT_RECOVERY_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			recovery = time.Duration(n) * time.Second
+		}
+	}
+
+	breaker := circuitbreaker.New(threshold, recovery)
+	breaker.SetStateChangeListener(func(from, to circuitbreaker.State) {
+		log.Printf("[GEMINI] circuit breaker %s -> %s", from, to)
+	})
+	return breaker
+}
+
+// allowCall checks the circuit breaker before an API request
+func (c *Client) allowCall() error {
+	if c.breaker == nil {
+		return nil
+	}
+	return c.breaker.Allow()
+}
+
+// recordCall reports the outcome of an API request to the circuit breaker. Only outages count as
+// failures: client errors and rate limits mean the API is up.
+func (c *Client) recordCall(ctx context.Context, err error) {
+	if c.breaker == nil {
+		return
+	}
+	if isOutageError(ctx, err) {
+		c.breaker.RecordFailure()
+	} else {
+		c.breaker.RecordSuccess()
+	}
+}
+
+// isOutageError reports whether err means the Gemini API is unavailable: a server error or a
+// failed connection. Requests abandoned by the caller don't count.
+func isOutageError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	// The HTTP client reports failed connections and timeouts as *url.Error
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsCircuitOpen reports whether err is a call rejected because the Gemini circuit breaker is open
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, circuitbreaker.ErrCircuitOpen)
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/ai/circuitbreaker"
+)
+
+func TestGeminiCallsFailFastWhileCircuitOpen(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		http.Error(w, `{"error":{"message":"backend unavailable"}}`, http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = server.URL
+	client.SetCircuitBreaker(circuitbreaker.New(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.generateTextRequest(context.Background(), GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err == nil {
+			t.Fatal("expected the server error")
+		}
+	}
+	if _, err := client.generateTextRequest(context.Background(), GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("generateTextRequest err = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := client.GenerateEmbedding(GenerateEmbeddingRequest{Text: "hi"}); !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("GenerateEmbedding err = %v, want ErrCircuitOpen without retrying", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("server hits = %d, want no requests while the circuit is open", got)
+	}
+}
+
+func TestClientErrorsDontOpenCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") == "bad-key" {
+			http.Error(w, `{"error":{"message":"invalid key"}}`, http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"hello"}]}}]}`)
+	}))
+	defer server.Close()
+
+	breaker := circuitbreaker.New(1, time.Minute)
+	client := NewGeminiClientWithKey("bad-key")
+	client.baseURL = server.URL
+	client.SetCircuitBreaker(breaker)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.generateTextRequest(context.Background(), GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err == nil {
+			t.Fatal("expected the rejected request to fail")
+		}
+	}
+	if breaker.State() != circuitbreaker.StateClosed {
+		t.Errorf("breaker state = %s, want client errors not counted as outages", breaker.State())
+	}
+}
@@ -0,0 +1,147 @@
+// Package circuitbreaker stops calls to a failing dependency for a while so a provider outage
+// fails fast instead of tying up requests in retries.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Allow while the breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the breaker state
+type State int
+
+const (
+	// StateClosed lets calls through and counts consecutive failures
+	StateClosed State = iota
+	// StateOpen rejects calls until the recovery timeout has passed
+	StateOpen
+	// StateHalfOpen lets a single probe call through to decide whether to close again
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker opens after failureThreshold consecutive failures and rejects calls for
+// recoveryTimeout. After that one probe call is let through: success closes the breaker,
+// failure opens it for another recoveryTimeout.
+type CircuitBreaker struct {
+	failureThreshold int
+	recoveryTimeout  time.Duration
+	onStateChange    func(from, to State)
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open probe call is in flight
+}
+
+// New creates a closed circuit breaker. A failureThreshold below 1 is treated as 1.
+func New(failureThreshold int, recoveryTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		recoveryTimeout:  recoveryTimeout,
+		now:              time.Now,
+	}
+}
+
+// SetStateChangeListener sets a function called after every state change, e.g. to log it
+func (b *CircuitBreaker) SetStateChangeListener(listener func(from, to State)) {
+	b.onStateChange = listener
+}
+
+// State returns the current state. An open breaker whose recovery timeout has passed reports
+// half-open, since the next call will be let through as a probe.
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.recoveryElapsed() {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a call may go ahead, returning ErrCircuitOpen when it may not.
+// Every allowed call must be followed by RecordSuccess or RecordFailure.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateOpen:
+		if !b.recoveryElapsed() {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+	case StateHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return nil
+}
+
+// RecordSuccess records a successful call, closing the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	from := b.state
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+	b.mu.Unlock()
+
+	b.notify(from, StateClosed)
+}
+
+// RecordFailure records a failed call. The breaker opens once failures reach the threshold,
+// or straight away when the half-open probe fails.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	from := b.state
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+	b.probing = false
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *CircuitBreaker) recoveryElapsed() bool {
+	return b.now().Sub(b.openedAt) >= b.recoveryTimeout
+}
+
+func (b *CircuitBreaker) notify(from, to State) {
+	if from != to && b.onStateChange != nil {
+		b.onStateChange(from, to)
+	}
+}
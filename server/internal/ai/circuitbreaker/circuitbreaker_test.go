@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker on a fake clock and a function to move the clock forward
+func newTestBreaker(threshold int, recovery time.Duration) (*CircuitBreaker, func(time.Duration)) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New(threshold, recovery)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func failCall(t *testing.T, b *CircuitBreaker) {
+	t.Helper()
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() = %v, want the call let through", err)
+	}
+	b.RecordFailure()
+}
+
+func TestOpensAfterSequentialFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, 30*time.Second)
+
+	for i := 0; i < 2; i++ {
+		failCall(t, b)
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("state = %s after 2 failures, want closed", b.State())
+	}
+	failCall(t, b)
+	if b.State() != StateOpen {
+		t.Fatalf("state = %s after 3 failures, want open", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestSuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(2, 30*time.Second)
+
+	failCall(t, b)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() = %v", err)
+	}
+	b.RecordSuccess()
+	failCall(t, b)
+	if b.State() != StateClosed {
+		t.Errorf("state = %s, want failures separated by a success not to open the breaker", b.State())
+	}
+}
+
+func TestRecoversAfterTimeout(t *testing.T) {
+	b, advance := newTestBreaker(2, 30*time.Second)
+	var transitions []string
+	b.SetStateChangeListener(func(from, to State) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	})
+	failCall(t, b)
+	failCall(t, b)
+
+	advance(29 * time.Second)
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() before the recovery timeout = %v, want ErrCircuitOpen", err)
+	}
+
+	advance(time.Second)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("state = %s after the recovery timeout, want half-open", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() = %v, want the probe let through", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second Allow() during the probe = %v, want ErrCircuitOpen", err)
+	}
+	b.RecordSuccess()
+	if b.State() != StateClosed {
+		t.Fatalf("state = %s after a successful probe, want closed", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow() after recovery = %v", err)
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transitions = %v, want %v", transitions, want)
+			break
+		}
+	}
+}
+
+func TestFailedProbeReopens(t *testing.T) {
+	b, advance := newTestBreaker(3, 10*time.Second)
+	for i := 0; i < 3; i++ {
+		failCall(t, b)
+	}
+
+	advance(10 * time.Second)
+	failCall(t, b)
+	if b.State() != StateOpen {
+		t.Fatalf("state = %s after a failed probe, want open", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() = %v, want the recovery timeout restarted", err)
+	}
+
+	advance(10 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow() = %v, want another probe after the timeout", err)
+	}
+}
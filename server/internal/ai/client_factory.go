@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// geminiProvider is the credential provider name for Gemini keys
+const geminiProvider = "gemini"
+
+// CredentialSource looks up tenant-specific API keys.
+// GetAPIKey returns "" when the tenant has no key for the provider.
+type CredentialSource interface {
+	GetAPIKey(tenantID, provider string) (string, error)
+}
+
+// GeminiClientFactory resolves the text generator to use for a tenant.
+// Tenant Gemini keys from the credential source take precedence over the default generator,
+// usually the provider chain built from AI_PROVIDER_ORDER.
+type GeminiClientFactory struct {
+	credentials   CredentialSource
+	defaultClient TextGenerator
+	clients       sync.Map // tenantID -> TextGenerator
+}
+
+// NewGeminiClientFactory creates a client factory. defaultClient may be nil.
+func NewGeminiClientFactory(credentials CredentialSource, defaultClient TextGenerator) *GeminiClientFactory {
+	return &GeminiClientFactory{
+		credentials:   credentials,
+		defaultClient: defaultClient,
+	}
+}
+
+// ClientForTenant returns the tenant's Gemini client, or the default generator when the tenant
+// has no key of its own, caching it for later calls
+func (f *GeminiClientFactory) ClientForTenant(tenantID string) (TextGenerator, error) {
+	if cached, ok := f.clients.Load(tenantID); ok {
+		return cached.(TextGenerator), nil
+	}
+
+	client := f.defaultClient
+	if f.credentials != nil {
+		apiKey, err := f.credentials.GetAPIKey(tenantID, geminiProvider)
+		if err != nil {
+			// Don't cache the fallback so the tenant key is retried next time
+			log.Printf("[AI] failed to load tenant gemini key, using default tenant=%s error=%v", tenantID, err)
+			if f.defaultClient == nil {
+				return nil, fmt.Errorf("failed to load gemini key: %w", err)
+			}
+			return f.defaultClient, nil
+		}
+		if apiKey != "" {
+			client = NewGeminiClientWithKey(apiKey)
+		}
+	}
+
+	if client == nil {
+		return nil, fmt.Errorf("no gemini API key configured for tenant")
+	}
+
+	f.clients.Store(tenantID, client)
+	return client, nil
+}
+
+// Invalidate drops the cached client for a tenant after its key changes
+func (f *GeminiClientFactory) Invalidate(tenantID string) {
+	f.clients.Delete(tenantID)
+}
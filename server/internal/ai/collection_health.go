@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// Collections are the Chroma collections the embedding service reads and writes
+var Collections = []string{
+	string(ContentTypeProductKnowledge),
+	string(ContentTypeConversationTranscript),
+	string(ContentTypeMessage),
+}
+
+// HealthCheck returns the names of the collections in Collections that don't exist in Chroma.
+// Embedding writes and queries against a missing collection fail until it is recreated.
+func (s *EmbeddingService) HealthCheck(ctx context.Context) ([]string, error) {
+	if s.chromaClient == nil {
+		return nil, fmt.Errorf("vector store is not configured")
+	}
+	missing := []string{}
+	for _, name := range Collections {
+		_, err := s.chromaClient.GetCollectionContext(ctx, name)
+		if errors.Is(err, chroma.ErrCollectionNotFound) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check collection %s: %w", name, err)
+		}
+	}
+	return missing, nil
+}
+
+// RecreateMissingCollections creates every collection HealthCheck reports missing and returns
+// their names. The documents they held are not restored; products are re-embedded on their
+// next update or with migrate -reembed-products.
+func (s *EmbeddingService) RecreateMissingCollections(ctx context.Context) ([]string, error) {
+	missing, err := s.HealthCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recreated := []string{}
+	for _, name := range missing {
+		if err := s.chromaClient.EnsureCollection(name); err != nil {
+			return recreated, fmt.Errorf("failed to recreate collection %s: %w", name, err)
+		}
+		log.Printf("[Embedding] recreated missing collection=%s", name)
+		recreated = append(recreated, name)
+	}
+	return recreated, nil
+}
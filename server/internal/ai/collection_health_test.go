@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// fakeChromaCollections serves Chroma's collection API, answering 404 for missing collections
+type fakeChromaCollections struct {
+	mu      sync.Mutex
+	exists  map[string]bool
+	created []string
+}
+
+func (f *fakeChromaCollections) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/collections/"):
+		name := strings.TrimPrefix(r.URL.Path, "/api/v1/collections/")
+		if !f.exists[name] {
+			http.Error(w, `{"error":"collection does not exist"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": name})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/collections":
+		var body struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.exists[body.Name] = true
+		f.created = append(f.created, body.Name)
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}
+}
+
+func newCollectionTestService(t *testing.T, existing ...string) (*EmbeddingService, *fakeChromaCollections) {
+	t.Helper()
+	fake := &fakeChromaCollections{exists: map[string]bool{}}
+	for _, name := range existing {
+		fake.exists["default_"+name] = true
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("CHROMA_URL", server.URL)
+	t.Setenv("TENANT_ID", "")
+	client, err := chroma.NewClient()
+	if err != nil {
+		t.Fatalf("chroma.NewClient: %v", err)
+	}
+	return NewEmbeddingService(nil, client), fake
+}
+
+func TestEmbeddingHealthCheckReportsMissingCollections(t *testing.T) {
+	service, _ := newCollectionTestService(t, "product_knowledge", "message_index")
+
+	missing, err := service.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != "conversation_context" {
+		t.Errorf("missing = %v, want [conversation_context]", missing)
+	}
+}
+
+func TestRecreateMissingCollections(t *testing.T) {
+	service, fake := newCollectionTestService(t)
+
+	recreated, err := service.RecreateMissingCollections(context.Background())
+	if err != nil {
+		t.Fatalf("RecreateMissingCollections: %v", err)
+	}
+	if len(recreated) != 3 {
+		t.Errorf("recreated = %v, want all collections", recreated)
+	}
+	if len(fake.created) != 3 || fake.created[0] != "default_product_knowledge" || fake.created[1] != "default_conversation_context" || fake.created[2] != "default_message_index" {
+		t.Errorf("created in chroma = %v", fake.created)
+	}
+
+	// Once recreated, nothing is missing and nothing more is created
+	if missing, err := service.HealthCheck(context.Background()); err != nil || len(missing) != 0 {
+		t.Errorf("HealthCheck after recreate = %v, %v; want none missing", missing, err)
+	}
+	if recreated, _ := service.RecreateMissingCollections(context.Background()); len(recreated) != 0 {
+		t.Errorf("second recreate = %v, want none", recreated)
+	}
+}
+
+func TestEnsureCollectionCreatesOnlyWhenMissing(t *testing.T) {
+	service, fake := newCollectionTestService(t, "product_knowledge")
+	client := service.chromaClient
+
+	if err := client.EnsureCollection("product_knowledge"); err != nil {
+		t.Fatalf("EnsureCollection(existing): %v", err)
+	}
+	if len(fake.created) != 0 {
+		t.Errorf("created = %v, want none for an existing collection", fake.created)
+	}
+	if err := client.EnsureCollection("conversation_context"); err != nil {
+		t.Fatalf("EnsureCollection(missing): %v", err)
+	}
+	if len(fake.created) != 1 || fake.created[0] != "default_conversation_context" {
+		t.Errorf("created = %v, want default_conversation_context", fake.created)
+	}
+}
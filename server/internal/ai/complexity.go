@@ -0,0 +1,212 @@
+package ai
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// Complexity buckets used for distribution analytics
+const (
+	ComplexityLow    = "low"    // 1-3
+	ComplexityMedium = "medium" // 4-6
+	ComplexityHigh   = "high"   // 7-10
+)
+
+// HighComplexityThreshold is the minimum score considered high complexity
+const HighComplexityThreshold = 7
+
+// highComplexitySLAMultiplier extends first-response SLA for high complexity conversations
+const highComplexitySLAMultiplier = 1.5
+
+// ComplexityFactors holds the raw inputs that drive the complexity score
+type ComplexityFactors struct {
+	ObjectionCount    int `json:"objection_count"`
+	MessageCount      int `json:"message_count"`
+	UniqueTopicsCount int `json:"unique_topics_count"`
+	LanguageSwitches  int `json:"language_switches"`
+	UrgencyLevel      int `json:"urgency_level"` // 0 (none) - 3 (critical)
+}
+
+// ComplexityScore represents how complex a conversation is to handle
+type ComplexityScore struct {
+	Score   int               `json:"score"` // 1-10
+	Factors ComplexityFactors `json:"factors"`
+}
+
+// topicKeywords maps conversation topics to keywords that indicate them
+var topicKeywords = map[string][]string{
+	"pricing":     {"price", "cost", "pricing", "discount", "budget", "expensive", "cheap", "plan"},
+	"features":    {"feature", "functionality", "capability", "support for", "does it"},
+	"integration": {"integrate", "integration", "api", "crm", "connect", "sync"},
+	"delivery":    {"delivery", "shipping", "onboarding", "setup", "timeline", "deploy"},
+	"contract":    {"contract", "agreement", "terms", "cancel", "refund", "invoice", "billing"},
+	"security":    {"security", "privacy", "gdpr", "compliance", "data protection"},
+	"support":     {"help", "issue", "problem", "error", "broken", "not working"},
+	"competitor":  {"competitor", "alternative", "other vendor", "compared to"},
+}
+
+// urgencyKeywords are ordered from strongest to weakest signal
+var urgencyKeywords = []struct {
+	level    int
+	keywords []string
+}{
+	{3, []string{"emergency", "critical", "immediately", "right now"}},
+	{2, []string{"urgent", "asap", "as soon as possible", "today"}},
+	{1, []string{"soon", "quickly", "this week", "deadline"}},
+}
+
+// ComplexityScorer scores conversations by handling complexity
+type ComplexityScorer struct{}
+
+// NewComplexityScorer creates a new complexity scorer
+func NewComplexityScorer() *ComplexityScorer {
+	return &ComplexityScorer{}
+}
+
+// Score calculates a 1-10 complexity score from messages and analysis metadata
+func (s *ComplexityScorer) Score(messages []*models.Message, metadata *models.ConversationMetadata) ComplexityScore {
+	factors := ComplexityFactors{
+		MessageCount:      len(messages),
+		UniqueTopicsCount: s.countTopics(messages),
+		LanguageSwitches:  s.countLanguageSwitches(messages),
+		UrgencyLevel:      s.urgencyLevel(messages, metadata),
+	}
+	if metadata != nil {
+		factors.ObjectionCount = len(metadata.Objections)
+	}
+
+	// Each factor contributes a bounded number of points (max 13 total)
+	points := minInt(factors.ObjectionCount, 3)
+
+	switch {
+	case factors.MessageCount >= 30:
+		points += 3
+	case factors.MessageCount >= 15:
+		points += 2
+	case factors.MessageCount >= 5:
+		points++
+	}
+
+	if factors.UniqueTopicsCount > 1 {
+		points += minInt(factors.UniqueTopicsCount-1, 3)
+	}
+
+	points += minInt(factors.LanguageSwitches, 2)
+	points += minInt(factors.UrgencyLevel, 2)
+
+	score := 1 + int(math.Round(float64(points)*9.0/13.0))
+	if score > 10 {
+		score = 10
+	}
+
+	return ComplexityScore{Score: score, Factors: factors}
+}
+
+// countTopics counts distinct topics mentioned by the customer
+func (s *ComplexityScorer) countTopics(messages []*models.Message) int {
+	topics := make(map[string]bool)
+	for _, msg := range messages {
+		if msg.Sender != "customer" {
+			continue
+		}
+		content := strings.ToLower(msg.Content)
+		for topic, keywords := range topicKeywords {
+			if topics[topic] {
+				continue
+			}
+			for _, keyword := range keywords {
+				if strings.Contains(content, keyword) {
+					topics[topic] = true
+					break
+				}
+			}
+		}
+	}
+	return len(topics)
+}
+
+// countLanguageSwitches counts changes of detected language between consecutive customer messages
+func (s *ComplexityScorer) countLanguageSwitches(messages []*models.Message) int {
+	switches := 0
+	lastLang := ""
+	for _, msg := range messages {
+		if msg.Sender != "customer" || msg.Language == "" || msg.Language == "unknown" {
+			continue
+		}
+		if lastLang != "" && msg.Language != lastLang {
+			switches++
+		}
+		lastLang = msg.Language
+	}
+	return switches
+}
+
+// urgencyLevel returns the strongest urgency signal found in customer messages or emotions
+func (s *ComplexityScorer) urgencyLevel(messages []*models.Message, metadata *models.ConversationMetadata) int {
+	level := 0
+	if metadata != nil {
+		for _, emotion := range metadata.Emotions {
+			if strings.EqualFold(emotion, "urgency") {
+				level = 2
+			}
+		}
+	}
+
+	for _, msg := range messages {
+		if msg.Sender != "customer" {
+			continue
+		}
+		content := strings.ToLower(msg.Content)
+		for _, group := range urgencyKeywords {
+			if group.level <= level {
+				break
+			}
+			for _, keyword := range group.keywords {
+				if strings.Contains(content, keyword) {
+					level = group.level
+					break
+				}
+			}
+		}
+	}
+	return level
+}
+
+// ComplexityBucket returns the distribution bucket for a score
+func ComplexityBucket(score int) string {
+	switch {
+	case score >= HighComplexityThreshold:
+		return ComplexityHigh
+	case score >= 4:
+		return ComplexityMedium
+	default:
+		return ComplexityLow
+	}
+}
+
+// AdjustFirstResponseSLA extends the first-response SLA by 50% for high complexity conversations
+func (c ComplexityScore) AdjustFirstResponseSLA(base time.Duration) time.Duration {
+	if c.Score >= HighComplexityThreshold {
+		return time.Duration(float64(base) * highComplexitySLAMultiplier)
+	}
+	return base
+}
+
+// FirstResponseSLA returns the first-response SLA for a conversation. Watchlisted conversations
+// skip the complexity extension and always get the base (shortest) SLA.
+func (c ComplexityScore) FirstResponseSLA(base time.Duration, watchlisted bool) time.Duration {
+	if watchlisted {
+		return base
+	}
+	return c.AdjustFirstResponseSLA(base)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func customerMessage(content, language string) *models.Message {
+	return &models.Message{Sender: "customer", Content: content, Language: language}
+}
+
+func TestComplexityScorerSimpleConversation(t *testing.T) {
+	score := NewComplexityScorer().Score([]*models.Message{
+		customerMessage("Hi, what does the starter plan cost?", "en"),
+		{Sender: "agent", Content: "It's $20 a month, and it integrates with your CRM", Language: "en"},
+	}, nil)
+
+	if score.Score != 1 {
+		t.Errorf("score = %d, want the minimum of 1", score.Score)
+	}
+	want := ComplexityFactors{MessageCount: 2, UniqueTopicsCount: 1}
+	if score.Factors != want {
+		t.Errorf("factors = %+v, want %+v (agent messages don't add topics)", score.Factors, want)
+	}
+	if ComplexityBucket(score.Score) != ComplexityLow {
+		t.Errorf("bucket = %s, want low", ComplexityBucket(score.Score))
+	}
+}
+
+func TestComplexityScorerFactors(t *testing.T) {
+	messages := []*models.Message{
+		customerMessage("We need this urgent: what's the price and does the API integrate with our CRM?", "en"),
+		customerMessage("¿Cumple con GDPR? security matters to us", "es"),
+		customerMessage("Our contract ends this week, we need delivery and setup immediately", "en"),
+		customerMessage("Is it cheaper than the competitor?", "unknown"),
+		customerMessage("ok", "en"),
+	}
+	metadata := &models.ConversationMetadata{Objections: []string{"price", "timing", "trust", "competitor"}}
+
+	score := NewComplexityScorer().Score(messages, metadata)
+	want := ComplexityFactors{
+		ObjectionCount:    4,
+		MessageCount:      5,
+		UniqueTopicsCount: 6, // pricing, integration, security, contract, delivery, competitor
+		LanguageSwitches:  2, // en -> es -> en; unknown languages are skipped
+		UrgencyLevel:      3, // "immediately" outranks "urgent"
+	}
+	if score.Factors != want {
+		t.Errorf("factors = %+v, want %+v", score.Factors, want)
+	}
+	// Capped points: 3 objections + 1 message + 3 topics + 2 switches + 2 urgency = 11 of 13
+	if score.Score != 9 {
+		t.Errorf("score = %d, want 9", score.Score)
+	}
+	if ComplexityBucket(score.Score) != ComplexityHigh {
+		t.Errorf("bucket = %s, want high", ComplexityBucket(score.Score))
+	}
+}
+
+func TestComplexityScorerCapsAtTen(t *testing.T) {
+	var messages []*models.Message
+	for i := 0; i < 40; i++ {
+		language := "en"
+		if i%2 == 1 {
+			language = "fr"
+		}
+		messages = append(messages, customerMessage(strings.Join([]string{
+			"emergency", "price", "feature", "api", "delivery", "contract", "security", "help", "competitor",
+		}, " "), language))
+	}
+	metadata := &models.ConversationMetadata{Objections: []string{"a", "b", "c", "d", "e"}}
+
+	if score := NewComplexityScorer().Score(messages, metadata); score.Score != 10 {
+		t.Errorf("score = %d, want the maximum of 10", score.Score)
+	}
+}
+
+func TestComplexityScorerUrgencyFromEmotions(t *testing.T) {
+	metadata := &models.ConversationMetadata{Emotions: []string{"Urgency"}}
+	score := NewComplexityScorer().Score([]*models.Message{customerMessage("hello", "en")}, metadata)
+	if score.Factors.UrgencyLevel != 2 {
+		t.Errorf("urgency = %d, want 2 from the urgency emotion", score.Factors.UrgencyLevel)
+	}
+
+	// Weaker keywords don't lower it
+	score = NewComplexityScorer().Score([]*models.Message{customerMessage("reply soon please", "en")}, metadata)
+	if score.Factors.UrgencyLevel != 2 {
+		t.Errorf("urgency = %d, want 2 kept over the weaker keyword", score.Factors.UrgencyLevel)
+	}
+}
+
+func TestComplexityBucket(t *testing.T) {
+	tests := map[int]string{1: ComplexityLow, 3: ComplexityLow, 4: ComplexityMedium, 6: ComplexityMedium, 7: ComplexityHigh, 10: ComplexityHigh}
+	for score, want := range tests {
+		if got := ComplexityBucket(score); got != want {
+			t.Errorf("ComplexityBucket(%d) = %s, want %s", score, got, want)
+		}
+	}
+}
+
+func TestComplexityFirstResponseSLA(t *testing.T) {
+	base := 30 * time.Minute
+	tests := []struct {
+		score       int
+		watchlisted bool
+		want        time.Duration
+	}{
+		{6, false, 30 * time.Minute},
+		{7, false, 45 * time.Minute},
+		{10, false, 45 * time.Minute},
+		{10, true, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := (ComplexityScore{Score: tt.score}).FirstResponseSLA(base, tt.watchlisted); got != tt.want {
+			t.Errorf("FirstResponseSLA(score %d, watchlisted %v) = %s, want %s", tt.score, tt.watchlisted, got, tt.want)
+		}
+	}
+}
@@ -11,18 +11,48 @@ type ConfidenceInputs struct {
 	ContextScores  []float64
 	RuleResults   []bool
 	SelfEvaluation float64
+	AcceptanceRate *float64 // Historic acceptance rate (0-1) of suggestions for the conversation's intent, if known
+	TenantID       string   // Tenant whose confidence calibration applies, if any
 }
 
 // ConfidenceScorer calculates confidence scores from multiple signals
-type ConfidenceScorer struct{}
+type ConfidenceScorer struct {
+	acceptanceWeight float64               // Weight of a known acceptance rate; 0 ignores it
+	calibrator       *ConfidenceCalibrator // Optional per-tenant calibration against feedback
+}
 
 // NewConfidenceScorer creates a new confidence scorer
 func NewConfidenceScorer() *ConfidenceScorer {
 	return &ConfidenceScorer{}
 }
 
-// CalculateConfidence computes confidence from multiple signals
+// SetAcceptanceWeight sets how much (0-1) a known historic acceptance rate counts towards confidence
+func (c *ConfidenceScorer) SetAcceptanceWeight(weight float64) {
+	c.acceptanceWeight = weight
+}
+
+// SetCalibrator rescales confidence to each tenant's observed suggestion acceptance (optional)
+func (c *ConfidenceScorer) SetCalibrator(calibrator *ConfidenceCalibrator) {
+	c.calibrator = calibrator
+}
+
+// CalculateConfidence computes confidence from multiple signals, calibrated for the inputs'
+// tenant when a calibrator is set
 func (c *ConfidenceScorer) CalculateConfidence(inputs ConfidenceInputs) float64 {
+	return c.Calibrate(inputs.TenantID, c.RawConfidence(inputs))
+}
+
+// Calibrate applies the tenant's Platt scaling curve to a raw confidence score. Scores are
+// returned unchanged without a calibrator, a tenant or a fitted curve.
+func (c *ConfidenceScorer) Calibrate(tenantID string, confidence float64) float64 {
+	if c.calibrator == nil || tenantID == "" {
+		return confidence
+	}
+	return c.calibrator.Calibrate(tenantID, confidence)
+}
+
+// RawConfidence computes confidence from multiple signals, before calibration
+func (c *ConfidenceScorer) RawConfidence(inputs ConfidenceInputs) float64 {
 	contextScore := c.calculateContextRelevance(inputs.ContextScores)
 	consistencyScore := c.checkSignalConsistency(inputs.Analysis)
 	ruleScore := c.calculateRuleValidation(inputs.RuleResults)
@@ -35,6 +65,11 @@ func (c *ConfidenceScorer) CalculateConfidence(inputs ConfidenceInputs) float64
 	confidence += ruleScore * 0.2
 	confidence += selfEvalScore * 0.1
 
+	// Blend in how often agents used suggestions for similar conversations
+	if inputs.AcceptanceRate != nil && c.acceptanceWeight > 0 {
+		confidence = confidence*(1-c.acceptanceWeight) + *inputs.AcceptanceRate*c.acceptanceWeight
+	}
+
 	if confidence > 1.0 {
 		confidence = 1.0
 	}
@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"ai-conversation-platform/internal/cache"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// confidenceCalibrationFeedback is how many of a tenant's latest feedback entries are calibrated against
+	confidenceCalibrationFeedback = 1000
+	// minConfidenceCalibrationSamples is the fewest samples needed before confidence is rescaled
+	minConfidenceCalibrationSamples = 50
+	// confidenceCalibrationBuckets splits confidence into deciles
+	confidenceCalibrationBuckets = 10
+	// confidenceCalibrationTTL is how long a tenant's calibration is reused before it is refit
+	confidenceCalibrationTTL = time.Hour
+)
+
+// ConfidenceFeedbackSource lists the confidence of suggestions agents gave feedback on
+// (see postgres.SuggestionFeedbackStorage)
+type ConfidenceFeedbackSource interface {
+	ListFeedbackConfidence(tenantID string, limit int) ([]postgres.FeedbackConfidenceSample, error)
+}
+
+// ConfidenceBucket compares predicted and observed acceptance for a decile of raw confidence
+type ConfidenceBucket struct {
+	ConfidenceBucket     string  `json:"confidence_bucket"`     // e.g. "0.7-0.8"
+	PredictedAcceptance  float64 `json:"predicted_acceptance"`  // Mean raw confidence
+	CalibratedAcceptance float64 `json:"calibrated_acceptance"` // Mean confidence after Platt scaling
+	ActualAcceptance     float64 `json:"actual_acceptance"`     // Share of suggestions accepted or edited
+	Samples              int     `json:"samples"`
+}
+
+// ConfidenceCalibration is a tenant's calibration curve. When Calibrated, raw confidence s maps
+// to 1/(1+exp(-(A*s+B))), fitted to what agents did with earlier suggestions (Platt scaling).
+type ConfidenceCalibration struct {
+	Calibrated bool               `json:"calibrated"` // Enough accepted and rejected samples to fit the curve
+	A          float64            `json:"a"`
+	B          float64            `json:"b"`
+	Samples    int                `json:"samples"`
+	Buckets    []ConfidenceBucket `json:"buckets"` // Deciles with samples, lowest first
+}
+
+// Apply maps a raw confidence score to the calibrated acceptance probability. Uncalibrated
+// curves return the score unchanged.
+func (c *ConfidenceCalibration) Apply(score float64) float64 {
+	if c == nil || !c.Calibrated {
+		return score
+	}
+	return sigmoid(c.A*score + c.B)
+}
+
+// ConfidenceCalibrator fits each tenant's suggestion confidence to its agents' feedback. Curves
+// are cached, so they follow new feedback within confidenceCalibrationTTL.
+type ConfidenceCalibrator struct {
+	source ConfidenceFeedbackSource
+	cache  cache.Cache
+}
+
+// NewConfidenceCalibrator creates a confidence calibrator. A nil cache refits on every call.
+func NewConfidenceCalibrator(source ConfidenceFeedbackSource, c cache.Cache) *ConfidenceCalibrator {
+	return &ConfidenceCalibrator{source: source, cache: c}
+}
+
+// Calibration returns the tenant's calibration curve, from the cache when possible
+func (c *ConfidenceCalibrator) Calibration(tenantID string) (*ConfidenceCalibration, error) {
+	return cache.Fetch(c.cache, confidenceCalibrationCacheKey(tenantID), confidenceCalibrationTTL, func() (*ConfidenceCalibration, error) {
+		return c.fit(tenantID)
+	})
+}
+
+// Refresh refits the tenant's calibration curve from the latest feedback and caches it
+func (c *ConfidenceCalibrator) Refresh(tenantID string) (*ConfidenceCalibration, error) {
+	calibration, err := c.fit(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		if data, err := json.Marshal(calibration); err == nil {
+			c.cache.Set(confidenceCalibrationCacheKey(tenantID), data, confidenceCalibrationTTL)
+		}
+	}
+	return calibration, nil
+}
+
+// Calibrate maps a raw confidence score through the tenant's curve. Without a usable curve, or
+// when it can't be loaded, the score is returned unchanged.
+func (c *ConfidenceCalibrator) Calibrate(tenantID string, score float64) float64 {
+	calibration, err := c.Calibration(tenantID)
+	if err != nil {
+		log.Printf("[AI] failed to load confidence calibration tenant=%s: %v", tenantID, err)
+		return score
+	}
+	return calibration.Apply(score)
+}
+
+func (c *ConfidenceCalibrator) fit(tenantID string) (*ConfidenceCalibration, error) {
+	samples, err := c.source.ListFeedbackConfidence(tenantID, confidenceCalibrationFeedback)
+	if err != nil {
+		return nil, err
+	}
+	return FitConfidenceCalibration(samples), nil
+}
+
+func confidenceCalibrationCacheKey(tenantID string) string {
+	return "ai:confidence-calibration:" + tenantID
+}
+
+// FitConfidenceCalibration bins samples by confidence decile and fits a Platt scaling curve to
+// them. The curve is only used once there are minConfidenceCalibrationSamples samples with both
+// accepted and rejected suggestions among them.
+func FitConfidenceCalibration(samples []postgres.FeedbackConfidenceSample) *ConfidenceCalibration {
+	calibration := &ConfidenceCalibration{Samples: len(samples), Buckets: []ConfidenceBucket{}}
+	accepted := 0
+	for _, sample := range samples {
+		if sample.Accepted {
+			accepted++
+		}
+	}
+	if len(samples) >= minConfidenceCalibrationSamples && accepted > 0 && accepted < len(samples) {
+		calibration.A, calibration.B = fitPlatt(samples, accepted)
+		calibration.Calibrated = true
+	}
+
+	type bucketTotals struct {
+		predicted, calibrated float64
+		accepted, samples     int
+	}
+	totals := make([]bucketTotals, confidenceCalibrationBuckets)
+	for _, sample := range samples {
+		score := math.Max(0, math.Min(1, sample.Confidence))
+		index := int(score * confidenceCalibrationBuckets)
+		if index == confidenceCalibrationBuckets {
+			index-- // A score of 1 belongs to the top decile
+		}
+		totals[index].predicted += score
+		totals[index].calibrated += calibration.Apply(score)
+		totals[index].samples++
+		if sample.Accepted {
+			totals[index].accepted++
+		}
+	}
+	for index, bucket := range totals {
+		if bucket.samples == 0 {
+			continue
+		}
+		n := float64(bucket.samples)
+		calibration.Buckets = append(calibration.Buckets, ConfidenceBucket{
+			ConfidenceBucket:     fmt.Sprintf("%.1f-%.1f", float64(index)/confidenceCalibrationBuckets, float64(index+1)/confidenceCalibrationBuckets),
+			PredictedAcceptance:  bucket.predicted / n,
+			CalibratedAcceptance: bucket.calibrated / n,
+			ActualAcceptance:     float64(bucket.accepted) / n,
+			Samples:              bucket.samples,
+		})
+	}
+	return calibration
+}
+
+// fitPlatt fits A and B of sigmoid(A*score+B) to the samples by Newton's method, using Platt's
+// smoothed targets so a perfectly separable history doesn't push the curve to a step
+func fitPlatt(samples []postgres.FeedbackConfidenceSample, accepted int) (float64, float64) {
+	rejected := len(samples) - accepted
+	hiTarget := (float64(accepted) + 1) / (float64(accepted) + 2)
+	loTarget := 1 / (float64(rejected) + 2)
+	targets := make([]float64, len(samples))
+	for i, sample := range samples {
+		targets[i] = loTarget
+		if sample.Accepted {
+			targets[i] = hiTarget
+		}
+	}
+
+	loss := func(a, b float64) float64 {
+		total := 0.0
+		for i, sample := range samples {
+			z := a*sample.Confidence + b
+			// log(1+exp(z)) - t*z, written to avoid overflow
+			total += math.Max(z, 0) + math.Log1p(math.Exp(-math.Abs(z))) - targets[i]*z
+		}
+		return total
+	}
+
+	a, b := 0.0, math.Log((float64(accepted)+1)/(float64(rejected)+1))
+	current := loss(a, b)
+	for iteration := 0; iteration < 100; iteration++ {
+		var gradA, gradB, hAA, hAB, hBB float64
+		for i, sample := range samples {
+			p := sigmoid(a*sample.Confidence + b)
+			d := p - targets[i]
+			w := p * (1 - p)
+			gradA += d * sample.Confidence
+			gradB += d
+			hAA += w * sample.Confidence * sample.Confidence
+			hAB += w * sample.Confidence
+			hBB += w
+		}
+		// Ridge the Hessian so samples with a single confidence value still give a step
+		hAA += 1e-9
+		hBB += 1e-9
+		det := hAA*hBB - hAB*hAB
+		if math.Abs(gradA) < 1e-9 && math.Abs(gradB) < 1e-9 || det <= 0 {
+			break
+		}
+		stepA := (hBB*gradA - hAB*gradB) / det
+		stepB := (hAA*gradB - hAB*gradA) / det
+
+		// Halve the step until the loss improves
+		improved := false
+		for scale := 1.0; scale > 1e-6; scale /= 2 {
+			nextA, nextB := a-scale*stepA, b-scale*stepB
+			if next := loss(nextA, nextB); next < current {
+				a, b, current = nextA, nextB, next
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return a, b
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
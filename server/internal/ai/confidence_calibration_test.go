@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"ai-conversation-platform/internal/cache"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeConfidenceFeedback struct {
+	samples []postgres.FeedbackConfidenceSample
+	err     error
+	calls   int
+}
+
+func (f *fakeConfidenceFeedback) ListFeedbackConfidence(tenantID string, limit int) ([]postgres.FeedbackConfidenceSample, error) {
+	f.calls++
+	if len(f.samples) > limit {
+		return f.samples[:limit], f.err
+	}
+	return f.samples, f.err
+}
+
+// overconfidentFeedback spreads n scores evenly over [0,1] and accepts each with probability
+// score², like a scorer that overstates how often its suggestions get used. Outcomes are drawn
+// from a low-discrepancy sequence so the test is deterministic.
+func overconfidentFeedback(n int) []postgres.FeedbackConfidenceSample {
+	samples := make([]postgres.FeedbackConfidenceSample, n)
+	for i := range samples {
+		score := (float64(i) + 0.5) / float64(n)
+		draw := math.Mod(float64(i)*0.6180339887, 1)
+		samples[i] = postgres.FeedbackConfidenceSample{Confidence: score, Accepted: draw < score*score}
+	}
+	return samples
+}
+
+func TestFitConfidenceCalibrationCorrectsMiscalibratedScores(t *testing.T) {
+	calibration := FitConfidenceCalibration(overconfidentFeedback(1000))
+	if !calibration.Calibrated || calibration.Samples != 1000 {
+		t.Fatalf("calibration = %+v, want a curve fitted to 1000 samples", calibration)
+	}
+	if len(calibration.Buckets) != confidenceCalibrationBuckets {
+		t.Fatalf("buckets = %d, want %d", len(calibration.Buckets), confidenceCalibrationBuckets)
+	}
+	if calibration.Buckets[0].ConfidenceBucket != "0.0-0.1" || calibration.Buckets[9].ConfidenceBucket != "0.9-1.0" {
+		t.Errorf("buckets = %q..%q, want 0.0-0.1..0.9-1.0", calibration.Buckets[0].ConfidenceBucket, calibration.Buckets[9].ConfidenceBucket)
+	}
+
+	var rawError, calibratedError float64
+	for _, bucket := range calibration.Buckets {
+		rawError += math.Abs(bucket.PredictedAcceptance - bucket.ActualAcceptance)
+		calibratedError += math.Abs(bucket.CalibratedAcceptance - bucket.ActualAcceptance)
+	}
+	if calibratedError > rawError/3 {
+		t.Errorf("calibrated error = %.3f, want well under the raw error %.3f", calibratedError, rawError)
+	}
+
+	// A middling score is pulled down towards the ~25% of such suggestions agents used
+	if got := calibration.Apply(0.5); got < 0.15 || got > 0.35 {
+		t.Errorf("Apply(0.5) = %.3f, want about 0.25", got)
+	}
+	if calibration.Apply(0.9) <= calibration.Apply(0.5) {
+		t.Error("calibration should keep higher scores higher")
+	}
+}
+
+func TestFitConfidenceCalibrationNeedsEnoughMixedFeedback(t *testing.T) {
+	allAccepted := make([]postgres.FeedbackConfidenceSample, 100)
+	for i := range allAccepted {
+		allAccepted[i] = postgres.FeedbackConfidenceSample{Confidence: 0.6, Accepted: true}
+	}
+	for name, samples := range map[string][]postgres.FeedbackConfidenceSample{
+		"no feedback":      nil,
+		"too few":          overconfidentFeedback(minConfidenceCalibrationSamples - 1),
+		"only one outcome": allAccepted,
+	} {
+		calibration := FitConfidenceCalibration(samples)
+		if calibration.Calibrated {
+			t.Errorf("%s: calibration = %+v, want it unused", name, calibration)
+		}
+		if got := calibration.Apply(0.7); got != 0.7 {
+			t.Errorf("%s: Apply(0.7) = %v, want the score unchanged", name, got)
+		}
+	}
+}
+
+func TestConfidenceScorerAppliesTenantCalibration(t *testing.T) {
+	source := &fakeConfidenceFeedback{samples: overconfidentFeedback(1000)}
+	scorer := NewConfidenceScorer()
+	scorer.SetCalibrator(NewConfidenceCalibrator(source, cache.NewMemoryCache(10)))
+
+	inputs := ConfidenceInputs{
+		Analysis:       &models.ConversationMetadata{Sentiment: "neutral", Intent: "inquiry"},
+		ContextScores:  []float64{0.5},
+		RuleResults:    []bool{true},
+		SelfEvaluation: 0.5,
+	}
+	raw := scorer.RawConfidence(inputs)
+	if got := scorer.CalculateConfidence(inputs); got != raw {
+		t.Errorf("confidence without tenant = %v, want the raw %v", got, raw)
+	}
+
+	inputs.TenantID = "tenant-1"
+	calibrated := scorer.CalculateConfidence(inputs)
+	if want := raw * raw; math.Abs(calibrated-want) > 0.1 {
+		t.Errorf("calibrated confidence = %.3f for raw %.3f, want close to the observed %.3f", calibrated, raw, want)
+	}
+
+	// The curve is cached rather than refitted per suggestion
+	scorer.CalculateConfidence(inputs)
+	if source.calls != 1 {
+		t.Errorf("feedback loaded %d times, want once", source.calls)
+	}
+
+	// Failing to load feedback leaves confidence uncalibrated
+	failing := NewConfidenceScorer()
+	failing.SetCalibrator(NewConfidenceCalibrator(&fakeConfidenceFeedback{err: errors.New("db down")}, nil))
+	if got := failing.CalculateConfidence(inputs); got != raw {
+		t.Errorf("confidence when feedback fails = %v, want the raw %v", got, raw)
+	}
+}
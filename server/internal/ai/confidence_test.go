@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"math"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestCalculateConfidenceBlendsAcceptanceRate(t *testing.T) {
+	inputs := ConfidenceInputs{
+		Analysis:       &models.ConversationMetadata{Sentiment: "positive", Intent: "buying"},
+		ContextScores:  []float64{0.8},
+		RuleResults:    []bool{true},
+		SelfEvaluation: 0.9,
+	}
+	scorer := NewConfidenceScorer()
+	base := scorer.CalculateConfidence(inputs) // 0.8*0.4 + 1*0.3 + 1*0.2 + 0.9*0.1 = 0.91
+
+	// Without a weight, a known acceptance rate doesn't change the score
+	rate := 0.2
+	inputs.AcceptanceRate = &rate
+	if got := scorer.CalculateConfidence(inputs); math.Abs(got-base) > 1e-9 {
+		t.Errorf("confidence without weight = %v, want %v", got, base)
+	}
+
+	scorer.SetAcceptanceWeight(0.5)
+	if got, want := scorer.CalculateConfidence(inputs), base*0.5+rate*0.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("confidence = %v, want %v", got, want)
+	}
+
+	// An unknown acceptance rate is ignored
+	inputs.AcceptanceRate = nil
+	if got := scorer.CalculateConfidence(inputs); math.Abs(got-base) > 1e-9 {
+		t.Errorf("confidence without acceptance rate = %v, want %v", got, base)
+	}
+}
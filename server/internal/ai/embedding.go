@@ -3,6 +3,9 @@ package ai
 import (
 	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"ai-conversation-platform/internal/storage/chroma"
 )
@@ -14,20 +17,91 @@ const (
 	ContentTypeProductKnowledge ContentType = "product_knowledge"
 	ContentTypeConversationSummary ContentType = "conversation_summary"
 	ContentTypeCustomerPreference ContentType = "customer_preference"
+	// Closed conversation transcripts, searched for similar past conversations
+	ContentTypeConversationTranscript ContentType = chroma.ConversationContextCollection
+	// Individual conversation messages, indexed for semantic message search
+	ContentTypeMessage ContentType = chroma.MessageIndexCollection
 )
 
+// embeddingBatchSize is how many texts BatchEmbed sends per Gemini request
+const embeddingBatchSize = 20
+
+// DefaultBatchDelay is the pause between BatchEmbed requests, so bulk imports don't flood the
+// embedding API
+const DefaultBatchDelay = time.Second
+
+// BatchEmbedder generates embeddings for several texts in one request
+type BatchEmbedder interface {
+	GenerateEmbeddingBatch(texts []string) ([][]float64, error)
+}
+
+// DocumentStore stores embedded documents in a vector collection
+type DocumentStore interface {
+	AddDocuments(collection string, req chroma.AddDocumentsRequest) error
+}
+
+// BatchEmbedError reports the texts BatchEmbed failed to embed or store. The other texts were stored.
+type BatchEmbedError struct {
+	Failed []int // Indexes into the texts passed to BatchEmbed
+	Err    error // Error of the last failed batch
+}
+
+func (e *BatchEmbedError) Error() string {
+	return fmt.Sprintf("failed to embed %d texts: %v", len(e.Failed), e.Err)
+}
+
+func (e *BatchEmbedError) Unwrap() error {
+	return e.Err
+}
+
 // EmbeddingService handles selective embedding strategy
 type EmbeddingService struct {
-	geminiClient *Client
-	chromaClient *chroma.Client
+	geminiClient       *Client
+	chromaClient       *chroma.Client
+	conversationLoader ConversationLoader
+	batchEmbedder      BatchEmbedder
+	documentStore      DocumentStore
+	batchDelay         time.Duration
 }
 
 // NewEmbeddingService creates a new embedding service
 func NewEmbeddingService(geminiClient *Client, chromaClient *chroma.Client) *EmbeddingService {
-	return &EmbeddingService{
+	s := &EmbeddingService{
 		geminiClient: geminiClient,
 		chromaClient: chromaClient,
+		batchDelay:   DefaultBatchDelay,
+	}
+	if geminiClient != nil {
+		s.batchEmbedder = geminiClient
+	}
+	if chromaClient != nil {
+		s.documentStore = chromaClient
+	}
+	return s
+}
+
+// BatchDelayFromEnv reads EMBEDDING_BATCH_DELAY_MS, the pause between BatchEmbed requests
+// (default 1000; 0 disables the pause)
+func BatchDelayFromEnv() time.Duration {
+	if v := os.Getenv("EMBEDDING_BATCH_DELAY_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms >= 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
 	}
+	return DefaultBatchDelay
+}
+
+// SetBatchDelay sets the pause between BatchEmbed requests; 0 sends them back to back
+func (s *EmbeddingService) SetBatchDelay(delay time.Duration) {
+	s.batchDelay = delay
+}
+
+// EmbeddingModel returns the model embeddings are generated with
+func (s *EmbeddingService) EmbeddingModel() string {
+	if s.geminiClient == nil {
+		return DefaultEmbeddingModel
+	}
+	return s.geminiClient.EmbeddingModel()
 }
 
 // ShouldEmbed determines if content should be embedded
@@ -43,8 +117,12 @@ func (s *EmbeddingService) ShouldEmbed(content string, contentType ContentType)
 		return true // Always embed summaries
 	case ContentTypeCustomerPreference:
 		return true // Always embed when preferences updated
+	case ContentTypeConversationTranscript:
+		return true // Closed transcripts are embedded once, in chunks
+	case ContentTypeMessage:
+		return true // Messages are embedded one by one into the search index
 	default:
-		return false // Don't embed raw messages
+		return false // Unknown content types aren't embedded
 	}
 }
 
@@ -102,3 +180,149 @@ func (s *EmbeddingService) EmbedAndStore(collection string, text string, content
 }
 
 
+
+// EmbedChunked embeds and stores each product chunk as a separate document
+// in the product knowledge collection
+func (s *EmbeddingService) EmbedChunked(chunks []ProductChunk) error {
+	collection := string(ContentTypeProductKnowledge)
+	for _, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(chunk.Metadata)+1)
+		for k, v := range chunk.Metadata {
+			metadata[k] = v
+		}
+		metadata["section_type"] = chunk.SectionType
+
+		if err := s.EmbedAndStore(collection, chunk.Text, ContentTypeProductKnowledge, metadata); err != nil {
+			return fmt.Errorf("failed to embed %s chunk: %w", chunk.SectionType, err)
+		}
+	}
+
+	return nil
+}
+
+// BatchEmbed embeds texts in Gemini batch requests of 20, pausing for the batch delay between
+// requests, and stores each batch in collection. A failed batch doesn't stop the others; the texts
+// that weren't stored are reported in a *BatchEmbedError. Metadata "id" values become document IDs.
+func (s *EmbeddingService) BatchEmbed(texts []string, metadatas []map[string]interface{}, collection string) error {
+	if len(texts) != len(metadatas) {
+		return fmt.Errorf("got %d metadatas for %d texts", len(metadatas), len(texts))
+	}
+	if s.batchEmbedder == nil || s.documentStore == nil {
+		return fmt.Errorf("batch embedding requires gemini and chroma")
+	}
+
+	var failed []int
+	var lastErr error
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		if start > 0 && s.batchDelay > 0 {
+			time.Sleep(s.batchDelay)
+		}
+		end := min(start+embeddingBatchSize, len(texts))
+		if err := s.embedBatch(texts[start:end], metadatas[start:end], collection); err != nil {
+			log.Printf("[Embedding] batch failed collection=%s start=%d size=%d error=%v", collection, start, end-start, err)
+			for i := start; i < end; i++ {
+				failed = append(failed, i)
+			}
+			lastErr = err
+		}
+	}
+
+	log.Printf("[Embedding] batch embed done collection=%s texts=%d failed=%d", collection, len(texts), len(failed))
+	if len(failed) > 0 {
+		return &BatchEmbedError{Failed: failed, Err: lastErr}
+	}
+	return nil
+}
+
+// embedBatch embeds and stores one batch of texts
+func (s *EmbeddingService) embedBatch(texts []string, metadatas []map[string]interface{}, collection string) error {
+	embeddings, err := s.batchEmbedder.GenerateEmbeddingBatch(texts)
+	if err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+
+	ids := make([]string, len(texts))
+	for i, text := range texts {
+		ids[i] = fmt.Sprintf("%s_%d", collection, len(text))
+		if idFromMeta, ok := metadatas[i]["id"].(string); ok && idFromMeta != "" {
+			ids[i] = idFromMeta
+		}
+	}
+
+	req := chroma.AddDocumentsRequest{
+		Documents:  texts,
+		Embeddings: embeddings,
+		Metadatas:  metadatas,
+		IDs:        ids,
+	}
+	if err := s.documentStore.AddDocuments(collection, req); err != nil {
+		return fmt.Errorf("failed to store embeddings: %w", err)
+	}
+	return nil
+}
+
+// EmbedChunkedBatch is EmbedChunked using BatchEmbed, for embedding many products at once. A
+// *BatchEmbedError's indexes refer to chunks.
+func (s *EmbeddingService) EmbedChunkedBatch(chunks []ProductChunk) error {
+	texts := make([]string, len(chunks))
+	metadatas := make([]map[string]interface{}, len(chunks))
+	for i, chunk := range chunks {
+		metadata := make(map[string]interface{}, len(chunk.Metadata)+2)
+		for k, v := range chunk.Metadata {
+			metadata[k] = v
+		}
+		metadata["section_type"] = chunk.SectionType
+		metadata["content_type"] = string(ContentTypeProductKnowledge)
+		texts[i] = chunk.Text
+		metadatas[i] = metadata
+	}
+	return s.BatchEmbed(texts, metadatas, string(ContentTypeProductKnowledge))
+}
+
+// DeleteProductEmbeddings removes all known chunk documents for a product
+func (s *EmbeddingService) DeleteProductEmbeddings(productID string) error {
+	collection := string(ContentTypeProductKnowledge)
+	if err := s.chromaClient.Delete(collection, ProductChunkIDs(productID)); err != nil {
+		return fmt.Errorf("failed to delete product embeddings: %w", err)
+	}
+	return nil
+}
+
+// vectorPageSize is how many documents are fetched per Chroma get request
+const vectorPageSize = 500
+
+// productDocuments fetches the IDs and metadata of all product knowledge documents matching where
+func (s *EmbeddingService) productDocuments(where map[string]interface{}) (*chroma.GetResponse, error) {
+	collection := string(ContentTypeProductKnowledge)
+	docs := &chroma.GetResponse{}
+	for offset := 0; ; offset += vectorPageSize {
+		page, err := s.chromaClient.GetDocumentsByMetadata(collection, where, vectorPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		docs.IDs = append(docs.IDs, page.IDs...)
+		docs.Metadatas = append(docs.Metadatas, page.Metadatas...)
+		if len(page.IDs) < vectorPageSize {
+			return docs, nil
+		}
+	}
+}
+
+// DeleteAllChunksForProduct removes every Chroma document stored for a product, including
+// chunks from indexed knowledge articles, by filtering on tenant and product metadata
+func (s *EmbeddingService) DeleteAllChunksForProduct(tenantID, productID string) error {
+	docs, err := s.productDocuments(map[string]interface{}{"tenant_id": tenantID, "product_id": productID})
+	if err != nil {
+		return fmt.Errorf("failed to find product chunks: %w", err)
+	}
+	ids := docs.IDs
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.chromaClient.Delete(string(ContentTypeProductKnowledge), ids); err != nil {
+		return fmt.Errorf("failed to delete product chunks: %w", err)
+	}
+	log.Printf("[Embedding] deleted product chunks tenant=%s product=%s count=%d", tenantID, productID, len(ids))
+	return nil
+}
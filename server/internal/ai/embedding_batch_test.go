@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// fakeBatchEmbedder returns one-value embeddings and fails the batches listed in failCalls
+type fakeBatchEmbedder struct {
+	batchSizes []int
+	failCalls  map[int]bool
+}
+
+func (f *fakeBatchEmbedder) GenerateEmbeddingBatch(texts []string) ([][]float64, error) {
+	call := len(f.batchSizes)
+	f.batchSizes = append(f.batchSizes, len(texts))
+	if f.failCalls[call] {
+		return nil, errors.New("gemini API error: status 503")
+	}
+	embeddings := make([][]float64, len(texts))
+	for i := range texts {
+		embeddings[i] = []float64{float64(len(texts[i]))}
+	}
+	return embeddings, nil
+}
+
+type fakeDocumentStore struct {
+	ids []string
+}
+
+func (f *fakeDocumentStore) AddDocuments(collection string, req chroma.AddDocumentsRequest) error {
+	if len(req.Documents) != len(req.Embeddings) || len(req.Documents) != len(req.IDs) {
+		return fmt.Errorf("mismatched batch: %d documents, %d embeddings, %d ids", len(req.Documents), len(req.Embeddings), len(req.IDs))
+	}
+	f.ids = append(f.ids, req.IDs...)
+	return nil
+}
+
+func batchTexts(n int) ([]string, []map[string]interface{}) {
+	texts := make([]string, n)
+	metadatas := make([]map[string]interface{}, n)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+		metadatas[i] = map[string]interface{}{"id": fmt.Sprintf("doc-%d", i)}
+	}
+	return texts, metadatas
+}
+
+func TestBatchEmbedChunksRequests(t *testing.T) {
+	embedder := &fakeBatchEmbedder{}
+	store := &fakeDocumentStore{}
+	service := &EmbeddingService{batchEmbedder: embedder, documentStore: store}
+
+	texts, metadatas := batchTexts(45)
+	if err := service.BatchEmbed(texts, metadatas, "product_knowledge"); err != nil {
+		t.Fatalf("BatchEmbed: %v", err)
+	}
+
+	if want := []int{20, 20, 5}; !reflect.DeepEqual(embedder.batchSizes, want) {
+		t.Errorf("batch sizes = %v, want %v", embedder.batchSizes, want)
+	}
+	if len(store.ids) != 45 || store.ids[0] != "doc-0" || store.ids[44] != "doc-44" {
+		t.Errorf("stored %d documents (%v...), want doc-0 to doc-44", len(store.ids), store.ids[:1])
+	}
+}
+
+func TestBatchEmbedReportsFailedBatches(t *testing.T) {
+	embedder := &fakeBatchEmbedder{failCalls: map[int]bool{1: true}}
+	store := &fakeDocumentStore{}
+	service := &EmbeddingService{batchEmbedder: embedder, documentStore: store}
+
+	texts, metadatas := batchTexts(45)
+	err := service.BatchEmbed(texts, metadatas, "product_knowledge")
+
+	var batchErr *BatchEmbedError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("BatchEmbed error = %v, want *BatchEmbedError", err)
+	}
+	if len(batchErr.Failed) != 20 || batchErr.Failed[0] != 20 || batchErr.Failed[19] != 39 {
+		t.Errorf("failed indexes = %v, want 20 to 39", batchErr.Failed)
+	}
+	// The batches around the failed one are still stored
+	if len(embedder.batchSizes) != 3 || len(store.ids) != 25 {
+		t.Errorf("calls = %d stored = %d, want 3 calls and 25 stored documents", len(embedder.batchSizes), len(store.ids))
+	}
+}
+
+func TestBatchEmbedWaitsBetweenBatches(t *testing.T) {
+	service := &EmbeddingService{batchEmbedder: &fakeBatchEmbedder{}, documentStore: &fakeDocumentStore{}}
+	service.SetBatchDelay(30 * time.Millisecond)
+
+	texts, metadatas := batchTexts(41) // 3 batches, so 2 pauses
+	start := time.Now()
+	if err := service.BatchEmbed(texts, metadatas, "product_knowledge"); err != nil {
+		t.Fatalf("BatchEmbed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("BatchEmbed took %v, want at least two 30ms delays", elapsed)
+	}
+}
+
+func TestBatchEmbedValidatesInput(t *testing.T) {
+	service := &EmbeddingService{batchEmbedder: &fakeBatchEmbedder{}, documentStore: &fakeDocumentStore{}}
+	if err := service.BatchEmbed([]string{"a", "b"}, []map[string]interface{}{{}}, "product_knowledge"); err == nil {
+		t.Error("expected an error for mismatched texts and metadatas")
+	}
+
+	unconfigured := NewEmbeddingService(nil, nil)
+	if err := unconfigured.BatchEmbed([]string{"a"}, []map[string]interface{}{{}}, "product_knowledge"); err == nil {
+		t.Error("expected an error without gemini and chroma")
+	}
+}
+
+func TestGenerateEmbeddingBatchRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":batchEmbedContents") {
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		var body struct {
+			Requests []struct {
+				Content struct {
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"content"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var embeddings []map[string]interface{}
+		for _, req := range body.Requests {
+			embeddings = append(embeddings, map[string]interface{}{"values": []float64{float64(len(req.Content.Parts[0].Text))}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"embeddings": embeddings})
+	}))
+	defer server.Close()
+
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = server.URL
+	embeddings, err := client.GenerateEmbeddingBatch([]string{"a", "abc"})
+	if err != nil {
+		t.Fatalf("GenerateEmbeddingBatch: %v", err)
+	}
+	if want := [][]float64{{1}, {3}}; !reflect.DeepEqual(embeddings, want) {
+		t.Errorf("embeddings = %v, want %v (in text order)", embeddings, want)
+	}
+}
@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// defaultEmbeddingQueueSize is the number of pending embedding jobs buffered before Enqueue fails
+const defaultEmbeddingQueueSize = 100
+
+// EmbeddingJob is a batch of chunks waiting to be embedded
+type EmbeddingJob struct {
+	Label  string // Identifies the source in logs, e.g. "article:<id>"
+	Chunks []ProductChunk
+
+	embed func() error // Replaces embedding Chunks when set (transcript jobs)
+}
+
+// MessageSource loads a conversation's messages (to keep the queue decoupled from storage)
+type MessageSource interface {
+	GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error)
+}
+
+// EmbeddingQueue embeds chunks in the background so slow embedding calls
+// don't hold up API requests
+type EmbeddingQueue struct {
+	service  *EmbeddingService
+	messages MessageSource
+	jobs     chan EmbeddingJob
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewEmbeddingQueue creates an embedding queue backed by the embedding service
+func NewEmbeddingQueue(service *EmbeddingService) *EmbeddingQueue {
+	return &EmbeddingQueue{
+		service: service,
+		jobs:    make(chan EmbeddingJob, defaultEmbeddingQueueSize),
+		stop:    make(chan struct{}),
+	}
+}
+
+// SetMessageSource enables embedding transcripts of closed conversations (optional)
+func (q *EmbeddingQueue) SetMessageSource(messages MessageSource) {
+	q.messages = messages
+}
+
+// OnConversationClosed queues the conversation's transcript for embedding. Messages are loaded
+// when the job runs.
+func (q *EmbeddingQueue) OnConversationClosed(tenantID, conversationID string) {
+	if q.messages == nil {
+		return
+	}
+	job := EmbeddingJob{
+		Label: "transcript:" + conversationID,
+		embed: func() error {
+			messages, err := q.messages.GetMessagesByConversation(tenantID, conversationID)
+			if err != nil {
+				return fmt.Errorf("failed to load messages: %w", err)
+			}
+			return q.service.EmbedConversationTranscript(tenantID, conversationID, messages)
+		},
+	}
+	if err := q.Enqueue(job); err != nil {
+		log.Printf("[Embedding] transcript not queued tenant=%s conversation=%s error=%v", tenantID, conversationID, err)
+	}
+}
+
+// OnMessageStored queues a new message for the semantic search index
+func (q *EmbeddingQueue) OnMessageStored(tenantID string, message *models.Message) {
+	job := EmbeddingJob{
+		Label: "message:" + message.ID,
+		embed: func() error { return q.service.EmbedMessage(tenantID, message) },
+	}
+	if err := q.Enqueue(job); err != nil {
+		log.Printf("[Embedding] message not queued tenant=%s message=%s error=%v", tenantID, message.ID, err)
+	}
+}
+
+// OnMessageDeleted queues removal of a deleted message from the semantic search index
+func (q *EmbeddingQueue) OnMessageDeleted(tenantID, messageID string) {
+	job := EmbeddingJob{
+		Label: "message-delete:" + messageID,
+		embed: func() error { return q.service.DeleteMessageEmbedding(messageID) },
+	}
+	if err := q.Enqueue(job); err != nil {
+		log.Printf("[Embedding] message deletion not queued tenant=%s message=%s error=%v", tenantID, messageID, err)
+	}
+}
+
+// Enqueue queues a job for embedding. Returns an error if the queue is full.
+func (q *EmbeddingQueue) Enqueue(job EmbeddingJob) error {
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return fmt.Errorf("embedding queue is full")
+	}
+}
+
+// Start runs the queue worker in the background until Stop is called
+func (q *EmbeddingQueue) Start() {
+	go func() {
+		for {
+			select {
+			case job := <-q.jobs:
+				q.run(job)
+			case <-q.stop:
+				return
+			}
+		}
+	}()
+}
+
+// run embeds one job, logging failures
+func (q *EmbeddingQueue) run(job EmbeddingJob) {
+	if job.embed != nil {
+		if err := job.embed(); err != nil {
+			log.Printf("[Embedding] job failed label=%s error=%v", job.Label, err)
+			return
+		}
+		log.Printf("[Embedding] job completed label=%s", job.Label)
+		return
+	}
+
+	if err := q.service.EmbedChunked(job.Chunks); err != nil {
+		log.Printf("[Embedding] job failed label=%s error=%v", job.Label, err)
+		return
+	}
+	log.Printf("[Embedding] job completed label=%s chunks=%d", job.Label, len(job.Chunks))
+}
+
+// Stop stops the queue worker. Pending jobs are dropped.
+func (q *EmbeddingQueue) Stop() {
+	q.stopOnce.Do(func() { close(q.stop) })
+}
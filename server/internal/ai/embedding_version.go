@@ -0,0 +1,256 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// ErrReindexRunning is returned by Reindex while another re-index is in progress
+var ErrReindexRunning = errors.New("embedding re-index already running")
+
+// EmbeddingVersionStore records the embedding model each collection is indexed with (see
+// postgres.EmbeddingVersionStorage)
+type EmbeddingVersionStore interface {
+	GetActiveVersion(collection string) (*postgres.EmbeddingVersion, error)
+	ActivateVersion(collection, model string, dimension int) (*postgres.EmbeddingVersion, error)
+}
+
+// ReindexProductSource lists the products to re-embed (see postgres.ProductStorage)
+type ReindexProductSource interface {
+	ListProductTenants() ([]string, error)
+	ListProducts(tenantID string) ([]*models.Product, error)
+}
+
+// ReindexVariantSource lists product variants, which are part of a product's chunks (see
+// postgres.ProductVariantStorage)
+type ReindexVariantSource interface {
+	ListVariants(tenantID, productID string, activeOnly bool) ([]models.ProductVariant, error)
+}
+
+// ProductIndexer embeds products into the product knowledge collection (see EmbeddingService)
+type ProductIndexer interface {
+	EmbeddingModel() string
+	GenerateEmbedding(text string) ([]float64, error)
+	DeleteProductEmbeddings(productID string) error
+	EmbedChunkedBatch(chunks []ProductChunk) error
+}
+
+// EmbeddingStatus reports which model the product knowledge collection is indexed with
+type EmbeddingStatus struct {
+	Collection    string     `json:"collection"`
+	CurrentModel  string     `json:"current_model"` // Model new embeddings and queries use
+	IndexedModel  string     `json:"indexed_model"` // Model the stored vectors came from; empty if not recorded yet
+	Dimension     int        `json:"dimension"`
+	LastIndexedAt *time.Time `json:"last_indexed_at"`
+	Reindexing    bool       `json:"reindexing"`
+	PendingCount  int        `json:"pending_count"` // Products the running or last failed re-index hasn't embedded
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// EmbeddingReindexer re-embeds every product when the embedding model changes. Vectors from
+// different models aren't comparable, so until the re-index finishes product retrieval scores are
+// unreliable.
+type EmbeddingReindexer struct {
+	indexer  ProductIndexer
+	versions EmbeddingVersionStore
+	products ReindexProductSource
+	variants ReindexVariantSource
+	chunker  *ProductChunker
+
+	mu      sync.Mutex
+	running bool
+	pending int
+	lastErr string
+	wg      sync.WaitGroup // Tracks the background re-index
+}
+
+// NewEmbeddingReindexer creates a new embedding re-indexer
+func NewEmbeddingReindexer(indexer ProductIndexer, versions EmbeddingVersionStore, products ReindexProductSource, variants ReindexVariantSource) *EmbeddingReindexer {
+	return &EmbeddingReindexer{
+		indexer:  indexer,
+		versions: versions,
+		products: products,
+		variants: variants,
+		chunker:  NewProductChunker(),
+	}
+}
+
+// CheckVersion compares the current embedding model with the one the product knowledge collection
+// was indexed with and starts a background re-index if they differ. It reports whether a re-index
+// was started.
+func (r *EmbeddingReindexer) CheckVersion() (bool, error) {
+	collection := string(ContentTypeProductKnowledge)
+	active, err := r.versions.GetActiveVersion(collection)
+	if err != nil {
+		return false, err
+	}
+	model := r.indexer.EmbeddingModel()
+
+	if active == nil && model == DefaultEmbeddingModel {
+		// Vectors stored before versions were recorded came from the default model
+		dimension, err := r.probeDimension()
+		if err != nil {
+			return false, err
+		}
+		if _, err := r.versions.ActivateVersion(collection, model, dimension); err != nil {
+			return false, err
+		}
+		log.Printf("[Embedding] recorded embedding version collection=%s model=%s dimension=%d", collection, model, dimension)
+		return false, nil
+	}
+	if active != nil && active.ModelName == model {
+		return false, nil
+	}
+
+	indexed := ""
+	if active != nil {
+		indexed = active.ModelName
+	}
+	log.Printf("[Embedding] embedding model changed collection=%s indexed=%q current=%s, re-indexing products", collection, indexed, model)
+	return r.StartReindex(), nil
+}
+
+// StartReindex re-embeds every product in the background. It returns false if a re-index is
+// already running.
+func (r *EmbeddingReindexer) StartReindex() bool {
+	if !r.begin() {
+		return false
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.run(); err != nil {
+			log.Printf("[Embedding] re-index failed: %v", err)
+		}
+	}()
+	return true
+}
+
+// Reindex re-embeds every product with the current model, then records the model as the
+// collection's active version
+func (r *EmbeddingReindexer) Reindex() error {
+	if !r.begin() {
+		return ErrReindexRunning
+	}
+	return r.run()
+}
+
+// Status reports the current and indexed models and the progress of a running re-index
+func (r *EmbeddingReindexer) Status() (*EmbeddingStatus, error) {
+	collection := string(ContentTypeProductKnowledge)
+	active, err := r.versions.GetActiveVersion(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &EmbeddingStatus{Collection: collection, CurrentModel: r.indexer.EmbeddingModel()}
+	if active != nil {
+		status.IndexedModel = active.ModelName
+		status.Dimension = active.Dimension
+		indexedAt := active.CreatedAt
+		status.LastIndexedAt = &indexedAt
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status.Reindexing = r.running
+	status.PendingCount = r.pending
+	status.LastError = r.lastErr
+	return status, nil
+}
+
+// begin marks a re-index as running, or returns false if one already is
+func (r *EmbeddingReindexer) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return false
+	}
+	r.running = true
+	r.lastErr = ""
+	return true
+}
+
+// run re-embeds the products tenant by tenant. Products already re-embedded when it fails keep
+// their new vectors; the version isn't recorded, so the next start re-indexes again.
+func (r *EmbeddingReindexer) run() (err error) {
+	defer func() {
+		r.mu.Lock()
+		r.running = false
+		if err != nil {
+			r.lastErr = err.Error()
+		}
+		r.mu.Unlock()
+	}()
+
+	collection := string(ContentTypeProductKnowledge)
+	model := r.indexer.EmbeddingModel()
+
+	// Probe the model before deleting anything, so a misconfigured model leaves the old vectors in place
+	dimension, err := r.probeDimension()
+	if err != nil {
+		return err
+	}
+
+	tenantIDs, err := r.products.ListProductTenants()
+	if err != nil {
+		return err
+	}
+	productsByTenant := make(map[string][]*models.Product, len(tenantIDs))
+	total := 0
+	for _, tenantID := range tenantIDs {
+		products, err := r.products.ListProducts(tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to list products for tenant %s: %w", tenantID, err)
+		}
+		productsByTenant[tenantID] = products
+		total += len(products)
+	}
+	r.setPending(total)
+
+	for _, tenantID := range tenantIDs {
+		products := productsByTenant[tenantID]
+		var chunks []ProductChunk
+		for _, product := range products {
+			if err := r.indexer.DeleteProductEmbeddings(product.ID); err != nil {
+				log.Printf("[Embedding] failed to delete embeddings for product %s: %v", product.ID, err)
+			}
+			if product.Variants, err = r.variants.ListVariants(tenantID, product.ID, false); err != nil {
+				return fmt.Errorf("failed to list variants for product %s: %w", product.ID, err)
+			}
+			chunks = append(chunks, r.chunker.Chunk(product)...)
+		}
+		if err := r.indexer.EmbedChunkedBatch(chunks); err != nil {
+			return fmt.Errorf("failed to embed products for tenant %s: %w", tenantID, err)
+		}
+		total -= len(products)
+		r.setPending(total)
+	}
+
+	if _, err := r.versions.ActivateVersion(collection, model, dimension); err != nil {
+		return err
+	}
+	log.Printf("[Embedding] re-indexed products collection=%s model=%s dimension=%d tenants=%d", collection, model, dimension, len(tenantIDs))
+	return nil
+}
+
+// probeDimension embeds a short text to learn the model's vector size
+func (r *EmbeddingReindexer) probeDimension() (int, error) {
+	embedding, err := r.indexer.GenerateEmbedding("embedding dimension probe")
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe embedding model %s: %w", r.indexer.EmbeddingModel(), err)
+	}
+	return len(embedding), nil
+}
+
+func (r *EmbeddingReindexer) setPending(pending int) {
+	r.mu.Lock()
+	r.pending = pending
+	r.mu.Unlock()
+}
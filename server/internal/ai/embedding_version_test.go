@@ -0,0 +1,179 @@
+package ai
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeProductIndexer records the products it was asked to re-embed
+type fakeProductIndexer struct {
+	model     string
+	probeErr  error
+	mu        sync.Mutex
+	deleted   []string
+	chunkRuns int
+	embedded  []string // product_id of every embedded chunk
+}
+
+func (f *fakeProductIndexer) EmbeddingModel() string { return f.model }
+
+func (f *fakeProductIndexer) GenerateEmbedding(text string) ([]float64, error) {
+	if f.probeErr != nil {
+		return nil, f.probeErr
+	}
+	return make([]float64, 768), nil
+}
+
+func (f *fakeProductIndexer) DeleteProductEmbeddings(productID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, productID)
+	return nil
+}
+
+func (f *fakeProductIndexer) EmbedChunkedBatch(chunks []ProductChunk) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chunkRuns++
+	for _, chunk := range chunks {
+		if productID, _ := chunk.Metadata["product_id"].(string); len(f.embedded) == 0 || f.embedded[len(f.embedded)-1] != productID {
+			f.embedded = append(f.embedded, productID)
+		}
+	}
+	return nil
+}
+
+// fakeVersionStore keeps embedding versions in memory
+type fakeVersionStore struct {
+	active    *postgres.EmbeddingVersion
+	activated []string // model of every ActivateVersion call
+}
+
+func (f *fakeVersionStore) GetActiveVersion(collection string) (*postgres.EmbeddingVersion, error) {
+	return f.active, nil
+}
+
+func (f *fakeVersionStore) ActivateVersion(collection, model string, dimension int) (*postgres.EmbeddingVersion, error) {
+	f.active = &postgres.EmbeddingVersion{CollectionName: collection, ModelName: model, Dimension: dimension, CreatedAt: time.Now(), IsActive: true}
+	f.activated = append(f.activated, model)
+	return f.active, nil
+}
+
+// fakeReindexProducts serves products and variants for the re-index
+type fakeReindexProducts map[string][]*models.Product
+
+func (f fakeReindexProducts) ListProductTenants() ([]string, error) {
+	return []string{"tenant-a", "tenant-b"}, nil
+}
+
+func (f fakeReindexProducts) ListProducts(tenantID string) ([]*models.Product, error) {
+	return f[tenantID], nil
+}
+
+func (f fakeReindexProducts) ListVariants(tenantID, productID string, activeOnly bool) ([]models.ProductVariant, error) {
+	return nil, nil
+}
+
+var reindexProducts = fakeReindexProducts{
+	"tenant-a": {{ID: "prod-1", TenantID: "tenant-a", Name: "CRM Pro", Description: "Sales CRM"}},
+	"tenant-b": {
+		{ID: "prod-2", TenantID: "tenant-b", Name: "Helpdesk", Description: "Support tickets"},
+		{ID: "prod-3", TenantID: "tenant-b", Name: "Chat Widget", Description: "Live chat"},
+	},
+}
+
+func TestCheckVersionReindexesOnModelChange(t *testing.T) {
+	indexer := &fakeProductIndexer{model: "text-embedding-004"}
+	versions := &fakeVersionStore{active: &postgres.EmbeddingVersion{ModelName: DefaultEmbeddingModel, Dimension: 768, IsActive: true}}
+	reindexer := NewEmbeddingReindexer(indexer, versions, reindexProducts, reindexProducts)
+
+	started, err := reindexer.CheckVersion()
+	if err != nil {
+		t.Fatalf("CheckVersion: %v", err)
+	}
+	if !started {
+		t.Fatal("CheckVersion didn't start a re-index after the model changed")
+	}
+	reindexer.wg.Wait()
+
+	if want := []string{"prod-1", "prod-2", "prod-3"}; !reflect.DeepEqual(indexer.deleted, want) || !reflect.DeepEqual(indexer.embedded, want) {
+		t.Errorf("deleted %v and embedded %v, want both %v", indexer.deleted, indexer.embedded, want)
+	}
+	if indexer.chunkRuns != 2 {
+		t.Errorf("embedded %d batches, want one per tenant", indexer.chunkRuns)
+	}
+	if !reflect.DeepEqual(versions.activated, []string{"text-embedding-004"}) {
+		t.Errorf("activated versions %v, want the new model", versions.activated)
+	}
+
+	status, err := reindexer.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Reindexing || status.PendingCount != 0 || status.IndexedModel != "text-embedding-004" || status.Dimension != 768 || status.LastIndexedAt == nil {
+		t.Errorf("status after re-index = %+v", status)
+	}
+}
+
+func TestCheckVersionKeepsMatchingModel(t *testing.T) {
+	indexer := &fakeProductIndexer{model: "text-embedding-004"}
+	versions := &fakeVersionStore{active: &postgres.EmbeddingVersion{ModelName: "text-embedding-004", Dimension: 768, IsActive: true}}
+	reindexer := NewEmbeddingReindexer(indexer, versions, reindexProducts, reindexProducts)
+
+	if started, err := reindexer.CheckVersion(); err != nil || started {
+		t.Fatalf("CheckVersion = %v, %v; want no re-index", started, err)
+	}
+	if len(indexer.deleted) != 0 || len(versions.activated) != 0 {
+		t.Errorf("deleted %v and activated %v, want nothing touched", indexer.deleted, versions.activated)
+	}
+}
+
+func TestCheckVersionRecordsUnversionedDefaultModel(t *testing.T) {
+	indexer := &fakeProductIndexer{model: DefaultEmbeddingModel}
+	versions := &fakeVersionStore{}
+	reindexer := NewEmbeddingReindexer(indexer, versions, reindexProducts, reindexProducts)
+
+	if started, err := reindexer.CheckVersion(); err != nil || started {
+		t.Fatalf("CheckVersion = %v, %v; want the existing vectors recorded without a re-index", started, err)
+	}
+	if versions.active == nil || versions.active.ModelName != DefaultEmbeddingModel || versions.active.Dimension != 768 {
+		t.Errorf("active version = %+v, want %s with dimension 768", versions.active, DefaultEmbeddingModel)
+	}
+	if len(indexer.deleted) != 0 {
+		t.Errorf("deleted %v, want nothing", indexer.deleted)
+	}
+
+	// An unversioned collection with another model is re-indexed
+	other := &fakeProductIndexer{model: "text-embedding-004"}
+	reindexer = NewEmbeddingReindexer(other, &fakeVersionStore{}, reindexProducts, reindexProducts)
+	if started, err := reindexer.CheckVersion(); err != nil || !started {
+		t.Fatalf("CheckVersion = %v, %v; want a re-index", started, err)
+	}
+	reindexer.wg.Wait()
+}
+
+func TestReindexKeepsVectorsWhenModelProbeFails(t *testing.T) {
+	indexer := &fakeProductIndexer{model: "text-embedding-004", probeErr: errors.New("model not found")}
+	versions := &fakeVersionStore{active: &postgres.EmbeddingVersion{ModelName: DefaultEmbeddingModel, IsActive: true}}
+	reindexer := NewEmbeddingReindexer(indexer, versions, reindexProducts, reindexProducts)
+
+	if err := reindexer.Reindex(); err == nil {
+		t.Fatal("Reindex succeeded with a failing model")
+	}
+	if len(indexer.deleted) != 0 || len(versions.activated) != 0 {
+		t.Errorf("deleted %v and activated %v, want the old vectors kept", indexer.deleted, versions.activated)
+	}
+	status, err := reindexer.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Reindexing || status.LastError == "" || status.IndexedModel != DefaultEmbeddingModel {
+		t.Errorf("status after failure = %+v", status)
+	}
+}
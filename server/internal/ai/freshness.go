@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"ai-conversation-platform/internal/models"
+)
+
+const (
+	// shortMessageWordLimit is the word count below which a message may be an acknowledgment
+	shortMessageWordLimit = 10
+	// freshMetadataMaxAge is how long existing analysis stays valid for filler messages
+	freshMetadataMaxAge = 5 * time.Minute
+	// defaultAnalysisMinInterval applies when ANALYSIS_MIN_INTERVAL_SECONDS is unset.
+	// A longer interval extends how long filler messages keep the existing analysis.
+	defaultAnalysisMinInterval = 30 * time.Second
+)
+
+// fillerPhrases are acknowledgments that never change intent or sentiment on their own
+var fillerPhrases = []string{
+	"thank you so much", "thank you very much", "thanks so much", "thanks very much",
+	"no problem", "sounds good", "got it", "thank you", "will do", "all good", "makes sense",
+	"ok", "okay", "k", "kk", "sure", "thanks", "thx", "ty", "cool", "great", "nice", "alright",
+	"yes", "yep", "yeah", "yup", "noted", "perfect", "awesome", "fine", "hi", "hello", "hey",
+	"bye", "cheers", "understood", "right", "lol", "haha", "np",
+}
+
+// ContextFreshnessScorer decides whether a new message warrants re-running AI analysis
+type ContextFreshnessScorer struct {
+	maxAge time.Duration // How long existing analysis stays valid for filler messages
+}
+
+// NewContextFreshnessScorer creates a scorer using ANALYSIS_MIN_INTERVAL_SECONDS (default 30)
+func NewContextFreshnessScorer() *ContextFreshnessScorer {
+	minInterval := defaultAnalysisMinInterval
+	if v := os.Getenv("ANALYSIS_MIN_INTERVAL_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			minInterval = time.Duration(seconds) * time.Second
+		}
+	}
+	maxAge := freshMetadataMaxAge
+	if minInterval > maxAge {
+		maxAge = minInterval
+	}
+	return &ContextFreshnessScorer{maxAge: maxAge}
+}
+
+// NeedsReanalysis reports whether newMessage can change the conversation analysis.
+// Only short filler acknowledgments ("ok", "thanks") are skipped, and only while the existing
+// metadata is under 5 minutes old (or the minimum interval, if longer). Any other message,
+// however short, is analyzed.
+func (s *ContextFreshnessScorer) NeedsReanalysis(newMessage *models.Message, currentMetadata *models.ConversationMetadata, conversationHistory []*models.Message) bool {
+	if newMessage == nil || currentMetadata == nil || currentMetadata.UpdatedAt.IsZero() {
+		return true
+	}
+
+	// The opening message always establishes intent
+	if len(conversationHistory) <= 1 {
+		return true
+	}
+
+	age := time.Since(currentMetadata.UpdatedAt)
+	if age >= s.maxAge {
+		return true
+	}
+
+	content := strings.TrimSpace(newMessage.Content)
+	if len(strings.Fields(content)) >= shortMessageWordLimit || strings.Contains(content, "?") {
+		return true
+	}
+
+	return !IsFillerMessage(content)
+}
+
+// IsFillerMessage reports whether text consists only of acknowledgment phrases
+func IsFillerMessage(text string) bool {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return unicode.ToLower(r)
+		}
+		// Punctuation and emoji are dropped; digits are kept so "ok 500" isn't filler
+		return -1
+	}, text)
+
+	words := strings.Fields(normalized)
+	if len(words) == 0 {
+		// Emoji-only or punctuation-only replies ("👍", "...") carry no new intent
+		return strings.TrimSpace(text) != ""
+	}
+
+	for len(words) > 0 {
+		matched := 0
+		for _, phrase := range fillerPhrases {
+			phraseWords := strings.Fields(phrase)
+			if len(phraseWords) > len(words) {
+				continue
+			}
+			if strings.Join(words[:len(phraseWords)], " ") == phrase {
+				matched = len(phraseWords)
+				break
+			}
+		}
+		if matched == 0 {
+			return false
+		}
+		words = words[matched:]
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func freshnessFixture(age time.Duration) (*models.ConversationMetadata, []*models.Message) {
+	metadata := &models.ConversationMetadata{
+		Intent:    "buying",
+		Sentiment: "positive",
+		UpdatedAt: time.Now().Add(-age),
+	}
+	history := []*models.Message{
+		{Sender: "customer", Content: "Hi, I'm interested in the enterprise plan for my team."},
+		{Sender: "agent", Content: "Great! The enterprise plan includes SSO and priority support."},
+	}
+	return metadata, history
+}
+
+func newTestFreshnessScorer(t *testing.T) *ContextFreshnessScorer {
+	t.Setenv("ANALYSIS_MIN_INTERVAL_SECONDS", "30")
+	return NewContextFreshnessScorer()
+}
+
+func TestNeedsReanalysisSkipsShortAcknowledgments(t *testing.T) {
+	scorer := newTestFreshnessScorer(t)
+	metadata, history := freshnessFixture(time.Minute)
+
+	skipped := []string{
+		"ok",
+		"Ok!",
+		"okay",
+		"sure",
+		"Thanks",
+		"thank you!",
+		"thanks so much",
+		"got it",
+		"cool",
+		"great, thanks",
+		"sounds good",
+		"Perfect.",
+		"yep",
+		"no problem",
+		"👍",
+	}
+
+	for _, content := range skipped {
+		msg := &models.Message{Sender: "customer", Content: content}
+		if scorer.NeedsReanalysis(msg, metadata, append(history, msg)) {
+			t.Errorf("expected %q to skip reanalysis", content)
+		}
+	}
+}
+
+func TestNeedsReanalysisTriggersOnSubstantiveMessages(t *testing.T) {
+	scorer := newTestFreshnessScorer(t)
+	metadata, history := freshnessFixture(time.Minute)
+
+	triggered := []string{
+		"How much does it cost?",
+		"That's too expensive for us",
+		"We're also looking at a competitor",
+		"Can you send over the contract?",
+		"I want to cancel",
+		"Actually we need this deployed by next Friday or the deal is off for this quarter",
+		"ok but what about the price?",
+		"Not interested anymore",
+		"Does it integrate with Salesforce?",
+		"Your support has been terrible",
+	}
+
+	for _, content := range triggered {
+		msg := &models.Message{Sender: "customer", Content: content}
+		if !scorer.NeedsReanalysis(msg, metadata, append(history, msg)) {
+			t.Errorf("expected %q to trigger reanalysis", content)
+		}
+	}
+}
+
+func TestNeedsReanalysisWhenMetadataMissingOrStale(t *testing.T) {
+	scorer := newTestFreshnessScorer(t)
+	msg := &models.Message{Sender: "customer", Content: "thanks"}
+
+	_, history := freshnessFixture(0)
+	if !scorer.NeedsReanalysis(msg, nil, append(history, msg)) {
+		t.Error("expected reanalysis when no metadata exists")
+	}
+
+	stale, history := freshnessFixture(6 * time.Minute)
+	if !scorer.NeedsReanalysis(msg, stale, append(history, msg)) {
+		t.Error("expected reanalysis when metadata is older than 5 minutes")
+	}
+
+	fresh, _ := freshnessFixture(time.Minute)
+	if !scorer.NeedsReanalysis(msg, fresh, []*models.Message{msg}) {
+		t.Error("expected reanalysis for the opening message")
+	}
+}
+
+func TestNeedsReanalysisAnalyzesShortSubstantiveMessagesImmediately(t *testing.T) {
+	scorer := newTestFreshnessScorer(t)
+	recent, history := freshnessFixture(20 * time.Second)
+
+	// Inside the minimum interval, only filler is skipped
+	for _, content := range []string{"I want to cancel my order", "ready to buy now", "Budget is tight", "ok 500", "thanks, cancel it"} {
+		msg := &models.Message{Sender: "customer", Content: content}
+		if !scorer.NeedsReanalysis(msg, recent, append(history, msg)) {
+			t.Errorf("expected %q to trigger reanalysis within the minimum interval", content)
+		}
+	}
+}
+
+func TestNeedsReanalysisMinIntervalExtendsFillerWindow(t *testing.T) {
+	t.Setenv("ANALYSIS_MIN_INTERVAL_SECONDS", "600")
+	scorer := NewContextFreshnessScorer()
+	msg := &models.Message{Sender: "customer", Content: "thanks"}
+
+	metadata, history := freshnessFixture(7 * time.Minute)
+	if scorer.NeedsReanalysis(msg, metadata, append(history, msg)) {
+		t.Error("expected filler to skip reanalysis within a 10 minute interval")
+	}
+	metadata, history = freshnessFixture(11 * time.Minute)
+	if !scorer.NeedsReanalysis(msg, metadata, append(history, msg)) {
+		t.Error("expected filler to trigger reanalysis after the interval")
+	}
+}
+
+func TestIsFillerMessage(t *testing.T) {
+	tests := map[string]bool{
+		"ok":                   true,
+		"Thank you very much!": true,
+		"👍👍":                   true,
+		"ok 500":               false,
+		"you":                  false,
+		"so very much":         false,
+		"sure, 2 seats":        false,
+		"":                     false,
+	}
+	for text, want := range tests {
+		if got := IsFillerMessage(text); got != want {
+			t.Errorf("IsFillerMessage(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
@@ -2,23 +2,39 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-conversation-platform/internal/ai/circuitbreaker"
+	"ai-conversation-platform/internal/metrics"
 )
 
+// DefaultTextModel is the Gemini model used for text generation
+const DefaultTextModel = "gemini-2.5-flash"
+
+// DefaultEmbeddingModel is the Gemini model used for embeddings
+const DefaultEmbeddingModel = "embedding-001"
+
 // Client represents a Google Gemini API client
 type Client struct {
 	apiKey    string
 	baseURL   string
+	model     string
+	embeddingModel string
 	httpClient *http.Client
+	promptCache *PromptCache // nil disables response caching
+	breaker   *circuitbreaker.CircuitBreaker // nil disables the circuit breaker
 }
 
 // NewGeminiClient creates a new Gemini API client
@@ -28,17 +44,76 @@ func NewGeminiClient() (*Client, error) {
 		return nil, fmt.Errorf("GEMINI_API_KEY environment variable is required")
 	}
 
+	return NewGeminiClientWithKey(apiKey), nil
+}
+
+// NewGeminiClientWithKey creates a Gemini API client for an explicit API key
+func NewGeminiClientWithKey(apiKey string) *Client {
 	return &Client{
 		apiKey:     apiKey,
 		baseURL:    "https://generativelanguage.googleapis.com/v1beta",
+		model:      DefaultTextModel,
+		embeddingModel: EmbeddingModelFromEnv(),
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-	}, nil
+		promptCache: defaultPromptCache,
+		breaker:    circuitBreakerFromEnv(),
+	}
+}
+
+// SetPromptCache replaces the response cache; nil disables caching
+func (c *Client) SetPromptCache(cache *PromptCache) {
+	c.promptCache = cache
+}
+
+// SetCircuitBreaker replaces the breaker guarding API calls; nil disables it
+func (c *Client) SetCircuitBreaker(breaker *circuitbreaker.CircuitBreaker) {
+	c.breaker = breaker
+}
+
+// Name returns the provider name
+func (c *Client) Name() string {
+	return ProviderGemini
+}
+
+// SetBaseURL points the client at another API endpoint, e.g. a proxy
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// Model returns the text generation model name
+func (c *Client) Model() string {
+	return c.model
+}
+
+// EmbeddingModel returns the embedding model name
+func (c *Client) EmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// EmbeddingModelFromEnv reads GEMINI_EMBEDDING_MODEL, the embedding model (default embedding-001).
+// Changing it re-indexes stored product embeddings on the next API start.
+func EmbeddingModelFromEnv() string {
+	if v := strings.TrimSpace(os.Getenv("GEMINI_EMBEDDING_MODEL")); v != "" {
+		return v
+	}
+	return DefaultEmbeddingModel
 }
 
 // HealthCheck verifies API connectivity
 func (c *Client) HealthCheck() error {
-	url := fmt.Sprintf("%s/models?key=%s", c.baseURL, c.apiKey)
-	resp, err := c.httpClient.Get(url)
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext verifies API connectivity, giving up when ctx is done
+func (c *Client) HealthCheckContext(ctx context.Context) error {
+	// The key goes in a header so it can't leak through errors that include the URL
+	url := fmt.Sprintf("%s/models", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-goog-api-key", c.apiKey)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("gemini health check failed: %w", err)
 	}
@@ -53,17 +128,50 @@ func (c *Client) HealthCheck() error {
 
 // GenerateTextRequest represents a text generation request
 type GenerateTextRequest struct {
-	Prompt  string
-	Context string
+	Prompt    string
+	Context   string
+	SkipCache bool // Always call the API, e.g. when the user asked to regenerate
 }
 
 // GenerateTextResponse represents a text generation response
 type GenerateTextResponse struct {
-	Text string
+	Text  string
+	Model string // model that produced the text
 }
 
-// GenerateText generates text using Gemini API with retry logic
+// GenerateText generates text using Gemini API with retry logic.
+// Responses to identical prompts are served from the prompt cache for a few minutes.
 func (c *Client) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, error) {
+	return c.GenerateTextContext(context.Background(), req)
+}
+
+// GenerateTextContext is GenerateText as part of the trace in ctx, giving up when ctx is done.
+// Uncached calls count against the call quota in ctx (see WithCallQuota).
+func (c *Client) GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
+	cacheKey := req.Context + "\x00" + req.Prompt
+	if c.promptCache != nil && !req.SkipCache {
+		if cached, ok := c.promptCache.Get(c.model, cacheKey); ok {
+			log.Printf("[GEMINI] prompt cache hit model=%s", c.model)
+			return cached, nil
+		}
+	}
+
+	if err := checkCallQuota(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := c.generateTextWithRetry(ctx, req)
+	if err != nil {
+		// Failed calls, including quota and rate limit errors, are never cached
+		return nil, err
+	}
+	if c.promptCache != nil {
+		c.promptCache.Set(c.model, cacheKey, resp)
+	}
+	return resp, nil
+}
+
+// generateTextWithRetry calls the API, retrying server errors and waiting out rate limits
+func (c *Client) generateTextWithRetry(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
 	maxRetries := 3
 	baseDelay := 1 * time.Second
 	
@@ -75,13 +183,18 @@ func (c *Client) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, e
 			time.Sleep(delay)
 		}
 		
-		resp, err := c.generateTextRequest(req)
+		resp, err := c.generateTextRequest(ctx, req, RetryAfterExtractor{BaseDelay: baseDelay, Attempt: attempt})
 		if err == nil {
 			return resp, nil
 		}
 		
 		lastErr = err
 		errStr := strings.ToLower(err.Error())
+
+		// The API is down; retrying would only be rejected again
+		if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+			return nil, err
+		}
 		
 		// Check for quota exceeded errors first - these should fail immediately
 		// Quota errors mean daily limit is reached and retrying won't help
@@ -92,8 +205,10 @@ func (c *Client) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, e
 				log.Printf("[GEMINI] Quota exceeded (429), failing immediately without retry")
 				return nil, err
 			}
-			// This is a rate limit, not quota - try to extract retry-after time
-			if retryAfter := extractRetryAfter(err.Error()); retryAfter > 0 {
+			// This is a rate limit, not quota - wait for the retry-after from the response
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				retryAfter := apiErr.RetryAfter
 				// If we have a retry-after time and haven't exceeded max retries, wait and retry
 				if attempt < maxRetries {
 					log.Printf("[GEMINI] Rate limited, waiting %.1fs before retry", retryAfter.Seconds())
@@ -128,18 +243,23 @@ func (c *Client) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, e
 	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
 }
 
-// generateTextRequest performs a single API request
-func (c *Client) generateTextRequest(req GenerateTextRequest) (*GenerateTextResponse, error) {
-	// Use gemini-2.5-flash which is available in v1beta API
-	url := fmt.Sprintf("%s/models/gemini-2.5-flash:generateContent?key=%s", c.baseURL, c.apiKey)
+// defaultSafetySettings asks Gemini to withhold harassing output before it reaches our own moderation
+var defaultSafetySettings = []map[string]interface{}{
+	{
+		"category":  "HARM_CATEGORY_HARASSMENT",
+		"threshold": "BLOCK_MEDIUM_AND_ABOVE",
+	},
+}
 
-	// Build prompt with context if provided
+// generateContentPayload builds the generateContent request body, prefixing the prompt with
+// the request's context if any
+func generateContentPayload(req GenerateTextRequest) map[string]interface{} {
 	prompt := req.Prompt
 	if req.Context != "" {
 		prompt = fmt.Sprintf("Context: %s\n\nQuestion: %s", req.Context, req.Prompt)
 	}
 
-	payload := map[string]interface{}{
+	return map[string]interface{}{
 		"contents": []map[string]interface{}{
 			{
 				"parts": []map[string]interface{}{
@@ -149,33 +269,60 @@ func (c *Client) generateTextRequest(req GenerateTextRequest) (*GenerateTextResp
 				},
 			},
 		},
+		"safetySettings": defaultSafetySettings,
 	}
+}
 
-	jsonData, err := json.Marshal(payload)
+// generateTextRequest performs a single API request. Rate limited responses return an
+// *APIError with the wait time worked out by retryAfter.
+func (c *Client) generateTextRequest(ctx context.Context, req GenerateTextRequest, retryAfter RetryAfterExtractor) (resp *GenerateTextResponse, err error) {
+	if err := c.allowCall(); err != nil {
+		return nil, err
+	}
+	defer func() { c.recordCall(ctx, err) }()
+
+	ctx, span := tracer.Start(ctx, "gemini.generate_text", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("gemini.model", c.model),
+		attribute.Int("gemini.attempt", retryAfter.Attempt),
+	))
+	start := time.Now()
+	defer func() {
+		metrics.ObserveGeminiCall(time.Since(start), err)
+		EndSpan(span, err)
+	}()
+
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
+
+	jsonData, err := json.Marshal(generateContentPayload(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call gemini API: %w", err)
 	}
-	defer resp.Body.Close()
+	defer httpResp.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", httpResp.StatusCode))
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("gemini API error: status %d, body: %s", resp.StatusCode, string(body))
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		apiErr := &APIError{StatusCode: httpResp.StatusCode, Body: string(body)}
+		if httpResp.StatusCode == http.StatusTooManyRequests {
+			apiErr.RetryAfter = retryAfter.Extract(httpResp, apiErr.Body)
+		}
+		return nil, apiErr
 	}
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -185,7 +332,7 @@ func (c *Client) generateTextRequest(req GenerateTextRequest) (*GenerateTextResp
 		return nil, fmt.Errorf("no text in response")
 	}
 
-	return &GenerateTextResponse{Text: text}, nil
+	return &GenerateTextResponse{Text: text, Model: c.model}, nil
 }
 
 // GenerateEmbeddingRequest represents an embedding generation request
@@ -200,6 +347,42 @@ type GenerateEmbeddingResponse struct {
 
 // GenerateEmbedding generates embeddings using Gemini API with retry logic
 func (c *Client) GenerateEmbedding(req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
+	return c.GenerateEmbeddingContext(context.Background(), req)
+}
+
+// GenerateEmbeddingContext is GenerateEmbedding as part of the trace in ctx, giving up when ctx is done
+func (c *Client) GenerateEmbeddingContext(ctx context.Context, req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
+	if err := checkCallQuota(ctx); err != nil {
+		return nil, err
+	}
+	var resp *GenerateEmbeddingResponse
+	err := retryEmbedding(func() (err error) {
+		resp, err = c.generateEmbeddingRequest(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GenerateEmbeddingBatch generates embeddings for several texts in one batchEmbedContents request,
+// with the same retry logic as GenerateEmbedding. Embeddings are returned in the order of texts.
+func (c *Client) GenerateEmbeddingBatch(texts []string) ([][]float64, error) {
+	var embeddings [][]float64
+	err := retryEmbedding(func() (err error) {
+		embeddings, err = c.generateEmbeddingBatchRequest(context.Background(), texts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// retryEmbedding runs an embedding request, retrying server and network errors with exponential
+// backoff. Quota and client errors fail immediately.
+func retryEmbedding(call func() error) error {
 	maxRetries := 3
 	baseDelay := 1 * time.Second
 	
@@ -211,25 +394,30 @@ func (c *Client) GenerateEmbedding(req GenerateEmbeddingRequest) (*GenerateEmbed
 			time.Sleep(delay)
 		}
 		
-		resp, err := c.generateEmbeddingRequest(req)
+		err := call()
 		if err == nil {
-			return resp, nil
+			return nil
 		}
 		
 		lastErr = err
+
+		// The API is down; retrying would only be rejected again
+		if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+			return err
+		}
 		
 		// Check for quota exceeded errors first - these should fail immediately
 		// Quota errors mean daily limit is reached and retrying won't help
 		if isQuotaExceededError(err) {
 			log.Printf("[GEMINI] Quota exceeded, failing immediately without retry")
-			return nil, err
+			return err
 		}
 		
 		// Don't retry on quota errors (429) or client errors (4xx)
 		if strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "rate limit") ||
 		   strings.Contains(err.Error(), "400") || strings.Contains(err.Error(), "401") ||
 		   strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "404") {
-			return nil, err
+			return err
 		}
 		
 		// Retry on server errors (5xx) or network errors
@@ -238,15 +426,25 @@ func (c *Client) GenerateEmbedding(req GenerateEmbeddingRequest) (*GenerateEmbed
 		}
 	}
 	
-	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
+	return fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
 }
 
 // generateEmbeddingRequest performs a single embedding API request
-func (c *Client) generateEmbeddingRequest(req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
-	url := fmt.Sprintf("%s/models/embedding-001:embedContent?key=%s", c.baseURL, c.apiKey)
+func (c *Client) generateEmbeddingRequest(ctx context.Context, req GenerateEmbeddingRequest) (resp *GenerateEmbeddingResponse, err error) {
+	if err := c.allowCall(); err != nil {
+		return nil, err
+	}
+	defer func() { c.recordCall(ctx, err) }()
+
+	ctx, span := tracer.Start(ctx, "gemini.generate_embedding", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("gemini.model", c.embeddingModel)),
+	)
+	defer func() { EndSpan(span, err) }()
+
+	url := fmt.Sprintf("%s/models/%s:embedContent?key=%s", c.baseURL, c.embeddingModel, c.apiKey)
 
 	payload := map[string]interface{}{
-		"model": "models/embedding-001",
+		"model": "models/" + c.embeddingModel,
 		"content": map[string]interface{}{
 			"parts": []map[string]interface{}{
 				{
@@ -261,26 +459,27 @@ func (c *Client) generateEmbeddingRequest(req GenerateEmbeddingRequest) (*Genera
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call gemini API: %w", err)
 	}
-	defer resp.Body.Close()
+	defer httpResp.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", httpResp.StatusCode))
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("gemini API error: status %d, body: %s", resp.StatusCode, string(body))
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return nil, &APIError{StatusCode: httpResp.StatusCode, Body: string(body)}
 	}
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -293,6 +492,81 @@ func (c *Client) generateEmbeddingRequest(req GenerateEmbeddingRequest) (*Genera
 	return &GenerateEmbeddingResponse{Embedding: embedding}, nil
 }
 
+// generateEmbeddingBatchRequest performs a single batch embedding API request
+func (c *Client) generateEmbeddingBatchRequest(ctx context.Context, texts []string) (embeddings [][]float64, err error) {
+	if err := c.allowCall(); err != nil {
+		return nil, err
+	}
+	defer func() { c.recordCall(ctx, err) }()
+
+	ctx, span := tracer.Start(ctx, "gemini.batch_embed", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("gemini.model", c.embeddingModel),
+		attribute.Int("gemini.batch_size", len(texts)),
+	))
+	defer func() { EndSpan(span, err) }()
+
+	url := fmt.Sprintf("%s/models/%s:batchEmbedContents?key=%s", c.baseURL, c.embeddingModel, c.apiKey)
+
+	requests := make([]map[string]interface{}, 0, len(texts))
+	for _, text := range texts {
+		requests = append(requests, map[string]interface{}{
+			"model": "models/" + c.embeddingModel,
+			"content": map[string]interface{}{
+				"parts": []map[string]interface{}{
+					{
+						"text": text,
+					},
+				},
+			},
+		})
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{"requests": requests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call gemini API: %w", err)
+	}
+	defer httpResp.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", httpResp.StatusCode))
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return nil, &APIError{StatusCode: httpResp.StatusCode, Body: string(body)}
+	}
+
+	var result struct {
+		Embeddings []struct {
+			Values []float64 `json:"values"`
+		} `json:"embeddings"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("batch embedding returned %d embeddings for %d texts", len(result.Embeddings), len(texts))
+	}
+
+	embeddings = make([][]float64, len(result.Embeddings))
+	for i, embedding := range result.Embeddings {
+		if len(embedding.Values) == 0 {
+			return nil, fmt.Errorf("no embedding in response for text %d", i)
+		}
+		embeddings[i] = embedding.Values
+	}
+	return embeddings, nil
+}
+
 // extractTextFromResponse extracts text from Gemini API response
 func extractTextFromResponse(result map[string]interface{}) string {
 	candidates, ok := result["candidates"].([]interface{})
@@ -382,20 +656,3 @@ func isQuotaExceededError(err error) bool {
 	
 	return false
 }
-
-// extractRetryAfter extracts the retry-after time from Gemini API error messages
-// Format: "Please retry in 18.60213991s."
-func extractRetryAfter(errorBody string) time.Duration {
-	// Look for "Please retry in X.XXs" pattern
-	re := regexp.MustCompile(`Please retry in ([\d.]+)s`)
-	matches := re.FindStringSubmatch(errorBody)
-	if len(matches) > 1 {
-		if seconds, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			// Add a small buffer (10%) to be safe
-			waitTime := time.Duration(seconds * 1.1 * float64(time.Second))
-			return waitTime
-		}
-	}
-	return 0
-}
-
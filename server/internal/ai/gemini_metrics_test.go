@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/metrics"
+)
+
+func TestGenerateTextRequestRecordsLatency(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.SetRegistry(registry)
+	t.Cleanup(func() { metrics.SetRegistry(nil) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "key=bad-key") {
+			http.Error(w, `{"error":{"message":"invalid key"}}`, http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"hello"}]}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = server.URL
+	if _, err := client.generateTextRequest(context.Background(), GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err != nil {
+		t.Fatalf("generateTextRequest: %v", err)
+	}
+	badClient := NewGeminiClientWithKey("bad-key")
+	badClient.baseURL = server.URL
+	if _, err := badClient.generateTextRequest(context.Background(), GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err == nil {
+		t.Fatal("expected an error for a rejected request")
+	}
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	output := rec.Body.String()
+	for _, sample := range []string{
+		`gemini_request_duration_seconds_count{outcome="success"} 1`,
+		`gemini_request_duration_seconds_count{outcome="error"} 1`,
+	} {
+		if !strings.Contains(output, sample+"\n") {
+			t.Errorf("missing sample %q", sample)
+		}
+	}
+}
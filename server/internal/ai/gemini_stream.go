@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-conversation-platform/internal/metrics"
+)
+
+// maxStreamEventSize bounds a single server-sent event from the streaming endpoint
+const maxStreamEventSize = 1 << 20
+
+// TextStreamer generates text incrementally; implemented by the Gemini client
+type TextStreamer interface {
+	GenerateTextStream(ctx context.Context, req GenerateTextRequest) (<-chan string, <-chan error)
+}
+
+// GenerateTextStream generates text with the streamGenerateContent endpoint, sending text chunks
+// on the first channel as the model produces them. Both channels are closed when the stream
+// ends; the error channel yields at most one error first. Streams are neither retried nor cached,
+// and stop when ctx is done.
+func (c *Client) GenerateTextStream(ctx context.Context, req GenerateTextRequest) (<-chan string, <-chan error) {
+	chunks := make(chan string, 16)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+		if err := c.streamTextRequest(ctx, req, chunks); err != nil {
+			errs <- err
+		}
+	}()
+	return chunks, errs
+}
+
+// streamTextRequest performs a single streaming request, sending each chunk of text on chunks
+func (c *Client) streamTextRequest(ctx context.Context, req GenerateTextRequest, chunks chan<- string) (err error) {
+	ctx, span := tracer.Start(ctx, "gemini.stream_text", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("gemini.model", c.model)),
+	)
+	start := time.Now()
+	defer func() {
+		metrics.ObserveGeminiCall(time.Since(start), err)
+		EndSpan(span, err)
+	}()
+
+	// alt=sse makes the endpoint send one server-sent event per response chunk
+	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", c.baseURL, c.model, c.apiKey)
+
+	jsonData, err := json.Marshal(generateContentPayload(req))
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call gemini API: %w", err)
+	}
+	defer httpResp.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", httpResp.StatusCode))
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return &APIError{StatusCode: httpResp.StatusCode, Body: string(body)}
+	}
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamEventSize)
+	received := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue // Blank separators and other SSE fields
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &result); err != nil {
+			return fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		text := extractTextFromResponse(result)
+		if text == "" {
+			continue
+		}
+		received = true
+		select {
+		case chunks <- text:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	if !received {
+		return fmt.Errorf("no text in response")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// streamChunk is one streamGenerateContent server-sent event carrying text
+func streamChunk(text string) string {
+	return fmt.Sprintf("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":%q}]}}]}\r\n\r\n", text)
+}
+
+func newStreamServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := NewGeminiClientWithKey("test-key")
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+// collectStream drains a stream, returning its chunks and error
+func collectStream(chunks <-chan string, errs <-chan error) ([]string, error) {
+	var got []string
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+	return got, <-errs
+}
+
+func TestGenerateTextStreamEmitsChunks(t *testing.T) {
+	parts := []string{`[{"text": "Hi there`, `, how can`, ` I help?"}]`}
+	client := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":streamGenerateContent") || r.URL.Query().Get("alt") != "sse" {
+			t.Errorf("request = %s?%s, want streamGenerateContent with alt=sse", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Flushing after every event sends the body with chunked transfer encoding
+		for _, part := range parts {
+			fmt.Fprint(w, streamChunk(part))
+			w.(http.Flusher).Flush()
+		}
+	})
+
+	got, err := collectStream(client.GenerateTextStream(context.Background(), GenerateTextRequest{Prompt: "hi"}))
+	if err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if len(got) != len(parts) {
+		t.Fatalf("chunks = %q, want %q", got, parts)
+	}
+	if joined := strings.Join(got, ""); joined != strings.Join(parts, "") {
+		t.Errorf("reassembled = %q, want %q", joined, strings.Join(parts, ""))
+	}
+}
+
+func TestGenerateTextStreamAPIError(t *testing.T) {
+	client := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"quota exceeded"}}`, http.StatusTooManyRequests)
+	})
+
+	got, err := collectStream(client.GenerateTextStream(context.Background(), GenerateTextRequest{Prompt: "hi"}))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("error = %v, want a 429 APIError", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("chunks = %q, want none", got)
+	}
+}
+
+func TestGenerateTextStreamEmptyResponse(t *testing.T) {
+	client := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"candidates\":[]}\r\n\r\n")
+	})
+
+	if _, err := collectStream(client.GenerateTextStream(context.Background(), GenerateTextRequest{Prompt: "hi"})); err == nil {
+		t.Error("expected an error for a stream without text")
+	}
+}
+
+func TestGenerateTextStreamStopsWhenCanceled(t *testing.T) {
+	release := make(chan struct{})
+	client := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, streamChunk("first"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errs := client.GenerateTextStream(ctx, GenerateTextRequest{Prompt: "hi"})
+	if first := <-chunks; first != "first" {
+		t.Fatalf("first chunk = %q", first)
+	}
+	cancel()
+	if _, err := collectStream(chunks, errs); err == nil {
+		t.Error("expected an error after cancellation")
+	}
+}
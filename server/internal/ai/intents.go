@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// DefaultIntents are used for tenants without a custom intent configuration
+var DefaultIntents = []string{"buying", "support", "complaint"}
+
+// Custom intent limits
+const (
+	MinCustomIntents    = 2
+	MaxCustomIntents    = 10
+	MaxIntentNameLength = 30
+)
+
+var intentNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// IntentConfigSource loads tenant intent configuration (to keep analyzer decoupled from storage)
+type IntentConfigSource interface {
+	GetIntentConfig(tenantID string) (*postgres.IntentConfig, error)
+}
+
+// DefaultIntentConfig returns the built-in intent configuration
+func DefaultIntentConfig() *postgres.IntentConfig {
+	return &postgres.IntentConfig{Intents: append([]string(nil), DefaultIntents...)}
+}
+
+// ValidateIntentConfig checks that intents are lowercase alphanumeric/underscore names of at
+// most 30 characters, unique, and between 2 and 10 values; descriptions must name a configured intent
+func ValidateIntentConfig(config *postgres.IntentConfig) error {
+	if len(config.Intents) < MinCustomIntents || len(config.Intents) > MaxCustomIntents {
+		return fmt.Errorf("between %d and %d intents are required", MinCustomIntents, MaxCustomIntents)
+	}
+
+	seen := make(map[string]bool, len(config.Intents))
+	for _, intent := range config.Intents {
+		if len(intent) > MaxIntentNameLength {
+			return fmt.Errorf("intent %q exceeds %d characters", intent, MaxIntentNameLength)
+		}
+		if !intentNamePattern.MatchString(intent) {
+			return fmt.Errorf("intent %q must be lowercase letters, digits or underscores", intent)
+		}
+		if seen[intent] {
+			return fmt.Errorf("duplicate intent %q", intent)
+		}
+		seen[intent] = true
+	}
+
+	for intent := range config.Descriptions {
+		if !seen[intent] {
+			return fmt.Errorf("description given for unknown intent %q", intent)
+		}
+	}
+	return nil
+}
+
+// intentConfigFor returns the tenant's intent configuration, falling back to the defaults
+func (a *Analyzer) intentConfigFor(tenantID string) *postgres.IntentConfig {
+	if a.intentConfigSource == nil || tenantID == "" {
+		return DefaultIntentConfig()
+	}
+	config, err := a.intentConfigSource.GetIntentConfig(tenantID)
+	if err != nil {
+		log.Printf("[AI] failed to load intent config tenant=%s, using defaults: %v", tenantID, err)
+		return DefaultIntentConfig()
+	}
+	if config == nil || len(config.Intents) == 0 {
+		return DefaultIntentConfig()
+	}
+	return config
+}
+
+// matchIntent returns the configured intent matching a model-provided value, or "" if none does
+func matchIntent(value string, intents []string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	normalized := strings.ReplaceAll(value, " ", "_")
+	for _, intent := range intents {
+		if intent == value || intent == normalized {
+			return intent
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeIntentConfigSource serves intent configs keyed by tenant
+type fakeIntentConfigSource struct {
+	configs map[string]*postgres.IntentConfig
+	err     error
+}
+
+func (f *fakeIntentConfigSource) GetIntentConfig(tenantID string) (*postgres.IntentConfig, error) {
+	return f.configs[tenantID], f.err
+}
+
+func TestCustomIntentsReachPromptAndMetadata(t *testing.T) {
+	a := &Analyzer{}
+	a.SetIntentConfigSource(&fakeIntentConfigSource{configs: map[string]*postgres.IntentConfig{
+		"tenant-1": {
+			Intents:      []string{"pricing_inquiry", "demo_request", "renewal", "escalation"},
+			Descriptions: map[string]string{"renewal": "Existing customer renewing a plan"},
+		},
+	}})
+
+	config := a.intentConfigFor("tenant-1")
+	prompt := a.buildAnalysisPrompt("customer: can I see a demo?", "", config)
+	if !strings.Contains(prompt, "- intent: one of [pricing_inquiry,demo_request,renewal,escalation]") {
+		t.Errorf("prompt doesn't list the tenant's intents:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "renewal: Existing customer renewing a plan") {
+		t.Errorf("prompt doesn't describe renewal:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "complaint") {
+		t.Errorf("prompt still offers the default intents:\n%s", prompt)
+	}
+
+	metadata, err := a.parseAnalysisResponse(`{"intent": "Demo Request", "sentiment": "positive"}`, "gemini", config.Intents)
+	if err != nil {
+		t.Fatalf("parseAnalysisResponse: %v", err)
+	}
+	if metadata.Intent != "demo_request" || metadata.IntentScore == 0 {
+		t.Errorf("intent = %q (score %.2f), want demo_request", metadata.Intent, metadata.IntentScore)
+	}
+
+	// A default intent the tenant replaced isn't stored
+	metadata, err = a.parseAnalysisResponse(`{"intent": "buying"}`, "gemini", config.Intents)
+	if err != nil {
+		t.Fatalf("parseAnalysisResponse: %v", err)
+	}
+	if metadata.Intent != "" {
+		t.Errorf("intent = %q, want it dropped", metadata.Intent)
+	}
+}
+
+func TestIntentConfigForFallsBackToDefaults(t *testing.T) {
+	source := &fakeIntentConfigSource{configs: map[string]*postgres.IntentConfig{}}
+	a := &Analyzer{}
+	a.SetIntentConfigSource(source)
+
+	if got := a.intentConfigFor("tenant-without-config").Intents; strings.Join(got, ",") != "buying,support,complaint" {
+		t.Errorf("intents without a config = %v, want the defaults", got)
+	}
+	source.err = errors.New("database unavailable")
+	if got := a.intentConfigFor("tenant-1").Intents; strings.Join(got, ",") != "buying,support,complaint" {
+		t.Errorf("intents when loading fails = %v, want the defaults", got)
+	}
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// supportedLanguageCodes are the ISO 639-1 codes accepted for conversation language overrides
+var supportedLanguageCodes = map[string]bool{
+	"ar": true, "bg": true, "bn": true, "ca": true, "cs": true, "da": true, "de": true,
+	"el": true, "en": true, "es": true, "et": true, "fa": true, "fi": true, "fr": true, "gu": true,
+	"he": true, "hi": true, "hr": true, "hu": true, "id": true, "it": true, "ja": true, "kn": true,
+	"ko": true, "lt": true, "lv": true, "ml": true, "mr": true, "ms": true, "nl": true, "no": true,
+	"pa": true, "pl": true, "pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sr": true,
+	"sv": true, "sw": true, "ta": true, "te": true, "th": true, "tl": true, "tr": true, "uk": true,
+	"ur": true, "vi": true, "zh": true,
+}
+
+// IsValidLanguageCode checks if code is a supported ISO 639-1 two-letter language code
+func IsValidLanguageCode(code string) bool {
+	return supportedLanguageCodes[strings.ToLower(code)]
+}
+
+// ConfirmLanguage asks the model for the language of a message the local detector was unsure
+// about. It returns the ISO 639-1 code and the model's confidence (0-1). Short or romanized text
+// (e.g. Hindi written in Latin letters) is what trigram detection gets wrong.
+func (a *Analyzer) ConfirmLanguage(ctx context.Context, tenantID, text string) (string, float32, error) {
+	prompt := fmt.Sprintf(`Identify the language of the following customer message. It may be written in a
+script other than the language's own, e.g. Hindi in Latin letters.
+Respond ONLY with JSON: {"language": "<ISO 639-1 code>", "confidence": <0-1>}
+
+Message:
+%s`, text)
+
+	resp, err := a.clientFor(tenantID).GenerateTextContext(ctx, GenerateTextRequest{Prompt: prompt})
+	if err != nil {
+		return "", 0, fmt.Errorf("language confirmation failed: %w", err)
+	}
+	return parseLanguageResponse(resp.Text)
+}
+
+// parseLanguageResponse extracts a supported language code and confidence from the model's reply.
+// A missing or out-of-range confidence is treated as 1.
+func parseLanguageResponse(responseText string) (string, float32, error) {
+	jsonStart := strings.Index(responseText, "{")
+	jsonEnd := strings.LastIndex(responseText, "}")
+	if jsonStart == -1 || jsonEnd < jsonStart {
+		return "", 0, fmt.Errorf("language response is not JSON: %q", responseText)
+	}
+	var result struct {
+		Language   string  `json:"language"`
+		Confidence float32 `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(responseText[jsonStart:jsonEnd+1]), &result); err != nil {
+		return "", 0, fmt.Errorf("failed to parse language response: %w", err)
+	}
+
+	language := strings.ToLower(strings.TrimSpace(result.Language))
+	if !IsValidLanguageCode(language) {
+		return "", 0, fmt.Errorf("unsupported language code %q", result.Language)
+	}
+	confidence := result.Confidence
+	if confidence <= 0 || confidence > 1 {
+		confidence = 1
+	}
+	return language, confidence, nil
+}
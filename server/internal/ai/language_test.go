@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// promptRecorder answers every prompt with text, keeping the last prompt
+type promptRecorder struct {
+	text   string
+	prompt string
+}
+
+func (g *promptRecorder) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, error) {
+	return g.GenerateTextContext(context.Background(), req)
+}
+
+func (g *promptRecorder) GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
+	g.prompt = req.Prompt
+	return &GenerateTextResponse{Text: g.text}, nil
+}
+
+func TestConfirmLanguage(t *testing.T) {
+	generator := &promptRecorder{text: "```json\n{\"language\": \"HI\", \"confidence\": 0.92}\n```"}
+	a := &Analyzer{generator: generator}
+
+	language, confidence, err := a.ConfirmLanguage(context.Background(), "tenant-1", "mujhe ye product chahiye jaldi")
+	if err != nil {
+		t.Fatalf("ConfirmLanguage: %v", err)
+	}
+	if language != "hi" || confidence != 0.92 {
+		t.Errorf("ConfirmLanguage = %s %.2f, want hi 0.92", language, confidence)
+	}
+	if !strings.Contains(generator.prompt, "mujhe ye product chahiye jaldi") {
+		t.Errorf("prompt does not include the message: %s", generator.prompt)
+	}
+}
+
+func TestParseLanguageResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		wantLanguage   string
+		wantConfidence float32
+		wantErr        bool
+	}{
+		{name: "json", response: `{"language": "gu", "confidence": 0.6}`, wantLanguage: "gu", wantConfidence: 0.6},
+		{name: "missing confidence", response: `{"language": "hi"}`, wantLanguage: "hi", wantConfidence: 1},
+		{name: "unsupported code", response: `{"language": "hinglish", "confidence": 0.9}`, wantErr: true},
+		{name: "not json", response: "Hindi", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, confidence, err := parseLanguageResponse(tt.response)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseLanguageResponse(%q) = %s, want an error", tt.response, language)
+				}
+				return
+			}
+			if err != nil || language != tt.wantLanguage || confidence != tt.wantConfidence {
+				t.Errorf("parseLanguageResponse(%q) = %s %.2f %v, want %s %.2f", tt.response, language, confidence, err, tt.wantLanguage, tt.wantConfidence)
+			}
+		})
+	}
+}
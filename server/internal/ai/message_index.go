@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// EmbedMessage indexes a message for semantic search. The document ID is the message ID, so
+// re-indexing a message replaces it. Deleted and empty messages aren't indexed.
+func (s *EmbeddingService) EmbedMessage(tenantID string, msg *models.Message) error {
+	if msg.DeletedAt != nil || strings.TrimSpace(msg.Content) == "" {
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"id":              msg.ID,
+		"conversation_id": msg.ConversationID,
+		"tenant_id":       tenantID,
+		"sender":          msg.Sender,
+		"timestamp":       msg.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if err := s.EmbedAndStore(string(ContentTypeMessage), msg.Content, ContentTypeMessage, metadata); err != nil {
+		return fmt.Errorf("failed to index message: %w", err)
+	}
+	return nil
+}
+
+// DeleteMessageEmbedding removes a message from the search index
+func (s *EmbeddingService) DeleteMessageEmbedding(messageID string) error {
+	if err := s.chromaClient.Delete(string(ContentTypeMessage), []string{messageID}); err != nil {
+		return fmt.Errorf("failed to delete message embedding: %w", err)
+	}
+	return nil
+}
+
+// SearchMessages returns the tenant's messages most similar in meaning to query
+func (s *EmbeddingService) SearchMessages(tenantID, query string, limit int) ([]*chroma.MessageSearchResult, error) {
+	embedding, err := s.GenerateEmbedding(query)
+	if err != nil {
+		return nil, err
+	}
+	return chroma.NewRetriever(s.chromaClient).SearchMessages(tenantID, embedding, limit)
+}
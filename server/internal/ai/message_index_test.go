@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// fakeMessageIndex serves Gemini's embedContent and Chroma's add and query APIs, recording
+// the Chroma request bodies by path
+type fakeMessageIndex struct {
+	mu       sync.Mutex
+	requests map[string]map[string]interface{}
+	query    map[string]interface{} // Response to query requests
+}
+
+func (f *fakeMessageIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, ":embedContent") {
+		json.NewEncoder(w).Encode(map[string]interface{}{"embedding": map[string]interface{}{"values": []float64{0.1, 0.2}}})
+		return
+	}
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	f.mu.Lock()
+	f.requests[r.URL.Path] = body
+	f.mu.Unlock()
+	if strings.HasSuffix(r.URL.Path, "/query") {
+		json.NewEncoder(w).Encode(f.query)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newMessageIndexTestService(t *testing.T) (*EmbeddingService, *fakeMessageIndex) {
+	t.Helper()
+	fake := &fakeMessageIndex{requests: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("CHROMA_URL", server.URL)
+	t.Setenv("TENANT_ID", "")
+	chromaClient, err := chroma.NewClient()
+	if err != nil {
+		t.Fatalf("chroma.NewClient: %v", err)
+	}
+	gemini := NewGeminiClientWithKey("test-key")
+	gemini.baseURL = server.URL
+	return NewEmbeddingService(gemini, chromaClient), fake
+}
+
+func TestEmbedMessageStoresTenantMetadata(t *testing.T) {
+	service, fake := newMessageIndexTestService(t)
+	sent := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	msg := &models.Message{ID: "msg-1", ConversationID: "conv-1", Sender: "customer", Content: "I want a refund", Timestamp: sent}
+
+	if err := service.EmbedMessage("tenant-1", msg); err != nil {
+		t.Fatalf("EmbedMessage: %v", err)
+	}
+	add := fake.requests["/api/v1/collections/default_message_index/add"]
+	if add == nil {
+		t.Fatalf("requests = %v, want an add to the message index", fake.requests)
+	}
+	if ids, _ := add["ids"].([]interface{}); len(ids) != 1 || ids[0] != "msg-1" {
+		t.Errorf("ids = %v, want [msg-1]", add["ids"])
+	}
+	metadatas, _ := add["metadatas"].([]interface{})
+	if len(metadatas) != 1 {
+		t.Fatalf("metadatas = %v, want one", add["metadatas"])
+	}
+	metadata, _ := metadatas[0].(map[string]interface{})
+	want := map[string]string{"tenant_id": "tenant-1", "conversation_id": "conv-1", "sender": "customer", "timestamp": "2024-03-01T10:30:00Z"}
+	for key, value := range want {
+		if metadata[key] != value {
+			t.Errorf("metadata[%s] = %v, want %s", key, metadata[key], value)
+		}
+	}
+}
+
+func TestEmbedMessageSkipsDeletedMessages(t *testing.T) {
+	service, fake := newMessageIndexTestService(t)
+	deleted := time.Now()
+	msg := &models.Message{ID: "msg-1", ConversationID: "conv-1", Content: "hello", DeletedAt: &deleted}
+
+	if err := service.EmbedMessage("tenant-1", msg); err != nil {
+		t.Fatalf("EmbedMessage: %v", err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("requests = %v, want none for a deleted message", fake.requests)
+	}
+}
+
+func TestSearchMessagesFiltersByTenant(t *testing.T) {
+	service, fake := newMessageIndexTestService(t)
+	long := strings.Repeat("a", 500)
+	fake.query = map[string]interface{}{
+		"ids":       [][]string{{"msg-1", "msg-2"}},
+		"documents": [][]string{{"I want a refund", long}},
+		"metadatas": [][]map[string]interface{}{{
+			{"conversation_id": "conv-1", "sender": "customer", "tenant_id": "tenant-1"},
+			{"conversation_id": "conv-2", "sender": "agent", "tenant_id": "tenant-1"},
+		}},
+		"distances": [][]float64{{0, 1}},
+	}
+
+	results, err := service.SearchMessages("tenant-1", "money back", 5)
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	query := fake.requests["/api/v1/collections/default_message_index/query"]
+	where, _ := query["where"].(map[string]interface{})
+	if where["tenant_id"] != "tenant-1" {
+		t.Errorf("where = %v, want the search restricted to tenant-1", query["where"])
+	}
+	if query["n_results"] != float64(5) {
+		t.Errorf("n_results = %v, want 5", query["n_results"])
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want 2", results)
+	}
+	first := results[0]
+	if first.MessageID != "msg-1" || first.ConversationID != "conv-1" || first.Sender != "customer" || first.Preview != "I want a refund" {
+		t.Errorf("first result = %+v", first)
+	}
+	if first.Score <= results[1].Score {
+		t.Errorf("scores = %v, %v; want the closer message ranked higher", first.Score, results[1].Score)
+	}
+	if preview := results[1].Preview; len(preview) >= len(long) || !strings.HasSuffix(preview, "…") {
+		t.Errorf("long preview = %q, want it truncated with an ellipsis", preview)
+	}
+}
@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
+)
+
+// RuleTypeContentModeration marks tenant rules that belong to the content moderation ruleset.
+// The rule name is reported as the blocked category.
+const RuleTypeContentModeration = "content_moderation"
+
+// Built-in moderation categories
+const (
+	ModerationCategoryHarassment = "harassment"
+	ModerationCategoryThreat     = "threat"
+	ModerationCategoryProfanity  = "profanity"
+)
+
+// ModerationResult is the outcome of moderating a piece of text
+type ModerationResult struct {
+	IsSafe            bool     `json:"is_safe"`
+	BlockedCategories []string `json:"blocked_categories,omitempty"`
+	SafeText          string   `json:"safe_text"` // Text with flagged matches masked; empty when unsafe
+}
+
+// DefaultModerationRules returns the built-in moderation ruleset applied to every tenant.
+// Harassment and threats block; profanity is only masked.
+func DefaultModerationRules() []*models.Rule {
+	return []*models.Rule{
+		{
+			ID:       "default_moderation_threat",
+			Name:     ModerationCategoryThreat,
+			Type:     RuleTypeContentModeration,
+			Pattern:  `(?i)\b(i will|i'll|i'm going to|gonna)\s+(kill|hurt|find|destroy)\s+you\b`,
+			Action:   "block",
+			IsActive: true,
+		},
+		{
+			ID:       "default_moderation_harassment",
+			Name:     ModerationCategoryHarassment,
+			Type:     RuleTypeContentModeration,
+			Pattern:  `(?i)\b(kill yourself|kys|go die|you worthless|you pathetic)\b`,
+			Action:   "block",
+			IsActive: true,
+		},
+		{
+			ID:       "default_moderation_profanity",
+			Name:     ModerationCategoryProfanity,
+			Type:     RuleTypeContentModeration,
+			Pattern:  `(?i)\b(fuck\w*|shit\w*|bitch\w*|asshole\w*|bastard\w*)\b`,
+			Action:   "flag",
+			IsActive: true,
+		},
+	}
+}
+
+// ContentModerator screens text against a tenant's content moderation ruleset
+type ContentModerator struct {
+	ruleEngine *rules.RuleEngine
+	rules      []*models.Rule
+}
+
+// NewContentModerator creates a moderator from a tenant's rules. Only rules of type
+// content_moderation are used, on top of the built-in defaults.
+func NewContentModerator(ruleEngine *rules.RuleEngine, tenantRules []*models.Rule) *ContentModerator {
+	moderationRules := DefaultModerationRules()
+	for _, rule := range tenantRules {
+		if rule.Type != RuleTypeContentModeration {
+			continue
+		}
+		// Moderation only blocks or masks; auto-correction templates are meant for AI replies
+		if rule.Action == "auto_correct" {
+			flagged := *rule
+			flagged.Action = "flag"
+			rule = &flagged
+		}
+		moderationRules = append(moderationRules, rule)
+	}
+
+	return &ContentModerator{
+		ruleEngine: ruleEngine,
+		rules:      moderationRules,
+	}
+}
+
+// NewTenantContentModerator loads the tenant's rules and creates a moderator.
+// If the rules cannot be loaded, only the built-in defaults apply.
+func NewTenantContentModerator(ruleEngine *rules.RuleEngine, loader RuleLoader, tenantID string) *ContentModerator {
+	var tenantRules []*models.Rule
+	if loader != nil {
+		loaded, err := loader.LoadRules(tenantID)
+		if err != nil {
+			log.Printf("[MODERATION] failed to load rules tenant=%s, using defaults: %v", tenantID, err)
+		} else {
+			tenantRules = loaded
+		}
+	}
+	return NewContentModerator(ruleEngine, tenantRules)
+}
+
+// Moderate checks text against the moderation ruleset
+func (m *ContentModerator) Moderate(text string) ModerationResult {
+	if m == nil || m.ruleEngine == nil || strings.TrimSpace(text) == "" {
+		return ModerationResult{IsSafe: true, SafeText: text}
+	}
+
+	validation := m.ruleEngine.ValidateOutput(text, m.rules)
+	if validation.Blocked {
+		categories := []string{}
+		for _, v := range validation.Violations {
+			if v.Action == "block" {
+				categories = append(categories, v.RuleName)
+			}
+		}
+		return ModerationResult{IsSafe: false, BlockedCategories: categories}
+	}
+
+	safeText := text
+	for _, v := range validation.Violations {
+		safeText = maskMatches(safeText, v.Pattern, v.MatchedText)
+	}
+	return ModerationResult{IsSafe: true, SafeText: safeText}
+}
+
+// maskMatches replaces every match of pattern (or the literal matched text) with asterisks
+func maskMatches(text, pattern, matchedText string) string {
+	mask := func(s string) string { return strings.Repeat("*", len([]rune(s))) }
+
+	if regex, err := regexp.Compile(pattern); err == nil {
+		return regex.ReplaceAllStringFunc(text, mask)
+	}
+	if matchedText == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, matchedText, mask(matchedText))
+}
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
+)
+
+type fakeRuleLoader struct {
+	rules []*models.Rule
+	err   error
+}
+
+func (f fakeRuleLoader) LoadRules(tenantID string) ([]*models.Rule, error) { return f.rules, f.err }
+
+func TestModerateBlocksHarassmentAndThreats(t *testing.T) {
+	moderator := NewContentModerator(rules.NewRuleEngine(), nil)
+
+	tests := []struct {
+		text     string
+		category string
+	}{
+		{"You are pathetic, go die", ModerationCategoryHarassment},
+		{"I'm going to find you after this", ModerationCategoryThreat},
+	}
+	for _, tt := range tests {
+		result := moderator.Moderate(tt.text)
+		if result.IsSafe || result.SafeText != "" {
+			t.Errorf("Moderate(%q) = %+v, want unsafe without safe text", tt.text, result)
+			continue
+		}
+		if len(result.BlockedCategories) != 1 || result.BlockedCategories[0] != tt.category {
+			t.Errorf("Moderate(%q) categories = %v, want [%s]", tt.text, result.BlockedCategories, tt.category)
+		}
+	}
+}
+
+func TestModerateMasksProfanity(t *testing.T) {
+	moderator := NewContentModerator(rules.NewRuleEngine(), nil)
+
+	result := moderator.Moderate("This shipping delay is bullshit, what the fuck")
+	if !result.IsSafe || len(result.BlockedCategories) != 0 {
+		t.Fatalf("result = %+v, want profanity to pass masked", result)
+	}
+	if want := "This shipping delay is bullshit, what the ****"; result.SafeText != want {
+		t.Errorf("SafeText = %q, want %q", result.SafeText, want)
+	}
+
+	clean := moderator.Moderate("When will my order ship?")
+	if !clean.IsSafe || clean.SafeText != "When will my order ship?" {
+		t.Errorf("clean text = %+v, want it unchanged", clean)
+	}
+}
+
+func TestContentModeratorUsesTenantModerationRules(t *testing.T) {
+	tenantRules := []*models.Rule{
+		{ID: "r1", Name: "competitor", Type: RuleTypeContentModeration, Pattern: `(?i)\bacme corp\b`, Action: "block", IsActive: true},
+		// Auto-correction templates are for AI replies; in moderation the match is masked instead
+		{ID: "r2", Name: "internal_codename", Type: RuleTypeContentModeration, Pattern: `(?i)\bproject falcon\b`, Action: "auto_correct", IsActive: true},
+		// Rules outside the moderation ruleset don't apply
+		{ID: "r3", Name: "no_refunds", Type: "compliance", Pattern: `(?i)\brefund\b`, Action: "block", IsActive: true},
+	}
+	moderator := NewContentModerator(rules.NewRuleEngine(), tenantRules)
+
+	if result := moderator.Moderate("Acme Corp is cheaper"); result.IsSafe || result.BlockedCategories[0] != "competitor" {
+		t.Errorf("tenant block rule = %+v, want blocked as competitor", result)
+	}
+	result := moderator.Moderate("Project Falcon launches soon")
+	if !result.IsSafe || result.SafeText != "************** launches soon" {
+		t.Errorf("auto_correct rule = %+v, want the match masked, not corrected", result)
+	}
+	if result := moderator.Moderate("Can I get a refund?"); !result.IsSafe || result.SafeText != "Can I get a refund?" {
+		t.Errorf("non-moderation rule = %+v, want it ignored", result)
+	}
+
+	// The tenant's rule is demoted on a copy; the loaded rule keeps its action
+	if tenantRules[1].Action != "auto_correct" {
+		t.Errorf("tenant rule action = %s, want it unchanged", tenantRules[1].Action)
+	}
+}
+
+func TestNewTenantContentModeratorFallsBackToDefaults(t *testing.T) {
+	engine := rules.NewRuleEngine()
+	competitor := &models.Rule{ID: "r1", Name: "competitor", Type: RuleTypeContentModeration, Pattern: `(?i)\bacme corp\b`, Action: "block", IsActive: true}
+
+	loaded := NewTenantContentModerator(engine, fakeRuleLoader{rules: []*models.Rule{competitor}}, "tenant-1")
+	if result := loaded.Moderate("Acme Corp is cheaper"); result.IsSafe {
+		t.Error("tenant rule not applied")
+	}
+
+	failing := NewTenantContentModerator(engine, fakeRuleLoader{err: errors.New("database down")}, "tenant-1")
+	if result := failing.Moderate("Acme Corp is cheaper"); !result.IsSafe {
+		t.Error("rule load failure should leave only the defaults")
+	}
+	if result := failing.Moderate("go die"); result.IsSafe {
+		t.Error("defaults not applied after a rule load failure")
+	}
+}
+
+func TestModerateWithoutModerator(t *testing.T) {
+	var moderator *ContentModerator
+	if result := moderator.Moderate("go die"); !result.IsSafe || result.SafeText != "go die" {
+		t.Errorf("nil moderator = %+v, want the text passed through", result)
+	}
+	if result := NewContentModerator(rules.NewRuleEngine(), nil).Moderate("   "); !result.IsSafe {
+		t.Errorf("blank text = %+v, want safe", result)
+	}
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ObjectionResolutionRecorder records objections raised and resolved in a conversation
+type ObjectionResolutionRecorder interface {
+	RecordObjectionsRaised(tenantID, conversationID string, objectionTypes []string, raisedAt time.Time) error
+	RecordObjectionsResolved(tenantID, conversationID string, objectionTypes []string, resolvingMessageID *string, resolvedAt time.Time) error
+}
+
+// SetObjectionResolutionRecorder tracks when objections appear and disappear between analyses (optional)
+func (a *Analyzer) SetObjectionResolutionRecorder(recorder ObjectionResolutionRecorder) {
+	a.objectionRecorder = recorder
+}
+
+// previousObjections returns the objections stored by the conversation's last analysis. It is
+// only loaded when objection resolutions are tracked.
+func (a *Analyzer) previousObjections(tenantID, conversationID string) []string {
+	if a.objectionRecorder == nil || tenantID == "" {
+		return nil
+	}
+	metadata, err := a.metadataStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		return nil
+	}
+	return metadata.Objections
+}
+
+// trackObjectionResolutions compares an analysis's objections with the previous ones. New objections
+// are recorded as raised; objections that disappeared are recorded as resolved by the latest agent
+// message. Failures are logged, not returned, since the analysis itself is already stored.
+func (a *Analyzer) trackObjectionResolutions(tenantID, conversationID string, previous, current []string, messages []*models.Message) {
+	if a.objectionRecorder == nil || tenantID == "" {
+		return
+	}
+	raised := missingFrom(current, previous)
+	resolved := missingFrom(previous, current)
+
+	if len(raised) > 0 {
+		raisedAt := time.Now()
+		if len(messages) > 0 {
+			raisedAt = messages[len(messages)-1].Timestamp
+		}
+		if err := a.objectionRecorder.RecordObjectionsRaised(tenantID, conversationID, raised, raisedAt); err != nil {
+			log.Printf("[AI] failed to record raised objections conversation=%s objections=%v error=%v", conversationID, raised, err)
+		}
+	}
+
+	if len(resolved) > 0 {
+		var resolvingMessageID *string
+		resolvedAt := time.Now()
+		if msg := lastAgentMessage(messages); msg != nil {
+			resolvingMessageID = &msg.ID
+			resolvedAt = msg.Timestamp
+		}
+		if err := a.objectionRecorder.RecordObjectionsResolved(tenantID, conversationID, resolved, resolvingMessageID, resolvedAt); err != nil {
+			log.Printf("[AI] failed to record resolved objections conversation=%s objections=%v error=%v", conversationID, resolved, err)
+			return
+		}
+		log.Printf("[AI] objections resolved conversation=%s objections=%v", conversationID, resolved)
+	}
+}
+
+// missingFrom returns the values of from that are not in other
+func missingFrom(from, other []string) []string {
+	seen := make(map[string]bool, len(other))
+	for _, value := range other {
+		seen[value] = true
+	}
+	var missing []string
+	for _, value := range from {
+		if !seen[value] {
+			missing = append(missing, value)
+			seen[value] = true
+		}
+	}
+	return missing
+}
+
+// lastAgentMessage returns the latest message sent by an agent, or nil
+func lastAgentMessage(messages []*models.Message) *models.Message {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Sender == "agent" {
+			return messages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+type fakeObjectionRecorder struct {
+	raised             []string
+	resolved           []string
+	resolvingMessageID *string
+	resolvedAt         time.Time
+}
+
+func (f *fakeObjectionRecorder) RecordObjectionsRaised(tenantID, conversationID string, objectionTypes []string, raisedAt time.Time) error {
+	f.raised = append(f.raised, objectionTypes...)
+	return nil
+}
+
+func (f *fakeObjectionRecorder) RecordObjectionsResolved(tenantID, conversationID string, objectionTypes []string, resolvingMessageID *string, resolvedAt time.Time) error {
+	f.resolved = append(f.resolved, objectionTypes...)
+	f.resolvingMessageID = resolvingMessageID
+	f.resolvedAt = resolvedAt
+	return nil
+}
+
+func TestTrackObjectionResolutions(t *testing.T) {
+	recorder := &fakeObjectionRecorder{}
+	a := &Analyzer{}
+	a.SetObjectionResolutionRecorder(recorder)
+
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	messages := []*models.Message{
+		{ID: "m1", Sender: "customer", Content: "Hi, I'm looking at the Pro plan", Timestamp: start},
+		{ID: "m2", Sender: "agent", Content: "Happy to help", Timestamp: start.Add(time.Minute)},
+		{ID: "m3", Sender: "customer", Content: "It's too expensive for us", Timestamp: start.Add(2 * time.Minute)},
+		{ID: "m4", Sender: "agent", Content: "We can offer 20% off annual billing", Timestamp: start.Add(3 * time.Minute)},
+		{ID: "m5", Sender: "agent", Content: "That brings it within your budget", Timestamp: start.Add(4 * time.Minute)},
+	}
+
+	// The price objection is detected once message 3 arrives
+	a.trackObjectionResolutions("tenant-1", "c1", nil, []string{"price"}, messages[:3])
+	if !reflect.DeepEqual(recorder.raised, []string{"price"}) || len(recorder.resolved) != 0 {
+		t.Fatalf("after message 3 raised=%v resolved=%v, want price raised", recorder.raised, recorder.resolved)
+	}
+
+	// Re-analysis with the same objection records nothing new
+	a.trackObjectionResolutions("tenant-1", "c1", []string{"price"}, []string{"price"}, messages[:4])
+	if len(recorder.raised) != 1 || len(recorder.resolved) != 0 {
+		t.Fatalf("after message 4 raised=%v resolved=%v, want no changes", recorder.raised, recorder.resolved)
+	}
+
+	// By message 5 it is gone, so the latest agent message resolved it
+	a.trackObjectionResolutions("tenant-1", "c1", []string{"price"}, []string{}, messages)
+	if !reflect.DeepEqual(recorder.resolved, []string{"price"}) {
+		t.Fatalf("after message 5 resolved=%v, want price resolved", recorder.resolved)
+	}
+	if recorder.resolvingMessageID == nil || *recorder.resolvingMessageID != "m5" || !recorder.resolvedAt.Equal(messages[4].Timestamp) {
+		t.Errorf("resolved by %v at %v, want m5 at %v", recorder.resolvingMessageID, recorder.resolvedAt, messages[4].Timestamp)
+	}
+}
+
+func TestMissingFrom(t *testing.T) {
+	got := missingFrom([]string{"price", "timing", "price", "trust"}, []string{"timing"})
+	if !reflect.DeepEqual(got, []string{"price", "trust"}) {
+		t.Errorf("missingFrom = %v, want [price trust]", got)
+	}
+	if got := missingFrom(nil, []string{"price"}); got != nil {
+		t.Errorf("missingFrom(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,197 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-conversation-platform/internal/ai"
+)
+
+// Default models
+const (
+	DefaultTextModel      = "gpt-4o"
+	DefaultEmbeddingModel = "text-embedding-3-small"
+)
+
+// tracer creates a client span for each OpenAI API call
+var tracer = otel.Tracer("ai-conversation-platform/internal/ai/openai")
+
+// APIError is a non-200 response from the OpenAI API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("openai API error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+// OpenAIClient is an OpenAI API client usable as a fallback ai.Provider.
+// Requests are not retried; the provider chain moves on to the next provider instead.
+type OpenAIClient struct {
+	apiKey         string
+	baseURL        string
+	model          string
+	embeddingModel string
+	httpClient     *http.Client
+}
+
+// NewOpenAIClient creates a client from OPENAI_API_KEY; OPENAI_MODEL overrides the text model
+func NewOpenAIClient() (*OpenAIClient, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required")
+	}
+
+	client := NewOpenAIClientWithKey(apiKey)
+	if model := os.Getenv("OPENAI_MODEL"); model != "" {
+		client.model = model
+	}
+	return client, nil
+}
+
+// NewOpenAIClientWithKey creates an OpenAI API client for an explicit API key
+func NewOpenAIClientWithKey(apiKey string) *OpenAIClient {
+	return &OpenAIClient{
+		apiKey:         apiKey,
+		baseURL:        "https://api.openai.com/v1",
+		model:          DefaultTextModel,
+		embeddingModel: DefaultEmbeddingModel,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetBaseURL points the client at another API endpoint, e.g. a proxy
+func (c *OpenAIClient) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// Name returns the provider name
+func (c *OpenAIClient) Name() string {
+	return ai.ProviderOpenAI
+}
+
+// Model returns the text generation model name
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// chatCompletionResponse is the part of a chat completion response the client reads
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// embeddingResponse is the part of an embeddings response the client reads
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// GenerateText generates text with the chat completions API
+func (c *OpenAIClient) GenerateText(req ai.GenerateTextRequest) (*ai.GenerateTextResponse, error) {
+	return c.GenerateTextContext(context.Background(), req)
+}
+
+// GenerateTextContext is GenerateText as part of the trace in ctx, giving up when ctx is done
+func (c *OpenAIClient) GenerateTextContext(ctx context.Context, req ai.GenerateTextRequest) (resp *ai.GenerateTextResponse, err error) {
+	ctx, span := tracer.Start(ctx, "openai.generate_text", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("openai.model", c.model)),
+	)
+	defer func() { ai.EndSpan(span, err) }()
+
+	// Same prompt layout as the Gemini client so both providers answer the same question
+	prompt := req.Prompt
+	if req.Context != "" {
+		prompt = fmt.Sprintf("Context: %s\n\nQuestion: %s", req.Context, req.Prompt)
+	}
+	payload := map[string]interface{}{
+		"model": c.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	var result chatCompletionResponse
+	if err := c.post(ctx, "/chat/completions", payload, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Choices) == 0 || result.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("no text in response")
+	}
+
+	return &ai.GenerateTextResponse{Text: result.Choices[0].Message.Content, Model: c.model}, nil
+}
+
+// GenerateEmbedding generates an embedding with the embeddings API
+func (c *OpenAIClient) GenerateEmbedding(req ai.GenerateEmbeddingRequest) (*ai.GenerateEmbeddingResponse, error) {
+	return c.GenerateEmbeddingContext(context.Background(), req)
+}
+
+// GenerateEmbeddingContext is GenerateEmbedding as part of the trace in ctx, giving up when ctx is done
+func (c *OpenAIClient) GenerateEmbeddingContext(ctx context.Context, req ai.GenerateEmbeddingRequest) (resp *ai.GenerateEmbeddingResponse, err error) {
+	ctx, span := tracer.Start(ctx, "openai.generate_embedding", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("openai.model", c.embeddingModel)),
+	)
+	defer func() { ai.EndSpan(span, err) }()
+
+	payload := map[string]interface{}{
+		"model": c.embeddingModel,
+		"input": req.Text,
+	}
+
+	var result embeddingResponse
+	if err := c.post(ctx, "/embeddings", payload, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Data) == 0 || len(result.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("no embedding in response")
+	}
+
+	return &ai.GenerateEmbeddingResponse{Embedding: result.Data[0].Embedding}, nil
+}
+
+// post sends a JSON request and decodes a successful response into result
+func (c *OpenAIClient) post(ctx context.Context, path string, payload interface{}, result interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call openai API: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return &APIError{StatusCode: httpResp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
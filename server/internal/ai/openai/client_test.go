@@ -0,0 +1,156 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"ai-conversation-platform/internal/ai"
+)
+
+// newOpenAIServer serves chat completions and embeddings, checking the request like the real API
+func newOpenAIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Model    string `json:"model"`
+			Input    string `json:"input"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/chat/completions":
+			if body.Model != DefaultTextModel || len(body.Messages) != 1 {
+				http.Error(w, "unexpected request", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{
+					{"message": map[string]string{"role": "assistant", "content": "echo: " + body.Messages[0].Content}},
+				},
+			})
+		case "/embeddings":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"embedding": []float64{float64(len(body.Input)), 0.5}}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateText(t *testing.T) {
+	client := NewOpenAIClientWithKey("test-key")
+	client.SetBaseURL(newOpenAIServer(t).URL)
+
+	resp, err := client.GenerateText(ai.GenerateTextRequest{Prompt: "Which plan?", Context: "Pro costs $20"})
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if want := "echo: Context: Pro costs $20\n\nQuestion: Which plan?"; resp.Text != want {
+		t.Errorf("text = %q, want %q", resp.Text, want)
+	}
+	if resp.Model != DefaultTextModel {
+		t.Errorf("model = %q, want %q", resp.Model, DefaultTextModel)
+	}
+}
+
+func TestGenerateEmbedding(t *testing.T) {
+	client := NewOpenAIClientWithKey("test-key")
+	client.SetBaseURL(newOpenAIServer(t).URL)
+
+	resp, err := client.GenerateEmbedding(ai.GenerateEmbeddingRequest{Text: "pricing"})
+	if err != nil {
+		t.Fatalf("GenerateEmbedding: %v", err)
+	}
+	if want := []float64{7, 0.5}; !reflect.DeepEqual(resp.Embedding, want) {
+		t.Errorf("embedding = %v, want %v", resp.Embedding, want)
+	}
+}
+
+func TestGenerateTextReturnsAPIError(t *testing.T) {
+	client := NewOpenAIClientWithKey("wrong-key")
+	client.SetBaseURL(newOpenAIServer(t).URL)
+
+	_, err := client.GenerateText(ai.GenerateTextRequest{Prompt: "hi"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want a 401 *APIError", err)
+	}
+}
+
+func TestNewOpenAIClientRequiresKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := NewOpenAIClient(); err == nil {
+		t.Error("expected an error without OPENAI_API_KEY")
+	}
+
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("OPENAI_MODEL", "gpt-4o-mini")
+	client, err := NewOpenAIClient()
+	if err != nil {
+		t.Fatalf("NewOpenAIClient: %v", err)
+	}
+	if client.Model() != "gpt-4o-mini" {
+		t.Errorf("model = %q, want the OPENAI_MODEL override", client.Model())
+	}
+}
+
+func TestProviderChainFallsBackFromGeminiQuotaToOpenAI(t *testing.T) {
+	var geminiCalls int32
+	gemini := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&geminiCalls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"error":{"code":429,"message":"You exceeded your current quota, please check your plan and billing details.","status":"RESOURCE_EXHAUSTED"}}`)
+	}))
+	defer gemini.Close()
+
+	geminiClient := ai.NewGeminiClientWithKey("gemini-key")
+	geminiClient.SetBaseURL(gemini.URL)
+	geminiClient.SetPromptCache(nil)
+	openaiClient := NewOpenAIClientWithKey("test-key")
+	openaiClient.SetBaseURL(newOpenAIServer(t).URL)
+
+	chain := ai.NewProviderChain([]ai.Provider{geminiClient, openaiClient})
+	resp, err := chain.GenerateText(ai.GenerateTextRequest{Prompt: "Suggest a reply"})
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if resp.Text != "echo: Suggest a reply" || resp.Model != DefaultTextModel {
+		t.Errorf("response = %+v, want the OpenAI answer", resp)
+	}
+	// Quota errors fail fast instead of being retried against Gemini
+	if got := atomic.LoadInt32(&geminiCalls); got != 1 {
+		t.Errorf("gemini calls = %d, want 1", got)
+	}
+
+	// With OpenAI down too, both failures are reported
+	openaiClient.SetBaseURL(gemini.URL + "/missing")
+	_, err = chain.GenerateText(ai.GenerateTextRequest{Prompt: "Suggest a reply"})
+	var chainErr *ai.ChainError
+	if !errors.As(err, &chainErr) || len(chainErr.Errors) != 2 {
+		t.Fatalf("error = %v, want a chain error from both providers", err)
+	}
+	if !strings.Contains(err.Error(), "gemini:") || !strings.Contains(err.Error(), "openai:") {
+		t.Errorf("error = %q, want both providers named", err.Error())
+	}
+}
@@ -0,0 +1,106 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// promptCacheTTL is how long a generated response is reused for an identical prompt
+	promptCacheTTL = 5 * time.Minute
+	// promptCacheMaxEntries caps the responses held in memory
+	promptCacheMaxEntries = 1000
+)
+
+// defaultPromptCache is shared by all Gemini clients so tenants' identical prompts hit one cache
+var defaultPromptCache = NewPromptCache(promptCacheTTL, promptCacheMaxEntries)
+
+// promptCacheEntry is a cached response and when it expires
+type promptCacheEntry struct {
+	response  GenerateTextResponse
+	expiresAt time.Time
+}
+
+// PromptCache is a short-lived in-memory cache of text responses keyed by a SHA-256 of the model and prompt
+type PromptCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]promptCacheEntry
+}
+
+// NewPromptCache creates a prompt cache holding at most maxEntries responses for ttl each
+func NewPromptCache(ttl time.Duration, maxEntries int) *PromptCache {
+	return &PromptCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]promptCacheEntry),
+	}
+}
+
+// Get returns the cached response for model and prompt, if present and not expired
+func (c *PromptCache) Get(model, prompt string) (*GenerateTextResponse, bool) {
+	key := promptCacheKey(model, prompt)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	response := entry.response
+	return &response, true
+}
+
+// Set caches a response for model and prompt. When the cache is full, expired entries are
+// dropped first, then the entry closest to expiry.
+func (c *PromptCache) Set(model, prompt string, response *GenerateTextResponse) {
+	key := promptCacheKey(model, prompt)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = promptCacheEntry{response: *response, expiresAt: now.Add(c.ttl)}
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted
+func (c *PromptCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictLocked makes room for one entry. c.mu must be held.
+func (c *PromptCache) evictLocked(now time.Time) {
+	oldestKey := ""
+	var oldestExpiry time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldestExpiry) {
+			oldestKey = key
+			oldestExpiry = entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// promptCacheKey hashes the model and prompt so large prompts aren't held as map keys
+func promptCacheKey(model, prompt string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
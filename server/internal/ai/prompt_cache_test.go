@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPromptCacheGetSet(t *testing.T) {
+	cache := NewPromptCache(time.Minute, 10)
+	if _, ok := cache.Get("model", "prompt"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	cache.Set("model", "prompt", &GenerateTextResponse{Text: "answer", Model: "model"})
+	got, ok := cache.Get("model", "prompt")
+	if !ok || got.Text != "answer" {
+		t.Fatalf("Get = %+v, %v; want cached answer", got, ok)
+	}
+	if _, ok := cache.Get("other-model", "prompt"); ok {
+		t.Error("responses must not be shared between models")
+	}
+	if _, ok := cache.Get("model", "prompt "); ok {
+		t.Error("different prompt should miss")
+	}
+}
+
+func TestPromptCacheExpires(t *testing.T) {
+	cache := NewPromptCache(10*time.Millisecond, 10)
+	cache.Set("model", "prompt", &GenerateTextResponse{Text: "answer"})
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("model", "prompt"); ok {
+		t.Error("expected expired entry to miss")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d, want expired entry removed", cache.Len())
+	}
+}
+
+func TestPromptCacheBounded(t *testing.T) {
+	cache := NewPromptCache(time.Minute, 3)
+	for i := 0; i < 5; i++ {
+		cache.Set("model", fmt.Sprintf("prompt-%d", i), &GenerateTextResponse{Text: "answer"})
+		time.Sleep(time.Millisecond)
+	}
+
+	if cache.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", cache.Len())
+	}
+	if _, ok := cache.Get("model", "prompt-0"); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	if _, ok := cache.Get("model", "prompt-4"); !ok {
+		t.Error("expected newest entry to be cached")
+	}
+}
+
+func TestGenerateTextServesCachedResponse(t *testing.T) {
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = "http://127.0.0.1:0" // Any real request would fail
+	cache := NewPromptCache(time.Minute, 10)
+	client.SetPromptCache(cache)
+
+	req := GenerateTextRequest{Prompt: "prompt", Context: "context"}
+	cache.Set(client.Model(), req.Context+"\x00"+req.Prompt, &GenerateTextResponse{Text: "cached", Model: client.Model()})
+
+	resp, err := client.GenerateText(req)
+	if err != nil || resp.Text != "cached" {
+		t.Fatalf("GenerateText = %+v, %v; want cached response", resp, err)
+	}
+}
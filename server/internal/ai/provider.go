@@ -0,0 +1,172 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Provider names accepted in AI_PROVIDER_ORDER
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai"
+)
+
+// DefaultProviderOrder tries Gemini first and falls back to OpenAI when it is configured
+var DefaultProviderOrder = []string{ProviderGemini, ProviderOpenAI}
+
+// Provider is an AI backend that can generate text and embeddings
+type Provider interface {
+	Name() string
+	GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error)
+	GenerateEmbeddingContext(ctx context.Context, req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error)
+}
+
+// TextGenerator generates text; implemented by a single client and by a ProviderChain
+type TextGenerator interface {
+	GenerateText(req GenerateTextRequest) (*GenerateTextResponse, error)
+	GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error)
+}
+
+// ProviderOrderFromEnv reads AI_PROVIDER_ORDER, a comma-separated list of providers to try in
+// order (default "gemini,openai"). Unknown and repeated names are skipped.
+func ProviderOrderFromEnv() []string {
+	value := os.Getenv("AI_PROVIDER_ORDER")
+	if strings.TrimSpace(value) == "" {
+		return DefaultProviderOrder
+	}
+
+	var order []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if name != ProviderGemini && name != ProviderOpenAI {
+			log.Printf("[AI] ignoring unknown provider in AI_PROVIDER_ORDER provider=%s", name)
+			continue
+		}
+		seen[name] = true
+		order = append(order, name)
+	}
+	if len(order) == 0 {
+		return DefaultProviderOrder
+	}
+	return order
+}
+
+// ProviderError is the error a single provider returned within a chain
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e ProviderError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+}
+
+// ChainError is returned when every provider in a chain failed
+type ChainError struct {
+	Errors []ProviderError // In the order the providers were tried
+}
+
+func (e *ChainError) Error() string {
+	if len(e.Errors) == 0 {
+		return "no AI providers configured"
+	}
+	messages := make([]string, len(e.Errors))
+	for i, providerErr := range e.Errors {
+		messages[i] = providerErr.Error()
+	}
+	return "all AI providers failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the provider errors so errors.Is and errors.As see each of them
+func (e *ChainError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, providerErr := range e.Errors {
+		errs[i] = providerErr.Err
+	}
+	return errs
+}
+
+// ProviderChain tries providers in order, moving on to the next one when a provider fails,
+// e.g. because its quota is exhausted
+type ProviderChain struct {
+	providers []Provider
+}
+
+// NewProviderChain creates a chain that tries providers in the given order
+func NewProviderChain(providers []Provider) *ProviderChain {
+	return &ProviderChain{providers: providers}
+}
+
+// Providers returns the names of the chain's providers in order
+func (c *ProviderChain) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, provider := range c.providers {
+		names[i] = provider.Name()
+	}
+	return names
+}
+
+// GenerateText generates text with the first provider that succeeds
+func (c *ProviderChain) GenerateText(req GenerateTextRequest) (*GenerateTextResponse, error) {
+	return c.GenerateTextContext(context.Background(), req)
+}
+
+// GenerateTextContext is GenerateText as part of the trace in ctx, giving up when ctx is done
+func (c *ProviderChain) GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
+	var resp *GenerateTextResponse
+	err := c.try(ctx, "generate_text", func(provider Provider) error {
+		var err error
+		resp, err = provider.GenerateTextContext(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// GenerateEmbedding generates an embedding with the first provider that succeeds. Vectors from
+// different providers are not comparable, so callers storing embeddings should use one provider.
+func (c *ProviderChain) GenerateEmbedding(req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
+	return c.GenerateEmbeddingContext(context.Background(), req)
+}
+
+// GenerateEmbeddingContext is GenerateEmbedding as part of the trace in ctx, giving up when ctx is done
+func (c *ProviderChain) GenerateEmbeddingContext(ctx context.Context, req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
+	var resp *GenerateEmbeddingResponse
+	err := c.try(ctx, "generate_embedding", func(provider Provider) error {
+		var err error
+		resp, err = provider.GenerateEmbeddingContext(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// try calls each provider until one succeeds, collecting the errors of those that failed
+func (c *ProviderChain) try(ctx context.Context, operation string, call func(Provider) error) error {
+	chainErr := &ChainError{}
+	for i, provider := range c.providers {
+		err := call(provider)
+		if err == nil {
+			if i > 0 {
+				log.Printf("[AI] fallback provider succeeded operation=%s provider=%s", operation, provider.Name())
+			}
+			return nil
+		}
+		chainErr.Errors = append(chainErr.Errors, ProviderError{Provider: provider.Name(), Err: err})
+
+		// A cancelled request is not retried against the next provider
+		if ctxErr := ctx.Err(); ctxErr != nil || errors.Is(err, context.Canceled) {
+			return chainErr
+		}
+		if i < len(c.providers)-1 {
+			log.Printf("[AI] provider failed, trying next operation=%s provider=%s error=%v", operation, provider.Name(), err)
+		}
+	}
+	return chainErr
+}
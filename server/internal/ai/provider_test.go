@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeProvider returns text or err, counting its calls
+type fakeProvider struct {
+	name  string
+	text  string
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &GenerateTextResponse{Text: p.text, Model: p.name + "-model"}, nil
+}
+
+func (p *fakeProvider) GenerateEmbeddingContext(ctx context.Context, req GenerateEmbeddingRequest) (*GenerateEmbeddingResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &GenerateEmbeddingResponse{Embedding: []float64{float64(len(p.name))}}, nil
+}
+
+func TestProviderChainUsesFirstSuccessfulProvider(t *testing.T) {
+	primary := &fakeProvider{name: "primary", text: "from primary"}
+	secondary := &fakeProvider{name: "secondary", text: "from secondary"}
+	chain := NewProviderChain([]Provider{primary, secondary})
+
+	resp, err := chain.GenerateText(GenerateTextRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if resp.Text != "from primary" || secondary.calls != 0 {
+		t.Errorf("text = %q, secondary calls = %d; want the primary answer only", resp.Text, secondary.calls)
+	}
+}
+
+func TestProviderChainFallsBackAndRecordsErrors(t *testing.T) {
+	quota := &APIError{StatusCode: 429, Body: "You exceeded your current quota"}
+	primary := &fakeProvider{name: "primary", err: quota}
+	secondary := &fakeProvider{name: "secondary", text: "from secondary"}
+	chain := NewProviderChain([]Provider{primary, secondary})
+
+	resp, err := chain.GenerateText(GenerateTextRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if resp.Text != "from secondary" || resp.Model != "secondary-model" {
+		t.Errorf("response = %+v, want the secondary answer", resp)
+	}
+
+	embedding, err := chain.GenerateEmbedding(GenerateEmbeddingRequest{Text: "hi"})
+	if err != nil {
+		t.Fatalf("GenerateEmbedding: %v", err)
+	}
+	if !reflect.DeepEqual(embedding.Embedding, []float64{9}) {
+		t.Errorf("embedding = %v, want the secondary embedding", embedding.Embedding)
+	}
+
+	// When every provider fails, each error is kept in order
+	secondary.err = errors.New("status 500")
+	_, err = chain.GenerateText(GenerateTextRequest{Prompt: "hi"})
+	var chainErr *ChainError
+	if !errors.As(err, &chainErr) {
+		t.Fatalf("error = %v, want a *ChainError", err)
+	}
+	if len(chainErr.Errors) != 2 || chainErr.Errors[0].Provider != "primary" || chainErr.Errors[1].Provider != "secondary" {
+		t.Fatalf("provider errors = %+v, want primary then secondary", chainErr.Errors)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		t.Errorf("errors.As(*APIError) = %v, want the primary quota error", apiErr)
+	}
+	if !IsQuotaError(err) {
+		t.Error("IsQuotaError = false, want true so the analysis is retried")
+	}
+}
+
+func TestProviderChainStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	primary := &fakeProvider{name: "primary", err: context.Canceled}
+	secondary := &fakeProvider{name: "secondary", text: "from secondary"}
+
+	if _, err := NewProviderChain([]Provider{primary, secondary}).GenerateTextContext(ctx, GenerateTextRequest{}); err == nil {
+		t.Fatal("expected an error for a cancelled request")
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary calls = %d, want 0 after cancellation", secondary.calls)
+	}
+}
+
+func TestProviderChainWithoutProviders(t *testing.T) {
+	if _, err := NewProviderChain(nil).GenerateText(GenerateTextRequest{}); err == nil || err.Error() != "no AI providers configured" {
+		t.Errorf("error = %v, want no AI providers configured", err)
+	}
+}
+
+func TestProviderOrderFromEnv(t *testing.T) {
+	tests := map[string][]string{
+		"":                       DefaultProviderOrder,
+		"openai":                 {"openai"},
+		" OpenAI , gemini ":      {"openai", "gemini"},
+		"gemini,claude,gemini":   {"gemini"},
+		"unknown":                DefaultProviderOrder,
+		"openai,,gemini,openai,": {"openai", "gemini"},
+	}
+	for value, want := range tests {
+		t.Setenv("AI_PROVIDER_ORDER", value)
+		if got := ProviderOrderFromEnv(); !reflect.DeepEqual(got, want) {
+			t.Errorf("AI_PROVIDER_ORDER=%q: order = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryInPattern matches Gemini's "Please retry in 18.60213991s." error message
+var retryInPattern = regexp.MustCompile(`Please retry in ([\d.]+)s`)
+
+// retryAfterBuffer is the safety margin added to a server-provided retry-after
+const retryAfterBuffer = 1.1
+
+// APIError is a non-200 Gemini response. RetryAfter is set for 429 responses.
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gemini API error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+// RetryAfterExtractor works out how long to wait before retrying a rate limited request
+type RetryAfterExtractor struct {
+	BaseDelay time.Duration // Default backoff unit when the response gives no hint
+	Attempt   int           // Zero-based attempt number, for the default backoff
+}
+
+// Extract returns the longest retry-after found in the Retry-After header, the
+// "Please retry in Xs" message, or a retryDelay JSON field, plus a 10% buffer.
+// A 429 without any hint falls back to BaseDelay * 2^Attempt. Returns 0 otherwise.
+func (e RetryAfterExtractor) Extract(httpResp *http.Response, bodyStr string) time.Duration {
+	var header time.Duration
+	if httpResp != nil {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(httpResp.Header.Get("Retry-After"))); err == nil && seconds > 0 {
+			header = time.Duration(seconds) * time.Second
+		}
+	}
+	message := retryAfterFromMessage(bodyStr)
+	field := retryDelayFromJSON(bodyStr)
+
+	longest := header
+	if message > longest {
+		longest = message
+	}
+	if field > longest {
+		longest = field
+	}
+
+	var retryAfter time.Duration
+	switch {
+	case longest > 0:
+		retryAfter = time.Duration(float64(longest) * retryAfterBuffer)
+	case httpResp != nil && httpResp.StatusCode == http.StatusTooManyRequests:
+		retryAfter = e.BaseDelay * time.Duration(1<<uint(e.Attempt))
+	}
+
+	if retryAfter > 0 {
+		log.Printf("[GEMINI] DEBUG retry_after=%.1fs header=%.1fs message=%.1fs retry_delay=%.1fs attempt=%d",
+			retryAfter.Seconds(), header.Seconds(), message.Seconds(), field.Seconds(), e.Attempt)
+	}
+	return retryAfter
+}
+
+// retryAfterFromMessage parses "Please retry in 18.60213991s." from an error message
+func retryAfterFromMessage(body string) time.Duration {
+	matches := retryInPattern.FindStringSubmatch(body)
+	if len(matches) > 1 {
+		if seconds, err := strconv.ParseFloat(matches[1], 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return 0
+}
+
+// retryDelayFromJSON finds a retryDelay field anywhere in a JSON error body. Gemini sends it
+// as a duration string ("18s") in RetryInfo details; proto-style {"seconds": 18, "nanos": 0} is also accepted.
+func retryDelayFromJSON(body string) time.Duration {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return 0
+	}
+	return findRetryDelay(parsed)
+}
+
+func findRetryDelay(node interface{}) time.Duration {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if delay, ok := v["retryDelay"]; ok {
+			if d := parseRetryDelay(delay); d > 0 {
+				return d
+			}
+		}
+		for _, child := range v {
+			if d := findRetryDelay(child); d > 0 {
+				return d
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if d := findRetryDelay(child); d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+func parseRetryDelay(value interface{}) time.Duration {
+	switch v := value.(type) {
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	case map[string]interface{}:
+		seconds := jsonNumber(v["seconds"])
+		nanos := jsonNumber(v["nanos"])
+		return time.Duration(seconds*float64(time.Second) + nanos)
+	}
+	return 0
+}
+
+// jsonNumber reads a number that may be encoded as a JSON number or string (int64 in proto JSON)
+func jsonNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func rateLimitedResponse(retryAfterHeader string) *http.Response {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	if retryAfterHeader != "" {
+		resp.Header.Set("Retry-After", retryAfterHeader)
+	}
+	return resp
+}
+
+func TestRetryAfterExtractorResponseShapes(t *testing.T) {
+	extractor := RetryAfterExtractor{BaseDelay: time.Second, Attempt: 2}
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		body string
+		want time.Duration
+	}{
+		{
+			name: "header only",
+			resp: rateLimitedResponse("19"),
+			body: `{"error": {"code": 429, "message": "Resource has been exhausted"}}`,
+			want: 20900 * time.Millisecond,
+		},
+		{
+			name: "body only",
+			resp: rateLimitedResponse(""),
+			body: `{"error": {"code": 429, "message": "Please retry in 18.6s.", "details": [` +
+				`{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "18s"}]}}`,
+			want: 20460 * time.Millisecond,
+		},
+		{
+			name: "header and body, longest wins",
+			resp: rateLimitedResponse("5"),
+			body: `{"error": {"code": 429, "details": [{"retryDelay": {"seconds": "30", "nanos": 0}}]}}`,
+			want: 33 * time.Second,
+		},
+		{
+			name: "neither falls back to exponential backoff",
+			resp: rateLimitedResponse(""),
+			body: `{"error": {"code": 429, "message": "Too many requests"}}`,
+			want: 4 * time.Second,
+		},
+		{
+			name: "malformed values are ignored",
+			resp: rateLimitedResponse("Wed, 21 Oct 2015 07:28:00 GMT"),
+			body: `{"error": {"retryDelay": "soon", "message": "Please retry in abc s"`,
+			want: 4 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractor.Extract(tt.resp, tt.body); got != tt.want {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterExtractorIgnoresNonRateLimitedResponses(t *testing.T) {
+	extractor := RetryAfterExtractor{BaseDelay: time.Second}
+	resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}
+
+	if got := extractor.Extract(resp, `{"error": {"code": 500}}`); got != 0 {
+		t.Errorf("Extract() = %v, want 0 for a 500 without retry hints", got)
+	}
+}
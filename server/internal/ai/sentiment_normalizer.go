@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"math"
+	"sync"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Calibration metrics and reference names stored in model_calibrations
+const (
+	CalibrationMetricSentiment = "sentiment"
+	// CalibrationReferenceModel holds the distribution of human-labelled scores
+	// that every model's output is mapped onto
+	CalibrationReferenceModel = "ground_truth"
+)
+
+// Reference distribution used until ground-truth labels have been calibrated
+const (
+	defaultReferenceMean = 0.5
+	defaultReferenceStd  = 0.2
+)
+
+// SentimentNormalizer puts sentiment scores from different models on a common
+// scale. A raw score is z-scored against its model's baseline, then mapped onto
+// the reference (ground-truth) distribution and clamped to 0-1. Scores from
+// uncalibrated models pass through unchanged.
+type SentimentNormalizer struct {
+	mu             sync.RWMutex
+	modelBaselines map[string]float64
+	modelStdDevs   map[string]float64
+}
+
+// NewSentimentNormalizer creates an empty normalizer
+func NewSentimentNormalizer() *SentimentNormalizer {
+	return &SentimentNormalizer{
+		modelBaselines: make(map[string]float64),
+		modelStdDevs:   make(map[string]float64),
+	}
+}
+
+// LoadSentimentNormalizer creates a normalizer from stored sentiment calibrations
+func LoadSentimentNormalizer(calibrationStorage *postgres.ModelCalibrationStorage) (*SentimentNormalizer, error) {
+	normalizer := NewSentimentNormalizer()
+	calibrations, err := calibrationStorage.ListCalibrations(CalibrationMetricSentiment)
+	if err != nil {
+		return normalizer, err
+	}
+	for _, cal := range calibrations {
+		normalizer.SetCalibration(cal.ModelName, cal.BaselineMean, cal.BaselineStd)
+	}
+	return normalizer, nil
+}
+
+// SetCalibration sets the baseline mean and standard deviation for a model
+func (n *SentimentNormalizer) SetCalibration(modelName string, mean, std float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.modelBaselines[modelName] = mean
+	n.modelStdDevs[modelName] = std
+}
+
+// Normalize maps a raw sentiment score from modelName onto the common scale
+func (n *SentimentNormalizer) Normalize(score float64, modelName string) float64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	mean, ok := n.modelBaselines[modelName]
+	std := n.modelStdDevs[modelName]
+	if !ok || std <= 0 {
+		return score
+	}
+
+	refMean, refStd := defaultReferenceMean, defaultReferenceStd
+	if m, ok := n.modelBaselines[CalibrationReferenceModel]; ok && n.modelStdDevs[CalibrationReferenceModel] > 0 {
+		refMean, refStd = m, n.modelStdDevs[CalibrationReferenceModel]
+	}
+
+	z := (score - mean) / std
+	return math.Max(0, math.Min(1, refMean+z*refStd))
+}
+
+// MeanStdDev returns the mean and population standard deviation of values
+func MeanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeanStdDev(t *testing.T) {
+	tests := []struct {
+		values   []float64
+		wantMean float64
+		wantStd  float64
+	}{
+		{nil, 0, 0},
+		{[]float64{0.4}, 0.4, 0},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, 2}, // Population, not sample, deviation
+		{[]float64{0.2, 0.8}, 0.5, 0.3},
+	}
+	for _, tt := range tests {
+		mean, std := MeanStdDev(tt.values)
+		if math.Abs(mean-tt.wantMean) > 1e-9 || math.Abs(std-tt.wantStd) > 1e-9 {
+			t.Errorf("MeanStdDev(%v) = %v, %v, want %v, %v", tt.values, mean, std, tt.wantMean, tt.wantStd)
+		}
+	}
+}
+
+func TestSentimentNormalizerNormalize(t *testing.T) {
+	normalizer := NewSentimentNormalizer()
+
+	// Uncalibrated models and models without spread pass through unchanged
+	if got := normalizer.Normalize(0.9, "gemini"); got != 0.9 {
+		t.Errorf("uncalibrated score = %v, want 0.9", got)
+	}
+	normalizer.SetCalibration("flat", 0.7, 0)
+	if got := normalizer.Normalize(0.9, "flat"); got != 0.9 {
+		t.Errorf("zero-std score = %v, want 0.9", got)
+	}
+
+	// Without ground truth, scores map onto the default 0.5/0.2 reference
+	normalizer.SetCalibration("gemini", 0.7, 0.1)
+	tests := []struct {
+		score, want float64
+	}{
+		{0.7, 0.5},
+		{0.8, 0.7},
+		{0.6, 0.3},
+		{1.0, 1.0}, // z=3 clamps to 1
+		{0.3, 0.0}, // z=-4 clamps to 0
+	}
+	for _, tt := range tests {
+		if got := normalizer.Normalize(tt.score, "gemini"); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Normalize(%v) with default reference = %v, want %v", tt.score, got, tt.want)
+		}
+	}
+
+	// A calibrated ground truth replaces the default reference
+	normalizer.SetCalibration(CalibrationReferenceModel, 0.6, 0.1)
+	if got := normalizer.Normalize(0.8, "gemini"); math.Abs(got-0.7) > 1e-9 {
+		t.Errorf("Normalize(0.8) with ground truth = %v, want 0.7", got)
+	}
+
+	// Two models with different baselines agree once normalized
+	normalizer.SetCalibration("openai", 0.4, 0.2)
+	if a, b := normalizer.Normalize(0.8, "gemini"), normalizer.Normalize(0.6, "openai"); math.Abs(a-b) > 1e-9 {
+		t.Errorf("normalized gemini 0.8 = %v, openai 0.6 = %v, want equal", a, b)
+	}
+}
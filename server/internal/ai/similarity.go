@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SimilarityChecker compares short texts such as chat replies
+type SimilarityChecker struct{}
+
+// Jaccard returns the Jaccard similarity of the word sets of a and b, from 0 (no shared words)
+// to 1 (same words). Words are lowercased and stripped of punctuation. Two empty texts score 0.
+func (SimilarityChecker) Jaccard(a, b string) float64 {
+	setA := wordSet(a)
+	setB := wordSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range setA {
+		if setB[word] {
+			shared++
+		}
+	}
+	union := len(setA) + len(setB) - shared
+	return float64(shared) / float64(union)
+}
+
+// wordSet returns the distinct lowercase words in text with punctuation removed
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		word := strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+				return -1
+			}
+			return r
+		}, field)
+		if word != "" {
+			words[word] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,45 @@
+package ai
+
+import "testing"
+
+func TestJaccardSimilarMessages(t *testing.T) {
+	checker := SimilarityChecker{}
+	similar := []struct{ a, b string }{
+		{"Here's information about WhatsApp Starter", "here's information about whatsapp starter!"},
+		{"Here's information about WhatsApp Starter.", "Here's information about the WhatsApp Starter"},
+		{"The Pro plan costs $49 per month.", "The Pro plan costs $49 per month"},
+		{"Thanks for reaching out! How can I help?", "Thanks for reaching out, how can I help?"},
+		{"Our starter plan includes 1,000 messages per month", "Our starter plan includes 1000 messages per month"},
+	}
+	for _, pair := range similar {
+		if score := checker.Jaccard(pair.a, pair.b); score <= 0.7 {
+			t.Errorf("Jaccard(%q, %q) = %.2f, want > 0.7", pair.a, pair.b, score)
+		}
+	}
+}
+
+func TestJaccardDissimilarMessages(t *testing.T) {
+	checker := SimilarityChecker{}
+	dissimilar := []struct{ a, b string }{
+		{"Here's information about WhatsApp Starter", "Shipping takes 3-5 business days"},
+		{"The Pro plan costs $49 per month", "Would you like me to book a demo for you?"},
+		{"Thanks for reaching out! How can I help?", "Your refund has been processed"},
+		{"WhatsApp Starter supports one number", "Enterprise adds SSO, audit logs and a dedicated manager"},
+		{"", "Here's information about WhatsApp Starter"},
+	}
+	for _, pair := range dissimilar {
+		if score := checker.Jaccard(pair.a, pair.b); score >= 0.7 {
+			t.Errorf("Jaccard(%q, %q) = %.2f, want < 0.7", pair.a, pair.b, score)
+		}
+	}
+}
+
+func TestJaccardExactValues(t *testing.T) {
+	checker := SimilarityChecker{}
+	if score := checker.Jaccard("a b c", "A, B. C!"); score != 1 {
+		t.Errorf("identical word sets = %.2f, want 1", score)
+	}
+	if score := checker.Jaccard("a b", "b c"); score != 1.0/3.0 {
+		t.Errorf("one shared word of three = %.2f, want 0.33", score)
+	}
+}
@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Bounds for the number of reply suggestions generated per request
+const (
+	DefaultSuggestionCount = 3
+	MinSuggestionCount     = 1
+	MaxSuggestionCount     = 10
+)
+
+// SuggestionCountMax returns SUGGESTION_COUNT_MAX, capped at MaxSuggestionCount
+func SuggestionCountMax() int {
+	if v, err := strconv.Atoi(os.Getenv("SUGGESTION_COUNT_MAX")); err == nil && v >= MinSuggestionCount && v <= MaxSuggestionCount {
+		return v
+	}
+	return MaxSuggestionCount
+}
+
+// SuggestionCountDefault returns SUGGESTION_COUNT_DEFAULT for tenants without their own setting,
+// falling back to 3 and never exceeding SuggestionCountMax
+func SuggestionCountDefault() int {
+	count := DefaultSuggestionCount
+	if v, err := strconv.Atoi(os.Getenv("SUGGESTION_COUNT_DEFAULT")); err == nil && v >= MinSuggestionCount {
+		count = v
+	}
+	if max := SuggestionCountMax(); count > max {
+		count = max
+	}
+	return count
+}
+
+// ValidateSuggestionCount checks that count is between 1 and SuggestionCountMax
+func ValidateSuggestionCount(count int) error {
+	if max := SuggestionCountMax(); count < MinSuggestionCount || count > max {
+		return fmt.Errorf("suggestions_count must be between %d and %d", MinSuggestionCount, max)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// summaryRefreshMessages is how many messages may arrive after a summary before it is regenerated
+	summaryRefreshMessages = 5
+	// maxSummaryWords caps the length of a summary
+	maxSummaryWords = 150
+	// maxSummaryInputChars caps the conversation text sent to the model; longer conversations
+	// are summarized from their latest messages
+	maxSummaryInputChars = 30000
+)
+
+// ErrNothingToSummarize is returned for conversations without messages
+var ErrNothingToSummarize = errors.New("conversation has no messages to summarize")
+
+// ConversationSummaryStore caches conversation summaries (see postgres.ConversationSummaryStorage)
+type ConversationSummaryStore interface {
+	GetSummary(tenantID, conversationID string) (*postgres.ConversationSummary, error)
+	SaveSummary(summary *postgres.ConversationSummary) error
+}
+
+// Summarizer writes short bullet-point summaries of conversations for agents. Summaries are
+// cached and only regenerated once more than summaryRefreshMessages new messages have arrived.
+type Summarizer struct {
+	analyzer *Analyzer
+	store    ConversationSummaryStore
+}
+
+// NewSummarizer creates a summarizer that generates with the analyzer's tenant clients
+func NewSummarizer(analyzer *Analyzer, store ConversationSummaryStore) *Summarizer {
+	return &Summarizer{analyzer: analyzer, store: store}
+}
+
+// Summarize returns the conversation's summary, from the cache while it still covers all but
+// summaryRefreshMessages of the messages. Deleted messages are left out.
+func (s *Summarizer) Summarize(ctx context.Context, tenantID, conversationID string, messages []*models.Message) (*postgres.ConversationSummary, error) {
+	messages = activeMessages(messages)
+	if len(messages) == 0 {
+		return nil, ErrNothingToSummarize
+	}
+
+	cached, err := s.store.GetSummary(tenantID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && len(messages) <= cached.MessageCountWhenGenerated+summaryRefreshMessages {
+		return cached, nil
+	}
+
+	conversationText := s.analyzer.buildConversationText(messages)
+	if len(conversationText) > maxSummaryInputChars {
+		conversationText = strings.ToValidUTF8(conversationText[len(conversationText)-maxSummaryInputChars:], "")
+	}
+	prompt := fmt.Sprintf(`Summarize this sales conversation for the agent taking it over.
+Write at most 5 short bullet points starting with "- ", %d words in total at most. Cover what the
+customer wants, their concerns or objections, what was offered or agreed, and any open next steps.
+Respond ONLY with the bullet points.
+
+Conversation:
+%s`, maxSummaryWords, conversationText)
+
+	resp, err := s.analyzer.clientFor(tenantID).GenerateTextContext(ctx, GenerateTextRequest{Prompt: prompt})
+	if err != nil {
+		return nil, fmt.Errorf("summary generation failed: %w", err)
+	}
+	RecordUsage(s.analyzer.usageRecorder, tenantID, UsageConversationSummary)
+
+	summaryText := limitWords(strings.TrimSpace(resp.Text), maxSummaryWords)
+	if summaryText == "" {
+		return nil, errors.New("summary generation returned no text")
+	}
+	summary := &postgres.ConversationSummary{
+		ConversationID:            conversationID,
+		SummaryText:               summaryText,
+		MessageCountWhenGenerated: len(messages),
+	}
+	if err := s.store.SaveSummary(summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// limitWords cuts text after maxWords words, keeping its line breaks. Bullet markers aren't words.
+func limitWords(text string, maxWords int) string {
+	words := 0
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		for j, field := range fields {
+			if field == "-" || field == "*" || field == "•" {
+				continue
+			}
+			if words == maxWords {
+				lines[i] = strings.Join(fields[:j], " ") + "…"
+				return strings.Join(lines[:i+1], "\n")
+			}
+			words++
+		}
+	}
+	return text
+}
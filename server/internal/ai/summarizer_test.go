@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeSummaryStore struct {
+	summaries map[string]*postgres.ConversationSummary
+	saves     int
+}
+
+func (f *fakeSummaryStore) GetSummary(tenantID, conversationID string) (*postgres.ConversationSummary, error) {
+	return f.summaries[conversationID], nil
+}
+
+func (f *fakeSummaryStore) SaveSummary(summary *postgres.ConversationSummary) error {
+	f.saves++
+	f.summaries[summary.ConversationID] = summary
+	return nil
+}
+
+// countingGenerator answers every prompt with text and counts the calls
+type countingGenerator struct {
+	promptRecorder
+	calls int
+}
+
+func (g *countingGenerator) GenerateTextContext(ctx context.Context, req GenerateTextRequest) (*GenerateTextResponse, error) {
+	g.calls++
+	return g.promptRecorder.GenerateTextContext(ctx, req)
+}
+
+func summaryMessages(n int) []*models.Message {
+	messages := make([]*models.Message, n)
+	for i := range messages {
+		messages[i] = &models.Message{ID: fmt.Sprintf("m%d", i), Sender: "customer", Content: fmt.Sprintf("message %d", i)}
+	}
+	return messages
+}
+
+func TestSummarizerCachesUntilEnoughNewMessages(t *testing.T) {
+	generator := &countingGenerator{promptRecorder: promptRecorder{text: "- Wants the pro plan\n- Asked for a discount"}}
+	store := &fakeSummaryStore{summaries: map[string]*postgres.ConversationSummary{}}
+	s := NewSummarizer(&Analyzer{generator: generator}, store)
+	ctx := context.Background()
+
+	summary, err := s.Summarize(ctx, "tenant-1", "conv-1", summaryMessages(10))
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if summary.SummaryText != "- Wants the pro plan\n- Asked for a discount" || summary.MessageCountWhenGenerated != 10 {
+		t.Fatalf("summary = %+v, want the generated summary of 10 messages", summary)
+	}
+	if !strings.Contains(generator.prompt, "customer: message 9") {
+		t.Errorf("prompt does not include the conversation: %s", generator.prompt)
+	}
+
+	// Up to 5 new messages reuse the cached summary
+	for _, n := range []int{10, 12, 15} {
+		if _, err := s.Summarize(ctx, "tenant-1", "conv-1", summaryMessages(n)); err != nil {
+			t.Fatalf("Summarize with %d messages: %v", n, err)
+		}
+	}
+	if generator.calls != 1 || store.saves != 1 {
+		t.Fatalf("generated %d times and saved %d times, want the cached summary reused", generator.calls, store.saves)
+	}
+
+	// The sixth new message invalidates it
+	generator.text = "- Agreed to a demo"
+	summary, err = s.Summarize(ctx, "tenant-1", "conv-1", summaryMessages(16))
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if generator.calls != 2 || summary.SummaryText != "- Agreed to a demo" || store.summaries["conv-1"].MessageCountWhenGenerated != 16 {
+		t.Errorf("summary = %+v after %d calls, want a regenerated summary of 16 messages", summary, generator.calls)
+	}
+}
+
+func TestSummarizerSkipsDeletedMessages(t *testing.T) {
+	generator := &countingGenerator{promptRecorder: promptRecorder{text: "- Asked about pricing"}}
+	store := &fakeSummaryStore{summaries: map[string]*postgres.ConversationSummary{}}
+	s := NewSummarizer(&Analyzer{generator: generator}, store)
+
+	deletedAt := time.Now()
+	messages := summaryMessages(3)
+	messages[1].Content = "my password is hunter2"
+	messages[1].DeletedAt = &deletedAt
+
+	summary, err := s.Summarize(context.Background(), "tenant-1", "conv-1", messages)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if summary.MessageCountWhenGenerated != 2 || strings.Contains(generator.prompt, "hunter2") {
+		t.Errorf("summary covers %d messages, prompt %q; want deleted messages left out", summary.MessageCountWhenGenerated, generator.prompt)
+	}
+
+	deletedOnly := []*models.Message{{Content: "removed", DeletedAt: &deletedAt}}
+	if _, err := s.Summarize(context.Background(), "tenant-1", "conv-2", deletedOnly); !errors.Is(err, ErrNothingToSummarize) {
+		t.Errorf("err = %v, want ErrNothingToSummarize", err)
+	}
+}
+
+func TestLimitWords(t *testing.T) {
+	if got := limitWords("- one two\n- three", 3); got != "- one two\n- three" {
+		t.Errorf("short text = %q, want it unchanged", got)
+	}
+	if got := limitWords("- one two\n- three four five\n- six", 4); got != "- one two\n- three four…" {
+		t.Errorf("long text = %q, want it cut after 4 words", got)
+	}
+}
@@ -0,0 +1,20 @@
+package ai
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans for AI provider calls and conversation analysis. It uses the global
+// tracer provider, which records nothing until main installs an exporting one.
+var tracer = otel.Tracer("ai-conversation-platform/internal/ai")
+
+// EndSpan ends span, marking it as failed when err is set
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
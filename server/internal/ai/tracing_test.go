@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestGenerateTextRequestRecordsClientSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "key=bad-key") {
+			http.Error(w, `{"error":{"message":"invalid key"}}`, http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"hello"}]}}]}`)
+	}))
+	defer server.Close()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	client := NewGeminiClientWithKey("test-key")
+	client.baseURL = server.URL
+	if _, err := client.generateTextRequest(ctx, GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err != nil {
+		t.Fatalf("generateTextRequest: %v", err)
+	}
+	badClient := NewGeminiClientWithKey("bad-key")
+	badClient.baseURL = server.URL
+	if _, err := badClient.generateTextRequest(ctx, GenerateTextRequest{Prompt: "hi"}, RetryAfterExtractor{}); err == nil {
+		t.Fatal("expected an error for a rejected request")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %d, want two Gemini calls and the request", len(spans))
+	}
+	for i, span := range spans[:2] {
+		if span.Name() != "gemini.generate_text" || span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("span %d = %s (%v), want a gemini.generate_text client span", i, span.Name(), span.SpanKind())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d isn't a child of the request span", i)
+		}
+	}
+
+	ok, failed := spans[0], spans[1]
+	if ok.Status().Code == codes.Error || !hasAttribute(ok.Attributes(), attribute.Int("http.status_code", http.StatusOK)) {
+		t.Errorf("successful call: status = %v attributes = %v, want ok with status code 200", ok.Status(), ok.Attributes())
+	}
+	if failed.Status().Code != codes.Error || len(failed.Events()) == 0 {
+		t.Errorf("failed call: status = %v events = %d, want an error status and recorded error", failed.Status(), len(failed.Events()))
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+const (
+	// transcriptChunkSize is the length in characters of each embedded transcript window
+	transcriptChunkSize = 500
+	// transcriptChunkOverlap is how many characters consecutive windows share, so an exchange
+	// split across a boundary is still embedded whole in one of them
+	transcriptChunkOverlap = 100
+)
+
+// ConversationLoader loads a conversation (to keep the embedding service decoupled from storage)
+type ConversationLoader interface {
+	GetConversation(tenantID, conversationID string) (*models.Conversation, error)
+}
+
+// SetConversationLoader provides customer and product IDs for transcript metadata (optional)
+func (s *EmbeddingService) SetConversationLoader(loader ConversationLoader) {
+	s.conversationLoader = loader
+}
+
+// EmbedConversationTranscript embeds a closed conversation's transcript in overlapping 500-character
+// chunks, replacing any chunks stored for it before. Each chunk's metadata carries the conversation,
+// customer, product and tenant IDs and when it was closed.
+func (s *EmbeddingService) EmbedConversationTranscript(tenantID, conversationID string, messages []*models.Message) error {
+	chunks := ChunkTranscript(BuildTranscript(messages), transcriptChunkSize, transcriptChunkOverlap)
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	closedAt := time.Now()
+	customerID, productID := "", ""
+	if s.conversationLoader != nil {
+		conv, err := s.conversationLoader.GetConversation(tenantID, conversationID)
+		if err != nil {
+			return fmt.Errorf("failed to load conversation: %w", err)
+		}
+		if conv.CustomerID != nil {
+			customerID = *conv.CustomerID
+		}
+		if conv.ProductID != nil {
+			productID = *conv.ProductID
+		}
+		closedAt = conv.UpdatedAt
+	}
+
+	if err := s.DeleteConversationTranscript(tenantID, conversationID); err != nil {
+		return err
+	}
+
+	collection := string(ContentTypeConversationTranscript)
+	for i, chunk := range chunks {
+		metadata := map[string]interface{}{
+			"id":              fmt.Sprintf("%s_transcript_%d", conversationID, i),
+			"conversation_id": conversationID,
+			"customer_id":     customerID,
+			"product_id":      productID,
+			"tenant_id":       tenantID,
+			"closed_at":       closedAt.UTC().Format(time.RFC3339),
+			"chunk_index":     i,
+		}
+		if err := s.EmbedAndStore(collection, chunk, ContentTypeConversationTranscript, metadata); err != nil {
+			return fmt.Errorf("failed to embed transcript chunk %d: %w", i, err)
+		}
+	}
+
+	log.Printf("[Embedding] embedded transcript tenant=%s conversation=%s chunks=%d", tenantID, conversationID, len(chunks))
+	return nil
+}
+
+// DeleteConversationTranscript removes a conversation's transcript chunks
+func (s *EmbeddingService) DeleteConversationTranscript(tenantID, conversationID string) error {
+	collection := string(ContentTypeConversationTranscript)
+	docs, err := s.chromaClient.GetDocumentsByMetadata(collection, map[string]interface{}{"tenant_id": tenantID, "conversation_id": conversationID}, vectorPageSize, 0)
+	if err != nil {
+		return fmt.Errorf("failed to find transcript chunks: %w", err)
+	}
+	if len(docs.IDs) == 0 {
+		return nil
+	}
+	if err := s.chromaClient.Delete(collection, docs.IDs); err != nil {
+		return fmt.Errorf("failed to delete transcript chunks: %w", err)
+	}
+	return nil
+}
+
+// BuildTranscript renders a conversation's messages one per line as "sender: content",
+// leaving out deleted messages
+func BuildTranscript(messages []*models.Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		if msg.DeletedAt != nil || strings.TrimSpace(msg.Content) == "" {
+			continue
+		}
+		b.WriteString(msg.Sender)
+		b.WriteString(": ")
+		b.WriteString(strings.TrimSpace(msg.Content))
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// ChunkTranscript splits text into windows of size characters, each starting size-overlap
+// characters after the previous one. Windows never split a multi-byte character.
+func ChunkTranscript(text string, size, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) == 0 || size <= 0 {
+		return nil
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	step := size - overlap
+	chunks := make([]string, 0, len(runes)/step+1)
+	for start := 0; ; start += step {
+		end := start + size
+		if end >= len(runes) {
+			chunks = append(chunks, string(runes[start:]))
+			return chunks
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+}
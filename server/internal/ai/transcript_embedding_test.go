@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestChunkTranscript(t *testing.T) {
+	text := strings.Repeat("a", 500) + strings.Repeat("b", 400) + strings.Repeat("c", 300)
+
+	chunks := ChunkTranscript(text, 500, 100)
+	if len(chunks) != 3 {
+		t.Fatalf("chunks = %d, want 3", len(chunks))
+	}
+	for i, chunk := range chunks[:2] {
+		if len([]rune(chunk)) != 500 {
+			t.Errorf("chunk %d length = %d, want 500", i, len([]rune(chunk)))
+		}
+	}
+	// Each window starts 400 characters after the previous, sharing 100 with it
+	if chunks[0][400:] != chunks[1][:100] || chunks[1][400:] != chunks[2][:100] {
+		t.Error("consecutive chunks should overlap by 100 characters")
+	}
+	if !strings.HasSuffix(chunks[2], "c") || len(chunks[2]) != 400 {
+		t.Errorf("last chunk length = %d, want the remaining 400 characters", len(chunks[2]))
+	}
+}
+
+func TestChunkTranscript_ShortAndMultiByte(t *testing.T) {
+	if chunks := ChunkTranscript("", 500, 100); chunks != nil {
+		t.Errorf("empty transcript chunks = %v, want none", chunks)
+	}
+	if chunks := ChunkTranscript("customer: hi", 500, 100); len(chunks) != 1 || chunks[0] != "customer: hi" {
+		t.Errorf("short transcript chunks = %q, want one chunk", chunks)
+	}
+
+	text := strings.Repeat("न", 12)
+	chunks := ChunkTranscript(text, 5, 2)
+	for _, chunk := range chunks {
+		if strings.ContainsRune(chunk, '�') {
+			t.Fatalf("chunk %q splits a multi-byte character", chunk)
+		}
+	}
+	if len(chunks) != 4 { // Starts at 0, 3, 6, 9
+		t.Errorf("chunks = %d, want 4", len(chunks))
+	}
+}
+
+func TestBuildTranscript(t *testing.T) {
+	deletedAt := time.Now()
+	messages := []*models.Message{
+		{Sender: "customer", Content: " What does the premium plan cost? "},
+		{Sender: "agent", Content: "It's $49 a month."},
+		{Sender: "customer", Content: "my card number is 4111", DeletedAt: &deletedAt},
+		{Sender: "customer", Content: "   "},
+		{Sender: "customer", Content: "Thanks!"},
+	}
+
+	want := "customer: What does the premium plan cost?\nagent: It's $49 a month.\ncustomer: Thanks!"
+	if got := BuildTranscript(messages); got != want {
+		t.Errorf("BuildTranscript() = %q, want %q", got, want)
+	}
+}
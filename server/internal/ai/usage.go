@@ -0,0 +1,26 @@
+package ai
+
+import "log"
+
+// AI usage operations recorded per tenant
+const (
+	UsageConversationAnalysis = "conversation_analysis"
+	UsageReplySuggestions     = "reply_suggestions"
+	UsagePricingSuggestion    = "pricing_suggestion"
+	UsageConversationSummary  = "conversation_summary"
+)
+
+// UsageRecorder records AI API calls made on behalf of a tenant
+type UsageRecorder interface {
+	RecordUsage(tenantID, operation string) error
+}
+
+// RecordUsage records a call if recorder is set. Failures are logged; usage tracking never blocks AI calls.
+func RecordUsage(recorder UsageRecorder, tenantID, operation string) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.RecordUsage(tenantID, operation); err != nil {
+		log.Printf("[AI] failed to record usage tenant=%s operation=%s error=%v", tenantID, operation, err)
+	}
+}
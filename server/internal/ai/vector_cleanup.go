@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// orphanCleanupInterval is how often the scheduled orphan cleanup runs
+const orphanCleanupInterval = 7 * 24 * time.Hour
+
+// OrphanCleanupResult summarizes an orphaned vector cleanup run
+type OrphanCleanupResult struct {
+	ScannedDocuments int      `json:"scanned_documents"`
+	OrphanedProducts []string `json:"orphaned_products"`
+	DeletedDocuments int      `json:"deleted_documents"`
+}
+
+// VectorStoreCleaner removes product knowledge vectors whose product no longer exists
+type VectorStoreCleaner struct {
+	embeddingService *EmbeddingService
+	productStorage   *postgres.ProductStorage
+	stop             chan struct{}
+	stopOnce         sync.Once
+}
+
+// NewVectorStoreCleaner creates a new vector store cleaner
+func NewVectorStoreCleaner(embeddingService *EmbeddingService, productStorage *postgres.ProductStorage) *VectorStoreCleaner {
+	return &VectorStoreCleaner{
+		embeddingService: embeddingService,
+		productStorage:   productStorage,
+		stop:             make(chan struct{}),
+	}
+}
+
+// CleanupOrphans deletes product vectors for products missing from the products table.
+// An empty tenantID scans every tenant. Documents without a product_id are left alone.
+func (c *VectorStoreCleaner) CleanupOrphans(tenantID string) (*OrphanCleanupResult, error) {
+	var where map[string]interface{}
+	if tenantID != "" {
+		where = map[string]interface{}{"tenant_id": tenantID}
+	}
+	docs, err := c.embeddingService.productDocuments(where)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan vector store: %w", err)
+	}
+
+	result := &OrphanCleanupResult{ScannedDocuments: len(docs.IDs), OrphanedProducts: []string{}}
+	existing := make(map[string]map[string]bool) // tenant -> product IDs in the database
+	orphaned := make(map[string]bool)
+	var orphanIDs []string
+	for i, id := range docs.IDs {
+		if i >= len(docs.Metadatas) {
+			break
+		}
+		docTenant, _ := docs.Metadatas[i]["tenant_id"].(string)
+		productID, _ := docs.Metadatas[i]["product_id"].(string)
+		if docTenant == "" || productID == "" {
+			continue
+		}
+
+		products, ok := existing[docTenant]
+		if !ok {
+			ids, err := c.productStorage.ListProductIDs(docTenant)
+			if err != nil {
+				return nil, err
+			}
+			products = make(map[string]bool, len(ids))
+			for _, pid := range ids {
+				products[pid] = true
+			}
+			existing[docTenant] = products
+		}
+		if products[productID] {
+			continue
+		}
+
+		orphanIDs = append(orphanIDs, id)
+		if !orphaned[productID] {
+			orphaned[productID] = true
+			result.OrphanedProducts = append(result.OrphanedProducts, productID)
+		}
+	}
+
+	for start := 0; start < len(orphanIDs); start += vectorPageSize {
+		end := start + vectorPageSize
+		if end > len(orphanIDs) {
+			end = len(orphanIDs)
+		}
+		if err := c.embeddingService.chromaClient.Delete(string(ContentTypeProductKnowledge), orphanIDs[start:end]); err != nil {
+			return result, fmt.Errorf("failed to delete orphaned vectors: %w", err)
+		}
+		result.DeletedDocuments += end - start
+	}
+
+	log.Printf("[Embedding] orphan cleanup tenant=%q scanned=%d orphaned_products=%d deleted=%d",
+		tenantID, result.ScannedDocuments, len(result.OrphanedProducts), result.DeletedDocuments)
+	return result, nil
+}
+
+// Start runs the orphan cleanup for all tenants once a week until Stop is called
+func (c *VectorStoreCleaner) Start() {
+	go func() {
+		ticker := time.NewTicker(orphanCleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := c.CleanupOrphans(""); err != nil {
+					log.Printf("[Embedding] scheduled orphan cleanup failed: %v", err)
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the cleanup scheduler
+func (c *VectorStoreCleaner) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
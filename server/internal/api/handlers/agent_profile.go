@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/agentassist"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AgentProfileHandler handles the requesting agent's suggestion profile
+type AgentProfileHandler struct {
+	profileStorage *postgres.AgentProfileStorage
+	profileBuilder *agentassist.ProfileBuilder
+}
+
+// NewAgentProfileHandler creates a new agent profile handler
+func NewAgentProfileHandler(profileStorage *postgres.AgentProfileStorage, profileBuilder *agentassist.ProfileBuilder) *AgentProfileHandler {
+	return &AgentProfileHandler{
+		profileStorage: profileStorage,
+		profileBuilder: profileBuilder,
+	}
+}
+
+// agentIdentity returns the tenant and user from the context, writing an error response if
+// the caller is a customer or they're missing
+func agentIdentity(c *gin.Context) (string, string, bool) {
+	role := c.GetString("role")
+	if role != "agent" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent access required"})
+		return "", "", false
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", "", false
+	}
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return "", "", false
+	}
+	return tenantID, userID, true
+}
+
+// GetProfile handles GET /api/agents/me/profile
+func (h *AgentProfileHandler) GetProfile(c *gin.Context) {
+	tenantID, agentID, ok := agentIdentity(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.profileStorage.GetProfile(tenantID, agentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "profile not built yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// RebuildProfile handles POST /api/agents/me/profile/rebuild
+func (h *AgentProfileHandler) RebuildProfile(c *gin.Context) {
+	tenantID, agentID, ok := agentIdentity(c)
+	if !ok {
+		return
+	}
+
+	if err := h.profileBuilder.BuildProfile(tenantID, agentID); err != nil {
+		if errors.Is(err, agentassist.ErrInsufficientHistory) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileStorage.GetProfile(tenantID, agentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
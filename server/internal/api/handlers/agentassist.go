@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -11,11 +15,12 @@ import (
 
 // AgentAssistHandler handles agent assist-related HTTP requests
 type AgentAssistHandler struct {
-	agentAssistService *agentassist.AgentAssistService
+	agentAssistService agentassist.AgentAssistServiceInterface
 }
 
-// NewAgentAssistHandler creates a new agent assist handler
-func NewAgentAssistHandler(agentAssistService *agentassist.AgentAssistService) *AgentAssistHandler {
+// NewAgentAssistHandler creates a new agent assist handler. Pass a nil interface, not a nil
+// *AgentAssistService, when AI features are disabled.
+func NewAgentAssistHandler(agentAssistService agentassist.AgentAssistServiceInterface) *AgentAssistHandler {
 	return &AgentAssistHandler{
 		agentAssistService: agentAssistService,
 	}
@@ -68,7 +73,8 @@ func (h *AgentAssistHandler) GetSuggestions(c *gin.Context) {
 		return
 	}
 
-	suggestions, err := h.agentAssistService.GetReplySuggestions(tenantID, conversationID, forceRegenerate)
+	// Suggestions are personalized to the requesting agent's writing profile
+	suggestions, err := h.agentAssistService.GetReplySuggestionsForAgent(c.Request.Context(), tenantID, conversationID, c.GetString("user_id"), forceRegenerate)
 	if err != nil {
 		log.Printf("[AGENT_ASSIST_HANDLER] error getting suggestions conversation=%s tenant=%s error=%v", conversationID, tenantID, err)
 		// Service should now always return empty suggestions on error, but handle gracefully just in case
@@ -87,6 +93,94 @@ func (h *AgentAssistHandler) GetSuggestions(c *gin.Context) {
 	})
 }
 
+// SuggestionChunk is a data frame of GET /api/conversations/:id/suggestions/stream
+type SuggestionChunk struct {
+	Text string `json:"text"`
+}
+
+// SuggestionStreamDone is the final "done" event of a suggestion stream
+type SuggestionStreamDone struct {
+	Suggestions    []agentassist.Suggestion `json:"suggestions"`
+	ContentBlocked bool                     `json:"content_blocked,omitempty"`
+}
+
+// StreamSuggestions handles GET /api/conversations/:id/suggestions/stream as server-sent events.
+// Each data frame carries the next chunk of model output ({"text": "..."}) as it is generated;
+// a final "done" event carries the parsed, moderated suggestions, or an "error" event is sent
+// if generation fails. Streamed suggestions are not cached.
+func (h *AgentAssistHandler) StreamSuggestions(c *gin.Context) {
+	conversationID := c.Param("id")
+	if conversationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "conversation_id is required"})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	role := c.GetString("role")
+	if role != "agent" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent access required"})
+		return
+	}
+
+	// Like GetSuggestions, an unconfigured service yields no suggestions rather than an error
+	var chunks <-chan string
+	var errs <-chan error
+	var err error
+	if h.agentAssistService != nil {
+		chunks, errs, err = h.agentAssistService.StreamReplySuggestions(c.Request.Context(), tenantID, conversationID, c.GetString("user_id"))
+		if err != nil && !errors.Is(err, agentassist.ErrContentBlocked) {
+			log.Printf("[AGENT_ASSIST_HANDLER] error starting suggestion stream conversation=%s tenant=%s error=%v", conversationID, tenantID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate suggestions"})
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	if h.agentAssistService == nil || err != nil {
+		writeSSE(c, "done", SuggestionStreamDone{Suggestions: []agentassist.Suggestion{}, ContentBlocked: err != nil})
+		return
+	}
+
+	var text strings.Builder
+	for chunk := range chunks {
+		text.WriteString(chunk)
+		writeSSE(c, "", SuggestionChunk{Text: chunk})
+	}
+	if err := <-errs; err != nil {
+		log.Printf("[AGENT_ASSIST_HANDLER] suggestion stream failed conversation=%s tenant=%s error=%v", conversationID, tenantID, err)
+		writeSSE(c, "error", gin.H{"error": "suggestion generation failed"})
+		return
+	}
+
+	suggestions := h.agentAssistService.FinishStreamedSuggestions(tenantID, conversationID, text.String())
+	writeSSE(c, "done", SuggestionStreamDone{Suggestions: suggestions})
+}
+
+// writeSSE writes one server-sent event with a JSON payload and flushes it to the client. An
+// empty event name sends a plain data frame.
+func writeSSE(c *gin.Context, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST_HANDLER] failed to encode stream event: %v", err)
+		return
+	}
+	if event != "" {
+		fmt.Fprintf(c.Writer, "event: %s\n", event)
+	}
+	fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+	c.Writer.Flush()
+}
+
 // GetInsightsResponse represents the response for getting insights
 type GetInsightsResponse struct {
 	Insights *agentassist.SuggestionsResponse `json:"insights"`
@@ -127,7 +221,7 @@ func (h *AgentAssistHandler) GetInsights(c *gin.Context) {
 
 	// Get insights (same as suggestions but focused on metadata)
 	// Insights don't need to bypass cache (always use cached if available)
-	suggestions, err := h.agentAssistService.GetReplySuggestions(tenantID, conversationID, false)
+	suggestions, err := h.agentAssistService.GetReplySuggestions(c.Request.Context(), tenantID, conversationID, false)
 	if err != nil {
 		log.Printf("[AGENT_ASSIST_HANDLER] error getting insights conversation=%s tenant=%s error=%v", conversationID, tenantID, err)
 		// Service should now always return empty suggestions on error, but handle gracefully just in case
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/services/agentassist"
+)
+
+// sseEvent is one parsed server-sent event
+type sseEvent struct {
+	event string
+	data  string
+}
+
+func parseSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if current.data != "" {
+				events = append(events, current)
+			}
+			current = sseEvent{}
+		case strings.HasPrefix(line, "event: "):
+			current.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		default:
+			t.Fatalf("unexpected SSE line %q", line)
+		}
+	}
+	return events
+}
+
+// newGeminiStream serves streamGenerateContent, flushing each part as its own chunked event
+func newGeminiStream(t *testing.T, parts ...string) *ai.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, part := range parts {
+			fmt.Fprintf(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":%q}]}}]}\r\n\r\n", part)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := ai.NewGeminiClientWithKey("test-key")
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestAgentAssistHandlerStreamSuggestions(t *testing.T) {
+	parts := []string{`[{"text": "Our annual plan`, ` includes\nonboarding."}]`}
+	mock := &MockAgentAssistService{
+		Stream:   newGeminiStream(t, parts...),
+		Response: &agentassist.SuggestionsResponse{Suggestions: []agentassist.Suggestion{{Text: "Our annual plan includes onboarding."}}},
+	}
+	handler := NewAgentAssistHandler(mock)
+	rec := serveHandler("/conversations/:id/suggestions/stream", http.MethodGet, "/conversations/c1/suggestions/stream",
+		testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}, handler.StreamSuggestions)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	events := parseSSE(t, rec.Body.String())
+	if len(events) != len(parts)+1 {
+		t.Fatalf("events = %+v, want %d chunks and done", events, len(parts))
+	}
+	var reassembled strings.Builder
+	for _, e := range events[:len(parts)] {
+		var chunk SuggestionChunk
+		if e.event != "" || json.Unmarshal([]byte(e.data), &chunk) != nil {
+			t.Fatalf("chunk event = %+v", e)
+		}
+		reassembled.WriteString(chunk.Text)
+	}
+	if want := strings.Join(parts, ""); reassembled.String() != want || mock.StreamedText != want {
+		t.Errorf("reassembled = %q, finished with %q; want %q", reassembled.String(), mock.StreamedText, want)
+	}
+
+	done := events[len(events)-1]
+	var result SuggestionStreamDone
+	if done.event != "done" || json.Unmarshal([]byte(done.data), &result) != nil {
+		t.Fatalf("last event = %+v, want done", done)
+	}
+	if len(result.Suggestions) != 1 || result.ContentBlocked {
+		t.Errorf("done = %+v, want the finished suggestion", result)
+	}
+	if mock.AgentID != "agent-1" {
+		t.Errorf("agent = %q, want agent-1", mock.AgentID)
+	}
+}
+
+func TestAgentAssistHandlerStreamSuggestionsRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity testContext
+		mock     *MockAgentAssistService
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "customers cannot stream suggestions",
+			identity: testContext{tenantID: "tenant-1", userID: "cust-1", role: "customer"},
+			mock:     &MockAgentAssistService{},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "missing tenant",
+			identity: testContext{userID: "agent-1", role: "agent"},
+			mock:     &MockAgentAssistService{},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "generation cannot start",
+			identity: testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"},
+			mock:     &MockAgentAssistService{StreamErr: errors.New("gemini unavailable")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "blocked input ends the stream without suggestions",
+			identity: testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"},
+			mock:     &MockAgentAssistService{StreamErr: agentassist.ErrContentBlocked},
+			wantCode: http.StatusOK,
+			wantBody: "event: done\ndata: {\"suggestions\":[],\"content_blocked\":true}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAgentAssistHandler(tt.mock)
+			rec := serveHandler("/conversations/:id/suggestions/stream", http.MethodGet, "/conversations/c1/suggestions/stream", tt.identity, handler.StreamSuggestions)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAgentAssistHandlerStreamSuggestionsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"quota"}}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	client := ai.NewGeminiClientWithKey("test-key")
+	client.SetBaseURL(server.URL)
+
+	handler := NewAgentAssistHandler(&MockAgentAssistService{Stream: client})
+	rec := serveHandler("/conversations/:id/suggestions/stream", http.MethodGet, "/conversations/c1/suggestions/stream",
+		testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}, handler.StreamSuggestions)
+
+	events := parseSSE(t, rec.Body.String())
+	if len(events) != 1 || events[0].event != "error" || strings.Contains(events[0].data, "quota") {
+		t.Errorf("events = %+v, want one generic error event", events)
+	}
+}
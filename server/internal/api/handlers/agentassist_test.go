@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ai-conversation-platform/internal/services/agentassist"
+)
+
+func TestAgentAssistHandlerGetSuggestions(t *testing.T) {
+	suggestions := &agentassist.SuggestionsResponse{
+		Suggestions: []agentassist.Suggestion{{Text: "Happy to help with that."}},
+		ContextUsed: true,
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		identity        testContext
+		mock            *MockAgentAssistService
+		wantCode        int
+		wantSuggestions int
+		wantRegenerate  bool
+	}{
+		{
+			name:            "personalized for the requesting agent",
+			path:            "/conversations/c1/suggestions",
+			identity:        testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"},
+			mock:            &MockAgentAssistService{Response: suggestions},
+			wantCode:        http.StatusOK,
+			wantSuggestions: 1,
+		},
+		{
+			name:            "regenerate bypasses cache",
+			path:            "/conversations/c1/suggestions?regenerate=true",
+			identity:        testContext{tenantID: "tenant-1", userID: "agent-1", role: "admin"},
+			mock:            &MockAgentAssistService{Response: suggestions},
+			wantCode:        http.StatusOK,
+			wantSuggestions: 1,
+			wantRegenerate:  true,
+		},
+		{
+			name:     "service error degrades to no suggestions",
+			path:     "/conversations/c1/suggestions",
+			identity: testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"},
+			mock:     &MockAgentAssistService{Err: errors.New("gemini unavailable")},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "customers are rejected",
+			path:     "/conversations/c1/suggestions",
+			identity: testContext{tenantID: "tenant-1", userID: "customer-1", role: "customer"},
+			mock:     &MockAgentAssistService{Response: suggestions},
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAgentAssistHandler(tt.mock)
+			rec := serveHandler("/conversations/:id/suggestions", http.MethodGet, tt.path, tt.identity, handler.GetSuggestions)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if tt.mock.AgentID != "" {
+					t.Error("service called for a rejected request")
+				}
+				return
+			}
+			if tt.mock.AgentID != tt.identity.userID || tt.mock.ForceRegenerate != tt.wantRegenerate {
+				t.Errorf("service called with agent=%q regenerate=%v, want %q %v", tt.mock.AgentID, tt.mock.ForceRegenerate, tt.identity.userID, tt.wantRegenerate)
+			}
+			var resp GetSuggestionsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Suggestions == nil || len(resp.Suggestions.Suggestions) != tt.wantSuggestions {
+				t.Errorf("suggestions = %+v, want %d", resp.Suggestions, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestAgentAssistHandlerWithoutService(t *testing.T) {
+	handler := NewAgentAssistHandler(nil)
+	rec := serveHandler("/conversations/:id/suggestions", http.MethodGet, "/conversations/c1/suggestions",
+		testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}, handler.GetSuggestions)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 with empty suggestions", rec.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AIConfigHandler handles tenant AI analysis configuration
+type AIConfigHandler struct {
+	aiConfigStorage *postgres.AIConfigStorage
+}
+
+// NewAIConfigHandler creates a new AI config handler
+func NewAIConfigHandler(aiConfigStorage *postgres.AIConfigStorage) *AIConfigHandler {
+	return &AIConfigHandler{aiConfigStorage: aiConfigStorage}
+}
+
+// IntentConfigResponse represents the tenant's analysis intents
+type IntentConfigResponse struct {
+	Intents      []string          `json:"intents"`
+	Descriptions map[string]string `json:"descriptions,omitempty"`
+	Custom       bool              `json:"custom"` // false when the default intents apply
+}
+
+// GetIntentConfig handles GET /api/admin/intent-config (admin only)
+func (h *AIConfigHandler) GetIntentConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	config, err := h.aiConfigStorage.GetIntentConfig(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if config == nil {
+		c.JSON(http.StatusOK, IntentConfigResponse{Intents: ai.DefaultIntents, Custom: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, IntentConfigResponse{Intents: config.Intents, Descriptions: config.Descriptions, Custom: true})
+}
+
+// UpdateIntentConfig handles PUT /api/admin/intent-config (admin only)
+func (h *AIConfigHandler) UpdateIntentConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req postgres.IntentConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for i, intent := range req.Intents {
+		req.Intents[i] = strings.TrimSpace(intent)
+	}
+	if err := ai.ValidateIntentConfig(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.aiConfigStorage.SetIntentConfig(tenantID, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, IntentConfigResponse{Intents: req.Intents, Descriptions: req.Descriptions, Custom: true})
+}
+
+// ResetIntentConfig handles DELETE /api/admin/intent-config (admin only), restoring the default intents
+func (h *AIConfigHandler) ResetIntentConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.aiConfigStorage.ClearIntentConfig(tenantID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, IntentConfigResponse{Intents: ai.DefaultIntents, Custom: false})
+}
+
+// SuggestionConfigRequest represents the request body for updating the suggestion count
+type SuggestionConfigRequest struct {
+	SuggestionsCount int `json:"suggestions_count" binding:"required"`
+}
+
+// SuggestionConfigResponse represents the tenant's reply suggestion count
+type SuggestionConfigResponse struct {
+	SuggestionsCount int  `json:"suggestions_count"`
+	Max              int  `json:"max"`
+	Custom           bool `json:"custom"` // false when SUGGESTION_COUNT_DEFAULT applies
+}
+
+// GetSuggestionConfig handles GET /api/admin/suggestion-config (admin only)
+func (h *AIConfigHandler) GetSuggestionConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	count, err := h.aiConfigStorage.GetSuggestionCount(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if count == nil {
+		c.JSON(http.StatusOK, SuggestionConfigResponse{SuggestionsCount: ai.SuggestionCountDefault(), Max: ai.SuggestionCountMax(), Custom: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuggestionConfigResponse{SuggestionsCount: *count, Max: ai.SuggestionCountMax(), Custom: true})
+}
+
+// UpdateSuggestionConfig handles PUT /api/admin/suggestion-config (admin only)
+func (h *AIConfigHandler) UpdateSuggestionConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req SuggestionConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ai.ValidateSuggestionCount(req.SuggestionsCount); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.aiConfigStorage.SetSuggestionCount(tenantID, req.SuggestionsCount); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuggestionConfigResponse{SuggestionsCount: req.SuggestionsCount, Max: ai.SuggestionCountMax(), Custom: true})
+}
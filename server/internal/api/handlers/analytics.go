@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"ai-conversation-platform/internal/ai"
 	"ai-conversation-platform/internal/services/analytics"
 	"ai-conversation-platform/internal/services/conversation"
 	"ai-conversation-platform/internal/storage/postgres"
@@ -13,21 +20,24 @@ import (
 
 // AnalyticsHandler handles analytics-related HTTP requests
 type AnalyticsHandler struct {
-	analyticsService   *analytics.AnalyticsService
+	analyticsService   analytics.AnalyticsServiceInterface
 	ingestionService   *conversation.IngestionService
 	userStorage        *postgres.UserStorage
+	dashboardUpdates   *conversation.DashboardUpdates
+	dashboardInterval  time.Duration // How often the dashboard stream refreshes without updates
 }
 
 // NewAnalyticsHandler creates a new analytics handler
 func NewAnalyticsHandler(
-	analyticsService *analytics.AnalyticsService,
+	analyticsService analytics.AnalyticsServiceInterface,
 	ingestionService *conversation.IngestionService,
 	userStorage *postgres.UserStorage,
 ) *AnalyticsHandler {
 	return &AnalyticsHandler{
 		analyticsService: analyticsService,
 		ingestionService: ingestionService,
-		userStorage:      userStorage,
+		userStorage:       userStorage,
+		dashboardInterval: defaultDashboardStreamInterval,
 	}
 }
 
@@ -54,8 +64,8 @@ func (h *AnalyticsHandler) GetLeads(c *gin.Context) {
 			conversationIDs[i] = strings.TrimSpace(conversationIDs[i])
 		}
 	} else {
-		// Fetch all conversations for tenant (nil customerID for admin/agent access to all conversations)
-		conversations, err := h.ingestionService.ListConversations(tenantID, nil, 1000, 0)
+		// Fetch all conversations for tenant (nil filters for admin/agent access to all conversations)
+		conversations, _, err := h.ingestionService.ListConversations(tenantID, nil, 1000, "")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -79,7 +89,19 @@ func (h *AnalyticsHandler) GetLeads(c *gin.Context) {
 		return
 	}
 
-	// Populate customer emails for leads
+	h.populateCustomerEmails(tenantID, leads)
+
+	c.JSON(http.StatusOK, GetLeadsResponse{
+		Leads: leads,
+		Total: len(leads),
+	})
+}
+
+// populateCustomerEmails fills in the customer email for each lead
+func (h *AnalyticsHandler) populateCustomerEmails(tenantID string, leads []analytics.PrioritizedLead) {
+	if h.ingestionService == nil || h.userStorage == nil {
+		return
+	}
 	for i := range leads {
 		// Get conversation to find customer_id
 		conv, _, err := h.ingestionService.GetConversation(tenantID, leads[i].ConversationID)
@@ -90,11 +112,6 @@ func (h *AnalyticsHandler) GetLeads(c *gin.Context) {
 			}
 		}
 	}
-
-	c.JSON(http.StatusOK, GetLeadsResponse{
-		Leads: leads,
-		Total: len(leads),
-	})
 }
 
 // GetWinProbabilityResponse represents the response for win probability
@@ -213,10 +230,23 @@ func (h *AnalyticsHandler) GetTrends(c *gin.Context) {
 		return
 	}
 
-	// Get trends through analytics service
-	// Note: This requires accessing messages and metadata, so we'll need to add a method to analytics service
-	// For now, we'll calculate it inline
-	trends, err := h.analyticsService.GetTrends(tenantID, conversationID)
+	// Optional window_config overrides the configured trend windows
+	var trends analytics.TrendAnalysis
+	var err error
+	if encoded := c.Query("window_config"); encoded != "" {
+		config, parseErr := parseTrendWindowConfig(encoded)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": parseErr.Error()})
+			return
+		}
+		trends, err = h.analyticsService.GetTrendsWithConfig(analytics.TrendAnalysisRequest{
+			ConversationID: conversationID,
+			TenantID:       tenantID,
+			Config:         config,
+		})
+	} else {
+		trends, err = h.analyticsService.GetTrends(tenantID, conversationID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -227,6 +257,32 @@ func (h *AnalyticsHandler) GetTrends(c *gin.Context) {
 	})
 }
 
+// parseTrendWindowConfig decodes the window_config query param: a base64-encoded (standard or
+// URL-safe, padding optional) JSON TrendWindowConfig
+func parseTrendWindowConfig(encoded string) (analytics.TrendWindowConfig, error) {
+	var config analytics.TrendWindowConfig
+	var raw []byte
+	var err error
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if raw, err = encoding.DecodeString(encoded); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return config, fmt.Errorf("window_config must be base64-encoded JSON")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, fmt.Errorf("invalid window_config: %v", err)
+	}
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid window_config: %w", err)
+	}
+	return config, nil
+}
+
 // GetCLVResponse represents the response for CLV
 type GetCLVResponse struct {
 	CLV analytics.CLVEstimate `json:"clv"`
@@ -293,6 +349,7 @@ type GetDashboardResponse struct {
 }
 
 // GetDashboard handles GET /api/analytics/dashboard
+// Query params: start_date, end_date (RFC3339, inclusive; default to all time), status
 func (h *AnalyticsHandler) GetDashboard(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 	if tenantID == "" {
@@ -300,8 +357,14 @@ func (h *AnalyticsHandler) GetDashboard(c *gin.Context) {
 		return
 	}
 
+	filters, err := parseDashboardFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get dashboard metrics
-	metrics, err := h.analyticsService.GetDashboardMetrics(tenantID)
+	metrics, err := h.analyticsService.GetDashboardMetrics(tenantID, filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -312,3 +375,363 @@ func (h *AnalyticsHandler) GetDashboard(c *gin.Context) {
 	})
 }
 
+
+// GetLeaderboardResponse represents the response for the agent leaderboard
+type GetLeaderboardResponse struct {
+	Entries []analytics.AgentLeaderboardEntry `json:"entries"`
+	MyRank  *analytics.AgentLeaderboardEntry  `json:"my_rank,omitempty"`
+	From    time.Time                         `json:"from"`
+	To      time.Time                         `json:"to"`
+	SortBy  string                            `json:"sort_by"`
+}
+
+// GetLeaderboard handles GET /api/analytics/leaderboard (Admin only)
+// Query params: sort_by, limit, from, to (RFC3339, defaults to the last 30 days)
+func (h *AnalyticsHandler) GetLeaderboard(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort_by", analytics.LeaderboardSortScore)
+	if !analytics.IsValidLeaderboardSort(sortBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort_by"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	from, to, err := parseTimeRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.analyticsService.GetLeaderboard(tenantID, from, to, sortBy, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	myRank, err := h.analyticsService.FindLeaderboardEntry(tenantID, from, to, sortBy, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, GetLeaderboardResponse{
+		Entries: entries,
+		MyRank:  myRank,
+		From:    from,
+		To:      to,
+		SortBy:  sortBy,
+	})
+}
+
+// GetComplexityResponse represents the response for conversation complexity
+type GetComplexityResponse struct {
+	ConversationID string             `json:"conversation_id"`
+	Complexity     ai.ComplexityScore `json:"complexity"`
+	Bucket         string             `json:"bucket"`
+}
+
+// GetComplexity handles GET /api/conversations/:id/complexity
+func (h *AnalyticsHandler) GetComplexity(c *gin.Context) {
+	conversationID := c.Param("id")
+	if conversationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "conversation_id is required"})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	// Verify the conversation belongs to the tenant before scoring it
+	if _, _, err := h.ingestionService.GetConversation(tenantID, conversationID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	complexity, err := h.analyticsService.GetComplexity(tenantID, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, GetComplexityResponse{
+		ConversationID: conversationID,
+		Complexity:     complexity,
+		Bucket:         ai.ComplexityBucket(complexity.Score),
+	})
+}
+
+// GetComplexityDistribution handles GET /api/analytics/complexity-distribution
+func (h *AnalyticsHandler) GetComplexityDistribution(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	distribution, err := h.analyticsService.GetComplexityDistribution(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"distribution": distribution})
+}
+
+// GetSuggestionAcceptanceRate handles GET /api/analytics/suggestions/acceptance-rate
+// The rate is the share (0-1) of suggestion feedback where agents used the suggestion, as is or edited.
+func (h *AnalyticsHandler) GetSuggestionAcceptanceRate(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	rate, err := h.analyticsService.GetSuggestionAcceptanceRate(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"acceptance_rate": rate})
+}
+
+// GetObjectionResolutionRates handles GET /api/analytics/objections/resolution-rates
+// Rates are per objection type: the share (0-1) of conversations raising it where an agent resolved it.
+func (h *AnalyticsHandler) GetObjectionResolutionRates(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	rates, err := h.analyticsService.GetObjectionResolutionRate(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolution_rates": rates})
+}
+
+// GetCustomerSegments handles GET /api/analytics/customer-segments
+// Each segment has its customer count, a few representative customer IDs and suggested actions.
+func (h *AnalyticsHandler) GetCustomerSegments(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	segments, err := h.analyticsService.SegmentCustomers(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"segments": segments})
+}
+
+// GetLanguageDistributionResponse represents the response for the customer language breakdown
+type GetLanguageDistributionResponse struct {
+	Languages []analytics.LanguageDistribution `json:"languages"`
+	From      time.Time                        `json:"from"`
+	To        time.Time                        `json:"to"`
+}
+
+// GetLanguageDistribution handles GET /api/analytics/languages
+// Query params: from, to (RFC3339, defaults to the last 30 days)
+func (h *AnalyticsHandler) GetLanguageDistribution(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	languages, err := h.analyticsService.GetLanguageDistribution(tenantID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, GetLanguageDistributionResponse{Languages: languages, From: from, To: to})
+}
+
+// GetMixedLanguageConversations handles GET /api/analytics/languages/mixed-conversations
+func (h *AnalyticsHandler) GetMixedLanguageConversations(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	conversations, err := h.analyticsService.GetMixedLanguageConversations(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversations": conversations, "total": len(conversations)})
+}
+
+// GetDwellTime handles GET /api/analytics/dwell-time
+func (h *AnalyticsHandler) GetDwellTime(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	dwell, err := h.analyticsService.GetAverageDwellTime(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"average_dwell_hours": dwell})
+}
+
+// GetSLABreaches handles GET /api/analytics/sla-breaches
+// Query params: from, to (RFC3339, defaults to the last 30 days), matched against the response deadline
+func (h *AnalyticsHandler) GetSLABreaches(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.analyticsService.GetSLABreachSummary(tenantID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetAgentPerformanceResponse represents the response for an agent's performance
+type GetAgentPerformanceResponse struct {
+	Performance analytics.AgentPerformance `json:"performance"`
+}
+
+// GetAgentPerformance handles GET /api/analytics/agents/:agent_id/performance
+// Agents can only see their own performance; admins can see any agent's.
+// Query params: from, to (RFC3339, defaults to the last 30 days)
+func (h *AnalyticsHandler) GetAgentPerformance(c *gin.Context) {
+	tenantID, userID, ok := agentIdentity(c)
+	if !ok {
+		return
+	}
+
+	agentID := c.Param("agent_id")
+	if agentID != userID && c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agents can only view their own performance"})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	performance, err := h.analyticsService.GetAgentPerformance(tenantID, agentID, from, to)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, GetAgentPerformanceResponse{
+		Performance: performance,
+	})
+}
+
+// parseDashboardFilters reads the dashboard's start_date, end_date and status query params
+func parseDashboardFilters(c *gin.Context) (analytics.DashboardFilters, error) {
+	filters := analytics.DashboardFilters{Status: c.Query("status")}
+	switch filters.Status {
+	case "", "active", "closed", "archived":
+	default:
+		return filters, fmt.Errorf("invalid status, expected active, closed or archived")
+	}
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return filters, fmt.Errorf("invalid start_date, expected RFC3339")
+		}
+		filters.StartDate = parsed
+	}
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return filters, fmt.Errorf("invalid end_date, expected RFC3339")
+		}
+		filters.EndDate = parsed
+	}
+	if !filters.StartDate.IsZero() && !filters.EndDate.IsZero() && filters.StartDate.After(filters.EndDate) {
+		return filters, fmt.Errorf("start_date must be before end_date")
+	}
+	return filters, nil
+}
+
+// parseTimeRange reads RFC3339 from/to query params. to defaults to now and
+// from defaults to defaultDays before to.
+func parseTimeRange(c *gin.Context, defaultDays int) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to, expected RFC3339")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -defaultDays)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from, expected RFC3339")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
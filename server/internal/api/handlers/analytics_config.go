@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+)
+
+// AnalyticsConfigService reads and updates the analytics weights and thresholds a tenant scores with
+type AnalyticsConfigService interface {
+	TenantConfig(tenantID string) analytics.AnalyticsConfig
+	UpdateTenantConfig(tenantID string, config analytics.AnalyticsConfig) error
+}
+
+// AnalyticsConfigHandler handles per-tenant analytics config requests
+type AnalyticsConfigHandler struct {
+	service AnalyticsConfigService
+}
+
+// NewAnalyticsConfigHandler creates a new analytics config handler
+func NewAnalyticsConfigHandler(service AnalyticsConfigService) *AnalyticsConfigHandler {
+	return &AnalyticsConfigHandler{service: service}
+}
+
+// GetAnalyticsConfig handles GET /api/admin/analytics-config (admin only)
+func (h *AnalyticsConfigHandler) GetAnalyticsConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.TenantConfig(tenantID))
+}
+
+// UpdateAnalyticsConfig handles PUT /api/admin/analytics-config (admin only)
+// Fields omitted from the body keep their current values. Each component's weights must sum to 1.
+func (h *AnalyticsConfigHandler) UpdateAnalyticsConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	config := h.service.TenantConfig(tenantID)
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateTenantConfig(tenantID, config); err != nil {
+		if errors.Is(err, analytics.ErrInvalidAnalyticsConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.TenantConfig(tenantID))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+)
+
+type fakeAnalyticsConfigService struct {
+	configs map[string]analytics.AnalyticsConfig
+	err     error
+}
+
+func (f *fakeAnalyticsConfigService) TenantConfig(tenantID string) analytics.AnalyticsConfig {
+	if config, ok := f.configs[tenantID]; ok {
+		return config
+	}
+	return analytics.DefaultAnalyticsConfig()
+}
+
+func (f *fakeAnalyticsConfigService) UpdateTenantConfig(tenantID string, config analytics.AnalyticsConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if f.err != nil {
+		return f.err
+	}
+	f.configs[tenantID] = config
+	return nil
+}
+
+func serveAnalyticsConfig(service AnalyticsConfigService, method, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewAnalyticsConfigHandler(service)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.GET("/api/admin/analytics-config", handler.GetAnalyticsConfig)
+	engine.PUT("/api/admin/analytics-config", handler.UpdateAnalyticsConfig)
+
+	req := httptest.NewRequest(method, "/api/admin/analytics-config", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAnalyticsConfigHandlerUpdateAnalyticsConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{name: "partial update", body: `{"lead_score_intent_weight": 0.6, "lead_score_engagement_weight": 0.2, "lead_score_sentiment_weight": 0.2}`, wantCode: http.StatusOK},
+		{name: "weights not summing to 1", body: `{"lead_score_intent_weight": 0.9}`, wantCode: http.StatusBadRequest},
+		{name: "threshold out of range", body: `{"churn_risk_threshold": 1.5}`, wantCode: http.StatusBadRequest},
+		{name: "malformed body", body: `{"lead_score_intent_weight": "high"}`, wantCode: http.StatusBadRequest},
+		{name: "storage error", body: `{"churn_risk_threshold": 0.5}`, err: errors.New("db down"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeAnalyticsConfigService{configs: map[string]analytics.AnalyticsConfig{}, err: tt.err}
+			rec := serveAnalyticsConfig(service, http.MethodPut, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				if _, ok := service.configs["tenant-1"]; ok {
+					t.Error("rejected config was stored")
+				}
+				return
+			}
+
+			var got map[string]float64
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			defaults := analytics.DefaultAnalyticsConfig()
+			if got["lead_score_intent_weight"] != 0.6 || got["win_prob_intent_weight"] != defaults.WinProbIntentWeight {
+				t.Errorf("config = %v, want the new lead score weights and the default win probability weights", got)
+			}
+		})
+	}
+}
+
+func TestAnalyticsConfigHandlerGetAnalyticsConfig(t *testing.T) {
+	config := analytics.DefaultAnalyticsConfig()
+	config.ChurnRiskThreshold = 0.55
+	service := &fakeAnalyticsConfigService{configs: map[string]analytics.AnalyticsConfig{"tenant-1": config}}
+
+	rec := serveAnalyticsConfig(service, http.MethodGet, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got map[string]float64
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got["churn_risk_threshold"] != 0.55 {
+		t.Errorf("churn_risk_threshold = %v, want the tenant's 0.55", got["churn_risk_threshold"])
+	}
+}
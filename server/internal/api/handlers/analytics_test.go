@@ -0,0 +1,763 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+)
+
+var analyticsAgent = testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}
+
+func TestAnalyticsHandlerGetLeads(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		identity  testContext
+		mock      *MockAnalyticsService
+		wantCode  int
+		wantTotal int
+		wantIDs   []string
+	}{
+		{
+			name:      "prioritizes requested conversations",
+			path:      "/leads?conversation_ids=c1,%20c2",
+			identity:  analyticsAgent,
+			mock:      &MockAnalyticsService{Leads: []analytics.PrioritizedLead{{ConversationID: "c2"}, {ConversationID: "c1"}}},
+			wantCode:  http.StatusOK,
+			wantTotal: 2,
+			wantIDs:   []string{"c1", "c2"},
+		},
+		{
+			name:     "service error",
+			path:     "/leads?conversation_ids=c1",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("database unavailable")},
+			wantCode: http.StatusInternalServerError,
+			wantIDs:  []string{"c1"},
+		},
+		{
+			name:     "missing tenant",
+			path:     "/leads?conversation_ids=c1",
+			identity: testContext{role: "agent"},
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/leads", http.MethodGet, tt.path, tt.identity, handler.GetLeads)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !reflect.DeepEqual(tt.mock.LeadIDs, tt.wantIDs) {
+				t.Errorf("PrioritizeLeads ids = %v, want %v", tt.mock.LeadIDs, tt.wantIDs)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetLeadsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Total != tt.wantTotal || len(resp.Leads) != tt.wantTotal {
+				t.Errorf("total = %d with %d leads, want %d", resp.Total, len(resp.Leads), tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerExportLeads(t *testing.T) {
+	email := `"Sam, Jr."@example.com`
+	stage := "negotiation"
+	action := "Call back today,\nthen send the \"enterprise\" quote"
+	mock := &MockAnalyticsService{Leads: []analytics.PrioritizedLead{
+		{
+			ConversationID:    "c1",
+			CustomerEmail:     &email,
+			WinProbability:    0.85,
+			UrgencyScore:      72.5,
+			DealValue:         12000,
+			PriorityScore:     91.25,
+			LeadStage:         &stage,
+			RecommendedAction: &action,
+			RiskFlags:         []string{"price_objection", "silent, 3 days"},
+			Engagement:        &analytics.EngagementMetrics{LastMessageTime: "2026-10-15T09:30:00Z"},
+		},
+		{ConversationID: "c2"},
+	}}
+	handler := NewAnalyticsHandler(mock, nil, nil)
+
+	rec := serveHandler("/leads/export", http.MethodGet, "/leads/export?format=csv", analyticsAgent, handler.ExportLeads)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	wantDisposition := `attachment; filename="leads_` + time.Now().Format("2006-01-02") + `.csv"`
+	if got := rec.Header().Get("Content-Disposition"); got != wantDisposition {
+		t.Errorf("Content-Disposition = %q, want %q", got, wantDisposition)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v\n%s", err, rec.Body.String())
+	}
+	want := [][]string{
+		{"conversation_id", "customer_email", "win_probability", "urgency_score", "deal_value",
+			"priority_score", "lead_stage", "recommended_action", "risk_flags", "last_message_time"},
+		{"c1", email, "0.85", "72.5", "12000", "91.25", stage, action, "price_objection;silent, 3 days", "2026-10-15T09:30:00Z"},
+		{"c2", "", "0", "0", "0", "0", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+
+	if rec := serveHandler("/leads/export", http.MethodGet, "/leads/export?format=xlsx", analyticsAgent, handler.ExportLeads); rec.Code != http.StatusBadRequest {
+		t.Errorf("unsupported format status = %d, want 400", rec.Code)
+	}
+
+	empty := serveHandler("/leads/export", http.MethodGet, "/leads/export", analyticsAgent, NewAnalyticsHandler(&MockAnalyticsService{}, nil, nil).ExportLeads)
+	if got := strings.TrimSpace(empty.Body.String()); !strings.HasPrefix(got, "conversation_id,") || strings.Contains(got, "\n") {
+		t.Errorf("empty export = %q, want just the header row", got)
+	}
+}
+
+// serveExport requests the analytics export, asking for gzip when acceptGzip is set
+func serveExport(mock *MockAnalyticsService, query string, acceptGzip bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/analytics/export", func(c *gin.Context) {
+		c.Set("tenant_id", analyticsAgent.tenantID)
+		c.Set("role", "admin")
+	}, NewAnalyticsHandler(mock, nil, nil).ExportAnalytics)
+
+	req := httptest.NewRequest(http.MethodGet, "/analytics/export?"+query, nil)
+	if acceptGzip {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAnalyticsHandlerExportAnalyticsJSONFramesPagesAsOneArray(t *testing.T) {
+	mock := &MockAnalyticsService{LeadPages: [][]analytics.PrioritizedLead{
+		{{ConversationID: "c1"}, {ConversationID: "c2"}},
+		{},
+		{{ConversationID: "c3"}},
+	}}
+
+	rec := serveExport(mock, "type=leads&format=json", false)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status = %d, content type = %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	var leads []analytics.PrioritizedLead
+	if err := json.Unmarshal(rec.Body.Bytes(), &leads); err != nil {
+		t.Fatalf("body is not one JSON array: %v\n%s", err, rec.Body.String())
+	}
+	if len(leads) != 3 || leads[0].ConversationID != "c1" || leads[2].ConversationID != "c3" {
+		t.Errorf("leads = %+v, want c1, c2 and c3 across pages", leads)
+	}
+
+	empty := serveExport(&MockAnalyticsService{}, "type=leads&format=json", false)
+	if got := strings.TrimSpace(empty.Body.String()); got != "[]" {
+		t.Errorf("empty export = %q, want []", got)
+	}
+}
+
+func TestAnalyticsHandlerExportAnalyticsCSVPages(t *testing.T) {
+	mock := &MockAnalyticsService{LeadPages: [][]analytics.PrioritizedLead{
+		{{ConversationID: "c1"}},
+		{{ConversationID: "c2"}},
+	}}
+
+	rec := serveExport(mock, "type=leads", false)
+	records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v\n%s", err, rec.Body.String())
+	}
+	if len(records) != 3 || records[0][0] != "conversation_id" || records[1][0] != "c1" || records[2][0] != "c2" {
+		t.Errorf("records = %q, want one header then a row per lead", records)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "leads-"+time.Now().Format("2006-01-02")+".csv") {
+		t.Errorf("Content-Disposition = %q, want a dated csv attachment", got)
+	}
+}
+
+func TestAnalyticsHandlerExportAnalyticsGzip(t *testing.T) {
+	mock := &MockAnalyticsService{LeadPages: [][]analytics.PrioritizedLead{{{ConversationID: "c1"}}, {{ConversationID: "c2"}}}}
+
+	rec := serveExport(mock, "type=leads&format=json", true)
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("headers = %v, want a gzip-encoded response", rec.Header())
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress: %v", err)
+	}
+	var leads []analytics.PrioritizedLead
+	if err := json.Unmarshal(body, &leads); err != nil || len(leads) != 2 {
+		t.Errorf("decompressed body = %s (%v), want both leads", body, err)
+	}
+
+	// Without Accept-Encoding the export is sent as is
+	plain := serveExport(mock, "type=dashboard", false)
+	if plain.Header().Get("Content-Encoding") != "" || !strings.HasPrefix(plain.Body.String(), "total_conversations,") {
+		t.Errorf("plain export = %v %q, want uncompressed csv", plain.Header(), plain.Body.String())
+	}
+}
+
+func TestAnalyticsHandlerGetWinProbability(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity testContext
+		mock     *MockAnalyticsService
+		wantCode int
+		wantProb float64
+	}{
+		{
+			name:     "returns probability",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{WinProbability: analytics.WinProbability{ConversationID: "c1", Probability: 0.72}},
+			wantCode: http.StatusOK,
+			wantProb: 0.72,
+		},
+		{
+			name:     "service error",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/conversations/:id/win-probability", http.MethodGet, "/conversations/c1/win-probability", tt.identity, handler.GetWinProbability)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetWinProbabilityResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.WinProbability.Probability != tt.wantProb {
+				t.Errorf("probability = %v, want %v", resp.WinProbability.Probability, tt.wantProb)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetChurnRisk(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   testContext
+		mock       *MockAnalyticsService
+		wantCode   int
+		wantAtRisk bool
+	}{
+		{
+			name:       "at risk conversation",
+			identity:   analyticsAgent,
+			mock:       &MockAnalyticsService{ChurnRisk: analytics.ChurnRisk{ConversationID: "c1", RiskScore: 0.8, IsAtRisk: true}},
+			wantCode:   http.StatusOK,
+			wantAtRisk: true,
+		},
+		{
+			name:     "healthy conversation",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{ChurnRisk: analytics.ChurnRisk{ConversationID: "c1", RiskScore: 0.1}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "service error",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/conversations/:id/churn-risk", http.MethodGet, "/conversations/c1/churn-risk", tt.identity, handler.GetChurnRisk)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetChurnRiskResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.ChurnRisk.IsAtRisk != tt.wantAtRisk {
+				t.Errorf("is_at_risk = %v, want %v", resp.ChurnRisk.IsAtRisk, tt.wantAtRisk)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetDashboard(t *testing.T) {
+	tests := []struct {
+		name      string
+		identity  testContext
+		mock      *MockAnalyticsService
+		wantCode  int
+		wantTotal int
+	}{
+		{
+			name:      "returns metrics",
+			identity:  analyticsAgent,
+			mock:      &MockAnalyticsService{Dashboard: analytics.DashboardMetrics{TotalConversations: 12, WinRate: 0.25}},
+			wantCode:  http.StatusOK,
+			wantTotal: 12,
+		},
+		{
+			name:     "service error",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/dashboard", http.MethodGet, "/dashboard", tt.identity, handler.GetDashboard)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetDashboardResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Metrics.TotalConversations != tt.wantTotal {
+				t.Errorf("total_conversations = %d, want %d", resp.Metrics.TotalConversations, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetDashboardFilters(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 2, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		wantCode    int
+		wantFilters analytics.DashboardFilters
+	}{
+		{"no filters", "", http.StatusOK, analytics.DashboardFilters{}},
+		{"date range and status", "?start_date=2024-03-01T00:00:00Z&end_date=2024-03-02T23:59:59Z&status=closed", http.StatusOK,
+			analytics.DashboardFilters{StartDate: start, EndDate: end, Status: "closed"}},
+		{"start only", "?start_date=2024-03-01T00:00:00Z", http.StatusOK, analytics.DashboardFilters{StartDate: start}},
+		{"invalid start_date", "?start_date=2024-03-01", http.StatusBadRequest, analytics.DashboardFilters{}},
+		{"invalid end_date", "?end_date=yesterday", http.StatusBadRequest, analytics.DashboardFilters{}},
+		{"start after end", "?start_date=2024-03-02T23:59:59Z&end_date=2024-03-01T00:00:00Z", http.StatusBadRequest, analytics.DashboardFilters{}},
+		{"invalid status", "?status=pending", http.StatusBadRequest, analytics.DashboardFilters{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockAnalyticsService{}
+			handler := NewAnalyticsHandler(mock, nil, nil)
+			rec := serveHandler("/dashboard", http.MethodGet, "/dashboard"+tt.query, analyticsAgent, handler.GetDashboard)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !mock.DashboardFilters.StartDate.Equal(tt.wantFilters.StartDate) ||
+				!mock.DashboardFilters.EndDate.Equal(tt.wantFilters.EndDate) ||
+				mock.DashboardFilters.Status != tt.wantFilters.Status {
+				t.Errorf("filters = %+v, want %+v", mock.DashboardFilters, tt.wantFilters)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetAgentPerformance(t *testing.T) {
+	const route = "/analytics/agents/:agent_id/performance"
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+	customer := testContext{tenantID: "tenant-1", userID: "customer-1", role: "customer"}
+
+	tests := []struct {
+		name      string
+		identity  testContext
+		path      string
+		mock      *MockAnalyticsService
+		wantCode  int
+		wantAgent string // Agent the service was asked about; empty if it must not be called
+	}{
+		{"agent sees own performance", analyticsAgent, "/analytics/agents/agent-1/performance",
+			&MockAnalyticsService{Performance: analytics.AgentPerformance{TotalConversations: 4, AvgQualityScore: 60}}, http.StatusOK, "agent-1"},
+		{"agent can't see another agent", analyticsAgent, "/analytics/agents/agent-2/performance", &MockAnalyticsService{}, http.StatusForbidden, ""},
+		{"admin sees any agent", admin, "/analytics/agents/agent-2/performance", &MockAnalyticsService{}, http.StatusOK, "agent-2"},
+		{"customer forbidden", customer, "/analytics/agents/customer-1/performance", &MockAnalyticsService{}, http.StatusForbidden, ""},
+		{"agent in another tenant", admin, "/analytics/agents/agent-9/performance",
+			&MockAnalyticsService{Err: errors.New("agent not found")}, http.StatusNotFound, "agent-9"},
+		{"invalid range", analyticsAgent, "/analytics/agents/agent-1/performance?from=yesterday", &MockAnalyticsService{}, http.StatusBadRequest, ""},
+		{"service error", analyticsAgent, "/analytics/agents/agent-1/performance", &MockAnalyticsService{Err: errors.New("boom")}, http.StatusInternalServerError, "agent-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler(route, http.MethodGet, tt.path, tt.identity, handler.GetAgentPerformance)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.mock.PerformanceAgentID != tt.wantAgent {
+				t.Errorf("service called for agent %q, want %q", tt.mock.PerformanceAgentID, tt.wantAgent)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetAgentPerformanceResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Performance.AgentID != tt.wantAgent || resp.Performance.TotalConversations != tt.mock.Performance.TotalConversations {
+				t.Errorf("performance = %+v", resp.Performance)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetLanguageDistribution(t *testing.T) {
+	languages := []analytics.LanguageDistribution{
+		{Language: "en", MessageCount: 30, ConversationCount: 6, Percentage: 75},
+		{Language: "hi", MessageCount: 10, ConversationCount: 2, Percentage: 25},
+	}
+	tests := []struct {
+		name      string
+		path      string
+		identity  testContext
+		mock      *MockAnalyticsService
+		wantCode  int
+		wantCount int
+	}{
+		{
+			name:      "returns distribution",
+			path:      "/languages",
+			identity:  analyticsAgent,
+			mock:      &MockAnalyticsService{Languages: languages},
+			wantCode:  http.StatusOK,
+			wantCount: 2,
+		},
+		{
+			name:     "invalid range",
+			path:     "/languages?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "service error",
+			path:     "/languages",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			path:     "/languages",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/languages", http.MethodGet, tt.path, tt.identity, handler.GetLanguageDistribution)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetLanguageDistributionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if len(resp.Languages) != tt.wantCount {
+				t.Errorf("languages = %d, want %d", len(resp.Languages), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetSLABreaches(t *testing.T) {
+	summary := analytics.SLABreachSummary{BreachCount: 4, OpenBreaches: 1, AverageBreachSeconds: 900}
+	tests := []struct {
+		name     string
+		path     string
+		identity testContext
+		mock     *MockAnalyticsService
+		wantCode int
+	}{
+		{
+			name:     "returns summary",
+			path:     "/sla-breaches?from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{SLABreaches: summary},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid range",
+			path:     "/sla-breaches?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "service error",
+			path:     "/sla-breaches",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			path:     "/sla-breaches",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/sla-breaches", http.MethodGet, tt.path, tt.identity, handler.GetSLABreaches)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp analytics.SLABreachSummary
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.BreachCount != 4 || resp.OpenBreaches != 1 || resp.AverageBreachSeconds != 900 {
+				t.Errorf("summary = %+v, want %+v", resp, summary)
+			}
+			if resp.From.Month() != 1 || resp.To.Month() != 2 {
+				t.Errorf("range = %s..%s, want the requested range", resp.From, resp.To)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetTrends(t *testing.T) {
+	windowConfig := base64.URLEncoding.EncodeToString([]byte(`{"window_size":3,"use_weighted_average":true}`))
+	tests := []struct {
+		name        string
+		path        string
+		wantCode    int
+		wantRequest *analytics.TrendAnalysisRequest
+	}{
+		{
+			name:     "configured windows",
+			path:     "/conversations/c1/trends",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "window_config override",
+			path:     "/conversations/c1/trends?window_config=" + windowConfig,
+			wantCode: http.StatusOK,
+			wantRequest: &analytics.TrendAnalysisRequest{
+				ConversationID: "c1",
+				TenantID:       "tenant-1",
+				Config:         analytics.TrendWindowConfig{WindowSize: 3, UseWeightedAverage: true},
+			},
+		},
+		{
+			name:     "not base64",
+			path:     "/conversations/c1/trends?window_config=%7B%7D!",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown field",
+			path:     "/conversations/c1/trends?window_config=" + base64.StdEncoding.EncodeToString([]byte(`{"window":3}`)),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "negative window size",
+			path:     "/conversations/c1/trends?window_config=" + base64.StdEncoding.EncodeToString([]byte(`{"window_size":-1}`)),
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockAnalyticsService{Trends: analytics.TrendAnalysis{SentimentTrend: analytics.TrendImproving}}
+			handler := NewAnalyticsHandler(mock, nil, nil)
+			rec := serveHandler("/conversations/:id/trends", http.MethodGet, tt.path, analyticsAgent, handler.GetTrends)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !reflect.DeepEqual(mock.TrendRequest, tt.wantRequest) {
+				t.Errorf("trend request = %+v, want %+v", mock.TrendRequest, tt.wantRequest)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp GetTrendsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Trends.SentimentTrend != analytics.TrendImproving {
+				t.Errorf("sentiment trend = %s, want Improving", resp.Trends.SentimentTrend)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetObjectionResolutionRates(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity testContext
+		mock     *MockAnalyticsService
+		wantCode int
+	}{
+		{
+			name:     "returns rates",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{ResolutionRates: map[string]float64{"price": 0.75, "timing": 0.5}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "service error",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/objections/resolution-rates", http.MethodGet, "/objections/resolution-rates", tt.identity, handler.GetObjectionResolutionRates)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				ResolutionRates map[string]float64 `json:"resolution_rates"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.ResolutionRates["price"] != 0.75 || resp.ResolutionRates["timing"] != 0.5 {
+				t.Errorf("resolution_rates = %v", resp.ResolutionRates)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerGetCustomerSegments(t *testing.T) {
+	segments := []analytics.CustomerSegment{
+		{Segment: analytics.SegmentAtRiskChurner, Count: 2, RepresentativeCustomerIDs: []string{"cust-1", "cust-2"}, SuggestedActions: []string{"Follow up"}},
+		{Segment: analytics.SegmentUndecided, Count: 0, RepresentativeCustomerIDs: []string{}, SuggestedActions: []string{"Qualify"}},
+	}
+
+	tests := []struct {
+		name     string
+		identity testContext
+		mock     *MockAnalyticsService
+		wantCode int
+	}{
+		{
+			name:     "returns segments",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Segments: segments},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "service error",
+			identity: analyticsAgent,
+			mock:     &MockAnalyticsService{Err: errors.New("boom")},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "missing tenant",
+			mock:     &MockAnalyticsService{},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAnalyticsHandler(tt.mock, nil, nil)
+			rec := serveHandler("/customer-segments", http.MethodGet, "/customer-segments", tt.identity, handler.GetCustomerSegments)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Segments []analytics.CustomerSegment `json:"segments"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Segments, segments) {
+				t.Errorf("segments = %+v, want %+v", resp.Segments, segments)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AssignmentStore reads and sets the agent assigned to a conversation
+type AssignmentStore interface {
+	GetAssignedAgent(tenantID, conversationID string) (*string, error)
+	AssignAgent(tenantID, conversationID, agentID string) error
+}
+
+// AssignmentHandler handles conversation agent assignment
+type AssignmentHandler struct {
+	store AssignmentStore
+}
+
+// NewAssignmentHandler creates a new assignment handler
+func NewAssignmentHandler(store AssignmentStore) *AssignmentHandler {
+	return &AssignmentHandler{store: store}
+}
+
+// AssignAgentRequest is the body of PUT /api/conversations/:id/assign. An empty agent_id unassigns
+// the conversation.
+type AssignAgentRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// AssignmentResponse is a conversation's current assignment
+type AssignmentResponse struct {
+	ConversationID  string  `json:"conversation_id"`
+	AssignedAgentID *string `json:"assigned_agent_id"` // null when unassigned
+}
+
+// GetAssignment handles GET /api/conversations/:id/assign (agent or admin)
+func (h *AssignmentHandler) GetAssignment(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	conversationID := c.Param("id")
+	agentID, err := h.store.GetAssignedAgent(tenantID, conversationID)
+	if err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AssignmentResponse{ConversationID: conversationID, AssignedAgentID: agentID})
+}
+
+// AssignAgent handles PUT /api/conversations/:id/assign (agent or admin). The agent must be an
+// active agent or admin of the same tenant.
+func (h *AssignmentHandler) AssignAgent(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req AssignAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	agentID := strings.TrimSpace(req.AgentID)
+
+	conversationID := c.Param("id")
+	if err := h.store.AssignAgent(tenantID, conversationID, agentID); err != nil {
+		respondAssignmentError(c, err)
+		return
+	}
+	log.Printf("[ASSIGN] conversation assigned tenant=%s conversation=%s agent=%q by=%s",
+		tenantID, conversationID, agentID, c.GetString("user_id"))
+
+	resp := AssignmentResponse{ConversationID: conversationID}
+	if agentID != "" {
+		resp.AssignedAgentID = &agentID
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// authorize rejects customers and returns the caller's tenant
+func (h *AssignmentHandler) authorize(c *gin.Context) (string, bool) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return "", false
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func respondAssignmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrInvalidAssignee):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
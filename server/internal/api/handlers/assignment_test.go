@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeAssignmentStore holds the assignment of tenant-1's conversation c1; assignees missing from
+// agents are rejected like agents of another tenant
+type fakeAssignmentStore struct {
+	assigned map[string]string
+	agents   map[string]bool
+}
+
+func (f *fakeAssignmentStore) GetAssignedAgent(tenantID, conversationID string) (*string, error) {
+	if tenantID != "tenant-1" || conversationID != "c1" {
+		return nil, errors.New("conversation not found")
+	}
+	agentID, ok := f.assigned[conversationID]
+	if !ok {
+		return nil, nil
+	}
+	return &agentID, nil
+}
+
+func (f *fakeAssignmentStore) AssignAgent(tenantID, conversationID, agentID string) error {
+	if tenantID != "tenant-1" || conversationID != "c1" {
+		return errors.New("conversation not found")
+	}
+	if agentID == "" {
+		delete(f.assigned, conversationID)
+		return nil
+	}
+	if !f.agents[agentID] {
+		return postgres.ErrInvalidAssignee
+	}
+	f.assigned[conversationID] = agentID
+	return nil
+}
+
+func serveAssignment(store AssignmentStore, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewAssignmentHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "agent")
+		c.Set("user_id", "agent-1")
+	})
+	engine.GET("/api/conversations/:id/assign", handler.GetAssignment)
+	engine.PUT("/api/conversations/:id/assign", handler.AssignAgent)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeAssignment(t *testing.T, rec *httptest.ResponseRecorder) AssignmentResponse {
+	t.Helper()
+	var resp AssignmentResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestAssignmentHandlerAssignAndUnassign(t *testing.T) {
+	store := &fakeAssignmentStore{assigned: map[string]string{}, agents: map[string]bool{"agent-2": true}}
+
+	rec := serveAssignment(store, http.MethodPut, "/api/conversations/c1/assign", `{"agent_id":"agent-2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("assign = %d: %s", rec.Code, rec.Body.String())
+	}
+	if resp := decodeAssignment(t, rec); resp.AssignedAgentID == nil || *resp.AssignedAgentID != "agent-2" {
+		t.Errorf("assign response = %+v, want agent-2", resp)
+	}
+
+	rec = serveAssignment(store, http.MethodGet, "/api/conversations/c1/assign", "")
+	if resp := decodeAssignment(t, rec); rec.Code != http.StatusOK || resp.AssignedAgentID == nil || *resp.AssignedAgentID != "agent-2" {
+		t.Errorf("get = %d %+v, want agent-2", rec.Code, resp)
+	}
+
+	rec = serveAssignment(store, http.MethodPut, "/api/conversations/c1/assign", `{"agent_id":""}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unassign = %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"assigned_agent_id":null`) {
+		t.Errorf("unassign response = %s, want assigned_agent_id null", rec.Body.String())
+	}
+	if _, ok := store.assigned["c1"]; ok {
+		t.Error("expected the conversation to be unassigned")
+	}
+}
+
+func TestAssignmentHandlerErrors(t *testing.T) {
+	store := &fakeAssignmentStore{assigned: map[string]string{}, agents: map[string]bool{}}
+
+	if rec := serveAssignment(store, http.MethodPut, "/api/conversations/c1/assign", `{"agent_id":"other-tenant-agent"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid assignee = %d, want 400", rec.Code)
+	}
+	if rec := serveAssignment(store, http.MethodPut, "/api/conversations/missing/assign", `{"agent_id":""}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown conversation = %d, want 404", rec.Code)
+	}
+	if rec := serveAssignment(store, http.MethodGet, "/api/conversations/missing/assign", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get unknown conversation = %d, want 404", rec.Code)
+	}
+	if rec := serveAssignment(store, http.MethodPut, "/api/conversations/c1/assign", `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AuditLogLister lists a tenant's audit log one page at a time
+type AuditLogLister interface {
+	ListAuditLogs(tenantID, resourceType string, limit int, cursor string) ([]*postgres.AuditLog, string, error)
+}
+
+// AuditLogHandler serves the audit log of admin changes
+type AuditLogHandler struct {
+	auditLogs AuditLogLister
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(auditLogs AuditLogLister) *AuditLogHandler {
+	return &AuditLogHandler{auditLogs: auditLogs}
+}
+
+// ListAuditLogsResponse represents the response for listing audit logs
+type ListAuditLogsResponse struct {
+	AuditLogs []*postgres.AuditLog `json:"audit_logs"`
+	NextAfter string               `json:"next_after,omitempty"` // Pass as after for the next page; empty on the last page
+}
+
+// ListAuditLogs handles GET /api/admin/audit-logs (Admin only)
+// Query params: resource_type (e.g. rule, product, brand_tone, autoreply_global), limit (default 50,
+// max 200), after (next_after from the previous page). Entries are newest first.
+func (h *AuditLogHandler) ListAuditLogs(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	entries, nextAfter, err := h.auditLogs.ListAuditLogs(tenantID, c.Query("resource_type"), limit, c.Query("after"))
+	if errors.Is(err, postgres.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid after"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListAuditLogsResponse{AuditLogs: entries, NextAfter: nextAfter})
+}
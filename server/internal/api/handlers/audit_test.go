@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeAuditLogLister records the arguments of the last ListAuditLogs call
+type fakeAuditLogLister struct {
+	entries      []*postgres.AuditLog
+	next         string
+	err          error
+	resourceType string
+	limit        int
+	cursor       string
+}
+
+func (f *fakeAuditLogLister) ListAuditLogs(tenantID, resourceType string, limit int, cursor string) ([]*postgres.AuditLog, string, error) {
+	f.resourceType, f.limit, f.cursor = resourceType, limit, cursor
+	return f.entries, f.next, f.err
+}
+
+func TestAuditLogHandlerListAuditLogs(t *testing.T) {
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+	tests := []struct {
+		name      string
+		path      string
+		identity  testContext
+		lister    *fakeAuditLogLister
+		wantCode  int
+		wantLimit int
+	}{
+		{
+			name:     "filters and pages",
+			path:     "/admin/audit-logs?resource_type=rule&limit=10&after=abc",
+			identity: admin,
+			lister: &fakeAuditLogLister{
+				entries: []*postgres.AuditLog{{ID: "a1", TenantID: "tenant-1", Action: "rule.updated", ResourceType: "rule"}},
+				next:    "next-page",
+			},
+			wantCode:  http.StatusOK,
+			wantLimit: 10,
+		},
+		{name: "default limit", path: "/admin/audit-logs", identity: admin, lister: &fakeAuditLogLister{}, wantCode: http.StatusOK, wantLimit: 50},
+		{name: "limit capped", path: "/admin/audit-logs?limit=1000", identity: admin, lister: &fakeAuditLogLister{}, wantCode: http.StatusOK, wantLimit: 200},
+		{name: "invalid limit", path: "/admin/audit-logs?limit=-1", identity: admin, lister: &fakeAuditLogLister{}, wantCode: http.StatusBadRequest},
+		{name: "invalid after", path: "/admin/audit-logs?after=%25", identity: admin, lister: &fakeAuditLogLister{err: postgres.ErrInvalidCursor}, wantCode: http.StatusBadRequest},
+		{name: "missing tenant", path: "/admin/audit-logs", lister: &fakeAuditLogLister{}, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuditLogHandler(tt.lister)
+			rec := serveHandler("/admin/audit-logs", http.MethodGet, tt.path, tt.identity, handler.ListAuditLogs)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if tt.lister.limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", tt.lister.limit, tt.wantLimit)
+			}
+			var resp ListAuditLogsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if len(resp.AuditLogs) != len(tt.lister.entries) || resp.NextAfter != tt.lister.next {
+				t.Errorf("response = %+v, want %d entries and next_after %q", resp, len(tt.lister.entries), tt.lister.next)
+			}
+		})
+	}
+
+	lister := &fakeAuditLogLister{}
+	serveHandler("/admin/audit-logs", http.MethodGet, "/admin/audit-logs?resource_type=product&after=cursor-1", admin, NewAuditLogHandler(lister).ListAuditLogs)
+	if lister.resourceType != "product" || lister.cursor != "cursor-1" {
+		t.Errorf("listed resource_type=%q after=%q, want product and cursor-1", lister.resourceType, lister.cursor)
+	}
+}
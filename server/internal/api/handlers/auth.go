@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,13 +14,19 @@ import (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	userStorage *postgres.UserStorage
+	userStorage   *postgres.UserStorage
+	refreshTokens *postgres.RefreshTokenStorage
+	revocations   *auth.RevocationCache
+	invitations   auth.InvitationStore
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(userStorage *postgres.UserStorage) *AuthHandler {
+// NewAuthHandler creates a new auth handler. revocations is shared with the JWT middleware so
+// logged-out access tokens are rejected.
+func NewAuthHandler(userStorage *postgres.UserStorage, refreshTokens *postgres.RefreshTokenStorage, revocations *auth.RevocationCache) *AuthHandler {
 	return &AuthHandler{
-		userStorage: userStorage,
+		userStorage:   userStorage,
+		refreshTokens: refreshTokens,
+		revocations:   revocations,
 	}
 }
 
@@ -31,8 +39,9 @@ type LoginRequest struct {
 
 // LoginResponse represents the response for login
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  *models.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"` // Long-lived; exchange at POST /api/auth/refresh
+	User         *models.User `json:"user"`
 }
 
 // Login handles POST /api/auth/login
@@ -54,6 +63,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if user.DeactivatedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account is deactivated"})
+		return
+	}
+
 	// Ensure tenant_id is present (use from request if user doesn't have it)
 	tenantID := user.TenantID
 	if tenantID == "" {
@@ -64,15 +78,16 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, tenantID, string(user.Role))
+	tokens, err := auth.IssueTokens(h.refreshTokens, user.ID, tenantID, string(user.Role))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, LoginResponse{
-		Token: token,
-		User:  user,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		User:         user,
 	})
 }
 
@@ -103,17 +118,76 @@ func (h *AuthHandler) CustomerLogin(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "this endpoint is for customers only"})
 		return
 	}
+	if user.DeactivatedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account is deactivated"})
+		return
+	}
 
 	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.TenantID, string(user.Role))
+	tokens, err := auth.IssueTokens(h.refreshTokens, user.ID, user.TenantID, string(user.Role))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, LoginResponse{
-		Token: token,
-		User:  user,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		User:         user,
 	})
 }
 
+// RefreshTokenRequest represents the request body for refreshing or revoking a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh handles POST /api/auth/refresh. The refresh token is rotated: the response carries a
+// new access token and a new refresh token, and the old refresh token stops working.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := auth.RefreshToken(h.refreshTokens, h.userStorage, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles POST /api/auth/logout. Revokes the refresh token and, when the request carries
+// an access token, rejects that access token until it expires.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := auth.RevokeRefreshToken(h.refreshTokens, req.RefreshToken); err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && h.revocations != nil {
+		if claims, err := auth.ValidateToken(token); err == nil {
+			h.revocations.RevokeClaims(claims)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -63,6 +64,7 @@ func (h *AutoReplyHandler) GetGlobalAutoReply(c *gin.Context) {
 type UpdateGlobalAutoReplyRequest struct {
 	Enabled            bool    `json:"enabled"`
 	ConfidenceThreshold float64 `json:"confidence_threshold"` // 0.0 - 1.0
+	OutOfHoursMessage  string  `json:"out_of_hours_message"` // Optional, sent outside business hours
 }
 
 // UpdateGlobalAutoReply handles PUT /api/autoreply/global (admin only)
@@ -95,6 +97,7 @@ func (h *AutoReplyHandler) UpdateGlobalAutoReply(c *gin.Context) {
 		TenantID:           tenantID,
 		Enabled:            req.Enabled,
 		ConfidenceThreshold: req.ConfidenceThreshold,
+		OutOfHoursMessage:  strings.TrimSpace(req.OutOfHoursMessage),
 		UpdatedAt:          time.Now(),
 	}
 
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// BrandToneHandler handles the tenant's brand tone used for reply suggestions
+type BrandToneHandler struct {
+	brandToneStorage *postgres.BrandToneStorage
+}
+
+// NewBrandToneHandler creates a new brand tone handler
+func NewBrandToneHandler(brandToneStorage *postgres.BrandToneStorage) *BrandToneHandler {
+	return &BrandToneHandler{brandToneStorage: brandToneStorage}
+}
+
+// BrandToneRequest represents the request body for setting the brand tone
+type BrandToneRequest struct {
+	Tone string `json:"tone" binding:"required"` // Free-text description, e.g. "Technical but warm"
+}
+
+// BrandToneResponse represents the tenant's brand tone
+type BrandToneResponse struct {
+	Tone string `json:"tone"`
+}
+
+// GetBrandTone handles GET /api/brand-tone (admin only). Tenants without a configured
+// tone get Professional.
+func (h *BrandToneHandler) GetBrandTone(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	tone, err := h.brandToneStorage.GetBrandTone(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BrandToneResponse{Tone: tone})
+}
+
+// UpdateBrandTone handles POST /api/brand-tone (admin only). The tone is used verbatim in
+// suggestion prompts and may be up to 500 characters.
+func (h *BrandToneHandler) UpdateBrandTone(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req BrandToneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tone is required"})
+		return
+	}
+	req.Tone = strings.TrimSpace(req.Tone)
+	if err := postgres.ValidateBrandTone(req.Tone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.brandToneStorage.UpdateBrandTone(tenantID, req.Tone); err != nil {
+		if errors.Is(err, postgres.ErrInvalidBrandTone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BrandToneResponse{Tone: req.Tone})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUpdateBrandToneRejectsInvalidTone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBrandToneHandler(nil)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.POST("/api/brand-tone", handler.UpdateBrandTone)
+
+	tests := map[string]string{
+		"missing tone":   `{}`,
+		"blank tone":     `{"tone": "   "}`,
+		"501 characters": `{"tone": "` + strings.Repeat("a", 501) + `"}`,
+		"501 multi-byte": `{"tone": "` + strings.Repeat("é", 501) + `"}`,
+		"malformed body": `{"tone": `,
+	}
+	for name, body := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/brand-tone", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
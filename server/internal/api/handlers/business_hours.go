@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/autoreply"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// BusinessHoursStore reads and replaces a tenant's business hours (see postgres.BusinessHoursStorage)
+type BusinessHoursStore interface {
+	GetBusinessHours(tenantID string) (*postgres.BusinessHoursConfig, error)
+	SetBusinessHours(config *postgres.BusinessHoursConfig) error
+}
+
+// BusinessHoursHandler handles the hours auto-reply is limited to
+type BusinessHoursHandler struct {
+	store BusinessHoursStore
+}
+
+// NewBusinessHoursHandler creates a new business hours handler
+func NewBusinessHoursHandler(store BusinessHoursStore) *BusinessHoursHandler {
+	return &BusinessHoursHandler{store: store}
+}
+
+// BusinessHoursRequest represents the request body for setting business hours
+type BusinessHoursRequest struct {
+	Timezone string                         `json:"timezone" binding:"required"`
+	Schedule []postgres.BusinessHoursWindow `json:"schedule"`
+}
+
+// BusinessHoursResponse represents the tenant's business hours
+type BusinessHoursResponse struct {
+	Timezone   string                         `json:"timezone"`
+	Schedule   []postgres.BusinessHoursWindow `json:"schedule"`
+	Configured bool                           `json:"configured"` // False means auto-reply answers around the clock
+}
+
+// GetBusinessHours handles GET /api/business-hours (admin only)
+func (h *BusinessHoursHandler) GetBusinessHours(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	config, err := h.store.GetBusinessHours(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if config == nil {
+		c.JSON(http.StatusOK, BusinessHoursResponse{Schedule: []postgres.BusinessHoursWindow{}})
+		return
+	}
+
+	c.JSON(http.StatusOK, BusinessHoursResponse{Timezone: config.Timezone, Schedule: config.Schedule, Configured: true})
+}
+
+// UpdateBusinessHours handles PUT /api/business-hours (admin only). Auto-reply stays quiet outside
+// the schedule, apart from the optional out-of-hours message.
+func (h *BusinessHoursHandler) UpdateBusinessHours(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req BusinessHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timezone is required"})
+		return
+	}
+
+	config := &postgres.BusinessHoursConfig{TenantID: tenantID, Timezone: req.Timezone, Schedule: req.Schedule}
+	if err := autoreply.ValidateBusinessHours(config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.store.SetBusinessHours(config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BusinessHoursResponse{Timezone: config.Timezone, Schedule: config.Schedule, Configured: true})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeBusinessHoursStore keeps one tenant's business hours in memory
+type fakeBusinessHoursStore struct {
+	config *postgres.BusinessHoursConfig
+}
+
+func (f *fakeBusinessHoursStore) GetBusinessHours(tenantID string) (*postgres.BusinessHoursConfig, error) {
+	return f.config, nil
+}
+
+func (f *fakeBusinessHoursStore) SetBusinessHours(config *postgres.BusinessHoursConfig) error {
+	f.config = config
+	return nil
+}
+
+func TestBusinessHoursHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &fakeBusinessHoursStore{}
+	handler := NewBusinessHoursHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.GET("/api/business-hours", handler.GetBusinessHours)
+	engine.PUT("/api/business-hours", handler.UpdateBusinessHours)
+
+	serveJSON := func(method, body string) (*httptest.ResponseRecorder, BusinessHoursResponse) {
+		req := httptest.NewRequest(method, "/api/business-hours", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		var resp BusinessHoursResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	if rec, resp := serveJSON(http.MethodGet, ""); rec.Code != http.StatusOK || resp.Configured {
+		t.Fatalf("GET before configuring = %d %+v, want unconfigured", rec.Code, resp)
+	}
+
+	invalid := map[string]string{
+		"missing timezone": `{"schedule": []}`,
+		"unknown timezone": `{"timezone": "Nowhere/Town"}`,
+		"bad day":          `{"timezone": "UTC", "schedule": [{"day_of_week": 9, "open_time": "09:00", "close_time": "17:00"}]}`,
+		"closes too early": `{"timezone": "UTC", "schedule": [{"day_of_week": 1, "open_time": "17:00", "close_time": "09:00"}]}`,
+	}
+	for name, body := range invalid {
+		if rec, _ := serveJSON(http.MethodPut, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+	if store.config != nil {
+		t.Fatalf("invalid business hours were saved: %+v", store.config)
+	}
+
+	body := `{"timezone": "Asia/Kolkata", "schedule": [{"day_of_week": 1, "open_time": "09:00", "close_time": "18:00"}]}`
+	if rec, _ := serveJSON(http.MethodPut, body); rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	rec, resp := serveJSON(http.MethodGet, "")
+	if rec.Code != http.StatusOK || !resp.Configured || resp.Timezone != "Asia/Kolkata" || len(resp.Schedule) != 1 || resp.Schedule[0].CloseTime != "18:00" {
+		t.Errorf("GET after PUT = %d %+v, want the saved hours", rec.Code, resp)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// minCalibrationSamples is the fewest samples per model needed for a usable standard deviation
+const minCalibrationSamples = 2
+
+// CalibrationHandler handles model score calibration
+type CalibrationHandler struct {
+	calibrationStorage  CalibrationStore
+	sentimentNormalizer *ai.SentimentNormalizer
+	confidenceCurves    ConfidenceCalibrationSource
+}
+
+// CalibrationStore persists global model calibrations (see postgres.ModelCalibrationStorage)
+type CalibrationStore interface {
+	SaveCalibration(cal *postgres.ModelCalibration) error
+}
+
+// ConfidenceCalibrationSource fits a tenant's suggestion confidence to its feedback
+// (see ai.ConfidenceCalibrator)
+type ConfidenceCalibrationSource interface {
+	Refresh(tenantID string) (*ai.ConfidenceCalibration, error)
+}
+
+// NewCalibrationHandler creates a new calibration handler
+func NewCalibrationHandler(calibrationStorage CalibrationStore, sentimentNormalizer *ai.SentimentNormalizer) *CalibrationHandler {
+	return &CalibrationHandler{
+		calibrationStorage:  calibrationStorage,
+		sentimentNormalizer: sentimentNormalizer,
+	}
+}
+
+// SetConfidenceCalibrator enables the suggestion confidence calibration report (optional)
+func (h *CalibrationHandler) SetConfidenceCalibrator(source ConfidenceCalibrationSource) {
+	h.confidenceCurves = source
+}
+
+// CalibrationSample is a model's raw sentiment score paired with a human label
+type CalibrationSample struct {
+	ModelName string   `json:"model_name" binding:"required"`
+	RawScore  *float64 `json:"raw_score" binding:"required"`
+	TrueLabel *float64 `json:"true_label" binding:"required"`
+}
+
+// CalibrateModelResponse represents the stored calibrations
+type CalibrateModelResponse struct {
+	Calibrations []*postgres.ModelCalibration `json:"calibrations"`
+}
+
+// CalibrateModel handles POST /api/superadmin/calibrate-model (super admin only).
+// Computes each model's raw score mean/std plus the ground-truth label
+// distribution, and applies them to sentiment normalization immediately.
+// Calibrations are shared by all tenants, so no tenant admin may change them.
+func (h *CalibrationHandler) CalibrateModel(c *gin.Context) {
+	var samples []CalibrationSample
+	if err := c.ShouldBindJSON(&samples); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(samples) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one sample is required"})
+		return
+	}
+
+	rawByModel := make(map[string][]float64)
+	labels := make([]float64, 0, len(samples))
+	for i, sample := range samples {
+		model := strings.TrimSpace(sample.ModelName)
+		if model == "" || model == ai.CalibrationReferenceModel {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("sample %d: invalid model_name", i)})
+			return
+		}
+		if sample.RawScore == nil || sample.TrueLabel == nil ||
+			*sample.RawScore < 0 || *sample.RawScore > 1 || *sample.TrueLabel < 0 || *sample.TrueLabel > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("sample %d: raw_score and true_label must be between 0 and 1", i)})
+			return
+		}
+		rawByModel[model] = append(rawByModel[model], *sample.RawScore)
+		labels = append(labels, *sample.TrueLabel)
+	}
+
+	modelNames := make([]string, 0, len(rawByModel))
+	for model, scores := range rawByModel {
+		if len(scores) < minCalibrationSamples {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("model %s needs at least %d samples", model, minCalibrationSamples)})
+			return
+		}
+		modelNames = append(modelNames, model)
+	}
+	sort.Strings(modelNames)
+
+	calibrations := make([]*postgres.ModelCalibration, 0, len(modelNames)+1)
+	for _, model := range modelNames {
+		mean, std := ai.MeanStdDev(rawByModel[model])
+		calibrations = append(calibrations, &postgres.ModelCalibration{
+			ModelName:    model,
+			Metric:       ai.CalibrationMetricSentiment,
+			BaselineMean: mean,
+			BaselineStd:  std,
+		})
+	}
+	labelMean, labelStd := ai.MeanStdDev(labels)
+	calibrations = append(calibrations, &postgres.ModelCalibration{
+		ModelName:    ai.CalibrationReferenceModel,
+		Metric:       ai.CalibrationMetricSentiment,
+		BaselineMean: labelMean,
+		BaselineStd:  labelStd,
+	})
+
+	for _, cal := range calibrations {
+		if err := h.calibrationStorage.SaveCalibration(cal); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if h.sentimentNormalizer != nil {
+			h.sentimentNormalizer.SetCalibration(cal.ModelName, cal.BaselineMean, cal.BaselineStd)
+		}
+	}
+
+	c.JSON(http.StatusOK, CalibrateModelResponse{Calibrations: calibrations})
+}
+
+// GetConfidenceCalibration handles GET /api/admin/ai/confidence-calibration (admin only).
+// Refits the tenant's confidence calibration from its latest suggestion feedback and returns,
+// per confidence decile, the acceptance the scorer predicted against what agents actually did.
+func (h *CalibrationHandler) GetConfidenceCalibration(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if h.confidenceCurves == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "confidence calibration is not configured"})
+		return
+	}
+
+	calibration, err := h.confidenceCurves.Refresh(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, calibration)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeConfidenceCurves struct {
+	samples  []postgres.FeedbackConfidenceSample
+	err      error
+	tenantID string
+}
+
+func (f *fakeConfidenceCurves) Refresh(tenantID string) (*ai.ConfidenceCalibration, error) {
+	f.tenantID = tenantID
+	if f.err != nil {
+		return nil, f.err
+	}
+	return ai.FitConfidenceCalibration(f.samples), nil
+}
+
+func TestCalibrationHandlerGetConfidenceCalibration(t *testing.T) {
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+	samples := []postgres.FeedbackConfidenceSample{
+		{Confidence: 0.95, Accepted: true}, {Confidence: 0.9}, {Confidence: 0.15},
+	}
+	tests := []struct {
+		name     string
+		identity testContext
+		curves   *fakeConfidenceCurves
+		wantCode int
+	}{
+		{name: "reports buckets", identity: admin, curves: &fakeConfidenceCurves{samples: samples}, wantCode: http.StatusOK},
+		{name: "not configured", identity: admin, wantCode: http.StatusServiceUnavailable},
+		{name: "missing tenant", identity: testContext{role: "admin"}, curves: &fakeConfidenceCurves{}, wantCode: http.StatusUnauthorized},
+		{name: "storage error", identity: admin, curves: &fakeConfidenceCurves{err: errors.New("db down")}, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCalibrationHandler(nil, nil)
+			if tt.curves != nil {
+				handler.SetConfidenceCalibrator(tt.curves)
+			}
+			rec := serveHandler("/confidence-calibration", http.MethodGet, "/confidence-calibration", tt.identity, handler.GetConfidenceCalibration)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if tt.curves.tenantID != "tenant-1" {
+				t.Errorf("calibrated tenant %q, want tenant-1", tt.curves.tenantID)
+			}
+			var resp ai.ConfidenceCalibration
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Calibrated || resp.Samples != 3 || len(resp.Buckets) != 2 {
+				t.Fatalf("response = %+v, want 3 samples in 2 buckets, too few to calibrate", resp)
+			}
+			top := resp.Buckets[1]
+			if top.ConfidenceBucket != "0.9-1.0" || top.Samples != 2 || top.ActualAcceptance != 0.5 {
+				t.Errorf("top bucket = %+v, want half of 2 samples accepted", top)
+			}
+		})
+	}
+}
+
+type fakeCalibrationStore struct {
+	saved []*postgres.ModelCalibration
+	err   error
+}
+
+func (f *fakeCalibrationStore) SaveCalibration(cal *postgres.ModelCalibration) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.saved = append(f.saved, cal)
+	return nil
+}
+
+func serveCalibrateModel(handler *CalibrationHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/calibrate-model", handler.CalibrateModel)
+	req := httptest.NewRequest(http.MethodPost, "/calibrate-model", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCalibrationHandlerCalibrateModel(t *testing.T) {
+	store := &fakeCalibrationStore{}
+	normalizer := ai.NewSentimentNormalizer()
+	handler := NewCalibrationHandler(store, normalizer)
+
+	// No tenant context: calibrations are global and the route is super admin only
+	rec := serveCalibrateModel(handler, `[
+		{"model_name": "gemini", "raw_score": 0.6, "true_label": 0.4},
+		{"model_name": "gemini", "raw_score": 0.8, "true_label": 0.8},
+		{"model_name": " openai ", "raw_score": 0.2, "true_label": 0.4},
+		{"model_name": "openai", "raw_score": 0.6, "true_label": 0.8}
+	]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp CalibrateModelResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Calibrations) != 3 || len(store.saved) != 3 {
+		t.Fatalf("calibrations = %+v, saved %d, want gemini, openai and ground truth", resp.Calibrations, len(store.saved))
+	}
+	want := []struct {
+		model     string
+		mean, std float64
+	}{
+		{"gemini", 0.7, 0.1},
+		{"openai", 0.4, 0.2},
+		{ai.CalibrationReferenceModel, 0.6, 0.2},
+	}
+	for i, w := range want {
+		cal := store.saved[i]
+		if cal.ModelName != w.model || cal.Metric != ai.CalibrationMetricSentiment ||
+			math.Abs(cal.BaselineMean-w.mean) > 1e-9 || math.Abs(cal.BaselineStd-w.std) > 1e-9 {
+			t.Errorf("calibration %d = %+v, want %s %v/%v", i, cal, w.model, w.mean, w.std)
+		}
+	}
+	// The normalizer applies the new calibration immediately
+	if got := normalizer.Normalize(0.8, "gemini"); math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("normalized gemini 0.8 = %v, want 0.8", got)
+	}
+}
+
+func TestCalibrationHandlerCalibrateModelValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		store    *fakeCalibrationStore
+		wantCode int
+	}{
+		{"empty", `[]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"not an array", `{"model_name": "gemini"}`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"missing score", `[{"model_name": "gemini", "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"blank model", `[{"model_name": " ", "raw_score": 0.5, "true_label": 0.5}, {"model_name": " ", "raw_score": 0.6, "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"reference model", `[{"model_name": "ground_truth", "raw_score": 0.5, "true_label": 0.5}, {"model_name": "ground_truth", "raw_score": 0.6, "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"score out of range", `[{"model_name": "gemini", "raw_score": 1.5, "true_label": 0.5}, {"model_name": "gemini", "raw_score": 0.6, "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"label out of range", `[{"model_name": "gemini", "raw_score": 0.5, "true_label": -0.1}, {"model_name": "gemini", "raw_score": 0.6, "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"one sample", `[{"model_name": "gemini", "raw_score": 0.5, "true_label": 0.5}]`, &fakeCalibrationStore{}, http.StatusBadRequest},
+		{"storage error", `[{"model_name": "gemini", "raw_score": 0.5, "true_label": 0.5}, {"model_name": "gemini", "raw_score": 0.6, "true_label": 0.5}]`, &fakeCalibrationStore{err: errors.New("db down")}, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := ai.NewSentimentNormalizer()
+			rec := serveCalibrateModel(NewCalibrationHandler(tt.store, normalizer), tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusBadRequest && len(tt.store.saved) != 0 {
+				t.Errorf("saved %+v, want nothing stored for an invalid request", tt.store.saved)
+			}
+			if got := normalizer.Normalize(0.9, "gemini"); got != 0.9 {
+				t.Errorf("normalized score = %v, want the normalizer unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// CategoryHandler handles the product category tree
+type CategoryHandler struct {
+	categoryStorage *postgres.CategoryStorage
+	productStorage  *postgres.ProductStorage
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryStorage *postgres.CategoryStorage, productStorage *postgres.ProductStorage) *CategoryHandler {
+	return &CategoryHandler{categoryStorage: categoryStorage, productStorage: productStorage}
+}
+
+// CreateCategoryRequest is the body of POST /api/categories
+type CreateCategoryRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	ParentID    *string `json:"parent_id"` // Omitted for a top-level category
+	Description string  `json:"description"`
+}
+
+// UpdateCategoryRequest is the body of PUT /api/categories/:id; omitted fields are kept.
+// An empty parent_id moves the category to the top level.
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name"`
+	ParentID    *string `json:"parent_id"`
+	Description *string `json:"description"`
+}
+
+// CategoryResponse is a single category with its path
+type CategoryResponse struct {
+	Category *models.ProductCategory `json:"category"`
+}
+
+// ListCategoriesResponse represents the response for listing categories
+type ListCategoriesResponse struct {
+	Categories []*models.ProductCategory `json:"categories"`
+	Total      int                       `json:"total"`
+}
+
+// ListCategories handles GET /api/categories. Categories are ordered by path, so children
+// follow their parent.
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	categories, err := h.categoryStorage.ListCategories(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListCategoriesResponse{Categories: categories, Total: len(categories)})
+}
+
+// GetCategory handles GET /api/categories/:id
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	category, err := h.categoryStorage.GetCategory(tenantID, c.Param("id"))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CategoryResponse{Category: category})
+}
+
+// CreateCategory handles POST /api/categories (admin only)
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	tenantID, ok := requireAdminTenant(c)
+	if !ok {
+		return
+	}
+
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+
+	now := time.Now()
+	category := &models.ProductCategory{
+		ID:          uuid.New().String(),
+		Name:        name,
+		ParentID:    req.ParentID,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := h.categoryStorage.CreateCategory(tenantID, category); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CategoryResponse{Category: category})
+}
+
+// UpdateCategory handles PUT /api/categories/:id (admin only). A category can't be moved under
+// itself or one of its subcategories. Products aren't re-embedded; their category path is
+// refreshed the next time they are saved or reindexed.
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	tenantID, ok := requireAdminTenant(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryStorage.GetCategory(tenantID, c.Param("id"))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+			return
+		}
+		category.Name = name
+	}
+	if req.ParentID != nil {
+		category.ParentID = req.ParentID
+		if *req.ParentID == "" {
+			category.ParentID = nil
+		}
+	}
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+	if err := h.categoryStorage.UpdateCategory(tenantID, category); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CategoryResponse{Category: category})
+}
+
+// DeleteCategory handles DELETE /api/categories/:id (admin only). Subcategories are deleted
+// with it; their products are kept without a category.
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	tenantID, ok := requireAdminTenant(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.categoryStorage.DeleteCategory(tenantID, c.Param("id"))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully", "deleted_category_ids": deleted})
+}
+
+// ListCategoryProducts handles GET /api/categories/:id/products. Products in subcategories
+// are included.
+func (h *CategoryHandler) ListCategoryProducts(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	products, err := listCategoryProducts(h.categoryStorage, h.productStorage, tenantID, c.Param("id"))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ListProductsResponse{Products: products})
+}
+
+// listCategoryProducts lists the products in a category and its subcategories
+func listCategoryProducts(categories *postgres.CategoryStorage, products *postgres.ProductStorage, tenantID, categoryID string) ([]*models.Product, error) {
+	ids, err := categories.SubcategoryIDs(tenantID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return products.ListProductsInCategories(tenantID, ids)
+}
+
+// requireAdminTenant rejects non-admins and returns the caller's tenant
+func requireAdminTenant(c *gin.Context) (string, bool) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return "", false
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func respondCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrCategoryCycle), errors.Is(err, postgres.ErrParentCategoryNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, postgres.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
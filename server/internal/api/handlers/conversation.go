@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"ai-conversation-platform/internal/ai"
 	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/moderation"
 	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/chroma"
 	"ai-conversation-platform/internal/storage/postgres"
 )
 
@@ -16,6 +23,24 @@ import (
 type ConversationHandler struct {
 	ingestionService *conversation.IngestionService
 	userStorage      *postgres.UserStorage
+	messageSearcher  MessageSearcher
+	summarizer       ConversationSummarizer
+	noteLister       NoteLister
+}
+
+// MessageSearcher finds a tenant's messages by meaning (see ai.EmbeddingService)
+type MessageSearcher interface {
+	SearchMessages(tenantID, query string, limit int) ([]*chroma.MessageSearchResult, error)
+}
+
+// ConversationSummarizer writes cached AI summaries of conversations (see ai.Summarizer)
+type ConversationSummarizer interface {
+	Summarize(ctx context.Context, tenantID, conversationID string, messages []*models.Message) (*postgres.ConversationSummary, error)
+}
+
+// NoteLister lists a conversation's internal agent notes (see postgres.NoteStorage)
+type NoteLister interface {
+	ListNotes(tenantID, conversationID string) ([]models.ConversationNote, error)
 }
 
 // NewConversationHandler creates a new conversation handler
@@ -26,6 +51,21 @@ func NewConversationHandler(ingestionService *conversation.IngestionService, use
 	}
 }
 
+// SetMessageSearcher enables semantic message search (optional)
+func (h *ConversationHandler) SetMessageSearcher(searcher MessageSearcher) {
+	h.messageSearcher = searcher
+}
+
+// SetSummarizer enables conversation summaries (optional)
+func (h *ConversationHandler) SetSummarizer(summarizer ConversationSummarizer) {
+	h.summarizer = summarizer
+}
+
+// SetNoteLister includes agent notes in conversations returned to agents and admins (optional)
+func (h *ConversationHandler) SetNoteLister(lister NoteLister) {
+	h.noteLister = lister
+}
+
 // CreateConversationRequest represents the request body for creating a conversation
 type CreateConversationRequest struct {
 	TenantID  string  `json:"tenant_id" binding:"required"`
@@ -149,6 +189,10 @@ func (h *ConversationHandler) SendMessage(c *gin.Context) {
 
 	// Ingest message
 	messageID, err := h.ingestionService.IngestMessage(tenantID, normalized)
+	if errors.Is(err, moderation.ErrContentModerated) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "code": "CONTENT_MODERATED"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -165,9 +209,14 @@ func (h *ConversationHandler) SendMessage(c *gin.Context) {
 type GetConversationResponse struct {
 	Conversation *models.Conversation `json:"conversation"`
 	Messages     []*models.Message     `json:"messages"`
+	Summary      *string               `json:"summary,omitempty"` // With include_summary=true (agent/admin)
+	Notes        []models.ConversationNote `json:"notes,omitempty"`   // Internal agent notes (agent/admin)
 }
 
 // GetConversation handles GET /api/conversations/:id
+// Admins may pass include_deleted=true to include soft-deleted messages. Agents and admins may
+// pass include_summary=true for the conversation's summary; it is left out if it can't be generated.
+// Agents and admins also get the conversation's internal notes.
 func (h *ConversationHandler) GetConversation(c *gin.Context) {
 	conversationID := c.Param("id")
 	if conversationID == "" {
@@ -185,8 +234,26 @@ func (h *ConversationHandler) GetConversation(c *gin.Context) {
 	userID := c.GetString("user_id")
 	userRole := c.GetString("role")
 
-	conv, messages, err := h.ingestionService.GetConversation(tenantID, conversationID)
+	// Deleted messages are only visible to admins for compliance review
+	includeDeleted := c.Query("include_deleted") == "true"
+	if includeDeleted && userRole != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required to include deleted messages"})
+		return
+	}
+
+	var conv *models.Conversation
+	var messages []*models.Message
+	var err error
+	if includeDeleted {
+		conv, messages, err = h.ingestionService.GetConversationIncludingDeleted(tenantID, conversationID)
+	} else {
+		conv, messages, err = h.ingestionService.GetConversation(tenantID, conversationID)
+	}
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -197,6 +264,11 @@ func (h *ConversationHandler) GetConversation(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied: you can only access your own conversations"})
 			return
 		}
+		// Read receipts and tags are agent context only
+		conv.Tags = nil
+		for _, msg := range messages {
+			msg.ReadBy = nil
+		}
 	}
 
 	// Populate customer email if customer_id exists
@@ -207,22 +279,135 @@ func (h *ConversationHandler) GetConversation(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, GetConversationResponse{
+	response := GetConversationResponse{
 		Conversation: conv,
 		Messages:     messages,
-	})
+	}
+	if c.Query("include_summary") == "true" && userRole != "customer" && h.summarizer != nil {
+		summary, err := h.summarizer.Summarize(c.Request.Context(), tenantID, conversationID, messages)
+		if err == nil {
+			response.Summary = &summary.SummaryText
+		} else if !errors.Is(err, ai.ErrNothingToSummarize) {
+			log.Printf("[CONVERSATION] failed to summarize conversation=%s: %v", conversationID, err)
+		}
+	}
+	response.Notes = h.conversationNotes(userRole, tenantID, conversationID)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// conversationNotes returns the conversation's internal notes for agents and admins. Customers
+// never get them; they're left out if they can't be loaded.
+func (h *ConversationHandler) conversationNotes(userRole, tenantID, conversationID string) []models.ConversationNote {
+	if userRole == "customer" || h.noteLister == nil {
+		return nil
+	}
+	notes, err := h.noteLister.ListNotes(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[CONVERSATION] failed to load notes conversation=%s: %v", conversationID, err)
+		return nil
+	}
+	return notes
+}
+
+// GetConversationSummary handles GET /api/conversations/:id/summary (agent or admin)
+// Returns a bullet-point summary of at most 150 words, regenerated once more than 5 messages
+// have arrived since it was written.
+func (h *ConversationHandler) GetConversationSummary(c *gin.Context) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if h.summarizer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "conversation summaries are not configured"})
+		return
+	}
+
+	conversationID := c.Param("id")
+	_, messages, err := h.ingestionService.GetConversation(tenantID, conversationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.summarizer.Summarize(c.Request.Context(), tenantID, conversationID, messages)
+	if err != nil {
+		if errors.Is(err, ai.ErrNothingToSummarize) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
 }
 
 // ListConversationsRequest represents query parameters for listing conversations
 type ListConversationsRequest struct {
-	Limit  int `form:"limit"`
-	Offset int `form:"offset"`
+	Limit       int    `form:"limit"`
+	Cursor      string `form:"cursor"`      // next_cursor from the previous page
+	Offset      int    `form:"offset"`      // Watchlisted and deleted listings only
+	Watchlisted bool   `form:"watchlisted"` // Only watchlisted conversations (agents/admins)
+	AgentID     string `form:"agent_id"`    // Only conversations assigned to this agent (agents/admins)
+
+	Status        string `form:"status"`      // active, closed or archived
+	CustomerID    string `form:"customer_id"` // Agents/admins only; customers always see their own
+	ProductID     string `form:"product_id"`
+	CreatedAfter  string `form:"created_after"`  // RFC3339, inclusive
+	CreatedBefore string `form:"created_before"` // RFC3339, inclusive
+	TagID         string `form:"tag_id"`         // Agents/admins only
 }
 
 // ListConversationsResponse represents the response for listing conversations
 type ListConversationsResponse struct {
 	Conversations []*models.Conversation `json:"conversations"`
 	Total         int                     `json:"total"`
+	NextCursor    string                  `json:"next_cursor,omitempty"` // Empty on the last page
+	HasMore       bool                    `json:"has_more"`
+}
+
+// conversationFilters validates the request's filter params and converts them to storage filters
+func (req *ListConversationsRequest) conversationFilters() (*postgres.ConversationFilters, error) {
+	filters := &postgres.ConversationFilters{
+		Status:     req.Status,
+		CustomerID: req.CustomerID,
+		ProductID:  req.ProductID,
+		AgentID:    req.AgentID,
+		TagID:      req.TagID,
+	}
+	switch req.Status {
+	case "", "active", "closed", "archived":
+	default:
+		return nil, fmt.Errorf("invalid status, expected active, closed or archived")
+	}
+	if req.CreatedAfter != "" {
+		parsed, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_after, expected RFC3339")
+		}
+		filters.CreatedAfter = &parsed
+	}
+	if req.CreatedBefore != "" {
+		parsed, err := time.Parse(time.RFC3339, req.CreatedBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_before, expected RFC3339")
+		}
+		filters.CreatedBefore = &parsed
+	}
+	if filters.CreatedAfter != nil && filters.CreatedBefore != nil && filters.CreatedAfter.After(*filters.CreatedBefore) {
+		return nil, fmt.Errorf("created_after must be before created_before")
+	}
+	return filters, nil
 }
 
 // ListConversations handles GET /api/conversations
@@ -254,14 +439,43 @@ func (h *ConversationHandler) ListConversations(c *gin.Context) {
 	userID := c.GetString("user_id")
 	userRole := c.GetString("role")
 
+	if userRole == "customer" && (req.AgentID != "" || req.TagID != "") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	filters, err := req.conversationFilters()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	// For customers, only show their own conversations
 	// For agents/admins, show all conversations in the tenant
-	var customerID *string
 	if userRole == "customer" {
-		customerID = &userID
+		filters.CustomerID = userID
 	}
 
-	conversations, err := h.ingestionService.ListConversations(tenantID, customerID, req.Limit, req.Offset)
+	var conversations []*models.Conversation
+	var nextCursor string
+	var hasMore bool
+	if req.Watchlisted {
+		if userRole == "customer" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+			return
+		}
+		// Fetch one extra to tell whether there is another page
+		conversations, err = h.ingestionService.ListWatchlistedConversations(tenantID, req.Limit+1, req.Offset)
+		if len(conversations) > req.Limit {
+			conversations = conversations[:req.Limit]
+			hasMore = true
+		}
+	} else {
+		conversations, nextCursor, err = h.ingestionService.ListConversations(tenantID, filters, req.Limit, req.Cursor)
+		hasMore = nextCursor != ""
+	}
+	if errors.Is(err, postgres.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -280,6 +494,647 @@ func (h *ConversationHandler) ListConversations(c *gin.Context) {
 	c.JSON(http.StatusOK, ListConversationsResponse{
 		Conversations: conversations,
 		Total:         len(conversations),
+		NextCursor:    nextCursor,
+		HasMore:       hasMore,
 	})
 }
 
+// SearchConversationsRequest represents query parameters for searching conversations
+type SearchConversationsRequest struct {
+	Query  string `form:"q" binding:"required"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// SearchConversationsResponse represents the response for searching conversations
+type SearchConversationsResponse struct {
+	Conversations []*models.Conversation `json:"conversations"`
+	Total         int                    `json:"total"`
+}
+
+// SearchConversations handles GET /api/conversations/search?q=keyword (agents/admins). Returns the
+// conversations whose messages match the query, best matches first.
+func (h *ConversationHandler) SearchConversations(c *gin.Context) {
+	var req SearchConversationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	conversations, err := h.ingestionService.SearchConversations(tenantID, req.Query, req.Limit, req.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchConversationsResponse{
+		Conversations: conversations,
+		Total:         len(conversations),
+	})
+}
+
+// SemanticSearchRequest represents query parameters for semantic message search
+type SemanticSearchRequest struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+// SemanticSearchResponse represents the response for semantic message search
+type SemanticSearchResponse struct {
+	Results []*chroma.MessageSearchResult `json:"results"`
+	Total   int                           `json:"total"`
+}
+
+// SemanticSearch handles GET /api/conversations/search/semantic?q=text&limit=10 (agents/admins).
+// Returns the tenant's messages closest in meaning to the query, most similar first.
+func (h *ConversationHandler) SemanticSearch(c *gin.Context) {
+	var req SemanticSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > 50 {
+		req.Limit = 50
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	if h.messageSearcher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "semantic search is not available"})
+		return
+	}
+
+	results, err := h.messageSearcher.SearchMessages(tenantID, req.Query, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SemanticSearchResponse{
+		Results: results,
+		Total:   len(results),
+	})
+}
+
+// TransferConversationRequest represents the request body for transferring a conversation
+type TransferConversationRequest struct {
+	ToAgentID string `json:"to_agent_id" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// TransferConversation handles POST /api/conversations/:id/transfer
+func (h *ConversationHandler) TransferConversation(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	var req TransferConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Target must be an active agent or admin within the same tenant
+	agent, err := h.userStorage.GetUser(tenantID, req.ToAgentID)
+	if err != nil || agent.Role == models.RoleCustomer || agent.DeactivatedAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to_agent_id must be an agent in this tenant"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := h.ingestionService.TransferConversation(tenantID, conversationID, req.ToAgentID, req.Reason, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "conversation transferred successfully", "to_agent_id": req.ToAgentID})
+}
+
+// SetConversationLanguageRequest represents the request body for overriding a conversation's language
+type SetConversationLanguageRequest struct {
+	Language string `json:"language" binding:"required"` // ISO 639-1 code, e.g. "hi"
+}
+
+// SetConversationLanguage handles PUT /api/conversations/:id/language
+func (h *ConversationHandler) SetConversationLanguage(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	var req SetConversationLanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !ai.IsValidLanguageCode(strings.TrimSpace(req.Language)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "language must be a supported ISO 639-1 code"})
+		return
+	}
+
+	conv, err := h.ingestionService.SetLanguageOverride(tenantID, conversationID, req.Language)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// UpdateConversationStatusRequest represents the request body for changing a conversation's status
+type UpdateConversationStatusRequest struct {
+	Status string `json:"status" binding:"required"` // closed or archived
+}
+
+// UpdateConversationStatus handles PUT /api/conversations/:id/status
+// Conversations move active → closed → archived; closing runs a final analysis first.
+func (h *ConversationHandler) UpdateConversationStatus(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	var req UpdateConversationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	var conv *models.Conversation
+	var err error
+	switch req.Status {
+	case conversation.StatusClosed:
+		conv, err = h.ingestionService.CloseConversation(tenantID, conversationID, userID)
+	case conversation.StatusArchived:
+		conv, err = h.ingestionService.ArchiveConversation(tenantID, conversationID, userID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be closed or archived"})
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, conversation.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// MergeConversationsRequest represents the request body for merging a duplicate conversation
+type MergeConversationsRequest struct {
+	SecondaryID string `json:"secondary_id" binding:"required"` // Conversation merged into :id and closed
+}
+
+// MergeConversations handles POST /api/conversations/:id/merge (admin only)
+// Moves the secondary conversation's messages into this one and closes the secondary.
+func (h *ConversationHandler) MergeConversations(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req MergeConversationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.SecondaryID == conversationID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a conversation can't be merged into itself"})
+		return
+	}
+
+	if err := h.ingestionService.MergeConversations(tenantID, conversationID, req.SecondaryID); err != nil {
+		switch {
+		case errors.Is(err, conversation.ErrInvalidMerge):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	conv, messages, err := h.ingestionService.GetConversation(tenantID, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, GetConversationResponse{
+		Conversation: conv,
+		Messages:     messages,
+	})
+}
+
+// PatchConversationMetadata handles PATCH /api/conversations/:id/metadata (admin only)
+// Only fields present in the body are changed.
+func (h *ConversationHandler) PatchConversationMetadata(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req postgres.MetadataPatch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.IsEmpty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no metadata fields to update"})
+		return
+	}
+	if err := validateMetadataPatch(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	metadata, err := h.ingestionService.PatchConversationMetadata(tenantID, conversationID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metadata)
+}
+
+// validateMetadataPatch checks scores are within 0-1 and sentiment is a known value
+func validateMetadataPatch(patch *postgres.MetadataPatch) error {
+	if patch.Intent != nil && strings.TrimSpace(*patch.Intent) == "" {
+		return fmt.Errorf("intent cannot be empty")
+	}
+	if patch.Sentiment != nil {
+		switch *patch.Sentiment {
+		case "positive", "neutral", "negative":
+		default:
+			return fmt.Errorf("sentiment must be positive, neutral or negative")
+		}
+	}
+	for name, score := range map[string]*float64{"intent_score": patch.IntentScore, "sentiment_score": patch.SentimentScore} {
+		if score != nil && (*score < 0 || *score > 1) {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+	if patch.ComplexityScore != nil && (*patch.ComplexityScore < 1 || *patch.ComplexityScore > 10) {
+		return fmt.Errorf("complexity_score must be between 1 and 10")
+	}
+	return nil
+}
+
+// TransferHistoryResponse represents the transfer history of a conversation
+type TransferHistoryResponse struct {
+	Transfers []*models.TransferEvent `json:"transfers"`
+}
+
+// GetTransferHistory handles GET /api/conversations/:id/transfer-history
+func (h *ConversationHandler) GetTransferHistory(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	transfers, err := h.ingestionService.GetTransferHistory(tenantID, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TransferHistoryResponse{Transfers: transfers})
+}
+
+// AnalysisHistoryResponse represents the analysis timeline of a conversation
+type AnalysisHistoryResponse struct {
+	Analyses []*models.AnalysisHistoryEntry `json:"analyses"`
+}
+
+// GetAnalysisHistory handles GET /api/conversations/:id/analysis-history. Returns every analysis
+// of the conversation, oldest first, to show how intent and sentiment evolved.
+func (h *ConversationHandler) GetAnalysisHistory(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	analyses, err := h.ingestionService.GetAnalysisHistory(tenantID, conversationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, AnalysisHistoryResponse{Analyses: analyses})
+}
+
+// DeleteMessageRequest represents the request body for deleting a message
+type DeleteMessageRequest struct {
+	Reason string `json:"reason" binding:"required"` // gdpr_request | abuse | error
+}
+
+// DeleteMessage handles PUT /api/conversations/:id/messages/:message_id/delete (admin only)
+func (h *ConversationHandler) DeleteMessage(c *gin.Context) {
+	conversationID := c.Param("id")
+	messageID := c.Param("message_id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req DeleteMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !conversation.IsValidDeletionReason(req.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reason: must be one of gdpr_request, abuse, error"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := h.ingestionService.DeleteMessage(tenantID, conversationID, messageID, userID, req.Reason); err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrMessageAlreadyDeleted):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "message deleted successfully"})
+}
+
+// DeleteConversation handles DELETE /api/conversations/:id (agent or admin)
+// The conversation is soft-deleted and permanently removed after RETENTION_DAYS.
+func (h *ConversationHandler) DeleteConversation(c *gin.Context) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.ingestionService.DeleteConversation(tenantID, conversationID, c.GetString("user_id")); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "already deleted"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "conversation deleted successfully"})
+}
+
+// ListDeletedConversations handles GET /api/admin/conversations/deleted (admin only)
+func (h *ConversationHandler) ListDeletedConversations(c *gin.Context) {
+	var req ListConversationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	conversations, err := h.ingestionService.ListDeletedConversations(tenantID, req.Limit, req.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListConversationsResponse{
+		Conversations: conversations,
+		Total:         len(conversations),
+	})
+}
+
+// WatchlistRequest represents the request body for adding a conversation to the watchlist
+type WatchlistRequest struct {
+	Priority int     `json:"priority"` // Defaults to 1; higher is more important
+	Notes    *string `json:"notes,omitempty"`
+}
+
+// AddToWatchlist handles POST /api/conversations/:id/watchlist (admin only)
+func (h *ConversationHandler) AddToWatchlist(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	// Body is optional
+	var req WatchlistRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Priority < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "priority must be positive"})
+		return
+	}
+
+	entry, err := h.ingestionService.AddToWatchlist(tenantID, conversationID, c.GetString("user_id"), req.Priority, req.Notes)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// RemoveFromWatchlist handles DELETE /api/conversations/:id/watchlist (admin only)
+func (h *ConversationHandler) RemoveFromWatchlist(c *gin.Context) {
+	conversationID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.ingestionService.RemoveFromWatchlist(tenantID, conversationID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "conversation removed from watchlist"})
+}
+
+// MarkMessageRead handles POST /api/conversations/:id/messages/:message_id/read (agent or admin)
+func (h *ConversationHandler) MarkMessageRead(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	err := h.ingestionService.MarkMessageRead(tenantID, c.Param("id"), c.Param("message_id"), c.GetString("user_id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "message marked as read"})
+}
+
+// GetUnreadCount handles GET /api/conversations/:id/unread-count (agent or admin)
+// Counts customer messages that no agent has read yet.
+func (h *ConversationHandler) GetUnreadCount(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	count, err := h.ingestionService.UnreadCount(tenantID, c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversation_id": c.Param("id"), "unread_count": count})
+}
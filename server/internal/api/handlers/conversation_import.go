@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+// maxImportFileBytes caps the size of an uploaded CSV
+const maxImportFileBytes = 20 << 20
+
+// ConversationImportService imports conversations from a CSV
+type ConversationImportService interface {
+	Import(tenantID string, r io.Reader) (conversation.ImportResult, error)
+}
+
+// ConversationImportHandler imports historical conversations
+type ConversationImportHandler struct {
+	importer ConversationImportService
+}
+
+// NewConversationImportHandler creates a new conversation import handler
+func NewConversationImportHandler(importer ConversationImportService) *ConversationImportHandler {
+	return &ConversationImportHandler{importer: importer}
+}
+
+// ImportConversations handles POST /api/admin/conversations/import (Admin only)
+// Multipart form with the CSV in the file field, up to 20MB. Columns: conversation_id,
+// customer_email, agent_email (optional), sender (customer or agent), content, channel (optional)
+// and timestamp (RFC3339). Customers are created as needed. Importing the same file again
+// creates nothing new. Rows that can't be imported are listed in errors.
+func (h *ConversationImportHandler) ImportConversations(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileBytes+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds 20MB"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file exceeds 20MB"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importer.Import(tenantID, file)
+	if errors.Is(err, conversation.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+type fakeConversationImporter struct {
+	result   conversation.ImportResult
+	err      error
+	tenantID string
+	content  string
+}
+
+func (f *fakeConversationImporter) Import(tenantID string, r io.Reader) (conversation.ImportResult, error) {
+	f.tenantID = tenantID
+	data, _ := io.ReadAll(r)
+	f.content = string(data)
+	return f.result, f.err
+}
+
+func multipartCSV(t *testing.T, field, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, "conversations.csv")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestConversationImportHandlerImportConversations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const csv = "conversation_id,customer_email,sender,content,timestamp\nc1,a@example.com,customer,Hi,2024-03-01T10:00:00Z\n"
+	tests := []struct {
+		name     string
+		field    string
+		importer *fakeConversationImporter
+		wantCode int
+	}{
+		{
+			name:     "imports the file",
+			field:    "file",
+			importer: &fakeConversationImporter{result: conversation.ImportResult{RowsProcessed: 1, ConversationsCreated: 1, MessagesCreated: 1, Errors: []conversation.ImportError{}}},
+			wantCode: http.StatusOK,
+		},
+		{name: "missing file", field: "upload", importer: &fakeConversationImporter{}, wantCode: http.StatusBadRequest},
+		{name: "invalid csv", field: "file", importer: &fakeConversationImporter{err: fmt.Errorf("%w: missing column sender", conversation.ErrInvalidImport)}, wantCode: http.StatusBadRequest},
+		{name: "import error", field: "file", importer: &fakeConversationImporter{err: errors.New("db down")}, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConversationImportHandler(tt.importer)
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				c.Set("tenant_id", "tenant-1")
+				c.Set("role", "admin")
+			})
+			engine.POST("/api/admin/conversations/import", handler.ImportConversations)
+
+			body, contentType := multipartCSV(t, tt.field, csv)
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/conversations/import", body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if tt.importer.tenantID != "tenant-1" || tt.importer.content != csv {
+				t.Errorf("imported %q for tenant %q, want the uploaded file for tenant-1", tt.importer.content, tt.importer.tenantID)
+			}
+			var resp conversation.ImportResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.RowsProcessed != 1 || resp.ConversationsCreated != 1 || resp.MessagesCreated != 1 || resp.Errors == nil {
+				t.Errorf("response = %+v, want the import result", resp)
+			}
+		})
+	}
+}
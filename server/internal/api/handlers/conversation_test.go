@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+func TestListConversationsRequestFilters(t *testing.T) {
+	req := ListConversationsRequest{
+		Status:        "closed",
+		CustomerID:    "customer-1",
+		ProductID:     "product-1",
+		AgentID:       "agent-1",
+		CreatedAfter:  "2024-01-01T00:00:00Z",
+		CreatedBefore: "2024-02-01T00:00:00Z",
+		TagID:         "tag-1",
+	}
+	filters, err := req.conversationFilters()
+	if err != nil {
+		t.Fatalf("conversationFilters: %v", err)
+	}
+	if filters.Status != "closed" || filters.CustomerID != "customer-1" || filters.ProductID != "product-1" || filters.AgentID != "agent-1" || filters.TagID != "tag-1" {
+		t.Errorf("filters = %+v, want the request's values", filters)
+	}
+	if filters.CreatedAfter == nil || !filters.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("created_after = %v, want 2024-01-01", filters.CreatedAfter)
+	}
+	if filters.CreatedBefore == nil || !filters.CreatedBefore.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("created_before = %v, want 2024-02-01", filters.CreatedBefore)
+	}
+
+	empty, err := (&ListConversationsRequest{}).conversationFilters()
+	if err != nil {
+		t.Fatalf("conversationFilters without params: %v", err)
+	}
+	if empty.CreatedAfter != nil || empty.CreatedBefore != nil || empty.Status != "" {
+		t.Errorf("filters = %+v, want no filters", empty)
+	}
+}
+
+func TestListConversationsRequestFiltersRejectsInvalidParams(t *testing.T) {
+	tests := map[string]ListConversationsRequest{
+		"unknown status":          {Status: "pending"},
+		"malformed after":         {CreatedAfter: "2024-01-01"},
+		"malformed before":        {CreatedBefore: "yesterday"},
+		"after later than before": {CreatedAfter: "2024-02-01T00:00:00Z", CreatedBefore: "2024-01-01T00:00:00Z"},
+	}
+	for name, req := range tests {
+		if _, err := req.conversationFilters(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExportConversationRejectsBeforeLoading(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		role string
+		path string
+		want int
+	}{
+		{name: "customer", role: "customer", path: "/api/conversations/conv-1/export?format=json", want: http.StatusForbidden},
+		{name: "unknown format", role: "agent", path: "/api/conversations/conv-1/export?format=xml", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConversationHandler(nil, nil)
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				c.Set("tenant_id", "tenant-1")
+				c.Set("role", tt.role)
+			})
+			engine.GET("/api/conversations/:id/export", handler.ExportConversation)
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if rec.Header().Get("Content-Disposition") != "" {
+				t.Error("expected no attachment on a rejected export")
+			}
+		})
+	}
+}
+
+func TestUpdateConversationStatusRejectsBeforeLoading(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		role string
+		body string
+		want int
+	}{
+		{name: "customer", role: "customer", body: `{"status":"closed"}`, want: http.StatusForbidden},
+		{name: "missing status", role: "agent", body: `{}`, want: http.StatusBadRequest},
+		{name: "reopen", role: "agent", body: `{"status":"active"}`, want: http.StatusBadRequest},
+		{name: "unknown status", role: "admin", body: `{"status":"deleted"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConversationHandler(nil, nil)
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				c.Set("tenant_id", "tenant-1")
+				c.Set("role", tt.role)
+			})
+			engine.PUT("/api/conversations/:id/status", handler.UpdateConversationStatus)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/conversations/conv-1/status", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			engine.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMergeConversationsRejectsBeforeLoading(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, body := range map[string]string{
+		"missing secondary": `{}`,
+		"merge into itself": `{"secondary_id":"conv-1"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewConversationHandler(nil, nil)
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				c.Set("tenant_id", "tenant-1")
+				c.Set("role", "admin")
+			})
+			engine.POST("/api/conversations/:id/merge", handler.MergeConversations)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/conversations/conv-1/merge", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			engine.ServeHTTP(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// fakeMessageSearcher records semantic searches and returns fixed results
+type fakeMessageSearcher struct {
+	tenantID string
+	query    string
+	limit    int
+}
+
+func (f *fakeMessageSearcher) SearchMessages(tenantID, query string, limit int) ([]*chroma.MessageSearchResult, error) {
+	f.tenantID, f.query, f.limit = tenantID, query, limit
+	return []*chroma.MessageSearchResult{{ConversationID: "conv-1", MessageID: "msg-1", Sender: "customer", Preview: "refund please", Score: 0.9}}, nil
+}
+
+func TestSemanticSearch(t *testing.T) {
+	agent := testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}
+	route := "/api/conversations/search/semantic"
+	tests := []struct {
+		name      string
+		path      string
+		identity  testContext
+		noSearch  bool
+		want      int
+		wantLimit int
+	}{
+		{name: "default limit", path: route + "?q=refund", identity: agent, want: http.StatusOK, wantLimit: 10},
+		{name: "limit capped", path: route + "?q=refund&limit=500", identity: agent, want: http.StatusOK, wantLimit: 50},
+		{name: "missing query", path: route + "?q=%20", identity: agent, want: http.StatusBadRequest},
+		{name: "customer", path: route + "?q=refund", identity: testContext{tenantID: "tenant-1", role: "customer"}, want: http.StatusForbidden},
+		{name: "search unavailable", path: route + "?q=refund", identity: agent, noSearch: true, want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &fakeMessageSearcher{}
+			handler := NewConversationHandler(nil, nil)
+			if !tt.noSearch {
+				handler.SetMessageSearcher(searcher)
+			}
+
+			rec := serveHandler(route, http.MethodGet, tt.path, tt.identity, handler.SemanticSearch)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if searcher.tenantID != "tenant-1" || searcher.query != "refund" || searcher.limit != tt.wantLimit {
+				t.Errorf("search = %+v, want tenant-1 %q with limit %d", searcher, "refund", tt.wantLimit)
+			}
+			var resp SemanticSearchResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Total != 1 || resp.Results[0].ConversationID != "conv-1" {
+				t.Errorf("response = %+v, want the searcher's result", resp)
+			}
+		})
+	}
+}
+
+func TestGetConversationSummaryRejectsBeforeLoading(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity testContext
+		want     int
+	}{
+		{name: "customer", identity: testContext{tenantID: "tenant-1", role: "customer"}, want: http.StatusForbidden},
+		{name: "missing tenant", identity: testContext{role: "agent"}, want: http.StatusUnauthorized},
+		{name: "summaries not configured", identity: testContext{tenantID: "tenant-1", role: "agent"}, want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConversationHandler(nil, nil)
+			rec := serveHandler("/api/conversations/:id/summary", http.MethodGet, "/api/conversations/conv-1/summary", tt.identity, handler.GetConversationSummary)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestConversationNotesOnlyForAgentsAndAdmins(t *testing.T) {
+	handler := NewConversationHandler(nil, nil)
+	if notes := handler.conversationNotes("agent", "tenant-1", "c1"); notes != nil {
+		t.Errorf("notes without a lister = %+v, want none", notes)
+	}
+
+	handler.SetNoteLister(&fakeNoteStore{notes: []*models.ConversationNote{
+		{ID: "note-1", ConversationID: "c1", TenantID: "tenant-1", AgentID: "agent-1", Content: "Internal only"},
+	}})
+	for _, role := range []string{"agent", "admin"} {
+		if notes := handler.conversationNotes(role, "tenant-1", "c1"); len(notes) != 1 || notes[0].Content != "Internal only" {
+			t.Errorf("%s notes = %+v, want the conversation's note", role, notes)
+		}
+	}
+	if notes := handler.conversationNotes("customer", "tenant-1", "c1"); notes != nil {
+		t.Errorf("customer notes = %+v, want none", notes)
+	}
+	if notes := handler.conversationNotes("admin", "tenant-2", "c1"); len(notes) != 0 {
+		t.Errorf("other tenant notes = %+v, want none", notes)
+	}
+}
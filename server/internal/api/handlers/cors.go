@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// CORSConfigHandler handles tenant CORS allowlist configuration
+type CORSConfigHandler struct {
+	corsStorage *postgres.CORSConfigStorage
+}
+
+// NewCORSConfigHandler creates a new CORS config handler
+func NewCORSConfigHandler(corsStorage *postgres.CORSConfigStorage) *CORSConfigHandler {
+	return &CORSConfigHandler{corsStorage: corsStorage}
+}
+
+// CORSConfigRequest represents the request body for updating allowed origins
+type CORSConfigRequest struct {
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+// CORSConfigResponse represents the tenant CORS configuration
+type CORSConfigResponse struct {
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+// GetCORSConfig handles GET /api/admin/cors-config (admin only)
+func (h *CORSConfigHandler) GetCORSConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	origins, err := h.corsStorage.GetAllowedOrigins(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, CORSConfigResponse{AllowedOrigins: origins})
+}
+
+// UpdateCORSConfig handles PUT /api/admin/cors-config (admin only)
+func (h *CORSConfigHandler) UpdateCORSConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req CORSConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	origins := make([]string, 0, len(req.AllowedOrigins))
+	for _, origin := range req.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") && !strings.HasPrefix(origin, "*.") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid origin: " + origin + " (must start with http://, https:// or *.)"})
+			return
+		}
+		origins = append(origins, origin)
+	}
+
+	if err := h.corsStorage.SetAllowedOrigins(tenantID, origins); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, CORSConfigResponse{AllowedOrigins: origins})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// CredentialsHandler handles tenant API credential management
+type CredentialsHandler struct {
+	credentialStorage *postgres.CredentialStorage
+	clientFactory     *ai.GeminiClientFactory
+}
+
+// NewCredentialsHandler creates a new credentials handler
+func NewCredentialsHandler(credentialStorage *postgres.CredentialStorage, clientFactory *ai.GeminiClientFactory) *CredentialsHandler {
+	return &CredentialsHandler{
+		credentialStorage: credentialStorage,
+		clientFactory:     clientFactory,
+	}
+}
+
+// SetGeminiKeyRequest represents the request body for setting a Gemini API key
+type SetGeminiKeyRequest struct {
+	APIKey string `json:"api_key" binding:"required"`
+}
+
+// SetGeminiKey handles PUT /api/admin/credentials/gemini (admin only)
+func (h *CredentialsHandler) SetGeminiKey(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if !h.credentialStorage.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "credential storage is not configured (CREDENTIAL_MASTER_KEY)"})
+		return
+	}
+
+	var req SetGeminiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apiKey := strings.TrimSpace(req.APIKey)
+	if apiKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "api_key is required"})
+		return
+	}
+
+	if err := h.credentialStorage.SetAPIKey(tenantID, postgres.ProviderGemini, apiKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.invalidate(tenantID)
+
+	h.respondStatus(c, tenantID)
+}
+
+// DeleteGeminiKey handles DELETE /api/admin/credentials/gemini (admin only).
+// The tenant falls back to the server-wide GEMINI_API_KEY.
+func (h *CredentialsHandler) DeleteGeminiKey(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.credentialStorage.DeleteAPIKey(tenantID, postgres.ProviderGemini); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.invalidate(tenantID)
+
+	h.respondStatus(c, tenantID)
+}
+
+// GetGeminiKeyStatus handles GET /api/admin/credentials/gemini/status (admin only).
+// The key itself is never returned.
+func (h *CredentialsHandler) GetGeminiKeyStatus(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	h.respondStatus(c, tenantID)
+}
+
+func (h *CredentialsHandler) respondStatus(c *gin.Context, tenantID string) {
+	status, err := h.credentialStorage.GetStatus(tenantID, postgres.ProviderGemini)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *CredentialsHandler) invalidate(tenantID string) {
+	if h.clientFactory != nil {
+		h.clientFactory.Invalidate(tenantID)
+	}
+}
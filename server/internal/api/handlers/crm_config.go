@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/integrations/crm"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// CRMConfigHandler handles per-tenant CRM field mappings for webhook payloads
+type CRMConfigHandler struct {
+	mappingStorage *postgres.CRMFieldMappingStorage
+	fieldMapper    *crm.FieldMapper
+}
+
+// NewCRMConfigHandler creates a new CRM config handler
+func NewCRMConfigHandler(mappingStorage *postgres.CRMFieldMappingStorage) *CRMConfigHandler {
+	return &CRMConfigHandler{
+		mappingStorage: mappingStorage,
+		fieldMapper:    crm.NewFieldMapper(nil),
+	}
+}
+
+// CRMConfigRequest represents the request body for setting a CRM field mapping
+type CRMConfigRequest struct {
+	Mappings map[string]string `json:"mappings" binding:"required"` // Platform field name -> CRM field name
+}
+
+// TestCRMConfigResponse shows a sample payload before and after the mapping
+type TestCRMConfigResponse struct {
+	CRMType       string                 `json:"crm_type"`
+	Mappings      map[string]string      `json:"mappings"`
+	SamplePayload map[string]interface{} `json:"sample_payload"`
+	MappedPayload map[string]interface{} `json:"mapped_payload"`
+}
+
+// crmTypeParam validates the :crm_type path param, writing a 400 when it isn't supported
+func crmTypeParam(c *gin.Context) (string, bool) {
+	crmType := strings.ToLower(c.Param("crm_type"))
+	if !crm.IsValidType(crmType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "crm_type must be one of: hubspot, salesforce, zoho, custom"})
+		return "", false
+	}
+	return crmType, true
+}
+
+// UpdateCRMConfig handles PUT /api/admin/crm-config/:crm_type (admin only)
+func (h *CRMConfigHandler) UpdateCRMConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	crmType, ok := crmTypeParam(c)
+	if !ok {
+		return
+	}
+
+	var req CRMConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mappings := make(map[string]string, len(req.Mappings))
+	for source, target := range req.Mappings {
+		mappings[strings.TrimSpace(source)] = strings.TrimSpace(target)
+	}
+	if err := crm.ValidateMappings(mappings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mapping := &postgres.CRMFieldMapping{
+		TenantID: tenantID,
+		CRMType:  crmType,
+		Mappings: mappings,
+	}
+	if err := h.mappingStorage.SetCRMFieldMapping(mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
+
+// TestCRMConfig handles GET /api/admin/crm-config/:crm_type/test (admin only).
+// Applies the saved mapping to a sample webhook payload.
+func (h *CRMConfigHandler) TestCRMConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	crmType, ok := crmTypeParam(c)
+	if !ok {
+		return
+	}
+
+	mapping, err := h.mappingStorage.GetCRMFieldMapping(tenantID, crmType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if mapping == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "crm field mapping not found"})
+		return
+	}
+
+	sample := crm.SamplePayload()
+	c.JSON(http.StatusOK, TestCRMConfigResponse{
+		CRMType:       crmType,
+		Mappings:      mapping.Mappings,
+		SamplePayload: sample,
+		MappedPayload: h.fieldMapper.Apply(sample, mapping.Mappings),
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+// defaultDashboardStreamInterval is how often the dashboard stream sends metrics when no
+// conversation changes
+const defaultDashboardStreamInterval = 30 * time.Second
+
+// SetDashboardUpdates pushes dashboard stream updates as soon as a message is ingested (optional).
+// Without it, streams only refresh on their interval.
+func (h *AnalyticsHandler) SetDashboardUpdates(updates *conversation.DashboardUpdates) {
+	h.dashboardUpdates = updates
+}
+
+// StreamDashboard handles GET /api/analytics/dashboard/stream (agent or admin)
+// Server-sent events: a dashboard event with the DashboardMetrics JSON on connect, every 30
+// seconds, and right after a message is ingested for the tenant. Takes the same filters as
+// GetDashboard. Streamed metrics are always freshly computed.
+func (h *AnalyticsHandler) StreamDashboard(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if role := c.GetString("role"); role != "agent" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent access required"})
+		return
+	}
+
+	filters, err := parseDashboardFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var updates <-chan struct{}
+	if h.dashboardUpdates != nil {
+		var unsubscribe func()
+		updates, unsubscribe = h.dashboardUpdates.Subscribe(tenantID)
+		defer unsubscribe()
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	h.writeDashboardEvent(c, tenantID, filters)
+
+	ticker := time.NewTicker(h.dashboardInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		case <-updates: // Nil without dashboard updates, so never ready
+		}
+		h.writeDashboardEvent(c, tenantID, filters)
+	}
+}
+
+// writeDashboardEvent computes the metrics and sends them as a dashboard event. Failures are sent
+// as an error event; the stream stays open and retries on the next tick or update.
+func (h *AnalyticsHandler) writeDashboardEvent(c *gin.Context, tenantID string, filters analytics.DashboardFilters) {
+	metrics, err := h.analyticsService.RefreshDashboardMetrics(tenantID, filters)
+	if err != nil {
+		log.Printf("[ANALYTICS_HANDLER] dashboard stream refresh failed tenant=%s error=%v", tenantID, err)
+		writeSSE(c, "error", gin.H{"error": "failed to compute dashboard metrics"})
+		return
+	}
+	writeSSE(c, "dashboard", metrics)
+}
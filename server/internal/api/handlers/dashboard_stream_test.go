@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+// countingDashboardService reports how many times the dashboard was computed as TotalConversations
+type countingDashboardService struct {
+	MockAnalyticsService
+	refreshes atomic.Int64
+}
+
+func (s *countingDashboardService) RefreshDashboardMetrics(tenantID string, filters analytics.DashboardFilters) (analytics.DashboardMetrics, error) {
+	return analytics.DashboardMetrics{TotalConversations: int(s.refreshes.Add(1))}, nil
+}
+
+func newDashboardStream(t *testing.T, handler *AnalyticsHandler, role string) *bufio.Reader {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/api/analytics/dashboard/stream", func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", role)
+		handler.StreamDashboard(c)
+	})
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/analytics/dashboard/stream", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, content type %q, want an event stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// nextDashboardEvent reads the next dashboard event, failing the test if none arrives within timeout
+func nextDashboardEvent(t *testing.T, stream *bufio.Reader, timeout time.Duration) analytics.DashboardMetrics {
+	t.Helper()
+	events := make(chan analytics.DashboardMetrics, 1)
+	go func() {
+		var event string
+		for {
+			line, err := stream.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: ") && event == "dashboard":
+				var metrics analytics.DashboardMetrics
+				if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &metrics) == nil {
+					events <- metrics
+				}
+				return
+			}
+		}
+	}()
+	select {
+	case metrics := <-events:
+		return metrics
+	case <-time.After(timeout):
+		t.Fatalf("no dashboard event within %v", timeout)
+		return analytics.DashboardMetrics{}
+	}
+}
+
+func TestStreamDashboardPushesOnUpdate(t *testing.T) {
+	service := &countingDashboardService{}
+	updates := conversation.NewDashboardUpdates()
+	handler := NewAnalyticsHandler(service, nil, nil)
+	handler.SetDashboardUpdates(updates)
+	handler.dashboardInterval = time.Hour // Only updates can trigger a push within the test
+
+	stream := newDashboardStream(t, handler, "agent")
+	if metrics := nextDashboardEvent(t, stream, 2*time.Second); metrics.TotalConversations != 1 {
+		t.Fatalf("first event = %+v, want the metrics on connect", metrics)
+	}
+
+	// A message ingested for another tenant doesn't refresh this dashboard
+	updates.Publish("tenant-2")
+	updates.Publish("tenant-1")
+	if metrics := nextDashboardEvent(t, stream, time.Second); metrics.TotalConversations != 2 {
+		t.Errorf("pushed event = %+v, want freshly computed metrics", metrics)
+	}
+	updates.Publish("tenant-1")
+	if metrics := nextDashboardEvent(t, stream, time.Second); metrics.TotalConversations != 3 {
+		t.Errorf("pushed event = %+v, want freshly computed metrics", metrics)
+	}
+}
+
+func TestStreamDashboardRefreshesOnInterval(t *testing.T) {
+	service := &countingDashboardService{}
+	handler := NewAnalyticsHandler(service, nil, nil)
+	handler.dashboardInterval = 20 * time.Millisecond
+
+	stream := newDashboardStream(t, handler, "admin")
+	for want := 1; want <= 3; want++ {
+		if metrics := nextDashboardEvent(t, stream, 2*time.Second); metrics.TotalConversations != want {
+			t.Errorf("event %d = %+v, want %d computations", want, metrics, want)
+		}
+	}
+}
+
+func TestStreamDashboardRejections(t *testing.T) {
+	handler := NewAnalyticsHandler(&countingDashboardService{}, nil, nil)
+	tests := []struct {
+		name     string
+		identity testContext
+		path     string
+		wantCode int
+	}{
+		{name: "customer", identity: testContext{tenantID: "tenant-1", userID: "cust-1", role: "customer"}, path: "/api/analytics/dashboard/stream", wantCode: http.StatusForbidden},
+		{name: "missing tenant", identity: testContext{role: "admin"}, path: "/api/analytics/dashboard/stream", wantCode: http.StatusUnauthorized},
+		{name: "invalid status", identity: testContext{tenantID: "tenant-1", role: "admin"}, path: "/api/analytics/dashboard/stream?status=open", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveHandler("/api/analytics/dashboard/stream", http.MethodGet, tt.path, tt.identity, handler.StreamDashboard)
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
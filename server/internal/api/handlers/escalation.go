@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// EscalationLister lists a tenant's open escalations
+type EscalationLister interface {
+	ListOpenEscalations(tenantID string) ([]*postgres.EscalationEvent, error)
+}
+
+// EscalationHandler serves conversations escalated to a human
+type EscalationHandler struct {
+	escalations EscalationLister
+}
+
+// NewEscalationHandler creates a new escalation handler
+func NewEscalationHandler(escalations EscalationLister) *EscalationHandler {
+	return &EscalationHandler{escalations: escalations}
+}
+
+// ListEscalationsResponse represents the response for listing escalations
+type ListEscalationsResponse struct {
+	Escalations []*postgres.EscalationEvent `json:"escalations"`
+	Total       int                         `json:"total"`
+}
+
+// ListEscalations handles GET /api/escalations (agent or admin)
+// Lists open escalations, worst sentiment first. Escalations resolve on their own once a later
+// analysis no longer triggers them.
+func (h *EscalationHandler) ListEscalations(c *gin.Context) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	escalations, err := h.escalations.ListOpenEscalations(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if escalations == nil {
+		escalations = []*postgres.EscalationEvent{}
+	}
+
+	c.JSON(http.StatusOK, ListEscalationsResponse{Escalations: escalations, Total: len(escalations)})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeEscalationLister struct {
+	escalations []*postgres.EscalationEvent
+	err         error
+	tenantID    string
+}
+
+func (f *fakeEscalationLister) ListOpenEscalations(tenantID string) ([]*postgres.EscalationEvent, error) {
+	f.tenantID = tenantID
+	return f.escalations, f.err
+}
+
+func TestEscalationHandlerListEscalations(t *testing.T) {
+	agent := testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}
+	tests := []struct {
+		name      string
+		identity  testContext
+		lister    *fakeEscalationLister
+		wantCode  int
+		wantTotal int
+	}{
+		{
+			name:     "lists open escalations",
+			identity: agent,
+			lister: &fakeEscalationLister{escalations: []*postgres.EscalationEvent{
+				{ID: "e1", ConversationID: "c1", SentimentScore: 0.05, Reason: postgres.EscalationReasonSentiment},
+				{ID: "e2", ConversationID: "c2", SentimentScore: 0.2, Reason: postgres.EscalationReasonRule},
+			}},
+			wantCode:  http.StatusOK,
+			wantTotal: 2,
+		},
+		{name: "none open", identity: agent, lister: &fakeEscalationLister{}, wantCode: http.StatusOK},
+		{name: "customer", identity: testContext{tenantID: "tenant-1", userID: "cust-1", role: "customer"}, lister: &fakeEscalationLister{}, wantCode: http.StatusForbidden},
+		{name: "missing tenant", identity: testContext{role: "admin"}, lister: &fakeEscalationLister{}, wantCode: http.StatusUnauthorized},
+		{name: "storage error", identity: agent, lister: &fakeEscalationLister{err: errors.New("db down")}, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewEscalationHandler(tt.lister)
+			rec := serveHandler("/escalations", http.MethodGet, "/escalations", tt.identity, handler.ListEscalations)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if tt.lister.tenantID != "tenant-1" {
+				t.Errorf("listed tenant %q, want tenant-1", tt.lister.tenantID)
+			}
+			var resp ListEscalationsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Escalations == nil || len(resp.Escalations) != tt.wantTotal || resp.Total != tt.wantTotal {
+				t.Errorf("response = %+v, want %d escalations", resp, tt.wantTotal)
+			}
+		})
+	}
+}
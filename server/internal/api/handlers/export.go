@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/export"
+	"ai-conversation-platform/internal/services/analytics"
+)
+
+// Export formats
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+// ExportAnalytics handles GET /api/analytics/export (Admin only)
+// Query params: type (leads|dashboard|agent_performance), format (csv|json),
+// from, to (RFC3339, defaults to the last 30 days). The file is streamed and
+// gzip-compressed when the client sends Accept-Encoding: gzip.
+func (h *AnalyticsHandler) ExportAnalytics(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	exportType := c.DefaultQuery("type", analytics.ExportTypeLeads)
+	if !analytics.IsValidExportType(exportType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, must be one of: leads, dashboard, agent_performance"})
+		return
+	}
+
+	format := c.DefaultQuery("format", exportFormatCSV)
+	if format != exportFormatCSV && format != exportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, must be csv or json"})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, 30)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Non-streamed exports are computed up front so failures can still return a JSON error
+	var dashboard analytics.DashboardMetrics
+	var agents []analytics.AgentLeaderboardEntry
+	switch exportType {
+	case analytics.ExportTypeDashboard:
+		dashboard, err = h.analyticsService.GetDashboardMetrics(tenantID, analytics.DashboardFilters{})
+	case analytics.ExportTypeAgentPerformance:
+		agents, err = h.analyticsService.GetLeaderboard(tenantID, from, to, analytics.LeaderboardSortScore, 0)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", strings.ReplaceAll(exportType, "_", "-"), time.Now().Format("2006-01-02"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == exportFormatCSV {
+		c.Header("Content-Type", "text/csv")
+	} else {
+		c.Header("Content-Type", "application/json")
+	}
+
+	var out io.Writer = c.Writer
+	if strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+		c.Header("Content-Encoding", "gzip")
+		c.Header("Vary", "Accept-Encoding")
+		gz := gzip.NewWriter(c.Writer)
+		defer gz.Close()
+		out = gz
+	}
+	c.Status(http.StatusOK)
+
+	switch exportType {
+	case analytics.ExportTypeLeads:
+		err = h.streamLeads(out, c, tenantID, from, to, format)
+	case analytics.ExportTypeDashboard:
+		if format == exportFormatCSV {
+			err = analytics.NewCSVExporter().ExportDashboard(out, dashboard)
+		} else {
+			err = json.NewEncoder(out).Encode([]analytics.DashboardMetrics{dashboard})
+		}
+	case analytics.ExportTypeAgentPerformance:
+		if format == exportFormatCSV {
+			err = analytics.NewCSVExporter().ExportAgentPerformance(out, agents)
+		} else {
+			if agents == nil {
+				agents = []analytics.AgentLeaderboardEntry{}
+			}
+			err = json.NewEncoder(out).Encode(agents)
+		}
+	}
+
+	// Headers are already sent, so a failure can only be logged and the stream cut short
+	if err != nil {
+		log.Printf("[EXPORT] export failed tenant=%s type=%s error=%v", tenantID, exportType, err)
+		c.Abort()
+	}
+}
+
+// ExportLeads handles GET /api/analytics/leads/export?format=csv
+// Scores all of the tenant's conversations and streams the leads pipeline as a CSV attachment,
+// one page of leads at a time.
+func (h *AnalyticsHandler) ExportLeads(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if format := c.DefaultQuery("format", exportFormatCSV); format != exportFormatCSV {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, must be csv"})
+		return
+	}
+
+	filename := fmt.Sprintf("leads_%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+
+	exporter := analytics.NewCSVExporter()
+	// The zero start time covers every conversation the tenant has
+	err := h.analyticsService.StreamLeads(tenantID, time.Time{}, time.Now(), func(leads []analytics.PrioritizedLead) error {
+		h.populateCustomerEmails(tenantID, leads)
+		if err := exporter.ExportLeadPipeline(c.Writer, leads); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		// An empty export still gets a header row
+		err = exporter.ExportLeadPipeline(c.Writer, nil)
+	}
+
+	// Headers are already sent, so a failure can only be logged and the stream cut short
+	if err != nil {
+		log.Printf("[EXPORT] leads export failed tenant=%s error=%v", tenantID, err)
+		c.Abort()
+	}
+}
+
+// streamLeads writes leads page by page, flushing after each page
+func (h *AnalyticsHandler) streamLeads(out io.Writer, c *gin.Context, tenantID string, from, to time.Time, format string) error {
+	exporter := analytics.NewCSVExporter()
+	first := true
+
+	if format == exportFormatJSON {
+		if _, err := io.WriteString(out, "["); err != nil {
+			return err
+		}
+	}
+
+	err := h.analyticsService.StreamLeads(tenantID, from, to, func(leads []analytics.PrioritizedLead) error {
+		h.populateCustomerEmails(tenantID, leads)
+
+		if format == exportFormatCSV {
+			if err := exporter.ExportLeads(out, leads); err != nil {
+				return err
+			}
+		} else {
+			for _, lead := range leads {
+				if !first {
+					if _, err := io.WriteString(out, ","); err != nil {
+						return err
+					}
+				}
+				first = false
+				data, err := json.Marshal(lead)
+				if err != nil {
+					return fmt.Errorf("failed to marshal lead: %w", err)
+				}
+				if _, err := out.Write(data); err != nil {
+					return err
+				}
+			}
+		}
+
+		if gz, ok := out.(*gzip.Writer); ok {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == exportFormatCSV {
+		// An empty export still gets a header row
+		return exporter.ExportLeads(out, nil)
+	}
+	_, err = io.WriteString(out, "]\n")
+	return err
+}
+
+// ExportConversation handles GET /api/conversations/:id/export?format=json|csv (agents/admins).
+// The conversation is sent as an attachment: JSON has a header block with the conversation and
+// its analysis metadata followed by the messages; CSV has one row per message.
+func (h *ConversationHandler) ExportConversation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatJSON)
+	if !export.IsValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format, must be json or csv"})
+		return
+	}
+
+	conversationID := c.Param("id")
+	conv, messages, err := h.ingestionService.GetConversation(tenantID, conversationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	metadata, err := h.ingestionService.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="conversation-%s.%s"`, conv.ID, format))
+	exporter := export.NewConversationExporter()
+	if format == export.FormatCSV {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		err = exporter.ToCSV(c.Writer, conv, messages, metadata)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		err = exporter.ToJSON(c.Writer, conv, messages, metadata)
+	}
+	if err != nil {
+		log.Printf("[EXPORT] conversation export failed tenant=%s conversation=%s error=%v", tenantID, conversationID, err)
+		c.Abort()
+	}
+}
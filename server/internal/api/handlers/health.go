@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/health"
+)
+
+// HealthHandler handles health and Kubernetes probe endpoints
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Health handles GET /health. Dependency statuses are only returned with an X-Health-Token
+// matching HEALTH_TOKEN; other callers get the plain status so existing probes keep working.
+func (h *HealthHandler) Health(c *gin.Context) {
+	if !validHealthToken(c.GetHeader("X-Health-Token")) {
+		c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+		return
+	}
+
+	report := h.checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == health.StatusUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Ready handles GET /health/ready, succeeding only when the database is reachable
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if err := h.checker.Ready(c.Request.Context()); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": health.StatusUnhealthy})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Live handles GET /health/live, succeeding whenever the process is serving requests
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// validHealthToken reports whether token matches HEALTH_TOKEN. Always false when HEALTH_TOKEN is unset.
+func validHealthToken(token string) bool {
+	expected := os.Getenv("HEALTH_TOKEN")
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// minPasswordLength is the shortest password accepted when joining through an invitation
+const minPasswordLength = 8
+
+// InviteNotifier delivers invitation tokens to invitees
+type InviteNotifier interface {
+	SendInvite(to, token string) error
+}
+
+// SetInvitations enables inviting users (optional). notifier may be nil, in which case the
+// token is returned to the admin to pass on.
+func (h *UserAdminHandler) SetInvitations(invitations auth.InvitationStore, notifier InviteNotifier) {
+	h.invitations = invitations
+	h.inviteNotifier = notifier
+}
+
+// InviteUserRequest represents the request body for inviting a user
+type InviteUserRequest struct {
+	Email string `json:"email" binding:"required"`
+	Role  string `json:"role" binding:"required"` // agent or admin
+}
+
+// InviteUserResponse represents the response for inviting a user
+type InviteUserResponse struct {
+	Invitation *auth.InvitationRecord `json:"invitation"`
+	EmailSent  bool                   `json:"email_sent"`
+	Token      string                 `json:"token,omitempty"` // Only when the invitation couldn't be emailed
+}
+
+// InviteUser handles POST /api/admin/users/invite (admin only)
+// Invites email to join the tenant as an agent or admin. The invitee gets a token, valid for 7
+// days, to accept at POST /api/auth/accept-invite. Inviting the same email again replaces the
+// pending invitation.
+func (h *UserAdminHandler) InviteUser(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if h.invitations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "invitations are not configured"})
+		return
+	}
+
+	var req InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !strings.Contains(req.Email, "@") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email must be an email address"})
+		return
+	}
+	if models.UserRole(req.Role) == models.RoleSuperAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admins cannot invite super admins"})
+		return
+	}
+
+	token, invitation, err := auth.IssueInvitation(h.invitations, tenantID, req.Email, req.Role, c.GetString("user_id"))
+	switch {
+	case errors.Is(err, auth.ErrInvitationRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, postgres.ErrUserEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := InviteUserResponse{Invitation: invitation}
+	if h.inviteNotifier != nil {
+		if err := h.inviteNotifier.SendInvite(invitation.Email, token); err != nil {
+			log.Printf("[INVITE] failed to email invitation=%s tenant=%s error=%v", invitation.ID, tenantID, err)
+		} else {
+			resp.EmailSent = true
+		}
+	}
+	if !resp.EmailSent {
+		resp.Token = token
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// SetInvitationStore enables accepting invitations (optional)
+func (h *AuthHandler) SetInvitationStore(invitations auth.InvitationStore) {
+	h.invitations = invitations
+}
+
+// AcceptInviteRequest represents the request body for accepting an invitation
+type AcceptInviteRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AcceptInvite handles POST /api/auth/accept-invite
+// Creates the invited user with the password; they can then log in. Each invitation can be
+// accepted once.
+func (h *AuthHandler) AcceptInvite(c *gin.Context) {
+	if h.invitations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "invitations are not configured"})
+		return
+	}
+
+	var req AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters"})
+		return
+	}
+
+	user, err := auth.AcceptInvitation(h.invitations, req.Token, req.Password)
+	switch {
+	case errors.Is(err, auth.ErrInvalidInvitation):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, auth.ErrInvitationExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, auth.ErrInvitationUsed), errors.Is(err, postgres.ErrUserEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invitation"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeInvitationStore keeps invitations in memory; emails in taken are already registered
+type fakeInvitationStore struct {
+	invitations map[string]*auth.InvitationRecord
+	taken       map[string]bool
+	users       []*models.User
+}
+
+func newFakeInvitationStore() *fakeInvitationStore {
+	return &fakeInvitationStore{invitations: map[string]*auth.InvitationRecord{}, taken: map[string]bool{}}
+}
+
+func (f *fakeInvitationStore) CreateInvitation(record *auth.InvitationRecord) error {
+	if f.taken[record.Email] {
+		return postgres.ErrUserEmailTaken
+	}
+	copied := *record
+	f.invitations[record.ID] = &copied
+	return nil
+}
+
+func (f *fakeInvitationStore) GetInvitation(id string) (*auth.InvitationRecord, error) {
+	record, ok := f.invitations[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (f *fakeInvitationStore) AcceptInvitation(id string, user *models.User) (bool, error) {
+	record := f.invitations[id]
+	if record.Status != auth.InvitationPending {
+		return false, nil
+	}
+	record.Status = auth.InvitationAccepted
+	f.users = append(f.users, user)
+	return true, nil
+}
+
+type fakeInviteNotifier struct {
+	to, token string
+	err       error
+}
+
+func (f *fakeInviteNotifier) SendInvite(to, token string) error {
+	f.to, f.token = to, token
+	return f.err
+}
+
+func postJSON(engine *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func newInvitationEngine(store auth.InvitationStore, notifier InviteNotifier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	users := NewUserAdminHandler(nil)
+	if store != nil {
+		users.SetInvitations(store, notifier)
+	}
+	authHandler := NewAuthHandler(nil, nil, nil)
+	if store != nil {
+		authHandler.SetInvitationStore(store)
+	}
+
+	engine := gin.New()
+	engine.POST("/api/auth/accept-invite", authHandler.AcceptInvite)
+	admin := engine.Group("/api/admin", func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("user_id", "admin-1")
+		c.Set("role", "admin")
+	})
+	admin.POST("/users/invite", users.InviteUser)
+	return engine
+}
+
+func TestInviteUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		notifier      *fakeInviteNotifier
+		taken         string
+		wantCode      int
+		wantEmailSent bool
+	}{
+		{name: "emails the invitation", body: `{"email": "agent@example.com", "role": "agent"}`, notifier: &fakeInviteNotifier{}, wantCode: http.StatusCreated, wantEmailSent: true},
+		{name: "without a notifier", body: `{"email": "admin@example.com", "role": "admin"}`, wantCode: http.StatusCreated},
+		{name: "email fails", body: `{"email": "agent@example.com", "role": "agent"}`, notifier: &fakeInviteNotifier{err: errors.New("smtp down")}, wantCode: http.StatusCreated},
+		{name: "super admin", body: `{"email": "root@example.com", "role": "super_admin"}`, notifier: &fakeInviteNotifier{}, wantCode: http.StatusForbidden},
+		{name: "customer", body: `{"email": "c@example.com", "role": "customer"}`, notifier: &fakeInviteNotifier{}, wantCode: http.StatusBadRequest},
+		{name: "invalid email", body: `{"email": "agent", "role": "agent"}`, notifier: &fakeInviteNotifier{}, wantCode: http.StatusBadRequest},
+		{name: "missing role", body: `{"email": "agent@example.com"}`, notifier: &fakeInviteNotifier{}, wantCode: http.StatusBadRequest},
+		{name: "registered email", body: `{"email": "agent@example.com", "role": "agent"}`, notifier: &fakeInviteNotifier{}, taken: "agent@example.com", wantCode: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeInvitationStore()
+			if tt.taken != "" {
+				store.taken[tt.taken] = true
+			}
+			var notifier InviteNotifier
+			if tt.notifier != nil {
+				notifier = tt.notifier
+			}
+			rec := postJSON(newInvitationEngine(store, notifier), "/api/admin/users/invite", tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusCreated {
+				if len(store.invitations) != 0 {
+					t.Errorf("stored %d invitations, want none", len(store.invitations))
+				}
+				return
+			}
+
+			var resp InviteUserResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Invitation == nil || resp.Invitation.TenantID != "tenant-1" || resp.Invitation.InvitedBy != "admin-1" {
+				t.Fatalf("invitation = %+v, want one from admin-1 in tenant-1", resp.Invitation)
+			}
+			if resp.EmailSent != tt.wantEmailSent || (resp.Token == "") == !tt.wantEmailSent {
+				t.Errorf("email_sent = %v with token %q, want the token only when the email wasn't sent", resp.EmailSent, resp.Token)
+			}
+			if tt.wantEmailSent && (tt.notifier.to != resp.Invitation.Email || tt.notifier.token == "") {
+				t.Errorf("emailed %q the token %q, want the invitee", tt.notifier.to, tt.notifier.token)
+			}
+			if strings.Contains(rec.Body.String(), "token_hash") {
+				t.Error("response must not include the token hash")
+			}
+		})
+	}
+}
+
+func TestAcceptInvite(t *testing.T) {
+	store := newFakeInvitationStore()
+	notifier := &fakeInviteNotifier{}
+	engine := newInvitationEngine(store, notifier)
+	if rec := postJSON(engine, "/api/admin/users/invite", `{"email": "agent@example.com", "role": "agent"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("invite status = %d: %s", rec.Code, rec.Body.String())
+	}
+	accept := func(token, password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(AcceptInviteRequest{Token: token, Password: password})
+		return postJSON(engine, "/api/auth/accept-invite", string(body))
+	}
+
+	if rec := accept(notifier.token, "short"); rec.Code != http.StatusBadRequest {
+		t.Errorf("short password status = %d, want 400", rec.Code)
+	}
+	if rec := accept("not-a-token", "long-enough"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("invalid token status = %d, want 401", rec.Code)
+	}
+
+	rec := accept(notifier.token, "long-enough")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("accept status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	var user models.User
+	if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if user.Email != "agent@example.com" || user.Role != models.RoleAgent || user.TenantID != "tenant-1" {
+		t.Errorf("user = %+v, want the invited agent", user)
+	}
+
+	if rec := accept(notifier.token, "long-enough"); rec.Code != http.StatusConflict {
+		t.Errorf("replayed accept status = %d, want 409", rec.Code)
+	}
+	if len(store.users) != 1 {
+		t.Errorf("created %d users, want 1", len(store.users))
+	}
+}
+
+func TestInvitationsNotConfigured(t *testing.T) {
+	engine := newInvitationEngine(nil, nil)
+	if rec := postJSON(engine, "/api/admin/users/invite", `{"email": "a@example.com", "role": "agent"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("invite status = %d, want 503", rec.Code)
+	}
+	if rec := postJSON(engine, "/api/auth/accept-invite", `{"token": "t", "password": "long-enough"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("accept status = %d, want 503", rec.Code)
+	}
+}
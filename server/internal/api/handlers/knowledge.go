@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/integrations/scraper"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// KnowledgeHandler handles knowledge base ingestion and article history
+type KnowledgeHandler struct {
+	indexer        *scraper.KnowledgeArticleIndexer
+	articleStorage *postgres.KnowledgeArticleStorage
+}
+
+// NewKnowledgeHandler creates a new knowledge handler
+func NewKnowledgeHandler(indexer *scraper.KnowledgeArticleIndexer, articleStorage *postgres.KnowledgeArticleStorage) *KnowledgeHandler {
+	return &KnowledgeHandler{indexer: indexer, articleStorage: articleStorage}
+}
+
+// IndexURLRequest represents the request body for indexing a web page
+type IndexURLRequest struct {
+	URL       string `json:"url" binding:"required"`
+	ProductID string `json:"product_id"` // Optional product the article describes
+}
+
+// UpdateArticleRequest represents the request body for editing a knowledge article
+type UpdateArticleRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
+
+// IndexURL handles POST /api/knowledge/index-url (admin only)
+func (h *KnowledgeHandler) IndexURL(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req IndexURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := h.indexer.IndexFromURL(tenantID, strings.TrimSpace(req.ProductID), req.URL)
+	if err != nil {
+		switch {
+		case errors.Is(err, scraper.ErrInvalidURL):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, scraper.ErrRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, article)
+}
+
+// UpdateArticle handles PUT /api/knowledge/:id (admin only)
+// The previous content is kept in the article's version history.
+func (h *KnowledgeHandler) UpdateArticle(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" || strings.TrimSpace(req.Content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title and content are required"})
+		return
+	}
+
+	article, err := h.indexer.UpdateArticle(tenantID, c.Param("id"), title, req.Content, c.GetString("user_id"))
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// ListVersions handles GET /api/knowledge/:id/versions
+func (h *KnowledgeHandler) ListVersions(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	versions, err := h.articleStorage.ListVersions(tenantID, c.Param("id"))
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// GetVersion handles GET /api/knowledge/:id/versions/:version_id
+func (h *KnowledgeHandler) GetVersion(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+
+	version, err := h.articleStorage.GetVersion(tenantID, c.Param("id"), c.Param("version_id"))
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// RestoreVersion handles POST /api/knowledge/:id/versions/:version_id/restore (admin only)
+// The restored content becomes a new version and is re-embedded.
+func (h *KnowledgeHandler) RestoreVersion(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	article, err := h.indexer.RestoreVersion(tenantID, c.Param("id"), c.Param("version_id"), c.GetString("user_id"))
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// writeArticleError maps knowledge article errors to HTTP statuses
+func writeArticleError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "modified concurrently"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
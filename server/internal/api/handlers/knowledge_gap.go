@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/agentassist"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// knowledgeGapWindow is how far back knowledge gaps are read when no from is given
+	knowledgeGapWindow = 30 * 24 * time.Hour
+	// maxKnowledgeGapsGrouped caps the questions grouped into topics per request
+	maxKnowledgeGapsGrouped = 5000
+)
+
+// KnowledgeGapLister lists the questions product knowledge had no answer for
+type KnowledgeGapLister interface {
+	ListGaps(tenantID string, from time.Time, limit int) ([]*postgres.KnowledgeGap, error)
+}
+
+// KnowledgeGapHandler reports which customer questions product documentation doesn't cover
+type KnowledgeGapHandler struct {
+	gaps KnowledgeGapLister
+	now  func() time.Time
+}
+
+// NewKnowledgeGapHandler creates a new knowledge gap handler
+func NewKnowledgeGapHandler(gaps KnowledgeGapLister) *KnowledgeGapHandler {
+	return &KnowledgeGapHandler{gaps: gaps, now: time.Now}
+}
+
+// ListKnowledgeGapsResponse represents the response for listing knowledge gap topics
+type ListKnowledgeGapsResponse struct {
+	Topics       []agentassist.KnowledgeGapTopic `json:"topics"`
+	GapsAnalyzed int                             `json:"gaps_analyzed"`
+	From         time.Time                       `json:"from"`
+}
+
+// ListKnowledgeGaps handles GET /api/admin/knowledge-gaps (admin only)
+// Query params: limit (topics, default 20, max 100), from (RFC3339, default 30 days ago).
+// Groups the questions product knowledge retrieval found nothing for into topics of similar
+// questions, most frequent first.
+func (h *KnowledgeGapHandler) ListKnowledgeGaps(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	from := h.now().Add(-knowledgeGapWindow)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from, expected RFC3339"})
+			return
+		}
+		from = parsed
+	}
+
+	gaps, err := h.gaps.ListGaps(tenantID, from, maxKnowledgeGapsGrouped)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListKnowledgeGapsResponse{
+		Topics:       agentassist.GroupKnowledgeGaps(gaps, limit),
+		GapsAnalyzed: len(gaps),
+		From:         from,
+	})
+}
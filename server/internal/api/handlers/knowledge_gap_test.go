@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeKnowledgeGapLister struct {
+	gaps     []*postgres.KnowledgeGap
+	err      error
+	tenantID string
+	from     time.Time
+}
+
+func (f *fakeKnowledgeGapLister) ListGaps(tenantID string, from time.Time, limit int) ([]*postgres.KnowledgeGap, error) {
+	f.tenantID, f.from = tenantID, from
+	return f.gaps, f.err
+}
+
+func TestKnowledgeGapHandlerListKnowledgeGaps(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+	gaps := []*postgres.KnowledgeGap{
+		{QueryText: "Do you ship to Canada?", CreatedAt: now},
+		{QueryText: "Is there a student discount?", CreatedAt: now},
+		{QueryText: "Can you ship to Canada?", CreatedAt: now},
+	}
+	tests := []struct {
+		name       string
+		identity   testContext
+		query      string
+		lister     *fakeKnowledgeGapLister
+		wantCode   int
+		wantTopics int
+		wantFrom   time.Time
+	}{
+		{name: "groups gaps", identity: admin, lister: &fakeKnowledgeGapLister{gaps: gaps}, wantCode: http.StatusOK, wantTopics: 2, wantFrom: now.Add(-knowledgeGapWindow)},
+		{name: "limit and from", identity: admin, query: "?limit=1&from=2024-05-01T00:00:00Z", lister: &fakeKnowledgeGapLister{gaps: gaps}, wantCode: http.StatusOK, wantTopics: 1, wantFrom: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "no gaps", identity: admin, lister: &fakeKnowledgeGapLister{}, wantCode: http.StatusOK, wantFrom: now.Add(-knowledgeGapWindow)},
+		{name: "invalid limit", identity: admin, query: "?limit=0", lister: &fakeKnowledgeGapLister{}, wantCode: http.StatusBadRequest},
+		{name: "invalid from", identity: admin, query: "?from=yesterday", lister: &fakeKnowledgeGapLister{}, wantCode: http.StatusBadRequest},
+		{name: "missing tenant", identity: testContext{role: "admin"}, lister: &fakeKnowledgeGapLister{}, wantCode: http.StatusUnauthorized},
+		{name: "storage error", identity: admin, lister: &fakeKnowledgeGapLister{err: errors.New("db down")}, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewKnowledgeGapHandler(tt.lister)
+			handler.now = func() time.Time { return now }
+			rec := serveHandler("/knowledge-gaps", http.MethodGet, "/knowledge-gaps"+tt.query, tt.identity, handler.ListKnowledgeGaps)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if tt.lister.tenantID != "tenant-1" || !tt.lister.from.Equal(tt.wantFrom) {
+				t.Errorf("listed tenant %q from %v, want tenant-1 from %v", tt.lister.tenantID, tt.lister.from, tt.wantFrom)
+			}
+			var resp ListKnowledgeGapsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if resp.Topics == nil || len(resp.Topics) != tt.wantTopics || resp.GapsAnalyzed != len(tt.lister.gaps) {
+				t.Errorf("response = %+v, want %d topics", resp, tt.wantTopics)
+			}
+			if tt.wantTopics > 0 && resp.Topics[0].Count != 2 {
+				t.Errorf("top topic = %+v, want the 2 shipping questions", resp.Topics[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+const (
+	defaultStreamPingInterval  = 30 * time.Second
+	defaultStreamMaxConnection = time.Hour
+)
+
+// MessageStreamConfig controls WebSocket message stream connections
+type MessageStreamConfig struct {
+	PingInterval  time.Duration // How often idle connections are pinged
+	MaxConnection time.Duration // Connections are closed after this long; clients reconnect
+}
+
+// MessageStreamConfigFromEnv reads WS_PING_INTERVAL_SECONDS (default 30) and
+// WS_MAX_CONNECTION_MINUTES (default 60)
+func MessageStreamConfigFromEnv() MessageStreamConfig {
+	config := MessageStreamConfig{
+		PingInterval:  defaultStreamPingInterval,
+		MaxConnection: defaultStreamMaxConnection,
+	}
+	if v := os.Getenv("WS_PING_INTERVAL_SECONDS"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			config.PingInterval = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := os.Getenv("WS_MAX_CONNECTION_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes > 0 {
+			config.MaxConnection = time.Duration(minutes) * time.Minute
+		}
+	}
+	return config
+}
+
+// ConversationLookup loads a tenant's conversation
+type ConversationLookup interface {
+	GetConversation(tenantID, conversationID string) (*models.Conversation, error)
+}
+
+// MessageStreamHandler streams a conversation's new messages over WebSocket
+type MessageStreamHandler struct {
+	conversations ConversationLookup
+	broadcaster   *conversation.MessageBroadcaster
+	config        MessageStreamConfig
+}
+
+// NewMessageStreamHandler creates a new message stream handler
+func NewMessageStreamHandler(conversations ConversationLookup, broadcaster *conversation.MessageBroadcaster, config MessageStreamConfig) *MessageStreamHandler {
+	return &MessageStreamHandler{
+		conversations: conversations,
+		broadcaster:   broadcaster,
+		config:        config,
+	}
+}
+
+// StreamMessages handles GET /api/conversations/:id/ws. Upgrades to a WebSocket that receives each
+// new message of the conversation as a JSON frame. Customers can only stream their own conversations.
+func (h *MessageStreamHandler) StreamMessages(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if h.broadcaster == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "message streaming is not configured"})
+		return
+	}
+
+	conversationID := c.Param("id")
+	conv, err := h.conversations.GetConversation(tenantID, conversationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.GetString("role") == "customer" && (conv.CustomerID == nil || *conv.CustomerID != c.GetString("user_id")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied: you can only access your own conversations"})
+		return
+	}
+
+	server := websocket.Server{
+		// Requests are authenticated by bearer token, which browsers don't attach cross-site,
+		// so the Origin check isn't needed
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			metrics.WebSocketOpened()
+			defer metrics.WebSocketClosed()
+			sub := h.broadcaster.Subscribe(tenantID, conversationID)
+			defer h.broadcaster.Unsubscribe(sub)
+			h.stream(ws, sub)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// stream writes subscribed messages to ws until the client disconnects, the subscription is
+// dropped or the connection reaches its maximum duration
+func (h *MessageStreamHandler) stream(ws *websocket.Conn, sub *conversation.MessageSubscription) {
+	defer ws.Close()
+
+	// Clients don't send anything; reading handles their pings and notices when they close
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		buf := make([]byte, 512)
+		for {
+			if _, err := ws.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(h.config.PingInterval)
+	defer ping.Stop()
+	maxConnection := time.NewTimer(h.config.MaxConnection)
+	defer maxConnection.Stop()
+
+	for {
+		select {
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			ws.SetWriteDeadline(time.Now().Add(h.config.PingInterval))
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			ws.SetWriteDeadline(time.Now().Add(h.config.PingInterval))
+			ws.PayloadType = websocket.PingFrame
+			_, err := ws.Write(nil)
+			ws.PayloadType = websocket.TextFrame
+			if err != nil {
+				return
+			}
+		case <-maxConnection.C:
+			return
+		case <-closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+type fakeConversationLookup map[string]*models.Conversation
+
+func (f fakeConversationLookup) GetConversation(tenantID, conversationID string) (*models.Conversation, error) {
+	conv, ok := f[conversationID]
+	if !ok || conv.TenantID != tenantID {
+		return nil, errors.New("conversation not found")
+	}
+	return conv, nil
+}
+
+func newStreamServer(t *testing.T, broadcaster *conversation.MessageBroadcaster, role, userID string) *httptest.Server {
+	t.Helper()
+	customerID := "customer-1"
+	lookup := fakeConversationLookup{"c1": {ID: "c1", TenantID: "tenant-1", CustomerID: &customerID}}
+	handler := NewMessageStreamHandler(lookup, broadcaster, MessageStreamConfig{PingInterval: 50 * time.Millisecond, MaxConnection: time.Minute})
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/api/conversations/:id/ws", func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", role)
+		c.Set("user_id", userID)
+		handler.StreamMessages(c)
+	})
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialStream(t *testing.T, server *httptest.Server, conversationID string) (*websocket.Conn, error) {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/conversations/" + conversationID + "/ws"
+	return websocket.Dial(url, "", server.URL)
+}
+
+func waitForSubscribers(t *testing.T, broadcaster *conversation.MessageBroadcaster, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for broadcaster.SubscriberCount("tenant-1", "c1") != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("SubscriberCount = %d, want %d", broadcaster.SubscriberCount("tenant-1", "c1"), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMessageStreamDeliversMessages(t *testing.T) {
+	broadcaster := conversation.NewMessageBroadcaster()
+	server := newStreamServer(t, broadcaster, "agent", "agent-1")
+
+	ws, err := dialStream(t, server, "c1")
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	waitForSubscribers(t, broadcaster, 1)
+
+	broadcaster.Publish("tenant-1", &models.Message{ID: "m1", ConversationID: "c1", Sender: "customer", Content: "Hi"})
+	broadcaster.Publish("tenant-1", &models.Message{ID: "m2", ConversationID: "c1", Sender: "agent", Content: "Hello"})
+
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for _, want := range []string{"m1", "m2"} {
+		var msg models.Message
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			t.Fatalf("Receive: %v", err)
+		}
+		if msg.ID != want || msg.ConversationID != "c1" {
+			t.Errorf("received %+v, want %s", msg, want)
+		}
+	}
+
+	// Closing the connection unregisters the subscriber
+	ws.Close()
+	waitForSubscribers(t, broadcaster, 0)
+}
+
+func TestMessageStreamAccess(t *testing.T) {
+	broadcaster := conversation.NewMessageBroadcaster()
+
+	tests := []struct {
+		name           string
+		role, userID   string
+		conversationID string
+		wantStatus     int
+	}{
+		{"unknown conversation", "agent", "agent-1", "missing", http.StatusNotFound},
+		{"other customer's conversation", "customer", "customer-2", "c1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStreamServer(t, broadcaster, tt.role, tt.userID)
+			resp, err := http.Get(server.URL + "/api/conversations/" + tt.conversationID + "/ws")
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	// The conversation's customer may stream it
+	server := newStreamServer(t, broadcaster, "customer", "customer-1")
+	ws, err := dialStream(t, server, "c1")
+	if err != nil {
+		t.Fatalf("Dial as the conversation's customer: %v", err)
+	}
+	ws.Close()
+}
+
+func TestMessageStreamClosesAtMaxConnection(t *testing.T) {
+	broadcaster := conversation.NewMessageBroadcaster()
+	handler := NewMessageStreamHandler(fakeConversationLookup{"c1": {ID: "c1", TenantID: "tenant-1"}}, broadcaster,
+		MessageStreamConfig{PingInterval: 20 * time.Millisecond, MaxConnection: 100 * time.Millisecond})
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/api/conversations/:id/ws", func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		handler.StreamMessages(c)
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	ws, err := dialStream(t, server, "c1")
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer ws.Close()
+
+	// Pings are answered by the client library; the server closes the connection at MaxConnection
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg models.Message
+	if err := websocket.JSON.Receive(ws, &msg); err == nil {
+		t.Fatalf("received %+v, want the connection closed", msg)
+	}
+	waitForSubscribers(t, broadcaster, 0)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/agentassist"
+	"ai-conversation-platform/internal/services/analytics"
+)
+
+// MockAnalyticsService implements analytics.AnalyticsServiceInterface with configurable results
+type MockAnalyticsService struct {
+	Leads           []analytics.PrioritizedLead
+	WinProbability  analytics.WinProbability
+	ChurnRisk       analytics.ChurnRisk
+	Dashboard       analytics.DashboardMetrics
+	Languages       []analytics.LanguageDistribution
+	SLABreaches     analytics.SLABreachSummary
+	Trends          analytics.TrendAnalysis
+	Performance     analytics.AgentPerformance
+	AcceptanceRate  float64
+	ResolutionRates map[string]float64
+	Segments        []analytics.CustomerSegment
+	Err             error // Returned by every method when set
+
+	// LeadPages are passed to StreamLeads one at a time; Leads is streamed as a single page when unset
+	LeadPages [][]analytics.PrioritizedLead
+
+	// LeadIDs records the conversation IDs passed to PrioritizeLeads
+	LeadIDs []string
+	// TrendRequest records the request passed to GetTrendsWithConfig
+	TrendRequest *analytics.TrendAnalysisRequest
+	// DashboardFilters records the filters passed to GetDashboardMetrics
+	DashboardFilters analytics.DashboardFilters
+	// PerformanceAgentID records the agent passed to GetAgentPerformance
+	PerformanceAgentID string
+}
+
+func (m *MockAnalyticsService) CalculateLeadScore(tenantID, conversationID string) (analytics.LeadScore, error) {
+	return analytics.LeadScore{}, m.Err
+}
+
+func (m *MockAnalyticsService) CalculateWinProbability(tenantID, conversationID string) (analytics.WinProbability, error) {
+	return m.WinProbability, m.Err
+}
+
+func (m *MockAnalyticsService) CalculateChurnRisk(tenantID, conversationID string) (analytics.ChurnRisk, error) {
+	return m.ChurnRisk, m.Err
+}
+
+func (m *MockAnalyticsService) PrioritizeLeads(tenantID string, conversationIDs []string) ([]analytics.PrioritizedLead, error) {
+	m.LeadIDs = conversationIDs
+	return m.Leads, m.Err
+}
+
+func (m *MockAnalyticsService) GetDashboardMetrics(tenantID string, filters analytics.DashboardFilters) (analytics.DashboardMetrics, error) {
+	m.DashboardFilters = filters
+	return m.Dashboard, m.Err
+}
+
+func (m *MockAnalyticsService) RefreshDashboardMetrics(tenantID string, filters analytics.DashboardFilters) (analytics.DashboardMetrics, error) {
+	m.DashboardFilters = filters
+	return m.Dashboard, m.Err
+}
+
+func (m *MockAnalyticsService) GetTrends(tenantID, conversationID string) (analytics.TrendAnalysis, error) {
+	return m.Trends, m.Err
+}
+
+func (m *MockAnalyticsService) GetTrendsWithConfig(req analytics.TrendAnalysisRequest) (analytics.TrendAnalysis, error) {
+	m.TrendRequest = &req
+	return m.Trends, m.Err
+}
+
+func (m *MockAnalyticsService) CalculateCLV(tenantID, conversationID string) (analytics.CLVEstimate, error) {
+	return analytics.CLVEstimate{}, m.Err
+}
+
+func (m *MockAnalyticsService) PredictSalesCycle(tenantID, conversationID string) (analytics.SalesCyclePrediction, error) {
+	return analytics.SalesCyclePrediction{}, m.Err
+}
+
+func (m *MockAnalyticsService) CalculateQualityScore(tenantID, conversationID string) (analytics.QualityScore, error) {
+	return analytics.QualityScore{}, m.Err
+}
+
+func (m *MockAnalyticsService) GetLeaderboard(tenantID string, from, to time.Time, sortBy string, limit int) ([]analytics.AgentLeaderboardEntry, error) {
+	return nil, m.Err
+}
+
+func (m *MockAnalyticsService) FindLeaderboardEntry(tenantID string, from, to time.Time, sortBy, agentID string) (*analytics.AgentLeaderboardEntry, error) {
+	return nil, m.Err
+}
+
+func (m *MockAnalyticsService) GetComplexity(tenantID, conversationID string) (ai.ComplexityScore, error) {
+	return ai.ComplexityScore{}, m.Err
+}
+
+func (m *MockAnalyticsService) GetComplexityDistribution(tenantID string) (map[string]int, error) {
+	return map[string]int{}, m.Err
+}
+
+func (m *MockAnalyticsService) GetAverageDwellTime(tenantID string) (map[string]float64, error) {
+	return map[string]float64{}, m.Err
+}
+
+func (m *MockAnalyticsService) StreamLeads(tenantID string, from, to time.Time, fn func(leads []analytics.PrioritizedLead) error) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.LeadPages == nil {
+		return fn(m.Leads)
+	}
+	for _, page := range m.LeadPages {
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockAnalyticsService) GetLanguageDistribution(tenantID string, from, to time.Time) ([]analytics.LanguageDistribution, error) {
+	return m.Languages, m.Err
+}
+
+func (m *MockAnalyticsService) GetMixedLanguageConversations(tenantID string) ([]*models.Conversation, error) {
+	return []*models.Conversation{}, m.Err
+}
+
+func (m *MockAnalyticsService) GetSLABreachSummary(tenantID string, from, to time.Time) (analytics.SLABreachSummary, error) {
+	summary := m.SLABreaches
+	summary.From, summary.To = from, to
+	return summary, m.Err
+}
+
+func (m *MockAnalyticsService) GetAgentPerformance(tenantID, agentID string, from, to time.Time) (analytics.AgentPerformance, error) {
+	m.PerformanceAgentID = agentID
+	performance := m.Performance
+	performance.AgentID = agentID
+	return performance, m.Err
+}
+
+func (m *MockAnalyticsService) GetSuggestionAcceptanceRate(tenantID string) (float64, error) {
+	return m.AcceptanceRate, m.Err
+}
+
+func (m *MockAnalyticsService) GetObjectionResolutionRate(tenantID string) (map[string]float64, error) {
+	return m.ResolutionRates, m.Err
+}
+
+func (m *MockAnalyticsService) SegmentCustomers(tenantID string) ([]analytics.CustomerSegment, error) {
+	return m.Segments, m.Err
+}
+
+// MockAgentAssistService implements agentassist.AgentAssistServiceInterface with configurable results
+type MockAgentAssistService struct {
+	Response *agentassist.SuggestionsResponse
+	Err      error
+
+	// Stream serves StreamReplySuggestions when set; StreamErr fails it before streaming
+	Stream    ai.TextStreamer
+	StreamErr error
+
+	// Calls record the arguments of the last call
+	AgentID         string
+	ForceRegenerate bool
+	StreamedText    string // Text passed to FinishStreamedSuggestions
+}
+
+func (m *MockAgentAssistService) GetReplySuggestions(ctx context.Context, tenantID, conversationID string, forceRegenerate bool) (*agentassist.SuggestionsResponse, error) {
+	return m.GetReplySuggestionsForAgent(ctx, tenantID, conversationID, "", forceRegenerate)
+}
+
+func (m *MockAgentAssistService) GetReplySuggestionsForAgent(ctx context.Context, tenantID, conversationID, agentID string, forceRegenerate bool) (*agentassist.SuggestionsResponse, error) {
+	m.AgentID = agentID
+	m.ForceRegenerate = forceRegenerate
+	return m.Response, m.Err
+}
+
+func (m *MockAgentAssistService) StreamReplySuggestions(ctx context.Context, tenantID, conversationID, agentID string) (<-chan string, <-chan error, error) {
+	m.AgentID = agentID
+	if m.StreamErr != nil {
+		return nil, nil, m.StreamErr
+	}
+	chunks, errs := m.Stream.GenerateTextStream(ctx, ai.GenerateTextRequest{Prompt: "suggest"})
+	return chunks, errs, nil
+}
+
+func (m *MockAgentAssistService) FinishStreamedSuggestions(tenantID, conversationID, text string) []agentassist.Suggestion {
+	m.StreamedText = text
+	if m.Response == nil {
+		return []agentassist.Suggestion{}
+	}
+	return m.Response.Suggestions
+}
+
+// testContext is the identity a test request is made as. An empty tenant simulates a missing JWT claim.
+type testContext struct {
+	tenantID string
+	userID   string
+	role     string
+}
+
+// serveHandler runs handler for a single request with the JWT context values set
+func serveHandler(route, method, path string, identity testContext, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Handle(method, route, func(c *gin.Context) {
+		if identity.tenantID != "" {
+			c.Set("tenant_id", identity.tenantID)
+		}
+		c.Set("user_id", identity.userID)
+		c.Set("role", identity.role)
+		c.Next()
+	}, handler)
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/moderation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// ModerationListStore manages a tenant's moderation list entries
+type ModerationListStore interface {
+	ListModerationPatterns(tenantID string) ([]*postgres.ModerationListEntry, error)
+	AddModerationPattern(entry *postgres.ModerationListEntry) error
+	DeleteModerationPattern(tenantID, id string) error
+}
+
+// ModerationHandler handles moderation list requests
+type ModerationHandler struct {
+	store ModerationListStore
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(store ModerationListStore) *ModerationHandler {
+	return &ModerationHandler{store: store}
+}
+
+// ModerationListEntryRequest represents the request body for adding a moderation list entry
+type ModerationListEntryRequest struct {
+	ListType string `json:"list_type" binding:"required"` // "block" | "flag"
+	Pattern  string `json:"pattern" binding:"required"`   // Case-insensitive regular expression
+}
+
+// ListModerationPatternsResponse represents the response for listing moderation list entries
+type ListModerationPatternsResponse struct {
+	Patterns []*postgres.ModerationListEntry `json:"patterns"`
+	Total    int                             `json:"total"`
+}
+
+// ListModerationPatterns handles GET /api/admin/moderation-lists (admin only)
+func (h *ModerationHandler) ListModerationPatterns(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	patterns, err := h.store.ListModerationPatterns(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListModerationPatternsResponse{Patterns: patterns, Total: len(patterns)})
+}
+
+// AddModerationPattern handles POST /api/admin/moderation-lists (admin only)
+func (h *ModerationHandler) AddModerationPattern(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req ModerationListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ListType = strings.ToLower(strings.TrimSpace(req.ListType))
+	if err := moderation.ValidateListEntry(req.ListType, req.Pattern); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := &postgres.ModerationListEntry{TenantID: tenantID, ListType: req.ListType, Pattern: req.Pattern}
+	if err := h.store.AddModerationPattern(entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// DeleteModerationPattern handles DELETE /api/admin/moderation-lists/:id (admin only)
+func (h *ModerationHandler) DeleteModerationPattern(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.store.DeleteModerationPattern(tenantID, c.Param("id")); err != nil {
+		if errors.Is(err, postgres.ErrModerationPatternNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation pattern deleted"})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeModerationListStore struct {
+	entries []*postgres.ModerationListEntry
+	err     error
+}
+
+func (f *fakeModerationListStore) ListModerationPatterns(tenantID string) ([]*postgres.ModerationListEntry, error) {
+	return f.entries, f.err
+}
+
+func (f *fakeModerationListStore) AddModerationPattern(entry *postgres.ModerationListEntry) error {
+	if f.err != nil {
+		return f.err
+	}
+	entry.ID = "pattern-1"
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeModerationListStore) DeleteModerationPattern(tenantID, id string) error {
+	for i, entry := range f.entries {
+		if entry.ID == id && entry.TenantID == tenantID {
+			f.entries = append(f.entries[:i], f.entries[i+1:]...)
+			return nil
+		}
+	}
+	return postgres.ErrModerationPatternNotFound
+}
+
+func serveModeration(store ModerationListStore, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewModerationHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.GET("/api/admin/moderation-lists", handler.ListModerationPatterns)
+	engine.POST("/api/admin/moderation-lists", handler.AddModerationPattern)
+	engine.DELETE("/api/admin/moderation-lists/:id", handler.DeleteModerationPattern)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestModerationHandlerAddModerationPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{name: "block pattern", body: `{"list_type": "Block", "pattern": "crypto\\s+giveaway"}`, wantCode: http.StatusCreated},
+		{name: "unknown list type", body: `{"list_type": "allow", "pattern": "refund"}`, wantCode: http.StatusBadRequest},
+		{name: "invalid regex", body: `{"list_type": "flag", "pattern": "(unclosed"}`, wantCode: http.StatusBadRequest},
+		{name: "missing pattern", body: `{"list_type": "flag"}`, wantCode: http.StatusBadRequest},
+		{name: "storage error", body: `{"list_type": "flag", "pattern": "refund"}`, err: errors.New("db down"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeModerationListStore{err: tt.err}
+			rec := serveModeration(store, http.MethodPost, "/api/admin/moderation-lists", tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusCreated {
+				if len(store.entries) != 1 || store.entries[0].ListType != "block" || store.entries[0].TenantID != "tenant-1" {
+					t.Errorf("entries = %+v, want the tenant's block pattern", store.entries)
+				}
+			} else if len(store.entries) != 0 {
+				t.Errorf("entries = %+v, want nothing stored", store.entries)
+			}
+		})
+	}
+}
+
+func TestModerationHandlerDeleteModerationPattern(t *testing.T) {
+	store := &fakeModerationListStore{entries: []*postgres.ModerationListEntry{{ID: "pattern-1", TenantID: "tenant-1"}}}
+
+	if rec := serveModeration(store, http.MethodDelete, "/api/admin/moderation-lists/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("delete missing = %d, want 404", rec.Code)
+	}
+	if rec := serveModeration(store, http.MethodDelete, "/api/admin/moderation-lists/pattern-1", ""); rec.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if len(store.entries) != 0 {
+		t.Errorf("entries = %+v, want the pattern removed", store.entries)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// maxNoteLength is the longest note accepted, in bytes
+const maxNoteLength = 5000
+
+// NoteStore manages agents' internal notes on conversations (see postgres.NoteStorage)
+type NoteStore interface {
+	CreateNote(note *models.ConversationNote) error
+	GetNote(tenantID, conversationID, noteID string) (*models.ConversationNote, error)
+	ListNotes(tenantID, conversationID string) ([]models.ConversationNote, error)
+	UpdateNote(note *models.ConversationNote) error
+	DeleteNote(tenantID, conversationID, noteID string) error
+}
+
+// NoteHandler handles agents' internal conversation notes. Customers can't see them.
+type NoteHandler struct {
+	store NoteStore
+}
+
+// NewNoteHandler creates a new note handler
+func NewNoteHandler(store NoteStore) *NoteHandler {
+	return &NoteHandler{store: store}
+}
+
+// NoteRequest is the body of POST /api/conversations/:id/notes and PUT /api/conversations/:id/notes/:note_id
+type NoteRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// ListNotesResponse is a conversation's notes
+type ListNotesResponse struct {
+	ConversationID string                    `json:"conversation_id"`
+	Notes          []models.ConversationNote `json:"notes"`
+	Total          int                       `json:"total"`
+}
+
+// normalizeNoteContent trims a note and checks its length
+func normalizeNoteContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("content is required")
+	}
+	if len(content) > maxNoteLength {
+		return "", errors.New("content must be at most 5000 characters")
+	}
+	return content, nil
+}
+
+// ListNotes handles GET /api/conversations/:id/notes (agent or admin)
+func (h *NoteHandler) ListNotes(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	conversationID := c.Param("id")
+	notes, err := h.store.ListNotes(tenantID, conversationID)
+	if err != nil {
+		respondNoteError(c, err)
+		return
+	}
+	if notes == nil {
+		notes = []models.ConversationNote{}
+	}
+
+	c.JSON(http.StatusOK, ListNotesResponse{ConversationID: conversationID, Notes: notes, Total: len(notes)})
+}
+
+// CreateNote handles POST /api/conversations/:id/notes (agent or admin)
+func (h *NoteHandler) CreateNote(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req NoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	content, err := normalizeNoteContent(req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note := &models.ConversationNote{
+		ConversationID: c.Param("id"),
+		TenantID:       tenantID,
+		AgentID:        c.GetString("user_id"),
+		Content:        content,
+	}
+	if err := h.store.CreateNote(note); err != nil {
+		respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// UpdateNote handles PUT /api/conversations/:id/notes/:note_id. Only the note's author or an
+// admin may change it.
+func (h *NoteHandler) UpdateNote(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req NoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	content, err := normalizeNoteContent(req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, ok := h.ownedNote(c, tenantID)
+	if !ok {
+		return
+	}
+	note.Content = content
+	if err := h.store.UpdateNote(note); err != nil {
+		respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// DeleteNote handles DELETE /api/conversations/:id/notes/:note_id. Only the note's author or an
+// admin may delete it.
+func (h *NoteHandler) DeleteNote(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	note, ok := h.ownedNote(c, tenantID)
+	if !ok {
+		return
+	}
+	if err := h.store.DeleteNote(tenantID, note.ConversationID, note.ID); err != nil {
+		respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note deleted"})
+}
+
+// ownedNote loads the requested note and rejects callers who are neither its author nor an admin
+func (h *NoteHandler) ownedNote(c *gin.Context, tenantID string) (*models.ConversationNote, bool) {
+	note, err := h.store.GetNote(tenantID, c.Param("id"), c.Param("note_id"))
+	if err != nil {
+		respondNoteError(c, err)
+		return nil, false
+	}
+	if c.GetString("role") != "admin" && note.AgentID != c.GetString("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the note's author or an admin can change it"})
+		return nil, false
+	}
+	return note, true
+}
+
+// authorize rejects customers and returns the caller's tenant
+func (h *NoteHandler) authorize(c *gin.Context) (string, bool) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return "", false
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func respondNoteError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// fakeNoteStore keeps notes in memory; c1 in tenant-1 is the only conversation
+type fakeNoteStore struct {
+	notes []*models.ConversationNote
+}
+
+func (f *fakeNoteStore) CreateNote(note *models.ConversationNote) error {
+	if note.TenantID != "tenant-1" || note.ConversationID != "c1" {
+		return errors.New("conversation not found")
+	}
+	note.ID = "note-" + string(rune('a'+len(f.notes)))
+	f.notes = append(f.notes, note)
+	return nil
+}
+
+func (f *fakeNoteStore) GetNote(tenantID, conversationID, noteID string) (*models.ConversationNote, error) {
+	for _, note := range f.notes {
+		if note.TenantID == tenantID && note.ConversationID == conversationID && note.ID == noteID {
+			copied := *note
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("note not found")
+}
+
+func (f *fakeNoteStore) ListNotes(tenantID, conversationID string) ([]models.ConversationNote, error) {
+	var notes []models.ConversationNote
+	for _, note := range f.notes {
+		if note.TenantID == tenantID && note.ConversationID == conversationID {
+			notes = append(notes, *note)
+		}
+	}
+	return notes, nil
+}
+
+func (f *fakeNoteStore) UpdateNote(note *models.ConversationNote) error {
+	for i, existing := range f.notes {
+		if existing.ID == note.ID && existing.TenantID == note.TenantID {
+			f.notes[i] = note
+			return nil
+		}
+	}
+	return errors.New("note not found")
+}
+
+func (f *fakeNoteStore) DeleteNote(tenantID, conversationID, noteID string) error {
+	for i, note := range f.notes {
+		if note.TenantID == tenantID && note.ConversationID == conversationID && note.ID == noteID {
+			f.notes = append(f.notes[:i], f.notes[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("note not found")
+}
+
+func serveNote(store NoteStore, caller testContext, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewNoteHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", caller.tenantID)
+		c.Set("user_id", caller.userID)
+		c.Set("role", caller.role)
+	})
+	engine.GET("/api/conversations/:id/notes", handler.ListNotes)
+	engine.POST("/api/conversations/:id/notes", handler.CreateNote)
+	engine.PUT("/api/conversations/:id/notes/:note_id", handler.UpdateNote)
+	engine.DELETE("/api/conversations/:id/notes/:note_id", handler.DeleteNote)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNoteHandlerCRUD(t *testing.T) {
+	store := &fakeNoteStore{}
+	author := testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}
+
+	rec := serveNote(store, author, http.MethodPost, "/api/conversations/c1/notes", `{"content": "  Budget approval next week  "}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var note models.ConversationNote
+	json.Unmarshal(rec.Body.Bytes(), &note)
+	if note.Content != "Budget approval next week" || note.AgentID != "agent-1" || note.TenantID != "tenant-1" {
+		t.Errorf("created note = %+v, want the trimmed note by agent-1 in tenant-1", note)
+	}
+
+	rec = serveNote(store, author, http.MethodGet, "/api/conversations/c1/notes", "")
+	var list ListNotesResponse
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if rec.Code != http.StatusOK || list.Total != 1 || list.Notes[0].ID != note.ID {
+		t.Fatalf("list = %d %s, want the note", rec.Code, rec.Body.String())
+	}
+
+	rec = serveNote(store, author, http.MethodPut, "/api/conversations/c1/notes/"+note.ID, `{"content": "Budget approved"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Budget approved") {
+		t.Errorf("update = %d %s, want the new content", rec.Code, rec.Body.String())
+	}
+
+	// Other agents can't change the note; admins can
+	otherAgent := testContext{tenantID: "tenant-1", userID: "agent-2", role: "agent"}
+	if rec := serveNote(store, otherAgent, http.MethodDelete, "/api/conversations/c1/notes/"+note.ID, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("delete by another agent = %d, want 403", rec.Code)
+	}
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+	if rec := serveNote(store, admin, http.MethodDelete, "/api/conversations/c1/notes/"+note.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("delete by admin = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveNote(store, author, http.MethodGet, "/api/conversations/c1/notes", ""); !strings.Contains(rec.Body.String(), `"notes":[]`) {
+		t.Errorf("list after delete = %s, want no notes", rec.Body.String())
+	}
+}
+
+func TestNoteHandlerRolesAndTenants(t *testing.T) {
+	store := &fakeNoteStore{notes: []*models.ConversationNote{
+		{ID: "note-1", ConversationID: "c1", TenantID: "tenant-1", AgentID: "agent-1", Content: "Internal only"},
+	}}
+	customer := testContext{tenantID: "tenant-1", userID: "customer-1", role: "customer"}
+	otherTenant := testContext{tenantID: "tenant-2", userID: "agent-1", role: "admin"}
+	agent := testContext{tenantID: "tenant-1", userID: "agent-1", role: "agent"}
+
+	tests := []struct {
+		name   string
+		caller testContext
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"customer list", customer, http.MethodGet, "/api/conversations/c1/notes", "", http.StatusForbidden},
+		{"customer create", customer, http.MethodPost, "/api/conversations/c1/notes", `{"content": "hi"}`, http.StatusForbidden},
+		{"customer update", customer, http.MethodPut, "/api/conversations/c1/notes/note-1", `{"content": "hi"}`, http.StatusForbidden},
+		{"customer delete", customer, http.MethodDelete, "/api/conversations/c1/notes/note-1", "", http.StatusForbidden},
+		{"other tenant create", otherTenant, http.MethodPost, "/api/conversations/c1/notes", `{"content": "hi"}`, http.StatusNotFound},
+		{"other tenant update", otherTenant, http.MethodPut, "/api/conversations/c1/notes/note-1", `{"content": "hi"}`, http.StatusNotFound},
+		{"other tenant delete", otherTenant, http.MethodDelete, "/api/conversations/c1/notes/note-1", "", http.StatusNotFound},
+		{"blank content", agent, http.MethodPost, "/api/conversations/c1/notes", `{"content": "   "}`, http.StatusBadRequest},
+		{"long content", agent, http.MethodPost, "/api/conversations/c1/notes", `{"content": "` + strings.Repeat("a", 5001) + `"}`, http.StatusBadRequest},
+		{"unknown note", agent, http.MethodPut, "/api/conversations/c1/notes/missing", `{"content": "hi"}`, http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		if rec := serveNote(store, tc.caller, tc.method, tc.path, tc.body); rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d (%s)", tc.name, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+
+	rec := serveNote(store, otherTenant, http.MethodGet, "/api/conversations/c1/notes", "")
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "Internal only") {
+		t.Errorf("other tenant list = %d %s, want no notes", rec.Code, rec.Body.String())
+	}
+	if store.notes[0].Content != "Internal only" || len(store.notes) != 1 {
+		t.Errorf("notes = %+v, want them unchanged", store.notes)
+	}
+}
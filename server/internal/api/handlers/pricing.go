@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/agentassist"
+)
+
+// PricingHandler handles pricing suggestion generation and review
+type PricingHandler struct {
+	agentAssistService *agentassist.AgentAssistService
+	pricingService     *agentassist.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler.
+// agentAssistService may be nil when AI features are disabled.
+func NewPricingHandler(agentAssistService *agentassist.AgentAssistService, pricingService *agentassist.PricingService) *PricingHandler {
+	return &PricingHandler{
+		agentAssistService: agentAssistService,
+		pricingService:     pricingService,
+	}
+}
+
+// PricingSuggestionResponse represents a single pricing suggestion
+type PricingSuggestionResponse struct {
+	Suggestion *models.PricingSuggestion `json:"suggestion"`
+}
+
+// ListPricingSuggestionsResponse represents the pricing suggestions of a conversation
+type ListPricingSuggestionsResponse struct {
+	Suggestions []*models.PricingSuggestion `json:"suggestions"`
+}
+
+// CreatePricingSuggestion handles POST /api/conversations/:id/pricing-suggestions
+func (h *PricingHandler) CreatePricingSuggestion(c *gin.Context) {
+	tenantID, ok := h.requireAgent(c)
+	if !ok {
+		return
+	}
+
+	if h.agentAssistService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "AI features are disabled"})
+		return
+	}
+
+	suggestion, err := h.agentAssistService.SuggestPricing(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, PricingSuggestionResponse{Suggestion: suggestion})
+}
+
+// ListPricingSuggestions handles GET /api/conversations/:id/pricing-suggestions
+func (h *PricingHandler) ListPricingSuggestions(c *gin.Context) {
+	tenantID, ok := h.requireAgent(c)
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.pricingService.ListSuggestions(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListPricingSuggestionsResponse{Suggestions: suggestions})
+}
+
+// ApprovePricingSuggestion handles PUT /api/conversations/:id/pricing-suggestions/:suggestion_id/approve (admin only)
+func (h *PricingHandler) ApprovePricingSuggestion(c *gin.Context) {
+	h.review(c, h.pricingService.ApproveSuggestion)
+}
+
+// RejectPricingSuggestion handles PUT /api/conversations/:id/pricing-suggestions/:suggestion_id/reject (admin only)
+func (h *PricingHandler) RejectPricingSuggestion(c *gin.Context) {
+	h.review(c, h.pricingService.RejectSuggestion)
+}
+
+// GetPricingStats handles GET /api/analytics/pricing-suggestions/stats
+func (h *PricingHandler) GetPricingStats(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	stats, err := h.pricingService.GetStats(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+func (h *PricingHandler) review(c *gin.Context, action func(tenantID, conversationID, suggestionID, reviewerID string) (*models.PricingSuggestion, error)) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	suggestion, err := action(tenantID, c.Param("id"), c.Param("suggestion_id"), c.GetString("user_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PricingSuggestionResponse{Suggestion: suggestion})
+}
+
+// requireAgent checks tenant context and agent/admin role
+func (h *PricingHandler) requireAgent(c *gin.Context) (string, bool) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", false
+	}
+
+	role := c.GetString("role")
+	if role != "agent" && role != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent access required"})
+		return "", false
+	}
+	return tenantID, true
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -18,77 +19,94 @@ import (
 // ProductHandler handles product-related HTTP requests
 type ProductHandler struct {
 	productStorage   *postgres.ProductStorage
+	variantStorage   *postgres.ProductVariantStorage
 	embeddingService *ai.EmbeddingService
+	categoryStorage  *postgres.CategoryStorage
 }
 
 // NewProductHandler creates a new product handler
-func NewProductHandler(productStorage *postgres.ProductStorage, embeddingService *ai.EmbeddingService) *ProductHandler {
+func NewProductHandler(productStorage *postgres.ProductStorage, variantStorage *postgres.ProductVariantStorage, embeddingService *ai.EmbeddingService) *ProductHandler {
 	return &ProductHandler{
 		productStorage:   productStorage,
+		variantStorage:   variantStorage,
 		embeddingService: embeddingService,
 	}
 }
 
-// buildProductText creates a comprehensive text representation of a product for embedding
-func buildProductText(product *models.Product) string {
-	var parts []string
-
-	parts = append(parts, fmt.Sprintf("Product: %s", product.Name))
-	parts = append(parts, fmt.Sprintf("Description: %s", product.Description))
+// SetCategoryStorage enables assigning products to categories and filtering by category (optional)
+func (h *ProductHandler) SetCategoryStorage(categoryStorage *postgres.CategoryStorage) {
+	h.categoryStorage = categoryStorage
+}
 
-	if product.Category != "" {
-		parts = append(parts, fmt.Sprintf("Category: %s", product.Category))
+// resolveCategory checks that categoryID is one of the tenant's categories and sets it, with its
+// path, on the product. An empty ID removes the product's category.
+func (h *ProductHandler) resolveCategory(tenantID string, product *models.Product, categoryID string) error {
+	if categoryID == "" {
+		product.CategoryID = nil
+		product.CategoryPath = ""
+		return nil
 	}
-
-	if product.Price > 0 {
-		parts = append(parts, fmt.Sprintf("Price: %s %.2f", product.PriceCurrency, product.Price))
+	if h.categoryStorage == nil {
+		return postgres.ErrCategoryNotFound
 	}
-
-	if len(product.Features) > 0 {
-		parts = append(parts, fmt.Sprintf("Features: %s", strings.Join(product.Features, ", ")))
+	category, err := h.categoryStorage.GetCategory(tenantID, categoryID)
+	if err != nil {
+		return err
 	}
+	product.CategoryID = &category.ID
+	product.CategoryPath = category.Path
+	return nil
+}
 
-	if len(product.Limitations) > 0 {
-		parts = append(parts, fmt.Sprintf("Limitations: %s", strings.Join(product.Limitations, ", ")))
+// respondResolveCategoryError writes the error from resolveCategory; an unknown category is a bad request
+func respondResolveCategoryError(c *gin.Context, err error) {
+	if errors.Is(err, postgres.ErrCategoryNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
 
-	if product.TargetAudience != "" {
-		parts = append(parts, fmt.Sprintf("Target Audience: %s", product.TargetAudience))
+// loadVariants attaches the product's variants, so they are returned and embedded with it
+func (h *ProductHandler) loadVariants(product *models.Product) error {
+	if h.variantStorage == nil {
+		return nil
 	}
-
-	if len(product.CommonQuestions) > 0 {
-		parts = append(parts, fmt.Sprintf("Common Questions: %s", strings.Join(product.CommonQuestions, ", ")))
+	variants, err := h.variantStorage.ListVariants(product.TenantID, product.ID, false)
+	if err != nil {
+		return err
 	}
-
-	return strings.Join(parts, "\n")
+	product.Variants = variants
+	return nil
 }
 
-// embedProduct embeds a product into Chroma DB for semantic search
+// embedProduct embeds a product into Chroma DB for semantic search.
+// Each product section is stored as a separate chunk so retrieval can match
+// the most relevant section instead of one diluted document.
 func (h *ProductHandler) embedProduct(product *models.Product) {
 	if h.embeddingService == nil {
 		return // Embedding service not available
 	}
 
-	productText := buildProductText(product)
-	collection := "product_knowledge"
-	metadata := map[string]interface{}{
-		"id":         product.ID,
-		"tenant_id":  product.TenantID,
-		"product_id": product.ID,
-		"name":       product.Name,
-		"category":   product.Category,
+	// Variant prices are part of the pricing section. The product is copied since the caller
+	// may still be writing it to the response.
+	withVariants := *product
+	product = &withVariants
+	if err := h.loadVariants(product); err != nil {
+		log.Printf("[ProductHandler] failed to load variants for product %s: %v", product.ID, err)
+	}
+
+	// Remove previous chunks so sections emptied by an update don't linger
+	if err := h.embeddingService.DeleteProductEmbeddings(product.ID); err != nil {
+		log.Printf("[ProductHandler] failed to delete old embeddings for product %s: %v", product.ID, err)
 	}
 
-	if err := h.embeddingService.EmbedAndStore(
-		collection,
-		productText,
-		ai.ContentTypeProductKnowledge,
-		metadata,
-	); err != nil {
+	chunks := ai.NewProductChunker().Chunk(product)
+	if err := h.embeddingService.EmbedChunked(chunks); err != nil {
 		log.Printf("[ProductHandler] failed to embed product %s: %v", product.ID, err)
 		// Don't fail the request if embedding fails
 	} else {
-		log.Printf("[ProductHandler] successfully embedded product %s", product.ID)
+		log.Printf("[ProductHandler] successfully embedded product %s chunks=%d", product.ID, len(chunks))
 	}
 }
 
@@ -97,7 +115,7 @@ type ListProductsResponse struct {
 	Products []*models.Product `json:"products"`
 }
 
-// ListProducts handles GET /api/products
+// ListProducts handles GET /api/products. ?category_id= limits it to a category and its subcategories.
 // Requires authentication - tenant_id is extracted from JWT token
 func (h *ProductHandler) ListProducts(c *gin.Context) {
 	// Get tenant ID from context (set by JWT middleware)
@@ -120,6 +138,20 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		log.Printf("[ProductHandler] Using tenant_id from query param: %s", tenantID)
 	}
 
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		if h.categoryStorage == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": postgres.ErrCategoryNotFound.Error()})
+			return
+		}
+		products, err := listCategoryProducts(h.categoryStorage, h.productStorage, tenantID, categoryID)
+		if err != nil {
+			respondCategoryError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, ListProductsResponse{Products: products})
+		return
+	}
+
 	products, err := h.productStorage.ListProducts(tenantID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -156,6 +188,10 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	if err := h.loadVariants(product); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, GetProductResponse{Product: product})
 }
@@ -164,7 +200,8 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 type CreateProductRequest struct {
 	Name            string   `json:"name" binding:"required"`
 	Description     string   `json:"description" binding:"required"`
-	Category        string   `json:"category"`
+	Category        string   `json:"category"`    // Free text, kept for older clients
+	CategoryID      string   `json:"category_id"` // One of the tenant's categories (see /api/categories)
 	Price           float64  `json:"price" binding:"required"`
 	PriceCurrency   string   `json:"price_currency"`
 	Features        []string `json:"features"`
@@ -199,6 +236,24 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	product := newProduct(tenantID, req)
+	if err := h.resolveCategory(tenantID, product, req.CategoryID); err != nil {
+		respondResolveCategoryError(c, err)
+		return
+	}
+	if err := h.productStorage.CreateProduct(tenantID, product); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Embed product into Chroma DB for semantic search (async, non-blocking)
+	go h.embedProduct(product)
+
+	c.JSON(http.StatusCreated, CreateProductResponse{Product: product})
+}
+
+// newProduct builds a new product from a create request
+func newProduct(tenantID string, req CreateProductRequest) *models.Product {
 	now := time.Now()
 	product := &models.Product{
 		ID:              uuid.New().String(),
@@ -219,16 +274,123 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	if product.PriceCurrency == "" {
 		product.PriceCurrency = "INR"
 	}
+	return product
+}
 
-	if err := h.productStorage.CreateProduct(tenantID, product); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// maxBulkProducts is the most products one bulk import can create
+const maxBulkProducts = 1000
+
+// BulkProductResult is the outcome for one product of a bulk import, in request order
+type BulkProductResult struct {
+	Index    int             `json:"index"`
+	Product  *models.Product `json:"product,omitempty"` // Set when the product was saved
+	Embedded bool            `json:"embedded"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// BulkCreateProductsResponse represents the response for a bulk product import
+type BulkCreateProductsResponse struct {
+	Results         []BulkProductResult `json:"results"`
+	Created         int                 `json:"created"`
+	EmbeddingFailed []string            `json:"embedding_failed"` // IDs of saved products that couldn't be embedded
+}
+
+// BulkCreateProducts handles POST /api/products/bulk (admin only). The body is a JSON array of
+// products. Products are saved one by one and then embedded in batches (see ai.EmbeddingService.BatchEmbed),
+// so a bulk import doesn't flood the embedding API. Saved products are kept when their embedding
+// fails; the response lists them and is 207 Multi-Status when any product failed.
+func (h *ProductHandler) BulkCreateProducts(c *gin.Context) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
 		return
 	}
 
-	// Embed product into Chroma DB for semantic search (async, non-blocking)
-	go h.embedProduct(product)
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
 
-	c.JSON(http.StatusCreated, CreateProductResponse{Product: product})
+	var reqs []CreateProductRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(reqs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one product is required"})
+		return
+	}
+	if len(reqs) > maxBulkProducts {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d products can be imported at once", maxBulkProducts)})
+		return
+	}
+
+	resp := BulkCreateProductsResponse{
+		Results:         make([]BulkProductResult, len(reqs)),
+		EmbeddingFailed: []string{},
+	}
+	var chunks []ai.ProductChunk
+	var chunkOwners []int // Result index of each chunk
+	for i, req := range reqs {
+		resp.Results[i].Index = i
+		product := newProduct(tenantID, req)
+		if err := h.resolveCategory(tenantID, product, req.CategoryID); err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		if err := h.productStorage.CreateProduct(tenantID, product); err != nil {
+			log.Printf("[ProductHandler] bulk import failed to save product index=%d tenant=%s error=%v", i, tenantID, err)
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		resp.Results[i].Product = product
+		resp.Created++
+
+		for _, chunk := range ai.NewProductChunker().Chunk(product) {
+			chunks = append(chunks, chunk)
+			chunkOwners = append(chunkOwners, i)
+		}
+	}
+
+	if h.embeddingService != nil && len(chunks) > 0 {
+		embedFailed := make(map[int]bool)
+		if err := h.embeddingService.EmbedChunkedBatch(chunks); err != nil {
+			var batchErr *ai.BatchEmbedError
+			if errors.As(err, &batchErr) {
+				for _, chunkIndex := range batchErr.Failed {
+					embedFailed[chunkOwners[chunkIndex]] = true
+				}
+			} else {
+				for _, owner := range chunkOwners {
+					embedFailed[owner] = true
+				}
+			}
+			log.Printf("[ProductHandler] bulk import embedding failed tenant=%s products=%d error=%v", tenantID, len(embedFailed), err)
+		}
+		for i := range resp.Results {
+			result := &resp.Results[i]
+			if result.Product == nil {
+				continue
+			}
+			if embedFailed[i] {
+				result.Error = "product saved but embedding failed"
+				resp.EmbeddingFailed = append(resp.EmbeddingFailed, result.Product.ID)
+				continue
+			}
+			result.Embedded = true
+		}
+	}
+	log.Printf("[ProductHandler] bulk import tenant=%s requested=%d created=%d embedding_failed=%d",
+		tenantID, len(reqs), resp.Created, len(resp.EmbeddingFailed))
+
+	status := http.StatusCreated
+	switch {
+	case resp.Created == 0:
+		status = http.StatusInternalServerError
+	case resp.Created < len(reqs) || len(resp.EmbeddingFailed) > 0:
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
 }
 
 // UpdateProductRequest represents the request body for updating a product
@@ -236,6 +398,7 @@ type UpdateProductRequest struct {
 	Name            string   `json:"name"`
 	Description     string   `json:"description"`
 	Category        string   `json:"category"`
+	CategoryID      *string  `json:"category_id"` // An empty string removes the product's category
 	Price           float64  `json:"price"`
 	PriceCurrency   string   `json:"price_currency"`
 	Features        []string `json:"features"`
@@ -288,6 +451,12 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	if req.Category != "" {
 		existingProduct.Category = req.Category
 	}
+	if req.CategoryID != nil {
+		if err := h.resolveCategory(tenantID, existingProduct, *req.CategoryID); err != nil {
+			respondResolveCategoryError(c, err)
+			return
+		}
+	}
 	if req.Price > 0 {
 		existingProduct.Price = req.Price
 	}
@@ -345,6 +514,215 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
+	// Remove the product's chunks so they stop appearing in semantic search
+	if h.embeddingService != nil {
+		if err := h.embeddingService.DeleteAllChunksForProduct(tenantID, productID); err != nil {
+			log.Printf("[ProductHandler] failed to delete embeddings for product %s: %v", productID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "product deleted successfully"})
 }
 
+
+// ListProductVariantsResponse represents the response for listing a product's variants
+type ListProductVariantsResponse struct {
+	Variants []models.ProductVariant `json:"variants"`
+}
+
+// ListProductVariants handles GET /api/products/:id/variants
+func (h *ProductHandler) ListProductVariants(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	product, err := h.productStorage.GetProduct(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	variants, err := h.variantStorage.ListVariants(tenantID, product.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListProductVariantsResponse{Variants: variants})
+}
+
+// CreateProductVariantRequest represents the request body for adding a product variant
+type CreateProductVariantRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Price         *float64 `json:"price" binding:"required"`
+	PriceCurrency string   `json:"price_currency"` // Defaults to the product's currency
+	Description   string   `json:"description"`
+	IsActive      *bool    `json:"is_active"` // Defaults to true
+}
+
+// ProductVariantResponse represents the response for a created or updated variant
+type ProductVariantResponse struct {
+	Variant *models.ProductVariant `json:"variant"`
+}
+
+// CreateProductVariant handles POST /api/products/:id/variants (admin only)
+func (h *ProductHandler) CreateProductVariant(c *gin.Context) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req CreateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if *req.Price < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "price must not be negative"})
+		return
+	}
+
+	product, err := h.productStorage.GetProduct(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant := &models.ProductVariant{
+		ProductID:     product.ID,
+		TenantID:      tenantID,
+		Name:          req.Name,
+		Price:         *req.Price,
+		PriceCurrency: req.PriceCurrency,
+		Description:   req.Description,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+	if variant.PriceCurrency == "" {
+		variant.PriceCurrency = product.PriceCurrency
+	}
+	if err := h.variantStorage.CreateVariant(variant); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Re-embed so the pricing section lists the new tier (async, non-blocking)
+	go h.embedProduct(product)
+
+	c.JSON(http.StatusCreated, ProductVariantResponse{Variant: variant})
+}
+
+// UpdateProductVariantRequest represents the request body for updating a product variant.
+// Only fields present in the body are changed.
+type UpdateProductVariantRequest struct {
+	Name          string   `json:"name"`
+	Price         *float64 `json:"price"`
+	PriceCurrency string   `json:"price_currency"`
+	Description   *string  `json:"description"`
+	IsActive      *bool    `json:"is_active"`
+}
+
+// UpdateProductVariant handles PUT /api/products/:id/variants/:variant_id (admin only)
+func (h *ProductHandler) UpdateProductVariant(c *gin.Context) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req UpdateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Price != nil && *req.Price < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "price must not be negative"})
+		return
+	}
+
+	product, err := h.productStorage.GetProduct(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	variant, err := h.variantStorage.GetVariant(tenantID, product.ID, c.Param("variant_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		variant.Name = req.Name
+	}
+	if req.Price != nil {
+		variant.Price = *req.Price
+	}
+	if req.PriceCurrency != "" {
+		variant.PriceCurrency = req.PriceCurrency
+	}
+	if req.Description != nil {
+		variant.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+	if err := h.variantStorage.UpdateVariant(variant); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	go h.embedProduct(product)
+
+	c.JSON(http.StatusOK, ProductVariantResponse{Variant: variant})
+}
+
+// DeleteProductVariant handles DELETE /api/products/:id/variants/:variant_id (admin only)
+func (h *ProductHandler) DeleteProductVariant(c *gin.Context) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	product, err := h.productStorage.GetProduct(tenantID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.variantStorage.DeleteVariant(tenantID, product.ID, c.Param("variant_id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	go h.embedProduct(product)
+
+	c.JSON(http.StatusOK, gin.H{"message": "product variant deleted successfully"})
+}
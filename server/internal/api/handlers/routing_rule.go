@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// RoutingRuleStore stores a tenant's conversation routing rules
+type RoutingRuleStore interface {
+	CreateRoutingRule(rule *postgres.RoutingRule) error
+	GetRoutingRule(tenantID, id string) (*postgres.RoutingRule, error)
+	ListRoutingRules(tenantID string) ([]*postgres.RoutingRule, error)
+	UpdateRoutingRule(rule *postgres.RoutingRule) error
+	DeleteRoutingRule(tenantID, id string) error
+}
+
+// RoutingRuleHandler handles the rules that assign new conversations to agents
+type RoutingRuleHandler struct {
+	store RoutingRuleStore
+}
+
+// NewRoutingRuleHandler creates a new routing rule handler
+func NewRoutingRuleHandler(store RoutingRuleStore) *RoutingRuleHandler {
+	return &RoutingRuleHandler{store: store}
+}
+
+// RoutingRuleRequest is the body of POST and PUT /api/routing-rules. Conditions are ANDed; an empty
+// list matches every conversation.
+type RoutingRuleRequest struct {
+	Priority   int                         `json:"priority"` // Higher priorities are evaluated first
+	Conditions []postgres.RoutingCondition `json:"conditions"`
+	Action     postgres.RoutingAction      `json:"action" binding:"required"`
+}
+
+// RoutingRuleResponse represents a routing rule
+type RoutingRuleResponse struct {
+	RoutingRule *postgres.RoutingRule `json:"routing_rule"`
+}
+
+// ListRoutingRulesResponse lists routing rules in evaluation order
+type ListRoutingRulesResponse struct {
+	RoutingRules []*postgres.RoutingRule `json:"routing_rules"`
+	Total        int                     `json:"total"`
+}
+
+// ListRoutingRules handles GET /api/routing-rules (admin only)
+func (h *RoutingRuleHandler) ListRoutingRules(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	rules, err := h.store.ListRoutingRules(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rules == nil {
+		rules = []*postgres.RoutingRule{}
+	}
+
+	c.JSON(http.StatusOK, ListRoutingRulesResponse{RoutingRules: rules, Total: len(rules)})
+}
+
+// GetRoutingRule handles GET /api/routing-rules/:id (admin only)
+func (h *RoutingRuleHandler) GetRoutingRule(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	rule, err := h.store.GetRoutingRule(tenantID, c.Param("id"))
+	if err != nil {
+		writeRoutingRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RoutingRuleResponse{RoutingRule: rule})
+}
+
+// CreateRoutingRule handles POST /api/routing-rules (admin only)
+func (h *RoutingRuleHandler) CreateRoutingRule(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	rule, ok := bindRoutingRule(c)
+	if !ok {
+		return
+	}
+	rule.TenantID = tenantID
+
+	if err := h.store.CreateRoutingRule(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, RoutingRuleResponse{RoutingRule: rule})
+}
+
+// UpdateRoutingRule handles PUT /api/routing-rules/:id (admin only). The rule is replaced.
+func (h *RoutingRuleHandler) UpdateRoutingRule(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	update, ok := bindRoutingRule(c)
+	if !ok {
+		return
+	}
+	rule, err := h.store.GetRoutingRule(tenantID, c.Param("id"))
+	if err != nil {
+		writeRoutingRuleError(c, err)
+		return
+	}
+	rule.Priority = update.Priority
+	rule.Conditions = update.Conditions
+	rule.Action = update.Action
+
+	if err := h.store.UpdateRoutingRule(rule); err != nil {
+		writeRoutingRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RoutingRuleResponse{RoutingRule: rule})
+}
+
+// DeleteRoutingRule handles DELETE /api/routing-rules/:id (admin only)
+func (h *RoutingRuleHandler) DeleteRoutingRule(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.store.DeleteRoutingRule(tenantID, c.Param("id")); err != nil {
+		writeRoutingRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Routing rule deleted"})
+}
+
+// bindRoutingRule reads and validates a routing rule request, responding with 400 when invalid
+func bindRoutingRule(c *gin.Context) (*postgres.RoutingRule, bool) {
+	var req RoutingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	rule := &postgres.RoutingRule{
+		Priority:   req.Priority,
+		Conditions: req.Conditions,
+		Action:     req.Action,
+	}
+	if rule.Conditions == nil {
+		rule.Conditions = []postgres.RoutingCondition{}
+	}
+	rule.Action.AgentID = strings.TrimSpace(rule.Action.AgentID)
+	if err := conversation.ValidateRoutingRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return rule, true
+}
+
+// writeRoutingRuleError maps "not found" to 404 and anything else to 500
+func writeRoutingRuleError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
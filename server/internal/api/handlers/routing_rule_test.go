@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeRoutingRuleStore keeps routing rules in memory
+type fakeRoutingRuleStore struct {
+	rules map[string]*postgres.RoutingRule
+}
+
+func (f *fakeRoutingRuleStore) CreateRoutingRule(rule *postgres.RoutingRule) error {
+	rule.ID = fmt.Sprintf("rr%d", len(f.rules)+1)
+	f.rules[rule.ID] = rule
+	return nil
+}
+
+func (f *fakeRoutingRuleStore) GetRoutingRule(tenantID, id string) (*postgres.RoutingRule, error) {
+	rule, ok := f.rules[id]
+	if !ok || rule.TenantID != tenantID {
+		return nil, fmt.Errorf("routing rule not found")
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+func (f *fakeRoutingRuleStore) ListRoutingRules(tenantID string) ([]*postgres.RoutingRule, error) {
+	return nil, nil
+}
+
+func (f *fakeRoutingRuleStore) UpdateRoutingRule(rule *postgres.RoutingRule) error {
+	f.rules[rule.ID] = rule
+	return nil
+}
+
+func (f *fakeRoutingRuleStore) DeleteRoutingRule(tenantID, id string) error {
+	delete(f.rules, id)
+	return nil
+}
+
+func newRoutingRuleEngine(store *fakeRoutingRuleStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewRoutingRuleHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.GET("/api/routing-rules", handler.ListRoutingRules)
+	engine.POST("/api/routing-rules", handler.CreateRoutingRule)
+	engine.GET("/api/routing-rules/:id", handler.GetRoutingRule)
+	engine.PUT("/api/routing-rules/:id", handler.UpdateRoutingRule)
+	return engine
+}
+
+func serveRoutingRule(engine *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateRoutingRuleRejectsInvalidRules(t *testing.T) {
+	engine := newRoutingRuleEngine(&fakeRoutingRuleStore{rules: map[string]*postgres.RoutingRule{}})
+
+	tests := map[string]string{
+		"missing action":     `{"priority": 1, "conditions": []}`,
+		"unknown action":     `{"action": {"type": "notify", "agent_id": "agent-1"}}`,
+		"missing agent":      `{"action": {"type": "assign_agent", "agent_id": " "}}`,
+		"unknown field":      `{"conditions": [{"field": "region", "operator": "eq", "value": "eu"}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`,
+		"numeric on string":  `{"conditions": [{"field": "intent", "operator": "gt", "value": "buying"}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`,
+		"string urgency":     `{"conditions": [{"field": "urgency_score", "operator": "gte", "value": "high"}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`,
+		"in without list":    `{"conditions": [{"field": "customer_language", "operator": "in", "value": "hi"}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`,
+		"empty string value": `{"conditions": [{"field": "sentiment", "operator": "eq", "value": ""}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`,
+	}
+	for name, body := range tests {
+		if rec := serveRoutingRule(engine, http.MethodPost, "/api/routing-rules", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
+
+func TestRoutingRuleCreateAndUpdate(t *testing.T) {
+	store := &fakeRoutingRuleStore{rules: map[string]*postgres.RoutingRule{}}
+	engine := newRoutingRuleEngine(store)
+
+	rec := serveRoutingRule(engine, http.MethodPost, "/api/routing-rules",
+		`{"priority": 10, "conditions": [{"field": "intent", "operator": "eq", "value": "buying"}, {"field": "urgency_score", "operator": "gte", "value": 0.8}], "action": {"type": "assign_agent", "agent_id": "agent-1"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created RoutingRuleResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	rule := created.RoutingRule
+	if rule.ID != "rr1" || rule.TenantID != "tenant-1" || rule.Priority != 10 || len(rule.Conditions) != 2 || rule.Action.AgentID != "agent-1" {
+		t.Errorf("created = %+v", rule)
+	}
+
+	rec = serveRoutingRule(engine, http.MethodPut, "/api/routing-rules/rr1", `{"priority": 3, "action": {"type": "assign_agent", "agent_id": "agent-2"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if updated := store.rules["rr1"]; updated.Priority != 3 || len(updated.Conditions) != 0 || updated.Action.AgentID != "agent-2" || updated.TenantID != "tenant-1" {
+		t.Errorf("updated = %+v", updated)
+	}
+
+	if rec := serveRoutingRule(engine, http.MethodPut, "/api/routing-rules/missing", `{"action": {"type": "assign_agent", "agent_id": "agent-2"}}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT missing status = %d, want 404", rec.Code)
+	}
+	if rec := serveRoutingRule(engine, http.MethodGet, "/api/routing-rules", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"routing_rules":[]`) {
+		t.Errorf("GET list = %d %s, want an empty list", rec.Code, rec.Body.String())
+	}
+}
@@ -8,21 +8,29 @@ import (
 	"github.com/google/uuid"
 
 	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
 	"ai-conversation-platform/internal/storage/postgres"
 )
 
 // RuleHandler handles rule-related HTTP requests
 type RuleHandler struct {
 	ruleStorage *postgres.RuleStorage
+	ruleEngine  *rules.RuleEngine
 }
 
 // NewRuleHandler creates a new rule handler
 func NewRuleHandler(ruleStorage *postgres.RuleStorage) *RuleHandler {
 	return &RuleHandler{
 		ruleStorage: ruleStorage,
+		ruleEngine:  rules.NewRuleEngine(),
 	}
 }
 
+// isValidRuleAction reports whether action is one the rule engine applies
+func isValidRuleAction(action string) bool {
+	return action == "block" || action == "auto_correct" || action == "flag" || action == models.RuleActionEscalate
+}
+
 // ListRulesRequest represents query parameters for listing rules
 type ListRulesRequest struct {
 	ActiveOnly bool `form:"active_only"`
@@ -94,7 +102,7 @@ type CreateRuleRequest struct {
 	Description string `json:"description"`
 	Type        string `json:"type" binding:"required"` // "block", "correct", "flag"
 	Pattern     string `json:"pattern" binding:"required"`
-	Action      string `json:"action" binding:"required"` // "block", "auto_correct", "flag"
+	Action      string `json:"action" binding:"required"` // "block", "auto_correct", "flag" or "escalate"
 	IsActive    bool   `json:"is_active"`
 }
 
@@ -117,6 +125,11 @@ func (h *RuleHandler) CreateRule(c *gin.Context) {
 		return
 	}
 
+	if err := h.ruleEngine.ValidateRulePattern(req.Type, req.Pattern); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	now := time.Now()
 	rule := &models.Rule{
 		ID:          uuid.New().String(),
@@ -168,6 +181,19 @@ func (h *RuleHandler) UpdateRule(c *gin.Context) {
 		return
 	}
 
+	var req UpdateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Pattern != "" {
+		// Valid max_discount patterns ({"max_pct": 20}) compile as regular expressions too
+		if err := h.ruleEngine.ValidatePattern(req.Pattern); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// Get existing rule
 	existingRule, err := h.ruleStorage.GetRule(tenantID, ruleID)
 	if err != nil {
@@ -175,10 +201,19 @@ func (h *RuleHandler) UpdateRule(c *gin.Context) {
 		return
 	}
 
-	var req UpdateRuleRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	// max_discount patterns are JSON; check them against the type the rule will have
+	ruleType, pattern := existingRule.Type, existingRule.Pattern
+	if req.Type != "" {
+		ruleType = req.Type
+	}
+	if req.Pattern != "" {
+		pattern = req.Pattern
+	}
+	if ruleType == rules.RuleTypeMaxDiscount {
+		if err := h.ruleEngine.ValidateRulePattern(ruleType, pattern); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	// Update fields if provided
@@ -210,6 +245,49 @@ func (h *RuleHandler) UpdateRule(c *gin.Context) {
 	c.JSON(http.StatusOK, UpdateRuleResponse{Rule: existingRule})
 }
 
+// TestRuleRequest represents the request body for trying a rule against sample text
+type TestRuleRequest struct {
+	Pattern    string `json:"pattern" binding:"required"`
+	Action     string `json:"action" binding:"required"` // "block", "auto_correct", "flag"
+	Type       string `json:"type"`                      // Picks the auto-correction template
+	SampleText string `json:"sample_text" binding:"required"`
+}
+
+// TestRuleResponse represents what the rule would do to the sample text
+type TestRuleResponse struct {
+	Matched       bool   `json:"matched"`
+	MatchedText   string `json:"matched_text,omitempty"`
+	Blocked       bool   `json:"blocked"`
+	Flagged       bool   `json:"flagged"`
+	CorrectedText string `json:"corrected_text"`
+}
+
+// TestRule handles POST /api/rules/test. Runs a rule against sample text without saving anything.
+func (h *RuleHandler) TestRule(c *gin.Context) {
+	var req TestRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.ruleEngine.ValidatePattern(req.Pattern); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !isValidRuleAction(req.Action) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid action, expected block, auto_correct, flag or escalate"})
+		return
+	}
+
+	result := h.ruleEngine.TestPattern(req.Pattern, req.Action, req.Type, req.SampleText)
+	c.JSON(http.StatusOK, TestRuleResponse{
+		Matched:       result.Matched,
+		MatchedText:   result.MatchedText,
+		Blocked:       result.Blocked,
+		Flagged:       result.Flagged,
+		CorrectedText: result.CorrectedText,
+	})
+}
+
 // DeleteRuleResponse represents the response for deleting a rule
 type DeleteRuleResponse struct {
 	Message string `json:"message"`
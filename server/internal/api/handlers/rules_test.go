@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveRules routes requests to a rule handler without storage; only requests rejected before
+// reaching the database can be served
+func serveRules(method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewRuleHandler(nil)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.POST("/api/rules", handler.CreateRule)
+	engine.POST("/api/rules/test", handler.TestRule)
+	engine.PUT("/api/rules/:id", handler.UpdateRule)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTestRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want TestRuleResponse
+	}{
+		{
+			name: "block",
+			body: `{"pattern": "\\d+% off", "action": "block", "sample_text": "Take 20% off now"}`,
+			want: TestRuleResponse{Matched: true, MatchedText: "20% off", Blocked: true, CorrectedText: "Take 20% off now"},
+		},
+		{
+			name: "auto_correct",
+			body: `{"pattern": "(?i)guaranteed", "action": "auto_correct", "type": "brand_tone", "sample_text": "Results GUARANTEED"}`,
+			want: TestRuleResponse{Matched: true, MatchedText: "GUARANTEED", CorrectedText: "Results Let me rephrase that in a more professional manner."},
+		},
+		{
+			name: "flag with keyword pattern",
+			body: `{"pattern": "refund", "action": "flag", "sample_text": "Can I get a refund?"}`,
+			want: TestRuleResponse{Matched: true, MatchedText: "refund", Flagged: true, CorrectedText: "Can I get a refund?"},
+		},
+		{
+			name: "no match",
+			body: `{"pattern": "refund", "action": "block", "sample_text": "Thanks!"}`,
+			want: TestRuleResponse{CorrectedText: "Thanks!"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveRules(http.MethodPost, "/api/rules/test", tt.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			var got TestRuleResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("response = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestRuleRejectsInvalidInput(t *testing.T) {
+	tests := map[string]string{
+		"invalid regex":  `{"pattern": "(50% off", "action": "block", "sample_text": "50% off"}`,
+		"unknown action": `{"pattern": "refund", "action": "delete", "sample_text": "refund"}`,
+		"missing sample": `{"pattern": "refund", "action": "flag"}`,
+	}
+	for name, body := range tests {
+		if rec := serveRules(http.MethodPost, "/api/rules/test", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
+
+func TestCreateAndUpdateRuleRejectInvalidPattern(t *testing.T) {
+	rec := serveRules(http.MethodPost, "/api/rules", `{"name": "discounts", "type": "flag", "pattern": "[0-9", "action": "flag"}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "missing closing ]") {
+		t.Errorf("POST /api/rules = %d %s, want 400 with the compile error", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRules(http.MethodPost, "/api/rules", `{"name": "discounts", "type": "max_discount", "pattern": "{\"max_pct\": 120}", "action": "block"}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "max_pct") {
+		t.Errorf("POST /api/rules max_discount = %d %s, want 400 for max_pct out of range", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRules(http.MethodPut, "/api/rules/rule-1", `{"pattern": "(unclosed"}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "missing closing )") {
+		t.Errorf("PUT /api/rules/:id = %d %s, want 400 with the compile error", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// maxSLAThresholdMinutes caps the response deadline at one week
+const maxSLAThresholdMinutes = 7 * 24 * 60
+
+// maxSLAResolutionHours caps the resolution deadline at 90 days
+const maxSLAResolutionHours = 90 * 24
+
+// SLAConfigHandler handles the tenant's agent response time target
+type SLAConfigHandler struct {
+	slaStorage *postgres.SLAStorage
+	slaTracker *conversation.SLATracker
+}
+
+// NewSLAConfigHandler creates a new SLA config handler
+func NewSLAConfigHandler(slaStorage *postgres.SLAStorage, slaTracker *conversation.SLATracker) *SLAConfigHandler {
+	return &SLAConfigHandler{
+		slaStorage: slaStorage,
+		slaTracker: slaTracker,
+	}
+}
+
+// SLAConfigRequest represents the request body for setting the response deadline
+type SLAConfigRequest struct {
+	ResponseThresholdMinutes int `json:"response_threshold_minutes" binding:"required"`
+}
+
+// SLAConfigResponse represents the tenant's response deadline
+type SLAConfigResponse struct {
+	ResponseThresholdMinutes int  `json:"response_threshold_minutes"`
+	IsDefault                bool `json:"is_default"` // The tenant has no config and uses SLA_RESPONSE_THRESHOLD_MINUTES
+}
+
+// GetSLAConfig handles GET /api/sla-config (admin only)
+func (h *SLAConfigHandler) GetSLAConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	config, err := h.slaStorage.GetSLAConfig(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if config == nil {
+		c.JSON(http.StatusOK, SLAConfigResponse{
+			ResponseThresholdMinutes: int(h.slaTracker.DefaultThreshold().Minutes()),
+			IsDefault:                true,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SLAConfigResponse{ResponseThresholdMinutes: config.ResponseThresholdMinutes})
+}
+
+// UpdateSLAConfig handles PUT /api/sla-config (admin only). The new deadline applies to customer
+// messages received from now on.
+func (h *SLAConfigHandler) UpdateSLAConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req SLAConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "response_threshold_minutes is required"})
+		return
+	}
+	if req.ResponseThresholdMinutes < 1 || req.ResponseThresholdMinutes > maxSLAThresholdMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "response_threshold_minutes must be between 1 and 10080"})
+		return
+	}
+
+	config := &postgres.SLAConfig{TenantID: tenantID, ResponseThresholdMinutes: req.ResponseThresholdMinutes}
+	if err := h.slaStorage.SetSLAConfig(config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SLAConfigResponse{ResponseThresholdMinutes: config.ResponseThresholdMinutes})
+}
+
+// ProductSLARequest represents the request body for setting a product's SLA
+type ProductSLARequest struct {
+	FirstResponseMinutes int `json:"first_response_minutes" binding:"required"`
+	ResolutionHours      int `json:"resolution_hours"` // 0 or omitted for no resolution deadline
+}
+
+// ProductSLAResponse represents a product's SLA
+type ProductSLAResponse struct {
+	ProductID            string `json:"product_id"`
+	FirstResponseMinutes int    `json:"first_response_minutes"`
+	ResolutionHours      int    `json:"resolution_hours"`
+	IsDefault            bool   `json:"is_default"` // The product has no SLA and uses the tenant's response deadline
+}
+
+// GetProductSLA handles GET /api/products/:id/sla (admin only)
+func (h *SLAConfigHandler) GetProductSLA(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	productID := c.Param("id")
+	config, err := h.slaStorage.GetProductSLAConfig(tenantID, productID)
+	if errors.Is(err, postgres.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if config == nil {
+		c.JSON(http.StatusOK, ProductSLAResponse{
+			ProductID:            productID,
+			FirstResponseMinutes: int(h.slaTracker.Threshold(tenantID).Minutes()),
+			IsDefault:            true,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductSLAResponse{
+		ProductID:            productID,
+		FirstResponseMinutes: config.FirstResponseMinutes,
+		ResolutionHours:      config.ResolutionHours,
+	})
+}
+
+// UpdateProductSLA handles PUT /api/products/:id/sla (admin only). Conversations about the
+// product use its first response deadline instead of the tenant's for customer messages received
+// from now on, and must be resolved within resolution_hours of starting.
+func (h *SLAConfigHandler) UpdateProductSLA(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req ProductSLARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "first_response_minutes is required"})
+		return
+	}
+	if req.FirstResponseMinutes < 1 || req.FirstResponseMinutes > maxSLAThresholdMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "first_response_minutes must be between 1 and 10080"})
+		return
+	}
+	if req.ResolutionHours < 0 || req.ResolutionHours > maxSLAResolutionHours {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resolution_hours must be between 0 and 2160"})
+		return
+	}
+
+	config := &postgres.ProductSLAConfig{
+		ProductID:            c.Param("id"),
+		TenantID:             tenantID,
+		FirstResponseMinutes: req.FirstResponseMinutes,
+		ResolutionHours:      req.ResolutionHours,
+	}
+	if err := h.slaStorage.SetProductSLAConfig(config); err != nil {
+		if errors.Is(err, postgres.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductSLAResponse{
+		ProductID:            config.ProductID,
+		FirstResponseMinutes: config.FirstResponseMinutes,
+		ResolutionHours:      config.ResolutionHours,
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUpdateSLAConfigRejectsInvalidThreshold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewSLAConfigHandler(nil, nil)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.PUT("/api/sla-config", handler.UpdateSLAConfig)
+
+	tests := map[string]string{
+		"missing threshold":  `{}`,
+		"zero minutes":       `{"response_threshold_minutes": 0}`,
+		"negative minutes":   `{"response_threshold_minutes": -5}`,
+		"more than one week": `{"response_threshold_minutes": 10081}`,
+		"not a number":       `{"response_threshold_minutes": "soon"}`,
+	}
+	for name, body := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/api/sla-config", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
+
+func TestUpdateProductSLARejectsInvalidConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewSLAConfigHandler(nil, nil)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.PUT("/api/products/:id/sla", handler.UpdateProductSLA)
+
+	tests := map[string]string{
+		"missing first response":    `{"resolution_hours": 24}`,
+		"zero first response":       `{"first_response_minutes": 0}`,
+		"first response over limit": `{"first_response_minutes": 10081}`,
+		"negative resolution":       `{"first_response_minutes": 5, "resolution_hours": -1}`,
+		"resolution over limit":     `{"first_response_minutes": 5, "resolution_hours": 2161}`,
+	}
+	for name, body := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/api/products/p1/sla", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/integrations/slack"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SlackConfigHandler handles tenant Slack integration settings
+type SlackConfigHandler struct {
+	slackConfigStorage *postgres.SlackConfigStorage
+	slackService       *slack.Service
+}
+
+// NewSlackConfigHandler creates a new Slack config handler
+func NewSlackConfigHandler(slackConfigStorage *postgres.SlackConfigStorage, slackService *slack.Service) *SlackConfigHandler {
+	return &SlackConfigHandler{
+		slackConfigStorage: slackConfigStorage,
+		slackService:       slackService,
+	}
+}
+
+// SlackConfigRequest represents the request body for updating Slack settings
+type SlackConfigRequest struct {
+	WebhookURL        string `json:"webhook_url" binding:"required"`
+	HotLeadChannel    string `json:"hot_lead_channel"`
+	EscalationChannel string `json:"escalation_channel"`
+	WatchlistChannel  string `json:"watchlist_channel"` // Defaults to #executive-watchlist
+	IsActive          *bool  `json:"is_active"`
+}
+
+// UpdateSlackConfig handles PUT /api/admin/slack-config (admin only)
+func (h *SlackConfigHandler) UpdateSlackConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req SlackConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	if !strings.HasPrefix(webhookURL, "https://") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_url must start with https://"})
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	config := &postgres.SlackConfig{
+		TenantID:          tenantID,
+		WebhookURL:        webhookURL,
+		HotLeadChannel:    strings.TrimSpace(req.HotLeadChannel),
+		EscalationChannel: strings.TrimSpace(req.EscalationChannel),
+		WatchlistChannel:  strings.TrimSpace(req.WatchlistChannel),
+		IsActive:          isActive,
+	}
+	if err := h.slackConfigStorage.SetSlackConfig(config); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
+
+// TestSlackConfig handles POST /api/admin/slack-config/test (admin only)
+func (h *SlackConfigHandler) TestSlackConfig(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.slackService.SendTest(tenantID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Test message sent"})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// SuggestionFeedbackStore records agent feedback on reply suggestions
+type SuggestionFeedbackStore interface {
+	CreateFeedback(feedback *models.SuggestionFeedback) error
+}
+
+// RecommendationAcceptor marks the products a suggestion recommended as accepted (see
+// postgres.ProductStorage)
+type RecommendationAcceptor interface {
+	AcceptProductRecommendations(tenantID, conversationID, suggestionID string, acceptedAt time.Time) (int64, error)
+}
+
+// SuggestionFeedbackHandler handles feedback on reply suggestions
+type SuggestionFeedbackHandler struct {
+	store           SuggestionFeedbackStore
+	recommendations RecommendationAcceptor
+}
+
+// NewSuggestionFeedbackHandler creates a new suggestion feedback handler
+func NewSuggestionFeedbackHandler(store SuggestionFeedbackStore) *SuggestionFeedbackHandler {
+	return &SuggestionFeedbackHandler{store: store}
+}
+
+// SetRecommendationAcceptor counts accepted and edited suggestions towards the conversion rate of
+// the products they recommended (optional)
+func (h *SuggestionFeedbackHandler) SetRecommendationAcceptor(recommendations RecommendationAcceptor) {
+	h.recommendations = recommendations
+}
+
+// SuggestionFeedbackRequest is the body of POST /api/conversations/:id/suggestions/:suggestion_id/feedback
+type SuggestionFeedbackRequest struct {
+	Action     string  `json:"action" binding:"required"` // accepted, rejected or edited
+	EditedText *string `json:"edited_text"`               // Required for edited
+}
+
+// SuggestionFeedbackResponse is the recorded feedback
+type SuggestionFeedbackResponse struct {
+	Feedback *models.SuggestionFeedback `json:"feedback"`
+}
+
+// SubmitFeedback handles POST /api/conversations/:id/suggestions/:suggestion_id/feedback (agent or admin)
+func (h *SuggestionFeedbackHandler) SubmitFeedback(c *gin.Context) {
+	tenantID, agentID, ok := agentIdentity(c)
+	if !ok {
+		return
+	}
+
+	var req SuggestionFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feedback := &models.SuggestionFeedback{
+		SuggestionID:   c.Param("suggestion_id"),
+		ConversationID: c.Param("id"),
+		TenantID:       tenantID,
+		AgentID:        agentID,
+		Action:         req.Action,
+	}
+	switch req.Action {
+	case models.SuggestionFeedbackAccepted, models.SuggestionFeedbackRejected:
+	case models.SuggestionFeedbackEdited:
+		if req.EditedText == nil || strings.TrimSpace(*req.EditedText) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "edited_text is required for edited suggestions"})
+			return
+		}
+		feedback.EditedText = req.EditedText
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be one of accepted, rejected, edited"})
+		return
+	}
+
+	if err := h.store.CreateFeedback(feedback); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.acceptRecommendations(feedback)
+
+	c.JSON(http.StatusCreated, SuggestionFeedbackResponse{Feedback: feedback})
+}
+
+// acceptRecommendations marks the products recommended by a suggestion the agent used, as is or
+// edited, as converted
+func (h *SuggestionFeedbackHandler) acceptRecommendations(feedback *models.SuggestionFeedback) {
+	if h.recommendations == nil || feedback.Action == models.SuggestionFeedbackRejected {
+		return
+	}
+	accepted, err := h.recommendations.AcceptProductRecommendations(feedback.TenantID, feedback.ConversationID, feedback.SuggestionID, feedback.FeedbackAt)
+	if err != nil {
+		log.Printf("[FEEDBACK] failed to accept product recommendations tenant=%s conversation=%s suggestion=%s: %v",
+			feedback.TenantID, feedback.ConversationID, feedback.SuggestionID, err)
+		return
+	}
+	if accepted > 0 {
+		log.Printf("[FEEDBACK] product recommendations accepted tenant=%s conversation=%s suggestion=%s products=%d",
+			feedback.TenantID, feedback.ConversationID, feedback.SuggestionID, accepted)
+	}
+}
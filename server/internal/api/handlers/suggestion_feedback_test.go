@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// fakeFeedbackStore records feedback; c1 is the only conversation of tenant-1
+type fakeFeedbackStore struct {
+	created []*models.SuggestionFeedback
+}
+
+func (f *fakeFeedbackStore) CreateFeedback(feedback *models.SuggestionFeedback) error {
+	if feedback.TenantID != "tenant-1" || feedback.ConversationID != "c1" {
+		return errors.New("conversation not found")
+	}
+	feedback.ID = "feedback-1"
+	f.created = append(f.created, feedback)
+	return nil
+}
+
+// fakeRecommendationAcceptor records the suggestions whose product recommendations were accepted
+type fakeRecommendationAcceptor struct {
+	accepted []string
+}
+
+func (f *fakeRecommendationAcceptor) AcceptProductRecommendations(tenantID, conversationID, suggestionID string, acceptedAt time.Time) (int64, error) {
+	f.accepted = append(f.accepted, conversationID+"/"+suggestionID)
+	return 1, nil
+}
+
+func serveFeedback(store SuggestionFeedbackStore, path, body string) *httptest.ResponseRecorder {
+	return serveFeedbackHandler(NewSuggestionFeedbackHandler(store), path, body)
+}
+
+func serveFeedbackHandler(handler *SuggestionFeedbackHandler, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "agent")
+		c.Set("user_id", "agent-1")
+	})
+	engine.POST("/api/conversations/:id/suggestions/:suggestion_id/feedback", handler.SubmitFeedback)
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSubmitSuggestionFeedback(t *testing.T) {
+	store := &fakeFeedbackStore{}
+	rec := serveFeedback(store, "/api/conversations/c1/suggestions/s1/feedback", `{"action": "edited", "edited_text": "Sure, 10% off today"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp SuggestionFeedbackResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(store.created) != 1 {
+		t.Fatalf("stored feedback = %v, want one entry", store.created)
+	}
+	got := store.created[0]
+	if got.SuggestionID != "s1" || got.AgentID != "agent-1" || got.Action != "edited" || got.EditedText == nil || *got.EditedText != "Sure, 10% off today" {
+		t.Errorf("stored feedback = %+v", got)
+	}
+	if resp.Feedback == nil || resp.Feedback.ID != "feedback-1" {
+		t.Errorf("response = %+v, want the stored feedback", resp.Feedback)
+	}
+
+	// edited_text only applies to edited suggestions
+	rec = serveFeedback(store, "/api/conversations/c1/suggestions/s2/feedback", `{"action": "accepted", "edited_text": "ignored"}`)
+	if rec.Code != http.StatusCreated || store.created[1].EditedText != nil {
+		t.Errorf("accepted = %d with edited text %v, want 201 without edited text", rec.Code, store.created[1].EditedText)
+	}
+}
+
+func TestSubmitSuggestionFeedbackRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{name: "unknown action", path: "/api/conversations/c1/suggestions/s1/feedback", body: `{"action": "ignored"}`, want: http.StatusBadRequest},
+		{name: "missing action", path: "/api/conversations/c1/suggestions/s1/feedback", body: `{}`, want: http.StatusBadRequest},
+		{name: "edited without text", path: "/api/conversations/c1/suggestions/s1/feedback", body: `{"action": "edited", "edited_text": " "}`, want: http.StatusBadRequest},
+		{name: "other conversation", path: "/api/conversations/c2/suggestions/s1/feedback", body: `{"action": "rejected"}`, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		store := &fakeFeedbackStore{}
+		if rec := serveFeedback(store, tt.path, tt.body); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+		if len(store.created) != 0 {
+			t.Errorf("%s: stored %v, want nothing", tt.name, store.created)
+		}
+	}
+}
+
+func TestSubmitSuggestionFeedbackAcceptsProductRecommendations(t *testing.T) {
+	acceptor := &fakeRecommendationAcceptor{}
+	handler := NewSuggestionFeedbackHandler(&fakeFeedbackStore{})
+	handler.SetRecommendationAcceptor(acceptor)
+
+	for suggestionID, body := range map[string]string{
+		"s1": `{"action": "accepted"}`,
+		"s2": `{"action": "edited", "edited_text": "Pro Plan fits best"}`,
+		"s3": `{"action": "rejected"}`,
+	} {
+		if rec := serveFeedbackHandler(handler, "/api/conversations/c1/suggestions/"+suggestionID+"/feedback", body); rec.Code != http.StatusCreated {
+			t.Fatalf("%s: status = %d: %s", suggestionID, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serveFeedbackHandler(handler, "/api/conversations/c2/suggestions/s4/feedback", `{"action": "accepted"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("other conversation status = %d, want 404", rec.Code)
+	}
+
+	sort.Strings(acceptor.accepted)
+	if want := []string{"c1/s1", "c1/s2"}; !reflect.DeepEqual(acceptor.accepted, want) {
+		t.Errorf("accepted recommendations = %v, want %v", acceptor.accepted, want)
+	}
+}
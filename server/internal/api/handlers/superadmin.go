@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/analytics"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SuperAdminHandler handles cross-tenant monitoring for the platform operator
+type SuperAdminHandler struct {
+	aggregation  *analytics.ChurnRiskAggregation
+	usageStorage *postgres.UsageStorage
+}
+
+// NewSuperAdminHandler creates a new super admin handler
+func NewSuperAdminHandler(aggregation *analytics.ChurnRiskAggregation, usageStorage *postgres.UsageStorage) *SuperAdminHandler {
+	return &SuperAdminHandler{
+		aggregation:  aggregation,
+		usageStorage: usageStorage,
+	}
+}
+
+// ChurnRiskAggregate handles GET /api/superadmin/churn-risk-aggregate
+func (h *SuperAdminHandler) ChurnRiskAggregate(c *gin.Context) {
+	tenants, err := h.aggregation.ChurnRisk()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tenants": tenants})
+}
+
+// WinRateAggregate handles GET /api/superadmin/win-rate-aggregate
+func (h *SuperAdminHandler) WinRateAggregate(c *gin.Context) {
+	tenants, err := h.aggregation.WinRate()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tenants": tenants})
+}
+
+// Usage handles GET /api/superadmin/usage?days=30
+func (h *SuperAdminHandler) Usage(c *gin.Context) {
+	days := 30
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = parsed
+	}
+
+	tenants, err := h.usageStorage.CountByTenant(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"days": days, "tenants": tenants})
+}
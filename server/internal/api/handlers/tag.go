@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// maxTagNameLength is the longest tag name accepted
+const maxTagNameLength = 50
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TagStore manages tenant tags and their conversations
+type TagStore interface {
+	CreateTag(tag *models.Tag) error
+	GetTag(tenantID, tagID string) (*models.Tag, error)
+	ListTags(tenantID string) ([]models.Tag, error)
+	UpdateTag(tag *models.Tag) error
+	DeleteTag(tenantID, tagID string) error
+	AddConversationTag(tenantID, conversationID, tagID string) error
+	RemoveConversationTag(tenantID, conversationID, tagID string) error
+	ListConversationTags(tenantID, conversationID string) ([]models.Tag, error)
+}
+
+// TagHandler handles conversation tags
+type TagHandler struct {
+	store TagStore
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(store TagStore) *TagHandler {
+	return &TagHandler{store: store}
+}
+
+// CreateTagRequest is the body of POST /api/tags
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"` // Optional hex color like #ff8800
+}
+
+// UpdateTagRequest is the body of PUT /api/tags/:id; omitted fields are kept
+type UpdateTagRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// TagConversationRequest is the body of POST /api/conversations/:id/tags
+type TagConversationRequest struct {
+	TagID string `json:"tag_id" binding:"required"`
+}
+
+// ListTagsResponse represents the response for listing tags
+type ListTagsResponse struct {
+	Tags  []models.Tag `json:"tags"`
+	Total int          `json:"total"`
+}
+
+// ConversationTagsResponse is a conversation's tags after a change
+type ConversationTagsResponse struct {
+	ConversationID string       `json:"conversation_id"`
+	Tags           []models.Tag `json:"tags"`
+}
+
+// normalizeTagName trims and lowercases a tag name and checks its length
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	if len(name) > maxTagNameLength {
+		return "", errors.New("name must be at most 50 characters")
+	}
+	return name, nil
+}
+
+// validateTagColor accepts an empty color or a hex color like #ff8800
+func validateTagColor(color string) error {
+	if color != "" && !tagColorPattern.MatchString(color) {
+		return errors.New("color must be a hex color like #ff8800")
+	}
+	return nil
+}
+
+// ListTags handles GET /api/tags (agent or admin)
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.store.ListTags(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+
+	c.JSON(http.StatusOK, ListTagsResponse{Tags: tags, Total: len(tags)})
+}
+
+// CreateTag handles POST /api/tags (agent or admin). Names are lowercased and unique per tenant.
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateTagColor(req.Color); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag := &models.Tag{TenantID: tenantID, Name: name, Color: req.Color}
+	if err := h.store.CreateTag(tag); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag handles PUT /api/tags/:id (agent or admin)
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var name string
+	if req.Name != nil {
+		var err error
+		if name, err = normalizeTagName(*req.Name); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Color != nil {
+		if err := validateTagColor(*req.Color); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	tag, err := h.store.GetTag(tenantID, c.Param("id"))
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+	if req.Name != nil {
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+	if err := h.store.UpdateTag(tag); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag handles DELETE /api/tags/:id (agent or admin). The tag is removed from every conversation.
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteTag(tenantID, c.Param("id")); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// TagConversation handles POST /api/conversations/:id/tags (agent or admin)
+func (h *TagHandler) TagConversation(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req TagConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conversationID := c.Param("id")
+	if err := h.store.AddConversationTag(tenantID, conversationID, req.TagID); err != nil {
+		respondTagError(c, err)
+		return
+	}
+	h.respondConversationTags(c, tenantID, conversationID)
+}
+
+// UntagConversation handles DELETE /api/conversations/:id/tags/:tag_id (agent or admin)
+func (h *TagHandler) UntagConversation(c *gin.Context) {
+	tenantID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	conversationID := c.Param("id")
+	if err := h.store.RemoveConversationTag(tenantID, conversationID, c.Param("tag_id")); err != nil {
+		respondTagError(c, err)
+		return
+	}
+	h.respondConversationTags(c, tenantID, conversationID)
+}
+
+// respondConversationTags writes the conversation's current tags
+func (h *TagHandler) respondConversationTags(c *gin.Context, tenantID, conversationID string) {
+	tags, err := h.store.ListConversationTags(tenantID, conversationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	c.JSON(http.StatusOK, ConversationTagsResponse{ConversationID: conversationID, Tags: tags})
+}
+
+// authorize rejects customers and returns the caller's tenant
+func (h *TagHandler) authorize(c *gin.Context) (string, bool) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return "", false
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func respondTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrTagExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
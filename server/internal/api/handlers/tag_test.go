@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeTagStore keeps tenant-1's tags in memory; c1 is its only conversation
+type fakeTagStore struct {
+	tags     map[string]*models.Tag
+	attached map[string]bool // tag IDs attached to c1
+}
+
+func (f *fakeTagStore) CreateTag(tag *models.Tag) error {
+	for _, existing := range f.tags {
+		if existing.Name == tag.Name {
+			return postgres.ErrTagExists
+		}
+	}
+	tag.ID = "tag-" + tag.Name
+	f.tags[tag.ID] = tag
+	return nil
+}
+
+func (f *fakeTagStore) GetTag(tenantID, tagID string) (*models.Tag, error) {
+	tag, ok := f.tags[tagID]
+	if tenantID != "tenant-1" || !ok {
+		return nil, errors.New("tag not found")
+	}
+	copied := *tag
+	return &copied, nil
+}
+
+func (f *fakeTagStore) ListTags(tenantID string) ([]models.Tag, error) {
+	var tags []models.Tag
+	for _, tag := range f.tags {
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+func (f *fakeTagStore) UpdateTag(tag *models.Tag) error {
+	f.tags[tag.ID] = tag
+	return nil
+}
+
+func (f *fakeTagStore) DeleteTag(tenantID, tagID string) error {
+	if _, err := f.GetTag(tenantID, tagID); err != nil {
+		return err
+	}
+	delete(f.tags, tagID)
+	delete(f.attached, tagID)
+	return nil
+}
+
+func (f *fakeTagStore) AddConversationTag(tenantID, conversationID, tagID string) error {
+	if _, err := f.GetTag(tenantID, tagID); err != nil {
+		return err
+	}
+	if conversationID != "c1" {
+		return errors.New("conversation not found")
+	}
+	f.attached[tagID] = true
+	return nil
+}
+
+func (f *fakeTagStore) RemoveConversationTag(tenantID, conversationID, tagID string) error {
+	if conversationID != "c1" || !f.attached[tagID] {
+		return errors.New("tag not found on conversation")
+	}
+	delete(f.attached, tagID)
+	return nil
+}
+
+func (f *fakeTagStore) ListConversationTags(tenantID, conversationID string) ([]models.Tag, error) {
+	var tags []models.Tag
+	for tagID := range f.attached {
+		tags = append(tags, *f.tags[tagID])
+	}
+	return tags, nil
+}
+
+func serveTag(store TagStore, role, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewTagHandler(store)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", role)
+	})
+	engine.POST("/api/tags", handler.CreateTag)
+	engine.PUT("/api/tags/:id", handler.UpdateTag)
+	engine.DELETE("/api/tags/:id", handler.DeleteTag)
+	engine.POST("/api/conversations/:id/tags", handler.TagConversation)
+	engine.DELETE("/api/conversations/:id/tags/:tag_id", handler.UntagConversation)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTagHandlerTagAndUntagConversation(t *testing.T) {
+	store := &fakeTagStore{tags: map[string]*models.Tag{}, attached: map[string]bool{}}
+
+	rec := serveTag(store, "agent", http.MethodPost, "/api/tags", `{"name": " Hot-Lead ", "color": "#ff8800"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	var tag models.Tag
+	json.Unmarshal(rec.Body.Bytes(), &tag)
+	if tag.Name != "hot-lead" || tag.TenantID != "tenant-1" {
+		t.Errorf("created tag = %+v, want hot-lead in tenant-1", tag)
+	}
+	if rec := serveTag(store, "agent", http.MethodPost, "/api/tags", `{"name": "hot-lead"}`); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate name = %d, want 409", rec.Code)
+	}
+
+	rec = serveTag(store, "agent", http.MethodPost, "/api/conversations/c1/tags", `{"tag_id": "`+tag.ID+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("tag conversation = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ConversationTagsResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.ConversationID != "c1" || len(resp.Tags) != 1 || resp.Tags[0].Name != "hot-lead" {
+		t.Errorf("tag response = %+v, want c1 tagged hot-lead", resp)
+	}
+
+	rec = serveTag(store, "agent", http.MethodDelete, "/api/conversations/c1/tags/"+tag.ID, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"tags":[]`) {
+		t.Errorf("untag = %d %s, want 200 with no tags", rec.Code, rec.Body.String())
+	}
+	if rec := serveTag(store, "agent", http.MethodDelete, "/api/conversations/c1/tags/"+tag.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("untag twice = %d, want 404", rec.Code)
+	}
+}
+
+func TestTagHandlerErrors(t *testing.T) {
+	store := &fakeTagStore{tags: map[string]*models.Tag{"tag-1": {ID: "tag-1", TenantID: "tenant-1", Name: "escalated"}}, attached: map[string]bool{}}
+
+	tests := []struct {
+		name, role, method, path, body string
+		want                           int
+	}{
+		{"customer", "customer", http.MethodPost, "/api/tags", `{"name": "vip"}`, http.StatusForbidden},
+		{"blank name", "agent", http.MethodPost, "/api/tags", `{"name": "  "}`, http.StatusBadRequest},
+		{"long name", "agent", http.MethodPost, "/api/tags", `{"name": "` + strings.Repeat("a", 51) + `"}`, http.StatusBadRequest},
+		{"bad color", "agent", http.MethodPost, "/api/tags", `{"name": "vip", "color": "orange"}`, http.StatusBadRequest},
+		{"update bad color", "admin", http.MethodPut, "/api/tags/tag-1", `{"color": "#12345"}`, http.StatusBadRequest},
+		{"update unknown tag", "admin", http.MethodPut, "/api/tags/missing", `{"name": "vip"}`, http.StatusNotFound},
+		{"delete unknown tag", "admin", http.MethodDelete, "/api/tags/missing", "", http.StatusNotFound},
+		{"unknown tag", "agent", http.MethodPost, "/api/conversations/c1/tags", `{"tag_id": "missing"}`, http.StatusNotFound},
+		{"unknown conversation", "agent", http.MethodPost, "/api/conversations/c2/tags", `{"tag_id": "tag-1"}`, http.StatusNotFound},
+		{"missing tag_id", "agent", http.MethodPost, "/api/conversations/c1/tags", `{}`, http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		if rec := serveTag(store, tc.role, tc.method, tc.path, tc.body); rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/tenant"
+)
+
+// TenantDataDeleter erases a tenant's data (see tenant.DataDeletionService)
+type TenantDataDeleter interface {
+	DeleteTenantData(tenantID string) (*tenant.DeletionReport, error)
+}
+
+// TenantDataHandler handles GDPR erasure of a tenant's data
+type TenantDataHandler struct {
+	deleter TenantDataDeleter
+}
+
+// NewTenantDataHandler creates a new tenant data handler
+func NewTenantDataHandler(deleter TenantDataDeleter) *TenantDataHandler {
+	return &TenantDataHandler{deleter: deleter}
+}
+
+// DeleteTenantData handles DELETE /api/admin/tenants/:tenant_id/data (super admin only).
+// Permanently deletes all of the tenant's data and returns the rows deleted per table.
+func (h *TenantDataHandler) DeleteTenantData(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+		return
+	}
+	// Erasing their own tenant would delete the caller's account mid-request
+	if tenantID == c.GetString("tenant_id") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete the data of your own tenant"})
+		return
+	}
+
+	report, err := h.deleter.DeleteTenantData(tenantID)
+	if err != nil {
+		log.Printf("[DATA_DELETION] tenant data deletion failed tenant=%s requested_by=%s error=%v", tenantID, c.GetString("user_id"), err)
+		if report != nil {
+			// The database rows are gone but embeddings remain; the request can be retried
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "tenant data deleted but embeddings could not be removed; retry the request", "report": report})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete tenant data"})
+		return
+	}
+
+	log.Printf("[DATA_DELETION] tenant data erased tenant=%s requested_by=%s rows=%d", tenantID, c.GetString("user_id"), report.TotalRows)
+	c.JSON(http.StatusOK, report)
+}
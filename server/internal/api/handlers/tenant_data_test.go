@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ai-conversation-platform/internal/services/tenant"
+)
+
+// fakeTenantDataDeleter records the tenant it was asked to erase
+type fakeTenantDataDeleter struct {
+	report  *tenant.DeletionReport
+	err     error
+	deleted string
+}
+
+func (f *fakeTenantDataDeleter) DeleteTenantData(tenantID string) (*tenant.DeletionReport, error) {
+	f.deleted = tenantID
+	return f.report, f.err
+}
+
+func TestTenantDataHandlerDeleteTenantData(t *testing.T) {
+	superAdmin := testContext{tenantID: "platform", userID: "root", role: "super_admin"}
+	report := &tenant.DeletionReport{TenantID: "tenant-1", Tables: map[string]int64{"conversations": 2, "users": 1}, TotalRows: 3}
+
+	tests := []struct {
+		name        string
+		path        string
+		deleter     *fakeTenantDataDeleter
+		wantCode    int
+		wantDeleted string
+	}{
+		{
+			name:        "returns the deletion report",
+			path:        "/admin/tenants/tenant-1/data",
+			deleter:     &fakeTenantDataDeleter{report: report},
+			wantCode:    http.StatusOK,
+			wantDeleted: "tenant-1",
+		},
+		{
+			name:     "own tenant is refused",
+			path:     "/admin/tenants/platform/data",
+			deleter:  &fakeTenantDataDeleter{report: report},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:        "database failure",
+			path:        "/admin/tenants/tenant-1/data",
+			deleter:     &fakeTenantDataDeleter{err: errors.New("database unavailable")},
+			wantCode:    http.StatusInternalServerError,
+			wantDeleted: "tenant-1",
+		},
+		{
+			name:        "embedding failure still reports deleted rows",
+			path:        "/admin/tenants/tenant-1/data",
+			deleter:     &fakeTenantDataDeleter{report: report, err: errors.New("chroma unavailable")},
+			wantCode:    http.StatusInternalServerError,
+			wantDeleted: "tenant-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTenantDataHandler(tt.deleter)
+			rec := serveHandler("/admin/tenants/:tenant_id/data", http.MethodDelete, tt.path, superAdmin, handler.DeleteTenantData)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.deleter.deleted != tt.wantDeleted {
+				t.Errorf("deleted tenant = %q, want %q", tt.deleter.deleted, tt.wantDeleted)
+			}
+			if tt.deleter.report == nil {
+				return
+			}
+			var body struct {
+				tenant.DeletionReport
+				Report *tenant.DeletionReport `json:"report"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			got := &body.DeletionReport
+			if body.Report != nil {
+				got = body.Report
+			}
+			if tt.wantCode != http.StatusBadRequest && (got.TotalRows != 3 || got.Tables["conversations"] != 2) {
+				t.Errorf("report = %+v, want the row counts", got)
+			}
+		})
+	}
+}
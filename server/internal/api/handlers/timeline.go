@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+)
+
+// TimelineHandler serves the unified conversation timeline
+type TimelineHandler struct {
+	timelineService *conversation.ConversationTimelineService
+}
+
+// NewTimelineHandler creates a new timeline handler
+func NewTimelineHandler(timelineService *conversation.ConversationTimelineService) *TimelineHandler {
+	return &TimelineHandler{timelineService: timelineService}
+}
+
+// TimelineResponse is the chronological timeline of a conversation
+type TimelineResponse struct {
+	ConversationID string                      `json:"conversation_id"`
+	Items          []conversation.TimelineItem `json:"items"`
+}
+
+// GetTimeline handles GET /api/conversations/:id/timeline (agent or admin)
+func (h *TimelineHandler) GetTimeline(c *gin.Context) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	conversationID := c.Param("id")
+	items, err := h.timelineService.Build(tenantID, conversationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if items == nil {
+		items = []conversation.TimelineItem{}
+	}
+
+	c.JSON(http.StatusOK, TimelineResponse{ConversationID: conversationID, Items: items})
+}
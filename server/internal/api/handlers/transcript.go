@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// TranscriptHandler handles emailing conversation transcripts to customers
+type TranscriptHandler struct {
+	transcriptService *conversation.TranscriptEmailService
+}
+
+// NewTranscriptHandler creates a new transcript handler. transcriptService may be nil when SMTP isn't configured.
+func NewTranscriptHandler(transcriptService *conversation.TranscriptEmailService) *TranscriptHandler {
+	return &TranscriptHandler{transcriptService: transcriptService}
+}
+
+// SendTranscriptRequest represents the request body for emailing a transcript
+type SendTranscriptRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// SendTranscript handles POST /api/conversations/:id/send-transcript (agent or admin)
+func (h *TranscriptHandler) SendTranscript(c *gin.Context) {
+	if c.GetString("role") == "customer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "agent or admin access required"})
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+	if h.transcriptService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "email delivery is not configured"})
+		return
+	}
+
+	var req SendTranscriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conversationID := c.Param("id")
+	if err := h.transcriptService.SendTranscript(tenantID, conversationID, req.Email); err != nil {
+		switch {
+		case errors.Is(err, conversation.ErrInvalidRecipient):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, postgres.ErrTranscriptAlreadySent):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "no messages"):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "transcript sent successfully"})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// UserAdminHandler handles tenant user administration
+type UserAdminHandler struct {
+	userStorage    *postgres.UserStorage
+	invitations    auth.InvitationStore
+	inviteNotifier InviteNotifier
+}
+
+// NewUserAdminHandler creates a new user admin handler
+func NewUserAdminHandler(userStorage *postgres.UserStorage) *UserAdminHandler {
+	return &UserAdminHandler{userStorage: userStorage}
+}
+
+// ListUsersRequest represents query parameters for listing users
+type ListUsersRequest struct {
+	Role   string `form:"role"`   // agent, customer or admin
+	Search string `form:"search"` // Case-insensitive email substring
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// ListUsersResponse represents the response for listing users
+type ListUsersResponse struct {
+	Users []*models.User `json:"users"`
+	Total int            `json:"total"`
+}
+
+// UpdateUserRoleRequest represents the request body for changing a user's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// validRole checks role is one of the known user roles
+func validRole(role string) bool {
+	switch models.UserRole(role) {
+	case models.RoleCustomer, models.RoleAgent, models.RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// ListUsers handles GET /api/admin/users (admin only)
+func (h *UserAdminHandler) ListUsers(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Role != "" && !validRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be agent, customer or admin"})
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	filter := postgres.UserFilter{Role: req.Role, EmailContains: strings.TrimSpace(req.Search)}
+	users, err := h.userStorage.SearchUsers(tenantID, filter, req.Limit, req.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total, err := h.userStorage.CountUsers(tenantID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ListUsersResponse{Users: users, Total: total})
+}
+
+// GetUser handles GET /api/admin/users/:id (admin only)
+func (h *UserAdminHandler) GetUser(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	user, err := h.userStorage.GetUser(tenantID, c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeactivateUser handles DELETE /api/admin/users/:id (admin only)
+// Users are deactivated rather than deleted so their conversations keep an owner.
+func (h *UserAdminHandler) DeactivateUser(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	userID := c.Param("id")
+	if userID == c.GetString("user_id") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot deactivate your own account"})
+		return
+	}
+
+	if err := h.userStorage.DeactivateUser(tenantID, userID); err != nil {
+		h.writeUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deactivated successfully"})
+}
+
+// UpdateUserRole handles PUT /api/admin/users/:id/role (admin only)
+func (h *UserAdminHandler) UpdateUserRole(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be agent, customer or admin"})
+		return
+	}
+
+	userID := c.Param("id")
+	if err := h.userStorage.UpdateUserRole(tenantID, userID, models.UserRole(req.Role)); err != nil {
+		h.writeUpdateError(c, err)
+		return
+	}
+
+	user, err := h.userStorage.GetUser(tenantID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// writeUpdateError maps user update errors to HTTP statuses
+func (h *UserAdminHandler) writeUpdateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, postgres.ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "already deactivated"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/ai"
+)
+
+// CollectionRepairer recreates missing Chroma collections (see ai.EmbeddingService)
+type CollectionRepairer interface {
+	RecreateMissingCollections(ctx context.Context) ([]string, error)
+}
+
+// EmbeddingStatusSource reports the embedding model version and re-index progress (see
+// ai.EmbeddingReindexer)
+type EmbeddingStatusSource interface {
+	Status() (*ai.EmbeddingStatus, error)
+}
+
+// VectorStoreHandler handles vector store maintenance
+type VectorStoreHandler struct {
+	cleaner     *ai.VectorStoreCleaner
+	collections CollectionRepairer
+	embeddings  EmbeddingStatusSource
+}
+
+// AIHealthResponse represents the result of repairing the vector store collections
+type AIHealthResponse struct {
+	Status               string   `json:"status"`
+	RecreatedCollections []string `json:"recreated_collections"` // Collections that were missing and have been recreated empty
+}
+
+// NewVectorStoreHandler creates a new vector store handler. cleaner is nil when Chroma is unavailable.
+func NewVectorStoreHandler(cleaner *ai.VectorStoreCleaner) *VectorStoreHandler {
+	return &VectorStoreHandler{cleaner: cleaner}
+}
+
+// SetCollectionRepairer enables GET /api/admin/ai/health (optional)
+func (h *VectorStoreHandler) SetCollectionRepairer(collections CollectionRepairer) {
+	h.collections = collections
+}
+
+// SetEmbeddingStatus enables GET /api/admin/embeddings/status (optional)
+func (h *VectorStoreHandler) SetEmbeddingStatus(embeddings EmbeddingStatusSource) {
+	h.embeddings = embeddings
+}
+
+// CleanupOrphans handles POST /api/admin/vector-store/cleanup-orphans (admin only)
+func (h *VectorStoreHandler) CleanupOrphans(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if h.cleaner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "vector store is not available"})
+		return
+	}
+
+	result, err := h.cleaner.CleanupOrphans(tenantID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AIHealth handles GET /api/admin/ai/health (admin only). Recreates any Chroma collection that is
+// missing, e.g. after a restart of a Chroma server with ephemeral storage.
+func (h *VectorStoreHandler) AIHealth(c *gin.Context) {
+	if h.collections == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "vector store is not available"})
+		return
+	}
+
+	recreated, err := h.collections.RecreateMissingCollections(c.Request.Context())
+	if err != nil {
+		log.Printf("[VECTOR_STORE] failed to recreate collections tenant=%s error=%v", c.GetString("tenant_id"), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to check vector store collections", "recreated_collections": recreated})
+		return
+	}
+	if len(recreated) > 0 {
+		log.Printf("[VECTOR_STORE] recreated collections=%v requested_by=%s", recreated, c.GetString("user_id"))
+	}
+
+	c.JSON(http.StatusOK, AIHealthResponse{Status: "ok", RecreatedCollections: recreated})
+}
+
+// EmbeddingStatus handles GET /api/admin/embeddings/status (admin only). Shows the current
+// embedding model, when products were last indexed and how many a running re-index has left.
+func (h *VectorStoreHandler) EmbeddingStatus(c *gin.Context) {
+	if h.embeddings == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "vector store is not available"})
+		return
+	}
+
+	status, err := h.embeddings.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"ai-conversation-platform/internal/ai"
+)
+
+// fakeCollectionRepairer reports the collections it recreated
+type fakeCollectionRepairer struct {
+	recreated []string
+	err       error
+}
+
+func (f fakeCollectionRepairer) RecreateMissingCollections(ctx context.Context) ([]string, error) {
+	return f.recreated, f.err
+}
+
+func TestVectorStoreHandlerAIHealth(t *testing.T) {
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+
+	handler := NewVectorStoreHandler(nil)
+	if rec := serveHandler("/admin/ai/health", http.MethodGet, "/admin/ai/health", admin, handler.AIHealth); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without chroma = %d, want 503", rec.Code)
+	}
+
+	handler.SetCollectionRepairer(fakeCollectionRepairer{recreated: []string{"product_knowledge"}})
+	rec := serveHandler("/admin/ai/health", http.MethodGet, "/admin/ai/health", admin, handler.AIHealth)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var resp AIHealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.RecreatedCollections) != 1 || resp.RecreatedCollections[0] != "product_knowledge" {
+		t.Errorf("recreated = %v, want [product_knowledge]", resp.RecreatedCollections)
+	}
+
+	handler.SetCollectionRepairer(fakeCollectionRepairer{err: errors.New("chroma unavailable")})
+	if rec := serveHandler("/admin/ai/health", http.MethodGet, "/admin/ai/health", admin, handler.AIHealth); rec.Code != http.StatusBadGateway {
+		t.Errorf("status on chroma failure = %d, want 502", rec.Code)
+	}
+}
+
+type fakeEmbeddingStatus struct {
+	status *ai.EmbeddingStatus
+}
+
+func (f fakeEmbeddingStatus) Status() (*ai.EmbeddingStatus, error) {
+	return f.status, nil
+}
+
+func TestVectorStoreHandlerEmbeddingStatus(t *testing.T) {
+	admin := testContext{tenantID: "tenant-1", userID: "admin-1", role: "admin"}
+
+	handler := NewVectorStoreHandler(nil)
+	if rec := serveHandler("/admin/embeddings/status", http.MethodGet, "/admin/embeddings/status", admin, handler.EmbeddingStatus); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without chroma = %d, want 503", rec.Code)
+	}
+
+	handler.SetEmbeddingStatus(fakeEmbeddingStatus{status: &ai.EmbeddingStatus{
+		Collection: "product_knowledge", CurrentModel: "text-embedding-004", IndexedModel: "embedding-001", Reindexing: true, PendingCount: 12,
+	}})
+	rec := serveHandler("/admin/embeddings/status", http.MethodGet, "/admin/embeddings/status", admin, handler.EmbeddingStatus)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var resp ai.EmbeddingStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if resp.CurrentModel != "text-embedding-004" || !resp.Reindexing || resp.PendingCount != 12 {
+		t.Errorf("response = %+v", resp)
+	}
+}
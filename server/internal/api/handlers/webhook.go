@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/integrations/crm"
+	"ai-conversation-platform/internal/services/webhook"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// minWebhookSecretLength is the shortest signing secret an admin may choose
+const minWebhookSecretLength = 16
+
+// WebhookHandler handles tenant webhooks for conversation events
+type WebhookHandler struct {
+	webhookStorage *postgres.WebhookStorage
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookStorage *postgres.WebhookStorage) *WebhookHandler {
+	return &WebhookHandler{webhookStorage: webhookStorage}
+}
+
+// CreateWebhookRequest represents the request body for creating a webhook
+type CreateWebhookRequest struct {
+	URL      string   `json:"url" binding:"required"`
+	Events   []string `json:"events" binding:"required"`
+	Secret   string   `json:"secret"`   // Generated when empty
+	CRMType  *string  `json:"crm_type"` // Applies the tenant's CRM field mapping to payloads
+	IsActive *bool    `json:"is_active"`
+}
+
+// UpdateWebhookRequest represents the request body for updating a webhook; omitted fields are kept
+type UpdateWebhookRequest struct {
+	URL      *string  `json:"url"`
+	Events   []string `json:"events"`
+	Secret   *string  `json:"secret"`
+	CRMType  *string  `json:"crm_type"` // "" removes the CRM mapping
+	IsActive *bool    `json:"is_active"`
+}
+
+// WebhookResponse represents a webhook
+type WebhookResponse struct {
+	Webhook *postgres.Webhook `json:"webhook"`
+}
+
+// CreateWebhookResponse includes the signing secret, which is only shown once
+type CreateWebhookResponse struct {
+	Webhook *postgres.Webhook `json:"webhook"`
+	Secret  string            `json:"secret"`
+}
+
+// ListWebhooksResponse represents the response for listing webhooks
+type ListWebhooksResponse struct {
+	Webhooks []*postgres.Webhook `json:"webhooks"`
+	Total    int                 `json:"total"`
+}
+
+// validateWebhookURL requires an absolute https URL
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute https:// URL")
+	}
+	return nil
+}
+
+// validateWebhookEvents requires at least one supported event
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return fmt.Errorf("events must list at least one event")
+	}
+	for _, event := range events {
+		if !webhook.IsSupportedEvent(event) {
+			return fmt.Errorf("unsupported event %q, must be one of: %s", event, strings.Join(webhook.SupportedEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// normalizeWebhookCRMType validates an optional CRM type; empty means none
+func normalizeWebhookCRMType(crmType *string) (*string, error) {
+	if crmType == nil {
+		return nil, nil
+	}
+	value := strings.ToLower(strings.TrimSpace(*crmType))
+	if value == "" {
+		return nil, nil
+	}
+	if !crm.IsValidType(value) {
+		return nil, fmt.Errorf("crm_type must be one of: hubspot, salesforce, zoho, custom")
+	}
+	return &value, nil
+}
+
+// ListWebhooks handles GET /api/webhooks (admin only)
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	hooks, err := h.webhookStorage.ListWebhooks(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if hooks == nil {
+		hooks = []*postgres.Webhook{}
+	}
+
+	c.JSON(http.StatusOK, ListWebhooksResponse{Webhooks: hooks, Total: len(hooks)})
+}
+
+// GetWebhook handles GET /api/webhooks/:id (admin only)
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	hook, err := h.webhookStorage.GetWebhook(tenantID, c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookResponse{Webhook: hook})
+}
+
+// CreateWebhook handles POST /api/webhooks (admin only). Deliveries are signed with the returned
+// secret: X-Webhook-Signature is "sha256=" followed by the hex HMAC-SHA256 of the body.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook := &postgres.Webhook{
+		TenantID: tenantID,
+		URL:      strings.TrimSpace(req.URL),
+		Secret:   req.Secret,
+		Events:   req.Events,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+	if err := validateWebhookURL(hook.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateWebhookEvents(hook.Events); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	crmType, err := normalizeWebhookCRMType(req.CRMType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hook.CRMType = crmType
+
+	if hook.Secret == "" {
+		if hook.Secret, err = webhook.GenerateSecret(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else if len(hook.Secret) < minWebhookSecretLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret must be at least 16 characters"})
+		return
+	}
+
+	if err := h.webhookStorage.CreateWebhook(hook); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateWebhookResponse{Webhook: hook, Secret: hook.Secret})
+}
+
+// UpdateWebhook handles PUT /api/webhooks/:id (admin only)
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(strings.TrimSpace(*req.URL)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Secret != nil && len(*req.Secret) < minWebhookSecretLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret must be at least 16 characters"})
+		return
+	}
+	crmType, err := normalizeWebhookCRMType(req.CRMType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook, err := h.webhookStorage.GetWebhook(tenantID, c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	if req.URL != nil {
+		hook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		hook.Events = req.Events
+	}
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+	if req.CRMType != nil {
+		hook.CRMType = crmType
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	if err := h.webhookStorage.UpdateWebhook(hook); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookResponse{Webhook: hook})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id (admin only)
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "tenant_id not found in context"})
+		return
+	}
+
+	if err := h.webhookStorage.DeleteWebhook(tenantID, c.Param("id")); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// writeWebhookError maps "not found" to 404 and anything else to 500
+func writeWebhookError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateWebhookRejectsInvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewWebhookHandler(nil)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("role", "admin")
+	})
+	engine.POST("/api/webhooks", handler.CreateWebhook)
+	engine.PUT("/api/webhooks/:id", handler.UpdateWebhook)
+
+	tests := map[string]struct{ method, path, body string }{
+		"missing url":       {http.MethodPost, "/api/webhooks", `{"events": ["message.created"]}`},
+		"plain http url":    {http.MethodPost, "/api/webhooks", `{"url": "http://example.com/hook", "events": ["message.created"]}`},
+		"relative url":      {http.MethodPost, "/api/webhooks", `{"url": "/hook", "events": ["message.created"]}`},
+		"no events":         {http.MethodPost, "/api/webhooks", `{"url": "https://example.com/hook", "events": []}`},
+		"unknown event":     {http.MethodPost, "/api/webhooks", `{"url": "https://example.com/hook", "events": ["conversation.deleted"]}`},
+		"short secret":      {http.MethodPost, "/api/webhooks", `{"url": "https://example.com/hook", "events": ["message.created"], "secret": "short"}`},
+		"unknown crm type":  {http.MethodPost, "/api/webhooks", `{"url": "https://example.com/hook", "events": ["message.created"], "crm_type": "pipedrive"}`},
+		"update http url":   {http.MethodPut, "/api/webhooks/w1", `{"url": "http://example.com/hook"}`},
+		"update no events":  {http.MethodPut, "/api/webhooks/w1", `{"events": []}`},
+		"update bad secret": {http.MethodPut, "/api/webhooks/w1", `{"secret": ""}`},
+	}
+	for name, tc := range tests {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
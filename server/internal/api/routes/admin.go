@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// AdminRouter registers tenant administration routes (admin only)
+type AdminRouter struct {
+	corsConfigHandler  *handlers.CORSConfigHandler
+	credentialsHandler *handlers.CredentialsHandler
+	slackConfigHandler *handlers.SlackConfigHandler
+	crmConfigHandler   *handlers.CRMConfigHandler
+	calibrationHandler *handlers.CalibrationHandler
+	aiConfigHandler    *handlers.AIConfigHandler
+	userAdminHandler   *handlers.UserAdminHandler
+	vectorStoreHandler *handlers.VectorStoreHandler
+}
+
+// NewAdminRouter creates a new admin router
+func NewAdminRouter(
+	corsConfigHandler *handlers.CORSConfigHandler,
+	credentialsHandler *handlers.CredentialsHandler,
+	slackConfigHandler *handlers.SlackConfigHandler,
+	crmConfigHandler *handlers.CRMConfigHandler,
+	calibrationHandler *handlers.CalibrationHandler,
+	aiConfigHandler *handlers.AIConfigHandler,
+	userAdminHandler *handlers.UserAdminHandler,
+	vectorStoreHandler *handlers.VectorStoreHandler,
+) *AdminRouter {
+	return &AdminRouter{
+		corsConfigHandler:  corsConfigHandler,
+		credentialsHandler: credentialsHandler,
+		slackConfigHandler: slackConfigHandler,
+		crmConfigHandler:   crmConfigHandler,
+		calibrationHandler: calibrationHandler,
+		aiConfigHandler:    aiConfigHandler,
+		userAdminHandler:   userAdminHandler,
+		vectorStoreHandler: vectorStoreHandler,
+	}
+}
+
+// Name returns the router name
+func (r *AdminRouter) Name() string { return "admin" }
+
+// Middlewares restricts all admin routes to admins
+func (r *AdminRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin routes
+func (r *AdminRouter) Register(group *gin.RouterGroup) {
+	admin := group.Group("/admin")
+	admin.GET("/cors-config", r.corsConfigHandler.GetCORSConfig)
+	admin.PUT("/cors-config", r.corsConfigHandler.UpdateCORSConfig)
+	admin.PUT("/credentials/gemini", r.credentialsHandler.SetGeminiKey)
+	admin.DELETE("/credentials/gemini", r.credentialsHandler.DeleteGeminiKey)
+	admin.GET("/credentials/gemini/status", r.credentialsHandler.GetGeminiKeyStatus)
+	admin.PUT("/slack-config", r.slackConfigHandler.UpdateSlackConfig)
+	admin.POST("/slack-config/test", r.slackConfigHandler.TestSlackConfig)
+	admin.PUT("/crm-config/:crm_type", r.crmConfigHandler.UpdateCRMConfig)
+	admin.GET("/crm-config/:crm_type/test", r.crmConfigHandler.TestCRMConfig)
+	admin.GET("/intent-config", r.aiConfigHandler.GetIntentConfig)
+	admin.PUT("/intent-config", r.aiConfigHandler.UpdateIntentConfig)
+	admin.DELETE("/intent-config", r.aiConfigHandler.ResetIntentConfig)
+	admin.GET("/suggestion-config", r.aiConfigHandler.GetSuggestionConfig)
+	admin.PUT("/suggestion-config", r.aiConfigHandler.UpdateSuggestionConfig)
+	admin.GET("/users", r.userAdminHandler.ListUsers)
+	admin.POST("/users/invite", r.userAdminHandler.InviteUser)
+	admin.GET("/users/:id", r.userAdminHandler.GetUser)
+	admin.DELETE("/users/:id", r.userAdminHandler.DeactivateUser)
+	admin.PUT("/users/:id/role", r.userAdminHandler.UpdateUserRole)
+	admin.POST("/vector-store/cleanup-orphans", r.vectorStoreHandler.CleanupOrphans)
+	admin.GET("/ai/health", r.vectorStoreHandler.AIHealth)
+	admin.GET("/ai/confidence-calibration", r.calibrationHandler.GetConfidenceCalibration)
+	admin.GET("/embeddings/status", r.vectorStoreHandler.EmbeddingStatus)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// AgentProfileRouter registers the requesting agent's suggestion profile routes
+type AgentProfileRouter struct {
+	handler *handlers.AgentProfileHandler
+}
+
+// NewAgentProfileRouter creates a new agent profile router
+func NewAgentProfileRouter(handler *handlers.AgentProfileHandler) *AgentProfileRouter {
+	return &AgentProfileRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AgentProfileRouter) Name() string { return "agentprofile" }
+
+// Middlewares returns no router-wide middlewares
+func (r *AgentProfileRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /agents/me/profile routes
+func (r *AgentProfileRouter) Register(group *gin.RouterGroup) {
+	profile := group.Group("/agents/me/profile")
+	profile.GET("", r.handler.GetProfile)
+	profile.POST("/rebuild", r.handler.RebuildProfile)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// AgentAssistRouter registers AI suggestion and insight routes
+type AgentAssistRouter struct {
+	handler *handlers.AgentAssistHandler
+}
+
+// NewAgentAssistRouter creates a new agent assist router
+func NewAgentAssistRouter(handler *handlers.AgentAssistHandler) *AgentAssistRouter {
+	return &AgentAssistRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AgentAssistRouter) Name() string { return "agentassist" }
+
+// Middlewares returns no router-wide middlewares
+func (r *AgentAssistRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers agent assist routes
+func (r *AgentAssistRouter) Register(group *gin.RouterGroup) {
+	group.POST("/conversations/:id/suggestions", r.handler.GetSuggestions)
+	group.GET("/conversations/:id/suggestions/stream", r.handler.StreamSuggestions)
+	group.GET("/conversations/:id/insights", r.handler.GetInsights)
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// AnalyticsRouter registers analytics routes
+type AnalyticsRouter struct {
+	handler *handlers.AnalyticsHandler
+}
+
+// NewAnalyticsRouter creates a new analytics router
+func NewAnalyticsRouter(handler *handlers.AnalyticsHandler) *AnalyticsRouter {
+	return &AnalyticsRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AnalyticsRouter) Name() string { return "analytics" }
+
+// Middlewares returns no router-wide middlewares
+func (r *AnalyticsRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /analytics routes
+func (r *AnalyticsRouter) Register(group *gin.RouterGroup) {
+	group.GET("/conversations/:id/complexity", r.handler.GetComplexity)
+
+	analytics := group.Group("/analytics")
+	analytics.GET("/leads", r.handler.GetLeads)
+	analytics.GET("/leads/export", r.handler.ExportLeads)
+	analytics.GET("/conversations/:id/win-probability", r.handler.GetWinProbability)
+	analytics.GET("/conversations/:id/churn-risk", r.handler.GetChurnRisk)
+	analytics.GET("/conversations/:id/trends", r.handler.GetTrends)
+	analytics.GET("/conversations/:id/clv", r.handler.GetCLV)
+	analytics.GET("/conversations/:id/sales-cycle", r.handler.GetSalesCycle)
+	analytics.GET("/dashboard", r.handler.GetDashboard)
+	analytics.GET("/dashboard/stream", r.handler.StreamDashboard)
+	analytics.GET("/complexity-distribution", r.handler.GetComplexityDistribution)
+	analytics.GET("/suggestions/acceptance-rate", r.handler.GetSuggestionAcceptanceRate)
+	analytics.GET("/objections/resolution-rates", r.handler.GetObjectionResolutionRates)
+	analytics.GET("/customer-segments", r.handler.GetCustomerSegments)
+	analytics.GET("/dwell-time", r.handler.GetDwellTime)
+	analytics.GET("/sla-breaches", r.handler.GetSLABreaches)
+	analytics.GET("/languages", r.handler.GetLanguageDistribution)
+	analytics.GET("/languages/mixed-conversations", r.handler.GetMixedLanguageConversations)
+	analytics.GET("/agents/:agent_id/performance", r.handler.GetAgentPerformance)
+
+	// Admin-only analytics routes
+	analyticsAdmin := analytics.Group("", middleware.AdminMiddleware())
+	analyticsAdmin.GET("/conversations/:id/quality", r.handler.GetQuality)
+	analyticsAdmin.GET("/leaderboard", r.handler.GetLeaderboard)
+	analyticsAdmin.GET("/export", r.handler.ExportAnalytics)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// AnalyticsConfigRouter registers the analytics weights and thresholds routes (admin only)
+type AnalyticsConfigRouter struct {
+	handler *handlers.AnalyticsConfigHandler
+}
+
+// NewAnalyticsConfigRouter creates a new analytics config router
+func NewAnalyticsConfigRouter(handler *handlers.AnalyticsConfigHandler) *AnalyticsConfigRouter {
+	return &AnalyticsConfigRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AnalyticsConfigRouter) Name() string { return "analytics-config" }
+
+// Middlewares restricts the config to admins
+func (r *AnalyticsConfigRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin/analytics-config
+func (r *AnalyticsConfigRouter) Register(group *gin.RouterGroup) {
+	group.GET("/admin/analytics-config", r.handler.GetAnalyticsConfig)
+	group.PUT("/admin/analytics-config", r.handler.UpdateAnalyticsConfig)
+}
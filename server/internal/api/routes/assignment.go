@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// AssignmentRouter registers the conversation assignment routes
+type AssignmentRouter struct {
+	handler *handlers.AssignmentHandler
+}
+
+// NewAssignmentRouter creates a new assignment router
+func NewAssignmentRouter(handler *handlers.AssignmentHandler) *AssignmentRouter {
+	return &AssignmentRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AssignmentRouter) Name() string { return "assignment" }
+
+// Middlewares returns no router-wide middlewares
+func (r *AssignmentRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers assignment routes
+func (r *AssignmentRouter) Register(group *gin.RouterGroup) {
+	group.GET("/conversations/:id/assign", r.handler.GetAssignment)
+	group.PUT("/conversations/:id/assign", r.handler.AssignAgent)
+}
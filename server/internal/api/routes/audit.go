@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// AuditRouter registers the audit log route (admin only)
+type AuditRouter struct {
+	handler *handlers.AuditLogHandler
+}
+
+// NewAuditRouter creates a new audit router
+func NewAuditRouter(handler *handlers.AuditLogHandler) *AuditRouter {
+	return &AuditRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AuditRouter) Name() string { return "audit" }
+
+// Middlewares restricts the audit log to admins
+func (r *AuditRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin/audit-logs
+func (r *AuditRouter) Register(group *gin.RouterGroup) {
+	group.GET("/admin/audit-logs", r.handler.ListAuditLogs)
+}
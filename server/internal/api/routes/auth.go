@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// AuthRouter registers the public authentication routes
+type AuthRouter struct {
+	handler *handlers.AuthHandler
+}
+
+// NewAuthRouter creates a new auth router
+func NewAuthRouter(handler *handlers.AuthHandler) *AuthRouter {
+	return &AuthRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *AuthRouter) Name() string { return "auth" }
+
+// Middlewares returns no middlewares; auth routes are public
+func (r *AuthRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /auth routes
+func (r *AuthRouter) Register(group *gin.RouterGroup) {
+	auth := group.Group("/auth")
+	auth.POST("/login", r.handler.Login)
+	auth.POST("/customer-login", r.handler.CustomerLogin)
+	auth.POST("/refresh", r.handler.Refresh)
+	auth.POST("/logout", r.handler.Logout)
+	auth.POST("/accept-invite", r.handler.AcceptInvite)
+}
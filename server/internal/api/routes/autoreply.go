@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// AutoReplyRouter registers auto-reply configuration routes
+type AutoReplyRouter struct {
+	handler *handlers.AutoReplyHandler
+	audit   middleware.AuditRecorder
+}
+
+// NewAutoReplyRouter creates a new auto-reply router
+func NewAutoReplyRouter(handler *handlers.AutoReplyHandler) *AutoReplyRouter {
+	return &AutoReplyRouter{handler: handler}
+}
+
+// SetAuditRecorder records global auto-reply config changes in the audit log (optional)
+func (r *AutoReplyRouter) SetAuditRecorder(audit middleware.AuditRecorder) {
+	r.audit = audit
+}
+
+// Name returns the router name
+func (r *AutoReplyRouter) Name() string { return "autoreply" }
+
+// Middlewares returns no router-wide middlewares
+func (r *AutoReplyRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /autoreply and per-conversation auto-reply routes
+func (r *AutoReplyRouter) Register(group *gin.RouterGroup) {
+	// Global config (admin only)
+	global := group.Group("/autoreply/global", middleware.AdminMiddleware())
+	global.GET("", r.handler.GetGlobalAutoReply)
+	global.PUT("", middleware.AuditMiddleware(r.audit, "autoreply_global", r.handler.GetGlobalAutoReply), r.handler.UpdateGlobalAutoReply)
+
+	// Conversation config (agent/admin)
+	group.GET("/conversations/:id/autoreply", r.handler.GetConversationAutoReply)
+	group.PUT("/conversations/:id/autoreply", r.handler.UpdateConversationAutoReply)
+
+	// Test auto-reply
+	group.POST("/conversations/:id/autoreply/test", r.handler.TestAutoReply)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// BrandToneRouter registers brand tone routes (admin only)
+type BrandToneRouter struct {
+	handler *handlers.BrandToneHandler
+	audit   middleware.AuditRecorder
+}
+
+// NewBrandToneRouter creates a new brand tone router
+func NewBrandToneRouter(handler *handlers.BrandToneHandler) *BrandToneRouter {
+	return &BrandToneRouter{handler: handler}
+}
+
+// SetAuditRecorder records brand tone changes in the audit log (optional)
+func (r *BrandToneRouter) SetAuditRecorder(audit middleware.AuditRecorder) {
+	r.audit = audit
+}
+
+// Name returns the router name
+func (r *BrandToneRouter) Name() string { return "brand-tone" }
+
+// Middlewares restricts all brand tone routes to admins
+func (r *BrandToneRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /brand-tone routes
+func (r *BrandToneRouter) Register(group *gin.RouterGroup) {
+	group.GET("/brand-tone", r.handler.GetBrandTone)
+	group.POST("/brand-tone", middleware.AuditMiddleware(r.audit, "brand_tone", r.handler.GetBrandTone), r.handler.UpdateBrandTone)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// BusinessHoursRouter registers business hours routes (admin only)
+type BusinessHoursRouter struct {
+	handler *handlers.BusinessHoursHandler
+}
+
+// NewBusinessHoursRouter creates a new business hours router
+func NewBusinessHoursRouter(handler *handlers.BusinessHoursHandler) *BusinessHoursRouter {
+	return &BusinessHoursRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *BusinessHoursRouter) Name() string { return "business_hours" }
+
+// Middlewares restricts all business hours routes to admins
+func (r *BusinessHoursRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /business-hours routes
+func (r *BusinessHoursRouter) Register(group *gin.RouterGroup) {
+	group.GET("/business-hours", r.handler.GetBusinessHours)
+	group.PUT("/business-hours", r.handler.UpdateBusinessHours)
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// CategoryRouter registers product category routes
+type CategoryRouter struct {
+	handler *handlers.CategoryHandler
+	audit   middleware.AuditRecorder
+}
+
+// NewCategoryRouter creates a new category router
+func NewCategoryRouter(handler *handlers.CategoryHandler) *CategoryRouter {
+	return &CategoryRouter{handler: handler}
+}
+
+// SetAuditRecorder records category changes in the audit log (optional)
+func (r *CategoryRouter) SetAuditRecorder(audit middleware.AuditRecorder) {
+	r.audit = audit
+}
+
+// Name returns the router name
+func (r *CategoryRouter) Name() string { return "categories" }
+
+// Middlewares returns no router-wide middlewares
+func (r *CategoryRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /categories routes
+func (r *CategoryRouter) Register(group *gin.RouterGroup) {
+	categories := group.Group("/categories")
+
+	// Authenticated users can browse the category tree
+	categories.GET("", r.handler.ListCategories)
+	categories.GET("/:id", r.handler.GetCategory)
+	categories.GET("/:id/products", r.handler.ListCategoryProducts)
+
+	// Admin-only management routes
+	categoriesAdmin := categories.Group("", middleware.AdminMiddleware())
+	categoriesAdmin.POST("", middleware.AuditMiddleware(r.audit, "category", nil), r.handler.CreateCategory)
+	categoriesAdmin.PUT("/:id", middleware.AuditMiddleware(r.audit, "category", r.handler.GetCategory), r.handler.UpdateCategory)
+	categoriesAdmin.DELETE("/:id", middleware.AuditMiddleware(r.audit, "category", r.handler.GetCategory), r.handler.DeleteCategory)
+}
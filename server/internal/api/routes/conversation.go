@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// ConversationRouter registers conversation, message and transfer routes
+type ConversationRouter struct {
+	handler *handlers.ConversationHandler
+}
+
+// NewConversationRouter creates a new conversation router
+func NewConversationRouter(handler *handlers.ConversationHandler) *ConversationRouter {
+	return &ConversationRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *ConversationRouter) Name() string { return "conversations" }
+
+// Middlewares returns no router-wide middlewares
+func (r *ConversationRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /conversations routes
+func (r *ConversationRouter) Register(group *gin.RouterGroup) {
+	group.POST("/conversations", r.handler.CreateConversation)
+	group.POST("/conversations/:id/messages", r.handler.SendMessage)
+	group.GET("/conversations/:id", r.handler.GetConversation)
+	group.GET("/conversations", r.handler.ListConversations)
+	group.GET("/conversations/search", r.handler.SearchConversations)
+	group.GET("/conversations/search/semantic", r.handler.SemanticSearch)
+	group.POST("/conversations/:id/transfer", r.handler.TransferConversation)
+	group.GET("/conversations/:id/export", r.handler.ExportConversation)
+	group.GET("/conversations/:id/transfer-history", r.handler.GetTransferHistory)
+	group.GET("/conversations/:id/analysis-history", r.handler.GetAnalysisHistory)
+	group.GET("/conversations/:id/summary", r.handler.GetConversationSummary)
+	group.PUT("/conversations/:id/language", r.handler.SetConversationLanguage)
+	group.PUT("/conversations/:id/status", r.handler.UpdateConversationStatus)
+	group.POST("/conversations/:id/messages/:message_id/read", r.handler.MarkMessageRead)
+	group.GET("/conversations/:id/unread-count", r.handler.GetUnreadCount)
+	group.PUT("/conversations/:id/messages/:message_id/delete", middleware.AdminMiddleware(), r.handler.DeleteMessage)
+	group.PATCH("/conversations/:id/metadata", middleware.AdminMiddleware(), r.handler.PatchConversationMetadata)
+	group.POST("/conversations/:id/merge", middleware.AdminMiddleware(), r.handler.MergeConversations)
+	group.POST("/conversations/:id/watchlist", middleware.AdminMiddleware(), r.handler.AddToWatchlist)
+	group.DELETE("/conversations/:id/watchlist", middleware.AdminMiddleware(), r.handler.RemoveFromWatchlist)
+	group.DELETE("/conversations/:id", r.handler.DeleteConversation)
+	group.GET("/admin/conversations/deleted", middleware.AdminMiddleware(), r.handler.ListDeletedConversations)
+}
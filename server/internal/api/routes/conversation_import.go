@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// ConversationImportRouter registers the conversation import route (admin only)
+type ConversationImportRouter struct {
+	handler *handlers.ConversationImportHandler
+}
+
+// NewConversationImportRouter creates a new conversation import router
+func NewConversationImportRouter(handler *handlers.ConversationImportHandler) *ConversationImportRouter {
+	return &ConversationImportRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *ConversationImportRouter) Name() string { return "conversation-import" }
+
+// Middlewares restricts imports to admins
+func (r *ConversationImportRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin/conversations/import
+func (r *ConversationImportRouter) Register(group *gin.RouterGroup) {
+	group.POST("/admin/conversations/import", r.handler.ImportConversations)
+}
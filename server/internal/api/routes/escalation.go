@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// EscalationRouter registers the escalation routes
+type EscalationRouter struct {
+	handler *handlers.EscalationHandler
+}
+
+// NewEscalationRouter creates a new escalation router
+func NewEscalationRouter(handler *handlers.EscalationHandler) *EscalationRouter {
+	return &EscalationRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *EscalationRouter) Name() string { return "escalations" }
+
+// Middlewares returns no router-wide middlewares
+func (r *EscalationRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /escalations
+func (r *EscalationRouter) Register(group *gin.RouterGroup) {
+	group.GET("/escalations", r.handler.ListEscalations)
+}
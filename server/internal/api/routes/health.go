@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// HealthRouter registers the health check and probe routes
+type HealthRouter struct {
+	handler *handlers.HealthHandler
+}
+
+// NewHealthRouter creates a new health router
+func NewHealthRouter(handler *handlers.HealthHandler) *HealthRouter {
+	return &HealthRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *HealthRouter) Name() string { return "health" }
+
+// Middlewares returns no middlewares; probes are unauthenticated
+func (r *HealthRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /health routes
+func (r *HealthRouter) Register(group *gin.RouterGroup) {
+	group.GET("/health", r.handler.Health)
+	group.GET("/health/ready", r.handler.Ready)
+	group.GET("/health/live", r.handler.Live)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// KnowledgeRouter registers knowledge base ingestion and article history routes
+type KnowledgeRouter struct {
+	handler *handlers.KnowledgeHandler
+}
+
+// NewKnowledgeRouter creates a new knowledge router
+func NewKnowledgeRouter(handler *handlers.KnowledgeHandler) *KnowledgeRouter {
+	return &KnowledgeRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *KnowledgeRouter) Name() string { return "knowledge" }
+
+// Middlewares returns no router-wide middlewares; write routes are admin only
+func (r *KnowledgeRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /knowledge routes
+func (r *KnowledgeRouter) Register(group *gin.RouterGroup) {
+	knowledge := group.Group("/knowledge")
+	knowledge.POST("/index-url", middleware.AdminMiddleware(), r.handler.IndexURL)
+	knowledge.PUT("/:id", middleware.AdminMiddleware(), r.handler.UpdateArticle)
+	knowledge.GET("/:id/versions", r.handler.ListVersions)
+	knowledge.GET("/:id/versions/:version_id", r.handler.GetVersion)
+	knowledge.POST("/:id/versions/:version_id/restore", middleware.AdminMiddleware(), r.handler.RestoreVersion)
+}
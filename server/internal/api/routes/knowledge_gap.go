@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// KnowledgeGapRouter registers the knowledge gap report route (admin only)
+type KnowledgeGapRouter struct {
+	handler *handlers.KnowledgeGapHandler
+}
+
+// NewKnowledgeGapRouter creates a new knowledge gap router
+func NewKnowledgeGapRouter(handler *handlers.KnowledgeGapHandler) *KnowledgeGapRouter {
+	return &KnowledgeGapRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *KnowledgeGapRouter) Name() string { return "knowledge-gaps" }
+
+// Middlewares restricts the report to admins
+func (r *KnowledgeGapRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin/knowledge-gaps
+func (r *KnowledgeGapRouter) Register(group *gin.RouterGroup) {
+	group.GET("/admin/knowledge-gaps", r.handler.ListKnowledgeGaps)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// MemoryRouter registers customer memory routes (admin only)
+type MemoryRouter struct {
+	handler *handlers.MemoryHandler
+}
+
+// NewMemoryRouter creates a new memory router
+func NewMemoryRouter(handler *handlers.MemoryHandler) *MemoryRouter {
+	return &MemoryRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *MemoryRouter) Name() string { return "memories" }
+
+// Middlewares restricts all memory routes to admins
+func (r *MemoryRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /memories routes
+func (r *MemoryRouter) Register(group *gin.RouterGroup) {
+	memories := group.Group("/memories")
+	memories.GET("", r.handler.ListMemories)
+	memories.GET("/:id", r.handler.GetMemory)
+	memories.POST("", r.handler.CreateMemory)
+	memories.PUT("/:id", r.handler.UpdateMemory)
+	memories.DELETE("/:id", r.handler.DeleteMemory)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// MessageStreamRouter registers the conversation WebSocket route
+type MessageStreamRouter struct {
+	handler *handlers.MessageStreamHandler
+}
+
+// NewMessageStreamRouter creates a new message stream router
+func NewMessageStreamRouter(handler *handlers.MessageStreamHandler) *MessageStreamRouter {
+	return &MessageStreamRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *MessageStreamRouter) Name() string { return "message-stream" }
+
+// Middlewares returns no router-wide middlewares
+func (r *MessageStreamRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers message stream routes
+func (r *MessageStreamRouter) Register(group *gin.RouterGroup) {
+	group.GET("/conversations/:id/ws", r.handler.StreamMessages)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// ModerationRouter registers the moderation list routes (admin only)
+type ModerationRouter struct {
+	handler *handlers.ModerationHandler
+}
+
+// NewModerationRouter creates a new moderation router
+func NewModerationRouter(handler *handlers.ModerationHandler) *ModerationRouter {
+	return &ModerationRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *ModerationRouter) Name() string { return "moderation" }
+
+// Middlewares restricts the moderation lists to admins
+func (r *ModerationRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /admin/moderation-lists routes
+func (r *ModerationRouter) Register(group *gin.RouterGroup) {
+	lists := group.Group("/admin/moderation-lists")
+	lists.GET("", r.handler.ListModerationPatterns)
+	lists.POST("", r.handler.AddModerationPattern)
+	lists.DELETE("/:id", r.handler.DeleteModerationPattern)
+}
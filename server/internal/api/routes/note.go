@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// NoteRouter registers the routes for agents' internal conversation notes
+type NoteRouter struct {
+	handler *handlers.NoteHandler
+}
+
+// NewNoteRouter creates a new note router
+func NewNoteRouter(handler *handlers.NoteHandler) *NoteRouter {
+	return &NoteRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *NoteRouter) Name() string { return "notes" }
+
+// Middlewares returns no router-wide middlewares; the handler rejects customers
+func (r *NoteRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /conversations/:id/notes routes
+func (r *NoteRouter) Register(group *gin.RouterGroup) {
+	group.GET("/conversations/:id/notes", r.handler.ListNotes)
+	group.POST("/conversations/:id/notes", r.handler.CreateNote)
+	group.PUT("/conversations/:id/notes/:note_id", r.handler.UpdateNote)
+	group.DELETE("/conversations/:id/notes/:note_id", r.handler.DeleteNote)
+}
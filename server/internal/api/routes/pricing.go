@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// PricingRouter registers pricing suggestion generation and review routes
+type PricingRouter struct {
+	handler *handlers.PricingHandler
+}
+
+// NewPricingRouter creates a new pricing router
+func NewPricingRouter(handler *handlers.PricingHandler) *PricingRouter {
+	return &PricingRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *PricingRouter) Name() string { return "pricing" }
+
+// Middlewares returns no router-wide middlewares
+func (r *PricingRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers pricing suggestion routes
+func (r *PricingRouter) Register(group *gin.RouterGroup) {
+	group.POST("/conversations/:id/pricing-suggestions", r.handler.CreatePricingSuggestion)
+	group.GET("/conversations/:id/pricing-suggestions", r.handler.ListPricingSuggestions)
+	group.PUT("/conversations/:id/pricing-suggestions/:suggestion_id/approve", middleware.AdminMiddleware(), r.handler.ApprovePricingSuggestion)
+	group.PUT("/conversations/:id/pricing-suggestions/:suggestion_id/reject", middleware.AdminMiddleware(), r.handler.RejectPricingSuggestion)
+	group.GET("/analytics/pricing-suggestions/stats", r.handler.GetPricingStats)
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// ProductRouter registers product catalog routes
+type ProductRouter struct {
+	handler *handlers.ProductHandler
+	audit   middleware.AuditRecorder
+}
+
+// NewProductRouter creates a new product router
+func NewProductRouter(handler *handlers.ProductHandler) *ProductRouter {
+	return &ProductRouter{handler: handler}
+}
+
+// SetAuditRecorder records product changes in the audit log (optional)
+func (r *ProductRouter) SetAuditRecorder(audit middleware.AuditRecorder) {
+	r.audit = audit
+}
+
+// Name returns the router name
+func (r *ProductRouter) Name() string { return "products" }
+
+// Middlewares returns no router-wide middlewares
+func (r *ProductRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /products routes
+func (r *ProductRouter) Register(group *gin.RouterGroup) {
+	products := group.Group("/products")
+
+	// Authenticated users can view products
+	products.GET("", r.handler.ListProducts)
+	products.GET("/:id", r.handler.GetProduct)
+	products.GET("/:id/variants", r.handler.ListProductVariants)
+
+	// Admin-only management routes
+	productsAdmin := products.Group("", middleware.AdminMiddleware())
+	productsAdmin.POST("", middleware.AuditMiddleware(r.audit, "product", nil), r.handler.CreateProduct)
+	productsAdmin.POST("/bulk", middleware.AuditMiddleware(r.audit, "product", nil), r.handler.BulkCreateProducts)
+	productsAdmin.PUT("/:id", middleware.AuditMiddleware(r.audit, "product", r.handler.GetProduct), r.handler.UpdateProduct)
+	productsAdmin.DELETE("/:id", middleware.AuditMiddleware(r.audit, "product", r.handler.GetProduct), r.handler.DeleteProduct)
+	productsAdmin.POST("/:id/variants", r.handler.CreateProductVariant)
+	productsAdmin.PUT("/:id/variants/:variant_id", r.handler.UpdateProductVariant)
+	productsAdmin.DELETE("/:id/variants/:variant_id", r.handler.DeleteProductVariant)
+}
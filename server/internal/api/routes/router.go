@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router registers the routes of one feature area on an API group
+type Router interface {
+	// Name identifies the router in logs
+	Name() string
+	// Middlewares are applied to every route the router registers
+	Middlewares() []gin.HandlerFunc
+	// Register adds the router's routes to the group
+	Register(r *gin.RouterGroup)
+}
+
+// RegisterAll registers each router on its own sub-group of group with the
+// router's middlewares applied. New feature areas are added by appending to routers.
+func RegisterAll(group *gin.RouterGroup, routers []Router) {
+	for _, router := range routers {
+		routerGroup := group.Group("", router.Middlewares()...)
+		router.Register(routerGroup)
+		log.Printf("[ROUTES] registered router=%s", router.Name())
+	}
+}
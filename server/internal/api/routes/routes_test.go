@@ -0,0 +1,687 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// newTestEngine registers routers under /api. The X-Test-Role header stands in
+// for the role normally set by JWT auth.
+func newTestEngine(routers ...Router) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	api := engine.Group("/api", func(c *gin.Context) {
+		c.Set("role", c.GetHeader("X-Test-Role"))
+		c.Next()
+	})
+	RegisterAll(api, routers)
+	return engine
+}
+
+func assertRoutes(t *testing.T, engine *gin.Engine, want []string) {
+	t.Helper()
+	var got []string
+	for _, route := range engine.Routes() {
+		got = append(got, route.Method+" "+route.Path)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+
+	if len(got) != len(want) {
+		t.Fatalf("registered %d routes, want %d\ngot:  %v\nwant: %v", len(got), len(want), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("route %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func serve(engine *gin.Engine, method, path, role string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-Test-Role", role)
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAuthRouter(handlers.NewAuthHandler(nil, nil, nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/auth/login",
+		"POST /api/auth/customer-login",
+		"POST /api/auth/refresh",
+		"POST /api/auth/logout",
+		"POST /api/auth/accept-invite",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/auth/login", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /api/auth/login = %d, want 404", rec.Code)
+	}
+}
+
+func TestConversationRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewConversationRouter(handlers.NewConversationHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/conversations",
+		"POST /api/conversations/:id/messages",
+		"GET /api/conversations/:id",
+		"GET /api/conversations",
+		"GET /api/conversations/search",
+		"GET /api/conversations/search/semantic",
+		"POST /api/conversations/:id/transfer",
+		"GET /api/conversations/:id/export",
+		"GET /api/conversations/:id/transfer-history",
+		"GET /api/conversations/:id/analysis-history",
+		"GET /api/conversations/:id/summary",
+		"PUT /api/conversations/:id/language",
+		"PUT /api/conversations/:id/status",
+		"POST /api/conversations/:id/messages/:message_id/read",
+		"GET /api/conversations/:id/unread-count",
+		"PUT /api/conversations/:id/messages/:message_id/delete",
+		"PATCH /api/conversations/:id/metadata",
+		"POST /api/conversations/:id/merge",
+		"POST /api/conversations/:id/watchlist",
+		"DELETE /api/conversations/:id/watchlist",
+		"DELETE /api/conversations/:id",
+		"GET /api/admin/conversations/deleted",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/conversations/c1/messages/m1/delete", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("message delete as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodPatch, "/api/conversations/c1/metadata", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("metadata patch as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodPost, "/api/conversations/c1/merge", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("merge as agent = %d, want 403", rec.Code)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		if rec := serve(engine, method, "/api/conversations/c1/watchlist", "agent"); rec.Code != http.StatusForbidden {
+			t.Errorf("%s /api/conversations/:id/watchlist as agent = %d, want 403", method, rec.Code)
+		}
+	}
+	if rec := serve(engine, http.MethodDelete, "/api/conversations/c1", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("DELETE /api/conversations/:id as customer = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodGet, "/api/admin/conversations/deleted", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/admin/conversations/deleted as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAnalyticsRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAnalyticsRouter(handlers.NewAnalyticsHandler(nil, nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/conversations/:id/complexity",
+		"GET /api/analytics/leads",
+		"GET /api/analytics/leads/export",
+		"GET /api/analytics/conversations/:id/win-probability",
+		"GET /api/analytics/conversations/:id/churn-risk",
+		"GET /api/analytics/conversations/:id/trends",
+		"GET /api/analytics/conversations/:id/clv",
+		"GET /api/analytics/conversations/:id/sales-cycle",
+		"GET /api/analytics/dashboard",
+		"GET /api/analytics/dashboard/stream",
+		"GET /api/analytics/complexity-distribution",
+		"GET /api/analytics/suggestions/acceptance-rate",
+		"GET /api/analytics/objections/resolution-rates",
+		"GET /api/analytics/customer-segments",
+		"GET /api/analytics/dwell-time",
+		"GET /api/analytics/sla-breaches",
+		"GET /api/analytics/languages",
+		"GET /api/analytics/languages/mixed-conversations",
+		"GET /api/analytics/agents/:agent_id/performance",
+		"GET /api/analytics/conversations/:id/quality",
+		"GET /api/analytics/leaderboard",
+		"GET /api/analytics/export",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/analytics/leaderboard", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("leaderboard as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodGet, "/api/analytics/export", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("export as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodPost, "/api/analytics/dashboard", "admin"); rec.Code != http.StatusNotFound {
+		t.Errorf("POST /api/analytics/dashboard = %d, want 404", rec.Code)
+	}
+}
+
+func TestProductRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewProductRouter(handlers.NewProductHandler(nil, nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/products",
+		"GET /api/products/:id",
+		"POST /api/products",
+		"POST /api/products/bulk",
+		"PUT /api/products/:id",
+		"DELETE /api/products/:id",
+		"GET /api/products/:id/variants",
+		"POST /api/products/:id/variants",
+		"PUT /api/products/:id/variants/:variant_id",
+		"DELETE /api/products/:id/variants/:variant_id",
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		path := "/api/products/p1"
+		if method == http.MethodPost {
+			path = "/api/products"
+		}
+		if rec := serve(engine, method, path, "agent"); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s as agent = %d, want 403", method, path, rec.Code)
+		}
+	}
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/products/p1/variants"},
+		{http.MethodPut, "/api/products/p1/variants/v1"},
+		{http.MethodDelete, "/api/products/p1/variants/v1"},
+	} {
+		if rec := serve(engine, route.method, route.path, "agent"); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s as agent = %d, want 403", route.method, route.path, rec.Code)
+		}
+	}
+}
+
+func TestCategoryRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewCategoryRouter(handlers.NewCategoryHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/categories",
+		"GET /api/categories/:id",
+		"GET /api/categories/:id/products",
+		"POST /api/categories",
+		"PUT /api/categories/:id",
+		"DELETE /api/categories/:id",
+	})
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/categories"},
+		{http.MethodPut, "/api/categories/c1"},
+		{http.MethodDelete, "/api/categories/c1"},
+	} {
+		if rec := serve(engine, route.method, route.path, "agent"); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s as agent = %d, want 403", route.method, route.path, rec.Code)
+		}
+	}
+}
+
+func TestRuleRouterRegister(t *testing.T) {
+	router := NewRuleRouter(handlers.NewRuleHandler(nil))
+	if len(router.Middlewares()) != 1 {
+		t.Fatalf("RuleRouter has %d middlewares, want admin middleware", len(router.Middlewares()))
+	}
+
+	engine := newTestEngine(router)
+	assertRoutes(t, engine, []string{
+		"GET /api/rules",
+		"GET /api/rules/:id",
+		"POST /api/rules",
+		"POST /api/rules/test",
+		"PUT /api/rules/:id",
+		"DELETE /api/rules/:id",
+	})
+
+	// Router-level admin middleware guards every rule route
+	for _, role := range []string{"agent", "customer", ""} {
+		if rec := serve(engine, http.MethodGet, "/api/rules", role); rec.Code != http.StatusForbidden {
+			t.Errorf("GET /api/rules as %q = %d, want 403", role, rec.Code)
+		}
+	}
+	if rec := serve(engine, http.MethodPatch, "/api/rules/r1", "admin"); rec.Code != http.StatusNotFound {
+		t.Errorf("PATCH /api/rules/:id = %d, want 404", rec.Code)
+	}
+}
+
+func TestMemoryRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewMemoryRouter(handlers.NewMemoryHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/memories",
+		"GET /api/memories/:id",
+		"POST /api/memories",
+		"PUT /api/memories/:id",
+		"DELETE /api/memories/:id",
+	})
+
+	if rec := serve(engine, http.MethodDelete, "/api/memories/m1", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("DELETE /api/memories/:id as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestBrandToneRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewBrandToneRouter(handlers.NewBrandToneHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/brand-tone",
+		"POST /api/brand-tone",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/brand-tone", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/brand-tone as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAuditRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAuditRouter(handlers.NewAuditLogHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/admin/audit-logs",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/admin/audit-logs", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/admin/audit-logs as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestConversationImportRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewConversationImportRouter(handlers.NewConversationImportHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/admin/conversations/import",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/admin/conversations/import", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/admin/conversations/import as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestKnowledgeGapRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewKnowledgeGapRouter(handlers.NewKnowledgeGapHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/admin/knowledge-gaps",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/admin/knowledge-gaps", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/admin/knowledge-gaps as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAnalyticsConfigRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAnalyticsConfigRouter(handlers.NewAnalyticsConfigHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/admin/analytics-config",
+		"PUT /api/admin/analytics-config",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/admin/analytics-config", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/admin/analytics-config as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestModerationRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewModerationRouter(handlers.NewModerationHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/admin/moderation-lists",
+		"POST /api/admin/moderation-lists",
+		"DELETE /api/admin/moderation-lists/:id",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/admin/moderation-lists", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/admin/moderation-lists as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestEscalationRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewEscalationRouter(handlers.NewEscalationHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/escalations",
+	})
+}
+
+func TestSLARouterRegister(t *testing.T) {
+	engine := newTestEngine(NewSLARouter(handlers.NewSLAConfigHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/sla-config",
+		"PUT /api/sla-config",
+		"GET /api/products/:id/sla",
+		"PUT /api/products/:id/sla",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/sla-config", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/sla-config as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodGet, "/api/products/p1/sla", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/products/p1/sla as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestWebhookRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewWebhookRouter(handlers.NewWebhookHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/webhooks",
+		"POST /api/webhooks",
+		"GET /api/webhooks/:id",
+		"PUT /api/webhooks/:id",
+		"DELETE /api/webhooks/:id",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/webhooks", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/webhooks as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodDelete, "/api/webhooks/w1", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("DELETE /api/webhooks/:id as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestRoutingRuleRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewRoutingRuleRouter(handlers.NewRoutingRuleHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/routing-rules",
+		"POST /api/routing-rules",
+		"GET /api/routing-rules/:id",
+		"PUT /api/routing-rules/:id",
+		"DELETE /api/routing-rules/:id",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/routing-rules", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/routing-rules as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodGet, "/api/routing-rules", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/routing-rules as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestBusinessHoursRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewBusinessHoursRouter(handlers.NewBusinessHoursHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/business-hours",
+		"PUT /api/business-hours",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/business-hours", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/business-hours as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestTenantDataRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewTenantDataRouter(handlers.NewTenantDataHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"DELETE /api/admin/tenants/:tenant_id/data",
+	})
+
+	for _, role := range []string{"admin", "agent"} {
+		if rec := serve(engine, http.MethodDelete, "/api/admin/tenants/t1/data", role); rec.Code != http.StatusForbidden {
+			t.Errorf("DELETE /api/admin/tenants/:tenant_id/data as %s = %d, want 403", role, rec.Code)
+		}
+	}
+}
+
+func TestKnowledgeRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewKnowledgeRouter(handlers.NewKnowledgeHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/knowledge/index-url",
+		"PUT /api/knowledge/:id",
+		"GET /api/knowledge/:id/versions",
+		"GET /api/knowledge/:id/versions/:version_id",
+		"POST /api/knowledge/:id/versions/:version_id/restore",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/knowledge/index-url", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/knowledge/index-url as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodPost, "/api/knowledge/a1/versions/v1/restore", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/knowledge/:id/versions/:version_id/restore as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAutoReplyRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAutoReplyRouter(handlers.NewAutoReplyHandler(nil, nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/autoreply/global",
+		"PUT /api/autoreply/global",
+		"GET /api/conversations/:id/autoreply",
+		"PUT /api/conversations/:id/autoreply",
+		"POST /api/conversations/:id/autoreply/test",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/autoreply/global", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/autoreply/global as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAgentAssistRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAgentAssistRouter(handlers.NewAgentAssistHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/conversations/:id/suggestions",
+		"GET /api/conversations/:id/suggestions/stream",
+		"GET /api/conversations/:id/insights",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/conversations/c1/suggestions", "agent"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /api/conversations/:id/suggestions = %d, want 404", rec.Code)
+	}
+}
+
+func TestAgentProfileRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAgentProfileRouter(handlers.NewAgentProfileHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/agents/me/profile",
+		"POST /api/agents/me/profile/rebuild",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/agents/me/profile/rebuild", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/agents/me/profile/rebuild as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestTranscriptRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewTranscriptRouter(handlers.NewTranscriptHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/conversations/:id/send-transcript",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/conversations/c1/send-transcript", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/conversations/:id/send-transcript as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestTimelineRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewTimelineRouter(handlers.NewTimelineHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/conversations/:id/timeline",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/conversations/c1/timeline", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/conversations/:id/timeline as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestAssignmentRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAssignmentRouter(handlers.NewAssignmentHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/conversations/:id/assign",
+		"PUT /api/conversations/:id/assign",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/conversations/c1/assign", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/conversations/:id/assign as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestTagRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewTagRouter(handlers.NewTagHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/tags",
+		"POST /api/tags",
+		"PUT /api/tags/:id",
+		"DELETE /api/tags/:id",
+		"POST /api/conversations/:id/tags",
+		"DELETE /api/conversations/:id/tags/:tag_id",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/conversations/c1/tags", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/conversations/:id/tags as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestNoteRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewNoteRouter(handlers.NewNoteHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/conversations/:id/notes",
+		"POST /api/conversations/:id/notes",
+		"PUT /api/conversations/:id/notes/:note_id",
+		"DELETE /api/conversations/:id/notes/:note_id",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/conversations/c1/notes", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/conversations/:id/notes as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestSuggestionFeedbackRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewSuggestionFeedbackRouter(handlers.NewSuggestionFeedbackHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/conversations/:id/suggestions/:suggestion_id/feedback",
+	})
+
+	if rec := serve(engine, http.MethodPost, "/api/conversations/c1/suggestions/s1/feedback", "customer"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /api/conversations/:id/suggestions/:suggestion_id/feedback as customer = %d, want 403", rec.Code)
+	}
+}
+
+func TestMessageStreamRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewMessageStreamRouter(handlers.NewMessageStreamHandler(nil, nil, handlers.MessageStreamConfig{})))
+	assertRoutes(t, engine, []string{
+		"GET /api/conversations/:id/ws",
+	})
+}
+
+func TestSuperAdminRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewSuperAdminRouter(handlers.NewSuperAdminHandler(nil, nil), handlers.NewCalibrationHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/superadmin/churn-risk-aggregate",
+		"GET /api/superadmin/win-rate-aggregate",
+		"GET /api/superadmin/usage",
+		"POST /api/superadmin/calibrate-model",
+	})
+
+	t.Setenv("SUPER_ADMIN_TOKEN", "")
+	if rec := serve(engine, http.MethodGet, "/api/superadmin/usage", "admin"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /api/superadmin/usage without SUPER_ADMIN_TOKEN configured = %d, want 404", rec.Code)
+	}
+
+	// A tenant admin role is never enough; only the super admin token is accepted
+	t.Setenv("SUPER_ADMIN_TOKEN", "operator-secret")
+	if rec := serve(engine, http.MethodGet, "/api/superadmin/usage", "admin"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/superadmin/usage as tenant admin = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/superadmin/usage?days=0", nil)
+	req.Header.Set("Authorization", "Bearer operator-secret")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/superadmin/usage?days=0 with token = %d, want 400", rec.Code)
+	}
+}
+
+func TestHealthRouterRegister(t *testing.T) {
+	engine := gin.New()
+	RegisterAll(engine.Group(""), []Router{NewHealthRouter(handlers.NewHealthHandler(nil))})
+	assertRoutes(t, engine, []string{
+		"GET /health",
+		"GET /health/ready",
+		"GET /health/live",
+	})
+
+	// Without a health token only the plain status is returned, so no checks run
+	t.Setenv("HEALTH_TOKEN", "probe-secret")
+	rec := serve(engine, http.MethodGet, "/health", "")
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "components") {
+		t.Errorf("GET /health without token = %d %s, want plain 200", rec.Code, rec.Body.String())
+	}
+	if rec := serve(engine, http.MethodGet, "/health/live", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /health/live = %d, want 200", rec.Code)
+	}
+}
+
+func TestPricingRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewPricingRouter(handlers.NewPricingHandler(nil, nil)))
+	assertRoutes(t, engine, []string{
+		"POST /api/conversations/:id/pricing-suggestions",
+		"GET /api/conversations/:id/pricing-suggestions",
+		"PUT /api/conversations/:id/pricing-suggestions/:suggestion_id/approve",
+		"PUT /api/conversations/:id/pricing-suggestions/:suggestion_id/reject",
+		"GET /api/analytics/pricing-suggestions/stats",
+	})
+
+	if rec := serve(engine, http.MethodPut, "/api/conversations/c1/pricing-suggestions/p1/approve", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("approve as agent = %d, want 403", rec.Code)
+	}
+}
+
+func TestAdminRouterRegister(t *testing.T) {
+	engine := newTestEngine(NewAdminRouter(handlers.NewCORSConfigHandler(nil), handlers.NewCredentialsHandler(nil, nil), handlers.NewSlackConfigHandler(nil, nil), handlers.NewCRMConfigHandler(nil), handlers.NewCalibrationHandler(nil, nil), handlers.NewAIConfigHandler(nil), handlers.NewUserAdminHandler(nil), handlers.NewVectorStoreHandler(nil)))
+	assertRoutes(t, engine, []string{
+		"GET /api/admin/cors-config",
+		"PUT /api/admin/cors-config",
+		"PUT /api/admin/credentials/gemini",
+		"DELETE /api/admin/credentials/gemini",
+		"GET /api/admin/credentials/gemini/status",
+		"PUT /api/admin/slack-config",
+		"POST /api/admin/slack-config/test",
+		"PUT /api/admin/crm-config/:crm_type",
+		"GET /api/admin/crm-config/:crm_type/test",
+		"GET /api/admin/intent-config",
+		"PUT /api/admin/intent-config",
+		"DELETE /api/admin/intent-config",
+		"GET /api/admin/suggestion-config",
+		"PUT /api/admin/suggestion-config",
+		"GET /api/admin/users",
+		"POST /api/admin/users/invite",
+		"GET /api/admin/users/:id",
+		"DELETE /api/admin/users/:id",
+		"PUT /api/admin/users/:id/role",
+		"POST /api/admin/vector-store/cleanup-orphans",
+		"GET /api/admin/ai/health",
+		"GET /api/admin/ai/confidence-calibration",
+		"GET /api/admin/embeddings/status",
+	})
+
+	if rec := serve(engine, http.MethodGet, "/api/admin/cors-config", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/admin/cors-config as agent = %d, want 403", rec.Code)
+	}
+	if rec := serve(engine, http.MethodPut, "/api/admin/credentials/gemini", "agent"); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT /api/admin/credentials/gemini as agent = %d, want 403", rec.Code)
+	}
+}
+
+// TestAllRoutersRegisterTogether guards against path conflicts between routers,
+// which gin reports by panicking at registration time
+func TestAllRoutersRegisterTogether(t *testing.T) {
+	newTestEngine(
+		NewConversationRouter(handlers.NewConversationHandler(nil, nil)),
+		NewRuleRouter(handlers.NewRuleHandler(nil)),
+		NewAnalyticsRouter(handlers.NewAnalyticsHandler(nil, nil, nil)),
+		NewProductRouter(handlers.NewProductHandler(nil, nil, nil)),
+		NewCategoryRouter(handlers.NewCategoryHandler(nil, nil)),
+		NewMemoryRouter(handlers.NewMemoryHandler(nil)),
+		NewBrandToneRouter(handlers.NewBrandToneHandler(nil)),
+		NewSLARouter(handlers.NewSLAConfigHandler(nil, nil)),
+		NewWebhookRouter(handlers.NewWebhookHandler(nil)),
+		NewRoutingRuleRouter(handlers.NewRoutingRuleHandler(nil)),
+		NewBusinessHoursRouter(handlers.NewBusinessHoursHandler(nil)),
+		NewPricingRouter(handlers.NewPricingHandler(nil, nil)),
+		NewAdminRouter(handlers.NewCORSConfigHandler(nil), handlers.NewCredentialsHandler(nil, nil), handlers.NewSlackConfigHandler(nil, nil), handlers.NewCRMConfigHandler(nil), handlers.NewCalibrationHandler(nil, nil), handlers.NewAIConfigHandler(nil), handlers.NewUserAdminHandler(nil), handlers.NewVectorStoreHandler(nil)),
+		NewAgentAssistRouter(handlers.NewAgentAssistHandler(nil)),
+		NewAgentProfileRouter(handlers.NewAgentProfileHandler(nil, nil)),
+		NewTranscriptRouter(handlers.NewTranscriptHandler(nil)),
+		NewTimelineRouter(handlers.NewTimelineHandler(nil)),
+		NewAssignmentRouter(handlers.NewAssignmentHandler(nil)),
+		NewTagRouter(handlers.NewTagHandler(nil)),
+		NewTenantDataRouter(handlers.NewTenantDataHandler(nil)),
+		NewMessageStreamRouter(handlers.NewMessageStreamHandler(nil, nil, handlers.MessageStreamConfig{})),
+		NewAutoReplyRouter(handlers.NewAutoReplyHandler(nil, nil, nil)),
+		NewKnowledgeRouter(handlers.NewKnowledgeHandler(nil, nil)),
+		NewSuperAdminRouter(handlers.NewSuperAdminHandler(nil, nil), handlers.NewCalibrationHandler(nil, nil)),
+		NewAuditRouter(handlers.NewAuditLogHandler(nil)),
+		NewEscalationRouter(handlers.NewEscalationHandler(nil)),
+		NewConversationImportRouter(handlers.NewConversationImportHandler(nil)),
+		NewKnowledgeGapRouter(handlers.NewKnowledgeGapHandler(nil)),
+		NewAnalyticsConfigRouter(handlers.NewAnalyticsConfigHandler(nil)),
+		NewModerationRouter(handlers.NewModerationHandler(nil)),
+		NewNoteRouter(handlers.NewNoteHandler(nil)),
+	)
+}
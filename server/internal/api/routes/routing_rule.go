@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// RoutingRuleRouter registers conversation routing rule routes (admin only)
+type RoutingRuleRouter struct {
+	handler *handlers.RoutingRuleHandler
+}
+
+// NewRoutingRuleRouter creates a new routing rule router
+func NewRoutingRuleRouter(handler *handlers.RoutingRuleHandler) *RoutingRuleRouter {
+	return &RoutingRuleRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *RoutingRuleRouter) Name() string { return "routing_rules" }
+
+// Middlewares restricts all routing rule routes to admins
+func (r *RoutingRuleRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /routing-rules routes
+func (r *RoutingRuleRouter) Register(group *gin.RouterGroup) {
+	rules := group.Group("/routing-rules")
+	rules.GET("", r.handler.ListRoutingRules)
+	rules.POST("", r.handler.CreateRoutingRule)
+	rules.GET("/:id", r.handler.GetRoutingRule)
+	rules.PUT("/:id", r.handler.UpdateRoutingRule)
+	rules.DELETE("/:id", r.handler.DeleteRoutingRule)
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// RuleRouter registers rule management routes (admin only)
+type RuleRouter struct {
+	handler *handlers.RuleHandler
+	audit   middleware.AuditRecorder
+}
+
+// NewRuleRouter creates a new rule router
+func NewRuleRouter(handler *handlers.RuleHandler) *RuleRouter {
+	return &RuleRouter{handler: handler}
+}
+
+// SetAuditRecorder records rule changes in the audit log (optional)
+func (r *RuleRouter) SetAuditRecorder(audit middleware.AuditRecorder) {
+	r.audit = audit
+}
+
+// Name returns the router name
+func (r *RuleRouter) Name() string { return "rules" }
+
+// Middlewares restricts all rule routes to admins
+func (r *RuleRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /rules routes
+func (r *RuleRouter) Register(group *gin.RouterGroup) {
+	rules := group.Group("/rules")
+	rules.GET("", r.handler.ListRules)
+	rules.GET("/:id", r.handler.GetRule)
+	rules.POST("", middleware.AuditMiddleware(r.audit, "rule", nil), r.handler.CreateRule)
+	rules.POST("/test", r.handler.TestRule)
+	rules.PUT("/:id", middleware.AuditMiddleware(r.audit, "rule", r.handler.GetRule), r.handler.UpdateRule)
+	rules.DELETE("/:id", middleware.AuditMiddleware(r.audit, "rule", r.handler.GetRule), r.handler.DeleteRule)
+}
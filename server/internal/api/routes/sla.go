@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// SLARouter registers response time SLA config routes (admin only)
+type SLARouter struct {
+	handler *handlers.SLAConfigHandler
+}
+
+// NewSLARouter creates a new SLA router
+func NewSLARouter(handler *handlers.SLAConfigHandler) *SLARouter {
+	return &SLARouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *SLARouter) Name() string { return "sla" }
+
+// Middlewares restricts all SLA config routes to admins
+func (r *SLARouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /sla-config and per-product SLA routes
+func (r *SLARouter) Register(group *gin.RouterGroup) {
+	group.GET("/sla-config", r.handler.GetSLAConfig)
+	group.PUT("/sla-config", r.handler.UpdateSLAConfig)
+	group.GET("/products/:id/sla", r.handler.GetProductSLA)
+	group.PUT("/products/:id/sla", r.handler.UpdateProductSLA)
+}
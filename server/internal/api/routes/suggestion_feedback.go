@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// SuggestionFeedbackRouter registers the route for feedback on reply suggestions
+type SuggestionFeedbackRouter struct {
+	handler *handlers.SuggestionFeedbackHandler
+}
+
+// NewSuggestionFeedbackRouter creates a new suggestion feedback router
+func NewSuggestionFeedbackRouter(handler *handlers.SuggestionFeedbackHandler) *SuggestionFeedbackRouter {
+	return &SuggestionFeedbackRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *SuggestionFeedbackRouter) Name() string { return "suggestion-feedback" }
+
+// Middlewares returns no router-wide middlewares
+func (r *SuggestionFeedbackRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers suggestion feedback routes
+func (r *SuggestionFeedbackRouter) Register(group *gin.RouterGroup) {
+	group.POST("/conversations/:id/suggestions/:suggestion_id/feedback", r.handler.SubmitFeedback)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// SuperAdminRouter registers cross-tenant monitoring and global model calibration routes for the
+// platform operator.
+// They are authenticated with SUPER_ADMIN_TOKEN and must be registered outside the JWT group.
+type SuperAdminRouter struct {
+	handler            *handlers.SuperAdminHandler
+	calibrationHandler *handlers.CalibrationHandler
+}
+
+// NewSuperAdminRouter creates a new super admin router
+func NewSuperAdminRouter(handler *handlers.SuperAdminHandler, calibrationHandler *handlers.CalibrationHandler) *SuperAdminRouter {
+	return &SuperAdminRouter{handler: handler, calibrationHandler: calibrationHandler}
+}
+
+// Name returns the router name
+func (r *SuperAdminRouter) Name() string { return "superadmin" }
+
+// Middlewares requires the super admin token on all routes
+func (r *SuperAdminRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.SuperAdminMiddleware()}
+}
+
+// Register registers /superadmin routes
+func (r *SuperAdminRouter) Register(group *gin.RouterGroup) {
+	superadmin := group.Group("/superadmin")
+	superadmin.GET("/churn-risk-aggregate", r.handler.ChurnRiskAggregate)
+	superadmin.GET("/win-rate-aggregate", r.handler.WinRateAggregate)
+	superadmin.GET("/usage", r.handler.Usage)
+	superadmin.POST("/calibrate-model", r.calibrationHandler.CalibrateModel)
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// TagRouter registers tag routes and the routes tagging conversations
+type TagRouter struct {
+	handler *handlers.TagHandler
+}
+
+// NewTagRouter creates a new tag router
+func NewTagRouter(handler *handlers.TagHandler) *TagRouter {
+	return &TagRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *TagRouter) Name() string { return "tags" }
+
+// Middlewares returns no router-wide middlewares
+func (r *TagRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers /tags and /conversations/:id/tags routes
+func (r *TagRouter) Register(group *gin.RouterGroup) {
+	group.GET("/tags", r.handler.ListTags)
+	group.POST("/tags", r.handler.CreateTag)
+	group.PUT("/tags/:id", r.handler.UpdateTag)
+	group.DELETE("/tags/:id", r.handler.DeleteTag)
+	group.POST("/conversations/:id/tags", r.handler.TagConversation)
+	group.DELETE("/conversations/:id/tags/:tag_id", r.handler.UntagConversation)
+}
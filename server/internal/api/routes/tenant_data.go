@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// TenantDataRouter registers tenant data erasure routes (super admin only)
+type TenantDataRouter struct {
+	handler *handlers.TenantDataHandler
+}
+
+// NewTenantDataRouter creates a new tenant data router
+func NewTenantDataRouter(handler *handlers.TenantDataHandler) *TenantDataRouter {
+	return &TenantDataRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *TenantDataRouter) Name() string { return "tenant_data" }
+
+// Middlewares restricts all tenant data routes to super admins; tenant admins are rejected
+func (r *TenantDataRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.SuperAdminRoleMiddleware()}
+}
+
+// Register registers /admin/tenants routes
+func (r *TenantDataRouter) Register(group *gin.RouterGroup) {
+	group.DELETE("/admin/tenants/:tenant_id/data", r.handler.DeleteTenantData)
+}
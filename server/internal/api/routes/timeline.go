@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// TimelineRouter registers the conversation timeline route
+type TimelineRouter struct {
+	handler *handlers.TimelineHandler
+}
+
+// NewTimelineRouter creates a new timeline router
+func NewTimelineRouter(handler *handlers.TimelineHandler) *TimelineRouter {
+	return &TimelineRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *TimelineRouter) Name() string { return "timeline" }
+
+// Middlewares returns no router-wide middlewares
+func (r *TimelineRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers timeline routes
+func (r *TimelineRouter) Register(group *gin.RouterGroup) {
+	group.GET("/conversations/:id/timeline", r.handler.GetTimeline)
+}
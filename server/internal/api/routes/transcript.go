@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+)
+
+// TranscriptRouter registers conversation transcript email routes
+type TranscriptRouter struct {
+	handler *handlers.TranscriptHandler
+}
+
+// NewTranscriptRouter creates a new transcript router
+func NewTranscriptRouter(handler *handlers.TranscriptHandler) *TranscriptRouter {
+	return &TranscriptRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *TranscriptRouter) Name() string { return "transcripts" }
+
+// Middlewares returns no router-wide middlewares
+func (r *TranscriptRouter) Middlewares() []gin.HandlerFunc { return nil }
+
+// Register registers transcript routes
+func (r *TranscriptRouter) Register(group *gin.RouterGroup) {
+	group.POST("/conversations/:id/send-transcript", r.handler.SendTranscript)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/api/handlers"
+	"ai-conversation-platform/internal/middleware"
+)
+
+// WebhookRouter registers webhook routes (admin only)
+type WebhookRouter struct {
+	handler *handlers.WebhookHandler
+}
+
+// NewWebhookRouter creates a new webhook router
+func NewWebhookRouter(handler *handlers.WebhookHandler) *WebhookRouter {
+	return &WebhookRouter{handler: handler}
+}
+
+// Name returns the router name
+func (r *WebhookRouter) Name() string { return "webhooks" }
+
+// Middlewares restricts all webhook routes to admins
+func (r *WebhookRouter) Middlewares() []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.AdminMiddleware()}
+}
+
+// Register registers /webhooks routes
+func (r *WebhookRouter) Register(group *gin.RouterGroup) {
+	webhooks := group.Group("/webhooks")
+	webhooks.GET("", r.handler.ListWebhooks)
+	webhooks.POST("", r.handler.CreateWebhook)
+	webhooks.GET("/:id", r.handler.GetWebhook)
+	webhooks.PUT("/:id", r.handler.UpdateWebhook)
+	webhooks.DELETE("/:id", r.handler.DeleteWebhook)
+}
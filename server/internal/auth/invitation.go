@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// invitationTTL is how long an invitation can be accepted
+const invitationTTL = 7 * 24 * time.Hour
+
+// tokenTypeInvite marks invitation tokens so they can't be used as access or refresh tokens
+const tokenTypeInvite = "invite"
+
+// Invitation statuses
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked" // Superseded by a newer invitation to the same email
+)
+
+var (
+	// ErrInvalidInvitation is returned for invitation tokens that are malformed, unknown or revoked
+	ErrInvalidInvitation = errors.New("invalid invitation")
+	// ErrInvitationExpired is returned for invitations older than seven days
+	ErrInvitationExpired = errors.New("invitation has expired")
+	// ErrInvitationUsed is returned when an invitation is accepted a second time
+	ErrInvitationUsed = errors.New("invitation has already been used")
+	// ErrInvitationRole is returned when inviting a role invitations can't grant
+	ErrInvitationRole = errors.New("invitations can only grant the agent or admin role")
+)
+
+// InvitationRecord is a stored invitation. Only a hash of the token itself is kept.
+type InvitationRecord struct {
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  string     `json:"invited_by"`
+	TokenHash  string     `json:"-"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+// InvitationStore persists invitations
+type InvitationStore interface {
+	// CreateInvitation stores an invitation, revoking pending invitations to the same email
+	CreateInvitation(record *InvitationRecord) error
+	// GetInvitation returns nil when no invitation has the ID
+	GetInvitation(id string) (*InvitationRecord, error)
+	// AcceptInvitation creates the user and marks the invitation accepted in one transaction,
+	// reporting false when the invitation was no longer pending
+	AcceptInvitation(id string, user *models.User) (bool, error)
+}
+
+// IssueInvitation creates an invitation for email to join the tenant with role and returns the
+// token to send the invitee. Only the agent and admin roles can be granted.
+func IssueInvitation(store InvitationStore, tenantID, email, role, invitedBy string) (string, *InvitationRecord, error) {
+	if models.UserRole(role) != models.RoleAgent && models.UserRole(role) != models.RoleAdmin {
+		return "", nil, ErrInvitationRole
+	}
+
+	token, err := signToken("", tenantID, role, tokenTypeInvite, invitationTTL)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	claims, err := parseToken(token)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse invitation token: %w", err)
+	}
+	record := &InvitationRecord{
+		ID:        claims.ID,
+		TenantID:  tenantID,
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Role:      role,
+		InvitedBy: invitedBy,
+		TokenHash: hashToken(token),
+		Status:    InvitationPending,
+		ExpiresAt: claims.ExpiresAt.Time,
+		CreatedAt: claims.IssuedAt.Time,
+	}
+	if err := store.CreateInvitation(record); err != nil {
+		return "", nil, err
+	}
+	return token, record, nil
+}
+
+// AcceptInvitation creates the invited user with password and marks the invitation used, so the
+// token can't be accepted again
+func AcceptInvitation(store InvitationStore, token, password string) (*models.User, error) {
+	claims, err := parseToken(token)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrInvitationExpired
+	}
+	if err != nil || claims.TokenType != tokenTypeInvite || claims.ID == "" {
+		return nil, ErrInvalidInvitation
+	}
+
+	record, err := store.GetInvitation(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.TenantID != claims.TenantID || record.Status == InvitationRevoked ||
+		subtle.ConstantTimeCompare([]byte(record.TokenHash), []byte(hashToken(token))) != 1 {
+		return nil, ErrInvalidInvitation
+	}
+	if record.Status == InvitationAccepted {
+		return nil, ErrInvitationUsed
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	now := time.Now()
+	user := &models.User{
+		ID:           uuid.New().String(),
+		TenantID:     record.TenantID,
+		Email:        record.Email,
+		PasswordHash: passwordHash,
+		Role:         models.UserRole(record.Role),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	accepted, err := store.AcceptInvitation(record.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		// Another request accepted it first
+		return nil, ErrInvitationUsed
+	}
+	return user, nil
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// memoryInvitationStore is an in-memory InvitationStore
+type memoryInvitationStore struct {
+	mu          sync.Mutex
+	invitations map[string]*InvitationRecord
+	users       []*models.User
+}
+
+func newMemoryInvitationStore() *memoryInvitationStore {
+	return &memoryInvitationStore{invitations: make(map[string]*InvitationRecord)}
+}
+
+func (s *memoryInvitationStore) CreateInvitation(record *InvitationRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.invitations {
+		if existing.TenantID == record.TenantID && existing.Email == record.Email && existing.Status == InvitationPending {
+			existing.Status = InvitationRevoked
+		}
+	}
+	copied := *record
+	s.invitations[record.ID] = &copied
+	return nil
+}
+
+func (s *memoryInvitationStore) GetInvitation(id string) (*InvitationRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.invitations[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (s *memoryInvitationStore) AcceptInvitation(id string, user *models.User) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.invitations[id]
+	if !ok || record.Status != InvitationPending {
+		return false, nil
+	}
+	record.Status = InvitationAccepted
+	s.users = append(s.users, user)
+	return true, nil
+}
+
+func TestAcceptInvitationCreatesUser(t *testing.T) {
+	store := newMemoryInvitationStore()
+	token, record, err := IssueInvitation(store, "tenant-1", " New.Agent@Example.com ", "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	if record.Email != "new.agent@example.com" || record.Status != InvitationPending || record.TokenHash != hashToken(token) {
+		t.Errorf("record = %+v, want a pending invitation storing the token hash", record)
+	}
+	if record.ExpiresAt.Before(time.Now().Add(6*24*time.Hour)) || record.ExpiresAt.After(time.Now().Add(7*24*time.Hour+time.Minute)) {
+		t.Errorf("expires_at = %v, want seven days from now", record.ExpiresAt)
+	}
+	if _, err := ValidateToken(token); err == nil {
+		t.Error("an invitation token must not be accepted as an access token")
+	}
+
+	user, err := AcceptInvitation(store, token, "s3cret-password")
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if user.TenantID != "tenant-1" || user.Email != "new.agent@example.com" || user.Role != models.RoleAgent {
+		t.Errorf("user = %+v, want the invited agent", user)
+	}
+	if !CheckPassword("s3cret-password", user.PasswordHash) {
+		t.Error("user password should be stored as a bcrypt hash")
+	}
+}
+
+func TestAcceptInvitationRejectsReplay(t *testing.T) {
+	store := newMemoryInvitationStore()
+	token, _, err := IssueInvitation(store, "tenant-1", "agent@example.com", "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	if _, err := AcceptInvitation(store, token, "password-1"); err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if _, err := AcceptInvitation(store, token, "password-2"); !errors.Is(err, ErrInvitationUsed) {
+		t.Errorf("replayed AcceptInvitation = %v, want ErrInvitationUsed", err)
+	}
+	if len(store.users) != 1 {
+		t.Errorf("created %d users, want 1", len(store.users))
+	}
+}
+
+func TestAcceptInvitationRejectsExpired(t *testing.T) {
+	store := newMemoryInvitationStore()
+	token, record, err := IssueInvitation(store, "tenant-1", "agent@example.com", "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	store.invitations[record.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := AcceptInvitation(store, token, "password"); !errors.Is(err, ErrInvitationExpired) {
+		t.Errorf("AcceptInvitation after stored expiry = %v, want ErrInvitationExpired", err)
+	}
+
+	// A token signed with an expiry in the past is rejected before the store is consulted
+	expired, err := signToken("", "tenant-1", "agent", tokenTypeInvite, -time.Minute)
+	if err != nil {
+		t.Fatalf("signToken: %v", err)
+	}
+	if _, err := AcceptInvitation(store, expired, "password"); !errors.Is(err, ErrInvitationExpired) {
+		t.Errorf("AcceptInvitation(expired token) = %v, want ErrInvitationExpired", err)
+	}
+	if len(store.users) != 0 {
+		t.Errorf("created %d users, want none", len(store.users))
+	}
+}
+
+func TestAcceptInvitationRejectsInvalidTokens(t *testing.T) {
+	store := newMemoryInvitationStore()
+	first, _, err := IssueInvitation(store, "tenant-1", "agent@example.com", "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	// Re-inviting the same email revokes the earlier invitation
+	if _, _, err := IssueInvitation(store, "tenant-1", "agent@example.com", "admin", "admin-1"); err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	access, err := GenerateToken("user-1", "tenant-1", "admin")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"garbage":            "not-a-token",
+		"access token":       access,
+		"revoked invitation": first,
+	} {
+		if _, err := AcceptInvitation(store, token, "password"); !errors.Is(err, ErrInvalidInvitation) {
+			t.Errorf("%s: AcceptInvitation = %v, want ErrInvalidInvitation", name, err)
+		}
+	}
+	unknown, _, err := IssueInvitation(newMemoryInvitationStore(), "tenant-1", "other@example.com", "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	if _, err := AcceptInvitation(store, unknown, "password"); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("AcceptInvitation(unknown invitation) = %v, want ErrInvalidInvitation", err)
+	}
+}
+
+func TestIssueInvitationRoleEnforcement(t *testing.T) {
+	store := newMemoryInvitationStore()
+	for _, role := range []string{"super_admin", "customer", "owner", ""} {
+		if _, _, err := IssueInvitation(store, "tenant-1", "x@example.com", role, "admin-1"); !errors.Is(err, ErrInvitationRole) {
+			t.Errorf("IssueInvitation(%q) = %v, want ErrInvitationRole", role, err)
+		}
+	}
+	if len(store.invitations) != 0 {
+		t.Errorf("stored %d invitations, want none", len(store.invitations))
+	}
+	for _, role := range []string{"agent", "admin"} {
+		if _, _, err := IssueInvitation(store, "tenant-1", role+"@example.com", role, "admin-1"); err != nil {
+			t.Errorf("IssueInvitation(%q) = %v, want success", role, err)
+		}
+	}
+}
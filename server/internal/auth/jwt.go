@@ -2,11 +2,11 @@ package auth
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,25 +20,38 @@ func getJWTSecret() string {
 	return secret
 }
 
-// Claims represents JWT claims
+// accessTokenTTL is how long an access token is valid
+const accessTokenTTL = 24 * time.Hour
+
+// tokenTypeRefresh marks refresh tokens so they can't be used as access tokens
+const tokenTypeRefresh = "refresh"
+
+// Claims represents JWT claims. ID (jti) identifies the token for revocation.
 type Claims struct {
-	UserID   string `json:"user_id"`
-	TenantID string `json:"tenant_id"`
-	Role     string `json:"role"`
+	UserID    string `json:"user_id"`
+	TenantID  string `json:"tenant_id"`
+	Role      string `json:"role"`
+	TokenType string `json:"token_type,omitempty"` // "refresh" for refresh tokens, empty for access tokens
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates a JWT token for a user
 func GenerateToken(userID, tenantID, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	return signToken(userID, tenantID, role, "", accessTokenTTL)
+}
 
+// signToken signs a token of tokenType with a fresh jti
+func signToken(userID, tenantID, role, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID:   userID,
-		TenantID: tenantID,
-		Role:     role,
+		UserID:    userID,
+		TenantID:  tenantID,
+		Role:      role,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -46,8 +59,20 @@ func GenerateToken(userID, tenantID, role string) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
-// ValidateToken validates a JWT token and returns claims
+// ValidateToken validates an access token and returns claims. Refresh tokens are rejected.
 func ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != "" {
+		return nil, errors.New("invalid token type")
+	}
+	return claims, nil
+}
+
+// parseToken verifies a token's signature and expiry and returns its claims
+func parseToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -79,40 +104,3 @@ func CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
-
-// RefreshToken refreshes an expired or soon-to-expire token
-// It accepts tokens that are expired but within the refresh window (7 days)
-func RefreshToken(tokenString string) (string, error) {
-	claims := &Claims{}
-
-	// Parse token without validating expiration
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return jwtSecret, nil
-	}, jwt.WithoutClaimsValidation())
-
-	if err != nil {
-		return "", fmt.Errorf("failed to parse token: %w", err)
-	}
-
-	if !token.Valid {
-		return "", errors.New("invalid token")
-	}
-
-	// Check if token is within refresh window (7 days from expiration)
-	if claims.ExpiresAt != nil {
-		expirationTime := claims.ExpiresAt.Time
-		refreshWindow := 7 * 24 * time.Hour
-		maxRefreshTime := expirationTime.Add(refreshWindow)
-
-		if time.Now().After(maxRefreshTime) {
-			return "", errors.New("token is too old to refresh")
-		}
-	}
-
-	// Generate new token with same claims but new expiration
-	return GenerateToken(claims.UserID, claims.TenantID, claims.Role)
-}
-
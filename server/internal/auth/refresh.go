@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// refreshTokenTTL is how long a refresh token can be used to get new access tokens
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	// ErrInvalidRefreshToken is returned for refresh tokens that are malformed, expired, unknown or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented again. All of the
+	// user's refresh tokens are revoked, since the token has likely been stolen.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// RefreshTokenRecord is a stored refresh token. Only a hash of the token itself is kept.
+type RefreshTokenRecord struct {
+	JTI       string
+	UserID    string
+	TenantID  string
+	TokenHash string
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+// RefreshTokenStore persists refresh tokens
+type RefreshTokenStore interface {
+	CreateRefreshToken(record *RefreshTokenRecord) error
+	// GetRefreshToken returns nil when no token has the jti
+	GetRefreshToken(jti string) (*RefreshTokenRecord, error)
+	// RevokeRefreshToken reports whether the token was still active, so only one caller can rotate it
+	RevokeRefreshToken(jti string) (bool, error)
+	RevokeUserRefreshTokens(tenantID, userID string) error
+}
+
+// UserLookup loads a user's current role and status (see postgres.UserStorage)
+type UserLookup interface {
+	GetUser(tenantID, userID string) (*models.User, error)
+}
+
+// TokenPair is a new access token with the refresh token used to renew it
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// IssueTokens generates an access token and a stored refresh token for a user
+func IssueTokens(store RefreshTokenStore, userID, tenantID, role string) (*TokenPair, error) {
+	accessToken, err := GenerateToken(userID, tenantID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := signToken(userID, tenantID, role, tokenTypeRefresh, refreshTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	claims, err := parseToken(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refresh token: %w", err)
+	}
+	record := &RefreshTokenRecord{
+		JTI:       claims.ID,
+		UserID:    userID,
+		TenantID:  tenantID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := store.CreateRefreshToken(record); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token. The refresh token is rotated:
+// it is revoked and a new one is returned with the access token. The new tokens carry the user's
+// current role; deactivated or deleted users can't refresh.
+func RefreshToken(store RefreshTokenStore, users UserLookup, refreshToken string) (*TokenPair, error) {
+	claims, record, err := lookupRefreshToken(store, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if record.Revoked {
+		return nil, revokeReusedFamily(store, claims)
+	}
+
+	user, err := users.GetUser(claims.TenantID, claims.UserID)
+	if err != nil || user.DeactivatedAt != nil {
+		log.Printf("[AUTH] refresh rejected for inactive user=%s tenant=%s", claims.UserID, claims.TenantID)
+		if err := store.RevokeUserRefreshTokens(claims.TenantID, claims.UserID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	rotated, err := store.RevokeRefreshToken(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request rotated the token first
+		return nil, revokeReusedFamily(store, claims)
+	}
+
+	return IssueTokens(store, user.ID, user.TenantID, string(user.Role))
+}
+
+// RevokeRefreshToken revokes a refresh token (logout) and returns its claims
+func RevokeRefreshToken(store RefreshTokenStore, refreshToken string) (*Claims, error) {
+	claims, _, err := lookupRefreshToken(store, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := store.RevokeRefreshToken(claims.ID); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// lookupRefreshToken verifies a refresh token and loads its stored record
+func lookupRefreshToken(store RefreshTokenStore, refreshToken string) (*Claims, *RefreshTokenRecord, error) {
+	claims, err := parseToken(refreshToken)
+	if err != nil || claims.TokenType != tokenTypeRefresh || claims.ID == "" {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	record, err := store.GetRefreshToken(claims.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record == nil || record.UserID != claims.UserID || record.TenantID != claims.TenantID ||
+		subtle.ConstantTimeCompare([]byte(record.TokenHash), []byte(hashToken(refreshToken))) != 1 {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	return claims, record, nil
+}
+
+// revokeReusedFamily revokes every refresh token of a user whose rotated token was replayed
+func revokeReusedFamily(store RefreshTokenStore, claims *Claims) error {
+	log.Printf("[AUTH] WARN refresh token reuse detected, revoking all refresh tokens user=%s tenant=%s jti=%s",
+		claims.UserID, claims.TenantID, claims.ID)
+	if err := store.RevokeUserRefreshTokens(claims.TenantID, claims.UserID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// hashToken returns the hex SHA-256 of a token, the form refresh tokens are stored in
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
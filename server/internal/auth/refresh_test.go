@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// memoryStore is an in-memory RefreshTokenStore
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*RefreshTokenRecord
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]*RefreshTokenRecord)}
+}
+
+func (s *memoryStore) CreateRefreshToken(record *RefreshTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *record
+	s.records[record.JTI] = &copied
+	return nil
+}
+
+func (s *memoryStore) GetRefreshToken(jti string) (*RefreshTokenRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[jti]
+	if !ok {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (s *memoryStore) RevokeRefreshToken(jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[jti]
+	if !ok || record.Revoked {
+		return false, nil
+	}
+	record.Revoked = true
+	return true, nil
+}
+
+func (s *memoryStore) RevokeUserRefreshTokens(tenantID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range s.records {
+		if record.TenantID == tenantID && record.UserID == userID {
+			record.Revoked = true
+		}
+	}
+	return nil
+}
+
+// memoryUsers is an in-memory UserLookup keyed by user ID
+type memoryUsers map[string]*models.User
+
+func (u memoryUsers) GetUser(tenantID, userID string) (*models.User, error) {
+	user, ok := u[userID]
+	if !ok || user.TenantID != tenantID {
+		return nil, errors.New("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// agentUsers has user-1 and user-2 as active agents of tenant-1
+func agentUsers() memoryUsers {
+	return memoryUsers{
+		"user-1": {ID: "user-1", TenantID: "tenant-1", Role: models.RoleAgent},
+		"user-2": {ID: "user-2", TenantID: "tenant-1", Role: models.RoleAgent},
+	}
+}
+
+func TestIssueTokensStoresHashOnly(t *testing.T) {
+	store := newMemoryStore()
+	tokens, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	if len(store.records) != 1 {
+		t.Fatalf("stored %d refresh tokens, want 1", len(store.records))
+	}
+	for _, record := range store.records {
+		if record.TokenHash == tokens.RefreshToken || record.TokenHash != hashToken(tokens.RefreshToken) {
+			t.Error("refresh token should be stored as its SHA-256 hash")
+		}
+		if record.UserID != "user-1" || record.TenantID != "tenant-1" || record.ExpiresAt.Before(time.Now().Add(29*24*time.Hour)) {
+			t.Errorf("stored record = %+v", record)
+		}
+	}
+
+	claims, err := ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken(access): %v", err)
+	}
+	if claims.ID == "" || claims.Role != "agent" {
+		t.Errorf("access claims = %+v, want a jti and the agent role", claims)
+	}
+	if _, err := ValidateToken(tokens.RefreshToken); err == nil {
+		t.Error("a refresh token must not be accepted as an access token")
+	}
+	if _, err := RefreshToken(store, agentUsers(), tokens.AccessToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken(access token) = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	store := newMemoryStore()
+	first, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	second, err := RefreshToken(store, agentUsers(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Fatal("refresh should return a new access token and a new refresh token")
+	}
+	claims, err := ValidateToken(second.AccessToken)
+	if err != nil || claims.UserID != "user-1" || claims.TenantID != "tenant-1" || claims.Role != "agent" {
+		t.Fatalf("refreshed access token claims = %+v, err = %v", claims, err)
+	}
+
+	// The rotated token keeps working until it is itself rotated
+	third, err := RefreshToken(store, agentUsers(), second.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken(rotated): %v", err)
+	}
+	if third.RefreshToken == second.RefreshToken {
+		t.Error("each refresh should rotate the refresh token")
+	}
+}
+
+func TestRefreshTokenReplayRevokesFamily(t *testing.T) {
+	store := newMemoryStore()
+	stolen, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	other, err := IssueTokens(store, "user-2", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	legit, err := RefreshToken(store, agentUsers(), stolen.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	// Replaying the rotated token is detected and revokes the user's current token too
+	if _, err := RefreshToken(store, agentUsers(), stolen.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replayed RefreshToken = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := RefreshToken(store, agentUsers(), legit.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("RefreshToken after replay = %v, want the family revoked", err)
+	}
+
+	// Other users are unaffected
+	if _, err := RefreshToken(store, agentUsers(), other.RefreshToken); err != nil {
+		t.Errorf("other user's RefreshToken = %v, want success", err)
+	}
+}
+
+func TestRefreshTokenRejectsUnknownAndExpired(t *testing.T) {
+	store := newMemoryStore()
+	tokens, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	if _, err := RefreshToken(newMemoryStore(), agentUsers(), tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken with unknown jti = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := RefreshToken(store, agentUsers(), "not-a-token"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken(garbage) = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	for _, record := range store.records {
+		record.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+	if _, err := RefreshToken(store, agentUsers(), tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken after stored expiry = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRevokeRefreshTokenLogout(t *testing.T) {
+	store := newMemoryStore()
+	tokens, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	claims, err := RevokeRefreshToken(store, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RevokeRefreshToken: %v", err)
+	}
+	if claims.UserID != "user-1" {
+		t.Errorf("claims user = %s, want user-1", claims.UserID)
+	}
+	if _, err := RefreshToken(store, agentUsers(), tokens.RefreshToken); err == nil {
+		t.Error("a logged-out refresh token must not refresh")
+	}
+}
+
+func TestRevocationCache(t *testing.T) {
+	cache := NewRevocationCache()
+	store := newMemoryStore()
+	tokens, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	claims, err := ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	if cache.IsRevoked(claims.ID) {
+		t.Fatal("token revoked before logout")
+	}
+	cache.RevokeClaims(claims)
+	if !cache.IsRevoked(claims.ID) {
+		t.Error("token should be revoked after logout")
+	}
+
+	// Entries lapse with the access token and are pruned on the next revocation
+	cache.Revoke("expired-jti", time.Now().Add(-time.Second))
+	if cache.IsRevoked("expired-jti") {
+		t.Error("expired entries should not count as revoked")
+	}
+	cache.Revoke("another-jti", time.Now().Add(time.Minute))
+	if _, ok := cache.revoked["expired-jti"]; ok {
+		t.Error("expired entries should be pruned")
+	}
+	if cache.IsRevoked("") {
+		t.Error("tokens without a jti can't be revoked")
+	}
+}
+
+func TestRefreshTokenUsesCurrentUser(t *testing.T) {
+	store := newMemoryStore()
+	users := agentUsers()
+	users["user-1"].Role = models.RoleAdmin
+	tokens, err := IssueTokens(store, "user-1", "tenant-1", "admin")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	// Demoted since the refresh token was issued
+	users["user-1"].Role = models.RoleAgent
+	refreshed, err := RefreshToken(store, users, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	claims, err := ValidateToken(refreshed.AccessToken)
+	if err != nil || claims.Role != "agent" {
+		t.Fatalf("refreshed claims = %+v (%v), want the current agent role", claims, err)
+	}
+
+	// Deactivated users can't refresh, and their other refresh tokens are revoked
+	other, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	deactivatedAt := time.Now()
+	users["user-1"].DeactivatedAt = &deactivatedAt
+	if _, err := RefreshToken(store, users, refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken for deactivated user = %v, want ErrInvalidRefreshToken", err)
+	}
+	users["user-1"].DeactivatedAt = nil
+	if _, err := RefreshToken(store, users, other.RefreshToken); err == nil {
+		t.Error("refresh tokens of a deactivated user should have been revoked")
+	}
+
+	delete(users, "user-1")
+	fresh, err := IssueTokens(store, "user-1", "tenant-1", "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	if _, err := RefreshToken(store, users, fresh.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken for deleted user = %v, want ErrInvalidRefreshToken", err)
+	}
+}
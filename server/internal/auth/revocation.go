@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationCache remembers the jti of logged-out access tokens until they expire. It is in-memory,
+// so revocations are per instance and lost on restart; access tokens are never valid for longer
+// than accessTokenTTL either way.
+type RevocationCache struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time // jti -> access token expiry
+}
+
+// NewRevocationCache creates an empty revocation cache
+func NewRevocationCache() *RevocationCache {
+	return &RevocationCache{revoked: make(map[string]time.Time)}
+}
+
+// Revoke rejects the access token with jti until expiresAt
+func (c *RevocationCache) Revoke(jti string, expiresAt time.Time) {
+	if jti == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for id, expiry := range c.revoked {
+		if now.After(expiry) {
+			delete(c.revoked, id)
+		}
+	}
+	c.revoked[jti] = expiresAt
+}
+
+// IsRevoked reports whether the access token with jti was revoked and hasn't expired yet
+func (c *RevocationCache) IsRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiry, ok := c.revoked[jti]
+	return ok && time.Now().Before(expiry)
+}
+
+// RevokeClaims revokes the access token the claims were parsed from
+func (c *RevocationCache) RevokeClaims(claims *Claims) {
+	if claims == nil || claims.ExpiresAt == nil {
+		return
+	}
+	c.Revoke(claims.ID, claims.ExpiresAt.Time)
+}
@@ -0,0 +1,59 @@
+// Package cache provides the shared response cache: Redis when REDIS_URL is set, otherwise an
+// in-memory LRU per process.
+package cache
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+)
+
+// DefaultMaxEntries caps the in-memory cache used when Redis isn't configured
+const DefaultMaxEntries = 10000
+
+// Cache stores byte values under string keys until their TTL expires. Implementations never
+// fail: an unreachable backend behaves like an empty cache, so callers fall back to the database.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// FromEnv returns a Redis cache when REDIS_URL is set, otherwise an in-memory LRU cache. An
+// invalid REDIS_URL is logged and the in-memory cache used instead.
+func FromEnv() Cache {
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		redisCache, err := NewRedisCache(url)
+		if err == nil {
+			log.Printf("[Cache] using redis")
+			return redisCache
+		}
+		log.Printf("[Cache] invalid REDIS_URL, using in-memory cache: %v", err)
+	}
+	return NewMemoryCache(DefaultMaxEntries)
+}
+
+// Fetch returns the JSON value cached under key, or calls load and caches its result for ttl.
+// A nil cache, a miss or an undecodable value all call load; load's errors aren't cached.
+func Fetch[T any](c Cache, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	if data, ok := c.Get(key); ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		log.Printf("[Cache] failed to decode cached value key=%s, reloading", key)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		c.Set(key, data, ttl)
+	}
+	return value, nil
+}
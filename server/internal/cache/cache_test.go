@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for TTL tests
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestMemoryCache(maxEntries int) (*MemoryCache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)}
+	c := NewMemoryCache(maxEntries)
+	c.now = clock.Now
+	return c, clock
+}
+
+func TestMemoryCacheHitAndMiss(t *testing.T) {
+	c, _ := newTestMemoryCache(10)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get of an unset key hit")
+	}
+	c.Set("key", []byte("value"), time.Minute)
+	if got, ok := c.Get("key"); !ok || string(got) != "value" {
+		t.Fatalf("Get = %q, %v; want value", got, ok)
+	}
+
+	c.Set("key", []byte("updated"), time.Minute)
+	if got, ok := c.Get("key"); !ok || string(got) != "updated" {
+		t.Errorf("Get after overwrite = %q, %v; want updated", got, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want 1", c.Len())
+	}
+}
+
+func TestMemoryCacheExpiresAfterTTL(t *testing.T) {
+	c, clock := newTestMemoryCache(10)
+	c.Set("key", []byte("value"), time.Minute)
+
+	clock.now = clock.now.Add(time.Minute - time.Second)
+	if _, ok := c.Get("key"); !ok {
+		t.Fatal("value expired before its TTL")
+	}
+	clock.now = clock.now.Add(time.Second)
+	if _, ok := c.Get("key"); ok {
+		t.Fatal("value still cached at its TTL")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len = %d, want the expired value evicted", c.Len())
+	}
+
+	c.Set("zero", []byte("value"), 0)
+	if _, ok := c.Get("zero"); ok {
+		t.Error("value with a zero TTL was cached")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestMemoryCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used key b wasn't evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("key %s was evicted", key)
+		}
+	}
+}
+
+type report struct {
+	Total int `json:"total"`
+}
+
+func TestFetchLoadsOnMissAndCaches(t *testing.T) {
+	c, clock := newTestMemoryCache(10)
+	loads := 0
+	load := func() (report, error) {
+		loads++
+		return report{Total: loads}, nil
+	}
+
+	first, err := Fetch(c, "report", time.Minute, load)
+	if err != nil || first.Total != 1 {
+		t.Fatalf("first Fetch = %+v, %v; want loaded report", first, err)
+	}
+	second, err := Fetch(c, "report", time.Minute, load)
+	if err != nil || second.Total != 1 || loads != 1 {
+		t.Fatalf("second Fetch = %+v, %v after %d loads; want the cached report", second, err, loads)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if third, _ := Fetch(c, "report", time.Minute, load); third.Total != 2 {
+		t.Errorf("Fetch after TTL = %+v, want a reload", third)
+	}
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	c, _ := newTestMemoryCache(10)
+	if _, err := Fetch(c, "report", time.Minute, func() (report, error) {
+		return report{}, errors.New("database down")
+	}); err == nil {
+		t.Fatal("Fetch swallowed the load error")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len = %d, want the failed load not cached", c.Len())
+	}
+}
+
+func TestFetchReloadsUndecodableValue(t *testing.T) {
+	c, _ := newTestMemoryCache(10)
+	c.Set("report", []byte("not json"), time.Minute)
+
+	got, err := Fetch(c, "report", time.Minute, func() (report, error) { return report{Total: 7}, nil })
+	if err != nil || got.Total != 7 {
+		t.Errorf("Fetch = %+v, %v; want the reloaded report", got, err)
+	}
+}
+
+func TestFetchWithoutCacheLoads(t *testing.T) {
+	got, err := Fetch(nil, "report", time.Minute, func() (report, error) { return report{Total: 3}, nil })
+	if err != nil || got.Total != 3 {
+		t.Errorf("Fetch = %+v, %v; want the loaded report", got, err)
+	}
+}
+
+func TestFetchFallsBackWhenRedisUnavailable(t *testing.T) {
+	// Nothing listens on port 1, so every Redis call fails
+	c, err := NewRedisCache("redis://127.0.0.1:1/0")
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	defer c.Close()
+
+	if _, ok := c.Get("key"); ok {
+		t.Fatal("Get hit on an unreachable server")
+	}
+	c.Set("key", []byte("value"), time.Minute)
+
+	loads := 0
+	for i := 0; i < 2; i++ {
+		got, err := Fetch[report](c, "report", time.Minute, func() (report, error) {
+			loads++
+			return report{Total: 5}, nil
+		})
+		if err != nil || got.Total != 5 {
+			t.Fatalf("Fetch = %+v, %v; want the database result", got, err)
+		}
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want every call served by the database", loads)
+	}
+}
+
+func TestNewRedisCacheRejectsInvalidURL(t *testing.T) {
+	if _, err := NewRedisCache("http://localhost:6379"); err == nil {
+		t.Error("NewRedisCache accepted a non-redis URL")
+	}
+}
+
+func TestFromEnvFallsBackToMemory(t *testing.T) {
+	t.Setenv("REDIS_URL", "")
+	if _, ok := FromEnv().(*MemoryCache); !ok {
+		t.Error("FromEnv without REDIS_URL didn't return the in-memory cache")
+	}
+
+	t.Setenv("REDIS_URL", "not a url")
+	if _, ok := FromEnv().(*MemoryCache); !ok {
+		t.Error("FromEnv with an invalid REDIS_URL didn't fall back to the in-memory cache")
+	}
+
+	t.Setenv("REDIS_URL", fmt.Sprintf("redis://127.0.0.1:%d/0", 6379))
+	c, ok := FromEnv().(*RedisCache)
+	if !ok {
+		t.Fatal("FromEnv with REDIS_URL didn't return the redis cache")
+	}
+	c.Close()
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds each Redis call, so an unreachable server costs a request at most this
+// long before it falls back to the database
+const redisTimeout = 250 * time.Millisecond
+
+// RedisCache is a cache shared by every API instance
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates a Redis cache from a redis:// or rediss:// URL. The server isn't
+// contacted until the first call.
+func NewRedisCache(url string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
+	}
+	opts.DialTimeout = redisTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	opts.MaxRetries = 1
+	return &RedisCache{client: redis.NewClient(opts)}, nil
+}
+
+// Get returns the value cached under key. Redis errors are logged and reported as a miss.
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("[Cache] redis get failed key=%s: %v", key, err)
+		return nil, false
+	}
+	return value, true
+}
+
+// Set caches value under key for ttl. Redis errors are logged; a non-positive ttl isn't cached.
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		log.Printf("[Cache] redis set failed key=%s: %v", key, err)
+	}
+}
+
+// HealthCheckContext pings Redis
+func (c *RedisCache) HealthCheckContext(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close closes the Redis connections
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// IsValidFormat checks if a conversation export format is supported
+func IsValidFormat(format string) bool {
+	return format == FormatJSON || format == FormatCSV
+}
+
+// csvHeader lists the columns of a conversation CSV export
+var csvHeader = []string{"timestamp", "sender", "content", "channel", "language"}
+
+// ConversationExporter writes a conversation and its messages for compliance exports.
+// Message content is written verbatim; timestamps are RFC3339 in UTC.
+type ConversationExporter struct{}
+
+// NewConversationExporter creates a conversation exporter
+func NewConversationExporter() *ConversationExporter {
+	return &ConversationExporter{}
+}
+
+// ConversationHeader summarizes the conversation at the top of a JSON export
+type ConversationHeader struct {
+	ConversationID string           `json:"conversation_id"`
+	Status         string           `json:"status"`
+	CustomerID     *string          `json:"customer_id"`
+	ProductID      *string          `json:"product_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	MessageCount   int              `json:"message_count"`
+	Metadata       *MetadataSummary `json:"metadata"` // null until the conversation has been analyzed
+}
+
+// MetadataSummary is the analysis result included in a JSON export
+type MetadataSummary struct {
+	Intent          string   `json:"intent"`
+	IntentScore     float64  `json:"intent_score"`
+	Sentiment       string   `json:"sentiment"`
+	SentimentScore  float64  `json:"sentiment_score"`
+	Emotions        []string `json:"emotions"`
+	Objections      []string `json:"objections"`
+	ComplexityScore float64  `json:"complexity_score"`
+}
+
+// ExportedMessage is a message as written to a JSON export
+type ExportedMessage struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Sender    string    `json:"sender"`
+	Content   string    `json:"content"`
+	Channel   string    `json:"channel"`
+	Language  string    `json:"language"`
+}
+
+// ConversationExport is the document written by ToJSON
+type ConversationExport struct {
+	Header   ConversationHeader `json:"header"`
+	Messages []ExportedMessage  `json:"messages"`
+}
+
+// ToJSON writes the conversation as an indented JSON document with a header block
+// followed by its messages. metadata may be nil.
+func (e *ConversationExporter) ToJSON(w io.Writer, conv *models.Conversation, messages []*models.Message, metadata *models.ConversationMetadata) error {
+	doc := ConversationExport{
+		Header: ConversationHeader{
+			ConversationID: conv.ID,
+			Status:         conv.Status,
+			CustomerID:     conv.CustomerID,
+			ProductID:      conv.ProductID,
+			CreatedAt:      conv.CreatedAt.UTC(),
+			UpdatedAt:      conv.UpdatedAt.UTC(),
+			MessageCount:   len(messages),
+		},
+		Messages: make([]ExportedMessage, 0, len(messages)),
+	}
+	if metadata != nil {
+		doc.Header.Metadata = &MetadataSummary{
+			Intent:          metadata.Intent,
+			IntentScore:     metadata.IntentScore,
+			Sentiment:       metadata.Sentiment,
+			SentimentScore:  metadata.SentimentScore,
+			Emotions:        nonNil(metadata.Emotions),
+			Objections:      nonNil(metadata.Objections),
+			ComplexityScore: metadata.ComplexityScore,
+		}
+	}
+	for _, msg := range messages {
+		doc.Messages = append(doc.Messages, ExportedMessage{
+			ID:        msg.ID,
+			Timestamp: msg.Timestamp.UTC(),
+			Sender:    msg.Sender,
+			Content:   msg.Content,
+			Channel:   msg.Channel,
+			Language:  msg.Language,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	// Content is exported as written, so <, > and & are not escaped
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write json export: %w", err)
+	}
+	return nil
+}
+
+// ToCSV writes one row per message with the columns timestamp, sender, content, channel
+// and language. Content with commas, quotes or line breaks is quoted per RFC 4180.
+// The conversation and metadata are accepted for symmetry with ToJSON; CSV exports carry
+// messages only.
+func (e *ConversationExporter) ToCSV(w io.Writer, conv *models.Conversation, messages []*models.Message, metadata *models.ConversationMetadata) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, msg := range messages {
+		record := []string{
+			msg.Timestamp.UTC().Format(time.RFC3339),
+			msg.Sender,
+			msg.Content,
+			msg.Channel,
+			msg.Language,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+	return nil
+}
+
+// nonNil turns a nil slice into an empty one so it encodes as [] rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func exportFixture() (*models.Conversation, []*models.Message) {
+	customerID := "cust-1"
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	// A non-UTC zone checks that timestamps are normalized
+	ist := time.FixedZone("IST", 5*3600+1800)
+	conv := &models.Conversation{
+		ID:         "conv-1",
+		TenantID:   "tenant-1",
+		CustomerID: &customerID,
+		Status:     "closed",
+		CreatedAt:  created,
+		UpdatedAt:  created.Add(time.Hour),
+	}
+	messages := []*models.Message{
+		{ID: "m1", Sender: "customer", Content: `Price is "too high", honestly`, Channel: "web", Language: "en", Timestamp: created.Add(time.Minute)},
+		{ID: "m2", Sender: "agent", Content: "Line one\nLine two\r\n<b>50% off</b> & more", Channel: "web", Language: "en", Timestamp: created.Add(2 * time.Minute).In(ist)},
+		{ID: "m3", Sender: "customer", Content: "ठीक है, धन्यवाद 🙏", Channel: "whatsapp", Language: "hi", Timestamp: created.Add(3 * time.Minute)},
+	}
+	return conv, messages
+}
+
+func TestToCSV(t *testing.T) {
+	conv, messages := exportFixture()
+	var buf bytes.Buffer
+	if err := NewConversationExporter().ToCSV(&buf, conv, messages, nil); err != nil {
+		t.Fatalf("ToCSV: %v", err)
+	}
+
+	want := "timestamp,sender,content,channel,language\n" +
+		"2024-03-01T09:01:00Z,customer,\"Price is \"\"too high\"\", honestly\",web,en\n" +
+		"2024-03-01T09:02:00Z,agent,\"Line one\nLine two\r\n<b>50% off</b> & more\",web,en\n" +
+		"2024-03-01T09:03:00Z,customer,\"ठीक है, धन्यवाद 🙏\",whatsapp,hi\n"
+	if got := buf.String(); got != want {
+		t.Errorf("csv output:\n%q\nwant:\n%q", got, want)
+	}
+
+	// The output parses back to the original content
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != len(messages)+1 {
+		t.Fatalf("rows = %d, want %d", len(records), len(messages)+1)
+	}
+	for i, msg := range messages {
+		// encoding/csv reads \r\n inside quoted fields back as \n
+		want := bytes.ReplaceAll([]byte(msg.Content), []byte("\r\n"), []byte("\n"))
+		if got := records[i+1][2]; got != string(want) {
+			t.Errorf("row %d content = %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+func TestToCSVWithoutMessages(t *testing.T) {
+	conv, _ := exportFixture()
+	var buf bytes.Buffer
+	if err := NewConversationExporter().ToCSV(&buf, conv, nil, nil); err != nil {
+		t.Fatalf("ToCSV: %v", err)
+	}
+	if got, want := buf.String(), "timestamp,sender,content,channel,language\n"; got != want {
+		t.Errorf("csv output = %q, want only the header %q", got, want)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	conv, messages := exportFixture()
+	metadata := &models.ConversationMetadata{
+		Intent:         "buying",
+		IntentScore:    0.8,
+		Sentiment:      "negative",
+		SentimentScore: 0.25,
+		Objections:     []string{"price"},
+	}
+	var buf bytes.Buffer
+	if err := NewConversationExporter().ToJSON(&buf, conv, messages[:2], metadata); err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+
+	want := `{
+  "header": {
+    "conversation_id": "conv-1",
+    "status": "closed",
+    "customer_id": "cust-1",
+    "product_id": null,
+    "created_at": "2024-03-01T09:00:00Z",
+    "updated_at": "2024-03-01T10:00:00Z",
+    "message_count": 2,
+    "metadata": {
+      "intent": "buying",
+      "intent_score": 0.8,
+      "sentiment": "negative",
+      "sentiment_score": 0.25,
+      "emotions": [],
+      "objections": [
+        "price"
+      ],
+      "complexity_score": 0
+    }
+  },
+  "messages": [
+    {
+      "id": "m1",
+      "timestamp": "2024-03-01T09:01:00Z",
+      "sender": "customer",
+      "content": "Price is \"too high\", honestly",
+      "channel": "web",
+      "language": "en"
+    },
+    {
+      "id": "m2",
+      "timestamp": "2024-03-01T09:02:00Z",
+      "sender": "agent",
+      "content": "Line one\nLine two\r\n<b>50% off</b> & more",
+      "channel": "web",
+      "language": "en"
+    }
+  ]
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("json output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToJSONRoundTripsContent(t *testing.T) {
+	conv, messages := exportFixture()
+	var buf bytes.Buffer
+	if err := NewConversationExporter().ToJSON(&buf, conv, messages, nil); err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+
+	var doc ConversationExport
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.Header.Metadata != nil {
+		t.Errorf("metadata = %+v, want null before analysis", doc.Header.Metadata)
+	}
+	if doc.Header.MessageCount != 3 {
+		t.Errorf("message_count = %d, want 3", doc.Header.MessageCount)
+	}
+	var got []string
+	for _, msg := range doc.Messages {
+		got = append(got, msg.Content)
+	}
+	want := []string{messages[0].Content, messages[1].Content, messages[2].Content}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contents = %q, want %q", got, want)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("ठीक है, धन्यवाद 🙏")) {
+		t.Error("expected non-ASCII content to be written unescaped")
+	}
+}
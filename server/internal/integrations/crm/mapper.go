@@ -0,0 +1,106 @@
+package crm
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Supported CRM types
+const (
+	TypeHubSpot    = "hubspot"
+	TypeSalesforce = "salesforce"
+	TypeZoho       = "zoho"
+	TypeCustom     = "custom"
+)
+
+// IsValidType reports whether crmType is a supported CRM
+func IsValidType(crmType string) bool {
+	switch crmType {
+	case TypeHubSpot, TypeSalesforce, TypeZoho, TypeCustom:
+		return true
+	}
+	return false
+}
+
+// MappingStorage loads a tenant's field mapping for a CRM (nil when none is configured)
+type MappingStorage interface {
+	GetCRMFieldMapping(tenantID, crmType string) (*postgres.CRMFieldMapping, error)
+}
+
+// FieldMapper renames outgoing payload fields to the names a tenant's CRM expects
+type FieldMapper struct {
+	storage MappingStorage
+}
+
+// NewFieldMapper creates a field mapper. storage may be nil when only Apply is used.
+func NewFieldMapper(storage MappingStorage) *FieldMapper {
+	return &FieldMapper{storage: storage}
+}
+
+// Apply returns a copy of payload with top-level keys renamed by mappings. Unmapped keys are kept
+// as they are; when a renamed key collides with an unmapped one, the renamed value wins.
+func (m *FieldMapper) Apply(payload map[string]interface{}, mappings map[string]string) map[string]interface{} {
+	mapped := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		if mappings[key] == "" {
+			mapped[key] = value
+		}
+	}
+	for key, value := range payload {
+		if target := mappings[key]; target != "" {
+			mapped[target] = value
+		}
+	}
+	return mapped
+}
+
+// ApplyForTenant applies the tenant's mapping for crmType to payload. The payload is returned
+// unchanged when crmType is empty or the tenant has no mapping for it.
+func (m *FieldMapper) ApplyForTenant(tenantID, crmType string, payload map[string]interface{}) (map[string]interface{}, error) {
+	if crmType == "" || m.storage == nil {
+		return payload, nil
+	}
+	mapping, err := m.storage.GetCRMFieldMapping(tenantID, crmType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load crm field mapping: %w", err)
+	}
+	if mapping == nil {
+		return payload, nil
+	}
+	return m.Apply(payload, mapping.Mappings), nil
+}
+
+// ValidateMappings checks that every platform and CRM field name is non-empty and that no two
+// platform fields map to the same CRM field
+func ValidateMappings(mappings map[string]string) error {
+	targets := make(map[string]string, len(mappings))
+	for source, target := range mappings {
+		if strings.TrimSpace(source) == "" || strings.TrimSpace(target) == "" {
+			return fmt.Errorf("field names must not be empty")
+		}
+		if other, ok := targets[target]; ok {
+			return fmt.Errorf("fields %q and %q both map to %q", other, source, target)
+		}
+		targets[target] = source
+	}
+	return nil
+}
+
+// SamplePayload returns an example conversation event payload, used to preview a mapping
+func SamplePayload() map[string]interface{} {
+	return map[string]interface{}{
+		"event":           "conversation.updated",
+		"conversation_id": "00000000-0000-0000-0000-000000000000",
+		"customer_id":     "customer-123",
+		"customer_email":  "customer@example.com",
+		"status":          "active",
+		"intent":          "purchase",
+		"sentiment":       "positive",
+		"lead_score":      82.5,
+		"win_probability": 0.64,
+		"churn_risk":      0.12,
+		"lead_stage":      "qualified",
+	}
+}
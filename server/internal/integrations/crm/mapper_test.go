@@ -0,0 +1,123 @@
+package crm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeMappingStorage struct {
+	mapping *postgres.CRMFieldMapping
+	err     error
+}
+
+func (f *fakeMappingStorage) GetCRMFieldMapping(tenantID, crmType string) (*postgres.CRMFieldMapping, error) {
+	return f.mapping, f.err
+}
+
+func TestFieldMapperApply(t *testing.T) {
+	payload := map[string]interface{}{
+		"lead_score":       82.5,
+		"win_probability":  0.64,
+		"conversation_id":  "conv-1",
+		"deal_probability": "stale",
+	}
+	mappings := map[string]string{
+		"lead_score":      "hs_lead_score",
+		"win_probability": "deal_probability",
+		"not_in_payload":  "ignored",
+	}
+
+	got := NewFieldMapper(nil).Apply(payload, mappings)
+	want := map[string]interface{}{
+		"hs_lead_score":    82.5,
+		"deal_probability": 0.64, // Renamed value wins over the colliding original
+		"conversation_id":  "conv-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if _, ok := payload["hs_lead_score"]; ok || len(payload) != 4 {
+		t.Error("Apply() must not modify the input payload")
+	}
+}
+
+func TestFieldMapperApplyForTenant(t *testing.T) {
+	payload := map[string]interface{}{"lead_score": 50.0}
+
+	tests := []struct {
+		name    string
+		crmType string
+		storage *fakeMappingStorage
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:    "applies tenant mapping",
+			crmType: TypeHubSpot,
+			storage: &fakeMappingStorage{mapping: &postgres.CRMFieldMapping{Mappings: map[string]string{"lead_score": "hs_lead_score"}}},
+			want:    map[string]interface{}{"hs_lead_score": 50.0},
+		},
+		{
+			name:    "no crm type",
+			storage: &fakeMappingStorage{mapping: &postgres.CRMFieldMapping{Mappings: map[string]string{"lead_score": "hs_lead_score"}}},
+			want:    payload,
+		},
+		{
+			name:    "no mapping configured",
+			crmType: TypeZoho,
+			storage: &fakeMappingStorage{},
+			want:    payload,
+		},
+		{
+			name:    "storage error",
+			crmType: TypeSalesforce,
+			storage: &fakeMappingStorage{err: errors.New("db down")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFieldMapper(tt.storage).ApplyForTenant("tenant-1", tt.crmType, payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyForTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyForTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string]string
+		wantErr  bool
+	}{
+		{"valid", map[string]string{"lead_score": "hs_lead_score", "win_probability": "deal_probability"}, false},
+		{"empty target", map[string]string{"lead_score": " "}, true},
+		{"empty source", map[string]string{"": "hs_lead_score"}, true},
+		{"duplicate target", map[string]string{"lead_score": "score", "win_probability": "score"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMappings(tt.mappings); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMappings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsValidType(t *testing.T) {
+	for _, crmType := range []string{TypeHubSpot, TypeSalesforce, TypeZoho, TypeCustom} {
+		if !IsValidType(crmType) {
+			t.Errorf("IsValidType(%q) = false, want true", crmType)
+		}
+	}
+	if IsValidType("pipedrive") {
+		t.Error("IsValidType(pipedrive) = true, want false")
+	}
+}
@@ -0,0 +1,33 @@
+package email
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// InviteNotifier emails invitation links to invited users
+type InviteNotifier struct {
+	sender     *SMTPSender
+	appBaseURL string
+}
+
+// NewInviteNotifier creates an invitation notifier. Links point at APP_BASE_URL (default
+// http://localhost:3000).
+func NewInviteNotifier(sender *SMTPSender) *InviteNotifier {
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000"
+	}
+	return &InviteNotifier{sender: sender, appBaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// SendInvite emails the invitee a link to accept the invitation
+func (n *InviteNotifier) SendInvite(to, token string) error {
+	link := n.appBaseURL + "/accept-invite?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("You have been invited to join the AI Conversation Platform.\n\n"+
+		"Set your password to accept the invitation:\n%s\n\n"+
+		"The invitation expires in 7 days. If you weren't expecting it, you can ignore this email.\n", link)
+	return n.sender.Send([]string{to}, "You're invited to the AI Conversation Platform", body)
+}
@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"html"
+	"strings"
+)
+
+// skippedTags hold content that is never visible page text
+var skippedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// blockTags start a new line of text
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true, "tr": true, "table": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "section": true,
+	"article": true, "header": true, "footer": true, "blockquote": true, "pre": true, "hr": true,
+	"dt": true, "dd": true, "main": true, "nav": true, "aside": true,
+}
+
+// extractText strips tags from an HTML document and returns its <title> and visible text,
+// one block per line. This is a simple tokenizer, not a full HTML parser.
+func extractText(doc string) (title, text string) {
+	var body, titleText strings.Builder
+	skipUntil := "" // closing tag that ends a skipped element
+	inTitle := false
+
+	for i := 0; i < len(doc); {
+		if doc[i] != '<' {
+			end := strings.IndexByte(doc[i:], '<')
+			if end < 0 {
+				end = len(doc) - i
+			}
+			if skipUntil == "" {
+				if inTitle {
+					titleText.WriteString(doc[i : i+end])
+				} else {
+					body.WriteString(doc[i : i+end])
+				}
+			}
+			i += end
+			continue
+		}
+
+		if strings.HasPrefix(doc[i:], "<!--") {
+			end := strings.Index(doc[i+4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+
+		end := strings.IndexByte(doc[i:], '>')
+		if end < 0 {
+			break
+		}
+		name, closing, selfClosing := parseTag(doc[i+1 : i+end])
+		i += end + 1
+
+		if skipUntil != "" {
+			if closing && name == skipUntil {
+				skipUntil = ""
+			}
+			continue
+		}
+
+		switch {
+		case skippedTags[name]:
+			if !closing && !selfClosing {
+				skipUntil = name
+			}
+		case name == "title":
+			inTitle = !closing
+		case blockTags[name]:
+			body.WriteByte('\n')
+		default:
+			body.WriteByte(' ')
+		}
+	}
+
+	return collapseSpaces(html.UnescapeString(titleText.String())), normalizeLines(html.UnescapeString(body.String()))
+}
+
+// parseTag returns the lowercase name of a tag body such as `a href="..."` or `/p`
+func parseTag(tag string) (name string, closing, selfClosing bool) {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "/") {
+		closing = true
+		tag = tag[1:]
+	}
+	if strings.HasSuffix(tag, "/") {
+		selfClosing = true
+		tag = strings.TrimSuffix(tag, "/")
+	}
+	if i := strings.IndexAny(tag, " \t\r\n"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag), closing, selfClosing
+}
+
+// normalizeLines collapses whitespace within lines and drops empty lines
+func normalizeLines(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = collapseSpaces(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import "testing"
+
+func TestExtractText(t *testing.T) {
+	doc := `<!DOCTYPE html>
+<html>
+<head>
+	<title>  Pricing &amp; Plans
+	| Acme </title>
+	<style>body { color: red; }</style>
+	<script type="text/javascript">var tracking = "<p>not text</p>";</script>
+</head>
+<body>
+	<!-- navigation <p>hidden</p> -->
+	<h1>Plans</h1>
+	<p>The <b>Pro</b> plan costs   $49/month.</p>
+	<ul><li>Unlimited seats</li><li>SSO &amp; audit logs</li></ul>
+	<noscript>Enable JavaScript</noscript>
+	<svg><text>logo</text></svg>
+	Contact<br/>sales
+</body>
+</html>`
+
+	title, text := extractText(doc)
+	if want := "Pricing & Plans | Acme"; title != want {
+		t.Errorf("title = %q, want %q", title, want)
+	}
+	want := "Plans\nThe Pro plan costs $49/month.\nUnlimited seats\nSSO & audit logs\nContact\nsales"
+	if text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+}
+
+func TestExtractTextWithoutTitle(t *testing.T) {
+	title, text := extractText(`<div>Shipping takes <a href="/faq">3-5 days</a></div>`)
+	if title != "" || text != "Shipping takes 3-5 days" {
+		t.Errorf("extractText = %q, %q; want no title and the inline text", title, text)
+	}
+
+	// An unterminated tag ends the document
+	if _, text := extractText("Returns within 30 days <p class="); text != "Returns within 30 days" {
+		t.Errorf("text = %q, want the text before the broken tag", text)
+	}
+}
@@ -0,0 +1,233 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// MaxContentLength caps the extracted article text, in characters
+	MaxContentLength = 50000
+	// MaxRequestsPerHour caps URL indexing requests per tenant
+	MaxRequestsPerHour = 10
+
+	maxBodyBytes = 5 << 20
+	maxRedirects = 5
+	fetchTimeout = 15 * time.Second
+)
+
+// ErrRateLimited is returned when a tenant exceeds MaxRequestsPerHour
+var ErrRateLimited = errors.New("url indexing rate limit exceeded")
+
+// KnowledgeArticle is a knowledge base article created from a scraped page
+type KnowledgeArticle = postgres.KnowledgeArticle
+
+// KnowledgeArticleIndexer fetches web pages and stores them as knowledge articles.
+// Embedding runs on the embedding queue so requests return as soon as the article is saved.
+type KnowledgeArticleIndexer struct {
+	articleStorage *postgres.KnowledgeArticleStorage
+	productStorage *postgres.ProductStorage
+	embeddingQueue *ai.EmbeddingQueue
+	chunker        *ai.ProductChunker
+	httpClient     *http.Client
+	limiter        *hourlyLimiter
+}
+
+// NewKnowledgeArticleIndexer creates an indexer. embeddingQueue may be nil when embeddings are unavailable.
+func NewKnowledgeArticleIndexer(
+	articleStorage *postgres.KnowledgeArticleStorage,
+	productStorage *postgres.ProductStorage,
+	embeddingQueue *ai.EmbeddingQueue,
+) *KnowledgeArticleIndexer {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: dialControl}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	return &KnowledgeArticleIndexer{
+		articleStorage: articleStorage,
+		productStorage: productStorage,
+		embeddingQueue: embeddingQueue,
+		chunker:        ai.NewProductChunker(),
+		httpClient: &http.Client{
+			Timeout:   fetchTimeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				_, err := ValidateURL(req.URL.String())
+				return err
+			},
+		},
+		limiter: newHourlyLimiter(MaxRequestsPerHour),
+	}
+}
+
+// IndexFromURL fetches a page, stores its text as a knowledge article and queues it for embedding.
+// productID may be empty for articles that are not about a specific product.
+func (i *KnowledgeArticleIndexer) IndexFromURL(tenantID, productID, url string) (*KnowledgeArticle, error) {
+	parsed, err := ValidateURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if productID != "" {
+		if _, err := i.productStorage.GetProduct(tenantID, productID); err != nil {
+			return nil, err
+		}
+	}
+	if !i.limiter.allow(tenantID) {
+		return nil, ErrRateLimited
+	}
+
+	title, text, err := i.fetch(parsed.String())
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, fmt.Errorf("no text content found at url")
+	}
+	if title == "" {
+		title = parsed.Host + parsed.Path
+	}
+
+	sourceURL := parsed.String()
+	article := &KnowledgeArticle{
+		TenantID:  tenantID,
+		Title:     title,
+		Content:   text,
+		SourceURL: &sourceURL,
+	}
+	if productID != "" {
+		article.ProductID = &productID
+	}
+	if err := i.articleStorage.CreateArticle(article); err != nil {
+		return nil, err
+	}
+	log.Printf("[SCRAPER] article indexed tenant=%s article=%s url=%s length=%d", tenantID, article.ID, sourceURL, len(text))
+
+	i.queueEmbedding(article)
+	return article, nil
+}
+
+// UpdateArticle edits an article, keeping the previous revision in its version history, and re-embeds it
+func (i *KnowledgeArticleIndexer) UpdateArticle(tenantID, articleID, title, content, changedBy string) (*KnowledgeArticle, error) {
+	article, err := i.articleStorage.UpdateArticle(tenantID, articleID, title, truncateChars(content, MaxContentLength), changedBy)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[SCRAPER] article updated tenant=%s article=%s version=%d", tenantID, articleID, article.Version)
+
+	i.queueEmbedding(article)
+	return article, nil
+}
+
+// RestoreVersion restores a previous version of an article and re-embeds the restored content
+func (i *KnowledgeArticleIndexer) RestoreVersion(tenantID, articleID, versionID, changedBy string) (*KnowledgeArticle, error) {
+	article, err := i.articleStorage.RestoreVersion(tenantID, articleID, versionID, changedBy)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[SCRAPER] article version restored tenant=%s article=%s from_version=%s version=%d", tenantID, articleID, versionID, article.Version)
+
+	i.queueEmbedding(article)
+	return article, nil
+}
+
+// fetch downloads a page and returns its title and text, truncated to MaxContentLength characters
+func (i *KnowledgeArticleIndexer) fetch(url string) (title, text string, err error) {
+	resp, err := i.httpClient.Get(url)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", "", fmt.Errorf("failed to fetch url: status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "text/") {
+		return "", "", fmt.Errorf("unsupported content type: %s", contentType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if strings.HasPrefix(contentType, "text/plain") {
+		text = normalizeLines(string(body))
+	} else {
+		title, text = extractText(string(body))
+	}
+	return title, truncateChars(text, MaxContentLength), nil
+}
+
+// queueEmbedding chunks an article and hands it to the embedding queue
+func (i *KnowledgeArticleIndexer) queueEmbedding(article *KnowledgeArticle) {
+	if i.embeddingQueue == nil {
+		log.Printf("[SCRAPER] embedding unavailable, article not embedded article=%s", article.ID)
+		return
+	}
+
+	productID := ""
+	if article.ProductID != nil {
+		productID = *article.ProductID
+	}
+	chunks := i.chunker.ChunkArticle(article.TenantID, productID, article.ID, article.Title, article.Content)
+	if err := i.embeddingQueue.Enqueue(ai.EmbeddingJob{Label: "article:" + article.ID, Chunks: chunks}); err != nil {
+		log.Printf("[SCRAPER] failed to queue embedding article=%s error=%v", article.ID, err)
+	}
+}
+
+// truncateChars cuts s to at most n characters without splitting a UTF-8 sequence
+func truncateChars(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n])
+}
+
+// hourlyLimiter allows up to limit requests per tenant per hour
+type hourlyLimiter struct {
+	mu    sync.Mutex
+	limit int
+	sent  map[string][]time.Time
+}
+
+func newHourlyLimiter(limit int) *hourlyLimiter {
+	return &hourlyLimiter{limit: limit, sent: make(map[string][]time.Time)}
+}
+
+// allow records a request and reports whether it fits within the limit
+func (l *hourlyLimiter) allow(tenantID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := time.Now().Add(-time.Hour)
+	recent := l.sent[tenantID][:0]
+	for _, t := range l.sent[tenantID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.limit {
+		l.sent[tenantID] = recent
+		return false
+	}
+	l.sent[tenantID] = append(recent, time.Now())
+	return true
+}
@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// newTestIndexer returns an indexer that trusts server's certificate. The server listens on
+// loopback, so the dial-time address check is left out; redirects are still validated.
+func newTestIndexer(server *httptest.Server) *KnowledgeArticleIndexer {
+	indexer := NewKnowledgeArticleIndexer(nil, nil, nil)
+	indexer.httpClient.Transport = server.Client().Transport
+	return indexer
+}
+
+func TestFetchRejectsRedirectsToPrivateHosts(t *testing.T) {
+	for _, target := range []string{
+		"https://10.0.0.5/internal",
+		"https://169.254.169.254/latest/meta-data/",
+		"https://[::1]/admin",
+		"https://localhost/admin",
+		"http://docs.example.com/", // Downgrades to plain HTTP are rejected too
+	} {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target, http.StatusFound)
+		}))
+
+		_, _, err := newTestIndexer(server).fetch(server.URL)
+		if !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("redirect to %s: err = %v, want ErrInvalidURL", target, err)
+		}
+		server.Close()
+	}
+}
+
+func TestCheckRedirectStopsAfterMaxRedirects(t *testing.T) {
+	checkRedirect := NewKnowledgeArticleIndexer(nil, nil, nil).httpClient.CheckRedirect
+	next := httptest.NewRequest(http.MethodGet, "https://docs.example.com/next", nil)
+
+	if err := checkRedirect(next, make([]*http.Request, maxRedirects-1)); err != nil {
+		t.Errorf("redirect %d = %v, want it followed", maxRedirects, err)
+	}
+	if err := checkRedirect(next, make([]*http.Request, maxRedirects)); err == nil {
+		t.Errorf("redirect %d followed, want the chain stopped", maxRedirects+1)
+	}
+}
+
+func TestFetchRejectsPrivateAddressesAtDialTime(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	// The default transport checks the resolved address, so loopback is refused before connecting
+	_, _, err := NewKnowledgeArticleIndexer(nil, nil, nil).fetch(server.URL)
+	if !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("err = %v, want ErrInvalidURL from the dialer", err)
+	}
+}
+
+func TestFetchExtractsTitleAndText(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Returns policy</title></head><body><p>Returns within 30 days.</p></body></html>`))
+	}))
+	defer server.Close()
+
+	title, text, err := newTestIndexer(server).fetch(server.URL)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if title != "Returns policy" || text != "Returns within 30 days." {
+		t.Errorf("fetch = %q, %q; want the page title and text", title, text)
+	}
+}
+
+func TestFetchCapsContentLength(t *testing.T) {
+	// Multi-byte characters check the cap counts characters, not bytes
+	page := strings.Repeat("é", MaxContentLength+500)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	_, text, err := newTestIndexer(server).fetch(server.URL)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if n := utf8.RuneCountInString(text); n != MaxContentLength || !utf8.ValidString(text) {
+		t.Errorf("text has %d characters (valid UTF-8: %v), want %d", n, utf8.ValidString(text), MaxContentLength)
+	}
+}
+
+func TestFetchRejectsNonTextResponses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.7"))
+	}))
+	defer server.Close()
+
+	if _, _, err := newTestIndexer(server).fetch(server.URL); err == nil || !strings.Contains(err.Error(), "unsupported content type") {
+		t.Errorf("err = %v, want an unsupported content type error", err)
+	}
+}
+
+func TestTruncateChars(t *testing.T) {
+	if got := truncateChars("héllo", 2); got != "hé" {
+		t.Errorf("truncateChars = %q, want hé", got)
+	}
+	if got := truncateChars("hi", 5); got != "hi" {
+		t.Errorf("truncateChars = %q, want the short string unchanged", got)
+	}
+}
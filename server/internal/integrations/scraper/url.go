@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrInvalidURL is returned for URLs that may not be fetched
+var ErrInvalidURL = errors.New("invalid url")
+
+// blockedNetworks are the RFC 1918 private ranges plus loopback, link-local and
+// other non-public ranges that must never be fetched on a tenant's behalf
+var blockedNetworks = mustParseCIDRs(
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"100.64.0.0/10",
+	"0.0.0.0/8",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isBlockedIP reports whether ip is in a private or otherwise non-public range
+func isBlockedIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateURL checks that a URL is an HTTPS URL that does not point at a private address.
+// Hostnames are checked again at dial time, after DNS resolution.
+func ValidateURL(rawURL string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%w: must use https", ErrInvalidURL)
+	}
+	if parsed.User != nil {
+		return nil, fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return nil, fmt.Errorf("%w: private addresses are not allowed", ErrInvalidURL)
+	}
+	if ip := net.ParseIP(host); ip != nil && isBlockedIP(ip) {
+		return nil, fmt.Errorf("%w: private addresses are not allowed", ErrInvalidURL)
+	}
+	return parsed, nil
+}
+
+// dialControl rejects connections to private addresses once the hostname has been resolved,
+// so DNS records pointing at internal hosts can't bypass ValidateURL
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+		return fmt.Errorf("%w: %s resolves to a private address", ErrInvalidURL, host)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Notification event types
+const (
+	EventHotLead    = "hot_lead"
+	EventEscalation = "escalation"
+	EventTest       = "test"
+)
+
+// NotificationEvent describes something the sales team should hear about in Slack
+type NotificationEvent struct {
+	Type              string  `json:"type"`
+	TenantID          string  `json:"tenant_id"`
+	ConversationID    string  `json:"conversation_id,omitempty"`
+	ConversationURL   string  `json:"conversation_url,omitempty"`
+	CustomerEmail     string  `json:"customer_email,omitempty"`
+	WinProbability    float64 `json:"win_probability"` // 0-1
+	RecommendedAction string  `json:"recommended_action,omitempty"`
+	Details           string  `json:"details,omitempty"`
+	Watchlisted       bool    `json:"watchlisted,omitempty"`
+}
+
+// RateLimitError is returned when Slack responds with 429
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("slack rate limited, retry after %s", e.RetryAfter)
+}
+
+// SlackNotifier posts Block Kit messages to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	channel    string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a webhook. channel may be empty to use the webhook default.
+func NewSlackNotifier(webhookURL, channel string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		channel:    channel,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify sends an event to Slack. A 429 response is returned as *RateLimitError.
+func (n *SlackNotifier) Notify(event NotificationEvent) error {
+	body, err := json.Marshal(n.buildPayload(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack payload: %w", err)
+	}
+
+	resp, err := n.httpClient.Post(n.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := time.Minute
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// buildPayload builds a Block Kit message for an event
+func (n *SlackNotifier) buildPayload(event NotificationEvent) map[string]interface{} {
+	title := eventTitle(event.Type)
+
+	customer := event.CustomerEmail
+	if customer == "" {
+		customer = "Unknown"
+	}
+
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": plainText(title),
+		},
+		map[string]interface{}{
+			"type": "section",
+			"fields": []interface{}{
+				markdown(fmt.Sprintf("*Customer*\n%s", customer)),
+				markdown(fmt.Sprintf("*Win probability*\n%.0f%%", event.WinProbability*100)),
+			},
+		},
+	}
+
+	if event.RecommendedAction != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": markdown(fmt.Sprintf("*Recommended action*\n%s", event.RecommendedAction)),
+		})
+	}
+
+	if event.Details != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type":     "context",
+			"elements": []interface{}{markdown(event.Details)},
+		})
+	}
+
+	if event.ConversationURL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []interface{}{
+				map[string]interface{}{
+					"type": "button",
+					"text": plainText("Open conversation"),
+					"url":  event.ConversationURL,
+				},
+			},
+		})
+	}
+
+	payload := map[string]interface{}{
+		// Fallback for notifications and clients that can't render blocks
+		"text":   fmt.Sprintf("%s: %s", title, customer),
+		"blocks": blocks,
+	}
+	if n.channel != "" {
+		payload["channel"] = n.channel
+	}
+	return payload
+}
+
+func eventTitle(eventType string) string {
+	switch eventType {
+	case EventHotLead:
+		return "Hot lead"
+	case EventEscalation:
+		return "Conversation escalated"
+	case EventTest:
+		return "Slack integration test"
+	default:
+		return "Notification"
+	}
+}
+
+func plainText(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "plain_text", "text": text}
+}
+
+func markdown(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "mrkdwn", "text": text}
+}
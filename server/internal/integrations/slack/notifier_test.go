@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slackPayload is the subset of a Block Kit message the tests inspect
+type slackPayload struct {
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+	Blocks  []struct {
+		Type string `json:"type"`
+		Text struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"text"`
+		Fields []struct {
+			Text string `json:"text"`
+		} `json:"fields"`
+		Elements []struct {
+			Type string          `json:"type"`
+			Text json.RawMessage `json:"text"` // A string in context blocks, an object on buttons
+			URL  string          `json:"url"`
+		} `json:"elements"`
+	} `json:"blocks"`
+}
+
+// newWebhook serves a fake Slack webhook that records each posted payload and answers with respond
+func newWebhook(t *testing.T, respond func(w http.ResponseWriter, payload slackPayload)) (*httptest.Server, *[]slackPayload) {
+	t.Helper()
+	var received []slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received = append(received, payload)
+		respond(w, payload)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func respondOK(w http.ResponseWriter, payload slackPayload) { w.WriteHeader(http.StatusOK) }
+
+func TestNotifyPostsBlockKitMessage(t *testing.T) {
+	server, received := newWebhook(t, respondOK)
+
+	event := NotificationEvent{
+		Type:              EventEscalation,
+		CustomerEmail:     "buyer@example.com",
+		WinProbability:    0.82,
+		RecommendedAction: "Call the customer today",
+		Details:           "Escalated: sentiment 0.10 below 0.25",
+		ConversationURL:   "https://app.example.com/conversations/c1",
+	}
+	if err := NewSlackNotifier(server.URL, "#escalations").Notify(event); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if len(*received) != 1 {
+		t.Fatalf("received %d payloads, want 1", len(*received))
+	}
+	payload := (*received)[0]
+	if payload.Channel != "#escalations" || payload.Text != "Conversation escalated: buyer@example.com" {
+		t.Errorf("channel = %q, text = %q; want #escalations with the fallback text", payload.Channel, payload.Text)
+	}
+
+	var types []string
+	for _, block := range payload.Blocks {
+		types = append(types, block.Type)
+	}
+	if got := strings.Join(types, ","); got != "header,section,section,context,actions" {
+		t.Fatalf("blocks = %s, want header,section,section,context,actions", got)
+	}
+	if header := payload.Blocks[0].Text; header.Type != "plain_text" || header.Text != "Conversation escalated" {
+		t.Errorf("header = %+v, want the plain text title", header)
+	}
+	fields := payload.Blocks[1].Fields
+	if len(fields) != 2 || fields[0].Text != "*Customer*\nbuyer@example.com" || fields[1].Text != "*Win probability*\n82%" {
+		t.Errorf("fields = %+v, want the customer and win probability", fields)
+	}
+	if action := payload.Blocks[2].Text.Text; action != "*Recommended action*\nCall the customer today" {
+		t.Errorf("recommended action = %q", action)
+	}
+	if button := payload.Blocks[4].Elements[0]; button.Type != "button" || button.URL != event.ConversationURL {
+		t.Errorf("button = %+v, want a link to the conversation", button)
+	}
+}
+
+func TestNotifyOmitsOptionalBlocks(t *testing.T) {
+	server, received := newWebhook(t, respondOK)
+
+	if err := NewSlackNotifier(server.URL, "").Notify(NotificationEvent{Type: EventHotLead}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	payload := (*received)[0]
+	if payload.Channel != "" || payload.Text != "Hot lead: Unknown" || len(payload.Blocks) != 2 {
+		t.Errorf("payload = %+v, want the webhook's default channel, an unknown customer and no optional blocks", payload)
+	}
+}
+
+func TestNotifyRateLimited(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"Retry-After header", "30", 30 * time.Second},
+		{"no header", "", time.Minute},
+		{"invalid header", "soon", time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newWebhook(t, func(w http.ResponseWriter, payload slackPayload) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			})
+
+			err := NewSlackNotifier(server.URL, "").Notify(NotificationEvent{Type: EventHotLead})
+			var rateErr *RateLimitError
+			if !errors.As(err, &rateErr) || rateErr.RetryAfter != tt.want {
+				t.Errorf("err = %v, want a RateLimitError retrying after %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyReturnsSlackErrors(t *testing.T) {
+	server, _ := newWebhook(t, func(w http.ResponseWriter, payload slackPayload) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid_blocks"))
+	})
+
+	err := NewSlackNotifier(server.URL, "").Notify(NotificationEvent{Type: EventHotLead})
+	var rateErr *RateLimitError
+	if err == nil || errors.As(err, &rateErr) || !strings.Contains(err.Error(), "400: invalid_blocks") {
+		t.Errorf("err = %v, want the status and Slack's response", err)
+	}
+}
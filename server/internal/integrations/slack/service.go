@@ -0,0 +1,352 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// notificationChannel identifies Slack rows in the notifications queue
+const notificationChannel = "slack"
+
+const (
+	defaultRateLimitPerMinute = 1
+	maxDeliveryAttempts       = 5
+	queueBatchSize            = 50
+	retryBaseDelay            = time.Minute
+)
+
+// DefaultWatchlistChannel is used for watchlisted conversations when the tenant has not configured one
+const DefaultWatchlistChannel = "#executive-watchlist"
+
+// ConfigStore loads tenants' Slack settings (see postgres.SlackConfigStorage)
+type ConfigStore interface {
+	GetSlackConfig(tenantID string) (*postgres.SlackConfig, error)
+}
+
+// NotificationQueue persists notifications awaiting (re)delivery (see postgres.NotificationStorage)
+type NotificationQueue interface {
+	Enqueue(tenantID, channel, payload string, nextAttemptAt time.Time) (*postgres.Notification, error)
+	ListDue(channel string, limit int) ([]*postgres.Notification, error)
+	MarkSent(id string) error
+	Reschedule(id, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(id, lastError string) error
+}
+
+// ConversationReader loads a conversation to find its customer
+type ConversationReader interface {
+	GetConversation(tenantID, conversationID string) (*models.Conversation, error)
+}
+
+// UserReader loads the customer of a conversation
+type UserReader interface {
+	GetUser(tenantID, userID string) (*models.User, error)
+}
+
+// WatchlistChecker reports whether a conversation is watchlisted (to keep the service decoupled from storage)
+type WatchlistChecker interface {
+	IsWatchlisted(tenantID, conversationID string) (bool, error)
+}
+
+// Service routes tenant events to their Slack channels. Sends beyond the
+// per-tenant rate limit, or rejected by Slack with 429, are queued in the
+// notifications table and retried by the background worker.
+type Service struct {
+	configStorage       ConfigStore
+	notificationStorage NotificationQueue
+	conversationStorage ConversationReader
+	userStorage         UserReader
+	watchlistChecker    WatchlistChecker
+	appBaseURL          string
+	limiter             *rateLimiter
+	stop                chan struct{}
+	stopOnce            sync.Once
+}
+
+// NewService creates a Slack notification service.
+// Reads SLACK_RATE_LIMIT_PER_MINUTE (default 1) and APP_BASE_URL for conversation links.
+func NewService(
+	configStorage ConfigStore,
+	notificationStorage NotificationQueue,
+	conversationStorage ConversationReader,
+	userStorage UserReader,
+) *Service {
+	perMinute := defaultRateLimitPerMinute
+	if v, err := strconv.Atoi(os.Getenv("SLACK_RATE_LIMIT_PER_MINUTE")); err == nil && v > 0 {
+		perMinute = v
+	}
+
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000"
+	}
+
+	return &Service{
+		configStorage:       configStorage,
+		notificationStorage: notificationStorage,
+		conversationStorage: conversationStorage,
+		userStorage:         userStorage,
+		appBaseURL:          strings.TrimSuffix(baseURL, "/"),
+		limiter:             newRateLimiter(perMinute),
+		stop:                make(chan struct{}),
+	}
+}
+
+// SetWatchlistChecker routes notifications for watchlisted conversations to the watchlist channel
+func (s *Service) SetWatchlistChecker(checker WatchlistChecker) {
+	s.watchlistChecker = checker
+}
+
+// NotifyHotLead sends a hot lead notification to the tenant's hot lead channel
+func (s *Service) NotifyHotLead(tenantID, conversationID string, winProbability float64, recommendedAction, details string) {
+	s.notify(NotificationEvent{
+		Type:              EventHotLead,
+		TenantID:          tenantID,
+		ConversationID:    conversationID,
+		WinProbability:    winProbability,
+		RecommendedAction: recommendedAction,
+		Details:           details,
+	})
+}
+
+// NotifyEscalation sends an escalation notification to the tenant's escalation channel
+func (s *Service) NotifyEscalation(tenantID, conversationID string, winProbability float64, recommendedAction, details string) {
+	s.notify(NotificationEvent{
+		Type:              EventEscalation,
+		TenantID:          tenantID,
+		ConversationID:    conversationID,
+		WinProbability:    winProbability,
+		RecommendedAction: recommendedAction,
+		Details:           details,
+	})
+}
+
+// SendTest sends a test message straight to the tenant's hot lead channel, bypassing the queue
+func (s *Service) SendTest(tenantID string) error {
+	config, err := s.configStorage.GetSlackConfig(tenantID)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("slack config not found")
+	}
+
+	event := NotificationEvent{
+		Type:     EventTest,
+		TenantID: tenantID,
+		Details:  "Slack notifications are configured correctly.",
+	}
+	return NewSlackNotifier(config.WebhookURL, config.HotLeadChannel).Notify(event)
+}
+
+// notify enriches and delivers an event, queueing it when rate limited
+func (s *Service) notify(event NotificationEvent) {
+	config, err := s.configStorage.GetSlackConfig(event.TenantID)
+	if err != nil {
+		log.Printf("[SLACK] failed to load config tenant=%s error=%v", event.TenantID, err)
+		return
+	}
+	if config == nil || !config.IsActive {
+		return
+	}
+
+	s.enrich(&event)
+
+	if !s.limiter.allow(event.TenantID) {
+		s.enqueue(event, time.Now().Add(s.limiter.window()))
+		return
+	}
+
+	err = notifierFor(config, event).Notify(event)
+	var rateErr *RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		s.enqueue(event, time.Now().Add(rateErr.RetryAfter))
+	case err != nil:
+		log.Printf("[SLACK] delivery failed, queued for retry tenant=%s type=%s error=%v", event.TenantID, event.Type, err)
+		s.enqueue(event, time.Now().Add(retryBaseDelay))
+	default:
+		log.Printf("[SLACK] notification sent tenant=%s type=%s conversation=%s", event.TenantID, event.Type, event.ConversationID)
+	}
+}
+
+// enrich fills in the conversation link, watchlist flag and customer email
+func (s *Service) enrich(event *NotificationEvent) {
+	if event.ConversationID == "" {
+		return
+	}
+	event.ConversationURL = fmt.Sprintf("%s/conversations/%s", s.appBaseURL, event.ConversationID)
+
+	if s.watchlistChecker != nil {
+		watchlisted, err := s.watchlistChecker.IsWatchlisted(event.TenantID, event.ConversationID)
+		if err != nil {
+			log.Printf("[SLACK] failed to check watchlist tenant=%s conversation=%s error=%v", event.TenantID, event.ConversationID, err)
+		}
+		event.Watchlisted = watchlisted
+	}
+
+	conv, err := s.conversationStorage.GetConversation(event.TenantID, event.ConversationID)
+	if err != nil || conv.CustomerID == nil || *conv.CustomerID == "" {
+		return
+	}
+	if user, err := s.userStorage.GetUser(event.TenantID, *conv.CustomerID); err == nil {
+		event.CustomerEmail = user.Email
+	}
+}
+
+func (s *Service) enqueue(event NotificationEvent, nextAttemptAt time.Time) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[SLACK] failed to marshal notification tenant=%s error=%v", event.TenantID, err)
+		return
+	}
+	if _, err := s.notificationStorage.Enqueue(event.TenantID, notificationChannel, string(payload), nextAttemptAt); err != nil {
+		log.Printf("[SLACK] failed to queue notification tenant=%s error=%v", event.TenantID, err)
+		return
+	}
+	log.Printf("[SLACK] notification queued tenant=%s type=%s next_attempt=%s", event.TenantID, event.Type, nextAttemptAt.Format(time.RFC3339))
+}
+
+// ProcessQueue retries due queued notifications. Returns the number delivered.
+func (s *Service) ProcessQueue() (int, error) {
+	due, err := s.notificationStorage.ListDue(notificationChannel, queueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range due {
+		var event NotificationEvent
+		if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+			s.markFailed(n.ID, fmt.Sprintf("invalid payload: %v", err))
+			continue
+		}
+
+		// Skip tenants still over the limit; the row stays due for the next run
+		if !s.limiter.allow(n.TenantID) {
+			continue
+		}
+
+		config, err := s.configStorage.GetSlackConfig(n.TenantID)
+		if err != nil {
+			log.Printf("[SLACK] failed to load config tenant=%s error=%v", n.TenantID, err)
+			continue
+		}
+		if config == nil || !config.IsActive {
+			s.markFailed(n.ID, "slack integration disabled")
+			continue
+		}
+
+		err = notifierFor(config, event).Notify(event)
+		var rateErr *RateLimitError
+		switch {
+		case err == nil:
+			if err := s.notificationStorage.MarkSent(n.ID); err != nil {
+				log.Printf("[SLACK] %v", err)
+			}
+			sent++
+		case n.Attempts+1 >= maxDeliveryAttempts:
+			s.markFailed(n.ID, err.Error())
+		case errors.As(err, &rateErr):
+			s.reschedule(n.ID, err.Error(), time.Now().Add(rateErr.RetryAfter))
+		default:
+			// Exponential backoff: 1m, 2m, 4m, ...
+			s.reschedule(n.ID, err.Error(), time.Now().Add(retryBaseDelay*time.Duration(1<<uint(n.Attempts))))
+		}
+	}
+	return sent, nil
+}
+
+func (s *Service) reschedule(id, lastError string, next time.Time) {
+	if err := s.notificationStorage.Reschedule(id, lastError, next); err != nil {
+		log.Printf("[SLACK] %v", err)
+	}
+}
+
+func (s *Service) markFailed(id, lastError string) {
+	log.Printf("[SLACK] giving up on notification id=%s error=%s", id, lastError)
+	if err := s.notificationStorage.MarkFailed(id, lastError); err != nil {
+		log.Printf("[SLACK] %v", err)
+	}
+}
+
+// Start runs the queue worker in the background until Stop is called
+func (s *Service) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.ProcessQueue(); err != nil {
+					log.Printf("[SLACK] queue processing failed: %v", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the queue worker
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// notifierFor picks the channel configured for an event. Watchlisted conversations always go
+// to the watchlist channel so executives see every event for them in one place.
+func notifierFor(config *postgres.SlackConfig, event NotificationEvent) *SlackNotifier {
+	channel := config.HotLeadChannel
+	if event.Type == EventEscalation && config.EscalationChannel != "" {
+		channel = config.EscalationChannel
+	}
+	if event.Watchlisted {
+		channel = config.WatchlistChannel
+		if channel == "" {
+			channel = DefaultWatchlistChannel
+		}
+	}
+	return NewSlackNotifier(config.WebhookURL, channel)
+}
+
+// rateLimiter allows up to limit sends per tenant per minute
+type rateLimiter struct {
+	mu    sync.Mutex
+	limit int
+	sent  map[string][]time.Time
+}
+
+func newRateLimiter(limit int) *rateLimiter {
+	return &rateLimiter{limit: limit, sent: make(map[string][]time.Time)}
+}
+
+func (l *rateLimiter) window() time.Duration { return time.Minute }
+
+// allow records a send and reports whether it fits within the limit
+func (l *rateLimiter) allow(tenantID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := time.Now().Add(-l.window())
+	recent := l.sent[tenantID][:0]
+	for _, t := range l.sent[tenantID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.limit {
+		l.sent[tenantID] = recent
+		return false
+	}
+	l.sent[tenantID] = append(recent, time.Now())
+	return true
+}
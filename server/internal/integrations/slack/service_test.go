@@ -0,0 +1,248 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeConfigStore map[string]*postgres.SlackConfig
+
+func (f fakeConfigStore) GetSlackConfig(tenantID string) (*postgres.SlackConfig, error) {
+	return f[tenantID], nil
+}
+
+// fakeQueue keeps notifications in memory and records how each was settled
+type fakeQueue struct {
+	pending []*postgres.Notification
+	sent    []string
+	failed  map[string]string
+	retried map[string]time.Time
+}
+
+func newFakeQueue(pending ...*postgres.Notification) *fakeQueue {
+	return &fakeQueue{pending: pending, failed: make(map[string]string), retried: make(map[string]time.Time)}
+}
+
+func (f *fakeQueue) Enqueue(tenantID, channel, payload string, nextAttemptAt time.Time) (*postgres.Notification, error) {
+	n := &postgres.Notification{ID: "n" + string(rune('1'+len(f.pending))), TenantID: tenantID, Channel: channel, Payload: payload, NextAttemptAt: nextAttemptAt}
+	f.pending = append(f.pending, n)
+	return n, nil
+}
+
+func (f *fakeQueue) ListDue(channel string, limit int) ([]*postgres.Notification, error) {
+	return f.pending, nil
+}
+
+func (f *fakeQueue) MarkSent(id string) error {
+	f.sent = append(f.sent, id)
+	return nil
+}
+
+func (f *fakeQueue) Reschedule(id, lastError string, nextAttemptAt time.Time) error {
+	f.retried[id] = nextAttemptAt
+	return nil
+}
+
+func (f *fakeQueue) MarkFailed(id, lastError string) error {
+	f.failed[id] = lastError
+	return nil
+}
+
+type fakeConversations struct{}
+
+func (fakeConversations) GetConversation(tenantID, conversationID string) (*models.Conversation, error) {
+	if conversationID != "c1" {
+		return nil, errors.New("conversation not found")
+	}
+	customerID := "customer-1"
+	return &models.Conversation{ID: conversationID, TenantID: tenantID, CustomerID: &customerID}, nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) GetUser(tenantID, userID string) (*models.User, error) {
+	return &models.User{ID: userID, TenantID: tenantID, Email: "buyer@example.com"}, nil
+}
+
+func newTestService(webhookURL string, queue *fakeQueue, perMinute int) *Service {
+	configs := fakeConfigStore{
+		"tenant-1": {TenantID: "tenant-1", WebhookURL: webhookURL, HotLeadChannel: "#leads", EscalationChannel: "#escalations", IsActive: true},
+		"tenant-2": {TenantID: "tenant-2", WebhookURL: webhookURL, IsActive: false},
+	}
+	s := NewService(configs, queue, fakeConversations{}, fakeUsers{})
+	s.limiter = newRateLimiter(perMinute)
+	return s
+}
+
+// queuedEvent decodes a queued notification's payload
+func queuedEvent(t *testing.T, n *postgres.Notification) NotificationEvent {
+	t.Helper()
+	var event NotificationEvent
+	if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+		t.Fatalf("invalid queued payload: %v", err)
+	}
+	return event
+}
+
+func TestNotifyEscalationSendsToEscalationChannel(t *testing.T) {
+	server, received := newWebhook(t, respondOK)
+	queue := newFakeQueue()
+	s := newTestService(server.URL, queue, 10)
+
+	s.NotifyEscalation("tenant-1", "c1", 0.4, "Reply to the customer", "Escalated: sentiment 0.10 below 0.25")
+
+	if len(*received) != 1 || len(queue.pending) != 0 {
+		t.Fatalf("received %d, queued %d; want one direct send", len(*received), len(queue.pending))
+	}
+	payload := (*received)[0]
+	if payload.Channel != "#escalations" || !strings.Contains(payload.Text, "buyer@example.com") {
+		t.Errorf("payload = %+v, want the escalation channel and the customer's email", payload)
+	}
+
+	// Disabled tenants get nothing
+	s.NotifyEscalation("tenant-2", "c1", 0.4, "", "")
+	if len(*received) != 1 || len(queue.pending) != 0 {
+		t.Errorf("received %d, queued %d after a disabled tenant's event; want nothing new", len(*received), len(queue.pending))
+	}
+}
+
+func TestNotifyQueuesRateLimitedSends(t *testing.T) {
+	server, received := newWebhook(t, func(w http.ResponseWriter, payload slackPayload) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	queue := newFakeQueue()
+	s := newTestService(server.URL, queue, 1)
+
+	// Slack answers 429: the event is queued until Retry-After has passed
+	start := time.Now()
+	s.NotifyHotLead("tenant-1", "c1", 0.9, "Send a quote", "New hot lead")
+	if len(*received) != 1 || len(queue.pending) != 1 {
+		t.Fatalf("received %d, queued %d; want one attempt then the event queued", len(*received), len(queue.pending))
+	}
+	queued := queue.pending[0]
+	if wait := queued.NextAttemptAt.Sub(start); wait < 2*time.Minute || wait > 2*time.Minute+time.Second {
+		t.Errorf("next attempt in %s, want 2m from Retry-After", wait)
+	}
+	if event := queuedEvent(t, queued); event.Type != EventHotLead || event.ConversationID != "c1" || event.CustomerEmail != "buyer@example.com" {
+		t.Errorf("queued event = %+v, want the enriched hot lead", event)
+	}
+
+	// Over the per-tenant limit the event is queued without calling Slack
+	s.NotifyEscalation("tenant-1", "c1", 0.9, "", "")
+	if len(*received) != 1 || len(queue.pending) != 2 {
+		t.Fatalf("received %d, queued %d; want the second event queued without a send", len(*received), len(queue.pending))
+	}
+	if wait := queue.pending[1].NextAttemptAt.Sub(start); wait < time.Minute || wait > time.Minute+time.Second {
+		t.Errorf("next attempt in %s, want the limiter's 1m window", wait)
+	}
+}
+
+func TestProcessQueue(t *testing.T) {
+	// Slack answers each notification by the outcome named in its details
+	server, _ := newWebhook(t, func(w http.ResponseWriter, payload slackPayload) {
+		details := ""
+		for _, block := range payload.Blocks {
+			if block.Type == "context" {
+				json.Unmarshal(block.Elements[0].Text, &details)
+			}
+		}
+		switch details {
+		case "limited":
+			w.Header().Set("Retry-After", "90")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	payload := func(details string) string {
+		encoded, _ := json.Marshal(NotificationEvent{Type: EventHotLead, TenantID: "tenant-1", Details: details})
+		return string(encoded)
+	}
+	queue := newFakeQueue(
+		&postgres.Notification{ID: "sent", TenantID: "tenant-1", Payload: payload("ok")},
+		&postgres.Notification{ID: "limited", TenantID: "tenant-1", Payload: payload("limited"), Attempts: 1},
+		&postgres.Notification{ID: "backoff", TenantID: "tenant-1", Payload: payload("broken"), Attempts: 2},
+		&postgres.Notification{ID: "exhausted", TenantID: "tenant-1", Payload: payload("broken"), Attempts: maxDeliveryAttempts - 1},
+		&postgres.Notification{ID: "garbled", TenantID: "tenant-1", Payload: "{"},
+		&postgres.Notification{ID: "disabled", TenantID: "tenant-2", Payload: payload("ok")},
+	)
+	s := newTestService(server.URL, queue, 100)
+
+	start := time.Now()
+	sent, err := s.ProcessQueue()
+	if err != nil {
+		t.Fatalf("ProcessQueue: %v", err)
+	}
+	if sent != 1 || len(queue.sent) != 1 || queue.sent[0] != "sent" {
+		t.Errorf("sent = %d %v, want only the deliverable notification", sent, queue.sent)
+	}
+
+	// 429s wait for Retry-After; other failures back off 1m, 2m, 4m, ... by attempt
+	wantRetries := map[string]time.Duration{"limited": 90 * time.Second, "backoff": 4 * retryBaseDelay}
+	if len(queue.retried) != len(wantRetries) {
+		t.Errorf("rescheduled = %v, want %v", queue.retried, wantRetries)
+	}
+	for id, want := range wantRetries {
+		if wait := queue.retried[id].Sub(start); wait < want || wait > want+time.Second {
+			t.Errorf("%s rescheduled in %s, want %s", id, wait, want)
+		}
+	}
+
+	for _, id := range []string{"exhausted", "garbled", "disabled"} {
+		if _, ok := queue.failed[id]; !ok {
+			t.Errorf("%s wasn't marked failed; failed = %v", id, queue.failed)
+		}
+	}
+	if len(queue.failed) != 3 {
+		t.Errorf("failed = %v, want exhausted, garbled and disabled", queue.failed)
+	}
+}
+
+func TestProcessQueueLeavesRateLimitedTenantsDue(t *testing.T) {
+	server, received := newWebhook(t, respondOK)
+	encoded, _ := json.Marshal(NotificationEvent{Type: EventHotLead, TenantID: "tenant-1"})
+	queue := newFakeQueue(
+		&postgres.Notification{ID: "first", TenantID: "tenant-1", Payload: string(encoded)},
+		&postgres.Notification{ID: "second", TenantID: "tenant-1", Payload: string(encoded)},
+	)
+	s := newTestService(server.URL, queue, 1)
+
+	if sent, err := s.ProcessQueue(); err != nil || sent != 1 {
+		t.Fatalf("ProcessQueue = %d, %v; want one send within the limit", sent, err)
+	}
+	if len(*received) != 1 || len(queue.retried) != 0 || len(queue.failed) != 0 {
+		t.Errorf("received %d, retried %v, failed %v; want the second left due for the next run", len(*received), queue.retried, queue.failed)
+	}
+}
+
+func TestNotifierForRoutesByEvent(t *testing.T) {
+	config := &postgres.SlackConfig{WebhookURL: "https://hooks.slack.com/x", HotLeadChannel: "#leads", EscalationChannel: "#escalations"}
+	tests := []struct {
+		name   string
+		config *postgres.SlackConfig
+		event  NotificationEvent
+		want   string
+	}{
+		{"hot lead", config, NotificationEvent{Type: EventHotLead}, "#leads"},
+		{"escalation", config, NotificationEvent{Type: EventEscalation}, "#escalations"},
+		{"escalation without its own channel", &postgres.SlackConfig{HotLeadChannel: "#leads"}, NotificationEvent{Type: EventEscalation}, "#leads"},
+		{"watchlisted", config, NotificationEvent{Type: EventEscalation, Watchlisted: true}, DefaultWatchlistChannel},
+		{"watchlisted with a configured channel", &postgres.SlackConfig{WatchlistChannel: "#execs"}, NotificationEvent{Type: EventHotLead, Watchlisted: true}, "#execs"},
+	}
+	for _, tt := range tests {
+		if got := notifierFor(tt.config, tt.event).channel; got != tt.want {
+			t.Errorf("%s: channel = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package metrics exposes Prometheus metrics for the API server. Metrics are only recorded once a
+// Registry is installed with SetRegistry; until then the recording functions do nothing.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Gemini call outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry holds the server's Prometheus collectors. A nil *Registry is valid and records nothing.
+type Registry struct {
+	registry             *prometheus.Registry
+	httpRequests         *prometheus.CounterVec
+	geminiLatency        *prometheus.HistogramVec
+	ruleViolations       *prometheus.CounterVec
+	websocketConnections prometheus.Gauge
+	dbRetries            *prometheus.CounterVec
+	analysisSkipped      prometheus.Counter
+}
+
+// NewRegistry creates a registry with the server's metrics plus the Go runtime and process collectors
+func NewRegistry() *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests handled, by method, route and status code.",
+		}, []string{"method", "path", "status"}),
+		geminiLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gemini_request_duration_seconds",
+			Help:    "Latency of Gemini text generation API calls, by outcome.",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 16, 30},
+		}, []string{"outcome"}),
+		ruleViolations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rule_violations_total",
+			Help: "Rule engine violations, by rule type.",
+		}, []string{"rule_type"}),
+		websocketConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "websocket_active_connections",
+			Help: "Open WebSocket message stream connections.",
+		}),
+		dbRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "postgres_retries_total",
+			Help: "Retried database operations, by operation and tenant.",
+		}, []string{"operation", "tenant_id"}),
+		analysisSkipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "analysis_skipped_total",
+			Help: "Conversation analyses skipped because the new message could not change the result.",
+		}),
+	}
+	r.registry.MustRegister(
+		r.httpRequests,
+		r.geminiLatency,
+		r.ruleViolations,
+		r.websocketConnections,
+		r.dbRetries,
+		r.analysisSkipped,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest counts a handled request. path should be the route pattern, not the raw URL,
+// to keep the number of series bounded.
+func (r *Registry) ObserveHTTPRequest(method, path string, status int) {
+	if r == nil {
+		return
+	}
+	r.httpRequests.WithLabelValues(method, path, strconv.Itoa(status)).Inc()
+}
+
+// ObserveGeminiCall records the latency of a Gemini API call
+func (r *Registry) ObserveGeminiCall(duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	r.geminiLatency.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// RecordRuleViolation counts a rule engine violation
+func (r *Registry) RecordRuleViolation(ruleType string) {
+	if r == nil {
+		return
+	}
+	r.ruleViolations.WithLabelValues(ruleType).Inc()
+}
+
+// WebSocketOpened counts a new WebSocket connection
+func (r *Registry) WebSocketOpened() {
+	if r == nil {
+		return
+	}
+	r.websocketConnections.Inc()
+}
+
+// WebSocketClosed counts a closed WebSocket connection
+func (r *Registry) WebSocketClosed() {
+	if r == nil {
+		return
+	}
+	r.websocketConnections.Dec()
+}
+
+// RecordDBRetry counts a retried database operation
+func (r *Registry) RecordDBRetry(operation, tenantID string) {
+	if r == nil {
+		return
+	}
+	r.dbRetries.WithLabelValues(operation, tenantID).Inc()
+}
+
+// RecordAnalysisSkipped counts a conversation analysis skipped as unnecessary
+func (r *Registry) RecordAnalysisSkipped() {
+	if r == nil {
+		return
+	}
+	r.analysisSkipped.Inc()
+}
+
+var globalRegistry atomic.Pointer[Registry]
+
+// SetRegistry installs the registry the package-level functions record to; nil turns metrics off
+func SetRegistry(r *Registry) {
+	globalRegistry.Store(r)
+}
+
+// ObserveHTTPRequest counts a handled request in the installed registry
+func ObserveHTTPRequest(method, path string, status int) {
+	globalRegistry.Load().ObserveHTTPRequest(method, path, status)
+}
+
+// ObserveGeminiCall records the latency of a Gemini API call in the installed registry
+func ObserveGeminiCall(duration time.Duration, err error) {
+	globalRegistry.Load().ObserveGeminiCall(duration, err)
+}
+
+// RecordRuleViolation counts a rule engine violation in the installed registry
+func RecordRuleViolation(ruleType string) {
+	globalRegistry.Load().RecordRuleViolation(ruleType)
+}
+
+// WebSocketOpened counts a new WebSocket connection in the installed registry
+func WebSocketOpened() {
+	globalRegistry.Load().WebSocketOpened()
+}
+
+// WebSocketClosed counts a closed WebSocket connection in the installed registry
+func WebSocketClosed() {
+	globalRegistry.Load().WebSocketClosed()
+}
+
+// RecordDBRetry counts a retried database operation in the installed registry
+func RecordDBRetry(operation, tenantID string) {
+	globalRegistry.Load().RecordDBRetry(operation, tenantID)
+}
+
+// RecordAnalysisSkipped counts a conversation analysis skipped as unnecessary in the installed registry
+func RecordAnalysisSkipped() {
+	globalRegistry.Load().RecordAnalysisSkipped()
+}
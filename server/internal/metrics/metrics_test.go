@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// installRegistry installs a fresh registry for the duration of the test
+func installRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	SetRegistry(r)
+	t.Cleanup(func() { SetRegistry(nil) })
+	return r
+}
+
+// scrape returns the registry's exposition output
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func assertSample(t *testing.T, output, sample string) {
+	t.Helper()
+	for _, line := range strings.Split(output, "\n") {
+		if line == sample {
+			return
+		}
+	}
+	t.Errorf("missing sample %q in:\n%s", sample, output)
+}
+
+func TestMiddlewareCountsRequestsByRoute(t *testing.T) {
+	r := installRegistry(t)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Middleware())
+	engine.GET("/api/conversations/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/conversations/conv-1", "/api/conversations/conv-2", "/api/conversations/missing", "/nope"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	output := scrape(t, r)
+	assertSample(t, output, `http_requests_total{method="GET",path="/api/conversations/:id",status="200"} 2`)
+	assertSample(t, output, `http_requests_total{method="GET",path="/api/conversations/:id",status="404"} 1`)
+	assertSample(t, output, `http_requests_total{method="GET",path="unmatched",status="404"} 1`)
+}
+
+func TestRecordingFunctions(t *testing.T) {
+	r := installRegistry(t)
+
+	ObserveGeminiCall(200*time.Millisecond, nil)
+	ObserveGeminiCall(time.Second, nil)
+	ObserveGeminiCall(3*time.Second, errors.New("rate limited"))
+	RecordRuleViolation("no_false_claims")
+	RecordRuleViolation("no_false_claims")
+	RecordRuleViolation("objection")
+	WebSocketOpened()
+	WebSocketOpened()
+	WebSocketClosed()
+	RecordDBRetry("CreateMessage", "tenant-1")
+	RecordDBRetry("CreateMessage", "tenant-1")
+	RecordAnalysisSkipped()
+
+	output := scrape(t, r)
+	assertSample(t, output, `gemini_request_duration_seconds_count{outcome="success"} 2`)
+	assertSample(t, output, `gemini_request_duration_seconds_count{outcome="error"} 1`)
+	assertSample(t, output, `gemini_request_duration_seconds_bucket{outcome="success",le="0.25"} 1`)
+	assertSample(t, output, `rule_violations_total{rule_type="no_false_claims"} 2`)
+	assertSample(t, output, `rule_violations_total{rule_type="objection"} 1`)
+	assertSample(t, output, `websocket_active_connections 1`)
+	assertSample(t, output, `postgres_retries_total{operation="CreateMessage",tenant_id="tenant-1"} 2`)
+	assertSample(t, output, `analysis_skipped_total 1`)
+}
+
+func TestNoRegistryRecordsNothing(t *testing.T) {
+	SetRegistry(nil)
+
+	// None of these may panic without a registry
+	ObserveHTTPRequest(http.MethodGet, "/", http.StatusOK)
+	ObserveGeminiCall(time.Second, nil)
+	RecordRuleViolation("objection")
+	WebSocketOpened()
+	WebSocketClosed()
+	RecordDBRetry("CreateMessage", "tenant-1")
+	RecordAnalysisSkipped()
+}
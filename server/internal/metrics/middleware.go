@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware counts each request by method, route pattern and status code. Requests that match
+// no route are counted under "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+		ObserveHTTPRequest(c.Request.Method, path, c.Writer.Status())
+	}
+}
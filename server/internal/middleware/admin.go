@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware rejects requests whose JWT role is not admin
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SuperAdminRoleMiddleware rejects requests whose JWT role is not super_admin. Tenant admins
+// are rejected too; unlike SuperAdminMiddleware it authenticates with a user's JWT.
+func SuperAdminRoleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "super_admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "super admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
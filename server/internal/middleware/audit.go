@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AuditRecorder appends entries to the audit log
+type AuditRecorder interface {
+	Record(entry *postgres.AuditLog) error
+}
+
+// snapshotEngine owns the contexts snapshot handlers run in; it serves no routes
+var snapshotEngine = sync.OnceValue(gin.New)
+
+// AuditMiddleware records successful changes to a resource in the audit log. snapshot is the
+// resource's GET handler: it runs with the same tenant and path params before the change, and its
+// response is stored as old_value. The changing handler's response is stored as new_value, except
+// for deletes. Creates pass a nil snapshot. With a nil recorder requests pass through unaudited.
+func AuditMiddleware(auditStorage AuditRecorder, resourceType string, snapshot gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auditStorage == nil {
+			c.Next()
+			return
+		}
+
+		var oldValue *string
+		if snapshot != nil {
+			oldValue = captureSnapshot(c, snapshot)
+		}
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+
+		entry := &postgres.AuditLog{
+			TenantID:     c.GetString("tenant_id"),
+			Action:       resourceType + "." + auditAction(c.Request.Method, snapshot != nil),
+			ResourceType: resourceType,
+			OldValue:     oldValue,
+		}
+		if userID := c.GetString("user_id"); userID != "" {
+			entry.UserID = &userID
+		}
+		if c.Request.Method != http.MethodDelete {
+			entry.NewValue = jsonValue(writer.body.Bytes())
+		}
+		if resourceID := c.Param("id"); resourceID != "" {
+			entry.ResourceID = &resourceID
+		} else if resourceID := responseResourceID(writer.body.Bytes()); resourceID != "" {
+			entry.ResourceID = &resourceID
+		}
+
+		if err := auditStorage.Record(entry); err != nil {
+			log.Printf("[AUDIT] failed to record audit log action=%s tenant=%s: %v", entry.Action, entry.TenantID, err)
+		}
+	}
+}
+
+// auditAction names a change by its HTTP method. POST is an update for singleton resources,
+// which have a snapshot of their previous state.
+func auditAction(method string, hasSnapshot bool) string {
+	switch {
+	case method == http.MethodDelete:
+		return "deleted"
+	case method == http.MethodPost && !hasSnapshot:
+		return "created"
+	default:
+		return "updated"
+	}
+}
+
+// captureSnapshot runs the snapshot handler against a GET copy of the request and returns its
+// response, or nil when the resource could not be loaded
+func captureSnapshot(c *gin.Context, snapshot gin.HandlerFunc) *string {
+	recorder := &snapshotResponseWriter{header: http.Header{}, status: http.StatusOK}
+	snapshotContext := gin.CreateTestContextOnly(recorder, snapshotEngine())
+	snapshotContext.Request = c.Request.Clone(c.Request.Context())
+	snapshotContext.Request.Method = http.MethodGet
+	snapshotContext.Request.Body = http.NoBody
+	snapshotContext.Params = c.Params
+	for key, value := range c.Keys {
+		snapshotContext.Set(key, value)
+	}
+
+	snapshot(snapshotContext)
+	if recorder.status != http.StatusOK {
+		return nil
+	}
+	return jsonValue(recorder.body.Bytes())
+}
+
+// jsonValue returns a JSON response body as an audit value, or nil when it is not JSON
+func jsonValue(body []byte) *string {
+	if len(body) == 0 || !json.Valid(body) {
+		return nil
+	}
+	value := string(body)
+	return &value
+}
+
+// responseResourceID returns the id of the resource a create handler responded with. Responses
+// wrap the resource in a single field, e.g. {"rule": {"id": ...}}.
+func responseResourceID(body []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+	for _, field := range fields {
+		var resource struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(field, &resource); err == nil && resource.ID != "" {
+			return resource.ID
+		}
+	}
+	return ""
+}
+
+// auditResponseWriter keeps a copy of the response body while writing it
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// snapshotResponseWriter buffers a snapshot handler's response instead of sending it
+type snapshotResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *snapshotResponseWriter) Header() http.Header { return w.header }
+
+func (w *snapshotResponseWriter) Write(data []byte) (int, error) { return w.body.Write(data) }
+
+func (w *snapshotResponseWriter) WriteHeader(status int) { w.status = status }
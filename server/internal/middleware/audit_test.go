@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeAuditRecorder struct {
+	entries []*postgres.AuditLog
+}
+
+func (f *fakeAuditRecorder) Record(entry *postgres.AuditLog) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+type testRule struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// newAuditedRuleEngine serves an in-memory rule store for tenant-1 with audited changes
+func newAuditedRuleEngine(recorder AuditRecorder, rules map[string]*testRule) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("tenant_id", "tenant-1")
+		c.Set("user_id", "admin-1")
+	})
+
+	getRule := func(c *gin.Context) {
+		rule, ok := rules[c.Param("id")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "rule not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"rule": rule})
+	}
+	engine.GET("/rules/:id", getRule)
+	engine.POST("/rules", AuditMiddleware(recorder, "rule", nil), func(c *gin.Context) {
+		rule := &testRule{}
+		if err := c.ShouldBindJSON(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		rule.ID = "r2"
+		rules[rule.ID] = rule
+		c.JSON(http.StatusCreated, gin.H{"rule": rule})
+	})
+	engine.PUT("/rules/:id", AuditMiddleware(recorder, "rule", getRule), func(c *gin.Context) {
+		rule, ok := rules[c.Param("id")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "rule not found"})
+			return
+		}
+		if err := c.ShouldBindJSON(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"rule": rule})
+	})
+	engine.DELETE("/rules/:id", AuditMiddleware(recorder, "rule", getRule), func(c *gin.Context) {
+		delete(rules, c.Param("id"))
+		c.JSON(http.StatusOK, gin.H{"message": "Rule deleted successfully"})
+	})
+	return engine
+}
+
+func serveAudited(engine *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeAuditRule unmarshals an audit value holding a {"rule": ...} response
+func decodeAuditRule(t *testing.T, value *string) testRule {
+	t.Helper()
+	if value == nil {
+		t.Fatal("audit value is nil")
+	}
+	var resp struct {
+		Rule testRule `json:"rule"`
+	}
+	if err := json.Unmarshal([]byte(*value), &resp); err != nil {
+		t.Fatalf("audit value is not a rule response: %v", err)
+	}
+	return resp.Rule
+}
+
+func TestAuditMiddlewareRecordsUpdate(t *testing.T) {
+	recorder := &fakeAuditRecorder{}
+	engine := newAuditedRuleEngine(recorder, map[string]*testRule{
+		"r1": {ID: "r1", Name: "pricing objections", Enabled: true},
+	})
+
+	rec := serveAudited(engine, http.MethodPut, "/rules/r1", `{"name": "price objections", "enabled": false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(recorder.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(recorder.entries))
+	}
+	entry := recorder.entries[0]
+	if entry.TenantID != "tenant-1" || entry.UserID == nil || *entry.UserID != "admin-1" ||
+		entry.Action != "rule.updated" || entry.ResourceType != "rule" || entry.ResourceID == nil || *entry.ResourceID != "r1" {
+		t.Errorf("entry = %+v, want rule.updated of r1 by admin-1", entry)
+	}
+
+	before := decodeAuditRule(t, entry.OldValue)
+	if before != (testRule{ID: "r1", Name: "pricing objections", Enabled: true}) {
+		t.Errorf("old_value = %+v, want the rule before the update", before)
+	}
+	after := decodeAuditRule(t, entry.NewValue)
+	if after != (testRule{ID: "r1", Name: "price objections", Enabled: false}) {
+		t.Errorf("new_value = %+v, want the rule after the update", after)
+	}
+	if *entry.NewValue != rec.Body.String() {
+		t.Errorf("new_value = %s, want the response %s", *entry.NewValue, rec.Body.String())
+	}
+}
+
+func TestAuditMiddlewareRecordsCreateAndDelete(t *testing.T) {
+	recorder := &fakeAuditRecorder{}
+	rules := map[string]*testRule{}
+	engine := newAuditedRuleEngine(recorder, rules)
+
+	if rec := serveAudited(engine, http.MethodPost, "/rules", `{"name": "timing"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAudited(engine, http.MethodDelete, "/rules/r2", ""); rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(recorder.entries) != 2 {
+		t.Fatalf("audit entries = %d, want 2", len(recorder.entries))
+	}
+
+	created := recorder.entries[0]
+	if created.Action != "rule.created" || created.OldValue != nil || created.ResourceID == nil || *created.ResourceID != "r2" {
+		t.Errorf("create entry = %+v, want rule.created of r2 without old_value", created)
+	}
+	if rule := decodeAuditRule(t, created.NewValue); rule.Name != "timing" {
+		t.Errorf("create new_value = %+v", rule)
+	}
+
+	deleted := recorder.entries[1]
+	if deleted.Action != "rule.deleted" || deleted.NewValue != nil {
+		t.Errorf("delete entry = %+v, want rule.deleted without new_value", deleted)
+	}
+	if rule := decodeAuditRule(t, deleted.OldValue); rule.ID != "r2" || rule.Name != "timing" {
+		t.Errorf("delete old_value = %+v, want the deleted rule", rule)
+	}
+}
+
+func TestAuditMiddlewareSkipsFailedChanges(t *testing.T) {
+	recorder := &fakeAuditRecorder{}
+	engine := newAuditedRuleEngine(recorder, map[string]*testRule{"r1": {ID: "r1", Name: "pricing"}})
+
+	if rec := serveAudited(engine, http.MethodPut, "/rules/missing", `{"name": "x"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("PUT missing status = %d", rec.Code)
+	}
+	if rec := serveAudited(engine, http.MethodPut, "/rules/r1", `{"name": `); rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT malformed status = %d", rec.Code)
+	}
+	if len(recorder.entries) != 0 {
+		t.Errorf("audit entries = %+v, want none for failed requests", recorder.entries)
+	}
+
+	// Without a recorder the change goes through unaudited
+	engine = newAuditedRuleEngine(nil, map[string]*testRule{"r1": {ID: "r1"}})
+	if rec := serveAudited(engine, http.MethodPut, "/rules/r1", `{"name": "x"}`); rec.Code != http.StatusOK {
+		t.Errorf("PUT without recorder status = %d", rec.Code)
+	}
+}
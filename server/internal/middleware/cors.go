@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/auth"
+)
+
+// CORS modes
+const (
+	CORSModeStrict     = "strict"     // Only allowlisted origins receive CORS headers
+	CORSModePermissive = "permissive" // Any origin, answered with "*" and without credentials (development)
+)
+
+// OriginSource loads a tenant's additional allowed origins (see postgres.CORSConfigStorage)
+type OriginSource interface {
+	GetAllowedOrigins(tenantID string) ([]string, error)
+}
+
+// CORSConfig configures the CORS middleware
+type CORSConfig struct {
+	AllowedOrigins  []string      // Exact origins or wildcard subdomains like "*.example.com"
+	Mode            string        // strict | permissive
+	OriginSource    OriginSource  // Optional per-tenant origins, allowed on top of AllowedOrigins for that tenant only
+	RefreshInterval time.Duration // How often a tenant's origins are reloaded from OriginSource
+}
+
+// CORSConfigFromEnv builds a CORS config from CORS_ALLOWED_ORIGINS and CORS_MODE.
+// Mode defaults to strict.
+func CORSConfigFromEnv() CORSConfig {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	mode := strings.ToLower(os.Getenv("CORS_MODE"))
+	if mode != CORSModePermissive {
+		mode = CORSModeStrict
+	}
+
+	return CORSConfig{
+		AllowedOrigins:  origins,
+		Mode:            mode,
+		RefreshInterval: time.Minute,
+	}
+}
+
+// tenantOrigins is a tenant's cached allowlist
+type tenantOrigins struct {
+	origins  []string
+	loadedAt time.Time
+}
+
+// originMatcher checks origins against the static allowlist and the requesting tenant's allowlist
+type originMatcher struct {
+	config CORSConfig
+	mu     sync.RWMutex
+	tenant map[string]tenantOrigins
+}
+
+// allowed reports whether origin may call the API for tenantID. A tenant's origins only apply
+// to requests for that tenant; without a tenant only the static allowlist applies.
+func (m *originMatcher) allowed(tenantID, origin string) bool {
+	for _, pattern := range m.config.AllowedOrigins {
+		if MatchOrigin(pattern, origin) {
+			return true
+		}
+	}
+
+	for _, pattern := range m.tenantOrigins(tenantID) {
+		if MatchOrigin(pattern, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tenantOrigins returns the tenant's cached origins from OriginSource, reloading when stale
+func (m *originMatcher) tenantOrigins(tenantID string) []string {
+	if m.config.OriginSource == nil || tenantID == "" {
+		return nil
+	}
+
+	m.mu.RLock()
+	cached, ok := m.tenant[tenantID]
+	m.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < m.config.RefreshInterval {
+		return cached.origins
+	}
+
+	loaded, err := m.config.OriginSource.GetAllowedOrigins(tenantID)
+	if err != nil {
+		// Keep serving the previous list rather than locking the tenant out
+		log.Printf("[CORS] failed to load allowed origins tenant=%s: %v", tenantID, err)
+		return cached.origins
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	// Drop stale entries so unknown tenant IDs don't accumulate
+	for id, entry := range m.tenant {
+		if now.Sub(entry.loadedAt) >= m.config.RefreshInterval {
+			delete(m.tenant, id)
+		}
+	}
+	m.tenant[tenantID] = tenantOrigins{origins: loaded, loadedAt: now}
+	return loaded
+}
+
+// requestTenant returns the tenant a request is for. Real requests use only the tenant of a
+// valid access token. Preflights carry no Authorization header, so for them clients relying on
+// a tenant allowlist pass tenant_id in the query string.
+func requestTenant(c *gin.Context) string {
+	if c.Request.Method == http.MethodOptions {
+		return c.Query("tenant_id")
+	}
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); token != "" {
+		if claims, err := auth.ValidateToken(token); err == nil {
+			return claims.TenantID
+		}
+	}
+	return ""
+}
+
+// MatchOrigin reports whether origin matches pattern.
+// Patterns are exact origins ("https://app.example.com") or wildcard subdomains
+// ("*.example.com" or "https://*.example.com"). A wildcard does not match the apex domain.
+func MatchOrigin(pattern, origin string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(pattern)), "/")
+	origin = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+	if pattern == "" || origin == "" {
+		return false
+	}
+
+	if pattern == origin {
+		return true
+	}
+
+	idx := strings.Index(pattern, "*.")
+	if idx < 0 {
+		return false
+	}
+
+	// Scheme must match when the pattern specifies one
+	scheme := pattern[:idx]
+	if scheme != "" && !strings.HasPrefix(origin, scheme) {
+		return false
+	}
+
+	host := origin
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	suffix := pattern[idx+1:] // ".example.com"
+	return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+}
+
+// corsAllowHeaders and corsAllowMethods are sent to every allowed origin
+const (
+	corsAllowHeaders = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With"
+	corsAllowMethods = "POST, OPTIONS, GET, PUT, PATCH, DELETE"
+)
+
+// CORSMiddleware returns a gin middleware enforcing the CORS config.
+// In strict mode allowed origins are echoed back with credentials; blocked origins are logged
+// with their tenant and receive no CORS headers, and blocked preflights get 403. Permissive mode
+// answers every origin with "*" and no credentials.
+func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Minute
+	}
+	matcher := &originMatcher{config: config, tenant: make(map[string]tenantOrigins)}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+
+		if origin != "" && config.Mode == CORSModePermissive {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			c.Writer.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+		} else if origin != "" {
+			tenantID := requestTenant(c)
+			if !matcher.allowed(tenantID, origin) {
+				log.Printf("[CORS] WARN blocked origin=%s tenant=%s method=%s path=%s", origin, tenantID, c.Request.Method, c.Request.URL.Path)
+				if c.Request.Method == "OPTIONS" {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				c.Next()
+				return
+			}
+
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Add("Vary", "Origin")
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			c.Writer.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+		}
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ai-conversation-platform/internal/auth"
+)
+
+type fakeOriginSource map[string][]string
+
+func (f fakeOriginSource) GetAllowedOrigins(tenantID string) ([]string, error) {
+	return f[tenantID], nil
+}
+
+func serveCORS(config CORSConfig, method, path, origin, token string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(CORSMiddleware(config))
+	engine.GET("/api/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	req := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern, origin string
+		want            bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com/", "HTTPS://APP.EXAMPLE.COM", true},
+		{"https://app.example.com", "https://other.example.com", false},
+		{"*.example.com", "https://app.example.com", true},
+		{"*.example.com", "http://a.b.example.com", true},
+		{"*.example.com", "https://example.com", false}, // The apex isn't a subdomain
+		{"*.example.com", "https://evilexample.com", false},
+		{"*.example.com", "https://example.com.evil.io", false},
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "http://app.example.com", false},
+		{"", "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := MatchOrigin(tt.pattern, tt.origin); got != tt.want {
+			t.Errorf("MatchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestCORSConfigFromEnvDefaultsToStrict(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	t.Setenv("CORS_MODE", "")
+	if config := CORSConfigFromEnv(); config.Mode != CORSModeStrict {
+		t.Errorf("mode = %q, want strict without configuration", config.Mode)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, *.example.org")
+	t.Setenv("CORS_MODE", "Permissive")
+	config := CORSConfigFromEnv()
+	if config.Mode != CORSModePermissive || len(config.AllowedOrigins) != 2 || config.AllowedOrigins[1] != "*.example.org" {
+		t.Errorf("config = %+v, want permissive with both origins", config)
+	}
+}
+
+func TestCORSMiddlewareStrictMode(t *testing.T) {
+	config := CORSConfig{
+		Mode:           CORSModeStrict,
+		AllowedOrigins: []string{"https://app.example.com", "*.example.org"},
+		OriginSource: fakeOriginSource{
+			"tenant-1": {"https://shop.tenant-one.com"},
+			"tenant-2": {"https://shop.tenant-two.com"},
+		},
+		RefreshInterval: time.Minute,
+	}
+
+	// Static origins and wildcard subdomains are echoed back with credentials
+	for _, origin := range []string{"https://app.example.com", "https://widget.example.org"} {
+		rec := serveCORS(config, http.MethodGet, "/api/products", origin, "")
+		if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != origin || rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("%s: status %d headers %v, want the origin allowed", origin, rec.Code, rec.Header())
+		}
+	}
+
+	// Unknown origins get no CORS headers; their preflights are rejected
+	rec := serveCORS(config, http.MethodGet, "/api/products", "https://evil.io", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("blocked GET = %d %v, want no CORS headers", rec.Code, rec.Header())
+	}
+	if rec := serveCORS(config, http.MethodOptions, "/api/products", "https://evil.io", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("blocked preflight = %d, want 403", rec.Code)
+	}
+	if rec := serveCORS(config, http.MethodOptions, "/api/products", "https://app.example.com", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("allowed preflight = %d, want 204", rec.Code)
+	}
+
+	// A tenant's origins only apply to requests for that tenant
+	tenantOrigin := "https://shop.tenant-one.com"
+	if rec := serveCORS(config, http.MethodOptions, "/api/products?tenant_id=tenant-1", tenantOrigin, ""); rec.Header().Get("Access-Control-Allow-Origin") != tenantOrigin {
+		t.Errorf("tenant-1 preflight headers = %v, want its origin allowed", rec.Header())
+	}
+	if rec := serveCORS(config, http.MethodOptions, "/api/products?tenant_id=tenant-2", tenantOrigin, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("tenant-2 preflight = %d, want tenant-1's origin blocked", rec.Code)
+	}
+	if rec := serveCORS(config, http.MethodOptions, "/api/products", tenantOrigin, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("preflight without tenant = %d, want tenant origins not applied", rec.Code)
+	}
+
+	// Real requests only trust the token's tenant, never the query string
+	if rec := serveCORS(config, http.MethodGet, "/api/products?tenant_id=tenant-1", tenantOrigin, ""); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("GET with only a tenant_id query headers = %v, want tenant origins not applied", rec.Header())
+	}
+	if rec := serveCORS(config, http.MethodGet, "/api/products?tenant_id=tenant-1", tenantOrigin, "not-a-token"); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("GET with an invalid token headers = %v, want tenant origins not applied", rec.Header())
+	}
+
+	// The token's tenant wins over the query string
+	token, err := auth.GenerateToken("agent-1", "tenant-2", "agent")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if rec := serveCORS(config, http.MethodGet, "/api/products?tenant_id=tenant-1", tenantOrigin, token); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("tenant-2 token headers = %v, want tenant-1's origin blocked", rec.Header())
+	}
+	if rec := serveCORS(config, http.MethodGet, "/api/products", "https://shop.tenant-two.com", token); rec.Header().Get("Access-Control-Allow-Origin") != "https://shop.tenant-two.com" {
+		t.Errorf("tenant-2 token headers = %v, want its origin allowed", rec.Header())
+	}
+}
+
+func TestCORSMiddlewarePermissiveMode(t *testing.T) {
+	config := CORSConfig{Mode: CORSModePermissive}
+
+	rec := serveCORS(config, http.MethodGet, "/api/products", "https://anything.dev", "")
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none with *", rec.Header().Get("Access-Control-Allow-Credentials"))
+	}
+	if rec := serveCORS(config, http.MethodOptions, "/api/products", "https://anything.dev", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("preflight = %d, want 204", rec.Code)
+	}
+	if rec := serveCORS(config, http.MethodGet, "/api/products", "", ""); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("same-origin request headers = %v, want no CORS headers", rec.Header())
+	}
+}
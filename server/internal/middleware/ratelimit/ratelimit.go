@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults used when the environment doesn't configure a quota
+const (
+	defaultMaxRequestsPerMinute = 300
+	defaultBurstSize            = 60
+	defaultMaxAICallsPerMinute  = 60
+)
+
+// ErrAIQuotaExceeded is returned for Gemini calls over a tenant's AI quota
+var ErrAIQuotaExceeded = errors.New("tenant AI quota exceeded")
+
+// Config configures the per-tenant quotas
+type Config struct {
+	MaxRequestsPerMinute int // Sustained API requests per tenant
+	BurstSize            int // Requests a tenant can make at once before being throttled
+	MaxAICallsPerMinute  int // Gemini calls per tenant, limited separately from requests
+}
+
+// ConfigFromEnv reads RATE_LIMIT_REQUESTS_PER_MINUTE, RATE_LIMIT_BURST and
+// RATE_LIMIT_AI_CALLS_PER_MINUTE. Missing or non-positive values use the defaults.
+func ConfigFromEnv() Config {
+	return Config{
+		MaxRequestsPerMinute: positiveEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", defaultMaxRequestsPerMinute),
+		BurstSize:            positiveEnv("RATE_LIMIT_BURST", defaultBurstSize),
+		MaxAICallsPerMinute:  positiveEnv("RATE_LIMIT_AI_CALLS_PER_MINUTE", defaultMaxAICallsPerMinute),
+	}
+}
+
+func positiveEnv(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// bucket is a token bucket refilled continuously, so a tenant's allowance recovers over a
+// sliding minute instead of resetting at fixed window boundaries
+type bucket struct {
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+// take spends a token if one is available, otherwise it returns how long until one is
+func (b *bucket) take(now time.Time, perSecond, capacity float64) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	return false, wait
+}
+
+// TenantRateLimiter throttles API requests and Gemini calls per tenant. Tenants have
+// independent buckets, so one busy tenant can't use up another's quota.
+type TenantRateLimiter struct {
+	config   Config
+	requests sync.Map // tenant_id -> *bucket
+	aiCalls  sync.Map // tenant_id -> *bucket
+	now      func() time.Time
+}
+
+// NewTenantRateLimiter creates a rate limiter. Non-positive config values use the defaults.
+func NewTenantRateLimiter(config Config) *TenantRateLimiter {
+	if config.MaxRequestsPerMinute <= 0 {
+		config.MaxRequestsPerMinute = defaultMaxRequestsPerMinute
+	}
+	if config.BurstSize <= 0 {
+		config.BurstSize = defaultBurstSize
+	}
+	if config.MaxAICallsPerMinute <= 0 {
+		config.MaxAICallsPerMinute = defaultMaxAICallsPerMinute
+	}
+	return &TenantRateLimiter{config: config, now: time.Now}
+}
+
+// Config returns the limiter's quotas
+func (l *TenantRateLimiter) Config() Config {
+	return l.config
+}
+
+// Allow counts a request of the tenant, returning false and the time to wait when over quota
+func (l *TenantRateLimiter) Allow(tenantID string) (bool, time.Duration) {
+	return l.take(&l.requests, tenantID, l.config.MaxRequestsPerMinute, l.config.BurstSize)
+}
+
+// AllowAICall counts a Gemini call of the tenant, returning false and the time to wait when over quota
+func (l *TenantRateLimiter) AllowAICall(tenantID string) (bool, time.Duration) {
+	return l.take(&l.aiCalls, tenantID, l.config.MaxAICallsPerMinute, l.config.MaxAICallsPerMinute)
+}
+
+func (l *TenantRateLimiter) take(buckets *sync.Map, tenantID string, perMinute, capacity int) (bool, time.Duration) {
+	now := l.now()
+	value, _ := buckets.LoadOrStore(tenantID, &bucket{tokens: float64(capacity), updated: now})
+	return value.(*bucket).take(now, float64(perMinute)/60, float64(capacity))
+}
+
+// AIQuota counts a tenant's Gemini calls; it satisfies ai.CallQuota
+type AIQuota struct {
+	limiter  *TenantRateLimiter
+	tenantID string
+}
+
+// AIQuota returns the AI quota of a tenant, to be attached to its requests' context
+func (l *TenantRateLimiter) AIQuota(tenantID string) *AIQuota {
+	return &AIQuota{limiter: l, tenantID: tenantID}
+}
+
+// AllowCall counts a Gemini call, returning ErrAIQuotaExceeded when over quota
+func (q *AIQuota) AllowCall() error {
+	allowed, wait := q.limiter.AllowAICall(q.tenantID)
+	if allowed {
+		return nil
+	}
+	log.Printf("[RATE_LIMIT] WARN AI quota exceeded tenant=%s", q.tenantID)
+	return fmt.Errorf("%w, retry in %ds", ErrAIQuotaExceeded, retryAfterSeconds(wait))
+}
+
+// Middleware rejects requests over the tenant's quota with 429 and a Retry-After header.
+// tenantOf identifies the tenant of a request; requests without one aren't limited here.
+func (l *TenantRateLimiter) Middleware(tenantOf func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := tenantOf(c)
+		if tenantID == "" {
+			c.Next()
+			return
+		}
+
+		allowed, wait := l.Allow(tenantID)
+		if !allowed {
+			log.Printf("[RATE_LIMIT] WARN request quota exceeded tenant=%s path=%s", tenantID, c.Request.URL.Path)
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds, as sent in Retry-After
+func retryAfterSeconds(wait time.Duration) int {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
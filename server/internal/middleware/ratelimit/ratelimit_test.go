@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestLimiter returns a limiter whose clock only moves when advanced
+func newTestLimiter(config Config) (*TenantRateLimiter, func(time.Duration)) {
+	limiter := NewTenantRateLimiter(config)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	return limiter, func(d time.Duration) { now = now.Add(d) }
+}
+
+func serveLimited(limiter *TenantRateLimiter, tenantID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(limiter.Middleware(func(c *gin.Context) string { return c.GetHeader("X-Tenant") }))
+	engine.GET("/api/conversations", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/conversations", nil)
+	req.Header.Set("X-Tenant", tenantID)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareReturns429OverQuota(t *testing.T) {
+	limiter, advance := newTestLimiter(Config{MaxRequestsPerMinute: 60, BurstSize: 3})
+
+	for i := 0; i < 3; i++ {
+		if rec := serveLimited(limiter, "tenant-1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200 within the burst", i+1, rec.Code)
+		}
+	}
+	rec := serveLimited(limiter, "tenant-1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 after the burst", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1 (one request per second)", got)
+	}
+
+	// The allowance recovers continuously rather than at the next minute
+	advance(time.Second)
+	if rec := serveLimited(limiter, "tenant-1"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 once a token was refilled", rec.Code)
+	}
+}
+
+func TestMiddlewareTenantsHaveIndependentCounters(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{MaxRequestsPerMinute: 60, BurstSize: 1})
+
+	if rec := serveLimited(limiter, "tenant-1"); rec.Code != http.StatusOK {
+		t.Fatalf("tenant-1 status = %d, want 200", rec.Code)
+	}
+	if rec := serveLimited(limiter, "tenant-1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("tenant-1 status = %d, want 429", rec.Code)
+	}
+	if rec := serveLimited(limiter, "tenant-2"); rec.Code != http.StatusOK {
+		t.Errorf("tenant-2 status = %d, want 200 while tenant-1 is throttled", rec.Code)
+	}
+}
+
+func TestMiddlewareSkipsRequestsWithoutTenant(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{MaxRequestsPerMinute: 60, BurstSize: 1})
+	for i := 0; i < 3; i++ {
+		if rec := serveLimited(limiter, ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want unauthenticated requests left to auth", i+1, rec.Code)
+		}
+	}
+}
+
+func TestAIQuotaIsSeparateFromRequests(t *testing.T) {
+	limiter, advance := newTestLimiter(Config{MaxRequestsPerMinute: 60, BurstSize: 1, MaxAICallsPerMinute: 2})
+	quota := limiter.AIQuota("tenant-1")
+
+	for i := 0; i < 2; i++ {
+		if err := quota.AllowCall(); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	if err := quota.AllowCall(); !errors.Is(err, ErrAIQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrAIQuotaExceeded", err)
+	}
+	if err := limiter.AIQuota("tenant-2").AllowCall(); err != nil {
+		t.Errorf("tenant-2: %v, want its own AI quota", err)
+	}
+	if allowed, _ := limiter.Allow("tenant-1"); !allowed {
+		t.Error("request quota was used up by AI calls")
+	}
+
+	advance(30 * time.Second)
+	if err := quota.AllowCall(); err != nil {
+		t.Errorf("after 30s: %v, want a call refilled", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_REQUESTS_PER_MINUTE", "120")
+	t.Setenv("RATE_LIMIT_BURST", "not-a-number")
+	t.Setenv("RATE_LIMIT_AI_CALLS_PER_MINUTE", "0")
+
+	want := Config{MaxRequestsPerMinute: 120, BurstSize: defaultBurstSize, MaxAICallsPerMinute: defaultMaxAICallsPerMinute}
+	if got := ConfigFromEnv(); got != want {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuperAdminMiddleware authenticates platform operators with the SUPER_ADMIN_TOKEN bearer token.
+// It never accepts tenant JWTs; when SUPER_ADMIN_TOKEN is unset the routes are disabled.
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("SUPER_ADMIN_TOKEN")
+		if expected == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			c.Abort()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid super admin token"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -9,6 +9,7 @@ type AutoReplyGlobalConfig struct {
 	TenantID           string    `json:"tenant_id"`
 	Enabled            bool      `json:"enabled"`
 	ConfidenceThreshold float64  `json:"confidence_threshold"` // 0.0 - 1.0
+	OutOfHoursMessage  string    `json:"out_of_hours_message"` // Sent outside business hours instead of an auto-reply; empty sends nothing
 	UpdatedAt          time.Time `json:"updated_at"`
 }
 
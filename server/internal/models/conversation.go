@@ -11,11 +11,61 @@ type Conversation struct {
 	CustomerID   *string   `json:"customer_id,omitempty"`   // Customer user ID (null for agent-initiated)
 	CustomerEmail *string  `json:"customer_email,omitempty"` // Customer email (populated in queries)
 	ProductID    *string   `json:"product_id,omitempty"`     // Optional product context
+	AssignedAgentID *string `json:"assigned_agent_id,omitempty"` // Agent responsible for the conversation
 	Status       string    `json:"status"`                   // active, closed, archived
+	ResolutionType *string `json:"resolution_type,omitempty"` // How a closed conversation ended (see Resolution* constants)
+	OverrideLanguage *string `json:"override_language,omitempty"` // Agent-set ISO 639-1 code; takes precedence over per-message detection
+	TranscriptSentAt *time.Time `json:"transcript_sent_at,omitempty"` // When the transcript was emailed to the customer
+	ClosedAt     *time.Time `json:"closed_at,omitempty"` // When the conversation was closed
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // Set when soft-deleted; purged after RETENTION_DAYS
+	DeletedBy    *string    `json:"deleted_by,omitempty"`
+	Tags         []Tag      `json:"tags,omitempty"` // Populated by GetConversation
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// Tag is a tenant-defined label agents attach to conversations (e.g. "hot-lead", "escalated")
+type Tag struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color,omitempty"` // Hex color like #ff8800
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConversationNote is an agent's internal note on a conversation. Notes are never shown to
+// customers or included in AI prompts.
+type ConversationNote struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"` // The agent or admin who wrote it
+	Content        string    `json:"content"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Conversation resolution types
+const (
+	ResolutionDealWon   = "deal_won"
+	ResolutionDealLost  = "deal_lost"
+	ResolutionResolved  = "resolved"  // Support issue resolved, no deal involved
+	ResolutionAbandoned = "abandoned" // Customer stopped responding
+	ResolutionMerged    = "merged"    // Merged into another conversation with the same customer
+)
+
+// TransferEvent records a conversation being handed from one agent to another
+type TransferEvent struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	FromAgentID    *string   `json:"from_agent_id,omitempty"` // Null when the conversation was unassigned
+	ToAgentID      string    `json:"to_agent_id"`
+	Reason         string    `json:"reason,omitempty"`
+	TransferredBy  string    `json:"transferred_by"`
+	TransferredAt  time.Time `json:"transferred_at"`
+}
+
 // Message represents a single message in a conversation
 type Message struct {
 	ID             string    `json:"id"`
@@ -24,20 +74,49 @@ type Message struct {
 	Content        string    `json:"content"`
 	Channel        string    `json:"channel"` // "web"
 	Language       string    `json:"language"`
+	LanguageConfidence float32 `json:"language_confidence"` // Detection confidence (0-1); 0 when the language is unknown
 	Timestamp      time.Time `json:"timestamp"`
 	CreatedAt      time.Time `json:"created_at"`
+	IsAutoReply    bool      `json:"is_auto_reply,omitempty"` // Sent by auto-reply rather than an agent
+	SuggestionConfidence *float64 `json:"suggestion_confidence,omitempty"` // Confidence of the suggestion an auto-reply was sent from
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // Set when soft-deleted (GDPR, abuse, error)
+	DeletedBy      *string    `json:"deleted_by,omitempty"`
+	ReadBy         []*MessageRead `json:"read_by,omitempty"` // Agent read receipts, populated for agents and admins
+}
+
+// MessageRead is a read receipt left by an agent or admin on a message
+type MessageRead struct {
+	ReaderID string    `json:"reader_id"`
+	ReadAt   time.Time `json:"read_at"`
 }
 
 // ConversationMetadata stores AI analysis results separately from messages
 type ConversationMetadata struct {
 	ID             string    `json:"id"`
 	ConversationID string    `json:"conversation_id"`
-	Intent         string    `json:"intent"`         // "buying", "support", "complaint"
+	Intent         string    `json:"intent"`         // "buying", "support", "complaint" or a tenant-configured intent
 	IntentScore    float64   `json:"intent_score"`   // 0-1
 	Sentiment      string    `json:"sentiment"`      // "positive", "neutral", "negative"
-	SentimentScore float64   `json:"sentiment_score"` // 0-1
+	SentimentScore float64   `json:"sentiment_score"` // 0-1, normalized across models
+	SentimentModel string    `json:"sentiment_model,omitempty"` // model that produced the sentiment score
 	Emotions       []string  `json:"emotions"`       // ["frustration", "urgency", etc.]
 	Objections     []string  `json:"objections"`     // ["price", "trust", "delivery", "competitor"]
+	ComplexityScore float64  `json:"complexity_score"` // 1-10, 0 when not yet scored
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
+// AnalysisHistoryEntry is one analysis of a conversation. ConversationMetadata only keeps the
+// latest; the history keeps every run, so agents can see how intent and sentiment evolved.
+type AnalysisHistoryEntry struct {
+	ID                     string    `json:"id"`
+	ConversationID         string    `json:"conversation_id"`
+	Intent                 string    `json:"intent"`
+	IntentScore            float64   `json:"intent_score"`
+	Sentiment              string    `json:"sentiment"`
+	SentimentScore         float64   `json:"sentiment_score"`
+	Emotions               []string  `json:"emotions"`
+	Objections             []string  `json:"objections"`
+	AnalyzedAt             time.Time `json:"analyzed_at"`
+	MessageCountAtAnalysis int       `json:"message_count_at_analysis"` // Messages the analysis was based on
+}
+
@@ -10,6 +10,7 @@ type CustomerMemory struct {
 	TenantID          string    `json:"tenant_id"`
 	CustomerID        string    `json:"customer_id"`
 	PreferredLanguage string    `json:"preferred_language"`
+	LanguageOverride  bool      `json:"language_override"` // PreferredLanguage was set by an agent; auto-detection must not change it
 	PricingSensitivity string   `json:"pricing_sensitivity"` // "high", "medium", "low"
 	ProductInterests  []string  `json:"product_interests"`
 	PastObjections    []string  `json:"past_objections"`
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Pricing suggestion statuses
+const (
+	PricingStatusPending  = "pending"
+	PricingStatusApproved = "approved"
+	PricingStatusRejected = "rejected"
+)
+
+// PricingSuggestion is an AI-generated pricing range awaiting admin review
+type PricingSuggestion struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	TenantID       string     `json:"tenant_id"`
+	MinPrice       float64    `json:"min_price"`
+	MaxPrice       float64    `json:"max_price"`
+	Confidence     float64    `json:"confidence"`
+	Reasoning      string     `json:"reasoning"`
+	Status         string     `json:"status"` // pending, approved, rejected
+	ApprovedBy     *string    `json:"approved_by,omitempty"`
+	ApprovedAt     *time.Time `json:"approved_at,omitempty"`
+	RejectedBy     *string    `json:"rejected_by,omitempty"`
+	RejectedAt     *time.Time `json:"rejected_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
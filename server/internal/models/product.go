@@ -10,15 +10,42 @@ type Product struct {
 	TenantID        string    `json:"tenant_id"`
 	Name            string    `json:"name"`
 	Description     string    `json:"description"`
-	Category        string    `json:"category"`
+	Category        string    `json:"category"`      // Free-text category, kept for backward compatibility
+	CategoryID      *string   `json:"category_id"`   // Entry in the tenant's category tree
+	CategoryPath    string    `json:"category_path,omitempty"` // e.g. "Automation > WhatsApp", loaded with the product
 	Price           float64   `json:"price"`
 	PriceCurrency   string    `json:"price_currency"` // "INR", "USD", etc.
 	Features        []string  `json:"features"`       // JSON array
 	Limitations     []string  `json:"limitations"`   // JSON array
 	TargetAudience  string    `json:"target_audience"`
 	CommonQuestions []string  `json:"common_questions"` // JSON array
+	Variants        []ProductVariant `json:"variants,omitempty"` // Pricing tiers, loaded separately from the product row
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// ProductVariant is a pricing tier of a product, e.g. a monthly or annual plan
+type ProductVariant struct {
+	ID            string    `json:"id"`
+	ProductID     string    `json:"product_id"`
+	TenantID      string    `json:"tenant_id"`
+	Name          string    `json:"name"`
+	Price         float64   `json:"price"`
+	PriceCurrency string    `json:"price_currency"`
+	Description   string    `json:"description"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
 
+// ProductCategory is a node in a tenant's product category tree
+type ProductCategory struct {
+	ID          string    `json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	Name        string    `json:"name"`
+	ParentID    *string   `json:"parent_id"` // nil for top-level categories
+	Description string    `json:"description"`
+	Path        string    `json:"path,omitempty"` // Names from the root down, e.g. "Automation > WhatsApp"
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -12,10 +12,12 @@ type Rule struct {
 	Description string    `json:"description"`
 	Type        string    `json:"type"` // "block", "correct", "flag"
 	Pattern     string    `json:"pattern"` // regex or keyword pattern
-	Action      string    `json:"action"` // "block", "auto_correct", "flag"
+	Action      string    `json:"action"` // "block", "auto_correct", "flag" or "escalate"
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-
+// RuleActionEscalate rules match customer messages instead of AI output: a match escalates the
+// conversation to a human (see analytics.AnalyticsService.OnAnalysisComplete)
+const RuleActionEscalate = "escalate"
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Suggestion feedback actions
+const (
+	SuggestionFeedbackAccepted = "accepted"
+	SuggestionFeedbackRejected = "rejected"
+	SuggestionFeedbackEdited   = "edited" // Used after the agent changed the text
+)
+
+// SuggestionFeedback records what an agent did with a reply suggestion
+type SuggestionFeedback struct {
+	ID             string    `json:"id"`
+	SuggestionID   string    `json:"suggestion_id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id,omitempty"`
+	Action         string    `json:"action"`                // accepted, rejected or edited
+	EditedText     *string   `json:"edited_text,omitempty"` // Text the agent sent instead, for edited suggestions
+	FeedbackAt     time.Time `json:"feedback_at"`
+}
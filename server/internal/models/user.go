@@ -8,9 +8,10 @@ import (
 type UserRole string
 
 const (
-	RoleCustomer UserRole = "customer"
-	RoleAgent    UserRole = "agent"
-	RoleAdmin    UserRole = "admin"
+	RoleCustomer   UserRole = "customer"
+	RoleAgent      UserRole = "agent"
+	RoleAdmin      UserRole = "admin"
+	RoleSuperAdmin UserRole = "super_admin" // Platform operator; not assignable through the admin API
 )
 
 // User represents a user in the system
@@ -22,6 +23,8 @@ type User struct {
 	Role      UserRole  `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"` // Soft delete; deactivated users cannot log in
+	ConversationCount *int   `json:"conversation_count,omitempty"` // Only populated by user listings
 }
 
 
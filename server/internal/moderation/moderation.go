@@ -0,0 +1,248 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Moderation actions, from least to most severe
+const (
+	ActionAllow = "allow"
+	ActionFlag  = "flag"  // Stored, with a moderation event
+	ActionBlock = "block" // Rejected before storage
+)
+
+// Moderation list types; a list's matches get the action of the same name
+const (
+	ListTypeBlock = "block"
+	ListTypeFlag  = "flag"
+)
+
+// Moderation categories
+const (
+	CategoryEmail         = "pii_email"
+	CategoryCardNumber    = "pii_card_number"
+	CategoryCustomPattern = "custom_pattern" // A tenant moderation list entry
+)
+
+// maxPatternLength caps the length of a moderation list pattern
+const maxPatternLength = 500
+
+// ErrContentModerated is returned when a message is blocked by moderation
+var ErrContentModerated = errors.New("message blocked by content moderation")
+
+// ErrInvalidListEntry is returned for moderation list entries that can't be used
+var ErrInvalidListEntry = errors.New("invalid moderation list entry")
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// ModerationResult is the outcome of moderating a piece of text
+type ModerationResult struct {
+	Action     string   `json:"action"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Flagged reports whether the text matched anything
+func (r ModerationResult) Flagged() bool {
+	return r.Action == ActionFlag || r.Action == ActionBlock
+}
+
+// Moderator screens text before it is stored
+type Moderator interface {
+	Moderate(text string) (ModerationResult, error)
+}
+
+// keywordRule is a compiled pattern and what a match means
+type keywordRule struct {
+	category string
+	action   string
+	pattern  *regexp.Regexp
+}
+
+// KeywordModerator screens text for PII and a tenant's moderation list patterns. PII is flagged;
+// list patterns flag or block according to their list.
+type KeywordModerator struct {
+	rules []keywordRule
+}
+
+// NewKeywordModerator creates a moderator from a tenant's moderation list entries on top of the
+// built-in PII checks. Entries that don't compile are logged and skipped.
+func NewKeywordModerator(entries []*postgres.ModerationListEntry) *KeywordModerator {
+	m := &KeywordModerator{rules: []keywordRule{
+		{category: CategoryEmail, action: ActionFlag, pattern: emailPattern},
+		{category: CategoryCardNumber, action: ActionFlag, pattern: cardNumberPattern},
+	}}
+	for _, entry := range entries {
+		pattern, err := compilePattern(entry.Pattern)
+		if err != nil {
+			log.Printf("[MODERATION] skipping invalid pattern id=%s tenant=%s: %v", entry.ID, entry.TenantID, err)
+			continue
+		}
+		action := ActionFlag
+		if entry.ListType == ListTypeBlock {
+			action = ActionBlock
+		}
+		m.rules = append(m.rules, keywordRule{category: CategoryCustomPattern, action: action, pattern: pattern})
+	}
+	return m
+}
+
+// Moderate checks text against the built-in PII checks and the tenant's patterns. The result
+// takes the most severe action matched.
+func (m *KeywordModerator) Moderate(text string) (ModerationResult, error) {
+	result := ModerationResult{Action: ActionAllow}
+	if strings.TrimSpace(text) == "" {
+		return result, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range m.rules {
+		if !rule.matches(text) {
+			continue
+		}
+		if !seen[rule.category] {
+			seen[rule.category] = true
+			result.Categories = append(result.Categories, rule.category)
+		}
+		if severity(rule.action) > severity(result.Action) {
+			result.Action = rule.action
+		}
+	}
+	return result, nil
+}
+
+func (r keywordRule) matches(text string) bool {
+	if r.category != CategoryCardNumber {
+		return r.pattern.MatchString(text)
+	}
+	// Only digit runs that pass the Luhn check are card numbers, not order or phone numbers
+	for _, match := range r.pattern.FindAllString(text, -1) {
+		if luhnValid(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		digit := int(s[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func severity(action string) int {
+	switch action {
+	case ActionBlock:
+		return 2
+	case ActionFlag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compilePattern compiles a moderation list pattern as a case-insensitive regular expression
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// ValidateListEntry checks a moderation list entry's list type and pattern
+func ValidateListEntry(listType, pattern string) error {
+	if listType != ListTypeBlock && listType != ListTypeFlag {
+		return fmt.Errorf("%w: list_type must be %q or %q", ErrInvalidListEntry, ListTypeBlock, ListTypeFlag)
+	}
+	if strings.TrimSpace(pattern) == "" || len(pattern) > maxPatternLength {
+		return fmt.Errorf("%w: pattern must be 1-%d characters", ErrInvalidListEntry, maxPatternLength)
+	}
+	if _, err := compilePattern(pattern); err != nil {
+		return fmt.Errorf("%w: pattern is not a valid regular expression: %v", ErrInvalidListEntry, err)
+	}
+	return nil
+}
+
+// ListStore lists a tenant's moderation list entries (see postgres.ModerationStorage)
+type ListStore interface {
+	ListModerationPatterns(tenantID string) ([]*postgres.ModerationListEntry, error)
+}
+
+// EventRecorder stores moderation events (see postgres.ModerationStorage)
+type EventRecorder interface {
+	RecordModerationEvent(event *postgres.ModerationEvent) error
+}
+
+// Service moderates tenants' messages with their moderation lists and records what it flags
+// or blocks
+type Service struct {
+	lists  ListStore
+	events EventRecorder
+}
+
+// NewService creates a moderation service
+func NewService(lists ListStore, events EventRecorder) *Service {
+	return &Service{lists: lists, events: events}
+}
+
+// TenantModerator loads the tenant's moderation lists and creates a moderator. If the lists
+// cannot be loaded, only the built-in PII checks apply.
+func (s *Service) TenantModerator(tenantID string) Moderator {
+	var entries []*postgres.ModerationListEntry
+	if s.lists != nil {
+		loaded, err := s.lists.ListModerationPatterns(tenantID)
+		if err != nil {
+			log.Printf("[MODERATION] failed to load moderation lists tenant=%s, using PII checks only: %v", tenantID, err)
+		} else {
+			entries = loaded
+		}
+	}
+	return NewKeywordModerator(entries)
+}
+
+// ModerateMessage moderates a message before it is stored and records a moderation event when
+// it is flagged or blocked. Content is never logged.
+func (s *Service) ModerateMessage(tenantID string, message *models.Message) (ModerationResult, error) {
+	result, err := s.TenantModerator(tenantID).Moderate(message.Content)
+	if err != nil || !result.Flagged() {
+		return result, err
+	}
+
+	log.Printf("[MODERATION] message %s by moderation message_id=%s conversation=%s categories=%s",
+		result.Action, message.ID, message.ConversationID, strings.Join(result.Categories, ","))
+
+	if s.events != nil {
+		event := &postgres.ModerationEvent{
+			TenantID:       tenantID,
+			ConversationID: message.ConversationID,
+			MessageID:      message.ID,
+			Sender:         message.Sender,
+			Action:         result.Action,
+			Categories:     result.Categories,
+		}
+		if err := s.events.RecordModerationEvent(event); err != nil {
+			log.Printf("[MODERATION] failed to record moderation event message_id=%s: %v", message.ID, err)
+		}
+	}
+	return result, nil
+}
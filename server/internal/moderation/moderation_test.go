@@ -0,0 +1,121 @@
+package moderation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func TestKeywordModeratorDetectsPII(t *testing.T) {
+	m := NewKeywordModerator(nil)
+	tests := []struct {
+		text           string
+		wantAction     string
+		wantCategories []string
+	}{
+		{"You can reach me at jane.doe+sales@example.co.uk", ActionFlag, []string{CategoryEmail}},
+		{"My card is 4111 1111 1111 1111", ActionFlag, []string{CategoryCardNumber}},
+		{"Email JANE@EXAMPLE.COM, card 4111-1111-1111-1111", ActionFlag, []string{CategoryEmail, CategoryCardNumber}},
+		{"Order 1234 5678 9012 3456 hasn't arrived", ActionAllow, nil}, // Fails the Luhn check
+		{"I'm at jane at example dot com", ActionAllow, nil},
+		{"How much is the pro plan?", ActionAllow, nil},
+		{"   ", ActionAllow, nil},
+	}
+
+	for _, tt := range tests {
+		result, err := m.Moderate(tt.text)
+		if err != nil {
+			t.Fatalf("Moderate(%q): %v", tt.text, err)
+		}
+		if result.Action != tt.wantAction || !reflect.DeepEqual(result.Categories, tt.wantCategories) {
+			t.Errorf("Moderate(%q) = %+v, want %s %v", tt.text, result, tt.wantAction, tt.wantCategories)
+		}
+	}
+}
+
+func TestKeywordModeratorAppliesTenantLists(t *testing.T) {
+	m := NewKeywordModerator([]*postgres.ModerationListEntry{
+		{ID: "1", ListType: ListTypeBlock, Pattern: `crypto\s+giveaway`},
+		{ID: "2", ListType: ListTypeFlag, Pattern: `\bacme corp\b`},
+		{ID: "3", ListType: ListTypeBlock, Pattern: `(unclosed`}, // Skipped
+	})
+	tests := []struct {
+		text           string
+		wantAction     string
+		wantCategories []string
+	}{
+		{"Join our CRYPTO   Giveaway now", ActionBlock, []string{CategoryCustomPattern}},
+		{"Acme Corp quoted us less", ActionFlag, []string{CategoryCustomPattern}},
+		{"acme corp crypto giveaway at win@example.com", ActionBlock, []string{CategoryEmail, CategoryCustomPattern}},
+		{"an (unclosed bracket", ActionAllow, nil},
+	}
+
+	for _, tt := range tests {
+		result, _ := m.Moderate(tt.text)
+		if result.Action != tt.wantAction || !reflect.DeepEqual(result.Categories, tt.wantCategories) {
+			t.Errorf("Moderate(%q) = %+v, want %s %v", tt.text, result, tt.wantAction, tt.wantCategories)
+		}
+	}
+}
+
+func TestValidateListEntry(t *testing.T) {
+	if err := ValidateListEntry(ListTypeBlock, `crypto\s+giveaway`); err != nil {
+		t.Errorf("valid entry: %v", err)
+	}
+	for name, entry := range map[string][2]string{
+		"unknown list type": {"allow", "refund"},
+		"empty pattern":     {ListTypeFlag, " "},
+		"invalid regex":     {ListTypeBlock, "(unclosed"},
+	} {
+		if err := ValidateListEntry(entry[0], entry[1]); !errors.Is(err, ErrInvalidListEntry) {
+			t.Errorf("%s: err = %v, want ErrInvalidListEntry", name, err)
+		}
+	}
+}
+
+type fakeModerationStore struct {
+	entries []*postgres.ModerationListEntry
+	listErr error
+	events  []*postgres.ModerationEvent
+}
+
+func (f *fakeModerationStore) ListModerationPatterns(tenantID string) ([]*postgres.ModerationListEntry, error) {
+	return f.entries, f.listErr
+}
+
+func (f *fakeModerationStore) RecordModerationEvent(event *postgres.ModerationEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func TestServiceModerateMessageRecordsEvents(t *testing.T) {
+	store := &fakeModerationStore{entries: []*postgres.ModerationListEntry{{ListType: ListTypeBlock, Pattern: "free money"}}}
+	s := NewService(store, store)
+
+	allowed, _ := s.ModerateMessage("tenant-1", &models.Message{ID: "m1", ConversationID: "c1", Sender: "customer", Content: "What does it cost?"})
+	if allowed.Flagged() || len(store.events) != 0 {
+		t.Fatalf("clean message = %+v with %d events, want allowed without an event", allowed, len(store.events))
+	}
+
+	blocked, _ := s.ModerateMessage("tenant-1", &models.Message{ID: "m2", ConversationID: "c1", Sender: "customer", Content: "FREE MONEY here"})
+	if blocked.Action != ActionBlock {
+		t.Fatalf("result = %+v, want block", blocked)
+	}
+	if len(store.events) != 1 {
+		t.Fatalf("events = %d, want 1", len(store.events))
+	}
+	event := store.events[0]
+	if event.TenantID != "tenant-1" || event.MessageID != "m2" || event.Action != ActionBlock || event.Categories[0] != CategoryCustomPattern {
+		t.Errorf("event = %+v, want the blocked message", event)
+	}
+
+	// Without the tenant's lists, PII is still flagged
+	store.listErr = errors.New("db down")
+	flagged, _ := s.ModerateMessage("tenant-1", &models.Message{ID: "m3", Content: "free money, mail me at a@b.io"})
+	if flagged.Action != ActionFlag {
+		t.Errorf("result when lists fail = %+v, want the email flagged", flagged)
+	}
+}
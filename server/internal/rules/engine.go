@@ -1,12 +1,15 @@
 package rules
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"regexp/syntax"
 	"sort"
 	"strings"
 
+	"ai-conversation-platform/internal/metrics"
 	"ai-conversation-platform/internal/models"
 )
 
@@ -82,6 +85,7 @@ func (e *RuleEngine) ValidateOutput(input string, rules []*models.Rule) Validati
 				Severity:    e.getSeverityForRule(rule),
 			}
 			violations = append(violations, violation)
+			metrics.RecordRuleViolation(violation.RuleType)
 
 			// Log rule trigger for audit
 			log.Printf("[RULE] violation detected rule_id=%s rule_name=%s action=%s severity=%s matched=%s",
@@ -119,23 +123,40 @@ func (e *RuleEngine) ValidateOutput(input string, rules []*models.Rule) Validati
 	return result
 }
 
-// filterActiveRules returns only active rules
+// filterActiveRules returns only active rules. Escalation rules are left out; they match customer
+// messages, not output (see MatchEscalationRule). So are max_discount rules, which check price
+// ranges (see ValidatePricingRange).
 func (e *RuleEngine) filterActiveRules(rules []*models.Rule) []*models.Rule {
 	active := []*models.Rule{}
 	for _, rule := range rules {
-		if rule.IsActive {
+		if rule.IsActive && rule.Action != models.RuleActionEscalate && rule.Type != RuleTypeMaxDiscount {
 			active = append(active, rule)
 		}
 	}
 	return active
 }
 
-// sortRulesByPriority sorts rules by action priority (block > correct > flag)
+// MatchEscalationRule returns the first active escalation rule matching a customer message and
+// the matched text, or nil when none matches
+func (e *RuleEngine) MatchEscalationRule(text string, rules []*models.Rule) (*models.Rule, string) {
+	for _, rule := range rules {
+		if !rule.IsActive || rule.Action != models.RuleActionEscalate {
+			continue
+		}
+		if matched, matchedText := e.matchPattern(text, rule.Pattern); matched {
+			return rule, matchedText
+		}
+	}
+	return nil, ""
+}
+
+// sortRulesByPriority returns a copy of rules sorted by action priority (block > correct > flag).
+// The sort is stable so rules with the same action are always evaluated in their given order.
 func (e *RuleEngine) sortRulesByPriority(rules []*models.Rule) []*models.Rule {
 	sorted := make([]*models.Rule, len(rules))
 	copy(sorted, rules)
 
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		priorityI := e.getActionPriority(sorted[i].Action)
 		priorityJ := e.getActionPriority(sorted[j].Action)
 		return priorityI < priorityJ // Lower number = higher priority
@@ -180,6 +201,53 @@ func (e *RuleEngine) matchPattern(text, pattern string) (bool, string) {
 	return false, ""
 }
 
+// ValidatePattern checks that a rule pattern compiles as a regular expression. Plain keywords are
+// valid regular expressions too.
+func (e *RuleEngine) ValidatePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("invalid pattern: %s: `%s`", syntaxErr.Code, syntaxErr.Expr)
+		}
+		return fmt.Errorf("invalid pattern: %v", err)
+	}
+	return nil
+}
+
+// PatternTestResult is what a rule would do to a sample text
+type PatternTestResult struct {
+	Matched       bool
+	MatchedText   string
+	Blocked       bool
+	Flagged       bool
+	CorrectedText string // The sample text after auto-correction; unchanged for other actions
+}
+
+// TestPattern evaluates a single rule against sample text without recording a violation, so admins
+// can try a pattern before activating it. ruleType picks the auto-correction template.
+func (e *RuleEngine) TestPattern(pattern, action, ruleType, sampleText string) PatternTestResult {
+	result := PatternTestResult{CorrectedText: sampleText}
+	matched, matchedText := e.matchPattern(sampleText, pattern)
+	if !matched {
+		return result
+	}
+	result.Matched = true
+	result.MatchedText = matchedText
+
+	switch action {
+	case "block":
+		result.Blocked = true
+	case "auto_correct":
+		result.CorrectedText = e.AutoCorrect(sampleText, Violation{RuleType: ruleType, Action: action, Pattern: pattern, MatchedText: matchedText})
+	case "flag":
+		result.Flagged = true
+	}
+	return result
+}
+
 // AutoCorrect applies correction template to text based on violation
 func (e *RuleEngine) AutoCorrect(text string, violation Violation) string {
 	// Determine policy type from rule name/type
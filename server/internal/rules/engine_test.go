@@ -0,0 +1,290 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/models"
+)
+
+func testRule(id, ruleType, pattern, action string) *models.Rule {
+	return &models.Rule{
+		ID:       id,
+		Name:     id,
+		Type:     ruleType,
+		Pattern:  pattern,
+		Action:   action,
+		IsActive: true,
+	}
+}
+
+func TestRuleEngineConcurrency(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("flag-price", "objection", `(?i)\bprice\b`, "flag"),
+		testRule("correct-claims", "no_false_claims", `(?i)\bguaranteed\b`, "auto_correct"),
+		testRule("flag-wait", "objection", `(?i)\bwait\b`, "flag"),
+		testRule("correct-tone", "brand_tone", `(?i)\bcrazy\b`, "auto_correct"),
+	}
+	input := "Our price is crazy low and results are guaranteed, no need to wait."
+	want := engine.ValidateOutput(input, rules)
+
+	const workers = 100
+	results := make([]ValidationResult, workers)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i] = engine.ValidateOutput(input, rules)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i, got := range results {
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("result %d differs from sequential result:\n got  %+v\n want %+v", i, got, want)
+		}
+	}
+	if ids := []string{rules[0].ID, rules[1].ID, rules[2].ID, rules[3].ID}; !reflect.DeepEqual(ids, []string{"flag-price", "correct-claims", "flag-wait", "correct-tone"}) {
+		t.Errorf("ValidateOutput reordered the caller's rules: %v", ids)
+	}
+}
+
+func TestRuleEngineBlockPriority(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("flag", "objection", "discount", "flag"),
+		testRule("correct", "no_unauthorized_discounts", "discount", "auto_correct"),
+		testRule("block", "no_legal_promises", "discount", "block"),
+	}
+
+	result := engine.ValidateOutput("I can offer you a discount today.", rules)
+	if !result.Blocked {
+		t.Fatal("expected block rule to block the response")
+	}
+	if len(result.Violations) != 1 || result.Violations[0].RuleID != "block" {
+		t.Fatalf("violations = %+v, want only the block rule", result.Violations)
+	}
+	if result.CorrectedText != "I can offer you a discount today." {
+		t.Errorf("blocked response was auto-corrected: %q", result.CorrectedText)
+	}
+}
+
+func TestRuleEngineSortIsStable(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("flag-a", "objection", "a", "flag"),
+		testRule("correct-a", "objection", "a", "auto_correct"),
+		testRule("flag-b", "objection", "b", "flag"),
+		testRule("correct-b", "objection", "b", "auto_correct"),
+		testRule("flag-c", "objection", "c", "flag"),
+	}
+
+	sorted := engine.sortRulesByPriority(rules)
+	var ids []string
+	for _, rule := range sorted {
+		ids = append(ids, rule.ID)
+	}
+	want := []string{"correct-a", "correct-b", "flag-a", "flag-b", "flag-c"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("sorted rules = %v, want %v", ids, want)
+	}
+	if rules[0].ID != "flag-a" {
+		t.Error("sortRulesByPriority modified the input slice")
+	}
+}
+
+func TestRuleEnginePatternFallback(t *testing.T) {
+	engine := NewRuleEngine()
+
+	// "(50% off" is not a valid regex, so it is matched as a case-insensitive substring
+	matched, matchedText := engine.matchPattern("Get (50% OFF today", "(50% off")
+	if !matched || matchedText != "(50% off" {
+		t.Errorf("matchPattern with invalid regex = %v, %q; want substring match", matched, matchedText)
+	}
+	if matched, _ := engine.matchPattern("Full price today", "(50% off"); matched {
+		t.Error("matchPattern with invalid regex matched text without the substring")
+	}
+
+	result := engine.ValidateOutput("Get (50% OFF today", []*models.Rule{testRule("flag", "objection", "(50% off", "flag")})
+	if len(result.Violations) != 1 {
+		t.Errorf("violations = %d, want 1 from the substring fallback", len(result.Violations))
+	}
+}
+
+func TestAutoCorrectIdempotent(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("claims", "no_false_claims", `(?i)\bguaranteed\b`, "auto_correct"),
+		testRule("tone", "brand_tone", `(?i)\bcrazy\b`, "auto_correct"),
+	}
+
+	first := engine.ValidateOutput("Results are guaranteed, this deal is crazy.", rules)
+	if first.CorrectedText == "Results are guaranteed, this deal is crazy." {
+		t.Fatal("expected the first pass to correct the text")
+	}
+
+	second := engine.ValidateOutput(first.CorrectedText, rules)
+	if second.CorrectedText != first.CorrectedText {
+		t.Errorf("second pass changed corrected text:\n first  %q\n second %q", first.CorrectedText, second.CorrectedText)
+	}
+	if len(second.Violations) != 0 {
+		t.Errorf("corrected text still has %d violation(s)", len(second.Violations))
+	}
+}
+
+func TestViolationSeverityMapping(t *testing.T) {
+	engine := NewRuleEngine()
+	cases := map[string]string{
+		"block":        "critical",
+		"auto_correct": "high",
+		"flag":         "medium",
+		"unknown":      "low",
+	}
+	for action, want := range cases {
+		if got := engine.getSeverityForRule(&models.Rule{Action: action}); got != want {
+			t.Errorf("severity for %s = %s, want %s", action, got, want)
+		}
+	}
+
+	result := engine.ValidateOutput("please wait", []*models.Rule{testRule("flag", "objection", "wait", "flag")})
+	if len(result.Violations) != 1 || result.Violations[0].Severity != string(SeverityMedium) {
+		t.Errorf("flag violation = %+v, want severity %s", result.Violations, SeverityMedium)
+	}
+}
+
+func TestValidateOutputRecordsViolationMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.SetRegistry(registry)
+	t.Cleanup(func() { metrics.SetRegistry(nil) })
+
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("flag-price", "objection", `(?i)\bprice\b`, "flag"),
+		testRule("flag-wait", "objection", `(?i)\bwait\b`, "flag"),
+		testRule("correct-claims", "no_false_claims", `(?i)\bguaranteed\b`, "auto_correct"),
+	}
+	engine.ValidateOutput("The price is guaranteed, no need to wait.", rules)
+	engine.ValidateOutput("Nothing to see here.", rules)
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	output := rec.Body.String()
+	for _, sample := range []string{
+		`rule_violations_total{rule_type="objection"} 2`,
+		`rule_violations_total{rule_type="no_false_claims"} 1`,
+	} {
+		if !strings.Contains(output, sample+"\n") {
+			t.Errorf("missing sample %q", sample)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	engine := NewRuleEngine()
+
+	for _, pattern := range []string{"refund", "money back guarantee", `(?i)\bguaranteed\b`, `\d+% off`} {
+		if err := engine.ValidatePattern(pattern); err != nil {
+			t.Errorf("ValidatePattern(%q) = %v, want valid", pattern, err)
+		}
+	}
+
+	tests := map[string]string{
+		"(50% off": "missing closing )",
+		"[a-":      "missing closing ]",
+		`(?i)\bx\`: "trailing backslash",
+		"   ":      "pattern is required",
+		"a{2,1}":   "invalid repeat count",
+	}
+	for pattern, want := range tests {
+		err := engine.ValidatePattern(pattern)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidatePattern(%q) = %v, want error containing %q", pattern, err, want)
+		}
+	}
+}
+
+func TestTestPatternActions(t *testing.T) {
+	engine := NewRuleEngine()
+	sample := "We offer a 50% off discount today"
+
+	block := engine.TestPattern(`\d+% off`, "block", "", sample)
+	if !block.Matched || block.MatchedText != "50% off" || !block.Blocked || block.Flagged {
+		t.Errorf("block = %+v, want a blocked match of %q", block, "50% off")
+	}
+	if block.CorrectedText != sample {
+		t.Errorf("block changed the text to %q", block.CorrectedText)
+	}
+
+	correct := engine.TestPattern(`\d+% off`, "auto_correct", "no_unauthorized_discounts", sample)
+	if !correct.Matched || correct.Blocked || correct.Flagged {
+		t.Errorf("auto_correct = %+v, want an unblocked match", correct)
+	}
+	if strings.Contains(correct.CorrectedText, "50% off") || !strings.Contains(correct.CorrectedText, "current pricing and promotions") {
+		t.Errorf("auto_correct text = %q, want the discount replaced by its template", correct.CorrectedText)
+	}
+
+	flag := engine.TestPattern("DISCOUNT", "flag", "", sample)
+	if flag.Matched {
+		t.Errorf("flag = %+v, regex patterns are case-sensitive", flag)
+	}
+	flag = engine.TestPattern("(?i)DISCOUNT", "flag", "", sample)
+	if !flag.Matched || flag.MatchedText != "discount" || !flag.Flagged || flag.Blocked || flag.CorrectedText != sample {
+		t.Errorf("flag = %+v, want a flagged match with the text unchanged", flag)
+	}
+
+	miss := engine.TestPattern("refund", "block", "", sample)
+	if miss.Matched || miss.Blocked || miss.CorrectedText != sample {
+		t.Errorf("no match = %+v, want nothing to happen", miss)
+	}
+}
+
+func TestTestPatternDoesNotRecordViolations(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.SetRegistry(registry)
+	t.Cleanup(func() { metrics.SetRegistry(nil) })
+
+	NewRuleEngine().TestPattern("refund", "block", "no_false_claims", "full refund guaranteed")
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if strings.Contains(rec.Body.String(), `rule_violations_total{rule_type="no_false_claims"}`) {
+		t.Error("TestPattern recorded a rule violation metric")
+	}
+}
+
+func TestEscalationRulesOnlyMatchCustomerMessages(t *testing.T) {
+	engine := NewRuleEngine()
+	inactive := testRule("escalate-lawyer", "escalation", `(?i)\blawyer\b`, models.RuleActionEscalate)
+	inactive.IsActive = false
+	rules := []*models.Rule{
+		testRule("flag-refund", "objection", `(?i)\brefund\b`, "flag"),
+		inactive,
+		testRule("escalate-cancel", "escalation", `(?i)\bcancel\b`, models.RuleActionEscalate),
+	}
+
+	// Escalation rules don't act on AI output
+	if result := engine.ValidateOutput("You can cancel anytime", rules); len(result.Violations) != 0 {
+		t.Errorf("violations = %+v, want escalation rules ignored by ValidateOutput", result.Violations)
+	}
+
+	rule, matched := engine.MatchEscalationRule("I want to CANCEL and get a refund", rules)
+	if rule == nil || rule.ID != "escalate-cancel" || matched != "CANCEL" {
+		t.Errorf("MatchEscalationRule = %v, %q; want escalate-cancel matching CANCEL", rule, matched)
+	}
+	if rule, _ := engine.MatchEscalationRule("My lawyer will call you", rules); rule != nil {
+		t.Errorf("MatchEscalationRule = %+v, want inactive rules skipped", rule)
+	}
+	if rule, _ := engine.MatchEscalationRule("Can I get a refund?", rules); rule != nil {
+		t.Errorf("MatchEscalationRule = %+v, want only escalate rules matched", rule)
+	}
+}
@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/models"
+)
+
+// RuleTypeMaxDiscount marks rules that cap the discount pricing suggestions may imply. Their
+// pattern is JSON, e.g. {"max_pct": 20}, rather than a regular expression.
+const RuleTypeMaxDiscount = "max_discount"
+
+// PricingRange is a suggested price range to validate against pricing rules
+type PricingRange struct {
+	MinPrice float64
+	MaxPrice float64
+}
+
+// PricingRule is a max_discount rule with its pattern parsed
+type PricingRule struct {
+	*models.Rule
+	MaxDiscountPercent float64
+}
+
+// pricingRulePattern is the JSON stored in a max_discount rule's pattern
+type pricingRulePattern struct {
+	MaxPct *float64 `json:"max_pct"`
+}
+
+// ParsePricingRule parses a max_discount rule's pattern. max_pct must be between 0 and 100.
+func ParsePricingRule(rule *models.Rule) (*PricingRule, error) {
+	var pattern pricingRulePattern
+	if err := json.Unmarshal([]byte(rule.Pattern), &pattern); err != nil {
+		return nil, fmt.Errorf("invalid pattern: max_discount rules take JSON like {\"max_pct\": 20}: %v", err)
+	}
+	if pattern.MaxPct == nil || *pattern.MaxPct < 0 || *pattern.MaxPct > 100 {
+		return nil, fmt.Errorf("invalid pattern: max_pct must be between 0 and 100")
+	}
+	return &PricingRule{Rule: rule, MaxDiscountPercent: *pattern.MaxPct}, nil
+}
+
+// ValidateRulePattern checks a rule's pattern for its type: JSON for max_discount rules, a
+// regular expression otherwise
+func (e *RuleEngine) ValidateRulePattern(ruleType, pattern string) error {
+	if ruleType == RuleTypeMaxDiscount {
+		_, err := ParsePricingRule(&models.Rule{Type: ruleType, Pattern: pattern})
+		return err
+	}
+	return e.ValidatePattern(pattern)
+}
+
+// ValidatePricingRange checks the discount a price range implies against the active max_discount
+// rules. The discount is how far the range's minimum is below productBasePrice; without a base
+// price there is nothing to compare. A violated rule with the block action blocks the range;
+// other actions only report the violation.
+func (e *RuleEngine) ValidatePricingRange(pricingRange PricingRange, productBasePrice float64, rules []*models.Rule) ValidationResult {
+	result := ValidationResult{
+		Passed:      true,
+		Violations:  []Violation{},
+		RuleResults: make([]bool, len(rules)),
+	}
+	if productBasePrice <= 0 {
+		for i := range result.RuleResults {
+			result.RuleResults[i] = true
+		}
+		return result
+	}
+	discountPercent := (productBasePrice - pricingRange.MinPrice) / productBasePrice * 100
+
+	for i, rule := range rules {
+		result.RuleResults[i] = true
+		if !rule.IsActive || rule.Type != RuleTypeMaxDiscount {
+			continue
+		}
+		pricingRule, err := ParsePricingRule(rule)
+		if err != nil {
+			log.Printf("[RULE] skipping max_discount rule rule_id=%s: %v", rule.ID, err)
+			continue
+		}
+		// Allow for rounding in the suggested prices
+		if discountPercent <= pricingRule.MaxDiscountPercent+1e-6 {
+			continue
+		}
+
+		result.RuleResults[i] = false
+		violation := Violation{
+			RuleID:      rule.ID,
+			RuleName:    rule.Name,
+			RuleType:    rule.Type,
+			Action:      rule.Action,
+			Pattern:     rule.Pattern,
+			MatchedText: fmt.Sprintf("%.1f%% discount (max %.1f%%)", discountPercent, pricingRule.MaxDiscountPercent),
+			Severity:    e.getSeverityForRule(rule),
+		}
+		result.Violations = append(result.Violations, violation)
+		metrics.RecordRuleViolation(violation.RuleType)
+		log.Printf("[RULE] violation detected rule_id=%s rule_name=%s action=%s severity=%s matched=%s",
+			violation.RuleID, violation.RuleName, violation.Action, violation.Severity, violation.MatchedText)
+
+		if rule.Action == "block" {
+			result.Blocked = true
+		}
+	}
+
+	if result.Blocked {
+		result.Passed = false
+		result.Explanation = e.GenerateExplanation(result.Violations)
+	}
+	return result
+}
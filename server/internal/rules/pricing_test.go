@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestValidatePricingRangeBlocksExcessDiscount(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{
+		testRule("flag-price", "objection", `(?i)\bprice\b`, "flag"),
+		testRule("max-discount", RuleTypeMaxDiscount, `{"max_pct": 20}`, "block"),
+	}
+
+	// 30% off a ₹1000 product
+	result := engine.ValidatePricingRange(PricingRange{MinPrice: 700, MaxPrice: 950}, 1000, rules)
+	if !result.Blocked || result.Passed {
+		t.Fatalf("result = %+v, want a 30%% discount blocked at max_pct 20", result)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].RuleID != "max-discount" || result.Explanation == "" {
+		t.Errorf("violations = %+v explanation = %q, want the max_discount rule explained", result.Violations, result.Explanation)
+	}
+	if !result.RuleResults[0] || result.RuleResults[1] {
+		t.Errorf("rule results = %v, want only the max_discount rule failed", result.RuleResults)
+	}
+
+	for name, tt := range map[string]struct {
+		pricingRange PricingRange
+		basePrice    float64
+	}{
+		"exactly the max":  {PricingRange{MinPrice: 800, MaxPrice: 1000}, 1000},
+		"above base price": {PricingRange{MinPrice: 1100, MaxPrice: 1300}, 1000},
+		"no base price":    {PricingRange{MinPrice: 100, MaxPrice: 200}, 0},
+	} {
+		if result := engine.ValidatePricingRange(tt.pricingRange, tt.basePrice, rules); !result.Passed || result.Blocked {
+			t.Errorf("%s: result = %+v, want passed", name, result)
+		}
+	}
+}
+
+func TestValidatePricingRangeFlagsWithoutBlocking(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{testRule("max-discount", RuleTypeMaxDiscount, `{"max_pct": 10}`, "flag")}
+
+	result := engine.ValidatePricingRange(PricingRange{MinPrice: 850, MaxPrice: 1000}, 1000, rules)
+	if result.Blocked || !result.Passed || len(result.Violations) != 1 {
+		t.Errorf("result = %+v, want the violation reported without blocking", result)
+	}
+
+	rules[0].IsActive = false
+	if result := engine.ValidatePricingRange(PricingRange{MinPrice: 500}, 1000, rules); len(result.Violations) != 0 {
+		t.Errorf("violations = %+v, want inactive rules ignored", result.Violations)
+	}
+}
+
+func TestValidateRulePattern(t *testing.T) {
+	engine := NewRuleEngine()
+	if err := engine.ValidateRulePattern(RuleTypeMaxDiscount, `{"max_pct": 20}`); err != nil {
+		t.Errorf("valid max_discount pattern: %v", err)
+	}
+	for _, pattern := range []string{`{"max_pct": 120}`, `{}`, `20%`} {
+		if err := engine.ValidateRulePattern(RuleTypeMaxDiscount, pattern); err == nil {
+			t.Errorf("pattern %q: want an error", pattern)
+		}
+	}
+	if err := engine.ValidateRulePattern("objection", `(unclosed`); err == nil {
+		t.Error("invalid regex: want an error")
+	}
+}
+
+func TestValidateOutputIgnoresMaxDiscountRules(t *testing.T) {
+	engine := NewRuleEngine()
+	rules := []*models.Rule{testRule("max-discount", RuleTypeMaxDiscount, `{"max_pct": 20}`, "block")}
+
+	if result := engine.ValidateOutput(`Our max_pct is 20 {"max_pct": 20}`, rules); !result.Passed || len(result.Violations) != 0 {
+		t.Errorf("result = %+v, want max_discount rules left to ValidatePricingRange", result)
+	}
+}
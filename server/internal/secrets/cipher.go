@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// masterKeyEnv holds the 32-byte AES-256 master key (base64 or hex encoded)
+const masterKeyEnv = "CREDENTIAL_MASTER_KEY"
+
+// Cipher encrypts and decrypts secrets with AES-256-GCM. Each secret is bound to its tenant:
+// the tenant ID is authenticated as additional data, so a ciphertext copied to another tenant
+// fails to decrypt.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// NewCipherFromEnv creates a cipher from CREDENTIAL_MASTER_KEY
+func NewCipherFromEnv() (*Cipher, error) {
+	encoded := os.Getenv(masterKeyEnv)
+	if encoded == "" {
+		return nil, fmt.Errorf("%s environment variable is required", masterKeyEnv)
+	}
+	key, err := decodeKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", masterKeyEnv, err)
+	}
+	return NewCipher(key)
+}
+
+// Encrypt encrypts a tenant's plaintext and returns base64(nonce || ciphertext)
+func (c *Cipher) Encrypt(tenantID, plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(tenantID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt. It fails if the secret was encrypted for a different tenant.
+func (c *Cipher) Decrypt(tenantID, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("encrypted secret is too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(tenantID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// decodeKey accepts a 64-character hex key or a base64-encoded 32-byte key
+func decodeKey(encoded string) ([]byte, error) {
+	if len(encoded) == 64 {
+		if key, err := hex.DecodeString(encoded); err == nil {
+			return key, nil
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("expected 64 hex characters or base64-encoded 32 bytes")
+	}
+	return key, nil
+}
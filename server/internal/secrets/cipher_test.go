@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, fill byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, 1)
+
+	for _, plaintext := range []string{"AIzaSyExampleKey-123", "", "ключ 🔑"} {
+		encrypted, err := c.Encrypt("tenant-1", plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if plaintext != "" && strings.Contains(encrypted, plaintext) {
+			t.Errorf("ciphertext %q contains the plaintext", encrypted)
+		}
+		decrypted, err := c.Decrypt("tenant-1", encrypted)
+		if err != nil || decrypted != plaintext {
+			t.Errorf("Decrypt = %q, %v; want %q", decrypted, err, plaintext)
+		}
+	}
+
+	// A fresh nonce makes every encryption of the same secret different
+	first, _ := c.Encrypt("tenant-1", "same key")
+	second, _ := c.Encrypt("tenant-1", "same key")
+	if first == second {
+		t.Error("encrypting twice produced the same ciphertext")
+	}
+}
+
+func TestCipherRejectsTamperedSecrets(t *testing.T) {
+	c := newTestCipher(t, 1)
+	encrypted, err := c.Encrypt("tenant-1", "AIzaSyExampleKey-123")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	sealed, _ := base64.StdEncoding.DecodeString(encrypted)
+
+	// Flipping a bit in the nonce, ciphertext or tag must fail authentication
+	for _, i := range []int{0, c.aead.NonceSize(), len(sealed) - 1} {
+		tampered := append([]byte(nil), sealed...)
+		tampered[i] ^= 0x01
+		if _, err := c.Decrypt("tenant-1", base64.StdEncoding.EncodeToString(tampered)); err == nil {
+			t.Errorf("byte %d tampered: Decrypt succeeded, want an error", i)
+		}
+	}
+
+	if _, err := c.Decrypt("tenant-1", base64.StdEncoding.EncodeToString(sealed[:len(sealed)-1])); err == nil {
+		t.Error("truncated secret decrypted, want an error")
+	}
+	if _, err := c.Decrypt("tenant-1", base64.StdEncoding.EncodeToString(sealed[:4])); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("Decrypt of 4 bytes = %v, want a too short error", err)
+	}
+	if _, err := c.Decrypt("tenant-1", "not base64!"); err == nil {
+		t.Error("Decrypt of invalid base64 succeeded, want an error")
+	}
+}
+
+func TestCipherRejectsWrongKey(t *testing.T) {
+	encrypted, err := newTestCipher(t, 1).Encrypt("tenant-1", "AIzaSyExampleKey-123")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if decrypted, err := newTestCipher(t, 2).Decrypt("tenant-1", encrypted); err == nil {
+		t.Errorf("Decrypt with another key = %q, want an error", decrypted)
+	}
+}
+
+func TestCipherBindsSecretsToTenant(t *testing.T) {
+	c := newTestCipher(t, 1)
+	encrypted, err := c.Encrypt("tenant-1", "AIzaSyExampleKey-123")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// A ciphertext copied into another tenant's row can't be used there
+	for _, tenantID := range []string{"tenant-2", "", "tenant-10"} {
+		if decrypted, err := c.Decrypt(tenantID, encrypted); err == nil {
+			t.Errorf("Decrypt as %q = %q, want an error", tenantID, decrypted)
+		}
+	}
+}
+
+func TestNewCipherRequiresA32ByteKey(t *testing.T) {
+	for _, size := range []int{0, 16, 31, 33} {
+		if _, err := NewCipher(make([]byte, size)); err == nil {
+			t.Errorf("NewCipher with %d bytes succeeded, want an error", size)
+		}
+	}
+}
+
+func TestNewCipherFromEnv(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	encrypted, _ := newTestCipher(t, 7).Encrypt("tenant-1", "secret")
+
+	for name, encoded := range map[string]string{
+		"hex":    hex.EncodeToString(key),
+		"base64": base64.StdEncoding.EncodeToString(key),
+	} {
+		t.Setenv(masterKeyEnv, encoded)
+		c, err := NewCipherFromEnv()
+		if err != nil {
+			t.Fatalf("%s key: NewCipherFromEnv: %v", name, err)
+		}
+		if decrypted, err := c.Decrypt("tenant-1", encrypted); err != nil || decrypted != "secret" {
+			t.Errorf("%s key: Decrypt = %q, %v; want the same key decoded", name, decrypted, err)
+		}
+	}
+
+	for _, encoded := range []string{"", "not a key", base64.StdEncoding.EncodeToString(key[:16])} {
+		t.Setenv(masterKeyEnv, encoded)
+		if _, err := NewCipherFromEnv(); err == nil {
+			t.Errorf("NewCipherFromEnv with %q succeeded, want an error", encoded)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package agentassist
+
+import (
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+func pastChunk(conversationID, text string, score float64) chroma.RetrievedChunk {
+	return chroma.RetrievedChunk{Text: text, Score: score, Metadata: map[string]interface{}{"conversation_id": conversationID}}
+}
+
+func TestBlendContextChunks(t *testing.T) {
+	products := []chroma.RetrievedChunk{
+		{Text: "Premium plan: $49/month", Score: 0.8},
+		{Text: "Basic plan: $19/month", Score: 0.4},
+	}
+	past := []chroma.RetrievedChunk{
+		pastChunk("current", "customer: what about discounts?", 0.95),
+		pastChunk("past-1", "customer: is there an annual discount?\nagent: 20% off yearly", 0.9),
+		pastChunk("past-1", "customer: great, I'll take it", 0.85),
+		pastChunk("past-2", "customer: can I pay monthly?", 0.5),
+		pastChunk("past-3", "customer: do you ship to Canada?", 0.3),
+	}
+
+	blended := blendContextChunks(products, past, "current")
+	if len(blended) != 4 {
+		t.Fatalf("blended = %d chunks, want 2 products + 2 past conversations", len(blended))
+	}
+
+	wantScores := []float64{0.9, 0.8, 0.5, 0.4}
+	for i, chunk := range blended {
+		if chunk.Score != wantScores[i] {
+			t.Errorf("chunk %d score = %v, want %v (ordered by relevance)", i, chunk.Score, wantScores[i])
+		}
+	}
+	if !strings.HasPrefix(blended[0].Text, "From a similar past conversation:\n") {
+		t.Errorf("past conversation chunk should be labelled, got %q", blended[0].Text)
+	}
+	for _, chunk := range blended {
+		if strings.Contains(chunk.Text, "what about discounts") {
+			t.Error("chunks from the current conversation should be skipped")
+		}
+	}
+}
+
+func TestBlendContextChunks_NoPastConversations(t *testing.T) {
+	products := []chroma.RetrievedChunk{{Text: "Premium plan", Score: 0.8}}
+	blended := blendContextChunks(products, nil, "current")
+	if len(blended) != 1 || blended[0].Text != "Premium plan" {
+		t.Errorf("blended = %+v, want product knowledge unchanged", blended)
+	}
+}
@@ -0,0 +1,43 @@
+package agentassist
+
+import "sync"
+
+// suggestionCall is an in-flight suggestion generation that concurrent callers wait on
+type suggestionCall struct {
+	wg          sync.WaitGroup
+	suggestions []Suggestion
+	err         error
+}
+
+// suggestionGroup deduplicates concurrent suggestion generation for the same key, in the manner of
+// singleflight: the first caller runs fn and later callers with the same key share its result.
+type suggestionGroup struct {
+	mu    sync.Mutex
+	calls map[string]*suggestionCall
+}
+
+// do runs fn once per key among concurrent callers. shared reports whether the result came from
+// another caller's call.
+func (g *suggestionGroup) do(key string, fn func() ([]Suggestion, error)) (suggestions []Suggestion, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*suggestionCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.suggestions, call.err, true
+	}
+	call := &suggestionCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.suggestions, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return call.suggestions, call.err, false
+}
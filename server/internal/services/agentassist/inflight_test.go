@@ -0,0 +1,53 @@
+package agentassist
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSuggestionGroupSharesConcurrentCalls(t *testing.T) {
+	var group suggestionGroup
+	var calls int32
+	release := make(chan struct{})
+
+	const callers = 10
+	var started, done sync.WaitGroup
+	results := make([][]Suggestion, callers)
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			results[i], _, _ = group.do("tenant|conv|msg", func() ([]Suggestion, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return []Suggestion{{Text: "hello"}}, nil
+			})
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(20 * time.Millisecond) // Let every caller join the in-flight call
+	close(release)
+	done.Wait()
+
+	if calls != 1 {
+		t.Errorf("generation ran %d times, want 1", calls)
+	}
+	for i, result := range results {
+		if len(result) != 1 || result[0].Text != "hello" {
+			t.Errorf("caller %d got %+v", i, result)
+		}
+	}
+
+	// Once finished, the next call generates again
+	group.do("tenant|conv|msg", func() ([]Suggestion, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, nil
+	})
+	if calls != 2 {
+		t.Errorf("generation ran %d times after the first call finished, want 2", calls)
+	}
+}
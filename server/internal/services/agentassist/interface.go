@@ -0,0 +1,14 @@
+package agentassist
+
+import "context"
+
+// AgentAssistServiceInterface is the agent assist API used by HTTP handlers, so they can be
+// tested without AI clients or a database
+type AgentAssistServiceInterface interface {
+	GetReplySuggestions(ctx context.Context, tenantID, conversationID string, forceRegenerate bool) (*SuggestionsResponse, error)
+	GetReplySuggestionsForAgent(ctx context.Context, tenantID, conversationID, agentID string, forceRegenerate bool) (*SuggestionsResponse, error)
+	StreamReplySuggestions(ctx context.Context, tenantID, conversationID, agentID string) (<-chan string, <-chan error, error)
+	FinishStreamedSuggestions(tenantID, conversationID, text string) []Suggestion
+}
+
+var _ AgentAssistServiceInterface = (*AgentAssistService)(nil)
@@ -0,0 +1,201 @@
+package agentassist
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// maxKnowledgeGapQueryLength caps the question text stored with a knowledge gap
+	maxKnowledgeGapQueryLength = 500
+	// knowledgeGapSimilarity is the TF-IDF cosine similarity a question needs to join a topic
+	knowledgeGapSimilarity = 0.3
+	// knowledgeGapTopicTerms is how many of a topic's top terms name it
+	knowledgeGapTopicTerms = 3
+	// knowledgeGapExamples is how many distinct questions are shown per topic
+	knowledgeGapExamples = 3
+)
+
+// KnowledgeGapRecorder stores questions product knowledge had no answer for
+// (see postgres.KnowledgeGapStorage)
+type KnowledgeGapRecorder interface {
+	RecordGap(gap *postgres.KnowledgeGap) error
+}
+
+// SetKnowledgeGapRecorder records customer questions that retrieve no product knowledge, so
+// admins can see which documentation is missing (optional)
+func (s *AgentAssistService) SetKnowledgeGapRecorder(recorder KnowledgeGapRecorder) {
+	s.knowledgeGaps = recorder
+}
+
+// recordKnowledgeGap logs a question no product knowledge was found for. Failures are logged;
+// they don't fail the suggestions.
+func (s *AgentAssistService) recordKnowledgeGap(tenantID, conversationID, queryText string) {
+	if s.knowledgeGaps == nil || strings.TrimSpace(queryText) == "" {
+		return
+	}
+	if len(queryText) > maxKnowledgeGapQueryLength {
+		queryText = strings.ToValidUTF8(queryText[:maxKnowledgeGapQueryLength], "")
+	}
+	gap := &postgres.KnowledgeGap{TenantID: tenantID, ConversationID: conversationID, QueryText: queryText}
+	if err := s.knowledgeGaps.RecordGap(gap); err != nil {
+		log.Printf("[AGENT_ASSIST] failed to record knowledge gap conversation=%s: %v", conversationID, err)
+	}
+}
+
+// KnowledgeGapTopic is a group of similar questions product knowledge had no answer for
+type KnowledgeGapTopic struct {
+	Topic      string    `json:"topic"`    // The group's most distinctive words
+	Count      int       `json:"count"`    // Questions in the group
+	Examples   []string  `json:"examples"` // Distinct questions, most recent first
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// GroupKnowledgeGaps groups knowledge gaps by text similarity and returns the limit largest
+// topics. Questions are compared as TF-IDF vectors over their words, stopwords dropped; each
+// question joins the most similar topic so far when the cosine similarity of their vectors is at
+// least knowledgeGapSimilarity, or else starts a new topic. Gaps should be newest first.
+func GroupKnowledgeGaps(gaps []*postgres.KnowledgeGap, limit int) []KnowledgeGapTopic {
+	docs := make([]map[string]float64, len(gaps))
+	docFreq := make(map[string]int)
+	for i, gap := range gaps {
+		docs[i] = termFrequencies(gap.QueryText)
+		for term := range docs[i] {
+			docFreq[term]++
+		}
+	}
+	for _, doc := range docs {
+		for term, tf := range doc {
+			// Smoothed IDF, so words in every question still count a little
+			doc[term] = tf * (math.Log(float64(len(gaps)+1)/float64(docFreq[term]+1)) + 1)
+		}
+		normalize(doc)
+	}
+
+	type topicGroup struct {
+		sum      map[string]float64 // Sum of the questions' vectors
+		centroid map[string]float64 // sum, normalized
+		gaps     []*postgres.KnowledgeGap
+	}
+	var groups []*topicGroup
+	for i, gap := range gaps {
+		if len(docs[i]) == 0 {
+			continue
+		}
+		var best *topicGroup
+		bestScore := knowledgeGapSimilarity
+		for _, group := range groups {
+			if score := cosine(docs[i], group.centroid); score >= bestScore {
+				best, bestScore = group, score
+			}
+		}
+		if best == nil {
+			best = &topicGroup{sum: make(map[string]float64)}
+			groups = append(groups, best)
+		}
+		for term, weight := range docs[i] {
+			best.sum[term] += weight
+		}
+		best.centroid = make(map[string]float64, len(best.sum))
+		for term, weight := range best.sum {
+			best.centroid[term] = weight
+		}
+		normalize(best.centroid)
+		best.gaps = append(best.gaps, gap)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i].gaps) > len(groups[j].gaps) })
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	topics := make([]KnowledgeGapTopic, 0, len(groups))
+	for _, group := range groups {
+		topic := KnowledgeGapTopic{Topic: topTerms(group.centroid), Count: len(group.gaps), Examples: []string{}}
+		seen := make(map[string]bool)
+		for _, gap := range group.gaps {
+			if gap.CreatedAt.After(topic.LastSeenAt) {
+				topic.LastSeenAt = gap.CreatedAt
+			}
+			key := strings.ToLower(strings.TrimSpace(gap.QueryText))
+			if !seen[key] && len(topic.Examples) < knowledgeGapExamples {
+				seen[key] = true
+				topic.Examples = append(topic.Examples, gap.QueryText)
+			}
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// termFrequencies returns the share of each non-stopword word among a text's words
+func termFrequencies(text string) map[string]float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	counts := make(map[string]float64)
+	total := 0.0
+	for _, word := range words {
+		if phraseStopwords[word] || len([]rune(word)) < 2 {
+			continue
+		}
+		counts[word]++
+		total++
+	}
+	for word := range counts {
+		counts[word] /= total
+	}
+	return counts
+}
+
+// normalize scales a vector to unit length
+func normalize(vector map[string]float64) {
+	norm := 0.0
+	for _, weight := range vector {
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for term, weight := range vector {
+		vector[term] = weight / norm
+	}
+}
+
+// cosine returns the cosine similarity of two unit vectors
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	dot := 0.0
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot
+}
+
+// topTerms names a topic by its highest weighted terms
+func topTerms(centroid map[string]float64) string {
+	terms := make([]string, 0, len(centroid))
+	for term := range centroid {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		// Weights are sums over map iteration order, so equal weights may differ in the last bits
+		if math.Abs(centroid[terms[i]]-centroid[terms[j]]) > 1e-9 {
+			return centroid[terms[i]] > centroid[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > knowledgeGapTopicTerms {
+		terms = terms[:knowledgeGapTopicTerms]
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,119 @@
+package agentassist
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/chroma"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeEmbedder struct{ err error }
+
+func (f fakeEmbedder) GenerateEmbedding(text string) ([]float64, error) {
+	return []float64{0.1, 0.2}, f.err
+}
+
+type fakeKnowledgeRetriever struct {
+	products []chroma.RetrievedChunk
+	err      error
+}
+
+func (f fakeKnowledgeRetriever) RetrieveProductKnowledge(tenantID string, queryEmbedding []float64, topK int) ([]chroma.RetrievedChunk, error) {
+	return f.products, f.err
+}
+
+func (f fakeKnowledgeRetriever) RetrieveConversationContext(tenantID string, queryEmbedding []float64, nResults int) ([]chroma.RetrievedChunk, error) {
+	return nil, nil
+}
+
+type fakeGapRecorder struct{ gaps []*postgres.KnowledgeGap }
+
+func (f *fakeGapRecorder) RecordGap(gap *postgres.KnowledgeGap) error {
+	f.gaps = append(f.gaps, gap)
+	return nil
+}
+
+func TestRetrieveContextRecordsKnowledgeGaps(t *testing.T) {
+	messages := []*models.Message{{ConversationID: "conv-1", Sender: "customer", Content: "Do you ship to Canada?"}}
+	tests := []struct {
+		name      string
+		embedder  fakeEmbedder
+		retriever fakeKnowledgeRetriever
+		wantErr   bool
+		wantGap   bool
+	}{
+		{name: "no product knowledge", wantGap: true},
+		{name: "product knowledge found", retriever: fakeKnowledgeRetriever{products: []chroma.RetrievedChunk{{Text: "We ship worldwide", Score: 0.8}}}},
+		{name: "embedding error", embedder: fakeEmbedder{err: errors.New("gemini unavailable")}, wantErr: true},
+		{name: "embedding quota", embedder: fakeEmbedder{err: errors.New("429 quota exceeded")}},
+		{name: "retrieval error", retriever: fakeKnowledgeRetriever{err: errors.New("chroma unavailable")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakeGapRecorder{}
+			s := &AgentAssistService{embeddingService: tt.embedder, retriever: tt.retriever}
+			s.SetKnowledgeGapRecorder(recorder)
+
+			_, _, err := s.retrieveContext("tenant-1", "conv-1", messages)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("retrieveContext err = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantGap {
+				if len(recorder.gaps) != 0 {
+					t.Errorf("recorded %+v, want no knowledge gap", recorder.gaps)
+				}
+				return
+			}
+			if len(recorder.gaps) != 1 {
+				t.Fatalf("recorded %d knowledge gaps, want 1", len(recorder.gaps))
+			}
+			gap := recorder.gaps[0]
+			if gap.TenantID != "tenant-1" || gap.ConversationID != "conv-1" || gap.QueryText != "Do you ship to Canada?" {
+				t.Errorf("gap = %+v, want the customer's question", gap)
+			}
+		})
+	}
+}
+
+func TestGroupKnowledgeGaps(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	questions := []string{
+		"Do you ship to Canada?",
+		"Is there a student discount?",
+		"Can you ship to canada",
+		"How long does shipping to Canada take?",
+		"Student discount available?",
+		"Do you ship to Canada?",
+		"???",
+	}
+	gaps := make([]*postgres.KnowledgeGap, len(questions))
+	for i, question := range questions {
+		gaps[i] = &postgres.KnowledgeGap{QueryText: question, CreatedAt: now.Add(-time.Duration(i) * time.Hour)}
+	}
+
+	topics := GroupKnowledgeGaps(gaps, 10)
+	if len(topics) != 3 {
+		t.Fatalf("topics = %+v, want shipping to Canada, student discounts and shipping times", topics)
+	}
+	shipping := topics[0]
+	if shipping.Count != 3 || shipping.Topic != "ship canada do" || !shipping.LastSeenAt.Equal(now) {
+		t.Errorf("top topic = %+v, want 3 questions about shipping to Canada", shipping)
+	}
+	if len(shipping.Examples) != 2 || shipping.Examples[0] != "Do you ship to Canada?" {
+		t.Errorf("examples = %q, want the distinct questions, most recent first", shipping.Examples)
+	}
+	if topics[1].Count != 2 || topics[1].Topic != "discount student available" {
+		t.Errorf("second topic = %+v, want the 2 student discount questions", topics[1])
+	}
+
+	if limited := GroupKnowledgeGaps(gaps, 1); len(limited) != 1 || limited[0].Count != 3 {
+		t.Errorf("limited topics = %+v, want only the largest", limited)
+	}
+	if empty := GroupKnowledgeGaps(nil, 10); len(empty) != 0 {
+		t.Errorf("topics without gaps = %+v, want none", empty)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"ai-conversation-platform/internal/ai"
 	"ai-conversation-platform/internal/models"
@@ -24,39 +28,108 @@ type PricingRange struct {
 
 // PricingService generates pricing range suggestions
 type PricingService struct {
-	geminiClient  *ai.Client
-	ruleEngine    *rules.RuleEngine
-	ruleStorage   *postgres.RuleStorage
+	generator      ai.TextGenerator
+	ruleEngine     *rules.RuleEngine
+	ruleStorage    *postgres.RuleStorage
+	pricingStorage PricingSuggestionStore
+	eventPublisher EventPublisher
+	clientFactory  *ai.GeminiClientFactory
+	usageRecorder  ai.UsageRecorder
+	variantSource  VariantSource
+	productSource  ProductSource
+}
+
+// PricingSuggestionStore persists pricing suggestions and their review (see postgres.PricingSuggestionStorage).
+// Approving or rejecting only succeeds while a suggestion is pending.
+type PricingSuggestionStore interface {
+	CreatePricingSuggestion(suggestion *models.PricingSuggestion) error
+	GetPricingSuggestion(tenantID, suggestionID string) (*models.PricingSuggestion, error)
+	ListPricingSuggestions(tenantID, conversationID string) ([]*models.PricingSuggestion, error)
+	ListRejectedPricingSuggestions(tenantID string) ([]*models.PricingSuggestion, error)
+	GetLatestApprovedPricing(tenantID, conversationID string) (*models.PricingSuggestion, error)
+	ApprovePricingSuggestion(tenantID, suggestionID, approvedBy string) error
+	RejectPricingSuggestion(tenantID, suggestionID, rejectedBy string) error
+	GetPricingSuggestionStats(tenantID string) (*postgres.PricingSuggestionStats, error)
+}
+
+// VariantSource lists a product's pricing tiers (see postgres.ProductVariantStorage)
+type VariantSource interface {
+	ListVariants(tenantID, productID string, activeOnly bool) ([]models.ProductVariant, error)
+}
+
+// ProductSource looks up a product's base price (see postgres.ProductStorage)
+type ProductSource interface {
+	GetProduct(tenantID, productID string) (*models.Product, error)
 }
 
-// NewPricingService creates a new pricing service
+// NewPricingService creates a new pricing service.
+// generator may be nil, in which case only the review workflow is available.
 func NewPricingService(
-	geminiClient *ai.Client,
+	generator ai.TextGenerator,
 	ruleEngine *rules.RuleEngine,
 	ruleStorage *postgres.RuleStorage,
+	pricingStorage PricingSuggestionStore,
 ) *PricingService {
 	return &PricingService{
-		geminiClient: geminiClient,
-		ruleEngine:   ruleEngine,
-		ruleStorage:  ruleStorage,
+		generator:      generator,
+		ruleEngine:     ruleEngine,
+		ruleStorage:    ruleStorage,
+		pricingStorage: pricingStorage,
 	}
 }
 
-// SuggestPricing generates pricing range suggestions based on context
-// Never auto-apply pricing suggestions - always requires admin approval
+// SetEventPublisher sets the event publisher (optional)
+func (s *PricingService) SetEventPublisher(eventPublisher EventPublisher) {
+	s.eventPublisher = eventPublisher
+}
+
+// SetClientFactory enables per-tenant Gemini clients (optional)
+func (s *PricingService) SetClientFactory(factory *ai.GeminiClientFactory) {
+	s.clientFactory = factory
+}
+
+// SetUsageRecorder records pricing calls per tenant (optional)
+func (s *PricingService) SetUsageRecorder(recorder ai.UsageRecorder) {
+	s.usageRecorder = recorder
+}
+
+// SetVariantSource makes suggestions consider the prices of the conversation product's tiers (optional)
+func (s *PricingService) SetVariantSource(source VariantSource) {
+	s.variantSource = source
+}
+
+// SetProductSource checks suggestions against the tenant's max_discount rules using the
+// conversation product's base price (optional)
+func (s *PricingService) SetProductSource(source ProductSource) {
+	s.productSource = source
+}
+
+// SuggestPricing generates a pricing range suggestion and stores it as pending.
+// Never auto-apply pricing suggestions - always requires admin approval.
+// productID may be empty; when set, the product's active variants are offered to the model
+// and bound the suggested range.
 func (s *PricingService) SuggestPricing(
 	tenantID string,
+	conversationID string,
+	productID string,
 	messages []*models.Message,
 	context string,
 	customerMemory *models.CustomerMemory,
-) (*PricingRange, error) {
-	log.Printf("[PRICING] generating pricing suggestion tenant=%s", tenantID)
+) (*models.PricingSuggestion, error) {
+	generator := tenantClient(s.clientFactory, s.generator, tenantID)
+	if generator == nil {
+		return nil, fmt.Errorf("AI pricing suggestions are not available")
+	}
+
+	log.Printf("[PRICING] generating pricing suggestion tenant=%s conversation=%s", tenantID, conversationID)
 
 	// Build conversation text
 	conversationText := s.buildConversationText(messages)
 
+	variants := s.productVariants(tenantID, productID)
+
 	// Build prompt for pricing suggestion
-	prompt := s.buildPricingPrompt(conversationText, context, customerMemory)
+	prompt := s.buildPricingPrompt(conversationText, context, customerMemory, variants)
 
 	// Call AI to generate pricing range
 	req := ai.GenerateTextRequest{
@@ -64,13 +137,14 @@ func (s *PricingService) SuggestPricing(
 		Context: context,
 	}
 
-	resp, err := s.geminiClient.GenerateText(req)
+	ai.RecordUsage(s.usageRecorder, tenantID, ai.UsagePricingSuggestion)
+	resp, err := generator.GenerateText(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate pricing suggestion: %w", err)
 	}
 
 	// Parse pricing range from response
-	pricingRange := s.parsePricingResponse(resp.Text)
+	pricingRange := fitRangeToVariants(s.parsePricingResponse(resp.Text), variants)
 
 	// Validate with rule engine
 	rules, err := s.ruleStorage.LoadRules(tenantID)
@@ -91,10 +165,32 @@ func (s *PricingService) SuggestPricing(
 		return nil, fmt.Errorf("pricing suggestion blocked by rule engine: %s", validationResult.Explanation)
 	}
 
+	discountResult := s.validateDiscount(tenantID, productID, pricingRange, rules)
+	if discountResult.Blocked {
+		log.Printf("[PRICING] pricing suggestion blocked by max discount rule product=%s", productID)
+		return nil, fmt.Errorf("pricing suggestion blocked by rule engine: %s", discountResult.Explanation)
+	}
+	pricingRange.Validated = pricingRange.Validated && discountResult.Passed
+
 	log.Printf("[PRICING] generated pricing range $%.2f - $%.2f confidence=%.2f", 
 		pricingRange.MinPrice, pricingRange.MaxPrice, pricingRange.Confidence)
 
-	return &pricingRange, nil
+	suggestion := &models.PricingSuggestion{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		MinPrice:       pricingRange.MinPrice,
+		MaxPrice:       pricingRange.MaxPrice,
+		Confidence:     pricingRange.Confidence,
+		Reasoning:      pricingRange.Reasoning,
+		Status:         models.PricingStatusPending,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.pricingStorage.CreatePricingSuggestion(suggestion); err != nil {
+		return nil, err
+	}
+
+	return suggestion, nil
 }
 
 // buildConversationText builds text from messages
@@ -111,6 +207,7 @@ func (s *PricingService) buildPricingPrompt(
 	conversationText string,
 	context string,
 	customerMemory *models.CustomerMemory,
+	variants []models.ProductVariant,
 ) string {
 	prompt := `Based on this customer conversation, suggest a pricing range (min and max price).
 Consider:
@@ -130,6 +227,14 @@ Return JSON with:
 Conversation:
 ` + conversationText
 
+	if len(variants) > 0 {
+		tiers := make([]string, 0, len(variants))
+		for _, variant := range variants {
+			tiers = append(tiers, fmt.Sprintf("- %s: %s %.2f", variant.Name, variant.PriceCurrency, variant.Price))
+		}
+		prompt = "Available pricing tiers (keep the range within these prices):\n" + strings.Join(tiers, "\n") + "\n\n" + prompt
+	}
+
 	if context != "" {
 		prompt = "Context:\n" + context + "\n\n" + prompt
 	}
@@ -183,3 +288,67 @@ func (s *PricingService) parsePricingResponse(responseText string) PricingRange
 	}
 }
 
+// productVariants returns the active variants of the conversation's product, if any
+func (s *PricingService) productVariants(tenantID, productID string) []models.ProductVariant {
+	if s.variantSource == nil || productID == "" {
+		return nil
+	}
+	variants, err := s.variantSource.ListVariants(tenantID, productID, true)
+	if err != nil {
+		log.Printf("[PRICING] failed to load product variants product=%s: %v", productID, err)
+		return nil
+	}
+	return variants
+}
+
+// validateDiscount checks the discount a range implies off the product's base price against
+// max_discount rules. Without a product or product source there is no base price to check.
+func (s *PricingService) validateDiscount(tenantID, productID string, pricingRange PricingRange, ruleSet []*models.Rule) rules.ValidationResult {
+	var basePrice float64
+	if s.productSource != nil && productID != "" {
+		product, err := s.productSource.GetProduct(tenantID, productID)
+		if err != nil {
+			log.Printf("[PRICING] failed to load product product=%s: %v", productID, err)
+		} else {
+			basePrice = product.Price
+		}
+	}
+	return s.ruleEngine.ValidatePricingRange(rules.PricingRange{
+		MinPrice: pricingRange.MinPrice,
+		MaxPrice: pricingRange.MaxPrice,
+	}, basePrice, ruleSet)
+}
+
+// fitRangeToVariants keeps a pricing range within the product's tier prices. A range entirely
+// outside them, such as the default range used when the response couldn't be parsed, becomes
+// the span of the tiers.
+func fitRangeToVariants(pricingRange PricingRange, variants []models.ProductVariant) PricingRange {
+	if len(variants) == 0 {
+		return pricingRange
+	}
+	lowest, highest := variants[0].Price, variants[0].Price
+	for _, variant := range variants[1:] {
+		lowest = math.Min(lowest, variant.Price)
+		highest = math.Max(highest, variant.Price)
+	}
+
+	if pricingRange.MaxPrice < lowest || pricingRange.MinPrice > highest {
+		pricingRange.MinPrice, pricingRange.MaxPrice = lowest, highest
+		return pricingRange
+	}
+	pricingRange.MinPrice = math.Max(pricingRange.MinPrice, lowest)
+	pricingRange.MaxPrice = math.Min(pricingRange.MaxPrice, highest)
+	return pricingRange
+}
+
+// tenantClient returns the tenant's Gemini client when a factory is set, otherwise fallback
+func tenantClient(factory *ai.GeminiClientFactory, fallback ai.TextGenerator, tenantID string) ai.TextGenerator {
+	if factory == nil {
+		return fallback
+	}
+	client, err := factory.ClientForTenant(tenantID)
+	if err != nil {
+		return fallback
+	}
+	return client
+}
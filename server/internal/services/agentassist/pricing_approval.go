@@ -0,0 +1,181 @@
+package agentassist
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// EventPricingApproved is published when an admin approves a pricing suggestion
+const EventPricingApproved = "pricing.approved"
+
+// EventPublisher delivers events to external subscribers (e.g. webhooks)
+type EventPublisher interface {
+	Publish(tenantID, eventType string, payload map[string]interface{})
+}
+
+// RejectedPricingRange is a rounded price range and how often it was rejected
+type RejectedPricingRange struct {
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+	Count    int     `json:"count"`
+}
+
+// PricingSuggestionStats summarizes how pricing suggestions are reviewed
+type PricingSuggestionStats struct {
+	Total                int                    `json:"total"`
+	Pending              int                    `json:"pending"`
+	Approved             int                    `json:"approved"`
+	Rejected             int                    `json:"rejected"`
+	ApprovalRate         float64                `json:"approval_rate"` // approved / reviewed, 0-1
+	AvgHoursToApprove    float64                `json:"avg_hours_to_approve"`
+	CommonRejectedRanges []RejectedPricingRange `json:"common_rejected_ranges"`
+}
+
+// maxRejectedRanges limits how many rejected ranges are reported in stats
+const maxRejectedRanges = 5
+
+// ListSuggestions lists pricing suggestions for a conversation, newest first
+func (s *PricingService) ListSuggestions(tenantID, conversationID string) ([]*models.PricingSuggestion, error) {
+	return s.pricingStorage.ListPricingSuggestions(tenantID, conversationID)
+}
+
+// ApproveSuggestion approves a pending pricing suggestion and publishes pricing.approved
+func (s *PricingService) ApproveSuggestion(tenantID, conversationID, suggestionID, approvedBy string) (*models.PricingSuggestion, error) {
+	if _, err := s.getConversationSuggestion(tenantID, conversationID, suggestionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingStorage.ApprovePricingSuggestion(tenantID, suggestionID, approvedBy); err != nil {
+		return nil, err
+	}
+
+	suggestion, err := s.pricingStorage.GetPricingSuggestion(tenantID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[PRICING] suggestion approved id=%s conversation=%s by=%s", suggestionID, conversationID, approvedBy)
+
+	if s.eventPublisher != nil {
+		s.eventPublisher.Publish(tenantID, EventPricingApproved, map[string]interface{}{
+			"suggestion_id":   suggestion.ID,
+			"conversation_id": suggestion.ConversationID,
+			"min_price":       suggestion.MinPrice,
+			"max_price":       suggestion.MaxPrice,
+			"confidence":      suggestion.Confidence,
+			"approved_by":     approvedBy,
+			"approved_at":     time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	return suggestion, nil
+}
+
+// RejectSuggestion rejects a pending pricing suggestion
+func (s *PricingService) RejectSuggestion(tenantID, conversationID, suggestionID, rejectedBy string) (*models.PricingSuggestion, error) {
+	if _, err := s.getConversationSuggestion(tenantID, conversationID, suggestionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingStorage.RejectPricingSuggestion(tenantID, suggestionID, rejectedBy); err != nil {
+		return nil, err
+	}
+	log.Printf("[PRICING] suggestion rejected id=%s conversation=%s by=%s", suggestionID, conversationID, rejectedBy)
+
+	return s.pricingStorage.GetPricingSuggestion(tenantID, suggestionID)
+}
+
+// GetStats returns approval rate, average time to approve and the most commonly rejected ranges
+func (s *PricingService) GetStats(tenantID string) (*PricingSuggestionStats, error) {
+	raw, err := s.pricingStorage.GetPricingSuggestionStats(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &PricingSuggestionStats{
+		Total:             raw.Total,
+		Pending:           raw.Pending,
+		Approved:          raw.Approved,
+		Rejected:          raw.Rejected,
+		AvgHoursToApprove: raw.AvgApprovalMins / 60.0,
+	}
+	if reviewed := raw.Approved + raw.Rejected; reviewed > 0 {
+		stats.ApprovalRate = float64(raw.Approved) / float64(reviewed)
+	}
+
+	rejected, err := s.pricingStorage.ListRejectedPricingSuggestions(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	stats.CommonRejectedRanges = commonRejectedRanges(rejected)
+	return stats, nil
+}
+
+// approvedPricingSuggestion returns the approved pricing for a conversation as a
+// pinned reply suggestion, or nil if nothing has been approved
+func (s *PricingService) approvedPricingSuggestion(tenantID, conversationID string) *Suggestion {
+	approved, err := s.pricingStorage.GetLatestApprovedPricing(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[PRICING] failed to load approved pricing conversation=%s: %v", conversationID, err)
+		return nil
+	}
+	if approved == nil {
+		return nil
+	}
+
+	return &Suggestion{
+		ID:         approved.ID,
+		Text:       fmt.Sprintf("We can offer this at a price between %.2f and %.2f.", approved.MinPrice, approved.MaxPrice),
+		Confidence: 1.0,
+		Reasoning:  "Admin-approved pricing: " + approved.Reasoning,
+		Pinned:     true,
+	}
+}
+
+func (s *PricingService) getConversationSuggestion(tenantID, conversationID, suggestionID string) (*models.PricingSuggestion, error) {
+	suggestion, err := s.pricingStorage.GetPricingSuggestion(tenantID, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.ConversationID != conversationID {
+		return nil, fmt.Errorf("pricing suggestion not found")
+	}
+	return suggestion, nil
+}
+
+// commonRejectedRanges groups rejected suggestions by range rounded to two
+// significant figures and returns the most frequent ones
+func commonRejectedRanges(rejected []*models.PricingSuggestion) []RejectedPricingRange {
+	counts := make(map[[2]float64]int)
+	for _, p := range rejected {
+		counts[[2]float64{roundSignificant(p.MinPrice), roundSignificant(p.MaxPrice)}]++
+	}
+
+	ranges := make([]RejectedPricingRange, 0, len(counts))
+	for key, count := range counts {
+		ranges = append(ranges, RejectedPricingRange{MinPrice: key[0], MaxPrice: key[1], Count: count})
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].Count != ranges[j].Count {
+			return ranges[i].Count > ranges[j].Count
+		}
+		return ranges[i].MinPrice < ranges[j].MinPrice
+	})
+
+	if len(ranges) > maxRejectedRanges {
+		ranges = ranges[:maxRejectedRanges]
+	}
+	return ranges
+}
+
+// roundSignificant rounds a price to two significant figures (e.g. 1234 -> 1200)
+func roundSignificant(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v))-1)
+	return math.Round(v/magnitude) * magnitude
+}
@@ -0,0 +1,266 @@
+package agentassist
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakePricingStore keeps pricing suggestions in memory and, like the database, only
+// reviews pending suggestions
+type fakePricingStore struct {
+	suggestions map[string]*models.PricingSuggestion
+	stats       postgres.PricingSuggestionStats
+}
+
+func newFakePricingStore(suggestions ...*models.PricingSuggestion) *fakePricingStore {
+	store := &fakePricingStore{suggestions: make(map[string]*models.PricingSuggestion)}
+	for _, p := range suggestions {
+		store.suggestions[p.ID] = p
+	}
+	return store
+}
+
+func (f *fakePricingStore) CreatePricingSuggestion(suggestion *models.PricingSuggestion) error {
+	f.suggestions[suggestion.ID] = suggestion
+	return nil
+}
+
+func (f *fakePricingStore) GetPricingSuggestion(tenantID, suggestionID string) (*models.PricingSuggestion, error) {
+	p, ok := f.suggestions[suggestionID]
+	if !ok || p.TenantID != tenantID {
+		return nil, fmt.Errorf("pricing suggestion not found")
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (f *fakePricingStore) ListPricingSuggestions(tenantID, conversationID string) ([]*models.PricingSuggestion, error) {
+	var suggestions []*models.PricingSuggestion
+	for _, p := range f.suggestions {
+		if p.TenantID == tenantID && p.ConversationID == conversationID {
+			suggestions = append(suggestions, p)
+		}
+	}
+	return suggestions, nil
+}
+
+func (f *fakePricingStore) ListRejectedPricingSuggestions(tenantID string) ([]*models.PricingSuggestion, error) {
+	var rejected []*models.PricingSuggestion
+	for _, p := range f.suggestions {
+		if p.TenantID == tenantID && p.Status == models.PricingStatusRejected {
+			rejected = append(rejected, p)
+		}
+	}
+	return rejected, nil
+}
+
+func (f *fakePricingStore) GetLatestApprovedPricing(tenantID, conversationID string) (*models.PricingSuggestion, error) {
+	var latest *models.PricingSuggestion
+	for _, p := range f.suggestions {
+		if p.TenantID != tenantID || p.ConversationID != conversationID || p.Status != models.PricingStatusApproved {
+			continue
+		}
+		if latest == nil || p.ApprovedAt.After(*latest.ApprovedAt) {
+			latest = p
+		}
+	}
+	return latest, nil
+}
+
+func (f *fakePricingStore) ApprovePricingSuggestion(tenantID, suggestionID, approvedBy string) error {
+	return f.review(tenantID, suggestionID, func(p *models.PricingSuggestion, now time.Time) {
+		p.Status, p.ApprovedBy, p.ApprovedAt = models.PricingStatusApproved, &approvedBy, &now
+	})
+}
+
+func (f *fakePricingStore) RejectPricingSuggestion(tenantID, suggestionID, rejectedBy string) error {
+	return f.review(tenantID, suggestionID, func(p *models.PricingSuggestion, now time.Time) {
+		p.Status, p.RejectedBy, p.RejectedAt = models.PricingStatusRejected, &rejectedBy, &now
+	})
+}
+
+func (f *fakePricingStore) review(tenantID, suggestionID string, apply func(p *models.PricingSuggestion, now time.Time)) error {
+	p, ok := f.suggestions[suggestionID]
+	if !ok || p.TenantID != tenantID || p.Status != models.PricingStatusPending {
+		return fmt.Errorf("pricing suggestion not found or already reviewed")
+	}
+	apply(p, time.Now())
+	return nil
+}
+
+func (f *fakePricingStore) GetPricingSuggestionStats(tenantID string) (*postgres.PricingSuggestionStats, error) {
+	stats := f.stats
+	return &stats, nil
+}
+
+// recordingPublisher records published events
+type recordingPublisher struct {
+	events []string
+	last   map[string]interface{}
+}
+
+func (r *recordingPublisher) Publish(tenantID, eventType string, payload map[string]interface{}) {
+	r.events = append(r.events, tenantID+" "+eventType)
+	r.last = payload
+}
+
+func pendingPricing(id, conversationID string, min, max float64) *models.PricingSuggestion {
+	return &models.PricingSuggestion{
+		ID: id, ConversationID: conversationID, TenantID: "tenant-1",
+		MinPrice: min, MaxPrice: max, Confidence: 0.7, Reasoning: "Budget mentioned",
+		Status: models.PricingStatusPending, CreatedAt: time.Now().Add(-time.Hour),
+	}
+}
+
+func TestApproveSuggestion(t *testing.T) {
+	store := newFakePricingStore(pendingPricing("p1", "c1", 800, 950))
+	publisher := &recordingPublisher{}
+	s := NewPricingService(nil, nil, nil, store)
+	s.SetEventPublisher(publisher)
+
+	approved, err := s.ApproveSuggestion("tenant-1", "c1", "p1", "admin-1")
+	if err != nil {
+		t.Fatalf("ApproveSuggestion: %v", err)
+	}
+	if approved.Status != models.PricingStatusApproved || approved.ApprovedBy == nil || *approved.ApprovedBy != "admin-1" || approved.ApprovedAt == nil {
+		t.Errorf("approved = %+v, want approved by admin-1", approved)
+	}
+	if !reflect.DeepEqual(publisher.events, []string{"tenant-1 " + EventPricingApproved}) {
+		t.Fatalf("events = %v, want one pricing.approved", publisher.events)
+	}
+	if publisher.last["suggestion_id"] != "p1" || publisher.last["min_price"] != 800.0 || publisher.last["approved_by"] != "admin-1" {
+		t.Errorf("payload = %v, want the approved range and reviewer", publisher.last)
+	}
+
+	// Reviewed suggestions can't be reviewed again
+	if _, err := s.ApproveSuggestion("tenant-1", "c1", "p1", "admin-2"); err == nil {
+		t.Error("approving twice succeeded, want an error")
+	}
+	if _, err := s.RejectSuggestion("tenant-1", "c1", "p1", "admin-2"); err == nil {
+		t.Error("rejecting an approved suggestion succeeded, want an error")
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("events = %v, want nothing published for failed reviews", publisher.events)
+	}
+}
+
+func TestRejectSuggestion(t *testing.T) {
+	store := newFakePricingStore(pendingPricing("p1", "c1", 800, 950))
+	publisher := &recordingPublisher{}
+	s := NewPricingService(nil, nil, nil, store)
+	s.SetEventPublisher(publisher)
+
+	rejected, err := s.RejectSuggestion("tenant-1", "c1", "p1", "admin-1")
+	if err != nil {
+		t.Fatalf("RejectSuggestion: %v", err)
+	}
+	if rejected.Status != models.PricingStatusRejected || rejected.RejectedBy == nil || *rejected.RejectedBy != "admin-1" || rejected.ApprovedAt != nil {
+		t.Errorf("rejected = %+v, want rejected by admin-1", rejected)
+	}
+	if _, err := s.ApproveSuggestion("tenant-1", "c1", "p1", "admin-1"); err == nil {
+		t.Error("approving a rejected suggestion succeeded, want an error")
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("events = %v, want none for rejections", publisher.events)
+	}
+}
+
+func TestReviewScopedToConversationAndTenant(t *testing.T) {
+	store := newFakePricingStore(pendingPricing("p1", "c1", 800, 950))
+	s := NewPricingService(nil, nil, nil, store)
+
+	if _, err := s.ApproveSuggestion("tenant-1", "c2", "p1", "admin-1"); err == nil {
+		t.Error("approving through another conversation succeeded, want not found")
+	}
+	if _, err := s.RejectSuggestion("tenant-2", "c1", "p1", "admin-1"); err == nil {
+		t.Error("rejecting from another tenant succeeded, want not found")
+	}
+	if store.suggestions["p1"].Status != models.PricingStatusPending {
+		t.Errorf("status = %s, want it still pending", store.suggestions["p1"].Status)
+	}
+}
+
+func TestApprovedPricingIsPinned(t *testing.T) {
+	store := newFakePricingStore(pendingPricing("p1", "c1", 800, 950), pendingPricing("p2", "c1", 700, 900))
+	pricing := NewPricingService(nil, nil, nil, store)
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	s.SetPricingService(pricing)
+	generated := []Suggestion{{Text: "Happy to help", Confidence: 0.8}}
+
+	// Pending suggestions aren't shown to agents
+	if got := s.pinApprovedPricing("tenant-1", "c1", generated); len(got) != 1 {
+		t.Fatalf("suggestions = %+v, want nothing pinned before approval", got)
+	}
+
+	if _, err := pricing.ApproveSuggestion("tenant-1", "c1", "p1", "admin-1"); err != nil {
+		t.Fatalf("ApproveSuggestion: %v", err)
+	}
+	got := s.pinApprovedPricing("tenant-1", "c1", generated)
+	if len(got) != 2 || got[1].Text != "Happy to help" {
+		t.Fatalf("suggestions = %+v, want the approved price first", got)
+	}
+	pinned := got[0]
+	if !pinned.Pinned || pinned.ID != "p1" || pinned.Confidence != 1.0 ||
+		pinned.Text != "We can offer this at a price between 800.00 and 950.00." ||
+		pinned.Reasoning != "Admin-approved pricing: Budget mentioned" {
+		t.Errorf("pinned = %+v, want the approved range", pinned)
+	}
+
+	// The latest approval wins; other conversations get nothing
+	store.suggestions["p2"].Status = models.PricingStatusApproved
+	later := time.Now().Add(time.Minute)
+	store.suggestions["p2"].ApprovedAt = &later
+	if got := s.pinApprovedPricing("tenant-1", "c1", generated); got[0].ID != "p2" {
+		t.Errorf("pinned = %s, want the latest approval", got[0].ID)
+	}
+	if got := s.pinApprovedPricing("tenant-1", "c2", generated); len(got) != 1 {
+		t.Errorf("suggestions for c2 = %+v, want nothing pinned", got)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	rejected := func(id string, min, max float64) *models.PricingSuggestion {
+		p := pendingPricing(id, "c1", min, max)
+		p.Status = models.PricingStatusRejected
+		return p
+	}
+	store := newFakePricingStore(
+		rejected("r1", 1234, 1490),
+		rejected("r2", 1180, 1510),
+		rejected("r3", 95, 120),
+	)
+	store.stats = postgres.PricingSuggestionStats{Total: 8, Pending: 2, Approved: 3, Rejected: 3, AvgApprovalMins: 90}
+	s := NewPricingService(nil, nil, nil, store)
+
+	stats, err := s.GetStats("tenant-1")
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.Total != 8 || stats.Pending != 2 || stats.ApprovalRate != 0.5 || stats.AvgHoursToApprove != 1.5 {
+		t.Errorf("stats = %+v, want half of reviewed approved after 1.5 hours on average", stats)
+	}
+	// 1234-1490 and 1180-1510 both round to 1200-1500
+	want := []RejectedPricingRange{{MinPrice: 1200, MaxPrice: 1500, Count: 2}, {MinPrice: 95, MaxPrice: 120, Count: 1}}
+	if !reflect.DeepEqual(stats.CommonRejectedRanges, want) {
+		t.Errorf("rejected ranges = %+v, want %+v", stats.CommonRejectedRanges, want)
+	}
+
+	store.stats = postgres.PricingSuggestionStats{Total: 1, Pending: 1}
+	if stats, _ := s.GetStats("tenant-1"); stats.ApprovalRate != 0 {
+		t.Errorf("approval rate = %v, want 0 with nothing reviewed", stats.ApprovalRate)
+	}
+}
+
+func TestRoundSignificant(t *testing.T) {
+	tests := map[float64]float64{1234: 1200, 1250: 1300, 99.4: 99, 7: 7, 0: 0, -5: 0}
+	for in, want := range tests {
+		if got := roundSignificant(in); got != want {
+			t.Errorf("roundSignificant(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package agentassist
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
+)
+
+func TestFitRangeToVariants(t *testing.T) {
+	variants := []models.ProductVariant{
+		{Name: "Monthly", Price: 999},
+		{Name: "Annual", Price: 9990},
+	}
+	tests := []struct {
+		name             string
+		min, max         float64
+		wantMin, wantMax float64
+	}{
+		{"within tiers", 1500, 5000, 1500, 5000},
+		{"above the highest tier", 8000, 12000, 8000, 9990},
+		{"below the lowest tier", 500, 1200, 999, 1200},
+		{"default range outside tiers", 100, 150, 999, 9990},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fitRangeToVariants(PricingRange{MinPrice: tt.min, MaxPrice: tt.max, Confidence: 0.8}, variants)
+			if got.MinPrice != tt.wantMin || got.MaxPrice != tt.wantMax {
+				t.Errorf("range = %.2f-%.2f, want %.2f-%.2f", got.MinPrice, got.MaxPrice, tt.wantMin, tt.wantMax)
+			}
+			if got.Confidence != 0.8 {
+				t.Errorf("confidence = %v, want it kept", got.Confidence)
+			}
+		})
+	}
+
+	unchanged := fitRangeToVariants(PricingRange{MinPrice: 100, MaxPrice: 150}, nil)
+	if unchanged.MinPrice != 100 || unchanged.MaxPrice != 150 {
+		t.Errorf("range = %+v, want it unchanged without variants", unchanged)
+	}
+}
+
+func TestBuildPricingPromptListsVariants(t *testing.T) {
+	s := &PricingService{}
+	variants := []models.ProductVariant{{Name: "Annual", Price: 9990, PriceCurrency: "INR"}}
+
+	prompt := s.buildPricingPrompt("customer: too expensive", "", nil, variants)
+	if !strings.Contains(prompt, "- Annual: INR 9990.00") {
+		t.Errorf("prompt = %q, want the tier prices", prompt)
+	}
+	if prompt := s.buildPricingPrompt("customer: too expensive", "", nil, nil); strings.Contains(prompt, "pricing tiers") {
+		t.Errorf("prompt = %q, want no tier section without variants", prompt)
+	}
+}
+
+type fakeProductSource map[string]*models.Product
+
+func (f fakeProductSource) GetProduct(tenantID, productID string) (*models.Product, error) {
+	product, ok := f[productID]
+	if !ok {
+		return nil, fmt.Errorf("product not found")
+	}
+	return product, nil
+}
+
+func TestValidateDiscountUsesProductBasePrice(t *testing.T) {
+	s := NewPricingService(nil, rules.NewRuleEngine(), nil, nil)
+	ruleSet := []*models.Rule{{ID: "max-discount", Name: "Max discount", Type: rules.RuleTypeMaxDiscount, Pattern: `{"max_pct": 20}`, Action: "block", IsActive: true}}
+	suggested := PricingRange{MinPrice: 700, MaxPrice: 950}
+
+	// Without a product source there is no base price to check against
+	if result := s.validateDiscount("tenant-1", "prod-1", suggested, ruleSet); result.Blocked {
+		t.Fatalf("result = %+v, want unchecked without a product source", result)
+	}
+
+	s.SetProductSource(fakeProductSource{"prod-1": {ID: "prod-1", Price: 1000}})
+	if result := s.validateDiscount("tenant-1", "prod-1", suggested, ruleSet); !result.Blocked {
+		t.Errorf("result = %+v, want 30%% off the ₹1000 product blocked", result)
+	}
+	if result := s.validateDiscount("tenant-1", "missing", suggested, ruleSet); result.Blocked {
+		t.Errorf("result = %+v, want unchecked when the product can't be loaded", result)
+	}
+}
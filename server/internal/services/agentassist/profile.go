@@ -0,0 +1,277 @@
+package agentassist
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// profileMessageLimit is how many recent messages a profile is built from
+	profileMessageLimit = 200
+	// minProfileMessages is the least history needed for a meaningful profile
+	minProfileMessages = 5
+	// maxCommonPhrases caps the phrases included in a profile
+	maxCommonPhrases = 5
+	// minPhraseOccurrences is how many messages a phrase must appear in to count as common
+	minPhraseOccurrences = 3
+	// profileRebuildInterval is how often all profiles are rebuilt
+	profileRebuildInterval = 24 * time.Hour
+)
+
+// ErrInsufficientHistory is returned when an agent has too few messages to build a profile
+var ErrInsufficientHistory = errors.New("not enough agent messages to build a profile")
+
+var (
+	formalMarkers = []string{"please", "kindly", "regards", "sincerely", "certainly", "assist", "thank you", "would you"}
+	casualMarkers = []string{"hey", "yeah", "awesome", "gonna", "cool", "no worries", "sure thing", "!"}
+	// phraseStopwords are skipped when a phrase consists only of them
+	phraseStopwords = map[string]bool{
+		"a": true, "an": true, "the": true, "and": true, "or": true, "to": true, "of": true, "in": true,
+		"on": true, "for": true, "is": true, "it": true, "i": true, "you": true, "we": true, "that": true,
+		"this": true, "be": true, "with": true, "at": true, "as": true, "are": true, "your": true, "if": true,
+	}
+)
+
+// ProfileBuilder builds agent suggestion profiles from past agent messages
+type ProfileBuilder struct {
+	conversationStorage *postgres.ConversationStorage
+	profileStorage      *postgres.AgentProfileStorage
+	stopCh              chan struct{}
+	stopOnce            sync.Once
+}
+
+// NewProfileBuilder creates a new agent profile builder
+func NewProfileBuilder(conversationStorage *postgres.ConversationStorage, profileStorage *postgres.AgentProfileStorage) *ProfileBuilder {
+	return &ProfileBuilder{
+		conversationStorage: conversationStorage,
+		profileStorage:      profileStorage,
+		stopCh:              make(chan struct{}),
+	}
+}
+
+// BuildProfile analyzes an agent's recent messages and stores the resulting profile
+func (b *ProfileBuilder) BuildProfile(tenantID, agentID string) error {
+	messages, err := b.conversationStorage.GetAgentMessages(tenantID, agentID, profileMessageLimit)
+	if err != nil {
+		return fmt.Errorf("failed to load agent messages: %w", err)
+	}
+	if len(messages) < minProfileMessages {
+		return ErrInsufficientHistory
+	}
+
+	profile := analyzeAgentMessages(messages)
+	profile.TenantID = tenantID
+	profile.AgentID = agentID
+	if err := b.profileStorage.SaveProfile(profile); err != nil {
+		return err
+	}
+
+	log.Printf("[AGENT_PROFILE] built profile agent=%s tenant=%s tone=%s messages=%d", agentID, tenantID, profile.PreferredTone, profile.MessageCount)
+	return nil
+}
+
+// BuildAll rebuilds the profile of every agent with message history
+func (b *ProfileBuilder) BuildAll() {
+	agents, err := b.conversationStorage.ListAgentsWithMessages()
+	if err != nil {
+		log.Printf("[AGENT_PROFILE] failed to list agents: %v", err)
+		return
+	}
+
+	built := 0
+	for _, agent := range agents {
+		err := b.BuildProfile(agent.TenantID, agent.AgentID)
+		if errors.Is(err, ErrInsufficientHistory) {
+			continue
+		}
+		if err != nil {
+			log.Printf("[AGENT_PROFILE] failed to build profile agent=%s tenant=%s: %v", agent.AgentID, agent.TenantID, err)
+			continue
+		}
+		built++
+	}
+	log.Printf("[AGENT_PROFILE] nightly rebuild finished profiles=%d agents=%d", built, len(agents))
+}
+
+// Start rebuilds all profiles once a day until Stop is called
+func (b *ProfileBuilder) Start() {
+	go func() {
+		ticker := time.NewTicker(profileRebuildInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.BuildAll()
+			case <-b.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("[AGENT_PROFILE] nightly profile rebuild scheduled interval=%s", profileRebuildInterval)
+}
+
+// Stop stops the nightly rebuild
+func (b *ProfileBuilder) Stop() {
+	b.stopOnce.Do(func() { close(b.stopCh) })
+}
+
+// analyzeAgentMessages derives tone, average length and common phrases from messages
+func analyzeAgentMessages(messages []string) *postgres.AgentSuggestionProfile {
+	totalWords := 0
+	formal, casual := 0, 0
+	phraseCounts := make(map[string]int)
+
+	for _, message := range messages {
+		lower := strings.ToLower(message)
+		words := strings.Fields(lower)
+		totalWords += len(words)
+
+		formal += countMarkers(lower, formalMarkers)
+		casual += countMarkers(lower, casualMarkers)
+		// Contractions read as casual ("we're", "don't")
+		for _, word := range words {
+			if strings.Contains(word, "'") {
+				casual++
+			}
+		}
+
+		// Count each phrase once per message so one long message can't dominate
+		seen := make(map[string]bool)
+		for _, phrase := range messagePhrases(words) {
+			if !seen[phrase] {
+				seen[phrase] = true
+				phraseCounts[phrase]++
+			}
+		}
+	}
+
+	avgLength := float64(totalWords) / float64(len(messages))
+	return &postgres.AgentSuggestionProfile{
+		PreferredTone:    preferredTone(formal, casual),
+		AvgMessageLength: math.Round(avgLength*10) / 10,
+		CommonPhrases:    commonPhrases(phraseCounts),
+		MessageCount:     len(messages),
+	}
+}
+
+// countMarkers counts occurrences of markers in text
+func countMarkers(text string, markers []string) int {
+	count := 0
+	for _, marker := range markers {
+		count += strings.Count(text, marker)
+	}
+	return count
+}
+
+// preferredTone picks a tone when one style clearly outweighs the other
+func preferredTone(formal, casual int) string {
+	switch {
+	case formal > casual*3/2 && formal > 0:
+		return "formal"
+	case casual > formal*3/2 && casual > 0:
+		return "casual"
+	default:
+		return "neutral"
+	}
+}
+
+// messagePhrases returns the two- and three-word phrases in a message
+func messagePhrases(words []string) []string {
+	cleaned := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, ".,!?;:\"()")
+		if word != "" {
+			cleaned = append(cleaned, word)
+		}
+	}
+
+	var phrases []string
+	for size := 2; size <= 3; size++ {
+		for i := 0; i+size <= len(cleaned); i++ {
+			gram := cleaned[i : i+size]
+			if allStopwords(gram) {
+				continue
+			}
+			phrases = append(phrases, strings.Join(gram, " "))
+		}
+	}
+	return phrases
+}
+
+// allStopwords reports whether every word is a stopword
+func allStopwords(words []string) bool {
+	for _, word := range words {
+		if !phraseStopwords[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPhrases returns the most frequent phrases, preferring longer ones on ties
+func commonPhrases(counts map[string]int) []string {
+	type phraseCount struct {
+		phrase string
+		count  int
+	}
+	var candidates []phraseCount
+	for phrase, count := range counts {
+		if count >= minPhraseOccurrences {
+			candidates = append(candidates, phraseCount{phrase, count})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].count != candidates[j].count {
+			return candidates[i].count > candidates[j].count
+		}
+		if len(candidates[i].phrase) != len(candidates[j].phrase) {
+			return len(candidates[i].phrase) > len(candidates[j].phrase)
+		}
+		return candidates[i].phrase < candidates[j].phrase
+	})
+
+	phrases := []string{}
+	for _, candidate := range candidates {
+		if len(phrases) == maxCommonPhrases {
+			break
+		}
+		// Skip phrases contained in one already chosen ("thank you" inside "thank you for")
+		redundant := false
+		for _, chosen := range phrases {
+			if strings.Contains(chosen, candidate.phrase) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			phrases = append(phrases, candidate.phrase)
+		}
+	}
+	return phrases
+}
+
+// agentPreference formats an agent profile as a prompt instruction
+func agentPreference(profile *postgres.AgentSuggestionProfile) string {
+	preference := fmt.Sprintf("Agent preference: %s, average response length ~%.0f words", profile.PreferredTone, profile.AvgMessageLength)
+	if len(profile.CommonPhrases) > 0 {
+		preference += fmt.Sprintf(", commonly uses phrases like %s", strings.Join(quoted(profile.CommonPhrases), ", "))
+	}
+	return preference
+}
+
+// quoted wraps each phrase in double quotes
+func quoted(phrases []string) []string {
+	out := make([]string, len(phrases))
+	for i, phrase := range phrases {
+		out[i] = fmt.Sprintf("%q", phrase)
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package agentassist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeAgentMessagesDetectsTone(t *testing.T) {
+	formal := []string{
+		"Thank you for reaching out. I would be glad to assist you with your order.",
+		"Please find the requested details below. Kind regards.",
+		"Certainly, I will assist you with the refund. Thank you for your patience.",
+		"Could you kindly confirm your account email? Thank you for your patience.",
+		"Thank you for your patience while I review this. Sincerely, Support.",
+	}
+	profile := analyzeAgentMessages(formal)
+	if profile.PreferredTone != "formal" {
+		t.Errorf("formal messages tone = %s, want formal", profile.PreferredTone)
+	}
+	if profile.MessageCount != len(formal) {
+		t.Errorf("MessageCount = %d, want %d", profile.MessageCount, len(formal))
+	}
+	if profile.AvgMessageLength < 10 || profile.AvgMessageLength > 15 {
+		t.Errorf("AvgMessageLength = %.1f, want between 10 and 15", profile.AvgMessageLength)
+	}
+	if len(profile.CommonPhrases) == 0 || !strings.Contains(profile.CommonPhrases[0], "thank you") {
+		t.Errorf("CommonPhrases = %v, want a \"thank you\" phrase first", profile.CommonPhrases)
+	}
+
+	casual := []string{
+		"Hey! Yeah that's totally doable.",
+		"Awesome, I'll sort it out now!",
+		"No worries, we're gonna ship it tomorrow!",
+		"Cool, that's done!",
+		"Hey, sure thing!",
+	}
+	if tone := analyzeAgentMessages(casual).PreferredTone; tone != "casual" {
+		t.Errorf("casual messages tone = %s, want casual", tone)
+	}
+}
+
+func TestCommonPhrasesSkipsRareAndStopwordPhrases(t *testing.T) {
+	phrases := commonPhrases(map[string]int{
+		"thank you for": 4,
+		"thank you":     4,
+		"happy to help": 3,
+		"one off":       2,
+	})
+	want := []string{"thank you for", "happy to help"}
+	if strings.Join(phrases, "|") != strings.Join(want, "|") {
+		t.Errorf("commonPhrases = %v, want %v", phrases, want)
+	}
+
+	if got := messagePhrases([]string{"to", "the"}); len(got) != 0 {
+		t.Errorf("stopword-only phrases should be skipped, got %v", got)
+	}
+}
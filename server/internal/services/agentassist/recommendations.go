@@ -0,0 +1,121 @@
+package agentassist
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// maxRankedProducts caps the products listed in the suggestion prompt's conversion ranking
+const maxRankedProducts = 10
+
+// ProductRecommendationStore records the products suggestions recommend and how often agents
+// accepted them (see postgres.ProductStorage)
+type ProductRecommendationStore interface {
+	ListProducts(tenantID string) ([]*models.Product, error)
+	RecordProductRecommendations(tenantID, conversationID, suggestionID string, productIDs []string, suggestedAt time.Time) error
+	ListProductConversions(tenantID string) ([]postgres.ProductConversion, error)
+}
+
+// SetProductRecommendationStore enables tracking product recommendations and ranking products by
+// conversion rate in the suggestion prompt (optional)
+func (s *AgentAssistService) SetProductRecommendationStore(store ProductRecommendationStore) {
+	s.productRecommendations = store
+}
+
+// recordProductRecommendations stores the tenant products each suggestion recommends, so they count
+// towards the products' conversion rates once agents accept or ignore the suggestion
+func (s *AgentAssistService) recordProductRecommendations(tenantID, conversationID string, suggestions []Suggestion) {
+	if s.productRecommendations == nil {
+		return
+	}
+	var products []*models.Product
+	for _, sug := range suggestions {
+		if len(sug.ProductRecommendations) == 0 || sug.ID == "" {
+			continue
+		}
+		if products == nil {
+			var err error
+			if products, err = s.productRecommendations.ListProducts(tenantID); err != nil {
+				log.Printf("[AGENT_ASSIST] failed to load products for recommendations tenant=%s: %v", tenantID, err)
+				return
+			}
+		}
+		productIDs := matchRecommendedProducts(sug.ProductRecommendations, products)
+		if len(productIDs) == 0 {
+			continue
+		}
+		if err := s.productRecommendations.RecordProductRecommendations(tenantID, conversationID, sug.ID, productIDs, time.Now()); err != nil {
+			log.Printf("[AGENT_ASSIST] failed to record product recommendations conversation=%s suggestion=%s: %v", conversationID, sug.ID, err)
+		}
+	}
+}
+
+// matchRecommendedProducts resolves the model's recommendations, which name products or give their
+// IDs, to the IDs of the tenant's products. Unknown products are dropped.
+func matchRecommendedProducts(recommendations []string, products []*models.Product) []string {
+	var productIDs []string
+	seen := make(map[string]bool)
+	for _, recommendation := range recommendations {
+		recommendation = strings.TrimSpace(recommendation)
+		for _, product := range products {
+			if product.ID != recommendation && !strings.EqualFold(product.Name, recommendation) {
+				continue
+			}
+			if !seen[product.ID] {
+				seen[product.ID] = true
+				productIDs = append(productIDs, product.ID)
+			}
+			break
+		}
+	}
+	return productIDs
+}
+
+// productRanking returns the tenant's recommended products, best converting first, or nil when
+// none have been recommended
+func (s *AgentAssistService) productRanking(tenantID string) []postgres.ProductConversion {
+	if s.productRecommendations == nil {
+		return nil
+	}
+	conversions, err := s.productRecommendations.ListProductConversions(tenantID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load product conversion rates tenant=%s: %v", tenantID, err)
+		return nil
+	}
+	return rankProductConversions(conversions)
+}
+
+// rankProductConversions sorts products by conversion rate, then by how often they were
+// recommended, keeping the top maxRankedProducts
+func rankProductConversions(conversions []postgres.ProductConversion) []postgres.ProductConversion {
+	ranked := append([]postgres.ProductConversion(nil), conversions...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Rate() != ranked[j].Rate() {
+			return ranked[i].Rate() > ranked[j].Rate()
+		}
+		if ranked[i].Recommended != ranked[j].Recommended {
+			return ranked[i].Recommended > ranked[j].Recommended
+		}
+		return ranked[i].ProductName < ranked[j].ProductName
+	})
+	if len(ranked) > maxRankedProducts {
+		ranked = ranked[:maxRankedProducts]
+	}
+	return ranked
+}
+
+// productRankingPrompt describes ranked products for the suggestion prompt
+func productRankingPrompt(ranking []postgres.ProductConversion) string {
+	var b strings.Builder
+	b.WriteString("Product Recommendation Conversion (products agents most often accepted when recommended, best first; prefer these when relevant):\n")
+	for _, c := range ranking {
+		fmt.Fprintf(&b, "- %s: %.0f%% (%d of %d recommendations accepted)\n", c.ProductName, c.Rate()*100, c.Accepted, c.Recommended)
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package agentassist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeRecommendationStore keeps recorded recommendations in memory
+type fakeRecommendationStore struct {
+	products    []*models.Product
+	conversions []postgres.ProductConversion
+	recorded    map[string][]string // suggestion ID -> product IDs
+}
+
+func (f *fakeRecommendationStore) ListProducts(tenantID string) ([]*models.Product, error) {
+	return f.products, nil
+}
+
+func (f *fakeRecommendationStore) RecordProductRecommendations(tenantID, conversationID, suggestionID string, productIDs []string, suggestedAt time.Time) error {
+	f.recorded[suggestionID] = productIDs
+	return nil
+}
+
+func (f *fakeRecommendationStore) ListProductConversions(tenantID string) ([]postgres.ProductConversion, error) {
+	return f.conversions, nil
+}
+
+func TestBuildSuggestionPromptRanksProductsByConversionRate(t *testing.T) {
+	s := &AgentAssistService{}
+	s.SetProductRecommendationStore(&fakeRecommendationStore{conversions: []postgres.ProductConversion{
+		{ProductID: "p1", ProductName: "Starter Plan", Recommended: 10, Accepted: 2},
+		{ProductID: "p2", ProductName: "Pro Plan", Recommended: 8, Accepted: 6},
+		{ProductID: "p3", ProductName: "Onboarding Add-on", Recommended: 4, Accepted: 0},
+		{ProductID: "p4", ProductName: "Team Plan", Recommended: 20, Accepted: 15},
+	}})
+
+	prompt := s.buildSuggestionPrompt("customer: which plan fits a team of five?", "", nil, "", nil, nil, s.productRanking("tenant-1"), 3)
+
+	want := "Product Recommendation Conversion (products agents most often accepted when recommended, best first; prefer these when relevant):\n" +
+		"- Team Plan: 75% (15 of 20 recommendations accepted)\n" +
+		"- Pro Plan: 75% (6 of 8 recommendations accepted)\n" +
+		"- Starter Plan: 20% (2 of 10 recommendations accepted)\n" +
+		"- Onboarding Add-on: 0% (0 of 4 recommendations accepted)\n"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("prompt does not contain the product ranking %q:\n%s", want, prompt)
+	}
+	if !strings.Contains(prompt, "customer: which plan fits a team of five?") {
+		t.Errorf("prompt lost the conversation:\n%s", prompt)
+	}
+
+	// Without recommendation history the prompt has no ranking
+	prompt = (&AgentAssistService{}).buildSuggestionPrompt("customer: hi", "", nil, "", nil, nil, nil, 1)
+	if strings.Contains(prompt, "Product Recommendation Conversion") {
+		t.Errorf("prompt without history has a ranking:\n%s", prompt)
+	}
+}
+
+func TestRankProductConversionsKeepsTopProducts(t *testing.T) {
+	var conversions []postgres.ProductConversion
+	for i := 0; i < maxRankedProducts+3; i++ {
+		conversions = append(conversions, postgres.ProductConversion{ProductID: string(rune('a' + i)), Recommended: 10, Accepted: i})
+	}
+	ranked := rankProductConversions(conversions)
+	if len(ranked) != maxRankedProducts {
+		t.Fatalf("ranked %d products, want %d", len(ranked), maxRankedProducts)
+	}
+	if ranked[0].Accepted != maxRankedProducts+2 || ranked[len(ranked)-1].Accepted != 3 {
+		t.Errorf("ranking = %+v, want the best converting first", ranked)
+	}
+}
+
+func TestRecordProductRecommendationsMatchesTenantProducts(t *testing.T) {
+	store := &fakeRecommendationStore{
+		products: []*models.Product{{ID: "p1", Name: "Pro Plan"}, {ID: "p2", Name: "Team Plan"}},
+		recorded: map[string][]string{},
+	}
+	s := &AgentAssistService{}
+	s.SetProductRecommendationStore(store)
+
+	s.recordProductRecommendations("tenant-1", "c1", []Suggestion{
+		{ID: "s1", ProductRecommendations: []string{"pro plan", "Enterprise Plan", "p2", "Pro Plan"}},
+		{ID: "s2", ProductRecommendations: []string{"Enterprise Plan"}},
+		{ID: "s3", ProductRecommendations: []string{}},
+	})
+
+	if want := map[string][]string{"s1": {"p1", "p2"}}; !reflect.DeepEqual(store.recorded, want) {
+		t.Errorf("recorded = %v, want %v", store.recorded, want)
+	}
+}
@@ -0,0 +1,110 @@
+package agentassist
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
+)
+
+// StreamReplySuggestions starts generating reply suggestions for the agent and returns the
+// model's raw output as it is produced, for display while the reply is being written. The
+// streamed text is the JSON array the non-streaming path parses; pass the reassembled text to
+// FinishStreamedSuggestions for the moderated, validated suggestions. Returns ErrContentBlocked
+// when the conversation fails content moderation. Auto-reply keeps using GetReplySuggestions.
+func (s *AgentAssistService) StreamReplySuggestions(ctx context.Context, tenantID, conversationID, agentID string) (<-chan string, <-chan error, error) {
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		chunks, errs := make(chan string), make(chan error)
+		close(chunks)
+		close(errs)
+		return chunks, errs, nil
+	}
+
+	rules, err := s.ruleStorage.LoadRules(tenantID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load rules: %v", err)
+		rules = []*models.Rule{}
+	}
+	conversationText := s.buildConversationText(messages)
+	if result := ai.NewContentModerator(s.ruleEngine, rules).Moderate(conversationText); !result.IsSafe {
+		log.Printf("[AGENT_ASSIST] WARN conversation blocked by content moderation conversation=%s categories=%s",
+			conversationID, strings.Join(result.BlockedCategories, ","))
+		s.recordContentBlocked(tenantID, conversationID, "input", result.BlockedCategories)
+		return nil, nil, ErrContentBlocked
+	}
+
+	generator := tenantClient(s.clientFactory, s.generator, tenantID)
+	if generator == nil {
+		return nil, nil, fmt.Errorf("text generation is not configured")
+	}
+
+	metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	knowledge, _, err := s.retrieveContext(tenantID, conversationID, messages)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] context retrieval failed: %v", err)
+		knowledge = ""
+	}
+	var customerMemory *models.CustomerMemory
+	if customerID := s.extractCustomerID(messages); customerID != "" {
+		if memory, err := s.memoryStorage.GetMemory(tenantID, customerID); err == nil {
+			customerMemory = memory
+		}
+	}
+	brandTone, _ := s.getBrandTone(tenantID)
+	prompt := s.buildSuggestionPrompt(conversationText, knowledge, customerMemory, brandTone, metadata,
+		s.agentProfile(tenantID, agentID), s.productRanking(tenantID), s.suggestionCount(tenantID))
+
+	log.Printf("[AGENT_ASSIST] streaming suggestions conversation=%s tenant=%s", conversationID, tenantID)
+	ai.RecordUsage(s.usageRecorder, tenantID, ai.UsageReplySuggestions)
+	chunks, errs := streamText(ctx, generator, ai.GenerateTextRequest{Prompt: prompt, Context: knowledge})
+	return chunks, errs, nil
+}
+
+// FinishStreamedSuggestions parses the reassembled text of a suggestion stream into suggestions,
+// moderated and validated like those from GetReplySuggestions
+func (s *AgentAssistService) FinishStreamedSuggestions(tenantID, conversationID, text string) []Suggestion {
+	if strings.TrimSpace(text) == "" {
+		return []Suggestion{}
+	}
+	rules, err := s.ruleStorage.LoadRules(tenantID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load rules: %v", err)
+		rules = []*models.Rule{}
+	}
+	metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+
+	suggestions := s.parseSuggestionsResponse(text, s.suggestionCount(tenantID))
+	suggestions = s.moderateSuggestions(ai.NewContentModerator(s.ruleEngine, rules), tenantID, conversationID, suggestions)
+	suggestions = s.validateSuggestions(tenantID, suggestions, rules, metadata, nil)
+	s.recordProductRecommendations(tenantID, conversationID, suggestions)
+	return s.pinApprovedPricing(tenantID, conversationID, suggestions)
+}
+
+// streamText streams from generators that support it. Other generators (e.g. OpenAI) answer in
+// one piece, which is sent as a single chunk.
+func streamText(ctx context.Context, generator ai.TextGenerator, req ai.GenerateTextRequest) (<-chan string, <-chan error) {
+	if streamer, ok := generator.(ai.TextStreamer); ok {
+		return streamer.GenerateTextStream(ctx, req)
+	}
+
+	chunks := make(chan string, 1)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+		resp, err := generator.GenerateTextContext(ctx, req)
+		if err != nil {
+			errs <- err
+			return
+		}
+		chunks <- resp.Text
+	}()
+	return chunks, errs
+}
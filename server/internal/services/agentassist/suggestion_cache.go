@@ -0,0 +1,97 @@
+package agentassist
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/cache"
+)
+
+// suggestionCacheTTL is how long generated suggestions stay in the response cache. The
+// suggestions table keeps them longer; the cache only spares the database read.
+const suggestionCacheTTL = 5 * time.Minute
+
+// cachedSuggestionSet is the part of a suggestions response that is cached; metadata is always
+// read fresh because it can change
+type cachedSuggestionSet struct {
+	Suggestions []Suggestion `json:"suggestions"`
+	ContextUsed bool         `json:"context_used"`
+}
+
+// SetCache puts a response cache in front of the suggestions table (optional)
+func (s *AgentAssistService) SetCache(c cache.Cache) {
+	s.responseCache = c
+}
+
+// suggestionCacheKey keys suggestions by conversation and last customer message. Suggestions
+// personalized with an agent's profile are keyed by that agent too (profiledAgentID is empty otherwise).
+func suggestionCacheKey(tenantID, conversationID, lastCustomerMessageID, profiledAgentID string) string {
+	key := fmt.Sprintf("suggestions:%s:%s:%s", tenantID, conversationID, lastCustomerMessageID)
+	if profiledAgentID != "" {
+		key += ":" + profiledAgentID
+	}
+	return key
+}
+
+// loadCachedSuggestions returns the suggestions generated for the conversation's last customer
+// message (and the profiled agent, if any), from the response cache or else the suggestions table.
+// Suggestions read from the table are put in the cache.
+func (s *AgentAssistService) loadCachedSuggestions(tenantID, conversationID, lastCustomerMessageID, profiledAgentID string) (*cachedSuggestionSet, bool) {
+	key := suggestionCacheKey(tenantID, conversationID, lastCustomerMessageID, profiledAgentID)
+	if s.responseCache != nil {
+		if data, ok := s.responseCache.Get(key); ok {
+			var set cachedSuggestionSet
+			if err := json.Unmarshal(data, &set); err == nil {
+				return &set, true
+			}
+		}
+	}
+
+	if s.suggestionsStorage == nil {
+		return nil, false
+	}
+	cached, err := s.suggestionsStorage.GetSuggestions(conversationID, lastCustomerMessageID, profiledAgentID)
+	if err != nil || cached == nil {
+		return nil, false
+	}
+	// Parse cached suggestions data (only suggestions array and context_used, not metadata)
+	set := &cachedSuggestionSet{ContextUsed: cached.ContextUsed}
+	if err := json.Unmarshal([]byte(cached.SuggestionsData), &set.Suggestions); err != nil {
+		log.Printf("[AGENT_ASSIST] failed to parse cached suggestions, regenerating: %v", err)
+		return nil, false
+	}
+	s.putCachedSuggestions(key, set)
+	return set, true
+}
+
+// saveCachedSuggestions stores generated suggestions in the suggestions table and the response
+// cache. Failures are logged; they don't fail the request.
+func (s *AgentAssistService) saveCachedSuggestions(tenantID, conversationID, lastCustomerMessageID, profiledAgentID string, suggestions []Suggestion, contextUsed bool) {
+	set := &cachedSuggestionSet{Suggestions: suggestions, ContextUsed: contextUsed}
+	s.putCachedSuggestions(suggestionCacheKey(tenantID, conversationID, lastCustomerMessageID, profiledAgentID), set)
+
+	if s.suggestionsStorage == nil {
+		return
+	}
+	// Only cache the suggestions array, not the full response (metadata can change)
+	suggestionsData, err := json.Marshal(suggestions)
+	if err != nil {
+		return
+	}
+	if err := s.suggestionsStorage.SaveSuggestions(conversationID, lastCustomerMessageID, profiledAgentID, string(suggestionsData), contextUsed); err != nil {
+		log.Printf("[AGENT_ASSIST] failed to save suggestions to cache: %v", err)
+		return
+	}
+	log.Printf("[AGENT_ASSIST] saved suggestions to cache conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
+}
+
+func (s *AgentAssistService) putCachedSuggestions(key string, set *cachedSuggestionSet) {
+	if s.responseCache == nil {
+		return
+	}
+	if data, err := json.Marshal(set); err == nil {
+		s.responseCache.Set(key, data, suggestionCacheTTL)
+	}
+}
@@ -0,0 +1,89 @@
+package agentassist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/cache"
+)
+
+// unavailableCache behaves like a Redis server that can't be reached
+type unavailableCache struct{ sets int }
+
+func (c *unavailableCache) Get(key string) ([]byte, bool) { return nil, false }
+
+func (c *unavailableCache) Set(key string, value []byte, ttl time.Duration) { c.sets++ }
+
+func TestSuggestionCacheRoundTrip(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	s.SetCache(cache.NewMemoryCache(10))
+
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", ""); ok {
+		t.Fatal("loadCachedSuggestions hit before anything was saved")
+	}
+
+	suggestions := []Suggestion{{ID: "s1", Text: "We can offer annual billing", Confidence: 0.8}}
+	s.saveCachedSuggestions("tenant-1", "conv-1", "msg-1", "", suggestions, true)
+
+	cached, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", "")
+	if !ok {
+		t.Fatal("loadCachedSuggestions missed saved suggestions")
+	}
+	if !reflect.DeepEqual(cached.Suggestions, suggestions) || !cached.ContextUsed {
+		t.Errorf("cached = %+v, want the saved suggestions with context used", cached)
+	}
+
+	// Suggestions are per last customer message and per tenant
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-2", ""); ok {
+		t.Error("suggestions for an older customer message were served for a newer one")
+	}
+	if _, ok := s.loadCachedSuggestions("tenant-2", "conv-1", "msg-1", ""); ok {
+		t.Error("suggestions were served to another tenant")
+	}
+}
+
+func TestSuggestionCacheKeepsPersonalizedSuggestionsPerAgent(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	s.SetCache(cache.NewMemoryCache(10))
+
+	personalized := []Suggestion{{ID: "s1", Text: "Hey! Happy to sort that out"}}
+	s.saveCachedSuggestions("tenant-1", "conv-1", "msg-1", "agent-1", personalized, true)
+
+	if cached, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", "agent-1"); !ok || !reflect.DeepEqual(cached.Suggestions, personalized) {
+		t.Errorf("agent-1 cached = %+v, %v; want its personalized suggestions", cached, ok)
+	}
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", "agent-2"); ok {
+		t.Error("agent-1's personalized suggestions were served to agent-2")
+	}
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", ""); ok {
+		t.Error("agent-1's personalized suggestions were served to unprofiled agents")
+	}
+
+	if got := suggestionCacheKey("tenant-1", "conv-1", "msg-1", ""); got != "suggestions:tenant-1:conv-1:msg-1" {
+		t.Errorf("unpersonalized key = %q, want it unchanged", got)
+	}
+}
+
+func TestSuggestionCacheUnavailableIsAMiss(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	unavailable := &unavailableCache{}
+	s.SetCache(unavailable)
+
+	s.saveCachedSuggestions("tenant-1", "conv-1", "msg-1", "", []Suggestion{{Text: "Hi"}}, false)
+	if unavailable.sets != 1 {
+		t.Errorf("sets = %d, want the suggestions offered to the cache", unavailable.sets)
+	}
+	// With no suggestions table either, the suggestions are regenerated
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", ""); ok {
+		t.Error("loadCachedSuggestions hit with an unavailable cache and no storage")
+	}
+}
+
+func TestSuggestionCacheDisabled(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	s.saveCachedSuggestions("tenant-1", "conv-1", "msg-1", "", []Suggestion{{Text: "Hi"}}, false)
+	if _, ok := s.loadCachedSuggestions("tenant-1", "conv-1", "msg-1", ""); ok {
+		t.Error("loadCachedSuggestions hit without a cache or storage")
+	}
+}
@@ -1,12 +1,22 @@
 package agentassist
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/cache"
 	"ai-conversation-platform/internal/models"
 	"ai-conversation-platform/internal/rules"
 	"ai-conversation-platform/internal/storage/chroma"
@@ -15,26 +25,51 @@ import (
 
 // Suggestion represents an AI-generated reply suggestion
 type Suggestion struct {
+	ID                string   `json:"id,omitempty"` // Referenced by suggestion feedback
 	Text              string   `json:"text"`
 	Confidence        float64  `json:"confidence"`
+	RawConfidence     float64  `json:"raw_confidence,omitempty"` // Before calibration; what calibration is fitted to
 	ProductMatch      bool     `json:"product_match"`
 	ProductRecommendations []string `json:"product_recommendations"`
 	Reasoning         string   `json:"reasoning"`
+	Pinned            bool     `json:"pinned,omitempty"` // Admin-approved content shown first
 }
 
 // SuggestionsResponse represents the response with multiple suggestions
 type SuggestionsResponse struct {
-	Suggestions []Suggestion `json:"suggestions"`
-	ContextUsed bool          `json:"context_used"`
-	Metadata    *models.ConversationMetadata `json:"metadata"`
+	Suggestions     []Suggestion                 `json:"suggestions"`
+	ContextUsed     bool                         `json:"context_used"`
+	ContentBlocked  bool                         `json:"content_blocked"` // Conversation failed content moderation
+	Metadata        *models.ConversationMetadata `json:"metadata"`
+	SuggestionCount int                          `json:"suggestion_count"` // Number of suggestions the tenant is configured for
+}
+
+// SuggestionCountSource loads a tenant's configured suggestion count (nil means the default)
+type SuggestionCountSource interface {
+	GetSuggestionCount(tenantID string) (*int, error)
 }
 
+// AcceptanceRateSource reports how often agents used suggestions on conversations with an intent
+// (see analytics.AnalyticsService)
+type AcceptanceRateSource interface {
+	GetIntentAcceptanceRate(tenantID, intent string) (rate float64, samples int, err error)
+}
+
+// minAcceptanceSamples is the feedback needed before an intent's acceptance rate affects confidence
+const minAcceptanceSamples = 10
+
+// tracer creates the spans for reply suggestion requests
+var tracer = otel.Tracer("ai-conversation-platform/internal/services/agentassist")
+
+// ErrContentBlocked is returned when the conversation itself fails content moderation
+var ErrContentBlocked = errors.New("conversation content blocked by moderation")
+
 // AgentAssistService orchestrates agent assist use-case
 type AgentAssistService struct {
 	analyzer         *ai.Analyzer
-	geminiClient     *ai.Client
-	retriever        *chroma.Retriever
-	embeddingService *ai.EmbeddingService
+	generator        ai.TextGenerator
+	retriever        knowledgeRetriever
+	embeddingService queryEmbedder
 	ruleEngine       *rules.RuleEngine
 	ruleStorage         *postgres.RuleStorage
 	conversationStorage *postgres.ConversationStorage
@@ -42,12 +77,35 @@ type AgentAssistService struct {
 	brandToneStorage    *postgres.BrandToneStorage
 	suggestionsStorage  *postgres.SuggestionsStorage
 	confidenceScorer    *ai.ConfidenceScorer
+	pricingService      *PricingService
+	clientFactory       *ai.GeminiClientFactory
+	auditStorage        *postgres.AuditStorage
+	suggestionCountSource SuggestionCountSource // Optional per-tenant suggestion count
+	acceptanceRateSource  AcceptanceRateSource  // Optional historic acceptance rates for confidence
+	agentProfileStorage   *postgres.AgentProfileStorage
+	usageRecorder         ai.UsageRecorder
+	inflight              suggestionGroup // Shares one generation between concurrent identical requests
+	productRecommendations ProductRecommendationStore // Optional recommendation tracking and conversion ranking
+	responseCache          cache.Cache                // Optional cache in front of the suggestions table
+	knowledgeGaps          KnowledgeGapRecorder       // Optional log of questions product knowledge can't answer
+}
+
+// knowledgeRetriever finds product knowledge and past conversations relevant to a query
+// (see chroma.Retriever)
+type knowledgeRetriever interface {
+	RetrieveProductKnowledge(tenantID string, queryEmbedding []float64, topK int) ([]chroma.RetrievedChunk, error)
+	RetrieveConversationContext(tenantID string, queryEmbedding []float64, nResults int) ([]chroma.RetrievedChunk, error)
+}
+
+// queryEmbedder embeds retrieval queries (see ai.EmbeddingService)
+type queryEmbedder interface {
+	GenerateEmbedding(text string) ([]float64, error)
 }
 
 // NewAgentAssistService creates a new agent assist service
 func NewAgentAssistService(
 	analyzer *ai.Analyzer,
-	geminiClient *ai.Client,
+	generator ai.TextGenerator,
 	retriever *chroma.Retriever,
 	embeddingService *ai.EmbeddingService,
 	ruleEngine *rules.RuleEngine,
@@ -59,7 +117,7 @@ func NewAgentAssistService(
 ) *AgentAssistService {
 	return &AgentAssistService{
 		analyzer:            analyzer,
-		geminiClient:        geminiClient,
+		generator:           generator,
 		retriever:           retriever,
 		embeddingService:    embeddingService,
 		ruleEngine:          ruleEngine,
@@ -72,10 +130,178 @@ func NewAgentAssistService(
 	}
 }
 
+// SetPricingService sets the pricing service used for pricing suggestions (optional)
+func (s *AgentAssistService) SetPricingService(pricingService *PricingService) {
+	s.pricingService = pricingService
+}
+
+// SetClientFactory enables per-tenant Gemini clients (optional)
+func (s *AgentAssistService) SetClientFactory(factory *ai.GeminiClientFactory) {
+	s.clientFactory = factory
+}
+
+// SetAuditStorage sets the audit log used to record moderation blocks (optional)
+func (s *AgentAssistService) SetAuditStorage(auditStorage *postgres.AuditStorage) {
+	s.auditStorage = auditStorage
+}
+
+// SetSuggestionCountSource enables per-tenant suggestion counts (optional)
+func (s *AgentAssistService) SetSuggestionCountSource(source SuggestionCountSource) {
+	s.suggestionCountSource = source
+}
+
+// SetConfidenceCalibrator calibrates suggestion confidence against each tenant's feedback (optional)
+func (s *AgentAssistService) SetConfidenceCalibrator(calibrator *ai.ConfidenceCalibrator) {
+	s.confidenceScorer.SetCalibrator(calibrator)
+}
+
+// SetAcceptanceRateSource feeds the historic acceptance rate of suggestions for a conversation's
+// intent into their confidence, with the given weight (0-1) (optional)
+func (s *AgentAssistService) SetAcceptanceRateSource(source AcceptanceRateSource, weight float64) {
+	s.acceptanceRateSource = source
+	s.confidenceScorer.SetAcceptanceWeight(weight)
+}
+
+// intentAcceptanceRate returns the acceptance rate of suggestions for the conversation's intent, or
+// nil when it isn't known from enough feedback
+func (s *AgentAssistService) intentAcceptanceRate(tenantID string, metadata *models.ConversationMetadata) *float64 {
+	if s.acceptanceRateSource == nil || metadata == nil || metadata.Intent == "" {
+		return nil
+	}
+	rate, samples, err := s.acceptanceRateSource.GetIntentAcceptanceRate(tenantID, metadata.Intent)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load acceptance rate intent=%s tenant=%s: %v", metadata.Intent, tenantID, err)
+		return nil
+	}
+	if samples < minAcceptanceSamples {
+		return nil
+	}
+	return &rate
+}
+
+// SetAgentProfileStorage enables personalizing suggestions to the requesting agent (optional)
+func (s *AgentAssistService) SetAgentProfileStorage(agentProfileStorage *postgres.AgentProfileStorage) {
+	s.agentProfileStorage = agentProfileStorage
+}
+
+// SetUsageRecorder records suggestion calls per tenant (optional)
+func (s *AgentAssistService) SetUsageRecorder(recorder ai.UsageRecorder) {
+	s.usageRecorder = recorder
+}
+
+// agentProfile loads the requesting agent's suggestion profile, or nil if there is none
+func (s *AgentAssistService) agentProfile(tenantID, agentID string) *postgres.AgentSuggestionProfile {
+	if s.agentProfileStorage == nil || agentID == "" {
+		return nil
+	}
+	profile, err := s.agentProfileStorage.GetProfile(tenantID, agentID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load agent profile agent=%s tenant=%s: %v", agentID, tenantID, err)
+		return nil
+	}
+	return profile
+}
+
+// suggestionCount returns the tenant's configured suggestion count, or SUGGESTION_COUNT_DEFAULT
+func (s *AgentAssistService) suggestionCount(tenantID string) int {
+	if s.suggestionCountSource == nil {
+		return ai.SuggestionCountDefault()
+	}
+	count, err := s.suggestionCountSource.GetSuggestionCount(tenantID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load suggestion count, using default tenant=%s: %v", tenantID, err)
+		return ai.SuggestionCountDefault()
+	}
+	if count == nil || ai.ValidateSuggestionCount(*count) != nil {
+		return ai.SuggestionCountDefault()
+	}
+	return *count
+}
+
+// trimSuggestions keeps at most count suggestions
+func trimSuggestions(suggestions []Suggestion, count int) []Suggestion {
+	if count > 0 && len(suggestions) > count {
+		return suggestions[:count]
+	}
+	return suggestions
+}
+
+// SuggestPricing generates and stores a pending pricing suggestion for a conversation
+func (s *AgentAssistService) SuggestPricing(tenantID, conversationID string) (*models.PricingSuggestion, error) {
+	if s.pricingService == nil {
+		return nil, fmt.Errorf("pricing suggestions are not available")
+	}
+
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("conversation has no messages")
+	}
+
+	context, _, err := s.retrieveContext(tenantID, conversationID, messages)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] pricing context retrieval failed: %v", err)
+		context = ""
+	}
+
+	var customerMemory *models.CustomerMemory
+	if customerID := s.extractCustomerID(messages); customerID != "" {
+		if memory, err := s.memoryStorage.GetMemory(tenantID, customerID); err == nil {
+			customerMemory = memory
+		}
+	}
+
+	productID := ""
+	if conv, err := s.conversationStorage.GetConversation(tenantID, conversationID); err == nil && conv.ProductID != nil {
+		productID = *conv.ProductID
+	}
+
+	return s.pricingService.SuggestPricing(tenantID, conversationID, productID, messages, context, customerMemory)
+}
+
+// pinApprovedPricing prepends admin-approved pricing as the top suggestion
+func (s *AgentAssistService) pinApprovedPricing(tenantID, conversationID string, suggestions []Suggestion) []Suggestion {
+	if s.pricingService == nil {
+		return suggestions
+	}
+	pinned := s.pricingService.approvedPricingSuggestion(tenantID, conversationID)
+	if pinned == nil {
+		return suggestions
+	}
+	return append([]Suggestion{*pinned}, suggestions...)
+}
+
 // GetReplySuggestions generates AI reply suggestions for agents
 // Flow: check cache → context retrieval → AI generation → rule validation → confidence scoring → return suggestions
 // If forceRegenerate is true, cache will be cleared and new suggestions will be generated
-func (s *AgentAssistService) GetReplySuggestions(tenantID, conversationID string, forceRegenerate bool) (*SuggestionsResponse, error) {
+func (s *AgentAssistService) GetReplySuggestions(ctx context.Context, tenantID, conversationID string, forceRegenerate bool) (*SuggestionsResponse, error) {
+	return s.GetReplySuggestionsForAgent(ctx, tenantID, conversationID, "", forceRegenerate)
+}
+
+// GetReplySuggestionsForAgent generates reply suggestions personalized to the agent's writing profile.
+// An empty agentID generates unpersonalized suggestions.
+func (s *AgentAssistService) GetReplySuggestionsForAgent(ctx context.Context, tenantID, conversationID, agentID string, forceRegenerate bool) (*SuggestionsResponse, error) {
+	ctx, span := tracer.Start(ctx, "agentassist.get_reply_suggestions", trace.WithAttributes(
+		attribute.String("tenant.id", tenantID),
+		attribute.String("conversation.id", conversationID),
+		attribute.Bool("force_regenerate", forceRegenerate),
+	))
+
+	resp, err := s.replySuggestions(ctx, tenantID, conversationID, agentID, forceRegenerate)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("suggestions.count", len(resp.Suggestions)))
+	}
+	ai.EndSpan(span, err)
+	return resp, err
+}
+
+// replySuggestions implements GetReplySuggestionsForAgent
+func (s *AgentAssistService) replySuggestions(ctx context.Context, tenantID, conversationID, agentID string, forceRegenerate bool) (*SuggestionsResponse, error) {
+	// A shared generation must outlive any one caller giving up
+	generateCtx := context.WithoutCancel(ctx)
+
 	log.Printf("[AGENT_ASSIST] generating suggestions conversation=%s tenant=%s forceRegenerate=%v", conversationID, tenantID, forceRegenerate)
 
 	// 1. Retrieve conversation context
@@ -84,10 +310,13 @@ func (s *AgentAssistService) GetReplySuggestions(tenantID, conversationID string
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
 
+	suggestionCount := s.suggestionCount(tenantID)
+
 	if len(messages) == 0 {
 		return &SuggestionsResponse{
-			Suggestions: []Suggestion{},
-			ContextUsed: false,
+			Suggestions:     []Suggestion{},
+			ContextUsed:     false,
+			SuggestionCount: suggestionCount,
 		}, nil
 	}
 
@@ -103,30 +332,29 @@ func (s *AgentAssistService) GetReplySuggestions(tenantID, conversationID string
 		}
 	}
 	
-	// 1c. Check cache for existing suggestions (before getting metadata since it can change)
-	if !forceRegenerate && s.suggestionsStorage != nil && lastCustomerMessageID != "" {
-		cached, err := s.suggestionsStorage.GetSuggestions(conversationID, lastCustomerMessageID)
-		if err == nil && cached != nil {
+	// 1c. Check cache for existing suggestions (before getting metadata since it can change).
+	// Suggestions in a profiled agent's style are cached for that agent only.
+	agentProfile := s.agentProfile(tenantID, agentID)
+	profiledAgentID := profiledAgent(agentProfile)
+	if !forceRegenerate && lastCustomerMessageID != "" {
+		if cached, ok := s.loadCachedSuggestions(tenantID, conversationID, lastCustomerMessageID, profiledAgentID); ok {
 			log.Printf("[AGENT_ASSIST] cache hit for conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
-			// Parse cached suggestions data (only suggestions array and context_used, not metadata)
-			var cachedSuggestions []Suggestion
-			if err := json.Unmarshal([]byte(cached.SuggestionsData), &cachedSuggestions); err == nil {
-				// Get fresh metadata since it can change
-				metadata, _ := s.conversationStorage.GetConversationMetadata(conversationID)
-				return &SuggestionsResponse{
-					Suggestions: cachedSuggestions,
-					ContextUsed: cached.ContextUsed,
-					Metadata:    metadata,
-				}, nil
-			}
-			log.Printf("[AGENT_ASSIST] failed to parse cached suggestions, regenerating: %v", err)
-		} else {
-			log.Printf("[AGENT_ASSIST] cache miss for conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
+			// Get fresh metadata since it can change
+			metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+			// The count may have changed since these were cached
+			cachedSuggestions := trimSuggestions(cached.Suggestions, suggestionCount)
+			return &SuggestionsResponse{
+				Suggestions:     s.pinApprovedPricing(tenantID, conversationID, cachedSuggestions),
+				ContextUsed:     cached.ContextUsed,
+				Metadata:        metadata,
+				SuggestionCount: suggestionCount,
+			}, nil
 		}
+		log.Printf("[AGENT_ASSIST] cache miss for conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
 	}
 
 	// 2. Get conversation metadata (intent, sentiment, etc.)
-	metadata, _ := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 
 	// 3. Retrieve context: recent messages, product KB, customer memory
 	context, contextScores, err := s.retrieveContext(tenantID, conversationID, messages)
@@ -156,30 +384,72 @@ func (s *AgentAssistService) GetReplySuggestions(tenantID, conversationID string
 	brandTone, _ := s.getBrandTone(tenantID)
 
 	// 6. Detect customer language for multi-language support
-	customerLang := s.detectCustomerLanguage(messages)
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load conversation, using detected language: %v", err)
+	}
+	customerLang := s.detectCustomerLanguage(conv, messages)
 	agentLang := "en" // Default agent language (can be configured)
 
-	// 7. Generate AI reply suggestions with product recommendations
-	suggestions, err := s.generateReplySuggestions(messages, context, customerMemory, brandTone, metadata, customerLang, agentLang)
+	// 7. Load rules for moderation and validation
+	rules, err := s.ruleStorage.LoadRules(tenantID)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] failed to load rules: %v", err)
+		rules = []*models.Rule{}
+	}
+	moderator := ai.NewContentModerator(s.ruleEngine, rules)
+
+	// 8. Generate AI reply suggestions with product recommendations, in the agent's style if profiled.
+	// Agents viewing the same conversation at once share a single generation.
+	suggestions, err, shared := s.inflight.do(inflightKey(tenantID, conversationID, lastCustomerMessageID, profiledAgentID), func() ([]Suggestion, error) {
+		return s.generateReplySuggestions(generateCtx, tenantClient(s.clientFactory, s.generator, tenantID), moderator, tenantID, conversationID, messages, context, customerMemory, brandTone, metadata, agentProfile, customerLang, agentLang, suggestionCount, forceRegenerate)
+	})
+	if shared {
+		log.Printf("[AGENT_ASSIST] shared in-flight suggestions conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
+	}
+	if errors.Is(err, ErrContentBlocked) {
+		return contentBlockedResponse(len(context) > 0, metadata, suggestionCount), nil
+	}
 	if err != nil {
 		// generateReplySuggestions should now always return empty suggestions on error, not nil
 		// But keep this as a safety net in case it still returns an error
 		log.Printf("[AGENT_ASSIST] AI suggestions generation returned error (using empty suggestions): %v", err)
 		return &SuggestionsResponse{
-			Suggestions: []Suggestion{},
-			ContextUsed: len(context) > 0,
-			Metadata:    metadata,
+			Suggestions:     []Suggestion{},
+			ContextUsed:     len(context) > 0,
+			Metadata:        metadata,
+			SuggestionCount: suggestionCount,
 		}, nil
 	}
 
-	// 8. Load rules for validation
-	rules, err := s.ruleStorage.LoadRules(tenantID)
-	if err != nil {
-		log.Printf("[AGENT_ASSIST] failed to load rules: %v", err)
-		rules = []*models.Rule{}
+	// 9. Validate suggestions through rule engine and calculate confidence
+	validatedSuggestions := s.validateSuggestions(tenantID, suggestions, rules, metadata, contextScores)
+	s.recordProductRecommendations(tenantID, conversationID, validatedSuggestions)
+
+	log.Printf("[AGENT_ASSIST] generated %d suggestions conversation=%s", len(validatedSuggestions), conversationID)
+
+	response := &SuggestionsResponse{
+		Suggestions:     validatedSuggestions,
+		ContextUsed:     len(context) > 0,
+		Metadata:        metadata,
+		SuggestionCount: suggestionCount,
 	}
 
-	// 9. Validate suggestions through rule engine and calculate confidence
+	// Save to cache after successful generation (only save suggestions array, not metadata)
+	if lastCustomerMessageID != "" {
+		s.saveCachedSuggestions(tenantID, conversationID, lastCustomerMessageID, profiledAgentID, response.Suggestions, len(context) > 0)
+	}
+
+	// Approved pricing is pinned after caching so approvals show up without regenerating
+	response.Suggestions = s.pinApprovedPricing(tenantID, conversationID, response.Suggestions)
+
+	return response, nil
+}
+
+// validateSuggestions runs suggestions through the rule engine, dropping blocked ones and
+// applying corrections, and scores their confidence. Each suggestion gets an ID for feedback.
+func (s *AgentAssistService) validateSuggestions(tenantID string, suggestions []Suggestion, rules []*models.Rule, metadata *models.ConversationMetadata, contextScores []float64) []Suggestion {
+	acceptanceRate := s.intentAcceptanceRate(tenantID, metadata)
 	validatedSuggestions := make([]Suggestion, 0, len(suggestions))
 	for _, sug := range suggestions {
 		// Validate with rule engine
@@ -201,37 +471,20 @@ func (s *AgentAssistService) GetReplySuggestions(tenantID, conversationID string
 		confidenceInputs := ai.ConfidenceInputs{
 			Analysis:       metadata,
 			ContextScores:  contextScores,
-			RuleResults:   validationResult.RuleResults,
+			RuleResults:    validationResult.RuleResults,
 			SelfEvaluation: sug.Confidence,
+			AcceptanceRate: acceptanceRate,
+			TenantID:       tenantID,
+		}
+		sug.RawConfidence = s.confidenceScorer.RawConfidence(confidenceInputs)
+		sug.Confidence = s.confidenceScorer.Calibrate(tenantID, sug.RawConfidence)
+		if sug.ID == "" {
+			sug.ID = uuid.New().String()
 		}
-		sug.Confidence = s.confidenceScorer.CalculateConfidence(confidenceInputs)
 
 		validatedSuggestions = append(validatedSuggestions, sug)
 	}
-
-	log.Printf("[AGENT_ASSIST] generated %d suggestions conversation=%s", len(validatedSuggestions), conversationID)
-
-	response := &SuggestionsResponse{
-		Suggestions: validatedSuggestions,
-		ContextUsed: len(context) > 0,
-		Metadata:    metadata,
-	}
-
-	// Save to cache after successful generation (only save suggestions array, not metadata)
-	if s.suggestionsStorage != nil && lastCustomerMessageID != "" {
-		// Only cache the suggestions array, not the full response (metadata can change)
-		suggestionsData, err := json.Marshal(response.Suggestions)
-		if err == nil {
-			if err := s.suggestionsStorage.SaveSuggestions(conversationID, lastCustomerMessageID, string(suggestionsData), len(context) > 0); err != nil {
-				log.Printf("[AGENT_ASSIST] failed to save suggestions to cache: %v", err)
-				// Don't fail the request if cache save fails
-			} else {
-				log.Printf("[AGENT_ASSIST] saved suggestions to cache conversation=%s last_message=%s", conversationID, lastCustomerMessageID)
-			}
-		}
-	}
-
-	return response, nil
+	return validatedSuggestions
 }
 
 // retrieveContext retrieves relevant context from Chroma
@@ -247,7 +500,10 @@ func (s *AgentAssistService) retrieveContext(tenantID, conversationID string, me
 	// Generate embedding
 	embedding, err := s.embeddingService.GenerateEmbedding(queryText)
 	if err != nil {
-		// Check if error is due to quota/API limits - return empty context gracefully
+		// Check if error is due to quota/API limits or Gemini being down - return empty context gracefully
+		if ai.IsCircuitOpen(err) {
+			return "", []float64{}, nil
+		}
 		if strings.Contains(err.Error(), "quota") || strings.Contains(err.Error(), "Quota") || 
 		   strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "rate limit") {
 			log.Printf("[AGENT_ASSIST] embedding generation blocked by API quota, continuing without context")
@@ -267,11 +523,22 @@ func (s *AgentAssistService) retrieveContext(tenantID, conversationID string, me
 		}
 		return "", []float64{}, fmt.Errorf("failed to retrieve product knowledge: %w", err)
 	}
+	if len(productChunks) == 0 {
+		s.recordKnowledgeGap(tenantID, conversationID, queryText)
+	}
+
+	// Similar past conversations are a bonus; product knowledge is still used if they fail
+	conversationChunks, err := s.retriever.RetrieveConversationContext(tenantID, embedding, pastConversationFanout)
+	if err != nil {
+		log.Printf("[AGENT_ASSIST] past conversation retrieval failed (non-fatal): %v", err)
+		conversationChunks = nil
+	}
 
 	// Build context from chunks
-	contextParts := make([]string, 0, len(productChunks))
-	contextScores := make([]float64, 0, len(productChunks))
-	for _, chunk := range productChunks {
+	chunks := blendContextChunks(productChunks, conversationChunks, conversationID)
+	contextParts := make([]string, 0, len(chunks))
+	contextScores := make([]float64, 0, len(chunks))
+	for _, chunk := range chunks {
 		contextParts = append(contextParts, chunk.Text)
 		contextScores = append(contextScores, chunk.Score)
 	}
@@ -280,6 +547,39 @@ func (s *AgentAssistService) retrieveContext(tenantID, conversationID string, me
 	return context, contextScores, nil
 }
 
+const (
+	// pastConversationFanout is how many transcript chunks are fetched; several may share a conversation
+	pastConversationFanout = 6
+	// maxPastConversations is how many similar past conversations are added to the context
+	maxPastConversations = 2
+)
+
+// blendContextChunks adds the best chunk from each of the most similar past conversations to the
+// product knowledge, ordered by relevance. Chunks from the current conversation are skipped.
+func blendContextChunks(productChunks, conversationChunks []chroma.RetrievedChunk, conversationID string) []chroma.RetrievedChunk {
+	blended := make([]chroma.RetrievedChunk, 0, len(productChunks)+maxPastConversations)
+	blended = append(blended, productChunks...)
+
+	seen := make(map[string]bool)
+	for _, chunk := range conversationChunks {
+		pastID, _ := chunk.Metadata["conversation_id"].(string)
+		if pastID == conversationID || seen[pastID] {
+			continue
+		}
+		if len(seen) == maxPastConversations {
+			break
+		}
+		seen[pastID] = true
+		chunk.Text = "From a similar past conversation:\n" + chunk.Text
+		blended = append(blended, chunk)
+	}
+
+	sort.SliceStable(blended, func(i, j int) bool {
+		return blended[i].Score > blended[j].Score
+	})
+	return blended
+}
+
 // extractCustomerID extracts customer ID from messages (uses conversation ID as proxy for now)
 func (s *AgentAssistService) extractCustomerID(messages []*models.Message) string {
 	// For now, use conversation ID as customer identifier
@@ -301,51 +601,96 @@ func (s *AgentAssistService) extractLastCustomerMessageID(messages []*models.Mes
 	return "" // No customer message found
 }
 
-// generateReplySuggestions generates reply suggestions using AI with multi-language support
+// profiledAgent returns the agent whose profile personalizes suggestions, or "" without a profile
+func profiledAgent(agentProfile *postgres.AgentSuggestionProfile) string {
+	if agentProfile == nil {
+		return ""
+	}
+	return agentProfile.AgentID
+}
+
+// inflightKey identifies identical suggestion requests. Personalized suggestions are only shared
+// between requests for the same agent.
+func inflightKey(tenantID, conversationID, lastCustomerMessageID, profiledAgentID string) string {
+	key := tenantID + "|" + conversationID + "|" + lastCustomerMessageID
+	if profiledAgentID != "" {
+		key += "|" + profiledAgentID
+	}
+	return key
+}
+
+// generateReplySuggestions generates reply suggestions using AI with multi-language support.
+// skipPromptCache forces a fresh model call when the agent asked to regenerate.
 func (s *AgentAssistService) generateReplySuggestions(
+	ctx context.Context,
+	generator ai.TextGenerator,
+	moderator *ai.ContentModerator,
+	tenantID string,
+	conversationID string,
 	messages []*models.Message,
 	context string,
 	customerMemory *models.CustomerMemory,
 	brandTone string,
 	metadata *models.ConversationMetadata,
+	agentProfile *postgres.AgentSuggestionProfile,
 	customerLang string,
 	agentLang string,
+	count int,
+	skipPromptCache bool,
 ) ([]Suggestion, error) {
 	// Build conversation text
 	conversationText := s.buildConversationText(messages)
 
+	// Never send unsafe conversations to the model
+	inputModeration := moderator.Moderate(conversationText)
+	if !inputModeration.IsSafe {
+		log.Printf("[AGENT_ASSIST] WARN conversation blocked by content moderation conversation=%s categories=%s",
+			conversationID, strings.Join(inputModeration.BlockedCategories, ","))
+		s.recordContentBlocked(tenantID, conversationID, "input", inputModeration.BlockedCategories)
+		return []Suggestion{}, ErrContentBlocked
+	}
+
 	// Build prompt with context, customer memory, brand tone, and product recommendations
-	prompt := s.buildSuggestionPrompt(conversationText, context, customerMemory, brandTone, metadata)
+	prompt := s.buildSuggestionPrompt(conversationText, context, customerMemory, brandTone, metadata, agentProfile, s.productRanking(tenantID), count)
 
 	// Use analyzer's translation support if languages differ
 	if customerLang != "" && customerLang != agentLang && s.analyzer != nil {
 		reply, err := s.analyzer.GenerateReplyWithTranslation(messages, agentLang, customerLang, prompt)
 		if err == nil {
 			// Parse the translated reply as a suggestion
-			return []Suggestion{
+			return s.moderateSuggestions(moderator, tenantID, conversationID, []Suggestion{
 				{
 					Text:       reply,
 					Confidence: 0.8,
 					Reasoning:  "Generated with multi-language support",
 				},
-			}, nil
+			}), nil
+		}
+		if ai.IsCircuitOpen(err) {
+			return []Suggestion{}, nil
 		}
 		// If translation fails, log but continue to fallback
 		log.Printf("[AGENT_ASSIST] Translation failed, falling back to direct API call: %v", err)
 	}
 
 	// Fallback to direct API call
-	if s.geminiClient == nil {
+	if generator == nil {
 		log.Printf("[AGENT_ASSIST] Gemini client not available, returning empty suggestions")
 		return []Suggestion{}, nil
 	}
 
 	req := ai.GenerateTextRequest{
-		Prompt:  prompt,
-		Context: context,
+		Prompt:    prompt,
+		Context:   context,
+		SkipCache: skipPromptCache,
 	}
 
-	resp, err := s.geminiClient.GenerateText(req)
+	ai.RecordUsage(s.usageRecorder, tenantID, ai.UsageReplySuggestions)
+	resp, err := generator.GenerateTextContext(ctx, req)
+	if ai.IsCircuitOpen(err) {
+		// Gemini is down; the circuit breaker logged it when it opened
+		return []Suggestion{}, nil
+	}
 	if err != nil {
 		log.Printf("[AGENT_ASSIST] Gemini API error (full): %v", err)
 		errStr := strings.ToLower(err.Error())
@@ -379,9 +724,57 @@ func (s *AgentAssistService) generateReplySuggestions(
 	}
 
 	// Parse suggestions from response
-	suggestions := s.parseSuggestionsResponse(resp.Text)
+	suggestions := s.parseSuggestionsResponse(resp.Text, count)
 
-	return suggestions, nil
+	return s.moderateSuggestions(moderator, tenantID, conversationID, suggestions), nil
+}
+
+// moderateSuggestions drops unsafe suggestions and masks flagged content in the rest
+func (s *AgentAssistService) moderateSuggestions(moderator *ai.ContentModerator, tenantID, conversationID string, suggestions []Suggestion) []Suggestion {
+	moderated := make([]Suggestion, 0, len(suggestions))
+	for _, sug := range suggestions {
+		result := moderator.Moderate(sug.Text)
+		if !result.IsSafe {
+			log.Printf("[AGENT_ASSIST] WARN suggestion blocked by content moderation conversation=%s categories=%s",
+				conversationID, strings.Join(result.BlockedCategories, ","))
+			s.recordContentBlocked(tenantID, conversationID, "suggestion", result.BlockedCategories)
+			continue
+		}
+		sug.Text = result.SafeText
+		moderated = append(moderated, sug)
+	}
+	return moderated
+}
+
+// contentBlockedResponse answers a suggestions request whose conversation failed content moderation
+func contentBlockedResponse(contextUsed bool, metadata *models.ConversationMetadata, count int) *SuggestionsResponse {
+	return &SuggestionsResponse{
+		Suggestions:     []Suggestion{},
+		ContextUsed:     contextUsed,
+		ContentBlocked:  true,
+		Metadata:        metadata,
+		SuggestionCount: count,
+	}
+}
+
+// recordContentBlocked writes a moderation block to the audit log. Content is never logged.
+func (s *AgentAssistService) recordContentBlocked(tenantID, conversationID, stage string, categories []string) {
+	if s.auditStorage == nil {
+		return
+	}
+	entry := &postgres.AuditLog{
+		TenantID:     tenantID,
+		Action:       "content.blocked",
+		ResourceType: "conversation",
+		ResourceID:   &conversationID,
+		NewValue: postgres.AuditValue(map[string]interface{}{
+			"stage":      stage,
+			"categories": categories,
+		}),
+	}
+	if err := s.auditStorage.Record(entry); err != nil {
+		log.Printf("[AGENT_ASSIST] failed to record moderation audit log: %v", err)
+	}
 }
 
 // buildConversationText builds text from messages
@@ -393,17 +786,10 @@ func (s *AgentAssistService) buildConversationText(messages []*models.Message) s
 	return strings.Join(parts, "\n")
 }
 
-// buildSuggestionPrompt builds the prompt for generating suggestions with product recommendations
-func (s *AgentAssistService) buildSuggestionPrompt(
-	conversationText string,
-	context string,
-	customerMemory *models.CustomerMemory,
-	brandTone string,
-	metadata *models.ConversationMetadata,
-) string {
-	prompt := `Generate 3 reply suggestions for an agent responding to this customer conversation.
+// suggestionPromptTemplate is the base suggestion prompt; {{count}} is replaced with the tenant's suggestion count
+const suggestionPromptTemplate = `Generate {{count}} reply suggestions for an agent responding to this customer conversation.
 Each suggestion should be:
-- Professional and helpful
+- Helpful and written in this brand tone: {{brand_tone}}
 - Context-aware (use conversation history)
 - Product-aware (use product knowledge if relevant)
 - Personalized (consider customer preferences if available)
@@ -411,19 +797,40 @@ Each suggestion should be:
 
 For each suggestion, also suggest relevant products if applicable.
 
-Return suggestions as JSON array:
+Return exactly {{count}} suggestions as a JSON array of {{count}} objects, best first:
 [
-  {"text": "suggestion 1", "confidence": 0.85, "reasoning": "why this suggestion", "product_recommendations": ["product1", "product2"]},
-  {"text": "suggestion 2", "confidence": 0.80, "reasoning": "why this suggestion", "product_recommendations": []},
-  {"text": "suggestion 3", "confidence": 0.75, "reasoning": "why this suggestion", "product_recommendations": ["product3"]}
+  {"text": "suggestion 1", "confidence": 0.85, "reasoning": "why this suggestion", "product_recommendations": ["product1", "product2"]}
 ]
 
 Conversation:
-` + conversationText
+`
+
+// defaultBrandTone is used for tenants without a configured brand tone
+const defaultBrandTone = "Professional"
+
+// buildSuggestionPrompt builds the prompt for generating count suggestions with product recommendations.
+// productRanking lists the tenant's products by recommendation conversion rate, best first.
+func (s *AgentAssistService) buildSuggestionPrompt(
+	conversationText string,
+	context string,
+	customerMemory *models.CustomerMemory,
+	brandTone string,
+	metadata *models.ConversationMetadata,
+	agentProfile *postgres.AgentSuggestionProfile,
+	productRanking []postgres.ProductConversion,
+	count int,
+) string {
+	// The tenant's tone description goes into the instructions verbatim; it is replaced last so
+	// placeholders inside it are left alone
+	if strings.TrimSpace(brandTone) == "" {
+		brandTone = defaultBrandTone
+	}
+	prompt := strings.ReplaceAll(suggestionPromptTemplate, "{{count}}", strconv.Itoa(count))
+	prompt = strings.Replace(prompt, "{{brand_tone}}", brandTone, 1) + conversationText
 
 	// Add context if available
 	if context != "" {
-		prompt = "Product Knowledge Context:\n" + context + "\n\n" + prompt
+		prompt = "Product Knowledge Context (excerpts marked as from a similar past conversation show what was asked and what worked there):\n" + context + "\n\n" + prompt
 	}
 
 	// Add customer memory if available
@@ -436,11 +843,6 @@ Conversation:
 		prompt = memoryInfo + "\n" + prompt
 	}
 
-	// Add brand tone instruction
-	if brandTone != "" {
-		prompt = fmt.Sprintf("Brand Tone: %s\n\n", brandTone) + prompt
-	}
-
 	// Add metadata insights
 	if metadata != nil {
 		insights := fmt.Sprintf("Conversation Insights:\n- Intent: %s (score: %.2f)\n- Sentiment: %s (score: %.2f)\n- Objections: %s\n",
@@ -450,11 +852,21 @@ Conversation:
 		prompt = insights + "\n" + prompt
 	}
 
+	// Point the model at the products agents have accepted most often
+	if len(productRanking) > 0 {
+		prompt = productRankingPrompt(productRanking) + "\n" + prompt
+	}
+
+	// Match the requesting agent's usual style
+	if agentProfile != nil {
+		prompt = agentPreference(agentProfile) + "\n\n" + prompt
+	}
+
 	return prompt
 }
 
-// parseSuggestionsResponse parses JSON suggestions from AI response
-func (s *AgentAssistService) parseSuggestionsResponse(responseText string) []Suggestion {
+// parseSuggestionsResponse parses JSON suggestions from AI response, keeping at most count
+func (s *AgentAssistService) parseSuggestionsResponse(responseText string, count int) []Suggestion {
 	// Try to extract JSON array
 	jsonStart := strings.Index(responseText, "[")
 	jsonEnd := strings.LastIndex(responseText, "]")
@@ -504,23 +916,46 @@ func (s *AgentAssistService) parseSuggestionsResponse(responseText string) []Sug
 		})
 	}
 
-	return result
+	if len(result) > count {
+		log.Printf("[AGENT_ASSIST] model returned %d suggestions, keeping %d", len(result), count)
+	}
+	return trimSuggestions(result, count)
 }
 
-// detectCustomerLanguage detects customer language from messages
-func (s *AgentAssistService) detectCustomerLanguage(messages []*models.Message) string {
-	if len(messages) == 0 {
-		return ""
+// detectCustomerLanguage returns the conversation's override language if set, otherwise the
+// language of the customer's messages weighted by detection confidence, so one confidently
+// detected message outweighs a few short, doubtful ones. Ties go to the most recent language.
+// conv may be nil.
+func (s *AgentAssistService) detectCustomerLanguage(conv *models.Conversation, messages []*models.Message) string {
+	if conv != nil && conv.OverrideLanguage != nil && *conv.OverrideLanguage != "" {
+		return *conv.OverrideLanguage
 	}
-	
-	// Use language from last customer message if available
+
+	weights := make(map[string]float32)
+	var newestFirst []string
 	for i := len(messages) - 1; i >= 0; i-- {
-		if messages[i].Sender == "customer" && messages[i].Language != "" && messages[i].Language != "unknown" {
-			return messages[i].Language
+		msg := messages[i]
+		if msg.Sender != "customer" || msg.Language == "" || msg.Language == "unknown" {
+			continue
+		}
+		// Messages stored before confidence was recorded were only labelled when detection was reliable
+		weight := msg.LanguageConfidence
+		if weight <= 0 {
+			weight = 1
 		}
+		if _, seen := weights[msg.Language]; !seen {
+			newestFirst = append(newestFirst, msg.Language)
+		}
+		weights[msg.Language] += weight
 	}
-	
-	return ""
+
+	best := ""
+	for _, language := range newestFirst {
+		if best == "" || weights[language] > weights[best] {
+			best = language
+		}
+	}
+	return best
 }
 
 // getBrandTone retrieves brand tone configuration
@@ -0,0 +1,215 @@
+package agentassist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/ai/circuitbreaker"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
+)
+
+func TestBuildSuggestionPromptIncludesBrandToneVerbatim(t *testing.T) {
+	s := &AgentAssistService{}
+	tone := `Empathetic for healthcare: acknowledge worries first, avoid "jargon" & never promise outcomes {{count}}`
+
+	prompt := s.buildSuggestionPrompt("customer: I'm worried about side effects", "", nil, tone, nil, nil, nil, 3)
+
+	if want := "- Helpful and written in this brand tone: " + tone + "\n"; !strings.Contains(prompt, want) {
+		t.Errorf("prompt does not contain the tone instruction %q:\n%s", want, prompt)
+	}
+	if got := strings.Count(prompt, tone); got != 1 {
+		t.Errorf("tone appears %d times, want once", got)
+	}
+	if !strings.Contains(prompt, "Generate 3 reply suggestions") {
+		t.Errorf("prompt lost the suggestion count:\n%s", prompt)
+	}
+}
+
+func TestBuildSuggestionPromptDefaultsToProfessionalTone(t *testing.T) {
+	s := &AgentAssistService{}
+	prompt := s.buildSuggestionPrompt("customer: hi", "", nil, "  ", nil, nil, nil, 1)
+	if !strings.Contains(prompt, "- Helpful and written in this brand tone: Professional\n") {
+		t.Errorf("prompt without a tone = %q, want the Professional default", prompt)
+	}
+	if strings.Contains(prompt, "{{brand_tone}}") {
+		t.Error("prompt still contains the brand tone placeholder")
+	}
+}
+
+// fakeAcceptanceRates returns a fixed acceptance rate and sample count for every intent
+type fakeAcceptanceRates struct {
+	rate    float64
+	samples int
+}
+
+func (f fakeAcceptanceRates) GetIntentAcceptanceRate(tenantID, intent string) (float64, int, error) {
+	return f.rate, f.samples, nil
+}
+
+func TestIntentAcceptanceRateNeedsEnoughFeedback(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	buying := &models.ConversationMetadata{Intent: "buying"}
+	if rate := s.intentAcceptanceRate("tenant-1", buying); rate != nil {
+		t.Errorf("rate without a source = %v, want nil", *rate)
+	}
+
+	s.SetAcceptanceRateSource(fakeAcceptanceRates{rate: 0.8, samples: minAcceptanceSamples - 1}, 0.2)
+	if rate := s.intentAcceptanceRate("tenant-1", buying); rate != nil {
+		t.Errorf("rate from too little feedback = %v, want nil", *rate)
+	}
+
+	s.SetAcceptanceRateSource(fakeAcceptanceRates{rate: 0.8, samples: minAcceptanceSamples}, 0.2)
+	if rate := s.intentAcceptanceRate("tenant-1", buying); rate == nil || *rate != 0.8 {
+		t.Errorf("rate = %v, want 0.8", rate)
+	}
+	if rate := s.intentAcceptanceRate("tenant-1", &models.ConversationMetadata{}); rate != nil {
+		t.Errorf("rate without an intent = %v, want nil", *rate)
+	}
+}
+
+// failingGenerator fails every call with err
+type failingGenerator struct {
+	err   error
+	calls int
+}
+
+func (g *failingGenerator) GenerateText(req ai.GenerateTextRequest) (*ai.GenerateTextResponse, error) {
+	return g.GenerateTextContext(context.Background(), req)
+}
+
+func (g *failingGenerator) GenerateTextContext(ctx context.Context, req ai.GenerateTextRequest) (*ai.GenerateTextResponse, error) {
+	g.calls++
+	return nil, g.err
+}
+
+func TestGenerateReplySuggestionsEmptyWhileCircuitOpen(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	generator := &failingGenerator{err: &ai.ChainError{Errors: []ai.ProviderError{
+		{Provider: ai.ProviderGemini, Err: circuitbreaker.ErrCircuitOpen},
+	}}}
+	messages := []*models.Message{{Sender: "customer", Content: "Is there a discount?"}}
+
+	suggestions, err := s.generateReplySuggestions(context.Background(), generator, nil, "tenant-1", "conv-1",
+		messages, "", nil, "", nil, nil, "", "", 3, false)
+	if err != nil {
+		t.Fatalf("generateReplySuggestions err = %v, want nil", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("suggestions = %+v, want none", suggestions)
+	}
+	if generator.calls != 1 {
+		t.Errorf("calls = %d, want 1", generator.calls)
+	}
+}
+
+// replyGenerator answers every call with a fixed model response
+type replyGenerator struct {
+	text  string
+	calls int
+}
+
+func (g *replyGenerator) GenerateText(req ai.GenerateTextRequest) (*ai.GenerateTextResponse, error) {
+	return g.GenerateTextContext(context.Background(), req)
+}
+
+func (g *replyGenerator) GenerateTextContext(ctx context.Context, req ai.GenerateTextRequest) (*ai.GenerateTextResponse, error) {
+	g.calls++
+	return &ai.GenerateTextResponse{Text: g.text}, nil
+}
+
+func TestGenerateReplySuggestionsModeratesSuggestions(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	moderator := ai.NewContentModerator(rules.NewRuleEngine(), nil)
+	generator := &replyGenerator{text: `[
+		{"text": "Go die, we're out of stock", "confidence": 0.9, "reasoning": "blocked"},
+		{"text": "Sorry, this shit happens with couriers. It ships Monday.", "confidence": 0.8, "reasoning": "masked"},
+		{"text": "It ships Monday.", "confidence": 0.7, "reasoning": "clean"}
+	]`}
+	messages := []*models.Message{{Sender: "customer", Content: "When does my order ship?"}}
+
+	suggestions, err := s.generateReplySuggestions(context.Background(), generator, moderator, "tenant-1", "conv-1",
+		messages, "", nil, "", nil, nil, "", "", 3, false)
+	if err != nil {
+		t.Fatalf("generateReplySuggestions: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("suggestions = %+v, want the blocked one dropped", suggestions)
+	}
+	if got := suggestions[0].Text; got != "Sorry, this **** happens with couriers. It ships Monday." {
+		t.Errorf("flagged suggestion = %q, want the profanity masked", got)
+	}
+	if got := suggestions[1].Text; got != "It ships Monday." {
+		t.Errorf("clean suggestion = %q, want it unchanged", got)
+	}
+}
+
+func TestGenerateReplySuggestionsBlocksUnsafeConversations(t *testing.T) {
+	s := NewAgentAssistService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	moderator := ai.NewContentModerator(rules.NewRuleEngine(), nil)
+	generator := &replyGenerator{text: `[{"text": "Happy to help", "confidence": 0.9}]`}
+	messages := []*models.Message{{Sender: "customer", Content: "Refund me or I'm going to find you"}}
+
+	suggestions, err := s.generateReplySuggestions(context.Background(), generator, moderator, "tenant-1", "conv-1",
+		messages, "", nil, "", nil, nil, "", "", 3, false)
+	if !errors.Is(err, ErrContentBlocked) {
+		t.Fatalf("err = %v, want ErrContentBlocked", err)
+	}
+	if suggestions == nil || len(suggestions) != 0 {
+		t.Errorf("suggestions = %#v, want an empty list", suggestions)
+	}
+	if generator.calls != 0 {
+		t.Errorf("calls = %d, want the conversation never sent to the model", generator.calls)
+	}
+}
+
+func TestContentBlockedResponse(t *testing.T) {
+	metadata := &models.ConversationMetadata{Intent: "complaint"}
+	encoded, err := json.Marshal(contentBlockedResponse(true, metadata, 3))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var response map[string]interface{}
+	json.Unmarshal(encoded, &response)
+	if response["content_blocked"] != true || response["context_used"] != true || response["suggestion_count"] != float64(3) {
+		t.Errorf("response = %s, want content_blocked with the request's context and count", encoded)
+	}
+	if suggestions, ok := response["suggestions"].([]interface{}); !ok || len(suggestions) != 0 {
+		t.Errorf("suggestions = %v, want an empty array, not null", response["suggestions"])
+	}
+}
+
+func TestDetectCustomerLanguageWeightsByConfidence(t *testing.T) {
+	s := &AgentAssistService{}
+	msg := func(sender, language string, confidence float32) *models.Message {
+		return &models.Message{Sender: sender, Language: language, LanguageConfidence: confidence}
+	}
+
+	// One confident Hindi message outweighs a later doubtful Gujarati one
+	messages := []*models.Message{
+		msg("customer", "hi", 0.95),
+		msg("agent", "en", 1),
+		msg("customer", "gu", 0.4),
+		msg("customer", "unknown", 0),
+	}
+	if got := s.detectCustomerLanguage(nil, messages); got != "hi" {
+		t.Errorf("language = %s, want hi", got)
+	}
+
+	// Equal weight goes to the most recent language
+	if got := s.detectCustomerLanguage(nil, []*models.Message{msg("customer", "hi", 0.8), msg("customer", "mr", 0.8)}); got != "mr" {
+		t.Errorf("tied language = %s, want the latest, mr", got)
+	}
+
+	override := "ta"
+	if got := s.detectCustomerLanguage(&models.Conversation{OverrideLanguage: &override}, messages); got != "ta" {
+		t.Errorf("language = %s, want the override", got)
+	}
+	if got := s.detectCustomerLanguage(nil, []*models.Message{msg("agent", "en", 1)}); got != "" {
+		t.Errorf("language without customer messages = %q, want empty", got)
+	}
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// AgentPerformance holds an agent's KPIs over the conversations assigned to them
+type AgentPerformance struct {
+	AgentID                 string    `json:"agent_id"`
+	From                    time.Time `json:"from"`
+	To                      time.Time `json:"to"`
+	TotalConversations      int       `json:"total_conversations"`
+	AvgFirstResponseMinutes float64   `json:"avg_first_response_minutes"`
+	AvgQualityScore         float64   `json:"avg_quality_score"` // 0-100
+	AutoReplyOverrides      int       `json:"auto_reply_overrides"`
+	ChurnRate               float64   `json:"churn_rate"`        // 0-1, share of conversations at risk of churn
+	AvgTransferRate         float64   `json:"avg_transfer_rate"` // 0-1, share of handled conversations transferred away
+}
+
+// GetAgentPerformance calculates an agent's KPIs for the conversations assigned to them and
+// created in the time range. Quality and churn risk are scored per conversation, as for
+// CalculateQualityScore and CalculateChurnRisk, from messages and metadata loaded for all the
+// conversations at once.
+func (s *AnalyticsService) GetAgentPerformance(tenantID, agentID string, from, to time.Time) (AgentPerformance, error) {
+	stats, err := s.conversationStorage.GetAgentPerformanceStats(tenantID, agentID, from, to)
+	if err != nil {
+		return AgentPerformance{}, err
+	}
+	messages, err := s.conversationStorage.GetMessagesByConversations(tenantID, stats.ConversationIDs)
+	if err != nil {
+		return AgentPerformance{}, err
+	}
+	metadata, err := s.conversationStorage.GetConversationMetadataByConversations(tenantID, stats.ConversationIDs)
+	if err != nil {
+		return AgentPerformance{}, err
+	}
+
+	config := s.TenantConfig(tenantID)
+	qualities := make([]QualityScore, 0, len(stats.ConversationIDs))
+	risks := make([]ChurnRisk, 0, len(stats.ConversationIDs))
+	for _, conversationID := range stats.ConversationIDs {
+		qualities = append(qualities, s.qualityScore(config, conversationID, messages[conversationID], metadata[conversationID]))
+		risks = append(risks, s.churnRisk(config, conversationID, messages[conversationID], metadata[conversationID]))
+	}
+
+	return agentPerformance(agentID, from, to, stats, qualities, risks), nil
+}
+
+// agentPerformance combines an agent's stored statistics with their conversations' scores
+func agentPerformance(agentID string, from, to time.Time, stats *postgres.AgentPerformanceStats, qualities []QualityScore, risks []ChurnRisk) AgentPerformance {
+	performance := AgentPerformance{
+		AgentID:                 agentID,
+		From:                    from,
+		To:                      to,
+		TotalConversations:      len(stats.ConversationIDs),
+		AvgFirstResponseMinutes: stats.AvgFirstResponseMinutes,
+		AutoReplyOverrides:      stats.AutoReplyOverrides,
+	}
+
+	if len(qualities) > 0 {
+		total := 0.0
+		for _, quality := range qualities {
+			total += quality.Score
+		}
+		performance.AvgQualityScore = total / float64(len(qualities))
+	}
+
+	if len(risks) > 0 {
+		atRisk := 0
+		for _, risk := range risks {
+			if risk.IsAtRisk {
+				atRisk++
+			}
+		}
+		performance.ChurnRate = float64(atRisk) / float64(len(risks))
+	}
+
+	if stats.HandledConversations > 0 {
+		performance.AvgTransferRate = float64(stats.TransferredAway) / float64(stats.HandledConversations)
+	}
+	return performance
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func TestAgentPerformanceAggregatesScores(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	stats := &postgres.AgentPerformanceStats{
+		ConversationIDs:         []string{"conv-1", "conv-2", "conv-3", "conv-4"},
+		RespondedConversations:  3,
+		AvgFirstResponseMinutes: 4.5,
+		AutoReplyOverrides:      1,
+		HandledConversations:    5,
+		TransferredAway:         2,
+	}
+	qualities := []QualityScore{
+		{ConversationID: "conv-1", Score: 90},
+		{ConversationID: "conv-2", Score: 70},
+		{ConversationID: "conv-3", Score: 50},
+		{ConversationID: "conv-4", Score: 30},
+	}
+	risks := []ChurnRisk{
+		{ConversationID: "conv-1", RiskScore: 0.1},
+		{ConversationID: "conv-2", RiskScore: 0.8, IsAtRisk: true},
+		{ConversationID: "conv-3", RiskScore: 0.2},
+		{ConversationID: "conv-4", RiskScore: 0.9, IsAtRisk: true},
+	}
+
+	got := agentPerformance("agent-1", from, to, stats, qualities, risks)
+	want := AgentPerformance{
+		AgentID:                 "agent-1",
+		From:                    from,
+		To:                      to,
+		TotalConversations:      4,
+		AvgFirstResponseMinutes: 4.5,
+		AvgQualityScore:         60,
+		AutoReplyOverrides:      1,
+		ChurnRate:               0.5,
+		AvgTransferRate:         0.4,
+	}
+	if got != want {
+		t.Errorf("agentPerformance = %+v, want %+v", got, want)
+	}
+}
+
+func TestAgentPerformanceWithoutConversations(t *testing.T) {
+	got := agentPerformance("agent-1", time.Time{}, time.Time{}, &postgres.AgentPerformanceStats{}, nil, nil)
+	if got.TotalConversations != 0 || got.AvgQualityScore != 0 || got.ChurnRate != 0 || got.AvgTransferRate != 0 {
+		t.Errorf("agentPerformance = %+v, want zero KPIs", got)
+	}
+}
@@ -0,0 +1,136 @@
+package analytics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+)
+
+// weightSumTolerance is how far a component's weights may sum from 1
+const weightSumTolerance = 1e-6
+
+// ErrInvalidAnalyticsConfig is returned when a tenant's analytics config can't be used
+var ErrInvalidAnalyticsConfig = errors.New("invalid analytics config")
+
+// AnalyticsConfigStore persists tenants' analytics config overrides as JSON
+// (see postgres.AnalyticsConfigStorage)
+type AnalyticsConfigStore interface {
+	ListAnalyticsConfigs() (map[string]string, error)
+	SaveAnalyticsConfig(tenantID, configJSON string) error
+}
+
+// Validate checks that each scoring component's weights are between 0 and 1 and sum to 1, and
+// that thresholds and defaults are in range
+func (c AnalyticsConfig) Validate() error {
+	components := []struct {
+		name    string
+		weights map[string]float64
+	}{
+		{"lead score", map[string]float64{
+			"lead_score_intent_weight":     c.LeadScoreIntentWeight,
+			"lead_score_engagement_weight": c.LeadScoreEngagementWeight,
+			"lead_score_sentiment_weight":  c.LeadScoreSentimentWeight,
+		}},
+		{"win probability", map[string]float64{
+			"win_prob_intent_weight":        c.WinProbIntentWeight,
+			"win_prob_sentiment_weight":     c.WinProbSentimentWeight,
+			"win_prob_objection_weight":     c.WinProbObjectionWeight,
+			"win_prob_response_time_weight": c.WinProbResponseTimeWeight,
+			"win_prob_duration_weight":      c.WinProbDurationWeight,
+		}},
+	}
+	for _, component := range components {
+		sum := 0.0
+		for name, weight := range component.weights {
+			if weight < 0 || weight > 1 {
+				return fmt.Errorf("%w: %s must be between 0 and 1", ErrInvalidAnalyticsConfig, name)
+			}
+			sum += weight
+		}
+		if math.Abs(sum-1) > weightSumTolerance {
+			return fmt.Errorf("%w: %s weights must sum to 1, got %.4f", ErrInvalidAnalyticsConfig, component.name, sum)
+		}
+	}
+
+	if c.ChurnRiskThreshold < 0 || c.ChurnRiskThreshold > 1 {
+		return fmt.Errorf("%w: churn_risk_threshold must be between 0 and 1", ErrInvalidAnalyticsConfig)
+	}
+	if c.EscalationSentimentThreshold < 0 || c.EscalationSentimentThreshold > 1 {
+		return fmt.Errorf("%w: escalation_sentiment_threshold must be between 0 and 1", ErrInvalidAnalyticsConfig)
+	}
+	if c.DefaultDealValue <= 0 || c.DefaultSalesCycleDays <= 0 || c.DefaultCLV <= 0 {
+		return fmt.Errorf("%w: default_deal_value, default_sales_cycle_days and default_clv must be positive", ErrInvalidAnalyticsConfig)
+	}
+	return nil
+}
+
+// SetConfigStore loads tenants' analytics config overrides from the store, so admins can tune
+// weights without redeploying (optional)
+func (s *AnalyticsService) SetConfigStore(store AnalyticsConfigStore) {
+	s.configStore = store
+	if err := s.ReloadConfig(); err != nil {
+		log.Printf("[ANALYTICS] failed to load tenant analytics configs, using defaults: %v", err)
+	}
+}
+
+// ReloadConfig reloads every tenant's analytics config override from the store. Overrides that
+// fail to parse or validate are logged and skipped, so their tenant gets the default config.
+func (s *AnalyticsService) ReloadConfig() error {
+	if s.configStore == nil {
+		return nil
+	}
+	stored, err := s.configStore.ListAnalyticsConfigs()
+	if err != nil {
+		return err
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	configs := make(map[string]AnalyticsConfig, len(stored))
+	for tenantID, configJSON := range stored {
+		config := s.config
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			log.Printf("[ANALYTICS] ignoring unreadable analytics config tenant=%s: %v", tenantID, err)
+			continue
+		}
+		if err := config.Validate(); err != nil {
+			log.Printf("[ANALYTICS] ignoring analytics config tenant=%s: %v", tenantID, err)
+			continue
+		}
+		configs[tenantID] = config
+	}
+	s.tenantConfigs = configs
+	return nil
+}
+
+// TenantConfig returns the analytics config that applies to the tenant: its override when it
+// has one, otherwise the default config
+func (s *AnalyticsService) TenantConfig(tenantID string) AnalyticsConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	if config, ok := s.tenantConfigs[tenantID]; ok {
+		return config
+	}
+	return s.config
+}
+
+// UpdateTenantConfig validates and stores the tenant's analytics config override, then reloads
+// the configs so it applies immediately. Only the fields with a JSON name are stored.
+func (s *AnalyticsService) UpdateTenantConfig(tenantID string, config AnalyticsConfig) error {
+	if s.configStore == nil {
+		return errors.New("analytics config storage is not configured")
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics config: %w", err)
+	}
+	if err := s.configStore.SaveAnalyticsConfig(tenantID, string(configJSON)); err != nil {
+		return err
+	}
+	return s.ReloadConfig()
+}
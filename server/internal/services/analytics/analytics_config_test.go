@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeConfigStore keeps analytics config overrides in memory
+type fakeConfigStore struct {
+	configs map[string]string
+	err     error
+}
+
+func (f *fakeConfigStore) ListAnalyticsConfigs() (map[string]string, error) {
+	return f.configs, f.err
+}
+
+func (f *fakeConfigStore) SaveAnalyticsConfig(tenantID, configJSON string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.configs[tenantID] = configJSON
+	return nil
+}
+
+func TestUpdateTenantConfigChangesLeadScores(t *testing.T) {
+	service := NewAnalyticsService(nil, nil, nil)
+	store := &fakeConfigStore{configs: map[string]string{}}
+	service.SetConfigStore(store)
+
+	// A hot buyer who barely engages
+	intent, engagement, sentiment := 0.9, 0.1, 0.5
+	before := service.TenantConfig("tenant-1").leadScore(intent, engagement, sentiment) // 36+3+15 = 54
+
+	config := service.TenantConfig("tenant-1")
+	config.LeadScoreIntentWeight, config.LeadScoreEngagementWeight, config.LeadScoreSentimentWeight = 0.8, 0.1, 0.1
+	if err := service.UpdateTenantConfig("tenant-1", config); err != nil {
+		t.Fatalf("UpdateTenantConfig: %v", err)
+	}
+
+	after := service.TenantConfig("tenant-1").leadScore(intent, engagement, sentiment) // 72+1+5 = 78
+	if after <= before || after < 77.9 || after > 78.1 {
+		t.Errorf("lead score = %.1f after weighting intent up, want 78 (was %.1f)", after, before)
+	}
+	if other := service.TenantConfig("tenant-2").leadScore(intent, engagement, sentiment); other != before {
+		t.Errorf("other tenant's lead score = %.1f, want the default %.1f", other, before)
+	}
+
+	// The override survives a reload, e.g. by another instance at startup
+	reloaded := NewAnalyticsService(nil, nil, nil)
+	reloaded.SetConfigStore(store)
+	if got := reloaded.TenantConfig("tenant-1").LeadScoreIntentWeight; got != 0.8 {
+		t.Errorf("reloaded intent weight = %v, want 0.8", got)
+	}
+	// Settings that aren't per tenant come from the service's config
+	if got := reloaded.TenantConfig("tenant-1").TrendWindow; got != DefaultTrendWindowConfig() {
+		t.Errorf("trend window = %+v, want the default", got)
+	}
+}
+
+func TestUpdateTenantConfigRejectsInvalidWeights(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*AnalyticsConfig)
+	}{
+		{"lead score weights over 1", func(c *AnalyticsConfig) { c.LeadScoreIntentWeight = 0.5 }},
+		{"win probability weights under 1", func(c *AnalyticsConfig) { c.WinProbDurationWeight = 0 }},
+		{"negative weight", func(c *AnalyticsConfig) {
+			c.LeadScoreIntentWeight, c.LeadScoreEngagementWeight, c.LeadScoreSentimentWeight = 1.2, -0.1, -0.1
+		}},
+		{"churn threshold out of range", func(c *AnalyticsConfig) { c.ChurnRiskThreshold = 1.5 }},
+		{"no default deal value", func(c *AnalyticsConfig) { c.DefaultDealValue = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAnalyticsService(nil, nil, nil)
+			store := &fakeConfigStore{configs: map[string]string{}}
+			service.SetConfigStore(store)
+
+			config := DefaultAnalyticsConfig()
+			tt.modify(&config)
+			if err := service.UpdateTenantConfig("tenant-1", config); !errors.Is(err, ErrInvalidAnalyticsConfig) {
+				t.Fatalf("UpdateTenantConfig err = %v, want ErrInvalidAnalyticsConfig", err)
+			}
+			if len(store.configs) != 0 {
+				t.Errorf("stored %v, want nothing", store.configs)
+			}
+		})
+	}
+}
+
+func TestReloadConfigSkipsInvalidOverrides(t *testing.T) {
+	service := NewAnalyticsService(nil, nil, nil)
+	service.SetConfigStore(&fakeConfigStore{configs: map[string]string{
+		"broken":  "{not json",
+		"invalid": `{"lead_score_intent_weight": 0.9}`,
+		"valid":   `{"win_prob_intent_weight": 0.35, "win_prob_duration_weight": 0.05, "churn_risk_threshold": 0.4}`,
+	}})
+
+	for _, tenantID := range []string{"broken", "invalid"} {
+		if got := service.TenantConfig(tenantID); got.LeadScoreIntentWeight != 0.4 {
+			t.Errorf("%s tenant config = %+v, want the default", tenantID, got)
+		}
+	}
+	// Fields missing from an override keep their default
+	valid := service.TenantConfig("valid")
+	if valid.WinProbIntentWeight != 0.35 || valid.ChurnRiskThreshold != 0.4 || valid.WinProbSentimentWeight != 0.25 {
+		t.Errorf("valid tenant config = %+v, want the override on top of the defaults", valid)
+	}
+}
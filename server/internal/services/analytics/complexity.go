@@ -0,0 +1,26 @@
+package analytics
+
+import (
+	"ai-conversation-platform/internal/ai"
+)
+
+// GetComplexity scores the current complexity of a conversation
+func (s *AnalyticsService) GetComplexity(tenantID, conversationID string) (ai.ComplexityScore, error) {
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		return ai.ComplexityScore{}, err
+	}
+
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		metadata = nil
+	}
+
+	return ai.NewComplexityScorer().Score(messages, metadata), nil
+}
+
+// GetComplexityDistribution returns conversation counts per complexity bucket
+// (low 1-3, medium 4-6, high 7-10)
+func (s *AnalyticsService) GetComplexityDistribution(tenantID string) (map[string]int, error) {
+	return s.conversationStorage.GetComplexityDistribution(tenantID)
+}
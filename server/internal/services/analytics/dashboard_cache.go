@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/cache"
+)
+
+// dashboardCacheTTL is how long computed dashboard metrics are reused
+const dashboardCacheTTL = time.Minute
+
+// SetCache caches dashboard metrics (optional)
+func (s *AnalyticsService) SetCache(c cache.Cache) {
+	s.responseCache = c
+}
+
+// GetDashboardMetrics returns the tenant's dashboard metrics over the conversations matching
+// filters, computed at most once a minute per tenant and filters. See dashboardMetrics.
+func (s *AnalyticsService) GetDashboardMetrics(tenantID string, filters DashboardFilters) (DashboardMetrics, error) {
+	return cache.Fetch(s.responseCache, dashboardCacheKey(tenantID, filters), dashboardCacheTTL, func() (DashboardMetrics, error) {
+		return s.dashboardMetrics(tenantID, filters)
+	})
+}
+
+// RefreshDashboardMetrics recomputes the tenant's dashboard metrics, ignoring and replacing any
+// cached ones. Used by live dashboards, which must not show minute-old numbers.
+func (s *AnalyticsService) RefreshDashboardMetrics(tenantID string, filters DashboardFilters) (DashboardMetrics, error) {
+	metrics, err := s.dashboardMetrics(tenantID, filters)
+	if err != nil {
+		return metrics, err
+	}
+	if s.responseCache != nil {
+		if data, err := json.Marshal(metrics); err == nil {
+			s.responseCache.Set(dashboardCacheKey(tenantID, filters), data, dashboardCacheTTL)
+		}
+	}
+	return metrics, nil
+}
+
+func dashboardCacheKey(tenantID string, filters DashboardFilters) string {
+	return fmt.Sprintf("analytics:dashboard:%s:%s:%s:%s", tenantID,
+		filters.StartDate.UTC().Format(time.RFC3339Nano), filters.EndDate.UTC().Format(time.RFC3339Nano), filters.Status)
+}
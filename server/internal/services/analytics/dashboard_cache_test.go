@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/cache"
+)
+
+func TestGetDashboardMetricsServedFromCache(t *testing.T) {
+	responseCache := cache.NewMemoryCache(10)
+	// No conversation storage: a cache miss would reach the database and panic
+	service := NewAnalyticsService(nil, nil, nil)
+	service.SetCache(responseCache)
+
+	filters := DashboardFilters{StartDate: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Status: "active"}
+	cached := DashboardMetrics{TotalConversations: 12, ActiveConversations: 4, WinRate: 0.25}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	responseCache.Set(dashboardCacheKey("tenant-1", filters), data, dashboardCacheTTL)
+
+	metrics, err := service.GetDashboardMetrics("tenant-1", filters)
+	if err != nil {
+		t.Fatalf("GetDashboardMetrics: %v", err)
+	}
+	if metrics.TotalConversations != 12 || metrics.ActiveConversations != 4 || metrics.WinRate != 0.25 {
+		t.Errorf("metrics = %+v, want the cached metrics", metrics)
+	}
+}
+
+func TestDashboardCacheKeySeparatesTenantsAndFilters(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	keys := map[string]bool{}
+	for _, key := range []string{
+		dashboardCacheKey("tenant-1", DashboardFilters{}),
+		dashboardCacheKey("tenant-2", DashboardFilters{}),
+		dashboardCacheKey("tenant-1", DashboardFilters{StartDate: start}),
+		dashboardCacheKey("tenant-1", DashboardFilters{EndDate: start}),
+		dashboardCacheKey("tenant-1", DashboardFilters{Status: "closed"}),
+	} {
+		if keys[key] {
+			t.Errorf("duplicate dashboard cache key %q", key)
+		}
+		keys[key] = true
+	}
+
+	// The same instant in another zone is the same dashboard
+	local := start.In(time.FixedZone("IST", 5*3600+1800))
+	if dashboardCacheKey("tenant-1", DashboardFilters{StartDate: start}) != dashboardCacheKey("tenant-1", DashboardFilters{StartDate: local}) {
+		t.Error("dashboard cache key depends on the filter's time zone")
+	}
+}
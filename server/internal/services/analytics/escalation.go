@@ -0,0 +1,151 @@
+package analytics
+
+import (
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/rules"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// EventConversationEscalated is published when a conversation is escalated
+const EventConversationEscalated = "conversation.escalated"
+
+// EventPublisher delivers events to external subscribers (e.g. webhooks)
+type EventPublisher interface {
+	Publish(tenantID, eventType string, payload map[string]interface{})
+}
+
+// EscalationStore records escalations, at most one open per conversation
+type EscalationStore interface {
+	CreateEscalationIfAbsent(event *postgres.EscalationEvent) (bool, error)
+	ResolveEscalation(tenantID, conversationID string) (bool, error)
+}
+
+// EscalationNotifier is told about new escalations (e.g. to post to Slack)
+type EscalationNotifier interface {
+	NotifyEscalation(tenantID, conversationID string, winProbability float64, recommendedAction, details string)
+}
+
+// RuleLoader loads a tenant's rules, of which the escalate rules are used
+type RuleLoader interface {
+	LoadRules(tenantID string) ([]*models.Rule, error)
+}
+
+// SetEscalationStorage enables escalation after each analysis (optional). rules may be nil, in
+// which case only sentiment escalates.
+func (s *AnalyticsService) SetEscalationStorage(escalations EscalationStore, rules RuleLoader) {
+	s.escalationStorage = escalations
+	s.ruleLoader = rules
+}
+
+// SetEscalationNotifier sets a notifier for new escalations (optional)
+func (s *AnalyticsService) SetEscalationNotifier(notifier EscalationNotifier) {
+	s.escalationNotifier = notifier
+}
+
+// SetEventPublisher sets the event publisher (optional)
+func (s *AnalyticsService) SetEventPublisher(eventPublisher EventPublisher) {
+	s.eventPublisher = eventPublisher
+}
+
+// CheckEscalation escalates a conversation whose sentiment fell below
+// EscalationSentimentThreshold or whose last customer message matches an escalate rule, and
+// resolves its open escalation once neither holds. It runs after each analysis.
+func (s *AnalyticsService) CheckEscalation(tenantID, conversationID string) error {
+	if s.escalationStorage == nil {
+		return nil
+	}
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return err
+	}
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to load analysis: %w", err)
+	}
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to load messages: %w", err)
+	}
+
+	lastCustomerMessage := ""
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Sender == "customer" {
+			lastCustomerMessage = messages[i].Content
+			break
+		}
+	}
+	_, err = s.applyEscalation(tenantID, conversationID, conv.AssignedAgentID, metadata.SentimentScore, lastCustomerMessage)
+	return err
+}
+
+// escalationTrigger returns why a conversation should be escalated, if it should
+func (s *AnalyticsService) escalationTrigger(tenantID string, sentimentScore float64, lastCustomerMessage string) (reason, details string, escalate bool) {
+	config := s.TenantConfig(tenantID)
+	if sentimentScore < config.EscalationSentimentThreshold {
+		return postgres.EscalationReasonSentiment,
+			fmt.Sprintf("sentiment %.2f below %.2f", sentimentScore, config.EscalationSentimentThreshold), true
+	}
+	if s.ruleLoader == nil || lastCustomerMessage == "" {
+		return "", "", false
+	}
+	tenantRules, err := s.ruleLoader.LoadRules(tenantID)
+	if err != nil {
+		log.Printf("[ESCALATION] failed to load rules tenant=%s, checking sentiment only: %v", tenantID, err)
+		return "", "", false
+	}
+	if rule, matched := rules.NewRuleEngine().MatchEscalationRule(lastCustomerMessage, tenantRules); rule != nil {
+		return postgres.EscalationReasonRule, fmt.Sprintf("rule %q matched %q", rule.Name, matched), true
+	}
+	return "", "", false
+}
+
+// applyEscalation escalates or resolves the conversation. Returns true if a new escalation was created.
+func (s *AnalyticsService) applyEscalation(tenantID, conversationID string, assignedTo *string, sentimentScore float64, lastCustomerMessage string) (bool, error) {
+	reason, details, escalate := s.escalationTrigger(tenantID, sentimentScore, lastCustomerMessage)
+	if !escalate {
+		resolved, err := s.escalationStorage.ResolveEscalation(tenantID, conversationID)
+		if err != nil {
+			return false, err
+		}
+		if resolved {
+			log.Printf("[ESCALATION] resolved tenant=%s conversation=%s sentiment=%.2f", tenantID, conversationID, sentimentScore)
+		}
+		return false, nil
+	}
+
+	event := &postgres.EscalationEvent{
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		SentimentScore: sentimentScore,
+		Reason:         reason,
+		Details:        details,
+		AssignedTo:     assignedTo,
+	}
+	created, err := s.escalationStorage.CreateEscalationIfAbsent(event)
+	if err != nil || !created {
+		return false, err
+	}
+	log.Printf("[ESCALATION] escalated tenant=%s conversation=%s reason=%s %s", tenantID, conversationID, reason, details)
+
+	if s.eventPublisher != nil {
+		payload := map[string]interface{}{
+			"escalation_id":   event.ID,
+			"conversation_id": conversationID,
+			"sentiment_score": sentimentScore,
+			"reason":          reason,
+			"details":         details,
+		}
+		if assignedTo != nil {
+			payload["assigned_to"] = *assignedTo
+		}
+		s.eventPublisher.Publish(tenantID, EventConversationEscalated, payload)
+	}
+	if s.escalationNotifier != nil {
+		s.escalationNotifier.NotifyEscalation(tenantID, conversationID, 0, "Review the conversation and reply to the customer",
+			"Escalated: "+details)
+	}
+	return true, nil
+}
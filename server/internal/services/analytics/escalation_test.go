@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeEscalationStore keeps open escalations per conversation, like the unique index does
+type fakeEscalationStore struct {
+	open     map[string]*postgres.EscalationEvent
+	resolved int
+}
+
+func (f *fakeEscalationStore) CreateEscalationIfAbsent(event *postgres.EscalationEvent) (bool, error) {
+	if f.open[event.ConversationID] != nil {
+		return false, nil
+	}
+	event.ID = "esc-" + event.ConversationID
+	f.open[event.ConversationID] = event
+	return true, nil
+}
+
+func (f *fakeEscalationStore) ResolveEscalation(tenantID, conversationID string) (bool, error) {
+	if f.open[conversationID] == nil {
+		return false, nil
+	}
+	delete(f.open, conversationID)
+	f.resolved++
+	return true, nil
+}
+
+type fakeRuleLoader struct {
+	rules []*models.Rule
+	err   error
+}
+
+func (f *fakeRuleLoader) LoadRules(tenantID string) ([]*models.Rule, error) { return f.rules, f.err }
+
+type publishedEvent struct {
+	eventType string
+	payload   map[string]interface{}
+}
+
+type fakeEventPublisher struct{ events []publishedEvent }
+
+func (f *fakeEventPublisher) Publish(tenantID, eventType string, payload map[string]interface{}) {
+	f.events = append(f.events, publishedEvent{eventType, payload})
+}
+
+type notifiedEscalation struct {
+	tenantID, conversationID, details string
+}
+
+type fakeEscalationNotifier struct{ escalations []notifiedEscalation }
+
+func (f *fakeEscalationNotifier) NotifyEscalation(tenantID, conversationID string, winProbability float64, recommendedAction, details string) {
+	f.escalations = append(f.escalations, notifiedEscalation{tenantID, conversationID, details})
+}
+
+func newEscalationTestService(rules []*models.Rule) (*AnalyticsService, *fakeEscalationStore, *fakeEventPublisher) {
+	s := NewAnalyticsService(nil, nil, nil)
+	store := &fakeEscalationStore{open: make(map[string]*postgres.EscalationEvent)}
+	publisher := &fakeEventPublisher{}
+	s.SetEscalationStorage(store, &fakeRuleLoader{rules: rules})
+	s.SetEventPublisher(publisher)
+	return s, store, publisher
+}
+
+func TestEscalationTriggersBelowSentimentThreshold(t *testing.T) {
+	s, store, publisher := newEscalationTestService(nil)
+	agent := "agent-1"
+
+	// At the threshold nothing happens
+	if created, err := s.applyEscalation("tenant-1", "conv-1", &agent, 0.25, "Hmm"); err != nil || created {
+		t.Fatalf("applyEscalation at threshold = %v, %v; want no escalation", created, err)
+	}
+	created, err := s.applyEscalation("tenant-1", "conv-1", &agent, 0.1, "This is useless")
+	if err != nil || !created {
+		t.Fatalf("applyEscalation below threshold = %v, %v; want escalation", created, err)
+	}
+
+	event := store.open["conv-1"]
+	if event == nil || event.Reason != postgres.EscalationReasonSentiment || event.SentimentScore != 0.1 || *event.AssignedTo != agent {
+		t.Errorf("escalation = %+v, want a sentiment escalation assigned to %s", event, agent)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].eventType != EventConversationEscalated {
+		t.Fatalf("published = %+v, want one conversation.escalated event", publisher.events)
+	}
+	payload := publisher.events[0].payload
+	if payload["conversation_id"] != "conv-1" || payload["escalation_id"] != "esc-conv-1" || payload["assigned_to"] != agent {
+		t.Errorf("payload = %v, want the conversation, escalation and assigned agent", payload)
+	}
+}
+
+func TestEscalationDeduplicatedWhileOpen(t *testing.T) {
+	s, store, publisher := newEscalationTestService(nil)
+
+	for i, score := range []float64{0.2, 0.1, 0.05} {
+		created, err := s.applyEscalation("tenant-1", "conv-1", nil, score, "")
+		if err != nil {
+			t.Fatalf("applyEscalation: %v", err)
+		}
+		if created != (i == 0) {
+			t.Errorf("analysis %d created = %v, want only the first to escalate", i, created)
+		}
+	}
+	if len(store.open) != 1 || len(publisher.events) != 1 {
+		t.Errorf("open = %d, published = %d; want one escalation and one event", len(store.open), len(publisher.events))
+	}
+}
+
+func TestEscalationResolvesWhenSentimentRecovers(t *testing.T) {
+	s, store, publisher := newEscalationTestService(nil)
+
+	s.applyEscalation("tenant-1", "conv-1", nil, 0.1, "")
+	if _, err := s.applyEscalation("tenant-1", "conv-1", nil, 0.6, "Thanks, that works"); err != nil {
+		t.Fatalf("applyEscalation: %v", err)
+	}
+	if len(store.open) != 0 || store.resolved != 1 {
+		t.Fatalf("open = %d, resolved = %d; want the escalation resolved", len(store.open), store.resolved)
+	}
+
+	// It escalates again if sentiment drops again
+	if created, _ := s.applyEscalation("tenant-1", "conv-1", nil, 0.1, ""); !created {
+		t.Error("conversation didn't escalate again after its escalation was resolved")
+	}
+	if len(publisher.events) != 2 {
+		t.Errorf("published %d events, want one per escalation", len(publisher.events))
+	}
+}
+
+func TestEscalationNotifiesNewEscalations(t *testing.T) {
+	s, _, _ := newEscalationTestService(nil)
+	notifier := &fakeEscalationNotifier{}
+	s.SetEscalationNotifier(notifier)
+
+	// Only the escalation that opens is notified, not repeats while it stays open or its resolution
+	for _, score := range []float64{0.1, 0.05, 0.6} {
+		if _, err := s.applyEscalation("tenant-1", "conv-1", nil, score, ""); err != nil {
+			t.Fatalf("applyEscalation: %v", err)
+		}
+	}
+	if len(notifier.escalations) != 1 {
+		t.Fatalf("notified = %+v, want one escalation", notifier.escalations)
+	}
+	got := notifier.escalations[0]
+	if got.tenantID != "tenant-1" || got.conversationID != "conv-1" || !strings.Contains(got.details, "sentiment 0.10 below 0.25") {
+		t.Errorf("notification = %+v, want conv-1 with the sentiment reason", got)
+	}
+}
+
+func TestEscalationRules(t *testing.T) {
+	rule := &models.Rule{ID: "r1", Name: "Cancellation", Pattern: `(?i)\bcancel\b`, Action: models.RuleActionEscalate, IsActive: true}
+	s, store, _ := newEscalationTestService([]*models.Rule{rule})
+
+	created, err := s.applyEscalation("tenant-1", "conv-1", nil, 0.8, "Please cancel my subscription")
+	if err != nil || !created {
+		t.Fatalf("applyEscalation = %v, %v; want the rule to escalate despite good sentiment", created, err)
+	}
+	if event := store.open["conv-1"]; event.Reason != postgres.EscalationReasonRule {
+		t.Errorf("reason = %s, want rule", event.Reason)
+	}
+
+	// The escalation stays open while the latest customer message still matches
+	s.applyEscalation("tenant-1", "conv-1", nil, 0.8, "I said CANCEL")
+	if store.resolved != 0 {
+		t.Error("escalation resolved while the rule still matched")
+	}
+	s.applyEscalation("tenant-1", "conv-1", nil, 0.8, "Actually the discount works for me")
+	if store.resolved != 1 {
+		t.Error("escalation wasn't resolved once the rule stopped matching")
+	}
+}
+
+func TestEscalationRuleLoadFailureChecksSentimentOnly(t *testing.T) {
+	s, _, _ := newEscalationTestService(nil)
+	s.SetEscalationStorage(s.escalationStorage, &fakeRuleLoader{err: errors.New("database down")})
+
+	if created, err := s.applyEscalation("tenant-1", "conv-1", nil, 0.8, "cancel"); err != nil || created {
+		t.Errorf("applyEscalation = %v, %v; want no escalation without rules", created, err)
+	}
+	if created, err := s.applyEscalation("tenant-1", "conv-1", nil, 0.1, "cancel"); err != nil || !created {
+		t.Errorf("applyEscalation = %v, %v; want sentiment to still escalate", created, err)
+	}
+}
+
+func TestEscalationThresholdConfigurable(t *testing.T) {
+	s, _, _ := newEscalationTestService(nil)
+	config := s.config
+	config.EscalationSentimentThreshold = 0
+	s.SetConfig(config)
+
+	if created, _ := s.applyEscalation("tenant-1", "conv-1", nil, 0, ""); created {
+		t.Error("a zero threshold escalated a conversation")
+	}
+}
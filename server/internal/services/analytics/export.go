@@ -0,0 +1,243 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Export types
+const (
+	ExportTypeLeads            = "leads"
+	ExportTypeDashboard        = "dashboard"
+	ExportTypeAgentPerformance = "agent_performance"
+)
+
+// exportPageSize is how many conversations are scored per page when streaming leads
+const exportPageSize = 1000
+
+// IsValidExportType checks if an export type is supported
+func IsValidExportType(exportType string) bool {
+	switch exportType {
+	case ExportTypeLeads, ExportTypeDashboard, ExportTypeAgentPerformance:
+		return true
+	}
+	return false
+}
+
+// CSVExporter writes analytics rows as CSV. Columns come from `csv:"..."` struct tags;
+// untagged fields are left out. An exporter writes the header row once, so successive
+// calls append pages to the same stream.
+type CSVExporter struct {
+	headerWritten bool
+}
+
+// NewCSVExporter creates a CSV exporter for a single export stream
+func NewCSVExporter() *CSVExporter {
+	return &CSVExporter{}
+}
+
+// ExportLeads writes prioritized leads as CSV
+func (e *CSVExporter) ExportLeads(writer io.Writer, leads []PrioritizedLead) error {
+	rows := make([]interface{}, len(leads))
+	for i := range leads {
+		rows[i] = leads[i]
+	}
+	return e.export(writer, reflect.TypeOf(PrioritizedLead{}), rows)
+}
+
+// leadPipelineColumns are the columns of the leads pipeline spreadsheet
+var leadPipelineColumns = []string{
+	"conversation_id", "customer_email", "win_probability", "urgency_score", "deal_value",
+	"priority_score", "lead_stage", "recommended_action", "risk_flags", "last_message_time",
+}
+
+// ExportLeadPipeline writes leads as the pipeline spreadsheet sales managers download: scores,
+// stage and next action per lead, with risk flags joined by ";". Like the other exports it
+// can be called once per page of leads, so large exports are streamed.
+func (e *CSVExporter) ExportLeadPipeline(writer io.Writer, leads []PrioritizedLead) error {
+	w := csv.NewWriter(writer)
+	if !e.headerWritten {
+		if err := w.Write(leadPipelineColumns); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		e.headerWritten = true
+	}
+
+	for _, lead := range leads {
+		lastMessageTime := ""
+		if lead.Engagement != nil {
+			lastMessageTime = lead.Engagement.LastMessageTime
+		}
+		record := []string{
+			lead.ConversationID,
+			stringOrEmpty(lead.CustomerEmail),
+			strconv.FormatFloat(lead.WinProbability, 'f', -1, 64),
+			strconv.FormatFloat(lead.UrgencyScore, 'f', -1, 64),
+			strconv.FormatFloat(lead.DealValue, 'f', -1, 64),
+			strconv.FormatFloat(lead.PriorityScore, 'f', -1, 64),
+			stringOrEmpty(lead.LeadStage),
+			stringOrEmpty(lead.RecommendedAction),
+			strings.Join(lead.RiskFlags, ";"),
+			lastMessageTime,
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+	return nil
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ExportAgentPerformance writes leaderboard entries as CSV
+func (e *CSVExporter) ExportAgentPerformance(writer io.Writer, entries []AgentLeaderboardEntry) error {
+	rows := make([]interface{}, len(entries))
+	for i := range entries {
+		rows[i] = entries[i]
+	}
+	return e.export(writer, reflect.TypeOf(AgentLeaderboardEntry{}), rows)
+}
+
+// ExportDashboard writes dashboard metrics as a single CSV row
+func (e *CSVExporter) ExportDashboard(writer io.Writer, metrics DashboardMetrics) error {
+	return e.export(writer, reflect.TypeOf(DashboardMetrics{}), []interface{}{metrics})
+}
+
+func (e *CSVExporter) export(writer io.Writer, rowType reflect.Type, rows []interface{}) error {
+	w := csv.NewWriter(writer)
+	fields := csvFields(rowType)
+
+	if !e.headerWritten {
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.name
+		}
+		if err := w.Write(header); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		e.headerWritten = true
+	}
+
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			record[i] = csvValue(v.Field(f.index))
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+	return nil
+}
+
+// csvField is a struct field exported as a CSV column
+type csvField struct {
+	name  string
+	index int
+}
+
+// csvFields returns the columns of a struct type in declaration order
+func csvFields(t reflect.Type) []csvField {
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("csv")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fields = append(fields, csvField{name: tag, index: i})
+	}
+	return fields
+}
+
+// csvValue formats a field value as a CSV cell; nil pointers become empty cells
+// and string slices are joined with "; "
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts[i] = csvValue(v.Index(i))
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
+
+// StreamLeads scores the tenant's conversations created within [from, to] one page
+// at a time and passes each page of leads to fn, so exports never hold every lead
+// in memory. Leads are sorted by priority within a page.
+func (s *AnalyticsService) StreamLeads(tenantID string, from, to time.Time, fn func(leads []PrioritizedLead) error) error {
+	filters := &postgres.ConversationFilters{CreatedAfter: &from, CreatedBefore: &to}
+	cursor := ""
+	for {
+		conversations, nextCursor, err := s.conversationStorage.ListConversations(tenantID, filters, exportPageSize, cursor)
+		if err != nil {
+			return err
+		}
+
+		conversationIDs := make([]string, 0, len(conversations))
+		for _, conv := range conversations {
+			conversationIDs = append(conversationIDs, conv.ID)
+		}
+
+		if len(conversationIDs) > 0 {
+			leads, err := s.PrioritizeLeads(tenantID, conversationIDs)
+			if err != nil {
+				return err
+			}
+			if len(leads) > 0 {
+				if err := fn(leads); err != nil {
+					return err
+				}
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
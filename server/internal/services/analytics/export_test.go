@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readCSV parses an export, failing the test on malformed output
+func readCSV(t *testing.T, data string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v\n%s", err, data)
+	}
+	return records
+}
+
+func TestCSVExporterUsesTagsForColumns(t *testing.T) {
+	type row struct {
+		ID       string    `csv:"id"`
+		Internal string    // Untagged fields are left out
+		Skipped  string    `csv:"-"`
+		Owner    *string   `csv:"owner"`
+		Score    float64   `csv:"score"`
+		Count    int       `csv:"count"`
+		Active   bool      `csv:"active"`
+		Labels   []string  `csv:"labels"`
+		Seen     time.Time `csv:"seen"`
+	}
+	owner := "agent-1"
+	seen := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	rows := []interface{}{
+		row{ID: "c1", Internal: "x", Skipped: "y", Owner: &owner, Score: 0.85, Count: 3, Active: true, Labels: []string{"vip", "renewal"}, Seen: seen},
+		row{ID: "c2"},
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVExporter().export(&buf, reflect.TypeOf(row{}), rows); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	want := [][]string{
+		{"id", "owner", "score", "count", "active", "labels", "seen"},
+		{"c1", "agent-1", "0.85", "3", "true", "vip; renewal", "2026-10-15T09:30:00Z"},
+		{"c2", "", "0", "0", "false", "", "0001-01-01T00:00:00Z"},
+	}
+	if got := readCSV(t, buf.String()); !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+}
+
+func TestCSVExporterExportLeads(t *testing.T) {
+	email := "buyer@example.com"
+	var buf bytes.Buffer
+	leads := []PrioritizedLead{{
+		ConversationID: "c1",
+		CustomerEmail:  &email,
+		WinProbability: 0.7,
+		RiskFlags:      []string{"price_objection", "silent, 3 days"},
+		Tags:           []string{"enterprise"},
+	}}
+	if err := NewCSVExporter().ExportLeads(&buf, leads); err != nil {
+		t.Fatalf("ExportLeads: %v", err)
+	}
+
+	records := readCSV(t, buf.String())
+	if len(records) != 2 {
+		t.Fatalf("records = %q, want a header and one lead", records)
+	}
+	values := make(map[string]string)
+	for i, column := range records[0] {
+		values[column] = records[1][i]
+	}
+	if values["conversation_id"] != "c1" || values["customer_email"] != email || values["win_probability"] != "0.7" {
+		t.Errorf("lead = %v, want c1 with its email and win probability", values)
+	}
+	if values["risk_flags"] != "price_objection; silent, 3 days" || values["tags"] != "enterprise" {
+		t.Errorf("risk_flags = %q, tags = %q; want slices joined with \"; \"", values["risk_flags"], values["tags"])
+	}
+	if _, ok := values["engagement"]; ok {
+		t.Error("untagged engagement field exported")
+	}
+}
+
+func TestCSVExporterWritesHeaderOnceAcrossPages(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewCSVExporter()
+	for _, page := range [][]PrioritizedLead{{{ConversationID: "c1"}}, {{ConversationID: "c2"}, {ConversationID: "c3"}}, nil} {
+		if err := exporter.ExportLeads(&buf, page); err != nil {
+			t.Fatalf("ExportLeads: %v", err)
+		}
+	}
+
+	records := readCSV(t, buf.String())
+	if len(records) != 4 || records[0][0] != "conversation_id" {
+		t.Fatalf("records = %q, want one header and three leads", records)
+	}
+	for i, want := range []string{"c1", "c2", "c3"} {
+		if records[i+1][0] != want {
+			t.Errorf("row %d = %s, want %s", i+1, records[i+1][0], want)
+		}
+	}
+
+	// A new exporter starts a new stream with its own header
+	buf.Reset()
+	if err := NewCSVExporter().ExportLeads(&buf, nil); err != nil {
+		t.Fatalf("ExportLeads: %v", err)
+	}
+	if records := readCSV(t, buf.String()); len(records) != 1 || records[0][0] != "conversation_id" {
+		t.Errorf("empty export = %q, want just the header", records)
+	}
+}
+
+func TestCSVExporterLeadPipelinePages(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewCSVExporter()
+	if err := exporter.ExportLeadPipeline(&buf, []PrioritizedLead{{ConversationID: "c1", RiskFlags: []string{"a", "b"}}}); err != nil {
+		t.Fatalf("ExportLeadPipeline: %v", err)
+	}
+	if err := exporter.ExportLeadPipeline(&buf, []PrioritizedLead{{ConversationID: "c2"}}); err != nil {
+		t.Fatalf("ExportLeadPipeline: %v", err)
+	}
+
+	records := readCSV(t, buf.String())
+	if len(records) != 3 || !reflect.DeepEqual(records[0], leadPipelineColumns) {
+		t.Fatalf("records = %q, want the pipeline header once and two leads", records)
+	}
+	if records[1][8] != "a;b" || records[2][0] != "c2" {
+		t.Errorf("rows = %q, want risk flags joined with \";\"", records[1:])
+	}
+}
+
+func TestCSVExporterExportDashboard(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCSVExporter().ExportDashboard(&buf, DashboardMetrics{TotalConversations: 12, WinRate: 0.25}); err != nil {
+		t.Fatalf("ExportDashboard: %v", err)
+	}
+
+	records := readCSV(t, buf.String())
+	if len(records) != 2 || records[0][0] != "total_conversations" || records[1][0] != "12" {
+		t.Fatalf("records = %q, want the dashboard as one row", records)
+	}
+	for i, column := range records[0] {
+		if column == "win_rate" && records[1][i] != "0.25" {
+			t.Errorf("win_rate = %s, want 0.25", records[1][i])
+		}
+	}
+}
@@ -3,9 +3,13 @@ package analytics
 import (
 	"log"
 	"math"
+	"os"
 	"sort"
+	"strconv"
+	"sync"
 	"time"
 
+	"ai-conversation-platform/internal/cache"
 	"ai-conversation-platform/internal/models"
 	"ai-conversation-platform/internal/storage/postgres"
 )
@@ -72,41 +76,69 @@ type EngagementMetrics struct {
 
 // PrioritizedLead represents a prioritized lead
 type PrioritizedLead struct {
-	ConversationID    string            `json:"conversation_id"`
-	CustomerEmail     *string           `json:"customer_email,omitempty"`
-	WinProbability    float64           `json:"win_probability"`
-	UrgencyScore      float64           `json:"urgency_score"`
-	DealValue         float64           `json:"deal_value"`
-	PriorityScore     float64           `json:"priority_score"`
-	LeadContext       *LeadContext      `json:"lead_context,omitempty"`
-	AIInsights        *AIInsights       `json:"ai_insights,omitempty"`
+	ConversationID    string             `json:"conversation_id" csv:"conversation_id"`
+	CustomerEmail     *string            `json:"customer_email,omitempty" csv:"customer_email"`
+	AssignedAgentID   *string            `json:"assigned_agent_id,omitempty" csv:"assigned_agent_id"`
+	WinProbability    float64            `json:"win_probability" csv:"win_probability"`
+	UrgencyScore      float64            `json:"urgency_score" csv:"urgency_score"`
+	DealValue         float64            `json:"deal_value" csv:"deal_value"`
+	PriorityScore     float64            `json:"priority_score" csv:"priority_score"`
+	LeadContext       *LeadContext       `json:"lead_context,omitempty"`
+	AIInsights        *AIInsights        `json:"ai_insights,omitempty"`
 	Engagement        *EngagementMetrics `json:"engagement,omitempty"`
-	RecommendedAction *string           `json:"recommended_action,omitempty"`
-	LeadStage         *string           `json:"lead_stage,omitempty"`
-	RiskFlags         []string          `json:"risk_flags,omitempty"`
+	RecommendedAction *string            `json:"recommended_action,omitempty" csv:"recommended_action"`
+	LeadStage         *string            `json:"lead_stage,omitempty" csv:"lead_stage"`
+	RiskFlags         []string           `json:"risk_flags,omitempty" csv:"risk_flags"`
+	ComplexityScore   float64            `json:"complexity_score" csv:"complexity_score"` // 1-10, 0 when not yet analyzed
+	Watchlisted       bool               `json:"watchlisted" csv:"watchlisted"`
+	SLAStatus         string             `json:"sla_status,omitempty" csv:"sla_status"` // ok, pending or breached; empty when untracked
+	SLA               *SLAStatus         `json:"sla,omitempty"`                          // Deadline behind SLAStatus and the product whose SLA applies
+	Tags              []string           `json:"tags,omitempty" csv:"tags"`
+	Segment           string             `json:"segment,omitempty" csv:"segment"` // Customer segment, see SegmentCustomers
+	HasNotes          bool               `json:"has_notes" csv:"has_notes"`       // Agents have left internal notes on the conversation
 }
 
-// AnalyticsConfig contains configurable weights and thresholds
+// AnalyticsConfig contains configurable weights and thresholds. Fields with a JSON name can be
+// overridden per tenant (see UpdateTenantConfig); the others apply to every tenant.
 type AnalyticsConfig struct {
 	// Lead scoring weights
-	LeadScoreIntentWeight      float64
-	LeadScoreEngagementWeight  float64
-	LeadScoreSentimentWeight   float64
+	LeadScoreIntentWeight      float64 `json:"lead_score_intent_weight"`
+	LeadScoreEngagementWeight  float64 `json:"lead_score_engagement_weight"`
+	LeadScoreSentimentWeight   float64 `json:"lead_score_sentiment_weight"`
 
 	// Win probability weights
-	WinProbIntentWeight        float64
-	WinProbSentimentWeight     float64
-	WinProbObjectionWeight     float64
-	WinProbResponseTimeWeight  float64
-	WinProbDurationWeight      float64
+	WinProbIntentWeight        float64 `json:"win_prob_intent_weight"`
+	WinProbSentimentWeight     float64 `json:"win_prob_sentiment_weight"`
+	WinProbObjectionWeight     float64 `json:"win_prob_objection_weight"`
+	WinProbResponseTimeWeight  float64 `json:"win_prob_response_time_weight"`
+	WinProbDurationWeight      float64 `json:"win_prob_duration_weight"`
 
 	// Churn risk thresholds
-	ChurnRiskThreshold         float64
+	ChurnRiskThreshold         float64 `json:"churn_risk_threshold"`
 
 	// Default values
-	DefaultDealValue           float64
-	DefaultSalesCycleDays      float64
-	DefaultCLV                 float64
+	DefaultDealValue           float64 `json:"default_deal_value"`
+	DefaultSalesCycleDays      float64 `json:"default_sales_cycle_days"`
+	DefaultCLV                 float64 `json:"default_clv"`
+
+	// Auto-replies more similar than this (Jaccard, 0-1) to the previous auto-reply aren't sent
+	AutoReplySimilarityThreshold float64 `json:"-"`
+
+	// Message windows compared by sentiment and emotion trends
+	TrendWindow TrendWindowConfig `json:"-"`
+
+	// Past objections kept in customer memory; the oldest are dropped first (0 keeps all)
+	MaxObjectionsRetained int `json:"-"`
+
+	// Weight (0-1) of the historic acceptance rate of suggestions for the conversation's intent
+	// in suggestion confidence (0 ignores it)
+	SuggestionAcceptanceWeight float64 `json:"-"`
+
+	// Thresholds of the customer segmentation decision tree
+	Segmentation SegmentationConfig `json:"-"`
+
+	// Conversations whose sentiment score (0-1) falls below this are escalated (0 disables)
+	EscalationSentimentThreshold float64 `json:"escalation_sentiment_threshold"`
 }
 
 // DefaultAnalyticsConfig returns default configuration
@@ -124,6 +156,12 @@ func DefaultAnalyticsConfig() AnalyticsConfig {
 		DefaultDealValue:          1000.0,
 		DefaultSalesCycleDays:     30.0,
 		DefaultCLV:                5000.0,
+		AutoReplySimilarityThreshold: 0.7,
+		TrendWindow:               DefaultTrendWindowConfig(),
+		MaxObjectionsRetained:     20,
+		SuggestionAcceptanceWeight: 0.2,
+		Segmentation:              DefaultSegmentationConfig(),
+		EscalationSentimentThreshold: 0.25,
 	}
 }
 
@@ -132,29 +170,82 @@ type AnalyticsService struct {
 	conversationStorage *postgres.ConversationStorage
 	trendAnalyzer       *TrendAnalyzer
 	config              AnalyticsConfig
+	leaderboardCache    *leaderboardCache
+	leadStageStorage    *postgres.LeadStageStorage
+	hotLeadAlertStorage *postgres.HotLeadAlertStorage
+	hotLeadNotifier     HotLeadNotifier
+	watchlistStorage    *postgres.WatchlistStorage
+	slaStorage          *postgres.SLAStorage
+	tagStorage          *postgres.TagStorage
+	noteStorage         *postgres.NoteStorage
+	feedbackStorage     *postgres.SuggestionFeedbackStorage
+	objectionStorage    *postgres.ObjectionResolutionStorage
+	memoryStorage       *postgres.MemoryStorage
+	responseCache       cache.Cache
+	escalationStorage   EscalationStore
+	escalationNotifier  EscalationNotifier
+	ruleLoader          RuleLoader
+	eventPublisher      EventPublisher
+	stageMu             sync.Mutex
+	configStore         AnalyticsConfigStore       // Optional per-tenant config overrides
+	tenantConfigs       map[string]AnalyticsConfig // Loaded from configStore by ReloadConfig
+	configMu            sync.RWMutex               // Guards config and tenantConfigs
 }
 
 // NewAnalyticsService creates a new analytics service
 func NewAnalyticsService(
 	conversationStorage *postgres.ConversationStorage,
+	leadStageStorage *postgres.LeadStageStorage,
+	hotLeadAlertStorage *postgres.HotLeadAlertStorage,
 ) *AnalyticsService {
 	return &AnalyticsService{
 		conversationStorage: conversationStorage,
+		leadStageStorage:    leadStageStorage,
+		hotLeadAlertStorage: hotLeadAlertStorage,
 		trendAnalyzer:       NewTrendAnalyzer(),
 		config:              DefaultAnalyticsConfig(),
+		leaderboardCache:    newLeaderboardCache(),
 	}
 }
 
-// SetConfig updates the analytics configuration
+// SetHotLeadNotifier sets a notifier for new hot lead alerts (optional)
+func (s *AnalyticsService) SetHotLeadNotifier(notifier HotLeadNotifier) {
+	s.hotLeadNotifier = notifier
+}
+
+// SetWatchlistStorage enables the priority boost for watchlisted conversations (optional)
+func (s *AnalyticsService) SetWatchlistStorage(watchlistStorage *postgres.WatchlistStorage) {
+	s.watchlistStorage = watchlistStorage
+}
+
+// SetSLAStorage enables response time SLA reporting (optional)
+func (s *AnalyticsService) SetSLAStorage(slaStorage *postgres.SLAStorage) {
+	s.slaStorage = slaStorage
+}
+
+// SetTagStorage adds conversation tags to prioritized leads (optional)
+func (s *AnalyticsService) SetTagStorage(tagStorage *postgres.TagStorage) {
+	s.tagStorage = tagStorage
+}
+
+// SetNoteStorage marks prioritized leads whose conversations have agent notes (optional)
+func (s *AnalyticsService) SetNoteStorage(noteStorage *postgres.NoteStorage) {
+	s.noteStorage = noteStorage
+}
+
+// SetConfig updates the analytics configuration tenants without overrides use
 func (s *AnalyticsService) SetConfig(config AnalyticsConfig) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
 	s.config = config
 }
 
 // CalculateLeadScore calculates lead score for a conversation
-// Weighted sum: buying intent (0.4), engagement (0.3), sentiment trend (0.3)
+// Weighted sum: buying intent (0.4), engagement (0.3), sentiment trend (0.3) by default; see TenantConfig
 func (s *AnalyticsService) CalculateLeadScore(
 	tenantID, conversationID string,
 ) (LeadScore, error) {
+	config := s.TenantConfig(tenantID)
 	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
 	if err != nil {
 		return LeadScore{}, err
@@ -165,7 +256,7 @@ func (s *AnalyticsService) CalculateLeadScore(
 		return LeadScore{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
 		// If no metadata, return default score
 		return LeadScore{ConversationID: conversationID, Score: 50.0}, nil
@@ -178,34 +269,37 @@ func (s *AnalyticsService) CalculateLeadScore(
 	engagementSignal := s.calculateEngagementSignal(messages, conv.CreatedAt)
 
 	// Calculate sentiment trend (0-1)
-	trends := s.trendAnalyzer.AnalyzeTrends(messages, metadata)
+	trends := s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, config.TrendWindow)
 	sentimentTrendSignal := s.trendToSignal(trends.SentimentTrend)
 
-	// Weighted sum
-	score := intentSignal*s.config.LeadScoreIntentWeight +
-		engagementSignal*s.config.LeadScoreEngagementWeight +
-		sentimentTrendSignal*s.config.LeadScoreSentimentWeight
-
-	// Scale to 0-100
-	score = score * 100.0
-	score = math.Max(0.0, math.Min(100.0, score))
-
 	return LeadScore{
 		ConversationID: conversationID,
-		Score:          score,
+		Score:          config.leadScore(intentSignal, engagementSignal, sentimentTrendSignal),
 	}, nil
 }
 
+// leadScore weighs the lead signals (0-1) into a score from 0 to 100
+func (c AnalyticsConfig) leadScore(intentSignal, engagementSignal, sentimentTrendSignal float64) float64 {
+	score := intentSignal*c.LeadScoreIntentWeight +
+		engagementSignal*c.LeadScoreEngagementWeight +
+		sentimentTrendSignal*c.LeadScoreSentimentWeight
+
+	// Scale to 0-100
+	score = score * 100.0
+	return math.Max(0.0, math.Min(100.0, score))
+}
+
 // CalculateWinProbability calculates win probability for a conversation
 func (s *AnalyticsService) CalculateWinProbability(
 	tenantID, conversationID string,
 ) (WinProbability, error) {
+	config := s.TenantConfig(tenantID)
 	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
 	if err != nil {
 		return WinProbability{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
 		return WinProbability{ConversationID: conversationID, Probability: 0.5}, nil
 	}
@@ -219,7 +313,7 @@ func (s *AnalyticsService) CalculateWinProbability(
 	intentStrength := metadata.IntentScore
 
 	// Sentiment trend (0-1)
-	trends := s.trendAnalyzer.AnalyzeTrends(messages, metadata)
+	trends := s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, config.TrendWindow)
 	sentimentTrendSignal := s.trendToSignal(trends.SentimentTrend)
 
 	// Objection frequency (inverted: fewer objections = higher probability)
@@ -232,11 +326,11 @@ func (s *AnalyticsService) CalculateWinProbability(
 	durationSignal := s.calculateDurationSignal(conv.CreatedAt, time.Now())
 
 	// Weighted sum
-	probability := intentStrength*s.config.WinProbIntentWeight +
-		sentimentTrendSignal*s.config.WinProbSentimentWeight +
-		(1.0-objectionFrequency)*s.config.WinProbObjectionWeight +
-		responseTimeSignal*s.config.WinProbResponseTimeWeight +
-		durationSignal*s.config.WinProbDurationWeight
+	probability := intentStrength*config.WinProbIntentWeight +
+		sentimentTrendSignal*config.WinProbSentimentWeight +
+		(1.0-objectionFrequency)*config.WinProbObjectionWeight +
+		responseTimeSignal*config.WinProbResponseTimeWeight +
+		durationSignal*config.WinProbDurationWeight
 
 	probability = math.Max(0.0, math.Min(1.0, probability))
 
@@ -494,11 +588,32 @@ func (s *AnalyticsService) identifyRiskFlags(metadata *models.ConversationMetada
 	return flags
 }
 
-// PrioritizeLeads ranks leads by priority
+// watchlistPriorityBoost is added to the priority score of watchlisted conversations
+const watchlistPriorityBoost = 0.3
+
+// watchlistedConversations returns the tenant's watchlisted conversation IDs
+func (s *AnalyticsService) watchlistedConversations(tenantID string) map[string]bool {
+	watchlisted := make(map[string]bool)
+	if s.watchlistStorage == nil {
+		return watchlisted
+	}
+	entries, err := s.watchlistStorage.ListWatchlist(tenantID)
+	if err != nil {
+		log.Printf("Error loading watchlist for tenant %s: %v", tenantID, err)
+		return watchlisted
+	}
+	for _, entry := range entries {
+		watchlisted[entry.ConversationID] = true
+	}
+	return watchlisted
+}
+
+// PrioritizeLeads ranks leads by priority. Watchlisted conversations get a fixed boost.
 func (s *AnalyticsService) PrioritizeLeads(
 	tenantID string,
 	conversationIDs []string,
 ) ([]PrioritizedLead, error) {
+	config := s.TenantConfig(tenantID)
 	// Deduplicate conversation IDs using a map
 	uniqueIDs := make(map[string]bool)
 	deduplicatedIDs := make([]string, 0)
@@ -517,6 +632,12 @@ func (s *AnalyticsService) PrioritizeLeads(
 		}
 	}
 
+	watchlisted := s.watchlistedConversations(tenantID)
+	slaSnapshot := s.loadSLASnapshot(tenantID)
+	tagNames := s.conversationTagNames(tenantID)
+	noted := s.notedConversations(tenantID)
+	pricingSensitivities := s.pricingSensitivities(tenantID)
+
 	var leads []PrioritizedLead
 
 	for _, convID := range filteredIDs {
@@ -527,12 +648,15 @@ func (s *AnalyticsService) PrioritizeLeads(
 		}
 
 		urgencyScore := s.calculateUrgencyScore(tenantID, convID)
-		dealValue := s.config.DefaultDealValue // TODO: Get from customer data
+		dealValue := config.DefaultDealValue // TODO: Get from customer data
 
 		// Priority score = weighted combination
 		priorityScore := winProb.Probability*0.5 +
 			urgencyScore*0.3 +
-			(dealValue/s.config.DefaultDealValue)*0.2
+			(dealValue/config.DefaultDealValue)*0.2
+		if watchlisted[convID] {
+			priorityScore += watchlistPriorityBoost
+		}
 
 		// Fetch conversation for context
 		conv, err := s.conversationStorage.GetConversation(tenantID, convID)
@@ -549,7 +673,7 @@ func (s *AnalyticsService) PrioritizeLeads(
 		}
 
 		// Fetch metadata for AI insights
-		metadata, err := s.conversationStorage.GetConversationMetadata(convID)
+		metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, convID)
 		if err != nil {
 			metadata = nil
 		}
@@ -557,7 +681,7 @@ func (s *AnalyticsService) PrioritizeLeads(
 		// Get trends for sentiment analysis
 		var trends TrendAnalysis
 		if metadata != nil {
-			trends = s.trendAnalyzer.AnalyzeTrends(messages, metadata)
+			trends = s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, config.TrendWindow)
 		} else {
 			trends = TrendAnalysis{
 				SentimentTrend: TrendStable,
@@ -572,9 +696,29 @@ func (s *AnalyticsService) PrioritizeLeads(
 		recommendedAction := s.generateRecommendedAction(metadata, urgencyScore, engagement)
 		leadStage := s.determineLeadStage(conv, metadata, winProb.Probability)
 		riskFlags := s.identifyRiskFlags(metadata, messages, engagement, trends)
+		pricingSensitivity := ""
+		if conv.CustomerID != nil {
+			pricingSensitivity = pricingSensitivities[*conv.CustomerID]
+		}
+		segment := config.Segmentation.segmentFor(pricingSensitivity, winProb.Probability, s.churnRiskScore(messages, metadata, trends))
+
+		complexityScore := 0.0
+		if metadata != nil {
+			complexityScore = metadata.ComplexityScore
+		}
+		productID := ""
+		if conv.ProductID != nil {
+			productID = *conv.ProductID
+		}
+		sla := slaSnapshot.statusFor(convID, productID, conv.Status, conv.CreatedAt)
+		slaStatus := ""
+		if sla != nil {
+			slaStatus = sla.Status
+		}
 
 		leads = append(leads, PrioritizedLead{
 			ConversationID:    convID,
+			AssignedAgentID:   conv.AssignedAgentID,
 			WinProbability:    winProb.Probability,
 			UrgencyScore:      urgencyScore,
 			DealValue:         dealValue,
@@ -585,6 +729,13 @@ func (s *AnalyticsService) PrioritizeLeads(
 			RecommendedAction: &recommendedAction,
 			LeadStage:         &leadStage,
 			RiskFlags:         riskFlags,
+			ComplexityScore:   complexityScore,
+			Watchlisted:       watchlisted[convID],
+			SLAStatus:         slaStatus,
+			SLA:               sla,
+			Tags:              tagNames[convID],
+			Segment:           segment,
+			HasNotes:          noted[convID],
 		})
 	}
 
@@ -605,13 +756,40 @@ func (s *AnalyticsService) CalculateChurnRisk(
 		return ChurnRisk{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
-	if err != nil {
-		return ChurnRisk{ConversationID: conversationID, RiskScore: 0.3, IsAtRisk: false}, nil
+	// Without metadata the conversation gets the unanalyzed default
+	metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	return s.churnRisk(s.TenantConfig(tenantID), conversationID, messages, metadata), nil
+}
+
+// churnRisk scores a conversation's churn risk from its messages and metadata (nil if unanalyzed)
+func (s *AnalyticsService) churnRisk(config AnalyticsConfig, conversationID string, messages []*models.Message, metadata *models.ConversationMetadata) ChurnRisk {
+	if metadata == nil {
+		return ChurnRisk{ConversationID: conversationID, RiskScore: unanalyzedChurnRisk, IsAtRisk: false}
+	}
+
+	trends := s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, config.TrendWindow)
+	riskScore := s.churnRiskScore(messages, metadata, trends)
+
+	isAtRisk := riskScore >= config.ChurnRiskThreshold
+
+	return ChurnRisk{
+		ConversationID: conversationID,
+		RiskScore:      riskScore,
+		IsAtRisk:       isAtRisk,
+	}
+}
+
+// unanalyzedChurnRisk is the churn risk of a conversation without metadata
+const unanalyzedChurnRisk = 0.3
+
+// churnRiskScore combines sustained negative sentiment, repeated unresolved objections and
+// declining engagement into a 0-1 churn risk
+func (s *AnalyticsService) churnRiskScore(messages []*models.Message, metadata *models.ConversationMetadata, trends TrendAnalysis) float64 {
+	if metadata == nil {
+		return unanalyzedChurnRisk
 	}
 
 	// Sustained negative sentiment
-	trends := s.trendAnalyzer.AnalyzeTrends(messages, metadata)
 	negativeSentimentRisk := 0.0
 	if trends.SentimentTrend == TrendDeteriorating {
 		negativeSentimentRisk = 0.5
@@ -625,15 +803,23 @@ func (s *AnalyticsService) CalculateChurnRisk(
 
 	// Combined risk score
 	riskScore := negativeSentimentRisk*0.4 + objectionRisk*0.4 + engagementRisk*0.2
-	riskScore = math.Max(0.0, math.Min(1.0, riskScore))
+	return math.Max(0.0, math.Min(1.0, riskScore))
+}
+
+// dashboardChurnRisk approximates CalculateChurnRisk from joined metadata without loading
+// messages: negative sentiment stands in for a deteriorating trend, and engagement is not scored
+func (s *AnalyticsService) dashboardChurnRisk(conv postgres.ConversationWithMetadata) float64 {
+	negativeSentimentRisk := 0.0
+	if conv.Sentiment == "negative" {
+		negativeSentimentRisk = 0.5
+	}
 
-	isAtRisk := riskScore >= s.config.ChurnRiskThreshold
+	objectionRisk := 0.0
+	if conv.MessageCount > 0 {
+		objectionRisk = math.Min(1.0, float64(len(conv.Objections))/float64(conv.MessageCount))
+	}
 
-	return ChurnRisk{
-		ConversationID: conversationID,
-		RiskScore:      riskScore,
-		IsAtRisk:       isAtRisk,
-	}, nil
+	return math.Max(0.0, math.Min(1.0, negativeSentimentRisk*0.4+objectionRisk*0.4))
 }
 
 // CalculateQualityScore calculates conversation quality score
@@ -645,16 +831,22 @@ func (s *AnalyticsService) CalculateQualityScore(
 		return QualityScore{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
-	if err != nil {
-		return QualityScore{ConversationID: conversationID, Score: 50.0}, nil
+	// Without metadata the conversation gets the unanalyzed default
+	metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	return s.qualityScore(s.TenantConfig(tenantID), conversationID, messages, metadata), nil
+}
+
+// qualityScore scores a conversation's quality from its messages and metadata (nil if unanalyzed)
+func (s *AnalyticsService) qualityScore(config AnalyticsConfig, conversationID string, messages []*models.Message, metadata *models.ConversationMetadata) QualityScore {
+	if metadata == nil {
+		return QualityScore{ConversationID: conversationID, Score: 50.0}
 	}
 
 	// Response latency (faster = better)
 	latencyScore := s.calculateLatencyScore(messages)
 
 	// Sentiment improvement
-	trends := s.trendAnalyzer.AnalyzeTrends(messages, metadata)
+	trends := s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, config.TrendWindow)
 	sentimentImprovementScore := s.trendToSignal(trends.SentimentTrend)
 
 	// Policy violations (fewer = better) - TODO: Get from rule engine
@@ -675,25 +867,26 @@ func (s *AnalyticsService) CalculateQualityScore(
 	return QualityScore{
 		ConversationID: conversationID,
 		Score:          qualityScore,
-	}, nil
+	}
 }
 
 // CalculateCLV estimates customer lifetime value
 func (s *AnalyticsService) CalculateCLV(
 	tenantID, conversationID string,
 ) (CLVEstimate, error) {
+	config := s.TenantConfig(tenantID)
 	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
 	if err != nil {
 		return CLVEstimate{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
-		return CLVEstimate{ConversationID: conversationID, CLV: s.config.DefaultCLV}, nil
+		return CLVEstimate{ConversationID: conversationID, CLV: config.DefaultCLV}, nil
 	}
 
 	// Historical average (using default for MVP)
-	historicalAverage := s.config.DefaultCLV
+	historicalAverage := config.DefaultCLV
 
 	// Engagement depth multiplier
 	engagementMultiplier := s.calculateEngagementDepth(messages)
@@ -703,7 +896,7 @@ func (s *AnalyticsService) CalculateCLV(
 
 	// CLV = base * engagement * intent
 	clv := historicalAverage * engagementMultiplier * (0.5 + intentMultiplier*0.5)
-	clv = math.Max(s.config.DefaultCLV*0.1, clv) // Minimum 10% of default
+	clv = math.Max(config.DefaultCLV*0.1, clv) // Minimum 10% of default
 
 	return CLVEstimate{
 		ConversationID: conversationID,
@@ -715,21 +908,22 @@ func (s *AnalyticsService) CalculateCLV(
 func (s *AnalyticsService) PredictSalesCycle(
 	tenantID, conversationID string,
 ) (SalesCyclePrediction, error) {
+	config := s.TenantConfig(tenantID)
 	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
 	if err != nil {
 		return SalesCyclePrediction{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
 		return SalesCyclePrediction{
 			ConversationID: conversationID,
-			DurationDays:   s.config.DefaultSalesCycleDays,
+			DurationDays:   config.DefaultSalesCycleDays,
 		}, nil
 	}
 
 	// Historical average (using default for MVP)
-	baseDuration := s.config.DefaultSalesCycleDays
+	baseDuration := config.DefaultSalesCycleDays
 
 	// Urgency signals reduce duration
 	urgencyMultiplier := s.calculateUrgencyScore(tenantID, conversationID)
@@ -855,6 +1049,11 @@ func (s *AnalyticsService) calculateDurationSignal(startTime, endTime time.Time)
 	return 0.7
 }
 
+// GetUrgencyScore returns how urgent a conversation is, 0-1, from urgency emotions and keywords
+func (s *AnalyticsService) GetUrgencyScore(tenantID, conversationID string) float64 {
+	return s.calculateUrgencyScore(tenantID, conversationID)
+}
+
 func (s *AnalyticsService) calculateUrgencyScore(tenantID, conversationID string) float64 {
 	// Check for urgency emotions or keywords
 	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
@@ -862,7 +1061,7 @@ func (s *AnalyticsService) calculateUrgencyScore(tenantID, conversationID string
 		return 0.5
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
 		return 0.5
 	}
@@ -1014,12 +1213,35 @@ func (s *AnalyticsService) calculateEngagementPattern(messages []*models.Message
 func (s *AnalyticsService) GetTrends(
 	tenantID, conversationID string,
 ) (TrendAnalysis, error) {
+	config := s.TenantConfig(tenantID)
+	return s.GetTrendsWithConfig(TrendAnalysisRequest{
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		Config:         config.TrendWindow,
+	})
+}
+
+// TrendAnalysisRequest asks for a conversation's trends computed with a specific window config
+type TrendAnalysisRequest struct {
+	ConversationID string
+	TenantID       string
+	Config         TrendWindowConfig
+}
+
+// GetTrendsWithConfig computes a conversation's trends using req.Config instead of the service's
+// configured windows
+func (s *AnalyticsService) GetTrendsWithConfig(req TrendAnalysisRequest) (TrendAnalysis, error) {
+	if err := req.Config.Validate(); err != nil {
+		return TrendAnalysis{}, err
+	}
+
+	tenantID, conversationID := req.TenantID, req.ConversationID
 	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
 	if err != nil {
 		return TrendAnalysis{}, err
 	}
 
-	metadata, err := s.conversationStorage.GetConversationMetadata(conversationID)
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
 	if err != nil {
 		// Return stable trend if no metadata
 		return TrendAnalysis{
@@ -1030,7 +1252,7 @@ func (s *AnalyticsService) GetTrends(
 		}, nil
 	}
 
-	return s.trendAnalyzer.AnalyzeTrends(messages, metadata), nil
+	return s.trendAnalyzer.AnalyzeTrendsWithConfig(messages, metadata, req.Config), nil
 }
 
 // IntentCount represents an intent with its count
@@ -1047,30 +1269,81 @@ type ObjectionCount struct {
 
 // DashboardMetrics represents dashboard metrics
 type DashboardMetrics struct {
-	TotalConversations int             `json:"total_conversations"`
-	ActiveConversations int             `json:"active_conversations"`
-	AverageSentiment    float64         `json:"average_sentiment"`
-	WinRate             float64         `json:"win_rate"`
-	ChurnRate           float64         `json:"churn_rate"`
-	TopIntents          []IntentCount   `json:"top_intents"`
-	TopObjections       []ObjectionCount `json:"top_objections"`
+	TotalConversations     int              `json:"total_conversations" csv:"total_conversations"`
+	ActiveConversations    int              `json:"active_conversations" csv:"active_conversations"`
+	AverageSentiment       float64          `json:"average_sentiment" csv:"average_sentiment"`
+	WinRate                float64          `json:"win_rate" csv:"win_rate"`
+	ChurnRate              float64          `json:"churn_rate" csv:"churn_rate"`
+	TopIntents             []IntentCount    `json:"top_intents"`
+	TopObjections          []ObjectionCount `json:"top_objections"`
+	StageTransitionCount   int              `json:"stage_transition_count" csv:"stage_transition_count"`
+	AvgDwellDiscoveryHours float64          `json:"avg_dwell_discovery_hours" csv:"avg_dwell_discovery_hours"`
+	WatchlistCount         int              `json:"watchlist_count" csv:"watchlist_count"`
+	ClosedToday            int              `json:"closed_today" csv:"closed_today"` // Closed since midnight UTC, regardless of filters
+	SLABreachCount         int              `json:"sla_breach_count" csv:"sla_breach_count"` // Conversations past a response or resolution deadline
+	TopCustomerLanguages   []LanguageDistribution `json:"top_customer_languages"`
+}
+
+// defaultDashboardMaxConversations caps the dashboard scan when DASHBOARD_MAX_CONVERSATIONS is unset
+const defaultDashboardMaxConversations = 5000
+
+// dashboardMaxConversations returns the maximum number of conversations scanned for the dashboard
+func dashboardMaxConversations() int {
+	if v, err := strconv.Atoi(os.Getenv("DASHBOARD_MAX_CONVERSATIONS")); err == nil && v > 0 {
+		return v
+	}
+	return defaultDashboardMaxConversations
 }
 
-// GetDashboardMetrics calculates dashboard metrics for a tenant
-func (s *AnalyticsService) GetDashboardMetrics(tenantID string) (DashboardMetrics, error) {
-	// Get all conversations for tenant (with reasonable limit, nil customerID for admin/agent access to all conversations)
-	conversations, err := s.conversationStorage.ListConversations(tenantID, nil, 1000, 0)
+// DashboardFilters narrows the conversations the dashboard is computed over. Zero values don't filter.
+type DashboardFilters struct {
+	StartDate time.Time // Inclusive lower bound on conversation created_at
+	EndDate   time.Time // Inclusive upper bound on conversation created_at; zero means now
+	Status    string    // active, closed or archived
+}
+
+// countFilters returns the storage filters counting the same conversations
+func (f DashboardFilters) countFilters() postgres.ConversationFilters {
+	filters := postgres.ConversationFilters{Status: f.Status}
+	if !f.StartDate.IsZero() {
+		filters.CreatedAfter = &f.StartDate
+	}
+	if !f.EndDate.IsZero() {
+		filters.CreatedBefore = &f.EndDate
+	}
+	return filters
+}
+
+// dashboardMetrics calculates dashboard metrics for a tenant over the conversations matching filters.
+// Conversations and metadata are loaded with one joined query; win rate is the share of
+// closed conversations resolved as deal_won. The total is counted separately, so it stays exact
+// when the scan is capped at DASHBOARD_MAX_CONVERSATIONS.
+func (s *AnalyticsService) dashboardMetrics(tenantID string, filters DashboardFilters) (DashboardMetrics, error) {
+	config := s.TenantConfig(tenantID)
+	conversations, err := s.conversationStorage.GetConversationsWithMetadata(tenantID, postgres.ConversationFilter{
+		From:   filters.StartDate,
+		To:     filters.EndDate,
+		Status: filters.Status,
+		Limit:  dashboardMaxConversations(),
+	})
+	if err != nil {
+		return DashboardMetrics{}, err
+	}
+	totalConversations, err := s.conversationStorage.CountConversations(tenantID, filters.countFilters())
 	if err != nil {
 		return DashboardMetrics{}, err
 	}
 
-	totalConversations := len(conversations)
+	scannedConversations := len(conversations)
 	activeConversations := 0
 	totalSentiment := 0.0
 	sentimentCount := 0
-	totalWinProb := 0.0
-	winProbCount := 0
+	closedCount := 0
+	wonCount := 0
 	atRiskCount := 0
+	watchlistCount := 0
+	slaBreachCount := 0
+	slaSnapshot := s.loadSLASnapshot(tenantID)
 	intentMap := make(map[string]int)
 	objectionMap := make(map[string]int)
 
@@ -1080,36 +1353,43 @@ func (s *AnalyticsService) GetDashboardMetrics(tenantID string) (DashboardMetric
 			activeConversations++
 		}
 
-		// Get metadata for sentiment and intents/objections
-		metadata, err := s.conversationStorage.GetConversationMetadata(conv.ID)
-		if err == nil {
-			// Sentiment
-			if metadata.SentimentScore > 0 {
-				totalSentiment += metadata.SentimentScore
-				sentimentCount++
+		// Win rate counts closed conversations by how they were resolved
+		if conv.Status == "closed" || conv.Status == "archived" {
+			closedCount++
+			if conv.ResolutionType == models.ResolutionDealWon {
+				wonCount++
 			}
+		}
 
-			// Intents
-			if metadata.Intent != "" {
-				intentMap[metadata.Intent]++
-			}
+		if conv.Watchlisted {
+			watchlistCount++
+		}
 
-			// Objections
-			for _, objection := range metadata.Objections {
-				objectionMap[objection]++
-			}
+		if sla := slaSnapshot.statusFor(conv.ConversationID, conv.ProductID, conv.Status, conv.CreatedAt); sla != nil && sla.Status == postgres.SLAStatusBreached {
+			slaBreachCount++
+		}
+
+		if !conv.HasMetadata {
+			continue
+		}
+
+		// Sentiment
+		if conv.SentimentScore > 0 {
+			totalSentiment += conv.SentimentScore
+			sentimentCount++
 		}
 
-		// Calculate win probability
-		winProb, err := s.CalculateWinProbability(tenantID, conv.ID)
-		if err == nil {
-			totalWinProb += winProb.Probability
-			winProbCount++
+		// Intents
+		if conv.Intent != "" {
+			intentMap[conv.Intent]++
 		}
 
-		// Calculate churn risk
-		churnRisk, err := s.CalculateChurnRisk(tenantID, conv.ID)
-		if err == nil && churnRisk.IsAtRisk {
+		// Objections
+		for _, objection := range conv.Objections {
+			objectionMap[objection]++
+		}
+
+		if s.dashboardChurnRisk(conv) >= config.ChurnRiskThreshold {
 			atRiskCount++
 		}
 	}
@@ -1121,13 +1401,13 @@ func (s *AnalyticsService) GetDashboardMetrics(tenantID string) (DashboardMetric
 	}
 
 	winRate := 0.0
-	if winProbCount > 0 {
-		winRate = totalWinProb / float64(winProbCount)
+	if closedCount > 0 {
+		winRate = float64(wonCount) / float64(closedCount)
 	}
 
 	churnRate := 0.0
-	if totalConversations > 0 {
-		churnRate = float64(atRiskCount) / float64(totalConversations)
+	if scannedConversations > 0 {
+		churnRate = float64(atRiskCount) / float64(scannedConversations)
 	}
 
 	// Build top intents (top 5)
@@ -1170,6 +1450,35 @@ func (s *AnalyticsService) GetDashboardMetrics(tenantID string) (DashboardMetric
 		})
 	}
 
+	// Lead stage dwell analytics
+	stageTransitionCount, err := s.leadStageStorage.CountTransitions(tenantID)
+	if err != nil {
+		log.Printf("Error counting stage transitions for tenant %s: %v", tenantID, err)
+	}
+	avgDwellDiscovery := 0.0
+	if dwell, err := s.GetAverageDwellTime(tenantID); err == nil {
+		avgDwellDiscovery = dwell[LeadStageDiscovery]
+	} else {
+		log.Printf("Error getting dwell time for tenant %s: %v", tenantID, err)
+	}
+	topLanguages := []LanguageDistribution{}
+	languagesTo := filters.EndDate
+	if languagesTo.IsZero() {
+		languagesTo = time.Now()
+	}
+	if distribution, err := s.GetLanguageDistribution(tenantID, filters.StartDate, languagesTo); err == nil {
+		topLanguages = topCustomerLanguages(distribution)
+	} else {
+		log.Printf("Error getting language distribution for tenant %s: %v", tenantID, err)
+	}
+
+	// Conversations closed today, independent of the dashboard filters
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	closedToday, err := s.conversationStorage.CountConversations(tenantID, postgres.ConversationFilters{ClosedAfter: &startOfDay})
+	if err != nil {
+		log.Printf("Error counting conversations closed today for tenant %s: %v", tenantID, err)
+	}
+
 	return DashboardMetrics{
 		TotalConversations: totalConversations,
 		ActiveConversations: activeConversations,
@@ -1178,6 +1487,12 @@ func (s *AnalyticsService) GetDashboardMetrics(tenantID string) (DashboardMetric
 		ChurnRate:           churnRate,
 		TopIntents:          topIntents,
 		TopObjections:       topObjections,
+		StageTransitionCount:   stageTransitionCount,
+		AvgDwellDiscoveryHours: avgDwellDiscovery,
+		WatchlistCount:         watchlistCount,
+		TopCustomerLanguages:   topLanguages,
+		ClosedToday:            closedToday,
+		SLABreachCount:         slaBreachCount,
 	}, nil
 }
 
@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"time"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
+)
+
+// AnalyticsServiceInterface is the analytics API used by HTTP handlers, so they can be tested
+// without a database
+type AnalyticsServiceInterface interface {
+	CalculateLeadScore(tenantID, conversationID string) (LeadScore, error)
+	CalculateWinProbability(tenantID, conversationID string) (WinProbability, error)
+	CalculateChurnRisk(tenantID, conversationID string) (ChurnRisk, error)
+	PrioritizeLeads(tenantID string, conversationIDs []string) ([]PrioritizedLead, error)
+	GetDashboardMetrics(tenantID string, filters DashboardFilters) (DashboardMetrics, error)
+	RefreshDashboardMetrics(tenantID string, filters DashboardFilters) (DashboardMetrics, error)
+	GetTrends(tenantID, conversationID string) (TrendAnalysis, error)
+	GetTrendsWithConfig(req TrendAnalysisRequest) (TrendAnalysis, error)
+	CalculateCLV(tenantID, conversationID string) (CLVEstimate, error)
+	PredictSalesCycle(tenantID, conversationID string) (SalesCyclePrediction, error)
+	CalculateQualityScore(tenantID, conversationID string) (QualityScore, error)
+	GetLeaderboard(tenantID string, from, to time.Time, sortBy string, limit int) ([]AgentLeaderboardEntry, error)
+	FindLeaderboardEntry(tenantID string, from, to time.Time, sortBy, agentID string) (*AgentLeaderboardEntry, error)
+	GetComplexity(tenantID, conversationID string) (ai.ComplexityScore, error)
+	GetComplexityDistribution(tenantID string) (map[string]int, error)
+	GetAverageDwellTime(tenantID string) (map[string]float64, error)
+	StreamLeads(tenantID string, from, to time.Time, fn func(leads []PrioritizedLead) error) error
+	GetLanguageDistribution(tenantID string, from, to time.Time) ([]LanguageDistribution, error)
+	GetMixedLanguageConversations(tenantID string) ([]*models.Conversation, error)
+	GetSLABreachSummary(tenantID string, from, to time.Time) (SLABreachSummary, error)
+	GetAgentPerformance(tenantID, agentID string, from, to time.Time) (AgentPerformance, error)
+	GetSuggestionAcceptanceRate(tenantID string) (float64, error)
+	GetObjectionResolutionRate(tenantID string) (map[string]float64, error)
+	SegmentCustomers(tenantID string) ([]CustomerSegment, error)
+}
+
+var _ AnalyticsServiceInterface = (*AnalyticsService)(nil)
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// unknownLanguage is the language recorded when detection fails
+const unknownLanguage = "unknown"
+
+// topCustomerLanguagesLimit is how many languages the dashboard shows
+const topCustomerLanguagesLimit = 5
+
+// LanguageDistribution is how much customers wrote in a language
+type LanguageDistribution struct {
+	Language          string  `json:"language"`
+	MessageCount      int     `json:"message_count"`
+	ConversationCount int     `json:"conversation_count"`
+	Percentage        float64 `json:"percentage"` // Share of messages with a detected language; 0 for unknown
+}
+
+// GetLanguageDistribution breaks down a tenant's customer messages by language, most used first.
+// Unknown messages are reported with their count but left out of percentages.
+func (s *AnalyticsService) GetLanguageDistribution(tenantID string, from, to time.Time) ([]LanguageDistribution, error) {
+	counts, err := s.conversationStorage.GetLanguageCounts(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return languageDistribution(counts), nil
+}
+
+// languageDistribution computes percentages and orders languages by message count
+func languageDistribution(counts []postgres.LanguageCount) []LanguageDistribution {
+	known := 0
+	for _, count := range counts {
+		if count.Language != unknownLanguage {
+			known += count.MessageCount
+		}
+	}
+
+	distribution := make([]LanguageDistribution, 0, len(counts))
+	for _, count := range counts {
+		entry := LanguageDistribution{
+			Language:          count.Language,
+			MessageCount:      count.MessageCount,
+			ConversationCount: count.ConversationCount,
+		}
+		if count.Language != unknownLanguage && known > 0 {
+			entry.Percentage = float64(count.MessageCount) / float64(known) * 100
+		}
+		distribution = append(distribution, entry)
+	}
+
+	sort.Slice(distribution, func(i, j int) bool {
+		if distribution[i].MessageCount != distribution[j].MessageCount {
+			return distribution[i].MessageCount > distribution[j].MessageCount
+		}
+		return distribution[i].Language < distribution[j].Language
+	})
+	return distribution
+}
+
+// topCustomerLanguages returns the most used detected languages, skipping unknown
+func topCustomerLanguages(distribution []LanguageDistribution) []LanguageDistribution {
+	top := make([]LanguageDistribution, 0, topCustomerLanguagesLimit)
+	for _, entry := range distribution {
+		if len(top) == topCustomerLanguagesLimit {
+			break
+		}
+		if entry.Language != unknownLanguage {
+			top = append(top, entry)
+		}
+	}
+	return top
+}
+
+// GetMixedLanguageConversations returns conversations whose customer messages use more than one
+// detected language. These customers are candidates for the translation pipeline.
+func (s *AnalyticsService) GetMixedLanguageConversations(tenantID string) ([]*models.Conversation, error) {
+	return s.conversationStorage.ListMixedLanguageConversations(tenantID)
+}
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func TestLanguageDistributionExcludesUnknownFromPercentages(t *testing.T) {
+	distribution := languageDistribution([]postgres.LanguageCount{
+		{Language: "hi", MessageCount: 20, ConversationCount: 4},
+		{Language: "unknown", MessageCount: 50, ConversationCount: 9},
+		{Language: "en", MessageCount: 60, ConversationCount: 10},
+		{Language: "es", MessageCount: 20, ConversationCount: 3},
+	})
+
+	want := []LanguageDistribution{
+		{Language: "en", MessageCount: 60, ConversationCount: 10, Percentage: 60},
+		{Language: "unknown", MessageCount: 50, ConversationCount: 9, Percentage: 0},
+		{Language: "es", MessageCount: 20, ConversationCount: 3, Percentage: 20},
+		{Language: "hi", MessageCount: 20, ConversationCount: 4, Percentage: 20},
+	}
+	if len(distribution) != len(want) {
+		t.Fatalf("got %d languages, want %d", len(distribution), len(want))
+	}
+	for i := range want {
+		if distribution[i] != want[i] {
+			t.Errorf("distribution[%d] = %+v, want %+v", i, distribution[i], want[i])
+		}
+	}
+
+	top := topCustomerLanguages(distribution)
+	if len(top) != 3 || top[0].Language != "en" || top[1].Language != "es" {
+		t.Errorf("topCustomerLanguages = %+v, want en, es, hi without unknown", top)
+	}
+}
+
+func TestLanguageDistributionOnlyUnknown(t *testing.T) {
+	distribution := languageDistribution([]postgres.LanguageCount{{Language: "unknown", MessageCount: 5, ConversationCount: 1}})
+	if len(distribution) != 1 || distribution[0].Percentage != 0 {
+		t.Errorf("distribution = %+v, want unknown with 0%%", distribution)
+	}
+	if top := topCustomerLanguages(distribution); len(top) != 0 {
+		t.Errorf("topCustomerLanguages = %+v, want none", top)
+	}
+}
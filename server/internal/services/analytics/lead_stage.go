@@ -0,0 +1,278 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
+)
+
+// Lead stages produced by determineLeadStage
+const (
+	LeadStageDiscovery  = "discovery"
+	LeadStageEvaluation = "evaluation"
+	LeadStageDecision   = "decision"
+)
+
+// stuckDwellMultiplier flags leads that stay in a stage this many times longer than average
+const stuckDwellMultiplier = 2.0
+
+// stuckLeadScanInterval is how often every tenant is checked for stuck leads
+const stuckLeadScanInterval = time.Hour
+
+// HotLeadNotifier is told about newly raised hot lead alerts (e.g. to post to Slack)
+type HotLeadNotifier interface {
+	NotifyHotLead(tenantID, conversationID string, winProbability float64, recommendedAction, details string)
+}
+
+// currentStage is the stage a conversation is in and when it entered it
+type currentStage struct {
+	stage     string
+	enteredAt time.Time
+}
+
+// stuckLead is a lead that has stayed in its stage more than stuckDwellMultiplier times the average
+type stuckLead struct {
+	conversationID string
+	stage          string
+	hours          float64
+	averageHours   float64
+}
+
+// OnAnalysisComplete checks for escalation and records lead stage changes after each analysis.
+// Stuck leads are detected by StuckLeadScanner, since that needs every transition of the tenant.
+func (s *AnalyticsService) OnAnalysisComplete(tenantID, conversationID string) {
+	if err := s.CheckEscalation(tenantID, conversationID); err != nil {
+		log.Printf("[ANALYTICS] escalation check failed conversation=%s error=%v", conversationID, err)
+	}
+	if err := s.TrackLeadStage(tenantID, conversationID); err != nil {
+		log.Printf("[ANALYTICS] stage tracking failed conversation=%s error=%v", conversationID, err)
+	}
+}
+
+// TrackLeadStage computes the current lead stage and records a transition if it changed
+func (s *AnalyticsService) TrackLeadStage(tenantID, conversationID string) error {
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		metadata = nil
+	}
+
+	winProb, err := s.CalculateWinProbability(tenantID, conversationID)
+	if err != nil {
+		return err
+	}
+	newStage := s.determineLeadStage(conv, metadata, winProb.Probability)
+
+	// Serialize compare-and-record so concurrent analyses don't duplicate transitions
+	s.stageMu.Lock()
+	defer s.stageMu.Unlock()
+
+	previousStage, err := s.leadStageStorage.GetCurrentStage(conversationID)
+	if err != nil {
+		return err
+	}
+	if previousStage == newStage {
+		return nil
+	}
+
+	if err := s.leadStageStorage.RecordTransition(tenantID, conversationID, previousStage, newStage); err != nil {
+		return err
+	}
+	log.Printf("[ANALYTICS] lead stage transition conversation=%s from=%s to=%s", conversationID, previousStage, newStage)
+
+	// A stage change means the lead is no longer stuck
+	if previousStage != "" {
+		if err := s.hotLeadAlertStorage.ResolveAlerts(tenantID, conversationID, postgres.HotLeadAlertStageStuck); err != nil {
+			log.Printf("[ANALYTICS] failed to resolve stuck alerts conversation=%s error=%v", conversationID, err)
+		}
+	}
+	return nil
+}
+
+// GetAverageDwellTime returns the average hours leads spend in each stage
+// before moving on. Stages with no completed dwell periods report 0.
+func (s *AnalyticsService) GetAverageDwellTime(tenantID string) (map[string]float64, error) {
+	transitions, err := s.leadStageStorage.ListTransitions(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	averages, _ := dwellStats(transitions)
+	return averages, nil
+}
+
+// DetectStuckLeads raises stage_stuck hot lead alerts for active leads that have
+// been in their current stage for more than twice the average dwell time.
+// Returns the number of new alerts created.
+func (s *AnalyticsService) DetectStuckLeads(tenantID string) (int, error) {
+	transitions, err := s.leadStageStorage.ListTransitions(tenantID)
+	if err != nil {
+		return 0, err
+	}
+	averages, current := dwellStats(transitions)
+
+	created := 0
+	for _, lead := range findStuckLeads(averages, current, time.Now()) {
+		conv, err := s.conversationStorage.GetConversation(tenantID, lead.conversationID)
+		if err != nil || conv.Status != "active" {
+			continue
+		}
+
+		details := fmt.Sprintf("in %s for %.1fh (average %.1fh)", lead.stage, lead.hours, lead.averageHours)
+		ok, err := s.hotLeadAlertStorage.CreateAlertIfAbsent(tenantID, lead.conversationID, postgres.HotLeadAlertStageStuck, details)
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+			log.Printf("[ANALYTICS] stuck lead conversation=%s %s", lead.conversationID, details)
+			s.notifyHotLead(tenantID, lead.conversationID, details)
+		}
+	}
+	return created, nil
+}
+
+// findStuckLeads returns the leads that have been in their current stage for more than
+// stuckDwellMultiplier times the stage's average dwell time, ordered by conversation. Stages
+// without a completed dwell period never flag a lead.
+func findStuckLeads(averages map[string]float64, current map[string]currentStage, now time.Time) []stuckLead {
+	var stuck []stuckLead
+	for conversationID, cur := range current {
+		avg := averages[cur.stage]
+		if avg <= 0 {
+			continue
+		}
+		hours := now.Sub(cur.enteredAt).Hours()
+		if hours <= stuckDwellMultiplier*avg {
+			continue
+		}
+		stuck = append(stuck, stuckLead{conversationID: conversationID, stage: cur.stage, hours: hours, averageHours: avg})
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].conversationID < stuck[j].conversationID })
+	return stuck
+}
+
+// StuckLeadScanner periodically raises stage_stuck alerts for every tenant with lead stage history
+type StuckLeadScanner struct {
+	analyticsService *AnalyticsService
+	tenants          *postgres.LeadStageStorage
+	interval         time.Duration
+	stop             chan struct{}
+	stopOnce         sync.Once
+}
+
+// NewStuckLeadScanner creates a stuck lead scanner that runs every hour
+func NewStuckLeadScanner(analyticsService *AnalyticsService, leadStageStorage *postgres.LeadStageStorage) *StuckLeadScanner {
+	return &StuckLeadScanner{
+		analyticsService: analyticsService,
+		tenants:          leadStageStorage,
+		interval:         stuckLeadScanInterval,
+		stop:             make(chan struct{}),
+	}
+}
+
+// ScanTenants runs stuck lead detection for every tenant. Returns the number of new alerts.
+func (d *StuckLeadScanner) ScanTenants() (int, error) {
+	tenantIDs, err := d.tenants.ListTransitionTenants()
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, tenantID := range tenantIDs {
+		n, err := d.analyticsService.DetectStuckLeads(tenantID)
+		created += n
+		if err != nil {
+			log.Printf("[ANALYTICS] stuck lead detection failed tenant=%s error=%v", tenantID, err)
+		}
+	}
+	return created, nil
+}
+
+// Start queues a scan on pool every hour until Stop is called. A scan is skipped when the pool
+// is full; the next tick queues another.
+func (d *StuckLeadScanner) Start(pool *worker.Pool) {
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				job := worker.Job{
+					Name: "stuck_lead_scan",
+					Run: func(ctx context.Context) error {
+						_, err := d.ScanTenants()
+						return err
+					},
+				}
+				if err := pool.Enqueue(job); err != nil {
+					log.Printf("[ANALYTICS] stuck lead scan not queued pending=%d error=%v", pool.Len(), err)
+				}
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("[ANALYTICS] stuck lead scan scheduled interval=%s", d.interval)
+}
+
+// Stop stops the stuck lead scan
+func (d *StuckLeadScanner) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
+
+// notifyHotLead forwards a new hot lead alert to the notifier with the lead's scores
+func (s *AnalyticsService) notifyHotLead(tenantID, conversationID, details string) {
+	if s.hotLeadNotifier == nil {
+		return
+	}
+
+	leads, err := s.PrioritizeLeads(tenantID, []string{conversationID})
+	if err != nil || len(leads) == 0 {
+		s.hotLeadNotifier.NotifyHotLead(tenantID, conversationID, 0, "", details)
+		return
+	}
+
+	action := ""
+	if leads[0].RecommendedAction != nil {
+		action = *leads[0].RecommendedAction
+	}
+	s.hotLeadNotifier.NotifyHotLead(tenantID, conversationID, leads[0].WinProbability, action, details)
+}
+
+// dwellStats computes average completed dwell hours per stage and the current
+// stage of every conversation. Transitions must be ordered by conversation and time.
+func dwellStats(transitions []*postgres.LeadStageTransition) (map[string]float64, map[string]currentStage) {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	current := make(map[string]currentStage)
+
+	for i, t := range transitions {
+		if i+1 < len(transitions) && transitions[i+1].ConversationID == t.ConversationID {
+			next := transitions[i+1]
+			totals[t.ToStage] += next.TransitionedAt.Sub(t.TransitionedAt).Hours()
+			counts[t.ToStage]++
+			continue
+		}
+		current[t.ConversationID] = currentStage{stage: t.ToStage, enteredAt: t.TransitionedAt}
+	}
+
+	averages := map[string]float64{
+		LeadStageDiscovery:  0,
+		LeadStageEvaluation: 0,
+		LeadStageDecision:   0,
+	}
+	for stage, total := range totals {
+		averages[stage] = total / float64(counts[stage])
+	}
+	return averages, current
+}
@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func transition(conversationID, toStage string, at time.Time) *postgres.LeadStageTransition {
+	return &postgres.LeadStageTransition{ConversationID: conversationID, ToStage: toStage, TransitionedAt: at}
+}
+
+func TestDwellStats(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	transitions := []*postgres.LeadStageTransition{
+		// c1 spent 10h in discovery and 4h in evaluation, and is now in decision
+		transition("c1", LeadStageDiscovery, start),
+		transition("c1", LeadStageEvaluation, start.Add(10*time.Hour)),
+		transition("c1", LeadStageDecision, start.Add(14*time.Hour)),
+		// c2 spent 20h in discovery and is now in evaluation
+		transition("c2", LeadStageDiscovery, start),
+		transition("c2", LeadStageEvaluation, start.Add(20*time.Hour)),
+		// c3 is still in its first stage
+		transition("c3", LeadStageDiscovery, start.Add(time.Hour)),
+	}
+
+	averages, current := dwellStats(transitions)
+	wantAverages := map[string]float64{LeadStageDiscovery: 15, LeadStageEvaluation: 4, LeadStageDecision: 0}
+	for stage, want := range wantAverages {
+		if math.Abs(averages[stage]-want) > 1e-9 {
+			t.Errorf("average %s dwell = %.2fh, want %.2fh", stage, averages[stage], want)
+		}
+	}
+	wantCurrent := map[string]currentStage{
+		"c1": {stage: LeadStageDecision, enteredAt: start.Add(14 * time.Hour)},
+		"c2": {stage: LeadStageEvaluation, enteredAt: start.Add(20 * time.Hour)},
+		"c3": {stage: LeadStageDiscovery, enteredAt: start.Add(time.Hour)},
+	}
+	if len(current) != len(wantCurrent) {
+		t.Fatalf("current = %+v, want %d conversations", current, len(wantCurrent))
+	}
+	for id, want := range wantCurrent {
+		if got := current[id]; got.stage != want.stage || !got.enteredAt.Equal(want.enteredAt) {
+			t.Errorf("current[%s] = %+v, want %+v", id, got, want)
+		}
+	}
+
+	// Without history every stage averages 0
+	averages, current = dwellStats(nil)
+	if len(averages) != 3 || averages[LeadStageDiscovery] != 0 || len(current) != 0 {
+		t.Errorf("empty dwellStats = %v, %v; want zero averages and no conversations", averages, current)
+	}
+}
+
+func TestFindStuckLeads(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	averages := map[string]float64{LeadStageDiscovery: 10, LeadStageEvaluation: 4, LeadStageDecision: 0}
+	current := map[string]currentStage{
+		"slow-discovery":  {stage: LeadStageDiscovery, enteredAt: now.Add(-21 * time.Hour)},
+		"at-threshold":    {stage: LeadStageDiscovery, enteredAt: now.Add(-20 * time.Hour)}, // Exactly twice the average isn't stuck
+		"fresh":           {stage: LeadStageDiscovery, enteredAt: now.Add(-time.Hour)},
+		"slow-evaluation": {stage: LeadStageEvaluation, enteredAt: now.Add(-9 * time.Hour)},
+		"no-average":      {stage: LeadStageDecision, enteredAt: now.Add(-1000 * time.Hour)},
+	}
+
+	stuck := findStuckLeads(averages, current, now)
+	if len(stuck) != 2 {
+		t.Fatalf("stuck = %+v, want slow-discovery and slow-evaluation", stuck)
+	}
+	if stuck[0].conversationID != "slow-discovery" || stuck[0].stage != LeadStageDiscovery ||
+		stuck[0].hours != 21 || stuck[0].averageHours != 10 {
+		t.Errorf("stuck[0] = %+v, want slow-discovery at 21h against a 10h average", stuck[0])
+	}
+	if stuck[1].conversationID != "slow-evaluation" || stuck[1].hours != 9 || stuck[1].averageHours != 4 {
+		t.Errorf("stuck[1] = %+v, want slow-evaluation at 9h against a 4h average", stuck[1])
+	}
+
+	if stuck := findStuckLeads(averages, nil, now); len(stuck) != 0 {
+		t.Errorf("stuck without leads = %+v, want none", stuck)
+	}
+}
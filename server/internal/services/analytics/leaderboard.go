@@ -0,0 +1,215 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Leaderboard sort options
+const (
+	LeaderboardSortScore              = "score"
+	LeaderboardSortWinRate            = "win_rate"
+	LeaderboardSortResponseTime       = "response_time"
+	LeaderboardSortSuggestionAccepted = "suggestion_acceptance"
+	LeaderboardSortConversations      = "total_conversations"
+)
+
+// leaderboardCacheTTL is how long a computed leaderboard is reused
+const leaderboardCacheTTL = 10 * time.Minute
+
+// AgentLeaderboardEntry represents an agent's position on the leaderboard
+type AgentLeaderboardEntry struct {
+	Rank                 int     `json:"rank" csv:"rank"`
+	AgentID              string  `json:"agent_id" csv:"agent_id"`
+	AgentEmail           string  `json:"agent_email" csv:"agent_email"`
+	WinRate              float64 `json:"win_rate" csv:"win_rate"` // 0-1
+	AvgResponseMinutes   float64 `json:"avg_response_minutes" csv:"avg_response_minutes"`
+	SuggestionAcceptance float64 `json:"suggestion_acceptance" csv:"suggestion_acceptance"` // 0-1
+	TotalConversations   int     `json:"total_conversations" csv:"total_conversations"`
+	AvgTransferRate      float64 `json:"avg_transfer_rate" csv:"avg_transfer_rate"` // 0-1, fraction of handled conversations transferred away
+	Score                float64 `json:"score" csv:"score"`                         // 0-1
+}
+
+// leaderboardCacheEntry holds a ranked leaderboard with its expiry
+type leaderboardCacheEntry struct {
+	entries   []AgentLeaderboardEntry
+	expiresAt time.Time
+}
+
+// leaderboardCache caches ranked leaderboards keyed by tenant, sort and range
+type leaderboardCache struct {
+	mu      sync.Mutex
+	entries map[string]leaderboardCacheEntry
+}
+
+func newLeaderboardCache() *leaderboardCache {
+	return &leaderboardCache{entries: make(map[string]leaderboardCacheEntry)}
+}
+
+func (c *leaderboardCache) get(key string) ([]AgentLeaderboardEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.entries, true
+}
+
+// set stores a leaderboard and evicts expired entries so the cache doesn't grow without bound
+func (c *leaderboardCache) set(key string, entries []AgentLeaderboardEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = leaderboardCacheEntry{entries: entries, expiresAt: now.Add(leaderboardCacheTTL)}
+}
+
+// leaderboardCacheKey keys a leaderboard by tenant, sort and range. The range is truncated to the
+// TTL so requests whose range ends at "now" share an entry.
+func leaderboardCacheKey(tenantID, sortBy string, from, to time.Time) string {
+	return fmt.Sprintf("%s|%s|%d|%d", tenantID, sortBy,
+		from.Truncate(leaderboardCacheTTL).Unix(), to.Truncate(leaderboardCacheTTL).Unix())
+}
+
+// IsValidLeaderboardSort checks if a sort option is supported
+func IsValidLeaderboardSort(sortBy string) bool {
+	switch sortBy {
+	case LeaderboardSortScore, LeaderboardSortWinRate, LeaderboardSortResponseTime,
+		LeaderboardSortSuggestionAccepted, LeaderboardSortConversations:
+		return true
+	}
+	return false
+}
+
+// GetLeaderboard ranks agents by performance within a time range.
+// Score = 0.5*WinRate + 0.3*SuggestionAcceptance + 0.2*(1-NormalizedResponseTime), where agents
+// who never replied to a customer get the worst response time.
+// Returns at most limit entries; limit <= 0 returns the full ranking.
+func (s *AnalyticsService) GetLeaderboard(tenantID string, from, to time.Time, sortBy string, limit int) ([]AgentLeaderboardEntry, error) {
+	entries, err := s.rankedLeaderboard(tenantID, from, to, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// FindLeaderboardEntry returns the leaderboard entry for a specific agent, or nil if unranked
+func (s *AnalyticsService) FindLeaderboardEntry(tenantID string, from, to time.Time, sortBy, agentID string) (*AgentLeaderboardEntry, error) {
+	entries, err := s.rankedLeaderboard(tenantID, from, to, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].AgentID == agentID {
+			entry := entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// rankedLeaderboard computes (or loads from cache) the full ranked leaderboard
+func (s *AnalyticsService) rankedLeaderboard(tenantID string, from, to time.Time, sortBy string) ([]AgentLeaderboardEntry, error) {
+	if sortBy == "" {
+		sortBy = LeaderboardSortScore
+	}
+	if !IsValidLeaderboardSort(sortBy) {
+		return nil, fmt.Errorf("invalid sort_by: %s", sortBy)
+	}
+
+	cacheKey := leaderboardCacheKey(tenantID, sortBy, from, to)
+	if entries, ok := s.leaderboardCache.get(cacheKey); ok {
+		return entries, nil
+	}
+
+	stats, err := s.conversationStorage.GetAgentStats(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize response time against the slowest agent; agents who never replied score as slowest
+	maxResponse := 0.0
+	unanswered := make(map[string]bool)
+	for _, st := range stats {
+		if !st.HasResponseTime {
+			unanswered[st.AgentID] = true
+		} else if st.AvgResponseMinutes > maxResponse {
+			maxResponse = st.AvgResponseMinutes
+		}
+	}
+
+	entries := make([]AgentLeaderboardEntry, 0, len(stats))
+	for _, st := range stats {
+		winRate := 0.0
+		if st.ClosedConversations > 0 {
+			winRate = float64(st.WonConversations) / float64(st.ClosedConversations)
+		}
+
+		acceptance := 0.0
+		if st.SuggestionFeedback > 0 {
+			acceptance = float64(st.SuggestionsAccepted) / float64(st.SuggestionFeedback)
+		}
+
+		transferRate := 0.0
+		if st.TotalConversations > 0 {
+			transferRate = float64(st.TransferredAway) / float64(st.TotalConversations)
+		}
+
+		normalizedResponse := 0.0
+		if !st.HasResponseTime {
+			normalizedResponse = 1
+		} else if maxResponse > 0 {
+			normalizedResponse = st.AvgResponseMinutes / maxResponse
+		}
+
+		entries = append(entries, AgentLeaderboardEntry{
+			AgentID:              st.AgentID,
+			AgentEmail:           st.AgentEmail,
+			WinRate:              winRate,
+			AvgResponseMinutes:   st.AvgResponseMinutes,
+			SuggestionAcceptance: acceptance,
+			TotalConversations:   st.TotalConversations,
+			AvgTransferRate:      transferRate,
+			Score:                0.5*winRate + 0.3*acceptance + 0.2*(1-normalizedResponse),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		switch sortBy {
+		case LeaderboardSortWinRate:
+			return entries[i].WinRate > entries[j].WinRate
+		case LeaderboardSortResponseTime:
+			// Faster responders rank higher; agents without replies rank last
+			if unanswered[entries[i].AgentID] != unanswered[entries[j].AgentID] {
+				return unanswered[entries[j].AgentID]
+			}
+			return entries[i].AvgResponseMinutes < entries[j].AvgResponseMinutes
+		case LeaderboardSortSuggestionAccepted:
+			return entries[i].SuggestionAcceptance > entries[j].SuggestionAcceptance
+		case LeaderboardSortConversations:
+			return entries[i].TotalConversations > entries[j].TotalConversations
+		default:
+			return entries[i].Score > entries[j].Score
+		}
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	s.leaderboardCache.set(cacheKey, entries)
+	return entries, nil
+}
@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaderboardCacheKeySharedAcrossDefaultRanges(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// Requests a few seconds apart whose range ends at "now" share an entry
+	if a, b := leaderboardCacheKey("tenant-1", LeaderboardSortScore, from, to.Add(time.Second)),
+		leaderboardCacheKey("tenant-1", LeaderboardSortScore, from, to.Add(9*time.Minute)); a != b {
+		t.Errorf("keys %q and %q differ within one TTL window", a, b)
+	}
+	keys := map[string]bool{}
+	for _, key := range []string{
+		leaderboardCacheKey("tenant-1", LeaderboardSortScore, from, to),
+		leaderboardCacheKey("tenant-2", LeaderboardSortScore, from, to),
+		leaderboardCacheKey("tenant-1", LeaderboardSortWinRate, from, to),
+		leaderboardCacheKey("tenant-1", LeaderboardSortScore, from, to.Add(leaderboardCacheTTL)),
+	} {
+		if keys[key] {
+			t.Errorf("duplicate cache key %q", key)
+		}
+		keys[key] = true
+	}
+}
+
+func TestLeaderboardServedFromCache(t *testing.T) {
+	// No conversation storage: a cache miss would reach the database and panic
+	service := NewAnalyticsService(nil, nil, nil)
+	to := time.Date(2026, 10, 16, 12, 1, 0, 0, time.UTC)
+	from := to.Add(-30 * 24 * time.Hour)
+	cached := []AgentLeaderboardEntry{{Rank: 1, AgentID: "agent-1"}, {Rank: 2, AgentID: "agent-2"}}
+	service.leaderboardCache.set(leaderboardCacheKey("tenant-1", LeaderboardSortScore, from, to), cached)
+
+	entries, err := service.GetLeaderboard("tenant-1", from, to.Add(time.Minute), "", 1)
+	if err != nil {
+		t.Fatalf("GetLeaderboard: %v", err)
+	}
+	if len(entries) != 1 || entries[0].AgentID != "agent-1" {
+		t.Errorf("entries = %+v, want the cached leader", entries)
+	}
+}
+
+func TestLeaderboardCacheEvictsExpiredEntriesOnSet(t *testing.T) {
+	cache := newLeaderboardCache()
+	cache.entries["stale"] = leaderboardCacheEntry{expiresAt: time.Now().Add(-time.Second)}
+	cache.entries["fresh"] = leaderboardCacheEntry{expiresAt: time.Now().Add(time.Minute)}
+
+	cache.set("new", []AgentLeaderboardEntry{{AgentID: "agent-1"}})
+	if _, ok := cache.entries["stale"]; ok {
+		t.Error("expired entry was kept")
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("entries = %v, want the fresh and new entries", cache.entries)
+	}
+	if entries, ok := cache.get("new"); !ok || entries[0].AgentID != "agent-1" {
+		t.Errorf("get(new) = %v, %v, want the stored leaderboard", entries, ok)
+	}
+}
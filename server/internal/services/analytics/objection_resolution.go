@@ -0,0 +1,19 @@
+package analytics
+
+import (
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SetObjectionResolutionStorage enables objection resolution rates (optional)
+func (s *AnalyticsService) SetObjectionResolutionStorage(objectionStorage *postgres.ObjectionResolutionStorage) {
+	s.objectionStorage = objectionStorage
+}
+
+// GetObjectionResolutionRate returns, per objection type, the share (0-1) of the tenant's
+// conversations raising it where an agent resolved it. It is empty when tracking is not configured.
+func (s *AnalyticsService) GetObjectionResolutionRate(tenantID string) (map[string]float64, error) {
+	if s.objectionStorage == nil {
+		return map[string]float64{}, nil
+	}
+	return s.objectionStorage.GetResolutionRates(tenantID)
+}
@@ -0,0 +1,199 @@
+package analytics
+
+import (
+	"log"
+	"sort"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Customer segments assigned by SegmentCustomers
+const (
+	SegmentAtRiskChurner            = "at-risk churner"
+	SegmentPriceSensitiveHighIntent = "price-sensitive high-intent"
+	SegmentLoyalLowRisk             = "loyal low-risk"
+	SegmentUndecided                = "undecided"
+)
+
+// customerSegments is the order segments are reported in
+var customerSegments = []string{
+	SegmentPriceSensitiveHighIntent,
+	SegmentLoyalLowRisk,
+	SegmentAtRiskChurner,
+	SegmentUndecided,
+}
+
+// segmentActions are the suggested actions for each segment
+var segmentActions = map[string][]string{
+	SegmentPriceSensitiveHighIntent: {
+		"Lead with value and ROI before discussing price",
+		"Offer a time-limited discount or annual billing to close",
+	},
+	SegmentLoyalLowRisk: {
+		"Offer upgrades and complementary products",
+		"Ask for a referral or review",
+	},
+	SegmentAtRiskChurner: {
+		"Escalate to a senior agent and follow up within 24 hours",
+		"Address open objections before pitching anything new",
+	},
+	SegmentUndecided: {
+		"Share case studies and product comparisons",
+		"Ask qualifying questions to uncover needs and timeline",
+	},
+}
+
+// memoryPageSize is how many customer memories are loaded per query
+const memoryPageSize = 500
+
+// SegmentationConfig holds the thresholds of the customer segmentation decision tree
+type SegmentationConfig struct {
+	// Churn risk (0-1) at or above which a customer is an at-risk churner, whatever else applies
+	AtRiskChurnRisk float64
+	// Win probability (0-1) at or above which a high pricing sensitivity customer is high-intent
+	HighIntentWinProbability float64
+	// Win probability (0-1) at or above which a low churn risk customer is loyal
+	LoyalWinProbability float64
+	// Churn risk (0-1) below which a customer is low-risk
+	LowChurnRisk float64
+	// Customer IDs listed per segment
+	RepresentativeCustomers int
+}
+
+// DefaultSegmentationConfig returns the default segmentation thresholds
+func DefaultSegmentationConfig() SegmentationConfig {
+	return SegmentationConfig{
+		AtRiskChurnRisk:          0.6,
+		HighIntentWinProbability: 0.6,
+		LoyalWinProbability:      0.5,
+		LowChurnRisk:             0.3,
+		RepresentativeCustomers:  5,
+	}
+}
+
+// CustomerSegment summarizes the customers in one segment
+type CustomerSegment struct {
+	Segment                   string   `json:"segment"`
+	Count                     int      `json:"count"`
+	RepresentativeCustomerIDs []string `json:"representative_customer_ids"` // Most recently active first; customers without a conversation last
+	SuggestedActions          []string `json:"suggested_actions"`
+}
+
+// segmentFor places a customer in a segment. Churn risk is checked first, so a customer about to
+// leave is never reported as high-intent or loyal.
+func (c SegmentationConfig) segmentFor(pricingSensitivity string, winProbability, churnRisk float64) string {
+	switch {
+	case churnRisk >= c.AtRiskChurnRisk:
+		return SegmentAtRiskChurner
+	case pricingSensitivity == "high" && winProbability >= c.HighIntentWinProbability:
+		return SegmentPriceSensitiveHighIntent
+	case winProbability >= c.LoyalWinProbability && churnRisk < c.LowChurnRisk:
+		return SegmentLoyalLowRisk
+	default:
+		return SegmentUndecided
+	}
+}
+
+// SetMemoryStorage enables pricing sensitivity in customer segments (optional)
+func (s *AnalyticsService) SetMemoryStorage(memoryStorage *postgres.MemoryStorage) {
+	s.memoryStorage = memoryStorage
+}
+
+// SegmentCustomers groups the tenant's customers into segments by the pricing sensitivity in their
+// memory and the win probability and churn risk of their latest conversation. Customers without
+// a conversation are undecided. Every segment is returned, including empty ones.
+func (s *AnalyticsService) SegmentCustomers(tenantID string) ([]CustomerSegment, error) {
+	config := s.TenantConfig(tenantID)
+	conversations, err := s.conversationStorage.GetConversationsWithMetadata(tenantID, postgres.ConversationFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Conversations are most recently updated first, so the first one seen is the customer's latest
+	latestConversation := make(map[string]string)
+	var customerIDs []string
+	for _, conv := range conversations {
+		if conv.CustomerID == "" {
+			continue
+		}
+		if _, seen := latestConversation[conv.CustomerID]; !seen {
+			latestConversation[conv.CustomerID] = conv.ConversationID
+			customerIDs = append(customerIDs, conv.CustomerID)
+		}
+	}
+
+	pricingSensitivities := s.pricingSensitivities(tenantID)
+	var withoutConversation []string
+	for customerID := range pricingSensitivities {
+		if _, seen := latestConversation[customerID]; !seen {
+			withoutConversation = append(withoutConversation, customerID)
+		}
+	}
+	sort.Strings(withoutConversation)
+	customerIDs = append(customerIDs, withoutConversation...)
+
+	assignments := make(map[string][]string, len(customerSegments))
+	for _, customerID := range customerIDs {
+		segment := SegmentUndecided
+		if convID, ok := latestConversation[customerID]; ok {
+			segment = s.conversationSegment(tenantID, convID, pricingSensitivities[customerID])
+		}
+		assignments[segment] = append(assignments[segment], customerID)
+	}
+	return summarizeSegments(assignments, config.Segmentation.RepresentativeCustomers), nil
+}
+
+// conversationSegment segments a customer by their latest conversation
+func (s *AnalyticsService) conversationSegment(tenantID, conversationID, pricingSensitivity string) string {
+	config := s.TenantConfig(tenantID)
+	winProb, err := s.CalculateWinProbability(tenantID, conversationID)
+	if err != nil {
+		log.Printf("Error calculating win probability for %s: %v", conversationID, err)
+		return SegmentUndecided
+	}
+	churnRisk, err := s.CalculateChurnRisk(tenantID, conversationID)
+	if err != nil {
+		log.Printf("Error calculating churn risk for %s: %v", conversationID, err)
+		return SegmentUndecided
+	}
+	return config.Segmentation.segmentFor(pricingSensitivity, winProb.Probability, churnRisk.RiskScore)
+}
+
+// summarizeSegments counts the customers assigned to each segment, keeping the first
+// representatives of each
+func summarizeSegments(assignments map[string][]string, representatives int) []CustomerSegment {
+	segments := make([]CustomerSegment, 0, len(customerSegments))
+	for _, name := range customerSegments {
+		customerIDs := assignments[name]
+		sample := customerIDs[:min(len(customerIDs), max(representatives, 0))]
+		segments = append(segments, CustomerSegment{
+			Segment:                   name,
+			Count:                     len(customerIDs),
+			RepresentativeCustomerIDs: append([]string{}, sample...),
+			SuggestedActions:          segmentActions[name],
+		})
+	}
+	return segments
+}
+
+// pricingSensitivities maps customer IDs to the pricing sensitivity in their memory. It is empty
+// when memory storage is not configured.
+func (s *AnalyticsService) pricingSensitivities(tenantID string) map[string]string {
+	sensitivities := make(map[string]string)
+	if s.memoryStorage == nil {
+		return sensitivities
+	}
+	for offset := 0; ; offset += memoryPageSize {
+		memories, err := s.memoryStorage.ListMemories(tenantID, memoryPageSize, offset)
+		if err != nil {
+			log.Printf("Error loading customer memories for tenant %s: %v", tenantID, err)
+			return sensitivities
+		}
+		for _, memory := range memories {
+			sensitivities[memory.CustomerID] = memory.PricingSensitivity
+		}
+		if len(memories) < memoryPageSize {
+			return sensitivities
+		}
+	}
+}
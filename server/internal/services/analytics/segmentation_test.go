@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegmentForBoundaries(t *testing.T) {
+	config := DefaultSegmentationConfig()
+
+	tests := []struct {
+		name               string
+		pricingSensitivity string
+		winProbability     float64
+		churnRisk          float64
+		want               string
+	}{
+		{"churn risk at threshold", "low", 0.9, 0.6, SegmentAtRiskChurner},
+		{"churn risk just below threshold", "low", 0.2, 0.59, SegmentUndecided},
+		{"churn risk beats high intent", "high", 0.9, 0.6, SegmentAtRiskChurner},
+		{"high sensitivity at intent threshold", "high", 0.6, 0.5, SegmentPriceSensitiveHighIntent},
+		{"high sensitivity just below intent threshold", "high", 0.59, 0.5, SegmentUndecided},
+		{"high intent beats loyal", "high", 0.9, 0.1, SegmentPriceSensitiveHighIntent},
+		{"medium sensitivity is never price-sensitive", "medium", 0.9, 0.5, SegmentUndecided},
+		{"unknown sensitivity is never price-sensitive", "", 0.9, 0.5, SegmentUndecided},
+		{"loyal at win probability threshold", "low", 0.5, 0.1, SegmentLoyalLowRisk},
+		{"win probability just below loyal threshold", "low", 0.49, 0.1, SegmentUndecided},
+		{"churn risk at low-risk threshold", "medium", 0.8, 0.3, SegmentUndecided},
+		{"churn risk just below low-risk threshold", "medium", 0.8, 0.29, SegmentLoyalLowRisk},
+		{"high sensitivity below intent can still be loyal", "high", 0.55, 0.1, SegmentLoyalLowRisk},
+		{"no signals", "", 0, 0, SegmentUndecided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.segmentFor(tt.pricingSensitivity, tt.winProbability, tt.churnRisk); got != tt.want {
+				t.Errorf("segmentFor(%q, %v, %v) = %q, want %q", tt.pricingSensitivity, tt.winProbability, tt.churnRisk, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmentForUsesConfiguredThresholds(t *testing.T) {
+	config := SegmentationConfig{
+		AtRiskChurnRisk:          0.8,
+		HighIntentWinProbability: 0.4,
+		LoyalWinProbability:      0.7,
+		LowChurnRisk:             0.5,
+	}
+
+	if got := config.segmentFor("low", 0.9, 0.7); got != SegmentUndecided {
+		t.Errorf("churn risk under a raised at-risk threshold = %q, want %q", got, SegmentUndecided)
+	}
+	if got := config.segmentFor("low", 0.9, 0.8); got != SegmentAtRiskChurner {
+		t.Errorf("churn risk at a raised at-risk threshold = %q, want %q", got, SegmentAtRiskChurner)
+	}
+	if got := config.segmentFor("high", 0.4, 0.6); got != SegmentPriceSensitiveHighIntent {
+		t.Errorf("win probability at a lowered intent threshold = %q, want %q", got, SegmentPriceSensitiveHighIntent)
+	}
+	if got := config.segmentFor("medium", 0.7, 0.49); got != SegmentLoyalLowRisk {
+		t.Errorf("churn risk under a raised low-risk threshold = %q, want %q", got, SegmentLoyalLowRisk)
+	}
+	if got := config.segmentFor("medium", 0.6, 0.1); got != SegmentUndecided {
+		t.Errorf("win probability under a raised loyal threshold = %q, want %q", got, SegmentUndecided)
+	}
+}
+
+func TestSummarizeSegments(t *testing.T) {
+	assignments := map[string][]string{
+		SegmentAtRiskChurner: {"cust-1", "cust-2", "cust-3"},
+		SegmentLoyalLowRisk:  {"cust-4"},
+	}
+
+	segments := summarizeSegments(assignments, 2)
+
+	var names []string
+	for _, segment := range segments {
+		names = append(names, segment.Segment)
+		if len(segment.SuggestedActions) == 0 {
+			t.Errorf("segment %q has no suggested actions", segment.Segment)
+		}
+	}
+	if !reflect.DeepEqual(names, customerSegments) {
+		t.Fatalf("segments = %v, want every segment in order %v", names, customerSegments)
+	}
+
+	atRisk := segments[2]
+	if atRisk.Count != 3 || !reflect.DeepEqual(atRisk.RepresentativeCustomerIDs, []string{"cust-1", "cust-2"}) {
+		t.Errorf("at-risk segment = %+v, want 3 customers with the first 2 as representatives", atRisk)
+	}
+	if undecided := segments[3]; undecided.Count != 0 || undecided.RepresentativeCustomerIDs == nil || len(undecided.RepresentativeCustomerIDs) != 0 {
+		t.Errorf("empty segment = %#v, want a zero count and no representatives", undecided)
+	}
+}
@@ -0,0 +1,31 @@
+package analytics
+
+import (
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// SentimentPoint is a conversation's sentiment score after one analysis
+type SentimentPoint struct {
+	AnalyzedAt     time.Time `json:"analyzed_at"`
+	SentimentScore float64   `json:"sentiment_score"`
+}
+
+// GetSentimentTimeline returns the sentiment score of every analysis of a conversation, oldest first
+func (s *AnalyticsService) GetSentimentTimeline(tenantID, conversationID string) ([]SentimentPoint, error) {
+	entries, err := s.conversationStorage.GetAnalysisHistory(tenantID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return sentimentTimeline(entries), nil
+}
+
+// sentimentTimeline picks the sentiment scores out of a conversation's analysis history
+func sentimentTimeline(entries []*models.AnalysisHistoryEntry) []SentimentPoint {
+	points := make([]SentimentPoint, 0, len(entries))
+	for _, entry := range entries {
+		points = append(points, SentimentPoint{AnalyzedAt: entry.AnalyzedAt, SentimentScore: entry.SentimentScore})
+	}
+	return points
+}
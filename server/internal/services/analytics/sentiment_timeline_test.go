@@ -0,0 +1,31 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestSentimentTimelineFollowsAnalyses(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	history := []*models.AnalysisHistoryEntry{
+		{Sentiment: "negative", SentimentScore: 0.2, AnalyzedAt: start, MessageCountAtAnalysis: 2},
+		{Sentiment: "neutral", SentimentScore: 0.5, AnalyzedAt: start.Add(10 * time.Minute), MessageCountAtAnalysis: 5},
+		{Sentiment: "positive", SentimentScore: 0.85, AnalyzedAt: start.Add(25 * time.Minute), MessageCountAtAnalysis: 9},
+	}
+
+	want := []SentimentPoint{
+		{AnalyzedAt: start, SentimentScore: 0.2},
+		{AnalyzedAt: start.Add(10 * time.Minute), SentimentScore: 0.5},
+		{AnalyzedAt: start.Add(25 * time.Minute), SentimentScore: 0.85},
+	}
+	if got := sentimentTimeline(history); !reflect.DeepEqual(got, want) {
+		t.Errorf("sentimentTimeline = %+v, want %+v", got, want)
+	}
+
+	if got := sentimentTimeline(nil); got == nil || len(got) != 0 {
+		t.Errorf("sentimentTimeline(nil) = %#v, want an empty timeline", got)
+	}
+}
@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SLABreachSummary is how often and by how much agents missed the response deadline
+type SLABreachSummary struct {
+	BreachCount          int       `json:"breach_count"`
+	OpenBreaches         int       `json:"open_breaches"`          // Still waiting for an agent reply
+	AverageBreachSeconds float64   `json:"average_breach_seconds"` // Time past the deadline; open breaches count up to now
+	From                 time.Time `json:"from"`
+	To                   time.Time `json:"to"`
+}
+
+// GetSLABreachSummary summarizes a tenant's missed response deadlines that fell within [from, to]
+func (s *AnalyticsService) GetSLABreachSummary(tenantID string, from, to time.Time) (SLABreachSummary, error) {
+	if s.slaStorage == nil {
+		return SLABreachSummary{}, fmt.Errorf("sla tracking is not configured")
+	}
+	summary, err := s.slaStorage.GetSLABreachSummary(tenantID, from, to, time.Now())
+	if err != nil {
+		return SLABreachSummary{}, err
+	}
+	return SLABreachSummary{
+		BreachCount:          summary.BreachCount,
+		OpenBreaches:         summary.OpenBreaches,
+		AverageBreachSeconds: summary.AverageBreachSeconds,
+		From:                 from,
+		To:                   to,
+	}, nil
+}
+
+// SLAStatus is where a conversation stands against its response and resolution deadlines
+type SLAStatus struct {
+	ConversationID string     `json:"conversation_id"`
+	ProductID      string     `json:"product_id,omitempty"`  // Set when the product's SLA applies instead of the tenant's
+	ExpectedBy     *time.Time `json:"expected_by,omitempty"` // The earliest deadline still open
+	Status         string     `json:"status"`                // ok, pending or breached
+}
+
+// slaSnapshot holds a tenant's SLA tracking state, loaded once per leads or dashboard request
+type slaSnapshot struct {
+	statuses  map[string]string                     // Response SLA status by conversation
+	deadlines map[string]time.Time                  // Earliest unanswered response deadline by conversation
+	products  map[string]*postgres.ProductSLAConfig // Product SLA configs by product
+	now       time.Time
+}
+
+// loadSLASnapshot loads the SLA state of the tenant's conversations. Parts that fail to load are
+// logged and left empty.
+func (s *AnalyticsService) loadSLASnapshot(tenantID string) slaSnapshot {
+	snapshot := slaSnapshot{
+		statuses:  map[string]string{},
+		deadlines: map[string]time.Time{},
+		products:  map[string]*postgres.ProductSLAConfig{},
+		now:       time.Now(),
+	}
+	if s.slaStorage == nil {
+		return snapshot
+	}
+	if loaded, err := s.slaStorage.GetSLAStatuses(tenantID, snapshot.now); err == nil {
+		snapshot.statuses = loaded
+	} else {
+		log.Printf("Error loading SLA statuses for tenant %s: %v", tenantID, err)
+	}
+	if loaded, err := s.slaStorage.GetOpenSLADeadlines(tenantID); err == nil {
+		snapshot.deadlines = loaded
+	} else {
+		log.Printf("Error loading SLA deadlines for tenant %s: %v", tenantID, err)
+	}
+	if loaded, err := s.slaStorage.ListProductSLAConfigs(tenantID); err == nil {
+		snapshot.products = loaded
+	} else {
+		log.Printf("Error loading product SLA configs for tenant %s: %v", tenantID, err)
+	}
+	return snapshot
+}
+
+// statusFor returns a conversation's SLA status, or nil when it isn't tracked. Response deadlines
+// already carry the product's first response SLA; the product's resolution deadline is checked here
+// for conversations that are still active.
+func (snapshot slaSnapshot) statusFor(conversationID, productID, conversationStatus string, createdAt time.Time) *SLAStatus {
+	status := &SLAStatus{ConversationID: conversationID, Status: snapshot.statuses[conversationID]}
+	if deadline, ok := snapshot.deadlines[conversationID]; ok {
+		status.ExpectedBy = &deadline
+	}
+
+	if config := snapshot.products[productID]; productID != "" && config != nil {
+		status.ProductID = productID
+		if config.ResolutionHours > 0 && conversationStatus == "active" {
+			resolveBy := createdAt.Add(time.Duration(config.ResolutionHours) * time.Hour)
+			if status.ExpectedBy == nil || resolveBy.Before(*status.ExpectedBy) {
+				status.ExpectedBy = &resolveBy
+			}
+			if snapshot.now.After(resolveBy) {
+				status.Status = postgres.SLAStatusBreached
+			} else if status.Status == "" || status.Status == postgres.SLAStatusOK {
+				status.Status = postgres.SLAStatusPending
+			}
+		}
+	}
+
+	if status.Status == "" {
+		return nil
+	}
+	return status
+}
@@ -0,0 +1,77 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func TestSLASnapshotStatusFor(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	responseBy := now.Add(10 * time.Minute)
+	snapshot := slaSnapshot{
+		statuses: map[string]string{
+			"waiting":  postgres.SLAStatusPending,
+			"answered": postgres.SLAStatusOK,
+			"product":  postgres.SLAStatusOK,
+		},
+		deadlines: map[string]time.Time{"waiting": responseBy},
+		products: map[string]*postgres.ProductSLAConfig{
+			"enterprise": {ProductID: "enterprise", FirstResponseMinutes: 5, ResolutionHours: 24},
+			"starter":    {ProductID: "starter", FirstResponseMinutes: 30},
+		},
+		now: now,
+	}
+
+	tests := []struct {
+		name           string
+		conversationID string
+		productID      string
+		status         string
+		createdAt      time.Time
+		wantStatus     string
+		wantProduct    string
+		wantExpectedBy *time.Time
+	}{
+		{name: "untracked", conversationID: "new", status: "active", createdAt: now},
+		{name: "waiting for a reply", conversationID: "waiting", status: "active", createdAt: now, wantStatus: postgres.SLAStatusPending, wantExpectedBy: &responseBy},
+		{name: "answered", conversationID: "answered", status: "active", createdAt: now, wantStatus: postgres.SLAStatusOK},
+		{
+			name: "product resolution deadline ahead", conversationID: "product", productID: "enterprise", status: "active",
+			createdAt: now.Add(-time.Hour), wantStatus: postgres.SLAStatusPending, wantProduct: "enterprise", wantExpectedBy: timePtr(now.Add(23 * time.Hour)),
+		},
+		{
+			name: "product resolution deadline passed", conversationID: "product", productID: "enterprise", status: "active",
+			createdAt: now.Add(-25 * time.Hour), wantStatus: postgres.SLAStatusBreached, wantProduct: "enterprise", wantExpectedBy: timePtr(now.Add(-time.Hour)),
+		},
+		{
+			name: "closed conversations have no resolution deadline", conversationID: "product", productID: "enterprise", status: "closed",
+			createdAt: now.Add(-25 * time.Hour), wantStatus: postgres.SLAStatusOK, wantProduct: "enterprise",
+		},
+		{
+			name: "product without resolution deadline", conversationID: "answered", productID: "starter", status: "active",
+			createdAt: now.Add(-25 * time.Hour), wantStatus: postgres.SLAStatusOK, wantProduct: "starter",
+		},
+		{name: "product without SLA", conversationID: "answered", productID: "basic", status: "active", createdAt: now, wantStatus: postgres.SLAStatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snapshot.statusFor(tt.conversationID, tt.productID, tt.status, tt.createdAt)
+			if tt.wantStatus == "" {
+				if got != nil {
+					t.Errorf("statusFor = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Status != tt.wantStatus || got.ProductID != tt.wantProduct || got.ConversationID != tt.conversationID {
+				t.Fatalf("statusFor = %+v, want status %q for product %q", got, tt.wantStatus, tt.wantProduct)
+			}
+			if (got.ExpectedBy == nil) != (tt.wantExpectedBy == nil) || (got.ExpectedBy != nil && !got.ExpectedBy.Equal(*tt.wantExpectedBy)) {
+				t.Errorf("expected_by = %v, want %v", got.ExpectedBy, tt.wantExpectedBy)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
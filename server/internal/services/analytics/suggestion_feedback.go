@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SetSuggestionFeedbackStorage enables suggestion acceptance rates (optional)
+func (s *AnalyticsService) SetSuggestionFeedbackStorage(feedbackStorage *postgres.SuggestionFeedbackStorage) {
+	s.feedbackStorage = feedbackStorage
+}
+
+// GetSuggestionAcceptanceRate returns the share (0-1) of the tenant's suggestion feedback where the
+// agent used the suggestion, as is or edited. It is 0 without feedback.
+func (s *AnalyticsService) GetSuggestionAcceptanceRate(tenantID string) (float64, error) {
+	rate, _, err := s.GetIntentAcceptanceRate(tenantID, "")
+	return rate, err
+}
+
+// GetIntentAcceptanceRate is GetSuggestionAcceptanceRate for conversations with the given intent
+// (all conversations when empty). It also returns how much feedback the rate is based on.
+func (s *AnalyticsService) GetIntentAcceptanceRate(tenantID, intent string) (float64, int, error) {
+	if s.feedbackStorage == nil {
+		return 0, 0, nil
+	}
+	counts, err := s.feedbackStorage.CountFeedback(tenantID, intent)
+	if err != nil {
+		return 0, 0, err
+	}
+	return acceptanceRate(counts), counts.Total(), nil
+}
+
+// acceptanceRate is the share of feedback that accepted or edited the suggestion
+func acceptanceRate(counts postgres.SuggestionFeedbackCounts) float64 {
+	if counts.Total() == 0 {
+		return 0
+	}
+	return float64(counts.Accepted+counts.Edited) / float64(counts.Total())
+}
@@ -0,0 +1,33 @@
+package analytics
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+func TestAcceptanceRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts postgres.SuggestionFeedbackCounts
+		want   float64
+	}{
+		{name: "no feedback", counts: postgres.SuggestionFeedbackCounts{}, want: 0},
+		{name: "edited counts as used", counts: postgres.SuggestionFeedbackCounts{Accepted: 2, Edited: 1, Rejected: 1}, want: 0.75},
+		{name: "all rejected", counts: postgres.SuggestionFeedbackCounts{Rejected: 3}, want: 0},
+		{name: "all accepted", counts: postgres.SuggestionFeedbackCounts{Accepted: 4}, want: 1},
+	}
+	for _, tt := range tests {
+		if got := acceptanceRate(tt.counts); got != tt.want {
+			t.Errorf("%s: acceptanceRate(%+v) = %v, want %v", tt.name, tt.counts, got, tt.want)
+		}
+	}
+}
+
+func TestGetSuggestionAcceptanceRateWithoutStorage(t *testing.T) {
+	service := NewAnalyticsService(nil, nil, nil)
+	rate, samples, err := service.GetIntentAcceptanceRate("tenant-1", "buying")
+	if err != nil || rate != 0 || samples != 0 {
+		t.Errorf("GetIntentAcceptanceRate = %v, %d, %v; want no rate without feedback storage", rate, samples, err)
+	}
+}
@@ -0,0 +1,31 @@
+package analytics
+
+import "log"
+
+// conversationTagNames returns the tag names of the tenant's tagged conversations
+func (s *AnalyticsService) conversationTagNames(tenantID string) map[string][]string {
+	names := make(map[string][]string)
+	if s.tagStorage == nil {
+		return names
+	}
+	loaded, err := s.tagStorage.GetTagNamesByConversation(tenantID)
+	if err != nil {
+		log.Printf("Error loading conversation tags for tenant %s: %v", tenantID, err)
+		return names
+	}
+	return loaded
+}
+
+// notedConversations returns the tenant's conversations that have agent notes. Only their
+// existence is used; note content never reaches analytics or AI prompts.
+func (s *AnalyticsService) notedConversations(tenantID string) map[string]bool {
+	if s.noteStorage == nil {
+		return map[string]bool{}
+	}
+	noted, err := s.noteStorage.GetNotedConversations(tenantID)
+	if err != nil {
+		log.Printf("Error loading conversation notes for tenant %s: %v", tenantID, err)
+		return map[string]bool{}
+	}
+	return noted
+}
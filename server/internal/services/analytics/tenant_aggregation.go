@@ -0,0 +1,189 @@
+package analytics
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+const (
+	// aggregationCacheTTL is how long cross-tenant aggregates are reused before recomputing
+	aggregationCacheTTL = 30 * time.Minute
+	// aggregationConcurrency caps the tenants computed at once
+	aggregationConcurrency = 4
+)
+
+// TenantChurnSummary is a tenant's churn risk for platform monitoring
+type TenantChurnSummary struct {
+	TenantID              string  `json:"tenant_id"`
+	AvgChurnRisk          float64 `json:"avg_churn_risk"`     // Mean risk score over analyzed conversations, 0-1
+	AtRiskPercentage      float64 `json:"at_risk_percentage"` // Dashboard churn rate as a percentage
+	ConversationsAnalyzed int     `json:"conversations_analyzed"`
+}
+
+// TenantWinRateSummary is a tenant's win rate for platform monitoring
+type TenantWinRateSummary struct {
+	TenantID            string  `json:"tenant_id"`
+	WinRate             float64 `json:"win_rate"`
+	ClosedConversations int     `json:"closed_conversations"`
+	DealsWon            int     `json:"deals_won"`
+}
+
+// TenantAggregate holds the per-tenant figures computed in one pass over a tenant's conversations
+type TenantAggregate struct {
+	Churn   TenantChurnSummary
+	WinRate TenantWinRateSummary
+}
+
+// ChurnRiskAggregation computes churn risk and win rate across all tenants for the platform operator.
+// Results are cached for 30 minutes since every tenant's conversations are scanned.
+type ChurnRiskAggregation struct {
+	analyticsService    *AnalyticsService
+	conversationStorage *postgres.ConversationStorage
+
+	mu         sync.Mutex
+	cached     []TenantAggregate
+	computedAt time.Time
+}
+
+// NewChurnRiskAggregation creates a new cross-tenant aggregation
+func NewChurnRiskAggregation(analyticsService *AnalyticsService, conversationStorage *postgres.ConversationStorage) *ChurnRiskAggregation {
+	return &ChurnRiskAggregation{
+		analyticsService:    analyticsService,
+		conversationStorage: conversationStorage,
+	}
+}
+
+// ChurnRisk returns every tenant's churn risk summary
+func (a *ChurnRiskAggregation) ChurnRisk() ([]TenantChurnSummary, error) {
+	aggregates, err := a.aggregates()
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]TenantChurnSummary, len(aggregates))
+	for i, aggregate := range aggregates {
+		summaries[i] = aggregate.Churn
+	}
+	return summaries, nil
+}
+
+// WinRate returns every tenant's win rate summary
+func (a *ChurnRiskAggregation) WinRate() ([]TenantWinRateSummary, error) {
+	aggregates, err := a.aggregates()
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]TenantWinRateSummary, len(aggregates))
+	for i, aggregate := range aggregates {
+		summaries[i] = aggregate.WinRate
+	}
+	return summaries, nil
+}
+
+// aggregates returns cached aggregates for all tenants, recomputing them once the cache expires
+func (a *ChurnRiskAggregation) aggregates() ([]TenantAggregate, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cached != nil && time.Since(a.computedAt) < aggregationCacheTTL {
+		return a.cached, nil
+	}
+
+	tenantIDs, err := a.conversationStorage.ListTenantIDs()
+	if err != nil {
+		return nil, err
+	}
+	aggregates := a.Compute(tenantIDs)
+
+	a.cached = aggregates
+	a.computedAt = time.Now()
+	log.Printf("[ANALYTICS] cross-tenant aggregates computed tenants=%d", len(aggregates))
+	return aggregates, nil
+}
+
+// Compute aggregates the given tenants, a few at a time. Tenants that fail are logged and skipped.
+func (a *ChurnRiskAggregation) Compute(tenantIDs []string) []TenantAggregate {
+	results := make([]*TenantAggregate, len(tenantIDs))
+	sem := make(chan struct{}, aggregationConcurrency)
+	var wg sync.WaitGroup
+
+	for i, tenantID := range tenantIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, tenantID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			aggregate, err := a.computeTenant(tenantID)
+			if err != nil {
+				log.Printf("[ANALYTICS] failed to aggregate tenant=%s error=%v", tenantID, err)
+				return
+			}
+			results[i] = aggregate
+		}(i, tenantID)
+	}
+	wg.Wait()
+
+	aggregates := make([]TenantAggregate, 0, len(tenantIDs))
+	for _, result := range results {
+		if result != nil {
+			aggregates = append(aggregates, *result)
+		}
+	}
+	return aggregates
+}
+
+// computeTenant derives a tenant's churn and win rate figures. The at-risk percentage and win rate
+// match GetDashboardMetrics; the average risk uses the same per-conversation churn estimate.
+func (a *ChurnRiskAggregation) computeTenant(tenantID string) (*TenantAggregate, error) {
+	metrics, err := a.analyticsService.GetDashboardMetrics(tenantID, DashboardFilters{})
+	if err != nil {
+		return nil, err
+	}
+	conversations, err := a.conversationStorage.GetConversationsWithMetadata(tenantID, postgres.ConversationFilter{
+		Limit: dashboardMaxConversations(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	analyzed := 0
+	totalRisk := 0.0
+	closed := 0
+	won := 0
+	for _, conv := range conversations {
+		if conv.Status == "closed" || conv.Status == "archived" {
+			closed++
+			if conv.ResolutionType == models.ResolutionDealWon {
+				won++
+			}
+		}
+		if conv.HasMetadata {
+			analyzed++
+			totalRisk += a.analyticsService.dashboardChurnRisk(conv)
+		}
+	}
+
+	avgRisk := 0.0
+	if analyzed > 0 {
+		avgRisk = totalRisk / float64(analyzed)
+	}
+
+	return &TenantAggregate{
+		Churn: TenantChurnSummary{
+			TenantID:              tenantID,
+			AvgChurnRisk:          avgRisk,
+			AtRiskPercentage:      metrics.ChurnRate * 100,
+			ConversationsAnalyzed: analyzed,
+		},
+		WinRate: TenantWinRateSummary{
+			TenantID:            tenantID,
+			WinRate:             metrics.WinRate,
+			ClosedConversations: closed,
+			DealsWon:            won,
+		},
+	}, nil
+}
@@ -1,6 +1,7 @@
 package analytics
 
 import (
+	"fmt"
 	"math"
 
 	"ai-conversation-platform/internal/models"
@@ -23,6 +24,41 @@ type TrendAnalysis struct {
 	EmotionSlope   float64      `json:"emotion_slope"`   // -1 to 1
 }
 
+// TrendWindowConfig controls which messages a trend compares
+type TrendWindowConfig struct {
+	// WindowSize compares the first and last WindowSize customer messages. 0 splits all
+	// messages at the midpoint instead.
+	WindowSize int `json:"window_size"`
+	// MinMessages is the fewest messages a trend is computed from; below it the trend is
+	// Stable. Values under 2 are treated as 2.
+	MinMessages int `json:"min_messages"`
+	// UseWeightedAverage weights each window toward the end of the conversation it
+	// represents, decaying exponentially, so the latest messages dominate the recent window
+	UseWeightedAverage bool `json:"use_weighted_average"`
+}
+
+// maxTrendWindowSize caps WindowSize and MinMessages from API requests
+const maxTrendWindowSize = 100
+
+// trendWeightDecay is the weight of each message relative to its neighbour nearer the window's anchor
+const trendWeightDecay = 0.5
+
+// DefaultTrendWindowConfig returns the midpoint split used when nothing is configured
+func DefaultTrendWindowConfig() TrendWindowConfig {
+	return TrendWindowConfig{MinMessages: 2}
+}
+
+// Validate checks the config is usable
+func (c TrendWindowConfig) Validate() error {
+	if c.WindowSize < 0 || c.WindowSize > maxTrendWindowSize {
+		return fmt.Errorf("window_size must be between 0 and %d", maxTrendWindowSize)
+	}
+	if c.MinMessages < 0 || c.MinMessages > maxTrendWindowSize {
+		return fmt.Errorf("min_messages must be between 0 and %d", maxTrendWindowSize)
+	}
+	return nil
+}
+
 // TrendAnalyzer analyzes sentiment and emotion trends over time
 type TrendAnalyzer struct{}
 
@@ -38,7 +74,17 @@ func (a *TrendAnalyzer) AnalyzeTrends(
 	messages []*models.Message,
 	metadata *models.ConversationMetadata,
 ) TrendAnalysis {
-	if len(messages) < 2 {
+	return a.AnalyzeTrendsWithConfig(messages, metadata, DefaultTrendWindowConfig())
+}
+
+// AnalyzeTrendsWithConfig computes trends comparing the early and recent windows chosen by config
+func (a *TrendAnalyzer) AnalyzeTrendsWithConfig(
+	messages []*models.Message,
+	metadata *models.ConversationMetadata,
+	config TrendWindowConfig,
+) TrendAnalysis {
+	early, recent, ok := trendWindows(messages, config)
+	if !ok {
 		return TrendAnalysis{
 			SentimentTrend: TrendStable,
 			EmotionTrend:   TrendStable,
@@ -47,8 +93,8 @@ func (a *TrendAnalyzer) AnalyzeTrends(
 		}
 	}
 
-	sentimentSlope := a.calculateSentimentTrend(messages, metadata)
-	emotionSlope := a.calculateEmotionTrend(messages, metadata)
+	sentimentSlope := a.calculateSentimentTrend(early, recent, metadata, config)
+	emotionSlope := a.calculateEmotionTrend(early, recent, metadata)
 
 	sentimentTrend := a.labelTrend(sentimentSlope)
 	emotionTrend := a.labelTrend(emotionSlope)
@@ -61,24 +107,50 @@ func (a *TrendAnalyzer) AnalyzeTrends(
 	}
 }
 
+// trendWindows picks the early and recent message windows. Without a window size, all messages
+// are split at the midpoint. With one, the first and last WindowSize customer messages are used;
+// in short conversations the two windows overlap. ok is false when there are too few messages.
+func trendWindows(messages []*models.Message, config TrendWindowConfig) (early, recent []*models.Message, ok bool) {
+	minMessages := config.MinMessages
+	if minMessages < 2 {
+		minMessages = 2
+	}
+
+	if config.WindowSize <= 0 {
+		if len(messages) < minMessages {
+			return nil, nil, false
+		}
+		midPoint := len(messages) / 2
+		return messages[:midPoint], messages[midPoint:], true
+	}
+
+	customerMessages := make([]*models.Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Sender == "customer" {
+			customerMessages = append(customerMessages, msg)
+		}
+	}
+	if len(customerMessages) < minMessages {
+		return nil, nil, false
+	}
+
+	size := config.WindowSize
+	if size > len(customerMessages) {
+		size = len(customerMessages)
+	}
+	return customerMessages[:size], customerMessages[len(customerMessages)-size:], true
+}
+
 // calculateSentimentTrend calculates sentiment trend slope (-1 to 1)
 // Positive slope = improving, negative = deteriorating
 func (a *TrendAnalyzer) calculateSentimentTrend(
-	messages []*models.Message,
+	earlyMessages, recentMessages []*models.Message,
 	metadata *models.ConversationMetadata,
+	config TrendWindowConfig,
 ) float64 {
-	if len(messages) < 2 {
-		return 0.0
-	}
-
-	// Split messages into early and recent windows
-	midPoint := len(messages) / 2
-	earlyMessages := messages[:midPoint]
-	recentMessages := messages[midPoint:]
-
 	// Calculate sentiment scores for each window
-	earlyScore := a.calculateWindowSentiment(earlyMessages, metadata)
-	recentScore := a.calculateWindowSentiment(recentMessages, metadata)
+	earlyScore := a.calculateWindowSentiment(earlyMessages, metadata, config, false)
+	recentScore := a.calculateWindowSentiment(recentMessages, metadata, config, true)
 
 	// Slope is the difference normalized to -1 to 1 range
 	slope := recentScore - earlyScore
@@ -87,18 +159,13 @@ func (a *TrendAnalyzer) calculateSentimentTrend(
 
 // calculateEmotionTrend calculates emotion trend slope (-1 to 1)
 func (a *TrendAnalyzer) calculateEmotionTrend(
-	messages []*models.Message,
+	earlyMessages, recentMessages []*models.Message,
 	metadata *models.ConversationMetadata,
 ) float64 {
-	if len(messages) < 2 {
+	if len(earlyMessages) == 0 || len(recentMessages) == 0 {
 		return 0.0
 	}
 
-	// Split messages into early and recent windows
-	midPoint := len(messages) / 2
-	earlyMessages := messages[:midPoint]
-	recentMessages := messages[midPoint:]
-
 	// Count negative emotions (frustration, urgency as negative)
 	earlyNegativeCount := a.countNegativeEmotions(earlyMessages, metadata)
 	recentNegativeCount := a.countNegativeEmotions(recentMessages, metadata)
@@ -112,10 +179,14 @@ func (a *TrendAnalyzer) calculateEmotionTrend(
 	return math.Max(-1.0, math.Min(1.0, slope*2)) // Scale to -1 to 1
 }
 
-// calculateWindowSentiment calculates average sentiment score for a message window
+// calculateWindowSentiment calculates average sentiment score for a message window.
+// With config.UseWeightedAverage, messages nearer the window's anchor count more: the last message
+// of the recent window, or the first message of the early window.
 func (a *TrendAnalyzer) calculateWindowSentiment(
 	messages []*models.Message,
 	metadata *models.ConversationMetadata,
+	config TrendWindowConfig,
+	recent bool,
 ) float64 {
 	if len(messages) == 0 {
 		return 0.5 // Neutral
@@ -128,25 +199,37 @@ func (a *TrendAnalyzer) calculateWindowSentiment(
 	positiveKeywords := []string{"great", "good", "excellent", "thanks", "appreciate", "love", "happy"}
 	negativeKeywords := []string{"bad", "terrible", "awful", "disappointed", "frustrated", "hate", "angry"}
 
-	positiveCount := 0
-	negativeCount := 0
+	weightedNet := 0.0
+	totalWeight := 0.0
 
-	for _, msg := range messages {
+	for i, msg := range messages {
+		net := 0
 		content := msg.Content
 		for _, keyword := range positiveKeywords {
 			if containsWord(content, keyword) {
-				positiveCount++
+				net++
 			}
 		}
 		for _, keyword := range negativeKeywords {
 			if containsWord(content, keyword) {
-				negativeCount++
+				net--
+			}
+		}
+
+		weight := 1.0
+		if config.UseWeightedAverage {
+			distance := i // From the first message
+			if recent {
+				distance = len(messages) - 1 - i // From the last message
 			}
+			weight = math.Pow(trendWeightDecay, float64(distance))
 		}
+		weightedNet += weight * float64(net)
+		totalWeight += weight
 	}
 
-	// Adjust score: +0.1 per positive keyword, -0.1 per negative keyword
-	adjustment := float64(positiveCount-negativeCount) * 0.1 / float64(len(messages))
+	// Adjust score: +0.1 per positive keyword, -0.1 per negative keyword, averaged over the window
+	adjustment := weightedNet * 0.1 / totalWeight
 	adjustedScore := baseScore + adjustment
 
 	return math.Max(0.0, math.Min(1.0, adjustedScore))
@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func trendMessages(senderContents ...string) []*models.Message {
+	messages := make([]*models.Message, 0, len(senderContents)/2)
+	for i := 0; i+1 < len(senderContents); i += 2 {
+		messages = append(messages, &models.Message{Sender: senderContents[i], Content: senderContents[i+1]})
+	}
+	return messages
+}
+
+// recoveringConversation starts neutral, turns angry, then ends happy. Split at the midpoint the
+// first message matches the average of the last two, so the recovery is invisible.
+var recoveringConversation = trendMessages(
+	"customer", "I'd like to know about the premium plan",
+	"customer", "This is terrible and awful, I hate waiting",
+	"customer", "Great, thanks, I love it",
+)
+
+func TestAnalyzeTrends_MidpointSplitMissesShortRecovery(t *testing.T) {
+	metadata := &models.ConversationMetadata{SentimentScore: 0.5}
+
+	trends := NewTrendAnalyzer().AnalyzeTrends(recoveringConversation, metadata)
+	if trends.SentimentTrend != TrendStable {
+		t.Fatalf("midpoint sentiment trend = %s (slope %.3f), want Stable", trends.SentimentTrend, trends.SentimentSlope)
+	}
+}
+
+func TestAnalyzeTrendsWithConfig_WindowDetectsImprovement(t *testing.T) {
+	metadata := &models.ConversationMetadata{SentimentScore: 0.5}
+	config := TrendWindowConfig{WindowSize: 3, UseWeightedAverage: true}
+
+	trends := NewTrendAnalyzer().AnalyzeTrendsWithConfig(recoveringConversation, metadata, config)
+	if trends.SentimentTrend != TrendImproving {
+		t.Errorf("windowed sentiment trend = %s (slope %.3f), want Improving", trends.SentimentTrend, trends.SentimentSlope)
+	}
+
+	// Unweighted windows covering the whole conversation are identical
+	config.UseWeightedAverage = false
+	trends = NewTrendAnalyzer().AnalyzeTrendsWithConfig(recoveringConversation, metadata, config)
+	if trends.SentimentTrend != TrendStable || trends.SentimentSlope != 0 {
+		t.Errorf("unweighted overlapping windows = %s (slope %.3f), want Stable", trends.SentimentTrend, trends.SentimentSlope)
+	}
+}
+
+func TestAnalyzeTrendsWithConfig_WindowUsesCustomerMessagesOnly(t *testing.T) {
+	metadata := &models.ConversationMetadata{SentimentScore: 0.5}
+	messages := trendMessages(
+		"customer", "This is bad",
+		"agent", "Great news, thanks for waiting, we love helping",
+		"agent", "Excellent, happy to help",
+		"customer", "Still bad, I'm disappointed",
+		"agent", "Sorry to hear that",
+		"customer", "Good, thanks",
+		"customer", "Great, I appreciate it",
+	)
+
+	trends := NewTrendAnalyzer().AnalyzeTrendsWithConfig(messages, metadata, TrendWindowConfig{WindowSize: 2})
+	// First two customer messages net -3 keywords over 2, last two net +4 over 2
+	if trends.SentimentTrend != TrendImproving {
+		t.Errorf("sentiment trend = %s (slope %.3f), want Improving", trends.SentimentTrend, trends.SentimentSlope)
+	}
+	want := 0.35
+	if diff := trends.SentimentSlope - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("sentiment slope = %.3f, want %.3f", trends.SentimentSlope, want)
+	}
+}
+
+func TestAnalyzeTrendsWithConfig_MinMessages(t *testing.T) {
+	metadata := &models.ConversationMetadata{SentimentScore: 0.5}
+	messages := trendMessages(
+		"customer", "This is terrible",
+		"agent", "Let me help",
+		"customer", "Great, thanks",
+	)
+
+	tests := []struct {
+		name   string
+		config TrendWindowConfig
+		want   TrendLabel
+	}{
+		{"enough customer messages", TrendWindowConfig{WindowSize: 1, MinMessages: 2}, TrendImproving},
+		{"too few customer messages", TrendWindowConfig{WindowSize: 1, MinMessages: 3}, TrendStable},
+		{"too few messages for midpoint", TrendWindowConfig{MinMessages: 4}, TrendStable},
+		{"zero means two", TrendWindowConfig{WindowSize: 1}, TrendImproving},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends := NewTrendAnalyzer().AnalyzeTrendsWithConfig(messages, metadata, tt.config)
+			if trends.SentimentTrend != tt.want {
+				t.Errorf("sentiment trend = %s, want %s", trends.SentimentTrend, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrendWindowConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TrendWindowConfig
+		wantErr bool
+	}{
+		{"default", DefaultTrendWindowConfig(), false},
+		{"window", TrendWindowConfig{WindowSize: 5, MinMessages: 5, UseWeightedAverage: true}, false},
+		{"negative window", TrendWindowConfig{WindowSize: -1}, true},
+		{"window too large", TrendWindowConfig{WindowSize: maxTrendWindowSize + 1}, true},
+		{"negative min messages", TrendWindowConfig{MinMessages: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
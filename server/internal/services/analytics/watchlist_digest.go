@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// EmailSender delivers plain-text email (to keep the digest decoupled from SMTP)
+type EmailSender interface {
+	Send(to []string, subject, body string) error
+}
+
+// WatchlistDigest emails tenant admins a nightly summary of active watchlisted conversations
+type WatchlistDigest struct {
+	analyticsService *AnalyticsService
+	watchlistStorage *postgres.WatchlistStorage
+	userStorage      *postgres.UserStorage
+	sender           EmailSender
+	hour             int // UTC hour the digest is sent
+	stop             chan struct{}
+	stopOnce         sync.Once
+}
+
+// NewWatchlistDigest creates a watchlist digest. Reads WATCHLIST_DIGEST_HOUR (UTC, default 0).
+func NewWatchlistDigest(
+	analyticsService *AnalyticsService,
+	watchlistStorage *postgres.WatchlistStorage,
+	userStorage *postgres.UserStorage,
+	sender EmailSender,
+) *WatchlistDigest {
+	hour := 0
+	if v, err := strconv.Atoi(os.Getenv("WATCHLIST_DIGEST_HOUR")); err == nil && v >= 0 && v < 24 {
+		hour = v
+	}
+
+	return &WatchlistDigest{
+		analyticsService: analyticsService,
+		watchlistStorage: watchlistStorage,
+		userStorage:      userStorage,
+		sender:           sender,
+		hour:             hour,
+		stop:             make(chan struct{}),
+	}
+}
+
+// SendDigests emails every tenant with watchlisted conversations. Returns the number of digests sent.
+func (d *WatchlistDigest) SendDigests() (int, error) {
+	tenantIDs, err := d.watchlistStorage.ListWatchlistTenants()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, tenantID := range tenantIDs {
+		ok, err := d.sendTenantDigest(tenantID)
+		if err != nil {
+			log.Printf("[WATCHLIST] digest failed tenant=%s error=%v", tenantID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendTenantDigest emails a tenant's admins; returns false when there was nothing to send
+func (d *WatchlistDigest) sendTenantDigest(tenantID string) (bool, error) {
+	entries, err := d.watchlistStorage.ListWatchlist(tenantID)
+	if err != nil {
+		return false, err
+	}
+
+	var lines []string
+	for _, entry := range entries {
+		conv, err := d.analyticsService.conversationStorage.GetConversation(tenantID, entry.ConversationID)
+		if err != nil || conv.Status != "active" {
+			continue
+		}
+
+		winProbability := 0.0
+		if winProb, err := d.analyticsService.CalculateWinProbability(tenantID, entry.ConversationID); err == nil {
+			winProbability = winProb.Probability
+		}
+
+		line := fmt.Sprintf("- %s (priority %d): win probability %.0f%%", entry.ConversationID, entry.Priority, winProbability*100)
+		if entry.Notes != nil && *entry.Notes != "" {
+			line += " - " + *entry.Notes
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return false, nil
+	}
+
+	admins, err := d.userStorage.ListUsersByRole(tenantID, models.RoleAdmin)
+	if err != nil {
+		return false, err
+	}
+	recipients := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Email != "" {
+			recipients = append(recipients, admin.Email)
+		}
+	}
+	if len(recipients) == 0 {
+		return false, nil
+	}
+
+	subject := fmt.Sprintf("Watchlist digest: %d active conversations", len(lines))
+	body := "Active watchlisted conversations:\n\n" + strings.Join(lines, "\n") + "\n"
+	if err := d.sender.Send(recipients, subject, body); err != nil {
+		return false, err
+	}
+	log.Printf("[WATCHLIST] digest sent tenant=%s conversations=%d recipients=%d", tenantID, len(lines), len(recipients))
+	return true, nil
+}
+
+// nextRun returns the next digest time after now
+func (d *WatchlistDigest) nextRun(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), d.hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
+
+// Start sends the digest every night at the configured hour until Stop is called
+func (d *WatchlistDigest) Start() {
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(d.nextRun(time.Now())))
+			select {
+			case <-timer.C:
+				if _, err := d.SendDigests(); err != nil {
+					log.Printf("[WATCHLIST] digest run failed: %v", err)
+				}
+			case <-d.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the digest scheduler
+func (d *WatchlistDigest) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
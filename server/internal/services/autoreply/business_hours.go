@@ -0,0 +1,150 @@
+package autoreply
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// BusinessHoursSource loads a tenant's business hours (see postgres.BusinessHoursStorage)
+type BusinessHoursSource interface {
+	GetBusinessHours(tenantID string) (*postgres.BusinessHoursConfig, error)
+}
+
+// SetBusinessHours limits auto-replies to each tenant's configured business hours (optional).
+// Tenants without business hours keep getting auto-replies around the clock.
+func (s *AutoReplyService) SetBusinessHours(source BusinessHoursSource) {
+	s.businessHours = source
+}
+
+// SetClock replaces the clock used for business hours and message timestamps (for tests)
+func (s *AutoReplyService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// withinBusinessHours reports whether the tenant's team is available right now
+func (s *AutoReplyService) withinBusinessHours(tenantID string) (bool, error) {
+	if s.businessHours == nil {
+		return true, nil
+	}
+	config, err := s.businessHours.GetBusinessHours(tenantID)
+	if err != nil {
+		return false, err
+	}
+	if config == nil {
+		return true, nil
+	}
+	return IsWithinBusinessHours(config, s.now())
+}
+
+// replyOutOfHours sends the tenant's out-of-hours message, once per run of customer messages, so a
+// customer writing several times at night isn't told the same thing after each message
+func (s *AutoReplyService) replyOutOfHours(tenantID, conversationID string, messages []*models.Message) error {
+	globalConfig, err := s.globalConfigStorage.GetGlobalConfig(tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get global config: %w", err)
+	}
+	if globalConfig.OutOfHoursMessage == "" {
+		log.Printf("[AUTO_REPLY] outside business hours, skipping conversation=%s", conversationID)
+		return nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Sender == "customer" {
+			continue
+		}
+		if messages[i].IsAutoReply && messages[i].Content == globalConfig.OutOfHoursMessage {
+			log.Printf("[AUTO_REPLY] out-of-hours message already sent conversation=%s", conversationID)
+			return nil
+		}
+		break
+	}
+
+	normalized, err := conversation.NormalizeMessage(globalConfig.OutOfHoursMessage, "agent", "web", s.now(), conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to normalize out-of-hours message: %w", err)
+	}
+	normalized.IsAutoReply = true
+
+	messageID, err := s.ingestionService.IngestMessage(tenantID, normalized)
+	if err != nil {
+		return fmt.Errorf("failed to send out-of-hours message: %w", err)
+	}
+	log.Printf("[AUTO_REPLY] sent out-of-hours message message_id=%s conversation=%s", messageID, conversationID)
+	return nil
+}
+
+// IsWithinBusinessHours reports whether t falls inside one of the schedule's windows, in the
+// config's timezone
+func IsWithinBusinessHours(config *postgres.BusinessHoursConfig, t time.Time) (bool, error) {
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("invalid business hours timezone %q: %w", config.Timezone, err)
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	for _, window := range config.Schedule {
+		if time.Weekday(window.DayOfWeek) != local.Weekday() {
+			continue
+		}
+		opens, err := parseClockMinutes(window.OpenTime)
+		if err != nil {
+			return false, err
+		}
+		closes, err := parseClockMinutes(window.CloseTime)
+		if err != nil {
+			return false, err
+		}
+		if minute >= opens && minute < closes {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ValidateBusinessHours checks a business hours config before it is saved
+func ValidateBusinessHours(config *postgres.BusinessHoursConfig) error {
+	if config.Timezone == "" {
+		return fmt.Errorf("timezone is required")
+	}
+	if _, err := time.LoadLocation(config.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", config.Timezone)
+	}
+	for i, window := range config.Schedule {
+		if window.DayOfWeek < 0 || window.DayOfWeek > 6 {
+			return fmt.Errorf("schedule[%d]: day_of_week must be between 0 (Sunday) and 6 (Saturday)", i)
+		}
+		opens, err := parseClockMinutes(window.OpenTime)
+		if err != nil {
+			return fmt.Errorf("schedule[%d]: %w", i, err)
+		}
+		closes, err := parseClockMinutes(window.CloseTime)
+		if err != nil {
+			return fmt.Errorf("schedule[%d]: %w", i, err)
+		}
+		if closes <= opens {
+			return fmt.Errorf("schedule[%d]: close_time must be after open_time", i)
+		}
+	}
+	return nil
+}
+
+// parseClockMinutes parses an HH:MM time of day into minutes after midnight. 24:00 is allowed as
+// the end of the day.
+func parseClockMinutes(value string) (int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) == 2 && len(parts[0]) == 2 && len(parts[1]) == 2 {
+		hour, hourErr := strconv.Atoi(parts[0])
+		minute, minuteErr := strconv.Atoi(parts[1])
+		if hourErr == nil && minuteErr == nil && minute >= 0 && minute < 60 &&
+			(hour >= 0 && hour < 24 || hour == 24 && minute == 0) {
+			return hour*60 + minute, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+}
@@ -0,0 +1,188 @@
+package autoreply
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/services/agentassist"
+	"ai-conversation-platform/internal/services/conversation"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeAutoReplyDeps stands in for the storages, agent assist and ingestion behind auto-reply
+type fakeAutoReplyDeps struct {
+	global        *models.AutoReplyGlobalConfig
+	messages      []*models.Message
+	hours         *postgres.BusinessHoursConfig
+	suggestions   []agentassist.Suggestion
+	suggestionReq int
+	sent          []*conversation.NormalizedMessage
+}
+
+func (f *fakeAutoReplyDeps) GetGlobalConfig(tenantID string) (*models.AutoReplyGlobalConfig, error) {
+	return f.global, nil
+}
+
+func (f *fakeAutoReplyDeps) GetConversationConfig(conversationID string) (*models.AutoReplyConversationConfig, error) {
+	return nil, errNoConversationConfig
+}
+
+func (f *fakeAutoReplyDeps) GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error) {
+	return f.messages, nil
+}
+
+func (f *fakeAutoReplyDeps) GetLastAutoReplyText(tenantID, conversationID string) (string, error) {
+	return "", nil
+}
+
+func (f *fakeAutoReplyDeps) GetReplySuggestions(ctx context.Context, tenantID, conversationID string, forceRegenerate bool) (*agentassist.SuggestionsResponse, error) {
+	f.suggestionReq++
+	return &agentassist.SuggestionsResponse{Suggestions: f.suggestions, SuggestionCount: len(f.suggestions)}, nil
+}
+
+func (f *fakeAutoReplyDeps) IngestMessage(tenantID string, normalized *conversation.NormalizedMessage) (string, error) {
+	f.sent = append(f.sent, normalized)
+	f.messages = append(f.messages, &models.Message{Sender: normalized.Sender, Content: normalized.Message, IsAutoReply: normalized.IsAutoReply})
+	return "msg-1", nil
+}
+
+func (f *fakeAutoReplyDeps) GetBusinessHours(tenantID string) (*postgres.BusinessHoursConfig, error) {
+	return f.hours, nil
+}
+
+var errNoConversationConfig = errors.New("conversation config not found")
+
+func newBusinessHoursService(deps *fakeAutoReplyDeps, now time.Time) *AutoReplyService {
+	s := &AutoReplyService{
+		globalConfigStorage:       deps,
+		conversationConfigStorage: deps,
+		conversationStorage:       deps,
+		agentAssistService:        deps,
+		ingestionService:          deps,
+		similarityThreshold:       defaultSimilarityThreshold,
+	}
+	s.SetBusinessHours(deps)
+	s.SetClock(func() time.Time { return now })
+	return s
+}
+
+// weekdayHours is open Monday-Friday 09:00-17:30 India time
+var weekdayHours = &postgres.BusinessHoursConfig{
+	Timezone: "Asia/Kolkata",
+	Schedule: []postgres.BusinessHoursWindow{
+		{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "17:30"},
+		{DayOfWeek: 2, OpenTime: "09:00", CloseTime: "17:30"},
+		{DayOfWeek: 3, OpenTime: "09:00", CloseTime: "17:30"},
+		{DayOfWeek: 4, OpenTime: "09:00", CloseTime: "17:30"},
+		{DayOfWeek: 5, OpenTime: "09:00", CloseTime: "17:30"},
+	},
+}
+
+func TestIsWithinBusinessHours(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"Wednesday 10:00 IST", time.Date(2026, 10, 14, 4, 30, 0, 0, time.UTC), true},
+		{"Wednesday 09:00 IST opens", time.Date(2026, 10, 14, 3, 30, 0, 0, time.UTC), true},
+		{"Wednesday 08:59 IST", time.Date(2026, 10, 14, 3, 29, 0, 0, time.UTC), false},
+		{"Wednesday 17:30 IST closes", time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), false},
+		{"Friday 20:00 UTC is Saturday in India", time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC), false},
+		{"Thursday 20:00 UTC is Friday 01:30 in India", time.Date(2026, 10, 15, 20, 0, 0, 0, time.UTC), false},
+		{"Friday 03:30 UTC is Friday 09:00 in India", time.Date(2026, 10, 16, 3, 30, 0, 0, time.UTC), true},
+	}
+	for _, tc := range tests {
+		got, err := IsWithinBusinessHours(weekdayHours, tc.at)
+		if err != nil {
+			t.Fatalf("%s: IsWithinBusinessHours: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: IsWithinBusinessHours = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestProcessAutoReplySuppressedOutsideBusinessHours(t *testing.T) {
+	saturday := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	deps := &fakeAutoReplyDeps{
+		global:      &models.AutoReplyGlobalConfig{Enabled: true, ConfidenceThreshold: 0.5},
+		messages:    []*models.Message{{Sender: "customer", Content: "Is anyone there?"}},
+		hours:       weekdayHours,
+		suggestions: []agentassist.Suggestion{{Text: "Yes, how can I help?", Confidence: 0.9}},
+	}
+
+	if err := newBusinessHoursService(deps, saturday).ProcessAutoReply("tenant-1", "conv-1"); err != nil {
+		t.Fatalf("ProcessAutoReply: %v", err)
+	}
+	if deps.suggestionReq != 0 || len(deps.sent) != 0 {
+		t.Errorf("outside hours: requested suggestions %d times and sent %d messages, want neither", deps.suggestionReq, len(deps.sent))
+	}
+
+	// Within hours the suggestion goes out as usual
+	monday := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	if err := newBusinessHoursService(deps, monday).ProcessAutoReply("tenant-1", "conv-1"); err != nil {
+		t.Fatalf("ProcessAutoReply: %v", err)
+	}
+	if len(deps.sent) != 1 || deps.sent[0].Message != "Yes, how can I help?" || !deps.sent[0].Timestamp.Equal(monday) {
+		t.Errorf("within hours sent %+v, want the suggestion stamped with the clock", deps.sent)
+	}
+}
+
+func TestProcessAutoReplySendsOutOfHoursMessageOnce(t *testing.T) {
+	saturday := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	closed := "Thanks for reaching out! Our team is back Monday at 9am IST."
+	deps := &fakeAutoReplyDeps{
+		global:      &models.AutoReplyGlobalConfig{Enabled: true, ConfidenceThreshold: 0.5, OutOfHoursMessage: closed},
+		messages:    []*models.Message{{Sender: "customer", Content: "Hello?"}},
+		hours:       weekdayHours,
+		suggestions: []agentassist.Suggestion{{Text: "Hi!", Confidence: 0.9}},
+	}
+	service := newBusinessHoursService(deps, saturday)
+
+	if err := service.ProcessAutoReply("tenant-1", "conv-1"); err != nil {
+		t.Fatalf("ProcessAutoReply: %v", err)
+	}
+	if len(deps.sent) != 1 || deps.sent[0].Message != closed || !deps.sent[0].IsAutoReply || deps.sent[0].Sender != "agent" {
+		t.Fatalf("sent %+v, want the out-of-hours message as an auto-reply", deps.sent)
+	}
+	if deps.suggestionReq != 0 {
+		t.Errorf("requested suggestions %d times outside hours", deps.suggestionReq)
+	}
+
+	// A follow-up from the customer doesn't repeat it
+	deps.messages = append(deps.messages, &models.Message{Sender: "customer", Content: "Anyone?"})
+	if err := service.ProcessAutoReply("tenant-1", "conv-1"); err != nil {
+		t.Fatalf("ProcessAutoReply: %v", err)
+	}
+	if len(deps.sent) != 1 {
+		t.Errorf("sent %d messages, want the out-of-hours message only once", len(deps.sent))
+	}
+}
+
+func TestValidateBusinessHours(t *testing.T) {
+	if err := ValidateBusinessHours(weekdayHours); err != nil {
+		t.Errorf("ValidateBusinessHours(weekdays) = %v", err)
+	}
+	allDay := &postgres.BusinessHoursConfig{Timezone: "UTC", Schedule: []postgres.BusinessHoursWindow{{DayOfWeek: 0, OpenTime: "00:00", CloseTime: "24:00"}}}
+	if err := ValidateBusinessHours(allDay); err != nil {
+		t.Errorf("ValidateBusinessHours(all day) = %v", err)
+	}
+
+	invalid := []*postgres.BusinessHoursConfig{
+		{Timezone: ""},
+		{Timezone: "Mars/Olympus_Mons"},
+		{Timezone: "UTC", Schedule: []postgres.BusinessHoursWindow{{DayOfWeek: 7, OpenTime: "09:00", CloseTime: "17:00"}}},
+		{Timezone: "UTC", Schedule: []postgres.BusinessHoursWindow{{DayOfWeek: 1, OpenTime: "9:00", CloseTime: "17:00"}}},
+		{Timezone: "UTC", Schedule: []postgres.BusinessHoursWindow{{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "24:30"}}},
+		{Timezone: "UTC", Schedule: []postgres.BusinessHoursWindow{{DayOfWeek: 1, OpenTime: "17:00", CloseTime: "09:00"}}},
+	}
+	for i, config := range invalid {
+		if err := ValidateBusinessHours(config); err == nil {
+			t.Errorf("ValidateBusinessHours(invalid[%d]) = nil, want an error", i)
+		}
+	}
+}
@@ -1,22 +1,61 @@
 package autoreply
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
 	"ai-conversation-platform/internal/services/agentassist"
 	"ai-conversation-platform/internal/services/conversation"
 	"ai-conversation-platform/internal/storage/postgres"
 )
 
+// defaultSimilarityThreshold is the Jaccard similarity above which an auto-reply counts as a repeat
+const defaultSimilarityThreshold = 0.7
+
+// HandoffNotifier is told when a conversation needs an agent to take over (e.g. to post to Slack)
+type HandoffNotifier interface {
+	NotifyEscalation(tenantID, conversationID string, winProbability float64, recommendedAction, details string)
+}
+
+// ConfigStore loads global and per-conversation auto-reply settings (see postgres.AutoReplyStorage)
+type ConfigStore interface {
+	GetGlobalConfig(tenantID string) (*models.AutoReplyGlobalConfig, error)
+	GetConversationConfig(conversationID string) (*models.AutoReplyConversationConfig, error)
+}
+
+// MessageStore loads the conversation history auto-reply decides on (see postgres.ConversationStorage)
+type MessageStore interface {
+	GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error)
+	GetLastAutoReplyText(tenantID, conversationID string) (string, error)
+}
+
+// SuggestionSource generates the reply suggestions auto-reply picks from
+type SuggestionSource interface {
+	GetReplySuggestions(ctx context.Context, tenantID, conversationID string, forceRegenerate bool) (*agentassist.SuggestionsResponse, error)
+}
+
+// MessageSender sends auto-replies into the conversation
+type MessageSender interface {
+	IngestMessage(tenantID string, normalized *conversation.NormalizedMessage) (string, error)
+}
+
 // AutoReplyService handles auto-reply functionality
 type AutoReplyService struct {
-	globalConfigStorage    *postgres.AutoReplyStorage
-	conversationConfigStorage *postgres.AutoReplyStorage
-	conversationStorage    *postgres.ConversationStorage
-	agentAssistService     *agentassist.AgentAssistService
-	ingestionService       *conversation.IngestionService
+	globalConfigStorage    ConfigStore
+	conversationConfigStorage ConfigStore
+	conversationStorage    MessageStore
+	agentAssistService     SuggestionSource
+	ingestionService       MessageSender
+	similarityChecker      ai.SimilarityChecker
+	similarityThreshold    float64
+	handoffNotifier        HandoffNotifier // Optional
+	businessHours          BusinessHoursSource // Optional, replies around the clock without it
+	now                    func() time.Time
 }
 
 // NewAutoReplyService creates a new auto-reply service
@@ -33,9 +72,28 @@ func NewAutoReplyService(
 		conversationStorage:        conversationStorage,
 		agentAssistService:         agentAssistService,
 		ingestionService:           ingestionService,
+		similarityThreshold:        defaultSimilarityThreshold,
+		now:                        time.Now,
 	}
 }
 
+// SetSimilarityThreshold sets how similar (Jaccard, 0-1) a reply may be to the previous auto-reply
+func (s *AutoReplyService) SetSimilarityThreshold(threshold float64) {
+	if threshold > 0 {
+		s.similarityThreshold = threshold
+	}
+}
+
+// SetHandoffNotifier sets the notifier used when auto-reply hands a conversation to an agent
+func (s *AutoReplyService) SetHandoffNotifier(notifier HandoffNotifier) {
+	s.handoffNotifier = notifier
+}
+
+// GetLastAutoReplyText returns the conversation's most recent auto-reply text, or "" if none was sent
+func (s *AutoReplyService) GetLastAutoReplyText(tenantID, conversationID string) (string, error) {
+	return s.conversationStorage.GetLastAutoReplyText(tenantID, conversationID)
+}
+
 // EffectiveConfig represents the effective auto-reply configuration for a conversation
 type EffectiveConfig struct {
 	Enabled            bool
@@ -126,8 +184,18 @@ func (s *AutoReplyService) ProcessAutoReply(tenantID, conversationID string) err
 		return nil
 	}
 
+	// 3a. Outside business hours, send the out-of-hours message instead of a suggestion
+	open, err := s.withinBusinessHours(tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to check business hours: %w", err)
+	}
+	if !open {
+		return s.replyOutOfHours(tenantID, conversationID, messages)
+	}
+
 	// 4. Get AI suggestions (use cached if available, don't force regenerate)
-	suggestionsResp, err := s.agentAssistService.GetReplySuggestions(tenantID, conversationID, false)
+	// Auto-replies run after the ingesting request has returned, so they aren't part of its trace
+	suggestionsResp, err := s.agentAssistService.GetReplySuggestions(context.Background(), tenantID, conversationID, false)
 	if err != nil {
 		return fmt.Errorf("failed to get suggestions: %w", err)
 	}
@@ -137,19 +205,25 @@ func (s *AutoReplyService) ProcessAutoReply(tenantID, conversationID string) err
 		return nil
 	}
 
-	// 5. Find best suggestion that meets confidence threshold
-	var bestSuggestion *agentassist.Suggestion
-	for i := range suggestionsResp.Suggestions {
-		sug := &suggestionsResp.Suggestions[i]
-		if sug.Confidence >= config.ConfidenceThreshold {
-			if bestSuggestion == nil || sug.Confidence > bestSuggestion.Confidence {
-				bestSuggestion = sug
-			}
-		}
+	// 5. Rank suggestions that meet the confidence threshold
+	candidates := rankSuggestions(suggestionsResp.Suggestions, suggestionsResp.SuggestionCount == 1, config.ConfidenceThreshold)
+	if len(candidates) == 0 {
+		log.Printf("[AUTO_REPLY] no suggestion meets confidence threshold (%.2f) conversation=%s", config.ConfidenceThreshold, conversationID)
+		return nil
 	}
 
+	// 5a. Don't repeat the previous auto-reply; fall back to the runner-up, then to an agent
+	lastAutoReply, err := s.GetLastAutoReplyText(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[AUTO_REPLY] failed to load last auto-reply, skipping repeat check conversation=%s error=%v", conversationID, err)
+	}
+	bestSuggestion := s.pickNonRepeating(candidates, lastAutoReply)
 	if bestSuggestion == nil {
-		log.Printf("[AUTO_REPLY] no suggestion meets confidence threshold (%.2f) conversation=%s", config.ConfidenceThreshold, conversationID)
+		log.Printf("[AUTO_REPLY] suggestions repeat the last auto-reply, handing off to agent conversation=%s threshold=%.2f", conversationID, s.similarityThreshold)
+		if s.handoffNotifier != nil {
+			s.handoffNotifier.NotifyEscalation(tenantID, conversationID, 0, "Reply to the customer",
+				"Auto-reply was skipped because the suggested replies repeat the previous automatic reply.")
+		}
 		return nil
 	}
 
@@ -158,12 +232,15 @@ func (s *AutoReplyService) ProcessAutoReply(tenantID, conversationID string) err
 		bestSuggestion.Text,
 		"agent",
 		"web",
-		time.Now(),
+		s.now(),
 		conversationID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to normalize auto-reply message: %w", err)
 	}
+	normalized.IsAutoReply = true
+	confidence := bestSuggestion.Confidence
+	normalized.SuggestionConfidence = &confidence
 
 	messageID, err := s.ingestionService.IngestMessage(tenantID, normalized)
 	if err != nil {
@@ -174,3 +251,45 @@ func (s *AutoReplyService) ProcessAutoReply(tenantID, conversationID string) err
 	return nil
 }
 
+
+// rankSuggestions returns the suggestions meeting the confidence threshold, most confident first.
+// A tenant configured for a single suggestion gets exactly that suggestion, ignoring pinned pricing.
+func rankSuggestions(suggestions []agentassist.Suggestion, singleSuggestion bool, threshold float64) []*agentassist.Suggestion {
+	var ranked []*agentassist.Suggestion
+	for i := range suggestions {
+		sug := &suggestions[i]
+		if singleSuggestion {
+			if sug.Pinned {
+				continue
+			}
+			if sug.Confidence >= threshold {
+				ranked = append(ranked, sug)
+			}
+			break
+		}
+		if sug.Confidence >= threshold {
+			ranked = append(ranked, sug)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Confidence > ranked[j].Confidence
+	})
+	return ranked
+}
+
+// pickNonRepeating returns the best of the top two candidates that isn't too similar to the
+// last auto-reply, or nil if both are
+func (s *AutoReplyService) pickNonRepeating(candidates []*agentassist.Suggestion, lastAutoReply string) *agentassist.Suggestion {
+	if lastAutoReply == "" {
+		return candidates[0]
+	}
+	for i, candidate := range candidates {
+		if i == 2 {
+			break
+		}
+		if s.similarityChecker.Jaccard(candidate.Text, lastAutoReply) <= s.similarityThreshold {
+			return candidate
+		}
+	}
+	return nil
+}
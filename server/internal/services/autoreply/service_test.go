@@ -0,0 +1,42 @@
+package autoreply
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/services/agentassist"
+)
+
+func TestRankSuggestionsByConfidence(t *testing.T) {
+	suggestions := []agentassist.Suggestion{
+		{Text: "low", Confidence: 0.5},
+		{Text: "second", Confidence: 0.8},
+		{Text: "best", Confidence: 0.9},
+	}
+
+	ranked := rankSuggestions(suggestions, false, 0.7)
+	if len(ranked) != 2 || ranked[0].Text != "best" || ranked[1].Text != "second" {
+		t.Fatalf("ranked = %+v, want best then second", ranked)
+	}
+
+	single := rankSuggestions([]agentassist.Suggestion{{Text: "pinned", Confidence: 1, Pinned: true}, {Text: "only", Confidence: 0.8}}, true, 0.7)
+	if len(single) != 1 || single[0].Text != "only" {
+		t.Errorf("single suggestion = %+v, want the first unpinned suggestion", single)
+	}
+}
+
+func TestPickNonRepeating(t *testing.T) {
+	service := &AutoReplyService{similarityThreshold: defaultSimilarityThreshold}
+	last := "Here's information about WhatsApp Starter"
+	repeat := &agentassist.Suggestion{Text: "Here's information about WhatsApp Starter!"}
+	different := &agentassist.Suggestion{Text: "WhatsApp Starter costs $19 per month and includes one number."}
+
+	if got := service.pickNonRepeating([]*agentassist.Suggestion{repeat, different}, ""); got != repeat {
+		t.Error("expected the best suggestion when no auto-reply was sent yet")
+	}
+	if got := service.pickNonRepeating([]*agentassist.Suggestion{repeat, different}, last); got != different {
+		t.Errorf("got %+v, want the second-best suggestion", got)
+	}
+	if got := service.pickNonRepeating([]*agentassist.Suggestion{repeat, repeat, different}, last); got != nil {
+		t.Errorf("got %+v, want nil when the top two both repeat", got)
+	}
+}
@@ -0,0 +1,20 @@
+package conversation
+
+import (
+	"fmt"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// GetAnalysisHistory returns every analysis of a conversation in chronological order
+func (s *IngestionService) GetAnalysisHistory(tenantID, conversationID string) ([]*models.AnalysisHistoryEntry, error) {
+	if _, err := s.conversationStorage.GetConversation(tenantID, conversationID); err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	entries, err := s.conversationStorage.GetAnalysisHistory(tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,134 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/worker"
+)
+
+// blockingAnalyzer holds every analysis until release is closed
+type blockingAnalyzer struct {
+	started  chan string
+	release  chan struct{}
+	analyzed int32
+}
+
+func (a *blockingAnalyzer) AnalyzeConversation(ctx context.Context, tenantID, conversationID string, messages []*models.Message) error {
+	a.started <- conversationID
+	<-a.release
+	atomic.AddInt32(&a.analyzed, 1)
+	return nil
+}
+
+func TestAnalyzeAsyncQueuesOnPool(t *testing.T) {
+	analyzer := &blockingAnalyzer{started: make(chan string, 10), release: make(chan struct{})}
+	pool := worker.NewPool(worker.Config{Workers: 1, QueueSize: 2})
+	pool.Start()
+
+	s := NewIngestionService(nil)
+	s.SetAnalyzer(analyzer, pool)
+
+	s.analyzeAsync("tenant-1", "c1", nil)
+	<-analyzer.started // c1 holds the only worker
+
+	s.analyzeAsync("tenant-1", "c2", nil)
+	s.analyzeAsync("tenant-1", "c3", nil)
+	if got := pool.Len(); got != 2 {
+		t.Fatalf("pending analyses = %d, want 2", got)
+	}
+	// The queue is full, so this analysis is dropped rather than started in a new goroutine
+	s.analyzeAsync("tenant-1", "c4", nil)
+	if got := pool.Len(); got != 2 {
+		t.Fatalf("pending analyses after overflow = %d, want 2", got)
+	}
+
+	close(analyzer.release)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if got := atomic.LoadInt32(&analyzer.analyzed); got != 3 {
+		t.Errorf("analyzed = %d, want 3", got)
+	}
+}
+
+// quotaAnalyzer fails with a quota error until its final attempt
+type quotaAnalyzer struct {
+	attempts int32
+}
+
+func (a *quotaAnalyzer) AnalyzeConversation(ctx context.Context, tenantID, conversationID string, messages []*models.Message) error {
+	atomic.AddInt32(&a.attempts, 1)
+	if !worker.IsFinalAttempt(ctx) {
+		return errors.New("429 quota exceeded")
+	}
+	return nil
+}
+
+func TestAnalyzeAsyncRetriesQuotaErrors(t *testing.T) {
+	analyzer := &quotaAnalyzer{}
+	pool := worker.NewPool(worker.Config{
+		Workers:      1,
+		QueueSize:    1,
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+		Retryable:    func(err error) bool { return err != nil },
+	})
+	pool.Start()
+
+	s := NewIngestionService(nil)
+	s.SetAnalyzer(analyzer, pool)
+	s.analyzeAsync("tenant-1", "c1", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if got := atomic.LoadInt32(&analyzer.attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestAnalyzeIfStaleSkipsFillerMessages(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.SetRegistry(registry)
+	t.Cleanup(func() { metrics.SetRegistry(nil) })
+
+	pool := worker.NewPool(worker.Config{Workers: 1, QueueSize: 5})
+	s := NewIngestionService(nil)
+	s.SetAnalyzer(&blockingAnalyzer{}, pool)
+
+	history := []*models.Message{{ID: "m1", ConversationID: "c1", Content: "I need a laptop for video editing"}}
+	fresh := &models.ConversationMetadata{ConversationID: "c1", Intent: "buying", UpdatedAt: time.Now()}
+
+	// A filler reply to fresh metadata is skipped and counted
+	filler := &models.Message{ID: "m2", ConversationID: "c1", Sender: "customer", Content: "ok thanks"}
+	s.analyzeIfStale("tenant-1", filler, fresh, append(history, filler))
+	if got := pool.Len(); got != 0 {
+		t.Errorf("pending analyses after filler = %d, want 0", got)
+	}
+
+	// A substantive message is analyzed
+	question := &models.Message{ID: "m3", ConversationID: "c1", Sender: "customer", Content: "Does it come with a warranty?"}
+	s.analyzeIfStale("tenant-1", question, fresh, append(history, question))
+	if got := pool.Len(); got != 1 {
+		t.Errorf("pending analyses after question = %d, want 1", got)
+	}
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if sample := "analysis_skipped_total 1"; !strings.Contains(rec.Body.String(), sample+"\n") {
+		t.Errorf("missing sample %q", sample)
+	}
+}
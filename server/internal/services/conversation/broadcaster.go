@@ -0,0 +1,102 @@
+package conversation
+
+import (
+	"log"
+	"sync"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// subscriberBufferSize is how many messages a subscriber can fall behind before it is dropped
+const subscriberBufferSize = 256
+
+// MessageSubscription receives the messages published to one conversation
+type MessageSubscription struct {
+	key      string
+	messages chan *models.Message
+}
+
+// Messages returns the subscription's channel. It is closed on Unsubscribe, or when the subscriber
+// falls too far behind; a closed channel means messages may have been missed.
+func (s *MessageSubscription) Messages() <-chan *models.Message {
+	return s.messages
+}
+
+// MessageBroadcaster fans new messages out to the subscribers of each conversation (e.g. agents'
+// WebSocket connections). Subscriptions are in-memory, so only messages ingested by this instance
+// are delivered.
+type MessageBroadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*MessageSubscription]struct{} // tenant|conversation -> subscriptions
+}
+
+// NewMessageBroadcaster creates a new message broadcaster
+func NewMessageBroadcaster() *MessageBroadcaster {
+	return &MessageBroadcaster{subscribers: make(map[string]map[*MessageSubscription]struct{})}
+}
+
+func broadcastKey(tenantID, conversationID string) string {
+	return tenantID + "|" + conversationID
+}
+
+// Subscribe registers a subscriber for a conversation's new messages
+func (b *MessageBroadcaster) Subscribe(tenantID, conversationID string) *MessageSubscription {
+	sub := &MessageSubscription{
+		key:      broadcastKey(tenantID, conversationID),
+		messages: make(chan *models.Message, subscriberBufferSize),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers[sub.key] == nil {
+		b.subscribers[sub.key] = make(map[*MessageSubscription]struct{})
+	}
+	b.subscribers[sub.key][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel. Safe to call more than once.
+func (b *MessageBroadcaster) Unsubscribe(sub *MessageSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs, ok := b.subscribers[sub.key]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.key)
+	}
+	close(sub.messages)
+}
+
+// Publish delivers a message to every subscriber of its conversation without blocking. Subscribers
+// whose buffer is full are dropped rather than slowing down ingestion.
+func (b *MessageBroadcaster) Publish(tenantID string, msg *models.Message) {
+	key := broadcastKey(tenantID, msg.ConversationID)
+
+	var slow []*MessageSubscription
+	b.mu.RLock()
+	for sub := range b.subscribers[key] {
+		select {
+		case sub.messages <- msg:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Printf("[BROADCAST] dropping slow subscriber conversation=%s buffered=%d", msg.ConversationID, subscriberBufferSize)
+		b.Unsubscribe(sub)
+	}
+}
+
+// SubscriberCount returns the number of subscribers of a conversation
+func (b *MessageBroadcaster) SubscriberCount(tenantID, conversationID string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[broadcastKey(tenantID, conversationID)])
+}
@@ -0,0 +1,102 @@
+package conversation
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestMessageBroadcasterConcurrentWritersDeliverExactlyOnce(t *testing.T) {
+	// Stays within the subscriber buffer so delivery doesn't depend on how fast readers are
+	const (
+		writers     = 8
+		perWriter   = subscriberBufferSize / 8
+		subscribers = 5
+	)
+	b := NewMessageBroadcaster()
+
+	subs := make([]*MessageSubscription, subscribers)
+	received := make([]map[string]int, subscribers)
+	var readers sync.WaitGroup
+	for i := range subs {
+		subs[i] = b.Subscribe("tenant-1", "c1")
+		received[i] = make(map[string]int)
+		readers.Add(1)
+		go func(i int) {
+			defer readers.Done()
+			for msg := range subs[i].Messages() {
+				received[i][msg.ID]++
+			}
+		}(i)
+	}
+
+	var writersWG sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		writersWG.Add(1)
+		go func(w int) {
+			defer writersWG.Done()
+			for n := 0; n < perWriter; n++ {
+				b.Publish("tenant-1", &models.Message{ID: fmt.Sprintf("w%d-%d", w, n), ConversationID: "c1"})
+			}
+		}(w)
+	}
+	writersWG.Wait()
+
+	for _, sub := range subs {
+		b.Unsubscribe(sub)
+	}
+	readers.Wait()
+
+	for i, counts := range received {
+		if len(counts) != writers*perWriter {
+			t.Errorf("subscriber %d received %d distinct messages, want %d", i, len(counts), writers*perWriter)
+		}
+		for id, n := range counts {
+			if n != 1 {
+				t.Errorf("subscriber %d received %s %d times, want once", i, id, n)
+			}
+		}
+	}
+}
+
+func TestMessageBroadcasterScopesByTenantAndConversation(t *testing.T) {
+	b := NewMessageBroadcaster()
+	sub := b.Subscribe("tenant-1", "c1")
+	defer b.Unsubscribe(sub)
+
+	b.Publish("tenant-2", &models.Message{ID: "other-tenant", ConversationID: "c1"})
+	b.Publish("tenant-1", &models.Message{ID: "other-conversation", ConversationID: "c2"})
+	b.Publish("tenant-1", &models.Message{ID: "m1", ConversationID: "c1"})
+
+	if got := (<-sub.Messages()).ID; got != "m1" {
+		t.Errorf("received %s, want m1 only", got)
+	}
+	if len(sub.Messages()) != 0 {
+		t.Errorf("%d unexpected messages buffered", len(sub.Messages()))
+	}
+}
+
+func TestMessageBroadcasterDropsSlowSubscriber(t *testing.T) {
+	b := NewMessageBroadcaster()
+	slow := b.Subscribe("tenant-1", "c1")
+
+	for i := 0; i <= subscriberBufferSize; i++ {
+		b.Publish("tenant-1", &models.Message{ID: fmt.Sprint(i), ConversationID: "c1"})
+	}
+
+	if n := b.SubscriberCount("tenant-1", "c1"); n != 0 {
+		t.Fatalf("SubscriberCount = %d, want the slow subscriber dropped", n)
+	}
+	delivered := 0
+	for range slow.Messages() {
+		delivered++
+	}
+	if delivered != subscriberBufferSize {
+		t.Errorf("delivered %d buffered messages before close, want %d", delivered, subscriberBufferSize)
+	}
+
+	// Unsubscribing a dropped subscription is a no-op
+	b.Unsubscribe(slow)
+}
@@ -0,0 +1,40 @@
+package conversation
+
+import "sync"
+
+// DashboardUpdates signals live dashboards that a tenant's conversations changed (a
+// conversation.updated event). Signals carry no data: subscribers re-read whatever they display.
+// Subscriptions are in-memory, so only messages ingested by this instance are signalled.
+type DashboardUpdates struct {
+	tenants sync.Map // tenant_id -> *sync.Map of chan struct{}
+}
+
+// NewDashboardUpdates creates a new dashboard update pubsub
+func NewDashboardUpdates() *DashboardUpdates {
+	return &DashboardUpdates{}
+}
+
+// Subscribe registers a subscriber for a tenant's updates and returns its channel and a function
+// that unsubscribes it. Signals that arrive while one is pending are merged into it.
+func (u *DashboardUpdates) Subscribe(tenantID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	value, _ := u.tenants.LoadOrStore(tenantID, &sync.Map{})
+	subscribers := value.(*sync.Map)
+	subscribers.Store(ch, struct{}{})
+	return ch, func() { subscribers.Delete(ch) }
+}
+
+// Publish signals every subscriber of the tenant without blocking
+func (u *DashboardUpdates) Publish(tenantID string) {
+	value, ok := u.tenants.Load(tenantID)
+	if !ok {
+		return
+	}
+	value.(*sync.Map).Range(func(key, _ interface{}) bool {
+		select {
+		case key.(chan struct{}) <- struct{}{}:
+		default: // A signal is already pending
+		}
+		return true
+	})
+}
@@ -0,0 +1,50 @@
+package conversation
+
+import (
+	"testing"
+)
+
+func TestDashboardUpdatesSignalsTenantSubscribers(t *testing.T) {
+	updates := NewDashboardUpdates()
+	first, unsubscribeFirst := updates.Subscribe("tenant-1")
+	second, unsubscribeSecond := updates.Subscribe("tenant-1")
+	other, unsubscribeOther := updates.Subscribe("tenant-2")
+	defer unsubscribeSecond()
+	defer unsubscribeOther()
+
+	// Repeated signals collapse into one pending signal instead of blocking
+	updates.Publish("tenant-1")
+	updates.Publish("tenant-1")
+	updates.Publish("tenant-3")
+
+	for name, ch := range map[string]<-chan struct{}{"first": first, "second": second} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("%s subscriber was not signalled", name)
+		}
+		select {
+		case <-ch:
+			t.Errorf("%s subscriber got a second signal, want them merged", name)
+		default:
+		}
+	}
+	select {
+	case <-other:
+		t.Error("another tenant's subscriber was signalled")
+	default:
+	}
+
+	unsubscribeFirst()
+	updates.Publish("tenant-1")
+	select {
+	case <-first:
+		t.Error("unsubscribed subscriber was signalled")
+	default:
+	}
+	select {
+	case <-second:
+	default:
+		t.Error("remaining subscriber was not signalled")
+	}
+}
@@ -0,0 +1,86 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// Message deletion reasons
+const (
+	DeletionReasonGDPR  = "gdpr_request"
+	DeletionReasonAbuse = "abuse"
+	DeletionReasonError = "error"
+)
+
+// IsValidDeletionReason checks if a message deletion reason is supported
+func IsValidDeletionReason(reason string) bool {
+	switch reason {
+	case DeletionReasonGDPR, DeletionReasonAbuse, DeletionReasonError:
+		return true
+	}
+	return false
+}
+
+// DeleteMessage soft-deletes a message in a conversation and records the deletion
+func (s *IngestionService) DeleteMessage(tenantID, conversationID, messageID, deletedBy, reason string) error {
+	if !IsValidDeletionReason(reason) {
+		return fmt.Errorf("invalid reason: %s", reason)
+	}
+
+	msg, err := s.conversationStorage.GetMessage(messageID)
+	if err != nil || msg.ConversationID != conversationID {
+		return fmt.Errorf("message not found")
+	}
+
+	if err := s.conversationStorage.DeleteMessage(tenantID, messageID, deletedBy, reason); err != nil {
+		return err
+	}
+	log.Printf("[COMPLIANCE] message deleted message=%s conversation=%s by=%s reason=%s", messageID, conversationID, deletedBy, reason)
+
+	// Deleted content must stop matching semantic searches
+	if s.messageIndexer != nil {
+		s.messageIndexer.OnMessageDeleted(tenantID, messageID)
+	}
+
+	// Re-analyze so deleted content stops influencing stored AI metadata
+	if s.analyzer != nil {
+		messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+		if err == nil && len(messages) > 0 {
+			s.analyzeAsync(tenantID, conversationID, messages)
+		}
+	}
+	return nil
+}
+
+// GetConversationIncludingDeleted retrieves a conversation with all messages, including deleted ones
+func (s *IngestionService) GetConversationIncludingDeleted(tenantID, conversationID string) (*models.Conversation, []*models.Message, error) {
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	messages, err := s.conversationStorage.GetMessagesByConversationIncludingDeleted(tenantID, conversationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	s.attachReadReceipts(tenantID, conversationID, messages)
+
+	return conv, messages, nil
+}
+
+// DeleteConversation soft-deletes a conversation. It stays restorable in the database until the
+// retention job purges it.
+func (s *IngestionService) DeleteConversation(tenantID, conversationID, deletedBy string) error {
+	if err := s.conversationStorage.SoftDeleteConversation(tenantID, conversationID, deletedBy); err != nil {
+		return err
+	}
+	log.Printf("[COMPLIANCE] conversation deleted conversation=%s tenant=%s by=%s", conversationID, tenantID, deletedBy)
+	return nil
+}
+
+// ListDeletedConversations lists a tenant's soft-deleted conversations awaiting purge
+func (s *IngestionService) ListDeletedConversations(tenantID string, limit, offset int) ([]*models.Conversation, error) {
+	return s.conversationStorage.ListDeletedConversations(tenantID, limit, offset)
+}
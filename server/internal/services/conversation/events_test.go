@@ -0,0 +1,34 @@
+package conversation
+
+import "testing"
+
+// recordingPublisher records published event types
+type recordingPublisher struct {
+	events   []string
+	payloads []map[string]interface{}
+}
+
+func (p *recordingPublisher) Publish(tenantID, eventType string, payload map[string]interface{}) {
+	p.events = append(p.events, tenantID+"/"+eventType)
+	p.payloads = append(p.payloads, payload)
+}
+
+func TestOnConversationClosedPublishesEvent(t *testing.T) {
+	publisher := &recordingPublisher{}
+	s := NewIngestionService(nil)
+	s.SetEventPublisher(publisher)
+
+	s.OnConversationClosed("tenant-1", "conv-1")
+
+	if len(publisher.events) != 1 || publisher.events[0] != "tenant-1/conversation.closed" {
+		t.Fatalf("events = %v, want tenant-1/conversation.closed", publisher.events)
+	}
+	if publisher.payloads[0]["conversation_id"] != "conv-1" || publisher.payloads[0]["closed_at"] == "" {
+		t.Errorf("payload = %v", publisher.payloads[0])
+	}
+}
+
+func TestOnConversationClosedWithoutPublisher(t *testing.T) {
+	// Publishing is optional
+	NewIngestionService(nil).OnConversationClosed("tenant-1", "conv-1")
+}
@@ -0,0 +1,302 @@
+package conversation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// maxImportRows caps the data rows of a single import
+const maxImportRows = 50000
+
+// importNamespace derives stable IDs for imported conversations and messages, so re-importing
+// the same CSV matches the rows created the first time
+var importNamespace = uuid.MustParse("6f1c9d2e-4b7a-4e0f-9a51-3c8d2b7e1f40")
+
+// requiredImportColumns must be present in the CSV header; agent_email and channel are optional
+var requiredImportColumns = []string{"conversation_id", "customer_email", "sender", "content", "timestamp"}
+
+// ErrInvalidImport is returned when a CSV can't be imported at all
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportStorage stores imported conversations, skipping conversations and messages that exist
+type ImportStorage interface {
+	ImportConversation(tenantID string, conv *models.Conversation, messages []*models.Message) (bool, int, error)
+}
+
+// ImportUserStore resolves the customers and agents named in an import
+type ImportUserStore interface {
+	GetOrCreateCustomerByEmail(tenantID, email string) (*models.User, error)
+	GetUserByEmail(tenantID, email string) (*models.User, error)
+}
+
+// AnalysisScheduler queues AI analysis of a stored conversation
+type AnalysisScheduler interface {
+	ScheduleAnalysis(tenantID, conversationID string)
+}
+
+// ImportError is a CSV row that couldn't be imported. Rows are numbered as in a spreadsheet, the
+// header being row 1.
+type ImportError struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	RowsProcessed        int           `json:"rows_processed"`
+	ConversationsCreated int           `json:"conversations_created"`
+	MessagesCreated      int           `json:"messages_created"`
+	Errors               []ImportError `json:"errors"`
+}
+
+// ConversationImporter imports historical conversations from CSV exports of other systems
+type ConversationImporter struct {
+	storage   ImportStorage
+	users     ImportUserStore
+	scheduler AnalysisScheduler
+	now       func() time.Time
+}
+
+// NewConversationImporter creates a conversation importer
+func NewConversationImporter(storage ImportStorage, users ImportUserStore) *ConversationImporter {
+	return &ConversationImporter{storage: storage, users: users, now: time.Now}
+}
+
+// SetAnalysisScheduler analyzes imported conversations after the import (optional)
+func (i *ConversationImporter) SetAnalysisScheduler(scheduler AnalysisScheduler) {
+	i.scheduler = scheduler
+}
+
+// importRow is a validated CSV row
+type importRow struct {
+	row           int
+	customerEmail string
+	agentEmail    string
+	message       *models.Message
+}
+
+// importedConversation collects the rows of one conversation_id
+type importedConversation struct {
+	externalID string
+	rows       []*importRow
+}
+
+// Import reads a CSV with the columns conversation_id, customer_email, agent_email, sender,
+// content, channel and timestamp (RFC3339), one message per row. Each conversation_id becomes a
+// conversation of the customer, assigned to the agent when agent_email names one, and is stored
+// in its own transaction. Invalid rows are reported in the result and skipped. Conversations and
+// messages get IDs derived from the tenant and the row contents, so importing the same CSV again
+// creates nothing new. Conversations that got new messages are queued for analysis.
+func (i *ConversationImporter) Import(tenantID string, r io.Reader) (ImportResult, error) {
+	result := ImportResult{Errors: []ImportError{}}
+	conversations, err := i.parse(tenantID, r, &result)
+	if err != nil {
+		return result, err
+	}
+
+	for _, conv := range conversations {
+		created, messages, ok := i.importConversation(tenantID, conv, &result)
+		if !ok {
+			continue
+		}
+		if created {
+			result.ConversationsCreated++
+		}
+		result.MessagesCreated += messages
+		if messages > 0 && i.scheduler != nil {
+			i.scheduler.ScheduleAnalysis(tenantID, importConversationID(tenantID, conv.externalID))
+		}
+	}
+
+	log.Printf("[IMPORT] tenant=%s rows=%d conversations_created=%d messages_created=%d errors=%d",
+		tenantID, result.RowsProcessed, result.ConversationsCreated, result.MessagesCreated, len(result.Errors))
+	return result, nil
+}
+
+// parse validates the CSV rows and groups them by conversation_id, in order of first appearance
+func (i *ConversationImporter) parse(tenantID string, r io.Reader, result *ImportResult) ([]*importedConversation, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	columns := make(map[string]int)
+	for index, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = index
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrInvalidImport, name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if index, ok := columns[name]; ok && index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+
+	byID := make(map[string]*importedConversation)
+	var conversations []*importedConversation
+	occurrences := make(map[string]int) // Identical messages in a conversation get distinct IDs
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		result.RowsProcessed++
+		if result.RowsProcessed > maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: row, Reason: err.Error()})
+			continue
+		}
+
+		parsed, err := i.parseRow(record, field)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: row, Reason: err.Error()})
+			continue
+		}
+		parsed.row = row
+		externalID := field(record, "conversation_id")
+		conv, ok := byID[externalID]
+		if !ok {
+			conv = &importedConversation{externalID: externalID}
+			byID[externalID] = conv
+			conversations = append(conversations, conv)
+		}
+		if len(conv.rows) > 0 && !strings.EqualFold(conv.rows[0].customerEmail, parsed.customerEmail) {
+			result.Errors = append(result.Errors, ImportError{Row: row, Reason: fmt.Sprintf(
+				"customer_email %s differs from %s earlier in conversation %s", parsed.customerEmail, conv.rows[0].customerEmail, externalID)})
+			continue
+		}
+
+		msg := parsed.message
+		key := strings.Join([]string{tenantID, externalID, msg.Sender, msg.Timestamp.UTC().Format(time.RFC3339Nano), msg.Content}, "\x00")
+		occurrences[key]++
+		msg.ID = uuid.NewSHA1(importNamespace, []byte(fmt.Sprintf("%s\x00%d", key, occurrences[key]))).String()
+		conv.rows = append(conv.rows, parsed)
+	}
+	return conversations, nil
+}
+
+// parseRow validates one CSV row
+func (i *ConversationImporter) parseRow(record []string, field func([]string, string) string) (*importRow, error) {
+	if field(record, "conversation_id") == "" {
+		return nil, errors.New("conversation_id is required")
+	}
+	customerEmail := strings.ToLower(field(record, "customer_email"))
+	if !strings.Contains(customerEmail, "@") {
+		return nil, errors.New("customer_email must be an email address")
+	}
+	sender := strings.ToLower(field(record, "sender"))
+	if sender != "customer" && sender != "agent" {
+		return nil, fmt.Errorf("sender must be customer or agent, got %q", field(record, "sender"))
+	}
+	content := field(record, "content")
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+	timestamp, err := time.Parse(time.RFC3339, field(record, "timestamp"))
+	if err != nil {
+		return nil, fmt.Errorf("timestamp must be RFC3339, got %q", field(record, "timestamp"))
+	}
+
+	language, confidence := detectLanguage(content)
+	return &importRow{
+		customerEmail: customerEmail,
+		agentEmail:    strings.ToLower(field(record, "agent_email")),
+		message: &models.Message{
+			Sender:             sender,
+			Content:            content,
+			Channel:            normalizeChannel(field(record, "channel")),
+			Language:           language,
+			LanguageConfidence: confidence,
+			Timestamp:          timestamp,
+			CreatedAt:          i.now(),
+		},
+	}, nil
+}
+
+// importConversation stores one conversation and returns whether it was created, the number of
+// messages created and whether it was imported at all. Failures are added to the result against
+// the conversation's first row.
+func (i *ConversationImporter) importConversation(tenantID string, conv *importedConversation, result *ImportResult) (bool, int, bool) {
+	if len(conv.rows) == 0 {
+		return false, 0, false
+	}
+	first := conv.rows[0]
+	fail := func(row int, reason string) {
+		result.Errors = append(result.Errors, ImportError{Row: row, Reason: fmt.Sprintf("conversation %s: %s", conv.externalID, reason)})
+	}
+
+	customer, err := i.users.GetOrCreateCustomerByEmail(tenantID, first.customerEmail)
+	if err != nil {
+		fail(first.row, err.Error())
+		return false, 0, false
+	}
+	if customer.Role != models.RoleCustomer {
+		fail(first.row, fmt.Sprintf("customer_email %s belongs to a %s", first.customerEmail, customer.Role))
+		return false, 0, false
+	}
+
+	var agentID *string
+	for _, row := range conv.rows {
+		if row.agentEmail == "" {
+			continue
+		}
+		agent, err := i.users.GetUserByEmail(tenantID, row.agentEmail)
+		if err != nil || (agent.Role != models.RoleAgent && agent.Role != models.RoleAdmin) {
+			fail(row.row, fmt.Sprintf("agent_email %s is not an agent, conversation left unassigned", row.agentEmail))
+		} else {
+			agentID = &agent.ID
+		}
+		break
+	}
+
+	messages := make([]*models.Message, len(conv.rows))
+	for index, row := range conv.rows {
+		messages[index] = row.message
+	}
+	sort.SliceStable(messages, func(a, b int) bool { return messages[a].Timestamp.Before(messages[b].Timestamp) })
+
+	conversation := &models.Conversation{
+		ID:              importConversationID(tenantID, conv.externalID),
+		TenantID:        tenantID,
+		CustomerID:      &customer.ID,
+		AssignedAgentID: agentID,
+		Status:          StatusActive,
+		CreatedAt:       messages[0].Timestamp,
+		UpdatedAt:       messages[len(messages)-1].Timestamp,
+	}
+	for _, msg := range messages {
+		msg.ConversationID = conversation.ID
+	}
+
+	created, messageCount, err := i.storage.ImportConversation(tenantID, conversation, messages)
+	if err != nil {
+		fail(first.row, err.Error())
+		return false, 0, false
+	}
+	return created, messageCount, true
+}
+
+// importConversationID is the ID of the conversation imported for a tenant's conversation_id
+func importConversationID(tenantID, externalID string) string {
+	return uuid.NewSHA1(importNamespace, []byte(tenantID+"\x00"+externalID)).String()
+}
@@ -0,0 +1,218 @@
+package conversation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// fakeImportStorage keeps imported rows by ID, skipping existing ones like the ON CONFLICT inserts
+type fakeImportStorage struct {
+	conversations map[string]*models.Conversation
+	messages      map[string]*models.Message
+	failFor       string // Conversation ID whose import fails
+}
+
+func newFakeImportStorage() *fakeImportStorage {
+	return &fakeImportStorage{conversations: map[string]*models.Conversation{}, messages: map[string]*models.Message{}}
+}
+
+func (f *fakeImportStorage) ImportConversation(tenantID string, conv *models.Conversation, messages []*models.Message) (bool, int, error) {
+	if conv.ID == f.failFor {
+		return false, 0, errors.New("database unavailable")
+	}
+	_, exists := f.conversations[conv.ID]
+	if !exists {
+		f.conversations[conv.ID] = conv
+	}
+	created := 0
+	for _, msg := range messages {
+		if _, ok := f.messages[msg.ID]; !ok {
+			f.messages[msg.ID] = msg
+			created++
+		}
+	}
+	return !exists, created, nil
+}
+
+// fakeImportUsers knows one agent and creates customers on demand
+type fakeImportUsers struct {
+	users map[string]*models.User
+}
+
+func newFakeImportUsers() *fakeImportUsers {
+	return &fakeImportUsers{users: map[string]*models.User{
+		"agent@example.com": {ID: "agent-1", Email: "agent@example.com", Role: models.RoleAgent},
+		"admin@example.com": {ID: "admin-1", Email: "admin@example.com", Role: models.RoleAdmin},
+	}}
+}
+
+func (f *fakeImportUsers) GetUserByEmail(tenantID, email string) (*models.User, error) {
+	if user, ok := f.users[email]; ok {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeImportUsers) GetOrCreateCustomerByEmail(tenantID, email string) (*models.User, error) {
+	if user, ok := f.users[email]; ok {
+		return user, nil
+	}
+	user := &models.User{ID: "cust-" + email, Email: email, Role: models.RoleCustomer}
+	f.users[email] = user
+	return user, nil
+}
+
+// fakeAnalysisScheduler records the conversations queued for analysis
+type fakeAnalysisScheduler struct{ scheduled []string }
+
+func (f *fakeAnalysisScheduler) ScheduleAnalysis(tenantID, conversationID string) {
+	f.scheduled = append(f.scheduled, conversationID)
+}
+
+const importCSV = `conversation_id,customer_email,agent_email,sender,content,channel,timestamp
+legacy-1,Priya@Example.com,agent@example.com,customer,Hi, is the annual plan discounted?,whatsapp,2024-03-01T10:00:00Z
+legacy-1,priya@example.com,,agent,"Yes, 10% off annually",WA,2024-03-01T10:05:00Z
+legacy-2,raj@example.com,,customer,Thanks,,2024-03-02T09:00:00Z
+legacy-2,raj@example.com,,customer,Thanks,,2024-03-02T09:00:00Z
+`
+
+func newTestImporter() (*ConversationImporter, *fakeImportStorage, *fakeAnalysisScheduler) {
+	storage := newFakeImportStorage()
+	scheduler := &fakeAnalysisScheduler{}
+	importer := NewConversationImporter(storage, newFakeImportUsers())
+	importer.SetAnalysisScheduler(scheduler)
+	return importer, storage, scheduler
+}
+
+func TestImportCreatesConversationsAndMessages(t *testing.T) {
+	importer, storage, scheduler := newTestImporter()
+
+	// The unquoted comma in the first row's content splits it into an extra field
+	result, err := importer.Import("tenant-1", strings.NewReader(importCSV))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.RowsProcessed != 4 || result.ConversationsCreated != 2 || result.MessagesCreated != 3 {
+		t.Fatalf("result = %+v, want 4 rows, 2 conversations and 3 messages", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 2 {
+		t.Fatalf("errors = %+v, want the malformed row 2", result.Errors)
+	}
+
+	legacy1 := storage.conversations[importConversationID("tenant-1", "legacy-1")]
+	if legacy1 == nil || *legacy1.CustomerID != "cust-priya@example.com" || legacy1.AssignedAgentID != nil {
+		t.Errorf("legacy-1 = %+v, want priya's conversation, unassigned since the agent row was malformed", legacy1)
+	}
+	legacy2 := storage.conversations[importConversationID("tenant-1", "legacy-2")]
+	if legacy2 == nil || !legacy2.CreatedAt.Equal(legacy2.UpdatedAt) || legacy2.Status != StatusActive {
+		t.Errorf("legacy-2 = %+v, want an active conversation spanning its messages", legacy2)
+	}
+	for _, msg := range storage.messages {
+		if msg.Sender == "agent" && msg.Channel != "whatsapp" {
+			t.Errorf("channel = %q, want WA normalized to whatsapp", msg.Channel)
+		}
+		if msg.ConversationID == legacy2.ID && msg.Channel != "web" {
+			t.Errorf("channel = %q, want web by default", msg.Channel)
+		}
+	}
+	if len(scheduler.scheduled) != 2 {
+		t.Errorf("scheduled analyses = %v, want both conversations", scheduler.scheduled)
+	}
+}
+
+func TestImportIsIdempotent(t *testing.T) {
+	importer, storage, scheduler := newTestImporter()
+	csv := strings.Replace(importCSV, "Hi, is the annual plan discounted?", `"Hi, is the annual plan discounted?"`, 1)
+
+	first, err := importer.Import("tenant-1", strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if first.ConversationsCreated != 2 || first.MessagesCreated != 4 || len(first.Errors) != 0 {
+		t.Fatalf("first import = %+v, want 2 conversations and 4 messages", first)
+	}
+	if legacy1 := storage.conversations[importConversationID("tenant-1", "legacy-1")]; legacy1.AssignedAgentID == nil || *legacy1.AssignedAgentID != "agent-1" {
+		t.Errorf("legacy-1 agent = %v, want agent-1", legacy1.AssignedAgentID)
+	}
+
+	second, err := importer.Import("tenant-1", strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if second.RowsProcessed != 4 || second.ConversationsCreated != 0 || second.MessagesCreated != 0 {
+		t.Errorf("second import = %+v, want nothing created", second)
+	}
+	if len(storage.conversations) != 2 || len(storage.messages) != 4 {
+		t.Errorf("stored %d conversations and %d messages, want 2 and 4", len(storage.conversations), len(storage.messages))
+	}
+	if len(scheduler.scheduled) != 2 {
+		t.Errorf("scheduled analyses = %v, want none for the second import", scheduler.scheduled)
+	}
+
+	// New rows for an existing conversation are added to it
+	more := csv + "legacy-2,raj@example.com,,agent,Anything else?,,2024-03-02T09:10:00Z\n"
+	third, err := importer.Import("tenant-1", strings.NewReader(more))
+	if err != nil {
+		t.Fatalf("third Import: %v", err)
+	}
+	if third.ConversationsCreated != 0 || third.MessagesCreated != 1 {
+		t.Errorf("third import = %+v, want only the new message", third)
+	}
+
+	// The same conversation_id in another tenant is a different conversation
+	if other, _ := importer.Import("tenant-2", strings.NewReader(csv)); other.ConversationsCreated != 2 {
+		t.Errorf("import for another tenant = %+v, want its own conversations", other)
+	}
+}
+
+func TestImportReportsInvalidRows(t *testing.T) {
+	importer, storage, _ := newTestImporter()
+	storage.failFor = importConversationID("tenant-1", "broken")
+
+	csv := `Timestamp,Sender,Content,Conversation_ID,Customer_Email,Agent_Email
+not-a-time,customer,Hello,c1,a@example.com,
+2024-03-01T10:00:00Z,bot,Hello,c1,a@example.com,
+2024-03-01T10:00:00Z,customer,,c1,a@example.com,
+2024-03-01T10:00:00Z,customer,Hello,,a@example.com,
+2024-03-01T10:00:00Z,customer,Hello,c1,not-an-email,
+2024-03-01T10:00:00Z,customer,Hello,c1,a@example.com,nobody@example.com
+2024-03-01T10:01:00Z,customer,Hello again,c1,b@example.com,
+2024-03-01T10:00:00Z,customer,Hello,c2,agent@example.com,
+2024-03-01T10:00:00Z,customer,Hello,broken,c@example.com,
+`
+	result, err := importer.Import("tenant-1", strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	wantRows := []int{2, 3, 4, 5, 6, 8, 7, 9, 10}
+	if len(result.Errors) != len(wantRows) {
+		t.Fatalf("errors = %+v, want rows %v", result.Errors, wantRows)
+	}
+	for i, row := range wantRows {
+		if result.Errors[i].Row != row {
+			t.Errorf("error %d = %+v, want row %d", i, result.Errors[i], row)
+		}
+	}
+	// c1 is still imported, unassigned, from its one valid row
+	if result.ConversationsCreated != 1 || result.MessagesCreated != 1 {
+		t.Errorf("result = %+v, want only c1 with one message", result)
+	}
+	if c1 := storage.conversations[importConversationID("tenant-1", "c1")]; c1 == nil || c1.AssignedAgentID != nil {
+		t.Errorf("c1 = %+v, want it unassigned", c1)
+	}
+}
+
+func TestImportRejectsInvalidFiles(t *testing.T) {
+	importer, _, _ := newTestImporter()
+	for name, csv := range map[string]string{
+		"empty":          "",
+		"missing column": "conversation_id,customer_email,sender,content\n",
+	} {
+		if _, err := importer.Import("tenant-1", strings.NewReader(csv)); !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("%s: err = %v, want ErrInvalidImport", name, err)
+		}
+	}
+}
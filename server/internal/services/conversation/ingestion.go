@@ -1,16 +1,23 @@
 package conversation
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/abadojack/whatlanggo"
 	"github.com/google/uuid"
 
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/metrics"
 	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/moderation"
+	"ai-conversation-platform/internal/rules"
 	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
 )
 
 // NormalizedMessage represents a normalized message
@@ -21,11 +28,20 @@ type NormalizedMessage struct {
 	Timestamp      time.Time
 	Channel        string
 	Language       string
+	LanguageConfidence float32 // Detection confidence (0-1)
+	IsAutoReply    bool // Sent by auto-reply rather than an agent
+	SuggestionConfidence *float64 // Confidence of the suggestion an auto-reply was sent from
 }
 
 // AnalyzerInterface defines the interface for AI analysis
 type AnalyzerInterface interface {
-	AnalyzeConversationAsync(tenantID, conversationID string, messages []*models.Message)
+	AnalyzeConversation(ctx context.Context, tenantID, conversationID string, messages []*models.Message) error
+}
+
+// LanguageConfirmer identifies the language of messages local detection is unsure about, using
+// the AI model (see ai.Analyzer.ConfirmLanguage)
+type LanguageConfirmer interface {
+	ConfirmLanguage(ctx context.Context, tenantID, text string) (string, float32, error)
 }
 
 // AutoReplyInterface defines the interface for auto-reply processing
@@ -33,23 +49,119 @@ type AutoReplyInterface interface {
 	ProcessAutoReply(tenantID, conversationID string) error
 }
 
+// MessageIndexer keeps the semantic message search index up to date (see ai.EmbeddingQueue).
+// Both calls must return quickly; indexing happens in the background.
+type MessageIndexer interface {
+	OnMessageStored(tenantID string, message *models.Message)
+	OnMessageDeleted(tenantID, messageID string)
+}
+
+// MessageModerator screens messages before they are stored (see moderation.Service)
+type MessageModerator interface {
+	ModerateMessage(tenantID string, message *models.Message) (moderation.ModerationResult, error)
+}
+
+// EventPublisher delivers conversation events to external subscribers (e.g. webhooks)
+type EventPublisher interface {
+	Publish(tenantID, eventType string, payload map[string]interface{})
+}
+
+// Conversation lifecycle events
+const (
+	EventMessageCreated      = "message.created"
+	EventConversationCreated = "conversation.created"
+	EventConversationClosed  = "conversation.closed"
+)
+
+// Language detection
+const (
+	unknownLanguage = "unknown"
+	// Trigram detection is unreliable on shorter messages, so they are left unknown
+	minLanguageDetectionLength = 20
+	// Detections below this confidence are confirmed with the AI model when one is configured
+	languageConfidenceThreshold = 0.7
+	languageConfirmationTimeout = 5 * time.Second
+)
+
 // IngestionService handles conversation ingestion
 type IngestionService struct {
 	conversationStorage *postgres.ConversationStorage
 	analyzer            AnalyzerInterface
+	analysisPool        *worker.Pool
 	autoReplyService    AutoReplyInterface
+	eventPublisher      EventPublisher
+	ruleEngine          *rules.RuleEngine
+	ruleLoader          ai.RuleLoader
+	auditStorage        *postgres.AuditStorage
+	freshnessScorer     *ai.ContextFreshnessScorer
+	watchlistStorage    *postgres.WatchlistStorage
+	memoryStorage       *postgres.MemoryStorage
+	broadcaster         *MessageBroadcaster
+	dashboardUpdates    *DashboardUpdates
+	slaTracker          *SLATracker
+	messageIndexer      MessageIndexer
+	languageConfirmer   LanguageConfirmer
+	routingEngine       *RoutingEngine
+	messageModerator    MessageModerator
 }
 
 // NewIngestionService creates a new ingestion service
 func NewIngestionService(conversationStorage *postgres.ConversationStorage) *IngestionService {
 	return &IngestionService{
 		conversationStorage: conversationStorage,
+		freshnessScorer:     ai.NewContextFreshnessScorer(),
 	}
 }
 
-// SetAnalyzer sets the AI analyzer (optional)
-func (s *IngestionService) SetAnalyzer(analyzer AnalyzerInterface) {
+// SetAnalyzer sets the AI analyzer and the worker pool analyses run on (optional)
+func (s *IngestionService) SetAnalyzer(analyzer AnalyzerInterface, pool *worker.Pool) {
 	s.analyzer = analyzer
+	s.analysisPool = pool
+}
+
+// analyzeAsync queues an analysis of the conversation on the worker pool. Analyses are dropped
+// when the pool is full rather than holding up ingestion; the next message queues a new one.
+func (s *IngestionService) analyzeAsync(tenantID, conversationID string, messages []*models.Message) {
+	if s.analyzer == nil || s.analysisPool == nil {
+		return
+	}
+	job := worker.Job{
+		Name: "analysis:" + conversationID,
+		Run: func(ctx context.Context) error {
+			if err := s.analyzer.AnalyzeConversation(ctx, tenantID, conversationID, messages); err != nil {
+				return err
+			}
+			s.routeConversation(tenantID, conversationID)
+			return nil
+		},
+	}
+	if err := s.analysisPool.Enqueue(job); err != nil {
+		log.Printf("[INGESTION] analysis not queued conversation=%s pending=%d error=%v", conversationID, s.analysisPool.Len(), err)
+	}
+}
+
+// analyzeIfStale queues analysis unless the new message can't change the stored metadata, in which
+// case the skip is counted in analysis_skipped_total
+func (s *IngestionService) analyzeIfStale(tenantID string, message *models.Message, metadata *models.ConversationMetadata, messages []*models.Message) {
+	if s.freshnessScorer.NeedsReanalysis(message, metadata, messages) {
+		s.analyzeAsync(tenantID, message.ConversationID, messages)
+		return
+	}
+	metrics.RecordAnalysisSkipped()
+	log.Printf("[INGESTION] analysis skipped, metadata still fresh conversation=%s message_id=%s", message.ConversationID, message.ID)
+}
+
+// ScheduleAnalysis queues AI analysis of a conversation with its stored messages, e.g. after an import
+func (s *IngestionService) ScheduleAnalysis(tenantID, conversationID string) {
+	if s.analyzer == nil || s.analysisPool == nil {
+		return
+	}
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[INGESTION] failed to load messages for analysis conversation=%s: %v", conversationID, err)
+		return
+	}
+	s.analyzeAsync(tenantID, conversationID, messages)
 }
 
 // SetAutoReplyService sets the auto-reply service (optional)
@@ -57,6 +169,67 @@ func (s *IngestionService) SetAutoReplyService(autoReplyService AutoReplyInterfa
 	s.autoReplyService = autoReplyService
 }
 
+// SetEventPublisher sets the event publisher (optional)
+func (s *IngestionService) SetEventPublisher(eventPublisher EventPublisher) {
+	s.eventPublisher = eventPublisher
+}
+
+// SetContentModeration enables flagging of unsafe inbound messages (optional)
+func (s *IngestionService) SetContentModeration(ruleEngine *rules.RuleEngine, ruleLoader ai.RuleLoader) {
+	s.ruleEngine = ruleEngine
+	s.ruleLoader = ruleLoader
+}
+
+// SetMessageModerator screens customer messages for PII and the tenant's moderation lists
+// before they are stored; blocked messages are rejected (optional)
+func (s *IngestionService) SetMessageModerator(moderator MessageModerator) {
+	s.messageModerator = moderator
+}
+
+// SetMessageBroadcaster streams stored messages to live subscribers (optional)
+func (s *IngestionService) SetMessageBroadcaster(broadcaster *MessageBroadcaster) {
+	s.broadcaster = broadcaster
+}
+
+// SetDashboardUpdates signals live dashboards when a message is stored (optional)
+func (s *IngestionService) SetDashboardUpdates(updates *DashboardUpdates) {
+	s.dashboardUpdates = updates
+}
+
+// SetSLATracker enables agent response time tracking (optional)
+func (s *IngestionService) SetSLATracker(tracker *SLATracker) {
+	s.slaTracker = tracker
+}
+
+// SetMessageIndexer indexes stored messages for semantic search (optional)
+func (s *IngestionService) SetMessageIndexer(indexer MessageIndexer) {
+	s.messageIndexer = indexer
+}
+
+// SetRoutingEngine assigns unassigned conversations with the tenant's routing rules after each
+// analysis (optional)
+func (s *IngestionService) SetRoutingEngine(engine *RoutingEngine) {
+	s.routingEngine = engine
+}
+
+// SetLanguageConfirmer confirms low-confidence language detections with the AI model (optional)
+func (s *IngestionService) SetLanguageConfirmer(confirmer LanguageConfirmer) {
+	s.languageConfirmer = confirmer
+}
+
+// SetAuditStorage sets the audit log used to record flagged messages (optional)
+func (s *IngestionService) SetAuditStorage(auditStorage *postgres.AuditStorage) {
+	s.auditStorage = auditStorage
+}
+
+// publishEvent publishes an event if a publisher is configured
+func (s *IngestionService) publishEvent(tenantID, eventType string, payload map[string]interface{}) {
+	if s.eventPublisher == nil {
+		return
+	}
+	s.eventPublisher.Publish(tenantID, eventType, payload)
+}
+
 // NormalizeMessage normalizes an incoming message into standard schema
 func NormalizeMessage(rawMessage string, sender string, channel string, timestamp time.Time, conversationID string) (*NormalizedMessage, error) {
 	// Validate sender
@@ -69,7 +242,7 @@ func NormalizeMessage(rawMessage string, sender string, channel string, timestam
 	channel = normalizeChannel(channel)
 
 	// Auto-detect language
-	language := detectLanguage(rawMessage)
+	language, languageConfidence := detectLanguage(rawMessage)
 
 	// Use provided timestamp or current time
 	if timestamp.IsZero() {
@@ -83,6 +256,7 @@ func NormalizeMessage(rawMessage string, sender string, channel string, timestam
 		Timestamp:      timestamp,
 		Channel:        channel,
 		Language:       language,
+		LanguageConfidence: languageConfidence,
 	}, nil
 }
 
@@ -105,21 +279,48 @@ func normalizeChannel(channel string) string {
 	}
 }
 
-// detectLanguage auto-detects message language
-func detectLanguage(text string) string {
-	if strings.TrimSpace(text) == "" {
-		return "unknown"
+// detectLanguage auto-detects message language and returns it with the detection confidence.
+// Messages shorter than minLanguageDetectionLength and detections below
+// languageConfidenceThreshold are "unknown"; the confidence is still returned for the latter.
+func detectLanguage(text string) (string, float32) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) < minLanguageDetectionLength {
+		return unknownLanguage, 0
 	}
 
 	info := whatlanggo.Detect(text)
-	if info.IsReliable() {
-		return info.Lang.Iso6391()
+	if info.Confidence < languageConfidenceThreshold {
+		return unknownLanguage, float32(info.Confidence)
 	}
-	return "unknown"
+	return info.Lang.Iso6391(), float32(info.Confidence)
+}
+
+// confirmLanguage asks the language confirmer about customer messages long enough to detect but
+// detected with low confidence, such as Hindi typed in Latin letters. The local result is kept when no
+// confirmer is set or the confirmation fails.
+func (s *IngestionService) confirmLanguage(tenantID string, normalized *NormalizedMessage) {
+	if s.languageConfirmer == nil || normalized.Sender != "customer" || normalized.Language != unknownLanguage ||
+		utf8.RuneCountInString(normalized.Message) < minLanguageDetectionLength {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), languageConfirmationTimeout)
+	defer cancel()
+	language, confidence, err := s.languageConfirmer.ConfirmLanguage(ctx, tenantID, normalized.Message)
+	if err != nil {
+		if !ai.IsCircuitOpen(err) {
+			log.Printf("[INGESTION] language confirmation failed conversation=%s error=%v", normalized.ConversationID, err)
+		}
+		return
+	}
+	normalized.Language = language
+	normalized.LanguageConfidence = confidence
 }
 
 // IngestMessage ingests a normalized message into the system and returns the message ID
 func (s *IngestionService) IngestMessage(tenantID string, normalized *NormalizedMessage) (string, error) {
+	s.confirmLanguage(tenantID, normalized)
+
 	// Create message ID
 	messageID := uuid.New().String()
 
@@ -131,20 +332,55 @@ func (s *IngestionService) IngestMessage(tenantID string, normalized *Normalized
 		Content:        normalized.Message,
 		Channel:        normalized.Channel,
 		Language:       normalized.Language,
+		LanguageConfidence: normalized.LanguageConfidence,
 		Timestamp:      normalized.Timestamp,
 		CreatedAt:      time.Now(),
+		IsAutoReply:    normalized.IsAutoReply,
+		SuggestionConfidence: normalized.SuggestionConfidence,
+	}
+
+	if err := s.screenMessage(tenantID, message); err != nil {
+		return "", err
 	}
 
 	// Store message (immutable)
-	if err := s.conversationStorage.CreateMessage(message); err != nil {
+	if err := s.conversationStorage.CreateMessage(tenantID, message); err != nil {
 		return "", fmt.Errorf("failed to store message: %w", err)
 	}
 
-	// Trigger async AI analysis if analyzer is set
+	// Flag unsafe content; messages are immutable so they are still stored
+	s.moderateMessage(tenantID, message)
+
+	if s.broadcaster != nil {
+		s.broadcaster.Publish(tenantID, message)
+	}
+	if s.dashboardUpdates != nil {
+		s.dashboardUpdates.Publish(tenantID)
+	}
+
+	s.trackSLA(tenantID, message)
+
+	if s.messageIndexer != nil {
+		s.messageIndexer.OnMessageStored(tenantID, message)
+	}
+
+	s.publishEvent(tenantID, EventMessageCreated, map[string]interface{}{
+		"message_id":      message.ID,
+		"conversation_id": message.ConversationID,
+		"sender":          message.Sender,
+		"content":         message.Content,
+		"channel":         message.Channel,
+		"language":        message.Language,
+		"is_auto_reply":   message.IsAutoReply,
+		"timestamp":       message.Timestamp.UTC().Format(time.RFC3339),
+	})
+
+	// Trigger async AI analysis if analyzer is set and the message can change the result
 	if s.analyzer != nil {
 		messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, normalized.ConversationID)
 		if err == nil {
-			s.analyzer.AnalyzeConversationAsync(tenantID, normalized.ConversationID, messages)
+			metadata, _ := s.conversationStorage.GetConversationMetadata(tenantID, normalized.ConversationID)
+			s.analyzeIfStale(tenantID, message, metadata, messages)
 		}
 	}
 
@@ -161,6 +397,79 @@ func (s *IngestionService) IngestMessage(tenantID string, normalized *Normalized
 	return messageID, nil
 }
 
+// trackSLA starts a response deadline for customer messages and closes open ones when an agent
+// replies. Auto-replies don't count as an agent response.
+func (s *IngestionService) trackSLA(tenantID string, message *models.Message) {
+	if s.slaTracker == nil {
+		return
+	}
+	var err error
+	switch {
+	case message.Sender == "customer":
+		err = s.slaTracker.RecordCustomerMessage(tenantID, message.ConversationID, message.ID)
+	case !message.IsAutoReply:
+		err = s.slaTracker.RecordAgentResponse(tenantID, message.ConversationID)
+	}
+	if err != nil {
+		log.Printf("[INGESTION] sla tracking failed conversation=%s message_id=%s error=%v", message.ConversationID, message.ID, err)
+	}
+}
+
+// screenMessage runs a customer message past the message moderator before it is stored and
+// returns moderation.ErrContentModerated when it is blocked. Moderator errors let the message through.
+func (s *IngestionService) screenMessage(tenantID string, message *models.Message) error {
+	if s.messageModerator == nil || message.Sender != "customer" {
+		return nil
+	}
+	result, err := s.messageModerator.ModerateMessage(tenantID, message)
+	if err != nil {
+		log.Printf("[INGESTION] message moderation failed conversation=%s error=%v", message.ConversationID, err)
+		return nil
+	}
+	if result.Action == moderation.ActionBlock {
+		return moderation.ErrContentModerated
+	}
+	return nil
+}
+
+// moderateMessage flags a stored message that fails content moderation. Content is never logged.
+func (s *IngestionService) moderateMessage(tenantID string, message *models.Message) {
+	if s.ruleEngine == nil {
+		return
+	}
+
+	result := ai.NewTenantContentModerator(s.ruleEngine, s.ruleLoader, tenantID).Moderate(message.Content)
+	if result.IsSafe {
+		return
+	}
+
+	log.Printf("[INGESTION] WARN message flagged by content moderation message_id=%s conversation=%s categories=%s",
+		message.ID, message.ConversationID, strings.Join(result.BlockedCategories, ","))
+
+	if s.auditStorage != nil {
+		entry := &postgres.AuditLog{
+			TenantID:     tenantID,
+			Action:       "message.flagged",
+			ResourceType: "message",
+			ResourceID:   &message.ID,
+			NewValue: postgres.AuditValue(map[string]interface{}{
+				"conversation_id": message.ConversationID,
+				"sender":          message.Sender,
+				"categories":      result.BlockedCategories,
+			}),
+		}
+		if err := s.auditStorage.Record(entry); err != nil {
+			log.Printf("[INGESTION] failed to record moderation audit log: %v", err)
+		}
+	}
+
+	s.publishEvent(tenantID, "message.flagged", map[string]interface{}{
+		"message_id":      message.ID,
+		"conversation_id": message.ConversationID,
+		"categories":      result.BlockedCategories,
+	})
+}
+
 // CreateConversation creates a new conversation
 // If customerID is provided, it will check for existing active conversation first
 func (s *IngestionService) CreateConversation(tenantID string, customerID *string, productID *string) (*models.Conversation, error) {
@@ -188,14 +497,33 @@ func (s *IngestionService) CreateConversation(tenantID string, customerID *strin
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
+	// Carry over a language an agent previously chose for this customer
+	conversation.OverrideLanguage = s.inheritedLanguageOverride(tenantID, customerID)
 
 	if err := s.conversationStorage.CreateConversation(tenantID, conversation); err != nil {
 		return nil, fmt.Errorf("failed to create conversation: %w", err)
 	}
 
+	s.publishEvent(tenantID, EventConversationCreated, map[string]interface{}{
+		"conversation_id": conversation.ID,
+		"customer_id":     conversation.CustomerID,
+		"product_id":      conversation.ProductID,
+		"status":          conversation.Status,
+		"created_at":      conversation.CreatedAt.UTC().Format(time.RFC3339),
+	})
+
 	return conversation, nil
 }
 
+// OnConversationClosed publishes conversation.closed; registered as a close listener on the
+// conversation storage so every way of closing a conversation is reported
+func (s *IngestionService) OnConversationClosed(tenantID, conversationID string) {
+	s.publishEvent(tenantID, EventConversationClosed, map[string]interface{}{
+		"conversation_id": conversationID,
+		"closed_at":       time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // GetConversation retrieves a conversation with messages
 func (s *IngestionService) GetConversation(tenantID, conversationID string) (*models.Conversation, []*models.Message, error) {
 	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
@@ -207,16 +535,55 @@ func (s *IngestionService) GetConversation(tenantID, conversationID string) (*mo
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get messages: %w", err)
 	}
+	s.attachReadReceipts(tenantID, conversationID, messages)
 
 	return conv, messages, nil
 }
 
-// ListConversations lists conversations for a tenant
-// If customerID is provided, only conversations for that customer are returned (for customer role)
-func (s *IngestionService) ListConversations(tenantID string, customerID *string, limit, offset int) ([]*models.Conversation, error) {
-	conversations, err := s.conversationStorage.ListConversations(tenantID, customerID, limit, offset)
+// GetConversationMetadata returns the conversation's analysis metadata, or nil if it has not been analyzed yet
+func (s *IngestionService) GetConversationMetadata(tenantID, conversationID string) (*models.ConversationMetadata, error) {
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		if err.Error() == "metadata not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// PatchConversationMetadata applies a partial metadata update and returns the result
+func (s *IngestionService) PatchConversationMetadata(tenantID, conversationID string, patch postgres.MetadataPatch) (*models.ConversationMetadata, error) {
+	if _, err := s.conversationStorage.GetConversation(tenantID, conversationID); err != nil {
+		return nil, fmt.Errorf("conversation not found")
+	}
+	if err := s.conversationStorage.PatchConversationMetadata(tenantID, conversationID, patch); err != nil {
+		return nil, err
+	}
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// ListConversations lists a page of conversations for a tenant and returns the cursor of the next
+// page (empty on the last page). An empty cursor starts at the most recently updated conversation.
+// filters narrows the list (e.g. to the customer's own conversations for the customer role); nil
+// lists every conversation of the tenant.
+func (s *IngestionService) ListConversations(tenantID string, filters *postgres.ConversationFilters, limit int, cursor string) ([]*models.Conversation, string, error) {
+	conversations, nextCursor, err := s.conversationStorage.ListConversations(tenantID, filters, limit, cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list conversations: %w", err)
+	}
+	return conversations, nextCursor, nil
+}
+
+// SearchConversations finds a tenant's conversations by message content, best matches first
+func (s *IngestionService) SearchConversations(tenantID, query string, limit, offset int) ([]*models.Conversation, error) {
+	conversations, err := s.conversationStorage.SearchConversations(tenantID, query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list conversations: %w", err)
+		return nil, fmt.Errorf("failed to search conversations: %w", err)
 	}
 	return conversations, nil
 }
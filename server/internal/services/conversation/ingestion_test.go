@@ -0,0 +1,67 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/moderation"
+)
+
+type fakeMessageModerator struct {
+	result moderation.ModerationResult
+	err    error
+	calls  int
+}
+
+func (f *fakeMessageModerator) ModerateMessage(tenantID string, message *models.Message) (moderation.ModerationResult, error) {
+	f.calls++
+	return f.result, f.err
+}
+
+func TestIngestMessageRejectsBlockedMessages(t *testing.T) {
+	moderator := &fakeMessageModerator{result: moderation.ModerationResult{Action: moderation.ActionBlock}}
+	// No conversation storage: a blocked message must be rejected before it is stored
+	s := NewIngestionService(nil)
+	s.SetMessageModerator(moderator)
+
+	_, err := s.IngestMessage("tenant-1", &NormalizedMessage{ConversationID: "c1", Sender: "customer", Message: "crypto giveaway", Timestamp: time.Now()})
+	if !errors.Is(err, moderation.ErrContentModerated) {
+		t.Fatalf("err = %v, want ErrContentModerated", err)
+	}
+}
+
+func TestScreenMessage(t *testing.T) {
+	customer := &models.Message{ID: "m1", ConversationID: "c1", Sender: "customer", Content: "mail me at a@b.io"}
+	tests := []struct {
+		name      string
+		moderator *fakeMessageModerator
+		message   *models.Message
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "flagged messages are stored", moderator: &fakeMessageModerator{result: moderation.ModerationResult{Action: moderation.ActionFlag}}, message: customer, wantCalls: 1},
+		{name: "blocked", moderator: &fakeMessageModerator{result: moderation.ModerationResult{Action: moderation.ActionBlock}}, message: customer, wantErr: true, wantCalls: 1},
+		{name: "moderator error lets the message through", moderator: &fakeMessageModerator{err: errors.New("timeout")}, message: customer, wantCalls: 1},
+		{name: "agent messages aren't moderated", moderator: &fakeMessageModerator{result: moderation.ModerationResult{Action: moderation.ActionBlock}}, message: &models.Message{Sender: "agent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewIngestionService(nil)
+			s.SetMessageModerator(tt.moderator)
+			if err := s.screenMessage("tenant-1", tt.message); (err != nil) != tt.wantErr {
+				t.Errorf("screenMessage err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.moderator.calls != tt.wantCalls {
+				t.Errorf("moderator called %d times, want %d", tt.moderator.calls, tt.wantCalls)
+			}
+		})
+	}
+
+	// Moderation is optional
+	if err := NewIngestionService(nil).screenMessage("tenant-1", customer); err != nil {
+		t.Errorf("screenMessage without a moderator = %v, want nil", err)
+	}
+}
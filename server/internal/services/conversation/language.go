@@ -0,0 +1,88 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// SetMemoryStorage enables persisting language overrides to customer memory (optional)
+func (s *IngestionService) SetMemoryStorage(memoryStorage *postgres.MemoryStorage) {
+	s.memoryStorage = memoryStorage
+}
+
+// SetLanguageOverride pins a conversation to a language chosen by an agent. The override
+// is also saved as the customer's preferred language so future conversations inherit it.
+func (s *IngestionService) SetLanguageOverride(tenantID, conversationID, language string) (*models.Conversation, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if !ai.IsValidLanguageCode(language) {
+		return nil, fmt.Errorf("invalid language code: %q", language)
+	}
+
+	if err := s.conversationStorage.SetOverrideLanguage(tenantID, conversationID, language); err != nil {
+		return nil, err
+	}
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	log.Printf("[LANGUAGE] override set tenant=%s conversation=%s language=%s", tenantID, conversationID, language)
+
+	if conv.CustomerID != nil && *conv.CustomerID != "" {
+		if err := s.saveLanguagePreference(tenantID, *conv.CustomerID, language); err != nil {
+			log.Printf("[LANGUAGE] failed to persist override to customer memory tenant=%s customer=%s: %v", tenantID, *conv.CustomerID, err)
+		}
+	}
+	return conv, nil
+}
+
+// saveLanguagePreference records an agent-chosen language in customer memory, creating it if needed
+func (s *IngestionService) saveLanguagePreference(tenantID, customerID, language string) error {
+	if s.memoryStorage == nil {
+		return nil
+	}
+
+	memory, err := s.memoryStorage.GetMemory(tenantID, customerID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		now := time.Now()
+		return s.memoryStorage.CreateMemory(tenantID, &models.CustomerMemory{
+			ID:                uuid.New().String(),
+			TenantID:          tenantID,
+			CustomerID:        customerID,
+			PreferredLanguage: language,
+			LanguageOverride:  true,
+			ProductInterests:  []string{},
+			PastObjections:    []string{},
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+
+	memory.PreferredLanguage = language
+	memory.LanguageOverride = true
+	memory.UpdatedAt = time.Now()
+	return s.memoryStorage.UpdateMemory(tenantID, memory)
+}
+
+// inheritedLanguageOverride returns the customer's agent-set language, if any, for a new conversation
+func (s *IngestionService) inheritedLanguageOverride(tenantID string, customerID *string) *string {
+	if s.memoryStorage == nil || customerID == nil || *customerID == "" {
+		return nil
+	}
+	memory, err := s.memoryStorage.GetMemory(tenantID, *customerID)
+	if err != nil || !memory.LanguageOverride || memory.PreferredLanguage == "" {
+		return nil
+	}
+	language := memory.PreferredLanguage
+	return &language
+}
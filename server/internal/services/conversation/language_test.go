@@ -0,0 +1,97 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeLanguageConfirmer answers with language, recording the text it was asked about
+type fakeLanguageConfirmer struct {
+	language string
+	err      error
+	tenantID string
+	texts    []string
+}
+
+func (f *fakeLanguageConfirmer) ConfirmLanguage(ctx context.Context, tenantID, text string) (string, float32, error) {
+	f.tenantID = tenantID
+	f.texts = append(f.texts, text)
+	if f.err != nil {
+		return "", 0, f.err
+	}
+	return f.language, 0.9, nil
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantLanguage string
+		wantConfirm  bool // low confidence, worth asking the model about
+	}{
+		{name: "empty", text: "   ", wantLanguage: "unknown"},
+		{name: "short Hindi", text: "मैं कल आऊंगा", wantLanguage: "unknown"},
+		{name: "Hindi", text: "मुझे इसकी कीमत जाननी है", wantLanguage: "hi"},
+		{name: "English", text: "Can you tell me the price of the annual plan?", wantLanguage: "en"},
+		// Romanized Hindi is misread as another language with low confidence
+		{name: "romanized Hindi", text: "mujhe ye product chahiye jaldi", wantLanguage: "unknown", wantConfirm: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, confidence := detectLanguage(tt.text)
+			if language != tt.wantLanguage {
+				t.Errorf("language = %s, want %s", language, tt.wantLanguage)
+			}
+			if tt.wantLanguage != "unknown" && confidence < languageConfidenceThreshold {
+				t.Errorf("confidence = %.2f, want at least %.2f", confidence, languageConfidenceThreshold)
+			}
+			if tt.wantConfirm && (confidence == 0 || confidence >= languageConfidenceThreshold) {
+				t.Errorf("confidence = %.2f, want a low-confidence detection", confidence)
+			}
+		})
+	}
+}
+
+func TestConfirmLanguageUsesModelForLowConfidence(t *testing.T) {
+	normalize := func(text, sender string) *NormalizedMessage {
+		normalized, err := NormalizeMessage(text, sender, "web", time.Now(), "c1")
+		if err != nil {
+			t.Fatalf("NormalizeMessage: %v", err)
+		}
+		return normalized
+	}
+	confirmer := &fakeLanguageConfirmer{language: "hi"}
+	s := &IngestionService{}
+	s.SetLanguageConfirmer(confirmer)
+
+	romanized := normalize("mujhe ye product chahiye jaldi", "customer")
+	s.confirmLanguage("tenant-1", romanized)
+	if romanized.Language != "hi" || romanized.LanguageConfidence != 0.9 {
+		t.Errorf("romanized Hindi = %s %.2f, want the model's hi 0.90", romanized.Language, romanized.LanguageConfidence)
+	}
+	if len(confirmer.texts) != 1 || confirmer.texts[0] != "mujhe ye product chahiye jaldi" || confirmer.tenantID != "tenant-1" {
+		t.Fatalf("confirmer asked %v for tenant %q, want the message for tenant-1", confirmer.texts, confirmer.tenantID)
+	}
+
+	// Confident, too short and agent messages are not sent to the model
+	for _, normalized := range []*NormalizedMessage{
+		normalize("Can you tell me the price of the annual plan?", "customer"),
+		normalize("मैं कल आऊंगा", "customer"),
+		normalize("aap kab tak deliver karoge", "agent"),
+	} {
+		s.confirmLanguage("tenant-1", normalized)
+	}
+	if len(confirmer.texts) != 1 {
+		t.Errorf("confirmer asked %v, want only the romanized customer message", confirmer.texts)
+	}
+
+	// A failed confirmation keeps the local result
+	s.SetLanguageConfirmer(&fakeLanguageConfirmer{err: errors.New("gemini unavailable")})
+	failed := normalize("theek hai, kal baat karte hain", "customer")
+	s.confirmLanguage("tenant-1", failed)
+	if failed.Language != "unknown" {
+		t.Errorf("language after failed confirmation = %s, want unknown", failed.Language)
+	}
+}
@@ -0,0 +1,108 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Conversation statuses
+const (
+	StatusActive   = "active"
+	StatusClosed   = "closed"
+	StatusArchived = "archived"
+)
+
+// ErrInvalidStatusTransition is returned when a conversation can't move to the requested status
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// finalAnalysisTimeout bounds the analysis run while closing a conversation
+const finalAnalysisTimeout = 30 * time.Second
+
+// validateStatusTransition allows active → closed and closed → archived
+func validateStatusTransition(from, to string) error {
+	if (from == StatusActive && to == StatusClosed) || (from == StatusClosed && to == StatusArchived) {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot change a %s conversation to %s", ErrInvalidStatusTransition, from, to)
+}
+
+// CloseConversation closes an active conversation and returns it. A final analysis runs first, synchronously,
+// so the stored metadata covers every message; a failed analysis doesn't prevent closing.
+func (s *IngestionService) CloseConversation(tenantID, conversationID, closingUserID string) (*models.Conversation, error) {
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStatusTransition(conv.Status, StatusClosed); err != nil {
+		return nil, err
+	}
+
+	if s.analyzer != nil {
+		messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages: %w", err)
+		}
+		if len(messages) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), finalAnalysisTimeout)
+			if err := s.analyzer.AnalyzeConversation(ctx, tenantID, conversationID, messages); err != nil {
+				log.Printf("[INGESTION] WARN final analysis failed conversation=%s: %v", conversationID, err)
+			}
+			cancel()
+		}
+	}
+
+	now := time.Now()
+	conv.Status = StatusClosed
+	conv.ClosedAt = &now
+	conv.UpdatedAt = now
+	if err := s.conversationStorage.UpdateConversation(tenantID, conv); err != nil {
+		return nil, err
+	}
+	log.Printf("[INGESTION] conversation closed conversation=%s tenant=%s by=%s", conversationID, tenantID, closingUserID)
+	s.recordStatusChange(tenantID, conversationID, closingUserID, StatusActive, StatusClosed)
+	return conv, nil
+}
+
+// ArchiveConversation archives a closed conversation and returns it
+func (s *IngestionService) ArchiveConversation(tenantID, conversationID, archivingUserID string) (*models.Conversation, error) {
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStatusTransition(conv.Status, StatusArchived); err != nil {
+		return nil, err
+	}
+
+	conv.Status = StatusArchived
+	conv.UpdatedAt = time.Now()
+	if err := s.conversationStorage.UpdateConversation(tenantID, conv); err != nil {
+		return nil, err
+	}
+	s.recordStatusChange(tenantID, conversationID, archivingUserID, StatusClosed, StatusArchived)
+	return conv, nil
+}
+
+// recordStatusChange adds a status change to the audit log, if one is configured
+func (s *IngestionService) recordStatusChange(tenantID, conversationID, userID, from, to string) {
+	if s.auditStorage == nil {
+		return
+	}
+	entry := &postgres.AuditLog{
+		TenantID:     tenantID,
+		UserID:       &userID,
+		Action:       "conversation." + to,
+		ResourceType: "conversation",
+		ResourceID:   &conversationID,
+		OldValue:     postgres.AuditValue(map[string]string{"status": from}),
+		NewValue:     postgres.AuditValue(map[string]string{"status": to}),
+	}
+	if err := s.auditStorage.Record(entry); err != nil {
+		log.Printf("[INGESTION] failed to record status change audit log: %v", err)
+	}
+}
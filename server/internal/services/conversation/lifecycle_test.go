@@ -0,0 +1,31 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateStatusTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{StatusActive, StatusClosed, true},
+		{StatusClosed, StatusArchived, true},
+		{StatusActive, StatusArchived, false},
+		{StatusClosed, StatusClosed, false},
+		{StatusClosed, StatusActive, false},
+		{StatusArchived, StatusActive, false},
+		{StatusArchived, StatusClosed, false},
+		{"", StatusClosed, false},
+	}
+	for _, tc := range cases {
+		err := validateStatusTransition(tc.from, tc.to)
+		if tc.allowed && err != nil {
+			t.Errorf("%s -> %s: unexpected error %v", tc.from, tc.to, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("%s -> %s: err = %v, want ErrInvalidStatusTransition", tc.from, tc.to, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package conversation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// EventConversationsMerged is published after a duplicate conversation is merged into another
+const EventConversationsMerged = "conversation.merged"
+
+// ErrInvalidMerge is returned when two conversations can't be merged
+var ErrInvalidMerge = errors.New("invalid merge")
+
+// validateMerge allows merging two different active conversations of the same customer
+func validateMerge(primary, secondary *models.Conversation) error {
+	if primary.ID == secondary.ID {
+		return fmt.Errorf("%w: a conversation can't be merged into itself", ErrInvalidMerge)
+	}
+	if primary.Status != StatusActive || secondary.Status != StatusActive {
+		return fmt.Errorf("%w: only active conversations can be merged", ErrInvalidMerge)
+	}
+	if valueOrEmpty(primary.CustomerID) != valueOrEmpty(secondary.CustomerID) {
+		return fmt.Errorf("%w: conversations belong to different customers", ErrInvalidMerge)
+	}
+	return nil
+}
+
+// MergeConversations merges a duplicate conversation into the primary one: the secondary's messages
+// move to the primary, its analysis is copied when the primary has none, and it is closed. The
+// primary is then re-analyzed with the combined messages.
+func (s *IngestionService) MergeConversations(tenantID, primaryID, secondaryID string) error {
+	primary, err := s.conversationStorage.GetConversation(tenantID, primaryID)
+	if err != nil {
+		return err
+	}
+	secondary, err := s.conversationStorage.GetConversation(tenantID, secondaryID)
+	if err != nil {
+		return err
+	}
+	if err := validateMerge(primary, secondary); err != nil {
+		return err
+	}
+
+	moved, err := s.conversationStorage.MergeConversations(tenantID, primaryID, secondaryID)
+	if err != nil {
+		return fmt.Errorf("failed to merge conversations: %w", err)
+	}
+	log.Printf("[INGESTION] conversations merged primary=%s secondary=%s tenant=%s messages=%d", primaryID, secondaryID, tenantID, moved)
+
+	s.publishEvent(tenantID, EventConversationsMerged, map[string]interface{}{
+		"conversation_id":        primaryID,
+		"merged_conversation_id": secondaryID,
+		"messages_moved":         moved,
+	})
+
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, primaryID)
+	if err != nil {
+		log.Printf("[INGESTION] failed to load merged messages for analysis conversation=%s: %v", primaryID, err)
+		return nil
+	}
+	s.analyzeAsync(tenantID, primaryID, messages)
+	return nil
+}
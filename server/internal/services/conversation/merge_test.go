@@ -0,0 +1,36 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestValidateMerge(t *testing.T) {
+	customer, other := "cust-1", "cust-2"
+	conv := func(id, status string, customerID *string) *models.Conversation {
+		return &models.Conversation{ID: id, Status: status, CustomerID: customerID}
+	}
+	cases := []struct {
+		name               string
+		primary, secondary *models.Conversation
+		allowed            bool
+	}{
+		{"same customer", conv("c1", StatusActive, &customer), conv("c2", StatusActive, &customer), true},
+		{"itself", conv("c1", StatusActive, &customer), conv("c1", StatusActive, &customer), false},
+		{"closed secondary", conv("c1", StatusActive, &customer), conv("c2", StatusClosed, &customer), false},
+		{"archived primary", conv("c1", StatusArchived, &customer), conv("c2", StatusActive, &customer), false},
+		{"different customers", conv("c1", StatusActive, &customer), conv("c2", StatusActive, &other), false},
+		{"anonymous and known customer", conv("c1", StatusActive, nil), conv("c2", StatusActive, &customer), false},
+	}
+	for _, tc := range cases {
+		err := validateMerge(tc.primary, tc.secondary)
+		if tc.allowed && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrInvalidMerge) {
+			t.Errorf("%s: err = %v, want ErrInvalidMerge", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// EventMessageRead is published when an agent reads a message for the first time
+const EventMessageRead = "message.read"
+
+// MarkMessageRead records a read receipt for a message and notifies other agents watching the conversation
+func (s *IngestionService) MarkMessageRead(tenantID, conversationID, messageID, readerID string) error {
+	msg, err := s.conversationStorage.GetMessage(messageID)
+	if err != nil || msg.ConversationID != conversationID {
+		return fmt.Errorf("message not found")
+	}
+
+	created, err := s.conversationStorage.MarkRead(messageID, readerID, tenantID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return nil
+	}
+
+	s.publishEvent(tenantID, EventMessageRead, map[string]interface{}{
+		"conversation_id": conversationID,
+		"message_id":      messageID,
+		"reader_id":       readerID,
+		"read_at":         time.Now().UTC().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// UnreadCount returns the number of customer messages in a conversation no agent has read
+func (s *IngestionService) UnreadCount(tenantID, conversationID string) (int, error) {
+	if _, err := s.conversationStorage.GetConversation(tenantID, conversationID); err != nil {
+		return 0, err
+	}
+	return s.conversationStorage.CountUnreadCustomerMessages(tenantID, conversationID)
+}
+
+// attachReadReceipts fills in each message's read receipts. Failures are logged and leave receipts empty.
+func (s *IngestionService) attachReadReceipts(tenantID, conversationID string, messages []*models.Message) {
+	reads, err := s.conversationStorage.GetMessageReads(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[CONVERSATION] failed to load read receipts conversation=%s error=%v", conversationID, err)
+		return
+	}
+	for _, msg := range messages {
+		msg.ReadBy = reads[msg.ID]
+	}
+}
@@ -0,0 +1,95 @@
+package conversation
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetentionDays is how long soft-deleted conversations are kept when RETENTION_DAYS is unset
+	defaultRetentionDays = 365
+	// retentionInterval is how often expired conversations are purged
+	retentionInterval = 24 * time.Hour
+)
+
+// RetentionStorage is the storage the retention job needs
+type RetentionStorage interface {
+	ListTenantsWithDeletedConversations() ([]string, error)
+	HardDeleteExpiredConversations(tenantID string, olderThan time.Duration) (int64, error)
+}
+
+// RetentionEnforcer permanently deletes conversations once they've been soft-deleted for the retention period
+type RetentionEnforcer struct {
+	storage   RetentionStorage
+	retention time.Duration
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewRetentionEnforcer creates a retention enforcer. Reads RETENTION_DAYS (default 365).
+func NewRetentionEnforcer(storage RetentionStorage) *RetentionEnforcer {
+	days := defaultRetentionDays
+	if v, err := strconv.Atoi(os.Getenv("RETENTION_DAYS")); err == nil && v > 0 {
+		days = v
+	}
+	return &RetentionEnforcer{
+		storage:   storage,
+		retention: time.Duration(days) * 24 * time.Hour,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Retention returns how long soft-deleted conversations are kept
+func (e *RetentionEnforcer) Retention() time.Duration {
+	return e.retention
+}
+
+// PurgeExpired hard-deletes expired conversations for every tenant. Tenants that fail are logged
+// and skipped. Returns the number of conversations removed.
+func (e *RetentionEnforcer) PurgeExpired() (int64, error) {
+	tenantIDs, err := e.storage.ListTenantsWithDeletedConversations()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, tenantID := range tenantIDs {
+		deleted, err := e.storage.HardDeleteExpiredConversations(tenantID, e.retention)
+		if err != nil {
+			log.Printf("[COMPLIANCE] retention purge failed tenant=%s error=%v", tenantID, err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("[COMPLIANCE] purged expired conversations tenant=%s count=%d", tenantID, deleted)
+		}
+		total += deleted
+	}
+	return total, nil
+}
+
+// Start purges expired conversations once a day until Stop is called
+func (e *RetentionEnforcer) Start() {
+	go func() {
+		ticker := time.NewTicker(retentionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := e.PurgeExpired(); err != nil {
+					log.Printf("[COMPLIANCE] scheduled retention purge failed: %v", err)
+				}
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("[COMPLIANCE] conversation retention scheduled retention=%s interval=%s", e.retention, retentionInterval)
+}
+
+// Stop stops the retention job
+func (e *RetentionEnforcer) Stop() {
+	e.stopOnce.Do(func() { close(e.stop) })
+}
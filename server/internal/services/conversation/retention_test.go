@@ -0,0 +1,69 @@
+package conversation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRetentionStorage struct {
+	tenants []string
+	deleted map[string]int64
+	failing map[string]bool
+	calls   []time.Duration
+}
+
+func (f *fakeRetentionStorage) ListTenantsWithDeletedConversations() ([]string, error) {
+	return f.tenants, nil
+}
+
+func (f *fakeRetentionStorage) HardDeleteExpiredConversations(tenantID string, olderThan time.Duration) (int64, error) {
+	f.calls = append(f.calls, olderThan)
+	if f.failing[tenantID] {
+		return 0, errors.New("database unavailable")
+	}
+	return f.deleted[tenantID], nil
+}
+
+func TestNewRetentionEnforcerReadsRetentionDays(t *testing.T) {
+	cases := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", 365 * 24 * time.Hour},
+		{"30", 30 * 24 * time.Hour},
+		{"0", 365 * 24 * time.Hour},
+		{"not-a-number", 365 * 24 * time.Hour},
+	}
+	for _, tc := range cases {
+		t.Setenv("RETENTION_DAYS", tc.env)
+		if got := NewRetentionEnforcer(&fakeRetentionStorage{}).Retention(); got != tc.want {
+			t.Errorf("RETENTION_DAYS=%q retention = %s, want %s", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestPurgeExpiredSkipsFailingTenants(t *testing.T) {
+	t.Setenv("RETENTION_DAYS", "7")
+	storage := &fakeRetentionStorage{
+		tenants: []string{"a", "b", "c"},
+		deleted: map[string]int64{"a": 2, "c": 3},
+		failing: map[string]bool{"b": true},
+	}
+
+	total, err := NewRetentionEnforcer(storage).PurgeExpired()
+	if err != nil {
+		t.Fatalf("PurgeExpired: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("purged %d conversations, want 5", total)
+	}
+	if len(storage.calls) != 3 {
+		t.Fatalf("HardDeleteExpiredConversations called %d times, want 3", len(storage.calls))
+	}
+	for _, olderThan := range storage.calls {
+		if olderThan != 7*24*time.Hour {
+			t.Errorf("olderThan = %s, want 168h", olderThan)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Fields routing conditions can test
+const (
+	RoutingFieldIntent           = "intent"
+	RoutingFieldSentiment        = "sentiment"
+	RoutingFieldProductID        = "product_id"
+	RoutingFieldCustomerLanguage = "customer_language"
+	RoutingFieldUrgencyScore     = "urgency_score"
+)
+
+// RoutingActionAssignAgent assigns the conversation to the action's agent
+const RoutingActionAssignAgent = "assign_agent"
+
+// Operators each field type supports. String fields compare
+// case-insensitively; "in" and "not_in" take a list of strings.
+var (
+	routingStringOperators = []string{"eq", "neq", "in", "not_in"}
+	routingNumberOperators = []string{"eq", "neq", "gt", "gte", "lt", "lte"}
+)
+
+// routingFieldOperators maps each routing field to the operators it supports
+var routingFieldOperators = map[string][]string{
+	RoutingFieldIntent:           routingStringOperators,
+	RoutingFieldSentiment:        routingStringOperators,
+	RoutingFieldProductID:        routingStringOperators,
+	RoutingFieldCustomerLanguage: routingStringOperators,
+	RoutingFieldUrgencyScore:     routingNumberOperators,
+}
+
+// RoutingRuleSource lists a tenant's routing rules (see postgres.RoutingRuleStorage)
+type RoutingRuleSource interface {
+	ListRoutingRules(tenantID string) ([]*postgres.RoutingRule, error)
+}
+
+// RoutingMessageSource loads the messages customer_language conditions read
+type RoutingMessageSource interface {
+	GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error)
+}
+
+// UrgencyScorer scores how urgent a conversation is, 0-1 (see analytics.AnalyticsService)
+type UrgencyScorer interface {
+	GetUrgencyScore(tenantID, conversationID string) float64
+}
+
+// RoutingEngine picks the agent a conversation should be assigned to from the tenant's routing
+// rules. Rules are tried from the highest priority down and the first rule whose conditions all
+// match wins.
+type RoutingEngine struct {
+	rules         RoutingRuleSource
+	messages      RoutingMessageSource
+	urgencyScorer UrgencyScorer
+}
+
+// NewRoutingEngine creates a routing engine
+func NewRoutingEngine(rules RoutingRuleSource, messages RoutingMessageSource) *RoutingEngine {
+	return &RoutingEngine{rules: rules, messages: messages}
+}
+
+// SetUrgencyScorer enables urgency_score conditions (optional). Without a scorer they never match.
+func (e *RoutingEngine) SetUrgencyScorer(scorer UrgencyScorer) {
+	e.urgencyScorer = scorer
+}
+
+// Evaluate returns the agent the first matching routing rule assigns the conversation to, or ""
+// when no rule matches. metadata may be nil before the conversation has been analyzed, in which
+// case intent and sentiment conditions don't match.
+func (e *RoutingEngine) Evaluate(conv *models.Conversation, metadata *models.ConversationMetadata) (string, error) {
+	rules, err := e.rules.ListRoutingRules(conv.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load routing rules: %w", err)
+	}
+	// Storage already lists rules in priority order; sort anyway so ties stay oldest first
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	facts := &routingFacts{engine: e, conv: conv, metadata: metadata}
+	for _, rule := range rules {
+		if rule.Action.Type != RoutingActionAssignAgent || rule.Action.AgentID == "" {
+			continue
+		}
+		if facts.matches(rule.Conditions) {
+			return rule.Action.AgentID, nil
+		}
+	}
+	return "", nil
+}
+
+// routeConversation assigns an unassigned conversation to the agent its routing rules pick. It
+// runs after each analysis so intent and sentiment conditions see the latest metadata.
+func (s *IngestionService) routeConversation(tenantID, conversationID string) {
+	if s.routingEngine == nil {
+		return
+	}
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[ROUTING] failed to load conversation=%s error=%v", conversationID, err)
+		return
+	}
+	if conv.AssignedAgentID != nil && *conv.AssignedAgentID != "" {
+		return
+	}
+	metadata, err := s.conversationStorage.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[ROUTING] failed to load metadata conversation=%s error=%v", conversationID, err)
+		return
+	}
+
+	agentID, err := s.routingEngine.Evaluate(conv, metadata)
+	if err != nil {
+		log.Printf("[ROUTING] evaluation failed tenant=%s conversation=%s error=%v", tenantID, conversationID, err)
+		return
+	}
+	if agentID == "" {
+		return
+	}
+	if err := s.conversationStorage.AssignAgent(tenantID, conversationID, agentID); err != nil {
+		log.Printf("[ROUTING] failed to assign tenant=%s conversation=%s agent=%s error=%v", tenantID, conversationID, agentID, err)
+		return
+	}
+	log.Printf("[ROUTING] conversation routed tenant=%s conversation=%s agent=%s", tenantID, conversationID, agentID)
+}
+
+// routingFacts resolves the fields conditions test, loading the customer's language and the
+// urgency score only when a condition needs them
+type routingFacts struct {
+	engine   *RoutingEngine
+	conv     *models.Conversation
+	metadata *models.ConversationMetadata
+
+	language       *string
+	urgency        *float64
+	urgencyLoaded  bool
+	languageLoaded bool
+}
+
+// matches reports whether every condition holds
+func (f *routingFacts) matches(conditions []postgres.RoutingCondition) bool {
+	for _, condition := range conditions {
+		if !f.matchCondition(condition) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *routingFacts) matchCondition(condition postgres.RoutingCondition) bool {
+	if condition.Field == RoutingFieldUrgencyScore {
+		score, ok := f.urgencyScore()
+		if !ok {
+			return false
+		}
+		want, ok := routingNumber(condition.Value)
+		return ok && compareRoutingNumber(condition.Operator, score, want)
+	}
+
+	value, ok := f.stringField(condition.Field)
+	if !ok {
+		// An unknown value only satisfies negative conditions
+		return condition.Operator == "neq" || condition.Operator == "not_in"
+	}
+	return compareRoutingString(condition.Operator, value, condition.Value)
+}
+
+// stringField returns a string field's value, or false when the conversation doesn't have one
+func (f *routingFacts) stringField(field string) (string, bool) {
+	var value string
+	switch field {
+	case RoutingFieldIntent:
+		if f.metadata != nil {
+			value = f.metadata.Intent
+		}
+	case RoutingFieldSentiment:
+		if f.metadata != nil {
+			value = f.metadata.Sentiment
+		}
+	case RoutingFieldProductID:
+		if f.conv.ProductID != nil {
+			value = *f.conv.ProductID
+		}
+	case RoutingFieldCustomerLanguage:
+		if language := f.customerLanguage(); language != nil {
+			value = *language
+		}
+	}
+	return value, value != ""
+}
+
+// customerLanguage returns the conversation's override language, otherwise the language of the
+// latest customer message with a detected language
+func (f *routingFacts) customerLanguage() *string {
+	if f.languageLoaded {
+		return f.language
+	}
+	f.languageLoaded = true
+
+	if f.conv.OverrideLanguage != nil && *f.conv.OverrideLanguage != "" {
+		f.language = f.conv.OverrideLanguage
+		return f.language
+	}
+	messages, err := f.engine.messages.GetMessagesByConversation(f.conv.TenantID, f.conv.ID)
+	if err != nil {
+		log.Printf("[ROUTING] failed to load messages conversation=%s error=%v", f.conv.ID, err)
+		return nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Sender == "customer" && messages[i].Language != "" && messages[i].Language != unknownLanguage {
+			f.language = &messages[i].Language
+			break
+		}
+	}
+	return f.language
+}
+
+func (f *routingFacts) urgencyScore() (float64, bool) {
+	if !f.urgencyLoaded {
+		f.urgencyLoaded = true
+		if f.engine.urgencyScorer != nil {
+			score := f.engine.urgencyScorer.GetUrgencyScore(f.conv.TenantID, f.conv.ID)
+			f.urgency = &score
+		}
+	}
+	if f.urgency == nil {
+		return 0, false
+	}
+	return *f.urgency, true
+}
+
+func compareRoutingString(operator, value string, want interface{}) bool {
+	switch operator {
+	case "eq", "neq":
+		s, ok := want.(string)
+		equal := ok && strings.EqualFold(value, s)
+		return equal == (operator == "eq")
+	case "in", "not_in":
+		list, ok := routingStringList(want)
+		found := false
+		for _, s := range list {
+			if strings.EqualFold(value, s) {
+				found = true
+				break
+			}
+		}
+		return ok && found == (operator == "in")
+	}
+	return false
+}
+
+func compareRoutingNumber(operator string, value, want float64) bool {
+	switch operator {
+	case "eq":
+		return value == want
+	case "neq":
+		return value != want
+	case "gt":
+		return value > want
+	case "gte":
+		return value >= want
+	case "lt":
+		return value < want
+	case "lte":
+		return value <= want
+	}
+	return false
+}
+
+// routingNumber reads a numeric condition value, as decoded from JSON
+func routingNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// routingStringList reads a list condition value, as decoded from JSON
+func routingStringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// ValidateRoutingRule checks that a rule only uses supported fields, operators and actions, and
+// that each condition's value has the type its operator expects
+func ValidateRoutingRule(rule *postgres.RoutingRule) error {
+	if rule.Action.Type != RoutingActionAssignAgent {
+		return fmt.Errorf("action.type must be %q", RoutingActionAssignAgent)
+	}
+	if strings.TrimSpace(rule.Action.AgentID) == "" {
+		return fmt.Errorf("action.agent_id is required")
+	}
+
+	for i, condition := range rule.Conditions {
+		operators, ok := routingFieldOperators[condition.Field]
+		if !ok {
+			return fmt.Errorf("conditions[%d]: unsupported field %q, must be one of: intent, sentiment, product_id, customer_language, urgency_score", i, condition.Field)
+		}
+		if !containsString(operators, condition.Operator) {
+			return fmt.Errorf("conditions[%d]: unsupported operator %q for %s, must be one of: %s", i, condition.Operator, condition.Field, strings.Join(operators, ", "))
+		}
+
+		switch {
+		case condition.Field == RoutingFieldUrgencyScore:
+			if _, ok := routingNumber(condition.Value); !ok {
+				return fmt.Errorf("conditions[%d]: value must be a number", i)
+			}
+		case condition.Operator == "in" || condition.Operator == "not_in":
+			if list, ok := routingStringList(condition.Value); !ok || len(list) == 0 {
+				return fmt.Errorf("conditions[%d]: value must be a non-empty list of strings", i)
+			}
+		default:
+			if s, ok := condition.Value.(string); !ok || s == "" {
+				return fmt.Errorf("conditions[%d]: value must be a non-empty string", i)
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
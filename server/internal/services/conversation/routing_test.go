@@ -0,0 +1,176 @@
+package conversation
+
+import (
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeRoutingStorage serves routing rules and messages from memory
+type fakeRoutingStorage struct {
+	rules        []*postgres.RoutingRule
+	messages     []*models.Message
+	messageLoads int
+}
+
+func (f *fakeRoutingStorage) ListRoutingRules(tenantID string) ([]*postgres.RoutingRule, error) {
+	var rules []*postgres.RoutingRule
+	for _, rule := range f.rules {
+		if rule.TenantID == tenantID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeRoutingStorage) GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error) {
+	f.messageLoads++
+	return f.messages, nil
+}
+
+type fixedUrgency float64
+
+func (u fixedUrgency) GetUrgencyScore(tenantID, conversationID string) float64 { return float64(u) }
+
+func routingRule(priority int, agentID string, conditions ...postgres.RoutingCondition) *postgres.RoutingRule {
+	return &postgres.RoutingRule{
+		TenantID:   "tenant-1",
+		Priority:   priority,
+		Conditions: conditions,
+		Action:     postgres.RoutingAction{Type: RoutingActionAssignAgent, AgentID: agentID},
+	}
+}
+
+func TestRoutingEngineEvaluatesRulesByPriority(t *testing.T) {
+	buying := postgres.RoutingCondition{Field: RoutingFieldIntent, Operator: "eq", Value: "buying"}
+	storage := &fakeRoutingStorage{rules: []*postgres.RoutingRule{
+		routingRule(1, "fallback-agent"),
+		routingRule(5, "sales-agent", buying),
+		routingRule(10, "vip-agent", buying, postgres.RoutingCondition{Field: RoutingFieldUrgencyScore, Operator: "gte", Value: 0.8}),
+		routingRule(5, "second-sales-agent", buying),
+		{TenantID: "tenant-2", Priority: 100, Action: postgres.RoutingAction{Type: RoutingActionAssignAgent, AgentID: "other-tenant-agent"}},
+	}}
+	engine := NewRoutingEngine(storage, storage)
+	conv := &models.Conversation{ID: "c1", TenantID: "tenant-1"}
+
+	tests := []struct {
+		name    string
+		intent  string
+		urgency float64
+		want    string
+	}{
+		{"highest priority match wins", "buying", 0.9, "vip-agent"},
+		{"ties go to the earlier rule", "buying", 0.3, "sales-agent"},
+		{"catch-all rule last", "support", 0.9, "fallback-agent"},
+	}
+	for _, tc := range tests {
+		engine.SetUrgencyScorer(fixedUrgency(tc.urgency))
+		got, err := engine.Evaluate(conv, &models.ConversationMetadata{Intent: tc.intent})
+		if err != nil {
+			t.Fatalf("%s: Evaluate: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: Evaluate = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// Without a catch-all nothing matches
+	storage.rules = storage.rules[1:4]
+	if got, _ := engine.Evaluate(conv, &models.ConversationMetadata{Intent: "support"}); got != "" {
+		t.Errorf("Evaluate without a match = %q, want none", got)
+	}
+}
+
+func TestRoutingEngineConditions(t *testing.T) {
+	productID := "prod-1"
+	hindi := "hi"
+	conv := &models.Conversation{ID: "c1", TenantID: "tenant-1", ProductID: &productID}
+	metadata := &models.ConversationMetadata{Intent: "buying", Sentiment: "negative"}
+	messages := []*models.Message{
+		{Sender: "customer", Language: "en"},
+		{Sender: "customer", Language: "hi"},
+		{Sender: "agent", Language: "en"},
+		{Sender: "customer", Language: unknownLanguage},
+	}
+
+	tests := []struct {
+		name      string
+		condition postgres.RoutingCondition
+		conv      *models.Conversation
+		metadata  *models.ConversationMetadata
+		want      bool
+	}{
+		{"intent eq is case-insensitive", postgres.RoutingCondition{Field: "intent", Operator: "eq", Value: "BUYING"}, conv, metadata, true},
+		{"intent neq", postgres.RoutingCondition{Field: "intent", Operator: "neq", Value: "buying"}, conv, metadata, false},
+		{"sentiment in", postgres.RoutingCondition{Field: "sentiment", Operator: "in", Value: []interface{}{"negative", "neutral"}}, conv, metadata, true},
+		{"sentiment not_in", postgres.RoutingCondition{Field: "sentiment", Operator: "not_in", Value: []interface{}{"negative"}}, conv, metadata, false},
+		{"product eq", postgres.RoutingCondition{Field: "product_id", Operator: "eq", Value: "prod-1"}, conv, metadata, true},
+		{"no product only matches neq", postgres.RoutingCondition{Field: "product_id", Operator: "neq", Value: "prod-1"}, &models.Conversation{ID: "c2", TenantID: "tenant-1"}, metadata, true},
+		{"no metadata doesn't match intent", postgres.RoutingCondition{Field: "intent", Operator: "eq", Value: "buying"}, conv, nil, false},
+		{"latest detected customer language", postgres.RoutingCondition{Field: "customer_language", Operator: "eq", Value: "hi"}, conv, metadata, true},
+		{"override language wins", postgres.RoutingCondition{Field: "customer_language", Operator: "eq", Value: "hi"}, &models.Conversation{ID: "c3", TenantID: "tenant-1", OverrideLanguage: &hindi}, metadata, true},
+		{"urgency gte", postgres.RoutingCondition{Field: "urgency_score", Operator: "gte", Value: 0.7}, conv, metadata, true},
+		{"urgency lt", postgres.RoutingCondition{Field: "urgency_score", Operator: "lt", Value: 0.7}, conv, metadata, false},
+		{"urgency with a string value", postgres.RoutingCondition{Field: "urgency_score", Operator: "gt", Value: "0.5"}, conv, metadata, false},
+	}
+	for _, tc := range tests {
+		storage := &fakeRoutingStorage{messages: messages, rules: []*postgres.RoutingRule{routingRule(1, "agent-1", tc.condition)}}
+		engine := NewRoutingEngine(storage, storage)
+		engine.SetUrgencyScorer(fixedUrgency(0.7))
+
+		got, err := engine.Evaluate(tc.conv, tc.metadata)
+		if err != nil {
+			t.Fatalf("%s: Evaluate: %v", tc.name, err)
+		}
+		if matched := got == "agent-1"; matched != tc.want {
+			t.Errorf("%s: matched = %v, want %v", tc.name, matched, tc.want)
+		}
+	}
+}
+
+func TestRoutingEngineLoadsOptionalFactsOnce(t *testing.T) {
+	language := postgres.RoutingCondition{Field: RoutingFieldCustomerLanguage, Operator: "eq", Value: "es"}
+	urgency := postgres.RoutingCondition{Field: RoutingFieldUrgencyScore, Operator: "gt", Value: 0.5}
+	storage := &fakeRoutingStorage{
+		messages: []*models.Message{{Sender: "customer", Language: "en"}},
+		rules:    []*postgres.RoutingRule{routingRule(3, "spanish-agent", language), routingRule(2, "spanish-agent", language), routingRule(1, "urgent-agent", urgency)},
+	}
+	engine := NewRoutingEngine(storage, storage)
+	conv := &models.Conversation{ID: "c1", TenantID: "tenant-1"}
+
+	// Without an urgency scorer urgency conditions never match
+	if got, _ := engine.Evaluate(conv, nil); got != "" {
+		t.Errorf("Evaluate without scorer = %q, want none", got)
+	}
+	if storage.messageLoads != 1 {
+		t.Errorf("messages loaded %d times, want once per evaluation", storage.messageLoads)
+	}
+}
+
+func TestValidateRoutingRule(t *testing.T) {
+	valid := routingRule(1, "agent-1",
+		postgres.RoutingCondition{Field: "customer_language", Operator: "in", Value: []interface{}{"hi", "en"}},
+		postgres.RoutingCondition{Field: "urgency_score", Operator: "gte", Value: 0.8},
+	)
+	if err := ValidateRoutingRule(valid); err != nil {
+		t.Errorf("ValidateRoutingRule(valid) = %v", err)
+	}
+	if err := ValidateRoutingRule(routingRule(1, "agent-1")); err != nil {
+		t.Errorf("ValidateRoutingRule(catch-all) = %v", err)
+	}
+
+	invalid := []*postgres.RoutingRule{
+		routingRule(1, ""),
+		routingRule(1, "agent-1", postgres.RoutingCondition{Field: "channel", Operator: "eq", Value: "email"}),
+		routingRule(1, "agent-1", postgres.RoutingCondition{Field: "intent", Operator: "gte", Value: "buying"}),
+		routingRule(1, "agent-1", postgres.RoutingCondition{Field: "urgency_score", Operator: "in", Value: []interface{}{0.5}}),
+		routingRule(1, "agent-1", postgres.RoutingCondition{Field: "sentiment", Operator: "in", Value: []interface{}{}}),
+		{Action: postgres.RoutingAction{Type: "notify", AgentID: "agent-1"}},
+	}
+	for i, rule := range invalid {
+		if err := ValidateRoutingRule(rule); err == nil {
+			t.Errorf("ValidateRoutingRule(invalid[%d]) = nil, want an error", i)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package conversation
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/ai"
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
+)
+
+const (
+	// defaultSLAThresholdMinutes is the response deadline when neither the tenant nor
+	// SLA_RESPONSE_THRESHOLD_MINUTES sets one
+	defaultSLAThresholdMinutes = 60
+	// slaScanInterval is how often passed deadlines are marked as breached
+	slaScanInterval = time.Minute
+)
+
+// SLAStorage is the storage the SLA tracker needs
+type SLAStorage interface {
+	GetSLAConfig(tenantID string) (*postgres.SLAConfig, error)
+	GetConversationProductSLAConfig(tenantID, conversationID string) (*postgres.ProductSLAConfig, error)
+	CreateSLARecord(record *postgres.SLARecord) error
+	MarkSLAResponded(tenantID, conversationID string, respondedAt time.Time) (int64, error)
+	MarkSLABreaches(now time.Time) (int64, error)
+}
+
+// SLAMetadataSource loads a conversation's analysis, whose complexity score extends the deadline
+type SLAMetadataSource interface {
+	GetConversationMetadata(tenantID, conversationID string) (*models.ConversationMetadata, error)
+}
+
+// SLAWatchlistSource reports whether a conversation is watchlisted (see postgres.WatchlistStorage)
+type SLAWatchlistSource interface {
+	IsWatchlisted(tenantID, conversationID string) (bool, error)
+}
+
+// SLATracker tracks how quickly agents reply to customer messages. Each customer message gets a
+// response deadline; a background scan marks deadlines that pass without an agent reply as breached.
+type SLATracker struct {
+	storage          SLAStorage
+	defaultThreshold time.Duration
+	metadata         SLAMetadataSource
+	watchlist        SLAWatchlistSource
+	scanInterval     time.Duration
+	now              func() time.Time
+	stop             chan struct{}
+	stopOnce         sync.Once
+}
+
+// NewSLATracker creates an SLA tracker. Reads SLA_RESPONSE_THRESHOLD_MINUTES (default 60), used
+// for tenants without their own SLA config.
+func NewSLATracker(storage SLAStorage) *SLATracker {
+	minutes := defaultSLAThresholdMinutes
+	if v, err := strconv.Atoi(os.Getenv("SLA_RESPONSE_THRESHOLD_MINUTES")); err == nil && v > 0 {
+		minutes = v
+	}
+	return &SLATracker{
+		storage:          storage,
+		defaultThreshold: time.Duration(minutes) * time.Minute,
+		scanInterval:     slaScanInterval,
+		now:              time.Now,
+		stop:             make(chan struct{}),
+	}
+}
+
+// SetClock replaces the tracker's clock (tests)
+func (t *SLATracker) SetClock(now func() time.Time) {
+	t.now = now
+}
+
+// SetComplexitySources extends deadlines for high complexity conversations by 50%, except for
+// watchlisted ones (optional; watchlist may be nil)
+func (t *SLATracker) SetComplexitySources(metadata SLAMetadataSource, watchlist SLAWatchlistSource) {
+	t.metadata = metadata
+	t.watchlist = watchlist
+}
+
+// DefaultThreshold returns the response deadline for tenants without an SLA config
+func (t *SLATracker) DefaultThreshold() time.Duration {
+	return t.defaultThreshold
+}
+
+// Threshold returns a tenant's response deadline, falling back to the default when the tenant
+// has no config or it can't be loaded
+func (t *SLATracker) Threshold(tenantID string) time.Duration {
+	config, err := t.storage.GetSLAConfig(tenantID)
+	if err != nil {
+		log.Printf("[SLA] failed to load sla config, using default tenant=%s error=%v", tenantID, err)
+		return t.defaultThreshold
+	}
+	if config == nil || config.ResponseThresholdMinutes <= 0 {
+		return t.defaultThreshold
+	}
+	return time.Duration(config.ResponseThresholdMinutes) * time.Minute
+}
+
+// ConversationThreshold returns a conversation's response deadline: its product's first response
+// SLA when the conversation is about a product that has one, otherwise the tenant's. High
+// complexity conversations get 50% longer unless they are watchlisted.
+func (t *SLATracker) ConversationThreshold(tenantID, conversationID string) time.Duration {
+	config, err := t.storage.GetConversationProductSLAConfig(tenantID, conversationID)
+	if err != nil {
+		log.Printf("[SLA] failed to load product sla config, using tenant threshold conversation=%s error=%v", conversationID, err)
+	}
+	if config != nil && config.FirstResponseMinutes > 0 {
+		return t.complexityAdjusted(tenantID, conversationID, time.Duration(config.FirstResponseMinutes)*time.Minute)
+	}
+	return t.complexityAdjusted(tenantID, conversationID, t.Threshold(tenantID))
+}
+
+// complexityAdjusted applies the conversation's stored complexity score to a deadline. Conversations
+// not analyzed yet, or whose analysis can't be loaded, keep the base deadline.
+func (t *SLATracker) complexityAdjusted(tenantID, conversationID string, base time.Duration) time.Duration {
+	if t.metadata == nil {
+		return base
+	}
+	metadata, err := t.metadata.GetConversationMetadata(tenantID, conversationID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			log.Printf("[SLA] failed to load complexity, using base threshold conversation=%s error=%v", conversationID, err)
+		}
+		return base
+	}
+
+	watchlisted := false
+	if t.watchlist != nil {
+		if watchlisted, err = t.watchlist.IsWatchlisted(tenantID, conversationID); err != nil {
+			log.Printf("[SLA] failed to check watchlist conversation=%s error=%v", conversationID, err)
+		}
+	}
+	complexity := ai.ComplexityScore{Score: int(metadata.ComplexityScore)}
+	return complexity.FirstResponseSLA(base, watchlisted)
+}
+
+// RecordCustomerMessage starts the response deadline for a customer message
+func (t *SLATracker) RecordCustomerMessage(tenantID, conversationID, messageID string) error {
+	now := t.now()
+	return t.storage.CreateSLARecord(&postgres.SLARecord{
+		TenantID:           tenantID,
+		ConversationID:     conversationID,
+		CustomerMessageID:  messageID,
+		ExpectedResponseBy: now.Add(t.ConversationThreshold(tenantID, conversationID)),
+		CreatedAt:          now,
+	})
+}
+
+// RecordAgentResponse closes the conversation's open deadlines; those already passed stay breached
+func (t *SLATracker) RecordAgentResponse(tenantID, conversationID string) error {
+	_, err := t.storage.MarkSLAResponded(tenantID, conversationID, t.now())
+	return err
+}
+
+// ScanBreaches marks every unanswered deadline that has passed as breached. Returns the number
+// of new breaches.
+func (t *SLATracker) ScanBreaches() (int64, error) {
+	breached, err := t.storage.MarkSLABreaches(t.now())
+	if err != nil {
+		return 0, err
+	}
+	if breached > 0 {
+		log.Printf("[SLA] response deadlines breached count=%d", breached)
+	}
+	return breached, nil
+}
+
+// Start queues a breach scan on pool every minute until Stop is called. A scan is skipped when
+// the pool is full; the next tick queues another.
+func (t *SLATracker) Start(pool *worker.Pool) {
+	go func() {
+		ticker := time.NewTicker(t.scanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				job := worker.Job{
+					Name: "sla_breach_scan",
+					Run: func(ctx context.Context) error {
+						_, err := t.ScanBreaches()
+						return err
+					},
+				}
+				if err := pool.Enqueue(job); err != nil {
+					log.Printf("[SLA] breach scan not queued pending=%d error=%v", pool.Len(), err)
+				}
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("[SLA] breach scan scheduled default_threshold=%s interval=%s", t.defaultThreshold, t.scanInterval)
+}
+
+// Stop stops the breach scan
+func (t *SLATracker) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
@@ -0,0 +1,327 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+	"ai-conversation-platform/internal/worker"
+)
+
+// fakeSLAStorage keeps SLA records in memory, mirroring the SQL in postgres.SLAStorage
+type fakeSLAStorage struct {
+	configs        map[string]*postgres.SLAConfig
+	productConfigs map[string]*postgres.ProductSLAConfig // By conversation ID
+	records        []*postgres.SLARecord
+	scans          chan time.Time
+}
+
+func (f *fakeSLAStorage) GetSLAConfig(tenantID string) (*postgres.SLAConfig, error) {
+	if tenantID == "broken" {
+		return nil, errors.New("database unavailable")
+	}
+	return f.configs[tenantID], nil
+}
+
+func (f *fakeSLAStorage) GetConversationProductSLAConfig(tenantID, conversationID string) (*postgres.ProductSLAConfig, error) {
+	if conversationID == "broken" {
+		return nil, errors.New("database unavailable")
+	}
+	return f.productConfigs[conversationID], nil
+}
+
+func (f *fakeSLAStorage) CreateSLARecord(record *postgres.SLARecord) error {
+	f.records = append(f.records, record)
+	return nil
+}
+
+func (f *fakeSLAStorage) MarkSLAResponded(tenantID, conversationID string, respondedAt time.Time) (int64, error) {
+	var closed int64
+	for _, r := range f.records {
+		if r.TenantID == tenantID && r.ConversationID == conversationID && r.RespondedAt == nil {
+			at := respondedAt
+			r.RespondedAt = &at
+			r.Breached = r.Breached || r.ExpectedResponseBy.Before(respondedAt)
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+func (f *fakeSLAStorage) MarkSLABreaches(now time.Time) (int64, error) {
+	select {
+	case f.scans <- now:
+	default:
+	}
+	var breached int64
+	for _, r := range f.records {
+		if !r.Breached && r.RespondedAt == nil && r.ExpectedResponseBy.Before(now) {
+			r.Breached = true
+			breached++
+		}
+	}
+	return breached, nil
+}
+
+// fakeClock is a manually advanced clock
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestNewSLATrackerReadsDefaultThreshold(t *testing.T) {
+	cases := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", time.Hour},
+		{"15", 15 * time.Minute},
+		{"0", time.Hour},
+		{"soon", time.Hour},
+	}
+	for _, tc := range cases {
+		t.Setenv("SLA_RESPONSE_THRESHOLD_MINUTES", tc.env)
+		if got := NewSLATracker(&fakeSLAStorage{}).DefaultThreshold(); got != tc.want {
+			t.Errorf("SLA_RESPONSE_THRESHOLD_MINUTES=%q threshold = %s, want %s", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestSLATrackerThresholdPerTenant(t *testing.T) {
+	t.Setenv("SLA_RESPONSE_THRESHOLD_MINUTES", "")
+	storage := &fakeSLAStorage{configs: map[string]*postgres.SLAConfig{
+		"fast": {TenantID: "fast", ResponseThresholdMinutes: 5},
+	}}
+	tracker := NewSLATracker(storage)
+
+	if got := tracker.Threshold("fast"); got != 5*time.Minute {
+		t.Errorf("configured tenant threshold = %s, want 5m", got)
+	}
+	if got := tracker.Threshold("other"); got != time.Hour {
+		t.Errorf("unconfigured tenant threshold = %s, want the 1h default", got)
+	}
+	if got := tracker.Threshold("broken"); got != time.Hour {
+		t.Errorf("threshold on storage error = %s, want the 1h default", got)
+	}
+}
+
+func TestSLATrackerProductSLAOverridesTenant(t *testing.T) {
+	t.Setenv("SLA_RESPONSE_THRESHOLD_MINUTES", "")
+	storage := &fakeSLAStorage{
+		configs: map[string]*postgres.SLAConfig{"tenant-1": {TenantID: "tenant-1", ResponseThresholdMinutes: 30}},
+		productConfigs: map[string]*postgres.ProductSLAConfig{
+			"enterprise": {ProductID: "p1", TenantID: "tenant-1", FirstResponseMinutes: 5, ResolutionHours: 24},
+		},
+	}
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}
+	tracker := NewSLATracker(storage)
+	tracker.SetClock(clock.Now)
+
+	cases := []struct {
+		conversationID string
+		want           time.Duration
+	}{
+		{"enterprise", 5 * time.Minute}, // The product's SLA wins over the tenant's
+		{"no-product", 30 * time.Minute},
+		{"broken", 30 * time.Minute}, // Falls back to the tenant's when the product config can't be loaded
+	}
+	for i, tc := range cases {
+		if got := tracker.ConversationThreshold("tenant-1", tc.conversationID); got != tc.want {
+			t.Errorf("%s threshold = %s, want %s", tc.conversationID, got, tc.want)
+		}
+		if err := tracker.RecordCustomerMessage("tenant-1", tc.conversationID, tc.conversationID+"-m"); err != nil {
+			t.Fatalf("RecordCustomerMessage: %v", err)
+		}
+		if got := storage.records[i].ExpectedResponseBy.Sub(clock.now); got != tc.want {
+			t.Errorf("%s deadline in %s, want %s", tc.conversationID, got, tc.want)
+		}
+	}
+
+	// Only the product's tighter deadline has passed after 10 minutes
+	clock.Advance(10 * time.Minute)
+	if n, err := tracker.ScanBreaches(); err != nil || n != 1 || !storage.records[0].Breached {
+		t.Errorf("ScanBreaches at +10m = %d, %v; want only the enterprise conversation breached", n, err)
+	}
+}
+
+func TestSLATrackerMarksBreachesAsTimeAdvances(t *testing.T) {
+	storage := &fakeSLAStorage{configs: map[string]*postgres.SLAConfig{
+		"tenant-1": {TenantID: "tenant-1", ResponseThresholdMinutes: 30},
+	}}
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}
+	tracker := NewSLATracker(storage)
+	tracker.SetClock(clock.Now)
+
+	if err := tracker.RecordCustomerMessage("tenant-1", "slow", "m1"); err != nil {
+		t.Fatalf("RecordCustomerMessage: %v", err)
+	}
+	if err := tracker.RecordCustomerMessage("tenant-1", "fast", "m2"); err != nil {
+		t.Fatalf("RecordCustomerMessage: %v", err)
+	}
+	if want := clock.now.Add(30 * time.Minute); !storage.records[0].ExpectedResponseBy.Equal(want) {
+		t.Fatalf("expected_response_by = %s, want %s", storage.records[0].ExpectedResponseBy, want)
+	}
+
+	// Within the deadline: the fast conversation is answered and nothing is breached yet
+	clock.Advance(20 * time.Minute)
+	if err := tracker.RecordAgentResponse("tenant-1", "fast"); err != nil {
+		t.Fatalf("RecordAgentResponse: %v", err)
+	}
+	if n, err := tracker.ScanBreaches(); err != nil || n != 0 {
+		t.Fatalf("ScanBreaches at +20m = %d, %v; want 0", n, err)
+	}
+
+	// Past the deadline: only the unanswered conversation is breached, and only once
+	clock.Advance(15 * time.Minute)
+	if n, err := tracker.ScanBreaches(); err != nil || n != 1 {
+		t.Fatalf("ScanBreaches at +35m = %d, %v; want 1", n, err)
+	}
+	if n, _ := tracker.ScanBreaches(); n != 0 {
+		t.Errorf("second scan = %d, want 0", n)
+	}
+	if !storage.records[0].Breached || storage.records[1].Breached {
+		t.Errorf("breached = %v/%v, want slow only", storage.records[0].Breached, storage.records[1].Breached)
+	}
+
+	// A late reply closes the breach at the time it was sent
+	clock.Advance(10 * time.Minute)
+	if err := tracker.RecordAgentResponse("tenant-1", "slow"); err != nil {
+		t.Fatalf("RecordAgentResponse: %v", err)
+	}
+	slow := storage.records[0]
+	if slow.RespondedAt == nil || slow.RespondedAt.Sub(slow.ExpectedResponseBy) != 15*time.Minute {
+		t.Errorf("responded_at = %v, want 15m after the deadline", slow.RespondedAt)
+	}
+	if !slow.Breached {
+		t.Error("late reply cleared the breach")
+	}
+}
+
+func TestSLATrackerReplyAfterDeadlineBeforeScanIsBreached(t *testing.T) {
+	storage := &fakeSLAStorage{}
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}
+	tracker := NewSLATracker(storage)
+	tracker.SetClock(clock.Now)
+
+	tracker.RecordCustomerMessage("tenant-1", "c1", "m1")
+	clock.Advance(tracker.DefaultThreshold() + time.Minute)
+	tracker.RecordAgentResponse("tenant-1", "c1")
+
+	if !storage.records[0].Breached {
+		t.Error("reply after the deadline should be breached even if no scan ran in between")
+	}
+}
+
+func TestIngestionTracksSLA(t *testing.T) {
+	storage := &fakeSLAStorage{}
+	tracker := NewSLATracker(storage)
+	s := NewIngestionService(nil)
+	s.SetSLATracker(tracker)
+
+	s.trackSLA("tenant-1", &models.Message{ID: "m1", ConversationID: "c1", Sender: "customer"})
+	if len(storage.records) != 1 || storage.records[0].CustomerMessageID != "m1" {
+		t.Fatalf("records = %+v, want one for the customer message", storage.records)
+	}
+
+	// Auto-replies aren't an agent response
+	s.trackSLA("tenant-1", &models.Message{ID: "m2", ConversationID: "c1", Sender: "agent", IsAutoReply: true})
+	if storage.records[0].RespondedAt != nil {
+		t.Fatal("auto-reply closed the deadline")
+	}
+
+	s.trackSLA("tenant-1", &models.Message{ID: "m3", ConversationID: "c1", Sender: "agent"})
+	if storage.records[0].RespondedAt == nil {
+		t.Error("agent reply left the deadline open")
+	}
+}
+
+func TestSLATrackerStartQueuesScansOnPool(t *testing.T) {
+	storage := &fakeSLAStorage{scans: make(chan time.Time, 10)}
+	tracker := NewSLATracker(storage)
+	tracker.scanInterval = 10 * time.Millisecond
+
+	pool := worker.NewPool(worker.Config{Workers: 1, QueueSize: 1})
+	pool.Start()
+	tracker.Start(pool)
+
+	select {
+	case <-storage.scans:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no breach scan ran on the pool")
+	}
+
+	tracker.Stop()
+	tracker.Stop() // Stop is idempotent
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+}
+
+type fakeSLAMetadata map[string]float64 // Complexity score by conversation ID
+
+func (f fakeSLAMetadata) GetConversationMetadata(tenantID, conversationID string) (*models.ConversationMetadata, error) {
+	if conversationID == "broken" {
+		return nil, errors.New("database unavailable")
+	}
+	score, ok := f[conversationID]
+	if !ok {
+		return nil, errors.New("metadata not found")
+	}
+	return &models.ConversationMetadata{ConversationID: conversationID, ComplexityScore: score}, nil
+}
+
+type fakeSLAWatchlist map[string]bool
+
+func (f fakeSLAWatchlist) IsWatchlisted(tenantID, conversationID string) (bool, error) {
+	return f[conversationID], nil
+}
+
+func TestSLATrackerExtendsHighComplexityDeadlines(t *testing.T) {
+	t.Setenv("SLA_RESPONSE_THRESHOLD_MINUTES", "")
+	storage := &fakeSLAStorage{
+		configs: map[string]*postgres.SLAConfig{"tenant-1": {TenantID: "tenant-1", ResponseThresholdMinutes: 30}},
+		productConfigs: map[string]*postgres.ProductSLAConfig{
+			"complex-product": {ProductID: "p1", TenantID: "tenant-1", FirstResponseMinutes: 10},
+		},
+	}
+	tracker := NewSLATracker(storage)
+	if got := tracker.ConversationThreshold("tenant-1", "complex"); got != 30*time.Minute {
+		t.Errorf("threshold without complexity sources = %s, want 30m", got)
+	}
+
+	tracker.SetComplexitySources(
+		fakeSLAMetadata{"simple": 3, "borderline": 6, "complex": 7, "complex-watchlisted": 9, "complex-product": 10},
+		fakeSLAWatchlist{"complex-watchlisted": true},
+	)
+	cases := []struct {
+		conversationID string
+		want           time.Duration
+	}{
+		{"simple", 30 * time.Minute},
+		{"borderline", 30 * time.Minute},
+		{"complex", 45 * time.Minute},
+		{"complex-watchlisted", 30 * time.Minute}, // Watchlisted conversations keep the shortest SLA
+		{"complex-product", 15 * time.Minute},     // The product's SLA is extended too
+		{"unanalyzed", 30 * time.Minute},
+		{"broken", 30 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := tracker.ConversationThreshold("tenant-1", tc.conversationID); got != tc.want {
+			t.Errorf("%s threshold = %s, want %s", tc.conversationID, got, tc.want)
+		}
+	}
+
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}
+	tracker.SetClock(clock.Now)
+	if err := tracker.RecordCustomerMessage("tenant-1", "complex", "m1"); err != nil {
+		t.Fatalf("RecordCustomerMessage: %v", err)
+	}
+	if got := storage.records[0].ExpectedResponseBy.Sub(clock.now); got != 45*time.Minute {
+		t.Errorf("complex conversation deadline in %s, want 45m", got)
+	}
+}
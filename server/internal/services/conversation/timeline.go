@@ -0,0 +1,202 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Timeline item types
+const (
+	TimelineMessage       = "message"
+	TimelineAutoReply     = "autoreply"
+	TimelineAssignment    = "assignment"
+	TimelineRuleViolation = "rule_violation"
+)
+
+// timelineCacheTTL is how long a built timeline is reused
+const timelineCacheTTL = 30 * time.Second
+
+// TimelineItem is a single entry in a conversation's timeline
+type TimelineItem struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Actor     string      `json:"actor"`   // Message sender, transferring user, "auto_reply" or "moderation"
+	Payload   interface{} `json:"payload"` // The message, transfer event or moderation details
+}
+
+// TimelineStorage loads the conversation records a timeline is built from
+type TimelineStorage interface {
+	GetConversation(tenantID, conversationID string) (*models.Conversation, error)
+	GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error)
+	GetTransferHistory(tenantID, conversationID string) ([]*models.TransferEvent, error)
+}
+
+// ModerationLogSource loads a conversation's content moderation audit entries
+type ModerationLogSource interface {
+	ListConversationModerationLogs(tenantID, conversationID string) ([]*postgres.AuditLog, error)
+}
+
+// timelineCacheEntry holds a built timeline with its expiry
+type timelineCacheEntry struct {
+	items     []TimelineItem
+	expiresAt time.Time
+}
+
+// timelineCache caches built timelines keyed by tenant and conversation
+type timelineCache struct {
+	mu      sync.Mutex
+	entries map[string]timelineCacheEntry
+}
+
+func newTimelineCache() *timelineCache {
+	return &timelineCache{entries: make(map[string]timelineCacheEntry)}
+}
+
+func (c *timelineCache) get(key string) ([]TimelineItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.items, true
+}
+
+func (c *timelineCache) set(key string, items []TimelineItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = timelineCacheEntry{items: items, expiresAt: time.Now().Add(timelineCacheTTL)}
+}
+
+// ConversationTimelineService merges a conversation's messages, transfers and moderation events
+// into a single chronological timeline
+type ConversationTimelineService struct {
+	storage    TimelineStorage
+	moderation ModerationLogSource
+	cache      *timelineCache
+}
+
+// NewConversationTimelineService creates a new timeline service. moderation may be nil, in which
+// case timelines have no rule_violation items.
+func NewConversationTimelineService(storage TimelineStorage, moderation ModerationLogSource) *ConversationTimelineService {
+	return &ConversationTimelineService{
+		storage:    storage,
+		moderation: moderation,
+		cache:      newTimelineCache(),
+	}
+}
+
+// Build returns the conversation's timeline sorted by timestamp. Sources are loaded in parallel and
+// the result is cached for 30 seconds.
+func (s *ConversationTimelineService) Build(tenantID, conversationID string) ([]TimelineItem, error) {
+	cacheKey := tenantID + "|" + conversationID
+	if items, ok := s.cache.get(cacheKey); ok {
+		return items, nil
+	}
+
+	if _, err := s.storage.GetConversation(tenantID, conversationID); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg            sync.WaitGroup
+		messages      []*models.Message
+		transfers     []*models.TransferEvent
+		moderationLog []*postgres.AuditLog
+		messagesErr   error
+		transfersErr  error
+		moderationErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		messages, messagesErr = s.storage.GetMessagesByConversation(tenantID, conversationID)
+	}()
+	go func() {
+		defer wg.Done()
+		transfers, transfersErr = s.storage.GetTransferHistory(tenantID, conversationID)
+	}()
+	if s.moderation != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			moderationLog, moderationErr = s.moderation.ListConversationModerationLogs(tenantID, conversationID)
+		}()
+	}
+	wg.Wait()
+
+	if messagesErr != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", messagesErr)
+	}
+	if transfersErr != nil {
+		return nil, fmt.Errorf("failed to get transfer history: %w", transfersErr)
+	}
+	if moderationErr != nil {
+		return nil, fmt.Errorf("failed to get moderation events: %w", moderationErr)
+	}
+
+	items := mergeTimeline(messages, transfers, moderationLog)
+	s.cache.set(cacheKey, items)
+	return items, nil
+}
+
+// mergeTimeline converts each source into timeline items and sorts them by timestamp. Items with
+// the same timestamp keep source order: messages, then transfers, then moderation events.
+func mergeTimeline(messages []*models.Message, transfers []*models.TransferEvent, moderationLog []*postgres.AuditLog) []TimelineItem {
+	items := make([]TimelineItem, 0, len(messages)+len(transfers)+len(moderationLog))
+	for _, msg := range messages {
+		item := TimelineItem{Type: TimelineMessage, Timestamp: msg.Timestamp, Actor: msg.Sender, Payload: msg}
+		if msg.IsAutoReply {
+			item.Type = TimelineAutoReply
+			item.Actor = "auto_reply"
+		}
+		items = append(items, item)
+	}
+	for _, transfer := range transfers {
+		items = append(items, TimelineItem{
+			Type:      TimelineAssignment,
+			Timestamp: transfer.TransferredAt,
+			Actor:     transfer.TransferredBy,
+			Payload:   transfer,
+		})
+	}
+	for _, entry := range moderationLog {
+		items = append(items, TimelineItem{
+			Type:      TimelineRuleViolation,
+			Timestamp: entry.CreatedAt,
+			Actor:     "moderation",
+			Payload:   moderationPayload(entry),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.Before(items[j].Timestamp)
+	})
+	return items
+}
+
+// moderationPayload flattens a moderation audit entry into the action, the flagged message (if
+// any) and the recorded details such as categories
+func moderationPayload(entry *postgres.AuditLog) map[string]interface{} {
+	payload := map[string]interface{}{}
+	if entry.NewValue != nil {
+		if err := json.Unmarshal([]byte(*entry.NewValue), &payload); err != nil {
+			log.Printf("[TIMELINE] failed to decode moderation audit log audit_id=%s error=%v", entry.ID, err)
+			payload = map[string]interface{}{}
+		}
+	}
+	delete(payload, "conversation_id")
+	payload["action"] = entry.Action
+	if entry.ResourceType == "message" && entry.ResourceID != nil {
+		payload["message_id"] = *entry.ResourceID
+	}
+	return payload
+}
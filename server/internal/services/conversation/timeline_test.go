@@ -0,0 +1,129 @@
+package conversation
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+type fakeTimelineStorage struct {
+	messages  []*models.Message
+	transfers []*models.TransferEvent
+	audit     []*postgres.AuditLog
+	auditErr  error
+	loads     int32
+}
+
+func (f *fakeTimelineStorage) GetConversation(tenantID, conversationID string) (*models.Conversation, error) {
+	if tenantID != "tenant-1" {
+		return nil, errors.New("conversation not found")
+	}
+	return &models.Conversation{ID: conversationID, TenantID: tenantID}, nil
+}
+
+func (f *fakeTimelineStorage) GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error) {
+	atomic.AddInt32(&f.loads, 1)
+	return f.messages, nil
+}
+
+func (f *fakeTimelineStorage) GetTransferHistory(tenantID, conversationID string) ([]*models.TransferEvent, error) {
+	return f.transfers, nil
+}
+
+func (f *fakeTimelineStorage) ListConversationModerationLogs(tenantID, conversationID string) ([]*postgres.AuditLog, error) {
+	return f.audit, f.auditErr
+}
+
+func TestTimelineBuildMergesSourcesChronologically(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	confidence := 0.92
+	messageID := "m2"
+	storage := &fakeTimelineStorage{
+		messages: []*models.Message{
+			{ID: "m1", Sender: "customer", Content: "Hi", Timestamp: start},
+			{ID: messageID, Sender: "agent", Content: "Hello!", Timestamp: start.Add(2 * time.Minute), IsAutoReply: true, SuggestionConfidence: &confidence},
+			{ID: "m3", Sender: "agent", Content: "Anything else?", Timestamp: start.Add(4 * time.Minute)},
+		},
+		transfers: []*models.TransferEvent{
+			{ToAgentID: "agent-2", TransferredBy: "admin-1", TransferredAt: start.Add(3 * time.Minute)},
+		},
+		audit: []*postgres.AuditLog{
+			{Action: "message.flagged", ResourceType: "message", ResourceID: &messageID, CreatedAt: start.Add(2 * time.Minute),
+				NewValue: postgres.AuditValue(map[string]interface{}{"conversation_id": "c1", "categories": []string{"pii"}})},
+		},
+	}
+
+	items, err := NewConversationTimelineService(storage, storage).Build("tenant-1", "c1")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	want := []struct{ typ, actor string }{
+		{TimelineMessage, "customer"},
+		{TimelineAutoReply, "auto_reply"},
+		{TimelineRuleViolation, "moderation"},
+		{TimelineAssignment, "admin-1"},
+		{TimelineMessage, "agent"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for i, w := range want {
+		if items[i].Type != w.typ || items[i].Actor != w.actor {
+			t.Errorf("item %d = %s/%s, want %s/%s", i, items[i].Type, items[i].Actor, w.typ, w.actor)
+		}
+	}
+
+	if msg, ok := items[1].Payload.(*models.Message); !ok || msg.SuggestionConfidence == nil || *msg.SuggestionConfidence != confidence {
+		t.Errorf("autoreply payload = %+v, want the message with its suggestion confidence", items[1].Payload)
+	}
+	payload, ok := items[2].Payload.(map[string]interface{})
+	if !ok || payload["action"] != "message.flagged" || payload["message_id"] != messageID || payload["categories"] == nil {
+		t.Errorf("rule violation payload = %+v", items[2].Payload)
+	}
+	if _, ok := payload["conversation_id"]; ok {
+		t.Error("rule violation payload should not repeat the conversation id")
+	}
+}
+
+func TestTimelineBuildCachesPerConversation(t *testing.T) {
+	storage := &fakeTimelineStorage{messages: []*models.Message{{ID: "m1", Sender: "customer", Timestamp: time.Now()}}}
+	service := NewConversationTimelineService(storage, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Build("tenant-1", "c1"); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+	}
+	if storage.loads != 1 {
+		t.Errorf("messages loaded %d times, want 1 (cached)", storage.loads)
+	}
+
+	if _, err := service.Build("tenant-1", "c2"); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if storage.loads != 2 {
+		t.Errorf("messages loaded %d times, want 2 (another conversation)", storage.loads)
+	}
+}
+
+func TestTimelineBuildErrors(t *testing.T) {
+	storage := &fakeTimelineStorage{auditErr: errors.New("db down")}
+	service := NewConversationTimelineService(storage, storage)
+
+	if _, err := service.Build("other-tenant", "c1"); err == nil || err.Error() != "conversation not found" {
+		t.Errorf("Build for another tenant = %v, want conversation not found", err)
+	}
+	if _, err := service.Build("tenant-1", "c1"); err == nil {
+		t.Error("Build should fail when a source fails")
+	}
+	// Failed builds are not cached
+	storage.auditErr = nil
+	if _, err := service.Build("tenant-1", "c1"); err != nil {
+		t.Errorf("Build after recovery: %v", err)
+	}
+}
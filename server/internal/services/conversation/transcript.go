@@ -0,0 +1,195 @@
+package conversation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// ErrInvalidRecipient is returned when a transcript recipient isn't a valid email address
+var ErrInvalidRecipient = errors.New("invalid recipient email address")
+
+// EmailSender delivers HTML email (to keep transcripts decoupled from SMTP)
+type EmailSender interface {
+	SendHTML(to []string, subject, htmlBody string) error
+}
+
+// TranscriptEmailService emails customers a copy of their conversation
+type TranscriptEmailService struct {
+	conversationStorage *postgres.ConversationStorage
+	productStorage      *postgres.ProductStorage
+	sender              EmailSender
+}
+
+// NewTranscriptEmailService creates a new transcript email service
+func NewTranscriptEmailService(
+	conversationStorage *postgres.ConversationStorage,
+	productStorage *postgres.ProductStorage,
+	sender EmailSender,
+) *TranscriptEmailService {
+	return &TranscriptEmailService{
+		conversationStorage: conversationStorage,
+		productStorage:      productStorage,
+		sender:              sender,
+	}
+}
+
+// transcriptMessage is a message as shown to the customer
+type transcriptMessage struct {
+	Sender    string // "customer" or "agent", also used as the CSS class
+	Label     string
+	Content   string
+	Timestamp string
+}
+
+// transcriptData is the data rendered into the transcript email
+type transcriptData struct {
+	From       string
+	To         string
+	Product    string
+	Resolution string
+	Messages   []transcriptMessage
+}
+
+// SendTranscript emails the conversation transcript to recipientEmail. A transcript is sent at most
+// once per conversation; later calls fail with postgres.ErrTranscriptAlreadySent.
+func (s *TranscriptEmailService) SendTranscript(tenantID, conversationID, recipientEmail string) error {
+	address, err := mail.ParseAddress(strings.TrimSpace(recipientEmail))
+	if err != nil {
+		return ErrInvalidRecipient
+	}
+
+	conv, err := s.conversationStorage.GetConversation(tenantID, conversationID)
+	if err != nil {
+		return err
+	}
+	messages, err := s.conversationStorage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("conversation has no messages")
+	}
+
+	body, err := renderTranscript(conv, s.productName(tenantID, conv), messages)
+	if err != nil {
+		return err
+	}
+
+	if err := s.conversationStorage.ClaimTranscriptSend(tenantID, conversationID, time.Now()); err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("Your conversation transcript (%s)", messages[0].Timestamp.Format("Jan 2, 2006"))
+	if err := s.sender.SendHTML([]string{address.Address}, subject, body); err != nil {
+		// Release the claim so the send can be retried
+		if releaseErr := s.conversationStorage.ReleaseTranscriptSend(tenantID, conversationID); releaseErr != nil {
+			log.Printf("[TRANSCRIPT] failed to release send claim conversation=%s error=%v", conversationID, releaseErr)
+		}
+		return err
+	}
+
+	log.Printf("[TRANSCRIPT] transcript sent conversation=%s tenant=%s messages=%d", conversationID, tenantID, len(messages))
+	return nil
+}
+
+// productName returns the name of the conversation's product, or "" if it has none
+func (s *TranscriptEmailService) productName(tenantID string, conv *models.Conversation) string {
+	if conv.ProductID == nil || s.productStorage == nil {
+		return ""
+	}
+	product, err := s.productStorage.GetProduct(tenantID, *conv.ProductID)
+	if err != nil {
+		log.Printf("[TRANSCRIPT] failed to load product, omitting from header product=%s error=%v", *conv.ProductID, err)
+		return ""
+	}
+	return product.Name
+}
+
+// renderTranscript formats messages as a customer-facing HTML transcript.
+// Only customer and agent messages are included; internal data such as analysis is never rendered.
+func renderTranscript(conv *models.Conversation, productName string, messages []*models.Message) (string, error) {
+	data := transcriptData{Product: productName}
+	if conv.ResolutionType != nil {
+		data.Resolution = resolutionLabel(*conv.ResolutionType)
+	}
+
+	for _, msg := range messages {
+		var label string
+		switch msg.Sender {
+		case "customer":
+			label = "You"
+		case "agent":
+			label = "Support"
+		default:
+			continue
+		}
+		data.Messages = append(data.Messages, transcriptMessage{
+			Sender:    msg.Sender,
+			Label:     label,
+			Content:   msg.Content,
+			Timestamp: msg.Timestamp.UTC().Format("Jan 2, 2006 15:04 MST"),
+		})
+	}
+	if len(data.Messages) > 0 {
+		data.From = data.Messages[0].Timestamp
+		data.To = data.Messages[len(data.Messages)-1].Timestamp
+	}
+
+	var buf bytes.Buffer
+	if err := transcriptTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render transcript: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// resolutionLabel turns a resolution type into customer-facing text
+func resolutionLabel(resolution string) string {
+	switch resolution {
+	case models.ResolutionDealWon:
+		return "Purchase completed"
+	case models.ResolutionDealLost:
+		return "Closed without purchase"
+	case models.ResolutionResolved:
+		return "Resolved"
+	case models.ResolutionAbandoned:
+		return "Closed"
+	}
+	return resolution
+}
+
+var transcriptTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<style>
+body { font-family: Arial, sans-serif; color: #222; }
+.header { border-bottom: 1px solid #ddd; margin-bottom: 16px; padding-bottom: 8px; }
+.header p { margin: 2px 0; color: #555; }
+.message { border-radius: 8px; margin: 8px 0; padding: 8px 12px; max-width: 80%; }
+.message.customer { background: #e8f0fe; margin-left: auto; }
+.message.agent { background: #f1f3f4; margin-right: auto; }
+.meta { color: #777; font-size: 12px; margin-bottom: 4px; }
+</style>
+</head>
+<body>
+<div class="header">
+<h2>Conversation transcript</h2>
+<p>{{.From}} &ndash; {{.To}}</p>
+{{if .Product}}<p>Product: {{.Product}}</p>{{end}}
+{{if .Resolution}}<p>Outcome: {{.Resolution}}</p>{{end}}
+</div>
+{{range .Messages}}<div class="message {{.Sender}}">
+<div class="meta">{{.Label}} &middot; {{.Timestamp}}</div>
+<div>{{.Content}}</div>
+</div>
+{{end}}</body>
+</html>
+`))
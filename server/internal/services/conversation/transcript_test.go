@@ -0,0 +1,42 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestRenderTranscriptFormatsCustomerView(t *testing.T) {
+	resolution := models.ResolutionResolved
+	conv := &models.Conversation{ID: "c1", ResolutionType: &resolution}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	messages := []*models.Message{
+		{Sender: "customer", Content: "Is <b>shipping</b> free?", Timestamp: start},
+		{Sender: "system", Content: "internal note", Timestamp: start.Add(time.Minute)},
+		{Sender: "agent", Content: "Yes, on orders over $50.", Timestamp: start.Add(2 * time.Minute)},
+	}
+
+	body, err := renderTranscript(conv, "Pro Plan", messages)
+	if err != nil {
+		t.Fatalf("renderTranscript: %v", err)
+	}
+
+	for _, want := range []string{
+		`class="message customer"`,
+		`class="message agent"`,
+		"Product: Pro Plan",
+		"Outcome: Resolved",
+		"Mar 1, 2026 10:00 UTC",
+		"Mar 1, 2026 10:02 UTC",
+		"Is &lt;b&gt;shipping&lt;/b&gt; free?",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("transcript missing %q", want)
+		}
+	}
+	if strings.Contains(body, "internal note") {
+		t.Error("transcript should only include customer and agent messages")
+	}
+}
@@ -0,0 +1,52 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// EventConversationTransferred is published after a conversation changes agents
+const EventConversationTransferred = "conversation.transferred"
+
+// TransferConversation hands a conversation to another agent and records the transfer
+func (s *IngestionService) TransferConversation(tenantID, conversationID, toAgentID, reason, transferredBy string) error {
+	event, err := s.conversationStorage.TransferConversation(tenantID, conversationID, toAgentID, reason, transferredBy)
+	if err != nil {
+		return fmt.Errorf("failed to transfer conversation: %w", err)
+	}
+
+	log.Printf("[TRANSFER] conversation=%s from=%s to=%s by=%s", conversationID, valueOrEmpty(event.FromAgentID), toAgentID, transferredBy)
+
+	s.publishEvent(tenantID, EventConversationTransferred, map[string]interface{}{
+		"conversation_id": conversationID,
+		"from_agent_id":   event.FromAgentID,
+		"to_agent_id":     toAgentID,
+		"reason":          reason,
+		"transferred_by":  transferredBy,
+		"transferred_at":  event.TransferredAt.UTC().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// GetTransferHistory returns the transfers of a conversation in chronological order
+func (s *IngestionService) GetTransferHistory(tenantID, conversationID string) ([]*models.TransferEvent, error) {
+	if _, err := s.conversationStorage.GetConversation(tenantID, conversationID); err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	events, err := s.conversationStorage.GetTransferHistory(tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer history: %w", err)
+	}
+	return events, nil
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,71 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+
+	"ai-conversation-platform/internal/models"
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// EventConversationWatchlisted is published when a conversation is added to the watchlist
+const EventConversationWatchlisted = "conversation.watchlisted"
+
+// SetWatchlistStorage enables the conversation watchlist (optional)
+func (s *IngestionService) SetWatchlistStorage(watchlistStorage *postgres.WatchlistStorage) {
+	s.watchlistStorage = watchlistStorage
+}
+
+// AddToWatchlist flags a conversation for executive monitoring and publishes conversation.watchlisted
+func (s *IngestionService) AddToWatchlist(tenantID, conversationID, addedBy string, priority int, notes *string) (*postgres.WatchlistEntry, error) {
+	if s.watchlistStorage == nil {
+		return nil, fmt.Errorf("watchlist is not enabled")
+	}
+	if _, err := s.conversationStorage.GetConversation(tenantID, conversationID); err != nil {
+		return nil, fmt.Errorf("conversation not found")
+	}
+
+	entry := &postgres.WatchlistEntry{
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		AddedBy:        addedBy,
+		Priority:       priority,
+		Notes:          notes,
+	}
+	if err := s.watchlistStorage.AddToWatchlist(entry); err != nil {
+		return nil, err
+	}
+	log.Printf("[WATCHLIST] conversation added tenant=%s conversation=%s by=%s priority=%d", tenantID, conversationID, addedBy, entry.Priority)
+
+	s.publishEvent(tenantID, EventConversationWatchlisted, map[string]interface{}{
+		"conversation_id": conversationID,
+		"added_by":        addedBy,
+		"priority":        entry.Priority,
+	})
+	return entry, nil
+}
+
+// RemoveFromWatchlist stops monitoring a conversation
+func (s *IngestionService) RemoveFromWatchlist(tenantID, conversationID string) error {
+	if s.watchlistStorage == nil {
+		return fmt.Errorf("watchlist is not enabled")
+	}
+	removed, err := s.watchlistStorage.RemoveFromWatchlist(tenantID, conversationID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("conversation not found on watchlist")
+	}
+	log.Printf("[WATCHLIST] conversation removed tenant=%s conversation=%s", tenantID, conversationID)
+	return nil
+}
+
+// ListWatchlistedConversations lists a tenant's watchlisted conversations with pagination
+func (s *IngestionService) ListWatchlistedConversations(tenantID string, limit, offset int) ([]*models.Conversation, error) {
+	conversations, err := s.conversationStorage.ListWatchlistedConversations(tenantID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlisted conversations: %w", err)
+	}
+	return conversations, nil
+}
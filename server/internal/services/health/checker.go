@@ -0,0 +1,219 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// checkTimeout bounds all dependency checks together
+	checkTimeout = 2 * time.Second
+	// chromaCountCollection is the collection whose size is reported for Chroma
+	chromaCountCollection = "product_knowledge"
+)
+
+// Overall and component statuses
+const (
+	StatusOK        = "ok"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+
+	ComponentHealthy   = "healthy"
+	ComponentUnhealthy = "unhealthy"
+	ComponentDisabled  = "disabled" // Not configured in this deployment
+)
+
+// DatabasePinger checks the primary database
+type DatabasePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// VectorStore checks Chroma and reports its size
+type VectorStore interface {
+	HealthCheckContext(ctx context.Context) error
+	CountDocuments(ctx context.Context, collection string) (int, error)
+}
+
+// CollectionChecker reports Chroma collections that are missing (see ai.EmbeddingService)
+type CollectionChecker interface {
+	HealthCheck(ctx context.Context) ([]string, error)
+}
+
+// ModelAPI checks the Gemini API
+type ModelAPI interface {
+	HealthCheckContext(ctx context.Context) error
+}
+
+// CacheServer checks Redis
+type CacheServer interface {
+	HealthCheckContext(ctx context.Context) error
+}
+
+// ComponentStatus is the result of checking one dependency
+type ComponentStatus struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	DocCount  *int   `json:"doc_count,omitempty"`
+	// MissingCollections lists Chroma collections that don't exist; recreate them with
+	// GET /api/admin/ai/health
+	MissingCollections []string `json:"missing_collections,omitempty"`
+}
+
+// Report is the health of the service and each of its dependencies
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Checker checks the service's dependencies. Chroma, Gemini and Redis are optional.
+type Checker struct {
+	db          DatabasePinger
+	chroma      VectorStore
+	collections CollectionChecker
+	gemini      ModelAPI
+	redis       CacheServer
+}
+
+// NewChecker creates a health checker for the database
+func NewChecker(db DatabasePinger) *Checker {
+	return &Checker{db: db}
+}
+
+// SetVectorStore sets the Chroma client to check
+func (c *Checker) SetVectorStore(chroma VectorStore) {
+	c.chroma = chroma
+}
+
+// SetCollectionChecker also checks that Chroma's collections exist (optional)
+func (c *Checker) SetCollectionChecker(collections CollectionChecker) {
+	c.collections = collections
+}
+
+// SetModelAPI sets the Gemini client to check
+func (c *Checker) SetModelAPI(gemini ModelAPI) {
+	c.gemini = gemini
+}
+
+// SetCacheServer sets the Redis cache to check (optional)
+func (c *Checker) SetCacheServer(redis CacheServer) {
+	c.redis = redis
+}
+
+// Ready reports whether the database is reachable, the one dependency requests can't be served without
+func (c *Checker) Ready(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	return c.db.Ping(ctx)
+}
+
+// Check checks every dependency in parallel within a 2 second deadline.
+// The service is unhealthy without PostgreSQL and degraded without Chroma or Gemini, or with an
+// unreachable Redis.
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		components = make(map[string]ComponentStatus)
+	)
+	run := func(name string, check func(ctx context.Context) ComponentStatus) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := check(ctx)
+			mu.Lock()
+			components[name] = status
+			mu.Unlock()
+		}()
+	}
+
+	run("postgres", func(ctx context.Context) ComponentStatus {
+		return timed("postgres", func() error { return c.db.Ping(ctx) })
+	})
+	run("chroma", c.checkChroma)
+	run("gemini", func(ctx context.Context) ComponentStatus {
+		if c.gemini == nil {
+			return ComponentStatus{Status: ComponentDisabled}
+		}
+		return timed("gemini", func() error { return c.gemini.HealthCheckContext(ctx) })
+	})
+	run("redis", func(ctx context.Context) ComponentStatus {
+		// Without REDIS_URL responses are cached in memory; reported so dashboards see every known component
+		if c.redis == nil {
+			return ComponentStatus{Status: ComponentDisabled}
+		}
+		return timed("redis", func() error { return c.redis.HealthCheckContext(ctx) })
+	})
+	wg.Wait()
+
+	return Report{Status: overallStatus(components), Components: components}
+}
+
+// checkChroma checks Chroma and its collections, and counts the product knowledge documents.
+// Chroma is unhealthy when a collection is missing, since queries against it fail.
+func (c *Checker) checkChroma(ctx context.Context) ComponentStatus {
+	if c.chroma == nil {
+		return ComponentStatus{Status: ComponentDisabled}
+	}
+	var count int
+	var missing []string
+	status := timed("chroma", func() error {
+		if err := c.chroma.HealthCheckContext(ctx); err != nil {
+			return err
+		}
+		if c.collections != nil {
+			var err error
+			if missing, err = c.collections.HealthCheck(ctx); err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing collections: %v", missing)
+			}
+		}
+		var err error
+		count, err = c.chroma.CountDocuments(ctx, chromaCountCollection)
+		if err != nil {
+			// Reachable but the collection may not exist yet
+			log.Printf("[HEALTH] failed to count chroma documents: %v", err)
+			count = 0
+		}
+		return nil
+	})
+	if status.Status == ComponentHealthy {
+		status.DocCount = &count
+	}
+	status.MissingCollections = missing
+	return status
+}
+
+// timed runs check and reports its outcome and latency. Errors are logged rather than
+// returned to the caller since they can contain connection details.
+func timed(name string, check func() error) ComponentStatus {
+	start := time.Now()
+	err := check()
+	status := ComponentStatus{Status: ComponentHealthy, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		log.Printf("[HEALTH] %s check failed: %v", name, err)
+		status.Status = ComponentUnhealthy
+	}
+	return status
+}
+
+// overallStatus derives the service status from its components
+func overallStatus(components map[string]ComponentStatus) string {
+	if components["postgres"].Status != ComponentHealthy {
+		return StatusUnhealthy
+	}
+	if components["chroma"].Status != ComponentHealthy || components["gemini"].Status != ComponentHealthy {
+		return StatusDegraded
+	}
+	if components["redis"].Status == ComponentUnhealthy {
+		return StatusDegraded
+	}
+	return StatusOK
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeDB struct{ err error }
+
+func (f fakeDB) Ping(ctx context.Context) error { return f.err }
+
+type fakeChroma struct {
+	err   error
+	count int
+}
+
+func (f fakeChroma) HealthCheckContext(ctx context.Context) error { return f.err }
+
+func (f fakeChroma) CountDocuments(ctx context.Context, collection string) (int, error) {
+	return f.count, nil
+}
+
+type slowGemini struct{}
+
+func (slowGemini) HealthCheckContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil
+	}
+}
+
+type fakeGemini struct{ err error }
+
+func (f fakeGemini) HealthCheckContext(ctx context.Context) error { return f.err }
+
+func TestCheckAllHealthy(t *testing.T) {
+	checker := NewChecker(fakeDB{})
+	checker.SetVectorStore(fakeChroma{count: 42})
+	checker.SetModelAPI(fakeGemini{})
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusOK {
+		t.Errorf("status = %s, want %s", report.Status, StatusOK)
+	}
+	chroma := report.Components["chroma"]
+	if chroma.Status != ComponentHealthy || chroma.DocCount == nil || *chroma.DocCount != 42 {
+		t.Errorf("chroma = %+v, want healthy with 42 documents", chroma)
+	}
+	if report.Components["redis"].Status != ComponentDisabled {
+		t.Errorf("redis = %+v, want disabled", report.Components["redis"])
+	}
+}
+
+func TestCheckDegradedWithoutOptionalDependencies(t *testing.T) {
+	checker := NewChecker(fakeDB{})
+	checker.SetVectorStore(fakeChroma{err: errors.New("connection refused")})
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusDegraded {
+		t.Errorf("status = %s, want %s", report.Status, StatusDegraded)
+	}
+	if report.Components["chroma"].Status != ComponentUnhealthy {
+		t.Errorf("chroma = %+v, want unhealthy", report.Components["chroma"])
+	}
+	if report.Components["gemini"].Status != ComponentDisabled {
+		t.Errorf("gemini = %+v, want disabled", report.Components["gemini"])
+	}
+}
+
+func TestCheckDegradedWithUnreachableRedis(t *testing.T) {
+	checker := NewChecker(fakeDB{})
+	checker.SetVectorStore(fakeChroma{})
+	checker.SetModelAPI(fakeGemini{})
+	checker.SetCacheServer(fakeGemini{err: errors.New("connection refused")})
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusDegraded {
+		t.Errorf("status = %s, want %s", report.Status, StatusDegraded)
+	}
+	if report.Components["redis"].Status != ComponentUnhealthy {
+		t.Errorf("redis = %+v, want unhealthy", report.Components["redis"])
+	}
+}
+
+func TestCheckUnhealthyWithoutDatabase(t *testing.T) {
+	checker := NewChecker(fakeDB{err: errors.New("connection refused")})
+	checker.SetVectorStore(fakeChroma{})
+	checker.SetModelAPI(fakeGemini{})
+
+	if report := checker.Check(context.Background()); report.Status != StatusUnhealthy {
+		t.Errorf("status = %s, want %s", report.Status, StatusUnhealthy)
+	}
+	if err := checker.Ready(context.Background()); err == nil {
+		t.Error("expected Ready to fail without the database")
+	}
+}
+
+func TestCheckTimesOutSlowDependencies(t *testing.T) {
+	checker := NewChecker(fakeDB{})
+	checker.SetVectorStore(fakeChroma{})
+	checker.SetModelAPI(slowGemini{})
+
+	start := time.Now()
+	report := checker.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > checkTimeout+time.Second {
+		t.Errorf("Check took %s, want it bounded by %s", elapsed, checkTimeout)
+	}
+	if report.Components["gemini"].Status != ComponentUnhealthy {
+		t.Errorf("gemini = %+v, want unhealthy after timeout", report.Components["gemini"])
+	}
+	if report.Status != StatusDegraded {
+		t.Errorf("status = %s, want %s", report.Status, StatusDegraded)
+	}
+}
+
+type fakeCollections struct{ missing []string }
+
+func (f fakeCollections) HealthCheck(ctx context.Context) ([]string, error) { return f.missing, nil }
+
+func TestCheckReportsMissingCollections(t *testing.T) {
+	checker := NewChecker(fakeDB{})
+	checker.SetVectorStore(fakeChroma{count: 3})
+	checker.SetCollectionChecker(fakeCollections{missing: []string{"conversation_context"}})
+	checker.SetModelAPI(fakeGemini{})
+
+	report := checker.Check(context.Background())
+	chroma := report.Components["chroma"]
+	if chroma.Status != ComponentUnhealthy || len(chroma.MissingCollections) != 1 || chroma.MissingCollections[0] != "conversation_context" {
+		t.Errorf("chroma = %+v, want unhealthy with conversation_context missing", chroma)
+	}
+	if report.Status != StatusDegraded {
+		t.Errorf("status = %s, want %s", report.Status, StatusDegraded)
+	}
+
+	checker.SetCollectionChecker(fakeCollections{})
+	if chroma := checker.Check(context.Background()).Components["chroma"]; chroma.Status != ComponentHealthy || chroma.MissingCollections != nil {
+		t.Errorf("chroma = %+v, want healthy once collections exist", chroma)
+	}
+}
@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// productKnowledgeCollection holds product and knowledge article chunks (see ai.ContentTypeProductKnowledge)
+const productKnowledgeCollection = "product_knowledge"
+
+// sharedVectorCollections hold several tenants' documents, tagged with tenant_id metadata
+var sharedVectorCollections = []string{
+	productKnowledgeCollection,
+	chroma.ConversationContextCollection,
+	chroma.MessageIndexCollection,
+}
+
+// DataStorage deletes a tenant's database rows
+type DataStorage interface {
+	DeleteTenantData(tenantID string) (map[string]int64, error)
+}
+
+// VectorStore deletes a tenant's embeddings (see chroma.Client)
+type VectorStore interface {
+	DeleteCollection(name string) error
+	DeleteWhere(collection string, where map[string]interface{}) error
+}
+
+// DeletionReport describes what a tenant data deletion removed
+type DeletionReport struct {
+	TenantID          string           `json:"tenant_id"`
+	Tables            map[string]int64 `json:"tables"` // Rows deleted per table
+	TotalRows         int64            `json:"total_rows"`
+	VectorCollections []string         `json:"vector_collections"` // Collections the tenant's embeddings were removed from
+	DeletedAt         time.Time        `json:"deleted_at"`
+}
+
+// DataDeletionService erases all of a tenant's data (GDPR right to erasure)
+type DataDeletionService struct {
+	storage DataStorage
+	vectors VectorStore
+}
+
+// NewDataDeletionService creates a new data deletion service
+func NewDataDeletionService(storage DataStorage) *DataDeletionService {
+	return &DataDeletionService{storage: storage}
+}
+
+// SetVectorStore also deletes the tenant's embeddings (optional)
+func (s *DataDeletionService) SetVectorStore(vectors VectorStore) {
+	s.vectors = vectors
+}
+
+// DeleteTenantData permanently deletes the tenant's database rows in one transaction, then its
+// embeddings. When the embeddings can't be deleted the rows are already gone: the report is
+// returned with the error, and the deletion can be retried.
+func (s *DataDeletionService) DeleteTenantData(tenantID string) (*DeletionReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tables, err := s.storage.DeleteTenantData(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tenant data: %w", err)
+	}
+	report := &DeletionReport{
+		TenantID:          tenantID,
+		Tables:            tables,
+		VectorCollections: []string{},
+		DeletedAt:         time.Now().UTC(),
+	}
+	for _, n := range tables {
+		report.TotalRows += n
+	}
+	log.Printf("[DATA_DELETION] deleted tenant data tenant=%s rows=%d", tenantID, report.TotalRows)
+
+	if s.vectors == nil {
+		return report, nil
+	}
+	if err := s.vectors.DeleteCollection(tenantID + "_" + productKnowledgeCollection); err != nil {
+		return report, fmt.Errorf("failed to delete product knowledge collection: %w", err)
+	}
+	report.VectorCollections = append(report.VectorCollections, tenantID+"_"+productKnowledgeCollection)
+	for _, collection := range sharedVectorCollections {
+		if err := s.vectors.DeleteWhere(collection, map[string]interface{}{"tenant_id": tenantID}); err != nil {
+			return report, fmt.Errorf("failed to delete %s embeddings: %w", collection, err)
+		}
+		report.VectorCollections = append(report.VectorCollections, collection)
+	}
+	log.Printf("[DATA_DELETION] deleted tenant embeddings tenant=%s collections=%v", tenantID, report.VectorCollections)
+	return report, nil
+}
@@ -0,0 +1,142 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"ai-conversation-platform/internal/storage/chroma"
+)
+
+// fakeDataStorage deletes rows from in-memory tables keyed by tenant
+type fakeDataStorage struct {
+	rows map[string]map[string]int64 // tenant -> table -> rows
+	err  error
+}
+
+func (f *fakeDataStorage) DeleteTenantData(tenantID string) (map[string]int64, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	deleted := f.rows[tenantID]
+	delete(f.rows, tenantID)
+	if deleted == nil {
+		deleted = map[string]int64{}
+	}
+	return deleted, nil
+}
+
+// chromaRequest is a request received by the fake Chroma server
+type chromaRequest struct {
+	method string
+	path   string
+	body   map[string]interface{}
+}
+
+// newFakeChroma serves the Chroma REST API, answering with status, and records requests
+func newFakeChroma(t *testing.T, status int) (*chroma.Client, func() []chromaRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []chromaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := chromaRequest{method: r.Method, path: r.URL.Path}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			json.Unmarshal(data, &req.body)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("CHROMA_URL", server.URL)
+	t.Setenv("TENANT_ID", "")
+	client, err := chroma.NewClient()
+	if err != nil {
+		t.Fatalf("chroma.NewClient: %v", err)
+	}
+	return client, func() []chromaRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]chromaRequest(nil), requests...)
+	}
+}
+
+func TestDeleteTenantDataReportsRowsAndDeletesEmbeddings(t *testing.T) {
+	storage := &fakeDataStorage{rows: map[string]map[string]int64{
+		"tenant-1": {"conversations": 3, "messages": 12, "users": 2},
+		"tenant-2": {"conversations": 1},
+	}}
+	vectors, requests := newFakeChroma(t, http.StatusOK)
+	service := NewDataDeletionService(storage)
+	service.SetVectorStore(vectors)
+
+	report, err := service.DeleteTenantData("tenant-1")
+	if err != nil {
+		t.Fatalf("DeleteTenantData: %v", err)
+	}
+	if report.TenantID != "tenant-1" || report.TotalRows != 17 || report.Tables["messages"] != 12 {
+		t.Errorf("report = %+v, want 17 rows for tenant-1", report)
+	}
+	if _, ok := storage.rows["tenant-2"]; !ok {
+		t.Error("another tenant's data was deleted")
+	}
+
+	got := requests()
+	if len(got) != 4 {
+		t.Fatalf("chroma requests = %+v, want collection delete and three document deletes", got)
+	}
+	if got[0].method != http.MethodDelete || got[0].path != "/api/v1/collections/default_tenant-1_product_knowledge" {
+		t.Errorf("first request = %s %s, want the tenant's product knowledge collection deleted", got[0].method, got[0].path)
+	}
+	for i, collection := range []string{"default_product_knowledge", "default_conversation_context", "default_message_index"} {
+		req := got[i+1]
+		where, _ := req.body["where"].(map[string]interface{})
+		if req.path != "/api/v1/collections/"+collection+"/delete" || where["tenant_id"] != "tenant-1" {
+			t.Errorf("request %d = %s %v, want tenant-1 documents deleted from %s", i+2, req.path, req.body, collection)
+		}
+	}
+	if len(report.VectorCollections) != 4 {
+		t.Errorf("vector collections = %v, want 4", report.VectorCollections)
+	}
+}
+
+func TestDeleteTenantDataMissingCollectionSucceeds(t *testing.T) {
+	vectors, _ := newFakeChroma(t, http.StatusNotFound)
+	service := NewDataDeletionService(&fakeDataStorage{})
+	service.SetVectorStore(vectors)
+
+	if _, err := service.DeleteTenantData("tenant-1"); err != nil {
+		t.Errorf("DeleteTenantData with no collections: %v", err)
+	}
+}
+
+func TestDeleteTenantDataVectorFailureReturnsReport(t *testing.T) {
+	vectors, _ := newFakeChroma(t, http.StatusInternalServerError)
+	storage := &fakeDataStorage{rows: map[string]map[string]int64{"tenant-1": {"users": 1}}}
+	service := NewDataDeletionService(storage)
+	service.SetVectorStore(vectors)
+
+	report, err := service.DeleteTenantData("tenant-1")
+	if err == nil {
+		t.Fatal("expected an error when Chroma fails")
+	}
+	if report == nil || report.TotalRows != 1 || len(report.VectorCollections) != 0 {
+		t.Errorf("report = %+v, want the deleted rows and no collections", report)
+	}
+}
+
+func TestDeleteTenantDataStorageFailure(t *testing.T) {
+	service := NewDataDeletionService(&fakeDataStorage{err: errors.New("database unavailable")})
+	if report, err := service.DeleteTenantData("tenant-1"); err == nil || report != nil {
+		t.Errorf("DeleteTenantData = %+v, %v; want an error and no report", report, err)
+	}
+	if _, err := service.DeleteTenantData(""); err == nil {
+		t.Error("expected an error for an empty tenant ID")
+	}
+}
@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-Webhook-Signature" // "sha256=" + hex HMAC-SHA256 of the body, keyed with the webhook secret
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery" // Same for every attempt of a delivery, so receivers can deduplicate
+)
+
+const (
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = time.Second
+)
+
+// SupportedEvents are the events webhooks can subscribe to
+var SupportedEvents = []string{
+	"conversation.created",
+	"conversation.closed",
+	"conversation.transferred",
+	"conversation.merged",
+	"conversation.watchlisted",
+	"conversation.escalated",
+	"message.created",
+	"message.read",
+	"message.flagged",
+	"pricing.approved",
+}
+
+// IsSupportedEvent reports whether event can be subscribed to
+func IsSupportedEvent(event string) bool {
+	for _, e := range SupportedEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookStorage loads the webhooks an event is delivered to
+type WebhookStorage interface {
+	ListActiveWebhooks(tenantID, event string) ([]*postgres.Webhook, error)
+}
+
+// PayloadMapper renames payload fields for a tenant's CRM (see crm.FieldMapper)
+type PayloadMapper interface {
+	ApplyForTenant(tenantID, crmType string, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Envelope is the JSON body POSTed to a webhook
+type Envelope struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	TenantID  string      `json:"tenant_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Dispatcher delivers events to the tenant's subscribed webhooks. Deliveries run in the
+// background; failed requests are retried with exponential backoff.
+type Dispatcher struct {
+	storage      WebhookStorage
+	mapper       PayloadMapper
+	httpClient   *http.Client
+	maxAttempts  int
+	retryBackoff time.Duration
+	pending      sync.WaitGroup
+}
+
+// NewDispatcher creates a webhook dispatcher
+func NewDispatcher(storage WebhookStorage) *Dispatcher {
+	return &Dispatcher{
+		storage:      storage,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		maxAttempts:  defaultMaxAttempts,
+		retryBackoff: defaultRetryBackoff,
+	}
+}
+
+// SetPayloadMapper applies tenant CRM field mappings to webhooks with a CRM type (optional)
+func (d *Dispatcher) SetPayloadMapper(mapper PayloadMapper) {
+	d.mapper = mapper
+}
+
+// SetRetryBackoff sets the wait before the first retry; doubled for each further retry
+func (d *Dispatcher) SetRetryBackoff(backoff time.Duration) {
+	d.retryBackoff = backoff
+}
+
+// Publish implements the services' EventPublisher interface
+func (d *Dispatcher) Publish(tenantID, eventType string, payload map[string]interface{}) {
+	d.Dispatch(tenantID, eventType, payload)
+}
+
+// Dispatch delivers an event to every active webhook of the tenant subscribed to it. It returns
+// once the deliveries are started; use Wait to wait for them.
+func (d *Dispatcher) Dispatch(tenantID, event string, payload interface{}) {
+	hooks, err := d.storage.ListActiveWebhooks(tenantID, event)
+	if err != nil {
+		log.Printf("[WEBHOOK] failed to load webhooks tenant=%s event=%s error=%v", tenantID, event, err)
+		return
+	}
+
+	for _, hook := range hooks {
+		envelope := Envelope{
+			ID:        uuid.New().String(),
+			Event:     event,
+			TenantID:  tenantID,
+			CreatedAt: time.Now().UTC(),
+			Data:      d.mapPayload(tenantID, hook, payload),
+		}
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			log.Printf("[WEBHOOK] failed to encode payload webhook=%s event=%s error=%v", hook.ID, event, err)
+			continue
+		}
+
+		d.pending.Add(1)
+		go func(hook *postgres.Webhook, deliveryID string) {
+			defer d.pending.Done()
+			if err := d.deliver(hook, event, deliveryID, body); err != nil {
+				log.Printf("[WEBHOOK] delivery failed webhook=%s event=%s delivery=%s error=%v", hook.ID, event, deliveryID, err)
+			}
+		}(hook, envelope.ID)
+	}
+}
+
+// Wait blocks until every started delivery has succeeded or given up
+func (d *Dispatcher) Wait() {
+	d.pending.Wait()
+}
+
+// mapPayload renames payload fields for webhooks that feed a CRM. Payloads that aren't maps, or
+// whose mapping can't be loaded, are sent unchanged.
+func (d *Dispatcher) mapPayload(tenantID string, hook *postgres.Webhook, payload interface{}) interface{} {
+	fields, ok := payload.(map[string]interface{})
+	if !ok || d.mapper == nil || hook.CRMType == nil {
+		return payload
+	}
+	mapped, err := d.mapper.ApplyForTenant(tenantID, *hook.CRMType, fields)
+	if err != nil {
+		log.Printf("[WEBHOOK] crm field mapping skipped webhook=%s error=%v", hook.ID, err)
+		return payload
+	}
+	return mapped
+}
+
+// deliver POSTs body to the webhook, retrying network errors, 429s and 5xx responses
+func (d *Dispatcher) deliver(hook *postgres.Webhook, event, deliveryID string, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		var retryable bool
+		retryable, err = d.post(hook, event, deliveryID, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt == d.maxAttempts {
+			break
+		}
+		backoff := d.retryBackoff << (attempt - 1)
+		log.Printf("[WEBHOOK] delivery failed, retrying webhook=%s attempt=%d backoff=%s error=%v", hook.ID, attempt, backoff, err)
+		time.Sleep(backoff)
+	}
+	return err
+}
+
+// post sends one delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(hook *postgres.Webhook, event, deliveryID string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryID)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by the hex HMAC-SHA256
+// of body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret for a new webhook
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
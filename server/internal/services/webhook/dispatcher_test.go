@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/storage/postgres"
+)
+
+// fakeWebhookStorage returns the webhooks subscribed to an event, like the real storage
+type fakeWebhookStorage struct {
+	hooks []*postgres.Webhook
+	err   error
+}
+
+func (f *fakeWebhookStorage) ListActiveWebhooks(tenantID, event string) ([]*postgres.Webhook, error) {
+	var matched []*postgres.Webhook
+	for _, hook := range f.hooks {
+		if hook.TenantID == tenantID && hook.IsActive && hook.Subscribes(event) {
+			matched = append(matched, hook)
+		}
+	}
+	return matched, f.err
+}
+
+// delivery is a request received by the test receiver
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver records deliveries, answering with statuses in order and then 200
+type receiver struct {
+	mu         sync.Mutex
+	deliveries []delivery
+	statuses   []int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusOK
+	if n := len(r.deliveries); n < len(r.statuses) {
+		status = r.statuses[n]
+	}
+	r.deliveries = append(r.deliveries, delivery{header: req.Header.Clone(), body: body})
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() []delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]delivery(nil), r.deliveries...)
+}
+
+func newTestDispatcher(t *testing.T, statuses ...int) (*Dispatcher, *receiver, *postgres.Webhook) {
+	t.Helper()
+	recv := &receiver{statuses: statuses}
+	server := httptest.NewServer(recv)
+	t.Cleanup(server.Close)
+
+	hook := &postgres.Webhook{ID: "wh-1", TenantID: "tenant-1", URL: server.URL, Secret: "s3cret", Events: []string{"message.created"}, IsActive: true}
+	dispatcher := NewDispatcher(&fakeWebhookStorage{hooks: []*postgres.Webhook{hook}})
+	dispatcher.SetRetryBackoff(time.Millisecond)
+	return dispatcher, recv, hook
+}
+
+func TestDispatchSignsBody(t *testing.T) {
+	dispatcher, recv, _ := newTestDispatcher(t)
+
+	dispatcher.Dispatch("tenant-1", "message.created", map[string]interface{}{"message_id": "m1"})
+	dispatcher.Wait()
+
+	got := recv.received()
+	if len(got) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(got))
+	}
+	d := got[0]
+	if want := Sign("s3cret", d.body); !hmac.Equal([]byte(d.header.Get(SignatureHeader)), []byte(want)) {
+		t.Errorf("signature = %q, want %q", d.header.Get(SignatureHeader), want)
+	}
+	if Sign("wrong", d.body) == d.header.Get(SignatureHeader) {
+		t.Error("signature should depend on the secret")
+	}
+	if d.header.Get(EventHeader) != "message.created" || d.header.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", d.header)
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(d.body, &envelope); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	data, _ := envelope.Data.(map[string]interface{})
+	if envelope.Event != "message.created" || envelope.TenantID != "tenant-1" || data["message_id"] != "m1" {
+		t.Errorf("envelope = %+v", envelope)
+	}
+	if envelope.ID == "" || envelope.ID != d.header.Get(DeliveryHeader) {
+		t.Errorf("delivery id = %q, header %q; want the same non-empty id", envelope.ID, d.header.Get(DeliveryHeader))
+	}
+}
+
+func TestSignKnownValue(t *testing.T) {
+	// echo -n '{"event":"test"}' | openssl dgst -sha256 -hmac key
+	want := "sha256=b524d1c679ac9958794a322458b779af498bc20603c6b49807f8f77e092c41c7"
+	if got := Sign("key", []byte(`{"event":"test"}`)); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestDispatchRetriesServerErrors(t *testing.T) {
+	dispatcher, recv, _ := newTestDispatcher(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	dispatcher.Dispatch("tenant-1", "message.created", map[string]interface{}{"message_id": "m1"})
+	dispatcher.Wait()
+
+	got := recv.received()
+	if len(got) != 3 {
+		t.Fatalf("attempts = %d, want 3 (two failures, then success)", len(got))
+	}
+	// Every attempt carries the same body, signature and delivery id
+	for i, d := range got[1:] {
+		if string(d.body) != string(got[0].body) || d.header.Get(SignatureHeader) != got[0].header.Get(SignatureHeader) {
+			t.Errorf("attempt %d differs from the first", i+2)
+		}
+		if d.header.Get(DeliveryHeader) != got[0].header.Get(DeliveryHeader) {
+			t.Errorf("attempt %d delivery id = %q, want %q", i+2, d.header.Get(DeliveryHeader), got[0].header.Get(DeliveryHeader))
+		}
+	}
+}
+
+func TestDispatchGivesUpAfterThreeAttempts(t *testing.T) {
+	dispatcher, recv, _ := newTestDispatcher(t, 500, 502, 503, 504)
+
+	dispatcher.Dispatch("tenant-1", "message.created", nil)
+	dispatcher.Wait()
+
+	if got := len(recv.received()); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDispatchDoesNotRetryClientErrors(t *testing.T) {
+	dispatcher, recv, _ := newTestDispatcher(t, http.StatusBadRequest)
+
+	dispatcher.Dispatch("tenant-1", "message.created", nil)
+	dispatcher.Wait()
+
+	if got := len(recv.received()); got != 1 {
+		t.Errorf("attempts = %d, want 1 for a 400", got)
+	}
+}
+
+func TestDispatchBacksOffExponentially(t *testing.T) {
+	dispatcher, recv, _ := newTestDispatcher(t, 500, 500)
+	dispatcher.SetRetryBackoff(20 * time.Millisecond)
+
+	start := time.Now()
+	dispatcher.Dispatch("tenant-1", "message.created", nil)
+	dispatcher.Wait()
+
+	if got := len(recv.received()); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+	// 20ms before the second attempt, 40ms before the third
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("elapsed = %s, want at least 60ms of backoff", elapsed)
+	}
+}
+
+func TestDispatchOnlyToSubscribedActiveWebhooks(t *testing.T) {
+	dispatcher, recv, hook := newTestDispatcher(t)
+
+	dispatcher.Dispatch("tenant-1", "conversation.closed", nil)
+	dispatcher.Dispatch("tenant-2", "message.created", nil)
+	hook.IsActive = false
+	dispatcher.Dispatch("tenant-1", "message.created", nil)
+	dispatcher.Wait()
+
+	if got := len(recv.received()); got != 0 {
+		t.Errorf("deliveries = %d, want none", got)
+	}
+}
+
+func TestDispatchStorageErrorSendsNothing(t *testing.T) {
+	dispatcher := NewDispatcher(&fakeWebhookStorage{err: errors.New("database unavailable")})
+	dispatcher.Dispatch("tenant-1", "message.created", nil)
+	dispatcher.Wait()
+}
+
+// renamingMapper renames customer_id for the given CRM
+type renamingMapper struct{ crmType string }
+
+func (m renamingMapper) ApplyForTenant(tenantID, crmType string, payload map[string]interface{}) (map[string]interface{}, error) {
+	if crmType != m.crmType {
+		return payload, nil
+	}
+	mapped := map[string]interface{}{}
+	for k, v := range payload {
+		if k == "customer_id" {
+			k = "contact_id"
+		}
+		mapped[k] = v
+	}
+	return mapped, nil
+}
+
+func TestDispatchAppliesCRMFieldMapping(t *testing.T) {
+	dispatcher, recv, hook := newTestDispatcher(t)
+	dispatcher.SetPayloadMapper(renamingMapper{crmType: "hubspot"})
+	crmType := "hubspot"
+	hook.CRMType = &crmType
+
+	dispatcher.Dispatch("tenant-1", "message.created", map[string]interface{}{"customer_id": "cust-1"})
+	dispatcher.Wait()
+
+	got := recv.received()
+	if len(got) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(got))
+	}
+	var envelope struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(got[0].body, &envelope)
+	if envelope.Data["contact_id"] != "cust-1" || envelope.Data["customer_id"] != nil {
+		t.Errorf("data = %v, want customer_id renamed to contact_id", envelope.Data)
+	}
+}
+
+func TestIsSupportedEvent(t *testing.T) {
+	for _, event := range []string{"message.created", "conversation.created", "conversation.closed"} {
+		if !IsSupportedEvent(event) {
+			t.Errorf("IsSupportedEvent(%q) = false", event)
+		}
+	}
+	if IsSupportedEvent("conversation.deleted") || IsSupportedEvent("") {
+		t.Error("unknown events should not be supported")
+	}
+}
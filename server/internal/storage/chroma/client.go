@@ -2,7 +2,9 @@ package chroma
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +12,9 @@ import (
 	"time"
 )
 
+// ErrCollectionNotFound is returned by GetCollection when the collection doesn't exist
+var ErrCollectionNotFound = errors.New("collection not found")
+
 // Client represents a Chroma DB REST API client
 type Client struct {
 	baseURL  string
@@ -38,8 +43,17 @@ func NewClient() (*Client, error) {
 
 // HealthCheck checks if Chroma DB is accessible
 func (c *Client) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext checks if Chroma DB is accessible, giving up when ctx is done
+func (c *Client) HealthCheckContext(ctx context.Context) error {
 	url := fmt.Sprintf("%s/api/v2/heartbeat", c.baseURL)
-	resp, err := c.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("chroma health check failed: %w", err)
 	}
@@ -52,6 +66,31 @@ func (c *Client) HealthCheck() error {
 	return nil
 }
 
+// CountDocuments returns the number of documents in a collection
+func (c *Client) CountDocuments(ctx context.Context, name string) (int, error) {
+	url := fmt.Sprintf("%s/api/v1/collections/%s/count", c.baseURL, c.getCollectionName(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to count documents: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var count int
+	if err := json.NewDecoder(resp.Body).Decode(&count); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return count, nil
+}
+
 // getCollectionName returns tenant-scoped collection name
 func (c *Client) getCollectionName(baseName string) string {
 	return fmt.Sprintf("%s_%s", c.tenantID, baseName)
@@ -95,17 +134,27 @@ func (c *Client) CreateCollection(name string) error {
 
 // GetCollection retrieves collection information
 func (c *Client) GetCollection(name string) (map[string]interface{}, error) {
+	return c.GetCollectionContext(context.Background(), name)
+}
+
+// GetCollectionContext retrieves collection information, giving up when ctx is done. Returns
+// ErrCollectionNotFound when the collection doesn't exist.
+func (c *Client) GetCollectionContext(ctx context.Context, name string) (map[string]interface{}, error) {
 	collectionName := c.getCollectionName(name)
 	url := fmt.Sprintf("%s/api/v1/collections/%s", c.baseURL, collectionName)
 
-	resp, err := c.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get collection: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("collection not found")
+		return nil, ErrCollectionNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -121,6 +170,19 @@ func (c *Client) GetCollection(name string) (map[string]interface{}, error) {
 	return result, nil
 }
 
+// EnsureCollection creates a collection if it doesn't exist, e.g. after a restart of a Chroma
+// server with ephemeral storage
+func (c *Client) EnsureCollection(name string) error {
+	_, err := c.GetCollection(name)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrCollectionNotFound) {
+		return err
+	}
+	return c.CreateCollection(name)
+}
+
 // AddDocumentsRequest represents a request to add documents
 type AddDocumentsRequest struct {
 	Documents  []string
@@ -172,6 +234,7 @@ type QueryRequest struct {
 	QueryEmbeddings [][]float64
 	NResults        int
 	Include         []string
+	Where           map[string]interface{} // Optional metadata equality filter
 }
 
 // QueryResponse represents a query response
@@ -200,6 +263,9 @@ func (c *Client) Query(collectionName string, req QueryRequest) (*QueryResponse,
 		"n_results":        req.NResults,
 		"include":          req.Include,
 	}
+	if len(req.Where) > 0 {
+		payload["where"] = whereFilter(req.Where)
+	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -325,3 +391,136 @@ func (c *Client) Delete(collectionName string, ids []string) error {
 	return nil
 }
 
+// DeleteWhere deletes every document in a collection matching all where conditions
+func (c *Client) DeleteWhere(collectionName string, where map[string]interface{}) error {
+	collection := c.getCollectionName(collectionName)
+	url := fmt.Sprintf("%s/api/v1/collections/%s/delete", c.baseURL, collection)
+
+	payload := map[string]interface{}{
+		"where": whereFilter(where),
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to delete documents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A missing collection has nothing to delete
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete documents: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes a collection and all its documents. Deleting a collection that
+// doesn't exist succeeds.
+func (c *Client) DeleteCollection(name string) error {
+	collectionName := c.getCollectionName(name)
+	url := fmt.Sprintf("%s/api/v1/collections/%s", c.baseURL, collectionName)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete collection: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// GetResponse represents documents fetched by metadata
+type GetResponse struct {
+	IDs       []string
+	Metadatas []map[string]interface{}
+}
+
+// whereFilter converts equality conditions into a Chroma where clause; several conditions are combined with $and
+func whereFilter(conditions map[string]interface{}) map[string]interface{} {
+	if len(conditions) <= 1 {
+		return conditions
+	}
+	clauses := make([]map[string]interface{}, 0, len(conditions))
+	for key, value := range conditions {
+		clauses = append(clauses, map[string]interface{}{key: value})
+	}
+	return map[string]interface{}{"$and": clauses}
+}
+
+// GetDocumentsByMetadata fetches document IDs and metadata matching all where conditions
+// (nil matches every document). limit and offset page through large collections.
+func (c *Client) GetDocumentsByMetadata(collectionName string, where map[string]interface{}, limit, offset int) (*GetResponse, error) {
+	collection := c.getCollectionName(collectionName)
+	url := fmt.Sprintf("%s/api/v1/collections/%s/get", c.baseURL, collection)
+
+	payload := map[string]interface{}{
+		"include": []string{"metadatas"},
+		"limit":   limit,
+		"offset":  offset,
+	}
+	if len(where) > 0 {
+		payload["where"] = whereFilter(where)
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get documents: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		IDs       []string                 `json:"ids"`
+		Metadatas []map[string]interface{} `json:"metadatas"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &GetResponse{IDs: result.IDs, Metadatas: result.Metadatas}, nil
+}
@@ -2,6 +2,7 @@ package chroma
 
 import (
 	"fmt"
+	"sort"
 )
 
 // RetrievedChunk represents a retrieved chunk with metadata
@@ -24,6 +25,11 @@ func NewRetriever(client *Client) *Retriever {
 
 // RetrieveContext retrieves top-k relevant chunks from a collection
 func (r *Retriever) RetrieveContext(collection string, queryEmbedding []float64, topK int) ([]RetrievedChunk, error) {
+	return r.retrieveWhere(collection, queryEmbedding, topK, nil)
+}
+
+// retrieveWhere retrieves top-k relevant chunks whose metadata matches where (nil matches all)
+func (r *Retriever) retrieveWhere(collection string, queryEmbedding []float64, topK int, where map[string]interface{}) ([]RetrievedChunk, error) {
 	if topK <= 0 {
 		topK = 10
 	}
@@ -32,6 +38,7 @@ func (r *Retriever) RetrieveContext(collection string, queryEmbedding []float64,
 		QueryEmbeddings: [][]float64{queryEmbedding},
 		NResults:        topK,
 		Include:         []string{"documents", "metadatas", "distances"},
+		Where:           where,
 	}
 
 	resp, err := r.client.Query(collection, req)
@@ -77,10 +84,120 @@ func (r *Retriever) RetrieveRelevantConversations(tenantID string, queryEmbeddin
 	return r.RetrieveContext(collection, queryEmbedding, topK)
 }
 
-// RetrieveProductKnowledge retrieves relevant product knowledge
+// ConversationContextCollection holds chunks of closed conversation transcripts
+const ConversationContextCollection = "conversation_context"
+
+// RetrieveConversationContext retrieves the tenant's most relevant closed-conversation transcript
+// chunks. Transcripts hold customer data, so results are always filtered to the tenant.
+func (r *Retriever) RetrieveConversationContext(tenantID string, queryEmbedding []float64, nResults int) ([]RetrievedChunk, error) {
+	return r.retrieveWhere(ConversationContextCollection, queryEmbedding, nResults, map[string]interface{}{"tenant_id": tenantID})
+}
+
+// MessageIndexCollection holds one embedding per conversation message, for semantic message search
+const MessageIndexCollection = "message_index"
+
+// messagePreviewLength is the maximum number of characters of a message returned in search results
+const messagePreviewLength = 200
+
+// MessageSearchResult is a message matching a semantic search
+type MessageSearchResult struct {
+	ConversationID string  `json:"conversation_id"`
+	MessageID      string  `json:"message_id"`
+	Sender         string  `json:"sender"`
+	Preview        string  `json:"preview"` // The start of the message content
+	Score          float64 `json:"score"`   // Similarity, higher is closer
+}
+
+// SearchMessages returns the tenant's indexed messages most similar to the query, most similar
+// first. Every tenant's messages share the collection, so results are always filtered to the tenant.
+func (r *Retriever) SearchMessages(tenantID string, queryEmbedding []float64, limit int) ([]*MessageSearchResult, error) {
+	chunks, err := r.retrieveWhere(MessageIndexCollection, queryEmbedding, limit, map[string]interface{}{"tenant_id": tenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*MessageSearchResult, 0, len(chunks))
+	for _, chunk := range chunks {
+		result := &MessageSearchResult{MessageID: chunk.ID, Preview: messagePreview(chunk.Text), Score: chunk.Score}
+		result.ConversationID, _ = chunk.Metadata["conversation_id"].(string)
+		result.Sender, _ = chunk.Metadata["sender"].(string)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// messagePreview shortens text to messagePreviewLength characters
+func messagePreview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= messagePreviewLength {
+		return text
+	}
+	return string(runes[:messagePreviewLength]) + "…"
+}
+
+// RetrieveProductKnowledge retrieves relevant product knowledge.
+// Products are stored as several section chunks, so results are grouped by
+// product_id and merged into one chunk per product with the most relevant
+// section first. topK limits the number of products returned.
 func (r *Retriever) RetrieveProductKnowledge(tenantID string, queryEmbedding []float64, topK int) ([]RetrievedChunk, error) {
+	if topK <= 0 {
+		topK = 10
+	}
+
 	// Client will add tenant prefix via getCollectionName
 	collection := "product_knowledge"
-	return r.RetrieveContext(collection, queryEmbedding, topK)
+	// Over-fetch since several chunks may belong to the same product
+	chunks, err := r.RetrieveContext(collection, queryEmbedding, topK*productChunkFanout)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeProductChunks(chunks)
+	if len(merged) > topK {
+		merged = merged[:topK]
+	}
+	return merged, nil
+}
+
+// productChunkFanout is the over-fetch factor for chunked product retrieval
+const productChunkFanout = 3
+
+// mergeProductChunks groups chunks by product_id and merges their texts.
+// Chunks arrive ordered by relevance, so the first chunk seen for a product
+// is its highest-relevance section and determines the merged score.
+// Chunks without a product_id are passed through unchanged.
+func mergeProductChunks(chunks []RetrievedChunk) []RetrievedChunk {
+	merged := make([]RetrievedChunk, 0, len(chunks))
+	index := make(map[string]int)
+
+	for _, chunk := range chunks {
+		productID, _ := chunk.Metadata["product_id"].(string)
+		if productID == "" {
+			merged = append(merged, chunk)
+			continue
+		}
+
+		if i, ok := index[productID]; ok {
+			merged[i].Text = merged[i].Text + "\n" + chunk.Text
+			if chunk.Score > merged[i].Score {
+				merged[i].Score = chunk.Score
+			}
+			continue
+		}
+
+		index[productID] = len(merged)
+		merged = append(merged, RetrievedChunk{
+			Text:     chunk.Text,
+			Score:    chunk.Score,
+			Metadata: chunk.Metadata,
+			ID:       productID,
+		})
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+
+	return merged
 }
 
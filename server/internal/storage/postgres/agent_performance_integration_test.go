@@ -0,0 +1,265 @@
+//go:build integration
+
+package postgres
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// createMessageAt adds a message sent at a given time to a conversation
+func createMessageAt(t *testing.T, storage *ConversationStorage, tenantID, conversationID, sender string, at time.Time, autoReply bool) {
+	t.Helper()
+	msg := &models.Message{
+		ID: uuid.New().String(), ConversationID: conversationID, Sender: sender, Content: "hello from " + sender,
+		Channel: "web", Language: "en", Timestamp: at, CreatedAt: at, IsAutoReply: autoReply,
+	}
+	if err := storage.CreateMessage(tenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+}
+
+func TestGetAgentPerformanceStats(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	autoReplies := NewAutoReplyStorage(testClient)
+	agent := newTestUser(t, users, "performance.agent@example.com", models.RoleAgent)
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	assigned := func() *models.Conversation {
+		t.Helper()
+		conv := newTestConversation(t, storage, nil, "active")
+		if err := storage.AssignAgent(testTenantID, conv.ID, agent.ID); err != nil {
+			t.Fatalf("AssignAgent: %v", err)
+		}
+		return conv
+	}
+
+	// Answered after 4 minutes; the earlier auto-reply isn't the agent's response
+	fast := assigned()
+	createMessageAt(t, storage, fast.TenantID, fast.ID, "customer", start, false)
+	createMessageAt(t, storage, fast.TenantID, fast.ID, "agent", start.Add(time.Minute), true)
+	createMessageAt(t, storage, fast.TenantID, fast.ID, "agent", start.Add(4*time.Minute), false)
+
+	// Answered after 10 minutes
+	slow := assigned()
+	createMessageAt(t, storage, slow.TenantID, slow.ID, "customer", start, false)
+	createMessageAt(t, storage, slow.TenantID, slow.ID, "agent", start.Add(10*time.Minute), false)
+
+	// Unanswered, with auto-reply turned off for the conversation
+	unanswered := assigned()
+	createMessageAt(t, storage, unanswered.TenantID, unanswered.ID, "customer", start, false)
+	if err := autoReplies.UpdateConversationConfig(&models.AutoReplyConversationConfig{
+		ConversationID: unanswered.ID, Enabled: false, UpdatedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("UpdateConversationConfig: %v", err)
+	}
+
+	// Another tenant's conversation pointing at the same agent ID isn't counted
+	otherTenant := "other-" + uuid.New().String()
+	other := &models.Conversation{ID: uuid.New().String(), TenantID: otherTenant, Status: "active", CreatedAt: start, UpdatedAt: start}
+	if err := storage.CreateConversation(otherTenant, other); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM conversations WHERE tenant_id = $1", otherTenant) })
+	if _, err := testClient.DB.Exec("UPDATE conversations SET assigned_agent_id = $1 WHERE id = $2", agent.ID, other.ID); err != nil {
+		t.Fatalf("assign other tenant's conversation: %v", err)
+	}
+	createMessageAt(t, storage, other.TenantID, other.ID, "customer", start, false)
+	createMessageAt(t, storage, other.TenantID, other.ID, "agent", start.Add(time.Minute), false)
+
+	stats, err := storage.GetAgentPerformanceStats(testTenantID, agent.ID, start.Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentPerformanceStats: %v", err)
+	}
+	if len(stats.ConversationIDs) != 3 {
+		t.Fatalf("conversations = %v, want the agent's 3 conversations in this tenant", stats.ConversationIDs)
+	}
+	for _, id := range stats.ConversationIDs {
+		if id == other.ID {
+			t.Errorf("another tenant's conversation %s was counted", id)
+		}
+	}
+	if stats.RespondedConversations != 2 || math.Abs(stats.AvgFirstResponseMinutes-7) > 0.01 {
+		t.Errorf("responded = %d avg first response = %.2f, want 2 and 7 minutes", stats.RespondedConversations, stats.AvgFirstResponseMinutes)
+	}
+	if stats.AutoReplyOverrides != 1 {
+		t.Errorf("auto-reply overrides = %d, want 1", stats.AutoReplyOverrides)
+	}
+
+	// Conversations created outside the range are excluded
+	empty, err := storage.GetAgentPerformanceStats(testTenantID, agent.ID, start.Add(-48*time.Hour), start.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentPerformanceStats(earlier range): %v", err)
+	}
+	if len(empty.ConversationIDs) != 0 {
+		t.Errorf("conversations outside the range = %v, want none", empty.ConversationIDs)
+	}
+
+	// The agent isn't a user of another tenant
+	if _, err := storage.GetAgentPerformanceStats(otherTenant, agent.ID, start.Add(-time.Hour), time.Now().Add(time.Hour)); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("stats from another tenant: err = %v, want not found", err)
+	}
+}
+
+func TestGetAgentStatsResponseTimes(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	responder := newTestUser(t, users, "stats.responder@example.com", models.RoleAgent)
+	silent := newTestUser(t, users, "stats.silent@example.com", models.RoleAgent)
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	assigned := func(agentID string) *models.Conversation {
+		t.Helper()
+		conv := newTestConversation(t, storage, nil, "active")
+		if err := storage.AssignAgent(testTenantID, conv.ID, agentID); err != nil {
+			t.Fatalf("AssignAgent: %v", err)
+		}
+		return conv
+	}
+
+	// The responder answers after 6 minutes; the silent agent never replies
+	answered := assigned(responder.ID)
+	createMessageAt(t, storage, answered.TenantID, answered.ID, "customer", start, false)
+	createMessageAt(t, storage, answered.TenantID, answered.ID, "agent", start.Add(6*time.Minute), false)
+	ignored := assigned(silent.ID)
+	createMessageAt(t, storage, ignored.TenantID, ignored.ID, "customer", start, false)
+
+	stats, err := storage.GetAgentStats(testTenantID, start.Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentStats: %v", err)
+	}
+	byAgent := map[string]*AgentStats{}
+	for _, st := range stats {
+		byAgent[st.AgentID] = st
+	}
+	if st := byAgent[responder.ID]; st == nil || !st.HasResponseTime || math.Abs(st.AvgResponseMinutes-6) > 0.01 {
+		t.Errorf("responder stats = %+v, want a 6 minute response time", st)
+	}
+	if st := byAgent[silent.ID]; st == nil || st.HasResponseTime {
+		t.Errorf("silent agent stats = %+v, want no response time", st)
+	}
+}
+
+func TestGetAgentStatsExcludesDeletedConversations(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	agent := newTestUser(t, users, "stats.deleted@example.com", models.RoleAgent)
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	var kept, deleted *models.Conversation
+	for _, conv := range []**models.Conversation{&kept, &deleted} {
+		*conv = newTestConversation(t, storage, nil, "active")
+		if err := storage.AssignAgent(testTenantID, (*conv).ID, agent.ID); err != nil {
+			t.Fatalf("AssignAgent: %v", err)
+		}
+	}
+	createMessageAt(t, storage, kept.TenantID, kept.ID, "customer", start, false)
+	createMessageAt(t, storage, kept.TenantID, kept.ID, "agent", start.Add(2*time.Minute), false)
+	createMessageAt(t, storage, deleted.TenantID, deleted.ID, "customer", start, false)
+	createMessageAt(t, storage, deleted.TenantID, deleted.ID, "agent", start.Add(20*time.Minute), false)
+	if err := storage.SoftDeleteConversation(testTenantID, deleted.ID, "admin-1"); err != nil {
+		t.Fatalf("SoftDeleteConversation: %v", err)
+	}
+
+	stats, err := storage.GetAgentStats(testTenantID, start.Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentStats: %v", err)
+	}
+	for _, st := range stats {
+		if st.AgentID != agent.ID {
+			continue
+		}
+		if st.TotalConversations != 1 || math.Abs(st.AvgResponseMinutes-2) > 0.01 {
+			t.Errorf("stats = %+v, want only the kept conversation counted", st)
+		}
+		return
+	}
+	t.Errorf("agent missing from stats %+v", stats)
+}
+
+func TestGetAgentPerformanceStatsCountsTransfers(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	agent := newTestUser(t, users, "performance.transfer@example.com", models.RoleAgent)
+	colleague := newTestUser(t, users, "performance.colleague@example.com", models.RoleAgent)
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	handled := make([]*models.Conversation, 4)
+	for i := range handled {
+		handled[i] = newTestConversation(t, storage, nil, "active")
+		if err := storage.AssignAgent(testTenantID, handled[i].ID, agent.ID); err != nil {
+			t.Fatalf("AssignAgent: %v", err)
+		}
+	}
+	transfer := func(conv *models.Conversation, from, to string) {
+		t.Helper()
+		if _, err := storage.TransferConversation(testTenantID, conv.ID, to, "", from); err != nil {
+			t.Fatalf("TransferConversation: %v", err)
+		}
+	}
+	// One conversation is transferred away, another away and back again
+	transfer(handled[0], agent.ID, colleague.ID)
+	transfer(handled[1], agent.ID, colleague.ID)
+	transfer(handled[1], colleague.ID, agent.ID)
+
+	stats, err := storage.GetAgentPerformanceStats(testTenantID, agent.ID, start, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentPerformanceStats: %v", err)
+	}
+	if len(stats.ConversationIDs) != 3 || stats.HandledConversations != 4 || stats.TransferredAway != 2 {
+		t.Errorf("assigned = %d handled = %d transferred away = %d, want 3, 4 and 2",
+			len(stats.ConversationIDs), stats.HandledConversations, stats.TransferredAway)
+	}
+}
+
+func TestGetAgentStatsCountsDealWonAsWins(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	agent := newTestUser(t, users, "stats.closer@example.com", models.RoleAgent)
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	closeAs := func(status, resolution string) {
+		t.Helper()
+		conv := newTestConversation(t, storage, nil, "active")
+		if err := storage.AssignAgent(testTenantID, conv.ID, agent.ID); err != nil {
+			t.Fatalf("AssignAgent: %v", err)
+		}
+		now := time.Now().UTC()
+		conv.Status, conv.ClosedAt, conv.UpdatedAt = status, &now, now
+		if resolution != "" {
+			conv.ResolutionType = &resolution
+		}
+		if err := storage.UpdateConversation(testTenantID, conv); err != nil {
+			t.Fatalf("UpdateConversation: %v", err)
+		}
+	}
+	// Two wins out of four closed conversations; the open deal_won one isn't closed yet
+	closeAs("closed", models.ResolutionDealWon)
+	closeAs("archived", models.ResolutionDealWon)
+	closeAs("closed", models.ResolutionDealLost)
+	closeAs("closed", "")
+	closeAs("active", models.ResolutionDealWon)
+
+	stats, err := storage.GetAgentStats(testTenantID, start, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgentStats: %v", err)
+	}
+	for _, st := range stats {
+		if st.AgentID != agent.ID {
+			continue
+		}
+		if st.TotalConversations != 5 || st.ClosedConversations != 4 || st.WonConversations != 2 {
+			t.Errorf("total = %d closed = %d won = %d, want 5, 4 and 2",
+				st.TotalConversations, st.ClosedConversations, st.WonConversations)
+		}
+		return
+	}
+	t.Errorf("agent missing from stats %+v", stats)
+}
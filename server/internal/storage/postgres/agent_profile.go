@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AgentSuggestionProfile captures an agent's writing style for personalized reply suggestions
+type AgentSuggestionProfile struct {
+	TenantID         string    `json:"tenant_id"`
+	AgentID          string    `json:"agent_id"`
+	PreferredTone    string    `json:"preferred_tone"`     // "formal", "casual" or "neutral"
+	AvgMessageLength float64   `json:"avg_message_length"` // Words per message
+	CommonPhrases    []string  `json:"common_phrases"`
+	MessageCount     int       `json:"message_count"` // Messages the profile was built from
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TenantAgent identifies an agent within a tenant
+type TenantAgent struct {
+	TenantID string
+	AgentID  string
+}
+
+// AgentProfileStorage handles agent suggestion profiles
+type AgentProfileStorage struct {
+	client *Client
+}
+
+// NewAgentProfileStorage creates a new agent profile storage instance
+func NewAgentProfileStorage(client *Client) *AgentProfileStorage {
+	return &AgentProfileStorage{client: client}
+}
+
+// GetProfile retrieves an agent's suggestion profile, or nil if it hasn't been built yet
+func (s *AgentProfileStorage) GetProfile(tenantID, agentID string) (*AgentSuggestionProfile, error) {
+	query := `
+		SELECT tenant_id, agent_id, preferred_tone, avg_message_length, common_phrases, message_count, updated_at
+		FROM agent_suggestion_profiles
+		WHERE tenant_id = $1 AND agent_id = $2
+	`
+	profile := &AgentSuggestionProfile{}
+	var phrasesJSON string
+	err := s.client.DB.QueryRow(query, tenantID, agentID).Scan(
+		&profile.TenantID, &profile.AgentID, &profile.PreferredTone, &profile.AvgMessageLength,
+		&phrasesJSON, &profile.MessageCount, &profile.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent profile: %w", err)
+	}
+	if err := json.Unmarshal([]byte(phrasesJSON), &profile.CommonPhrases); err != nil {
+		profile.CommonPhrases = []string{}
+	}
+	return profile, nil
+}
+
+// SaveProfile creates or replaces an agent's suggestion profile
+func (s *AgentProfileStorage) SaveProfile(profile *AgentSuggestionProfile) error {
+	phrasesJSON, err := json.Marshal(profile.CommonPhrases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal common phrases: %w", err)
+	}
+	if profile.UpdatedAt.IsZero() {
+		profile.UpdatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO agent_suggestion_profiles (tenant_id, agent_id, preferred_tone, avg_message_length, common_phrases, message_count, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(tenant_id, agent_id) DO UPDATE SET
+			preferred_tone = excluded.preferred_tone,
+			avg_message_length = excluded.avg_message_length,
+			common_phrases = excluded.common_phrases,
+			message_count = excluded.message_count,
+			updated_at = excluded.updated_at
+	`
+	_, err = s.client.DB.Exec(query,
+		profile.TenantID, profile.AgentID, profile.PreferredTone, profile.AvgMessageLength,
+		string(phrasesJSON), profile.MessageCount, profile.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save agent profile: %w", err)
+	}
+	return nil
+}
+
+// GetAgentMessages returns the content of an agent's most recent messages, newest first.
+// Messages carry no author, so agent messages in conversations assigned to the agent are used.
+func (s *ConversationStorage) GetAgentMessages(tenantID, agentID string, limit int) ([]string, error) {
+	query := `
+		SELECT m.content
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.tenant_id = $1 AND c.assigned_agent_id = $2 AND m.sender = 'agent' AND m.deleted_at IS NULL
+		ORDER BY m.timestamp DESC
+		LIMIT $3
+	`
+	rows, err := s.client.DB.Query(query, tenantID, agentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []string
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return nil, fmt.Errorf("failed to scan agent message: %w", err)
+		}
+		messages = append(messages, content)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent messages: %w", err)
+	}
+	return messages, nil
+}
+
+// ListAgentsWithMessages lists every tenant/agent pair with agent messages in assigned conversations
+func (s *ConversationStorage) ListAgentsWithMessages() ([]TenantAgent, error) {
+	query := `
+		SELECT DISTINCT c.tenant_id, c.assigned_agent_id
+		FROM conversations c
+		JOIN messages m ON m.conversation_id = c.id
+		WHERE c.assigned_agent_id IS NOT NULL AND m.sender = 'agent'
+	`
+	rows, err := s.client.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents with messages: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []TenantAgent
+	for rows.Next() {
+		var agent TenantAgent
+		if err := rows.Scan(&agent.TenantID, &agent.AgentID); err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agents: %w", err)
+	}
+	return agents, nil
+}
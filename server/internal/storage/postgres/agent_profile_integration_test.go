@@ -0,0 +1,44 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAgentProfileSaveAndReplace(t *testing.T) {
+	storage := NewAgentProfileStorage(testClient)
+	agentID := uuid.New().String()
+
+	profile, err := storage.GetProfile(testTenantID, agentID)
+	if err != nil || profile != nil {
+		t.Fatalf("GetProfile before build = %v, %v; want nil, nil", profile, err)
+	}
+
+	if err := storage.SaveProfile(&AgentSuggestionProfile{
+		TenantID: testTenantID, AgentID: agentID, PreferredTone: "casual",
+		AvgMessageLength: 8.5, CommonPhrases: []string{"no worries"}, MessageCount: 12,
+	}); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+	if err := storage.SaveProfile(&AgentSuggestionProfile{
+		TenantID: testTenantID, AgentID: agentID, PreferredTone: "formal",
+		AvgMessageLength: 21, CommonPhrases: []string{"thank you for", "kind regards"}, MessageCount: 30,
+	}); err != nil {
+		t.Fatalf("SaveProfile (replace): %v", err)
+	}
+
+	got, err := storage.GetProfile(testTenantID, agentID)
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+	if got.PreferredTone != "formal" || got.AvgMessageLength != 21 || got.MessageCount != 30 {
+		t.Errorf("profile = %+v, want the replacement", got)
+	}
+	if !reflect.DeepEqual(got.CommonPhrases, []string{"thank you for", "kind regards"}) {
+		t.Errorf("CommonPhrases = %v", got.CommonPhrases)
+	}
+}
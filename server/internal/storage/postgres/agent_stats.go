@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AgentStats holds aggregated per-agent conversation statistics
+type AgentStats struct {
+	AgentID             string
+	AgentEmail          string
+	TotalConversations  int
+	ClosedConversations int
+	WonConversations    int
+	AvgResponseMinutes  float64
+	HasResponseTime     bool // False when the agent never replied to a customer message in the range
+	SuggestionFeedback  int
+	SuggestionsAccepted int
+	TransferredAway     int // Conversations the agent handed to another agent
+}
+
+// minHandledMessagesForWinRate is the number of agent messages needed before a
+// transferred-away conversation counts toward the transferring agent's win rate
+const minHandledMessagesForWinRate = 3
+
+// minutesBetween returns a SQL expression for the minutes elapsed between two timestamp expressions
+func (s *ConversationStorage) minutesBetween(later, earlier string) string {
+	if s.client.DBType == "sqlite" {
+		return fmt.Sprintf("((julianday(%s) - julianday(%s)) * 1440.0)", later, earlier)
+	}
+	return fmt.Sprintf("(EXTRACT(EPOCH FROM (%s - %s)) / 60.0)", later, earlier)
+}
+
+// GetAgentStats aggregates conversation, response time, suggestion feedback and
+// transfer statistics for every agent who handled conversations in the time range.
+// Soft-deleted conversations are excluded.
+// An agent handled a conversation if it is currently assigned to them or they
+// transferred it away. Agents without any reply to a customer message have no
+// response time (HasResponseTime is false). A conversation counts as won when it is resolved as
+// deal_won, matching the dashboard win rate; conversations transferred away after fewer than
+// minHandledMessagesForWinRate agent messages are excluded from the win rate.
+func (s *ConversationStorage) GetAgentStats(tenantID string, from, to time.Time) ([]*AgentStats, error) {
+	responseGap := s.minutesBetween(
+		"(SELECT MIN(a.timestamp) FROM messages a WHERE a.conversation_id = m.conversation_id AND a.sender = 'agent' AND a.timestamp > m.timestamp)",
+		"m.timestamp",
+	)
+
+	// Agent messages sent between the previous transfer (or conversation start) and this transfer
+	tenureMessages := `(SELECT COUNT(*) FROM messages m
+		WHERE m.conversation_id = te.conversation_id AND m.sender = 'agent' AND m.timestamp <= te.transferred_at
+		AND m.timestamp >= COALESCE((SELECT MAX(p.transferred_at) FROM transfer_events p
+			WHERE p.conversation_id = te.conversation_id AND p.transferred_at < te.transferred_at), tc.created_at))`
+
+	query := fmt.Sprintf(`
+		WITH handled AS (
+			SELECT assigned_agent_id AS agent_id, id AS conversation_id, 1 AS counts_for_win, 0 AS transferred
+			FROM conversations
+			WHERE tenant_id = $1 AND assigned_agent_id IS NOT NULL AND deleted_at IS NULL
+			UNION ALL
+			SELECT te.from_agent_id, te.conversation_id,
+				CASE WHEN %s >= %d THEN 1 ELSE 0 END,
+				1
+			FROM transfer_events te
+			JOIN conversations tc ON tc.id = te.conversation_id
+			WHERE te.tenant_id = $1 AND te.from_agent_id IS NOT NULL
+		)
+		SELECT
+			u.id,
+			u.email,
+			COUNT(DISTINCT c.id),
+			COUNT(DISTINCT CASE WHEN h.counts_for_win = 1 AND c.status IN ('closed', 'archived') THEN c.id END),
+			COUNT(DISTINCT CASE WHEN h.counts_for_win = 1 AND c.status IN ('closed', 'archived') AND c.resolution_type = 'deal_won' THEN c.id END),
+			AVG(rt.avg_minutes),
+			COALESCE(MAX(sf.total), 0),
+			COALESCE(MAX(sf.accepted), 0),
+			COUNT(DISTINCT CASE WHEN h.transferred = 1 THEN c.id END)
+		FROM users u
+		JOIN handled h ON h.agent_id = u.id
+		JOIN conversations c ON c.id = h.conversation_id AND c.tenant_id = u.tenant_id AND c.deleted_at IS NULL
+		LEFT JOIN (
+			SELECT m.conversation_id, AVG(%s) AS avg_minutes
+			FROM messages m
+			JOIN conversations rc ON rc.id = m.conversation_id
+			WHERE m.sender = 'customer' AND rc.tenant_id = $1 AND rc.deleted_at IS NULL AND rc.created_at >= $2 AND rc.created_at <= $3
+			GROUP BY m.conversation_id
+		) rt ON rt.conversation_id = c.id
+		LEFT JOIN (
+			SELECT agent_id,
+				COUNT(*) AS total,
+				SUM(CASE WHEN action IN ('accepted', 'edited') THEN 1 ELSE 0 END) AS accepted
+			FROM suggestion_feedback
+			WHERE tenant_id = $1 AND feedback_at >= $2 AND feedback_at <= $3
+			GROUP BY agent_id
+		) sf ON sf.agent_id = u.id
+		WHERE u.tenant_id = $1 AND c.created_at >= $2 AND c.created_at <= $3
+		GROUP BY u.id, u.email
+	`, tenureMessages, minHandledMessagesForWinRate, responseGap)
+
+	rows, err := s.client.DB.Query(query, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*AgentStats
+	for rows.Next() {
+		st := &AgentStats{}
+		var avgResponse sql.NullFloat64
+		if err := rows.Scan(
+			&st.AgentID, &st.AgentEmail, &st.TotalConversations, &st.ClosedConversations,
+			&st.WonConversations, &avgResponse, &st.SuggestionFeedback, &st.SuggestionsAccepted,
+			&st.TransferredAway,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan agent stats: %w", err)
+		}
+		st.AvgResponseMinutes, st.HasResponseTime = avgResponse.Float64, avgResponse.Valid
+		stats = append(stats, st)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent stats: %w", err)
+	}
+	return stats, nil
+}
+
+// AgentPerformanceStats holds one agent's statistics for the conversations assigned to them
+type AgentPerformanceStats struct {
+	ConversationIDs         []string // Assigned conversations created in the time range
+	RespondedConversations  int      // Conversations the agent replied to after a customer message
+	AvgFirstResponseMinutes float64  // From a conversation's first customer message to the agent's first reply
+	AutoReplyOverrides      int      // Conversations where auto-reply was turned off for the conversation
+	HandledConversations    int      // Assigned conversations plus those the agent transferred away
+	TransferredAway         int      // Handled conversations the agent transferred to another agent
+}
+
+// GetAgentPerformanceStats aggregates statistics for the conversations assigned to an agent and
+// created in the time range. Auto-replies don't count as the agent's first response. As in
+// GetAgentStats, the agent handled a conversation if it is assigned to them or they transferred
+// it away.
+// Returns an error containing "not found" if the agent isn't a user of the tenant.
+func (s *ConversationStorage) GetAgentPerformanceStats(tenantID, agentID string, from, to time.Time) (*AgentPerformanceStats, error) {
+	var users int
+	if err := s.client.DB.QueryRow("SELECT COUNT(*) FROM users WHERE id = $1 AND tenant_id = $2", agentID, tenantID).Scan(&users); err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	if users == 0 {
+		return nil, fmt.Errorf("agent not found")
+	}
+
+	firstCustomerMessage := "(SELECT MIN(cm.timestamp) FROM messages cm WHERE cm.conversation_id = c.id AND cm.sender = 'customer' AND cm.deleted_at IS NULL)"
+	firstResponse := s.minutesBetween(
+		"(SELECT MIN(am.timestamp) FROM messages am WHERE am.conversation_id = c.id AND am.sender = 'agent'"+
+			" AND am.is_auto_reply = FALSE AND am.deleted_at IS NULL AND am.timestamp >= "+firstCustomerMessage+")",
+		firstCustomerMessage,
+	)
+	query := fmt.Sprintf(`
+		SELECT c.id, %s,
+			(SELECT COUNT(*) FROM auto_reply_conversations ar WHERE ar.conversation_id = c.id AND ar.enabled = FALSE)
+		FROM conversations c
+		WHERE c.tenant_id = $1 AND c.assigned_agent_id = $2 AND c.deleted_at IS NULL
+			AND c.created_at >= $3 AND c.created_at <= $4
+		ORDER BY c.created_at
+	`, firstResponse)
+
+	rows, err := s.client.DB.Query(query, tenantID, agentID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent performance stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &AgentPerformanceStats{ConversationIDs: []string{}}
+	totalResponseMinutes := 0.0
+	for rows.Next() {
+		var conversationID string
+		var responseMinutes sql.NullFloat64
+		var overridden int
+		if err := rows.Scan(&conversationID, &responseMinutes, &overridden); err != nil {
+			return nil, fmt.Errorf("failed to scan agent performance stats: %w", err)
+		}
+		stats.ConversationIDs = append(stats.ConversationIDs, conversationID)
+		if responseMinutes.Valid {
+			stats.RespondedConversations++
+			totalResponseMinutes += responseMinutes.Float64
+		}
+		if overridden > 0 {
+			stats.AutoReplyOverrides++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent performance stats: %w", err)
+	}
+	if stats.RespondedConversations > 0 {
+		stats.AvgFirstResponseMinutes = totalResponseMinutes / float64(stats.RespondedConversations)
+	}
+
+	transferRows, err := s.client.DB.Query(`
+		SELECT DISTINCT c.id, c.assigned_agent_id
+		FROM transfer_events te
+		JOIN conversations c ON c.id = te.conversation_id
+		WHERE c.tenant_id = $1 AND te.from_agent_id = $2 AND c.deleted_at IS NULL
+			AND c.created_at >= $3 AND c.created_at <= $4
+	`, tenantID, agentID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent transfers: %w", err)
+	}
+	defer transferRows.Close()
+
+	stats.HandledConversations = len(stats.ConversationIDs)
+	for transferRows.Next() {
+		var conversationID string
+		var assignedTo sql.NullString
+		if err := transferRows.Scan(&conversationID, &assignedTo); err != nil {
+			return nil, fmt.Errorf("failed to scan agent transfers: %w", err)
+		}
+		stats.TransferredAway++
+		// Conversations transferred back to the agent are already among the assigned ones
+		if assignedTo.String != agentID {
+			stats.HandledConversations++
+		}
+	}
+	if err = transferRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent transfers: %w", err)
+	}
+	return stats, nil
+}
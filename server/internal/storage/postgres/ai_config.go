@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// IntentConfig is a tenant's custom set of analysis intents
+type IntentConfig struct {
+	Intents      []string          `json:"intents"`
+	Descriptions map[string]string `json:"descriptions,omitempty"` // Optional hint per intent for the analysis prompt
+}
+
+// AIConfigStorage handles per-tenant AI analysis configuration
+type AIConfigStorage struct {
+	client *Client
+}
+
+// NewAIConfigStorage creates a new AI config storage instance
+func NewAIConfigStorage(client *Client) *AIConfigStorage {
+	return &AIConfigStorage{client: client}
+}
+
+// GetIntentConfig retrieves a tenant's custom intents, or nil if the tenant uses the defaults
+func (s *AIConfigStorage) GetIntentConfig(tenantID string) (*IntentConfig, error) {
+	query := `
+		SELECT custom_intents, intent_descriptions
+		FROM tenant_ai_config
+		WHERE tenant_id = $1
+	`
+	var intentsJSON, descriptionsJSON sql.NullString
+	err := s.client.DB.QueryRow(query, tenantID).Scan(&intentsJSON, &descriptionsJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intent config: %w", err)
+	}
+	if !intentsJSON.Valid {
+		return nil, nil
+	}
+
+	config := &IntentConfig{}
+	if err := json.Unmarshal([]byte(intentsJSON.String), &config.Intents); err != nil {
+		return nil, fmt.Errorf("failed to parse custom intents: %w", err)
+	}
+	if len(config.Intents) == 0 {
+		return nil, nil
+	}
+	if descriptionsJSON.Valid {
+		if err := json.Unmarshal([]byte(descriptionsJSON.String), &config.Descriptions); err != nil {
+			config.Descriptions = nil
+		}
+	}
+	return config, nil
+}
+
+// SetIntentConfig creates or replaces a tenant's custom intents
+func (s *AIConfigStorage) SetIntentConfig(tenantID string, config *IntentConfig) error {
+	intentsJSON, err := json.Marshal(config.Intents)
+	if err != nil {
+		return fmt.Errorf("failed to marshal intents: %w", err)
+	}
+	descriptionsJSON, err := json.Marshal(config.Descriptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal intent descriptions: %w", err)
+	}
+
+	query := `
+		INSERT INTO tenant_ai_config (tenant_id, custom_intents, intent_descriptions, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			custom_intents = excluded.custom_intents,
+			intent_descriptions = excluded.intent_descriptions,
+			updated_at = excluded.updated_at
+	`
+	_, err = s.client.DB.Exec(query, tenantID, string(intentsJSON), string(descriptionsJSON), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set intent config: %w", err)
+	}
+	return nil
+}
+
+// ClearIntentConfig removes a tenant's custom intents so the defaults apply again
+func (s *AIConfigStorage) ClearIntentConfig(tenantID string) error {
+	query := `
+		UPDATE tenant_ai_config
+		SET custom_intents = NULL, intent_descriptions = NULL, updated_at = $1
+		WHERE tenant_id = $2
+	`
+	if _, err := s.client.DB.Exec(query, time.Now(), tenantID); err != nil {
+		return fmt.Errorf("failed to clear intent config: %w", err)
+	}
+	return nil
+}
+
+// GetSuggestionCount retrieves a tenant's reply suggestion count, or nil if the tenant uses the default
+func (s *AIConfigStorage) GetSuggestionCount(tenantID string) (*int, error) {
+	query := `
+		SELECT suggestions_count
+		FROM tenant_ai_config
+		WHERE tenant_id = $1
+	`
+	var count sql.NullInt64
+	err := s.client.DB.QueryRow(query, tenantID).Scan(&count)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestion count: %w", err)
+	}
+	if !count.Valid {
+		return nil, nil
+	}
+	value := int(count.Int64)
+	return &value, nil
+}
+
+// SetSuggestionCount creates or replaces a tenant's reply suggestion count
+func (s *AIConfigStorage) SetSuggestionCount(tenantID string, count int) error {
+	query := `
+		INSERT INTO tenant_ai_config (tenant_id, suggestions_count, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			suggestions_count = excluded.suggestions_count,
+			updated_at = excluded.updated_at
+	`
+	if _, err := s.client.DB.Exec(query, tenantID, count, time.Now()); err != nil {
+		return fmt.Errorf("failed to set suggestion count: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIntentConfigRoundTrip(t *testing.T) {
+	storage := NewAIConfigStorage(testClient)
+	tenantID := "intents-" + uuid.New().String()
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM tenant_ai_config WHERE tenant_id = $1", tenantID) })
+
+	if config, err := storage.GetIntentConfig(tenantID); err != nil || config != nil {
+		t.Fatalf("GetIntentConfig before setting = %+v, %v; want nil", config, err)
+	}
+
+	want := &IntentConfig{
+		Intents:      []string{"pricing_inquiry", "demo_request", "renewal", "escalation"},
+		Descriptions: map[string]string{"escalation": "Customer asks for a manager"},
+	}
+	if err := storage.SetIntentConfig(tenantID, want); err != nil {
+		t.Fatalf("SetIntentConfig: %v", err)
+	}
+	if err := storage.SetSuggestionCount(tenantID, 5); err != nil {
+		t.Fatalf("SetSuggestionCount: %v", err)
+	}
+	got, err := storage.GetIntentConfig(tenantID)
+	if err != nil {
+		t.Fatalf("GetIntentConfig: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIntentConfig = %+v, want %+v", got, want)
+	}
+
+	// Clearing restores the defaults without touching the rest of the tenant's AI config
+	if err := storage.ClearIntentConfig(tenantID); err != nil {
+		t.Fatalf("ClearIntentConfig: %v", err)
+	}
+	if config, err := storage.GetIntentConfig(tenantID); err != nil || config != nil {
+		t.Errorf("GetIntentConfig after clearing = %+v, %v; want nil", config, err)
+	}
+	if count, err := storage.GetSuggestionCount(tenantID); err != nil || count == nil || *count != 5 {
+		t.Errorf("suggestion count after clearing intents = %v, %v; want 5", count, err)
+	}
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantUsage is a tenant's AI call count, broken down by operation
+type TenantUsage struct {
+	TenantID    string         `json:"tenant_id"`
+	TotalCalls  int            `json:"total_calls"`
+	ByOperation map[string]int `json:"by_operation"`
+}
+
+// UsageStorage records AI API calls per tenant
+type UsageStorage struct {
+	client *Client
+}
+
+// NewUsageStorage creates a new usage storage instance
+func NewUsageStorage(client *Client) *UsageStorage {
+	return &UsageStorage{client: client}
+}
+
+// RecordUsage records one AI API call made for a tenant
+func (s *UsageStorage) RecordUsage(tenantID, operation string) error {
+	_, err := s.client.DB.Exec(`
+		INSERT INTO ai_usage_events (id, tenant_id, operation, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, uuid.New().String(), tenantID, operation, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record ai usage: %w", err)
+	}
+	return nil
+}
+
+// CountByTenant counts AI calls per tenant since the given time, busiest tenants first
+func (s *UsageStorage) CountByTenant(since time.Time) ([]*TenantUsage, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT tenant_id, operation, COUNT(*)
+		FROM ai_usage_events
+		WHERE created_at >= $1
+		GROUP BY tenant_id, operation
+		ORDER BY tenant_id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ai usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []*TenantUsage{}
+	byTenant := make(map[string]*TenantUsage)
+	for rows.Next() {
+		var tenantID, operation string
+		var count int
+		if err := rows.Scan(&tenantID, &operation, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan ai usage: %w", err)
+		}
+		tenant, ok := byTenant[tenantID]
+		if !ok {
+			tenant = &TenantUsage{TenantID: tenantID, ByOperation: make(map[string]int)}
+			byTenant[tenantID] = tenant
+			usage = append(usage, tenant)
+		}
+		tenant.ByOperation[operation] = count
+		tenant.TotalCalls += count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ai usage: %w", err)
+	}
+
+	sort.SliceStable(usage, func(i, j int) bool {
+		return usage[i].TotalCalls > usage[j].TotalCalls
+	})
+	return usage, nil
+}
+
+// ListTenantIDs returns every tenant with at least one conversation
+func (s *ConversationStorage) ListTenantIDs() ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT tenant_id FROM conversations ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenants: %w", err)
+	}
+	return tenantIDs, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCountUsageByTenant(t *testing.T) {
+	storage := NewUsageStorage(testClient)
+	busy := "usage-" + uuid.New().String()
+	quiet := "usage-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM ai_usage_events WHERE tenant_id IN ($1, $2)", busy, quiet)
+	})
+
+	since := time.Now().Add(-time.Minute)
+	for _, op := range []string{"analysis", "analysis", "reply_suggestions"} {
+		if err := storage.RecordUsage(busy, op); err != nil {
+			t.Fatalf("RecordUsage: %v", err)
+		}
+	}
+	if err := storage.RecordUsage(quiet, "analysis"); err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+
+	usage, err := storage.CountByTenant(since)
+	if err != nil {
+		t.Fatalf("CountByTenant: %v", err)
+	}
+	byTenant := make(map[string]*TenantUsage)
+	order := make(map[string]int)
+	for i, tenant := range usage {
+		byTenant[tenant.TenantID] = tenant
+		order[tenant.TenantID] = i
+	}
+
+	if got := byTenant[busy]; got == nil || got.TotalCalls != 3 || got.ByOperation["analysis"] != 2 || got.ByOperation["reply_suggestions"] != 1 {
+		t.Fatalf("busy tenant usage = %+v, want 3 calls (2 analysis, 1 reply_suggestions)", got)
+	}
+	if got := byTenant[quiet]; got == nil || got.TotalCalls != 1 {
+		t.Fatalf("quiet tenant usage = %+v, want 1 call", got)
+	}
+	if order[busy] > order[quiet] {
+		t.Error("expected busier tenant to be listed first")
+	}
+
+	later, err := storage.CountByTenant(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CountByTenant: %v", err)
+	}
+	for _, tenant := range later {
+		if tenant.TenantID == busy || tenant.TenantID == quiet {
+			t.Errorf("usage before the window was counted for tenant %s", tenant.TenantID)
+		}
+	}
+}
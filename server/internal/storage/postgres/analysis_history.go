@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// AppendAnalysisHistory records one analysis of a conversation. Unlike CreateConversationMetadata
+// it never replaces earlier entries.
+func (s *ConversationStorage) AppendAnalysisHistory(entry *models.AnalysisHistoryEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.Emotions == nil {
+		entry.Emotions = []string{}
+	}
+	if entry.Objections == nil {
+		entry.Objections = []string{}
+	}
+	emotionsJSON, _ := json.Marshal(entry.Emotions)
+	objectionsJSON, _ := json.Marshal(entry.Objections)
+
+	_, err := s.client.DB.Exec(`
+		INSERT INTO conversation_analysis_history (id, conversation_id, intent, intent_score, sentiment, sentiment_score, emotions, objections, analyzed_at, message_count_at_analysis)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, entry.ID, entry.ConversationID, entry.Intent, entry.IntentScore, entry.Sentiment, entry.SentimentScore,
+		string(emotionsJSON), string(objectionsJSON), entry.AnalyzedAt, entry.MessageCountAtAnalysis)
+	if err != nil {
+		return fmt.Errorf("failed to append analysis history: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysisHistory returns every analysis of a tenant's conversation, oldest first
+func (s *ConversationStorage) GetAnalysisHistory(tenantID, conversationID string) ([]*models.AnalysisHistoryEntry, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT h.id, h.conversation_id, h.intent, h.intent_score, h.sentiment, h.sentiment_score,
+			h.emotions, h.objections, h.analyzed_at, h.message_count_at_analysis
+		FROM conversation_analysis_history h
+		JOIN conversations c ON h.conversation_id = c.id
+		WHERE h.conversation_id = $1 AND c.tenant_id = $2
+		ORDER BY h.analyzed_at ASC
+	`, conversationID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.AnalysisHistoryEntry{}
+	for rows.Next() {
+		entry := &models.AnalysisHistoryEntry{}
+		var intent, sentiment sql.NullString
+		var emotionsJSON, objectionsJSON string
+		if err := rows.Scan(
+			&entry.ID, &entry.ConversationID, &intent, &entry.IntentScore, &sentiment, &entry.SentimentScore,
+			&emotionsJSON, &objectionsJSON, &entry.AnalyzedAt, &entry.MessageCountAtAnalysis,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis history: %w", err)
+		}
+		entry.Intent = intent.String
+		entry.Sentiment = sentiment.String
+		if err := json.Unmarshal([]byte(emotionsJSON), &entry.Emotions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal emotions: %w", err)
+		}
+		if err := json.Unmarshal([]byte(objectionsJSON), &entry.Objections); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal objections: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating analysis history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestAnalysisHistoryAppendsEveryAnalysis(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	analyses := []*models.AnalysisHistoryEntry{
+		{ConversationID: conv.ID, Intent: "support", IntentScore: 0.6, Sentiment: "negative", SentimentScore: 0.2, Emotions: []string{"frustration"}, Objections: []string{"price"}, AnalyzedAt: start, MessageCountAtAnalysis: 2},
+		{ConversationID: conv.ID, Intent: "buying", IntentScore: 0.8, Sentiment: "positive", SentimentScore: 0.9, AnalyzedAt: start.Add(5 * time.Minute), MessageCountAtAnalysis: 6},
+	}
+	// Appended out of order; the history is returned oldest first
+	for i := len(analyses) - 1; i >= 0; i-- {
+		if err := storage.AppendAnalysisHistory(analyses[i]); err != nil {
+			t.Fatalf("AppendAnalysisHistory: %v", err)
+		}
+	}
+
+	history, err := storage.GetAnalysisHistory(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetAnalysisHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history has %d entries, want both analyses kept", len(history))
+	}
+	if history[0].Intent != "support" || history[0].SentimentScore != 0.2 || history[0].MessageCountAtAnalysis != 2 ||
+		!reflect.DeepEqual(history[0].Emotions, []string{"frustration"}) || !reflect.DeepEqual(history[0].Objections, []string{"price"}) {
+		t.Errorf("first entry = %+v", history[0])
+	}
+	if history[1].Intent != "buying" || history[1].SentimentScore != 0.9 || len(history[1].Emotions) != 0 || history[1].Objections == nil {
+		t.Errorf("second entry = %+v", history[1])
+	}
+
+	// Other tenants can't read it
+	if other, err := storage.GetAnalysisHistory("other-tenant", conv.ID); err != nil || len(other) != 0 {
+		t.Errorf("GetAnalysisHistory from another tenant = %v, %v; want nothing", other, err)
+	}
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+)
+
+// AnalyticsConfigStorage handles per-tenant analytics config overrides, stored as JSON
+type AnalyticsConfigStorage struct {
+	client *Client
+}
+
+// NewAnalyticsConfigStorage creates a new analytics config storage instance
+func NewAnalyticsConfigStorage(client *Client) *AnalyticsConfigStorage {
+	return &AnalyticsConfigStorage{client: client}
+}
+
+// ListAnalyticsConfigs returns every tenant's analytics config JSON by tenant ID
+func (s *AnalyticsConfigStorage) ListAnalyticsConfigs() (map[string]string, error) {
+	rows, err := s.client.DB.Query(`SELECT tenant_id, config_json FROM tenant_analytics_config`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics configs: %w", err)
+	}
+	defer rows.Close()
+
+	configs := make(map[string]string)
+	for rows.Next() {
+		var tenantID, configJSON string
+		if err := rows.Scan(&tenantID, &configJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan analytics config: %w", err)
+		}
+		configs[tenantID] = configJSON
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list analytics configs: %w", err)
+	}
+	return configs, nil
+}
+
+// SaveAnalyticsConfig creates or replaces a tenant's analytics config JSON
+func (s *AnalyticsConfigStorage) SaveAnalyticsConfig(tenantID, configJSON string) error {
+	_, err := s.client.DB.Exec(`
+		INSERT INTO tenant_analytics_config (tenant_id, config_json, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			config_json = excluded.config_json,
+			updated_at = excluded.updated_at
+	`, tenantID, configJSON, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save analytics config: %w", err)
+	}
+	return nil
+}
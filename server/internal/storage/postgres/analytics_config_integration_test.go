@@ -0,0 +1,28 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+)
+
+func TestAnalyticsConfigSaveAndList(t *testing.T) {
+	storage := NewAnalyticsConfigStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM tenant_analytics_config WHERE tenant_id = $1", tenantID) })
+
+	if err := storage.SaveAnalyticsConfig(tenantID, `{"churn_risk_threshold": 0.5}`); err != nil {
+		t.Fatalf("SaveAnalyticsConfig: %v", err)
+	}
+	if err := storage.SaveAnalyticsConfig(tenantID, `{"churn_risk_threshold": 0.7}`); err != nil {
+		t.Fatalf("second SaveAnalyticsConfig: %v", err)
+	}
+
+	configs, err := storage.ListAnalyticsConfigs()
+	if err != nil {
+		t.Fatalf("ListAnalyticsConfigs: %v", err)
+	}
+	if got := configs[tenantID]; got != `{"churn_risk_threshold": 0.7}` {
+		t.Errorf("config = %q, want the replaced config", got)
+	}
+}
@@ -0,0 +1,119 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestAssignAgentSetsAndClearsAssignment(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	agent := newTestUser(t, users, "assign.agent@example.com", models.RoleAgent)
+	conv := newTestConversation(t, storage, nil, "active")
+
+	if err := storage.AssignAgent(testTenantID, conv.ID, agent.ID); err != nil {
+		t.Fatalf("AssignAgent: %v", err)
+	}
+	got, err := storage.GetConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if got.AssignedAgentID == nil || *got.AssignedAgentID != agent.ID {
+		t.Fatalf("assigned_agent_id = %v, want %s", got.AssignedAgentID, agent.ID)
+	}
+
+	// An empty agent ID unassigns the conversation
+	if err := storage.AssignAgent(testTenantID, conv.ID, ""); err != nil {
+		t.Fatalf("AssignAgent(unassign): %v", err)
+	}
+	assigned, err := storage.GetAssignedAgent(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetAssignedAgent: %v", err)
+	}
+	if assigned != nil {
+		t.Errorf("assigned agent after unassign = %q, want none", *assigned)
+	}
+}
+
+func TestAssignAgentRejectsInvalidAssignees(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	customer := newTestUser(t, users, "assign.customer@example.com", models.RoleCustomer)
+	conv := newTestConversation(t, storage, &customer.ID, "active")
+
+	// An agent of another tenant
+	otherTenantID := "test-" + uuid.New().String()
+	now := time.Now().UTC().Truncate(time.Second)
+	otherAgent := &models.User{
+		ID:        uuid.New().String(),
+		TenantID:  otherTenantID,
+		Email:     "assign.other." + otherTenantID + "@example.com",
+		Role:      models.RoleAgent,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := users.CreateUser(otherTenantID, otherAgent); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM users WHERE tenant_id = $1", otherTenantID)
+	})
+
+	cases := map[string]string{
+		"cross-tenant agent": otherAgent.ID,
+		"customer":           customer.ID,
+		"unknown user":       uuid.New().String(),
+	}
+	for name, agentID := range cases {
+		if err := storage.AssignAgent(testTenantID, conv.ID, agentID); !errors.Is(err, ErrInvalidAssignee) {
+			t.Errorf("%s: AssignAgent error = %v, want ErrInvalidAssignee", name, err)
+		}
+	}
+
+	got, err := storage.GetAssignedAgent(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetAssignedAgent: %v", err)
+	}
+	if got != nil {
+		t.Errorf("conversation was assigned to %q despite the rejections", *got)
+	}
+
+	// Conversations of another tenant can't be assigned either
+	agent := newTestUser(t, users, "assign.agent2@example.com", models.RoleAgent)
+	if err := storage.AssignAgent(otherTenantID, conv.ID, ""); err == nil {
+		t.Error("expected assigning another tenant's conversation to fail")
+	}
+	if err := storage.AssignAgent(testTenantID, uuid.New().String(), agent.ID); err == nil {
+		t.Error("expected assigning an unknown conversation to fail")
+	}
+}
+
+func TestListConversationsFiltersByAgent(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	agent := newTestUser(t, users, "assign.filter@example.com", models.RoleAgent)
+	mine := newTestConversation(t, storage, nil, "active")
+	newTestConversation(t, storage, nil, "active")
+
+	if err := storage.AssignAgent(testTenantID, mine.ID, agent.ID); err != nil {
+		t.Fatalf("AssignAgent: %v", err)
+	}
+
+	listed, _, err := storage.ListConversations(testTenantID, &ConversationFilters{AgentID: agent.ID}, 10, "")
+	if err != nil {
+		t.Fatalf("ListConversations: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != mine.ID {
+		t.Fatalf("ListConversations(agent) = %d conversations, want only %s", len(listed), mine.ID)
+	}
+	if listed[0].AssignedAgentID == nil || *listed[0].AssignedAgentID != agent.ID {
+		t.Errorf("listed assigned_agent_id = %v, want %s", listed[0].AssignedAgentID, agent.ID)
+	}
+}
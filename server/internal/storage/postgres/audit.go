@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is a single audit trail entry. OldValue/NewValue hold JSON snapshots.
+type AuditLog struct {
+	ID           string    `json:"id"`
+	TenantID     string    `json:"tenant_id"`
+	UserID       *string   `json:"user_id,omitempty"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   *string   `json:"resource_id,omitempty"`
+	OldValue     *string   `json:"old_value,omitempty"`
+	NewValue     *string   `json:"new_value,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditStorage handles audit log persistence
+type AuditStorage struct {
+	client *Client
+}
+
+// NewAuditStorage creates a new audit storage instance
+func NewAuditStorage(client *Client) *AuditStorage {
+	return &AuditStorage{client: client}
+}
+
+// Record appends an entry to the audit log
+func (s *AuditStorage) Record(entry *AuditLog) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO audit_logs (id, tenant_id, user_id, action, resource_type, resource_id, old_value, new_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := s.client.DB.Exec(query, entry.ID, entry.TenantID, entry.UserID, entry.Action, entry.ResourceType,
+		entry.ResourceID, entry.OldValue, entry.NewValue, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
+
+// ListConversationModerationLogs returns the content moderation entries for a conversation, oldest
+// first: messages flagged on ingestion and suggestions blocked for the conversation
+func (s *AuditStorage) ListConversationModerationLogs(tenantID, conversationID string) ([]*AuditLog, error) {
+	query := `
+		SELECT id, tenant_id, user_id, action, resource_type, resource_id, old_value, new_value, created_at
+		FROM audit_logs
+		WHERE tenant_id = $1 AND (
+			(action = 'content.blocked' AND resource_type = 'conversation' AND resource_id = $2)
+			OR (action = 'message.flagged' AND resource_type = 'message'
+				AND resource_id IN (SELECT id FROM messages WHERE conversation_id = $3))
+		)
+		ORDER BY created_at ASC
+	`
+	rows, err := s.client.DB.Query(query, tenantID, conversationID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moderation audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditLogRows(rows)
+}
+
+// ListAuditLogs lists a tenant's audit log, newest first, one page at a time. resourceType narrows
+// the list to one resource type when set. Pass an empty cursor for the first page and the returned
+// cursor for the next one; the returned cursor is empty on the last page.
+func (s *AuditStorage) ListAuditLogs(tenantID, resourceType string, limit int, cursor string) ([]*AuditLog, string, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if resourceType != "" {
+		args = append(args, resourceType)
+		conditions = append(conditions, fmt.Sprintf("resource_type = $%d", len(args)))
+	}
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		args = append(args, createdAt, createdAt, id)
+		conditions = append(conditions, fmt.Sprintf("(created_at < $%d OR (created_at = $%d AND id < $%d))",
+			len(args)-2, len(args)-1, len(args)))
+	}
+	// Fetch one extra row to know whether there is a next page
+	args = append(args, limit+1)
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, user_id, action, resource_type, resource_id, old_value, new_value, created_at
+		FROM audit_logs
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditLogRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return entries, nextCursor, nil
+}
+
+// scanAuditLogRows scans rows of (id, tenant_id, user_id, action, resource_type, resource_id,
+// old_value, new_value, created_at)
+func scanAuditLogRows(rows *sql.Rows) ([]*AuditLog, error) {
+	entries := []*AuditLog{}
+	for rows.Next() {
+		entry := &AuditLog{}
+		var userID, resourceID, oldValue, newValue sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.TenantID, &userID, &entry.Action, &entry.ResourceType,
+			&resourceID, &oldValue, &newValue, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		entry.UserID = nullStringPtr(userID)
+		entry.ResourceID = nullStringPtr(resourceID)
+		entry.OldValue = nullStringPtr(oldValue)
+		entry.NewValue = nullStringPtr(newValue)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit logs: %w", err)
+	}
+	return entries, nil
+}
+
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// AuditValue marshals v into a JSON string for OldValue/NewValue
+func AuditValue(v interface{}) *string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	value := string(data)
+	return &value
+}
@@ -0,0 +1,124 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestListConversationModerationLogs(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	audit := NewAuditStorage(testClient)
+	conv := newTestConversation(t, conversations, nil, "active")
+	other := newTestConversation(t, conversations, nil, "active")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	confidence := 0.92
+	msg := &models.Message{
+		ID: uuid.New().String(), ConversationID: conv.ID, Sender: "agent", Content: "Sure, it ships today",
+		Channel: "web", Timestamp: now, CreatedAt: now, IsAutoReply: true, SuggestionConfidence: &confidence,
+	}
+	if err := conversations.CreateMessage(conv.TenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+
+	entries := []*AuditLog{
+		{TenantID: testTenantID, Action: "content.blocked", ResourceType: "conversation", ResourceID: &conv.ID,
+			NewValue: AuditValue(map[string]interface{}{"stage": "suggestion"}), CreatedAt: now.Add(2 * time.Second)},
+		{TenantID: testTenantID, Action: "message.flagged", ResourceType: "message", ResourceID: &msg.ID,
+			NewValue: AuditValue(map[string]interface{}{"conversation_id": conv.ID}), CreatedAt: now.Add(time.Second)},
+		// Not moderation, another conversation and another tenant
+		{TenantID: testTenantID, Action: "conversation.deleted", ResourceType: "conversation", ResourceID: &conv.ID, CreatedAt: now},
+		{TenantID: testTenantID, Action: "content.blocked", ResourceType: "conversation", ResourceID: &other.ID, CreatedAt: now},
+		{TenantID: "other-tenant", Action: "content.blocked", ResourceType: "conversation", ResourceID: &conv.ID, CreatedAt: now},
+	}
+	for _, entry := range entries {
+		if err := audit.Record(entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM audit_logs WHERE tenant_id IN ($1, $2)", testTenantID, "other-tenant")
+	})
+
+	got, err := audit.ListConversationModerationLogs(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("ListConversationModerationLogs: %v", err)
+	}
+	if len(got) != 2 || got[0].Action != "message.flagged" || got[1].Action != "content.blocked" {
+		t.Fatalf("moderation logs = %+v, want message.flagged then content.blocked", got)
+	}
+	if got[0].ResourceID == nil || *got[0].ResourceID != msg.ID || got[1].NewValue == nil {
+		t.Errorf("moderation log fields not scanned: %+v", got)
+	}
+
+	messages, err := conversations.GetMessagesByConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversation: %v", err)
+	}
+	if len(messages) != 1 || !messages[0].IsAutoReply || messages[0].SuggestionConfidence == nil || *messages[0].SuggestionConfidence != confidence {
+		t.Errorf("auto-reply message = %+v, want is_auto_reply with confidence %.2f", messages[0], confidence)
+	}
+}
+
+func TestListAuditLogs(t *testing.T) {
+	audit := NewAuditStorage(testClient)
+	tenantID := "audit-" + uuid.New().String()
+	now := time.Now().UTC().Truncate(time.Second)
+	ruleID, productID := "r1", "p1"
+
+	entries := []*AuditLog{
+		{TenantID: tenantID, Action: "rule.created", ResourceType: "rule", ResourceID: &ruleID,
+			NewValue: AuditValue(map[string]string{"name": "pricing"}), CreatedAt: now},
+		{TenantID: tenantID, Action: "product.updated", ResourceType: "product", ResourceID: &productID, CreatedAt: now.Add(time.Second)},
+		{TenantID: tenantID, Action: "rule.updated", ResourceType: "rule", ResourceID: &ruleID,
+			OldValue: AuditValue(map[string]string{"name": "pricing"}), NewValue: AuditValue(map[string]string{"name": "price"}),
+			CreatedAt: now.Add(2 * time.Second)},
+		{TenantID: "other-" + tenantID, Action: "rule.updated", ResourceType: "rule", CreatedAt: now},
+	}
+	for _, entry := range entries {
+		if err := audit.Record(entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM audit_logs WHERE tenant_id IN ($1, $2)", tenantID, "other-"+tenantID)
+	})
+
+	page, next, err := audit.ListAuditLogs(tenantID, "", 2, "")
+	if err != nil {
+		t.Fatalf("ListAuditLogs: %v", err)
+	}
+	if len(page) != 2 || page[0].Action != "rule.updated" || page[1].Action != "product.updated" || next == "" {
+		t.Fatalf("first page = %+v next=%q, want the two newest entries and a cursor", page, next)
+	}
+	if page[0].OldValue == nil || *page[0].OldValue != `{"name":"pricing"}` || page[0].NewValue == nil || *page[0].NewValue != `{"name":"price"}` {
+		t.Errorf("values = %v -> %v, want pricing -> price", page[0].OldValue, page[0].NewValue)
+	}
+
+	page, next, err = audit.ListAuditLogs(tenantID, "", 2, next)
+	if err != nil {
+		t.Fatalf("ListAuditLogs page 2: %v", err)
+	}
+	if len(page) != 1 || page[0].Action != "rule.created" || next != "" {
+		t.Errorf("second page = %+v next=%q, want the oldest entry and no cursor", page, next)
+	}
+
+	page, _, err = audit.ListAuditLogs(tenantID, "rule", 10, "")
+	if err != nil {
+		t.Fatalf("ListAuditLogs by resource type: %v", err)
+	}
+	if len(page) != 2 || page[0].ResourceType != "rule" || page[1].ResourceType != "rule" {
+		t.Errorf("rule entries = %+v, want 2", page)
+	}
+
+	if _, _, err := audit.ListAuditLogs(tenantID, "", 10, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("invalid cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -20,13 +20,14 @@ func NewAutoReplyStorage(client *Client) *AutoReplyStorage {
 // GetGlobalConfig retrieves global auto-reply configuration for a tenant
 func (s *AutoReplyStorage) GetGlobalConfig(tenantID string) (*models.AutoReplyGlobalConfig, error) {
 	query := `
-		SELECT tenant_id, enabled, confidence_threshold, updated_at
+		SELECT tenant_id, enabled, confidence_threshold, out_of_hours_message, updated_at
 		FROM auto_reply_global
 		WHERE tenant_id = $1
 	`
 	config := &models.AutoReplyGlobalConfig{}
+	var outOfHoursMessage sql.NullString
 	err := s.client.DB.QueryRow(query, tenantID).Scan(
-		&config.TenantID, &config.Enabled, &config.ConfidenceThreshold, &config.UpdatedAt,
+		&config.TenantID, &config.Enabled, &config.ConfidenceThreshold, &outOfHoursMessage, &config.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		// Return default config if not found
@@ -39,6 +40,7 @@ func (s *AutoReplyStorage) GetGlobalConfig(tenantID string) (*models.AutoReplyGl
 	if err != nil {
 		return nil, fmt.Errorf("failed to get global config: %w", err)
 	}
+	config.OutOfHoursMessage = outOfHoursMessage.String
 	return config, nil
 }
 
@@ -46,10 +48,10 @@ func (s *AutoReplyStorage) GetGlobalConfig(tenantID string) (*models.AutoReplyGl
 func (s *AutoReplyStorage) UpdateGlobalConfig(config *models.AutoReplyGlobalConfig) error {
 	if s.client.DBType == "sqlite" {
 		query := `
-			INSERT OR REPLACE INTO auto_reply_global (tenant_id, enabled, confidence_threshold, updated_at)
-			VALUES ($1, $2, $3, $4)
+			INSERT OR REPLACE INTO auto_reply_global (tenant_id, enabled, confidence_threshold, out_of_hours_message, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
 		`
-		_, err := s.client.DB.Exec(query, config.TenantID, config.Enabled, config.ConfidenceThreshold, config.UpdatedAt)
+		_, err := s.client.DB.Exec(query, config.TenantID, config.Enabled, config.ConfidenceThreshold, config.OutOfHoursMessage, config.UpdatedAt)
 		if err != nil {
 			return fmt.Errorf("failed to update global config: %w", err)
 		}
@@ -57,14 +59,15 @@ func (s *AutoReplyStorage) UpdateGlobalConfig(config *models.AutoReplyGlobalConf
 	}
 
 	query := `
-		INSERT INTO auto_reply_global (tenant_id, enabled, confidence_threshold, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO auto_reply_global (tenant_id, enabled, confidence_threshold, out_of_hours_message, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT(tenant_id) DO UPDATE SET
 			enabled = excluded.enabled,
 			confidence_threshold = excluded.confidence_threshold,
+			out_of_hours_message = excluded.out_of_hours_message,
 			updated_at = excluded.updated_at
 	`
-	_, err := s.client.DB.Exec(query, config.TenantID, config.Enabled, config.ConfidenceThreshold, config.UpdatedAt)
+	_, err := s.client.DB.Exec(query, config.TenantID, config.Enabled, config.ConfidenceThreshold, config.OutOfHoursMessage, config.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update global config: %w", err)
 	}
@@ -133,3 +136,24 @@ func (s *AutoReplyStorage) UpdateConversationConfig(config *models.AutoReplyConv
 }
 
 
+
+// GetLastAutoReplyText returns the content of the conversation's most recent auto-reply, or "" if none was sent
+func (s *ConversationStorage) GetLastAutoReplyText(tenantID, conversationID string) (string, error) {
+	query := `
+		SELECT m.content
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.tenant_id = $1 AND m.conversation_id = $2 AND m.is_auto_reply = TRUE AND m.deleted_at IS NULL
+		ORDER BY m.timestamp DESC
+		LIMIT 1
+	`
+	var content string
+	err := s.client.DB.QueryRow(query, tenantID, conversationID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get last auto-reply: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestGetLastAutoReplyText(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+
+	if text, err := storage.GetLastAutoReplyText(testTenantID, conv.ID); err != nil || text != "" {
+		t.Fatalf("GetLastAutoReplyText with no auto-replies = %q, %v; want empty", text, err)
+	}
+
+	base := time.Now().UTC().Truncate(time.Second)
+	for i, msg := range []*models.Message{
+		{Sender: "agent", Content: "first auto-reply", IsAutoReply: true},
+		{Sender: "agent", Content: "second auto-reply", IsAutoReply: true},
+		{Sender: "agent", Content: "typed by an agent"},
+	} {
+		msg.ID = uuid.New().String()
+		msg.ConversationID = conv.ID
+		msg.Channel = "web"
+		msg.Language = "en"
+		msg.Timestamp = base.Add(time.Duration(i) * time.Second)
+		msg.CreatedAt = msg.Timestamp
+		if err := storage.CreateMessage(conv.TenantID, msg); err != nil {
+			t.Fatalf("CreateMessage: %v", err)
+		}
+	}
+
+	text, err := storage.GetLastAutoReplyText(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetLastAutoReplyText: %v", err)
+	}
+	if text != "second auto-reply" {
+		t.Errorf("GetLastAutoReplyText = %q, want the latest auto-reply", text)
+	}
+	if text, _ := storage.GetLastAutoReplyText("other-tenant", conv.ID); text != "" {
+		t.Errorf("another tenant read auto-reply %q", text)
+	}
+}
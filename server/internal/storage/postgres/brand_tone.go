@@ -2,10 +2,30 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// MaxBrandToneLength is the longest brand tone description accepted, in characters
+const MaxBrandToneLength = 500
+
+// ErrInvalidBrandTone is returned for an empty or overlong brand tone
+var ErrInvalidBrandTone = errors.New("invalid brand tone")
+
+// ValidateBrandTone checks that a brand tone is non-empty and at most MaxBrandToneLength characters
+func ValidateBrandTone(tone string) error {
+	if strings.TrimSpace(tone) == "" {
+		return fmt.Errorf("%w: tone is required", ErrInvalidBrandTone)
+	}
+	if n := utf8.RuneCountInString(tone); n > MaxBrandToneLength {
+		return fmt.Errorf("%w: tone is %d characters, the maximum is %d", ErrInvalidBrandTone, n, MaxBrandToneLength)
+	}
+	return nil
+}
+
 // BrandToneStorage handles brand tone configuration storage
 type BrandToneStorage struct {
 	client *Client
@@ -36,16 +56,12 @@ func (s *BrandToneStorage) GetBrandTone(tenantID string) (string, error) {
 	return tone, nil
 }
 
-// SetBrandTone sets brand tone for a tenant
-func (s *BrandToneStorage) SetBrandTone(tenantID, tone string) error {
-	// Validate tone
-	validTones := map[string]bool{
-		"Professional":   true,
-		"Friendly":       true,
-		"Sales-focused":  true,
-	}
-	if !validTones[tone] {
-		return fmt.Errorf("invalid tone: %s (must be Professional, Friendly, or Sales-focused)", tone)
+// UpdateBrandTone sets a tenant's brand tone. The tone is a free-text description such as
+// "Technical but warm", stored as given apart from surrounding whitespace.
+func (s *BrandToneStorage) UpdateBrandTone(tenantID, tone string) error {
+	tone = strings.TrimSpace(tone)
+	if err := ValidateBrandTone(tone); err != nil {
+		return err
 	}
 
 	query := `
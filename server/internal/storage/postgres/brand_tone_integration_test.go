@@ -0,0 +1,45 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBrandTonePersistsCustomDescriptions(t *testing.T) {
+	storage := NewBrandToneStorage(testClient)
+	tenantID := uuid.New().String()
+	t.Cleanup(func() { storage.DeleteBrandTone(tenantID) })
+
+	if tone, err := storage.GetBrandTone(tenantID); err != nil || tone != "Professional" {
+		t.Fatalf("GetBrandTone without a tone = %q, %v; want Professional", tone, err)
+	}
+
+	tone := `Technical but warm — explain "why", not just "how"`
+	if err := storage.UpdateBrandTone(tenantID, "  "+tone+"\n"); err != nil {
+		t.Fatalf("UpdateBrandTone: %v", err)
+	}
+	if got, err := storage.GetBrandTone(tenantID); err != nil || got != tone {
+		t.Fatalf("GetBrandTone = %q, %v; want %q", got, err, tone)
+	}
+
+	// 500 characters is the limit, counted in characters rather than bytes
+	longest := strings.Repeat("é", MaxBrandToneLength)
+	if err := storage.UpdateBrandTone(tenantID, longest); err != nil {
+		t.Fatalf("UpdateBrandTone with %d characters: %v", MaxBrandToneLength, err)
+	}
+	if got, _ := storage.GetBrandTone(tenantID); got != longest {
+		t.Errorf("GetBrandTone returned %d characters, want the %d-character tone", len([]rune(got)), MaxBrandToneLength)
+	}
+
+	if err := storage.UpdateBrandTone(tenantID, longest+"é"); !errors.Is(err, ErrInvalidBrandTone) {
+		t.Errorf("UpdateBrandTone over the limit = %v, want ErrInvalidBrandTone", err)
+	}
+	if got, _ := storage.GetBrandTone(tenantID); got != longest {
+		t.Error("expected a rejected tone to leave the stored tone unchanged")
+	}
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BusinessHoursWindow is one opening period, e.g. Monday 09:00-17:00
+type BusinessHoursWindow struct {
+	DayOfWeek int    `json:"day_of_week"` // 0 = Sunday ... 6 = Saturday
+	OpenTime  string `json:"open_time"`   // HH:MM in the tenant's timezone
+	CloseTime string `json:"close_time"`  // HH:MM, exclusive; 24:00 closes at midnight
+}
+
+// BusinessHoursConfig is when a tenant's team is available. Auto-reply only answers within these hours.
+type BusinessHoursConfig struct {
+	TenantID  string                `json:"tenant_id"`
+	Timezone  string                `json:"timezone"` // IANA name, e.g. Asia/Kolkata
+	Schedule  []BusinessHoursWindow `json:"schedule"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// BusinessHoursStorage handles tenant business hours configuration
+type BusinessHoursStorage struct {
+	client *Client
+}
+
+// NewBusinessHoursStorage creates a new business hours storage instance
+func NewBusinessHoursStorage(client *Client) *BusinessHoursStorage {
+	return &BusinessHoursStorage{client: client}
+}
+
+// GetBusinessHours retrieves a tenant's business hours, or nil if none are configured
+func (s *BusinessHoursStorage) GetBusinessHours(tenantID string) (*BusinessHoursConfig, error) {
+	config := &BusinessHoursConfig{}
+	var scheduleJSON string
+	err := s.client.DB.QueryRow(
+		"SELECT tenant_id, timezone, schedule, updated_at FROM business_hours_config WHERE tenant_id = $1",
+		tenantID,
+	).Scan(&config.TenantID, &config.Timezone, &scheduleJSON, &config.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business hours: %w", err)
+	}
+	if err := json.Unmarshal([]byte(scheduleJSON), &config.Schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal business hours schedule: %w", err)
+	}
+	return config, nil
+}
+
+// SetBusinessHours creates or replaces a tenant's business hours
+func (s *BusinessHoursStorage) SetBusinessHours(config *BusinessHoursConfig) error {
+	if config.Schedule == nil {
+		config.Schedule = []BusinessHoursWindow{}
+	}
+	scheduleJSON, err := json.Marshal(config.Schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal business hours schedule: %w", err)
+	}
+	config.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO business_hours_config (tenant_id, timezone, schedule, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			timezone = excluded.timezone,
+			schedule = excluded.schedule,
+			updated_at = excluded.updated_at
+	`
+	if _, err := s.client.DB.Exec(query, config.TenantID, config.Timezone, string(scheduleJSON), config.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to set business hours: %w", err)
+	}
+	return nil
+}
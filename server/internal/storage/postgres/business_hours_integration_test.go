@@ -0,0 +1,52 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestBusinessHoursRoundTrip(t *testing.T) {
+	storage := NewBusinessHoursStorage(testClient)
+	tenantID := "hours-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM business_hours_config WHERE tenant_id = $1", tenantID)
+		testClient.DB.Exec("DELETE FROM auto_reply_global WHERE tenant_id = $1", tenantID)
+	})
+
+	if config, err := storage.GetBusinessHours(tenantID); err != nil || config != nil {
+		t.Fatalf("GetBusinessHours before set = %+v, %v; want nil", config, err)
+	}
+
+	weekdays := []BusinessHoursWindow{{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "17:00"}, {DayOfWeek: 2, OpenTime: "09:00", CloseTime: "17:00"}}
+	if err := storage.SetBusinessHours(&BusinessHoursConfig{TenantID: tenantID, Timezone: "UTC", Schedule: weekdays}); err != nil {
+		t.Fatalf("SetBusinessHours: %v", err)
+	}
+	saturday := []BusinessHoursWindow{{DayOfWeek: 6, OpenTime: "10:00", CloseTime: "14:00"}}
+	if err := storage.SetBusinessHours(&BusinessHoursConfig{TenantID: tenantID, Timezone: "Asia/Kolkata", Schedule: saturday}); err != nil {
+		t.Fatalf("SetBusinessHours (replace): %v", err)
+	}
+
+	config, err := storage.GetBusinessHours(tenantID)
+	if err != nil || config == nil {
+		t.Fatalf("GetBusinessHours = %+v, %v", config, err)
+	}
+	if config.Timezone != "Asia/Kolkata" || !reflect.DeepEqual(config.Schedule, saturday) {
+		t.Errorf("GetBusinessHours = %+v, want the replaced Saturday hours", config)
+	}
+
+	// The out-of-hours message is stored with the global auto-reply config
+	autoReply := NewAutoReplyStorage(testClient)
+	if err := autoReply.UpdateGlobalConfig(&models.AutoReplyGlobalConfig{TenantID: tenantID, Enabled: true, ConfidenceThreshold: 0.7, OutOfHoursMessage: "We're closed, back at 9am."}); err != nil {
+		t.Fatalf("UpdateGlobalConfig: %v", err)
+	}
+	global, err := autoReply.GetGlobalConfig(tenantID)
+	if err != nil || global.OutOfHoursMessage != "We're closed, back at 9am." {
+		t.Errorf("GetGlobalConfig = %+v, %v; want the out-of-hours message", global, err)
+	}
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ErrCategoryNotFound is returned for a category the tenant doesn't have
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrParentCategoryNotFound is returned when a category's parent isn't one of the tenant's categories
+var ErrParentCategoryNotFound = errors.New("parent category not found")
+
+// ErrCategoryCycle is returned when a category would become its own ancestor
+var ErrCategoryCycle = errors.New("a category can't be moved under itself or one of its subcategories")
+
+// CategoryPathSeparator joins category names into a path
+const CategoryPathSeparator = " > "
+
+// CategoryStorage handles a tenant's product category tree
+type CategoryStorage struct {
+	client *Client
+}
+
+// NewCategoryStorage creates a new category storage instance
+func NewCategoryStorage(client *Client) *CategoryStorage {
+	return &CategoryStorage{client: client}
+}
+
+// categoryTree is a tenant's categories indexed by ID and by parent
+type categoryTree struct {
+	byID     map[string]*models.ProductCategory
+	children map[string][]string // Parent ID ("" for top-level) to child IDs, in name order
+}
+
+// loadCategoryTree loads all of a tenant's categories
+func loadCategoryTree(db *sql.DB, tenantID string) (*categoryTree, error) {
+	rows, err := db.Query(`
+		SELECT id, tenant_id, name, parent_id, description, created_at, updated_at
+		FROM product_categories
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	tree := &categoryTree{byID: make(map[string]*models.ProductCategory), children: make(map[string][]string)}
+	for rows.Next() {
+		category := &models.ProductCategory{}
+		var parentID sql.NullString
+		if err := rows.Scan(&category.ID, &category.TenantID, &category.Name, &parentID, &category.Description,
+			&category.CreatedAt, &category.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		if parentID.Valid {
+			category.ParentID = &parentID.String
+		}
+		tree.byID[category.ID] = category
+		tree.children[parentID.String] = append(tree.children[parentID.String], category.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	for _, category := range tree.byID {
+		category.Path = tree.path(category.ID)
+	}
+	return tree, nil
+}
+
+// path joins the names from the root category down to id. A parent missing from the tree ends
+// the path, and a cycle in stored data is cut where it repeats.
+func (t *categoryTree) path(id string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for category := t.byID[id]; category != nil && !seen[category.ID]; {
+		seen[category.ID] = true
+		names = append(names, category.Name)
+		if category.ParentID == nil {
+			break
+		}
+		category = t.byID[*category.ParentID]
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, CategoryPathSeparator)
+}
+
+// subtree returns id and the IDs of all its descendants, parents before their children
+func (t *categoryTree) subtree(id string) []string {
+	if t.byID[id] == nil {
+		return nil
+	}
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// CreateCategory creates a category under its parent, or at the top level when ParentID is nil
+func (s *CategoryStorage) CreateCategory(tenantID string, category *models.ProductCategory) error {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return err
+	}
+	if category.ParentID != nil && tree.byID[*category.ParentID] == nil {
+		return ErrParentCategoryNotFound
+	}
+
+	_, err = s.client.DB.Exec(`
+		INSERT INTO product_categories (id, tenant_id, name, parent_id, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, category.ID, tenantID, category.Name, category.ParentID, category.Description, category.CreatedAt, category.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	category.TenantID = tenantID
+	category.Path = category.Name
+	if category.ParentID != nil {
+		category.Path = tree.byID[*category.ParentID].Path + CategoryPathSeparator + category.Name
+	}
+	return nil
+}
+
+// GetCategory retrieves a category with its path (tenant-scoped)
+func (s *CategoryStorage) GetCategory(tenantID, categoryID string) (*models.ProductCategory, error) {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	category := tree.byID[categoryID]
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// ListCategories lists a tenant's categories with their paths, ordered by path
+func (s *CategoryStorage) ListCategories(tenantID string) ([]*models.ProductCategory, error) {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]*models.ProductCategory, 0, len(tree.byID))
+	for _, category := range tree.byID {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Path < categories[j].Path })
+	return categories, nil
+}
+
+// UpdateCategory updates a category's name, description and parent. Moving a category under
+// itself or one of its descendants returns ErrCategoryCycle.
+func (s *CategoryStorage) UpdateCategory(tenantID string, category *models.ProductCategory) error {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return err
+	}
+	if tree.byID[category.ID] == nil {
+		return ErrCategoryNotFound
+	}
+	if category.ParentID != nil {
+		if tree.byID[*category.ParentID] == nil {
+			return ErrParentCategoryNotFound
+		}
+		for _, id := range tree.subtree(category.ID) {
+			if id == *category.ParentID {
+				return ErrCategoryCycle
+			}
+		}
+	}
+
+	category.UpdatedAt = time.Now()
+	_, err = s.client.DB.Exec(`
+		UPDATE product_categories
+		SET name = $1, parent_id = $2, description = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6
+	`, category.Name, category.ParentID, category.Description, category.UpdatedAt, category.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	tree.byID[category.ID] = category
+	category.Path = tree.path(category.ID)
+	return nil
+}
+
+// DeleteCategory deletes a category and all its subcategories. Their products are kept without a
+// category. It returns the IDs of the deleted categories.
+func (s *CategoryStorage) DeleteCategory(tenantID, categoryID string) ([]string, error) {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	ids := tree.subtree(categoryID)
+	if len(ids) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+
+	placeholders := make([]string, len(ids))
+	args := []interface{}{tenantID}
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	in := strings.Join(placeholders, ", ")
+
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE products SET category_id = NULL WHERE tenant_id = $1 AND category_id IN ("+in+")", args...); err != nil {
+		return nil, fmt.Errorf("failed to detach products from categories: %w", err)
+	}
+	// Children are deleted before their parents, so the parent_id foreign key holds throughout
+	for i := len(ids) - 1; i >= 0; i-- {
+		if _, err := tx.Exec("DELETE FROM product_categories WHERE id = $1 AND tenant_id = $2", ids[i], tenantID); err != nil {
+			return nil, fmt.Errorf("failed to delete category: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit category deletion: %w", err)
+	}
+	return ids, nil
+}
+
+// SubcategoryIDs returns the ID of a category and of all its descendants
+func (s *CategoryStorage) SubcategoryIDs(tenantID, categoryID string) ([]string, error) {
+	tree, err := loadCategoryTree(s.client.DB, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	ids := tree.subtree(categoryID)
+	if len(ids) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+	return ids, nil
+}
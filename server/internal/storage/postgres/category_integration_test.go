@@ -0,0 +1,230 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// newTestCategoryTree creates Automation > WhatsApp > Bots and a top-level Analytics category
+// for a fresh tenant
+func newTestCategoryTree(t *testing.T) (*CategoryStorage, string, map[string]*models.ProductCategory) {
+	t.Helper()
+	tenantID := "category-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM products WHERE tenant_id = $1", tenantID)
+		testClient.DB.Exec("DELETE FROM product_categories WHERE tenant_id = $1", tenantID)
+	})
+
+	storage := NewCategoryStorage(testClient)
+	now := time.Now().UTC()
+	categories := make(map[string]*models.ProductCategory)
+	for _, c := range []struct{ name, parent string }{
+		{"Automation", ""},
+		{"WhatsApp", "Automation"},
+		{"Bots", "WhatsApp"},
+		{"Analytics", ""},
+	} {
+		category := &models.ProductCategory{ID: uuid.New().String(), Name: c.name, CreatedAt: now, UpdatedAt: now}
+		if c.parent != "" {
+			category.ParentID = &categories[c.parent].ID
+		}
+		if err := storage.CreateCategory(tenantID, category); err != nil {
+			t.Fatalf("CreateCategory(%s): %v", c.name, err)
+		}
+		categories[c.name] = category
+	}
+	return storage, tenantID, categories
+}
+
+func newTestCategorizedProduct(t *testing.T, tenantID, name string, categoryID *string) *models.Product {
+	t.Helper()
+	now := time.Now().UTC()
+	product := &models.Product{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Name:          name,
+		Description:   name,
+		CategoryID:    categoryID,
+		Price:         100,
+		PriceCurrency: "INR",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := NewProductStorage(testClient).CreateProduct(tenantID, product); err != nil {
+		t.Fatalf("CreateProduct(%s): %v", name, err)
+	}
+	return product
+}
+
+func TestCategoryTreePaths(t *testing.T) {
+	storage, tenantID, categories := newTestCategoryTree(t)
+
+	if got := categories["Bots"].Path; got != "Automation > WhatsApp > Bots" {
+		t.Errorf("created path = %q, want Automation > WhatsApp > Bots", got)
+	}
+	bots, err := storage.GetCategory(tenantID, categories["Bots"].ID)
+	if err != nil {
+		t.Fatalf("GetCategory: %v", err)
+	}
+	if bots.Path != "Automation > WhatsApp > Bots" || bots.ParentID == nil || *bots.ParentID != categories["WhatsApp"].ID {
+		t.Errorf("GetCategory = %+v, want Bots under WhatsApp with its full path", bots)
+	}
+
+	list, err := storage.ListCategories(tenantID)
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	var paths []string
+	for _, category := range list {
+		paths = append(paths, category.Path)
+	}
+	want := []string{"Analytics", "Automation", "Automation > WhatsApp", "Automation > WhatsApp > Bots"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+
+	if _, err := storage.GetCategory("other-tenant", categories["Bots"].ID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("GetCategory from another tenant err = %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func TestCategorySubcategoryIDs(t *testing.T) {
+	storage, tenantID, categories := newTestCategoryTree(t)
+
+	ids, err := storage.SubcategoryIDs(tenantID, categories["Automation"].ID)
+	if err != nil {
+		t.Fatalf("SubcategoryIDs: %v", err)
+	}
+	want := []string{categories["Automation"].ID, categories["WhatsApp"].ID, categories["Bots"].ID}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("SubcategoryIDs = %v, want Automation, WhatsApp then Bots", ids)
+	}
+
+	if ids, _ := storage.SubcategoryIDs(tenantID, categories["Analytics"].ID); len(ids) != 1 {
+		t.Errorf("SubcategoryIDs of a leaf = %v, want only itself", ids)
+	}
+	if _, err := storage.SubcategoryIDs(tenantID, "missing"); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("SubcategoryIDs of a missing category err = %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func TestCategoryUpdateRejectsCycles(t *testing.T) {
+	storage, tenantID, categories := newTestCategoryTree(t)
+
+	automation := *categories["Automation"]
+	for _, parent := range []string{"Automation", "Bots"} {
+		automation.ParentID = &categories[parent].ID
+		if err := storage.UpdateCategory(tenantID, &automation); !errors.Is(err, ErrCategoryCycle) {
+			t.Errorf("moving Automation under %s err = %v, want ErrCategoryCycle", parent, err)
+		}
+	}
+	missing := "missing"
+	automation.ParentID = &missing
+	if err := storage.UpdateCategory(tenantID, &automation); !errors.Is(err, ErrParentCategoryNotFound) {
+		t.Errorf("moving Automation under a missing parent err = %v, want ErrParentCategoryNotFound", err)
+	}
+
+	// Moving a subtree updates the paths below it
+	whatsApp := *categories["WhatsApp"]
+	whatsApp.ParentID = &categories["Analytics"].ID
+	if err := storage.UpdateCategory(tenantID, &whatsApp); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if whatsApp.Path != "Analytics > WhatsApp" {
+		t.Errorf("moved path = %q, want Analytics > WhatsApp", whatsApp.Path)
+	}
+	bots, err := storage.GetCategory(tenantID, categories["Bots"].ID)
+	if err != nil {
+		t.Fatalf("GetCategory: %v", err)
+	}
+	if bots.Path != "Analytics > WhatsApp > Bots" {
+		t.Errorf("child path = %q, want Analytics > WhatsApp > Bots", bots.Path)
+	}
+}
+
+func TestProductsInCategoryIncludeSubcategories(t *testing.T) {
+	storage, tenantID, categories := newTestCategoryTree(t)
+	bot := newTestCategorizedProduct(t, tenantID, "WhatsApp Bot", &categories["Bots"].ID)
+	newTestCategorizedProduct(t, tenantID, "Dashboard", &categories["Analytics"].ID)
+	newTestCategorizedProduct(t, tenantID, "Uncategorized", nil)
+
+	products := NewProductStorage(testClient)
+	got, err := products.GetProduct(tenantID, bot.ID)
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if got.CategoryID == nil || *got.CategoryID != categories["Bots"].ID || got.CategoryPath != "Automation > WhatsApp > Bots" {
+		t.Errorf("GetProduct category = %v %q, want Bots with its full path", got.CategoryID, got.CategoryPath)
+	}
+
+	ids, err := storage.SubcategoryIDs(tenantID, categories["Automation"].ID)
+	if err != nil {
+		t.Fatalf("SubcategoryIDs: %v", err)
+	}
+	inAutomation, err := products.ListProductsInCategories(tenantID, ids)
+	if err != nil {
+		t.Fatalf("ListProductsInCategories: %v", err)
+	}
+	if len(inAutomation) != 1 || inAutomation[0].ID != bot.ID {
+		t.Errorf("products in Automation = %+v, want only the bot from its Bots subcategory", inAutomation)
+	}
+
+	all, err := products.ListProducts(tenantID)
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("ListProducts returned %d products, want 3", len(all))
+	}
+}
+
+func TestCategoryDeleteCascades(t *testing.T) {
+	storage, tenantID, categories := newTestCategoryTree(t)
+	bot := newTestCategorizedProduct(t, tenantID, "WhatsApp Bot", &categories["Bots"].ID)
+	dashboard := newTestCategorizedProduct(t, tenantID, "Dashboard", &categories["Analytics"].ID)
+
+	deleted, err := storage.DeleteCategory(tenantID, categories["Automation"].ID)
+	if err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	sort.Strings(deleted)
+	want := []string{categories["Automation"].ID, categories["WhatsApp"].ID, categories["Bots"].ID}
+	sort.Strings(want)
+	if !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted = %v, want Automation and its subcategories", deleted)
+	}
+
+	remaining, err := storage.ListCategories(tenantID)
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Name != "Analytics" {
+		t.Errorf("remaining categories = %+v, want only Analytics", remaining)
+	}
+
+	// Products in deleted categories are kept without a category
+	products := NewProductStorage(testClient)
+	got, err := products.GetProduct(tenantID, bot.ID)
+	if err != nil {
+		t.Fatalf("GetProduct after delete: %v", err)
+	}
+	if got.CategoryID != nil || got.CategoryPath != "" {
+		t.Errorf("product category = %v %q, want none", got.CategoryID, got.CategoryPath)
+	}
+	if got, _ := products.GetProduct(tenantID, dashboard.ID); got == nil || got.CategoryPath != "Analytics" {
+		t.Errorf("product in another category = %+v, want it untouched", got)
+	}
+
+	if _, err := storage.DeleteCategory(tenantID, categories["Automation"].ID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("deleting again err = %v, want ErrCategoryNotFound", err)
+	}
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -54,6 +55,11 @@ func NewClient() (*Client, error) {
 	return &Client{DB: db, DBType: dbType}, nil
 }
 
+// Ping checks the database is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return c.DB.PingContext(ctx)
+}
+
 // Close closes the database connection
 func (c *Client) Close() error {
 	return c.DB.Close()
@@ -3,14 +3,28 @@ package postgres
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"ai-conversation-platform/internal/models"
 )
 
+// ErrMessageAlreadyDeleted is returned when deleting a message that has already been soft-deleted
+var ErrMessageAlreadyDeleted = errors.New("message already deleted")
+
+// ConversationCloseListener is notified after a conversation's status changes to closed
+type ConversationCloseListener interface {
+	OnConversationClosed(tenantID, conversationID string)
+}
+
 // ConversationStorage handles conversation-related database operations
 type ConversationStorage struct {
-	client *Client
+	client         *Client
+	closeListeners []ConversationCloseListener
 }
 
 // NewConversationStorage creates a new conversation storage instance
@@ -18,31 +32,43 @@ func NewConversationStorage(client *Client) *ConversationStorage {
 	return &ConversationStorage{client: client}
 }
 
+// AddCloseListener adds a listener notified when UpdateConversation closes a conversation (optional)
+func (s *ConversationStorage) AddCloseListener(listener ConversationCloseListener) {
+	s.closeListeners = append(s.closeListeners, listener)
+}
+
 // CreateConversation creates a new conversation
 func (s *ConversationStorage) CreateConversation(tenantID string, conv *models.Conversation) error {
 	query := `
-		INSERT INTO conversations (id, tenant_id, customer_id, product_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO conversations (id, tenant_id, customer_id, product_id, status, override_language, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err := s.client.DB.Exec(query, conv.ID, tenantID, conv.CustomerID, conv.ProductID, conv.Status, conv.CreatedAt, conv.UpdatedAt)
+	err := s.client.withRetry("CreateConversation", tenantID, func() error {
+		_, err := s.client.DB.Exec(query, conv.ID, tenantID, conv.CustomerID, conv.ProductID, conv.Status, conv.OverrideLanguage, conv.CreatedAt, conv.UpdatedAt)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create conversation: %w", err)
 	}
 	return nil
 }
 
-// GetConversation retrieves a conversation by ID (tenant-scoped)
+// GetConversation retrieves a conversation by ID (tenant-scoped). Soft-deleted conversations are not found.
 func (s *ConversationStorage) GetConversation(tenantID, conversationID string) (*models.Conversation, error) {
 	query := `
-		SELECT id, tenant_id, customer_id, product_id, status, created_at, updated_at
+		SELECT id, tenant_id, customer_id, product_id, assigned_agent_id, status, resolution_type, override_language, transcript_sent_at, closed_at, created_at, updated_at
 		FROM conversations
-		WHERE id = $1 AND tenant_id = $2
+		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`
 	conv := &models.Conversation{}
 	var customerID sql.NullString
 	var productID sql.NullString
+	var assignedAgentID sql.NullString
+	var resolutionType sql.NullString
+	var overrideLanguage sql.NullString
+	var transcriptSentAt, closedAt sql.NullTime
 	err := s.client.DB.QueryRow(query, conversationID, tenantID).Scan(
-		&conv.ID, &conv.TenantID, &customerID, &productID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt,
+		&conv.ID, &conv.TenantID, &customerID, &productID, &assignedAgentID, &conv.Status, &resolutionType, &overrideLanguage, &transcriptSentAt, &closedAt, &conv.CreatedAt, &conv.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("conversation not found")
@@ -56,17 +82,65 @@ func (s *ConversationStorage) GetConversation(tenantID, conversationID string) (
 	if productID.Valid {
 		conv.ProductID = &productID.String
 	}
+	if assignedAgentID.Valid {
+		conv.AssignedAgentID = &assignedAgentID.String
+	}
+	if resolutionType.Valid {
+		conv.ResolutionType = &resolutionType.String
+	}
+	if overrideLanguage.Valid {
+		conv.OverrideLanguage = &overrideLanguage.String
+	}
+	if transcriptSentAt.Valid {
+		conv.TranscriptSentAt = &transcriptSentAt.Time
+	}
+	if closedAt.Valid {
+		conv.ClosedAt = &closedAt.Time
+	}
+	conv.Tags, err = listConversationTags(s.client.DB, tenantID, conv.ID)
+	if err != nil {
+		return nil, err
+	}
 	return conv, nil
 }
 
-// UpdateConversation updates conversation status (tenant-scoped)
-func (s *ConversationStorage) UpdateConversation(tenantID string, conv *models.Conversation) error {
+// SetOverrideLanguage sets the agent-chosen language for a conversation (tenant-scoped)
+func (s *ConversationStorage) SetOverrideLanguage(tenantID, conversationID, language string) error {
 	query := `
 		UPDATE conversations
-		SET status = $1, updated_at = $2
+		SET override_language = $1, updated_at = $2
 		WHERE id = $3 AND tenant_id = $4
 	`
-	result, err := s.client.DB.Exec(query, conv.Status, conv.UpdatedAt, conv.ID, tenantID)
+	result, err := s.client.DB.Exec(query, language, time.Now(), conversationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to set override language: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+	return nil
+}
+
+// UpdateConversation updates conversation status, resolution type and close time (tenant-scoped).
+// Close listeners are notified when the status changes to closed.
+func (s *ConversationStorage) UpdateConversation(tenantID string, conv *models.Conversation) error {
+	closing := false
+	if conv.Status == "closed" && len(s.closeListeners) > 0 {
+		var previousStatus string
+		err := s.client.DB.QueryRow("SELECT status FROM conversations WHERE id = $1 AND tenant_id = $2", conv.ID, tenantID).Scan(&previousStatus)
+		closing = err == nil && previousStatus != "closed"
+	}
+
+	query := `
+		UPDATE conversations
+		SET status = $1, resolution_type = $2, closed_at = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6
+	`
+	result, err := s.client.DB.Exec(query, conv.Status, conv.ResolutionType, conv.ClosedAt, conv.UpdatedAt, conv.ID, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to update conversation: %w", err)
 	}
@@ -77,6 +151,12 @@ func (s *ConversationStorage) UpdateConversation(tenantID string, conv *models.C
 	if rowsAffected == 0 {
 		return fmt.Errorf("conversation not found")
 	}
+
+	if closing {
+		for _, listener := range s.closeListeners {
+			listener.OnConversationClosed(tenantID, conv.ID)
+		}
+	}
 	return nil
 }
 
@@ -85,7 +165,7 @@ func (s *ConversationStorage) FindActiveConversationByCustomer(tenantID, custome
 	query := `
 		SELECT id, tenant_id, customer_id, product_id, status, created_at, updated_at
 		FROM conversations
-		WHERE tenant_id = $1 AND customer_id = $2 AND status = 'active'
+		WHERE tenant_id = $1 AND customer_id = $2 AND status = 'active' AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 		LIMIT 1
 	`
@@ -110,46 +190,125 @@ func (s *ConversationStorage) FindActiveConversationByCustomer(tenantID, custome
 	return conv, nil
 }
 
-// ListConversations lists conversations for a tenant with pagination
-// If customerID is provided (non-empty), only conversations for that customer are returned
-func (s *ConversationStorage) ListConversations(tenantID string, customerID *string, limit, offset int) ([]*models.Conversation, error) {
-	var query string
-	var args []interface{}
+// ConversationFilters narrows ListConversations. Empty fields don't filter.
+type ConversationFilters struct {
+	Status        string // active, closed or archived
+	CustomerID    string // Customers only see their own conversations; agents/admins see the whole tenant
+	ProductID     string
+	AgentID       string     // Assigned agent
+	CreatedAfter  *time.Time // Inclusive lower bound on created_at
+	CreatedBefore *time.Time // Inclusive upper bound on created_at
+	TagID         string     // Conversations carrying this tag
+	ClosedAfter   *time.Time // Inclusive lower bound on closed_at
+}
 
-	if customerID != nil && *customerID != "" {
-		// Filter by tenant and customer
-		query = `
-			SELECT id, tenant_id, customer_id, product_id, status, created_at, updated_at
-			FROM conversations
-			WHERE tenant_id = $1 AND customer_id = $2
-			ORDER BY updated_at DESC
-			LIMIT $3 OFFSET $4
-		`
-		args = []interface{}{tenantID, *customerID, limit, offset}
-	} else {
-		// Filter by tenant only (for agents/admins)
-		query = `
-			SELECT id, tenant_id, customer_id, product_id, status, created_at, updated_at
-			FROM conversations
-			WHERE tenant_id = $1
-			ORDER BY updated_at DESC
-			LIMIT $2 OFFSET $3
-		`
-		args = []interface{}{tenantID, limit, offset}
+// appendConditions adds a WHERE condition and its argument for each set filter
+func (f *ConversationFilters) appendConditions(conditions []string, args []interface{}) ([]string, []interface{}) {
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.CustomerID != "" {
+		add("customer_id = $%d", f.CustomerID)
 	}
+	if f.ProductID != "" {
+		add("product_id = $%d", f.ProductID)
+	}
+	if f.AgentID != "" {
+		add("assigned_agent_id = $%d", f.AgentID)
+	}
+	if f.CreatedAfter != nil {
+		add("created_at >= $%d", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		add("created_at <= $%d", *f.CreatedBefore)
+	}
+	if f.TagID != "" {
+		add("id IN (SELECT conversation_id FROM conversation_tags WHERE tag_id = $%d)", f.TagID)
+	}
+	if f.ClosedAfter != nil {
+		add("closed_at >= $%d", *f.ClosedAfter)
+	}
+	return conditions, args
+}
+
+// CountConversations counts a tenant's conversations matching filters
+func (s *ConversationStorage) CountConversations(tenantID string, filters ConversationFilters) (int, error) {
+	conditions, args := filters.appendConditions([]string{"tenant_id = $1", "deleted_at IS NULL"}, []interface{}{tenantID})
+
+	var count int
+	query := "SELECT COUNT(*) FROM conversations WHERE " + strings.Join(conditions, " AND ")
+	if err := s.client.DB.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count conversations: %w", err)
+	}
+	return count, nil
+}
+
+// ListConversations lists conversations for a tenant, most recently updated first, one page at a
+// time. Pass an empty cursor for the first page and the returned cursor for the next one; the
+// returned cursor is empty on the last page. Pages are keyed on (updated_at, id) rather than an
+// offset, so conversations created between calls don't shift later pages.
+// filters narrows the list; nil lists every conversation of the tenant.
+func (s *ConversationStorage) ListConversations(tenantID string, filters *ConversationFilters, limit int, cursor string) ([]*models.Conversation, string, error) {
+	conditions := []string{"tenant_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{tenantID}
+
+	if filters != nil {
+		conditions, args = filters.appendConditions(conditions, args)
+	}
+	if cursor != "" {
+		updatedAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		args = append(args, updatedAt, updatedAt, id)
+		conditions = append(conditions, fmt.Sprintf("(updated_at < $%d OR (updated_at = $%d AND id < $%d))",
+			len(args)-2, len(args)-1, len(args)))
+	}
+	// Fetch one extra row to know whether there is a next page
+	args = append(args, limit+1)
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, customer_id, product_id, assigned_agent_id, status, created_at, updated_at
+		FROM conversations
+		WHERE %s
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
 
 	rows, err := s.client.DB.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list conversations: %w", err)
+		return nil, "", fmt.Errorf("failed to list conversations: %w", err)
 	}
 	defer rows.Close()
 
+	conversations, err := scanConversationRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if limit > 0 && len(conversations) > limit {
+		conversations = conversations[:limit]
+		last := conversations[limit-1]
+		nextCursor = encodeCursor(last.UpdatedAt, last.ID)
+	}
+	return conversations, nextCursor, nil
+}
+
+// scanConversationRows scans rows of (id, tenant_id, customer_id, product_id, assigned_agent_id,
+// status, created_at, updated_at)
+func scanConversationRows(rows *sql.Rows) ([]*models.Conversation, error) {
 	var conversations []*models.Conversation
 	for rows.Next() {
 		conv := &models.Conversation{}
 		var customerIDVal sql.NullString
 		var productID sql.NullString
-		err := rows.Scan(&conv.ID, &conv.TenantID, &customerIDVal, &productID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt)
+		var assignedAgentID sql.NullString
+		err := rows.Scan(&conv.ID, &conv.TenantID, &customerIDVal, &productID, &assignedAgentID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan conversation: %w", err)
 		}
@@ -159,24 +318,83 @@ func (s *ConversationStorage) ListConversations(tenantID string, customerID *str
 		if productID.Valid {
 			conv.ProductID = &productID.String
 		}
+		if assignedAgentID.Valid {
+			conv.AssignedAgentID = &assignedAgentID.String
+		}
 		conversations = append(conversations, conv)
 	}
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating conversations: %w", err)
 	}
 	return conversations, nil
 }
 
-// CreateMessage creates a new message (immutable)
-func (s *ConversationStorage) CreateMessage(msg *models.Message) error {
+// SearchConversations finds a tenant's conversations with messages containing query, best matches
+// first. PostgreSQL uses full-text search on messages.content_tsv and ranks conversations by the
+// summed rank of their matching messages; SQLite falls back to a case-insensitive substring match
+// ranked by the number of matching messages. Deleted messages and conversations are not searched.
+func (s *ConversationStorage) SearchConversations(tenantID, query string, limit, offset int) ([]*models.Conversation, error) {
+	var sqlQuery string
+	var args []interface{}
+	if s.client.DBType == "sqlite" {
+		sqlQuery = `
+			SELECT c.id, c.tenant_id, c.customer_id, c.product_id, c.assigned_agent_id, c.status, c.created_at, c.updated_at
+			FROM conversations c
+			JOIN (
+				SELECT conversation_id, COUNT(*) AS score
+				FROM messages
+				WHERE deleted_at IS NULL AND LOWER(content) LIKE $1 ESCAPE '\'
+				GROUP BY conversation_id
+			) matches ON matches.conversation_id = c.id
+			WHERE c.tenant_id = $2 AND c.deleted_at IS NULL
+			ORDER BY matches.score DESC, c.updated_at DESC, c.id DESC
+			LIMIT $3 OFFSET $4
+		`
+		args = []interface{}{"%" + escapeLike(strings.ToLower(query)) + "%", tenantID, limit, offset}
+	} else {
+		sqlQuery = `
+			SELECT c.id, c.tenant_id, c.customer_id, c.product_id, c.assigned_agent_id, c.status, c.created_at, c.updated_at
+			FROM conversations c
+			JOIN (
+				SELECT conversation_id, SUM(ts_rank(content_tsv, plainto_tsquery('english', $1))) AS score
+				FROM messages
+				WHERE deleted_at IS NULL AND content_tsv @@ plainto_tsquery('english', $1)
+				GROUP BY conversation_id
+			) matches ON matches.conversation_id = c.id
+			WHERE c.tenant_id = $2 AND c.deleted_at IS NULL
+			ORDER BY matches.score DESC, c.updated_at DESC, c.id DESC
+			LIMIT $3 OFFSET $4
+		`
+		args = []interface{}{query, tenantID, limit, offset}
+	}
+
+	rows, err := s.client.DB.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search conversations: %w", err)
+	}
+	defer rows.Close()
+	return scanConversationRows(rows)
+}
+
+// escapeLike escapes LIKE wildcards so they match literally (with ESCAPE '\')
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// CreateMessage creates a new message (immutable). tenantID is the conversation's tenant.
+func (s *ConversationStorage) CreateMessage(tenantID string, msg *models.Message) error {
 	query := `
-		INSERT INTO messages (id, conversation_id, sender, content, channel, language, timestamp, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO messages (id, conversation_id, sender, content, channel, language, timestamp, created_at, is_auto_reply, suggestion_confidence, language_confidence)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := s.client.DB.Exec(query,
-		msg.ID, msg.ConversationID, msg.Sender, msg.Content,
-		msg.Channel, msg.Language, msg.Timestamp, msg.CreatedAt,
-	)
+	err := s.client.withRetry("CreateMessage", tenantID, func() error {
+		_, err := s.client.DB.Exec(query,
+			msg.ID, msg.ConversationID, msg.Sender, msg.Content,
+			msg.Channel, msg.Language, msg.Timestamp, msg.CreatedAt, msg.IsAutoReply, msg.SuggestionConfidence,
+			msg.LanguageConfidence,
+		)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
 	}
@@ -186,15 +404,12 @@ func (s *ConversationStorage) CreateMessage(msg *models.Message) error {
 // GetMessage retrieves a message by ID
 func (s *ConversationStorage) GetMessage(messageID string) (*models.Message, error) {
 	query := `
-		SELECT id, conversation_id, sender, content, channel, language, timestamp, created_at
+		SELECT id, conversation_id, sender, content, channel, language, timestamp, created_at, deleted_at, deleted_by,
+			is_auto_reply, suggestion_confidence, language_confidence
 		FROM messages
 		WHERE id = $1
 	`
-	msg := &models.Message{}
-	err := s.client.DB.QueryRow(query, messageID).Scan(
-		&msg.ID, &msg.ConversationID, &msg.Sender, &msg.Content,
-		&msg.Channel, &msg.Language, &msg.Timestamp, &msg.CreatedAt,
-	)
+	msg, err := scanMessage(s.client.DB.QueryRow(query, messageID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("message not found")
 	}
@@ -204,15 +419,77 @@ func (s *ConversationStorage) GetMessage(messageID string) (*models.Message, err
 	return msg, nil
 }
 
-// GetMessagesByConversation retrieves all messages for a conversation (tenant-scoped via conversation)
+// GetMessagesByConversation retrieves all non-deleted messages for a conversation (tenant-scoped via conversation)
 func (s *ConversationStorage) GetMessagesByConversation(tenantID, conversationID string) ([]*models.Message, error) {
+	return s.listMessages(tenantID, conversationID, false)
+}
+
+// GetMessagesByConversations retrieves the messages of several conversations of a tenant in one
+// query, keyed by conversation ID and ordered by timestamp. Soft-deleted messages are excluded.
+func (s *ConversationStorage) GetMessagesByConversations(tenantID string, conversationIDs []string) (map[string][]*models.Message, error) {
+	messages := make(map[string][]*models.Message, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return messages, nil
+	}
+
+	in, args := conversationIDList(tenantID, conversationIDs)
+	rows, err := s.client.DB.Query(`
+		SELECT m.id, m.conversation_id, m.sender, m.content, m.channel, m.language, m.timestamp, m.created_at, m.deleted_at, m.deleted_by,
+			m.is_auto_reply, m.suggestion_confidence, m.language_confidence
+		FROM messages m
+		INNER JOIN conversations c ON m.conversation_id = c.id
+		WHERE c.tenant_id = $1 AND m.conversation_id IN (`+in+`) AND m.deleted_at IS NULL
+		ORDER BY m.conversation_id, m.timestamp ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages[msg.ConversationID] = append(messages[msg.ConversationID], msg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating messages: %w", err)
+	}
+	return messages, nil
+}
+
+// conversationIDList returns the placeholders for an IN list of conversation IDs and the query
+// arguments, with tenantID as $1
+func conversationIDList(tenantID string, conversationIDs []string) (string, []interface{}) {
+	placeholders := make([]string, len(conversationIDs))
+	args := []interface{}{tenantID}
+	for i, id := range conversationIDs {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// GetMessagesByConversationIncludingDeleted retrieves all messages for a conversation,
+// including soft-deleted ones (admin compliance review only)
+func (s *ConversationStorage) GetMessagesByConversationIncludingDeleted(tenantID, conversationID string) ([]*models.Message, error) {
+	return s.listMessages(tenantID, conversationID, true)
+}
+
+func (s *ConversationStorage) listMessages(tenantID, conversationID string, includeDeleted bool) ([]*models.Message, error) {
 	query := `
-		SELECT m.id, m.conversation_id, m.sender, m.content, m.channel, m.language, m.timestamp, m.created_at
+		SELECT m.id, m.conversation_id, m.sender, m.content, m.channel, m.language, m.timestamp, m.created_at, m.deleted_at, m.deleted_by,
+			m.is_auto_reply, m.suggestion_confidence, m.language_confidence
 		FROM messages m
 		INNER JOIN conversations c ON m.conversation_id = c.id
 		WHERE m.conversation_id = $1 AND c.tenant_id = $2
-		ORDER BY m.timestamp ASC
 	`
+	if !includeDeleted {
+		query += " AND m.deleted_at IS NULL"
+	}
+	query += " ORDER BY m.timestamp ASC"
+
 	rows, err := s.client.DB.Query(query, conversationID, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
@@ -221,11 +498,7 @@ func (s *ConversationStorage) GetMessagesByConversation(tenantID, conversationID
 
 	var messages []*models.Message
 	for rows.Next() {
-		msg := &models.Message{}
-		err := rows.Scan(
-			&msg.ID, &msg.ConversationID, &msg.Sender, &msg.Content,
-			&msg.Channel, &msg.Language, &msg.Timestamp, &msg.CreatedAt,
-		)
+		msg, err := scanMessage(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
@@ -237,21 +510,120 @@ func (s *ConversationStorage) GetMessagesByConversation(tenantID, conversationID
 	return messages, nil
 }
 
-// CreateConversationMetadata creates or updates conversation metadata
-func (s *ConversationStorage) CreateConversationMetadata(metadata *models.ConversationMetadata) error {
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMessage scans a message row including its soft-delete, auto-reply and language confidence columns
+func scanMessage(row rowScanner) (*models.Message, error) {
+	msg := &models.Message{}
+	var language sql.NullString
+	var deletedAt sql.NullTime
+	var deletedBy sql.NullString
+	var suggestionConfidence sql.NullFloat64
+	err := row.Scan(
+		&msg.ID, &msg.ConversationID, &msg.Sender, &msg.Content,
+		&msg.Channel, &language, &msg.Timestamp, &msg.CreatedAt, &deletedAt, &deletedBy,
+		&msg.IsAutoReply, &suggestionConfidence, &msg.LanguageConfidence,
+	)
+	if err != nil {
+		return nil, err
+	}
+	msg.Language = language.String
+	if deletedAt.Valid {
+		msg.DeletedAt = &deletedAt.Time
+	}
+	if deletedBy.Valid {
+		msg.DeletedBy = &deletedBy.String
+	}
+	if suggestionConfidence.Valid {
+		msg.SuggestionConfidence = &suggestionConfidence.Float64
+	}
+	return msg, nil
+}
+
+// DeleteMessage soft-deletes a message and records the deletion for compliance.
+// Tenant ownership is verified through the message's conversation. Deleting a message
+// twice fails with ErrMessageAlreadyDeleted.
+func (s *ConversationStorage) DeleteMessage(tenantID, messageID, deletedBy, reason string) error {
+	var conversationID string
+	var deletedAt sql.NullTime
+	err := s.client.DB.QueryRow(`
+		SELECT m.conversation_id, m.deleted_at
+		FROM messages m
+		INNER JOIN conversations c ON m.conversation_id = c.id
+		WHERE m.id = $1 AND c.tenant_id = $2
+	`, messageID, tenantID).Scan(&conversationID, &deletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("message not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	if deletedAt.Valid {
+		return ErrMessageAlreadyDeleted
+	}
+
+	now := time.Now()
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE messages
+		SET deleted_at = $1, deleted_by = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`, now, deletedBy, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		// Deleted by a concurrent request since it was loaded
+		return ErrMessageAlreadyDeleted
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO message_deletions (id, message_id, conversation_id, tenant_id, deleted_by, reason, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, uuid.New().String(), messageID, conversationID, tenantID, deletedBy, reason, now); err != nil {
+		return fmt.Errorf("failed to record message deletion: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit message deletion: %w", err)
+	}
+	return nil
+}
+
+// CreateConversationMetadata creates or updates conversation metadata. tenantID is the
+// conversation's tenant.
+func (s *ConversationStorage) CreateConversationMetadata(tenantID string, metadata *models.ConversationMetadata) error {
+	return s.client.withRetry("CreateConversationMetadata", tenantID, func() error {
+		return s.createConversationMetadata(metadata)
+	})
+}
+
+func (s *ConversationStorage) createConversationMetadata(metadata *models.ConversationMetadata) error {
 	emotionsJSON, _ := json.Marshal(metadata.Emotions)
 	objectionsJSON, _ := json.Marshal(metadata.Objections)
 
 	// SQLite uses different ON CONFLICT syntax, so we'll use a simpler approach
 	if s.client.DBType == "sqlite" {
 		query := `
-			INSERT OR REPLACE INTO conversation_metadata (id, conversation_id, intent, intent_score, sentiment, sentiment_score, emotions, objections, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			INSERT OR REPLACE INTO conversation_metadata (id, conversation_id, intent, intent_score, sentiment, sentiment_score, sentiment_model, emotions, objections, complexity_score, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		`
 		_, err := s.client.DB.Exec(query,
 			metadata.ID, metadata.ConversationID, metadata.Intent, metadata.IntentScore,
-			metadata.Sentiment, metadata.SentimentScore,
-			string(emotionsJSON), string(objectionsJSON), metadata.UpdatedAt,
+			metadata.Sentiment, metadata.SentimentScore, metadata.SentimentModel,
+			string(emotionsJSON), string(objectionsJSON), metadata.ComplexityScore, metadata.UpdatedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create conversation metadata: %w", err)
@@ -260,21 +632,23 @@ func (s *ConversationStorage) CreateConversationMetadata(metadata *models.Conver
 	}
 
 	query := `
-		INSERT INTO conversation_metadata (id, conversation_id, intent, intent_score, sentiment, sentiment_score, emotions, objections, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO conversation_metadata (id, conversation_id, intent, intent_score, sentiment, sentiment_score, sentiment_model, emotions, objections, complexity_score, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT(conversation_id) DO UPDATE SET
 			intent = excluded.intent,
 			intent_score = excluded.intent_score,
 			sentiment = excluded.sentiment,
 			sentiment_score = excluded.sentiment_score,
+			sentiment_model = excluded.sentiment_model,
 			emotions = excluded.emotions,
 			objections = excluded.objections,
+			complexity_score = excluded.complexity_score,
 			updated_at = excluded.updated_at
 	`
 	_, err := s.client.DB.Exec(query,
 		metadata.ID, metadata.ConversationID, metadata.Intent, metadata.IntentScore,
-		metadata.Sentiment, metadata.SentimentScore,
-		string(emotionsJSON), string(objectionsJSON), metadata.UpdatedAt,
+		metadata.Sentiment, metadata.SentimentScore, metadata.SentimentModel,
+		string(emotionsJSON), string(objectionsJSON), metadata.ComplexityScore, metadata.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create conversation metadata: %w", err)
@@ -282,27 +656,77 @@ func (s *ConversationStorage) CreateConversationMetadata(metadata *models.Conver
 	return nil
 }
 
-// GetConversationMetadata retrieves metadata for a conversation
-func (s *ConversationStorage) GetConversationMetadata(conversationID string) (*models.ConversationMetadata, error) {
+// GetConversationMetadata retrieves metadata for a conversation belonging to the tenant
+func (s *ConversationStorage) GetConversationMetadata(tenantID, conversationID string) (*models.ConversationMetadata, error) {
 	query := `
-		SELECT id, conversation_id, intent, intent_score, sentiment, sentiment_score, emotions, objections, updated_at
-		FROM conversation_metadata
-		WHERE conversation_id = $1
+		SELECT cm.id, cm.conversation_id, cm.intent, cm.intent_score, cm.sentiment, cm.sentiment_score, cm.sentiment_model,
+			cm.emotions, cm.objections, cm.complexity_score, cm.updated_at
+		FROM conversation_metadata cm
+		JOIN conversations c ON cm.conversation_id = c.id
+		WHERE cm.conversation_id = $1 AND c.tenant_id = $2
 	`
-	metadata := &models.ConversationMetadata{}
-	var emotionsJSON, objectionsJSON string
-
-	err := s.client.DB.QueryRow(query, conversationID).Scan(
-		&metadata.ID, &metadata.ConversationID, &metadata.Intent, &metadata.IntentScore,
-		&metadata.Sentiment, &metadata.SentimentScore,
-		&emotionsJSON, &objectionsJSON, &metadata.UpdatedAt,
-	)
+	metadata, err := scanConversationMetadata(s.client.DB.QueryRow(query, conversationID, tenantID))
 	if err == sql.ErrNoRows {
+		// Conversations in other tenants are reported as missing, not as lacking metadata
+		if _, convErr := s.GetConversation(tenantID, conversationID); convErr != nil {
+			return nil, fmt.Errorf("conversation not found")
+		}
 		return nil, fmt.Errorf("metadata not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metadata: %w", err)
 	}
+	return metadata, nil
+}
+
+// GetConversationMetadataByConversations retrieves the metadata of several conversations of a
+// tenant in one query, keyed by conversation ID. Conversations without metadata are left out.
+func (s *ConversationStorage) GetConversationMetadataByConversations(tenantID string, conversationIDs []string) (map[string]*models.ConversationMetadata, error) {
+	result := make(map[string]*models.ConversationMetadata, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	in, args := conversationIDList(tenantID, conversationIDs)
+	rows, err := s.client.DB.Query(`
+		SELECT cm.id, cm.conversation_id, cm.intent, cm.intent_score, cm.sentiment, cm.sentiment_score, cm.sentiment_model,
+			cm.emotions, cm.objections, cm.complexity_score, cm.updated_at
+		FROM conversation_metadata cm
+		JOIN conversations c ON cm.conversation_id = c.id
+		WHERE c.tenant_id = $1 AND cm.conversation_id IN (`+in+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		metadata, err := scanConversationMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metadata: %w", err)
+		}
+		result[metadata.ConversationID] = metadata
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating metadata: %w", err)
+	}
+	return result, nil
+}
+
+// scanConversationMetadata scans a conversation_metadata row selected as in GetConversationMetadata
+func scanConversationMetadata(row rowScanner) (*models.ConversationMetadata, error) {
+	metadata := &models.ConversationMetadata{}
+	var emotionsJSON, objectionsJSON string
+	var complexityScore sql.NullFloat64
+	var sentimentModel sql.NullString
+
+	if err := row.Scan(
+		&metadata.ID, &metadata.ConversationID, &metadata.Intent, &metadata.IntentScore,
+		&metadata.Sentiment, &metadata.SentimentScore, &sentimentModel,
+		&emotionsJSON, &objectionsJSON, &complexityScore, &metadata.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
 
 	if err := json.Unmarshal([]byte(emotionsJSON), &metadata.Emotions); err != nil {
 		metadata.Emotions = []string{}
@@ -310,6 +734,10 @@ func (s *ConversationStorage) GetConversationMetadata(conversationID string) (*m
 	if err := json.Unmarshal([]byte(objectionsJSON), &metadata.Objections); err != nil {
 		metadata.Objections = []string{}
 	}
+	if complexityScore.Valid {
+		metadata.ComplexityScore = complexityScore.Float64
+	}
+	metadata.SentimentModel = sentimentModel.String
 
 	return metadata, nil
 }
@@ -321,13 +749,13 @@ func (s *ConversationStorage) UpdateConversationMetadata(metadata *models.Conver
 
 	query := `
 		UPDATE conversation_metadata
-		SET intent = $1, intent_score = $2, sentiment = $3, sentiment_score = $4,
-		    emotions = $5, objections = $6, updated_at = $7
-		WHERE conversation_id = $8
+		SET intent = $1, intent_score = $2, sentiment = $3, sentiment_score = $4, sentiment_model = $5,
+		    emotions = $6, objections = $7, complexity_score = $8, updated_at = $9
+		WHERE conversation_id = $10
 	`
 	result, err := s.client.DB.Exec(query,
-		metadata.Intent, metadata.IntentScore, metadata.Sentiment, metadata.SentimentScore,
-		string(emotionsJSON), string(objectionsJSON), metadata.UpdatedAt, metadata.ConversationID,
+		metadata.Intent, metadata.IntentScore, metadata.Sentiment, metadata.SentimentScore, metadata.SentimentModel,
+		string(emotionsJSON), string(objectionsJSON), metadata.ComplexityScore, metadata.UpdatedAt, metadata.ConversationID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update metadata: %w", err)
@@ -342,3 +770,119 @@ func (s *ConversationStorage) UpdateConversationMetadata(metadata *models.Conver
 	return nil
 }
 
+// MetadataPatch is a partial update to conversation metadata; nil fields are left unchanged.
+// An empty, non-nil slice clears Emotions or Objections.
+type MetadataPatch struct {
+	Intent          *string  `json:"intent,omitempty"`
+	IntentScore     *float64 `json:"intent_score,omitempty"`
+	Sentiment       *string  `json:"sentiment,omitempty"`
+	SentimentScore  *float64 `json:"sentiment_score,omitempty"`
+	SentimentModel  *string  `json:"sentiment_model,omitempty"`
+	Emotions        []string `json:"emotions,omitempty"`
+	Objections      []string `json:"objections,omitempty"`
+	ComplexityScore *float64 `json:"complexity_score,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p MetadataPatch) IsEmpty() bool {
+	return p.Intent == nil && p.IntentScore == nil && p.Sentiment == nil && p.SentimentScore == nil &&
+		p.SentimentModel == nil && p.Emotions == nil && p.Objections == nil && p.ComplexityScore == nil
+}
+
+// PatchConversationMetadata updates only the fields set in patch, plus updated_at. tenantID
+// is the conversation's tenant.
+func (s *ConversationStorage) PatchConversationMetadata(tenantID, conversationID string, patch MetadataPatch) error {
+	return s.client.withRetry("PatchConversationMetadata", tenantID, func() error {
+		return s.patchConversationMetadata(conversationID, patch)
+	})
+}
+
+func (s *ConversationStorage) patchConversationMetadata(conversationID string, patch MetadataPatch) error {
+	sets := make([]string, 0, 9)
+	args := make([]interface{}, 0, 10)
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if patch.Intent != nil {
+		set("intent", *patch.Intent)
+	}
+	if patch.IntentScore != nil {
+		set("intent_score", *patch.IntentScore)
+	}
+	if patch.Sentiment != nil {
+		set("sentiment", *patch.Sentiment)
+	}
+	if patch.SentimentScore != nil {
+		set("sentiment_score", *patch.SentimentScore)
+	}
+	if patch.SentimentModel != nil {
+		set("sentiment_model", *patch.SentimentModel)
+	}
+	if patch.Emotions != nil {
+		emotionsJSON, _ := json.Marshal(patch.Emotions)
+		set("emotions", string(emotionsJSON))
+	}
+	if patch.Objections != nil {
+		objectionsJSON, _ := json.Marshal(patch.Objections)
+		set("objections", string(objectionsJSON))
+	}
+	if patch.ComplexityScore != nil {
+		set("complexity_score", *patch.ComplexityScore)
+	}
+	set("updated_at", time.Now())
+
+	args = append(args, conversationID)
+	query := fmt.Sprintf("UPDATE conversation_metadata SET %s WHERE conversation_id = $%d", strings.Join(sets, ", "), len(args))
+
+	result, err := s.client.DB.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to patch metadata: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("metadata not found")
+	}
+	return nil
+}
+
+// GetComplexityDistribution counts scored conversations per complexity bucket
+// (low 1-3, medium 4-6, high 7-10) for a tenant
+func (s *ConversationStorage) GetComplexityDistribution(tenantID string) (map[string]int, error) {
+	query := `
+		SELECT
+			CASE
+				WHEN cm.complexity_score >= 7 THEN 'high'
+				WHEN cm.complexity_score >= 4 THEN 'medium'
+				ELSE 'low'
+			END AS bucket,
+			COUNT(*)
+		FROM conversation_metadata cm
+		JOIN conversations c ON c.id = cm.conversation_id
+		WHERE c.tenant_id = $1 AND c.deleted_at IS NULL AND cm.complexity_score IS NOT NULL AND cm.complexity_score > 0
+		GROUP BY bucket
+	`
+	rows, err := s.client.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get complexity distribution: %w", err)
+	}
+	defer rows.Close()
+
+	distribution := map[string]int{"low": 0, "medium": 0, "high": 0}
+	for rows.Next() {
+		var bucket string
+		var count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan complexity bucket: %w", err)
+		}
+		distribution[bucket] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating complexity distribution: %w", err)
+	}
+	return distribution, nil
+}
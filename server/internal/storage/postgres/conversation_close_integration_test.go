@@ -0,0 +1,101 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+// recordingCloseListener records conversations reported as closed
+type recordingCloseListener struct {
+	closed []string
+}
+
+func (l *recordingCloseListener) OnConversationClosed(tenantID, conversationID string) {
+	l.closed = append(l.closed, tenantID+"/"+conversationID)
+}
+
+func TestUpdateConversationNotifiesCloseListener(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	listener := &recordingCloseListener{}
+	other := &recordingCloseListener{}
+	storage.AddCloseListener(listener)
+	storage.AddCloseListener(other)
+
+	conv := newTestConversation(t, storage, nil, "active")
+
+	// Updates that don't close the conversation aren't reported
+	conv.UpdatedAt = time.Now().UTC()
+	if err := storage.UpdateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("UpdateConversation: %v", err)
+	}
+	if len(listener.closed) != 0 {
+		t.Fatalf("closed = %v, want none for an active update", listener.closed)
+	}
+
+	conv.Status = "closed"
+	if err := storage.UpdateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("UpdateConversation close: %v", err)
+	}
+	if len(listener.closed) != 1 || listener.closed[0] != testTenantID+"/"+conv.ID {
+		t.Fatalf("closed = %v, want the conversation once", listener.closed)
+	}
+	if len(other.closed) != 1 {
+		t.Errorf("second listener closed = %v, want every listener notified", other.closed)
+	}
+
+	// Saving an already closed conversation again is not a new close
+	if err := storage.UpdateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("UpdateConversation again: %v", err)
+	}
+	if len(listener.closed) != 1 {
+		t.Errorf("closed = %v, want no second notification", listener.closed)
+	}
+
+	// Another tenant can't close the conversation
+	if err := storage.UpdateConversation("other-tenant", conv); err == nil {
+		t.Error("UpdateConversation from another tenant should fail")
+	}
+	if len(listener.closed) != 1 {
+		t.Errorf("closed = %v, want no notification for another tenant", listener.closed)
+	}
+}
+
+func TestUpdateConversationStoresCloseTime(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	now := time.Now().UTC().Truncate(time.Second)
+	createConversationAt(t, storage, tenantID, "close-"+tenantID, nil, now.Add(-48*time.Hour))
+	createConversationAt(t, storage, tenantID, "open-"+tenantID, nil, now.Add(-48*time.Hour))
+
+	conv, err := storage.GetConversation(tenantID, "close-"+tenantID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if conv.ClosedAt != nil {
+		t.Fatalf("ClosedAt = %v, want nil for an active conversation", conv.ClosedAt)
+	}
+	conv.Status = "closed"
+	conv.ClosedAt = &now
+	if err := storage.UpdateConversation(tenantID, conv); err != nil {
+		t.Fatalf("UpdateConversation: %v", err)
+	}
+
+	stored, err := storage.GetConversation(tenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversation after close: %v", err)
+	}
+	if stored.ClosedAt == nil || !stored.ClosedAt.Equal(now) {
+		t.Errorf("ClosedAt = %v, want %v", stored.ClosedAt, now)
+	}
+
+	since := now.Add(-time.Hour)
+	count, err := storage.CountConversations(tenantID, ConversationFilters{ClosedAfter: &since})
+	if err != nil {
+		t.Fatalf("CountConversations: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("closed since an hour ago = %d, want 1", count)
+	}
+}
@@ -0,0 +1,134 @@
+//go:build integration
+
+package postgres
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestListConversationsFilters(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	base := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Second)
+
+	alice := uuid.New().String()
+	bob := uuid.New().String()
+	laptop := "product-laptop"
+	phone := "product-phone"
+
+	create := func(id, status string, customerID, productID *string, createdAt time.Time) {
+		t.Helper()
+		conv := &models.Conversation{
+			ID:         id,
+			TenantID:   tenantID,
+			CustomerID: customerID,
+			ProductID:  productID,
+			Status:     status,
+			CreatedAt:  createdAt,
+			UpdatedAt:  createdAt,
+		}
+		if err := storage.CreateConversation(tenantID, conv); err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+	}
+	create("conv-1", "active", &alice, &laptop, base)
+	create("conv-2", "closed", &alice, &phone, base.Add(time.Hour))
+	create("conv-3", "active", &bob, &phone, base.Add(2*time.Hour))
+	create("conv-4", "archived", &bob, nil, base.Add(3*time.Hour))
+	create("conv-5", "active", nil, &laptop, base.Add(4*time.Hour))
+
+	after := base.Add(time.Hour)
+	before := base.Add(3 * time.Hour)
+
+	tests := []struct {
+		name    string
+		filters *ConversationFilters
+		want    []string
+	}{
+		{"nil filters", nil, []string{"conv-1", "conv-2", "conv-3", "conv-4", "conv-5"}},
+		{"empty filters", &ConversationFilters{}, []string{"conv-1", "conv-2", "conv-3", "conv-4", "conv-5"}},
+		{"status", &ConversationFilters{Status: "active"}, []string{"conv-1", "conv-3", "conv-5"}},
+		{"customer", &ConversationFilters{CustomerID: alice}, []string{"conv-1", "conv-2"}},
+		{"product", &ConversationFilters{ProductID: phone}, []string{"conv-2", "conv-3"}},
+		{"created after", &ConversationFilters{CreatedAfter: &after}, []string{"conv-2", "conv-3", "conv-4", "conv-5"}},
+		{"created before", &ConversationFilters{CreatedBefore: &before}, []string{"conv-1", "conv-2", "conv-3", "conv-4"}},
+		{"created range", &ConversationFilters{CreatedAfter: &after, CreatedBefore: &before}, []string{"conv-2", "conv-3", "conv-4"}},
+		{"status and customer", &ConversationFilters{Status: "active", CustomerID: bob}, []string{"conv-3"}},
+		{"status and product", &ConversationFilters{Status: "active", ProductID: laptop}, []string{"conv-1", "conv-5"}},
+		{"customer and product", &ConversationFilters{CustomerID: alice, ProductID: phone}, []string{"conv-2"}},
+		{"status and created range", &ConversationFilters{Status: "active", CreatedAfter: &after, CreatedBefore: &before}, []string{"conv-3"}},
+		{"all filters", &ConversationFilters{Status: "closed", CustomerID: alice, ProductID: phone, CreatedAfter: &after, CreatedBefore: &before}, []string{"conv-2"}},
+		{"no match", &ConversationFilters{Status: "archived", CustomerID: alice}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversations, next, err := storage.ListConversations(tenantID, tt.filters, 10, "")
+			if err != nil {
+				t.Fatalf("ListConversations: %v", err)
+			}
+			if next != "" {
+				t.Errorf("next cursor = %q, want empty", next)
+			}
+			var got []string
+			for _, conv := range conversations {
+				got = append(got, conv.ID)
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+
+			var countFilters ConversationFilters
+			if tt.filters != nil {
+				countFilters = *tt.filters
+			}
+			count, err := storage.CountConversations(tenantID, countFilters)
+			if err != nil {
+				t.Fatalf("CountConversations: %v", err)
+			}
+			if count != len(tt.want) {
+				t.Errorf("count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestListConversationsFiltersPaginate(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	createConversationAt(t, storage, tenantID, "conv-1", nil, base)
+	createConversationAt(t, storage, tenantID, "conv-2", nil, base.Add(time.Minute))
+	createConversationAt(t, storage, tenantID, "conv-3", nil, base.Add(2*time.Minute))
+	if _, err := testClient.DB.Exec("UPDATE conversations SET status = 'closed' WHERE id = $1 AND tenant_id = $2", "conv-2", tenantID); err != nil {
+		t.Fatalf("close conv-2: %v", err)
+	}
+
+	filters := &ConversationFilters{Status: "active"}
+	first, next, err := storage.ListConversations(tenantID, filters, 1, "")
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first) != 1 || first[0].ID != "conv-3" || next == "" {
+		t.Fatalf("first page = %d conversations (next %q), want conv-3 with a next cursor", len(first), next)
+	}
+	second, next, err := storage.ListConversations(tenantID, filters, 1, next)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second) != 1 || second[0].ID != "conv-1" || next != "" {
+		t.Fatalf("second page = %d conversations (next %q), want conv-1 on the last page", len(second), next)
+	}
+}
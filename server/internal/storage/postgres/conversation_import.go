@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ErrConversationIDConflict is returned when an imported conversation's ID is taken by another tenant
+var ErrConversationIDConflict = errors.New("conversation id belongs to another tenant")
+
+// ImportConversation stores an imported conversation and its messages in one transaction.
+// Conversations and messages that already exist (by ID) are left alone, so importing the same data
+// twice creates nothing the second time. Returns whether the conversation was created and the
+// number of messages created.
+func (s *ConversationStorage) ImportConversation(tenantID string, conv *models.Conversation, messages []*models.Message) (bool, int, error) {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO conversations (id, tenant_id, customer_id, assigned_agent_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(id) DO NOTHING
+	`, conv.ID, tenantID, conv.CustomerID, conv.AssignedAgentID, conv.Status, conv.CreatedAt, conv.UpdatedAt)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to import conversation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	created := rowsAffected > 0
+	if !created {
+		var owner string
+		if err := tx.QueryRow("SELECT tenant_id FROM conversations WHERE id = $1", conv.ID).Scan(&owner); err != nil {
+			return false, 0, fmt.Errorf("failed to check existing conversation: %w", err)
+		}
+		if owner != tenantID {
+			return false, 0, ErrConversationIDConflict
+		}
+	}
+
+	messagesCreated := 0
+	for _, msg := range messages {
+		result, err := tx.Exec(`
+			INSERT INTO messages (id, conversation_id, sender, content, channel, language, timestamp, created_at, is_auto_reply, language_confidence)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, FALSE, $9)
+			ON CONFLICT(id) DO NOTHING
+		`, msg.ID, conv.ID, msg.Sender, msg.Content, msg.Channel, msg.Language, msg.Timestamp, msg.CreatedAt, msg.LanguageConfidence)
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to import message: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		messagesCreated += int(rowsAffected)
+	}
+
+	// Messages added to an existing conversation may be newer than it
+	if !created && messagesCreated > 0 {
+		if _, err := tx.Exec("UPDATE conversations SET updated_at = $1 WHERE id = $2 AND updated_at < $3",
+			conv.UpdatedAt, conv.ID, conv.UpdatedAt); err != nil {
+			return false, 0, fmt.Errorf("failed to update conversation: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, fmt.Errorf("failed to commit conversation import: %w", err)
+	}
+	return created, messagesCreated, nil
+}
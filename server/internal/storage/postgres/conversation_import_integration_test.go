@@ -0,0 +1,91 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func importMessage(conversationID, content string, at time.Time) *models.Message {
+	return &models.Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Sender:         "customer",
+		Content:        content,
+		Channel:        "web",
+		Language:       "en",
+		Timestamp:      at,
+		CreatedAt:      at,
+	}
+}
+
+func TestImportConversationIsIdempotent(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	customerID := uuid.New().String()
+	start := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	conv := &models.Conversation{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		CustomerID: &customerID,
+		Status:     "active",
+		CreatedAt:  start,
+		UpdatedAt:  start.Add(time.Minute),
+	}
+	messages := []*models.Message{
+		importMessage(conv.ID, "Is there a discount?", start),
+		importMessage(conv.ID, "Still waiting", start.Add(time.Minute)),
+	}
+
+	created, count, err := storage.ImportConversation(tenantID, conv, messages)
+	if err != nil {
+		t.Fatalf("ImportConversation: %v", err)
+	}
+	if !created || count != 2 {
+		t.Fatalf("first import created=%v messages=%d, want the conversation and 2 messages", created, count)
+	}
+
+	created, count, err = storage.ImportConversation(tenantID, conv, messages)
+	if err != nil {
+		t.Fatalf("second ImportConversation: %v", err)
+	}
+	if created || count != 0 {
+		t.Errorf("second import created=%v messages=%d, want nothing", created, count)
+	}
+
+	later := importMessage(conv.ID, "Hello?", start.Add(time.Hour))
+	conv.UpdatedAt = later.Timestamp
+	created, count, err = storage.ImportConversation(tenantID, conv, append(messages, later))
+	if err != nil {
+		t.Fatalf("third ImportConversation: %v", err)
+	}
+	if created || count != 1 {
+		t.Errorf("third import created=%v messages=%d, want only the new message", created, count)
+	}
+
+	stored, err := storage.GetMessagesByConversation(tenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversation: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Errorf("stored %d messages, want 3", len(stored))
+	}
+	got, err := storage.GetConversation(tenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if !got.CreatedAt.Equal(start) || !got.UpdatedAt.Equal(later.Timestamp) {
+		t.Errorf("conversation spans %v to %v, want %v to %v", got.CreatedAt, got.UpdatedAt, start, later.Timestamp)
+	}
+
+	otherTenant := newIsolatedTenant(t, "pagination", "conversations")
+	if _, _, err := storage.ImportConversation(otherTenant, conv, messages); !errors.Is(err, ErrConversationIDConflict) {
+		t.Errorf("import into another tenant err = %v, want ErrConversationIDConflict", err)
+	}
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// duplicateConversationWindow is how close together a customer's active conversations must have
+// started to count as duplicates
+const duplicateConversationWindow = 24 * time.Hour
+
+// FindDuplicateConversations returns a customer's active conversations that started within 24 hours
+// of another of their active conversations, oldest first. Fewer than two matches means no duplicates,
+// and an empty slice is returned.
+func (s *ConversationStorage) FindDuplicateConversations(tenantID, customerID string) ([]*models.Conversation, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, tenant_id, customer_id, product_id, assigned_agent_id, status, created_at, updated_at
+		FROM conversations
+		WHERE tenant_id = $1 AND customer_id = $2 AND status = 'active' AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC
+	`, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations, err := scanConversationRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sorted by start time, so a conversation's nearest neighbours are next to it
+	duplicates := []*models.Conversation{}
+	for i, conv := range conversations {
+		nearPrevious := i > 0 && conv.CreatedAt.Sub(conversations[i-1].CreatedAt) <= duplicateConversationWindow
+		nearNext := i < len(conversations)-1 && conversations[i+1].CreatedAt.Sub(conv.CreatedAt) <= duplicateConversationWindow
+		if nearPrevious || nearNext {
+			duplicates = append(duplicates, conv)
+		}
+	}
+	return duplicates, nil
+}
+
+// MergeConversations moves every message of the secondary conversation to the primary one, copies
+// the secondary's analysis metadata when the primary has none, and closes the secondary with the
+// merged resolution, all in one transaction. Messages keep their timestamps. Close listeners are
+// not notified, since the secondary's messages now belong to the primary. Returns the number of
+// messages moved.
+func (s *ConversationStorage) MergeConversations(tenantID, primaryID, secondaryID string) (int64, error) {
+	now := time.Now()
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE conversations
+		SET status = $1, resolution_type = $2, closed_at = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6 AND deleted_at IS NULL
+	`, "closed", models.ResolutionMerged, now, now, secondaryID, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to close merged conversation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, fmt.Errorf("conversation not found")
+	}
+
+	result, err = tx.Exec("UPDATE conversations SET updated_at = $1 WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL",
+		now, primaryID, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update primary conversation: %w", err)
+	}
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return 0, fmt.Errorf("conversation not found")
+	}
+
+	result, err = tx.Exec("UPDATE messages SET conversation_id = $1 WHERE conversation_id = $2", primaryID, secondaryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move messages: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO conversation_metadata (id, conversation_id, intent, intent_score, sentiment, sentiment_score, sentiment_model, emotions, objections, complexity_score, updated_at)
+		SELECT $1, $2, intent, intent_score, sentiment, sentiment_score, sentiment_model, emotions, objections, complexity_score, $3
+		FROM conversation_metadata
+		WHERE conversation_id = $4
+			AND NOT EXISTS (SELECT 1 FROM conversation_metadata WHERE conversation_id = $5)
+	`, uuid.New().String(), primaryID, now, secondaryID, primaryID); err != nil {
+		return 0, fmt.Errorf("failed to copy conversation metadata: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit conversation merge: %w", err)
+	}
+	return moved, nil
+}
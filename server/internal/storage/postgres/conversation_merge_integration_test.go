@@ -0,0 +1,165 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestFindDuplicateConversations(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	customerID := uuid.New().String()
+	otherCustomer := uuid.New().String()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	createConversationAt(t, storage, tenantID, "old-"+tenantID, &customerID, now.Add(-72*time.Hour))
+	createConversationAt(t, storage, tenantID, "morning-"+tenantID, &customerID, now.Add(-10*time.Hour))
+	createConversationAt(t, storage, tenantID, "evening-"+tenantID, &customerID, now.Add(-time.Hour))
+	createConversationAt(t, storage, tenantID, "other-"+tenantID, &otherCustomer, now.Add(-time.Hour))
+	createConversationAt(t, storage, tenantID, "closed-"+tenantID, &customerID, now.Add(-2*time.Hour))
+	closed, err := storage.GetConversation(tenantID, "closed-"+tenantID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	closed.Status = "closed"
+	if err := storage.UpdateConversation(tenantID, closed); err != nil {
+		t.Fatalf("UpdateConversation: %v", err)
+	}
+
+	duplicates, err := storage.FindDuplicateConversations(tenantID, customerID)
+	if err != nil {
+		t.Fatalf("FindDuplicateConversations: %v", err)
+	}
+	if len(duplicates) != 2 || duplicates[0].ID != "morning-"+tenantID || duplicates[1].ID != "evening-"+tenantID {
+		t.Errorf("duplicates = %v, want the two active conversations from the last 24 hours, oldest first", conversationIDs(duplicates))
+	}
+
+	duplicates, err = storage.FindDuplicateConversations(tenantID, otherCustomer)
+	if err != nil {
+		t.Fatalf("FindDuplicateConversations: %v", err)
+	}
+	if len(duplicates) != 0 {
+		t.Errorf("duplicates = %v, want none for a single conversation", conversationIDs(duplicates))
+	}
+}
+
+func TestMergeConversations(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	customerID := uuid.New().String()
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	primaryID, secondaryID := "primary-"+tenantID, "secondary-"+tenantID
+	createConversationAt(t, storage, tenantID, primaryID, &customerID, start)
+	createConversationAt(t, storage, tenantID, secondaryID, &customerID, start.Add(time.Minute))
+
+	// The customer switched browsers mid-conversation, so the messages interleave
+	createMessageAt(t, storage, tenantID, primaryID, "customer", start, false)
+	createMessageAt(t, storage, tenantID, secondaryID, "customer", start.Add(2*time.Minute), false)
+	createMessageAt(t, storage, tenantID, primaryID, "agent", start.Add(3*time.Minute), false)
+	createMessageAt(t, storage, tenantID, secondaryID, "agent", start.Add(4*time.Minute), false)
+
+	metadata := &models.ConversationMetadata{
+		ID: uuid.New().String(), ConversationID: secondaryID, Intent: "buying", IntentScore: 0.9,
+		Sentiment: "positive", SentimentScore: 0.7, Emotions: []string{}, Objections: []string{"price"},
+		UpdatedAt: start,
+	}
+	if err := storage.CreateConversationMetadata(tenantID, metadata); err != nil {
+		t.Fatalf("CreateConversationMetadata: %v", err)
+	}
+
+	if _, err := storage.MergeConversations("other-tenant", primaryID, secondaryID); err == nil {
+		t.Fatal("MergeConversations from another tenant should fail")
+	}
+	moved, err := storage.MergeConversations(tenantID, primaryID, secondaryID)
+	if err != nil {
+		t.Fatalf("MergeConversations: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("moved = %d, want 2", moved)
+	}
+
+	messages, err := storage.GetMessagesByConversation(tenantID, primaryID)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversation: %v", err)
+	}
+	if len(messages) != 4 {
+		t.Fatalf("messages = %d, want 4 after the merge", len(messages))
+	}
+	want := []struct {
+		sender string
+		at     time.Time
+	}{
+		{"customer", start},
+		{"customer", start.Add(2 * time.Minute)},
+		{"agent", start.Add(3 * time.Minute)},
+		{"agent", start.Add(4 * time.Minute)},
+	}
+	for i, msg := range messages {
+		if msg.Sender != want[i].sender || !msg.Timestamp.Equal(want[i].at) {
+			t.Errorf("message %d = %s at %v, want %s at %v", i, msg.Sender, msg.Timestamp, want[i].sender, want[i].at)
+		}
+	}
+	if remaining, _ := storage.GetMessagesByConversation(tenantID, secondaryID); len(remaining) != 0 {
+		t.Errorf("secondary messages = %d, want none", len(remaining))
+	}
+
+	secondary, err := storage.GetConversation(tenantID, secondaryID)
+	if err != nil {
+		t.Fatalf("GetConversation secondary: %v", err)
+	}
+	if secondary.Status != "closed" || secondary.ClosedAt == nil || secondary.ResolutionType == nil || *secondary.ResolutionType != models.ResolutionMerged {
+		t.Errorf("secondary = %+v, want closed as merged", secondary)
+	}
+
+	copied, err := storage.GetConversationMetadata(tenantID, primaryID)
+	if err != nil {
+		t.Fatalf("GetConversationMetadata primary: %v", err)
+	}
+	if copied.Intent != "buying" || len(copied.Objections) != 1 || copied.Objections[0] != "price" {
+		t.Errorf("primary metadata = %+v, want the secondary's analysis copied", copied)
+	}
+}
+
+func TestMergeConversationsKeepsPrimaryMetadata(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	primaryID, secondaryID := "primary-"+tenantID, "secondary-"+tenantID
+	createConversationAt(t, storage, tenantID, primaryID, nil, start)
+	createConversationAt(t, storage, tenantID, secondaryID, nil, start)
+
+	for id, intent := range map[string]string{primaryID: "support", secondaryID: "buying"} {
+		metadata := &models.ConversationMetadata{
+			ID: uuid.New().String(), ConversationID: id, Intent: intent, Sentiment: "neutral",
+			Emotions: []string{}, Objections: []string{}, UpdatedAt: start,
+		}
+		if err := storage.CreateConversationMetadata(tenantID, metadata); err != nil {
+			t.Fatalf("CreateConversationMetadata: %v", err)
+		}
+	}
+
+	if _, err := storage.MergeConversations(tenantID, primaryID, secondaryID); err != nil {
+		t.Fatalf("MergeConversations: %v", err)
+	}
+	metadata, err := storage.GetConversationMetadata(tenantID, primaryID)
+	if err != nil {
+		t.Fatalf("GetConversationMetadata: %v", err)
+	}
+	if metadata.Intent != "support" {
+		t.Errorf("intent = %s, want the primary's own analysis kept", metadata.Intent)
+	}
+}
+
+func conversationIDs(conversations []*models.Conversation) []string {
+	ids := make([]string, len(conversations))
+	for i, conv := range conversations {
+		ids[i] = conv.ID
+	}
+	return ids
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ConversationFilter narrows a tenant-wide conversation scan
+type ConversationFilter struct {
+	From   time.Time // Inclusive lower bound on created_at
+	To     time.Time // Inclusive upper bound on created_at; zero means now
+	Status string    // Only conversations with this status; empty means any
+	Limit  int       // Maximum rows returned, most recently updated first; <= 0 means no limit
+}
+
+// ConversationWithMetadata is a conversation joined with its analysis metadata.
+// Metadata fields are zero when the conversation has not been analyzed yet.
+type ConversationWithMetadata struct {
+	ConversationID string
+	CustomerID     string // Empty for agent-initiated conversations
+	ProductID      string // Empty when the conversation isn't about a product
+	Status         string
+	ResolutionType string
+	CreatedAt      time.Time
+	MessageCount   int
+	Watchlisted    bool
+	HasMetadata    bool
+	Intent         string
+	IntentScore    float64
+	Sentiment      string
+	SentimentScore float64
+	Objections     []string
+	Emotions       []string
+}
+
+// GetConversationsWithMetadata returns conversations joined with their metadata
+// and live message counts in a single query
+func (s *ConversationStorage) GetConversationsWithMetadata(tenantID string, filter ConversationFilter) ([]ConversationWithMetadata, error) {
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	query := `
+		SELECT c.id, c.customer_id, c.product_id, c.status, c.resolution_type, c.created_at,
+			(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id AND m.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM watchlist w WHERE w.conversation_id = c.id),
+			cm.id, cm.intent, cm.intent_score, cm.sentiment, cm.sentiment_score, cm.objections, cm.emotions
+		FROM conversations c
+		LEFT JOIN conversation_metadata cm ON c.id = cm.conversation_id
+		WHERE c.tenant_id = $1 AND c.deleted_at IS NULL AND c.created_at BETWEEN $2 AND $3
+	`
+	args := []interface{}{tenantID, filter.From, to}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND c.status = $%d", len(args))
+	}
+	query += " ORDER BY c.updated_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations with metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var results []ConversationWithMetadata
+	for rows.Next() {
+		var row ConversationWithMetadata
+		var customerID, productID, resolutionType, metadataID, intent, sentiment, objectionsJSON, emotionsJSON sql.NullString
+		var intentScore, sentimentScore sql.NullFloat64
+		var watchlistCount int
+		if err := rows.Scan(
+			&row.ConversationID, &customerID, &productID, &row.Status, &resolutionType, &row.CreatedAt, &row.MessageCount, &watchlistCount,
+			&metadataID, &intent, &intentScore, &sentiment, &sentimentScore, &objectionsJSON, &emotionsJSON,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation with metadata: %w", err)
+		}
+
+		row.CustomerID = customerID.String
+		row.ProductID = productID.String
+		row.ResolutionType = resolutionType.String
+		row.Watchlisted = watchlistCount > 0
+		row.HasMetadata = metadataID.Valid
+		row.Intent = intent.String
+		row.IntentScore = intentScore.Float64
+		row.Sentiment = sentiment.String
+		row.SentimentScore = sentimentScore.Float64
+		if err := json.Unmarshal([]byte(objectionsJSON.String), &row.Objections); err != nil {
+			row.Objections = []string{}
+		}
+		if err := json.Unmarshal([]byte(emotionsJSON.String), &row.Emotions); err != nil {
+			row.Emotions = []string{}
+		}
+		results = append(results, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conversations with metadata: %w", err)
+	}
+	return results, nil
+}
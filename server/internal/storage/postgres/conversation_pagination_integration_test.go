@@ -0,0 +1,106 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func createConversationAt(t *testing.T, storage *ConversationStorage, tenantID, id string, customerID *string, updatedAt time.Time) {
+	t.Helper()
+	conv := &models.Conversation{
+		ID:         id,
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		Status:     "active",
+		CreatedAt:  updatedAt,
+		UpdatedAt:  updatedAt,
+	}
+	if err := storage.CreateConversation(tenantID, conv); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+}
+
+func TestListConversationsCursorStableAcrossInserts(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	// Two conversations share an updated_at so the id tiebreak is exercised
+	createConversationAt(t, storage, tenantID, "conv-a", nil, base)
+	createConversationAt(t, storage, tenantID, "conv-b", nil, base.Add(time.Minute))
+	createConversationAt(t, storage, tenantID, "conv-c", nil, base.Add(time.Minute))
+	createConversationAt(t, storage, tenantID, "conv-d", nil, base.Add(2*time.Minute))
+	createConversationAt(t, storage, tenantID, "conv-e", nil, base.Add(3*time.Minute))
+
+	var seen []string
+	cursor := ""
+	for page := 0; ; page++ {
+		conversations, next, err := storage.ListConversations(tenantID, nil, 2, cursor)
+		if err != nil {
+			t.Fatalf("ListConversations page %d: %v", page, err)
+		}
+		for _, conv := range conversations {
+			seen = append(seen, conv.ID)
+		}
+
+		// New conversations arriving mid-pagination must not shift later pages
+		createConversationAt(t, storage, tenantID, uuid.New().String(), nil, time.Now().UTC())
+
+		if next == "" {
+			break
+		}
+		if page > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		cursor = next
+	}
+
+	want := []string{"conv-e", "conv-d", "conv-c", "conv-b", "conv-a"}
+	if len(seen) != len(want) {
+		t.Fatalf("paged through %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("paged through %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestListConversationsCursorByCustomer(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	customerID := uuid.New().String()
+	otherCustomerID := uuid.New().String()
+
+	createConversationAt(t, storage, tenantID, "conv-1", &customerID, base)
+	createConversationAt(t, storage, tenantID, "conv-2", &otherCustomerID, base.Add(time.Minute))
+	createConversationAt(t, storage, tenantID, "conv-3", &customerID, base.Add(2*time.Minute))
+
+	first, next, err := storage.ListConversations(tenantID, &ConversationFilters{CustomerID: customerID}, 1, "")
+	if err != nil {
+		t.Fatalf("ListConversations: %v", err)
+	}
+	if len(first) != 1 || first[0].ID != "conv-3" || next == "" {
+		t.Fatalf("first page = %v next=%q, want conv-3 with a cursor", first, next)
+	}
+
+	second, next, err := storage.ListConversations(tenantID, &ConversationFilters{CustomerID: customerID}, 1, next)
+	if err != nil {
+		t.Fatalf("ListConversations: %v", err)
+	}
+	if len(second) != 1 || second[0].ID != "conv-1" || next != "" {
+		t.Errorf("second page = %v next=%q, want conv-1 as the last page", second, next)
+	}
+
+	if _, _, err := storage.ListConversations(tenantID, nil, 1, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListConversations with a bad cursor = %v, want ErrInvalidCursor", err)
+	}
+}
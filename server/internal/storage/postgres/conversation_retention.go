@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// conversationChildTables hold rows keyed by conversation_id that are removed with a hard-deleted
+// conversation. message_deletions is an audit log and is intentionally kept.
+var conversationChildTables = []string{
+	"conversation_metadata",
+	"conversation_analysis_history",
+	"auto_reply_conversations",
+	"suggestions",
+	"suggestion_feedback",
+	"transfer_events",
+	"lead_stage_transitions",
+	"hot_lead_alerts",
+	"pricing_suggestions",
+	"watchlist",
+	"sla_breaches",
+	"conversation_tags",
+	"moderation_events",
+	"conversation_summaries",
+	"conversation_notes",
+	"escalation_events",
+	"objection_resolutions",
+	"product_recommendations",
+	"knowledge_gaps",
+}
+
+// SoftDeleteConversation hides a conversation from reads until it is purged by the retention job
+func (s *ConversationStorage) SoftDeleteConversation(tenantID, conversationID, deletedBy string) error {
+	query := `
+		UPDATE conversations
+		SET deleted_at = $1, deleted_by = $2
+		WHERE id = $3 AND tenant_id = $4 AND deleted_at IS NULL
+	`
+	result, err := s.client.DB.Exec(query, time.Now(), deletedBy, conversationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var deletedAt sql.NullTime
+	err = s.client.DB.QueryRow("SELECT deleted_at FROM conversations WHERE id = $1 AND tenant_id = $2", conversationID, tenantID).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("conversation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get conversation: %w", err)
+	}
+	return fmt.Errorf("conversation already deleted")
+}
+
+// HardDeleteConversation permanently removes a conversation, its messages and derived data,
+// whether or not it was soft-deleted first. Used for GDPR erasure once the data has been exported.
+func (s *ConversationStorage) HardDeleteConversation(tenantID, conversationID string) error {
+	deleted, err := s.hardDeleteConversations("id = $1 AND tenant_id = $2", conversationID, tenantID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+	return nil
+}
+
+// HardDeleteExpiredConversations permanently removes a tenant's conversations that were
+// soft-deleted more than olderThan ago. Returns the number of conversations removed.
+func (s *ConversationStorage) HardDeleteExpiredConversations(tenantID string, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+	return s.hardDeleteConversations("tenant_id = $1 AND deleted_at IS NOT NULL AND deleted_at < $2", tenantID, cutoff)
+}
+
+// hardDeleteConversations removes the conversations matching where, along with their dependent rows,
+// in one transaction. Foreign key cascades aren't relied on since SQLite leaves them disabled.
+func (s *ConversationStorage) hardDeleteConversations(where string, args ...interface{}) (int64, error) {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := "SELECT id FROM conversations WHERE " + where
+	if _, err := tx.Exec("DELETE FROM message_reads WHERE message_id IN (SELECT id FROM messages WHERE conversation_id IN ("+ids+"))", args...); err != nil {
+		return 0, fmt.Errorf("failed to delete message reads: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM messages WHERE conversation_id IN ("+ids+")", args...); err != nil {
+		return 0, fmt.Errorf("failed to delete messages: %w", err)
+	}
+	for _, table := range conversationChildTables {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE conversation_id IN ("+ids+")", args...); err != nil {
+			return 0, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM conversations WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete conversations: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit conversation deletion: %w", err)
+	}
+	return deleted, nil
+}
+
+// ListDeletedConversations lists a tenant's soft-deleted conversations, most recently deleted first
+func (s *ConversationStorage) ListDeletedConversations(tenantID string, limit, offset int) ([]*models.Conversation, error) {
+	query := `
+		SELECT id, tenant_id, customer_id, product_id, status, created_at, updated_at, deleted_at, deleted_by
+		FROM conversations
+		WHERE tenant_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := s.client.DB.Query(query, tenantID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []*models.Conversation{}
+	for rows.Next() {
+		conv := &models.Conversation{}
+		var customerID, productID, deletedBy sql.NullString
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&conv.ID, &conv.TenantID, &customerID, &productID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt, &deletedAt, &deletedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		if customerID.Valid {
+			conv.CustomerID = &customerID.String
+		}
+		if productID.Valid {
+			conv.ProductID = &productID.String
+		}
+		if deletedAt.Valid {
+			conv.DeletedAt = &deletedAt.Time
+		}
+		if deletedBy.Valid {
+			conv.DeletedBy = &deletedBy.String
+		}
+		conversations = append(conversations, conv)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deleted conversations: %w", err)
+	}
+	return conversations, nil
+}
+
+// ListTenantsWithDeletedConversations returns every tenant with at least one soft-deleted conversation
+func (s *ConversationStorage) ListTenantsWithDeletedConversations() ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT tenant_id FROM conversations WHERE deleted_at IS NOT NULL ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenants: %w", err)
+	}
+	return tenantIDs, nil
+}
@@ -0,0 +1,113 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestSoftDeleteConversationHidesIt(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	customerID := uuid.New().String()
+	conv := newTestConversation(t, storage, &customerID, "active")
+
+	if err := storage.SoftDeleteConversation("other-tenant", conv.ID, "admin-1"); err == nil || err.Error() != "conversation not found" {
+		t.Errorf("SoftDeleteConversation from another tenant = %v, want conversation not found", err)
+	}
+	if err := storage.SoftDeleteConversation(testTenantID, conv.ID, "admin-1"); err != nil {
+		t.Fatalf("SoftDeleteConversation: %v", err)
+	}
+	if err := storage.SoftDeleteConversation(testTenantID, conv.ID, "admin-1"); err == nil || err.Error() != "conversation already deleted" {
+		t.Errorf("second SoftDeleteConversation = %v, want conversation already deleted", err)
+	}
+
+	if _, err := storage.GetConversation(testTenantID, conv.ID); err == nil || err.Error() != "conversation not found" {
+		t.Errorf("GetConversation after soft delete = %v, want conversation not found", err)
+	}
+	listed, _, err := storage.ListConversations(testTenantID, &ConversationFilters{CustomerID: customerID}, 10, "")
+	if err != nil {
+		t.Fatalf("ListConversations: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Errorf("ListConversations returned %d soft-deleted conversations", len(listed))
+	}
+	if active, _ := storage.FindActiveConversationByCustomer(testTenantID, customerID); active != nil {
+		t.Errorf("FindActiveConversationByCustomer returned soft-deleted conversation %s", active.ID)
+	}
+
+	deleted, err := storage.ListDeletedConversations(testTenantID, 100, 0)
+	if err != nil {
+		t.Fatalf("ListDeletedConversations: %v", err)
+	}
+	var found *models.Conversation
+	for _, d := range deleted {
+		if d.ID == conv.ID {
+			found = d
+		}
+	}
+	if found == nil || found.DeletedAt == nil || found.DeletedBy == nil || *found.DeletedBy != "admin-1" {
+		t.Errorf("ListDeletedConversations entry = %+v, want deleted_at and deleted_by admin-1", found)
+	}
+}
+
+func TestHardDeleteExpiredConversations(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	expired := newTestConversation(t, storage, nil, "closed")
+	recent := newTestConversation(t, storage, nil, "closed")
+	live := newTestConversation(t, storage, nil, "active")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	msg := &models.Message{ID: uuid.New().String(), ConversationID: expired.ID, Sender: "customer", Content: "hello", Channel: "web", Language: "en", Timestamp: now, CreatedAt: now}
+	if err := storage.CreateMessage(expired.TenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	createTestMetadata(t, storage, expired.TenantID, expired.ID, "buying")
+
+	for _, conv := range []*models.Conversation{expired, recent} {
+		if err := storage.SoftDeleteConversation(testTenantID, conv.ID, "admin-1"); err != nil {
+			t.Fatalf("SoftDeleteConversation: %v", err)
+		}
+	}
+	// Backdate one deletion past the retention period
+	if _, err := testClient.DB.Exec("UPDATE conversations SET deleted_at = $1 WHERE id = $2", time.Now().Add(-48*time.Hour), expired.ID); err != nil {
+		t.Fatalf("backdate deleted_at: %v", err)
+	}
+
+	purged, err := storage.HardDeleteExpiredConversations(testTenantID, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("HardDeleteExpiredConversations: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged %d conversations, want 1", purged)
+	}
+
+	var count int
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM conversations WHERE id = $1", expired.ID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expired conversation still present")
+	}
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM messages WHERE conversation_id = $1", expired.ID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expired conversation left %d messages", count)
+	}
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM conversation_metadata WHERE conversation_id = $1", expired.ID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expired conversation left its metadata")
+	}
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM conversations WHERE id IN ($1, $2)", recent.ID, live.ID).Scan(&count)
+	if count != 2 {
+		t.Errorf("recently deleted and live conversations kept = %d, want 2", count)
+	}
+
+	if err := storage.HardDeleteConversation(testTenantID, live.ID); err != nil {
+		t.Fatalf("HardDeleteConversation: %v", err)
+	}
+	if err := storage.HardDeleteConversation(testTenantID, live.ID); err == nil || err.Error() != "conversation not found" {
+		t.Errorf("second HardDeleteConversation = %v, want conversation not found", err)
+	}
+}
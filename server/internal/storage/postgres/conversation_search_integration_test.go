@@ -0,0 +1,122 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func createSearchMessage(t *testing.T, storage *ConversationStorage, tenantID, conversationID, content string) *models.Message {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	msg := &models.Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Sender:         "customer",
+		Content:        content,
+		Channel:        "web",
+		Language:       "en",
+		Timestamp:      now,
+		CreatedAt:      now,
+	}
+	if err := storage.CreateMessage(tenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	return msg
+}
+
+func searchIDs(t *testing.T, storage *ConversationStorage, tenantID, query string, limit, offset int) []string {
+	t.Helper()
+	conversations, err := storage.SearchConversations(tenantID, query, limit, offset)
+	if err != nil {
+		t.Fatalf("SearchConversations(%q): %v", query, err)
+	}
+	ids := make([]string, 0, len(conversations))
+	for _, conv := range conversations {
+		ids = append(ids, conv.ID)
+	}
+	return ids
+}
+
+func TestSearchConversationsRanksByMatches(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	createConversationAt(t, storage, tenantID, "search-once", nil, base.Add(2*time.Minute))
+	createConversationAt(t, storage, tenantID, "search-thrice", nil, base)
+	createConversationAt(t, storage, tenantID, "search-none", nil, base.Add(time.Minute))
+
+	createSearchMessage(t, storage, tenantID, "search-once", "Is there a refund policy?")
+	createSearchMessage(t, storage, tenantID, "search-thrice", "I want a REFUND")
+	createSearchMessage(t, storage, tenantID, "search-thrice", "The refund never arrived")
+	createSearchMessage(t, storage, tenantID, "search-thrice", "Please escalate my refund")
+	createSearchMessage(t, storage, tenantID, "search-none", "When does my order ship?")
+
+	got := searchIDs(t, storage, tenantID, "refund", 10, 0)
+	if len(got) != 2 || got[0] != "search-thrice" || got[1] != "search-once" {
+		t.Fatalf("search = %v, want [search-thrice search-once] (most matches first)", got)
+	}
+
+	if got := searchIDs(t, storage, tenantID, "refund", 1, 1); len(got) != 1 || got[0] != "search-once" {
+		t.Errorf("second page = %v, want [search-once]", got)
+	}
+	if got := searchIDs(t, storage, tenantID, "warranty", 10, 0); len(got) != 0 {
+		t.Errorf("search without matches = %v, want none", got)
+	}
+}
+
+func TestSearchConversationsTenantIsolation(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantA := newIsolatedTenant(t, "pagination", "conversations")
+	tenantB := newIsolatedTenant(t, "pagination", "conversations")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	createConversationAt(t, storage, tenantA, "tenant-a-conv", nil, now)
+	createConversationAt(t, storage, tenantB, "tenant-b-conv", nil, now)
+	createSearchMessage(t, storage, tenantA, "tenant-a-conv", "Do you offer a discount?")
+	createSearchMessage(t, storage, tenantB, "tenant-b-conv", "Any discount for students?")
+
+	if got := searchIDs(t, storage, tenantA, "discount", 10, 0); len(got) != 1 || got[0] != "tenant-a-conv" {
+		t.Errorf("tenant A search = %v, want only its own conversation", got)
+	}
+	if got := searchIDs(t, storage, tenantB, "discount", 10, 0); len(got) != 1 || got[0] != "tenant-b-conv" {
+		t.Errorf("tenant B search = %v, want only its own conversation", got)
+	}
+}
+
+func TestSearchConversationsSkipsDeletedMessages(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	createConversationAt(t, storage, tenantID, "search-deleted", nil, time.Now().UTC().Truncate(time.Second))
+	msg := createSearchMessage(t, storage, tenantID, "search-deleted", "my card number is 4111")
+
+	if err := storage.DeleteMessage(tenantID, msg.ID, "admin-1", "gdpr_request"); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+	if got := searchIDs(t, storage, tenantID, "card number", 10, 0); len(got) != 0 {
+		t.Errorf("search = %v, want deleted messages excluded", got)
+	}
+}
+
+func TestSearchConversationsMatchesWildcardsLiterally(t *testing.T) {
+	if testClient.DBType != "sqlite" {
+		t.Skip("LIKE fallback is only used on SQLite")
+	}
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	now := time.Now().UTC().Truncate(time.Second)
+	createConversationAt(t, storage, tenantID, "search-percent", nil, now)
+	createConversationAt(t, storage, tenantID, "search-plain", nil, now)
+	createSearchMessage(t, storage, tenantID, "search-percent", "Is 50% off still available?")
+	createSearchMessage(t, storage, tenantID, "search-plain", "Is 500 off still available?")
+
+	if got := searchIDs(t, storage, tenantID, "50%", 10, 0); len(got) != 1 || got[0] != "search-percent" {
+		t.Errorf("search for 50%% = %v, want only the literal match", got)
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ConversationSummary is a cached AI summary of a conversation
+type ConversationSummary struct {
+	ConversationID            string    `json:"conversation_id"`
+	SummaryText               string    `json:"summary"`
+	MessageCountWhenGenerated int       `json:"message_count"` // Messages the summary covers
+	GeneratedAt               time.Time `json:"generated_at"`
+}
+
+// ConversationSummaryStorage handles cached conversation summaries
+type ConversationSummaryStorage struct {
+	client *Client
+}
+
+// NewConversationSummaryStorage creates a new conversation summary storage instance
+func NewConversationSummaryStorage(client *Client) *ConversationSummaryStorage {
+	return &ConversationSummaryStorage{client: client}
+}
+
+// GetSummary returns the cached summary of a tenant's conversation, or nil when there is none
+func (s *ConversationSummaryStorage) GetSummary(tenantID, conversationID string) (*ConversationSummary, error) {
+	summary := &ConversationSummary{}
+	err := s.client.DB.QueryRow(`
+		SELECT s.conversation_id, s.summary_text, s.message_count_when_generated, s.generated_at
+		FROM conversation_summaries s
+		JOIN conversations c ON c.id = s.conversation_id
+		WHERE s.conversation_id = $1 AND c.tenant_id = $2
+	`, conversationID, tenantID).Scan(&summary.ConversationID, &summary.SummaryText, &summary.MessageCountWhenGenerated, &summary.GeneratedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation summary: %w", err)
+	}
+	return summary, nil
+}
+
+// SaveSummary creates or replaces a conversation's cached summary
+func (s *ConversationSummaryStorage) SaveSummary(summary *ConversationSummary) error {
+	if summary.GeneratedAt.IsZero() {
+		summary.GeneratedAt = time.Now()
+	}
+
+	_, err := s.client.DB.Exec(`
+		INSERT INTO conversation_summaries (conversation_id, summary_text, message_count_when_generated, generated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(conversation_id) DO UPDATE SET
+			summary_text = excluded.summary_text,
+			message_count_when_generated = excluded.message_count_when_generated,
+			generated_at = excluded.generated_at
+	`, summary.ConversationID, summary.SummaryText, summary.MessageCountWhenGenerated, summary.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save conversation summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestConversationSummarySaveAndGet(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewConversationSummaryStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := uuid.New().String()
+	createConversationAt(t, conversations, tenantID, conversationID, nil, time.Now().UTC())
+
+	if summary, err := storage.GetSummary(tenantID, conversationID); err != nil || summary != nil {
+		t.Fatalf("GetSummary before saving = %+v, %v; want nil", summary, err)
+	}
+
+	if err := storage.SaveSummary(&ConversationSummary{ConversationID: conversationID, SummaryText: "- Asked about pricing", MessageCountWhenGenerated: 4}); err != nil {
+		t.Fatalf("SaveSummary: %v", err)
+	}
+	if err := storage.SaveSummary(&ConversationSummary{ConversationID: conversationID, SummaryText: "- Asked about pricing\n- Wants a demo", MessageCountWhenGenerated: 10}); err != nil {
+		t.Fatalf("second SaveSummary: %v", err)
+	}
+
+	summary, err := storage.GetSummary(tenantID, conversationID)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if summary == nil || summary.MessageCountWhenGenerated != 10 || summary.SummaryText != "- Asked about pricing\n- Wants a demo" || summary.GeneratedAt.IsZero() {
+		t.Fatalf("summary = %+v, want the replaced summary", summary)
+	}
+
+	if other, err := storage.GetSummary("other-tenant", conversationID); err != nil || other != nil {
+		t.Errorf("other tenant's summary = %+v, %v; want nil", other, err)
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CORSConfigStorage handles per-tenant CORS origin allowlists
+type CORSConfigStorage struct {
+	client *Client
+}
+
+// NewCORSConfigStorage creates a new CORS config storage instance
+func NewCORSConfigStorage(client *Client) *CORSConfigStorage {
+	return &CORSConfigStorage{client: client}
+}
+
+// GetAllowedOrigins retrieves the allowed origins for a tenant
+func (s *CORSConfigStorage) GetAllowedOrigins(tenantID string) ([]string, error) {
+	query := `
+		SELECT allowed_origins
+		FROM cors_config
+		WHERE tenant_id = $1
+	`
+	var originsJSON string
+	err := s.client.DB.QueryRow(query, tenantID).Scan(&originsJSON)
+	if err == sql.ErrNoRows {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cors config: %w", err)
+	}
+
+	var origins []string
+	if err := json.Unmarshal([]byte(originsJSON), &origins); err != nil {
+		origins = []string{}
+	}
+	return origins, nil
+}
+
+// SetAllowedOrigins sets the allowed origins for a tenant
+func (s *CORSConfigStorage) SetAllowedOrigins(tenantID string, origins []string) error {
+	originsJSON, err := json.Marshal(origins)
+	if err != nil {
+		return fmt.Errorf("failed to marshal origins: %w", err)
+	}
+
+	query := `
+		INSERT INTO cors_config (tenant_id, allowed_origins, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			allowed_origins = excluded.allowed_origins,
+			updated_at = excluded.updated_at
+	`
+	_, err = s.client.DB.Exec(query, tenantID, string(originsJSON), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set cors config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/secrets"
+)
+
+// Credential providers
+const (
+	ProviderGemini = "gemini"
+)
+
+// CredentialStatus describes a stored credential without exposing it
+type CredentialStatus struct {
+	Configured  bool       `json:"configured"`
+	LastUpdated *time.Time `json:"last_updated"`
+}
+
+// CredentialStorage handles encrypted per-tenant API credentials
+type CredentialStorage struct {
+	client *Client
+	cipher *secrets.Cipher
+}
+
+// NewCredentialStorage creates a new credential storage instance.
+// cipher may be nil, in which case credentials cannot be stored or read.
+func NewCredentialStorage(client *Client, cipher *secrets.Cipher) *CredentialStorage {
+	return &CredentialStorage{client: client, cipher: cipher}
+}
+
+// Enabled reports whether credential encryption is configured
+func (s *CredentialStorage) Enabled() bool {
+	return s.cipher != nil
+}
+
+// SetAPIKey encrypts and stores a tenant's API key for a provider
+func (s *CredentialStorage) SetAPIKey(tenantID, provider, apiKey string) error {
+	if s.cipher == nil {
+		return fmt.Errorf("credential encryption is not configured")
+	}
+
+	encrypted, err := s.cipher.Encrypt(tenantID, apiKey)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tenant_api_credentials (tenant_id, provider, api_key_encrypted, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(tenant_id, provider) DO UPDATE SET
+			api_key_encrypted = excluded.api_key_encrypted,
+			updated_at = excluded.updated_at
+	`
+	_, err = s.client.DB.Exec(query, tenantID, provider, encrypted, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to store credential: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKey returns a tenant's decrypted API key for a provider, or "" if none is stored
+func (s *CredentialStorage) GetAPIKey(tenantID, provider string) (string, error) {
+	if s.cipher == nil {
+		return "", nil
+	}
+
+	query := `
+		SELECT api_key_encrypted
+		FROM tenant_api_credentials
+		WHERE tenant_id = $1 AND provider = $2
+	`
+	var encrypted string
+	err := s.client.DB.QueryRow(query, tenantID, provider).Scan(&encrypted)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get credential: %w", err)
+	}
+	return s.cipher.Decrypt(tenantID, encrypted)
+}
+
+// DeleteAPIKey removes a tenant's API key for a provider
+func (s *CredentialStorage) DeleteAPIKey(tenantID, provider string) error {
+	query := `DELETE FROM tenant_api_credentials WHERE tenant_id = $1 AND provider = $2`
+	if _, err := s.client.DB.Exec(query, tenantID, provider); err != nil {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	return nil
+}
+
+// GetStatus reports whether a tenant has a key stored for a provider
+func (s *CredentialStorage) GetStatus(tenantID, provider string) (*CredentialStatus, error) {
+	query := `
+		SELECT updated_at
+		FROM tenant_api_credentials
+		WHERE tenant_id = $1 AND provider = $2
+	`
+	var updatedAt time.Time
+	err := s.client.DB.QueryRow(query, tenantID, provider).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return &CredentialStatus{Configured: false}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential status: %w", err)
+	}
+	return &CredentialStatus{Configured: true, LastUpdated: &updatedAt}, nil
+}
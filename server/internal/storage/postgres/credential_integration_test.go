@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/secrets"
+)
+
+func TestCredentialsCannotBeSwappedBetweenTenants(t *testing.T) {
+	cipher, err := secrets.NewCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	storage := NewCredentialStorage(testClient, cipher)
+	victim, attacker := uuid.New().String(), uuid.New().String()
+	t.Cleanup(func() {
+		storage.DeleteAPIKey(victim, ProviderGemini)
+		storage.DeleteAPIKey(attacker, ProviderGemini)
+	})
+
+	if err := storage.SetAPIKey(victim, ProviderGemini, "victim-key"); err != nil {
+		t.Fatalf("SetAPIKey: %v", err)
+	}
+	if err := storage.SetAPIKey(attacker, ProviderGemini, "attacker-key"); err != nil {
+		t.Fatalf("SetAPIKey: %v", err)
+	}
+	if key, err := storage.GetAPIKey(victim, ProviderGemini); err != nil || key != "victim-key" {
+		t.Fatalf("GetAPIKey = %q, %v; want the stored key", key, err)
+	}
+
+	// Copy the victim's ciphertext into the attacker's row
+	_, err = testClient.DB.Exec(`
+		UPDATE tenant_api_credentials
+		SET api_key_encrypted = (SELECT api_key_encrypted FROM tenant_api_credentials WHERE tenant_id = $1 AND provider = $2)
+		WHERE tenant_id = $3 AND provider = $2
+	`, victim, ProviderGemini, attacker)
+	if err != nil {
+		t.Fatalf("failed to copy credential: %v", err)
+	}
+	if key, err := storage.GetAPIKey(attacker, ProviderGemini); err == nil {
+		t.Errorf("GetAPIKey with another tenant's ciphertext = %q, want a decryption error", key)
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CRMFieldMapping renames platform payload fields to the names a tenant's CRM expects
+type CRMFieldMapping struct {
+	TenantID  string            `json:"tenant_id"`
+	CRMType   string            `json:"crm_type"`
+	Mappings  map[string]string `json:"mappings"` // Platform field name -> CRM field name
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// CRMFieldMappingStorage handles per-tenant CRM field mappings
+type CRMFieldMappingStorage struct {
+	client *Client
+}
+
+// NewCRMFieldMappingStorage creates a new CRM field mapping storage instance
+func NewCRMFieldMappingStorage(client *Client) *CRMFieldMappingStorage {
+	return &CRMFieldMappingStorage{client: client}
+}
+
+// GetCRMFieldMapping retrieves a tenant's mapping for a CRM, or nil if none is configured
+func (s *CRMFieldMappingStorage) GetCRMFieldMapping(tenantID, crmType string) (*CRMFieldMapping, error) {
+	query := `
+		SELECT tenant_id, crm_type, mappings, updated_at
+		FROM crm_field_mappings
+		WHERE tenant_id = $1 AND crm_type = $2
+	`
+	mapping := &CRMFieldMapping{}
+	var mappingsJSON string
+	err := s.client.DB.QueryRow(query, tenantID, crmType).Scan(&mapping.TenantID, &mapping.CRMType, &mappingsJSON, &mapping.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crm field mapping: %w", err)
+	}
+	if err := json.Unmarshal([]byte(mappingsJSON), &mapping.Mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse crm field mapping: %w", err)
+	}
+	return mapping, nil
+}
+
+// SetCRMFieldMapping creates or replaces a tenant's mapping for a CRM
+func (s *CRMFieldMappingStorage) SetCRMFieldMapping(mapping *CRMFieldMapping) error {
+	mappingsJSON, err := json.Marshal(mapping.Mappings)
+	if err != nil {
+		return fmt.Errorf("failed to encode crm field mapping: %w", err)
+	}
+
+	mapping.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO crm_field_mappings (tenant_id, crm_type, mappings, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(tenant_id, crm_type) DO UPDATE SET
+			mappings = excluded.mappings,
+			updated_at = excluded.updated_at
+	`
+	if _, err := s.client.DB.Exec(query, mapping.TenantID, mapping.CRMType, string(mappingsJSON), mapping.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to set crm field mapping: %w", err)
+	}
+	return nil
+}
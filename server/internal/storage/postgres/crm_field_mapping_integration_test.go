@@ -0,0 +1,52 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCRMFieldMappingRoundTrip(t *testing.T) {
+	storage := NewCRMFieldMappingStorage(testClient)
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM crm_field_mappings WHERE tenant_id = $1", testTenantID)
+	})
+
+	if got, err := storage.GetCRMFieldMapping(testTenantID, "hubspot"); err != nil || got != nil {
+		t.Fatalf("GetCRMFieldMapping before set = %v, %v; want nil, nil", got, err)
+	}
+
+	mapping := &CRMFieldMapping{
+		TenantID: testTenantID,
+		CRMType:  "hubspot",
+		Mappings: map[string]string{"lead_score": "hs_lead_score"},
+	}
+	if err := storage.SetCRMFieldMapping(mapping); err != nil {
+		t.Fatalf("SetCRMFieldMapping: %v", err)
+	}
+
+	// Setting again replaces the mapping
+	mapping.Mappings = map[string]string{"lead_score": "hs_lead_score", "win_probability": "deal_probability"}
+	if err := storage.SetCRMFieldMapping(mapping); err != nil {
+		t.Fatalf("SetCRMFieldMapping replace: %v", err)
+	}
+
+	got, err := storage.GetCRMFieldMapping(testTenantID, "hubspot")
+	if err != nil || got == nil {
+		t.Fatalf("GetCRMFieldMapping = %v, %v", got, err)
+	}
+	if !reflect.DeepEqual(got.Mappings, mapping.Mappings) {
+		t.Errorf("mappings = %v, want %v", got.Mappings, mapping.Mappings)
+	}
+
+	// Mappings are per CRM
+	if other, err := storage.GetCRMFieldMapping(testTenantID, "salesforce"); err != nil || other != nil {
+		t.Errorf("salesforce mapping = %v, %v; want nil, nil", other, err)
+	}
+
+	// Unsupported CRM types are rejected by the schema
+	if err := storage.SetCRMFieldMapping(&CRMFieldMapping{TenantID: testTenantID, CRMType: "pipedrive", Mappings: map[string]string{}}); err == nil {
+		t.Error("SetCRMFieldMapping with unsupported crm_type should fail")
+	}
+}
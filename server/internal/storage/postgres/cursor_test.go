@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 10, 0, 0, 123456000, time.FixedZone("IST", 5*3600+1800))
+	cursor := encodeCursor(updatedAt, "conv-1")
+
+	gotUpdatedAt, gotID, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if !gotUpdatedAt.Equal(updatedAt) || gotID != "conv-1" {
+		t.Errorf("decoded (%v, %q), want (%v, conv-1)", gotUpdatedAt, gotID, updatedAt)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	for name, cursor := range map[string]string{
+		"not base64":   "%%%",
+		"no separator": encode("2026-03-01T10:00:00Z"),
+		"empty id":     encode("2026-03-01T10:00:00Z|"),
+		"bad time":     encode("yesterday|conv-1"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := decodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+			}
+		})
+	}
+}
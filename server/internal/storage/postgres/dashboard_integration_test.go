@@ -0,0 +1,267 @@
+//go:build integration
+
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+const dashboardDatasetSize = 500
+
+// countingConn counts every statement prepared on a connection. It only exposes
+// Prepare, so database/sql routes all queries and execs through it.
+type countingConn struct {
+	driver.Conn
+	queries *int64
+}
+
+func (c countingConn) Prepare(query string) (driver.Stmt, error) {
+	atomic.AddInt64(c.queries, 1)
+	return c.Conn.Prepare(query)
+}
+
+type countingDriver struct {
+	base    driver.Driver
+	queries *int64
+}
+
+func (d countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{Conn: conn, queries: d.queries}, nil
+}
+
+var countingDriverSeq int64
+
+// newCountingClient returns a client on the test database that counts statements
+func newCountingClient(tb testing.TB) (*Client, *int64) {
+	tb.Helper()
+	dsn := os.Getenv("SQLITE_PATH")
+	if testClient.DBType == "postgres" {
+		dsn = os.Getenv("DATABASE_URL")
+	}
+
+	var queries int64
+	name := fmt.Sprintf("counting-%d", atomic.AddInt64(&countingDriverSeq, 1))
+	sql.Register(name, countingDriver{base: testClient.DB.Driver(), queries: &queries})
+	db, err := sql.Open(name, dsn)
+	if err != nil {
+		tb.Fatalf("open counting client: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	tb.Cleanup(func() { db.Close() })
+	return &Client{DB: db, DBType: testClient.DBType}, &queries
+}
+
+// seedDashboardDataset creates conversations with metadata and two messages each for a
+// dedicated tenant; every fifth conversation is closed, alternating deal_won and deal_lost
+func seedDashboardDataset(tb testing.TB, n int) string {
+	tb.Helper()
+	tenantID := "dashboard-" + uuid.New().String()
+	storage := NewConversationStorage(testClient)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for i := 0; i < n; i++ {
+		conv := &models.Conversation{
+			ID:        uuid.New().String(),
+			TenantID:  tenantID,
+			Status:    "active",
+			CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+			UpdatedAt: now.Add(-time.Duration(i) * time.Minute),
+		}
+		if err := storage.CreateConversation(tenantID, conv); err != nil {
+			tb.Fatalf("CreateConversation: %v", err)
+		}
+		if i%5 == 0 {
+			resolution := models.ResolutionDealLost
+			if i%10 == 0 {
+				resolution = models.ResolutionDealWon
+			}
+			conv.Status = "closed"
+			conv.ResolutionType = &resolution
+			if err := storage.UpdateConversation(tenantID, conv); err != nil {
+				tb.Fatalf("UpdateConversation: %v", err)
+			}
+		}
+
+		for _, sender := range []string{"customer", "agent"} {
+			msg := &models.Message{
+				ID: uuid.New().String(), ConversationID: conv.ID, Sender: sender, Content: "hello",
+				Channel: "web", Language: "en", Timestamp: conv.CreatedAt, CreatedAt: conv.CreatedAt,
+			}
+			if err := storage.CreateMessage(tenantID, msg); err != nil {
+				tb.Fatalf("CreateMessage: %v", err)
+			}
+		}
+
+		metadata := &models.ConversationMetadata{
+			ID: uuid.New().String(), ConversationID: conv.ID, Intent: "buying", IntentScore: 0.8,
+			Sentiment: "positive", SentimentScore: 0.7, Emotions: []string{}, Objections: []string{"price"},
+			UpdatedAt: now,
+		}
+		if err := storage.CreateConversationMetadata(tenantID, metadata); err != nil {
+			tb.Fatalf("CreateConversationMetadata: %v", err)
+		}
+	}
+
+	tb.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM conversation_metadata WHERE conversation_id IN (SELECT id FROM conversations WHERE tenant_id = $1)", tenantID)
+		testClient.DB.Exec("DELETE FROM messages WHERE conversation_id IN (SELECT id FROM conversations WHERE tenant_id = $1)", tenantID)
+		testClient.DB.Exec("DELETE FROM conversations WHERE tenant_id = $1", tenantID)
+	})
+	return tenantID
+}
+
+// scanPerConversation reproduces the previous dashboard access pattern: one list query plus
+// metadata, win probability (messages, metadata, conversation and its tags) and churn risk
+// (messages, metadata) lookups for every conversation
+func scanPerConversation(tb testing.TB, storage *ConversationStorage, tenantID string) {
+	conversations, _, err := storage.ListConversations(tenantID, nil, 1000, "")
+	if err != nil {
+		tb.Fatalf("ListConversations: %v", err)
+	}
+	for _, conv := range conversations {
+		storage.GetConversationMetadata(tenantID, conv.ID)
+		storage.GetMessagesByConversation(tenantID, conv.ID)
+		storage.GetConversationMetadata(tenantID, conv.ID)
+		storage.GetConversation(tenantID, conv.ID)
+		storage.GetMessagesByConversation(tenantID, conv.ID)
+		storage.GetConversationMetadata(tenantID, conv.ID)
+	}
+}
+
+func TestGetConversationsWithMetadataSingleQuery(t *testing.T) {
+	tenantID := seedDashboardDataset(t, dashboardDatasetSize)
+	client, queries := newCountingClient(t)
+	storage := NewConversationStorage(client)
+
+	rows, err := storage.GetConversationsWithMetadata(tenantID, ConversationFilter{Limit: 5000})
+	if err != nil {
+		t.Fatalf("GetConversationsWithMetadata: %v", err)
+	}
+	joinedQueries := atomic.LoadInt64(queries)
+
+	if len(rows) != dashboardDatasetSize {
+		t.Fatalf("rows = %d, want %d", len(rows), dashboardDatasetSize)
+	}
+	if joinedQueries != 1 {
+		t.Errorf("joined scan ran %d queries, want 1", joinedQueries)
+	}
+
+	closed, won := 0, 0
+	for _, row := range rows {
+		if !row.HasMetadata || row.Intent != "buying" || row.MessageCount != 2 || len(row.Objections) != 1 {
+			t.Fatalf("unexpected row: %+v", row)
+		}
+		if row.Status == "closed" {
+			closed++
+			if row.ResolutionType == models.ResolutionDealWon {
+				won++
+			}
+		}
+	}
+	if closed != dashboardDatasetSize/5 || won != dashboardDatasetSize/10 {
+		t.Errorf("closed = %d won = %d, want %d and %d", closed, won, dashboardDatasetSize/5, dashboardDatasetSize/10)
+	}
+
+	atomic.StoreInt64(queries, 0)
+	scanPerConversation(t, storage, tenantID)
+	perConversationQueries := atomic.LoadInt64(queries)
+	t.Logf("dashboard scan of %d conversations: per-conversation=%d queries, joined=%d query",
+		dashboardDatasetSize, perConversationQueries, joinedQueries)
+	if want := int64(7*dashboardDatasetSize + 1); perConversationQueries != want {
+		t.Errorf("per-conversation scan ran %d queries, want %d", perConversationQueries, want)
+	}
+}
+
+func TestGetConversationsWithMetadataDateWindow(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-5 * 24 * time.Hour)
+
+	// One conversation per day over five days; the third is closed
+	for i := 0; i < 5; i++ {
+		createConversationAt(t, storage, tenantID, fmt.Sprintf("conv-%d", i), nil, day.Add(time.Duration(i)*24*time.Hour+time.Hour))
+	}
+	if _, err := testClient.DB.Exec("UPDATE conversations SET status = 'closed' WHERE id = $1 AND tenant_id = $2", "conv-2", tenantID); err != nil {
+		t.Fatalf("close conv-2: %v", err)
+	}
+
+	// A two-day window covering the second and third days
+	from := day.Add(24 * time.Hour)
+	to := day.Add(3*24*time.Hour - time.Second)
+
+	tests := []struct {
+		name   string
+		filter ConversationFilter
+		want   []string
+	}{
+		{"two-day window", ConversationFilter{From: from, To: to}, []string{"conv-1", "conv-2"}},
+		{"window and status", ConversationFilter{From: from, To: to, Status: "closed"}, []string{"conv-2"}},
+		{"status only", ConversationFilter{Status: "active"}, []string{"conv-0", "conv-1", "conv-3", "conv-4"}},
+		{"window and limit", ConversationFilter{From: from, To: to, Limit: 1}, []string{"conv-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := storage.GetConversationsWithMetadata(tenantID, tt.filter)
+			if err != nil {
+				t.Fatalf("GetConversationsWithMetadata: %v", err)
+			}
+			var got []string
+			for _, row := range rows {
+				got = append(got, row.ConversationID)
+			}
+			sort.Strings(got)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	count, err := storage.CountConversations(tenantID, ConversationFilters{CreatedAfter: &from, CreatedBefore: &to})
+	if err != nil {
+		t.Fatalf("CountConversations: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count over the window = %d, want 2", count)
+	}
+}
+
+func BenchmarkDashboardScan(b *testing.B) {
+	tenantID := seedDashboardDataset(b, dashboardDatasetSize)
+
+	b.Run("per_conversation", func(b *testing.B) {
+		client, queries := newCountingClient(b)
+		storage := NewConversationStorage(client)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			scanPerConversation(b, storage, tenantID)
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(queries))/float64(b.N), "queries/op")
+	})
+
+	b.Run("joined", func(b *testing.B) {
+		client, queries := newCountingClient(b)
+		storage := NewConversationStorage(client)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := storage.GetConversationsWithMetadata(tenantID, ConversationFilter{Limit: 5000}); err != nil {
+				b.Fatalf("GetConversationsWithMetadata: %v", err)
+			}
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(queries))/float64(b.N), "queries/op")
+	})
+}
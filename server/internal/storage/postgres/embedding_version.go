@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmbeddingVersion records which embedding model produced the vectors in a Chroma collection.
+// Vectors from different models can't be compared, so the collection is re-indexed when the
+// model changes.
+type EmbeddingVersion struct {
+	ID             string    `json:"id"`
+	CollectionName string    `json:"collection_name"`
+	ModelName      string    `json:"model_name"`
+	Dimension      int       `json:"dimension"`
+	CreatedAt      time.Time `json:"created_at"` // When the collection finished indexing with this model
+	IsActive       bool      `json:"is_active"`
+}
+
+// EmbeddingVersionStorage handles embedding model versions of the vector collections
+type EmbeddingVersionStorage struct {
+	client *Client
+}
+
+// NewEmbeddingVersionStorage creates a new embedding version storage instance
+func NewEmbeddingVersionStorage(client *Client) *EmbeddingVersionStorage {
+	return &EmbeddingVersionStorage{client: client}
+}
+
+// GetActiveVersion retrieves the version a collection is currently indexed with, or nil if none
+// is recorded
+func (s *EmbeddingVersionStorage) GetActiveVersion(collection string) (*EmbeddingVersion, error) {
+	version := &EmbeddingVersion{}
+	err := s.client.DB.QueryRow(`
+		SELECT id, collection_name, model_name, dimension, created_at, is_active
+		FROM embedding_versions
+		WHERE collection_name = $1 AND is_active = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, collection, true).Scan(
+		&version.ID, &version.CollectionName, &version.ModelName, &version.Dimension, &version.CreatedAt, &version.IsActive,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding version: %w", err)
+	}
+	return version, nil
+}
+
+// ActivateVersion records that a collection is now indexed with model, deactivating its previous
+// versions. Earlier versions are kept as history.
+func (s *EmbeddingVersionStorage) ActivateVersion(collection, model string, dimension int) (*EmbeddingVersion, error) {
+	version := &EmbeddingVersion{
+		ID:             uuid.New().String(),
+		CollectionName: collection,
+		ModelName:      model,
+		Dimension:      dimension,
+		CreatedAt:      time.Now(),
+		IsActive:       true,
+	}
+
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE embedding_versions SET is_active = $1 WHERE collection_name = $2", false, collection); err != nil {
+		return nil, fmt.Errorf("failed to deactivate embedding versions: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO embedding_versions (id, collection_name, model_name, dimension, created_at, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, version.ID, version.CollectionName, version.ModelName, version.Dimension, version.CreatedAt, version.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record embedding version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit embedding version: %w", err)
+	}
+	return version, nil
+}
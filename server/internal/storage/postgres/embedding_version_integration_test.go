@@ -0,0 +1,44 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEmbeddingVersionActivation(t *testing.T) {
+	storage := NewEmbeddingVersionStorage(testClient)
+	collection := "versions-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM embedding_versions WHERE collection_name = $1", collection)
+	})
+
+	if version, err := storage.GetActiveVersion(collection); err != nil || version != nil {
+		t.Fatalf("GetActiveVersion before any version = %+v, %v; want nil", version, err)
+	}
+
+	if _, err := storage.ActivateVersion(collection, "embedding-001", 768); err != nil {
+		t.Fatalf("ActivateVersion: %v", err)
+	}
+	if _, err := storage.ActivateVersion(collection, "text-embedding-004", 768); err != nil {
+		t.Fatalf("ActivateVersion (new model): %v", err)
+	}
+
+	version, err := storage.GetActiveVersion(collection)
+	if err != nil || version == nil {
+		t.Fatalf("GetActiveVersion = %+v, %v", version, err)
+	}
+	if version.ModelName != "text-embedding-004" || version.Dimension != 768 || !version.IsActive {
+		t.Errorf("GetActiveVersion = %+v, want the new model", version)
+	}
+
+	// The previous version is kept as history but no longer active
+	var active, total int
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM embedding_versions WHERE collection_name = $1 AND is_active = $2", collection, true).Scan(&active)
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM embedding_versions WHERE collection_name = $1", collection).Scan(&total)
+	if active != 1 || total != 2 {
+		t.Errorf("active = %d, total = %d; want 1 active of 2", active, total)
+	}
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Escalation reasons
+const (
+	EscalationReasonSentiment = "sentiment" // Sentiment fell below the escalation threshold
+	EscalationReasonRule      = "rule"      // A customer message matched an escalate rule
+)
+
+// EscalationEvent hands a conversation to a human after it went badly
+type EscalationEvent struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	TenantID       string     `json:"tenant_id"`
+	TriggeredAt    time.Time  `json:"triggered_at"`
+	SentimentScore float64    `json:"sentiment_score"` // 0-1, at the time of escalation
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	AssignedTo     *string    `json:"assigned_to,omitempty"` // The conversation's agent when it was escalated
+	Resolved       bool       `json:"resolved"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// EscalationStorage handles escalation event persistence
+type EscalationStorage struct {
+	client *Client
+}
+
+// NewEscalationStorage creates a new escalation storage instance
+func NewEscalationStorage(client *Client) *EscalationStorage {
+	return &EscalationStorage{client: client}
+}
+
+// CreateEscalationIfAbsent records an escalation unless the conversation already has an open one.
+// Returns true if the escalation was created. The check is done by a unique index, so concurrent
+// analyses of the same conversation can't both escalate it.
+func (s *EscalationStorage) CreateEscalationIfAbsent(event *EscalationEvent) (bool, error) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.TriggeredAt.IsZero() {
+		event.TriggeredAt = time.Now()
+	}
+	event.Resolved = false
+	event.ResolvedAt = nil
+
+	result, err := s.client.DB.Exec(`
+		INSERT INTO escalation_events (id, conversation_id, tenant_id, triggered_at, sentiment_score, reason, details, assigned_to, resolved)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, false)
+		ON CONFLICT DO NOTHING
+	`, event.ID, event.ConversationID, event.TenantID, event.TriggeredAt, event.SentimentScore,
+		event.Reason, event.Details, event.AssignedTo)
+	if err != nil {
+		return false, fmt.Errorf("failed to create escalation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ResolveEscalation resolves the conversation's open escalation. Returns true if there was one.
+func (s *EscalationStorage) ResolveEscalation(tenantID, conversationID string) (bool, error) {
+	result, err := s.client.DB.Exec(`
+		UPDATE escalation_events
+		SET resolved = true, resolved_at = $1
+		WHERE tenant_id = $2 AND conversation_id = $3 AND resolved = false
+	`, time.Now(), tenantID, conversationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve escalation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ListOpenEscalations lists a tenant's open escalations, worst sentiment first
+func (s *EscalationStorage) ListOpenEscalations(tenantID string) ([]*EscalationEvent, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, conversation_id, tenant_id, triggered_at, sentiment_score, reason, details, assigned_to, resolved, resolved_at
+		FROM escalation_events
+		WHERE tenant_id = $1 AND resolved = false
+		ORDER BY sentiment_score ASC, triggered_at ASC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escalations: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*EscalationEvent{}
+	for rows.Next() {
+		event := &EscalationEvent{}
+		var assignedTo sql.NullString
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&event.ID, &event.ConversationID, &event.TenantID, &event.TriggeredAt, &event.SentimentScore,
+			&event.Reason, &event.Details, &assignedTo, &event.Resolved, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation: %w", err)
+		}
+		if assignedTo.Valid {
+			event.AssignedTo = &assignedTo.String
+		}
+		if resolvedAt.Valid {
+			event.ResolvedAt = &resolvedAt.Time
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalations: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,118 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// openEscalationsFor filters a tenant's open escalations down to the given conversations
+func openEscalationsFor(t *testing.T, storage *EscalationStorage, conversationIDs ...string) []*EscalationEvent {
+	t.Helper()
+	events, err := storage.ListOpenEscalations(testTenantID)
+	if err != nil {
+		t.Fatalf("ListOpenEscalations: %v", err)
+	}
+	wanted := make(map[string]bool)
+	for _, id := range conversationIDs {
+		wanted[id] = true
+	}
+	var filtered []*EscalationEvent
+	for _, event := range events {
+		if wanted[event.ConversationID] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+func TestEscalationOnePerConversation(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewEscalationStorage(testClient)
+	conv := newTestConversation(t, conversations, nil, "active")
+	agent := "agent-1"
+
+	created, err := storage.CreateEscalationIfAbsent(&EscalationEvent{
+		ConversationID: conv.ID, TenantID: testTenantID, SentimentScore: 0.1, Reason: EscalationReasonSentiment, AssignedTo: &agent,
+	})
+	if err != nil || !created {
+		t.Fatalf("CreateEscalationIfAbsent = %v, %v; want created", created, err)
+	}
+	created, err = storage.CreateEscalationIfAbsent(&EscalationEvent{
+		ConversationID: conv.ID, TenantID: testTenantID, SentimentScore: 0.05, Reason: EscalationReasonSentiment,
+	})
+	if err != nil || created {
+		t.Fatalf("second CreateEscalationIfAbsent = %v, %v; want deduplicated", created, err)
+	}
+
+	open := openEscalationsFor(t, storage, conv.ID)
+	if len(open) != 1 || open[0].SentimentScore != 0.1 || open[0].AssignedTo == nil || *open[0].AssignedTo != agent {
+		t.Fatalf("open escalations = %+v, want the first one assigned to %s", open, agent)
+	}
+
+	resolved, err := storage.ResolveEscalation(testTenantID, conv.ID)
+	if err != nil || !resolved {
+		t.Fatalf("ResolveEscalation = %v, %v; want resolved", resolved, err)
+	}
+	if open := openEscalationsFor(t, storage, conv.ID); len(open) != 0 {
+		t.Errorf("open escalations after resolving = %+v, want none", open)
+	}
+	if resolved, _ := storage.ResolveEscalation(testTenantID, conv.ID); resolved {
+		t.Error("ResolveEscalation resolved an escalation twice")
+	}
+
+	// A resolved escalation doesn't stop the conversation from escalating again
+	created, err = storage.CreateEscalationIfAbsent(&EscalationEvent{
+		ConversationID: conv.ID, TenantID: testTenantID, SentimentScore: 0.2, Reason: EscalationReasonRule, Details: "cancel",
+	})
+	if err != nil || !created {
+		t.Errorf("CreateEscalationIfAbsent after resolving = %v, %v; want created", created, err)
+	}
+}
+
+func TestListOpenEscalationsWorstSentimentFirst(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewEscalationStorage(testClient)
+	var ids []string
+	for _, score := range []float64{0.2, 0.05, 0.15} {
+		conv := newTestConversation(t, conversations, nil, "active")
+		ids = append(ids, conv.ID)
+		if _, err := storage.CreateEscalationIfAbsent(&EscalationEvent{
+			ConversationID: conv.ID, TenantID: testTenantID, SentimentScore: score, Reason: EscalationReasonSentiment,
+		}); err != nil {
+			t.Fatalf("CreateEscalationIfAbsent: %v", err)
+		}
+	}
+
+	open := openEscalationsFor(t, storage, ids...)
+	if len(open) != 3 || open[0].ConversationID != ids[1] || open[1].ConversationID != ids[2] || open[2].ConversationID != ids[0] {
+		t.Errorf("open escalations = %+v, want sentiment 0.05, 0.15 then 0.2", open)
+	}
+	if events, err := storage.ListOpenEscalations("other-tenant-" + uuid.New().String()); err != nil || len(events) != 0 {
+		t.Errorf("ListOpenEscalations for another tenant = %+v, %v; want none", events, err)
+	}
+}
+
+func TestRulesAcceptEscalateAction(t *testing.T) {
+	storage := NewRuleStorage(testClient)
+	tenantID := "escalate-" + uuid.New().String()
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM rules WHERE tenant_id = $1", tenantID) })
+
+	now := time.Now().UTC()
+	rule := &models.Rule{
+		ID: uuid.New().String(), TenantID: tenantID, Name: "Cancellation", Type: "escalation",
+		Pattern: `(?i)\bcancel\b`, Action: models.RuleActionEscalate, IsActive: true, CreatedAt: now, UpdatedAt: now,
+	}
+	if err := storage.CreateRule(tenantID, rule); err != nil {
+		t.Fatalf("CreateRule with escalate action: %v", err)
+	}
+	loaded, err := storage.LoadRules(tenantID)
+	if err != nil || len(loaded) != 1 || loaded[0].Action != models.RuleActionEscalate {
+		t.Errorf("LoadRules = %+v, %v; want the escalate rule", loaded, err)
+	}
+}
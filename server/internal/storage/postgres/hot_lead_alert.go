@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hot lead alert reasons
+const (
+	HotLeadAlertStageStuck = "stage_stuck"
+)
+
+// HotLeadAlert flags a lead that needs attention
+type HotLeadAlert struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	TenantID       string     `json:"tenant_id"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// HotLeadAlertStorage handles hot lead alert persistence
+type HotLeadAlertStorage struct {
+	client *Client
+}
+
+// NewHotLeadAlertStorage creates a new hot lead alert storage instance
+func NewHotLeadAlertStorage(client *Client) *HotLeadAlertStorage {
+	return &HotLeadAlertStorage{client: client}
+}
+
+// CreateAlertIfAbsent creates an alert unless an unresolved alert with the same
+// reason already exists for the conversation. Returns true if an alert was created.
+func (s *HotLeadAlertStorage) CreateAlertIfAbsent(tenantID, conversationID, reason, details string) (bool, error) {
+	var existing string
+	err := s.client.DB.QueryRow(`
+		SELECT id FROM hot_lead_alerts
+		WHERE tenant_id = $1 AND conversation_id = $2 AND reason = $3 AND resolved_at IS NULL
+		LIMIT 1
+	`, tenantID, conversationID, reason).Scan(&existing)
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to check hot lead alert: %w", err)
+	}
+
+	query := `
+		INSERT INTO hot_lead_alerts (id, conversation_id, tenant_id, reason, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = s.client.DB.Exec(query, uuid.New().String(), conversationID, tenantID, reason, details, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to create hot lead alert: %w", err)
+	}
+	return true, nil
+}
+
+// ResolveAlerts marks unresolved alerts with the given reason as resolved for a conversation
+func (s *HotLeadAlertStorage) ResolveAlerts(tenantID, conversationID, reason string) error {
+	query := `
+		UPDATE hot_lead_alerts
+		SET resolved_at = $1
+		WHERE tenant_id = $2 AND conversation_id = $3 AND reason = $4 AND resolved_at IS NULL
+	`
+	_, err := s.client.DB.Exec(query, time.Now(), tenantID, conversationID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to resolve hot lead alerts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+//go:build integration
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// Integration tests run against a real database with the full migration set applied.
+//
+//	make test-integration                       # PostgreSQL 15 via docker-compose.test.yml
+//	go test -tags integration ./internal/storage/postgres/...   # SQLite temp file fallback
+//
+// TEST_DATABASE_URL selects PostgreSQL; without it a temporary SQLite database is used.
+
+var (
+	testClient   *Client
+	testTenantID string
+)
+
+func TestMain(m *testing.M) {
+	cleanup, err := setupTestDatabase()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "integration setup failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	teardownTestData()
+	testClient.Close()
+	cleanup()
+	os.Exit(code)
+}
+
+// setupTestDatabase points the client at the test database and runs migrations
+func setupTestDatabase() (func(), error) {
+	cleanup := func() {}
+
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		os.Setenv("DB_TYPE", "postgres")
+		os.Setenv("DATABASE_URL", url)
+	} else {
+		dir, err := os.MkdirTemp("", "platform-integration")
+		if err != nil {
+			return cleanup, fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		cleanup = func() { os.RemoveAll(dir) }
+		os.Setenv("DB_TYPE", "sqlite")
+		// Foreign keys are off by default in SQLite; enable them so cascades match PostgreSQL
+		os.Setenv("SQLITE_PATH", filepath.Join(dir, "test.db")+"?_foreign_keys=on")
+	}
+
+	// Run the real migration command so tests exercise the production schema
+	cmd := exec.Command("go", "run", "./cmd/migrate", "-direction=up")
+	cmd.Dir = filepath.Join("..", "..", "..")
+	cmd.Env = os.Environ()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return cleanup, fmt.Errorf("migrations failed: %w\n%s", err, out)
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return cleanup, err
+	}
+	testClient = client
+	testTenantID = "test-" + uuid.New().String()
+
+	return cleanup, nil
+}
+
+// teardownTestData removes everything created for the test tenant
+func teardownTestData() {
+	statements := []string{
+		"DELETE FROM messages WHERE conversation_id IN (SELECT id FROM conversations WHERE tenant_id = $1)",
+		"DELETE FROM conversations WHERE tenant_id = $1",
+		"DELETE FROM products WHERE tenant_id = $1",
+		"DELETE FROM rules WHERE tenant_id = $1",
+		"DELETE FROM users WHERE tenant_id = $1",
+	}
+	for _, stmt := range statements {
+		if _, err := testClient.DB.Exec(stmt, testTenantID); err != nil {
+			fmt.Fprintf(os.Stderr, "teardown failed: %v\n", err)
+		}
+	}
+}
+
+// newIsolatedTenant returns a fresh tenant ID starting with prefix; rows of the tenant are
+// deleted from tables, in order, when the test ends
+func newIsolatedTenant(t *testing.T, prefix string, tables ...string) string {
+	t.Helper()
+	tenantID := prefix + "-" + uuid.New().String()
+	t.Cleanup(func() {
+		for _, table := range tables {
+			testClient.DB.Exec("DELETE FROM "+table+" WHERE tenant_id = $1", tenantID)
+		}
+	})
+	return tenantID
+}
+
+func newTestConversation(t *testing.T, storage *ConversationStorage, customerID *string, status string) *models.Conversation {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	conv := &models.Conversation{
+		ID:         uuid.New().String(),
+		TenantID:   testTenantID,
+		CustomerID: customerID,
+		Status:     status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := storage.CreateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	return conv
+}
+
+func TestConversationRoundTrip(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	customerID := uuid.New().String()
+	productID := "prod-round-trip"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	conv := &models.Conversation{
+		ID:         uuid.New().String(),
+		TenantID:   testTenantID,
+		CustomerID: &customerID,
+		ProductID:  &productID,
+		Status:     "active",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := storage.CreateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+
+	got, err := storage.GetConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if got.ID != conv.ID || got.TenantID != testTenantID || got.Status != "active" {
+		t.Errorf("round trip mismatch: got %+v", got)
+	}
+	if got.CustomerID == nil || *got.CustomerID != customerID {
+		t.Errorf("customer_id = %v, want %s", got.CustomerID, customerID)
+	}
+	if got.ProductID == nil || *got.ProductID != productID {
+		t.Errorf("product_id = %v, want %s", got.ProductID, productID)
+	}
+	if !got.CreatedAt.Equal(now) {
+		t.Errorf("created_at = %v, want %v", got.CreatedAt, now)
+	}
+
+	// Other tenants must not see the conversation
+	if _, err := storage.GetConversation("other-tenant", conv.ID); err == nil {
+		t.Error("expected conversation not found for another tenant")
+	}
+}
+
+func TestMessagesCascadeOnConversationDelete(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+
+	for i := 0; i < 3; i++ {
+		now := time.Now().UTC()
+		msg := &models.Message{
+			ID:             uuid.New().String(),
+			ConversationID: conv.ID,
+			Sender:         "customer",
+			Content:        fmt.Sprintf("message %d", i),
+			Channel:        "web",
+			Language:       "en",
+			Timestamp:      now,
+			CreatedAt:      now,
+		}
+		if err := storage.CreateMessage(conv.TenantID, msg); err != nil {
+			t.Fatalf("CreateMessage: %v", err)
+		}
+	}
+
+	messages, err := storage.GetMessagesByConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversation: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(messages))
+	}
+
+	if _, err := testClient.DB.Exec("DELETE FROM conversations WHERE id = $1 AND tenant_id = $2", conv.ID, testTenantID); err != nil {
+		t.Fatalf("delete conversation: %v", err)
+	}
+
+	var remaining int
+	if err := testClient.DB.QueryRow("SELECT COUNT(*) FROM messages WHERE conversation_id = $1", conv.ID).Scan(&remaining); err != nil {
+		t.Fatalf("count messages: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("got %d messages after conversation delete, want 0", remaining)
+	}
+}
+
+func TestFindActiveConversationByCustomerIgnoresClosed(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	customerID := uuid.New().String()
+	conv := newTestConversation(t, storage, &customerID, "active")
+
+	found, err := storage.FindActiveConversationByCustomer(testTenantID, customerID)
+	if err != nil {
+		t.Fatalf("FindActiveConversationByCustomer: %v", err)
+	}
+	if found == nil || found.ID != conv.ID {
+		t.Fatalf("expected active conversation %s, got %+v", conv.ID, found)
+	}
+
+	conv.Status = "closed"
+	conv.UpdatedAt = time.Now().UTC()
+	if err := storage.UpdateConversation(testTenantID, conv); err != nil {
+		t.Fatalf("UpdateConversation: %v", err)
+	}
+
+	found, err = storage.FindActiveConversationByCustomer(testTenantID, customerID)
+	if err != nil {
+		t.Fatalf("FindActiveConversationByCustomer: %v", err)
+	}
+	if found != nil {
+		t.Errorf("expected nil for closed conversation, got %+v", found)
+	}
+}
+
+func TestSearchProductsPriceFilters(t *testing.T) {
+	storage := NewProductStorage(testClient)
+	now := time.Now().UTC()
+	for _, p := range []struct {
+		name  string
+		price float64
+	}{
+		{"Starter", 999},
+		{"Growth", 4999},
+		{"Enterprise", 19999},
+	} {
+		product := &models.Product{
+			ID:            uuid.New().String(),
+			TenantID:      testTenantID,
+			Name:          p.name,
+			Description:   p.name + " plan",
+			Category:      "Automation",
+			Price:         p.price,
+			PriceCurrency: "INR",
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := storage.CreateProduct(testTenantID, product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+
+	floatPtr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name   string
+		filter ProductSearchFilter
+		want   []string
+	}{
+		{"no filter", ProductSearchFilter{}, []string{"Starter", "Growth", "Enterprise"}},
+		{"min price", ProductSearchFilter{MinPrice: floatPtr(1000)}, []string{"Growth", "Enterprise"}},
+		{"max price", ProductSearchFilter{MaxPrice: floatPtr(4999)}, []string{"Starter", "Growth"}},
+		{"price range", ProductSearchFilter{MinPrice: floatPtr(1000), MaxPrice: floatPtr(5000)}, []string{"Growth"}},
+		{"range and query", ProductSearchFilter{Query: "enterprise", MinPrice: floatPtr(1000)}, []string{"Enterprise"}},
+		{"empty range", ProductSearchFilter{MinPrice: floatPtr(50000)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := storage.SearchProducts(testTenantID, tt.filter)
+			if err != nil {
+				t.Fatalf("SearchProducts: %v", err)
+			}
+			if len(products) != len(tt.want) {
+				t.Fatalf("got %d products, want %d", len(products), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if products[i].Name != name {
+					t.Errorf("products[%d] = %s, want %s", i, products[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRulesReturnsOnlyActive(t *testing.T) {
+	storage := NewRuleStorage(testClient)
+	now := time.Now().UTC()
+	for _, r := range []struct {
+		name   string
+		active bool
+	}{
+		{"active-rule", true},
+		{"inactive-rule", false},
+	} {
+		rule := &models.Rule{
+			ID:        uuid.New().String(),
+			TenantID:  testTenantID,
+			Name:      r.name,
+			Type:      "block",
+			Pattern:   "guaranteed",
+			Action:    "block",
+			IsActive:  r.active,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := storage.CreateRule(testTenantID, rule); err != nil {
+			t.Fatalf("CreateRule: %v", err)
+		}
+	}
+
+	rules, err := storage.LoadRules(testTenantID)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Name != "active-rule" {
+		t.Errorf("LoadRules returned %d rules, want only active-rule", len(rules))
+	}
+}
+
+func TestGetConversationsDataInBatch(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	analyzed := newTestConversation(t, storage, nil, "active")
+	unanalyzed := newTestConversation(t, storage, nil, "active")
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	createMessageAt(t, storage, analyzed.TenantID, analyzed.ID, "customer", start, false)
+	createMessageAt(t, storage, analyzed.TenantID, analyzed.ID, "agent", start.Add(time.Minute), false)
+	createMessageAt(t, storage, unanalyzed.TenantID, unanalyzed.ID, "customer", start, false)
+	createTestMetadata(t, storage, analyzed.TenantID, analyzed.ID, "buying")
+
+	ids := []string{analyzed.ID, unanalyzed.ID}
+	messages, err := storage.GetMessagesByConversations(testTenantID, ids)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversations: %v", err)
+	}
+	if got := messages[analyzed.ID]; len(got) != 2 || got[0].Sender != "customer" || got[1].Sender != "agent" {
+		t.Errorf("analyzed messages = %+v, want both in order", got)
+	}
+	if len(messages[unanalyzed.ID]) != 1 {
+		t.Errorf("unanalyzed messages = %+v, want one", messages[unanalyzed.ID])
+	}
+
+	metadata, err := storage.GetConversationMetadataByConversations(testTenantID, ids)
+	if err != nil {
+		t.Fatalf("GetConversationMetadataByConversations: %v", err)
+	}
+	if len(metadata) != 1 || metadata[analyzed.ID] == nil || metadata[analyzed.ID].Intent != "buying" {
+		t.Errorf("metadata = %+v, want only the analyzed conversation's", metadata)
+	}
+
+	// Another tenant gets nothing for the same IDs
+	if other, err := storage.GetMessagesByConversations("other-tenant", ids); err != nil || len(other) != 0 {
+		t.Errorf("other tenant's messages = %v (%v), want none", other, err)
+	}
+	if other, err := storage.GetConversationMetadataByConversations("other-tenant", ids); err != nil || len(other) != 0 {
+		t.Errorf("other tenant's metadata = %v (%v), want none", other, err)
+	}
+}
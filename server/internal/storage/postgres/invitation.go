@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/models"
+)
+
+// ErrUserEmailTaken is returned when inviting or creating a user whose email is already registered
+var ErrUserEmailTaken = errors.New("a user with this email already exists")
+
+// InvitationStorage persists user invitations; implements auth.InvitationStore
+type InvitationStorage struct {
+	client *Client
+}
+
+// NewInvitationStorage creates a new invitation storage instance
+func NewInvitationStorage(client *Client) *InvitationStorage {
+	return &InvitationStorage{client: client}
+}
+
+// CreateInvitation stores a new invitation and revokes the tenant's pending invitations to the
+// same email, so only the latest one can be accepted. Returns ErrUserEmailTaken when the email
+// already belongs to a user.
+func (s *InvitationStorage) CreateInvitation(record *auth.InvitationRecord) error {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkEmailAvailable(tx, record.Email); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE user_invitations SET status = $1 WHERE tenant_id = $2 AND email = $3 AND status = $4`,
+		auth.InvitationRevoked, record.TenantID, record.Email, auth.InvitationPending); err != nil {
+		return fmt.Errorf("failed to revoke previous invitations: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO user_invitations (id, tenant_id, email, role, invited_by, token_hash, status, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, record.ID, record.TenantID, record.Email, record.Role, record.InvitedBy, record.TokenHash,
+		record.Status, record.ExpiresAt, record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invitation: %w", err)
+	}
+	return nil
+}
+
+// GetInvitation retrieves an invitation by ID, or nil if there is none
+func (s *InvitationStorage) GetInvitation(id string) (*auth.InvitationRecord, error) {
+	query := `
+		SELECT id, tenant_id, email, role, invited_by, token_hash, status, expires_at, created_at, accepted_at
+		FROM user_invitations
+		WHERE id = $1
+	`
+	record := &auth.InvitationRecord{}
+	var acceptedAt sql.NullTime
+	err := s.client.DB.QueryRow(query, id).Scan(&record.ID, &record.TenantID, &record.Email, &record.Role,
+		&record.InvitedBy, &record.TokenHash, &record.Status, &record.ExpiresAt, &record.CreatedAt, &acceptedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if acceptedAt.Valid {
+		record.AcceptedAt = &acceptedAt.Time
+	}
+	return record, nil
+}
+
+// AcceptInvitation marks a pending invitation accepted and creates its user in one transaction.
+// Reports false, creating nothing, when the invitation is no longer pending. Returns
+// ErrUserEmailTaken when the email was registered since the invitation was sent.
+func (s *InvitationStorage) AcceptInvitation(id string, user *models.User) (bool, error) {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE user_invitations SET status = $1, accepted_at = $2 WHERE id = $3 AND status = $4`,
+		auth.InvitationAccepted, time.Now(), id, auth.InvitationPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := checkEmailAvailable(tx, user.Email); err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO users (id, tenant_id, email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, user.ID, user.TenantID, user.Email, user.PasswordHash, string(user.Role), user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit invitation: %w", err)
+	}
+	return true, nil
+}
+
+// checkEmailAvailable returns ErrUserEmailTaken when any tenant has a user with the email, since
+// emails are unique across tenants
+func checkEmailAvailable(tx *sql.Tx, email string) error {
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE LOWER(email) = LOWER($1)`, email).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if count > 0 {
+		return ErrUserEmailTaken
+	}
+	return nil
+}
+
+var _ auth.InvitationStore = (*InvitationStorage)(nil)
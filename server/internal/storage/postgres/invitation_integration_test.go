@@ -0,0 +1,88 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/auth"
+)
+
+func TestInvitationStorageAcceptOnce(t *testing.T) {
+	storage := NewInvitationStorage(testClient)
+	users := NewUserStorage(testClient)
+	email := "invitee-" + uuid.New().String() + "@example.com"
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM user_invitations WHERE tenant_id = $1", testTenantID)
+	})
+
+	superseded, _, err := auth.IssueInvitation(storage, testTenantID, email, "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	token, record, err := auth.IssueInvitation(storage, testTenantID, email, "admin", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+
+	if _, err := auth.AcceptInvitation(storage, superseded, "password"); !errors.Is(err, auth.ErrInvalidInvitation) {
+		t.Errorf("AcceptInvitation(superseded) = %v, want ErrInvalidInvitation", err)
+	}
+	user, err := auth.AcceptInvitation(storage, token, "password")
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if _, err := auth.AcceptInvitation(storage, token, "password"); !errors.Is(err, auth.ErrInvitationUsed) {
+		t.Errorf("replayed AcceptInvitation = %v, want ErrInvitationUsed", err)
+	}
+
+	stored, err := users.GetUserByEmail(testTenantID, email)
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if stored.ID != user.ID || stored.Role != "admin" || !auth.CheckPassword("password", stored.PasswordHash) {
+		t.Errorf("stored user = %+v, want the invited admin", stored)
+	}
+	accepted, err := storage.GetInvitation(record.ID)
+	if err != nil {
+		t.Fatalf("GetInvitation: %v", err)
+	}
+	if accepted.Status != auth.InvitationAccepted || accepted.AcceptedAt == nil {
+		t.Errorf("invitation = %+v, want it accepted", accepted)
+	}
+	if missing, err := storage.GetInvitation("missing"); err != nil || missing != nil {
+		t.Errorf("GetInvitation(missing) = %v, %v, want nil, nil", missing, err)
+	}
+
+	// Registered emails can't be invited again, in any tenant
+	if _, _, err := auth.IssueInvitation(storage, "other-"+testTenantID, email, "agent", "admin-1"); !errors.Is(err, ErrUserEmailTaken) {
+		t.Errorf("IssueInvitation(registered email) = %v, want ErrUserEmailTaken", err)
+	}
+}
+
+func TestInvitationStorageEmailTakenOnAccept(t *testing.T) {
+	storage := NewInvitationStorage(testClient)
+	email := "race-" + uuid.New().String() + "@example.com"
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM user_invitations WHERE tenant_id = $1", testTenantID)
+	})
+
+	token, record, err := auth.IssueInvitation(storage, testTenantID, email, "agent", "admin-1")
+	if err != nil {
+		t.Fatalf("IssueInvitation: %v", err)
+	}
+	if _, err := NewUserStorage(testClient).GetOrCreateCustomerByEmail(testTenantID, email); err != nil {
+		t.Fatalf("GetOrCreateCustomerByEmail: %v", err)
+	}
+
+	if _, err := auth.AcceptInvitation(storage, token, "password"); !errors.Is(err, ErrUserEmailTaken) {
+		t.Errorf("AcceptInvitation = %v, want ErrUserEmailTaken", err)
+	}
+	// The failed accept is rolled back, leaving the invitation pending
+	if pending, err := storage.GetInvitation(record.ID); err != nil || pending.Status != auth.InvitationPending {
+		t.Errorf("invitation = %+v, %v, want it still pending", pending, err)
+	}
+}
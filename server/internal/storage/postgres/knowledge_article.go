@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeArticle is a knowledge base document, optionally tied to a product
+type KnowledgeArticle struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	ProductID *string   `json:"product_id,omitempty"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	SourceURL *string   `json:"source_url,omitempty"` // Set for articles indexed from a URL
+	Version   int       `json:"version"`
+	IsLatest  bool      `json:"is_latest"` // Always true for the current article; false for history entries
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// KnowledgeArticleStorage handles knowledge base articles
+type KnowledgeArticleStorage struct {
+	client *Client
+}
+
+// NewKnowledgeArticleStorage creates a new knowledge article storage instance
+func NewKnowledgeArticleStorage(client *Client) *KnowledgeArticleStorage {
+	return &KnowledgeArticleStorage{client: client}
+}
+
+// CreateArticle creates a new knowledge article
+func (s *KnowledgeArticleStorage) CreateArticle(article *KnowledgeArticle) error {
+	if article.ID == "" {
+		article.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = now
+	}
+	article.UpdatedAt = now
+	article.Version = 1
+	article.IsLatest = true
+
+	query := `
+		INSERT INTO knowledge_articles (id, tenant_id, product_id, title, content, source_url, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := s.client.DB.Exec(query, article.ID, article.TenantID, article.ProductID, article.Title,
+		article.Content, article.SourceURL, article.Version, article.CreatedAt, article.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create knowledge article: %w", err)
+	}
+	return nil
+}
+
+// GetArticle retrieves a knowledge article by ID
+func (s *KnowledgeArticleStorage) GetArticle(tenantID, articleID string) (*KnowledgeArticle, error) {
+	query := `
+		SELECT id, tenant_id, product_id, title, content, source_url, version, created_at, updated_at
+		FROM knowledge_articles
+		WHERE tenant_id = $1 AND id = $2
+	`
+	article := &KnowledgeArticle{IsLatest: true}
+	var productID, sourceURL sql.NullString
+	err := s.client.DB.QueryRow(query, tenantID, articleID).Scan(
+		&article.ID, &article.TenantID, &productID, &article.Title, &article.Content,
+		&sourceURL, &article.Version, &article.CreatedAt, &article.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("knowledge article not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get knowledge article: %w", err)
+	}
+	if productID.Valid {
+		article.ProductID = &productID.String
+	}
+	if sourceURL.Valid {
+		article.SourceURL = &sourceURL.String
+	}
+	return article, nil
+}
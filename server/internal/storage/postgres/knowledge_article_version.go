@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxArticleVersions caps the history kept per knowledge article; the oldest versions are pruned
+const MaxArticleVersions = 20
+
+// KnowledgeArticleVersion is a previous revision of a knowledge article
+type KnowledgeArticleVersion struct {
+	ID        string    `json:"id"`
+	ArticleID string    `json:"article_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content,omitempty"` // Omitted from version listings
+	Version   int       `json:"version"`
+	IsLatest  bool      `json:"is_latest"`
+	ChangedBy *string   `json:"changed_by,omitempty"` // User whose edit replaced this version
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UpdateArticle replaces an article's title and content, saving the previous revision as a version.
+// History beyond MaxArticleVersions is pruned, oldest first.
+func (s *KnowledgeArticleStorage) UpdateArticle(tenantID, articleID, title, content, changedBy string) (*KnowledgeArticle, error) {
+	article, err := s.GetArticle(tenantID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var changedByValue interface{}
+	if changedBy != "" {
+		changedByValue = changedBy
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO knowledge_article_versions (id, article_id, title, content, version, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, uuid.New().String(), articleID, article.Title, article.Content, article.Version, changedByValue, now); err != nil {
+		return nil, fmt.Errorf("failed to save article version: %w", err)
+	}
+
+	// The version check guards against a concurrent update saving the same revision twice
+	result, err := tx.Exec(`
+		UPDATE knowledge_articles
+		SET title = $1, content = $2, version = version + 1, updated_at = $3
+		WHERE id = $4 AND tenant_id = $5 AND version = $6
+	`, title, content, now, articleID, tenantID, article.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update knowledge article: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("knowledge article was modified concurrently")
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM knowledge_article_versions
+		WHERE article_id = $1 AND version <= $2
+	`, articleID, article.Version-MaxArticleVersions); err != nil {
+		return nil, fmt.Errorf("failed to prune article versions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit article update: %w", err)
+	}
+
+	article.Title = title
+	article.Content = content
+	article.Version++
+	article.UpdatedAt = now
+	return article, nil
+}
+
+// ListVersions lists an article's previous versions, newest first, without their content
+func (s *KnowledgeArticleStorage) ListVersions(tenantID, articleID string) ([]*KnowledgeArticleVersion, error) {
+	if _, err := s.GetArticle(tenantID, articleID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.client.DB.Query(`
+		SELECT id, article_id, title, version, changed_by, created_at
+		FROM knowledge_article_versions
+		WHERE article_id = $1
+		ORDER BY version DESC
+	`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list article versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []*KnowledgeArticleVersion{}
+	for rows.Next() {
+		version := &KnowledgeArticleVersion{}
+		var changedBy sql.NullString
+		if err := rows.Scan(&version.ID, &version.ArticleID, &version.Title, &version.Version, &changedBy, &version.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan article version: %w", err)
+		}
+		if changedBy.Valid {
+			version.ChangedBy = &changedBy.String
+		}
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating article versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersion retrieves a previous version of an article, including its content
+func (s *KnowledgeArticleStorage) GetVersion(tenantID, articleID, versionID string) (*KnowledgeArticleVersion, error) {
+	if _, err := s.GetArticle(tenantID, articleID); err != nil {
+		return nil, err
+	}
+
+	version := &KnowledgeArticleVersion{}
+	var changedBy sql.NullString
+	err := s.client.DB.QueryRow(`
+		SELECT id, article_id, title, content, version, changed_by, created_at
+		FROM knowledge_article_versions
+		WHERE id = $1 AND article_id = $2
+	`, versionID, articleID).Scan(
+		&version.ID, &version.ArticleID, &version.Title, &version.Content,
+		&version.Version, &changedBy, &version.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("article version not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article version: %w", err)
+	}
+	if changedBy.Valid {
+		version.ChangedBy = &changedBy.String
+	}
+	return version, nil
+}
+
+// RestoreVersion copies a previous version back into the article. The replaced content is
+// itself saved as a version, so a restore can be undone.
+func (s *KnowledgeArticleStorage) RestoreVersion(tenantID, articleID, versionID, changedBy string) (*KnowledgeArticle, error) {
+	version, err := s.GetVersion(tenantID, articleID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.UpdateArticle(tenantID, articleID, version.Title, version.Content, changedBy)
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package postgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestArticle(t *testing.T, storage *KnowledgeArticleStorage) *KnowledgeArticle {
+	t.Helper()
+	article := &KnowledgeArticle{TenantID: testTenantID, Title: "Shipping", Content: "Ships in 5 days"}
+	if err := storage.CreateArticle(article); err != nil {
+		t.Fatalf("CreateArticle: %v", err)
+	}
+	return article
+}
+
+func TestUpdateArticleKeepsVersionsAndRestores(t *testing.T) {
+	storage := NewKnowledgeArticleStorage(testClient)
+	article := newTestArticle(t, storage)
+
+	updated, err := storage.UpdateArticle(testTenantID, article.ID, "Shipping", "Ships in 2 days", "admin-1")
+	if err != nil {
+		t.Fatalf("UpdateArticle: %v", err)
+	}
+	if updated.Version != 2 || !updated.IsLatest {
+		t.Errorf("updated version = %d latest=%v, want 2 true", updated.Version, updated.IsLatest)
+	}
+
+	versions, err := storage.ListVersions(testTenantID, article.ID)
+	if err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != 1 || versions[0].Content != "" {
+		t.Fatalf("versions = %+v, want version 1 without content", versions)
+	}
+	if versions[0].ChangedBy == nil || *versions[0].ChangedBy != "admin-1" {
+		t.Errorf("ChangedBy = %v, want admin-1", versions[0].ChangedBy)
+	}
+
+	old, err := storage.GetVersion(testTenantID, article.ID, versions[0].ID)
+	if err != nil {
+		t.Fatalf("GetVersion: %v", err)
+	}
+	if old.Content != "Ships in 5 days" {
+		t.Errorf("version content = %q, want the original", old.Content)
+	}
+
+	restored, err := storage.RestoreVersion(testTenantID, article.ID, old.ID, "admin-2")
+	if err != nil {
+		t.Fatalf("RestoreVersion: %v", err)
+	}
+	if restored.Content != "Ships in 5 days" || restored.Version != 3 {
+		t.Errorf("restored = %q v%d, want original content as v3", restored.Content, restored.Version)
+	}
+	current, err := storage.GetArticle(testTenantID, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticle: %v", err)
+	}
+	if current.Content != "Ships in 5 days" || current.Version != 3 {
+		t.Errorf("stored article = %q v%d, want original content as v3", current.Content, current.Version)
+	}
+}
+
+func TestUpdateArticlePrunesOldestVersions(t *testing.T) {
+	storage := NewKnowledgeArticleStorage(testClient)
+	article := newTestArticle(t, storage)
+
+	for i := 0; i < MaxArticleVersions+3; i++ {
+		if _, err := storage.UpdateArticle(testTenantID, article.ID, "Shipping", fmt.Sprintf("revision %d", i), ""); err != nil {
+			t.Fatalf("UpdateArticle %d: %v", i, err)
+		}
+	}
+
+	versions, err := storage.ListVersions(testTenantID, article.ID)
+	if err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	if len(versions) != MaxArticleVersions {
+		t.Fatalf("kept %d versions, want %d", len(versions), MaxArticleVersions)
+	}
+	if oldest := versions[len(versions)-1].Version; oldest != 4 {
+		t.Errorf("oldest kept version = %d, want 4", oldest)
+	}
+}
+
+func TestArticleVersionsAreTenantScoped(t *testing.T) {
+	storage := NewKnowledgeArticleStorage(testClient)
+	article := newTestArticle(t, storage)
+
+	if _, err := storage.ListVersions("other-tenant", article.ID); err == nil {
+		t.Error("ListVersions from another tenant should fail")
+	}
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeGap is a customer question product knowledge retrieval found nothing for
+type KnowledgeGap struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	QueryText      string    `json:"query_text"`
+	ConversationID string    `json:"conversation_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// KnowledgeGapStorage handles knowledge gap persistence
+type KnowledgeGapStorage struct {
+	client *Client
+}
+
+// NewKnowledgeGapStorage creates a new knowledge gap storage instance
+func NewKnowledgeGapStorage(client *Client) *KnowledgeGapStorage {
+	return &KnowledgeGapStorage{client: client}
+}
+
+// RecordGap stores a knowledge gap; ID and CreatedAt are set when empty
+func (s *KnowledgeGapStorage) RecordGap(gap *KnowledgeGap) error {
+	if gap.ID == "" {
+		gap.ID = uuid.New().String()
+	}
+	if gap.CreatedAt.IsZero() {
+		gap.CreatedAt = time.Now()
+	}
+
+	_, err := s.client.DB.Exec(`
+		INSERT INTO knowledge_gaps (id, tenant_id, query_text, conversation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, gap.ID, gap.TenantID, gap.QueryText, gap.ConversationID, gap.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record knowledge gap: %w", err)
+	}
+	return nil
+}
+
+// ListGaps returns up to limit of the tenant's knowledge gaps recorded since from, newest first
+func (s *KnowledgeGapStorage) ListGaps(tenantID string, from time.Time, limit int) ([]*KnowledgeGap, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, tenant_id, query_text, conversation_id, created_at
+		FROM knowledge_gaps
+		WHERE tenant_id = $1 AND created_at >= $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, tenantID, from, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list knowledge gaps: %w", err)
+	}
+	defer rows.Close()
+
+	gaps := []*KnowledgeGap{}
+	for rows.Next() {
+		gap := &KnowledgeGap{}
+		if err := rows.Scan(&gap.ID, &gap.TenantID, &gap.QueryText, &gap.ConversationID, &gap.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan knowledge gap: %w", err)
+		}
+		gaps = append(gaps, gap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list knowledge gaps: %w", err)
+	}
+	return gaps, nil
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKnowledgeGapRecordAndList(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewKnowledgeGapStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM knowledge_gaps WHERE tenant_id = $1", tenantID) })
+	conversationID := uuid.New().String()
+	createConversationAt(t, conversations, tenantID, conversationID, nil, time.Now().UTC())
+
+	now := time.Now().UTC()
+	for i, question := range []string{"Do you ship to Canada?", "Is there a student discount?", "Can I pay with PayPal?"} {
+		gap := &KnowledgeGap{TenantID: tenantID, QueryText: question, ConversationID: conversationID, CreatedAt: now.Add(time.Duration(i-2) * 24 * time.Hour)}
+		if err := storage.RecordGap(gap); err != nil {
+			t.Fatalf("RecordGap: %v", err)
+		}
+		if gap.ID == "" {
+			t.Fatal("RecordGap should set the ID")
+		}
+	}
+
+	gaps, err := storage.ListGaps(tenantID, now.Add(-36*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListGaps: %v", err)
+	}
+	if len(gaps) != 2 || gaps[0].QueryText != "Can I pay with PayPal?" || gaps[1].QueryText != "Is there a student discount?" {
+		t.Fatalf("gaps = %+v, want the two since from, newest first", gaps)
+	}
+	if gaps[0].ConversationID != conversationID || gaps[0].TenantID != tenantID {
+		t.Errorf("gap = %+v, want it tied to the conversation and tenant", gaps[0])
+	}
+
+	if limited, err := storage.ListGaps(tenantID, time.Time{}, 1); err != nil || len(limited) != 1 {
+		t.Errorf("ListGaps with limit 1 = %d gaps, %v; want 1", len(limited), err)
+	}
+	if other, err := storage.ListGaps("other-tenant", time.Time{}, 10); err != nil || len(other) != 0 {
+		t.Errorf("other tenant gaps = %+v, %v; want none", other, err)
+	}
+}
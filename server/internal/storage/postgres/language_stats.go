@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// LanguageCount is how many customer messages and conversations used a language
+type LanguageCount struct {
+	Language          string
+	MessageCount      int
+	ConversationCount int
+}
+
+// GetLanguageCounts counts a tenant's customer messages per detected language within a time range.
+// Messages without a detected language are counted as "unknown".
+func (s *ConversationStorage) GetLanguageCounts(tenantID string, from, to time.Time) ([]LanguageCount, error) {
+	query := `
+		SELECT COALESCE(NULLIF(m.language, ''), 'unknown') AS lang, COUNT(*), COUNT(DISTINCT m.conversation_id)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.tenant_id = $1 AND m.timestamp BETWEEN $2 AND $3
+			AND m.sender = 'customer' AND m.deleted_at IS NULL AND c.deleted_at IS NULL
+		GROUP BY lang
+	`
+	rows, err := s.client.DB.Query(query, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []LanguageCount
+	for rows.Next() {
+		var count LanguageCount
+		if err := rows.Scan(&count.Language, &count.MessageCount, &count.ConversationCount); err != nil {
+			return nil, fmt.Errorf("failed to scan language count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating language counts: %w", err)
+	}
+	return counts, nil
+}
+
+// ListMixedLanguageConversations lists a tenant's conversations whose customer messages use more
+// than one detected language, most recently updated first
+func (s *ConversationStorage) ListMixedLanguageConversations(tenantID string) ([]*models.Conversation, error) {
+	query := `
+		SELECT c.id, c.tenant_id, c.customer_id, c.product_id, c.status, c.created_at, c.updated_at
+		FROM conversations c
+		WHERE c.tenant_id = $1 AND c.deleted_at IS NULL AND (
+			SELECT COUNT(DISTINCT m.language)
+			FROM messages m
+			WHERE m.conversation_id = c.id AND m.sender = 'customer' AND m.deleted_at IS NULL
+				AND m.language IS NOT NULL AND m.language NOT IN ('', 'unknown')
+		) > 1
+		ORDER BY c.updated_at DESC
+	`
+	rows, err := s.client.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mixed-language conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []*models.Conversation{}
+	for rows.Next() {
+		conv := &models.Conversation{}
+		var customerID, productID sql.NullString
+		if err := rows.Scan(&conv.ID, &conv.TenantID, &customerID, &productID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		if customerID.Valid {
+			conv.CustomerID = &customerID.String
+		}
+		if productID.Valid {
+			conv.ProductID = &productID.String
+		}
+		conversations = append(conversations, conv)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conversations: %w", err)
+	}
+	return conversations, nil
+}
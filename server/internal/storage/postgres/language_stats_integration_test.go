@@ -0,0 +1,104 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestLanguageCountsAndMixedConversations(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	mixed := newTestConversation(t, storage, nil, "active")
+	single := newTestConversation(t, storage, nil, "active")
+
+	base := time.Now().UTC().Truncate(time.Second)
+	messages := []struct {
+		conv     *models.Conversation
+		sender   string
+		language string
+	}{
+		{mixed, "customer", "en"},
+		{mixed, "customer", "hi"},
+		{mixed, "customer", "unknown"},
+		{single, "customer", "en"},
+		{single, "agent", "fr"}, // Agent messages don't count toward customer languages
+	}
+	for i, m := range messages {
+		msg := &models.Message{
+			ID: uuid.New().String(), ConversationID: m.conv.ID, Sender: m.sender, Content: "hello",
+			Channel: "web", Language: m.language, Timestamp: base.Add(time.Duration(i) * time.Second), CreatedAt: base,
+		}
+		if err := storage.CreateMessage(m.conv.TenantID, msg); err != nil {
+			t.Fatalf("CreateMessage: %v", err)
+		}
+	}
+
+	counts, err := storage.GetLanguageCounts(testTenantID, base.Add(-time.Minute), base.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetLanguageCounts: %v", err)
+	}
+	got := make(map[string]LanguageCount)
+	for _, count := range counts {
+		got[count.Language] = count
+	}
+	if got["en"].MessageCount != 2 || got["en"].ConversationCount != 2 {
+		t.Errorf("en = %+v, want 2 messages in 2 conversations", got["en"])
+	}
+	if got["hi"].MessageCount != 1 || got["unknown"].MessageCount != 1 {
+		t.Errorf("hi = %+v, unknown = %+v, want 1 message each", got["hi"], got["unknown"])
+	}
+	if _, ok := got["fr"]; ok {
+		t.Errorf("agent message language counted: %+v", got["fr"])
+	}
+
+	conversations, err := storage.ListMixedLanguageConversations(testTenantID)
+	if err != nil {
+		t.Fatalf("ListMixedLanguageConversations: %v", err)
+	}
+	foundMixed := false
+	for _, conv := range conversations {
+		if conv.ID == single.ID {
+			t.Errorf("conversation with one customer language and an agent reply in another listed as mixed")
+		}
+		if conv.ID == mixed.ID {
+			foundMixed = true
+		}
+	}
+	if !foundMixed {
+		t.Errorf("mixed-language conversation not listed")
+	}
+}
+
+func TestMessageLanguageConfidenceRoundTrip(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	msg := &models.Message{
+		ID: uuid.New().String(), ConversationID: conv.ID, Sender: "customer", Content: "mujhe ye product chahiye jaldi",
+		Channel: "web", Language: "hi", LanguageConfidence: 0.75, Timestamp: now, CreatedAt: now,
+	}
+	if err := storage.CreateMessage(conv.TenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+
+	stored, err := storage.GetMessage(msg.ID)
+	if err != nil {
+		t.Fatalf("GetMessage: %v", err)
+	}
+	if stored.Language != "hi" || stored.LanguageConfidence != 0.75 {
+		t.Errorf("stored language = %s %.2f, want hi 0.75", stored.Language, stored.LanguageConfidence)
+	}
+	messages, err := storage.GetMessagesByConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetMessagesByConversation: %v", err)
+	}
+	if len(messages) != 1 || messages[0].LanguageConfidence != 0.75 {
+		t.Errorf("listed messages = %+v, want the language confidence", messages)
+	}
+}
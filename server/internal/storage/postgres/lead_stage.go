@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeadStageTransition records a lead moving from one stage to another
+type LeadStageTransition struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	FromStage      string    `json:"from_stage,omitempty"` // Empty for the first recorded stage
+	ToStage        string    `json:"to_stage"`
+	TransitionedAt time.Time `json:"transitioned_at"`
+}
+
+// LeadStageStorage handles lead stage transition history
+type LeadStageStorage struct {
+	client *Client
+}
+
+// NewLeadStageStorage creates a new lead stage storage instance
+func NewLeadStageStorage(client *Client) *LeadStageStorage {
+	return &LeadStageStorage{client: client}
+}
+
+// RecordTransition records a lead stage transition
+func (s *LeadStageStorage) RecordTransition(tenantID, conversationID, fromStage, toStage string) error {
+	query := `
+		INSERT INTO lead_stage_transitions (id, conversation_id, tenant_id, from_stage, to_stage, transitioned_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	var from sql.NullString
+	if fromStage != "" {
+		from = sql.NullString{String: fromStage, Valid: true}
+	}
+	_, err := s.client.DB.Exec(query, uuid.New().String(), conversationID, tenantID, from, toStage, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record stage transition: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentStage returns the most recently recorded stage of a conversation, or "" if none
+func (s *LeadStageStorage) GetCurrentStage(conversationID string) (string, error) {
+	query := `
+		SELECT to_stage
+		FROM lead_stage_transitions
+		WHERE conversation_id = $1
+		ORDER BY transitioned_at DESC
+		LIMIT 1
+	`
+	var stage string
+	err := s.client.DB.QueryRow(query, conversationID).Scan(&stage)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get current stage: %w", err)
+	}
+	return stage, nil
+}
+
+// ListTransitions lists all stage transitions for a tenant ordered by conversation and time
+func (s *LeadStageStorage) ListTransitions(tenantID string) ([]*LeadStageTransition, error) {
+	query := `
+		SELECT id, conversation_id, tenant_id, from_stage, to_stage, transitioned_at
+		FROM lead_stage_transitions
+		WHERE tenant_id = $1
+		ORDER BY conversation_id, transitioned_at ASC
+	`
+	rows, err := s.client.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []*LeadStageTransition
+	for rows.Next() {
+		t := &LeadStageTransition{}
+		var fromStage sql.NullString
+		if err := rows.Scan(&t.ID, &t.ConversationID, &t.TenantID, &fromStage, &t.ToStage, &t.TransitionedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stage transition: %w", err)
+		}
+		t.FromStage = fromStage.String
+		transitions = append(transitions, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stage transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// ListTransitionTenants returns the tenants that have at least one stage transition
+func (s *LeadStageStorage) ListTransitionTenants() ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT tenant_id FROM lead_stage_transitions ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage transition tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan stage transition tenant: %w", err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stage transition tenants: %w", err)
+	}
+	return tenantIDs, nil
+}
+
+// CountTransitions counts stage changes for a tenant, excluding the initial stage assignment
+func (s *LeadStageStorage) CountTransitions(tenantID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM lead_stage_transitions
+		WHERE tenant_id = $1 AND from_stage IS NOT NULL
+	`
+	var count int
+	if err := s.client.DB.QueryRow(query, tenantID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count stage transitions: %w", err)
+	}
+	return count, nil
+}
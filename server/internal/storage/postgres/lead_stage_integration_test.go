@@ -0,0 +1,45 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestListTransitionTenants(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewLeadStageStorage(testClient)
+	tenantA := "stage-a-" + uuid.New().String()
+	tenantB := "stage-b-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM lead_stage_transitions WHERE tenant_id IN ($1, $2)", tenantA, tenantB)
+		testClient.DB.Exec("DELETE FROM conversations WHERE tenant_id IN ($1, $2)", tenantA, tenantB)
+	})
+	createConversationAt(t, conversations, tenantA, tenantA+"-c1", nil, time.Now())
+	createConversationAt(t, conversations, tenantB, tenantB+"-c1", nil, time.Now())
+
+	for _, tr := range []struct{ tenantID, conversationID, from, to string }{
+		{tenantA, tenantA + "-c1", "", "discovery"},
+		{tenantA, tenantA + "-c1", "discovery", "evaluation"},
+		{tenantB, tenantB + "-c1", "", "discovery"},
+	} {
+		if err := storage.RecordTransition(tr.tenantID, tr.conversationID, tr.from, tr.to); err != nil {
+			t.Fatalf("RecordTransition: %v", err)
+		}
+	}
+
+	tenantIDs, err := storage.ListTransitionTenants()
+	if err != nil {
+		t.Fatalf("ListTransitionTenants: %v", err)
+	}
+	seen := map[string]int{}
+	for _, id := range tenantIDs {
+		seen[id]++
+	}
+	if seen[tenantA] != 1 || seen[tenantB] != 1 {
+		t.Errorf("tenants = %v, want %s and %s once each", tenantIDs, tenantA, tenantB)
+	}
+}
@@ -4,13 +4,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"ai-conversation-platform/internal/models"
 )
 
 // MemoryStorage handles customer memory-related database operations
 type MemoryStorage struct {
-	client *Client
+	client                *Client
+	maxObjectionsRetained int
 }
 
 // NewMemoryStorage creates a new memory storage instance
@@ -18,17 +23,23 @@ func NewMemoryStorage(client *Client) *MemoryStorage {
 	return &MemoryStorage{client: client}
 }
 
+// SetMaxObjectionsRetained caps how many past objections UpsertFromAnalysis keeps per customer;
+// the oldest are dropped first. 0 keeps every objection.
+func (s *MemoryStorage) SetMaxObjectionsRetained(max int) {
+	s.maxObjectionsRetained = max
+}
+
 // CreateMemory creates a new customer memory record
 func (s *MemoryStorage) CreateMemory(tenantID string, memory *models.CustomerMemory) error {
 	productInterestsJSON, _ := json.Marshal(memory.ProductInterests)
 	pastObjectionsJSON, _ := json.Marshal(memory.PastObjections)
 
 	query := `
-		INSERT INTO customer_memory (id, tenant_id, customer_id, preferred_language, pricing_sensitivity, product_interests, past_objections, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO customer_memory (id, tenant_id, customer_id, preferred_language, language_override, pricing_sensitivity, product_interests, past_objections, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 	_, err := s.client.DB.Exec(query,
-		memory.ID, tenantID, memory.CustomerID, memory.PreferredLanguage,
+		memory.ID, tenantID, memory.CustomerID, memory.PreferredLanguage, memory.LanguageOverride,
 		memory.PricingSensitivity, string(productInterestsJSON), string(pastObjectionsJSON),
 		memory.CreatedAt, memory.UpdatedAt,
 	)
@@ -41,7 +52,7 @@ func (s *MemoryStorage) CreateMemory(tenantID string, memory *models.CustomerMem
 // GetMemory retrieves customer memory by customer ID (tenant-scoped)
 func (s *MemoryStorage) GetMemory(tenantID, customerID string) (*models.CustomerMemory, error) {
 	query := `
-		SELECT id, tenant_id, customer_id, preferred_language, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
+		SELECT id, tenant_id, customer_id, preferred_language, language_override, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
 		FROM customer_memory
 		WHERE customer_id = $1 AND tenant_id = $2
 	`
@@ -49,7 +60,7 @@ func (s *MemoryStorage) GetMemory(tenantID, customerID string) (*models.Customer
 	var productInterestsJSON, pastObjectionsJSON string
 
 	err := s.client.DB.QueryRow(query, customerID, tenantID).Scan(
-		&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage,
+		&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage, &memory.LanguageOverride,
 		&memory.PricingSensitivity, &productInterestsJSON, &pastObjectionsJSON,
 		&memory.CreatedAt, &memory.UpdatedAt,
 	)
@@ -77,11 +88,11 @@ func (s *MemoryStorage) UpdateMemory(tenantID string, memory *models.CustomerMem
 
 	query := `
 		UPDATE customer_memory
-		SET preferred_language = $1, pricing_sensitivity = $2, product_interests = $3, past_objections = $4, updated_at = $5
-		WHERE customer_id = $6 AND tenant_id = $7
+		SET preferred_language = $1, language_override = $2, pricing_sensitivity = $3, product_interests = $4, past_objections = $5, updated_at = $6
+		WHERE customer_id = $7 AND tenant_id = $8
 	`
 	result, err := s.client.DB.Exec(query,
-		memory.PreferredLanguage, memory.PricingSensitivity,
+		memory.PreferredLanguage, memory.LanguageOverride, memory.PricingSensitivity,
 		string(productInterestsJSON), string(pastObjectionsJSON),
 		memory.UpdatedAt, memory.CustomerID, tenantID,
 	)
@@ -101,7 +112,7 @@ func (s *MemoryStorage) UpdateMemory(tenantID string, memory *models.CustomerMem
 // ListMemories lists customer memories for a tenant with pagination
 func (s *MemoryStorage) ListMemories(tenantID string, limit, offset int) ([]*models.CustomerMemory, error) {
 	query := `
-		SELECT id, tenant_id, customer_id, preferred_language, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
+		SELECT id, tenant_id, customer_id, preferred_language, language_override, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
 		FROM customer_memory
 		WHERE tenant_id = $1
 		ORDER BY updated_at DESC
@@ -119,7 +130,7 @@ func (s *MemoryStorage) ListMemories(tenantID string, limit, offset int) ([]*mod
 		var productInterestsJSON, pastObjectionsJSON string
 
 		err := rows.Scan(
-			&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage,
+			&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage, &memory.LanguageOverride,
 			&memory.PricingSensitivity, &productInterestsJSON, &pastObjectionsJSON,
 			&memory.CreatedAt, &memory.UpdatedAt,
 		)
@@ -145,7 +156,7 @@ func (s *MemoryStorage) ListMemories(tenantID string, limit, offset int) ([]*mod
 // GetMemoryByID retrieves customer memory by memory ID (tenant-scoped)
 func (s *MemoryStorage) GetMemoryByID(tenantID, memoryID string) (*models.CustomerMemory, error) {
 	query := `
-		SELECT id, tenant_id, customer_id, preferred_language, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
+		SELECT id, tenant_id, customer_id, preferred_language, language_override, pricing_sensitivity, product_interests, past_objections, created_at, updated_at
 		FROM customer_memory
 		WHERE id = $1 AND tenant_id = $2
 	`
@@ -153,7 +164,7 @@ func (s *MemoryStorage) GetMemoryByID(tenantID, memoryID string) (*models.Custom
 	var productInterestsJSON, pastObjectionsJSON string
 
 	err := s.client.DB.QueryRow(query, memoryID, tenantID).Scan(
-		&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage,
+		&memory.ID, &memory.TenantID, &memory.CustomerID, &memory.PreferredLanguage, &memory.LanguageOverride,
 		&memory.PricingSensitivity, &productInterestsJSON, &pastObjectionsJSON,
 		&memory.CreatedAt, &memory.UpdatedAt,
 	)
@@ -194,3 +205,85 @@ func (s *MemoryStorage) DeleteMemory(tenantID, memoryID string) error {
 	return nil
 }
 
+// UpsertFromAnalysis merges a conversation analysis into the customer's memory, creating it if
+// needed: the analysis' objections are merged into past_objections and productInterests (e.g. the
+// name of the product the conversation is about) into product_interests, without duplicates.
+func (s *MemoryStorage) UpsertFromAnalysis(tenantID, customerID string, metadata *models.ConversationMetadata, productInterests ...string) error {
+	if metadata == nil {
+		return nil
+	}
+
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	var productInterestsJSON, pastObjectionsJSON sql.NullString
+	err = tx.QueryRow(`
+		SELECT id, product_interests, past_objections
+		FROM customer_memory
+		WHERE customer_id = $1 AND tenant_id = $2
+	`, customerID, tenantID).Scan(&id, &productInterestsJSON, &pastObjectionsJSON)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get memory: %w", err)
+	}
+	exists := err == nil
+
+	var interests, objections []string
+	json.Unmarshal([]byte(productInterestsJSON.String), &interests)
+	json.Unmarshal([]byte(pastObjectionsJSON.String), &objections)
+	interests = mergeUnique(interests, productInterests, 0)
+	objections = mergeUnique(objections, metadata.Objections, s.maxObjectionsRetained)
+
+	interestsOut, _ := json.Marshal(interests)
+	objectionsOut, _ := json.Marshal(objections)
+	now := time.Now()
+	if exists {
+		_, err = tx.Exec(`
+			UPDATE customer_memory
+			SET product_interests = $1, past_objections = $2, updated_at = $3
+			WHERE id = $4 AND tenant_id = $5
+		`, string(interestsOut), string(objectionsOut), now, id, tenantID)
+	} else {
+		_, err = tx.Exec(`
+			INSERT INTO customer_memory (id, tenant_id, customer_id, preferred_language, language_override, pricing_sensitivity, product_interests, past_objections, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, uuid.New().String(), tenantID, customerID, "", false, "", string(interestsOut), string(objectionsOut), now, now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upsert memory: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit memory: %w", err)
+	}
+	return nil
+}
+
+// mergeUnique appends additions to existing, ignoring case and surrounding space when comparing.
+// A value that is already present moves to the end, so it counts as recent. Only the last max
+// entries are kept when max > 0.
+func mergeUnique(existing, additions []string, max int) []string {
+	merged := make([]string, 0, len(existing)+len(additions))
+	for _, values := range [][]string{existing, additions} {
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			for i, kept := range merged {
+				if strings.EqualFold(kept, value) {
+					merged = append(merged[:i], merged[i+1:]...)
+					break
+				}
+			}
+			merged = append(merged, value)
+		}
+	}
+	if max > 0 && len(merged) > max {
+		merged = merged[len(merged)-max:]
+	}
+	return merged
+}
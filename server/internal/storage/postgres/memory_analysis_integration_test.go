@@ -0,0 +1,111 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func analysisWithObjections(objections ...string) *models.ConversationMetadata {
+	return &models.ConversationMetadata{Intent: "buying", Objections: objections}
+}
+
+func TestUpsertFromAnalysisDeduplicatesAcrossRuns(t *testing.T) {
+	storage := NewMemoryStorage(testClient)
+	customerID := uuid.New().String()
+
+	if err := storage.UpsertFromAnalysis(testTenantID, customerID, analysisWithObjections("price", "trust"), "Pro Plan"); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if err := storage.UpsertFromAnalysis(testTenantID, customerID, analysisWithObjections("Price", "delivery"), "pro plan", "Starter Plan"); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if err := storage.UpsertFromAnalysis(testTenantID, customerID, analysisWithObjections()); err != nil {
+		t.Fatalf("run without objections: %v", err)
+	}
+
+	memory, err := storage.GetMemory(testTenantID, customerID)
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	// A repeated objection moves to the end as the most recent
+	if want := []string{"trust", "Price", "delivery"}; !reflect.DeepEqual(memory.PastObjections, want) {
+		t.Errorf("past_objections = %v, want %v", memory.PastObjections, want)
+	}
+	if want := []string{"pro plan", "Starter Plan"}; !reflect.DeepEqual(memory.ProductInterests, want) {
+		t.Errorf("product_interests = %v, want %v", memory.ProductInterests, want)
+	}
+}
+
+func TestUpsertFromAnalysisCapsObjections(t *testing.T) {
+	storage := NewMemoryStorage(testClient)
+	storage.SetMaxObjectionsRetained(3)
+	customerID := uuid.New().String()
+
+	runs := [][]string{
+		{"price", "trust"},
+		{"delivery", "competitor"},
+		{"price", "timing"},
+	}
+	for i, objections := range runs {
+		if err := storage.UpsertFromAnalysis(testTenantID, customerID, analysisWithObjections(objections...)); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+
+	memory, err := storage.GetMemory(testTenantID, customerID)
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if want := []string{"competitor", "price", "timing"}; !reflect.DeepEqual(memory.PastObjections, want) {
+		t.Errorf("past_objections = %v, want the %d most recent %v", memory.PastObjections, len(want), want)
+	}
+}
+
+func TestUpsertFromAnalysisKeepsExistingMemory(t *testing.T) {
+	storage := NewMemoryStorage(testClient)
+	customerID := uuid.New().String()
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := storage.CreateMemory(testTenantID, &models.CustomerMemory{
+		ID:                 uuid.New().String(),
+		CustomerID:         customerID,
+		PreferredLanguage:  "hi",
+		LanguageOverride:   true,
+		PricingSensitivity: "high",
+		ProductInterests:   []string{"Pro Plan"},
+		PastObjections:     []string{"trust"},
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}); err != nil {
+		t.Fatalf("CreateMemory: %v", err)
+	}
+
+	if err := storage.UpsertFromAnalysis(testTenantID, customerID, analysisWithObjections("price"), "Starter Plan"); err != nil {
+		t.Fatalf("UpsertFromAnalysis: %v", err)
+	}
+
+	memory, err := storage.GetMemory(testTenantID, customerID)
+	if err != nil {
+		t.Fatalf("GetMemory: %v", err)
+	}
+	if memory.PreferredLanguage != "hi" || !memory.LanguageOverride || memory.PricingSensitivity != "high" {
+		t.Errorf("memory = %+v, want language and pricing sensitivity unchanged", memory)
+	}
+	if want := []string{"trust", "price"}; !reflect.DeepEqual(memory.PastObjections, want) {
+		t.Errorf("past_objections = %v, want %v", memory.PastObjections, want)
+	}
+	if want := []string{"Pro Plan", "Starter Plan"}; !reflect.DeepEqual(memory.ProductInterests, want) {
+		t.Errorf("product_interests = %v, want %v", memory.ProductInterests, want)
+	}
+
+	// Memories are tenant-scoped
+	if _, err := storage.GetMemory("other-tenant", customerID); err == nil {
+		t.Error("expected no memory for the customer in another tenant")
+	}
+}
@@ -0,0 +1,111 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// countMessageDeletions returns how many deletions are recorded for a message
+func countMessageDeletions(t *testing.T, messageID string) int {
+	t.Helper()
+	var count int
+	if err := testClient.DB.QueryRow("SELECT COUNT(*) FROM message_deletions WHERE message_id = $1", messageID).Scan(&count); err != nil {
+		t.Fatalf("failed to count message deletions: %v", err)
+	}
+	return count
+}
+
+func TestDeleteMessageSoftDeletesAndRecordsDeletion(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := "deletion-" + uuid.New().String()
+	createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC().Truncate(time.Second))
+	msg := createSearchMessage(t, storage, tenantID, conversationID, "my card number is 4111")
+	kept := createSearchMessage(t, storage, tenantID, conversationID, "when does it ship?")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM message_deletions WHERE tenant_id = $1", tenantID) })
+
+	if err := storage.DeleteMessage(tenantID, msg.ID, "admin-1", "gdpr_request"); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+
+	deleted, err := storage.GetMessage(msg.ID)
+	if err != nil {
+		t.Fatalf("GetMessage: %v", err)
+	}
+	if deleted.DeletedAt == nil || deleted.DeletedBy == nil || *deleted.DeletedBy != "admin-1" {
+		t.Errorf("deleted message = %+v, want deleted_at and deleted_by set", deleted)
+	}
+	var reason, recordedTenant, recordedConversation string
+	err = testClient.DB.QueryRow("SELECT reason, tenant_id, conversation_id FROM message_deletions WHERE message_id = $1", msg.ID).
+		Scan(&reason, &recordedTenant, &recordedConversation)
+	if err != nil || reason != "gdpr_request" || recordedTenant != tenantID || recordedConversation != conversationID {
+		t.Errorf("deletion record = %s, %s, %s, %v; want the reason, tenant and conversation", reason, recordedTenant, recordedConversation, err)
+	}
+
+	messages, err := storage.GetMessagesByConversation(tenantID, conversationID)
+	if err != nil || len(messages) != 1 || messages[0].ID != kept.ID {
+		t.Errorf("messages = %v, %v; want only the kept message", messages, err)
+	}
+
+	// A second delete conflicts and records nothing
+	if err := storage.DeleteMessage(tenantID, msg.ID, "admin-2", "abuse"); !errors.Is(err, ErrMessageAlreadyDeleted) {
+		t.Errorf("second DeleteMessage = %v, want ErrMessageAlreadyDeleted", err)
+	}
+	if n := countMessageDeletions(t, msg.ID); n != 1 {
+		t.Errorf("deletion records = %d, want 1", n)
+	}
+	if again, _ := storage.GetMessage(msg.ID); again.DeletedBy == nil || *again.DeletedBy != "admin-1" {
+		t.Errorf("deleted_by = %v, want the first deletion kept", again.DeletedBy)
+	}
+}
+
+func TestDeleteMessageScopedToTenant(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := "deletion-" + uuid.New().String()
+	createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC().Truncate(time.Second))
+	msg := createSearchMessage(t, storage, tenantID, conversationID, "hello")
+
+	if err := storage.DeleteMessage("other-tenant", msg.ID, "admin-1", "abuse"); err == nil || errors.Is(err, ErrMessageAlreadyDeleted) {
+		t.Errorf("DeleteMessage from another tenant = %v, want not found", err)
+	}
+	if err := storage.DeleteMessage(tenantID, "missing", "admin-1", "abuse"); err == nil || errors.Is(err, ErrMessageAlreadyDeleted) {
+		t.Errorf("DeleteMessage of a missing message = %v, want not found", err)
+	}
+	if got, _ := storage.GetMessage(msg.ID); got.DeletedAt != nil {
+		t.Error("message deleted from another tenant")
+	}
+}
+
+func TestDeleteMessageRollsBackWhenDeletionCannotBeRecorded(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := "deletion-" + uuid.New().String()
+	createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC().Truncate(time.Second))
+	msg := createSearchMessage(t, storage, tenantID, conversationID, "my card number is 4111")
+
+	// Without the audit table the deletion can't be recorded, so the soft delete must not stick
+	if _, err := testClient.DB.Exec("ALTER TABLE message_deletions RENAME TO message_deletions_unavailable"); err != nil {
+		t.Fatalf("failed to rename message_deletions: %v", err)
+	}
+	restore := func() { testClient.DB.Exec("ALTER TABLE message_deletions_unavailable RENAME TO message_deletions") }
+	t.Cleanup(restore)
+
+	if err := storage.DeleteMessage(tenantID, msg.ID, "admin-1", "gdpr_request"); err == nil {
+		t.Fatal("DeleteMessage succeeded without recording the deletion")
+	}
+	restore()
+
+	if got, _ := storage.GetMessage(msg.ID); got.DeletedAt != nil {
+		t.Errorf("message deleted_at = %v, want the soft delete rolled back", got.DeletedAt)
+	}
+	if err := storage.DeleteMessage(tenantID, msg.ID, "admin-1", "gdpr_request"); err != nil {
+		t.Errorf("DeleteMessage after the failure = %v, want it to succeed", err)
+	}
+	testClient.DB.Exec("DELETE FROM message_deletions WHERE tenant_id = $1", tenantID)
+}
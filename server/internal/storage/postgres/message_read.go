@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// MarkRead records that readerID has read a message. Returns false if the reader had already read it.
+// Tenant ownership is verified through the message's conversation.
+func (s *ConversationStorage) MarkRead(messageID, readerID, tenantID string) (bool, error) {
+	result, err := s.client.DB.Exec(`
+		INSERT INTO message_reads (message_id, reader_id, tenant_id, read_at)
+		SELECT m.id, $1, c.tenant_id, $2
+		FROM messages m
+		INNER JOIN conversations c ON m.conversation_id = c.id
+		WHERE m.id = $3 AND c.tenant_id = $4 AND m.deleted_at IS NULL
+		ON CONFLICT (message_id, reader_id) DO NOTHING
+	`, readerID, time.Now(), messageID, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark message read: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetMessageReads returns the read receipts for a conversation's messages, keyed by message ID
+func (s *ConversationStorage) GetMessageReads(tenantID, conversationID string) (map[string][]*models.MessageRead, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT r.message_id, r.reader_id, r.read_at
+		FROM message_reads r
+		INNER JOIN messages m ON r.message_id = m.id
+		WHERE m.conversation_id = $1 AND r.tenant_id = $2
+		ORDER BY r.read_at ASC
+	`, conversationID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message reads: %w", err)
+	}
+	defer rows.Close()
+
+	reads := make(map[string][]*models.MessageRead)
+	for rows.Next() {
+		var messageID string
+		read := &models.MessageRead{}
+		if err := rows.Scan(&messageID, &read.ReaderID, &read.ReadAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message read: %w", err)
+		}
+		reads[messageID] = append(reads[messageID], read)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message reads: %w", err)
+	}
+	return reads, nil
+}
+
+// CountUnreadCustomerMessages counts a conversation's customer messages that no agent has read
+func (s *ConversationStorage) CountUnreadCustomerMessages(tenantID, conversationID string) (int, error) {
+	var count int
+	err := s.client.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM messages m
+		INNER JOIN conversations c ON m.conversation_id = c.id
+		WHERE m.conversation_id = $1 AND c.tenant_id = $2
+		  AND m.sender = 'customer' AND m.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM message_reads r WHERE r.message_id = m.id)
+	`, conversationID, tenantID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	return count, nil
+}
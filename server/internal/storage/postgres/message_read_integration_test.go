@@ -0,0 +1,69 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func newTestMessage(t *testing.T, storage *ConversationStorage, tenantID, conversationID, sender string) *models.Message {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	msg := &models.Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Sender:         sender,
+		Content:        "hello from " + sender,
+		Channel:        "web",
+		Language:       "en",
+		Timestamp:      now,
+		CreatedAt:      now,
+	}
+	if err := storage.CreateMessage(tenantID, msg); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	return msg
+}
+
+func TestMarkReadAndUnreadCount(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+	first := newTestMessage(t, storage, conv.TenantID, conv.ID, "customer")
+	newTestMessage(t, storage, conv.TenantID, conv.ID, "customer")
+	newTestMessage(t, storage, conv.TenantID, conv.ID, "agent")
+
+	if count, err := storage.CountUnreadCustomerMessages(testTenantID, conv.ID); err != nil || count != 2 {
+		t.Fatalf("unread before reads = %d, %v; want 2", count, err)
+	}
+
+	created, err := storage.MarkRead(first.ID, "agent-a", testTenantID)
+	if err != nil || !created {
+		t.Fatalf("MarkRead = %v, %v; want true", created, err)
+	}
+	if created, err := storage.MarkRead(first.ID, "agent-a", testTenantID); err != nil || created {
+		t.Errorf("repeat MarkRead = %v, %v; want false", created, err)
+	}
+	if _, err := storage.MarkRead(first.ID, "agent-b", testTenantID); err != nil {
+		t.Fatalf("MarkRead by second agent: %v", err)
+	}
+	if created, err := storage.MarkRead(first.ID, "agent-c", "other-tenant"); err != nil || created {
+		t.Errorf("MarkRead from another tenant = %v, %v; want false", created, err)
+	}
+
+	if count, err := storage.CountUnreadCustomerMessages(testTenantID, conv.ID); err != nil || count != 1 {
+		t.Errorf("unread after reads = %d, %v; want 1", count, err)
+	}
+
+	reads, err := storage.GetMessageReads(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetMessageReads: %v", err)
+	}
+	if len(reads[first.ID]) != 2 || len(reads) != 1 {
+		t.Errorf("reads = %v, want two receipts on the first message only", reads)
+	}
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestPatchConversationMetadataKeepsUnsetFields(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "active")
+
+	original := &models.ConversationMetadata{
+		ID:              uuid.New().String(),
+		ConversationID:  conv.ID,
+		Intent:          "buying",
+		IntentScore:     0.9,
+		Sentiment:       "neutral",
+		SentimentScore:  0.5,
+		SentimentModel:  "gemini-2.5-flash",
+		Emotions:        []string{"curiosity"},
+		Objections:      []string{"price"},
+		ComplexityScore: 4,
+		UpdatedAt:       time.Now(),
+	}
+	if err := storage.CreateConversationMetadata(conv.TenantID, original); err != nil {
+		t.Fatalf("CreateConversationMetadata: %v", err)
+	}
+
+	sentiment := "negative"
+	score := 0.2
+	if err := storage.PatchConversationMetadata(conv.TenantID, conv.ID, MetadataPatch{Sentiment: &sentiment, SentimentScore: &score}); err != nil {
+		t.Fatalf("PatchConversationMetadata: %v", err)
+	}
+
+	got, err := storage.GetConversationMetadata(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversationMetadata: %v", err)
+	}
+	if got.Sentiment != "negative" || got.SentimentScore != 0.2 {
+		t.Errorf("sentiment = %s (%.2f), want negative (0.20)", got.Sentiment, got.SentimentScore)
+	}
+	if got.Intent != "buying" || got.IntentScore != 0.9 {
+		t.Errorf("intent = %s (%.2f), want unchanged buying (0.90)", got.Intent, got.IntentScore)
+	}
+	if got.SentimentModel != original.SentimentModel || got.ComplexityScore != 4 {
+		t.Errorf("sentiment_model/complexity changed: %s %.1f", got.SentimentModel, got.ComplexityScore)
+	}
+	if !reflect.DeepEqual(got.Emotions, original.Emotions) || !reflect.DeepEqual(got.Objections, original.Objections) {
+		t.Errorf("emotions/objections changed: %v %v", got.Emotions, got.Objections)
+	}
+
+	// An empty slice clears a list; a nil one leaves it alone
+	if err := storage.PatchConversationMetadata(conv.TenantID, conv.ID, MetadataPatch{Objections: []string{}}); err != nil {
+		t.Fatalf("PatchConversationMetadata (clear objections): %v", err)
+	}
+	got, err = storage.GetConversationMetadata(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversationMetadata: %v", err)
+	}
+	if len(got.Objections) != 0 || !reflect.DeepEqual(got.Emotions, original.Emotions) {
+		t.Errorf("after clearing objections: objections=%v emotions=%v", got.Objections, got.Emotions)
+	}
+}
+
+func TestPatchConversationMetadataNotFound(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	intent := "support"
+
+	err := storage.PatchConversationMetadata(testTenantID, uuid.New().String(), MetadataPatch{Intent: &intent})
+	if err == nil || err.Error() != "metadata not found" {
+		t.Errorf("PatchConversationMetadata on missing metadata = %v, want metadata not found", err)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// createTestMetadata stores metadata with the given intent for a conversation
+func createTestMetadata(t *testing.T, storage *ConversationStorage, tenantID, conversationID, intent string) {
+	t.Helper()
+	metadata := &models.ConversationMetadata{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Intent:         intent,
+		IntentScore:    0.8,
+		Sentiment:      "neutral",
+		SentimentScore: 0.5,
+		Emotions:       []string{},
+		Objections:     []string{},
+		UpdatedAt:      time.Now(),
+	}
+	if err := storage.CreateConversationMetadata(tenantID, metadata); err != nil {
+		t.Fatalf("CreateConversationMetadata: %v", err)
+	}
+}
+
+func TestGetConversationMetadataIsTenantScoped(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+
+	convA := newTestConversation(t, storage, nil, "active")
+	createTestMetadata(t, storage, convA.TenantID, convA.ID, "buying")
+
+	tenantB := "test-" + uuid.New().String()
+	now := time.Now().UTC().Truncate(time.Second)
+	convB := &models.Conversation{
+		ID:        uuid.New().String(),
+		TenantID:  tenantB,
+		Status:    "active",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := storage.CreateConversation(tenantB, convB); err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM conversation_metadata WHERE conversation_id = $1", convB.ID)
+		testClient.DB.Exec("DELETE FROM conversations WHERE id = $1", convB.ID)
+	})
+	createTestMetadata(t, storage, convB.TenantID, convB.ID, "support")
+
+	got, err := storage.GetConversationMetadata(testTenantID, convA.ID)
+	if err != nil {
+		t.Fatalf("GetConversationMetadata own conversation: %v", err)
+	}
+	if got.Intent != "buying" {
+		t.Errorf("intent = %s, want buying", got.Intent)
+	}
+
+	// Tenant A must not be able to read tenant B's metadata by conversation ID
+	got, err = storage.GetConversationMetadata(testTenantID, convB.ID)
+	if err == nil || err.Error() != "conversation not found" {
+		t.Errorf("GetConversationMetadata across tenants = %+v, %v; want conversation not found", got, err)
+	}
+	if _, err := storage.GetConversationMetadata(tenantB, convB.ID); err != nil {
+		t.Errorf("GetConversationMetadata for tenant B: %v", err)
+	}
+
+	// A conversation of the tenant without analysis yet still reports missing metadata
+	fresh := newTestConversation(t, storage, nil, "active")
+	if _, err := storage.GetConversationMetadata(testTenantID, fresh.ID); err == nil || err.Error() != "metadata not found" {
+		t.Errorf("GetConversationMetadata without metadata = %v, want metadata not found", err)
+	}
+}
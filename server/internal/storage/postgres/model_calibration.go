@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+)
+
+// ModelCalibration is the baseline distribution of a model's raw output for a metric
+type ModelCalibration struct {
+	ModelName    string    `json:"model_name"`
+	Metric       string    `json:"metric"`
+	BaselineMean float64   `json:"baseline_mean"`
+	BaselineStd  float64   `json:"baseline_std"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ModelCalibrationStorage handles per-model score calibrations
+type ModelCalibrationStorage struct {
+	client *Client
+}
+
+// NewModelCalibrationStorage creates a new model calibration storage instance
+func NewModelCalibrationStorage(client *Client) *ModelCalibrationStorage {
+	return &ModelCalibrationStorage{client: client}
+}
+
+// SaveCalibration creates or replaces a model's calibration for a metric
+func (s *ModelCalibrationStorage) SaveCalibration(cal *ModelCalibration) error {
+	cal.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO model_calibrations (model_name, metric, baseline_mean, baseline_std, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(model_name, metric) DO UPDATE SET
+			baseline_mean = excluded.baseline_mean,
+			baseline_std = excluded.baseline_std,
+			updated_at = excluded.updated_at
+	`
+	_, err := s.client.DB.Exec(query, cal.ModelName, cal.Metric, cal.BaselineMean, cal.BaselineStd, cal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save model calibration: %w", err)
+	}
+	return nil
+}
+
+// ListCalibrations returns all model calibrations for a metric
+func (s *ModelCalibrationStorage) ListCalibrations(metric string) ([]*ModelCalibration, error) {
+	query := `
+		SELECT model_name, metric, baseline_mean, baseline_std, updated_at
+		FROM model_calibrations
+		WHERE metric = $1
+		ORDER BY model_name
+	`
+	rows, err := s.client.DB.Query(query, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model calibrations: %w", err)
+	}
+	defer rows.Close()
+
+	var calibrations []*ModelCalibration
+	for rows.Next() {
+		cal := &ModelCalibration{}
+		if err := rows.Scan(&cal.ModelName, &cal.Metric, &cal.BaselineMean, &cal.BaselineStd, &cal.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan model calibration: %w", err)
+		}
+		calibrations = append(calibrations, cal)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating model calibrations: %w", err)
+	}
+	return calibrations, nil
+}
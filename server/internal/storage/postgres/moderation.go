@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrModerationPatternNotFound is returned when a moderation list entry doesn't exist for the tenant
+var ErrModerationPatternNotFound = errors.New("moderation pattern not found")
+
+// ModerationListEntry is a pattern on one of a tenant's moderation lists
+type ModerationListEntry struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	ListType  string    `json:"list_type"` // "block" or "flag"
+	Pattern   string    `json:"pattern"`   // Case-insensitive regular expression
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationEvent records a message moderation flagged or blocked. Message content isn't stored.
+type ModerationEvent struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	ConversationID string    `json:"conversation_id"`
+	MessageID      string    `json:"message_id"` // Never stored when the message was blocked
+	Sender         string    `json:"sender"`
+	Action         string    `json:"action"` // "flag" or "block"
+	Categories     []string  `json:"categories"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ModerationStorage handles moderation lists and events
+type ModerationStorage struct {
+	client *Client
+}
+
+// NewModerationStorage creates a new moderation storage instance
+func NewModerationStorage(client *Client) *ModerationStorage {
+	return &ModerationStorage{client: client}
+}
+
+// ListModerationPatterns returns the tenant's moderation list entries, oldest first
+func (s *ModerationStorage) ListModerationPatterns(tenantID string) ([]*ModerationListEntry, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, tenant_id, list_type, pattern, created_at
+		FROM moderation_lists
+		WHERE tenant_id = $1
+		ORDER BY created_at, id
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moderation patterns: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*ModerationListEntry{}
+	for rows.Next() {
+		entry := &ModerationListEntry{}
+		if err := rows.Scan(&entry.ID, &entry.TenantID, &entry.ListType, &entry.Pattern, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan moderation pattern: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list moderation patterns: %w", err)
+	}
+	return entries, nil
+}
+
+// AddModerationPattern stores a moderation list entry; ID and CreatedAt are set when empty
+func (s *ModerationStorage) AddModerationPattern(entry *ModerationListEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	_, err := s.client.DB.Exec(`
+		INSERT INTO moderation_lists (id, tenant_id, list_type, pattern, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, entry.ID, entry.TenantID, entry.ListType, entry.Pattern, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add moderation pattern: %w", err)
+	}
+	return nil
+}
+
+// DeleteModerationPattern removes a moderation list entry
+func (s *ModerationStorage) DeleteModerationPattern(tenantID, id string) error {
+	result, err := s.client.DB.Exec("DELETE FROM moderation_lists WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete moderation pattern: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrModerationPatternNotFound
+	}
+	return nil
+}
+
+// RecordModerationEvent stores a moderation event; ID and CreatedAt are set when empty
+func (s *ModerationStorage) RecordModerationEvent(event *ModerationEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	categories := event.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	categoriesJSON, err := json.Marshal(categories)
+	if err != nil {
+		return fmt.Errorf("failed to marshal moderation categories: %w", err)
+	}
+
+	_, err = s.client.DB.Exec(`
+		INSERT INTO moderation_events (id, tenant_id, conversation_id, message_id, sender, action, categories, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, event.ID, event.TenantID, event.ConversationID, event.MessageID, event.Sender, event.Action, string(categoriesJSON), event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record moderation event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestModerationPatternsAddListDelete(t *testing.T) {
+	storage := NewModerationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM moderation_lists WHERE tenant_id = $1", tenantID) })
+
+	now := time.Now().UTC()
+	block := &ModerationListEntry{TenantID: tenantID, ListType: "block", Pattern: `crypto\s+giveaway`, CreatedAt: now.Add(-time.Minute)}
+	flag := &ModerationListEntry{TenantID: tenantID, ListType: "flag", Pattern: "competitor", CreatedAt: now}
+	for _, entry := range []*ModerationListEntry{block, flag} {
+		if err := storage.AddModerationPattern(entry); err != nil {
+			t.Fatalf("AddModerationPattern: %v", err)
+		}
+	}
+
+	entries, err := storage.ListModerationPatterns(tenantID)
+	if err != nil {
+		t.Fatalf("ListModerationPatterns: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != block.ID || entries[0].Pattern != `crypto\s+giveaway` || entries[1].ListType != "flag" {
+		t.Fatalf("entries = %+v, want both patterns, oldest first", entries)
+	}
+
+	if err := storage.DeleteModerationPattern("other-tenant", block.ID); !errors.Is(err, ErrModerationPatternNotFound) {
+		t.Errorf("delete from another tenant = %v, want ErrModerationPatternNotFound", err)
+	}
+	if err := storage.DeleteModerationPattern(tenantID, block.ID); err != nil {
+		t.Fatalf("DeleteModerationPattern: %v", err)
+	}
+	if entries, _ := storage.ListModerationPatterns(tenantID); len(entries) != 1 || entries[0].ID != flag.ID {
+		t.Errorf("entries after delete = %+v, want only the flag pattern", entries)
+	}
+}
+
+func TestRecordModerationEvent(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewModerationStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM moderation_events WHERE tenant_id = $1", tenantID) })
+	conversationID := uuid.New().String()
+	createConversationAt(t, conversations, tenantID, conversationID, nil, time.Now().UTC())
+
+	event := &ModerationEvent{
+		TenantID:       tenantID,
+		ConversationID: conversationID,
+		MessageID:      uuid.New().String(),
+		Sender:         "customer",
+		Action:         "block",
+		Categories:     []string{"pii_email", "blocklist"},
+	}
+	if err := storage.RecordModerationEvent(event); err != nil {
+		t.Fatalf("RecordModerationEvent: %v", err)
+	}
+	if event.ID == "" {
+		t.Fatal("RecordModerationEvent should set the ID")
+	}
+
+	var action, categories string
+	err := testClient.DB.QueryRow("SELECT action, categories FROM moderation_events WHERE id = $1", event.ID).Scan(&action, &categories)
+	if err != nil {
+		t.Fatalf("read event: %v", err)
+	}
+	if action != "block" || categories != `["pii_email","blocklist"]` {
+		t.Errorf("event = %s %s, want the block and its categories", action, categories)
+	}
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// NoteStorage handles agents' internal notes on conversations
+type NoteStorage struct {
+	client *Client
+}
+
+// NewNoteStorage creates a new note storage instance
+func NewNoteStorage(client *Client) *NoteStorage {
+	return &NoteStorage{client: client}
+}
+
+// CreateNote stores a note on one of the tenant's conversations
+func (s *NoteStorage) CreateNote(note *models.ConversationNote) error {
+	var count int
+	err := s.client.DB.QueryRow(
+		"SELECT COUNT(*) FROM conversations WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL",
+		note.ConversationID, note.TenantID,
+	).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+
+	if note.ID == "" {
+		note.ID = uuid.New().String()
+	}
+	now := time.Now()
+	note.CreatedAt, note.UpdatedAt = now, now
+
+	query := `
+		INSERT INTO conversation_notes (id, conversation_id, tenant_id, agent_id, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = s.client.DB.Exec(query, note.ID, note.ConversationID, note.TenantID, note.AgentID, note.Content, note.CreatedAt, note.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	return nil
+}
+
+// GetNote retrieves a note on one of the tenant's conversations
+func (s *NoteStorage) GetNote(tenantID, conversationID, noteID string) (*models.ConversationNote, error) {
+	note := &models.ConversationNote{}
+	err := s.client.DB.QueryRow(`
+		SELECT id, conversation_id, tenant_id, agent_id, content, created_at, updated_at
+		FROM conversation_notes
+		WHERE id = $1 AND conversation_id = $2 AND tenant_id = $3
+	`, noteID, conversationID, tenantID).Scan(
+		&note.ID, &note.ConversationID, &note.TenantID, &note.AgentID, &note.Content, &note.CreatedAt, &note.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	return note, nil
+}
+
+// ListNotes lists a conversation's notes, oldest first
+func (s *NoteStorage) ListNotes(tenantID, conversationID string) ([]models.ConversationNote, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, conversation_id, tenant_id, agent_id, content, created_at, updated_at
+		FROM conversation_notes
+		WHERE conversation_id = $1 AND tenant_id = $2
+		ORDER BY created_at ASC, id ASC
+	`, conversationID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []models.ConversationNote
+	for rows.Next() {
+		var note models.ConversationNote
+		if err := rows.Scan(&note.ID, &note.ConversationID, &note.TenantID, &note.AgentID, &note.Content, &note.CreatedAt, &note.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notes: %w", err)
+	}
+	return notes, nil
+}
+
+// UpdateNote replaces a note's content
+func (s *NoteStorage) UpdateNote(note *models.ConversationNote) error {
+	note.UpdatedAt = time.Now()
+	result, err := s.client.DB.Exec(
+		"UPDATE conversation_notes SET content = $1, updated_at = $2 WHERE id = $3 AND conversation_id = $4 AND tenant_id = $5",
+		note.Content, note.UpdatedAt, note.ID, note.ConversationID, note.TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("note not found")
+	}
+	return nil
+}
+
+// DeleteNote removes a note
+func (s *NoteStorage) DeleteNote(tenantID, conversationID, noteID string) error {
+	result, err := s.client.DB.Exec(
+		"DELETE FROM conversation_notes WHERE id = $1 AND conversation_id = $2 AND tenant_id = $3",
+		noteID, conversationID, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("note not found")
+	}
+	return nil
+}
+
+// GetNotedConversations returns the IDs of the tenant's conversations that have notes
+func (s *NoteStorage) GetNotedConversations(tenantID string) (map[string]bool, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT conversation_id FROM conversation_notes WHERE tenant_id = $1", tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list noted conversations: %w", err)
+	}
+	defer rows.Close()
+
+	noted := make(map[string]bool)
+	for rows.Next() {
+		var conversationID string
+		if err := rows.Scan(&conversationID); err != nil {
+			return nil, fmt.Errorf("failed to scan noted conversation: %w", err)
+		}
+		noted[conversationID] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating noted conversations: %w", err)
+	}
+	return noted, nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestNoteStorageCRUD(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewNoteStorage(testClient)
+	tenantID := newIsolatedTenant(t, "note", "conversation_notes", "conversations")
+	convID := tenantID + "-c1"
+	createConversationAt(t, conversations, tenantID, convID, nil, time.Now())
+
+	first := &models.ConversationNote{ConversationID: convID, TenantID: tenantID, AgentID: "agent-1", Content: "Budget approval next week"}
+	second := &models.ConversationNote{ConversationID: convID, TenantID: tenantID, AgentID: "agent-2", Content: "Prefers email"}
+	for _, note := range []*models.ConversationNote{first, second} {
+		if err := storage.CreateNote(note); err != nil {
+			t.Fatalf("CreateNote: %v", err)
+		}
+	}
+	missing := &models.ConversationNote{ConversationID: tenantID + "-missing", TenantID: tenantID, AgentID: "agent-1", Content: "x"}
+	if err := storage.CreateNote(missing); err == nil || !strings.Contains(err.Error(), "conversation not found") {
+		t.Errorf("note on unknown conversation = %v, want conversation not found", err)
+	}
+
+	notes, err := storage.ListNotes(tenantID, convID)
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if len(notes) != 2 || notes[0].ID != first.ID || notes[1].AgentID != "agent-2" {
+		t.Fatalf("notes = %+v, want both notes oldest first", notes)
+	}
+
+	first.Content = "Budget approved"
+	if err := storage.UpdateNote(first); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	updated, err := storage.GetNote(tenantID, convID, first.ID)
+	if err != nil || updated.Content != "Budget approved" {
+		t.Errorf("updated note = %+v (%v), want the new content", updated, err)
+	}
+
+	if err := storage.DeleteNote(tenantID, convID, second.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if err := storage.DeleteNote(tenantID, convID, second.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("second delete = %v, want note not found", err)
+	}
+
+	noted, err := storage.GetNotedConversations(tenantID)
+	if err != nil || len(noted) != 1 || !noted[convID] {
+		t.Errorf("noted conversations = %v (%v), want only %s", noted, err, convID)
+	}
+}
+
+func TestNoteStorageTenantScoping(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewNoteStorage(testClient)
+	tenantID := newIsolatedTenant(t, "note", "conversation_notes", "conversations")
+	otherTenantID := newIsolatedTenant(t, "note", "conversation_notes", "conversations")
+	convID := tenantID + "-c1"
+	createConversationAt(t, conversations, tenantID, convID, nil, time.Now())
+
+	note := &models.ConversationNote{ConversationID: convID, TenantID: tenantID, AgentID: "agent-1", Content: "Internal only"}
+	if err := storage.CreateNote(note); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	// Another tenant can't note, read, change or delete the conversation's notes
+	foreign := &models.ConversationNote{ConversationID: convID, TenantID: otherTenantID, AgentID: "agent-9", Content: "x"}
+	if err := storage.CreateNote(foreign); err == nil {
+		t.Error("note on another tenant's conversation was created")
+	}
+	if notes, err := storage.ListNotes(otherTenantID, convID); err != nil || len(notes) != 0 {
+		t.Errorf("other tenant's notes = %+v (%v), want none", notes, err)
+	}
+	if _, err := storage.GetNote(otherTenantID, convID, note.ID); err == nil {
+		t.Error("other tenant read the note")
+	}
+	if err := storage.UpdateNote(&models.ConversationNote{ID: note.ID, ConversationID: convID, TenantID: otherTenantID, Content: "changed"}); err == nil {
+		t.Error("other tenant updated the note")
+	}
+	if err := storage.DeleteNote(otherTenantID, convID, note.ID); err == nil {
+		t.Error("other tenant deleted the note")
+	}
+	if noted, _ := storage.GetNotedConversations(otherTenantID); len(noted) != 0 {
+		t.Errorf("other tenant's noted conversations = %v, want none", noted)
+	}
+
+	kept, err := storage.GetNote(tenantID, convID, note.ID)
+	if err != nil || kept.Content != "Internal only" {
+		t.Errorf("note = %+v (%v), want it unchanged", kept, err)
+	}
+}
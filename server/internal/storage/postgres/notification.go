@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification statuses
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// Notification is an outbound notification queued for (re)delivery
+type Notification struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	Channel       string     `json:"channel"` // delivery integration, e.g. "slack"
+	Payload       string     `json:"payload"` // JSON-encoded event
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// NotificationStorage handles the outbound notification queue
+type NotificationStorage struct {
+	client *Client
+}
+
+// NewNotificationStorage creates a new notification storage instance
+func NewNotificationStorage(client *Client) *NotificationStorage {
+	return &NotificationStorage{client: client}
+}
+
+// Enqueue queues a pending notification for delivery at nextAttemptAt
+func (s *NotificationStorage) Enqueue(tenantID, channel, payload string, nextAttemptAt time.Time) (*Notification, error) {
+	n := &Notification{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Channel:       channel,
+		Payload:       payload,
+		Status:        NotificationStatusPending,
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     time.Now(),
+	}
+	query := `
+		INSERT INTO notifications (id, tenant_id, channel, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7)
+	`
+	_, err := s.client.DB.Exec(query, n.ID, n.TenantID, n.Channel, n.Payload, n.Status, n.NextAttemptAt, n.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return n, nil
+}
+
+// ListDue returns pending notifications for a channel whose next attempt is due, oldest first
+func (s *NotificationStorage) ListDue(channel string, limit int) ([]*Notification, error) {
+	query := `
+		SELECT id, tenant_id, channel, payload, status, attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM notifications
+		WHERE channel = $1 AND status = $2 AND next_attempt_at <= $3
+		ORDER BY next_attempt_at ASC
+		LIMIT $4
+	`
+	rows, err := s.client.DB.Query(query, channel, NotificationStatusPending, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*Notification
+	for rows.Next() {
+		n := &Notification{}
+		var lastError sql.NullString
+		var sentAt sql.NullTime
+		if err := rows.Scan(
+			&n.ID, &n.TenantID, &n.Channel, &n.Payload, &n.Status, &n.Attempts,
+			&lastError, &n.NextAttemptAt, &n.CreatedAt, &sentAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if lastError.Valid {
+			n.LastError = &lastError.String
+		}
+		if sentAt.Valid {
+			n.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkSent marks a notification as delivered
+func (s *NotificationStorage) MarkSent(id string) error {
+	query := `UPDATE notifications SET status = $1, attempts = attempts + 1, sent_at = $2 WHERE id = $3`
+	if _, err := s.client.DB.Exec(query, NotificationStatusSent, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to mark notification sent: %w", err)
+	}
+	return nil
+}
+
+// Reschedule records a failed attempt and schedules the next one
+func (s *NotificationStorage) Reschedule(id, lastError string, nextAttemptAt time.Time) error {
+	query := `UPDATE notifications SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`
+	if _, err := s.client.DB.Exec(query, lastError, nextAttemptAt, id); err != nil {
+		return fmt.Errorf("failed to reschedule notification: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed gives up on a notification
+func (s *NotificationStorage) MarkFailed(id, lastError string) error {
+	query := `UPDATE notifications SET status = $1, attempts = attempts + 1, last_error = $2 WHERE id = $3`
+	if _, err := s.client.DB.Exec(query, NotificationStatusFailed, lastError, id); err != nil {
+		return fmt.Errorf("failed to mark notification failed: %w", err)
+	}
+	return nil
+}
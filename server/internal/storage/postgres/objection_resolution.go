@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Objection resolution statuses
+const (
+	ObjectionUnresolved = "unresolved"
+	ObjectionResolved   = "resolved"
+)
+
+// ObjectionResolution tracks one objection type raised in a conversation
+type ObjectionResolution struct {
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	ConversationID     string     `json:"conversation_id"`
+	ObjectionType      string     `json:"objection_type"`
+	ResolutionStatus   string     `json:"resolution_status"` // resolved or unresolved
+	RaisedAt           time.Time  `json:"raised_at"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty"`
+	ResolvingMessageID *string    `json:"resolving_message_id,omitempty"`
+}
+
+// ObjectionResolutionStorage records when objections are raised and resolved
+type ObjectionResolutionStorage struct {
+	client *Client
+}
+
+// NewObjectionResolutionStorage creates a new objection resolution storage instance
+func NewObjectionResolutionStorage(client *Client) *ObjectionResolutionStorage {
+	return &ObjectionResolutionStorage{client: client}
+}
+
+// RecordObjectionsRaised marks objections as unresolved in a conversation. An objection raised
+// again after it was resolved is reopened.
+func (s *ObjectionResolutionStorage) RecordObjectionsRaised(tenantID, conversationID string, objectionTypes []string, raisedAt time.Time) error {
+	for _, objectionType := range objectionTypes {
+		_, err := s.client.DB.Exec(`
+			INSERT INTO objection_resolutions (id, tenant_id, conversation_id, objection_type, resolution_status, raised_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT(conversation_id, objection_type) DO UPDATE SET
+				resolution_status = excluded.resolution_status,
+				raised_at = excluded.raised_at,
+				resolved_at = NULL,
+				resolving_message_id = NULL
+		`, uuid.New().String(), tenantID, conversationID, objectionType, ObjectionUnresolved, raisedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record raised objection: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordObjectionsResolved marks objections as resolved in a conversation, crediting the agent
+// message that resolved them (nil when unknown)
+func (s *ObjectionResolutionStorage) RecordObjectionsResolved(tenantID, conversationID string, objectionTypes []string, resolvingMessageID *string, resolvedAt time.Time) error {
+	for _, objectionType := range objectionTypes {
+		_, err := s.client.DB.Exec(`
+			INSERT INTO objection_resolutions (id, tenant_id, conversation_id, objection_type, resolution_status, raised_at, resolved_at, resolving_message_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT(conversation_id, objection_type) DO UPDATE SET
+				resolution_status = excluded.resolution_status,
+				resolved_at = excluded.resolved_at,
+				resolving_message_id = excluded.resolving_message_id
+		`, uuid.New().String(), tenantID, conversationID, objectionType, ObjectionResolved, resolvedAt, resolvedAt, resolvingMessageID)
+		if err != nil {
+			return fmt.Errorf("failed to record resolved objection: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListObjectionResolutions returns a conversation's tracked objections by objection type
+func (s *ObjectionResolutionStorage) ListObjectionResolutions(tenantID, conversationID string) ([]*ObjectionResolution, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT id, tenant_id, conversation_id, objection_type, resolution_status, raised_at, resolved_at, resolving_message_id
+		FROM objection_resolutions
+		WHERE tenant_id = $1 AND conversation_id = $2
+		ORDER BY objection_type ASC
+	`, tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objection resolutions: %w", err)
+	}
+	defer rows.Close()
+
+	resolutions := []*ObjectionResolution{}
+	for rows.Next() {
+		r := &ObjectionResolution{}
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.ConversationID, &r.ObjectionType, &r.ResolutionStatus,
+			&r.RaisedAt, &r.ResolvedAt, &r.ResolvingMessageID); err != nil {
+			return nil, fmt.Errorf("failed to scan objection resolution: %w", err)
+		}
+		resolutions = append(resolutions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating objection resolutions: %w", err)
+	}
+	return resolutions, nil
+}
+
+// GetResolutionRates returns, per objection type, the share (0-1) of the tenant's conversations
+// raising it where it was resolved
+func (s *ObjectionResolutionStorage) GetResolutionRates(tenantID string) (map[string]float64, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT objection_type,
+			SUM(CASE WHEN resolution_status = $1 THEN 1 ELSE 0 END),
+			COUNT(*)
+		FROM objection_resolutions
+		WHERE tenant_id = $2
+		GROUP BY objection_type
+	`, ObjectionResolved, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get objection resolution rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]float64)
+	for rows.Next() {
+		var objectionType string
+		var resolved, total int
+		if err := rows.Scan(&objectionType, &resolved, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan objection resolution rate: %w", err)
+		}
+		if total > 0 {
+			rates[objectionType] = float64(resolved) / float64(total)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating objection resolution rates: %w", err)
+	}
+	return rates, nil
+}
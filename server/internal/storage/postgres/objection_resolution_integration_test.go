@@ -0,0 +1,71 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObjectionResolutionLifecycle(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewObjectionResolutionStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	firstID, secondID := "first-"+tenantID, "second-"+tenantID
+	createConversationAt(t, conversations, tenantID, firstID, nil, start)
+	createConversationAt(t, conversations, tenantID, secondID, nil, start)
+
+	if err := storage.RecordObjectionsRaised(tenantID, firstID, []string{"price", "timing"}, start); err != nil {
+		t.Fatalf("RecordObjectionsRaised: %v", err)
+	}
+	if err := storage.RecordObjectionsRaised(tenantID, secondID, []string{"price"}, start); err != nil {
+		t.Fatalf("RecordObjectionsRaised: %v", err)
+	}
+	messageID := "msg-" + tenantID
+	resolvedAt := start.Add(10 * time.Minute)
+	if err := storage.RecordObjectionsResolved(tenantID, firstID, []string{"price"}, &messageID, resolvedAt); err != nil {
+		t.Fatalf("RecordObjectionsResolved: %v", err)
+	}
+
+	resolutions, err := storage.ListObjectionResolutions(tenantID, firstID)
+	if err != nil {
+		t.Fatalf("ListObjectionResolutions: %v", err)
+	}
+	if len(resolutions) != 2 {
+		t.Fatalf("resolutions = %d, want 2", len(resolutions))
+	}
+	price, timing := resolutions[0], resolutions[1]
+	if price.ObjectionType != "price" || price.ResolutionStatus != ObjectionResolved ||
+		price.ResolvingMessageID == nil || *price.ResolvingMessageID != messageID ||
+		price.ResolvedAt == nil || !price.ResolvedAt.Equal(resolvedAt) || !price.RaisedAt.Equal(start) {
+		t.Errorf("price = %+v, want resolved by %s at %v", price, messageID, resolvedAt)
+	}
+	if timing.ObjectionType != "timing" || timing.ResolutionStatus != ObjectionUnresolved || timing.ResolvedAt != nil {
+		t.Errorf("timing = %+v, want unresolved", timing)
+	}
+
+	rates, err := storage.GetResolutionRates(tenantID)
+	if err != nil {
+		t.Fatalf("GetResolutionRates: %v", err)
+	}
+	if rates["price"] != 0.5 || rates["timing"] != 0 || len(rates) != 2 {
+		t.Errorf("rates = %v, want price 0.5 and timing 0", rates)
+	}
+
+	// Raising a resolved objection again reopens it
+	if err := storage.RecordObjectionsRaised(tenantID, firstID, []string{"price"}, start.Add(20*time.Minute)); err != nil {
+		t.Fatalf("RecordObjectionsRaised: %v", err)
+	}
+	resolutions, err = storage.ListObjectionResolutions(tenantID, firstID)
+	if err != nil {
+		t.Fatalf("ListObjectionResolutions: %v", err)
+	}
+	if resolutions[0].ResolutionStatus != ObjectionUnresolved || resolutions[0].ResolvingMessageID != nil || resolutions[0].ResolvedAt != nil {
+		t.Errorf("price = %+v, want reopened as unresolved", resolutions[0])
+	}
+
+	if rates, err := storage.GetResolutionRates("other-" + tenantID); err != nil || len(rates) != 0 {
+		t.Errorf("other tenant rates = %v, %v; want none", rates, err)
+	}
+}
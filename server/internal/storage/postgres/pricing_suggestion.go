@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// PricingSuggestionStats holds raw aggregates for pricing suggestion review analytics
+type PricingSuggestionStats struct {
+	Total           int
+	Pending         int
+	Approved        int
+	Rejected        int
+	AvgApprovalMins float64
+}
+
+// PricingSuggestionStorage handles pricing suggestion persistence
+type PricingSuggestionStorage struct {
+	client *Client
+}
+
+// NewPricingSuggestionStorage creates a new pricing suggestion storage instance
+func NewPricingSuggestionStorage(client *Client) *PricingSuggestionStorage {
+	return &PricingSuggestionStorage{client: client}
+}
+
+const pricingSuggestionColumns = `id, conversation_id, tenant_id, min_price, max_price, confidence, reasoning, status,
+		approved_by, approved_at, rejected_by, rejected_at, created_at`
+
+// CreatePricingSuggestion stores a new pricing suggestion
+func (s *PricingSuggestionStorage) CreatePricingSuggestion(suggestion *models.PricingSuggestion) error {
+	query := `
+		INSERT INTO pricing_suggestions (id, conversation_id, tenant_id, min_price, max_price, confidence, reasoning, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := s.client.DB.Exec(query,
+		suggestion.ID, suggestion.ConversationID, suggestion.TenantID, suggestion.MinPrice, suggestion.MaxPrice,
+		suggestion.Confidence, suggestion.Reasoning, suggestion.Status, suggestion.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pricing suggestion: %w", err)
+	}
+	return nil
+}
+
+// GetPricingSuggestion retrieves a pricing suggestion by ID (tenant-scoped)
+func (s *PricingSuggestionStorage) GetPricingSuggestion(tenantID, suggestionID string) (*models.PricingSuggestion, error) {
+	query := `SELECT ` + pricingSuggestionColumns + ` FROM pricing_suggestions WHERE id = $1 AND tenant_id = $2`
+	suggestion, err := scanPricingSuggestion(s.client.DB.QueryRow(query, suggestionID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("pricing suggestion not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pricing suggestion: %w", err)
+	}
+	return suggestion, nil
+}
+
+// ListPricingSuggestions lists pricing suggestions for a conversation, newest first
+func (s *PricingSuggestionStorage) ListPricingSuggestions(tenantID, conversationID string) ([]*models.PricingSuggestion, error) {
+	query := `SELECT ` + pricingSuggestionColumns + ` FROM pricing_suggestions
+		WHERE tenant_id = $1 AND conversation_id = $2
+		ORDER BY created_at DESC`
+	return s.queryPricingSuggestions(query, tenantID, conversationID)
+}
+
+// ListRejectedPricingSuggestions lists all rejected pricing suggestions for a tenant
+func (s *PricingSuggestionStorage) ListRejectedPricingSuggestions(tenantID string) ([]*models.PricingSuggestion, error) {
+	query := `SELECT ` + pricingSuggestionColumns + ` FROM pricing_suggestions
+		WHERE tenant_id = $1 AND status = 'rejected'
+		ORDER BY created_at DESC`
+	return s.queryPricingSuggestions(query, tenantID)
+}
+
+// GetLatestApprovedPricing returns the most recently approved suggestion for a conversation, or nil
+func (s *PricingSuggestionStorage) GetLatestApprovedPricing(tenantID, conversationID string) (*models.PricingSuggestion, error) {
+	query := `SELECT ` + pricingSuggestionColumns + ` FROM pricing_suggestions
+		WHERE tenant_id = $1 AND conversation_id = $2 AND status = 'approved'
+		ORDER BY approved_at DESC
+		LIMIT 1`
+	suggestion, err := scanPricingSuggestion(s.client.DB.QueryRow(query, tenantID, conversationID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approved pricing: %w", err)
+	}
+	return suggestion, nil
+}
+
+// ApprovePricingSuggestion marks a pending suggestion as approved
+func (s *PricingSuggestionStorage) ApprovePricingSuggestion(tenantID, suggestionID, approvedBy string) error {
+	query := `
+		UPDATE pricing_suggestions
+		SET status = 'approved', approved_by = $1, approved_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND status = 'pending'
+	`
+	return s.review(query, approvedBy, suggestionID, tenantID)
+}
+
+// RejectPricingSuggestion marks a pending suggestion as rejected
+func (s *PricingSuggestionStorage) RejectPricingSuggestion(tenantID, suggestionID, rejectedBy string) error {
+	query := `
+		UPDATE pricing_suggestions
+		SET status = 'rejected', rejected_by = $1, rejected_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND status = 'pending'
+	`
+	return s.review(query, rejectedBy, suggestionID, tenantID)
+}
+
+func (s *PricingSuggestionStorage) review(query, reviewer, suggestionID, tenantID string) error {
+	result, err := s.client.DB.Exec(query, reviewer, time.Now(), suggestionID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update pricing suggestion: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pricing suggestion not found or already reviewed")
+	}
+	return nil
+}
+
+// GetPricingSuggestionStats aggregates review counts and average approval time for a tenant
+func (s *PricingSuggestionStorage) GetPricingSuggestionStats(tenantID string) (*PricingSuggestionStats, error) {
+	approvalMinutes := "(EXTRACT(EPOCH FROM (approved_at - created_at)) / 60.0)"
+	if s.client.DBType == "sqlite" {
+		approvalMinutes = "((julianday(approved_at) - julianday(created_at)) * 1440.0)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 'approved' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 'rejected' THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(CASE WHEN status = 'approved' THEN %s END), 0)
+		FROM pricing_suggestions
+		WHERE tenant_id = $1
+	`, approvalMinutes)
+
+	stats := &PricingSuggestionStats{}
+	err := s.client.DB.QueryRow(query, tenantID).Scan(
+		&stats.Total, &stats.Pending, &stats.Approved, &stats.Rejected, &stats.AvgApprovalMins,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pricing suggestion stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (s *PricingSuggestionStorage) queryPricingSuggestions(query string, args ...interface{}) ([]*models.PricingSuggestion, error) {
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pricing suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []*models.PricingSuggestion{}
+	for rows.Next() {
+		suggestion, err := scanPricingSuggestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pricing suggestion: %w", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pricing suggestions: %w", err)
+	}
+	return suggestions, nil
+}
+
+func scanPricingSuggestion(row rowScanner) (*models.PricingSuggestion, error) {
+	p := &models.PricingSuggestion{}
+	var reasoning, approvedBy, rejectedBy sql.NullString
+	var approvedAt, rejectedAt sql.NullTime
+	err := row.Scan(
+		&p.ID, &p.ConversationID, &p.TenantID, &p.MinPrice, &p.MaxPrice, &p.Confidence, &reasoning, &p.Status,
+		&approvedBy, &approvedAt, &rejectedBy, &rejectedAt, &p.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.Reasoning = reasoning.String
+	if approvedBy.Valid {
+		p.ApprovedBy = &approvedBy.String
+	}
+	if approvedAt.Valid {
+		p.ApprovedAt = &approvedAt.Time
+	}
+	if rejectedBy.Valid {
+		p.RejectedBy = &rejectedBy.String
+	}
+	if rejectedAt.Valid {
+		p.RejectedAt = &rejectedAt.Time
+	}
+	return p, nil
+}
@@ -0,0 +1,96 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func createTestPricing(t *testing.T, storage *PricingSuggestionStorage, tenantID, conversationID string, min, max float64) *models.PricingSuggestion {
+	t.Helper()
+	suggestion := &models.PricingSuggestion{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		MinPrice:       min,
+		MaxPrice:       max,
+		Confidence:     0.7,
+		Reasoning:      "Budget mentioned",
+		Status:         models.PricingStatusPending,
+		CreatedAt:      time.Now().Add(-2 * time.Hour),
+	}
+	if err := storage.CreatePricingSuggestion(suggestion); err != nil {
+		t.Fatalf("CreatePricingSuggestion: %v", err)
+	}
+	return suggestion
+}
+
+func TestPricingSuggestionReviewOnlyFromPending(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewPricingSuggestionStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := "pricing-" + uuid.New().String()
+	createConversationAt(t, conversations, tenantID, conversationID, nil, time.Now().UTC().Truncate(time.Second))
+
+	approved := createTestPricing(t, storage, tenantID, conversationID, 800, 950)
+	rejected := createTestPricing(t, storage, tenantID, conversationID, 1234, 1490)
+	createTestPricing(t, storage, tenantID, conversationID, 600, 700)
+
+	if latest, err := storage.GetLatestApprovedPricing(tenantID, conversationID); err != nil || latest != nil {
+		t.Fatalf("GetLatestApprovedPricing before approval = %+v, %v; want nil", latest, err)
+	}
+
+	// Other tenants can't review the suggestion
+	if err := storage.ApprovePricingSuggestion("other-tenant", approved.ID, "admin-1"); err == nil {
+		t.Error("approval from another tenant succeeded")
+	}
+
+	if err := storage.ApprovePricingSuggestion(tenantID, approved.ID, "admin-1"); err != nil {
+		t.Fatalf("ApprovePricingSuggestion: %v", err)
+	}
+	if err := storage.RejectPricingSuggestion(tenantID, rejected.ID, "admin-2"); err != nil {
+		t.Fatalf("RejectPricingSuggestion: %v", err)
+	}
+
+	// Reviewed suggestions stay as they were reviewed
+	if err := storage.RejectPricingSuggestion(tenantID, approved.ID, "admin-2"); err == nil {
+		t.Error("rejecting an approved suggestion succeeded")
+	}
+	if err := storage.ApprovePricingSuggestion(tenantID, rejected.ID, "admin-1"); err == nil {
+		t.Error("approving a rejected suggestion succeeded")
+	}
+
+	got, err := storage.GetPricingSuggestion(tenantID, approved.ID)
+	if err != nil {
+		t.Fatalf("GetPricingSuggestion: %v", err)
+	}
+	if got.Status != models.PricingStatusApproved || got.ApprovedBy == nil || *got.ApprovedBy != "admin-1" || got.ApprovedAt == nil || got.RejectedAt != nil {
+		t.Errorf("approved suggestion = %+v, want approved by admin-1 only", got)
+	}
+
+	latest, err := storage.GetLatestApprovedPricing(tenantID, conversationID)
+	if err != nil || latest == nil || latest.ID != approved.ID {
+		t.Errorf("GetLatestApprovedPricing = %+v, %v; want the approved suggestion", latest, err)
+	}
+
+	rejectedList, err := storage.ListRejectedPricingSuggestions(tenantID)
+	if err != nil || len(rejectedList) != 1 || rejectedList[0].ID != rejected.ID || *rejectedList[0].RejectedBy != "admin-2" {
+		t.Errorf("ListRejectedPricingSuggestions = %+v, %v; want the rejected suggestion", rejectedList, err)
+	}
+
+	stats, err := storage.GetPricingSuggestionStats(tenantID)
+	if err != nil {
+		t.Fatalf("GetPricingSuggestionStats: %v", err)
+	}
+	if stats.Total != 3 || stats.Pending != 1 || stats.Approved != 1 || stats.Rejected != 1 {
+		t.Errorf("stats = %+v, want one suggestion in each status", stats)
+	}
+	if stats.AvgApprovalMins < 110 || stats.AvgApprovalMins > 130 {
+		t.Errorf("average approval time = %.1f minutes, want about 120", stats.AvgApprovalMins)
+	}
+}
@@ -3,11 +3,19 @@ package postgres
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"ai-conversation-platform/internal/models"
 )
 
+// productColumns are the products columns read by scanProduct, in order
+const productColumns = "id, tenant_id, name, description, category, category_id, price, price_currency, features, limitations, target_audience, common_questions, created_at, updated_at"
+
+// ErrProductNotFound is returned when a product doesn't exist in the tenant
+var ErrProductNotFound = errors.New("product not found")
+
 // ProductStorage handles product-related database operations
 type ProductStorage struct {
 	client *Client
@@ -25,11 +33,11 @@ func (s *ProductStorage) CreateProduct(tenantID string, product *models.Product)
 	commonQuestionsJSON, _ := json.Marshal(product.CommonQuestions)
 
 	query := `
-		INSERT INTO products (id, tenant_id, name, description, category, price, price_currency, features, limitations, target_audience, common_questions, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO products (id, tenant_id, name, description, category, category_id, price, price_currency, features, limitations, target_audience, common_questions, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 	_, err := s.client.DB.Exec(query,
-		product.ID, tenantID, product.Name, product.Description, product.Category,
+		product.ID, tenantID, product.Name, product.Description, product.Category, product.CategoryID,
 		product.Price, product.PriceCurrency, string(featuresJSON), string(limitationsJSON),
 		product.TargetAudience, string(commonQuestionsJSON), product.CreatedAt, product.UpdatedAt,
 	)
@@ -42,43 +50,28 @@ func (s *ProductStorage) CreateProduct(tenantID string, product *models.Product)
 // GetProduct retrieves a product by ID (tenant-scoped)
 func (s *ProductStorage) GetProduct(tenantID, productID string) (*models.Product, error) {
 	query := `
-		SELECT id, tenant_id, name, description, category, price, price_currency, features, limitations, target_audience, common_questions, created_at, updated_at
+		SELECT ` + productColumns + `
 		FROM products
 		WHERE id = $1 AND tenant_id = $2
 	`
-	product := &models.Product{}
-	var featuresJSON, limitationsJSON, commonQuestionsJSON string
-
-	err := s.client.DB.QueryRow(query, productID, tenantID).Scan(
-		&product.ID, &product.TenantID, &product.Name, &product.Description, &product.Category,
-		&product.Price, &product.PriceCurrency, &featuresJSON, &limitationsJSON,
-		&product.TargetAudience, &commonQuestionsJSON, &product.CreatedAt, &product.UpdatedAt,
-	)
+	product, err := scanProduct(s.client.DB.QueryRow(query, productID, tenantID))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("product not found")
+		return nil, ErrProductNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// Unmarshal JSON arrays
-	if err := json.Unmarshal([]byte(featuresJSON), &product.Features); err != nil {
-		product.Features = []string{}
-	}
-	if err := json.Unmarshal([]byte(limitationsJSON), &product.Limitations); err != nil {
-		product.Limitations = []string{}
-	}
-	if err := json.Unmarshal([]byte(commonQuestionsJSON), &product.CommonQuestions); err != nil {
-		product.CommonQuestions = []string{}
+	if err := s.attachCategoryPaths(tenantID, []*models.Product{product}); err != nil {
+		return nil, err
 	}
-
 	return product, nil
 }
 
 // ListProducts lists all products for a tenant
 func (s *ProductStorage) ListProducts(tenantID string) ([]*models.Product, error) {
 	query := `
-		SELECT id, tenant_id, name, description, category, price, price_currency, features, limitations, target_audience, common_questions, created_at, updated_at
+		SELECT ` + productColumns + `
 		FROM products
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -89,38 +82,33 @@ func (s *ProductStorage) ListProducts(tenantID string) ([]*models.Product, error
 	}
 	defer rows.Close()
 
-	var products []*models.Product
-	for rows.Next() {
-		product := &models.Product{}
-		var featuresJSON, limitationsJSON, commonQuestionsJSON string
-
-		err := rows.Scan(
-			&product.ID, &product.TenantID, &product.Name, &product.Description, &product.Category,
-			&product.Price, &product.PriceCurrency, &featuresJSON, &limitationsJSON,
-			&product.TargetAudience, &commonQuestionsJSON, &product.CreatedAt, &product.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan product: %w", err)
-		}
-
-		// Unmarshal JSON arrays
-		if err := json.Unmarshal([]byte(featuresJSON), &product.Features); err != nil {
-			product.Features = []string{}
-		}
-		if err := json.Unmarshal([]byte(limitationsJSON), &product.Limitations); err != nil {
-			product.Limitations = []string{}
-		}
-		if err := json.Unmarshal([]byte(commonQuestionsJSON), &product.CommonQuestions); err != nil {
-			product.CommonQuestions = []string{}
-		}
+	return s.scanProducts(tenantID, rows)
+}
 
-		products = append(products, product)
+// ListProductsInCategories lists a tenant's products assigned to any of the categories
+func (s *ProductStorage) ListProductsInCategories(tenantID string, categoryIDs []string) ([]*models.Product, error) {
+	if len(categoryIDs) == 0 {
+		return []*models.Product{}, nil
 	}
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating products: %w", err)
+	args := []interface{}{tenantID}
+	placeholders := make([]string, len(categoryIDs))
+	for i, id := range categoryIDs {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
 	}
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE tenant_id = $1 AND category_id IN (` + strings.Join(placeholders, ", ") + `)
+		ORDER BY created_at DESC
+	`
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer rows.Close()
 
-	return products, nil
+	return s.scanProducts(tenantID, rows)
 }
 
 // UpdateProduct updates a product (tenant-scoped)
@@ -132,13 +120,14 @@ func (s *ProductStorage) UpdateProduct(tenantID string, product *models.Product)
 	query := `
 		UPDATE products
 		SET name = $1, description = $2, category = $3, price = $4, price_currency = $5,
-		    features = $6, limitations = $7, target_audience = $8, common_questions = $9, updated_at = $10
-		WHERE id = $11 AND tenant_id = $12
+		    features = $6, limitations = $7, target_audience = $8, common_questions = $9, updated_at = $10,
+		    category_id = $11
+		WHERE id = $12 AND tenant_id = $13
 	`
 	result, err := s.client.DB.Exec(query,
 		product.Name, product.Description, product.Category, product.Price, product.PriceCurrency,
 		string(featuresJSON), string(limitationsJSON), product.TargetAudience, string(commonQuestionsJSON),
-		product.UpdatedAt, product.ID, tenantID,
+		product.UpdatedAt, product.CategoryID, product.ID, tenantID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
@@ -153,8 +142,15 @@ func (s *ProductStorage) UpdateProduct(tenantID string, product *models.Product)
 	return nil
 }
 
-// DeleteProduct deletes a product (tenant-scoped)
+// DeleteProduct deletes a product and its variants (tenant-scoped)
 func (s *ProductStorage) DeleteProduct(tenantID, productID string) error {
+	if _, err := s.client.DB.Exec("DELETE FROM product_variants WHERE product_id = $1 AND tenant_id = $2", productID, tenantID); err != nil {
+		return fmt.Errorf("failed to delete product variants: %w", err)
+	}
+	if _, err := s.client.DB.Exec("DELETE FROM product_sla_config WHERE product_id = $1 AND tenant_id = $2", productID, tenantID); err != nil {
+		return fmt.Errorf("failed to delete product sla config: %w", err)
+	}
+
 	query := `
 		DELETE FROM products
 		WHERE id = $1 AND tenant_id = $2
@@ -168,9 +164,165 @@ func (s *ProductStorage) DeleteProduct(tenantID, productID string) error {
 		return fmt.Errorf("failed to check rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("product not found")
+		return ErrProductNotFound
 	}
 	return nil
 }
 
+// ProductSearchFilter filters products in SearchProducts. Zero values are ignored.
+type ProductSearchFilter struct {
+	Query    string   // Case-insensitive match on name or description
+	Category string
+	MinPrice *float64
+	MaxPrice *float64
+}
 
+// SearchProducts lists products for a tenant matching the filter
+func (s *ProductStorage) SearchProducts(tenantID string, filter ProductSearchFilter) ([]*models.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE tenant_id = $1
+	`
+	args := []interface{}{tenantID}
+
+	if filter.Query != "" {
+		args = append(args, "%"+strings.ToLower(filter.Query)+"%")
+		query += fmt.Sprintf(" AND (LOWER(name) LIKE $%d OR LOWER(description) LIKE $%d)", len(args), len(args))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		query += fmt.Sprintf(" AND category = $%d", len(args))
+	}
+	if filter.MinPrice != nil {
+		args = append(args, *filter.MinPrice)
+		query += fmt.Sprintf(" AND price >= $%d", len(args))
+	}
+	if filter.MaxPrice != nil {
+		args = append(args, *filter.MaxPrice)
+		query += fmt.Sprintf(" AND price <= $%d", len(args))
+	}
+	query += " ORDER BY price ASC, created_at DESC"
+
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanProducts(tenantID, rows)
+}
+
+// scanProduct scans a row of productColumns
+func scanProduct(row rowScanner) (*models.Product, error) {
+	product := &models.Product{}
+	var categoryID sql.NullString
+	var featuresJSON, limitationsJSON, commonQuestionsJSON string
+
+	err := row.Scan(
+		&product.ID, &product.TenantID, &product.Name, &product.Description, &product.Category, &categoryID,
+		&product.Price, &product.PriceCurrency, &featuresJSON, &limitationsJSON,
+		&product.TargetAudience, &commonQuestionsJSON, &product.CreatedAt, &product.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID.Valid {
+		product.CategoryID = &categoryID.String
+	}
+
+	// Unmarshal JSON arrays
+	if err := json.Unmarshal([]byte(featuresJSON), &product.Features); err != nil {
+		product.Features = []string{}
+	}
+	if err := json.Unmarshal([]byte(limitationsJSON), &product.Limitations); err != nil {
+		product.Limitations = []string{}
+	}
+	if err := json.Unmarshal([]byte(commonQuestionsJSON), &product.CommonQuestions); err != nil {
+		product.CommonQuestions = []string{}
+	}
+	return product, nil
+}
+
+// scanProducts scans rows of productColumns and fills in their category paths
+func (s *ProductStorage) scanProducts(tenantID string, rows *sql.Rows) ([]*models.Product, error) {
+	var products []*models.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	if err := s.attachCategoryPaths(tenantID, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// attachCategoryPaths sets CategoryPath on products that have a category, loading the tenant's
+// category tree only when one does
+func (s *ProductStorage) attachCategoryPaths(tenantID string, products []*models.Product) error {
+	var tree *categoryTree
+	for _, product := range products {
+		if product.CategoryID == nil {
+			continue
+		}
+		if tree == nil {
+			var err error
+			if tree, err = loadCategoryTree(s.client.DB, tenantID); err != nil {
+				return err
+			}
+		}
+		product.CategoryPath = tree.path(*product.CategoryID)
+	}
+	return nil
+}
+
+// ListProductIDs lists the IDs of all products for a tenant
+func (s *ProductStorage) ListProductIDs(tenantID string) ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT id FROM products WHERE tenant_id = $1", tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan product id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product ids: %w", err)
+	}
+	return ids, nil
+}
+
+// ListProductTenants lists the tenants that have at least one product
+func (s *ProductStorage) ListProductTenants() ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT tenant_id FROM products ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant id: %w", err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product tenants: %w", err)
+	}
+	return tenantIDs, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductConversion counts how often a product recommended in reply suggestions was accepted
+type ProductConversion struct {
+	ProductID   string
+	ProductName string
+	Recommended int
+	Accepted    int
+}
+
+// Rate returns the share of recommendations that were accepted, 0 when there were none
+func (c ProductConversion) Rate() float64 {
+	if c.Recommended == 0 {
+		return 0
+	}
+	return float64(c.Accepted) / float64(c.Recommended)
+}
+
+// RecordProductRecommendations records the products a reply suggestion recommended, as not yet
+// accepted. The conversation must belong to the tenant.
+func (s *ProductStorage) RecordProductRecommendations(tenantID, conversationID, suggestionID string, productIDs []string, suggestedAt time.Time) error {
+	for _, productID := range productIDs {
+		_, err := s.client.DB.Exec(`
+			INSERT INTO product_recommendations (id, tenant_id, conversation_id, suggestion_id, product_id, suggested_at, accepted)
+			SELECT $1, c.tenant_id, c.id, $2, $3, $4, FALSE
+			FROM conversations c
+			WHERE c.id = $5 AND c.tenant_id = $6
+		`, uuid.New().String(), suggestionID, productID, suggestedAt, conversationID, tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to record product recommendation: %w", err)
+		}
+	}
+	return nil
+}
+
+// AcceptProductRecommendations marks the products a suggestion recommended as accepted and returns
+// how many were marked. Recommendations already accepted keep their original time.
+func (s *ProductStorage) AcceptProductRecommendations(tenantID, conversationID, suggestionID string, acceptedAt time.Time) (int64, error) {
+	result, err := s.client.DB.Exec(`
+		UPDATE product_recommendations
+		SET accepted = TRUE, accepted_at = $1
+		WHERE suggestion_id = $2 AND conversation_id = $3 AND tenant_id = $4 AND accepted = FALSE
+	`, acceptedAt, suggestionID, conversationID, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to accept product recommendations: %w", err)
+	}
+	accepted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return accepted, nil
+}
+
+// GetConversionRate returns the share of a product's recommendations agents accepted, 0 when it
+// has never been recommended or the rate can't be loaded
+func (s *ProductStorage) GetConversionRate(tenantID, productID string) float64 {
+	var conversion ProductConversion
+	err := s.client.DB.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN accepted = TRUE THEN 1 ELSE 0 END), 0)
+		FROM product_recommendations
+		WHERE tenant_id = $1 AND product_id = $2
+	`, tenantID, productID).Scan(&conversion.Recommended, &conversion.Accepted)
+	if err != nil {
+		log.Printf("[PRODUCT] failed to get conversion rate tenant=%s product=%s: %v", tenantID, productID, err)
+		return 0
+	}
+	return conversion.Rate()
+}
+
+// ListProductConversions counts recommendations and acceptances for each of the tenant's products
+// that has been recommended
+func (s *ProductStorage) ListProductConversions(tenantID string) ([]ProductConversion, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT p.id, p.name, COUNT(*), SUM(CASE WHEN pr.accepted = TRUE THEN 1 ELSE 0 END)
+		FROM product_recommendations pr
+		JOIN products p ON p.id = pr.product_id AND p.tenant_id = pr.tenant_id
+		WHERE pr.tenant_id = $1
+		GROUP BY p.id, p.name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product conversions: %w", err)
+	}
+	defer rows.Close()
+
+	var conversions []ProductConversion
+	for rows.Next() {
+		var c ProductConversion
+		if err := rows.Scan(&c.ProductID, &c.ProductName, &c.Recommended, &c.Accepted); err != nil {
+			return nil, fmt.Errorf("failed to scan product conversion: %w", err)
+		}
+		conversions = append(conversions, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product conversions: %w", err)
+	}
+	return conversions, nil
+}
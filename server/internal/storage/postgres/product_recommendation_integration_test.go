@@ -0,0 +1,86 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestProductConversionRate(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	products := NewProductStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM product_recommendations WHERE tenant_id = $1", tenantID)
+		testClient.DB.Exec("DELETE FROM products WHERE tenant_id = $1", tenantID)
+	})
+
+	now := time.Now().UTC().Truncate(time.Second)
+	convID := "conv-" + tenantID
+	createConversationAt(t, conversations, tenantID, convID, nil, now)
+	crm := &models.Product{ID: uuid.New().String(), TenantID: tenantID, Name: "Sales CRM", CreatedAt: now, UpdatedAt: now}
+	helpdesk := &models.Product{ID: uuid.New().String(), TenantID: tenantID, Name: "Helpdesk", CreatedAt: now, UpdatedAt: now}
+	for _, product := range []*models.Product{crm, helpdesk} {
+		if err := products.CreateProduct(tenantID, product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+
+	// The CRM is recommended by four suggestions and the helpdesk by one; agents accept two of
+	// the CRM's suggestions, one of them twice
+	for i, suggestionID := range []string{"s1", "s2", "s3", "s4"} {
+		productIDs := []string{crm.ID}
+		if i == 0 {
+			productIDs = append(productIDs, helpdesk.ID)
+		}
+		if err := products.RecordProductRecommendations(tenantID, convID, suggestionID, productIDs, now); err != nil {
+			t.Fatalf("RecordProductRecommendations: %v", err)
+		}
+	}
+	for _, suggestionID := range []string{"s2", "s3", "s3"} {
+		if _, err := products.AcceptProductRecommendations(tenantID, convID, suggestionID, now.Add(time.Minute)); err != nil {
+			t.Fatalf("AcceptProductRecommendations: %v", err)
+		}
+	}
+	if accepted, err := products.AcceptProductRecommendations("other-"+tenantID, convID, "s4", now); err != nil || accepted != 0 {
+		t.Errorf("accepting from another tenant = %d, %v; want nothing accepted", accepted, err)
+	}
+
+	if rate := products.GetConversionRate(tenantID, crm.ID); rate != 0.5 {
+		t.Errorf("CRM conversion rate = %v, want 0.5", rate)
+	}
+	if rate := products.GetConversionRate(tenantID, helpdesk.ID); rate != 0 {
+		t.Errorf("helpdesk conversion rate = %v, want 0", rate)
+	}
+	if rate := products.GetConversionRate(tenantID, "never-recommended"); rate != 0 {
+		t.Errorf("unrecommended product conversion rate = %v, want 0", rate)
+	}
+
+	conversions, err := products.ListProductConversions(tenantID)
+	if err != nil {
+		t.Fatalf("ListProductConversions: %v", err)
+	}
+	byID := make(map[string]ProductConversion)
+	for _, c := range conversions {
+		byID[c.ProductID] = c
+	}
+	if c := byID[crm.ID]; c.ProductName != "Sales CRM" || c.Recommended != 4 || c.Accepted != 2 {
+		t.Errorf("CRM conversion = %+v, want 2 of 4", c)
+	}
+	if c := byID[helpdesk.ID]; c.Recommended != 1 || c.Accepted != 0 {
+		t.Errorf("helpdesk conversion = %+v, want 0 of 1", c)
+	}
+
+	// Recommendations are only recorded for the tenant's own conversations
+	if err := products.RecordProductRecommendations("other-"+tenantID, convID, "s5", []string{crm.ID}, now); err != nil {
+		t.Fatalf("RecordProductRecommendations for another tenant: %v", err)
+	}
+	if rate := products.GetConversionRate(tenantID, crm.ID); rate != 0.5 {
+		t.Errorf("CRM conversion rate after another tenant's recommendation = %v, want 0.5", rate)
+	}
+}
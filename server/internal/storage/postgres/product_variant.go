@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ProductVariantStorage handles product pricing tiers
+type ProductVariantStorage struct {
+	client *Client
+}
+
+// NewProductVariantStorage creates a new product variant storage instance
+func NewProductVariantStorage(client *Client) *ProductVariantStorage {
+	return &ProductVariantStorage{client: client}
+}
+
+const productVariantColumns = `id, product_id, tenant_id, name, price, price_currency, description, is_active, created_at, updated_at`
+
+// CreateVariant adds a variant to a product. The product must belong to the variant's tenant;
+// ID and timestamps are set when empty.
+func (s *ProductVariantStorage) CreateVariant(variant *models.ProductVariant) error {
+	if variant.ID == "" {
+		variant.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if variant.CreatedAt.IsZero() {
+		variant.CreatedAt = now
+	}
+	if variant.UpdatedAt.IsZero() {
+		variant.UpdatedAt = now
+	}
+
+	result, err := s.client.DB.Exec(`
+		INSERT INTO product_variants (id, product_id, tenant_id, name, price, price_currency, description, is_active, created_at, updated_at)
+		SELECT $1, p.id, p.tenant_id, $2, $3, $4, $5, $6, $7, $8
+		FROM products p
+		WHERE p.id = $9 AND p.tenant_id = $10
+	`, variant.ID, variant.Name, variant.Price, variant.PriceCurrency, variant.Description, variant.IsActive,
+		variant.CreatedAt, variant.UpdatedAt, variant.ProductID, variant.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to create product variant: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// GetVariant returns a variant of a tenant's product
+func (s *ProductVariantStorage) GetVariant(tenantID, productID, variantID string) (*models.ProductVariant, error) {
+	query := `SELECT ` + productVariantColumns + ` FROM product_variants
+		WHERE id = $1 AND product_id = $2 AND tenant_id = $3`
+	variant, err := scanProductVariant(s.client.DB.QueryRow(query, variantID, productID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product variant not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product variant: %w", err)
+	}
+	return variant, nil
+}
+
+// ListVariants lists a product's variants from cheapest to most expensive. With activeOnly,
+// inactive variants are left out.
+func (s *ProductVariantStorage) ListVariants(tenantID, productID string, activeOnly bool) ([]models.ProductVariant, error) {
+	query := `SELECT ` + productVariantColumns + ` FROM product_variants
+		WHERE product_id = $1 AND tenant_id = $2`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY price ASC, name ASC`
+
+	rows, err := s.client.DB.Query(query, productID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := []models.ProductVariant{}
+	for rows.Next() {
+		variant, err := scanProductVariant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product variant: %w", err)
+		}
+		variants = append(variants, *variant)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product variants: %w", err)
+	}
+	return variants, nil
+}
+
+// UpdateVariant saves a variant's name, price, description and active flag
+func (s *ProductVariantStorage) UpdateVariant(variant *models.ProductVariant) error {
+	variant.UpdatedAt = time.Now()
+	result, err := s.client.DB.Exec(`
+		UPDATE product_variants
+		SET name = $1, price = $2, price_currency = $3, description = $4, is_active = $5, updated_at = $6
+		WHERE id = $7 AND product_id = $8 AND tenant_id = $9
+	`, variant.Name, variant.Price, variant.PriceCurrency, variant.Description, variant.IsActive, variant.UpdatedAt,
+		variant.ID, variant.ProductID, variant.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update product variant: %w", err)
+	}
+	return requireVariantRow(result)
+}
+
+// DeleteVariant deletes a variant of a tenant's product
+func (s *ProductVariantStorage) DeleteVariant(tenantID, productID, variantID string) error {
+	result, err := s.client.DB.Exec("DELETE FROM product_variants WHERE id = $1 AND product_id = $2 AND tenant_id = $3",
+		variantID, productID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete product variant: %w", err)
+	}
+	return requireVariantRow(result)
+}
+
+func requireVariantRow(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("product variant not found")
+	}
+	return nil
+}
+
+func scanProductVariant(row rowScanner) (*models.ProductVariant, error) {
+	v := &models.ProductVariant{}
+	err := row.Scan(&v.ID, &v.ProductID, &v.TenantID, &v.Name, &v.Price, &v.PriceCurrency, &v.Description,
+		&v.IsActive, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
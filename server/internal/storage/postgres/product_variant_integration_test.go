@@ -0,0 +1,118 @@
+//go:build integration
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func newVariantTestProduct(t *testing.T) (*ProductStorage, *models.Product) {
+	t.Helper()
+	tenantID := "variant-" + uuid.New().String()
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM product_variants WHERE tenant_id = $1", tenantID)
+		testClient.DB.Exec("DELETE FROM products WHERE tenant_id = $1", tenantID)
+	})
+
+	storage := NewProductStorage(testClient)
+	now := time.Now().UTC()
+	product := &models.Product{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Name:          "Sales CRM",
+		Description:   "CRM for small teams",
+		Price:         999,
+		PriceCurrency: "INR",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := storage.CreateProduct(tenantID, product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	return storage, product
+}
+
+func TestProductVariantCRUD(t *testing.T) {
+	_, product := newVariantTestProduct(t)
+	storage := NewProductVariantStorage(testClient)
+
+	annual := &models.ProductVariant{ProductID: product.ID, TenantID: product.TenantID, Name: "Annual", Price: 9990, PriceCurrency: "INR", IsActive: true}
+	monthly := &models.ProductVariant{ProductID: product.ID, TenantID: product.TenantID, Name: "Monthly", Price: 999, PriceCurrency: "INR", IsActive: true}
+	legacy := &models.ProductVariant{ProductID: product.ID, TenantID: product.TenantID, Name: "Legacy", Price: 499, PriceCurrency: "INR"}
+	for _, variant := range []*models.ProductVariant{annual, monthly, legacy} {
+		if err := storage.CreateVariant(variant); err != nil {
+			t.Fatalf("CreateVariant(%s): %v", variant.Name, err)
+		}
+	}
+
+	all, err := storage.ListVariants(product.TenantID, product.ID, false)
+	if err != nil {
+		t.Fatalf("ListVariants: %v", err)
+	}
+	if len(all) != 3 || all[0].Name != "Legacy" || all[2].Name != "Annual" {
+		t.Errorf("variants = %+v, want all three cheapest first", all)
+	}
+	active, err := storage.ListVariants(product.TenantID, product.ID, true)
+	if err != nil {
+		t.Fatalf("ListVariants active: %v", err)
+	}
+	if len(active) != 2 || active[0].Name != "Monthly" {
+		t.Errorf("active variants = %+v, want Monthly and Annual", active)
+	}
+
+	annual.Price = 8990
+	annual.Description = "2 months free"
+	if err := storage.UpdateVariant(annual); err != nil {
+		t.Fatalf("UpdateVariant: %v", err)
+	}
+	got, err := storage.GetVariant(product.TenantID, product.ID, annual.ID)
+	if err != nil {
+		t.Fatalf("GetVariant: %v", err)
+	}
+	if got.Price != 8990 || got.Description != "2 months free" || !got.IsActive {
+		t.Errorf("variant = %+v, want the update saved", got)
+	}
+
+	if err := storage.DeleteVariant(product.TenantID, product.ID, legacy.ID); err != nil {
+		t.Fatalf("DeleteVariant: %v", err)
+	}
+	if _, err := storage.GetVariant(product.TenantID, product.ID, legacy.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("GetVariant after delete err = %v, want not found", err)
+	}
+}
+
+func TestProductVariantsAreTenantScoped(t *testing.T) {
+	products, product := newVariantTestProduct(t)
+	storage := NewProductVariantStorage(testClient)
+
+	foreign := &models.ProductVariant{ProductID: product.ID, TenantID: "other-tenant", Name: "Monthly", Price: 999, PriceCurrency: "INR"}
+	if err := storage.CreateVariant(foreign); err == nil || !strings.Contains(err.Error(), "product not found") {
+		t.Fatalf("CreateVariant for another tenant's product err = %v, want product not found", err)
+	}
+
+	variant := &models.ProductVariant{ProductID: product.ID, TenantID: product.TenantID, Name: "Monthly", Price: 999, PriceCurrency: "INR", IsActive: true}
+	if err := storage.CreateVariant(variant); err != nil {
+		t.Fatalf("CreateVariant: %v", err)
+	}
+	if err := storage.DeleteVariant("other-tenant", product.ID, variant.ID); err == nil {
+		t.Error("DeleteVariant from another tenant should fail")
+	}
+
+	// Deleting the product removes its variants
+	if err := products.DeleteProduct(product.TenantID, product.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	variants, err := storage.ListVariants(product.TenantID, product.ID, false)
+	if err != nil {
+		t.Fatalf("ListVariants: %v", err)
+	}
+	if len(variants) != 0 {
+		t.Errorf("variants = %+v, want none after the product was deleted", variants)
+	}
+}
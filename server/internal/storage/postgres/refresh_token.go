@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ai-conversation-platform/internal/auth"
+)
+
+// RefreshTokenStorage persists hashed refresh tokens; implements auth.RefreshTokenStore
+type RefreshTokenStorage struct {
+	client *Client
+}
+
+// NewRefreshTokenStorage creates a new refresh token storage instance
+func NewRefreshTokenStorage(client *Client) *RefreshTokenStorage {
+	return &RefreshTokenStorage{client: client}
+}
+
+// CreateRefreshToken stores a newly issued refresh token
+func (s *RefreshTokenStorage) CreateRefreshToken(record *auth.RefreshTokenRecord) error {
+	query := `
+		INSERT INTO refresh_tokens (jti, user_id, tenant_id, token_hash, expires_at, revoked, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := s.client.DB.Exec(query, record.JTI, record.UserID, record.TenantID, record.TokenHash,
+		record.ExpiresAt, record.Revoked, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token by jti, or nil if there is none
+func (s *RefreshTokenStorage) GetRefreshToken(jti string) (*auth.RefreshTokenRecord, error) {
+	query := `
+		SELECT jti, user_id, tenant_id, token_hash, expires_at, revoked
+		FROM refresh_tokens
+		WHERE jti = $1
+	`
+	record := &auth.RefreshTokenRecord{}
+	err := s.client.DB.QueryRow(query, jti).Scan(&record.JTI, &record.UserID, &record.TenantID,
+		&record.TokenHash, &record.ExpiresAt, &record.Revoked)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return record, nil
+}
+
+// RevokeRefreshToken revokes a refresh token, reporting whether it was still active
+func (s *RefreshTokenStorage) RevokeRefreshToken(jti string) (bool, error) {
+	result, err := s.client.DB.Exec(`UPDATE refresh_tokens SET revoked = TRUE WHERE jti = $1 AND revoked = FALSE`, jti)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user
+func (s *RefreshTokenStorage) RevokeUserRefreshTokens(tenantID, userID string) error {
+	return revokeUserRefreshTokens(s.client.DB, tenantID, userID)
+}
+
+func revokeUserRefreshTokens(db *sql.DB, tenantID, userID string) error {
+	_, err := db.Exec(`UPDATE refresh_tokens SET revoked = TRUE WHERE tenant_id = $1 AND user_id = $2 AND revoked = FALSE`, tenantID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/auth"
+	"ai-conversation-platform/internal/models"
+)
+
+func TestRefreshTokenStorage(t *testing.T) {
+	storage := NewRefreshTokenStorage(testClient)
+	users := NewUserStorage(testClient)
+	userID := newTestUser(t, users, "refresh-"+uuid.New().String()+"@example.com", models.RoleAgent).ID
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM refresh_tokens WHERE tenant_id = $1", testTenantID)
+	})
+
+	tokens, err := auth.IssueTokens(storage, userID, testTenantID, "agent")
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	rotated, err := auth.RefreshToken(storage, users, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	if _, err := auth.RefreshToken(storage, users, tokens.RefreshToken); err != auth.ErrRefreshTokenReused {
+		t.Fatalf("replayed RefreshToken = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := auth.RefreshToken(storage, users, rotated.RefreshToken); err != auth.ErrRefreshTokenReused {
+		t.Errorf("RefreshToken after replay = %v, want the user's tokens revoked", err)
+	}
+
+	if record, err := storage.GetRefreshToken("missing"); err != nil || record != nil {
+		t.Errorf("GetRefreshToken(missing) = %v, %v, want nil, nil", record, err)
+	}
+}
+
+func TestDeactivateUserRevokesRefreshTokens(t *testing.T) {
+	users := NewUserStorage(testClient)
+	tokens := NewRefreshTokenStorage(testClient)
+	now := time.Now()
+	user := newTestUser(t, users, "refresh-"+uuid.New().String()+"@example.com", models.RoleAgent)
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM refresh_tokens WHERE tenant_id = $1", testTenantID)
+	})
+
+	record := &auth.RefreshTokenRecord{JTI: uuid.New().String(), UserID: user.ID, TenantID: testTenantID,
+		TokenHash: "hash", ExpiresAt: now.Add(time.Hour)}
+	if err := tokens.CreateRefreshToken(record); err != nil {
+		t.Fatalf("CreateRefreshToken: %v", err)
+	}
+
+	if err := users.DeactivateUser(testTenantID, user.ID); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	got, err := tokens.GetRefreshToken(record.JTI)
+	if err != nil || got == nil || !got.Revoked {
+		t.Errorf("refresh token after deactivation = %+v, err %v, want revoked", got, err)
+	}
+}
+
+func TestUpdateUserRoleRevokesRefreshTokens(t *testing.T) {
+	users := NewUserStorage(testClient)
+	tokens := NewRefreshTokenStorage(testClient)
+	user := newTestUser(t, users, "refresh-"+uuid.New().String()+"@example.com", models.RoleAgent)
+	t.Cleanup(func() {
+		testClient.DB.Exec("DELETE FROM refresh_tokens WHERE tenant_id = $1", testTenantID)
+	})
+
+	record := &auth.RefreshTokenRecord{JTI: uuid.New().String(), UserID: user.ID, TenantID: testTenantID,
+		TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := tokens.CreateRefreshToken(record); err != nil {
+		t.Fatalf("CreateRefreshToken: %v", err)
+	}
+
+	if err := users.UpdateUserRole(testTenantID, user.ID, models.RoleCustomer); err != nil {
+		t.Fatalf("UpdateUserRole: %v", err)
+	}
+	got, err := tokens.GetRefreshToken(record.JTI)
+	if err != nil || got == nil || !got.Revoked {
+		t.Errorf("refresh token after role change = %+v, err %v, want revoked", got, err)
+	}
+}
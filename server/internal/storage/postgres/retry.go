@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+
+	"ai-conversation-platform/internal/metrics"
+)
+
+// ErrMaxRetriesExceeded is returned (wrapping the last error) when every retry attempt failed
+var ErrMaxRetriesExceeded = errors.New("max retries exceeded")
+
+// DefaultRetryableErrors are PostgreSQL error codes worth retrying:
+// 40001 serialization_failure, 40P01 deadlock_detected, 53300 too_many_connections
+var DefaultRetryableErrors = []string{"40001", "40P01", "53300"}
+
+const (
+	defaultRetryAttempts = 3
+	retryBaseDelay       = 50 * time.Millisecond
+	retryMultiplier      = 2
+	retryMaxDelay        = 2 * time.Second
+)
+
+// retrySleep is swapped out in tests
+var retrySleep = time.Sleep
+
+// WithRetry runs fn, retrying with exponential backoff and jitter while it fails
+// with one of retryableErrors. Non-retryable errors are returned immediately.
+func WithRetry(fn func() error, maxAttempts int, retryableErrors []string) error {
+	return withRetry("", "", fn, maxAttempts, retryableErrors)
+}
+
+// withRetry runs fn with the default retry policy, labelling logs and metrics
+func (c *Client) withRetry(operation, tenantID string, fn func() error) error {
+	return withRetry(operation, tenantID, fn, defaultRetryAttempts, DefaultRetryableErrors)
+}
+
+func withRetry(operation, tenantID string, fn func() error, maxAttempts int, retryableErrors []string) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+		if !isRetryable(lastErr, retryableErrors) {
+			return lastErr
+		}
+		if attempt == maxAttempts {
+			break
+		}
+
+		log.Printf("[DB] DEBUG retrying operation=%s attempt=%d error=%v", operation, attempt, lastErr)
+		metrics.RecordDBRetry(operation, tenantID)
+		retrySleep(retryDelay(attempt))
+	}
+
+	return fmt.Errorf("%w after %d attempts: %w", ErrMaxRetriesExceeded, maxAttempts, lastErr)
+}
+
+// retryDelay returns the backoff before the next attempt: base * 2^(attempt-1),
+// capped at retryMaxDelay, with jitter over the upper half of the window
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= retryMultiplier
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isRetryable matches the PostgreSQL error code against retryableErrors. SQLite
+// busy and locked errors are always retried; any other error is not.
+func isRetryable(err error, retryableErrors []string) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		for _, code := range retryableErrors {
+			if string(pqErr.Code) == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+
+	"ai-conversation-platform/internal/metrics"
+	"ai-conversation-platform/internal/models"
+)
+
+// flakyDriver is a database/sql driver whose Exec fails with a given error
+// for the first failures calls and succeeds afterwards
+type flakyDriver struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    int
+}
+
+func (d *flakyDriver) Open(name string) (driver.Conn, error) { return &flakyConn{driver: d}, nil }
+
+func (d *flakyDriver) exec() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	if d.calls <= d.failures {
+		return d.err
+	}
+	return nil
+}
+
+type flakyConn struct{ driver *flakyDriver }
+
+func (c *flakyConn) Prepare(query string) (driver.Stmt, error) { return &flakyStmt{conn: c}, nil }
+func (c *flakyConn) Close() error                              { return nil }
+func (c *flakyConn) Begin() (driver.Tx, error)                 { return flakyTx{}, nil }
+
+type flakyStmt struct{ conn *flakyConn }
+
+func (s *flakyStmt) Close() error  { return nil }
+func (s *flakyStmt) NumInput() int { return -1 }
+func (s *flakyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.conn.driver.exec(); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s *flakyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("query not supported")
+}
+
+type flakyTx struct{}
+
+func (flakyTx) Commit() error   { return nil }
+func (flakyTx) Rollback() error { return nil }
+
+var driverSeq int
+
+// newFlakyClient returns a client backed by a fresh flaky driver
+func newFlakyClient(t *testing.T, failures int, err error) (*Client, *flakyDriver) {
+	t.Helper()
+	d := &flakyDriver{failures: failures, err: err}
+	driverSeq++
+	name := fmt.Sprintf("flaky-%d", driverSeq)
+	sql.Register(name, d)
+	db, openErr := sql.Open(name, "")
+	if openErr != nil {
+		t.Fatalf("open: %v", openErr)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Client{DB: db, DBType: "postgres"}, d
+}
+
+func noSleep(t *testing.T) {
+	t.Helper()
+	prev := retrySleep
+	retrySleep = func(time.Duration) {}
+	t.Cleanup(func() { retrySleep = prev })
+}
+
+func testConversation() *models.Conversation {
+	now := time.Now()
+	return &models.Conversation{ID: "conv-1", Status: "active", CreatedAt: now, UpdatedAt: now}
+}
+
+func TestCreateConversationRetriesSerializationFailure(t *testing.T) {
+	noSleep(t)
+	registry := metrics.NewRegistry()
+	metrics.SetRegistry(registry)
+	t.Cleanup(func() { metrics.SetRegistry(nil) })
+	client, d := newFlakyClient(t, 2, &pq.Error{Code: "40001", Message: "could not serialize access"})
+	storage := NewConversationStorage(client)
+
+	if err := storage.CreateConversation("tenant-retry", testConversation()); err != nil {
+		t.Fatalf("CreateConversation() error = %v, want nil", err)
+	}
+	if d.calls != 3 {
+		t.Errorf("exec calls = %d, want 3", d.calls)
+	}
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if sample := `postgres_retries_total{operation="CreateConversation",tenant_id="tenant-retry"} 2`; !strings.Contains(rec.Body.String(), sample+"\n") {
+		t.Errorf("missing sample %q", sample)
+	}
+}
+
+func TestCreateMessageGivesUpAfterMaxAttempts(t *testing.T) {
+	noSleep(t)
+	deadlock := &pq.Error{Code: "40P01", Message: "deadlock detected"}
+	client, d := newFlakyClient(t, 10, deadlock)
+	storage := NewConversationStorage(client)
+
+	err := storage.CreateMessage("tenant-retry", &models.Message{ID: "msg-1", ConversationID: "conv-1"})
+	if !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Fatalf("CreateMessage() error = %v, want ErrMaxRetriesExceeded", err)
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "40P01" {
+		t.Errorf("CreateMessage() error does not wrap the last pq error: %v", err)
+	}
+	if d.calls != defaultRetryAttempts {
+		t.Errorf("exec calls = %d, want %d", d.calls, defaultRetryAttempts)
+	}
+}
+
+func TestNonRetryableErrorIsNotRetried(t *testing.T) {
+	noSleep(t)
+	client, d := newFlakyClient(t, 1, &pq.Error{Code: "23505", Message: "duplicate key"})
+	storage := NewRuleStorage(client)
+
+	err := storage.BulkCreateRules("tenant-retry", []*models.Rule{{ID: "rule-1", Name: "r"}})
+	if err == nil || errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Fatalf("BulkCreateRules() error = %v, want the unique violation", err)
+	}
+	if d.calls != 1 {
+		t.Errorf("exec calls = %d, want 1", d.calls)
+	}
+}
+
+func TestWithRetryIgnoresCodeInErrorText(t *testing.T) {
+	noSleep(t)
+	calls := 0
+	err := WithRetry(func() error {
+		calls++
+		return errors.New("pq: sorry, too many clients already (SQLSTATE 53300)")
+	}, 5, DefaultRetryableErrors)
+	if err == nil || errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Fatalf("WithRetry() error = %v, want the untyped error returned as is", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRetryRetriesSQLiteBusy(t *testing.T) {
+	noSleep(t)
+	for _, code := range []sqlite3.ErrNo{sqlite3.ErrBusy, sqlite3.ErrLocked} {
+		calls := 0
+		err := WithRetry(func() error {
+			calls++
+			if calls < 2 {
+				return fmt.Errorf("failed to create rule: %w", sqlite3.Error{Code: code})
+			}
+			return nil
+		}, 5, DefaultRetryableErrors)
+		if err != nil || calls != 2 {
+			t.Errorf("code %v: error = %v calls = %d, want success on the second call", code, err, calls)
+		}
+	}
+
+	calls := 0
+	err := WithRetry(func() error {
+		calls++
+		return sqlite3.Error{Code: sqlite3.ErrConstraint}
+	}, 5, DefaultRetryableErrors)
+	if err == nil || calls != 1 {
+		t.Errorf("constraint error = %v calls = %d, want it returned after one call", err, calls)
+	}
+}
+
+func TestRetryDelayIsBounded(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := retryDelay(attempt)
+		if delay <= 0 || delay > retryMaxDelay {
+			t.Errorf("retryDelay(%d) = %v, want within (0, %v]", attempt, delay, retryMaxDelay)
+		}
+	}
+	if d := retryDelay(1); d > retryBaseDelay {
+		t.Errorf("retryDelay(1) = %v, want <= %v", d, retryBaseDelay)
+	}
+}
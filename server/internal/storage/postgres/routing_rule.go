@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoutingCondition compares one conversation field with a value, e.g. intent eq "buying"
+type RoutingCondition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"` // A string, a number, or a list of strings for "in"
+}
+
+// RoutingAction is what a matching routing rule does with the conversation
+type RoutingAction struct {
+	Type    string `json:"type"`
+	AgentID string `json:"agent_id"`
+}
+
+// RoutingRule assigns conversations matching all of its conditions. Rules are evaluated from the
+// highest priority down; a rule without conditions matches every conversation.
+type RoutingRule struct {
+	ID         string             `json:"id"`
+	TenantID   string             `json:"tenant_id"`
+	Priority   int                `json:"priority"`
+	Conditions []RoutingCondition `json:"conditions"`
+	Action     RoutingAction      `json:"action"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// RoutingRuleStorage handles tenant conversation routing rules
+type RoutingRuleStorage struct {
+	client *Client
+}
+
+// NewRoutingRuleStorage creates a new routing rule storage instance
+func NewRoutingRuleStorage(client *Client) *RoutingRuleStorage {
+	return &RoutingRuleStorage{client: client}
+}
+
+const routingRuleColumns = "id, tenant_id, priority, conditions, action, created_at, updated_at"
+
+// CreateRoutingRule stores a new routing rule
+func (s *RoutingRuleStorage) CreateRoutingRule(rule *RoutingRule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	conditionsJSON, actionJSON, err := encodeRoutingRule(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO routing_rules (` + routingRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = s.client.DB.Exec(query, rule.ID, rule.TenantID, rule.Priority, conditionsJSON, actionJSON, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+	return nil
+}
+
+// GetRoutingRule retrieves a tenant's routing rule by ID
+func (s *RoutingRuleStorage) GetRoutingRule(tenantID, id string) (*RoutingRule, error) {
+	row := s.client.DB.QueryRow("SELECT "+routingRuleColumns+" FROM routing_rules WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	rule, err := scanRoutingRule(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("routing rule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRoutingRules lists a tenant's routing rules in evaluation order: highest priority first,
+// then oldest first
+func (s *RoutingRuleStorage) ListRoutingRules(tenantID string) ([]*RoutingRule, error) {
+	query := "SELECT " + routingRuleColumns + " FROM routing_rules WHERE tenant_id = $1 ORDER BY priority DESC, created_at ASC, id ASC"
+	rows, err := s.client.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*RoutingRule
+	for rows.Next() {
+		rule, err := scanRoutingRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating routing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateRoutingRule replaces a routing rule's priority, conditions and action
+func (s *RoutingRuleStorage) UpdateRoutingRule(rule *RoutingRule) error {
+	rule.UpdatedAt = time.Now()
+	conditionsJSON, actionJSON, err := encodeRoutingRule(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE routing_rules
+		SET priority = $1, conditions = $2, action = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6
+	`
+	result, err := s.client.DB.Exec(query, rule.Priority, conditionsJSON, actionJSON, rule.UpdatedAt, rule.ID, rule.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("routing rule not found")
+	}
+	return nil
+}
+
+// DeleteRoutingRule removes a tenant's routing rule
+func (s *RoutingRuleStorage) DeleteRoutingRule(tenantID, id string) error {
+	result, err := s.client.DB.Exec("DELETE FROM routing_rules WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("routing rule not found")
+	}
+	return nil
+}
+
+// encodeRoutingRule returns a rule's conditions and action as the JSON text they are stored as
+func encodeRoutingRule(rule *RoutingRule) (string, string, error) {
+	conditions := rule.Conditions
+	if conditions == nil {
+		conditions = []RoutingCondition{}
+	}
+	conditionsJSON, err := json.Marshal(conditions)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode routing conditions: %w", err)
+	}
+	actionJSON, err := json.Marshal(rule.Action)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode routing action: %w", err)
+	}
+	return string(conditionsJSON), string(actionJSON), nil
+}
+
+func scanRoutingRule(row rowScanner) (*RoutingRule, error) {
+	rule := &RoutingRule{}
+	var conditionsJSON, actionJSON string
+	if err := row.Scan(&rule.ID, &rule.TenantID, &rule.Priority, &conditionsJSON, &actionJSON, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(conditionsJSON), &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to parse routing conditions: %w", err)
+	}
+	if err := json.Unmarshal([]byte(actionJSON), &rule.Action); err != nil {
+		return nil, fmt.Errorf("failed to parse routing action: %w", err)
+	}
+	return rule, nil
+}
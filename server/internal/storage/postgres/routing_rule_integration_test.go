@@ -0,0 +1,100 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoutingRuleCRUD(t *testing.T) {
+	storage := NewRoutingRuleStorage(testClient)
+	tenantID := newIsolatedTenant(t, "routing", "routing_rules")
+	otherTenant := newIsolatedTenant(t, "routing", "routing_rules")
+
+	rule := &RoutingRule{
+		TenantID: tenantID,
+		Priority: 10,
+		Conditions: []RoutingCondition{
+			{Field: "intent", Operator: "eq", Value: "buying"},
+			{Field: "urgency_score", Operator: "gte", Value: 0.8},
+			{Field: "customer_language", Operator: "in", Value: []interface{}{"hi", "en"}},
+		},
+		Action: RoutingAction{Type: "assign_agent", AgentID: "agent-1"},
+	}
+	if err := storage.CreateRoutingRule(rule); err != nil {
+		t.Fatalf("CreateRoutingRule: %v", err)
+	}
+
+	got, err := storage.GetRoutingRule(tenantID, rule.ID)
+	if err != nil {
+		t.Fatalf("GetRoutingRule: %v", err)
+	}
+	if got.Priority != 10 || len(got.Conditions) != 3 || got.Action != rule.Action {
+		t.Errorf("GetRoutingRule = %+v, want %+v", got, rule)
+	}
+	if got.Conditions[1].Value != 0.8 {
+		t.Errorf("urgency condition value = %#v, want 0.8", got.Conditions[1].Value)
+	}
+	if values, ok := got.Conditions[2].Value.([]interface{}); !ok || len(values) != 2 || values[0] != "hi" {
+		t.Errorf("language condition value = %#v, want [hi en]", got.Conditions[2].Value)
+	}
+	if _, err := storage.GetRoutingRule(otherTenant, rule.ID); err == nil {
+		t.Error("GetRoutingRule from another tenant should fail")
+	}
+
+	got.Priority = 5
+	got.Conditions = nil
+	got.Action.AgentID = "agent-2"
+	if err := storage.UpdateRoutingRule(got); err != nil {
+		t.Fatalf("UpdateRoutingRule: %v", err)
+	}
+	updated, _ := storage.GetRoutingRule(tenantID, rule.ID)
+	if updated.Priority != 5 || len(updated.Conditions) != 0 || updated.Action.AgentID != "agent-2" {
+		t.Errorf("after update = %+v", updated)
+	}
+
+	if err := storage.DeleteRoutingRule(otherTenant, rule.ID); err == nil {
+		t.Error("DeleteRoutingRule from another tenant should fail")
+	}
+	if err := storage.DeleteRoutingRule(tenantID, rule.ID); err != nil {
+		t.Fatalf("DeleteRoutingRule: %v", err)
+	}
+	if rules, err := storage.ListRoutingRules(tenantID); err != nil || len(rules) != 0 {
+		t.Errorf("ListRoutingRules after delete = %d, %v; want none", len(rules), err)
+	}
+}
+
+func TestListRoutingRulesOrdersByPriority(t *testing.T) {
+	storage := NewRoutingRuleStorage(testClient)
+	tenantID := newIsolatedTenant(t, "routing", "routing_rules")
+	otherTenant := newIsolatedTenant(t, "routing", "routing_rules")
+
+	created := time.Now().Add(-time.Hour)
+	rules := []*RoutingRule{
+		{TenantID: tenantID, Priority: 1, CreatedAt: created},
+		{TenantID: tenantID, Priority: 20, CreatedAt: created.Add(time.Minute)},
+		{TenantID: tenantID, Priority: 1, CreatedAt: created.Add(2 * time.Minute)},
+		{TenantID: otherTenant, Priority: 100, CreatedAt: created},
+	}
+	for _, rule := range rules {
+		rule.Action = RoutingAction{Type: "assign_agent", AgentID: "agent-1"}
+		if err := storage.CreateRoutingRule(rule); err != nil {
+			t.Fatalf("CreateRoutingRule: %v", err)
+		}
+	}
+
+	listed, err := storage.ListRoutingRules(tenantID)
+	if err != nil {
+		t.Fatalf("ListRoutingRules: %v", err)
+	}
+	want := []string{rules[1].ID, rules[0].ID, rules[2].ID}
+	if len(listed) != len(want) {
+		t.Fatalf("ListRoutingRules = %d rules, want %d", len(listed), len(want))
+	}
+	for i, rule := range listed {
+		if rule.ID != want[i] {
+			t.Errorf("rule %d = %s (priority %d), want %s", i, rule.ID, rule.Priority, want[i])
+		}
+	}
+}
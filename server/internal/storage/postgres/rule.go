@@ -33,6 +33,40 @@ func (s *RuleStorage) CreateRule(tenantID string, rule *models.Rule) error {
 	return nil
 }
 
+// BulkCreateRules creates several rules in a single transaction
+func (s *RuleStorage) BulkCreateRules(tenantID string, rules []*models.Rule) error {
+	return s.client.withRetry("BulkCreateRules", tenantID, func() error {
+		return s.bulkCreateRules(tenantID, rules)
+	})
+}
+
+func (s *RuleStorage) bulkCreateRules(tenantID string, rules []*models.Rule) error {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO rules (id, tenant_id, name, description, type, pattern, action, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	for _, rule := range rules {
+		_, err := tx.Exec(query,
+			rule.ID, tenantID, rule.Name, rule.Description, rule.Type,
+			rule.Pattern, rule.Action, rule.IsActive, rule.CreatedAt, rule.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create rule %s: %w", rule.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rules: %w", err)
+	}
+	return nil
+}
+
 // GetRule retrieves a rule by ID (tenant-scoped)
 func (s *RuleStorage) GetRule(tenantID, ruleID string) (*models.Rule, error) {
 	query := `
@@ -0,0 +1,357 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLA statuses reported for a conversation
+const (
+	SLAStatusOK       = "ok"       // Every customer message has been answered
+	SLAStatusPending  = "pending"  // Waiting for an agent reply, still within the deadline
+	SLAStatusBreached = "breached" // Waiting for an agent reply past the deadline
+)
+
+// SLAConfig is a tenant's agent response time target
+type SLAConfig struct {
+	TenantID                 string    `json:"tenant_id"`
+	ResponseThresholdMinutes int       `json:"response_threshold_minutes"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+// ProductSLAConfig overrides the tenant's SLA for conversations about a product
+type ProductSLAConfig struct {
+	ProductID            string    `json:"product_id"`
+	TenantID             string    `json:"tenant_id"`
+	FirstResponseMinutes int       `json:"first_response_minutes"`
+	ResolutionHours      int       `json:"resolution_hours"` // 0 means no resolution deadline
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// SLARecord tracks the agent response deadline for one customer message
+type SLARecord struct {
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	ConversationID     string     `json:"conversation_id"`
+	CustomerMessageID  string     `json:"customer_message_id"`
+	ExpectedResponseBy time.Time  `json:"expected_response_by"`
+	RespondedAt        *time.Time `json:"responded_at,omitempty"`
+	Breached           bool       `json:"breached"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// SLABreachSummary aggregates a tenant's breached response deadlines
+type SLABreachSummary struct {
+	BreachCount int `json:"breach_count"`
+	// OpenBreaches are breaches still waiting for an agent reply
+	OpenBreaches int `json:"open_breaches"`
+	// AverageBreachSeconds is how long replies came after the deadline on average;
+	// unanswered breaches count up to now
+	AverageBreachSeconds float64 `json:"average_breach_seconds"`
+}
+
+// SLAStorage handles response time SLA configuration and tracking
+type SLAStorage struct {
+	client *Client
+}
+
+// NewSLAStorage creates a new SLA storage instance
+func NewSLAStorage(client *Client) *SLAStorage {
+	return &SLAStorage{client: client}
+}
+
+// GetSLAConfig retrieves a tenant's SLA config, or nil if none is configured
+func (s *SLAStorage) GetSLAConfig(tenantID string) (*SLAConfig, error) {
+	config := &SLAConfig{}
+	err := s.client.DB.QueryRow(
+		"SELECT tenant_id, response_threshold_minutes, updated_at FROM tenant_sla_config WHERE tenant_id = $1",
+		tenantID,
+	).Scan(&config.TenantID, &config.ResponseThresholdMinutes, &config.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sla config: %w", err)
+	}
+	return config, nil
+}
+
+// SetSLAConfig creates or replaces a tenant's SLA config
+func (s *SLAStorage) SetSLAConfig(config *SLAConfig) error {
+	config.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO tenant_sla_config (tenant_id, response_threshold_minutes, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			response_threshold_minutes = excluded.response_threshold_minutes,
+			updated_at = excluded.updated_at
+	`
+	if _, err := s.client.DB.Exec(query, config.TenantID, config.ResponseThresholdMinutes, config.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to set sla config: %w", err)
+	}
+	return nil
+}
+
+// GetProductSLAConfig retrieves a product's SLA config, or nil if the product uses the tenant's.
+// Returns ErrProductNotFound if the product doesn't exist in the tenant.
+func (s *SLAStorage) GetProductSLAConfig(tenantID, productID string) (*ProductSLAConfig, error) {
+	var firstResponseMinutes, resolutionHours sql.NullInt64
+	var updatedAt sql.NullTime
+	err := s.client.DB.QueryRow(`
+		SELECT c.first_response_minutes, c.resolution_hours, c.updated_at
+		FROM products p
+		LEFT JOIN product_sla_config c ON c.product_id = p.id
+		WHERE p.id = $1 AND p.tenant_id = $2
+	`, productID, tenantID).Scan(&firstResponseMinutes, &resolutionHours, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product sla config: %w", err)
+	}
+	if !firstResponseMinutes.Valid {
+		return nil, nil
+	}
+	return &ProductSLAConfig{
+		ProductID:            productID,
+		TenantID:             tenantID,
+		FirstResponseMinutes: int(firstResponseMinutes.Int64),
+		ResolutionHours:      int(resolutionHours.Int64),
+		UpdatedAt:            updatedAt.Time,
+	}, nil
+}
+
+// SetProductSLAConfig creates or replaces a product's SLA config. Returns ErrProductNotFound if
+// the product doesn't exist in the tenant.
+func (s *SLAStorage) SetProductSLAConfig(config *ProductSLAConfig) error {
+	config.UpdatedAt = time.Now()
+	result, err := s.client.DB.Exec(`
+		INSERT INTO product_sla_config (product_id, tenant_id, first_response_minutes, resolution_hours, updated_at)
+		SELECT id, tenant_id, $1, $2, $3 FROM products WHERE id = $4 AND tenant_id = $5
+		ON CONFLICT(product_id) DO UPDATE SET
+			first_response_minutes = excluded.first_response_minutes,
+			resolution_hours = excluded.resolution_hours,
+			updated_at = excluded.updated_at
+	`, config.FirstResponseMinutes, config.ResolutionHours, config.UpdatedAt, config.ProductID, config.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to set product sla config: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// GetConversationProductSLAConfig retrieves the SLA config of the product a conversation is
+// about, or nil if it has no product or the product uses the tenant's SLA
+func (s *SLAStorage) GetConversationProductSLAConfig(tenantID, conversationID string) (*ProductSLAConfig, error) {
+	config := &ProductSLAConfig{}
+	err := s.client.DB.QueryRow(`
+		SELECT c.product_id, c.tenant_id, c.first_response_minutes, c.resolution_hours, c.updated_at
+		FROM conversations conv
+		JOIN product_sla_config c ON c.product_id = conv.product_id AND c.tenant_id = conv.tenant_id
+		WHERE conv.id = $1 AND conv.tenant_id = $2
+	`, conversationID, tenantID).Scan(&config.ProductID, &config.TenantID, &config.FirstResponseMinutes,
+		&config.ResolutionHours, &config.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation product sla config: %w", err)
+	}
+	return config, nil
+}
+
+// ListProductSLAConfigs returns a tenant's product SLA configs by product ID
+func (s *SLAStorage) ListProductSLAConfigs(tenantID string) (map[string]*ProductSLAConfig, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT product_id, tenant_id, first_response_minutes, resolution_hours, updated_at
+		FROM product_sla_config
+		WHERE tenant_id = $1
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product sla configs: %w", err)
+	}
+	defer rows.Close()
+
+	configs := make(map[string]*ProductSLAConfig)
+	for rows.Next() {
+		config := &ProductSLAConfig{}
+		if err := rows.Scan(&config.ProductID, &config.TenantID, &config.FirstResponseMinutes,
+			&config.ResolutionHours, &config.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan product sla config: %w", err)
+		}
+		configs[config.ProductID] = config
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product sla configs: %w", err)
+	}
+	return configs, nil
+}
+
+// CreateSLARecord starts tracking a customer message. A message that is already tracked is left alone.
+func (s *SLAStorage) CreateSLARecord(record *SLARecord) error {
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO sla_breaches (id, tenant_id, conversation_id, customer_message_id, expected_response_by, breached, created_at)
+		VALUES ($1, $2, $3, $4, $5, FALSE, $6)
+		ON CONFLICT(customer_message_id) DO NOTHING
+	`
+	_, err := s.client.DB.Exec(query, record.ID, record.TenantID, record.ConversationID,
+		record.CustomerMessageID, record.ExpectedResponseBy.UTC(), record.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to create sla record: %w", err)
+	}
+	return nil
+}
+
+// MarkSLAResponded closes every open deadline in a conversation, marking the ones the reply missed
+// as breached. Returns the number of deadlines closed.
+func (s *SLAStorage) MarkSLAResponded(tenantID, conversationID string, respondedAt time.Time) (int64, error) {
+	respondedAt = respondedAt.UTC()
+	query := `
+		UPDATE sla_breaches
+		SET responded_at = $1,
+			breached = CASE WHEN expected_response_by < $2 THEN TRUE ELSE breached END
+		WHERE tenant_id = $3 AND conversation_id = $4 AND responded_at IS NULL
+	`
+	result, err := s.client.DB.Exec(query, respondedAt, respondedAt, tenantID, conversationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark sla responded: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// MarkSLABreaches marks unanswered deadlines that passed before now as breached, across all
+// tenants. Returns the number of newly breached deadlines.
+func (s *SLAStorage) MarkSLABreaches(now time.Time) (int64, error) {
+	result, err := s.client.DB.Exec(`
+		UPDATE sla_breaches
+		SET breached = TRUE
+		WHERE breached = FALSE AND responded_at IS NULL AND expected_response_by < $1
+	`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark sla breaches: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetSLABreachSummary summarizes a tenant's breaches whose deadline fell within [from, to]
+func (s *SLAStorage) GetSLABreachSummary(tenantID string, from, to, now time.Time) (*SLABreachSummary, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT expected_response_by, responded_at
+		FROM sla_breaches
+		WHERE tenant_id = $1 AND breached = TRUE AND expected_response_by >= $2 AND expected_response_by <= $3
+	`, tenantID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sla breaches: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &SLABreachSummary{}
+	var total time.Duration
+	for rows.Next() {
+		var expected time.Time
+		var respondedAt sql.NullTime
+		if err := rows.Scan(&expected, &respondedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sla breach: %w", err)
+		}
+		end := now
+		if respondedAt.Valid {
+			end = respondedAt.Time
+		} else {
+			summary.OpenBreaches++
+		}
+		summary.BreachCount++
+		total += end.Sub(expected)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sla breaches: %w", err)
+	}
+	if summary.BreachCount > 0 {
+		summary.AverageBreachSeconds = total.Seconds() / float64(summary.BreachCount)
+	}
+	return summary, nil
+}
+
+// GetSLAStatuses returns the current SLA status of each tracked conversation of a tenant.
+// Deadlines past now count as breached even before the breach scan has marked them.
+func (s *SLAStorage) GetSLAStatuses(tenantID string, now time.Time) (map[string]string, error) {
+	// SQLite numbers placeholders by first appearance, so now is $1
+	rows, err := s.client.DB.Query(`
+		SELECT conversation_id,
+			SUM(CASE WHEN responded_at IS NULL AND (breached = TRUE OR expected_response_by < $1) THEN 1 ELSE 0 END),
+			SUM(CASE WHEN responded_at IS NULL THEN 1 ELSE 0 END)
+		FROM sla_breaches
+		WHERE tenant_id = $2
+		GROUP BY conversation_id
+	`, now.UTC(), tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sla statuses: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := make(map[string]string)
+	for rows.Next() {
+		var conversationID string
+		var overdue, open int
+		if err := rows.Scan(&conversationID, &overdue, &open); err != nil {
+			return nil, fmt.Errorf("failed to scan sla status: %w", err)
+		}
+		switch {
+		case overdue > 0:
+			statuses[conversationID] = SLAStatusBreached
+		case open > 0:
+			statuses[conversationID] = SLAStatusPending
+		default:
+			statuses[conversationID] = SLAStatusOK
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sla statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+// GetOpenSLADeadlines returns the earliest unanswered response deadline of each of a tenant's
+// conversations waiting for an agent reply
+func (s *SLAStorage) GetOpenSLADeadlines(tenantID string) (map[string]time.Time, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT conversation_id, expected_response_by
+		FROM sla_breaches
+		WHERE tenant_id = $1 AND responded_at IS NULL
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open sla deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	deadlines := make(map[string]time.Time)
+	for rows.Next() {
+		var conversationID string
+		var expected time.Time
+		if err := rows.Scan(&conversationID, &expected); err != nil {
+			return nil, fmt.Errorf("failed to scan sla deadline: %w", err)
+		}
+		if earliest, ok := deadlines[conversationID]; !ok || expected.Before(earliest) {
+			deadlines[conversationID] = expected
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sla deadlines: %w", err)
+	}
+	return deadlines, nil
+}
@@ -0,0 +1,195 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestSLAConfigRoundTrip(t *testing.T) {
+	storage := NewSLAStorage(testClient)
+	tenantID := newIsolatedTenant(t, "sla", "sla_breaches", "tenant_sla_config", "conversations", "product_sla_config", "products")
+
+	if config, err := storage.GetSLAConfig(tenantID); err != nil || config != nil {
+		t.Fatalf("GetSLAConfig before set = %+v, %v; want nil", config, err)
+	}
+	for _, minutes := range []int{30, 15} {
+		if err := storage.SetSLAConfig(&SLAConfig{TenantID: tenantID, ResponseThresholdMinutes: minutes}); err != nil {
+			t.Fatalf("SetSLAConfig(%d): %v", minutes, err)
+		}
+	}
+	config, err := storage.GetSLAConfig(tenantID)
+	if err != nil || config == nil || config.ResponseThresholdMinutes != 15 {
+		t.Fatalf("GetSLAConfig = %+v, %v; want 15 minutes", config, err)
+	}
+}
+
+func TestSLABreachTracking(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewSLAStorage(testClient)
+	tenantID := newIsolatedTenant(t, "sla", "sla_breaches", "tenant_sla_config", "conversations", "product_sla_config", "products")
+	start := time.Now().UTC().Truncate(time.Second).Add(-3 * time.Hour)
+	for _, id := range []string{"answered", "late", "waiting", "fresh"} {
+		createConversationAt(t, conversations, tenantID, tenantID+"-"+id, nil, start)
+	}
+	conv := func(id string) string { return tenantID + "-" + id }
+
+	deadline := start.Add(30 * time.Minute)
+	records := map[string]time.Time{
+		"answered": deadline,
+		"late":     deadline,
+		"waiting":  deadline,
+		"fresh":    start.Add(4 * time.Hour), // Deadline still ahead
+	}
+	for id, expected := range records {
+		record := &SLARecord{TenantID: tenantID, ConversationID: conv(id), CustomerMessageID: conv(id) + "-m1", ExpectedResponseBy: expected}
+		if err := storage.CreateSLARecord(record); err != nil {
+			t.Fatalf("CreateSLARecord(%s): %v", id, err)
+		}
+	}
+	// Tracking the same message twice keeps the first deadline
+	if err := storage.CreateSLARecord(&SLARecord{TenantID: tenantID, ConversationID: conv("answered"), CustomerMessageID: conv("answered") + "-m1", ExpectedResponseBy: start}); err != nil {
+		t.Fatalf("CreateSLARecord (duplicate): %v", err)
+	}
+
+	// Answered in time
+	if n, err := storage.MarkSLAResponded(tenantID, conv("answered"), start.Add(10*time.Minute)); err != nil || n != 1 {
+		t.Fatalf("MarkSLAResponded(answered) = %d, %v; want 1", n, err)
+	}
+
+	// Before the scan, a passed deadline already reports as breached
+	now := start.Add(time.Hour)
+	statuses, err := storage.GetSLAStatuses(tenantID, now)
+	if err != nil {
+		t.Fatalf("GetSLAStatuses: %v", err)
+	}
+	want := map[string]string{conv("answered"): SLAStatusOK, conv("late"): SLAStatusBreached, conv("waiting"): SLAStatusBreached, conv("fresh"): SLAStatusPending}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("status(%s) = %q, want %q", id, statuses[id], status)
+		}
+	}
+
+	if n, err := storage.MarkSLABreaches(now); err != nil || n < 2 {
+		t.Fatalf("MarkSLABreaches = %d, %v; want at least this tenant's 2", n, err)
+	}
+	if n, err := storage.MarkSLABreaches(now); err != nil || n != 0 {
+		t.Errorf("second MarkSLABreaches = %d, %v; want 0", n, err)
+	}
+
+	// A late reply keeps the breach and records when it came
+	if _, err := storage.MarkSLAResponded(tenantID, conv("late"), deadline.Add(20*time.Minute)); err != nil {
+		t.Fatalf("MarkSLAResponded(late): %v", err)
+	}
+
+	summary, err := storage.GetSLABreachSummary(tenantID, start, start.Add(2*time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetSLABreachSummary: %v", err)
+	}
+	// late: 20m past the deadline; waiting: still open, 30m past the deadline at now
+	if summary.BreachCount != 2 || summary.OpenBreaches != 1 || summary.AverageBreachSeconds != 25*60 {
+		t.Errorf("summary = %+v, want 2 breaches, 1 open, 1500s average", summary)
+	}
+
+	// Deadlines outside the range are left out
+	if summary, err := storage.GetSLABreachSummary(tenantID, start.Add(2*time.Hour), start.Add(5*time.Hour), now); err != nil || summary.BreachCount != 0 {
+		t.Errorf("summary outside range = %+v, %v; want no breaches", summary, err)
+	}
+	if statuses, _ := storage.GetSLAStatuses(tenantID, now); statuses[conv("late")] != SLAStatusOK {
+		t.Errorf("status(late) after reply = %q, want ok", statuses[conv("late")])
+	}
+}
+
+func TestProductSLAConfig(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewSLAStorage(testClient)
+	tenantID := newIsolatedTenant(t, "sla", "sla_breaches", "tenant_sla_config", "conversations", "product_sla_config", "products")
+	product := newTestCategorizedProduct(t, tenantID, "Enterprise", nil)
+	other := newTestCategorizedProduct(t, tenantID, "Starter", nil)
+
+	if config, err := storage.GetProductSLAConfig(tenantID, product.ID); err != nil || config != nil {
+		t.Fatalf("GetProductSLAConfig before set = %+v, %v; want nil", config, err)
+	}
+	for _, minutes := range []int{30, 5} {
+		if err := storage.SetProductSLAConfig(&ProductSLAConfig{ProductID: product.ID, TenantID: tenantID, FirstResponseMinutes: minutes, ResolutionHours: 24}); err != nil {
+			t.Fatalf("SetProductSLAConfig(%d): %v", minutes, err)
+		}
+	}
+	config, err := storage.GetProductSLAConfig(tenantID, product.ID)
+	if err != nil || config == nil || config.FirstResponseMinutes != 5 || config.ResolutionHours != 24 {
+		t.Fatalf("GetProductSLAConfig = %+v, %v; want 5 minutes and 24 hours", config, err)
+	}
+
+	// Products of other tenants can't be configured or read
+	if err := storage.SetProductSLAConfig(&ProductSLAConfig{ProductID: product.ID, TenantID: "other-tenant", FirstResponseMinutes: 1}); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("SetProductSLAConfig for another tenant err = %v, want ErrProductNotFound", err)
+	}
+	if _, err := storage.GetProductSLAConfig("other-tenant", product.ID); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("GetProductSLAConfig for another tenant err = %v, want ErrProductNotFound", err)
+	}
+
+	// Conversations pick up their product's config
+	now := time.Now().UTC()
+	for id, productID := range map[string]*string{"enterprise": &product.ID, "starter": &other.ID, "none": nil} {
+		conv := &models.Conversation{ID: tenantID + "-" + id, TenantID: tenantID, ProductID: productID, Status: "active", CreatedAt: now, UpdatedAt: now}
+		if err := conversations.CreateConversation(tenantID, conv); err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+	}
+	if config, err := storage.GetConversationProductSLAConfig(tenantID, tenantID+"-enterprise"); err != nil || config == nil || config.FirstResponseMinutes != 5 {
+		t.Errorf("GetConversationProductSLAConfig(enterprise) = %+v, %v; want the product's 5 minutes", config, err)
+	}
+	for _, id := range []string{"starter", "none"} {
+		if config, err := storage.GetConversationProductSLAConfig(tenantID, tenantID+"-"+id); err != nil || config != nil {
+			t.Errorf("GetConversationProductSLAConfig(%s) = %+v, %v; want nil", id, config, err)
+		}
+	}
+
+	configs, err := storage.ListProductSLAConfigs(tenantID)
+	if err != nil || len(configs) != 1 || configs[product.ID] == nil {
+		t.Errorf("ListProductSLAConfigs = %v, %v; want only the enterprise product", configs, err)
+	}
+
+	// Deleting the product deletes its config
+	if err := NewProductStorage(testClient).DeleteProduct(tenantID, product.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if configs, _ := storage.ListProductSLAConfigs(tenantID); len(configs) != 0 {
+		t.Errorf("configs after deleting the product = %v, want none", configs)
+	}
+}
+
+func TestOpenSLADeadlines(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewSLAStorage(testClient)
+	tenantID := newIsolatedTenant(t, "sla", "sla_breaches", "tenant_sla_config", "conversations", "product_sla_config", "products")
+	start := time.Now().UTC().Truncate(time.Second)
+	for _, id := range []string{"waiting", "answered"} {
+		createConversationAt(t, conversations, tenantID, tenantID+"-"+id, nil, start)
+	}
+	for i, offset := range []time.Duration{time.Hour, 30 * time.Minute} {
+		if err := storage.CreateSLARecord(&SLARecord{TenantID: tenantID, ConversationID: tenantID + "-waiting", CustomerMessageID: uuid.New().String(), ExpectedResponseBy: start.Add(offset)}); err != nil {
+			t.Fatalf("CreateSLARecord(%d): %v", i, err)
+		}
+	}
+	if err := storage.CreateSLARecord(&SLARecord{TenantID: tenantID, ConversationID: tenantID + "-answered", CustomerMessageID: uuid.New().String(), ExpectedResponseBy: start}); err != nil {
+		t.Fatalf("CreateSLARecord: %v", err)
+	}
+	if _, err := storage.MarkSLAResponded(tenantID, tenantID+"-answered", start); err != nil {
+		t.Fatalf("MarkSLAResponded: %v", err)
+	}
+
+	deadlines, err := storage.GetOpenSLADeadlines(tenantID)
+	if err != nil {
+		t.Fatalf("GetOpenSLADeadlines: %v", err)
+	}
+	if len(deadlines) != 1 || !deadlines[tenantID+"-waiting"].Equal(start.Add(30*time.Minute)) {
+		t.Errorf("deadlines = %v, want only the waiting conversation's earliest deadline", deadlines)
+	}
+}
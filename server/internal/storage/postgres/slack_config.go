@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SlackConfig is a tenant's Slack integration settings
+type SlackConfig struct {
+	TenantID          string    `json:"tenant_id"`
+	WebhookURL        string    `json:"webhook_url"`
+	HotLeadChannel    string    `json:"hot_lead_channel"`
+	EscalationChannel string    `json:"escalation_channel"`
+	WatchlistChannel  string    `json:"watchlist_channel"`
+	IsActive          bool      `json:"is_active"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SlackConfigStorage handles per-tenant Slack configuration
+type SlackConfigStorage struct {
+	client *Client
+}
+
+// NewSlackConfigStorage creates a new Slack config storage instance
+func NewSlackConfigStorage(client *Client) *SlackConfigStorage {
+	return &SlackConfigStorage{client: client}
+}
+
+// GetSlackConfig retrieves a tenant's Slack config, or nil if none is configured
+func (s *SlackConfigStorage) GetSlackConfig(tenantID string) (*SlackConfig, error) {
+	query := `
+		SELECT tenant_id, webhook_url, hot_lead_channel, escalation_channel, watchlist_channel, is_active, updated_at
+		FROM tenant_slack_config
+		WHERE tenant_id = $1
+	`
+	config := &SlackConfig{}
+	err := s.client.DB.QueryRow(query, tenantID).Scan(
+		&config.TenantID, &config.WebhookURL, &config.HotLeadChannel,
+		&config.EscalationChannel, &config.WatchlistChannel, &config.IsActive, &config.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slack config: %w", err)
+	}
+	return config, nil
+}
+
+// SetSlackConfig creates or replaces a tenant's Slack config
+func (s *SlackConfigStorage) SetSlackConfig(config *SlackConfig) error {
+	config.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO tenant_slack_config (tenant_id, webhook_url, hot_lead_channel, escalation_channel, watchlist_channel, is_active, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(tenant_id) DO UPDATE SET
+			webhook_url = excluded.webhook_url,
+			hot_lead_channel = excluded.hot_lead_channel,
+			escalation_channel = excluded.escalation_channel,
+			watchlist_channel = excluded.watchlist_channel,
+			is_active = excluded.is_active,
+			updated_at = excluded.updated_at
+	`
+	_, err := s.client.DB.Exec(query,
+		config.TenantID, config.WebhookURL, config.HotLeadChannel,
+		config.EscalationChannel, config.WatchlistChannel, config.IsActive, config.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set slack config: %w", err)
+	}
+	return nil
+}
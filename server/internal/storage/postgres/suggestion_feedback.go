@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// SuggestionFeedbackCounts counts a tenant's suggestion feedback by action
+type SuggestionFeedbackCounts struct {
+	Accepted int
+	Rejected int
+	Edited   int
+}
+
+// Total returns the number of feedback entries counted
+func (c SuggestionFeedbackCounts) Total() int {
+	return c.Accepted + c.Rejected + c.Edited
+}
+
+// SuggestionFeedbackStorage handles agent feedback on reply suggestions
+type SuggestionFeedbackStorage struct {
+	client *Client
+}
+
+// NewSuggestionFeedbackStorage creates a new suggestion feedback storage instance
+func NewSuggestionFeedbackStorage(client *Client) *SuggestionFeedbackStorage {
+	return &SuggestionFeedbackStorage{client: client}
+}
+
+const suggestionFeedbackColumns = `id, suggestion_id, conversation_id, tenant_id, agent_id, action, edited_text, feedback_at`
+
+// CreateFeedback records feedback on a suggestion. The conversation must belong to the feedback's
+// tenant; ID and FeedbackAt are set when empty.
+func (s *SuggestionFeedbackStorage) CreateFeedback(feedback *models.SuggestionFeedback) error {
+	if feedback.ID == "" {
+		feedback.ID = uuid.New().String()
+	}
+	if feedback.FeedbackAt.IsZero() {
+		feedback.FeedbackAt = time.Now()
+	}
+
+	agentID := sql.NullString{String: feedback.AgentID, Valid: feedback.AgentID != ""}
+	result, err := s.client.DB.Exec(`
+		INSERT INTO suggestion_feedback (id, suggestion_id, conversation_id, tenant_id, agent_id, action, edited_text, feedback_at)
+		SELECT $1, $2, c.id, c.tenant_id, $3, $4, $5, $6
+		FROM conversations c
+		WHERE c.id = $7 AND c.tenant_id = $8 AND c.deleted_at IS NULL
+	`, feedback.ID, feedback.SuggestionID, agentID, feedback.Action, feedback.EditedText, feedback.FeedbackAt,
+		feedback.ConversationID, feedback.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to create suggestion feedback: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+	return nil
+}
+
+// GetFeedback returns a feedback entry of the tenant
+func (s *SuggestionFeedbackStorage) GetFeedback(tenantID, feedbackID string) (*models.SuggestionFeedback, error) {
+	query := `SELECT ` + suggestionFeedbackColumns + ` FROM suggestion_feedback WHERE id = $1 AND tenant_id = $2`
+	feedback, err := scanSuggestionFeedback(s.client.DB.QueryRow(query, feedbackID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("suggestion feedback not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestion feedback: %w", err)
+	}
+	return feedback, nil
+}
+
+// ListFeedback lists the feedback given on a conversation's suggestions, oldest first
+func (s *SuggestionFeedbackStorage) ListFeedback(tenantID, conversationID string) ([]*models.SuggestionFeedback, error) {
+	query := `SELECT ` + suggestionFeedbackColumns + ` FROM suggestion_feedback
+		WHERE tenant_id = $1 AND conversation_id = $2
+		ORDER BY feedback_at ASC`
+	rows, err := s.client.DB.Query(query, tenantID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suggestion feedback: %w", err)
+	}
+	defer rows.Close()
+
+	feedback := []*models.SuggestionFeedback{}
+	for rows.Next() {
+		entry, err := scanSuggestionFeedback(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion feedback: %w", err)
+		}
+		feedback = append(feedback, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suggestion feedback: %w", err)
+	}
+	return feedback, nil
+}
+
+// UpdateFeedback changes the action and edited text of a feedback entry
+func (s *SuggestionFeedbackStorage) UpdateFeedback(feedback *models.SuggestionFeedback) error {
+	feedback.FeedbackAt = time.Now()
+	result, err := s.client.DB.Exec(`
+		UPDATE suggestion_feedback SET action = $1, edited_text = $2, feedback_at = $3
+		WHERE id = $4 AND tenant_id = $5
+	`, feedback.Action, feedback.EditedText, feedback.FeedbackAt, feedback.ID, feedback.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update suggestion feedback: %w", err)
+	}
+	return requireFeedbackRow(result)
+}
+
+// DeleteFeedback deletes a feedback entry of the tenant
+func (s *SuggestionFeedbackStorage) DeleteFeedback(tenantID, feedbackID string) error {
+	result, err := s.client.DB.Exec("DELETE FROM suggestion_feedback WHERE id = $1 AND tenant_id = $2", feedbackID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete suggestion feedback: %w", err)
+	}
+	return requireFeedbackRow(result)
+}
+
+// CountFeedback counts the tenant's suggestion feedback by action. A non-empty intent only counts
+// feedback on conversations analyzed with that intent.
+func (s *SuggestionFeedbackStorage) CountFeedback(tenantID, intent string) (SuggestionFeedbackCounts, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN f.action = 'accepted' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN f.action = 'rejected' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN f.action = 'edited' THEN 1 ELSE 0 END), 0)
+		FROM suggestion_feedback f
+	`
+	args := []interface{}{tenantID}
+	if intent != "" {
+		query += ` JOIN conversation_metadata cm ON cm.conversation_id = f.conversation_id
+		WHERE f.tenant_id = $1 AND cm.intent = $2`
+		args = append(args, intent)
+	} else {
+		query += ` WHERE f.tenant_id = $1`
+	}
+
+	var counts SuggestionFeedbackCounts
+	if err := s.client.DB.QueryRow(query, args...).Scan(&counts.Accepted, &counts.Rejected, &counts.Edited); err != nil {
+		return SuggestionFeedbackCounts{}, fmt.Errorf("failed to count suggestion feedback: %w", err)
+	}
+	return counts, nil
+}
+
+func requireFeedbackRow(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("suggestion feedback not found")
+	}
+	return nil
+}
+
+func scanSuggestionFeedback(row rowScanner) (*models.SuggestionFeedback, error) {
+	f := &models.SuggestionFeedback{}
+	var agentID, editedText sql.NullString
+	err := row.Scan(&f.ID, &f.SuggestionID, &f.ConversationID, &f.TenantID, &agentID, &f.Action, &editedText, &f.FeedbackAt)
+	if err != nil {
+		return nil, err
+	}
+	f.AgentID = agentID.String
+	f.EditedText = nullStringPtr(editedText)
+	return f, nil
+}
+
+// FeedbackConfidenceSample pairs the confidence a suggestion was shown with against whether the
+// agent used it
+type FeedbackConfidenceSample struct {
+	Confidence float64
+	Accepted   bool // Accepted or edited
+}
+
+// feedbackSuggestion is the part of a stored suggestion needed to calibrate its confidence
+type feedbackSuggestion struct {
+	ID            string   `json:"id"`
+	Confidence    float64  `json:"confidence"`
+	RawConfidence *float64 `json:"raw_confidence"`
+}
+
+// ListFeedbackConfidence returns the confidence of the suggestions behind the tenant's latest
+// limit feedback entries, newest first. Confidence is read from the suggestions stored for the
+// conversation, preferring the uncalibrated score; feedback on suggestions that are no longer
+// stored is skipped.
+func (s *SuggestionFeedbackStorage) ListFeedbackConfidence(tenantID string, limit int) ([]FeedbackConfidenceSample, error) {
+	rows, err := s.client.DB.Query(`
+		SELECT f.id, f.suggestion_id, f.action, s.suggestions_data
+		FROM (
+			SELECT id, suggestion_id, conversation_id, action, feedback_at
+			FROM suggestion_feedback
+			WHERE tenant_id = $1
+			ORDER BY feedback_at DESC
+			LIMIT $2
+		) f
+		JOIN suggestions s ON s.conversation_id = f.conversation_id
+		ORDER BY f.feedback_at DESC, f.id
+	`, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suggestion feedback confidence: %w", err)
+	}
+	defer rows.Close()
+
+	samples := []FeedbackConfidenceSample{}
+	found := make(map[string]bool)
+	for rows.Next() {
+		var feedbackID, suggestionID, action, data string
+		if err := rows.Scan(&feedbackID, &suggestionID, &action, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion feedback confidence: %w", err)
+		}
+		if found[feedbackID] {
+			continue
+		}
+		var suggestions []feedbackSuggestion
+		if err := ParseSuggestionsData(data, &suggestions); err != nil {
+			continue
+		}
+		for _, sug := range suggestions {
+			if sug.ID != suggestionID {
+				continue
+			}
+			confidence := sug.Confidence
+			if sug.RawConfidence != nil {
+				confidence = *sug.RawConfidence
+			}
+			samples = append(samples, FeedbackConfidenceSample{
+				Confidence: confidence,
+				Accepted:   action == models.SuggestionFeedbackAccepted || action == models.SuggestionFeedbackEdited,
+			})
+			found[feedbackID] = true
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list suggestion feedback confidence: %w", err)
+	}
+	return samples, nil
+}
@@ -0,0 +1,174 @@
+//go:build integration
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestSuggestionFeedbackCRUD(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	feedbackStorage := NewSuggestionFeedbackStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM suggestion_feedback WHERE tenant_id = $1", tenantID) })
+	conversationID := uuid.New().String()
+	createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC())
+
+	edited := "Sure, 10% off today"
+	feedback := &models.SuggestionFeedback{
+		SuggestionID: "suggestion-1", ConversationID: conversationID, TenantID: tenantID, AgentID: "agent-1",
+		Action: models.SuggestionFeedbackEdited, EditedText: &edited,
+	}
+	if err := feedbackStorage.CreateFeedback(feedback); err != nil {
+		t.Fatalf("CreateFeedback: %v", err)
+	}
+	if feedback.ID == "" || feedback.FeedbackAt.IsZero() {
+		t.Errorf("created feedback = %+v, want an ID and time", feedback)
+	}
+
+	got, err := feedbackStorage.GetFeedback(tenantID, feedback.ID)
+	if err != nil {
+		t.Fatalf("GetFeedback: %v", err)
+	}
+	if got.SuggestionID != "suggestion-1" || got.AgentID != "agent-1" || got.Action != "edited" || got.EditedText == nil || *got.EditedText != edited {
+		t.Errorf("stored feedback = %+v", got)
+	}
+
+	feedback.Action = models.SuggestionFeedbackRejected
+	feedback.EditedText = nil
+	if err := feedbackStorage.UpdateFeedback(feedback); err != nil {
+		t.Fatalf("UpdateFeedback: %v", err)
+	}
+	list, err := feedbackStorage.ListFeedback(tenantID, conversationID)
+	if err != nil {
+		t.Fatalf("ListFeedback: %v", err)
+	}
+	if len(list) != 1 || list[0].Action != "rejected" || list[0].EditedText != nil {
+		t.Errorf("feedback after update = %+v, want one rejected entry", list)
+	}
+
+	// Feedback on another tenant's conversation isn't stored, and other tenants can't see it
+	other := &models.SuggestionFeedback{SuggestionID: "suggestion-2", ConversationID: conversationID, TenantID: "other-tenant", Action: "accepted"}
+	if err := feedbackStorage.CreateFeedback(other); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("CreateFeedback for another tenant: err = %v, want not found", err)
+	}
+	if _, err := feedbackStorage.GetFeedback("other-tenant", feedback.ID); err == nil {
+		t.Error("GetFeedback from another tenant: expected not found")
+	}
+
+	if err := feedbackStorage.DeleteFeedback(tenantID, feedback.ID); err != nil {
+		t.Fatalf("DeleteFeedback: %v", err)
+	}
+	if err := feedbackStorage.DeleteFeedback(tenantID, feedback.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("second DeleteFeedback: err = %v, want not found", err)
+	}
+}
+
+func TestCountSuggestionFeedbackByIntent(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	feedbackStorage := NewSuggestionFeedbackStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM suggestion_feedback WHERE tenant_id = $1", tenantID) })
+
+	record := func(intent string, actions ...string) {
+		t.Helper()
+		conversationID := uuid.New().String()
+		createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC())
+		createTestMetadata(t, storage, tenantID, conversationID, intent)
+		for _, action := range actions {
+			feedback := &models.SuggestionFeedback{SuggestionID: uuid.New().String(), ConversationID: conversationID, TenantID: tenantID, Action: action}
+			if err := feedbackStorage.CreateFeedback(feedback); err != nil {
+				t.Fatalf("CreateFeedback: %v", err)
+			}
+		}
+	}
+	record("buying", "accepted", "accepted", "edited", "rejected")
+	record("complaint", "rejected", "rejected")
+
+	all, err := feedbackStorage.CountFeedback(tenantID, "")
+	if err != nil {
+		t.Fatalf("CountFeedback: %v", err)
+	}
+	if all != (SuggestionFeedbackCounts{Accepted: 2, Rejected: 3, Edited: 1}) {
+		t.Errorf("counts = %+v, want 2 accepted, 3 rejected, 1 edited", all)
+	}
+
+	buying, err := feedbackStorage.CountFeedback(tenantID, "buying")
+	if err != nil {
+		t.Fatalf("CountFeedback(buying): %v", err)
+	}
+	if buying != (SuggestionFeedbackCounts{Accepted: 2, Rejected: 1, Edited: 1}) {
+		t.Errorf("buying counts = %+v, want 2 accepted, 1 rejected, 1 edited", buying)
+	}
+
+	if none, err := feedbackStorage.CountFeedback("other-tenant", ""); err != nil || none.Total() != 0 {
+		t.Errorf("other tenant counts = %+v, %v; want none", none, err)
+	}
+}
+
+func TestListFeedbackConfidence(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	feedbackStorage := NewSuggestionFeedbackStorage(testClient)
+	suggestionsStorage := NewSuggestionsStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM suggestion_feedback WHERE tenant_id = $1", tenantID) })
+	conversationID := uuid.New().String()
+	createConversationAt(t, storage, tenantID, conversationID, nil, time.Now().UTC())
+
+	// Suggestions for two customer messages; s1 predates calibration and has no raw score
+	if err := suggestionsStorage.SaveSuggestions(conversationID, "msg-1", "", `[{"id":"s1","confidence":0.8},{"id":"s2","confidence":0.4}]`, true); err != nil {
+		t.Fatalf("SaveSuggestions: %v", err)
+	}
+	if err := suggestionsStorage.SaveSuggestions(conversationID, "msg-2", "", `[{"id":"s3","confidence":0.5,"raw_confidence":0.9}]`, true); err != nil {
+		t.Fatalf("SaveSuggestions: %v", err)
+	}
+
+	start := time.Now().UTC().Add(-time.Hour)
+	for i, f := range []struct{ suggestion, action string }{
+		{"s1", models.SuggestionFeedbackAccepted},
+		{"s2", models.SuggestionFeedbackRejected},
+		{"gone", models.SuggestionFeedbackAccepted},
+		{"s3", models.SuggestionFeedbackEdited},
+	} {
+		feedback := &models.SuggestionFeedback{
+			SuggestionID: f.suggestion, ConversationID: conversationID, TenantID: tenantID, Action: f.action,
+			FeedbackAt: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := feedbackStorage.CreateFeedback(feedback); err != nil {
+			t.Fatalf("CreateFeedback: %v", err)
+		}
+	}
+
+	samples, err := feedbackStorage.ListFeedbackConfidence(tenantID, 10)
+	if err != nil {
+		t.Fatalf("ListFeedbackConfidence: %v", err)
+	}
+	want := []FeedbackConfidenceSample{{Confidence: 0.9, Accepted: true}, {Confidence: 0.4}, {Confidence: 0.8, Accepted: true}}
+	if len(samples) != len(want) {
+		t.Fatalf("samples = %+v, want %+v", samples, want)
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Errorf("sample %d = %+v, want %+v", i, samples[i], want[i])
+		}
+	}
+
+	// Only the latest feedback is read
+	latest, err := feedbackStorage.ListFeedbackConfidence(tenantID, 2)
+	if err != nil {
+		t.Fatalf("ListFeedbackConfidence: %v", err)
+	}
+	if len(latest) != 1 || latest[0].Confidence != 0.9 {
+		t.Errorf("latest samples = %+v, want only s3's", latest)
+	}
+
+	if other, err := feedbackStorage.ListFeedbackConfidence("other-tenant", 10); err != nil || len(other) != 0 {
+		t.Errorf("other tenant samples = %+v, %v; want none", other, err)
+	}
+}
@@ -14,6 +14,7 @@ type SuggestionsCache struct {
 	ID                    string    `json:"id"`
 	ConversationID        string    `json:"conversation_id"`
 	LastCustomerMessageID string    `json:"last_customer_message_id"`
+	AgentID               string    `json:"agent_id,omitempty"` // Agent whose profile personalized them; empty when unpersonalized
 	SuggestionsData       string    `json:"suggestions_data"` // JSON string
 	ContextUsed           bool      `json:"context_used"`
 	CreatedAt             time.Time `json:"created_at"`
@@ -30,21 +31,23 @@ func NewSuggestionsStorage(client *Client) *SuggestionsStorage {
 	return &SuggestionsStorage{client: client}
 }
 
-// GetSuggestions retrieves cached suggestions for a conversation and last customer message ID
-func (s *SuggestionsStorage) GetSuggestions(conversationID, lastCustomerMessageID string) (*SuggestionsCache, error) {
+// GetSuggestions retrieves cached suggestions for a conversation and last customer message ID.
+// agentID selects suggestions personalized for that agent; empty selects unpersonalized ones.
+func (s *SuggestionsStorage) GetSuggestions(conversationID, lastCustomerMessageID, agentID string) (*SuggestionsCache, error) {
 	query := `
-		SELECT id, conversation_id, last_customer_message_id, suggestions_data, context_used, created_at, updated_at
+		SELECT id, conversation_id, last_customer_message_id, agent_id, suggestions_data, context_used, created_at, updated_at
 		FROM suggestions
-		WHERE conversation_id = $1 AND last_customer_message_id = $2
+		WHERE conversation_id = $1 AND last_customer_message_id = $2 AND agent_id = $3
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
 	
 	cache := &SuggestionsCache{}
-	err := s.client.DB.QueryRow(query, conversationID, lastCustomerMessageID).Scan(
+	err := s.client.DB.QueryRow(query, conversationID, lastCustomerMessageID, agentID).Scan(
 		&cache.ID,
 		&cache.ConversationID,
 		&cache.LastCustomerMessageID,
+		&cache.AgentID,
 		&cache.SuggestionsData,
 		&cache.ContextUsed,
 		&cache.CreatedAt,
@@ -61,24 +64,25 @@ func (s *SuggestionsStorage) GetSuggestions(conversationID, lastCustomerMessageI
 	return cache, nil
 }
 
-// SaveSuggestions saves suggestions to cache
-func (s *SuggestionsStorage) SaveSuggestions(conversationID, lastCustomerMessageID string, suggestionsData string, contextUsed bool) error {
+// SaveSuggestions saves suggestions to cache. agentID is the agent whose profile personalized them,
+// or empty for unpersonalized suggestions.
+func (s *SuggestionsStorage) SaveSuggestions(conversationID, lastCustomerMessageID, agentID string, suggestionsData string, contextUsed bool) error {
 	now := time.Now()
 	id := uuid.New().String()
 	
-	// Delete old cache entry for this conversation + message ID combination
-	deleteQuery := `DELETE FROM suggestions WHERE conversation_id = $1 AND last_customer_message_id = $2`
-	_, err := s.client.DB.Exec(deleteQuery, conversationID, lastCustomerMessageID)
+	// Delete old cache entry for this conversation + message ID + agent combination
+	deleteQuery := `DELETE FROM suggestions WHERE conversation_id = $1 AND last_customer_message_id = $2 AND agent_id = $3`
+	_, err := s.client.DB.Exec(deleteQuery, conversationID, lastCustomerMessageID, agentID)
 	if err != nil {
 		return fmt.Errorf("failed to delete old suggestions cache: %w", err)
 	}
 	
 	// Insert new cache entry
 	insertQuery := `
-		INSERT INTO suggestions (id, conversation_id, last_customer_message_id, suggestions_data, context_used, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO suggestions (id, conversation_id, last_customer_message_id, agent_id, suggestions_data, context_used, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err = s.client.DB.Exec(insertQuery, id, conversationID, lastCustomerMessageID, suggestionsData, contextUsed, now, now)
+	_, err = s.client.DB.Exec(insertQuery, id, conversationID, lastCustomerMessageID, agentID, suggestionsData, contextUsed, now, now)
 	if err != nil {
 		return fmt.Errorf("failed to save suggestions cache: %w", err)
 	}
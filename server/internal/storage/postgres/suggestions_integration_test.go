@@ -0,0 +1,39 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSuggestionsStorageKeysByProfiledAgent(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewSuggestionsStorage(testClient)
+	tenantID := newIsolatedTenant(t, "pagination", "conversations")
+	conversationID := uuid.New().String()
+	createConversationAt(t, conversations, tenantID, conversationID, nil, time.Now().UTC())
+	t.Cleanup(func() { testClient.DB.Exec("DELETE FROM suggestions WHERE conversation_id = $1", conversationID) })
+
+	if err := storage.SaveSuggestions(conversationID, "msg-1", "", `[{"id":"plain"}]`, false); err != nil {
+		t.Fatalf("SaveSuggestions: %v", err)
+	}
+	if err := storage.SaveSuggestions(conversationID, "msg-1", "agent-1", `[{"id":"personal"}]`, true); err != nil {
+		t.Fatalf("SaveSuggestions(agent-1): %v", err)
+	}
+
+	// Saving agent-1's suggestions didn't replace the unpersonalized ones
+	plain, err := storage.GetSuggestions(conversationID, "msg-1", "")
+	if err != nil || plain == nil || plain.SuggestionsData != `[{"id":"plain"}]` || plain.AgentID != "" {
+		t.Errorf("unpersonalized = %+v (%v), want the plain suggestions", plain, err)
+	}
+	personal, err := storage.GetSuggestions(conversationID, "msg-1", "agent-1")
+	if err != nil || personal == nil || personal.SuggestionsData != `[{"id":"personal"}]` || personal.AgentID != "agent-1" {
+		t.Errorf("agent-1 = %+v (%v), want the personalized suggestions", personal, err)
+	}
+	if other, err := storage.GetSuggestions(conversationID, "msg-1", "agent-2"); err != nil || other != nil {
+		t.Errorf("agent-2 = %+v (%v), want nothing cached", other, err)
+	}
+}
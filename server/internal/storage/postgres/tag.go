@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ErrTagExists is returned when a tenant already has a tag with the same name
+var ErrTagExists = errors.New("tag already exists")
+
+// TagStorage handles tenant tags and the conversations they are attached to
+type TagStorage struct {
+	client *Client
+}
+
+// NewTagStorage creates a new tag storage instance
+func NewTagStorage(client *Client) *TagStorage {
+	return &TagStorage{client: client}
+}
+
+// CreateTag stores a new tag. Names are unique per tenant.
+func (s *TagStorage) CreateTag(tag *models.Tag) error {
+	if tag.ID == "" {
+		tag.ID = uuid.New().String()
+	}
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = time.Now()
+	}
+	if err := s.checkNameAvailable(tag.TenantID, tag.Name, tag.ID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tags (id, tenant_id, name, color, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := s.client.DB.Exec(query, tag.ID, tag.TenantID, tag.Name, tag.Color, tag.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// GetTag retrieves a tenant's tag by ID
+func (s *TagStorage) GetTag(tenantID, tagID string) (*models.Tag, error) {
+	tag := &models.Tag{}
+	err := s.client.DB.QueryRow(
+		"SELECT id, tenant_id, name, color, created_at FROM tags WHERE id = $1 AND tenant_id = $2",
+		tagID, tenantID,
+	).Scan(&tag.ID, &tag.TenantID, &tag.Name, &tag.Color, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return tag, nil
+}
+
+// ListTags lists a tenant's tags by name
+func (s *TagStorage) ListTags(tenantID string) ([]models.Tag, error) {
+	return queryTags(s.client.DB,
+		"SELECT id, tenant_id, name, color, created_at FROM tags WHERE tenant_id = $1 ORDER BY name ASC",
+		tenantID)
+}
+
+// UpdateTag renames or recolors a tag
+func (s *TagStorage) UpdateTag(tag *models.Tag) error {
+	if err := s.checkNameAvailable(tag.TenantID, tag.Name, tag.ID); err != nil {
+		return err
+	}
+	result, err := s.client.DB.Exec(
+		"UPDATE tags SET name = $1, color = $2 WHERE id = $3 AND tenant_id = $4",
+		tag.Name, tag.Color, tag.ID, tag.TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found")
+	}
+	return nil
+}
+
+// DeleteTag removes a tag and detaches it from every conversation
+func (s *TagStorage) DeleteTag(tenantID, tagID string) error {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM conversation_tags WHERE tag_id = $1 AND tenant_id = $2", tagID, tenantID); err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM tags WHERE id = $1 AND tenant_id = $2", tagID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found")
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// AddConversationTag attaches a tag to a conversation. Both must belong to the tenant; attaching
+// a tag twice is a no-op.
+func (s *TagStorage) AddConversationTag(tenantID, conversationID, tagID string) error {
+	if _, err := s.GetTag(tenantID, tagID); err != nil {
+		return err
+	}
+	var count int
+	err := s.client.DB.QueryRow(
+		"SELECT COUNT(*) FROM conversations WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL",
+		conversationID, tenantID,
+	).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+
+	query := `
+		INSERT INTO conversation_tags (conversation_id, tag_id, tenant_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (conversation_id, tag_id) DO NOTHING
+	`
+	if _, err := s.client.DB.Exec(query, conversationID, tagID, tenantID, time.Now()); err != nil {
+		return fmt.Errorf("failed to tag conversation: %w", err)
+	}
+	return nil
+}
+
+// RemoveConversationTag detaches a tag from a conversation
+func (s *TagStorage) RemoveConversationTag(tenantID, conversationID, tagID string) error {
+	result, err := s.client.DB.Exec(
+		"DELETE FROM conversation_tags WHERE conversation_id = $1 AND tag_id = $2 AND tenant_id = $3",
+		conversationID, tagID, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to untag conversation: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found on conversation")
+	}
+	return nil
+}
+
+// ListConversationTags lists the tags attached to a conversation by name
+func (s *TagStorage) ListConversationTags(tenantID, conversationID string) ([]models.Tag, error) {
+	return listConversationTags(s.client.DB, tenantID, conversationID)
+}
+
+// GetTagNamesByConversation maps each of the tenant's tagged conversations to its tag names
+func (s *TagStorage) GetTagNamesByConversation(tenantID string) (map[string][]string, error) {
+	query := `
+		SELECT ct.conversation_id, t.name
+		FROM conversation_tags ct
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE ct.tenant_id = $1 AND t.tenant_id = $2
+		ORDER BY ct.conversation_id, t.name
+	`
+	rows, err := s.client.DB.Query(query, tenantID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation tags: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string][]string)
+	for rows.Next() {
+		var conversationID, name string
+		if err := rows.Scan(&conversationID, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation tag: %w", err)
+		}
+		names[conversationID] = append(names[conversationID], name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conversation tags: %w", err)
+	}
+	return names, nil
+}
+
+// checkNameAvailable returns ErrTagExists when another of the tenant's tags has name
+func (s *TagStorage) checkNameAvailable(tenantID, name, tagID string) error {
+	var count int
+	err := s.client.DB.QueryRow(
+		"SELECT COUNT(*) FROM tags WHERE tenant_id = $1 AND name = $2 AND id <> $3",
+		tenantID, name, tagID,
+	).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check tag name: %w", err)
+	}
+	if count > 0 {
+		return ErrTagExists
+	}
+	return nil
+}
+
+// listConversationTags lists a conversation's tags; shared with ConversationStorage.GetConversation
+func listConversationTags(db *sql.DB, tenantID, conversationID string) ([]models.Tag, error) {
+	query := `
+		SELECT t.id, t.tenant_id, t.name, t.color, t.created_at
+		FROM conversation_tags ct
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE ct.conversation_id = $1 AND ct.tenant_id = $2 AND t.tenant_id = $3
+		ORDER BY t.name ASC
+	`
+	return queryTags(db, query, conversationID, tenantID, tenantID)
+}
+
+func queryTags(db *sql.DB, query string, args ...interface{}) ([]models.Tag, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.TenantID, &tag.Name, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+	return tags, nil
+}
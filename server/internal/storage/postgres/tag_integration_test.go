@@ -0,0 +1,153 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func createTestTag(t *testing.T, storage *TagStorage, tenantID, name string) *models.Tag {
+	t.Helper()
+	tag := &models.Tag{TenantID: tenantID, Name: name, Color: "#ff8800"}
+	if err := storage.CreateTag(tag); err != nil {
+		t.Fatalf("CreateTag(%s): %v", name, err)
+	}
+	return tag
+}
+
+func tagNames(tags []models.Tag) []string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestConversationTagsManyToMany(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewTagStorage(testClient)
+	tenantID := newIsolatedTenant(t, "tag", "conversation_tags", "tags", "conversations")
+	now := time.Now()
+	for _, id := range []string{"c1", "c2", "c3"} {
+		createConversationAt(t, conversations, tenantID, tenantID+"-"+id, nil, now)
+	}
+	conv := func(id string) string { return tenantID + "-" + id }
+
+	hot := createTestTag(t, storage, tenantID, "hot-lead")
+	pricing := createTestTag(t, storage, tenantID, "pricing-discussion")
+	if err := storage.CreateTag(&models.Tag{TenantID: tenantID, Name: "hot-lead"}); !errors.Is(err, ErrTagExists) {
+		t.Errorf("duplicate name = %v, want ErrTagExists", err)
+	}
+
+	// c1 carries both tags, c2 only hot-lead; tagging twice is a no-op
+	for _, link := range [][2]string{{"c1", hot.ID}, {"c1", pricing.ID}, {"c2", hot.ID}, {"c2", hot.ID}} {
+		if err := storage.AddConversationTag(tenantID, conv(link[0]), link[1]); err != nil {
+			t.Fatalf("AddConversationTag(%s, %s): %v", link[0], link[1], err)
+		}
+	}
+
+	got, err := conversations.GetConversation(tenantID, conv("c1"))
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if names := tagNames(got.Tags); len(names) != 2 || names[0] != "hot-lead" || names[1] != "pricing-discussion" {
+		t.Errorf("c1 tags = %v, want [hot-lead pricing-discussion]", names)
+	}
+	if tags, _ := storage.ListConversationTags(tenantID, conv("c2")); len(tags) != 1 {
+		t.Errorf("c2 tags = %v, want hot-lead once", tagNames(tags))
+	}
+
+	hotConversations, _, err := conversations.ListConversations(tenantID, &ConversationFilters{TagID: hot.ID}, 10, "")
+	if err != nil {
+		t.Fatalf("ListConversations by tag: %v", err)
+	}
+	if len(hotConversations) != 2 {
+		t.Errorf("conversations tagged hot-lead = %d, want 2", len(hotConversations))
+	}
+
+	names, err := storage.GetTagNamesByConversation(tenantID)
+	if err != nil {
+		t.Fatalf("GetTagNamesByConversation: %v", err)
+	}
+	if len(names[conv("c1")]) != 2 || len(names[conv("c2")]) != 1 || names[conv("c3")] != nil {
+		t.Errorf("tag names = %v", names)
+	}
+
+	// Removing a link leaves the tag and other links alone
+	if err := storage.RemoveConversationTag(tenantID, conv("c1"), hot.ID); err != nil {
+		t.Fatalf("RemoveConversationTag: %v", err)
+	}
+	if err := storage.RemoveConversationTag(tenantID, conv("c1"), hot.ID); err == nil {
+		t.Error("removing a missing link should fail")
+	}
+	if tags, _ := storage.ListConversationTags(tenantID, conv("c2")); len(tags) != 1 {
+		t.Errorf("c2 lost its tag when c1 was untagged")
+	}
+
+	// Deleting a tag detaches it from every conversation
+	if err := storage.DeleteTag(tenantID, pricing.ID); err != nil {
+		t.Fatalf("DeleteTag: %v", err)
+	}
+	if tags, _ := storage.ListConversationTags(tenantID, conv("c1")); len(tags) != 0 {
+		t.Errorf("c1 tags after delete = %v, want none", tagNames(tags))
+	}
+	var links int
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM conversation_tags WHERE tag_id = $1", pricing.ID).Scan(&links)
+	if links != 0 {
+		t.Errorf("links to deleted tag = %d, want 0", links)
+	}
+
+	// Hard-deleting a conversation removes its links
+	if err := conversations.HardDeleteConversation(tenantID, conv("c2")); err != nil {
+		t.Fatalf("HardDeleteConversation: %v", err)
+	}
+	testClient.DB.QueryRow("SELECT COUNT(*) FROM conversation_tags WHERE conversation_id = $1", conv("c2")).Scan(&links)
+	if links != 0 {
+		t.Errorf("links of deleted conversation = %d, want 0", links)
+	}
+}
+
+func TestConversationTagsTenantScoping(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	storage := NewTagStorage(testClient)
+	tenantID := newIsolatedTenant(t, "tag", "conversation_tags", "tags", "conversations")
+	otherTenant := newIsolatedTenant(t, "tag", "conversation_tags", "tags", "conversations")
+	createConversationAt(t, conversations, tenantID, tenantID+"-c1", nil, time.Now())
+	createConversationAt(t, conversations, otherTenant, otherTenant+"-c1", nil, time.Now())
+
+	mine := createTestTag(t, storage, tenantID, "escalated")
+	theirs := createTestTag(t, storage, otherTenant, "escalated") // Same name is fine in another tenant
+
+	if err := storage.AddConversationTag(tenantID, tenantID+"-c1", theirs.ID); err == nil {
+		t.Error("attaching another tenant's tag should fail")
+	}
+	if err := storage.AddConversationTag(tenantID, otherTenant+"-c1", mine.ID); err == nil {
+		t.Error("tagging another tenant's conversation should fail")
+	}
+	if _, err := storage.GetTag(otherTenant, mine.ID); err == nil {
+		t.Error("GetTag from another tenant should fail")
+	}
+	if err := storage.UpdateTag(&models.Tag{ID: mine.ID, TenantID: otherTenant, Name: "renamed"}); err == nil {
+		t.Error("UpdateTag from another tenant should fail")
+	}
+	if err := storage.DeleteTag(otherTenant, mine.ID); err == nil {
+		t.Error("DeleteTag from another tenant should fail")
+	}
+
+	if err := storage.AddConversationTag(tenantID, tenantID+"-c1", mine.ID); err != nil {
+		t.Fatalf("AddConversationTag: %v", err)
+	}
+	if err := storage.RemoveConversationTag(otherTenant, tenantID+"-c1", mine.ID); err == nil {
+		t.Error("RemoveConversationTag from another tenant should fail")
+	}
+	if listed, _, _ := conversations.ListConversations(otherTenant, &ConversationFilters{TagID: mine.ID}, 10, ""); len(listed) != 0 {
+		t.Errorf("other tenant listed %d conversations by our tag, want 0", len(listed))
+	}
+	if tags, _ := storage.ListTags(otherTenant); len(tags) != 1 || tags[0].ID != theirs.ID {
+		t.Errorf("other tenant tags = %v, want only its own", tagNames(tags))
+	}
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"fmt"
+)
+
+// tenantConversationIDs selects the IDs of a tenant's conversations, including soft-deleted ones
+const tenantConversationIDs = "SELECT id FROM conversations WHERE tenant_id = $1"
+
+// tenantDataTables lists every table holding tenant data with the condition selecting a tenant's
+// rows ($1 is the tenant ID). Tables are deleted in this order, children before the rows they
+// reference, so foreign keys hold whether or not they are enforced.
+var tenantDataTables = []struct {
+	table string
+	where string
+}{
+	// Conversation data
+	{"suggestions", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"suggestion_feedback", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"auto_reply_conversations", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"conversation_metadata", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"conversation_analysis_history", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"conversation_summaries", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"conversation_notes", "tenant_id = $1"},
+	{"message_reads", "message_id IN (SELECT id FROM messages WHERE conversation_id IN (" + tenantConversationIDs + "))"},
+	{"messages", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"message_deletions", "tenant_id = $1"},
+	{"transfer_events", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"lead_stage_transitions", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"hot_lead_alerts", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"escalation_events", "tenant_id = $1"},
+	{"knowledge_gaps", "tenant_id = $1"},
+	{"moderation_events", "tenant_id = $1"},
+	{"pricing_suggestions", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"watchlist", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"sla_breaches", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"conversation_tags", "conversation_id IN (" + tenantConversationIDs + ")"},
+	{"objection_resolutions", "tenant_id = $1"},
+	{"product_recommendations", "tenant_id = $1"},
+	{"conversations", "tenant_id = $1"},
+	{"tags", "tenant_id = $1"},
+	{"customer_memory", "tenant_id = $1"},
+
+	// Product knowledge
+	{"knowledge_article_versions", "article_id IN (SELECT id FROM knowledge_articles WHERE tenant_id = $1)"},
+	{"knowledge_articles", "tenant_id = $1"},
+	{"product_variants", "tenant_id = $1"},
+	{"product_sla_config", "tenant_id = $1"},
+	{"products", "tenant_id = $1"},
+	{"product_categories", "tenant_id = $1"},
+
+	// Tenant configuration
+	{"rules", "tenant_id = $1"},
+	{"brand_tone", "tenant_id = $1"},
+	{"auto_reply_global", "tenant_id = $1"},
+	{"cors_config", "tenant_id = $1"},
+	{"tenant_api_credentials", "tenant_id = $1"},
+	{"tenant_slack_config", "tenant_id = $1"},
+	{"tenant_ai_config", "tenant_id = $1"},
+	{"tenant_analytics_config", "tenant_id = $1"},
+	{"tenant_sla_config", "tenant_id = $1"},
+	{"crm_field_mappings", "tenant_id = $1"},
+	{"webhooks", "tenant_id = $1"},
+	{"routing_rules", "tenant_id = $1"},
+	{"moderation_lists", "tenant_id = $1"},
+	{"business_hours_config", "tenant_id = $1"},
+
+	// Activity records
+	{"notifications", "tenant_id = $1"},
+	{"audit_logs", "tenant_id = $1"},
+	{"ai_usage_events", "tenant_id = $1"},
+	{"agent_suggestion_profiles", "tenant_id = $1"},
+
+	// Accounts
+	{"user_invitations", "tenant_id = $1"},
+	{"refresh_tokens", "tenant_id = $1"},
+	{"users", "tenant_id = $1"},
+}
+
+// TenantDataStorage erases all of a tenant's data
+type TenantDataStorage struct {
+	client *Client
+}
+
+// NewTenantDataStorage creates a new tenant data storage instance
+func NewTenantDataStorage(client *Client) *TenantDataStorage {
+	return &TenantDataStorage{client: client}
+}
+
+// TenantDataTables returns the tables DeleteTenantData deletes from, in deletion order
+func TenantDataTables() []string {
+	tables := make([]string, len(tenantDataTables))
+	for i, t := range tenantDataTables {
+		tables[i] = t.table
+	}
+	return tables
+}
+
+// DeleteTenantData permanently deletes every row belonging to a tenant in one transaction.
+// Returns the number of rows deleted per table; deleting an unknown tenant deletes nothing.
+func (s *TenantDataStorage) DeleteTenantData(tenantID string) (map[string]int64, error) {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	counts := make(map[string]int64, len(tenantDataTables))
+	for _, t := range tenantDataTables {
+		result, err := tx.Exec("DELETE FROM "+t.table+" WHERE "+t.where, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", t.table, err)
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		counts[t.table] = deleted
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tenant data deletion: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,169 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seedTenantData inserts one row for the tenant into every table DeleteTenantData covers
+func seedTenantData(t *testing.T, tenantID string) {
+	t.Helper()
+	id := func() string { return uuid.New().String() }
+	conv, msg, user, tag, article, category, product := id(), id(), id(), id(), id(), id(), id()
+	now := time.Now().UTC()
+
+	rows := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"INSERT INTO users (id, tenant_id, email, password_hash, role) VALUES ($1, $2, $3, $4, $5)", []interface{}{user, tenantID, user + "@example.com", "hash", "agent"}},
+		{"INSERT INTO refresh_tokens (jti, user_id, tenant_id, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), user, tenantID, id(), now.Add(time.Hour)}},
+		{"INSERT INTO user_invitations (id, tenant_id, email, role, invited_by, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)", []interface{}{id(), tenantID, "invitee-" + user + "@example.com", "agent", user, id(), now.Add(time.Hour)}},
+		{"INSERT INTO conversations (id, tenant_id, status) VALUES ($1, $2, $3)", []interface{}{conv, tenantID, "active"}},
+		{"INSERT INTO messages (id, conversation_id, sender, content) VALUES ($1, $2, $3, $4)", []interface{}{msg, conv, "customer", "hello"}},
+		{"INSERT INTO message_reads (message_id, reader_id, tenant_id) VALUES ($1, $2, $3)", []interface{}{msg, user, tenantID}},
+		{"INSERT INTO message_deletions (id, message_id, conversation_id, tenant_id, deleted_by, reason) VALUES ($1, $2, $3, $4, $5, $6)", []interface{}{id(), id(), conv, tenantID, user, "gdpr_request"}},
+		{"INSERT INTO suggestions (id, conversation_id, last_customer_message_id, suggestions_data) VALUES ($1, $2, $3, $4)", []interface{}{id(), conv, msg, "[]"}},
+		{"INSERT INTO suggestion_feedback (id, suggestion_id, conversation_id, tenant_id, action) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), id(), conv, tenantID, "accepted"}},
+		{"INSERT INTO auto_reply_conversations (conversation_id) VALUES ($1)", []interface{}{conv}},
+		{"INSERT INTO conversation_metadata (id, conversation_id) VALUES ($1, $2)", []interface{}{id(), conv}},
+		{"INSERT INTO conversation_analysis_history (id, conversation_id) VALUES ($1, $2)", []interface{}{id(), conv}},
+		{"INSERT INTO conversation_summaries (conversation_id, summary_text, message_count_when_generated) VALUES ($1, $2, $3)", []interface{}{conv, "- Asked about pricing", 2}},
+		{"INSERT INTO conversation_notes (id, conversation_id, tenant_id, agent_id, content) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), conv, tenantID, user, "Prefers email"}},
+		{"INSERT INTO transfer_events (id, conversation_id, tenant_id, to_agent_id, transferred_by) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), conv, tenantID, user, user}},
+		{"INSERT INTO lead_stage_transitions (id, conversation_id, tenant_id, to_stage) VALUES ($1, $2, $3, $4)", []interface{}{id(), conv, tenantID, "qualified"}},
+		{"INSERT INTO hot_lead_alerts (id, conversation_id, tenant_id, reason) VALUES ($1, $2, $3, $4)", []interface{}{id(), conv, tenantID, "high intent"}},
+		{"INSERT INTO escalation_events (id, conversation_id, tenant_id, sentiment_score, reason) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), conv, tenantID, 0.1, "sentiment"}},
+		{"INSERT INTO knowledge_gaps (id, tenant_id, query_text, conversation_id) VALUES ($1, $2, $3, $4)", []interface{}{id(), tenantID, "do you ship to Canada?", conv}},
+		{"INSERT INTO moderation_events (id, tenant_id, conversation_id, message_id, sender, action) VALUES ($1, $2, $3, $4, $5, $6)", []interface{}{id(), tenantID, conv, id(), "customer", "flag"}},
+		{"INSERT INTO pricing_suggestions (id, conversation_id, tenant_id, min_price, max_price) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), conv, tenantID, 10.0, 20.0}},
+		{"INSERT INTO watchlist (id, conversation_id, tenant_id, added_by) VALUES ($1, $2, $3, $4)", []interface{}{id(), conv, tenantID, user}},
+		{"INSERT INTO sla_breaches (id, tenant_id, conversation_id, customer_message_id, expected_response_by) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), tenantID, conv, msg, now}},
+		{"INSERT INTO tags (id, tenant_id, name) VALUES ($1, $2, $3)", []interface{}{tag, tenantID, "vip"}},
+		{"INSERT INTO conversation_tags (conversation_id, tag_id, tenant_id) VALUES ($1, $2, $3)", []interface{}{conv, tag, tenantID}},
+		{"INSERT INTO objection_resolutions (id, tenant_id, conversation_id, objection_type) VALUES ($1, $2, $3, $4)", []interface{}{id(), tenantID, conv, "price"}},
+		{"INSERT INTO customer_memory (id, tenant_id, customer_id) VALUES ($1, $2, $3)", []interface{}{id(), tenantID, id()}},
+		{"INSERT INTO knowledge_articles (id, tenant_id, title, content) VALUES ($1, $2, $3, $4)", []interface{}{article, tenantID, "FAQ", "Answers"}},
+		{"INSERT INTO knowledge_article_versions (id, article_id, title, content, version) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), article, "FAQ", "Old answers", 1}},
+		{"INSERT INTO product_categories (id, tenant_id, name) VALUES ($1, $2, $3)", []interface{}{category, tenantID, "Plans"}},
+		{"INSERT INTO products (id, tenant_id, name, description, price, category_id) VALUES ($1, $2, $3, $4, $5, $6)", []interface{}{product, tenantID, "Plan", "A plan", 99.0, category}},
+		{"INSERT INTO product_recommendations (id, tenant_id, conversation_id, suggestion_id, product_id) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), tenantID, conv, id(), product}},
+		{"INSERT INTO product_variants (id, product_id, tenant_id, name, price) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), product, tenantID, "Annual", 990.0}},
+		{"INSERT INTO product_sla_config (product_id, tenant_id, first_response_minutes) VALUES ($1, $2, $3)", []interface{}{product, tenantID, 15}},
+		{"INSERT INTO rules (id, tenant_id, name, type, pattern, action) VALUES ($1, $2, $3, $4, $5, $6)", []interface{}{id(), tenantID, "No promises", "compliance", "guarantee", "flag"}},
+		{"INSERT INTO brand_tone (tenant_id, tone) VALUES ($1, $2)", []interface{}{tenantID, "Friendly"}},
+		{"INSERT INTO auto_reply_global (tenant_id) VALUES ($1)", []interface{}{tenantID}},
+		{"INSERT INTO cors_config (tenant_id) VALUES ($1)", []interface{}{tenantID}},
+		{"INSERT INTO tenant_api_credentials (tenant_id, provider, api_key_encrypted) VALUES ($1, $2, $3)", []interface{}{tenantID, "gemini", "encrypted"}},
+		{"INSERT INTO tenant_slack_config (tenant_id, webhook_url) VALUES ($1, $2)", []interface{}{tenantID, "https://hooks.slack.com/x"}},
+		{"INSERT INTO tenant_ai_config (tenant_id) VALUES ($1)", []interface{}{tenantID}},
+		{"INSERT INTO tenant_analytics_config (tenant_id, config_json) VALUES ($1, $2)", []interface{}{tenantID, "{}"}},
+		{"INSERT INTO moderation_lists (id, tenant_id, list_type, pattern) VALUES ($1, $2, $3, $4)", []interface{}{id(), tenantID, "block", "crypto giveaway"}},
+		{"INSERT INTO tenant_sla_config (tenant_id, response_threshold_minutes) VALUES ($1, $2)", []interface{}{tenantID, 30}},
+		{"INSERT INTO crm_field_mappings (tenant_id, crm_type) VALUES ($1, $2)", []interface{}{tenantID, "hubspot"}},
+		{"INSERT INTO webhooks (id, tenant_id, url, secret) VALUES ($1, $2, $3, $4)", []interface{}{id(), tenantID, "https://example.com/hook", "secret"}},
+		{"INSERT INTO routing_rules (id, tenant_id, action) VALUES ($1, $2, $3)", []interface{}{id(), tenantID, `{"type": "assign_agent", "agent_id": "agent-1"}`}},
+		{"INSERT INTO business_hours_config (tenant_id, timezone) VALUES ($1, $2)", []interface{}{tenantID, "UTC"}},
+		{"INSERT INTO notifications (id, tenant_id, channel, payload, next_attempt_at) VALUES ($1, $2, $3, $4, $5)", []interface{}{id(), tenantID, "slack", "{}", now}},
+		{"INSERT INTO audit_logs (id, tenant_id, action, resource_type) VALUES ($1, $2, $3, $4)", []interface{}{id(), tenantID, "update", "rule"}},
+		{"INSERT INTO ai_usage_events (id, tenant_id, operation) VALUES ($1, $2, $3)", []interface{}{id(), tenantID, "suggestions"}},
+		{"INSERT INTO agent_suggestion_profiles (tenant_id, agent_id, preferred_tone) VALUES ($1, $2, $3)", []interface{}{tenantID, user, "friendly"}},
+	}
+	for _, row := range rows {
+		if _, err := testClient.DB.Exec(row.query, row.args...); err != nil {
+			t.Fatalf("seed %q: %v", row.query, err)
+		}
+	}
+}
+
+// tableRowCounts returns the total number of rows in each tenant data table
+func tableRowCounts(t *testing.T) map[string]int64 {
+	t.Helper()
+	counts := map[string]int64{}
+	for _, table := range TenantDataTables() {
+		var n int64
+		if err := testClient.DB.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		counts[table] = n
+	}
+	return counts
+}
+
+func TestDeleteTenantDataEmptiesEveryTable(t *testing.T) {
+	storage := NewTenantDataStorage(testClient)
+	erased := "erase-" + uuid.New().String()
+	kept := "keep-" + uuid.New().String()
+	seedTenantData(t, erased)
+	seedTenantData(t, kept)
+	t.Cleanup(func() { storage.DeleteTenantData(kept) })
+
+	before := tableRowCounts(t)
+	report, err := storage.DeleteTenantData(erased)
+	if err != nil {
+		t.Fatalf("DeleteTenantData: %v", err)
+	}
+	after := tableRowCounts(t)
+
+	for _, table := range TenantDataTables() {
+		if report[table] != 1 {
+			t.Errorf("%s: deleted %d rows, want the 1 seeded row", table, report[table])
+		}
+		// Only the erased tenant's row is gone; the other tenant's row remains
+		if after[table] != before[table]-1 {
+			t.Errorf("%s: %d rows before, %d after; want exactly one fewer", table, before[table], after[table])
+		}
+	}
+
+	// Erasing again is a no-op
+	again, err := storage.DeleteTenantData(erased)
+	if err != nil {
+		t.Fatalf("second DeleteTenantData: %v", err)
+	}
+	for table, n := range again {
+		if n != 0 {
+			t.Errorf("%s: second deletion removed %d rows", table, n)
+		}
+	}
+
+	keptCounts, err := storage.DeleteTenantData(kept)
+	if err != nil {
+		t.Fatalf("DeleteTenantData(kept): %v", err)
+	}
+	if keptCounts["conversations"] != 1 || keptCounts["users"] != 1 {
+		t.Errorf("other tenant's data = %v, want it untouched until erased", keptCounts)
+	}
+}
+
+func TestTenantDataTablesCoverEveryTenantTable(t *testing.T) {
+	covered := map[string]bool{}
+	for _, table := range TenantDataTables() {
+		covered[table] = true
+	}
+
+	// Any table with a tenant_id column holds tenant data and must be erased with it
+	var query string
+	if testClient.DBType == "sqlite" {
+		query = "SELECT m.name FROM sqlite_master m, pragma_table_info(m.name) p WHERE m.type = 'table' AND p.name = 'tenant_id'"
+	} else {
+		query = "SELECT table_name FROM information_schema.columns WHERE table_schema = current_schema() AND column_name = 'tenant_id'"
+	}
+	rows, err := testClient.DB.Query(query)
+	if err != nil {
+		t.Fatalf("list tenant tables: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		if !covered[table] {
+			t.Errorf("table %s has a tenant_id column but isn't erased by DeleteTenantData", table)
+		}
+	}
+}
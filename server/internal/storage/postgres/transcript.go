@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTranscriptAlreadySent is returned when a conversation's transcript has already been emailed
+var ErrTranscriptAlreadySent = errors.New("transcript already sent for this conversation")
+
+// ClaimTranscriptSend marks a conversation's transcript as sent, failing with ErrTranscriptAlreadySent
+// if it was sent before. Claiming before sending keeps concurrent requests from sending twice.
+func (s *ConversationStorage) ClaimTranscriptSend(tenantID, conversationID string, sentAt time.Time) error {
+	result, err := s.client.DB.Exec(`
+		UPDATE conversations
+		SET transcript_sent_at = $1
+		WHERE id = $2 AND tenant_id = $3 AND transcript_sent_at IS NULL
+	`, sentAt, conversationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to mark transcript sent: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := s.GetConversation(tenantID, conversationID); err != nil {
+			return err
+		}
+		return ErrTranscriptAlreadySent
+	}
+	return nil
+}
+
+// ReleaseTranscriptSend clears a claim made by ClaimTranscriptSend after a failed send
+func (s *ConversationStorage) ReleaseTranscriptSend(tenantID, conversationID string) error {
+	_, err := s.client.DB.Exec(`
+		UPDATE conversations
+		SET transcript_sent_at = NULL
+		WHERE id = $1 AND tenant_id = $2
+	`, conversationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to clear transcript sent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClaimTranscriptSendOnce(t *testing.T) {
+	storage := NewConversationStorage(testClient)
+	conv := newTestConversation(t, storage, nil, "closed")
+
+	if err := storage.ClaimTranscriptSend(testTenantID, conv.ID, time.Now()); err != nil {
+		t.Fatalf("first ClaimTranscriptSend: %v", err)
+	}
+	if err := storage.ClaimTranscriptSend(testTenantID, conv.ID, time.Now()); !errors.Is(err, ErrTranscriptAlreadySent) {
+		t.Errorf("second ClaimTranscriptSend = %v, want ErrTranscriptAlreadySent", err)
+	}
+
+	got, err := storage.GetConversation(testTenantID, conv.ID)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if got.TranscriptSentAt == nil {
+		t.Error("TranscriptSentAt not set after claim")
+	}
+
+	// A released claim (failed send) can be claimed again
+	if err := storage.ReleaseTranscriptSend(testTenantID, conv.ID); err != nil {
+		t.Fatalf("ReleaseTranscriptSend: %v", err)
+	}
+	if err := storage.ClaimTranscriptSend(testTenantID, conv.ID, time.Now()); err != nil {
+		t.Errorf("ClaimTranscriptSend after release: %v", err)
+	}
+
+	if err := storage.ClaimTranscriptSend("other-tenant", conv.ID, time.Now()); err == nil || err.Error() != "conversation not found" {
+		t.Errorf("ClaimTranscriptSend from another tenant = %v, want conversation not found", err)
+	}
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// ErrInvalidAssignee is returned when a conversation is assigned to a user who isn't an active agent
+// or admin of the conversation's tenant
+var ErrInvalidAssignee = errors.New("assignee must be an active agent or admin in the tenant")
+
+// AssignAgent sets the agent responsible for a conversation; an empty agentID unassigns it. The agent
+// must be an active agent or admin of the same tenant (tenant-scoped).
+func (s *ConversationStorage) AssignAgent(tenantID, conversationID, agentID string) error {
+	var assignee interface{}
+	if agentID != "" {
+		var role string
+		err := s.client.DB.QueryRow(
+			"SELECT role FROM users WHERE id = $1 AND tenant_id = $2 AND deactivated_at IS NULL",
+			agentID, tenantID,
+		).Scan(&role)
+		if err == sql.ErrNoRows {
+			return ErrInvalidAssignee
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get assignee: %w", err)
+		}
+		if role != string(models.RoleAgent) && role != string(models.RoleAdmin) {
+			return ErrInvalidAssignee
+		}
+		assignee = agentID
+	}
+
+	query := `
+		UPDATE conversations
+		SET assigned_agent_id = $1, updated_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND deleted_at IS NULL
+	`
+	result, err := s.client.DB.Exec(query, assignee, time.Now(), conversationID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to assign agent: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+	return nil
+}
+
+// GetAssignedAgent returns the agent currently assigned to a conversation, or nil if unassigned
+func (s *ConversationStorage) GetAssignedAgent(tenantID, conversationID string) (*string, error) {
+	query := `
+		SELECT assigned_agent_id
+		FROM conversations
+		WHERE id = $1 AND tenant_id = $2
+	`
+	var agentID sql.NullString
+	err := s.client.DB.QueryRow(query, conversationID, tenantID).Scan(&agentID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("conversation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned agent: %w", err)
+	}
+	if !agentID.Valid || agentID.String == "" {
+		return nil, nil
+	}
+	return &agentID.String, nil
+}
+
+// TransferConversation reassigns a conversation to another agent and records the transfer in one
+// transaction, so an agent is never reassigned without a transfer event. Returns the recorded event.
+func (s *ConversationStorage) TransferConversation(tenantID, conversationID, toAgentID, reason, transferredBy string) (*models.TransferEvent, error) {
+	tx, err := s.client.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var assigned sql.NullString
+	err = tx.QueryRow("SELECT assigned_agent_id FROM conversations WHERE id = $1 AND tenant_id = $2", conversationID, tenantID).Scan(&assigned)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("conversation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned agent: %w", err)
+	}
+	var fromAgentID *string
+	if assigned.Valid && assigned.String != "" {
+		fromAgentID = &assigned.String
+	}
+	if fromAgentID != nil && *fromAgentID == toAgentID {
+		return nil, fmt.Errorf("conversation is already assigned to this agent")
+	}
+
+	event := &models.TransferEvent{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		TenantID:       tenantID,
+		FromAgentID:    fromAgentID,
+		ToAgentID:      toAgentID,
+		Reason:         reason,
+		TransferredBy:  transferredBy,
+		TransferredAt:  time.Now(),
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE conversations SET assigned_agent_id = $1, updated_at = $2 WHERE id = $3 AND tenant_id = $4",
+		toAgentID, event.TransferredAt, conversationID, tenantID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to assign conversation: %w", err)
+	}
+
+	query := `
+		INSERT INTO transfer_events (id, conversation_id, tenant_id, from_agent_id, to_agent_id, reason, transferred_by, transferred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = tx.Exec(query, event.ID, event.ConversationID, tenantID, event.FromAgentID,
+		event.ToAgentID, event.Reason, event.TransferredBy, event.TransferredAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record transfer event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return event, nil
+}
+
+// GetTransferHistory lists the transfers of a conversation ordered by transferred_at (tenant-scoped)
+func (s *ConversationStorage) GetTransferHistory(tenantID, conversationID string) ([]*models.TransferEvent, error) {
+	query := `
+		SELECT id, conversation_id, tenant_id, from_agent_id, to_agent_id, reason, transferred_by, transferred_at
+		FROM transfer_events
+		WHERE conversation_id = $1 AND tenant_id = $2
+		ORDER BY transferred_at ASC
+	`
+	rows, err := s.client.DB.Query(query, conversationID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.TransferEvent{}
+	for rows.Next() {
+		event := &models.TransferEvent{}
+		var fromAgentID sql.NullString
+		var reason sql.NullString
+		if err := rows.Scan(
+			&event.ID, &event.ConversationID, &event.TenantID, &fromAgentID, &event.ToAgentID,
+			&reason, &event.TransferredBy, &event.TransferredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer event: %w", err)
+		}
+		if fromAgentID.Valid {
+			event.FromAgentID = &fromAgentID.String
+		}
+		event.Reason = reason.String
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfer events: %w", err)
+	}
+	return events, nil
+}
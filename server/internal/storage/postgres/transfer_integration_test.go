@@ -0,0 +1,69 @@
+//go:build integration
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func TestTransferConversationRecordsEvent(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	first := newTestUser(t, users, "transfer.first@example.com", models.RoleAgent)
+	second := newTestUser(t, users, "transfer.second@example.com", models.RoleAgent)
+	conv := newTestConversation(t, storage, nil, "active")
+	if err := storage.AssignAgent(testTenantID, conv.ID, first.ID); err != nil {
+		t.Fatalf("AssignAgent: %v", err)
+	}
+
+	event, err := storage.TransferConversation(testTenantID, conv.ID, second.ID, "needs pricing help", first.ID)
+	if err != nil {
+		t.Fatalf("TransferConversation: %v", err)
+	}
+	if event.FromAgentID == nil || *event.FromAgentID != first.ID || event.ToAgentID != second.ID {
+		t.Errorf("event = %+v, want a transfer from the first to the second agent", event)
+	}
+	if assigned, err := storage.GetAssignedAgent(testTenantID, conv.ID); err != nil || assigned == nil || *assigned != second.ID {
+		t.Errorf("assigned agent = %v (%v), want the second agent", assigned, err)
+	}
+	history, err := storage.GetTransferHistory(testTenantID, conv.ID)
+	if err != nil || len(history) != 1 || history[0].ID != event.ID || history[0].Reason != "needs pricing help" {
+		t.Errorf("history = %+v (%v), want the recorded transfer", history, err)
+	}
+
+	if _, err := storage.TransferConversation(testTenantID, conv.ID, second.ID, "", first.ID); err == nil || !strings.Contains(err.Error(), "already assigned") {
+		t.Errorf("transfer to the current agent = %v, want already assigned", err)
+	}
+	if _, err := storage.TransferConversation("other-tenant", conv.ID, first.ID, "", first.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("transfer from another tenant = %v, want not found", err)
+	}
+}
+
+func TestTransferConversationRollsBackWithoutEvent(t *testing.T) {
+	users := NewUserStorage(testClient)
+	storage := NewConversationStorage(testClient)
+	first := newTestUser(t, users, "rollback.first@example.com", models.RoleAgent)
+	second := newTestUser(t, users, "rollback.second@example.com", models.RoleAgent)
+	conv := newTestConversation(t, storage, nil, "active")
+	if err := storage.AssignAgent(testTenantID, conv.ID, first.ID); err != nil {
+		t.Fatalf("AssignAgent: %v", err)
+	}
+
+	// Recording the event fails, so the reassignment must not be kept
+	if _, err := testClient.DB.Exec("ALTER TABLE transfer_events RENAME TO transfer_events_unavailable"); err != nil {
+		t.Fatalf("rename transfer_events: %v", err)
+	}
+	_, err := storage.TransferConversation(testTenantID, conv.ID, second.ID, "", first.ID)
+	if _, restoreErr := testClient.DB.Exec("ALTER TABLE transfer_events_unavailable RENAME TO transfer_events"); restoreErr != nil {
+		t.Fatalf("restore transfer_events: %v", restoreErr)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failed to record transfer event") {
+		t.Fatalf("TransferConversation = %v, want the event insert to fail", err)
+	}
+	if assigned, err := storage.GetAssignedAgent(testTenantID, conv.ID); err != nil || assigned == nil || *assigned != first.ID {
+		t.Errorf("assigned agent = %v (%v), want the first agent kept", assigned, err)
+	}
+}
@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,15 @@ import (
 	"ai-conversation-platform/internal/models"
 )
 
+// ErrLastAdmin is returned when a change would leave a tenant without an active admin
+var ErrLastAdmin = errors.New("cannot remove the last admin")
+
+// UserFilter narrows a user search; empty fields match everything
+type UserFilter struct {
+	Role          string
+	EmailContains string
+}
+
 // UserStorage handles user-related database operations
 type UserStorage struct {
 	client *Client
@@ -20,6 +31,25 @@ func NewUserStorage(client *Client) *UserStorage {
 	return &UserStorage{client: client}
 }
 
+// scanUser scans a user row; extra destinations are scanned after the user columns
+func scanUser(row rowScanner, extra ...interface{}) (*models.User, error) {
+	user := &models.User{}
+	var roleStr string
+	var deactivatedAt sql.NullTime
+	dest := append([]interface{}{
+		&user.ID, &user.TenantID, &user.Email, &user.PasswordHash,
+		&roleStr, &user.CreatedAt, &user.UpdatedAt, &deactivatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	user.Role = models.UserRole(roleStr)
+	if deactivatedAt.Valid {
+		user.DeactivatedAt = &deactivatedAt.Time
+	}
+	return user, nil
+}
+
 // CreateUser creates a new user
 func (s *UserStorage) CreateUser(tenantID string, user *models.User) error {
 	query := `
@@ -39,50 +69,34 @@ func (s *UserStorage) CreateUser(tenantID string, user *models.User) error {
 // GetUser retrieves a user by ID (tenant-scoped)
 func (s *UserStorage) GetUser(tenantID, userID string) (*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at, deactivated_at
 		FROM users
 		WHERE id = $1 AND tenant_id = $2
 	`
-	user := &models.User{}
-	var roleStr string
-
-	err := s.client.DB.QueryRow(query, userID, tenantID).Scan(
-		&user.ID, &user.TenantID, &user.Email, &user.PasswordHash,
-		&roleStr, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(s.client.DB.QueryRow(query, userID, tenantID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-
-	user.Role = models.UserRole(roleStr)
 	return user, nil
 }
 
 // GetUserByEmail retrieves a user by email (tenant-scoped)
 func (s *UserStorage) GetUserByEmail(tenantID, email string) (*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at, deactivated_at
 		FROM users
 		WHERE email = $1 AND tenant_id = $2
 	`
-	user := &models.User{}
-	var roleStr string
-
-	err := s.client.DB.QueryRow(query, email, tenantID).Scan(
-		&user.ID, &user.TenantID, &user.Email, &user.PasswordHash,
-		&roleStr, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(s.client.DB.QueryRow(query, email, tenantID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
-
-	user.Role = models.UserRole(roleStr)
 	return user, nil
 }
 
@@ -110,35 +124,31 @@ func (s *UserStorage) UpdateUser(tenantID string, user *models.User) error {
 	return nil
 }
 
-// ListUsers lists users for a tenant with pagination
+// ListUsers lists users for a tenant with pagination, including conversation counts
 func (s *UserStorage) ListUsers(tenantID string, limit, offset int) ([]*models.User, error) {
+	return s.SearchUsers(tenantID, UserFilter{}, limit, offset)
+}
+
+// ListUsersByRole lists all active users with a role in a tenant
+func (s *UserStorage) ListUsersByRole(tenantID string, role models.UserRole) ([]*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, created_at, updated_at, deactivated_at
 		FROM users
-		WHERE tenant_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE tenant_id = $1 AND role = $2 AND deactivated_at IS NULL
+		ORDER BY created_at ASC
 	`
-	rows, err := s.client.DB.Query(query, tenantID, limit, offset)
+	rows, err := s.client.DB.Query(query, tenantID, string(role))
 	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, fmt.Errorf("failed to list users by role: %w", err)
 	}
 	defer rows.Close()
 
 	var users []*models.User
 	for rows.Next() {
-		user := &models.User{}
-		var roleStr string
-
-		err := rows.Scan(
-			&user.ID, &user.TenantID, &user.Email, &user.PasswordHash,
-			&roleStr, &user.CreatedAt, &user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
-
-		user.Role = models.UserRole(roleStr)
 		users = append(users, user)
 	}
 	if err = rows.Err(); err != nil {
@@ -174,3 +184,127 @@ func (s *UserStorage) GetOrCreateCustomerByEmail(tenantID, email string) (*model
 	return newUser, nil
 }
 
+
+// userFilterClause builds the WHERE clause for a user search; tenant_id is always $1
+func userFilterClause(tenantID string, filter UserFilter) (string, []interface{}) {
+	where := "u.tenant_id = $1"
+	args := []interface{}{tenantID}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		where += fmt.Sprintf(" AND u.role = $%d", len(args))
+	}
+	if filter.EmailContains != "" {
+		args = append(args, "%"+strings.ToLower(filter.EmailContains)+"%")
+		where += fmt.Sprintf(" AND LOWER(u.email) LIKE $%d", len(args))
+	}
+	return where, args
+}
+
+// SearchUsers lists a tenant's users matching filter, newest first. Each user's
+// ConversationCount covers conversations they are the customer on or assigned to.
+func (s *UserStorage) SearchUsers(tenantID string, filter UserFilter, limit, offset int) ([]*models.User, error) {
+	where, args := userFilterClause(tenantID, filter)
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT u.id, u.tenant_id, u.email, u.password_hash, u.role, u.created_at, u.updated_at, u.deactivated_at,
+		       COUNT(c.id)
+		FROM users u
+		LEFT JOIN conversations c ON c.tenant_id = u.tenant_id AND (c.customer_id = u.id OR c.assigned_agent_id = u.id)
+		WHERE %s
+		GROUP BY u.id, u.tenant_id, u.email, u.password_hash, u.role, u.created_at, u.updated_at, u.deactivated_at
+		ORDER BY u.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		var count int
+		user, err := scanUser(rows, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		user.ConversationCount = &count
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+	return users, nil
+}
+
+// CountUsers counts a tenant's users matching filter
+func (s *UserStorage) CountUsers(tenantID string, filter UserFilter) (int, error) {
+	where, args := userFilterClause(tenantID, filter)
+	var total int
+	if err := s.client.DB.QueryRow("SELECT COUNT(*) FROM users u WHERE "+where, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return total, nil
+}
+
+// lastAdminGuard matches unless the user is the tenant's only active admin. tenantParam is the
+// tenant's placeholder; SQLite numbers placeholders by first appearance, so it must already be in use.
+func lastAdminGuard(tenantParam string) string {
+	return `(role != 'admin' OR deactivated_at IS NOT NULL OR
+		(SELECT COUNT(*) FROM users WHERE tenant_id = ` + tenantParam + ` AND role = 'admin' AND deactivated_at IS NULL) > 1)`
+}
+
+// UpdateUserRole changes a user's role. Demoting the tenant's last active admin returns ErrLastAdmin.
+func (s *UserStorage) UpdateUserRole(tenantID, userID string, role models.UserRole) error {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND (role = $1 OR ` + lastAdminGuard("$4") + `)
+	`
+	result, err := s.client.DB.Exec(query, string(role), time.Now(), userID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	if err := s.checkLastAdminGuard(result, tenantID, userID); err != nil {
+		return err
+	}
+	// Refresh tokens issued under the old role must not be renewed
+	return revokeUserRefreshTokens(s.client.DB, tenantID, userID)
+}
+
+// DeactivateUser soft-deletes a user. Deactivating the tenant's last active admin returns ErrLastAdmin.
+func (s *UserStorage) DeactivateUser(tenantID, userID string) error {
+	query := `
+		UPDATE users
+		SET deactivated_at = $1, updated_at = $1
+		WHERE id = $2 AND tenant_id = $3 AND deactivated_at IS NULL AND ` + lastAdminGuard("$3")
+	result, err := s.client.DB.Exec(query, time.Now(), userID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	if err := s.checkLastAdminGuard(result, tenantID, userID); err != nil {
+		return err
+	}
+	// Deactivated users can't renew their access tokens
+	return revokeUserRefreshTokens(s.client.DB, tenantID, userID)
+}
+
+// checkLastAdminGuard explains why a guarded update matched no rows
+func (s *UserStorage) checkLastAdminGuard(result sql.Result, tenantID, userID string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+	user, err := s.GetUser(tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeactivatedAt != nil {
+		return fmt.Errorf("user is already deactivated")
+	}
+	return ErrLastAdmin
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+func newTestUser(t *testing.T, storage *UserStorage, email string, role models.UserRole) *models.User {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Second)
+	user := &models.User{
+		ID:        uuid.New().String(),
+		TenantID:  testTenantID,
+		Email:     email,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := storage.CreateUser(testTenantID, user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return user
+}
+
+func TestSearchUsersFiltersAndCountsConversations(t *testing.T) {
+	users := NewUserStorage(testClient)
+	conversations := NewConversationStorage(testClient)
+	customer := newTestUser(t, users, "Search.Customer@example.com", models.RoleCustomer)
+	newTestUser(t, users, "search.agent@example.com", models.RoleAgent)
+	newTestConversation(t, conversations, &customer.ID, "active")
+	newTestConversation(t, conversations, &customer.ID, "closed")
+
+	found, err := users.SearchUsers(testTenantID, UserFilter{Role: "customer", EmailContains: "search.customer"}, 20, 0)
+	if err != nil {
+		t.Fatalf("SearchUsers: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != customer.ID {
+		t.Fatalf("SearchUsers returned %+v, want only %s", found, customer.ID)
+	}
+	if found[0].ConversationCount == nil || *found[0].ConversationCount != 2 {
+		t.Errorf("conversation_count = %v, want 2", found[0].ConversationCount)
+	}
+
+	total, err := users.CountUsers(testTenantID, UserFilter{EmailContains: "search."})
+	if err != nil {
+		t.Fatalf("CountUsers: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("CountUsers = %d, want 2", total)
+	}
+}
+
+func TestLastAdminCannotBeRemoved(t *testing.T) {
+	users := NewUserStorage(testClient)
+	// Start from a clean set of admins so the count is known
+	if _, err := testClient.DB.Exec("DELETE FROM users WHERE tenant_id = $1 AND role = 'admin'", testTenantID); err != nil {
+		t.Fatalf("cleanup admins: %v", err)
+	}
+	first := newTestUser(t, users, "admin.one@example.com", models.RoleAdmin)
+	second := newTestUser(t, users, "admin.two@example.com", models.RoleAdmin)
+
+	if err := users.UpdateUserRole(testTenantID, first.ID, models.RoleAgent); err != nil {
+		t.Fatalf("demoting one of two admins: %v", err)
+	}
+	if err := users.UpdateUserRole(testTenantID, second.ID, models.RoleAgent); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("demoting the last admin = %v, want ErrLastAdmin", err)
+	}
+	if err := users.DeactivateUser(testTenantID, second.ID); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("deactivating the last admin = %v, want ErrLastAdmin", err)
+	}
+
+	// The demoted admin can be deactivated and drops out of role lookups
+	if err := users.DeactivateUser(testTenantID, first.ID); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	got, err := users.GetUser(testTenantID, first.ID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.DeactivatedAt == nil {
+		t.Error("deactivated_at not set")
+	}
+	agents, err := users.ListUsersByRole(testTenantID, models.RoleAgent)
+	if err != nil {
+		t.Fatalf("ListUsersByRole: %v", err)
+	}
+	for _, agent := range agents {
+		if agent.ID == first.ID {
+			t.Error("ListUsersByRole returned a deactivated user")
+		}
+	}
+}
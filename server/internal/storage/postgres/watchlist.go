@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-conversation-platform/internal/models"
+)
+
+// WatchlistEntry flags a conversation for close executive monitoring
+type WatchlistEntry struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AddedBy        string    `json:"added_by"`
+	Priority       int       `json:"priority"`
+	Notes          *string   `json:"notes,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// WatchlistStorage handles watchlisted conversations
+type WatchlistStorage struct {
+	client *Client
+}
+
+// NewWatchlistStorage creates a new watchlist storage instance
+func NewWatchlistStorage(client *Client) *WatchlistStorage {
+	return &WatchlistStorage{client: client}
+}
+
+// AddToWatchlist adds a conversation to the watchlist, updating priority and notes if it is already listed
+func (s *WatchlistStorage) AddToWatchlist(entry *WatchlistEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.Priority <= 0 {
+		entry.Priority = 1
+	}
+
+	query := `
+		INSERT INTO watchlist (id, conversation_id, tenant_id, added_by, priority, notes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(tenant_id, conversation_id) DO UPDATE SET
+			added_by = excluded.added_by,
+			priority = excluded.priority,
+			notes = excluded.notes
+	`
+	_, err := s.client.DB.Exec(query, entry.ID, entry.ConversationID, entry.TenantID,
+		entry.AddedBy, entry.Priority, entry.Notes, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add conversation to watchlist: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromWatchlist removes a conversation from the watchlist. Returns false if it was not listed.
+func (s *WatchlistStorage) RemoveFromWatchlist(tenantID, conversationID string) (bool, error) {
+	result, err := s.client.DB.Exec(
+		"DELETE FROM watchlist WHERE tenant_id = $1 AND conversation_id = $2",
+		tenantID, conversationID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove conversation from watchlist: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove conversation from watchlist: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// IsWatchlisted reports whether a conversation is on the tenant's watchlist
+func (s *WatchlistStorage) IsWatchlisted(tenantID, conversationID string) (bool, error) {
+	var count int
+	err := s.client.DB.QueryRow(
+		"SELECT COUNT(*) FROM watchlist WHERE tenant_id = $1 AND conversation_id = $2",
+		tenantID, conversationID,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check watchlist: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListWatchlist lists a tenant's watchlist, highest priority first
+func (s *WatchlistStorage) ListWatchlist(tenantID string) ([]*WatchlistEntry, error) {
+	query := `
+		SELECT id, conversation_id, tenant_id, added_by, priority, notes, created_at
+		FROM watchlist
+		WHERE tenant_id = $1
+		ORDER BY priority DESC, created_at ASC
+	`
+	rows, err := s.client.DB.Query(query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*WatchlistEntry
+	for rows.Next() {
+		entry := &WatchlistEntry{}
+		var notes sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.ConversationID, &entry.TenantID, &entry.AddedBy,
+			&entry.Priority, &notes, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist entry: %w", err)
+		}
+		if notes.Valid {
+			entry.Notes = &notes.String
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watchlist: %w", err)
+	}
+	return entries, nil
+}
+
+// ListWatchlistTenants returns the tenants that have at least one watchlisted conversation
+func (s *WatchlistStorage) ListWatchlistTenants() ([]string, error) {
+	rows, err := s.client.DB.Query("SELECT DISTINCT tenant_id FROM watchlist ORDER BY tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist tenant: %w", err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watchlist tenants: %w", err)
+	}
+	return tenantIDs, nil
+}
+
+// ListWatchlistedConversations lists a tenant's watchlisted conversations with pagination
+func (s *ConversationStorage) ListWatchlistedConversations(tenantID string, limit, offset int) ([]*models.Conversation, error) {
+	query := `
+		SELECT c.id, c.tenant_id, c.customer_id, c.product_id, c.status, c.created_at, c.updated_at
+		FROM conversations c
+		JOIN watchlist w ON w.conversation_id = c.id AND w.tenant_id = c.tenant_id
+		WHERE c.tenant_id = $1 AND c.deleted_at IS NULL
+		ORDER BY w.priority DESC, c.updated_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := s.client.DB.Query(query, tenantID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlisted conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []*models.Conversation
+	for rows.Next() {
+		conv := &models.Conversation{}
+		var customerID, productID sql.NullString
+		if err := rows.Scan(&conv.ID, &conv.TenantID, &customerID, &productID, &conv.Status, &conv.CreatedAt, &conv.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		if customerID.Valid {
+			conv.CustomerID = &customerID.String
+		}
+		if productID.Valid {
+			conv.ProductID = &productID.String
+		}
+		conversations = append(conversations, conv)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watchlisted conversations: %w", err)
+	}
+	return conversations, nil
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package postgres
+
+import (
+	"testing"
+)
+
+func TestWatchlistLifecycle(t *testing.T) {
+	conversations := NewConversationStorage(testClient)
+	watchlist := NewWatchlistStorage(testClient)
+	watched := newTestConversation(t, conversations, nil, "active")
+	other := newTestConversation(t, conversations, nil, "active")
+
+	if err := watchlist.AddToWatchlist(&WatchlistEntry{ConversationID: watched.ID, TenantID: testTenantID, AddedBy: "admin-1"}); err != nil {
+		t.Fatalf("AddToWatchlist: %v", err)
+	}
+	notes := "Fortune 500 prospect"
+	if err := watchlist.AddToWatchlist(&WatchlistEntry{ConversationID: watched.ID, TenantID: testTenantID, AddedBy: "admin-2", Priority: 3, Notes: &notes}); err != nil {
+		t.Fatalf("AddToWatchlist (update): %v", err)
+	}
+
+	entries, err := watchlist.ListWatchlist(testTenantID)
+	if err != nil {
+		t.Fatalf("ListWatchlist: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Priority != 3 || entries[0].AddedBy != "admin-2" || entries[0].Notes == nil || *entries[0].Notes != notes {
+		t.Fatalf("unexpected watchlist after re-adding: %+v", entries)
+	}
+
+	if ok, err := watchlist.IsWatchlisted(testTenantID, watched.ID); err != nil || !ok {
+		t.Errorf("IsWatchlisted(watched) = %v, %v; want true", ok, err)
+	}
+	if ok, err := watchlist.IsWatchlisted("other-tenant", watched.ID); err != nil || ok {
+		t.Errorf("IsWatchlisted(other tenant) = %v, %v; want false", ok, err)
+	}
+
+	listed, err := conversations.ListWatchlistedConversations(testTenantID, 10, 0)
+	if err != nil {
+		t.Fatalf("ListWatchlistedConversations: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != watched.ID {
+		t.Errorf("ListWatchlistedConversations = %d conversations, want only %s", len(listed), watched.ID)
+	}
+
+	rows, err := conversations.GetConversationsWithMetadata(testTenantID, ConversationFilter{})
+	if err != nil {
+		t.Fatalf("GetConversationsWithMetadata: %v", err)
+	}
+	for _, row := range rows {
+		if want := row.ConversationID == watched.ID; row.Watchlisted != want {
+			t.Errorf("conversation %s watchlisted = %v, want %v", row.ConversationID, row.Watchlisted, want)
+		}
+	}
+
+	if removed, err := watchlist.RemoveFromWatchlist(testTenantID, watched.ID); err != nil || !removed {
+		t.Errorf("RemoveFromWatchlist = %v, %v; want true", removed, err)
+	}
+	if removed, err := watchlist.RemoveFromWatchlist(testTenantID, other.ID); err != nil || removed {
+		t.Errorf("RemoveFromWatchlist(not listed) = %v, %v; want false", removed, err)
+	}
+}
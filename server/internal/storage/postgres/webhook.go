@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is a tenant endpoint that receives signed conversation events
+type Webhook struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"` // Only returned when the webhook is created
+	Events    []string  `json:"events"`
+	CRMType   *string   `json:"crm_type,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Subscribes reports whether the webhook receives event
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookStorage handles tenant webhooks
+type WebhookStorage struct {
+	client *Client
+}
+
+// NewWebhookStorage creates a new webhook storage instance
+func NewWebhookStorage(client *Client) *WebhookStorage {
+	return &WebhookStorage{client: client}
+}
+
+const webhookColumns = "id, tenant_id, url, secret, events, crm_type, is_active, created_at"
+
+// CreateWebhook stores a new webhook
+func (s *WebhookStorage) CreateWebhook(hook *Webhook) error {
+	if hook.ID == "" {
+		hook.ID = uuid.New().String()
+	}
+	if hook.CreatedAt.IsZero() {
+		hook.CreatedAt = time.Now()
+	}
+	eventsJSON, err := json.Marshal(hook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (` + webhookColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = s.client.DB.Exec(query, hook.ID, hook.TenantID, hook.URL, hook.Secret,
+		string(eventsJSON), hook.CRMType, hook.IsActive, hook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// GetWebhook retrieves a tenant's webhook by ID
+func (s *WebhookStorage) GetWebhook(tenantID, id string) (*Webhook, error) {
+	row := s.client.DB.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	hook, err := scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return hook, nil
+}
+
+// ListWebhooks lists a tenant's webhooks, oldest first
+func (s *WebhookStorage) ListWebhooks(tenantID string) ([]*Webhook, error) {
+	return s.queryWebhooks("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = $1 ORDER BY created_at ASC, id ASC", tenantID)
+}
+
+// ListActiveWebhooks lists a tenant's active webhooks subscribed to event
+func (s *WebhookStorage) ListActiveWebhooks(tenantID, event string) ([]*Webhook, error) {
+	hooks, err := s.queryWebhooks("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = $1 AND is_active = TRUE ORDER BY created_at ASC, id ASC", tenantID)
+	if err != nil {
+		return nil, err
+	}
+	// Events are stored as a JSON array, so subscriptions are matched here rather than in SQL
+	var subscribed []*Webhook
+	for _, hook := range hooks {
+		if hook.Subscribes(event) {
+			subscribed = append(subscribed, hook)
+		}
+	}
+	return subscribed, nil
+}
+
+// UpdateWebhook replaces a webhook's URL, secret, events, CRM type and active flag
+func (s *WebhookStorage) UpdateWebhook(hook *Webhook) error {
+	eventsJSON, err := json.Marshal(hook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	query := `
+		UPDATE webhooks
+		SET url = $1, secret = $2, events = $3, crm_type = $4, is_active = $5
+		WHERE id = $6 AND tenant_id = $7
+	`
+	result, err := s.client.DB.Exec(query, hook.URL, hook.Secret, string(eventsJSON), hook.CRMType, hook.IsActive, hook.ID, hook.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+	return nil
+}
+
+// DeleteWebhook removes a tenant's webhook
+func (s *WebhookStorage) DeleteWebhook(tenantID, id string) error {
+	result, err := s.client.DB.Exec("DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+	return nil
+}
+
+func (s *WebhookStorage) queryWebhooks(query string, args ...interface{}) ([]*Webhook, error) {
+	rows, err := s.client.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []*Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+func scanWebhook(row rowScanner) (*Webhook, error) {
+	hook := &Webhook{}
+	var eventsJSON string
+	var crmType sql.NullString
+	if err := row.Scan(&hook.ID, &hook.TenantID, &hook.URL, &hook.Secret, &eventsJSON, &crmType, &hook.IsActive, &hook.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventsJSON), &hook.Events); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook events: %w", err)
+	}
+	if crmType.Valid {
+		hook.CRMType = &crmType.String
+	}
+	return hook, nil
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+package postgres
+
+import "testing"
+
+func TestWebhookCRUD(t *testing.T) {
+	storage := NewWebhookStorage(testClient)
+	tenantID := newIsolatedTenant(t, "webhook", "webhooks")
+	otherTenant := newIsolatedTenant(t, "webhook", "webhooks")
+
+	crmType := "hubspot"
+	hook := &Webhook{TenantID: tenantID, URL: "https://example.com/hook", Secret: "0123456789abcdef", Events: []string{"message.created", "conversation.closed"}, CRMType: &crmType, IsActive: true}
+	if err := storage.CreateWebhook(hook); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	got, err := storage.GetWebhook(tenantID, hook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook: %v", err)
+	}
+	if got.URL != hook.URL || got.Secret != hook.Secret || len(got.Events) != 2 || got.CRMType == nil || *got.CRMType != "hubspot" || !got.IsActive {
+		t.Errorf("GetWebhook = %+v, want %+v", got, hook)
+	}
+	if _, err := storage.GetWebhook(otherTenant, hook.ID); err == nil {
+		t.Error("GetWebhook from another tenant should fail")
+	}
+
+	got.URL = "https://example.com/v2"
+	got.Events = []string{"conversation.created"}
+	got.CRMType = nil
+	got.IsActive = false
+	if err := storage.UpdateWebhook(got); err != nil {
+		t.Fatalf("UpdateWebhook: %v", err)
+	}
+	updated, _ := storage.GetWebhook(tenantID, hook.ID)
+	if updated.URL != "https://example.com/v2" || !updated.Subscribes("conversation.created") || updated.Subscribes("message.created") || updated.CRMType != nil || updated.IsActive {
+		t.Errorf("after update = %+v", updated)
+	}
+
+	if err := storage.DeleteWebhook(otherTenant, hook.ID); err == nil {
+		t.Error("DeleteWebhook from another tenant should fail")
+	}
+	if err := storage.DeleteWebhook(tenantID, hook.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if hooks, err := storage.ListWebhooks(tenantID); err != nil || len(hooks) != 0 {
+		t.Errorf("ListWebhooks after delete = %d, %v; want none", len(hooks), err)
+	}
+}
+
+func TestListActiveWebhooksFiltersByEvent(t *testing.T) {
+	storage := NewWebhookStorage(testClient)
+	tenantID := newIsolatedTenant(t, "webhook", "webhooks")
+	otherTenant := newIsolatedTenant(t, "webhook", "webhooks")
+
+	hooks := []*Webhook{
+		{TenantID: tenantID, URL: "https://example.com/messages", Events: []string{"message.created"}, IsActive: true},
+		{TenantID: tenantID, URL: "https://example.com/closed", Events: []string{"conversation.closed"}, IsActive: true},
+		{TenantID: tenantID, URL: "https://example.com/paused", Events: []string{"message.created"}, IsActive: false},
+		{TenantID: otherTenant, URL: "https://example.com/other", Events: []string{"message.created"}, IsActive: true},
+	}
+	for _, hook := range hooks {
+		hook.Secret = "0123456789abcdef"
+		if err := storage.CreateWebhook(hook); err != nil {
+			t.Fatalf("CreateWebhook: %v", err)
+		}
+	}
+
+	active, err := storage.ListActiveWebhooks(tenantID, "message.created")
+	if err != nil {
+		t.Fatalf("ListActiveWebhooks: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != hooks[0].ID {
+		t.Errorf("ListActiveWebhooks = %d webhooks, want only %s", len(active), hooks[0].URL)
+	}
+	if all, _ := storage.ListWebhooks(tenantID); len(all) != 3 {
+		t.Errorf("ListWebhooks = %d, want 3", len(all))
+	}
+}
@@ -0,0 +1,32 @@
+package worker
+
+import "context"
+
+type attemptKey struct{}
+
+type attemptInfo struct {
+	number int
+	final  bool
+}
+
+func withAttempt(ctx context.Context, number int, final bool) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attemptInfo{number: number, final: final})
+}
+
+// Attempt returns which attempt of a pool job ctx belongs to, starting at 1. Outside a pool job
+// it returns 1.
+func Attempt(ctx context.Context) int {
+	if info, ok := ctx.Value(attemptKey{}).(attemptInfo); ok {
+		return info.number
+	}
+	return 1
+}
+
+// IsFinalAttempt reports whether a failure of the running job will not be retried, so the job
+// should fall back to whatever it can do without retrying. Outside a pool job it returns true.
+func IsFinalAttempt(ctx context.Context) bool {
+	if info, ok := ctx.Value(attemptKey{}).(attemptInfo); ok {
+		return info.final
+	}
+	return true
+}
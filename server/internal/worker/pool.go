@@ -0,0 +1,215 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultQueueSize    = 100
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = 5 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when every worker is busy and the queue buffer is full
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrPoolClosed is returned by Enqueue once the pool is draining
+	ErrPoolClosed = errors.New("worker pool is closed")
+)
+
+// Job is a unit of background work
+type Job struct {
+	Name string // Identifies the job in logs, e.g. "analysis:<conversation id>"
+	Run  func(ctx context.Context) error
+}
+
+// Config controls a worker pool
+type Config struct {
+	Workers      int                  // Jobs run concurrently
+	QueueSize    int                  // Jobs buffered before Enqueue fails
+	MaxAttempts  int                  // Runs of a job, including the first, before a retryable failure is given up
+	RetryBackoff time.Duration        // Wait before the first retry; doubled for each further retry
+	Retryable    func(err error) bool // Failures worth retrying (e.g. quota errors); nil retries nothing
+}
+
+// ConfigFromEnv reads WORKER_COUNT (default 4), WORKER_QUEUE_SIZE (default 100),
+// WORKER_MAX_ATTEMPTS (default 3) and WORKER_RETRY_BACKOFF_MS (default 5000)
+func ConfigFromEnv() Config {
+	config := Config{
+		Workers:      defaultWorkers,
+		QueueSize:    defaultQueueSize,
+		MaxAttempts:  defaultMaxAttempts,
+		RetryBackoff: defaultRetryBackoff,
+	}
+	if n, ok := positiveEnv("WORKER_COUNT"); ok {
+		config.Workers = n
+	}
+	if n, ok := positiveEnv("WORKER_QUEUE_SIZE"); ok {
+		config.QueueSize = n
+	}
+	if n, ok := positiveEnv("WORKER_MAX_ATTEMPTS"); ok {
+		config.MaxAttempts = n
+	}
+	if ms, ok := positiveEnv("WORKER_RETRY_BACKOFF_MS"); ok {
+		config.RetryBackoff = time.Duration(ms) * time.Millisecond
+	}
+	return config
+}
+
+func positiveEnv(key string) (int, bool) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// queuedJob is a job with the number of the attempt it is queued for
+type queuedJob struct {
+	job     Job
+	attempt int
+}
+
+// Pool runs jobs on a fixed number of workers fed by a bounded queue, so bursts of work can't
+// start unbounded goroutines. Retryable failures are re-enqueued with exponential backoff.
+type Pool struct {
+	config  Config
+	jobs    chan queuedJob
+	pending sync.WaitGroup // Jobs queued, running or waiting to be retried
+
+	mu     sync.RWMutex
+	closed bool
+
+	startOnce sync.Once
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewPool creates a worker pool. Call Start to run its workers.
+func NewPool(config Config) *Pool {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	return &Pool{
+		config: config,
+		jobs:   make(chan queuedJob, config.QueueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start runs the pool's workers in the background until Drain returns
+func (p *Pool) Start() {
+	p.startOnce.Do(func() {
+		for i := 0; i < p.config.Workers; i++ {
+			go p.work()
+		}
+	})
+}
+
+// Enqueue queues a job without blocking. Returns ErrQueueFull when the queue is full and
+// ErrPoolClosed once the pool is draining.
+func (p *Pool) Enqueue(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	p.pending.Add(1)
+	select {
+	case p.jobs <- queuedJob{job: job, attempt: 1}:
+		return nil
+	default:
+		p.pending.Done()
+		return ErrQueueFull
+	}
+}
+
+// Len returns the number of jobs waiting for a worker
+func (p *Pool) Len() int {
+	return len(p.jobs)
+}
+
+// Drain stops accepting jobs and waits for queued, running and retrying jobs to finish, then
+// stops the workers. If ctx ends first the workers are stopped anyway and the remaining jobs
+// are dropped.
+func (p *Pool) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	defer p.stopOnce.Do(func() { close(p.stop) })
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to drain worker pool pending=%d: %w", p.Len(), ctx.Err())
+	}
+}
+
+func (p *Pool) work() {
+	for {
+		select {
+		case qj := <-p.jobs:
+			p.run(qj)
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// run runs one attempt of a job and schedules a retry for retryable failures
+func (p *Pool) run(qj queuedJob) {
+	ctx := withAttempt(context.Background(), qj.attempt, qj.attempt >= p.config.MaxAttempts)
+	err := qj.job.Run(ctx)
+	if err == nil {
+		p.pending.Done()
+		return
+	}
+
+	if qj.attempt < p.config.MaxAttempts && p.config.Retryable != nil && p.config.Retryable(err) {
+		backoff := p.config.RetryBackoff << (qj.attempt - 1)
+		log.Printf("[WORKER] job failed, retrying job=%s attempt=%d backoff=%s error=%v", qj.job.Name, qj.attempt, backoff, err)
+		retry := queuedJob{job: qj.job, attempt: qj.attempt + 1}
+		time.AfterFunc(backoff, func() { p.requeue(retry) })
+		return
+	}
+
+	log.Printf("[WORKER] job failed job=%s attempt=%d error=%v", qj.job.Name, qj.attempt, err)
+	p.pending.Done()
+}
+
+// requeue puts a retry back on the queue, waiting for room. Retries are dropped once the
+// workers have stopped.
+func (p *Pool) requeue(qj queuedJob) {
+	select {
+	case p.jobs <- qj:
+	case <-p.stop:
+		log.Printf("[WORKER] retry dropped, pool stopped job=%s attempt=%d", qj.job.Name, qj.attempt)
+		p.pending.Done()
+	}
+}
@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errQuota = errors.New("quota exceeded")
+
+func isQuota(err error) bool { return errors.Is(err, errQuota) }
+
+// blockingJob returns a job that signals started and then waits for release
+func blockingJob(name string, started chan<- string, release <-chan struct{}) Job {
+	return Job{Name: name, Run: func(ctx context.Context) error {
+		started <- name
+		<-release
+		return nil
+	}}
+}
+
+func TestPoolLimitsQueueDepth(t *testing.T) {
+	pool := NewPool(Config{Workers: 2, QueueSize: 3})
+	pool.Start()
+
+	started := make(chan string, 10)
+	release := make(chan struct{})
+
+	// Two jobs occupy the workers...
+	for _, name := range []string{"a", "b"} {
+		if err := pool.Enqueue(blockingJob(name, started, release)); err != nil {
+			t.Fatalf("Enqueue(%s): %v", name, err)
+		}
+	}
+	<-started
+	<-started
+
+	// ...three more fill the queue...
+	for _, name := range []string{"c", "d", "e"} {
+		if err := pool.Enqueue(blockingJob(name, started, release)); err != nil {
+			t.Fatalf("Enqueue(%s): %v", name, err)
+		}
+	}
+	if got := pool.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+
+	// ...and the next one is rejected instead of starting another goroutine
+	if err := pool.Enqueue(blockingJob("f", started, release)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue on a full queue = %v, want ErrQueueFull", err)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if got := len(started); got != 3 {
+		t.Errorf("queued jobs run = %d, want 3", got)
+	}
+	if got := pool.Len(); got != 0 {
+		t.Errorf("Len() after drain = %d, want 0", got)
+	}
+}
+
+func TestPoolDrainRejectsNewJobs(t *testing.T) {
+	pool := NewPool(Config{Workers: 1, QueueSize: 1})
+	pool.Start()
+	if err := pool.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if err := pool.Enqueue(Job{Name: "late", Run: func(context.Context) error { return nil }}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Enqueue after Drain = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestPoolDrainTimesOut(t *testing.T) {
+	pool := NewPool(Config{Workers: 1, QueueSize: 1})
+	pool.Start()
+
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	defer close(release)
+	if err := pool.Enqueue(blockingJob("stuck", started, release)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain with a stuck job = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestPoolRetriesRetryableFailuresWithBackoff(t *testing.T) {
+	pool := NewPool(Config{Workers: 1, QueueSize: 1, MaxAttempts: 3, RetryBackoff: 10 * time.Millisecond, Retryable: isQuota})
+	pool.Start()
+
+	var mu sync.Mutex
+	var runs []time.Time
+	var finals []bool
+	job := Job{Name: "quota", Run: func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs = append(runs, time.Now())
+		finals = append(finals, IsFinalAttempt(ctx))
+		if Attempt(ctx) < 3 {
+			return errQuota
+		}
+		return nil
+	}}
+	if err := pool.Enqueue(job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(runs) != 3 {
+		t.Fatalf("runs = %d, want 3", len(runs))
+	}
+	if gap := runs[1].Sub(runs[0]); gap < 10*time.Millisecond {
+		t.Errorf("first retry after %s, want at least 10ms", gap)
+	}
+	if gap := runs[2].Sub(runs[1]); gap < 20*time.Millisecond {
+		t.Errorf("second retry after %s, want at least 20ms (doubled backoff)", gap)
+	}
+	if finals[0] || finals[1] || !finals[2] {
+		t.Errorf("final attempt flags = %v, want only the last attempt final", finals)
+	}
+}
+
+func TestPoolStopsRetryingAtMaxAttempts(t *testing.T) {
+	pool := NewPool(Config{Workers: 1, QueueSize: 1, MaxAttempts: 2, RetryBackoff: time.Millisecond, Retryable: isQuota})
+	pool.Start()
+
+	var runs int32
+	if err := pool.Enqueue(Job{Name: "quota", Run: func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return errQuota
+	}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := pool.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if got := atomic.LoadInt32(&runs); got != 2 {
+		t.Errorf("runs = %d, want 2 (MaxAttempts)", got)
+	}
+}
+
+func TestPoolDoesNotRetryOtherFailures(t *testing.T) {
+	pool := NewPool(Config{Workers: 1, QueueSize: 1, MaxAttempts: 3, RetryBackoff: time.Millisecond, Retryable: isQuota})
+	pool.Start()
+
+	var runs int32
+	if err := pool.Enqueue(Job{Name: "broken", Run: func(context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return errors.New("invalid response")
+	}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := pool.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		t.Errorf("runs = %d, want 1", got)
+	}
+}
+
+func TestAttemptOutsidePool(t *testing.T) {
+	if got := Attempt(context.Background()); got != 1 {
+		t.Errorf("Attempt() = %d, want 1", got)
+	}
+	if !IsFinalAttempt(context.Background()) {
+		t.Error("IsFinalAttempt() = false outside a pool job, want true")
+	}
+}